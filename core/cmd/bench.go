@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"data-voyager/core/internal/bench"
+)
+
+// benchCmd represents the bench command
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Load-test a datasource through a running server",
+	Long: `Send a query to a datasource from several concurrent workers for a fixed
+time and report latency percentiles, throughput and error rates.
+
+Requests go through the server's query API, the same path the web interface
+uses, so the numbers include the server's overhead and reflect what users
+will see. Interrupting the run still prints the results so far.`,
+	Example: `  data-voyager bench --datasource analytics --query-file q.sql --concurrency 10 --duration 60s`,
+	Args:    cobra.NoArgs,
+	RunE:    runBench,
+}
+
+var (
+	benchServer      string
+	benchDatasource  string
+	benchQueryFile   string
+	benchConcurrency int
+	benchDuration    time.Duration
+	benchLimit       int
+	benchHeaders     []string
+	benchJSON        bool
+)
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+
+	benchCmd.Flags().StringVar(&benchServer, "server", "http://localhost:8080", "base URL of the server")
+	benchCmd.Flags().StringVar(&benchDatasource, "datasource", "", "datasource name or uid")
+	benchCmd.Flags().StringVar(&benchQueryFile, "query-file", "", "file containing the query to run")
+	benchCmd.Flags().IntVar(&benchConcurrency, "concurrency", 10, "number of concurrent workers")
+	benchCmd.Flags().DurationVar(&benchDuration, "duration", time.Minute, "how long to run")
+	benchCmd.Flags().IntVar(&benchLimit, "limit", 0, "row limit per query (default: server default)")
+	benchCmd.Flags().StringArrayVar(&benchHeaders, "header", nil, `extra request header, e.g. "Authorization: Bearer <token>" (repeatable)`)
+	benchCmd.Flags().BoolVar(&benchJSON, "json", false, "print the report as JSON")
+	_ = benchCmd.MarkFlagRequired("datasource")
+	_ = benchCmd.MarkFlagRequired("query-file")
+}
+
+func runBench(cmd *cobra.Command, _ []string) error {
+	query, err := os.ReadFile(benchQueryFile)
+	if err != nil {
+		return fmt.Errorf("failed to read query file: %w", err)
+	}
+	headers := http.Header{}
+	for _, h := range benchHeaders {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok {
+			return fmt.Errorf("invalid header %q, want \"Name: value\"", h)
+		}
+		headers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Let every worker keep its own connection instead of queueing on the
+	// default two idle connections per host.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = benchConcurrency
+
+	report, err := bench.Run(ctx, &http.Client{Transport: transport}, bench.Options{
+		Server:      benchServer,
+		Datasource:  benchDatasource,
+		Query:       string(query),
+		Concurrency: benchConcurrency,
+		Duration:    benchDuration,
+		Limit:       benchLimit,
+		Headers:     headers,
+	})
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if benchJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return report.WriteText(out)
+}
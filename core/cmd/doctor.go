@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"data-voyager/core"
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/doctor"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that the server can start",
+	Long: `Check the configuration, metadata store, plugins, encryption key, port
+and frontend assets the server would use, and say how to fix what is wrong.
+
+It reads the same config.toml and environment as serve. It does not apply
+migrations or generate keys, and exits non-zero when a check fails.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+var doctorJSON bool
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "print the report as JSON")
+}
+
+func runDoctor(cmd *cobra.Command, _ []string) error {
+	d := &doctor.Doctor{
+		Load:     func() (*config.ViperConfig, error) { return config.InitViper("config", "") },
+		Frontend: core.CheckFrontend,
+	}
+	report := d.Run(cmd.Context())
+
+	out := cmd.OutOrStdout()
+	if doctorJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		marks := map[string]string{doctor.StatusOK: "✓", doctor.StatusWarn: "!", doctor.StatusFail: "✗"}
+		for _, res := range report.Results {
+			_, _ = fmt.Fprintf(out, "%s %-15s %s\n", marks[res.Status], res.Check, res.Message)
+			if res.Hint != "" {
+				_, _ = fmt.Fprintf(out, "  %-15s → %s\n", "", res.Hint)
+			}
+		}
+		_, _ = fmt.Fprintf(out, "\n%d ok, %d warning(s), %d failed\n",
+			report.Count(doctor.StatusOK), report.Count(doctor.StatusWarn), report.Count(doctor.StatusFail))
+	}
+	if report.Failed() {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d check(s) failed", report.Count(doctor.StatusFail))
+	}
+	return nil
+}
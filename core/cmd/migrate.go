@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/spf13/cobra"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/store"
+)
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply, inspect or roll back metadata store migrations",
+	Long: `Manage the metadata store schema configured in [metadata_store].
+
+  migrate status              show the schema version and pending migrations
+  migrate up                  apply pending migrations
+  migrate rollback --to N     migrate down to version N
+  migrate backups             list pre-migration table snapshots
+  migrate drop-backups --to N delete snapshots taken at version N or before
+
+Each migration runs in its own transaction, so one that fails leaves the
+schema at the last migration that succeeded. On PostgreSQL, up holds an
+advisory lock so servers starting at the same time migrate once, and with
+metadata_store.migration_backup (the default) first copies every table the
+pending migrations change into the dv_migration_backup schema. To undo an
+upgrade, stop the servers, run "migrate rollback --to N" with N the version
+"migrate status" reported before upgrading, and start the previous release:
+rollback runs the down migrations and then restores the tables snapshotted
+at N. Rows written since the snapshot are lost. On SQLite and MySQL,
+rollback only runs the down migrations; back those databases up yourself.`,
+	Args: cobra.NoArgs,
+}
+
+var migrateTo int64
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.AddCommand(
+		&cobra.Command{Use: "status", Short: "Show the schema version and pending migrations", Args: cobra.NoArgs, RunE: runMigrateStatus},
+		&cobra.Command{Use: "up", Short: "Apply pending migrations", Args: cobra.NoArgs, RunE: runMigrateUp},
+		&cobra.Command{Use: "backups", Short: "List pre-migration table snapshots", Args: cobra.NoArgs, RunE: runMigrateBackups},
+	)
+	rollbackCmd := &cobra.Command{Use: "rollback", Short: "Migrate down to a version and restore its snapshots", Args: cobra.NoArgs, RunE: runMigrateRollback}
+	dropCmd := &cobra.Command{Use: "drop-backups", Short: "Delete snapshots taken at a version or before", Args: cobra.NoArgs, RunE: runMigrateDropBackups}
+	for _, c := range []*cobra.Command{rollbackCmd, dropCmd} {
+		c.Flags().Int64Var(&migrateTo, "to", -1, "schema version")
+		_ = c.MarkFlagRequired("to")
+		migrateCmd.AddCommand(c)
+	}
+}
+
+// openForMigrate opens the metadata store without migrating it.
+func openForMigrate(cmd *cobra.Command) (*sqlx.DB, config.DBConfig, error) {
+	cfg, err := config.InitViper("config", "")
+	if err != nil {
+		return nil, config.DBConfig{}, err
+	}
+	mc := cfg.MetadataStore
+	if mc.Type == "memory" {
+		return nil, mc, fmt.Errorf("the memory metadata store has no schema to migrate")
+	}
+	mc.MigrateOnStart = false
+	db, err := store.Open(mc)
+	if err != nil {
+		return nil, mc, err
+	}
+	cmd.SilenceUsage = true
+	return db, mc, nil
+}
+
+func runMigrateStatus(cmd *cobra.Command, _ []string) error {
+	db, mc, err := openForMigrate(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+	current, latest, err := store.MigrationStatus(db, mc.Type)
+	if err != nil {
+		return err
+	}
+	todo, err := store.Pending(db, mc.Type)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(out, "%s schema is at version %d; this release ships %d\n", mc.Type, current, latest)
+	for _, m := range todo {
+		_, _ = fmt.Fprintf(out, "  pending %s", m.Name)
+		if len(m.Tables) > 0 {
+			_, _ = fmt.Fprintf(out, " (changes %s)", strings.Join(m.Tables, ", "))
+		}
+		_, _ = fmt.Fprintln(out)
+	}
+	return nil
+}
+
+func runMigrateUp(cmd *cobra.Command, _ []string) error {
+	db, mc, err := openForMigrate(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+	backups, err := store.Migrate(cmd.Context(), db, mc.Type, mc.MigrationBackup)
+	out := cmd.OutOrStdout()
+	for _, b := range backups {
+		_, _ = fmt.Fprintf(out, "backed up %s to %s.%s\n", b.Table, store.BackupSchema, b.Name)
+	}
+	if err != nil {
+		return err
+	}
+	current, _, err := store.MigrationStatus(db, mc.Type)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "%s schema is at version %d\n", mc.Type, current)
+	return nil
+}
+
+func runMigrateRollback(cmd *cobra.Command, _ []string) error {
+	db, mc, err := openForMigrate(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+	restored, err := store.Rollback(cmd.Context(), db, mc.Type, migrateTo)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(out, "%s schema rolled back to version %d\n", mc.Type, migrateTo)
+	for _, b := range restored {
+		_, _ = fmt.Fprintf(out, "restored %s from %s.%s\n", b.Table, store.BackupSchema, b.Name)
+	}
+	return nil
+}
+
+func runMigrateBackups(cmd *cobra.Command, _ []string) error {
+	db, mc, err := openForMigrate(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+	backups, err := store.Backups(cmd.Context(), db, mc.Type)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	if len(backups) == 0 {
+		_, _ = fmt.Fprintln(out, "no pre-migration snapshots")
+		return nil
+	}
+	for _, b := range backups {
+		_, _ = fmt.Fprintf(out, "version %-5d %-30s %s.%s\n", b.Version, b.Table, store.BackupSchema, b.Name)
+	}
+	return nil
+}
+
+func runMigrateDropBackups(cmd *cobra.Command, _ []string) error {
+	db, mc, err := openForMigrate(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+	n, err := store.DropBackups(cmd.Context(), db, mc.Type, migrateTo)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "dropped %d snapshot(s)\n", n)
+	return nil
+}
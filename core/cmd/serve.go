@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -12,18 +13,11 @@ import (
 	"time"
 
 	"data-voyager/core"
-	"data-voyager/core/internal/aiconfig"
-	"data-voyager/core/internal/app"
 	"data-voyager/core/internal/config"
-	"data-voyager/core/internal/connection"
-	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/devmode"
 	_ "data-voyager/core/internal/generated" // load extension init() registrations
 	"data-voyager/core/internal/logger"
-	"data-voyager/core/internal/settings"
-	"data-voyager/core/internal/statsstore"
-	"data-voyager/core/internal/store"
 
-	"github.com/gin-gonic/gin"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -34,12 +28,21 @@ var serveCmd = &cobra.Command{
 	Long: `Start the Data Voyager web server with API endpoints and web interface.
 The server provides REST API endpoints for managing data sources and
 serves the web interface for data exploration and visualization.`,
-	RunE: runServe,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := runServe(cmd, args)
+		var restart *devmode.Restart
+		if errors.As(err, &restart) {
+			slog.Info("restarting server", "changed", restart.Reason)
+			return restart.Exec()
+		}
+		return err
+	},
 }
 
 var (
 	host string
 	port int
+	dev  bool
 )
 
 func init() {
@@ -47,6 +50,7 @@ func init() {
 
 	serveCmd.Flags().StringVarP(&host, "host", "H", "", "server host (default: from config)")
 	serveCmd.Flags().IntVarP(&port, "port", "p", 0, "server port (default: from config)")
+	serveCmd.Flags().BoolVar(&dev, "dev", false, "restart in place when the config file or server binary changes, keeping the listening socket")
 
 	_ = viper.BindPFlag("server.host", serveCmd.Flags().Lookup("host"))
 	_ = viper.BindPFlag("server.port", serveCmd.Flags().Lookup("port"))
@@ -77,121 +81,32 @@ func runServe(_ *cobra.Command, _ []string) error {
 		)
 	}
 
-	db, err := store.Open(cfg.MetadataStore)
-	if err != nil {
-		return fmt.Errorf("failed to open metadata store: %w", err)
-	}
-	defer func() { _ = db.Close() }()
-
-	repos, err := store.NewRepos(db, cfg.MetadataStore)
-	if err != nil {
-		return fmt.Errorf("failed to initialize repositories: %w", err)
-	}
-
-	registry := datasource.NewRegistry()
-
-	// Derive data directory from the SQLite path so the encryption key file
-	// lives alongside the database. For non-SQLite stores the dataDir is empty
-	// and BuildService falls back to the VOYAGER_ENCRYPTION_KEY env var.
-	dataDir := ""
-	if cfg.MetadataStore.Type == "sqlite" || cfg.MetadataStore.Type == "sqlite3" {
-		dataDir = filepath.Dir(cfg.MetadataStore.SQLite.Path)
-	}
-
-	// Resolve encryption key once — shared by both settings and aiconfig packages.
-	encryptKey, err := settings.ResolveEncryptionKey(dataDir)
-	if err != nil {
-		return fmt.Errorf("failed to resolve encryption key: %w", err)
-	}
-
-	// Register all service loaders. Add new domains here as the app grows.
-	settingsSvc, err := settings.BuildService(repos.Settings, encryptKey)
+	server, err := core.NewServer(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to initialize settings service: %w", err)
-	}
-
-	// Open statistics store (optional — noop when type is empty).
-	var aiHistoryRepo aiconfig.HistoryRepository = aiconfig.NoopHistoryRepository{}
-	var connHistoryRepo connection.HistoryRepository = connection.NoopHistoryRepository{}
-	if cfg.StatisticsStore.Type != "" {
-		statsDB, err := statsstore.Open(cfg.StatisticsStore)
-		if err != nil {
-			return fmt.Errorf("failed to open statistics store: %w", err)
-		}
-		defer func() { _ = statsDB.Close() }()
-		statsRepos, err := statsstore.NewRepos(statsDB, cfg.StatisticsStore)
-		if err != nil {
-			return fmt.Errorf("failed to initialize statistics repositories: %w", err)
-		}
-		aiHistoryRepo = statsRepos.AIConfigHistory
-		connHistoryRepo = statsRepos.ConnectionHistory
-	}
-
-	aiConfigSvc, err := aiconfig.BuildService(repos.AIConfigs, encryptKey, aiHistoryRepo)
-	if err != nil {
-		return fmt.Errorf("failed to initialize aiconfig service: %w", err)
-	}
-
-	loaders := []app.Loader{
-		connection.NewLoaderWithHistory(repos.Connection, registry, cfg, settingsSvc, aiConfigSvc, connHistoryRepo),
-	}
-	for _, l := range loaders {
-		if err := l.Load(); err != nil {
-			return fmt.Errorf("loader failed: %w", err)
-		}
-	}
-
-	if cfg.Logging.Level != "debug" {
-		gin.SetMode(gin.ReleaseMode)
-	}
-	r := gin.New()
-	r.Use(logger.GinMiddleware(), gin.Recovery())
-
-	r.GET("/health", func(c *gin.Context) {
-		ctx := context.Background()
-		if err := repos.Connection.Health(ctx); err != nil {
-			c.JSON(http.StatusServiceUnavailable, gin.H{
-				"status":  "unhealthy",
-				"message": "metadata store is unhealthy",
-				"error":   err.Error(),
-			})
-			return
-		}
-		c.JSON(http.StatusOK, gin.H{
-			"status":         "healthy",
-			"version":        "0.1.0",
-			"metadata_store": "connected",
-			"plugins_loaded": len(registry.GetSupportedTypes()),
-		})
-	})
-
-	apiV1 := r.Group("/api/v1")
-	{
-		apiV1.GET("/ping", func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{
-				"message":   "pong",
-				"timestamp": time.Now().UTC().Format(time.RFC3339),
-			})
-		})
-
-		for _, l := range loaders {
-			l.RegisterRoutes(apiV1)
-		}
+		return err
 	}
+	defer func() { _ = server.Close() }()
 
-	core.ServeFrontend(r)
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
 
 	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
 	srv := &http.Server{
 		Addr:         addr,
-		Handler:      r,
+		Handler:      server,
 		ReadTimeout:  time.Duration(cfg.Server.ReadTimeout) * time.Second,
 		WriteTimeout: time.Duration(cfg.Server.WriteTimeout) * time.Second,
 	}
 
+	ln, err := devmode.Listen(addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	server.MarkStarted()
 	go func() {
 		slog.Info("starting server", "addr", addr)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
 			slog.Error("server failed", "err", err)
 			os.Exit(1)
 		}
@@ -199,10 +114,70 @@ func runServe(_ *cobra.Command, _ []string) error {
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+
+	changed := make(chan string, 1)
+	if dev {
+		paths := devWatchPaths(cfg)
+		slog.Info("development mode: watching for changes", "paths", paths)
+		go devmode.Watch(watchCtx, time.Second, paths, func(path string) {
+			select {
+			case changed <- path:
+			default:
+			}
+		})
+	}
+
+	var restart *devmode.Restart
+wait:
+	for {
+		select {
+		case <-quit:
+			break wait
+		case path := <-changed:
+			// A broken config would take the server down for good after the
+			// restart, so keep serving with the old one until it is fixed.
+			if path == cfg.ConfigFile {
+				if _, err := config.InitViper("config", ""); err != nil {
+					slog.Error("config changed but is invalid, not restarting", "err", err)
+					continue
+				}
+			}
+			if restart, err = devmode.NewRestart(path, addr, ln); err != nil {
+				slog.Error("cannot restart in place", "err", err)
+				continue
+			}
+			break wait
+		}
+	}
+	if restart != nil {
+		// In-flight requests finish; new connections queue on the socket
+		// until the next process picks it up.
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			slog.Warn("server did not shut down cleanly before restart", "err", err)
+		}
+		return restart
+	}
+
+	// Fail readiness first and keep serving for shutdown_delay so endpoints
+	// are removed from load balancers before connections start closing.
+	server.Drain()
+	if delay := time.Duration(cfg.Server.ShutdownDelay) * time.Second; delay > 0 {
+		slog.Info("draining server", "delay", delay)
+		select {
+		case <-time.After(delay):
+		case <-quit:
+			slog.Info("second signal received, skipping drain delay")
+		}
+	}
 	slog.Info("shutting down server")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	timeout := time.Duration(cfg.Server.ShutdownTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
@@ -213,3 +188,22 @@ func runServe(_ *cobra.Command, _ []string) error {
 	slog.Info("server exited")
 	return nil
 }
+
+// devWatchPaths lists the files whose changes restart a --dev server: the
+// config file and the running binary, which is rebuilt whenever an in-tree
+// plugin changes.
+func devWatchPaths(cfg *config.ViperConfig) []string {
+	var paths []string
+	if cfg.ConfigFile != "" {
+		paths = append(paths, cfg.ConfigFile)
+	}
+	if exe, err := os.Executable(); err == nil {
+		if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+			exe = resolved
+		}
+		paths = append(paths, exe)
+	} else {
+		slog.Warn("cannot watch the server binary", "err", err)
+	}
+	return paths
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"data-voyager/core/internal/buildinfo"
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/upgrade"
+)
+
+// upgradeCmd represents the upgrade command
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Check for a newer release and optionally install it",
+	Long: `Check the GitHub releases configured in [updates] for a version newer than
+this binary. With --apply, download the binary for this platform, verify it
+against the release checksums (and their signature when updates.public_key
+is set) and replace this binary with it. Restart the server afterwards.`,
+	Args: cobra.NoArgs,
+	RunE: runUpgrade,
+}
+
+var upgradeApply bool
+
+func init() {
+	rootCmd.AddCommand(upgradeCmd)
+	upgradeCmd.Flags().BoolVar(&upgradeApply, "apply", false, "download, verify and install the newer release")
+}
+
+func runUpgrade(cmd *cobra.Command, _ []string) error {
+	cfg, err := config.InitViper("config", "")
+	if err != nil {
+		return err
+	}
+	svc, err := upgrade.NewService(cfg.Updates, buildinfo.Version)
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+	out := cmd.OutOrStdout()
+
+	if !upgradeApply {
+		st, _, err := svc.Check(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("check for updates: %w", err)
+		}
+		if !st.UpdateAvailable {
+			_, _ = fmt.Fprintf(out, "Data Voyager %s is up to date (latest release: %s)\n", st.Current, st.Latest)
+			return nil
+		}
+		_, _ = fmt.Fprintf(out, "Data Voyager %s is available (running %s): %s\n", st.Latest, st.Current, st.ReleaseURL)
+		_, _ = fmt.Fprintln(out, "Run `data-voyager upgrade --apply` to install it.")
+		return nil
+	}
+
+	if !svc.Signed() {
+		_, _ = fmt.Fprintln(out, "! updates.public_key is not set; only the release checksums are verified")
+	}
+	exe, err := upgrade.Executable()
+	if err != nil {
+		return err
+	}
+	applied, err := svc.Apply(cmd.Context(), exe)
+	if errors.Is(err, upgrade.ErrUpToDate) {
+		_, _ = fmt.Fprintf(out, "Data Voyager %s is up to date\n", buildinfo.Version)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "Upgraded %s from %s to %s. Restart the server to use it.\n", applied.Path, applied.From, applied.To)
+	return nil
+}
@@ -4,12 +4,8 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
-)
 
-var (
-	version   = "0.1.0"
-	buildTime = "unknown"
-	gitCommit = "unknown"
+	"data-voyager/core/internal/buildinfo"
 )
 
 // versionCmd represents the version command
@@ -18,9 +14,9 @@ var versionCmd = &cobra.Command{
 	Short: "Print version information",
 	Long:  `Print version information for Data Voyager.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("Data Voyager %s\n", version)
-		fmt.Printf("Build time: %s\n", buildTime)
-		fmt.Printf("Git commit: %s\n", gitCommit)
+		fmt.Printf("Data Voyager %s\n", buildinfo.Version)
+		fmt.Printf("Build time: %s\n", buildinfo.BuildTime)
+		fmt.Printf("Git commit: %s\n", buildinfo.GitCommit)
 	},
 }
 
@@ -31,6 +31,24 @@ type SqlQuery = {
   limit?: number
 }
 
+// A datasource whose warehouse is resuming from an idle suspend answers 503
+// with code warehouse_resuming. Queries are sent again after Retry-After
+// until the warehouse has had WAREHOUSE_RESUME_WAIT_MS to come up.
+const WAREHOUSE_RESUME_WAIT_MS = 120_000
+
+async function retryWhileResuming<T extends { error?: unknown; response: Response }>(
+  send: () => Promise<T>,
+): Promise<T> {
+  const deadline = Date.now() + WAREHOUSE_RESUME_WAIT_MS
+  for (;;) {
+    const res = await send()
+    const code = (res.error as { code?: string } | undefined)?.code
+    const wait = (Number(res.response.headers.get('Retry-After')) || 15) * 1000
+    if (code !== 'warehouse_resuming' || Date.now() + wait > deadline) return res
+    await new Promise((resolve) => setTimeout(resolve, wait))
+  }
+}
+
 function toDatasourceInstance(ds: BackendDatasource): DatasourceInstance<Record<string, unknown>> {
   return {
     uid: ds.uid,
@@ -108,6 +126,13 @@ function toQueryResult(response: QueryResponse, requestId?: string): QueryResult
             executionTimeMs: response.stats.executionTimeMs,
             rowsReturned: response.stats.rowsReturned,
             ...(response.stats.bytesRead !== undefined ? { bytesRead: response.stats.bytesRead } : {}),
+            ...(response.stats.fetchSize !== undefined ? { fetchSize: response.stats.fetchSize } : {}),
+            ...(response.stats.batches !== undefined ? { batches: response.stats.batches } : {}),
+            ...(response.stats.estimatedBytes !== undefined ? { estimatedBytes: response.stats.estimatedBytes } : {}),
+            ...(response.stats.estimatedCost !== undefined ? { estimatedCost: response.stats.estimatedCost } : {}),
+            ...(response.stats.cost !== undefined ? { cost: response.stats.cost } : {}),
+            ...(response.stats.currency !== undefined ? { currency: response.stats.currency } : {}),
+            ...(response.rowLimit !== undefined ? { rowLimit: response.rowLimit } : {}),
           },
         }
       : {}),
@@ -135,6 +160,7 @@ function toBatchQueryResult(response: BatchQueryResponse): BatchQueryResult {
           data: item.data,
           ...(item.stats !== undefined ? { stats: item.stats } : {}),
           ...(item.inspect !== undefined ? { inspect: item.inspect } : {}),
+          ...(item.rowLimit !== undefined ? { rowLimit: item.rowLimit } : {}),
         }, item.id),
       }
     }),
@@ -197,25 +223,29 @@ const backend: DatasourceManagerBackend = {
     },
   },
   async query(request, context): Promise<QueryResult> {
-    const { data, error } = await apiClient.POST('/datasources/{uid}/query', {
-      params: { path: { uid: request.datasourceUid } },
-      body: toQueryRequest(request, context),
-    })
+    const { data, error } = await retryWhileResuming(() =>
+      apiClient.POST('/datasources/{uid}/query', {
+        params: { path: { uid: request.datasourceUid } },
+        body: toQueryRequest(request, context),
+      }),
+    )
     if (error) throw new Error((error as { error?: string }).error ?? 'Query failed')
     return toQueryResult(data, request.id)
   },
   async batchQuery(requests, context): Promise<BatchQueryResult> {
     if (requests.length === 0) return { items: [] }
     const datasourceUid = requests[0].datasourceUid
-    const { data, error } = await apiClient.POST('/datasources/{uid}/query/batch', {
-      params: { path: { uid: datasourceUid } },
-      body: {
-        queries: requests.map((request) => ({
-          id: request.id,
-          request: toQueryRequest(request, context),
-        })),
-      },
-    })
+    const { data, error } = await retryWhileResuming(() =>
+      apiClient.POST('/datasources/{uid}/query/batch', {
+        params: { path: { uid: datasourceUid } },
+        body: {
+          queries: requests.map((request) => ({
+            id: request.id,
+            request: toQueryRequest(request, context),
+          })),
+        },
+      }),
+    )
     if (error) throw new Error((error as { error?: string }).error ?? 'Batch query failed')
     return toBatchQueryResult(data)
   },
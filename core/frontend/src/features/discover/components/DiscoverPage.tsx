@@ -48,6 +48,16 @@ function toBackendTimeRange(start: DateTimeRangeValue, end: DateTimeRangeValue):
 // ─── view mode ────────────────────────────────────────────────────────────────
 type ViewMode = 'table' | 'timeseries'
 
+// formatCost shows a query's cost under its datasource's cost model, with
+// enough digits that cheap queries do not read as free.
+function formatCost(cost: unknown, currency: unknown): string {
+  return new Intl.NumberFormat(undefined, {
+    style: 'currency',
+    currency: typeof currency === 'string' ? currency : 'USD',
+    maximumSignificantDigits: 3,
+  }).format(Number(cost))
+}
+
 // ─── query item ─────────────────────────────────────────────────────────────
 type QueryItem = { refId: string; text: string; collapsed: boolean }
 const REF_IDS = 'ABCDEFGHIJKLMNOPQRSTUVWXYZ'
@@ -152,6 +162,45 @@ function ResultPanel({ item }: { item: BatchQueryResultItem }) {
             <span>{item.data.stats.rowsReturned} rows</span>
             <span>·</span>
             <span>{item.data.stats.executionTimeMs} ms</span>
+            {item.data.stats.batches !== undefined && (
+              <>
+                <span>·</span>
+                <span
+                  title={
+                    item.data.stats.fetchSize !== undefined
+                      ? `fetch size ${item.data.stats.fetchSize}`
+                      : 'driver default fetch size'
+                  }
+                >
+                  {item.data.stats.batches} batches
+                </span>
+              </>
+            )}
+            {item.data.stats.cost !== undefined && (
+              <>
+                <span>·</span>
+                <span
+                  title={
+                    item.data.stats.estimatedCost !== undefined
+                      ? `estimated ${formatCost(item.data.stats.estimatedCost, item.data.stats.currency)} before running`
+                      : undefined
+                  }
+                >
+                  {formatCost(item.data.stats.cost, item.data.stats.currency)}
+                </span>
+              </>
+            )}
+            {item.data.stats.rowLimit !== undefined && (
+              <>
+                <span>·</span>
+                <span
+                  className="text-yellow-500"
+                  title="The query had no LIMIT, so the server added one. Add a LIMIT to choose how many rows to read."
+                >
+                  limited to {item.data.stats.rowLimit} rows
+                </span>
+              </>
+            )}
           </>
         )}
         <div className="ml-auto flex items-center gap-2">
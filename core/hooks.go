@@ -0,0 +1,73 @@
+package core
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/notification"
+)
+
+// PluginRegistry holds the datasource plugins a server can use.
+type PluginRegistry = datasource.Registry
+
+// Event is a notification raised by the server: an alert, a failed
+// schedule, a health change.
+type Event = notification.Event
+
+// Router is handed to OnRouter hooks before any route is registered, so
+// middleware added with Use runs for every request, ahead of the API's
+// authentication.
+type Router struct {
+	*gin.Engine
+	api []func(*gin.RouterGroup)
+}
+
+// API adds routes to /api/v1, behind the same authentication as the
+// built-in API.
+func (r *Router) API(register func(api *gin.RouterGroup)) {
+	r.api = append(r.api, register)
+}
+
+// Hooks registered with OnRouter, OnPluginRegistry and OnEvent apply to
+// every server created afterwards. Extensions register them from init(),
+// as they do datasource plugins.
+var (
+	hooksMu        sync.Mutex
+	routerHooks    []func(*Router)
+	registryHooks  []func(*PluginRegistry)
+	eventListeners []func(context.Context, Event)
+)
+
+// OnRouter has fn customize the HTTP router of each server.
+func OnRouter(fn func(r *Router)) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	routerHooks = append(routerHooks, fn)
+}
+
+// OnPluginRegistry has fn adjust each server's plugins once the registered
+// ones and any WithPlugins are loaded, e.g. to add, replace or disable some.
+func OnPluginRegistry(fn func(r *PluginRegistry)) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	registryHooks = append(registryHooks, fn)
+}
+
+// OnEvent has fn called with every event a server raises, before it goes
+// to notification channels. fn runs on the caller's goroutine and should
+// return quickly.
+func OnEvent(fn func(ctx context.Context, ev Event)) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	eventListeners = append(eventListeners, fn)
+}
+
+// hooks returns copies of the registered hooks.
+func hooks() (router []func(*Router), registry []func(*PluginRegistry), events []func(context.Context, Event)) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	return append(router, routerHooks...), append(registry, registryHooks...), append(events, eventListeners...)
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHooks(t *testing.T) {
+	t.Cleanup(func() { routerHooks, registryHooks, eventListeners = nil, nil, nil })
+
+	OnRouter(func(r *Router) {
+		r.Use(func(c *gin.Context) {
+			if c.GetHeader("X-Key") != "secret" {
+				c.AbortWithStatus(http.StatusUnauthorized)
+				return
+			}
+			c.Next()
+		})
+		r.GET("/ext/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+		r.API(func(api *gin.RouterGroup) {
+			api.GET("/ext/hello", func(c *gin.Context) { c.String(http.StatusOK, "hello") })
+		})
+	})
+	var registry *PluginRegistry
+	OnPluginRegistry(func(r *PluginRegistry) {
+		registry = r
+		r.Register(testPlugin{})
+	})
+
+	s, err := NewServer(testConfig(t))
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+
+	require.NotNil(t, registry)
+	_, ok := registry.Get("embedded")
+	assert.True(t, ok)
+
+	assert.Equal(t, http.StatusUnauthorized, serve(s, http.MethodGet, "/api/v1/ping").Code,
+		"router middleware guards built-in routes too")
+	req := func(path string) (int, string) {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.Header.Set("X-Key", "secret")
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, r)
+		return w.Code, w.Body.String()
+	}
+	code, body := req("/ext/ping")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "pong", body)
+	code, body = req("/api/v1/ext/hello")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "hello", body)
+}
@@ -0,0 +1,58 @@
+package activity
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// Handler serves the activity feed.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates an activity HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// List handles GET /activity?types=alert,schema_changed&datasource_id=ID&before=CURSOR&limit=N
+func (h *Handler) List(c *gin.Context) {
+	if !identity.FromContext(c.Request.Context()).Can(identity.PermDatasourceRead) {
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "permission denied")})
+		return
+	}
+	opts := Options{DatasourceID: c.Query("datasource_id"), Before: c.Query("before")}
+	if v := c.Query("types"); v != "" {
+		opts.Types = strings.Split(v, ",")
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be an integer"})
+			return
+		}
+		opts.Limit = n
+	}
+	page, err := h.svc.List(c.Request.Context(), opts)
+	if err != nil {
+		if errors.Is(err, ErrInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": page})
+}
+
+// RegisterRoutes wires the handler onto r.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/activity", h.List)
+}
@@ -0,0 +1,79 @@
+// Package activity keeps the workspace activity feed: new datasources,
+// edited queries, fired alerts and schema changes, newest first, for the
+// home page. It is built on the server's events: the service listens to
+// every event raised and records the types the feed shows.
+package activity
+
+import (
+	"context"
+	"time"
+
+	"data-voyager/core/internal/notification"
+)
+
+// Types lists the event types the feed records.
+var Types = []string{
+	notification.EventDatasourceCreated,
+	notification.EventQueryEdited,
+	notification.EventAlert,
+	notification.EventSchemaChanged,
+}
+
+// Entry is one recorded event. DatasourceID is the datasource the event is
+// about, if any; Actor is the user who caused it, empty for events the
+// server raises on its own such as alerts.
+type Entry struct {
+	ID           string            `json:"id"`
+	Type         string            `json:"type"`
+	Severity     string            `json:"severity"`
+	Title        string            `json:"title"`
+	Body         string            `json:"body,omitempty"`
+	Source       string            `json:"source,omitempty"`
+	DatasourceID string            `json:"datasource_id,omitempty"`
+	Actor        string            `json:"actor,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// Page is one page of the feed. NextCursor is passed back as Before to
+// read the next page; it is empty on the last one.
+type Page struct {
+	Entries    []*Entry `json:"entries"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// Options narrows a feed request.
+type Options struct {
+	// Types limits the event types returned; empty means all of Types.
+	Types        []string
+	DatasourceID string
+	// Before is the cursor of the previous page; empty starts at the
+	// newest entry.
+	Before string
+	Limit  int
+}
+
+// Filter selects entries from a Repository, newest first. Before is an
+// entry ID: IDs are time-ordered, so entries with smaller IDs are older.
+type Filter struct {
+	Types        []string
+	DatasourceID string
+	Before       string
+	Limit        int
+}
+
+// Repository persists feed entries in the statistics store.
+type Repository interface {
+	Record(ctx context.Context, e *Entry) error
+	List(ctx context.Context, f Filter) ([]*Entry, error)
+}
+
+// NoopRepository discards entries and lists none. Used when
+// statistics_store is not configured.
+type NoopRepository struct{}
+
+func (NoopRepository) Record(_ context.Context, _ *Entry) error { return nil }
+
+func (NoopRepository) List(_ context.Context, _ Filter) ([]*Entry, error) {
+	return []*Entry{}, nil
+}
@@ -0,0 +1,152 @@
+package activity
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/notification"
+)
+
+// Page sizes.
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// maxScans bounds how many batches List reads to fill a page when the
+// caller cannot see some of the entries.
+const maxScans = 5
+
+// ErrInvalid wraps request validation failures.
+var ErrInvalid = errors.New("invalid activity request")
+
+// Service records events into the feed and reads it back.
+type Service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+// NewService creates an activity service.
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo, now: time.Now}
+}
+
+// Listen records ev if the feed shows its type. It is registered as a
+// notification listener, so a failure is logged rather than returned and
+// the event is delivered regardless.
+func (s *Service) Listen(ctx context.Context, ev notification.Event) {
+	if !slices.Contains(Types, ev.Type) {
+		return
+	}
+	id, err := uuid.NewV7()
+	if err != nil {
+		return
+	}
+	// The actor has a column of its own, which personal data erasure
+	// covers; it is not kept twice.
+	labels := maps.Clone(ev.Labels)
+	delete(labels, "user")
+	at := ev.Time
+	if at.IsZero() {
+		at = s.now()
+	}
+	e := &Entry{
+		ID:           id.String(),
+		Type:         ev.Type,
+		Severity:     cmp.Or(ev.Severity, notification.SeverityInfo),
+		Title:        ev.Title,
+		Body:         ev.Body,
+		Source:       ev.Source,
+		DatasourceID: cmp.Or(ev.Labels["datasource_id"], ev.Labels["source_datasource_id"]),
+		Actor:        ev.Labels["user"],
+		Labels:       labels,
+		CreatedAt:    at.UTC(),
+	}
+	// The event may be raised by a request that is about to finish.
+	if err := s.repo.Record(context.WithoutCancel(ctx), e); err != nil {
+		slog.Warn("activity not recorded", "type", ev.Type, "err", err)
+	}
+}
+
+// List returns a page of the feed, newest first. Entries about datasources
+// hidden from the caller are left out.
+func (s *Service) List(ctx context.Context, opts Options) (*Page, error) {
+	limit := opts.Limit
+	switch {
+	case limit == 0:
+		limit = DefaultLimit
+	case limit < 0 || limit > MaxLimit:
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalid, MaxLimit)
+	}
+	for _, t := range opts.Types {
+		if !slices.Contains(Types, t) {
+			return nil, fmt.Errorf("%w: unknown type %q", ErrInvalid, t)
+		}
+	}
+	caller := identity.FromContext(ctx)
+	page := &Page{Entries: []*Entry{}}
+	if opts.DatasourceID != "" && !caller.CanSee(opts.DatasourceID) {
+		return page, nil
+	}
+
+	f := Filter{Types: opts.Types, DatasourceID: opts.DatasourceID, Before: opts.Before, Limit: limit}
+	for range maxScans {
+		batch, err := s.repo.List(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range batch {
+			if visible(caller, e) {
+				page.Entries = append(page.Entries, e)
+			}
+		}
+		if len(batch) < f.Limit {
+			return trim(page, limit), nil
+		}
+		f.Before = batch[len(batch)-1].ID
+		if len(page.Entries) >= limit {
+			break
+		}
+	}
+	if len(page.Entries) > limit {
+		return trim(page, limit), nil
+	}
+	// The page is full, or as full as maxScans batches could make it; the
+	// rest of the feed may still have entries.
+	page.NextCursor = f.Before
+	return page, nil
+}
+
+// trim cuts page to limit entries, pointing the cursor after the last one
+// kept when entries were cut.
+func trim(page *Page, limit int) *Page {
+	if len(page.Entries) > limit {
+		page.Entries = page.Entries[:limit]
+		page.NextCursor = page.Entries[limit-1].ID
+	}
+	return page
+}
+
+// visible reports whether caller may see e: every datasource it names,
+// under any *datasource_id label, must be visible to them.
+func visible(caller *identity.Identity, e *Entry) bool {
+	if e.DatasourceID != "" && !caller.CanSee(e.DatasourceID) {
+		return false
+	}
+	for k, v := range e.Labels {
+		if strings.HasSuffix(k, "datasource_id") && v != "" && !caller.CanSee(v) {
+			return false
+		}
+	}
+	return true
+}
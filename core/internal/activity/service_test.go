@@ -0,0 +1,120 @@
+package activity
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/notification"
+)
+
+// memRepo keeps entries in memory, filtering them like the SQL stores.
+type memRepo struct {
+	entries []*Entry
+	lists   int
+}
+
+func (m *memRepo) Record(_ context.Context, e *Entry) error {
+	m.entries = append(m.entries, e)
+	return nil
+}
+
+func (m *memRepo) List(_ context.Context, f Filter) ([]*Entry, error) {
+	m.lists++
+	out := []*Entry{}
+	for _, e := range slices.Backward(m.entries) {
+		if (f.DatasourceID == "" || e.DatasourceID == f.DatasourceID) && (f.Before == "" || e.ID < f.Before) &&
+			(len(f.Types) == 0 || slices.Contains(f.Types, e.Type)) && len(out) < f.Limit {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func as(name string, datasources ...string) context.Context {
+	return identity.With(context.Background(), &identity.Identity{Username: name, Role: identity.RoleViewer, Datasources: datasources})
+}
+
+func TestListen(t *testing.T) {
+	repo := &memRepo{}
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	svc.Listen(ctx, notification.Event{Type: notification.EventHealth, Title: "store down"})
+	assert.Empty(t, repo.entries, "health events are not activity")
+
+	svc.Listen(ctx, notification.Event{
+		Type: notification.EventDatasourceCreated, Title: "Datasource orders was added", Source: "orders",
+		Labels: map[string]string{"datasource_id": "pg", "user": "ann"},
+	})
+	require.Len(t, repo.entries, 1)
+	e := repo.entries[0]
+	assert.Equal(t, notification.SeverityInfo, e.Severity)
+	assert.Equal(t, "pg", e.DatasourceID)
+	assert.Equal(t, "ann", e.Actor)
+	assert.Equal(t, map[string]string{"datasource_id": "pg"}, e.Labels, "the actor is not kept twice")
+	assert.False(t, e.CreatedAt.IsZero())
+}
+
+func TestList_Pages(t *testing.T) {
+	repo := &memRepo{}
+	svc := NewService(repo)
+	for range 5 {
+		svc.Listen(context.Background(), notification.Event{Type: notification.EventAlert, Title: "disk full"})
+	}
+	svc.Listen(context.Background(), notification.Event{Type: notification.EventSchemaChanged, Title: "schema"})
+
+	page, err := svc.List(context.Background(), Options{Types: []string{notification.EventAlert}, Limit: 3})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 3)
+	assert.Equal(t, repo.entries[4].ID, page.Entries[0].ID, "newest first")
+	require.NotEmpty(t, page.NextCursor)
+
+	page, err = svc.List(context.Background(), Options{Types: []string{notification.EventAlert}, Before: page.NextCursor, Limit: 3})
+	require.NoError(t, err)
+	assert.Len(t, page.Entries, 2)
+	assert.Empty(t, page.NextCursor, "last page")
+}
+
+func TestList_HidesDatasources(t *testing.T) {
+	repo := &memRepo{}
+	svc := NewService(repo)
+	for _, ds := range []string{"pg", "ch", "ch", "ch", "pg"} {
+		svc.Listen(context.Background(), notification.Event{
+			Type: notification.EventQueryEdited, Title: "view saved", Labels: map[string]string{"datasource_id": ds},
+		})
+	}
+	svc.Listen(context.Background(), notification.Event{
+		Type: notification.EventAlert, Title: "copy failed", Labels: map[string]string{"source_datasource_id": "pg", "target_datasource_id": "ch"},
+	})
+
+	page, err := svc.List(as("eve", "pg"), Options{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2, "scans past entries the caller cannot see")
+	for _, e := range page.Entries {
+		assert.Equal(t, "pg", e.DatasourceID)
+	}
+
+	page, err = svc.List(as("eve", "pg"), Options{DatasourceID: "ch"})
+	require.NoError(t, err)
+	assert.Empty(t, page.Entries)
+	assert.NotNil(t, page.Entries)
+}
+
+func TestList_Validates(t *testing.T) {
+	svc := NewService(&memRepo{})
+	for name, opts := range map[string]Options{
+		"negative limit": {Limit: -1},
+		"limit too big":  {Limit: MaxLimit + 1},
+		"unknown type":   {Types: []string{notification.EventHealth}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.List(context.Background(), opts)
+			assert.ErrorIs(t, err, ErrInvalid)
+		})
+	}
+}
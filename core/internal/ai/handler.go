@@ -8,6 +8,8 @@ import (
 
 	"data-voyager/core/internal/config"
 	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
 
 	"github.com/gin-gonic/gin"
 )
@@ -82,9 +84,13 @@ type chatBody struct {
 // Chat handles POST /api/v1/datasources/:uid/ai/chat
 // and streams the agent response as Server-Sent Events.
 func (h *Handler) Chat(c *gin.Context) {
+	if !identity.FromContext(c.Request.Context()).Can(identity.PermDatasourceQuery) {
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "permission denied")})
+		return
+	}
 	datasourceUID := c.Param("uid")
 	if datasourceUID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid datasource uid"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "invalid datasource uid")})
 		return
 	}
 
@@ -94,14 +100,14 @@ func (h *Handler) Chat(c *gin.Context) {
 		return
 	}
 	if len(body.Messages) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "messages required"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "messages required")})
 		return
 	}
 
 	// Build provider from DB/config
 	effCfg, err := h.resolveConfig(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load AI config"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to load AI config")})
 		return
 	}
 
@@ -114,7 +120,7 @@ func (h *Handler) Chat(c *gin.Context) {
 	// Look up connection to get dialect for system prompt
 	conn, err := h.repo.GetConnByID(c.Request.Context(), datasourceUID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "datasource not found"})
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "datasource not found")})
 		return
 	}
 
@@ -7,6 +7,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+
+	"data-voyager/core/internal/i18n"
 )
 
 // Handler serves CRUD endpoints for AI configs.
@@ -71,7 +73,7 @@ func toResponse(cfg *AIConfig) aiConfigResponse {
 func (h *Handler) List(c *gin.Context) {
 	cfgs, err := h.svc.List(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list AI configs"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to list AI configs")})
 		return
 	}
 	resp := make([]aiConfigResponse, len(cfgs))
@@ -86,7 +88,7 @@ func (h *Handler) GetByID(c *gin.Context) {
 	id := c.Param("id")
 	cfg, err := h.svc.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "AI config not found"})
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "AI config not found")})
 		return
 	}
 	// check if api_key is set by reading raw from repo indirectly via HasAPIKey
@@ -100,18 +102,18 @@ func (h *Handler) GetByID(c *gin.Context) {
 func (h *Handler) Create(c *gin.Context) {
 	var body createAIConfigRequest
 	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "invalid request body")})
 		return
 	}
 
 	if !validProvider(body.Provider) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "provider must be one of: claude, openai, copilot, ollama"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "provider must be one of: claude, openai, copilot, ollama")})
 		return
 	}
 
 	newID, err := uuid.NewV7()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate id"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to generate id")})
 		return
 	}
 
@@ -128,7 +130,7 @@ func (h *Handler) Create(c *gin.Context) {
 	}
 
 	if err := h.svc.Create(c.Request.Context(), cfg); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create AI config"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to create AI config")})
 		return
 	}
 
@@ -142,12 +144,12 @@ func (h *Handler) Update(c *gin.Context) {
 	id := c.Param("id")
 	var body updateAIConfigRequest
 	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "invalid request body")})
 		return
 	}
 
 	if body.Provider != "" && !validProvider(body.Provider) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "provider must be one of: claude, openai, copilot, ollama"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "provider must be one of: claude, openai, copilot, ollama")})
 		return
 	}
 
@@ -160,13 +162,13 @@ func (h *Handler) Update(c *gin.Context) {
 	}
 
 	if err := h.svc.Update(c.Request.Context(), id, input); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update AI config"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to update AI config")})
 		return
 	}
 
 	cfg, err := h.svc.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reload AI config"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to reload AI config")})
 		return
 	}
 	hasKey, _ := h.svc.HasAPIKey(c.Request.Context(), id)
@@ -179,7 +181,7 @@ func (h *Handler) Update(c *gin.Context) {
 func (h *Handler) Delete(c *gin.Context) {
 	id := c.Param("id")
 	if err := h.svc.Delete(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete AI config"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to delete AI config")})
 		return
 	}
 	c.Status(http.StatusNoContent)
@@ -189,7 +191,7 @@ func (h *Handler) Delete(c *gin.Context) {
 func (h *Handler) Activate(c *gin.Context) {
 	id := c.Param("id")
 	if err := h.svc.SetActive(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to activate AI config"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to activate AI config")})
 		return
 	}
 	c.Status(http.StatusNoContent)
@@ -230,7 +232,7 @@ func (h *Handler) ListHistory(c *gin.Context) {
 	limit, offset := parsePagination(c)
 	records, err := h.svc.historyRepo.List(c.Request.Context(), limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list AI config history"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to list AI config history")})
 		return
 	}
 	resp := make([]aiConfigHistoryResponse, len(records))
@@ -246,7 +248,7 @@ func (h *Handler) ListHistoryByConfig(c *gin.Context) {
 	limit, offset := parsePagination(c)
 	records, err := h.svc.historyRepo.ListByConfig(c.Request.Context(), id, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list AI config history"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to list AI config history")})
 		return
 	}
 	resp := make([]aiConfigHistoryResponse, len(records))
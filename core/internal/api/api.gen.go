@@ -69,6 +69,120 @@ func (e AIConfigHistoryAction) Valid() bool {
 	}
 }
 
+// Defines values for AnalyzeResultMethod.
+const (
+	Sample AnalyzeResultMethod = "sample"
+	Sql    AnalyzeResultMethod = "sql"
+)
+
+// Valid indicates whether the value is a known member of the AnalyzeResultMethod enum.
+func (e AnalyzeResultMethod) Valid() bool {
+	switch e {
+	case Sample:
+		return true
+	case Sql:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for AsyncQueryStatus.
+const (
+	Failed    AsyncQueryStatus = "failed"
+	Running   AsyncQueryStatus = "running"
+	Succeeded AsyncQueryStatus = "succeeded"
+)
+
+// Valid indicates whether the value is a known member of the AsyncQueryStatus enum.
+func (e AsyncQueryStatus) Valid() bool {
+	switch e {
+	case Failed:
+		return true
+	case Running:
+		return true
+	case Succeeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for BulkDatasourceOperationOp.
+const (
+	Activate   BulkDatasourceOperationOp = "activate"
+	Create     BulkDatasourceOperationOp = "create"
+	Deactivate BulkDatasourceOperationOp = "deactivate"
+	Delete     BulkDatasourceOperationOp = "delete"
+	Update     BulkDatasourceOperationOp = "update"
+)
+
+// Valid indicates whether the value is a known member of the BulkDatasourceOperationOp enum.
+func (e BulkDatasourceOperationOp) Valid() bool {
+	switch e {
+	case Activate:
+		return true
+	case Create:
+		return true
+	case Deactivate:
+		return true
+	case Delete:
+		return true
+	case Update:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for ColumnFilterOp.
+const (
+	Contains   ColumnFilterOp = "contains"
+	EndsWith   ColumnFilterOp = "ends_with"
+	Eq         ColumnFilterOp = "eq"
+	Ge         ColumnFilterOp = "ge"
+	Gt         ColumnFilterOp = "gt"
+	In         ColumnFilterOp = "in"
+	IsNull     ColumnFilterOp = "is_null"
+	Le         ColumnFilterOp = "le"
+	Lt         ColumnFilterOp = "lt"
+	Ne         ColumnFilterOp = "ne"
+	NotNull    ColumnFilterOp = "not_null"
+	StartsWith ColumnFilterOp = "starts_with"
+)
+
+// Valid indicates whether the value is a known member of the ColumnFilterOp enum.
+func (e ColumnFilterOp) Valid() bool {
+	switch e {
+	case Contains:
+		return true
+	case EndsWith:
+		return true
+	case Eq:
+		return true
+	case Ge:
+		return true
+	case Gt:
+		return true
+	case In:
+		return true
+	case IsNull:
+		return true
+	case Le:
+		return true
+	case Lt:
+		return true
+	case Ne:
+		return true
+	case NotNull:
+		return true
+	case StartsWith:
+		return true
+	default:
+		return false
+	}
+}
+
 // Defines values for CreateAIConfigRequestProvider.
 const (
 	CreateAIConfigRequestProviderClaude  CreateAIConfigRequestProvider = "claude"
@@ -114,6 +228,48 @@ func (e DatasourceHistoryAction) Valid() bool {
 	}
 }
 
+// Defines values for DialectParameterStyle.
+const (
+	Dollar   DialectParameterStyle = "dollar"
+	None     DialectParameterStyle = "none"
+	Question DialectParameterStyle = "question"
+)
+
+// Valid indicates whether the value is a known member of the DialectParameterStyle enum.
+func (e DialectParameterStyle) Valid() bool {
+	switch e {
+	case Dollar:
+		return true
+	case None:
+		return true
+	case Question:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for Environment.
+const (
+	Dev     Environment = "dev"
+	Prod    Environment = "prod"
+	Staging Environment = "staging"
+)
+
+// Valid indicates whether the value is a known member of the Environment enum.
+func (e Environment) Valid() bool {
+	switch e {
+	case Dev:
+		return true
+	case Prod:
+		return true
+	case Staging:
+		return true
+	default:
+		return false
+	}
+}
+
 // Defines values for FieldKind.
 const (
 	Boolean FieldKind = "boolean"
@@ -159,6 +315,75 @@ func (e FrameType) Valid() bool {
 	}
 }
 
+// Defines values for ParameterViolationsResponsePolicy.
+const (
+	Off    ParameterViolationsResponsePolicy = "off"
+	Reject ParameterViolationsResponsePolicy = "reject"
+	Warn   ParameterViolationsResponsePolicy = "warn"
+)
+
+// Valid indicates whether the value is a known member of the ParameterViolationsResponsePolicy enum.
+func (e ParameterViolationsResponsePolicy) Valid() bool {
+	switch e {
+	case Off:
+		return true
+	case Reject:
+		return true
+	case Warn:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for PivotTransformAggregate.
+const (
+	Avg   PivotTransformAggregate = "avg"
+	Count PivotTransformAggregate = "count"
+	First PivotTransformAggregate = "first"
+	Max   PivotTransformAggregate = "max"
+	Min   PivotTransformAggregate = "min"
+	Sum   PivotTransformAggregate = "sum"
+)
+
+// Valid indicates whether the value is a known member of the PivotTransformAggregate enum.
+func (e PivotTransformAggregate) Valid() bool {
+	switch e {
+	case Avg:
+		return true
+	case Count:
+		return true
+	case First:
+		return true
+	case Max:
+		return true
+	case Min:
+		return true
+	case Sum:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for QueryTransformType.
+const (
+	Pivot   QueryTransformType = "pivot"
+	Unpivot QueryTransformType = "unpivot"
+)
+
+// Valid indicates whether the value is a known member of the QueryTransformType enum.
+func (e QueryTransformType) Valid() bool {
+	switch e {
+	case Pivot:
+		return true
+	case Unpivot:
+		return true
+	default:
+		return false
+	}
+}
+
 // Defines values for UpdateAIConfigRequestProvider.
 const (
 	Claude  UpdateAIConfigRequestProvider = "claude"
@@ -183,6 +408,24 @@ func (e UpdateAIConfigRequestProvider) Valid() bool {
 	}
 }
 
+// Defines values for GetTableRowsParamsOrder.
+const (
+	Asc  GetTableRowsParamsOrder = "asc"
+	Desc GetTableRowsParamsOrder = "desc"
+)
+
+// Valid indicates whether the value is a known member of the GetTableRowsParamsOrder enum.
+func (e GetTableRowsParamsOrder) Valid() bool {
+	switch e {
+	case Asc:
+		return true
+	case Desc:
+		return true
+	default:
+		return false
+	}
+}
+
 // AIConfig defines model for AIConfig.
 type AIConfig struct {
 	// ApiKeySet Whether an API key has been stored (key value never returned)
@@ -238,6 +481,88 @@ type AISettingsData struct {
 	Provider string                   `json:"provider"`
 }
 
+// AnalyzeRequest defines model for AnalyzeRequest.
+type AnalyzeRequest struct {
+	Columns []string `json:"columns"`
+
+	// Query Query whose result is analyzed, as a subquery.
+	Query *string `json:"query,omitempty"`
+
+	// SampleLimit Row cap when statistics are computed from a sample.
+	SampleLimit *int    `json:"sample_limit,omitempty"`
+	Schema      *string `json:"schema,omitempty"`
+
+	// Table Table to analyze. Exactly one of table and query is required.
+	Table *string `json:"table,omitempty"`
+}
+
+// AnalyzeResponse defines model for AnalyzeResponse.
+type AnalyzeResponse struct {
+	Data AnalyzeResult `json:"data"`
+
+	// Warnings Non-fatal notices about the datasource, e.g. deprecation.
+	Warnings *[]string `json:"warnings,omitempty"`
+}
+
+// AnalyzeResult defines model for AnalyzeResult.
+type AnalyzeResult struct {
+	Columns []ColumnStats `json:"columns"`
+
+	// Correlation Pearson correlation of each pair of columns; matrix[i][j] is null when either column is constant or they share fewer than two rows.
+	Correlation CorrelationMatrix   `json:"correlation"`
+	Method      AnalyzeResultMethod `json:"method"`
+
+	// Rows Rows scanned, including those null in every column.
+	Rows int64 `json:"rows"`
+}
+
+// AnalyzeResultMethod defines model for AnalyzeResult.Method.
+type AnalyzeResultMethod string
+
+// ApplyDatasourceRequest defines model for ApplyDatasourceRequest.
+type ApplyDatasourceRequest struct {
+	// Enabled Defaults to true.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Environment Deployment environment label.
+	Environment *Environment            `json:"environment,omitempty"`
+	Meta        *map[string]interface{} `json:"meta,omitempty"`
+	Name        string                  `json:"name"`
+	Options     map[string]interface{}  `json:"options"`
+
+	// QueryTimeout Maximum query run time in seconds for this datasource; 0 means no datasource limit.
+	QueryTimeout *int                `json:"queryTimeout,omitempty"`
+	TemplateUid  *openapi_types.UUID `json:"templateUid,omitempty"`
+
+	// Type Datasource type; cannot change once created.
+	Type string `json:"type"`
+}
+
+// AsyncQuery defines model for AsyncQuery.
+type AsyncQuery struct {
+	// Archived True when the result was spilled to object storage.
+	Archived      bool               `json:"archived"`
+	CompletedAt   *time.Time         `json:"completedAt,omitempty"`
+	CreatedAt     time.Time          `json:"createdAt"`
+	DatasourceUid openapi_types.UUID `json:"datasourceUid"`
+
+	// ExpiresAt When the result is dropped; running queries do not expire.
+	ExpiresAt time.Time `json:"expiresAt"`
+	Id        string    `json:"id"`
+
+	// SizeBytes Encoded size of the stored result once finished.
+	SizeBytes *int64           `json:"sizeBytes,omitempty"`
+	Status    AsyncQueryStatus `json:"status"`
+}
+
+// AsyncQueryStatus defines model for AsyncQuery.Status.
+type AsyncQueryStatus string
+
+// AsyncQueryResponse defines model for AsyncQueryResponse.
+type AsyncQueryResponse struct {
+	Data AsyncQuery `json:"data"`
+}
+
 // BatchQueryItem defines model for BatchQueryItem.
 type BatchQueryItem struct {
 	// Id Query identifier (e.g. "A", "B"). Returned in results.
@@ -253,6 +578,9 @@ type BatchQueryRequest struct {
 // BatchQueryResponse defines model for BatchQueryResponse.
 type BatchQueryResponse struct {
 	Results []BatchQueryResultItem `json:"results"`
+
+	// Warnings Non-fatal notices about the datasource, e.g. deprecation, and about each query, prefixed with its id.
+	Warnings *[]string `json:"warnings,omitempty"`
 }
 
 // BatchQueryResultItem defines model for BatchQueryResultItem.
@@ -260,10 +588,63 @@ type BatchQueryResultItem struct {
 	Data *QueryResult `json:"data,omitempty"`
 
 	// Error Per-query error message if this query failed.
-	Error   *string       `json:"error,omitempty"`
-	Id      string        `json:"id"`
-	Inspect *QueryInspect `json:"inspect,omitempty"`
-	Stats   *QueryStats   `json:"stats,omitempty"`
+	Error *string `json:"error,omitempty"`
+
+	// ErrorCode Machine-readable code for error, e.g. "query_timeout".
+	ErrorCode *string `json:"errorCode,omitempty"`
+
+	// ErrorColumn 1-based column of the query the error points at, when known.
+	ErrorColumn *int `json:"errorColumn,omitempty"`
+
+	// ErrorLine 1-based line of the query the error points at, when known.
+	ErrorLine *int          `json:"errorLine,omitempty"`
+	Id        string        `json:"id"`
+	Inspect   *QueryInspect `json:"inspect,omitempty"`
+
+	// RowLimit LIMIT the server added to this query; see QueryResponse.rowLimit.
+	RowLimit *int        `json:"rowLimit,omitempty"`
+	Stats    *QueryStats `json:"stats,omitempty"`
+}
+
+// BulkDatasourceOperation defines model for BulkDatasourceOperation.
+type BulkDatasourceOperation struct {
+	Changes    *UpdateDatasourceRequest `json:"changes,omitempty"`
+	Datasource *CreateDatasourceRequest `json:"datasource,omitempty"`
+
+	// Force For delete, also remove resources that reference the datasource
+	Force *bool                     `json:"force,omitempty"`
+	Op    BulkDatasourceOperationOp `json:"op"`
+
+	// Uid Target datasource; required for every op except create
+	Uid *openapi_types.UUID `json:"uid,omitempty"`
+}
+
+// BulkDatasourceOperationOp defines model for BulkDatasourceOperation.Op.
+type BulkDatasourceOperationOp string
+
+// BulkDatasourceRequest defines model for BulkDatasourceRequest.
+type BulkDatasourceRequest struct {
+	Operations []BulkDatasourceOperation `json:"operations"`
+}
+
+// BulkDatasourceResponse defines model for BulkDatasourceResponse.
+type BulkDatasourceResponse struct {
+	Data      []BulkDatasourceResult `json:"data"`
+	Failed    int                    `json:"failed"`
+	Succeeded int                    `json:"succeeded"`
+}
+
+// BulkDatasourceResult defines model for BulkDatasourceResult.
+type BulkDatasourceResult struct {
+	Data      *Datasource `json:"data,omitempty"`
+	Error     *string     `json:"error,omitempty"`
+	ErrorCode *string     `json:"errorCode,omitempty"`
+	Index     int         `json:"index"`
+	Op        string      `json:"op"`
+
+	// Status HTTP status of the operation
+	Status int                 `json:"status"`
+	Uid    *openapi_types.UUID `json:"uid,omitempty"`
 }
 
 // ClaudeSettingsInput defines model for ClaudeSettingsInput.
@@ -281,6 +662,32 @@ type ClaudeSettingsResponse struct {
 	Model     *string `json:"model,omitempty"`
 }
 
+// ColumnFilter defines model for ColumnFilter.
+type ColumnFilter struct {
+	Column string `json:"column"`
+
+	// Op contains, starts_with and ends_with match text case-insensitively; in takes an array value; is_null and not_null take none.
+	Op    ColumnFilterOp `json:"op"`
+	Value interface{}    `json:"value,omitempty"`
+}
+
+// ColumnFilterOp contains, starts_with and ends_with match text case-insensitively; in takes an array value; is_null and not_null take none.
+type ColumnFilterOp string
+
+// ColumnStats Statistics over the non-null values; absent when there are none.
+type ColumnStats struct {
+	Count  int64    `json:"count"`
+	Max    *float64 `json:"max,omitempty"`
+	Mean   *float64 `json:"mean,omitempty"`
+	Median *float64 `json:"median,omitempty"`
+	Min    *float64 `json:"min,omitempty"`
+	Name   string   `json:"name"`
+	Nulls  int64    `json:"nulls"`
+	P25    *float64 `json:"p25,omitempty"`
+	P75    *float64 `json:"p75,omitempty"`
+	Stddev *float64 `json:"stddev,omitempty"`
+}
+
 // CopilotSettingsInput defines model for CopilotSettingsInput.
 type CopilotSettingsInput struct {
 	// ApiKey Empty string keeps the existing key
@@ -296,6 +703,24 @@ type CopilotSettingsResponse struct {
 	Model     *string `json:"model,omitempty"`
 }
 
+// CorrelationMatrix Pearson correlation of each pair of columns; matrix[i][j] is null when either column is constant or they share fewer than two rows.
+type CorrelationMatrix struct {
+	Columns []string     `json:"columns"`
+	Matrix  [][]*float64 `json:"matrix"`
+}
+
+// CostModel defines model for CostModel.
+type CostModel struct {
+	// Currency ISO 4217 code the price is in; defaults to USD.
+	Currency *string `json:"currency,omitempty"`
+
+	// MinBytesBilled Bytes billed for any query that scans less, but more than nothing; e.g. 10485760 for BigQuery.
+	MinBytesBilled *int64 `json:"minBytesBilled,omitempty"`
+
+	// PerTib Price per tebibyte (2^40 bytes) scanned, e.g. 6.25 for BigQuery on-demand pricing.
+	PerTib float64 `json:"perTib"`
+}
+
 // CreateAIConfigRequest defines model for CreateAIConfigRequest.
 type CreateAIConfigRequest struct {
 	ApiKey   *string                       `json:"api_key,omitempty"`
@@ -310,10 +735,44 @@ type CreateAIConfigRequestProvider string
 
 // CreateDatasourceRequest defines model for CreateDatasourceRequest.
 type CreateDatasourceRequest struct {
-	Meta    *map[string]interface{} `json:"meta,omitempty"`
-	Name    string                  `json:"name"`
-	Options map[string]interface{}  `json:"options"`
-	Type    string                  `json:"type"`
+	// Alias Stable name saved queries can use instead of the uid, unique within the environment.
+	Alias *string `json:"alias,omitempty"`
+
+	// Environment Deployment environment label.
+	Environment *Environment            `json:"environment,omitempty"`
+	ExternalId  *string                 `json:"externalId,omitempty"`
+	Meta        *map[string]interface{} `json:"meta,omitempty"`
+	Name        string                  `json:"name"`
+
+	// Options Driver options. With templateUid set, these override the template's options.
+	Options map[string]interface{} `json:"options"`
+
+	// QueryTimeout Maximum query run time in seconds for this datasource; 0 means no datasource limit.
+	QueryTimeout *int `json:"queryTimeout,omitempty"`
+
+	// Replicas Read replicas for read-only queries, tried in order before the primary. Replaces the current list.
+	Replicas    *[]DatasourceReplica `json:"replicas,omitempty"`
+	TemplateUid *openapi_types.UUID  `json:"templateUid,omitempty"`
+	Type        string               `json:"type"`
+}
+
+// CreateDatasourceTemplateRequest defines model for CreateDatasourceTemplateRequest.
+type CreateDatasourceTemplateRequest struct {
+	Description *string                `json:"description,omitempty"`
+	Name        string                 `json:"name"`
+	Options     map[string]interface{} `json:"options"`
+	Tags        *[]string              `json:"tags,omitempty"`
+	Type        string                 `json:"type"`
+}
+
+// CreatedDatasourceKey defines model for CreatedDatasourceKey.
+type CreatedDatasourceKey struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Id        string    `json:"id"`
+
+	// Key Send as X-Voyager-Datasource-Key. Shown only once.
+	Key  string `json:"key"`
+	Name string `json:"name"`
 }
 
 // DataFrame defines model for DataFrame.
@@ -327,18 +786,86 @@ type DataFrame struct {
 
 // Datasource defines model for Datasource.
 type Datasource struct {
-	CreatedAt time.Time               `json:"createdAt"`
-	Enabled   bool                    `json:"enabled"`
-	Meta      *map[string]interface{} `json:"meta,omitempty"`
-	Name      string                  `json:"name"`
+	// Alias Stable name saved queries can use instead of the uid; each environment resolves it to its own datasource.
+	Alias       *string      `json:"alias,omitempty"`
+	CostModel   *CostModel   `json:"costModel,omitempty"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	Deprecation *Deprecation `json:"deprecation,omitempty"`
+	Enabled     bool         `json:"enabled"`
+
+	// Environment Deployment environment label (dev, staging, prod).
+	Environment *string `json:"environment,omitempty"`
+
+	// ExternalId Caller-assigned stable identifier, e.g. a Terraform resource address.
+	ExternalId    *string                 `json:"externalId,omitempty"`
+	Maintenance   *MaintenanceWindow      `json:"maintenance,omitempty"`
+	Meta          *map[string]interface{} `json:"meta,omitempty"`
+	Name          string                  `json:"name"`
+	NetworkPolicy *NetworkPolicy          `json:"networkPolicy,omitempty"`
 
 	// Options Driver-specific datasource options
 	Options json.RawMessage `json:"options"`
 
+	// Overrides Options set on the datasource itself when it inherits from a template; options holds the merged result.
+	Overrides *json.RawMessage `json:"overrides,omitempty"`
+
+	// QueryTimeout Maximum query run time in seconds for this datasource; 0 means no datasource limit.
+	QueryTimeout *int                 `json:"queryTimeout,omitempty"`
+	Replicas     *[]DatasourceReplica `json:"replicas,omitempty"`
+
+	// TemplateUid Template this datasource inherits defaults from.
+	TemplateUid *openapi_types.UUID `json:"templateUid,omitempty"`
+
 	// Type Datasource type identifier (e.g. "postgresql", "clickhouse")
 	Type      string             `json:"type"`
 	Uid       openapi_types.UUID `json:"uid"`
 	UpdatedAt time.Time          `json:"updatedAt"`
+
+	// Version Incremented on every change; also returned as the ETag.
+	Version *int64 `json:"version,omitempty"`
+}
+
+// DatasourceCapabilities defines model for DatasourceCapabilities.
+type DatasourceCapabilities struct {
+	// Exec Statements returning no rows (DDL, DML) can be run.
+	Exec bool `json:"exec"`
+
+	// Explain EXPLAIN is supported for query plans.
+	Explain bool `json:"explain"`
+
+	// Ingest NDJSON records can be bulk-loaded with POST /datasources/{uid}/ingest.
+	Ingest bool `json:"ingest"`
+
+	// NamedParams QueryRequest.parameters are bound by the datasource server-side, e.g. ClickHouse {name:Type} placeholders.
+	NamedParams bool `json:"namedParams"`
+
+	// Schemas The schema tree can be browsed.
+	Schemas bool `json:"schemas"`
+
+	// Streaming Results can be consumed incrementally.
+	Streaming bool `json:"streaming"`
+
+	// Transactions Multi-statement transactions are supported.
+	Transactions bool `json:"transactions"`
+
+	// Writes INSERT/UPDATE/DELETE are accepted.
+	Writes bool `json:"writes"`
+}
+
+// DatasourceDialect defines model for DatasourceDialect.
+type DatasourceDialect struct {
+	Functions       []DialectFunction `json:"functions"`
+	IdentifierQuote string            `json:"identifierQuote"`
+
+	// ParameterStyle How bind parameters are written, e.g. $1 (dollar) or ? (question).
+	ParameterStyle DialectParameterStyle `json:"parameterStyle"`
+	ReservedWords  []string              `json:"reservedWords"`
+	StringQuote    string                `json:"stringQuote"`
+}
+
+// DatasourceDialectResponse defines model for DatasourceDialectResponse.
+type DatasourceDialectResponse struct {
+	Data DatasourceDialect `json:"data"`
 }
 
 // DatasourceHistory defines model for DatasourceHistory.
@@ -359,11 +886,40 @@ type DatasourceHistoryListResponse struct {
 	Data []DatasourceHistory `json:"data"`
 }
 
+// DatasourceKey defines model for DatasourceKey.
+type DatasourceKey struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+}
+
 // DatasourceListResponse defines model for DatasourceListResponse.
 type DatasourceListResponse struct {
 	Data []Datasource `json:"data"`
 }
 
+// DatasourceReference defines model for DatasourceReference.
+type DatasourceReference struct {
+	Id string `json:"id"`
+
+	// Kind Kind of the referencing resource, e.g. ownership
+	Kind string  `json:"kind"`
+	Name *string `json:"name,omitempty"`
+}
+
+// DatasourceReferenceListResponse defines model for DatasourceReferenceListResponse.
+type DatasourceReferenceListResponse struct {
+	Data []DatasourceReference `json:"data"`
+}
+
+// DatasourceReplica defines model for DatasourceReplica.
+type DatasourceReplica struct {
+	Name string `json:"name"`
+
+	// Options Driver options merged over the datasource's, typically just the host.
+	Options json.RawMessage `json:"options"`
+}
+
 // DatasourceResponse defines model for DatasourceResponse.
 type DatasourceResponse struct {
 	Data Datasource `json:"data"`
@@ -386,6 +942,30 @@ type DatasourceStatsResponse struct {
 	Data DatasourceStats `json:"data"`
 }
 
+// DatasourceTemplate defines model for DatasourceTemplate.
+type DatasourceTemplate struct {
+	CreatedAt   time.Time `json:"createdAt"`
+	Description *string   `json:"description,omitempty"`
+	Name        string    `json:"name"`
+
+	// Options Default driver options inherited by datasources.
+	Options   json.RawMessage    `json:"options"`
+	Tags      *[]string          `json:"tags,omitempty"`
+	Type      string             `json:"type"`
+	Uid       openapi_types.UUID `json:"uid"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+}
+
+// DatasourceTemplateListResponse defines model for DatasourceTemplateListResponse.
+type DatasourceTemplateListResponse struct {
+	Data []DatasourceTemplate `json:"data"`
+}
+
+// DatasourceTemplateResponse defines model for DatasourceTemplateResponse.
+type DatasourceTemplateResponse struct {
+	Data DatasourceTemplate `json:"data"`
+}
+
 // DatasourceTestResponse defines model for DatasourceTestResponse.
 type DatasourceTestResponse struct {
 	Data DatasourceTestResult `json:"data"`
@@ -399,38 +979,253 @@ type DatasourceTestResult struct {
 	TestedAt  *time.Time `json:"testedAt,omitempty"`
 }
 
-// DatasourceTypesResponse defines model for DatasourceTypesResponse.
-type DatasourceTypesResponse struct {
-	Data []string `json:"data"`
+// DatasourceTypeInfo defines model for DatasourceTypeInfo.
+type DatasourceTypeInfo struct {
+	Capabilities DatasourceCapabilities `json:"capabilities"`
+	Name         string                 `json:"name"`
+	Type         string                 `json:"type"`
+
+	// Version Build metadata a plugin reports about itself. Fields are empty for plugins that do not report a version.
+	Version DatasourceTypeVersion `json:"version"`
 }
 
-// ErrorResponse defines model for ErrorResponse.
-type ErrorResponse struct {
-	Error string `json:"error"`
+// DatasourceTypeResponse defines model for DatasourceTypeResponse.
+type DatasourceTypeResponse struct {
+	Data DatasourceTypeInfo `json:"data"`
 }
 
-// Field defines model for Field.
-type Field struct {
-	// Kind Semantic type of a field, used for rendering (axis selection, formatting).
-	Kind FieldKind `json:"kind"`
+// DatasourceTypeState defines model for DatasourceTypeState.
+type DatasourceTypeState struct {
+	Enabled bool   `json:"enabled"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
 
-	// Labels Key-value labels attached to the field (e.g. Prometheus metric labels).
-	Labels *map[string]string `json:"labels,omitempty"`
-	Name   string             `json:"name"`
+	// Version Build metadata a plugin reports about itself. Fields are empty for plugins that do not report a version.
+	Version DatasourceTypeVersion `json:"version"`
+}
 
-	// Type Native database type string (display only).
-	Type   *string       `json:"type,omitempty"`
-	Values []interface{} `json:"values"`
+// DatasourceTypeStateResponse defines model for DatasourceTypeStateResponse.
+type DatasourceTypeStateResponse struct {
+	Data DatasourceTypeState `json:"data"`
 }
 
-// FieldKind Semantic type of a field, used for rendering (axis selection, formatting).
-type FieldKind string
+// DatasourceTypeStatesResponse defines model for DatasourceTypeStatesResponse.
+type DatasourceTypeStatesResponse struct {
+	Data []DatasourceTypeState `json:"data"`
+}
 
-// FrameType Hint for how the DataFrame should be visualized.
-type FrameType string
+// DatasourceTypeVersion Build metadata a plugin reports about itself. Fields are empty for plugins that do not report a version.
+type DatasourceTypeVersion struct {
+	// Compatible Whether the running server is within the supported range.
+	Compatible bool `json:"compatible"`
 
-// OllamaSettingsInput defines model for OllamaSettingsInput.
-type OllamaSettingsInput struct {
+	// DriverVersion Client library and version, e.g. "github.com/lib/pq v1.10.9".
+	DriverVersion *string `json:"driverVersion,omitempty"`
+
+	// MaxServerVersion First unsupported server release.
+	MaxServerVersion *string `json:"maxServerVersion,omitempty"`
+
+	// MinServerVersion Oldest supported server release.
+	MinServerVersion *string `json:"minServerVersion,omitempty"`
+	Version          string  `json:"version"`
+}
+
+// DatasourceTypesResponse defines model for DatasourceTypesResponse.
+type DatasourceTypesResponse struct {
+	Data []string `json:"data"`
+}
+
+// DeprecateDatasourceRequest defines model for DeprecateDatasourceRequest.
+type DeprecateDatasourceRequest struct {
+	// Message Guidance for consumers, e.g. the replacement to migrate to.
+	Message  *string    `json:"message,omitempty"`
+	SunsetAt *time.Time `json:"sunsetAt,omitempty"`
+}
+
+// Deprecation defines model for Deprecation.
+type Deprecation struct {
+	DeprecatedAt time.Time `json:"deprecatedAt"`
+	Message      string    `json:"message"`
+
+	// SunsetAt Date after which the datasource may be removed.
+	SunsetAt *time.Time `json:"sunsetAt,omitempty"`
+}
+
+// DialectFunction defines model for DialectFunction.
+type DialectFunction struct {
+	Description string  `json:"description"`
+	Name        string  `json:"name"`
+	ReturnType  *string `json:"returnType,omitempty"`
+	Signature   string  `json:"signature"`
+}
+
+// DialectParameterStyle How bind parameters are written, e.g. $1 (dollar) or ? (question).
+type DialectParameterStyle string
+
+// DiffChange defines model for DiffChange.
+type DiffChange struct {
+	Columns []DiffColumnChange     `json:"columns"`
+	Key     map[string]interface{} `json:"key"`
+}
+
+// DiffColumnChange defines model for DiffColumnChange.
+type DiffColumnChange struct {
+	Column string      `json:"column"`
+	Left   interface{} `json:"left"`
+	Right  interface{} `json:"right"`
+}
+
+// DiffRequest defines model for DiffRequest.
+type DiffRequest struct {
+	// Columns Non-key columns to compare. Defaults to every non-key column both sides return.
+	Columns *[]string `json:"columns,omitempty"`
+
+	// Keys Columns identifying a row; unique on each side.
+	Keys []string   `json:"keys"`
+	Left DiffSource `json:"left"`
+
+	// Limit Maximum rows read from each side. A side returning more fails the request rather than producing a partial diff.
+	Limit *int `json:"limit,omitempty"`
+
+	// MaxRows Maximum rows listed per added, removed and changed group.
+	MaxRows *int       `json:"max_rows,omitempty"`
+	Right   DiffSource `json:"right"`
+
+	// Tolerance Absolute difference under which numbers count as equal.
+	Tolerance *float64 `json:"tolerance,omitempty"`
+}
+
+// DiffResponse defines model for DiffResponse.
+type DiffResponse struct {
+	Data DiffResult `json:"data"`
+
+	// Warnings Non-fatal notices about either datasource, e.g. deprecation.
+	Warnings *[]string `json:"warnings,omitempty"`
+}
+
+// DiffResult defines model for DiffResult.
+type DiffResult struct {
+	Added int `json:"added"`
+
+	// AddedRows Full rows present only on the right.
+	AddedRows   []map[string]interface{} `json:"added_rows"`
+	Changed     int                      `json:"changed"`
+	ChangedRows []DiffChange             `json:"changed_rows"`
+
+	// Columns Columns that were compared.
+	Columns         []string `json:"columns"`
+	Keys            []string `json:"keys"`
+	LeftOnlyColumns []string `json:"left_only_columns"`
+	LeftRows        int      `json:"left_rows"`
+	Removed         int      `json:"removed"`
+
+	// RemovedRows Full rows present only on the left.
+	RemovedRows      []map[string]interface{} `json:"removed_rows"`
+	RightOnlyColumns []string                 `json:"right_only_columns"`
+	RightRows        int                      `json:"right_rows"`
+
+	// Truncated Set when max_rows cut any row list short; counts stay exact.
+	Truncated bool `json:"truncated"`
+	Unchanged int  `json:"unchanged"`
+}
+
+// DiffSource defines model for DiffSource.
+type DiffSource struct {
+	DatasourceId openapi_types.UUID `json:"datasource_id"`
+
+	// Query Query template, rendered and macro-expanded as in QueryRequest.
+	Query  *string `json:"query,omitempty"`
+	Schema *string `json:"schema,omitempty"`
+
+	// Table Table to read. Exactly one of table and query is required.
+	Table     *string                 `json:"table,omitempty"`
+	TimeRange *TimeRange              `json:"time_range,omitempty"`
+	Variables *map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Environment Deployment environment label.
+type Environment string
+
+// ErrorResponse defines model for ErrorResponse.
+type ErrorResponse struct {
+	// Code Machine-readable error code, e.g. "query_timeout" when the query deadline passed. Classified database errors use auth_failed, unknown_database, permission_denied, undefined_object or syntax_error.
+	Code *string `json:"code,omitempty"`
+
+	// Column 1-based column of the query the database error points at, when known.
+	Column *int   `json:"column,omitempty"`
+	Error  string `json:"error"`
+
+	// Line 1-based line of the query the database error points at, when known.
+	Line *int `json:"line,omitempty"`
+}
+
+// Field defines model for Field.
+type Field struct {
+	// Kind Semantic type of a field, used for rendering (axis selection, formatting).
+	Kind FieldKind `json:"kind"`
+
+	// Labels Key-value labels attached to the field (e.g. Prometheus metric labels).
+	Labels *map[string]string `json:"labels,omitempty"`
+	Name   string             `json:"name"`
+
+	// Type Native database type string (display only).
+	Type   *string       `json:"type,omitempty"`
+	Values []interface{} `json:"values"`
+}
+
+// FieldKind Semantic type of a field, used for rendering (axis selection, formatting).
+type FieldKind string
+
+// FrameType Hint for how the DataFrame should be visualized.
+type FrameType string
+
+// IngestRecordError defines model for IngestRecordError.
+type IngestRecordError struct {
+	Error string `json:"error"`
+
+	// Line Line of the request body holding the record, from 1.
+	Line int `json:"line"`
+}
+
+// IngestResponse defines model for IngestResponse.
+type IngestResponse struct {
+	// Accepted Records written.
+	Accepted int                 `json:"accepted"`
+	Errors   []IngestRecordError `json:"errors"`
+
+	// Failed Records that were not written; each has an entry in errors.
+	Failed int `json:"failed"`
+}
+
+// MaintenanceWindow defines model for MaintenanceWindow.
+type MaintenanceWindow struct {
+	End time.Time `json:"end"`
+
+	// Message Shown to users whose queries are rejected, e.g. "Upgrading to PostgreSQL 16".
+	Message *string `json:"message,omitempty"`
+
+	// Start Defaults to now.
+	Start *time.Time `json:"start,omitempty"`
+}
+
+// NetworkPolicy defines model for NetworkPolicy.
+type NetworkPolicy struct {
+	AllowCidrs *[]string        `json:"allowCidrs,omitempty"`
+	DenyCidrs  *[]string        `json:"denyCidrs,omitempty"`
+	Keys       *[]DatasourceKey `json:"keys,omitempty"`
+}
+
+// NetworkPolicyRequest defines model for NetworkPolicyRequest.
+type NetworkPolicyRequest struct {
+	// AllowCidrs Networks, in CIDR notation or as single addresses, the datasource may be used from.
+	AllowCidrs *[]string `json:"allowCidrs,omitempty"`
+
+	// DenyCidrs Networks the datasource may never be used from, even with a key.
+	DenyCidrs *[]string `json:"denyCidrs,omitempty"`
+}
+
+// OllamaSettingsInput defines model for OllamaSettingsInput.
+type OllamaSettingsInput struct {
 	BaseUrl *string `json:"base_url,omitempty"`
 	Model   *string `json:"model,omitempty"`
 }
@@ -456,11 +1251,82 @@ type OpenAISettingsResponse struct {
 	Model     *string `json:"model,omitempty"`
 }
 
+// ParameterViolation defines model for ParameterViolation.
+type ParameterViolation struct {
+	At             time.Time `json:"at"`
+	DatasourceId   string    `json:"datasourceId"`
+	DatasourceName string    `json:"datasourceName"`
+
+	// Literals The literal values found in its conditions; strings are quoted.
+	Literals []string `json:"literals"`
+
+	// Query The query as submitted, before templates were rendered.
+	Query string `json:"query"`
+
+	// Rejected Whether the query was refused rather than run with a warning.
+	Rejected bool   `json:"rejected"`
+	Username string `json:"username"`
+}
+
+// ParameterViolationsResponse defines model for ParameterViolationsResponse.
+type ParameterViolationsResponse struct {
+	Data   []ParameterViolation              `json:"data"`
+	Policy ParameterViolationsResponsePolicy `json:"policy"`
+}
+
+// ParameterViolationsResponsePolicy defines model for ParameterViolationsResponse.Policy.
+type ParameterViolationsResponsePolicy string
+
+// PivotTransform Turns the distinct values of column into columns. Rows sharing the row_fields values collapse into one; each cell aggregates value.
+type PivotTransform struct {
+	Aggregate *PivotTransformAggregate `json:"aggregate,omitempty"`
+	Column    string                   `json:"column"`
+	RowFields *[]string                `json:"row_fields,omitempty"`
+	Value     string                   `json:"value"`
+}
+
+// PivotTransformAggregate defines model for PivotTransform.Aggregate.
+type PivotTransformAggregate string
+
+// PromoteDatasourceRequest defines model for PromoteDatasourceRequest.
+type PromoteDatasourceRequest struct {
+	// Environment Deployment environment label.
+	Environment Environment `json:"environment"`
+
+	// Name Name of the promoted datasource. Defaults to "<source name> (<environment>)".
+	Name *string `json:"name,omitempty"`
+
+	// Options Driver options for the target environment (overrides when the source inherits a template).
+	Options map[string]interface{} `json:"options"`
+}
+
+// QueryEstimate defines model for QueryEstimate.
+type QueryEstimate struct {
+	// Bytes Bytes the query is expected to scan.
+	Bytes int64 `json:"bytes"`
+
+	// Cost Estimated cost under the datasource's cost model; absent without one.
+	Cost     *float64 `json:"cost,omitempty"`
+	Currency *string  `json:"currency,omitempty"`
+
+	// Method How the estimate was made, e.g. "explain estimate" or "file size".
+	Method string `json:"method"`
+
+	// Rows Rows the query is expected to read; absent when unknown.
+	Rows *int64 `json:"rows,omitempty"`
+}
+
 // QueryInspect defines model for QueryInspect.
 type QueryInspect struct {
 	// ExecutedQuery Final query after all variable substitution.
 	ExecutedQuery string `json:"executedQuery"`
 
+	// Interval Bucket width used by $__timeGroup, e.g. "5m"; absent when the query does not group by time.
+	Interval *string `json:"interval,omitempty"`
+
+	// IntervalMs The interval in milliseconds.
+	IntervalMs *int64 `json:"intervalMs,omitempty"`
+
 	// RawQuery Original template before variable substitution.
 	RawQuery string `json:"rawQuery"`
 
@@ -468,14 +1334,49 @@ type QueryInspect struct {
 	Variables *map[string]interface{} `json:"variables,omitempty"`
 }
 
+// QueryRefresh defines model for QueryRefresh.
+type QueryRefresh struct {
+	// Changes What differs, e.g. "row count changed from 120 to 118".
+	Changes []string      `json:"changes"`
+	Current ResultSummary `json:"current"`
+
+	// Drift True when the fresh result differs from the original.
+	Drift    bool          `json:"drift"`
+	Original ResultSummary `json:"original"`
+	Result   AsyncQuery    `json:"result"`
+}
+
+// QueryRefreshRequest defines model for QueryRefreshRequest.
+type QueryRefreshRequest struct {
+	// KeyColumns Columns whose checksums are compared; all columns when empty.
+	KeyColumns *[]string `json:"keyColumns,omitempty"`
+}
+
+// QueryRefreshResponse defines model for QueryRefreshResponse.
+type QueryRefreshResponse struct {
+	Data QueryRefresh `json:"data"`
+}
+
 // QueryRequest defines model for QueryRequest.
 type QueryRequest struct {
 	Limit *int `json:"limit,omitempty"`
 
-	// Query Raw query template. Server-side {{ variable }} substitution is applied before execution. Built-in variables (__ prefix) are injected automatically from time_range.
+	// MaxDataPoints Point budget for $__timeGroup without an explicit interval. The bucket width is the smallest of 1s, 5s, 10s, 15s, 30s, 1m, 5m, 10m, 15m, 30m, 1h, 3h, 6h, 12h, 1d, 7d or 30d that keeps the time range within this many points. Defaults to 1000.
+	MaxDataPoints *int `json:"max_data_points,omitempty"`
+
+	// Parameters Values for named placeholders in the query, e.g. {name:String} on ClickHouse. The datasource binds and type-checks them itself; they are never substituted into the query text. Only accepted by datasources with the namedParams capability.
+	Parameters *map[string]interface{} `json:"parameters,omitempty"`
+
+	// Query Raw query template. Server-side {{ variable }} substitution is applied before execution. Built-in variables (__ prefix) are injected automatically from time_range. Time-range macros ($__timeFilter(column), $__timeFrom, $__timeTo, $__unixEpochFilter(column), $__unixEpochFrom, $__unixEpochTo, $__timeGroup(column[, interval])) are then expanded in the datasource's SQL dialect.
 	Query     string     `json:"query"`
 	TimeRange *TimeRange `json:"time_range,omitempty"`
 
+	// Timeout Requested time limit in seconds. The effective deadline is the smallest of this, the datasource's queryTimeout and the server's query_timeout.
+	Timeout *int `json:"timeout,omitempty"`
+
+	// Transforms Reshaping steps applied, in order, to every result frame after the query runs.
+	Transforms *[]QueryTransform `json:"transforms,omitempty"`
+
 	// Variables User-defined variables for {{ }} template substitution.
 	Variables *map[string]interface{} `json:"variables,omitempty"`
 }
@@ -484,7 +1385,13 @@ type QueryRequest struct {
 type QueryResponse struct {
 	Data    QueryResult   `json:"data"`
 	Inspect *QueryInspect `json:"inspect,omitempty"`
-	Stats   *QueryStats   `json:"stats,omitempty"`
+
+	// RowLimit LIMIT the server added because the query had none and the datasource is tagged "interactive"; absent when the query ran as written.
+	RowLimit *int        `json:"rowLimit,omitempty"`
+	Stats    *QueryStats `json:"stats,omitempty"`
+
+	// Warnings Non-fatal notices about the datasource, e.g. deprecation, and about the query, e.g. a missing LIMIT on a large table.
+	Warnings *[]string `json:"warnings,omitempty"`
 }
 
 // QueryResult defines model for QueryResult.
@@ -494,9 +1401,124 @@ type QueryResult struct {
 
 // QueryStats defines model for QueryStats.
 type QueryStats struct {
-	BytesRead       *int64 `json:"bytesRead,omitempty"`
-	ExecutionTimeMs int64  `json:"executionTimeMs"`
-	RowsReturned    int64  `json:"rowsReturned"`
+	// Batches Number of batches the rows arrived in (ClickHouse blocks, PostgreSQL cursor fetches); absent when the driver does not report it.
+	Batches   *int64 `json:"batches,omitempty"`
+	BytesRead *int64 `json:"bytesRead,omitempty"`
+
+	// Cost Cost of bytesRead under the datasource's cost model; absent without one.
+	Cost     *float64 `json:"cost,omitempty"`
+	Currency *string  `json:"currency,omitempty"`
+
+	// EstimatedBytes Bytes the datasource estimated the query would scan before it ran; present for datasources with a cost model that can estimate.
+	EstimatedBytes  *int64   `json:"estimatedBytes,omitempty"`
+	EstimatedCost   *float64 `json:"estimatedCost,omitempty"`
+	ExecutionTimeMs int64    `json:"executionTimeMs"`
+
+	// FetchSize Rows-per-batch setting the query ran with (the datasource's fetch_size); absent when the driver default was used.
+	FetchSize    *int  `json:"fetchSize,omitempty"`
+	RowsReturned int64 `json:"rowsReturned"`
+}
+
+// QueryTransform defines model for QueryTransform.
+type QueryTransform struct {
+	// Pivot Turns the distinct values of column into columns. Rows sharing the row_fields values collapse into one; each cell aggregates value.
+	Pivot *PivotTransform    `json:"pivot,omitempty"`
+	Type  QueryTransformType `json:"type"`
+
+	// Unpivot Turns columns into (name_field, value_field) rows, repeating the keep fields on each. Empty columns melts every field not in keep.
+	Unpivot *UnpivotTransform `json:"unpivot,omitempty"`
+}
+
+// QueryTransformType defines model for QueryTransform.Type.
+type QueryTransformType string
+
+// ResultSummary defines model for ResultSummary.
+type ResultSummary struct {
+	// Checksums Hex checksum of each compared column's values, independent of row order.
+	Checksums map[string]string `json:"checksums"`
+	Columns   []string          `json:"columns"`
+	Rows      int64             `json:"rows"`
+}
+
+// RowCount defines model for RowCount.
+type RowCount struct {
+	// Approximate True when the count comes from statistics rather than a full scan.
+	Approximate bool  `json:"approximate"`
+	Count       int64 `json:"count"`
+
+	// Method Counting strategy used, e.g. "pg_class.reltuples", "system.parts", "count".
+	Method string `json:"method"`
+}
+
+// RowCountResponse defines model for RowCountResponse.
+type RowCountResponse struct {
+	Data RowCount `json:"data"`
+}
+
+// SystemHealth defines model for SystemHealth.
+type SystemHealth struct {
+	CollectedAt time.Time      `json:"collectedAt"`
+	Metrics     []SystemMetric `json:"metrics"`
+
+	// Nodes Cluster members, for backends that report them.
+	Nodes    *[]SystemNode   `json:"nodes,omitempty"`
+	Sessions []SystemSession `json:"sessions"`
+}
+
+// SystemHealthResponse defines model for SystemHealthResponse.
+type SystemHealthResponse struct {
+	Data SystemHealth `json:"data"`
+}
+
+// SystemMetric defines model for SystemMetric.
+type SystemMetric struct {
+	// Name Canonical names are connections, max_connections, active_queries, uptime_seconds, memory_bytes and cache_hit_ratio; backend-specific metrics carry a prefix such as "pg." or "ch.".
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// SystemNode defines model for SystemNode.
+type SystemNode struct {
+	Address string `json:"address"`
+	Id      string `json:"id"`
+	Live    bool   `json:"live"`
+
+	// Locality Placement of the node, e.g. "region=us-east1,zone=b".
+	Locality  *string   `json:"locality,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+	Version   *string   `json:"version,omitempty"`
+}
+
+// SystemSession defines model for SystemSession.
+type SystemSession struct {
+	Database string `json:"database"`
+
+	// ElapsedMs How long the current query or state has been running.
+	ElapsedMs int64 `json:"elapsedMs"`
+
+	// Id Backend session or query identifier (pid, query_id).
+	Id    string `json:"id"`
+	Query string `json:"query"`
+
+	// State Backend state label, e.g. "active" or "idle".
+	State string `json:"state"`
+	User  string `json:"user"`
+
+	// WaitEvent What the session is blocked on, if anything.
+	WaitEvent *string `json:"waitEvent,omitempty"`
+}
+
+// TableRowsResponse defines model for TableRowsResponse.
+type TableRowsResponse struct {
+	Data QueryResult `json:"data"`
+
+	// NextCursor Cursor for the next page; absent on the last page.
+	NextCursor *string     `json:"nextCursor,omitempty"`
+	Stats      *QueryStats `json:"stats,omitempty"`
+	TotalRows  *RowCount   `json:"totalRows,omitempty"`
+
+	// Warnings Non-fatal notices about the datasource, e.g. deprecation.
+	Warnings *[]string `json:"warnings,omitempty"`
 }
 
 // TestDatasourceRequest defines model for TestDatasourceRequest.
@@ -514,6 +1536,14 @@ type TimeRange struct {
 	To *string `json:"to,omitempty"`
 }
 
+// UnpivotTransform Turns columns into (name_field, value_field) rows, repeating the keep fields on each. Empty columns melts every field not in keep.
+type UnpivotTransform struct {
+	Columns    *[]string `json:"columns,omitempty"`
+	Keep       *[]string `json:"keep,omitempty"`
+	NameField  *string   `json:"name_field,omitempty"`
+	ValueField *string   `json:"value_field,omitempty"`
+}
+
 // UpdateAIConfigRequest defines model for UpdateAIConfigRequest.
 type UpdateAIConfigRequest struct {
 	// ApiKey Empty string keeps existing key
@@ -539,27 +1569,69 @@ type UpdateAISettingsRequest struct {
 
 // UpdateDatasourceRequest defines model for UpdateDatasourceRequest.
 type UpdateDatasourceRequest struct {
-	Enabled *bool                   `json:"enabled,omitempty"`
-	Meta    *map[string]interface{} `json:"meta,omitempty"`
-	Name    *string                 `json:"name,omitempty"`
-	Options *map[string]interface{} `json:"options,omitempty"`
+	// Alias Stable name saved queries can use instead of the uid, unique within the environment. An empty string removes it.
+	Alias   *string `json:"alias,omitempty"`
+	Enabled *bool   `json:"enabled,omitempty"`
+
+	// Environment Deployment environment label.
+	Environment *Environment            `json:"environment,omitempty"`
+	Meta        *map[string]interface{} `json:"meta,omitempty"`
+	Name        *string                 `json:"name,omitempty"`
+	Options     *map[string]interface{} `json:"options,omitempty"`
+
+	// QueryTimeout Maximum query run time in seconds for this datasource; 0 means no datasource limit.
+	QueryTimeout *int `json:"queryTimeout,omitempty"`
+
+	// Replicas Read replicas for read-only queries, tried in order before the primary. Replaces the current list.
+	Replicas *[]DatasourceReplica `json:"replicas,omitempty"`
+}
+
+// UpdateDatasourceTemplateRequest defines model for UpdateDatasourceTemplateRequest.
+type UpdateDatasourceTemplateRequest struct {
+	Description *string                 `json:"description,omitempty"`
+	Name        *string                 `json:"name,omitempty"`
+	Options     *map[string]interface{} `json:"options,omitempty"`
+	Tags        *[]string               `json:"tags,omitempty"`
+}
+
+// UpdateDatasourceTypeStateRequest defines model for UpdateDatasourceTypeStateRequest.
+type UpdateDatasourceTypeStateRequest struct {
+	Enabled bool `json:"enabled"`
 }
 
+// IfMatch defines model for IfMatch.
+type IfMatch = string
+
 // BadGateway defines model for BadGateway.
 type BadGateway = ErrorResponse
 
 // BadRequest defines model for BadRequest.
 type BadRequest = ErrorResponse
 
+// Conflict defines model for Conflict.
+type Conflict = ErrorResponse
+
+// Forbidden defines model for Forbidden.
+type Forbidden = ErrorResponse
+
+// GatewayTimeout defines model for GatewayTimeout.
+type GatewayTimeout = ErrorResponse
+
 // InternalError defines model for InternalError.
 type InternalError = ErrorResponse
 
+// Maintenance defines model for Maintenance.
+type Maintenance = ErrorResponse
+
 // NotFound defines model for NotFound.
 type NotFound = ErrorResponse
 
 // NotImplemented defines model for NotImplemented.
 type NotImplemented = ErrorResponse
 
+// PreconditionFailed defines model for PreconditionFailed.
+type PreconditionFailed = ErrorResponse
+
 // ListAIConfigHistoryParams defines parameters for ListAIConfigHistory.
 type ListAIConfigHistoryParams struct {
 	Limit  *int `form:"limit,omitempty" json:"limit,omitempty"`
@@ -582,6 +1654,18 @@ type ListDatasourcesParams struct {
 
 	// CreatedBy Filter by creator
 	CreatedBy *string `form:"createdBy,omitempty" json:"createdBy,omitempty"`
+
+	// Environment Filter by environment label (dev, staging, prod)
+	Environment *string `form:"environment,omitempty" json:"environment,omitempty"`
+
+	// Alias Filter by alias
+	Alias *string `form:"alias,omitempty" json:"alias,omitempty"`
+}
+
+// ApplyDatasourceParams defines parameters for ApplyDatasource.
+type ApplyDatasourceParams struct {
+	// IfMatch ETag from a previous response; the write fails with 412 if the datasource has changed since.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
 }
 
 // ListDatasourceHistoryParams defines parameters for ListDatasourceHistory.
@@ -590,38 +1674,155 @@ type ListDatasourceHistoryParams struct {
 	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
 }
 
+// DeleteDatasourceParams defines parameters for DeleteDatasource.
+type DeleteDatasourceParams struct {
+	Force *bool `form:"force,omitempty" json:"force,omitempty"`
+}
+
+// UpdateDatasourceParams defines parameters for UpdateDatasource.
+type UpdateDatasourceParams struct {
+	// IfMatch ETag from a previous response; the write fails with 412 if the datasource has changed since.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
 // ListDatasourceHistoryByDatasourceParams defines parameters for ListDatasourceHistoryByDatasource.
 type ListDatasourceHistoryByDatasourceParams struct {
 	Limit  *int `form:"limit,omitempty" json:"limit,omitempty"`
 	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
 }
 
+// IngestDatasourceParams defines parameters for IngestDatasource.
+type IngestDatasourceParams struct {
+	// Target Table (optionally database.table) or index to write to.
+	Target string `form:"target" json:"target"`
+}
+
+// CreateDatasourceKeyJSONBody defines parameters for CreateDatasourceKey.
+type CreateDatasourceKeyJSONBody struct {
+	Name string `json:"name"`
+}
+
+// TerminateDatasourceSessionParams defines parameters for TerminateDatasourceSession.
+type TerminateDatasourceSessionParams struct {
+	// QueryOnly Cancel the running query but leave the session connected.
+	QueryOnly *bool `form:"queryOnly,omitempty" json:"queryOnly,omitempty"`
+}
+
+// CountTableRowsParams defines parameters for CountTableRows.
+type CountTableRowsParams struct {
+	Schema *string `form:"schema,omitempty" json:"schema,omitempty"`
+
+	// Column When set, count distinct values of this column instead of rows.
+	Column *string `form:"column,omitempty" json:"column,omitempty"`
+	Exact  *bool   `form:"exact,omitempty" json:"exact,omitempty"`
+}
+
+// GetTableRowsParams defines parameters for GetTableRows.
+type GetTableRowsParams struct {
+	// Schema Schema or database containing the table.
+	Schema *string `form:"schema,omitempty" json:"schema,omitempty"`
+
+	// Sort Column to sort and seek on. NULLs sort as the largest value.
+	Sort string `form:"sort" json:"sort"`
+
+	// Key Unique column, e.g. the primary key, that orders rows sharing a sort value, so pages neither skip nor repeat them. Pass the sort column again when it is unique itself.
+	Key   string                   `form:"key" json:"key"`
+	Order *GetTableRowsParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+
+	// Cursor Opaque cursor returned as nextCursor by the previous page.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+	Limit  *int    `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Filter Quick filters as a JSON array, ANDed together. Send the same filters with every page of a cursor.
+	Filter *[]ColumnFilter `form:"filter,omitempty" json:"filter,omitempty"`
+}
+
+// GetTableRowsParamsOrder defines parameters for GetTableRows.
+type GetTableRowsParamsOrder string
+
+// SetDatasourceTypeStateJSONRequestBody defines body for SetDatasourceTypeState for application/json ContentType.
+type SetDatasourceTypeStateJSONRequestBody = UpdateDatasourceTypeStateRequest
+
 // CreateAIConfigJSONRequestBody defines body for CreateAIConfig for application/json ContentType.
 type CreateAIConfigJSONRequestBody = CreateAIConfigRequest
 
 // UpdateAIConfigJSONRequestBody defines body for UpdateAIConfig for application/json ContentType.
 type UpdateAIConfigJSONRequestBody = UpdateAIConfigRequest
 
+// CreateDatasourceTemplateJSONRequestBody defines body for CreateDatasourceTemplate for application/json ContentType.
+type CreateDatasourceTemplateJSONRequestBody = CreateDatasourceTemplateRequest
+
+// UpdateDatasourceTemplateJSONRequestBody defines body for UpdateDatasourceTemplate for application/json ContentType.
+type UpdateDatasourceTemplateJSONRequestBody = UpdateDatasourceTemplateRequest
+
 // CreateDatasourceJSONRequestBody defines body for CreateDatasource for application/json ContentType.
 type CreateDatasourceJSONRequestBody = CreateDatasourceRequest
 
+// BulkDatasourcesJSONRequestBody defines body for BulkDatasources for application/json ContentType.
+type BulkDatasourcesJSONRequestBody = BulkDatasourceRequest
+
+// ApplyDatasourceJSONRequestBody defines body for ApplyDatasource for application/json ContentType.
+type ApplyDatasourceJSONRequestBody = ApplyDatasourceRequest
+
 // TestDatasourceConfigJSONRequestBody defines body for TestDatasourceConfig for application/json ContentType.
 type TestDatasourceConfigJSONRequestBody = TestDatasourceRequest
 
 // UpdateDatasourceJSONRequestBody defines body for UpdateDatasource for application/json ContentType.
 type UpdateDatasourceJSONRequestBody = UpdateDatasourceRequest
 
+// AnalyzeDatasourceJSONRequestBody defines body for AnalyzeDatasource for application/json ContentType.
+type AnalyzeDatasourceJSONRequestBody = AnalyzeRequest
+
+// SetDatasourceCostModelJSONRequestBody defines body for SetDatasourceCostModel for application/json ContentType.
+type SetDatasourceCostModelJSONRequestBody = CostModel
+
+// DeprecateDatasourceJSONRequestBody defines body for DeprecateDatasource for application/json ContentType.
+type DeprecateDatasourceJSONRequestBody = DeprecateDatasourceRequest
+
+// SetDatasourceMaintenanceJSONRequestBody defines body for SetDatasourceMaintenance for application/json ContentType.
+type SetDatasourceMaintenanceJSONRequestBody = MaintenanceWindow
+
+// SetDatasourceNetworkPolicyJSONRequestBody defines body for SetDatasourceNetworkPolicy for application/json ContentType.
+type SetDatasourceNetworkPolicyJSONRequestBody = NetworkPolicyRequest
+
+// CreateDatasourceKeyJSONRequestBody defines body for CreateDatasourceKey for application/json ContentType.
+type CreateDatasourceKeyJSONRequestBody CreateDatasourceKeyJSONBody
+
+// PromoteDatasourceJSONRequestBody defines body for PromoteDatasource for application/json ContentType.
+type PromoteDatasourceJSONRequestBody = PromoteDatasourceRequest
+
 // QueryDatasourceJSONRequestBody defines body for QueryDatasource for application/json ContentType.
 type QueryDatasourceJSONRequestBody = QueryRequest
 
+// SubmitAsyncQueryJSONRequestBody defines body for SubmitAsyncQuery for application/json ContentType.
+type SubmitAsyncQueryJSONRequestBody = QueryRequest
+
 // BatchQueryDatasourceJSONRequestBody defines body for BatchQueryDatasource for application/json ContentType.
 type BatchQueryDatasourceJSONRequestBody = BatchQueryRequest
 
+// EstimateDatasourceQueryJSONRequestBody defines body for EstimateDatasourceQuery for application/json ContentType.
+type EstimateDatasourceQueryJSONRequestBody = QueryRequest
+
+// DiffDataJSONRequestBody defines body for DiffData for application/json ContentType.
+type DiffDataJSONRequestBody = DiffRequest
+
+// RefreshAsyncQueryJSONRequestBody defines body for RefreshAsyncQuery for application/json ContentType.
+type RefreshAsyncQueryJSONRequestBody = QueryRefreshRequest
+
 // UpdateAISettingsJSONRequestBody defines body for UpdateAISettings for application/json ContentType.
 type UpdateAISettingsJSONRequestBody = UpdateAISettingsRequest
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// List installed datasource types with their enabled state
+	// (GET /admin/datasource-types)
+	ListDatasourceTypeStates(c *gin.Context)
+	// Enable or disable a datasource type
+	// (PUT /admin/datasource-types/{type})
+	SetDatasourceTypeState(c *gin.Context, pType string)
+	// List queries that wrote raw literals into their conditions
+	// (GET /admin/parameter-violations)
+	ListParameterViolations(c *gin.Context)
 	// List all AI provider optionss (no api_key values)
 	// (GET /ai-configs)
 	ListAIConfigs(c *gin.Context)
@@ -649,15 +1850,45 @@ type ServerInterface interface {
 	// Get datasource statistics
 	// (GET /datasource-stats)
 	GetDatasourceStats(c *gin.Context)
+	// List datasource templates
+	// (GET /datasource-templates)
+	ListDatasourceTemplates(c *gin.Context)
+	// Create a datasource template
+	// (POST /datasource-templates)
+	CreateDatasourceTemplate(c *gin.Context)
+	// Delete a datasource template
+	// (DELETE /datasource-templates/{uid})
+	DeleteDatasourceTemplate(c *gin.Context, uid openapi_types.UUID)
+	// Get a datasource template by UID
+	// (GET /datasource-templates/{uid})
+	GetDatasourceTemplate(c *gin.Context, uid openapi_types.UUID)
+	// Update a datasource template
+	// (PUT /datasource-templates/{uid})
+	UpdateDatasourceTemplate(c *gin.Context, uid openapi_types.UUID)
 	// List supported datasource types
 	// (GET /datasource-types)
 	ListDatasourceTypes(c *gin.Context)
+	// Get a datasource type and its capabilities
+	// (GET /datasource-types/{type})
+	GetDatasourceType(c *gin.Context, pType string)
+	// Get the query dialect of a datasource type
+	// (GET /datasource-types/{type}/dialect)
+	GetDatasourceDialect(c *gin.Context, pType string)
 	// List all datasources
 	// (GET /datasources)
 	ListDatasources(c *gin.Context, params ListDatasourcesParams)
 	// Create a datasource
 	// (POST /datasources)
 	CreateDatasource(c *gin.Context)
+	// Run many datasource operations in one call
+	// (POST /datasources/bulk)
+	BulkDatasources(c *gin.Context)
+	// Look up a datasource by its external ID
+	// (GET /datasources/external/{externalId})
+	GetDatasourceByExternalId(c *gin.Context, externalId string)
+	// Create or replace a datasource by external ID
+	// (PUT /datasources/external/{externalId})
+	ApplyDatasource(c *gin.Context, externalId string, params ApplyDatasourceParams)
 	// List all datasource change history (requires statistics_store)
 	// (GET /datasources/history)
 	ListDatasourceHistory(c *gin.Context, params ListDatasourceHistoryParams)
@@ -666,28 +1897,100 @@ type ServerInterface interface {
 	TestDatasourceConfig(c *gin.Context)
 	// Delete a datasource
 	// (DELETE /datasources/{uid})
-	DeleteDatasource(c *gin.Context, uid openapi_types.UUID)
+	DeleteDatasource(c *gin.Context, uid openapi_types.UUID, params DeleteDatasourceParams)
 	// Get a datasource by UID
 	// (GET /datasources/{uid})
 	GetDatasource(c *gin.Context, uid openapi_types.UUID)
 	// Update a datasource
 	// (PUT /datasources/{uid})
-	UpdateDatasource(c *gin.Context, uid openapi_types.UUID)
+	UpdateDatasource(c *gin.Context, uid openapi_types.UUID, params UpdateDatasourceParams)
+	// Descriptive statistics and correlations for numeric columns
+	// (POST /datasources/{uid}/analyze)
+	AnalyzeDatasource(c *gin.Context, uid openapi_types.UUID)
+	// Stop pricing queries against a datasource
+	// (DELETE /datasources/{uid}/cost-model)
+	ClearDatasourceCostModel(c *gin.Context, uid openapi_types.UUID)
+	// Set how queries against a datasource are priced
+	// (PUT /datasources/{uid}/cost-model)
+	SetDatasourceCostModel(c *gin.Context, uid openapi_types.UUID)
+	// Lift a datasource deprecation
+	// (DELETE /datasources/{uid}/deprecation)
+	UndeprecateDatasource(c *gin.Context, uid openapi_types.UUID)
+	// Mark a datasource as deprecated
+	// (PUT /datasources/{uid}/deprecation)
+	DeprecateDatasource(c *gin.Context, uid openapi_types.UUID)
 	// List change history for a specific datasource
 	// (GET /datasources/{uid}/history)
 	ListDatasourceHistoryByDatasource(c *gin.Context, uid openapi_types.UUID, params ListDatasourceHistoryByDatasourceParams)
+	// Bulk-load NDJSON records into a table or index
+	// (POST /datasources/{uid}/ingest)
+	IngestDatasource(c *gin.Context, uid openapi_types.UUID, params IngestDatasourceParams)
+	// Cancel or end a datasource maintenance window
+	// (DELETE /datasources/{uid}/maintenance)
+	ClearDatasourceMaintenance(c *gin.Context, uid openapi_types.UUID)
+	// Schedule a datasource maintenance window
+	// (PUT /datasources/{uid}/maintenance)
+	SetDatasourceMaintenance(c *gin.Context, uid openapi_types.UUID)
+	// Remove a datasource's network policy and keys
+	// (DELETE /datasources/{uid}/network-policy)
+	ClearDatasourceNetworkPolicy(c *gin.Context, uid openapi_types.UUID)
+	// Restrict where a datasource can be used from
+	// (PUT /datasources/{uid}/network-policy)
+	SetDatasourceNetworkPolicy(c *gin.Context, uid openapi_types.UUID)
+	// Create a key that lets requests use the datasource from any allowed origin
+	// (POST /datasources/{uid}/network-policy/keys)
+	CreateDatasourceKey(c *gin.Context, uid openapi_types.UUID)
+	// Revoke a datasource key
+	// (DELETE /datasources/{uid}/network-policy/keys/{keyId})
+	DeleteDatasourceKey(c *gin.Context, uid openapi_types.UUID, keyId string)
+	// Copy a datasource definition into another environment
+	// (POST /datasources/{uid}/promote)
+	PromoteDatasource(c *gin.Context, uid openapi_types.UUID)
 	// Execute a query through a datasource
 	// (POST /datasources/{uid}/query)
 	QueryDatasource(c *gin.Context, uid openapi_types.UUID)
+	// Run a query in the background
+	// (POST /datasources/{uid}/query/async)
+	SubmitAsyncQuery(c *gin.Context, uid openapi_types.UUID)
 	// Execute multiple queries through a datasource and return all results
 	// (POST /datasources/{uid}/query/batch)
 	BatchQueryDatasource(c *gin.Context, uid openapi_types.UUID)
+	// Estimate how much a query would scan, and cost, without running it
+	// (POST /datasources/{uid}/query/estimate)
+	EstimateDatasourceQuery(c *gin.Context, uid openapi_types.UUID)
+	// List resources that reference a datasource
+	// (GET /datasources/{uid}/references)
+	ListDatasourceReferences(c *gin.Context, uid openapi_types.UUID)
 	// Get datasource schema for a datasource
 	// (GET /datasources/{uid}/schema)
 	GetDatasourceSchema(c *gin.Context, uid openapi_types.UUID)
+	// Live backend health read from system tables
+	// (GET /datasources/{uid}/system-health)
+	GetDatasourceSystemHealth(c *gin.Context, uid openapi_types.UUID)
+	// Terminate a backend session or cancel its query
+	// (DELETE /datasources/{uid}/system-health/sessions/{sessionId})
+	TerminateDatasourceSession(c *gin.Context, uid openapi_types.UUID, sessionId string, params TerminateDatasourceSessionParams)
+	// Count table rows, or distinct values of a column
+	// (GET /datasources/{uid}/tables/{table}/count)
+	CountTableRows(c *gin.Context, uid openapi_types.UUID, table string, params CountTableRowsParams)
+	// Browse table rows with keyset (seek) pagination
+	// (GET /datasources/{uid}/tables/{table}/rows)
+	GetTableRows(c *gin.Context, uid openapi_types.UUID, table string, params GetTableRowsParams)
 	// Test a datasource
 	// (POST /datasources/{uid}/test)
 	TestDatasource(c *gin.Context, uid openapi_types.UUID)
+	// Compare two query or table results row by row
+	// (POST /diff)
+	DiffData(c *gin.Context)
+	// Status of an async query
+	// (GET /query-results/{id})
+	GetAsyncQuery(c *gin.Context, id string)
+	// Result of a finished async query
+	// (GET /query-results/{id}/data)
+	GetAsyncQueryResult(c *gin.Context, id string)
+	// Run a finished async query again and check its result for drift
+	// (POST /query-results/{id}/refresh)
+	RefreshAsyncQuery(c *gin.Context, id string)
 	// Get current AI settings (no secret values)
 	// (GET /settings/ai)
 	GetAISettings(c *gin.Context)
@@ -705,6 +2008,57 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(c *gin.Context)
 
+// ListDatasourceTypeStates operation middleware
+func (siw *ServerInterfaceWrapper) ListDatasourceTypeStates(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListDatasourceTypeStates(c)
+}
+
+// SetDatasourceTypeState operation middleware
+func (siw *ServerInterfaceWrapper) SetDatasourceTypeState(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "type" -------------
+	var pType string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "type", c.Param("type"), &pType, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter type: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.SetDatasourceTypeState(c, pType)
+}
+
+// ListParameterViolations operation middleware
+func (siw *ServerInterfaceWrapper) ListParameterViolations(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListParameterViolations(c)
+}
+
 // ListAIConfigs operation middleware
 func (siw *ServerInterfaceWrapper) ListAIConfigs(c *gin.Context) {
 
@@ -923,8 +2277,8 @@ func (siw *ServerInterfaceWrapper) GetDatasourceStats(c *gin.Context) {
 	siw.Handler.GetDatasourceStats(c)
 }
 
-// ListDatasourceTypes operation middleware
-func (siw *ServerInterfaceWrapper) ListDatasourceTypes(c *gin.Context) {
+// ListDatasourceTemplates operation middleware
+func (siw *ServerInterfaceWrapper) ListDatasourceTemplates(c *gin.Context) {
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -933,19 +2287,170 @@ func (siw *ServerInterfaceWrapper) ListDatasourceTypes(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.ListDatasourceTypes(c)
+	siw.Handler.ListDatasourceTemplates(c)
 }
 
-// ListDatasources operation middleware
-func (siw *ServerInterfaceWrapper) ListDatasources(c *gin.Context) {
-
-	var err error
-	_ = err
-
-	// Parameter object where we will unmarshal all parameters from the context
-	var params ListDatasourcesParams
+// CreateDatasourceTemplate operation middleware
+func (siw *ServerInterfaceWrapper) CreateDatasourceTemplate(c *gin.Context) {
 
-	// ------------- Optional query parameter "type" -------------
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CreateDatasourceTemplate(c)
+}
+
+// DeleteDatasourceTemplate operation middleware
+func (siw *ServerInterfaceWrapper) DeleteDatasourceTemplate(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeleteDatasourceTemplate(c, uid)
+}
+
+// GetDatasourceTemplate operation middleware
+func (siw *ServerInterfaceWrapper) GetDatasourceTemplate(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetDatasourceTemplate(c, uid)
+}
+
+// UpdateDatasourceTemplate operation middleware
+func (siw *ServerInterfaceWrapper) UpdateDatasourceTemplate(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.UpdateDatasourceTemplate(c, uid)
+}
+
+// ListDatasourceTypes operation middleware
+func (siw *ServerInterfaceWrapper) ListDatasourceTypes(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListDatasourceTypes(c)
+}
+
+// GetDatasourceType operation middleware
+func (siw *ServerInterfaceWrapper) GetDatasourceType(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "type" -------------
+	var pType string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "type", c.Param("type"), &pType, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter type: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetDatasourceType(c, pType)
+}
+
+// GetDatasourceDialect operation middleware
+func (siw *ServerInterfaceWrapper) GetDatasourceDialect(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "type" -------------
+	var pType string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "type", c.Param("type"), &pType, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter type: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetDatasourceDialect(c, pType)
+}
+
+// ListDatasources operation middleware
+func (siw *ServerInterfaceWrapper) ListDatasources(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListDatasourcesParams
+
+	// ------------- Optional query parameter "type" -------------
 
 	err = runtime.BindQueryParameterWithOptions("form", true, false, "type", c.Request.URL.Query(), &params.Type, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
 	if err != nil {
@@ -969,6 +2474,22 @@ func (siw *ServerInterfaceWrapper) ListDatasources(c *gin.Context) {
 		return
 	}
 
+	// ------------- Optional query parameter "environment" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "environment", c.Request.URL.Query(), &params.Environment, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter environment: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "alias" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "alias", c.Request.URL.Query(), &params.Alias, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter alias: %w", err), http.StatusBadRequest)
+		return
+	}
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -992,6 +2513,93 @@ func (siw *ServerInterfaceWrapper) CreateDatasource(c *gin.Context) {
 	siw.Handler.CreateDatasource(c)
 }
 
+// BulkDatasources operation middleware
+func (siw *ServerInterfaceWrapper) BulkDatasources(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.BulkDatasources(c)
+}
+
+// GetDatasourceByExternalId operation middleware
+func (siw *ServerInterfaceWrapper) GetDatasourceByExternalId(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "externalId" -------------
+	var externalId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "externalId", c.Param("externalId"), &externalId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter externalId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetDatasourceByExternalId(c, externalId)
+}
+
+// ApplyDatasource operation middleware
+func (siw *ServerInterfaceWrapper) ApplyDatasource(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "externalId" -------------
+	var externalId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "externalId", c.Param("externalId"), &externalId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter externalId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ApplyDatasourceParams
+
+	headers := c.Request.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandler(c, fmt.Errorf("Expected one value for If-Match, got %d", n), http.StatusBadRequest)
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false, Type: "string", Format: ""})
+		if err != nil {
+			siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter If-Match: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ApplyDatasource(c, externalId, params)
+}
+
 // ListDatasourceHistory operation middleware
 func (siw *ServerInterfaceWrapper) ListDatasourceHistory(c *gin.Context) {
 
@@ -1055,6 +2663,17 @@ func (siw *ServerInterfaceWrapper) DeleteDatasource(c *gin.Context) {
 		return
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteDatasourceParams
+
+	// ------------- Optional query parameter "force" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "force", c.Request.URL.Query(), &params.Force, runtime.BindQueryParameterOptions{Type: "boolean", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter force: %w", err), http.StatusBadRequest)
+		return
+	}
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -1062,7 +2681,7 @@ func (siw *ServerInterfaceWrapper) DeleteDatasource(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.DeleteDatasource(c, uid)
+	siw.Handler.DeleteDatasource(c, uid, params)
 }
 
 // GetDatasource operation middleware
@@ -1105,6 +2724,30 @@ func (siw *ServerInterfaceWrapper) UpdateDatasource(c *gin.Context) {
 		return
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UpdateDatasourceParams
+
+	headers := c.Request.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandler(c, fmt.Errorf("Expected one value for If-Match, got %d", n), http.StatusBadRequest)
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false, Type: "string", Format: ""})
+		if err != nil {
+			siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter If-Match: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -1112,11 +2755,11 @@ func (siw *ServerInterfaceWrapper) UpdateDatasource(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.UpdateDatasource(c, uid)
+	siw.Handler.UpdateDatasource(c, uid, params)
 }
 
-// ListDatasourceHistoryByDatasource operation middleware
-func (siw *ServerInterfaceWrapper) ListDatasourceHistoryByDatasource(c *gin.Context) {
+// AnalyzeDatasource operation middleware
+func (siw *ServerInterfaceWrapper) AnalyzeDatasource(c *gin.Context) {
 
 	var err error
 	_ = err
@@ -1130,25 +2773,6 @@ func (siw *ServerInterfaceWrapper) ListDatasourceHistoryByDatasource(c *gin.Cont
 		return
 	}
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params ListDatasourceHistoryByDatasourceParams
-
-	// ------------- Optional query parameter "limit" -------------
-
-	err = runtime.BindQueryParameterWithOptions("form", true, false, "limit", c.Request.URL.Query(), &params.Limit, runtime.BindQueryParameterOptions{Type: "integer", Format: ""})
-	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
-		return
-	}
-
-	// ------------- Optional query parameter "offset" -------------
-
-	err = runtime.BindQueryParameterWithOptions("form", true, false, "offset", c.Request.URL.Query(), &params.Offset, runtime.BindQueryParameterOptions{Type: "integer", Format: ""})
-	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter offset: %w", err), http.StatusBadRequest)
-		return
-	}
-
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -1156,11 +2780,11 @@ func (siw *ServerInterfaceWrapper) ListDatasourceHistoryByDatasource(c *gin.Cont
 		}
 	}
 
-	siw.Handler.ListDatasourceHistoryByDatasource(c, uid, params)
+	siw.Handler.AnalyzeDatasource(c, uid)
 }
 
-// QueryDatasource operation middleware
-func (siw *ServerInterfaceWrapper) QueryDatasource(c *gin.Context) {
+// ClearDatasourceCostModel operation middleware
+func (siw *ServerInterfaceWrapper) ClearDatasourceCostModel(c *gin.Context) {
 
 	var err error
 	_ = err
@@ -1181,11 +2805,11 @@ func (siw *ServerInterfaceWrapper) QueryDatasource(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.QueryDatasource(c, uid)
+	siw.Handler.ClearDatasourceCostModel(c, uid)
 }
 
-// BatchQueryDatasource operation middleware
-func (siw *ServerInterfaceWrapper) BatchQueryDatasource(c *gin.Context) {
+// SetDatasourceCostModel operation middleware
+func (siw *ServerInterfaceWrapper) SetDatasourceCostModel(c *gin.Context) {
 
 	var err error
 	_ = err
@@ -1206,11 +2830,11 @@ func (siw *ServerInterfaceWrapper) BatchQueryDatasource(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.BatchQueryDatasource(c, uid)
+	siw.Handler.SetDatasourceCostModel(c, uid)
 }
 
-// GetDatasourceSchema operation middleware
-func (siw *ServerInterfaceWrapper) GetDatasourceSchema(c *gin.Context) {
+// UndeprecateDatasource operation middleware
+func (siw *ServerInterfaceWrapper) UndeprecateDatasource(c *gin.Context) {
 
 	var err error
 	_ = err
@@ -1231,11 +2855,681 @@ func (siw *ServerInterfaceWrapper) GetDatasourceSchema(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.GetDatasourceSchema(c, uid)
+	siw.Handler.UndeprecateDatasource(c, uid)
 }
 
-// TestDatasource operation middleware
-func (siw *ServerInterfaceWrapper) TestDatasource(c *gin.Context) {
+// DeprecateDatasource operation middleware
+func (siw *ServerInterfaceWrapper) DeprecateDatasource(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeprecateDatasource(c, uid)
+}
+
+// ListDatasourceHistoryByDatasource operation middleware
+func (siw *ServerInterfaceWrapper) ListDatasourceHistoryByDatasource(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListDatasourceHistoryByDatasourceParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "limit", c.Request.URL.Query(), &params.Limit, runtime.BindQueryParameterOptions{Type: "integer", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "offset", c.Request.URL.Query(), &params.Offset, runtime.BindQueryParameterOptions{Type: "integer", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter offset: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListDatasourceHistoryByDatasource(c, uid, params)
+}
+
+// IngestDatasource operation middleware
+func (siw *ServerInterfaceWrapper) IngestDatasource(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params IngestDatasourceParams
+
+	// ------------- Required query parameter "target" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, true, "target", c.Request.URL.Query(), &params.Target, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter target: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.IngestDatasource(c, uid, params)
+}
+
+// ClearDatasourceMaintenance operation middleware
+func (siw *ServerInterfaceWrapper) ClearDatasourceMaintenance(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ClearDatasourceMaintenance(c, uid)
+}
+
+// SetDatasourceMaintenance operation middleware
+func (siw *ServerInterfaceWrapper) SetDatasourceMaintenance(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.SetDatasourceMaintenance(c, uid)
+}
+
+// ClearDatasourceNetworkPolicy operation middleware
+func (siw *ServerInterfaceWrapper) ClearDatasourceNetworkPolicy(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ClearDatasourceNetworkPolicy(c, uid)
+}
+
+// SetDatasourceNetworkPolicy operation middleware
+func (siw *ServerInterfaceWrapper) SetDatasourceNetworkPolicy(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.SetDatasourceNetworkPolicy(c, uid)
+}
+
+// CreateDatasourceKey operation middleware
+func (siw *ServerInterfaceWrapper) CreateDatasourceKey(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CreateDatasourceKey(c, uid)
+}
+
+// DeleteDatasourceKey operation middleware
+func (siw *ServerInterfaceWrapper) DeleteDatasourceKey(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "keyId" -------------
+	var keyId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "keyId", c.Param("keyId"), &keyId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter keyId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeleteDatasourceKey(c, uid, keyId)
+}
+
+// PromoteDatasource operation middleware
+func (siw *ServerInterfaceWrapper) PromoteDatasource(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PromoteDatasource(c, uid)
+}
+
+// QueryDatasource operation middleware
+func (siw *ServerInterfaceWrapper) QueryDatasource(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.QueryDatasource(c, uid)
+}
+
+// SubmitAsyncQuery operation middleware
+func (siw *ServerInterfaceWrapper) SubmitAsyncQuery(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.SubmitAsyncQuery(c, uid)
+}
+
+// BatchQueryDatasource operation middleware
+func (siw *ServerInterfaceWrapper) BatchQueryDatasource(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.BatchQueryDatasource(c, uid)
+}
+
+// EstimateDatasourceQuery operation middleware
+func (siw *ServerInterfaceWrapper) EstimateDatasourceQuery(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.EstimateDatasourceQuery(c, uid)
+}
+
+// ListDatasourceReferences operation middleware
+func (siw *ServerInterfaceWrapper) ListDatasourceReferences(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListDatasourceReferences(c, uid)
+}
+
+// GetDatasourceSchema operation middleware
+func (siw *ServerInterfaceWrapper) GetDatasourceSchema(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetDatasourceSchema(c, uid)
+}
+
+// GetDatasourceSystemHealth operation middleware
+func (siw *ServerInterfaceWrapper) GetDatasourceSystemHealth(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetDatasourceSystemHealth(c, uid)
+}
+
+// TerminateDatasourceSession operation middleware
+func (siw *ServerInterfaceWrapper) TerminateDatasourceSession(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "sessionId" -------------
+	var sessionId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "sessionId", c.Param("sessionId"), &sessionId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter sessionId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params TerminateDatasourceSessionParams
+
+	// ------------- Optional query parameter "queryOnly" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "queryOnly", c.Request.URL.Query(), &params.QueryOnly, runtime.BindQueryParameterOptions{Type: "boolean", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter queryOnly: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.TerminateDatasourceSession(c, uid, sessionId, params)
+}
+
+// CountTableRows operation middleware
+func (siw *ServerInterfaceWrapper) CountTableRows(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "table" -------------
+	var table string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "table", c.Param("table"), &table, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter table: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CountTableRowsParams
+
+	// ------------- Optional query parameter "schema" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "schema", c.Request.URL.Query(), &params.Schema, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter schema: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "column" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "column", c.Request.URL.Query(), &params.Column, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter column: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "exact" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "exact", c.Request.URL.Query(), &params.Exact, runtime.BindQueryParameterOptions{Type: "boolean", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter exact: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CountTableRows(c, uid, table, params)
+}
+
+// GetTableRows operation middleware
+func (siw *ServerInterfaceWrapper) GetTableRows(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "uid" -------------
+	var uid openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uid", c.Param("uid"), &uid, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter uid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "table" -------------
+	var table string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "table", c.Param("table"), &table, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter table: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTableRowsParams
+
+	// ------------- Optional query parameter "schema" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "schema", c.Request.URL.Query(), &params.Schema, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter schema: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Required query parameter "sort" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, true, "sort", c.Request.URL.Query(), &params.Sort, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter sort: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Required query parameter "key" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, true, "key", c.Request.URL.Query(), &params.Key, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter key: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "order" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "order", c.Request.URL.Query(), &params.Order, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter order: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "cursor", c.Request.URL.Query(), &params.Cursor, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter cursor: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "limit", c.Request.URL.Query(), &params.Limit, runtime.BindQueryParameterOptions{Type: "integer", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "filter" -------------
+
+	if paramValue := c.Query("filter"); paramValue != "" {
+
+		var value []ColumnFilter
+		err = json.Unmarshal([]byte(paramValue), &value)
+		if err != nil {
+			siw.ErrorHandler(c, fmt.Errorf("Error unmarshaling parameter 'filter' as JSON: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		params.Filter = &value
+
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetTableRows(c, uid, table, params)
+}
+
+// TestDatasource operation middleware
+func (siw *ServerInterfaceWrapper) TestDatasource(c *gin.Context) {
 
 	var err error
 	_ = err
@@ -1259,6 +3553,94 @@ func (siw *ServerInterfaceWrapper) TestDatasource(c *gin.Context) {
 	siw.Handler.TestDatasource(c, uid)
 }
 
+// DiffData operation middleware
+func (siw *ServerInterfaceWrapper) DiffData(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DiffData(c)
+}
+
+// GetAsyncQuery operation middleware
+func (siw *ServerInterfaceWrapper) GetAsyncQuery(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetAsyncQuery(c, id)
+}
+
+// GetAsyncQueryResult operation middleware
+func (siw *ServerInterfaceWrapper) GetAsyncQueryResult(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetAsyncQueryResult(c, id)
+}
+
+// RefreshAsyncQuery operation middleware
+func (siw *ServerInterfaceWrapper) RefreshAsyncQuery(c *gin.Context) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.RefreshAsyncQuery(c, id)
+}
+
 // GetAISettings operation middleware
 func (siw *ServerInterfaceWrapper) GetAISettings(c *gin.Context) {
 
@@ -1312,6 +3694,9 @@ func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options
 		ErrorHandler:       errorHandler,
 	}
 
+	router.GET(options.BaseURL+"/admin/datasource-types", wrapper.ListDatasourceTypeStates)
+	router.PUT(options.BaseURL+"/admin/datasource-types/:type", wrapper.SetDatasourceTypeState)
+	router.GET(options.BaseURL+"/admin/parameter-violations", wrapper.ListParameterViolations)
 	router.GET(options.BaseURL+"/ai-configs", wrapper.ListAIConfigs)
 	router.POST(options.BaseURL+"/ai-configs", wrapper.CreateAIConfig)
 	router.GET(options.BaseURL+"/ai-configs/history", wrapper.ListAIConfigHistory)
@@ -1321,19 +3706,53 @@ func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options
 	router.POST(options.BaseURL+"/ai-configs/:id/activate", wrapper.ActivateAIConfig)
 	router.GET(options.BaseURL+"/ai-configs/:id/history", wrapper.ListAIConfigHistoryByConfig)
 	router.GET(options.BaseURL+"/datasource-stats", wrapper.GetDatasourceStats)
+	router.GET(options.BaseURL+"/datasource-templates", wrapper.ListDatasourceTemplates)
+	router.POST(options.BaseURL+"/datasource-templates", wrapper.CreateDatasourceTemplate)
+	router.DELETE(options.BaseURL+"/datasource-templates/:uid", wrapper.DeleteDatasourceTemplate)
+	router.GET(options.BaseURL+"/datasource-templates/:uid", wrapper.GetDatasourceTemplate)
+	router.PUT(options.BaseURL+"/datasource-templates/:uid", wrapper.UpdateDatasourceTemplate)
 	router.GET(options.BaseURL+"/datasource-types", wrapper.ListDatasourceTypes)
+	router.GET(options.BaseURL+"/datasource-types/:type", wrapper.GetDatasourceType)
+	router.GET(options.BaseURL+"/datasource-types/:type/dialect", wrapper.GetDatasourceDialect)
 	router.GET(options.BaseURL+"/datasources", wrapper.ListDatasources)
 	router.POST(options.BaseURL+"/datasources", wrapper.CreateDatasource)
+	router.POST(options.BaseURL+"/datasources/bulk", wrapper.BulkDatasources)
+	router.GET(options.BaseURL+"/datasources/external/:externalId", wrapper.GetDatasourceByExternalId)
+	router.PUT(options.BaseURL+"/datasources/external/:externalId", wrapper.ApplyDatasource)
 	router.GET(options.BaseURL+"/datasources/history", wrapper.ListDatasourceHistory)
 	router.POST(options.BaseURL+"/datasources/test", wrapper.TestDatasourceConfig)
 	router.DELETE(options.BaseURL+"/datasources/:uid", wrapper.DeleteDatasource)
 	router.GET(options.BaseURL+"/datasources/:uid", wrapper.GetDatasource)
 	router.PUT(options.BaseURL+"/datasources/:uid", wrapper.UpdateDatasource)
+	router.POST(options.BaseURL+"/datasources/:uid/analyze", wrapper.AnalyzeDatasource)
+	router.DELETE(options.BaseURL+"/datasources/:uid/cost-model", wrapper.ClearDatasourceCostModel)
+	router.PUT(options.BaseURL+"/datasources/:uid/cost-model", wrapper.SetDatasourceCostModel)
+	router.DELETE(options.BaseURL+"/datasources/:uid/deprecation", wrapper.UndeprecateDatasource)
+	router.PUT(options.BaseURL+"/datasources/:uid/deprecation", wrapper.DeprecateDatasource)
 	router.GET(options.BaseURL+"/datasources/:uid/history", wrapper.ListDatasourceHistoryByDatasource)
+	router.POST(options.BaseURL+"/datasources/:uid/ingest", wrapper.IngestDatasource)
+	router.DELETE(options.BaseURL+"/datasources/:uid/maintenance", wrapper.ClearDatasourceMaintenance)
+	router.PUT(options.BaseURL+"/datasources/:uid/maintenance", wrapper.SetDatasourceMaintenance)
+	router.DELETE(options.BaseURL+"/datasources/:uid/network-policy", wrapper.ClearDatasourceNetworkPolicy)
+	router.PUT(options.BaseURL+"/datasources/:uid/network-policy", wrapper.SetDatasourceNetworkPolicy)
+	router.POST(options.BaseURL+"/datasources/:uid/network-policy/keys", wrapper.CreateDatasourceKey)
+	router.DELETE(options.BaseURL+"/datasources/:uid/network-policy/keys/:keyId", wrapper.DeleteDatasourceKey)
+	router.POST(options.BaseURL+"/datasources/:uid/promote", wrapper.PromoteDatasource)
 	router.POST(options.BaseURL+"/datasources/:uid/query", wrapper.QueryDatasource)
+	router.POST(options.BaseURL+"/datasources/:uid/query/async", wrapper.SubmitAsyncQuery)
 	router.POST(options.BaseURL+"/datasources/:uid/query/batch", wrapper.BatchQueryDatasource)
+	router.POST(options.BaseURL+"/datasources/:uid/query/estimate", wrapper.EstimateDatasourceQuery)
+	router.GET(options.BaseURL+"/datasources/:uid/references", wrapper.ListDatasourceReferences)
 	router.GET(options.BaseURL+"/datasources/:uid/schema", wrapper.GetDatasourceSchema)
+	router.GET(options.BaseURL+"/datasources/:uid/system-health", wrapper.GetDatasourceSystemHealth)
+	router.DELETE(options.BaseURL+"/datasources/:uid/system-health/sessions/:sessionId", wrapper.TerminateDatasourceSession)
+	router.GET(options.BaseURL+"/datasources/:uid/tables/:table/count", wrapper.CountTableRows)
+	router.GET(options.BaseURL+"/datasources/:uid/tables/:table/rows", wrapper.GetTableRows)
 	router.POST(options.BaseURL+"/datasources/:uid/test", wrapper.TestDatasource)
+	router.POST(options.BaseURL+"/diff", wrapper.DiffData)
+	router.GET(options.BaseURL+"/query-results/:id", wrapper.GetAsyncQuery)
+	router.GET(options.BaseURL+"/query-results/:id/data", wrapper.GetAsyncQueryResult)
+	router.POST(options.BaseURL+"/query-results/:id/refresh", wrapper.RefreshAsyncQuery)
 	router.GET(options.BaseURL+"/settings/ai", wrapper.GetAISettings)
 	router.PUT(options.BaseURL+"/settings/ai", wrapper.UpdateAISettings)
 }
@@ -1343,62 +3762,247 @@ func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options
 // const string: with thousands of chunks the chained `+` fold is several
 // times slower for the Go compiler than parsing a slice literal.
 var swaggerSpec = []string{
-	"7FxtbyO38f8qBP//FzawlqXLJS38zr6HRLjk7mrftUAjw6B2RxLjXXJNcm2rhoB+iH7CfpKC5D5Ry5VW",
-	"siRf2gQBTtaSw+E88TfD0T7hkCcpZ8CUxGdPWIBMOZNg/rgg0Y9EwQOZ679CzhQwpT+SNI1pSBTl7PQ3",
-	"yZn+ToYzSIj+9P8CJvgM/99pRfrUPpWn74Tg4jJfBC8WiwBHIENBU00Mn+k1Ub4o+vc//4WyVCoBJEER",
-	"UUTyTIRQ/8gFustAzNGE0BgivAg0hUu4y0Cqw3JdLLoI8JApEIzEZt7huCiWRVcg7kEgu/wiwB+5es8z",
-	"Fh2OlY9cIbukXX6YpDEkwBQcmIn6wnpEPlnTPh++4WxCp/pzKngKQlFr+CSlN7cwv5FgWHSp/m0GagYC",
-	"EYbOPw/RLczRjEg0BmBIKi4gQkf6y3sSZ4AYaE0IUJlgEB3jAKt5CvgMjzmPgTAtnzGRcJOJWK+VP5VK",
-	"UDbVD0MBREF0QwwrEy4S/QlHRMGJoglUFKs5NPKSovKGhIreQ+1pjY2ER+DngZEEvA9Swe9pBMbGgWUJ",
-	"PvsVhzHJIs0WT4ERigMc8pTGXOmv4pgkBF97eM7SaMN9LgIs4C6jQtvUr3rTOac1vgJHl8UeayKvS8UR",
-	"tsNRxTAf/wahcfLCfH6iWutzjxWF1mJqorHkK9pYm2wM9pPhwnzrk084I2y6oR2EhsGbFnPIn7Yqt2Va",
-	"XecdNFLx4K7oKsmKytllB5n/TKUqA0BD/vqY0P9SBYlcF0yWtbkoVydCkHljb4b4Khb3wNvzmVrPUDc+",
-	"uq97BUpRNpVvc/ruqnmsWLPuGzOqoFRF/CqyrCNgh/koACPjGCJ/RMzD1Rrqn8woH/E8Aq6bnwKrBFWf",
-	"393Vim3U5vj0cUFUOPuLBkxDBUlTH9bl3fPODEc0AqbohIJAR9Cb9tAIn49wgEb4YoSPe+gyP+EQZUiA",
-	"zGIle76QJCpotkomZtESUfniSkFo9TZrSNDdqUaNxaa7+OCS5PRxSdnQzhyscctirXWstvlmLs8teL00",
-	"MwuOVzJZLLKWyYLgViGkRsR4XwGQXZP7DOLEwnozACUgJZkCohOkZlQ6kL+3CQBiMtVb6sLkMB+rMaMi",
-	"SnaadGVGeuzVJ1U3rA1ZmqlWKNqU0bskVXNk94ZuAVKJ1AwQPFKp7Fdzn2RWYs02BLhYy3278S5h6Q3R",
-	"70YcuVH+dyfQlkPqJSVq8GoFHlpiaU2kuxFPlXEklP0MbKpm9Si7h/xjyWOXEep1q3DelrWIVvEkYOMi",
-	"iSKqbY3En2vPlcjAQ72jALixXrkxefvFGvJ+oZhB1co+0WihvBf5DlxhTCjEUfej7L0e3jy7AjzR5L/k",
-	"u1hJoRzYnsgubbSiHRT8tu3Sqt4DbG2md75BsrYSiT7Lhra1Gjc+vhX0HsSJPhXphIZOFS6nt8xDgB9P",
-	"pvwk//I3yVnvkjz8Yk/zuhkurVRR1gN82DPlUk0FyLvYgtAwpuHtjGcSRvjYJ93MQoJSFVlmUGRbGeJ8",
-	"2ypEVi9DLLlKUIPplYHU11xtZjuqNKyoLmxir5X+P7bZWTWk8NQVQ762gbaoY6XBJdVgsMFOs+zQVQM7",
-	"zO2b2t06ya9I7YW/XTD2vPpDnZfN174yVNZzsEGI3YKJIpVoOvA9vOEZcx2QMvXD68r5KFMwBWErHxlT",
-	"F/PCr/xMd6LUxAZckbg7L0tCqM0OnH25PHcQ066MxZ+UdVDWF+jiRt2YyGmZvHdrTvTsBh8xUcDC+S9d",
-	"9Z0n035gcOuHHwrkcw5FfourddfsdJ6C3CB2NbawXYhy75Iay5Z1ijVVODPMR9/i2AbdW2ov5dZC4A/U",
-	"XqXFZAyxXOXxzRPWwVYfYH5ib6YsKUSUIuEMIqS4yXcN4M1x1mfBE1AzyCRKQAka5pOOe3gTuOlHeR+J",
-	"jg0GRuok0UK9PP0+iqhMYzJHnMXzY2+Jx2zCSSTWKT8HZUbm5fxWZX3IVeMyfQUJYYqGlls+QcQKLECZ",
-	"hAhNuEACWAR2F+SRSiQhBoMzAmRdR6f3Zk8FWMt9iGXJ2NyG5JsMSg/0Ibb39QTIZfInypRhZcYfjE7L",
-	"fAzJGc/iCI0B3VOZkZj+w1bQSlY0PtXipgncSFu2DHDMp9LLhFv6bqm47Kwc0VJo3+OCTmX+91ZQarlX",
-	"eMF6klNYbQbZRwgzBZEZ1RTne8pInJd+yUSBQCSO0T0RVNssktlYKqoyPdp/80AeWih/EnRqiCtIUn2c",
-	"ojFMuIANiBcjN8yp32dxjEwzxKOqIkh9NXREWRhnkTaYcUZjdUIZurkpWfPG4uXSfrHzYEnG1206aq1l",
-	"xTSheU/EhBg4Muj3+30fzrjzC/uSPORKLKTdy/tVTiSNAD09VWJfLFxZUIlMuwhEhYbsfrRW0EUhnVI0",
-	"6OjmBqUCJvTxGBEBiDK9S4gQyRRPiKIhieM5mgieIBPxhE4DeyPmU3E1YN2J/YUmcGkGbm8ZXyWIkwgm",
-	"lEFU25E2j6cnLZjSVltss8UW7tYp/jlwd+l254WuW1pBXp29ZmFSn49yo7zYljjXwY6ccCtDLSnheK40",
-	"ECZRR1hfeoK2vs7JgOAPsri63SbbW151iaJv0zqX6VA1P2Rlu0NJu/Jpj+XwxIMUFRFKI0R95uvYgWxw",
-	"QedhCKmSaHj1Cf35h/4AHY3wq/6r1yf91yf9wZd+/8z8//cRPg7QV0YfUSIRkYggliWgQXgBkkd48KfB",
-	"q8EPffufmcAFIkhAbME1PKYCpDTnyAgP0E88ExKRKR/h47Ywxz1IhkWrdqK/lxpdWusx3I6MWEY4QGmc",
-	"6T8/8ocR9q7pQwpfTdVyk5untdhrL7jrEL1xq+RTobsWCW3TYWOB7tbtNeX0nffWlJS3aawpJ6/uqmkR",
-	"dYeItb8LnL1cAjb3ujBn9oT7b2TQX/mcTEGgy3dXX9D556FJE1UMy8/to3sQ0k7u9wa9fqm1lOIz/F2v",
-	"3/sOBzglamb4PiX0xHYHmj+nNhXROzCdwcMIn+GfqVRFRDAnTb1X/VW/v7PGYm8Ln6e/+NMHvavv7co+",
-	"giWHp25HuGlEzpKEaIBs9mVymfMhKmyzuFKT6IhxlIc521Iszf0W0YL6FdfEdq1Nm0uP4Nxr/Kp36oJH",
-	"u+vs9/cKLNyjVhvioqG5wc41t0prb/KrsUWAX3dRXe33BLvQtl3edI831d2m2UVQ95DTWXURuNZTimsl",
-	"7WwaiSoQmv4TploYd3leaCNNnt4FNWmXed73/QAn5JEm+iT7Xqd8CWX2r4EPJvoX4JOJbcT2rFAn6cko",
-	"F9cHcHnfBd9hPN/qFtmbSJRrGB3lviORzoo0gAnljfmVwXFHW3mi0cKKOQYFTVt5a753goMj49eeyi1H",
-	"b3Kh70IKloPcI8KCjZYI57X3H0G1b6B/0OhiLeO1FdtqmZS/ytmFEH8E5UgQjedo+HbFSeEJBvo0rly1",
-	"7LKtQnfdbZeR03WA8/KoqxsXye/p8PGnC50On5cxj43PncNblJWpa1RHYPKqAo+4idUmEem0+LGLgdH7",
-	"sEUvEjrPV31GuDu8Iq5AmRTbXqbXtRHGQIREXM1AyI3EvwWCuJiXMvsDSXyTSGIJO0xMLajs1+twuO7e",
-	"EbXtVW1XJ2Vpt+0YX+7a2KOi2rpN9qckfUbXuiYrRFfTSPW8cN2a+LR4V+fHS40Uh5Gf27OxZyOXWZpy",
-	"oSCqi1Llm+0ixa4ClM1At3whGSsQGmgtcYIDb8TKH7V7S9C+Qh77tclksoV+1VzaWKIsRK1aw/SMctFC",
-	"Pe8ovZjjNSfvAQzu0OlZ5BiF38hWF19qrYv7LL80q5QHLsB4Wj2/3RJMTa9dY0cn4NTs6f0DMm3UA/1y",
-	"/r1d/WW1yajitsAbH9zr0L1myf6b1wNnyS3ttbvMlZ9tGJozz+9b0ANVM54pJMm9bdDrZgBPWaca3NIZ",
-	"8UJVuC5BMeiA3w8DPb/ZUlzdfMZz9NWpxTWQw9q8K1uTeK35XdOa6tze0UnbHeqLxZ7/shrdNkjGRKXt",
-	"8MzF3LGYP7DNN4ptVheEOgX6A4Qmv2GW7aOHCY5ebGb68/YeHJfedXLQiOg2fb5wMBx0muC80U1Pe9WJ",
-	"teJNhq6bvLP90IgUPckzwbPp7DkR1RA6HRMVzl7YfKvXtuzdhptv3DmwIXveo/O/a81JFiuaxoDyNw95",
-	"zRoRFuUvJjRJcPECoM2svdJOh5K+HXuYmr77a99dpgdbKHblNYDhND+cv+0zuaxkvFxMc2sY+JsqVBwa",
-	"/ZsyReeTSuZ9sKe2cba9kaZomN1vK43zYsD93rmFmRDAFDofokIIpq1SQihAeboqi1HW21Z1tTiy2l9f",
-	"y3Kbd6fDtUMrw+FLa1fEtjBUiljVUmJ106IaQ9j8csx3O3f+eYjuBzjApqUfn5KUnt4PsI4sOa0Vr51J",
-	"CCNTE72rkFb3qOY12nml42pvPjLlBjw0ag2h9lo+sybnJVS7u19cL/4TAAD//w==",
+	"7H2JbiQ3kuivEPUGsASkrna3Z6Ybi0WfY637cks9nvfcXoHKjKriKItMk0xJ5YaA/Yj9wv2ShwiSeRWz",
+	"Kqt0etaLhUddmUkGg8Fg3PF1lKpZoSRIa0ZPv44KrvkMLGj61+H4HbfpFP/MwKRaFFYoOXo6en3MJ2ys",
+	"1YxxVmg4F6o0TIMplDTwjNkpsAstLLAxF7lhF8JO2eODR0yM6VnGLTeq1CmwKTcsnXI5gYwZIVPYHSUj",
+	"gXNMgWegR8lI8hmMno4OxzsOmmRk0inMOIJl5wU+M1YLORldXV0lowAGreAFz/7GLVzwOf4rVdKCtPgn",
+	"L4pcpBzXs/dPg4v62hj2TxrGo6ej/7NXY2fPPTV7r7VW+pOfxE3ZRs4LnjE/Kfuf//pvVhbGauCz5rIb",
+	"fyrNfi1BzwlXkI2uEhzhE/xagrF3C3WY9CoZvVRynIv0DgGoZrxKRm+UPhVZBvLupq+nvEpGfvuOxQxU",
+	"eYc4CGTjJybywQPjCCQDnuVCAiu4MZCxrVRlwL6M6OmJdd98GW3jCg6lBS15TlPe3QLCtOwI9Dlo5qa/",
+	"SkbvuMD5uUzh7qBBIEQK7LPk51zk/DSHCqWNEygME5JxNqthZBdCZuqiQnHj0ZfRdoKHVljDLriGqSoN",
+	"jaHBlDMhA2OUTGQ5MFOaAmS9WdUnJ+H9L6PtL3KUeIZHbOsTWD3feT62oBeZ7xGkSmaGldKK3PFaByzI",
+	"zBBoVrELLiwbK+2ehzl3Y8wTVzYBjQi8SkbvlX2jSpnd3S69V5a5Kd30h7MihxlIC3cMRHPiq2T0UROi",
+	"Bb7xxvHmOwOnOTdzkxPlhkuQZSJjUlk2o3/hJqel1iAtOwdtcBAc1M+H4Dw/RAYrJvh3oVUB2gp3R/JC",
+	"nJzB/MSAXSS2n6Zgp6CRnJ9/PGRnMKcr+xRAMmOVRjaEP57zvAQmAQ+9BltqCdn2KAk0dqpUDpx46yk3",
+	"cFLqPHJ9J6NUA7eQnXACZaz0DP8aZdzCDjK4UbL4jciiQwlzwlMrzqHxtAHGTGUQh8HJG5EHhVbnInNH",
+	"EmQ5Gz39eZTmvMwQLFWA5GKUjFJViFxZ/CnP+YyPfonAXBbZmuskyebXUmgkw59x0R7SBlxJay/DGhso",
+	"b2KlhewWRDXA6vSf4G7kQD7fC9z1eYSKUkcxDdS44euxR0jlObi/CAr6NYYfLxKuRQcpAXjSQw7+ae/m",
+	"9nzW3PMBO1LD0J6xvUkOVa1VDsD5W2FsxTMW8I/3Gf6vsDAzq/hPdzevqtm51ny+sDYafBmItwDb9YFa",
+	"DdAwOIbPewTWCjkxr/z47Vk9r1gx70t6K4xUXxI1Z1k1gHstNgJIlIGyOEf07GrF6B/ordjgngOu+r4A",
+	"+fww9v3woxaW0fgmuh+S5/PfoKFKdfZD5eVMmhZlLjCAGb88dA8f7SejmZD+Xwdd6kycHL54hf6IP7OL",
+	"qTJAMmJuUVrkDrgsYdwwzkx5Sp/vxjib4SiZnORiJvwVPeZlbkdPD/b39/e7ssMndcFSXrCLKd3R3Apj",
+	"RWoY18BwQ0oLWVDe3cg46Yxfilk582O6pfofkgVJcYkKnowsbs4iGo7xZxRN/cp32etLntp8zpQEpsaM",
+	"vmNcZl7dIZHabfruyvsw7OVSOrgWO6gGQcxfJSjISyThxZW+V3JnzC3PUUITKRjGT1Gba6sdCYPdyS7L",
+	"oNDghEhcZT8hbsgLW2APOgLLeQu+f2S5NYswIYfSGnIeJIHlI1WvvuNWi0s6bGCnKmsKEebXfBQOQFRS",
+	"0OoisgWf1IVhJuVS4gkTMs3LDFUzS6dQlnmOKh9Kq3PmcIDIr+QMIe13j0eLdN/BOs1dQZ1U2GwjIrot",
+	"RZHPX1W00MuiGgy7vcBXjgUYPFBWl7AblbVBngut5MwrLEtVksarbifoSPDMKSE8/9gADGeMrCoIVzMh",
+	"34Kc2GmTedRbpmgRZu3hiS00bDJtjLxzDMwzD11KZsUMcJuNV5idOixM4xA+Y/tsBlwaJlXjZ0astsUW",
+	"//Ld4xZX3I9xRQuzIucWPjtpsqKnsiSJsOdML+xtDQe+8IwhHSvr7aRMyRSYF64JxGXY7lCsF0bppXoj",
+	"ohRq5jL9MdxoHVlfp1NxHiPLY12Cu3jstLrtLrhhphA5KrFWMTcHaY980kO4SKCkKTxfRwFwOFnnk3rL",
+	"h24ZXBZCg3ke15Vb60ZK06ooIHuG9Ii3BVGnAMMyReq7G63FezbRdY34DV7MLUQ44WuZqoyM67+5W3YK",
+	"QXH3YBI9jYUUZgpZC5Q+NpiMjOW2NE1G7Rc4SkamTFOAjOQzb9P+ZZA6296MapLmxjbxn9R0uJyAr3nx",
+	"V+MMv3VfoFWGvkFpcXFekfWJiSIDacVYgGZbJB98GT3/MkrYl9GLL6PtXfbJ21aQr7n9M1GRUdc3yrLF",
+	"efy4d6ObEgZavszeC8zT+2ABo4O5q6UidwfeMNcqUPuoweNzA1ideBUg7kpFNy8pJiQmu3eBp1N36SWs",
+	"0DAWl5A5j5uwhonsGlJlQMhKhIbFb3TAGoPgwBA8Fh2LKOgdd7XTC2wGxvAJOJeiMC0fWvRE0GcvVQYx",
+	"0SGdCgk7GnhGSgjZ6lFcoI88/hccLcumQUFwcaKDHTTFZV7kDOzYgY5/uaUVSkhrGLeJu0rPpLqQu1E+",
+	"TB+8FRL65yKP0fVn6rOySlNAOozPHPp3ndT+ttZmm2C/PXx3eOxuKedC4lnm5IZ6m58xA8Bap3k3jLjb",
+	"e1+ZQUB63WaRFUYPQZmf1dLahwJ0pft09CwS3VZC8JkMpYt6QUtSWalZaegbZKz89wu+T+YsswnjuVFM",
+	"w0ydkxxDIxhmp9wyDWPQgNJCmz9FJThVLNqCK1NwZQluGILpt+ofUaN57No85noCtiXSh41zJ5h0PFUw",
+	"uEyhsKyCZIWk1yEAVQwggN5bUAXKWONy6SGtlknqYH9/nQuyAcaQxdyIOXdhUM/mu5fkuPKwRU5vJVFG",
+	"HsdkshVC6JIlR60kQ26xepTWJbb8Goqw0wwu40hQReP3+otaEm+fi++Pjz8y9zBw/2r7oyyyHKQAdfki",
+	"wUvAVaDE8Nw2ah/KorS9jsiIDjMr7Jw5ENgZQGFoPXApjHU/zWNX8VJPY5//72ol9P0Ho+NJXdP3uRZE",
+	"JEC8EbmPEIhZ9aKTqGIRvejS5kKaBOlFW3NC0iNKmCAz/y/vZ4ZLy1JuYEdIA9II9CTm82eojVh+BmjW",
+	"ZnSknUP4GRPmhKxtOJpU1v0DX2VSSdilwIdwScCvo2QkYZSMcov/wb8m+NcERkkFpSO0ACZ9nlV/C+l8",
+	"nDgNDuZnjF4nBOHo6de4KdkR9S+9uD8KEkUnOKO2tyuUXpBOpZI7tG6a0Txj/NSAtJWdRAPZ5gkho2Rh",
+	"L0vZNmj0a+Uzftl6M1Plad64nmU5O/VvIkEOfTUTw18WQ9/sdcQipszABRePngycrvjz0DeNzTI4H/Ry",
+	"3LjmdiwsJE5BLQ/d744d9jgY75Ufdr0JEQWSa6Mka5jm8WIk9bngQuM/vAH/GbI7LS5/Fr/8/M9fmDDO",
+	"Y0DnFQTFw7g38VGqpLFcWuaCrebMTPE0j+GCjj+XzF4ohq4Cx+028EF2ZaVZtcTqm+qPRaJF2J1LrmVX",
+	"rym+O/xSGbL2cXgo4gRu7LuwfZ0FU5hSGiHrw6MP7PGjgz877RsputAihOc9Y1nD4/H56FVU+Z4JSWbQ",
+	"F2RsXpyCHrJTekraAZfzSinmljxGhuVgTMJOS8tmSoPbQ6nsVMjJM2cKONh//Jcnf/5un8Z4ISY/Btft",
+	"As9a7i8oQB+L0wit0sILJCA4FadzC2zr0X8+3mf4p9muPVsEzXe7j560IGFK7mQwwzsXUSjkpAVbRRkR",
+	"4HpYmwc0utekUNUxFz0qUIOb3QxnGuxuurGwrTi7XxqC0KeLL6InFzwuTqA9Cqdihp9DVjkQUi4ZBZ5K",
+	"Y4FnQcYvRZawUopf0Q8jkGbp54YjkEQMbi1onOA/f+Y7v/2C/9nf+evJzi9f95PvHl39KXa4NncmwqWL",
+	"Cj6kIznjl2G7Hj15kqzavgfgiey457RAyc5/u8t+Qvm44fljBmyCWDdAMqAWnp2Fd74x1cej36WbUwMF",
+	"vsb87kiK4TFBooFnO0rm80C5CbNaOAeG0hlodgpjpSuGP+N6jm6OIufO8FRHtubC2JY1e5g+/smBE73p",
+	"NvTX3o7Ltcssjj10vUyjhfvNGeSm/njLJ2sKLneBv6xG4A/uvuF5/mE8evrzUILBz66SLrKjovgRoB/G",
+	"sH/s/F3N+QT0Tj3Mzg8w32VHU3UhGR0A5dOblq8X51lc3S9XCcUFvNF+T9vAjQXk2XDj4ht8PWqFw+GP",
+	"/S4tHaF6sV+X6yysHjsJ8MZ28VXL0H1rt+QzJ/k3LjUyd+fnYJiwKGQKaxjuXc0vd+Nhxw1Zd3nEU3hx",
+	"w1iF2v+3kvk1Xl0V9tm51jt3HRS5mhN2mpjK+SnkbCuDc7IZTYScoPNRZdu78ZiJ5v3fSfvieQ56hxsj",
+	"JhIyZtxe1n5wL+Rydgxac0RV5ZdA35AGE/eAz9oZR8vQ1UhO+olSaa4tdyyyYrAXSp99VLlI56vged96",
+	"+Xpyyo4pIBVjkbZyDv143RUko8udidrxP2JOy+4nfvHOuVoJEC/PrAnKBzcfM2CZkgv5V9ZAPnbqtbBM",
+	"yCloPHw+RjVc0s8C2Gyq8syJBjPQkyqSZXf99fy+ZK1bE3w6LjX/sLuyemcqXRy3aHe1M21gpFss+qVQ",
+	"xk40mF9zFwaT5iI9o2Q2zHTs9xKuhMgnpazDgUNq1aLpQqbaZ44hffu4UvL5PgseVR+3wx3hYgb1JiGn",
+	"ZTPxpyMTJY3A+GbIVL3S5fftS17wU5GLcNu27164hDRu76aVG79ENEBKZ+9iW69evU3Yq3dvt+kiPgU8",
+	"Qz2RqpdFzkUEta//8fHt88P3TBhmyqJQ2nrTjTuURc6liQ8p5MRLzZ2gm1f/cfThPdOQKp2ZANlpmZ/t",
+	"5IpnIXrm44ejY7ZXU7/Z+1qK7GrPDRufEvcl+4g59KYnwMvL8rt1oj3Z/k8x/5Gdzrus0cVA7BiRhSig",
+	"l0j/31PO6Vec7ilKVFeM1CXki6B70NHIBewc9ykw95BZDVAhBLcQsp7BKKMdj0VEBaSgoTAMmkfLGel7",
+	"/ozwPJ/HR7WaS+OyoyJwvitzK3ZMIDjWfJuQWNFHfHSqSBAZ9/D90etPx3ufP756fvx679Xrt6+PX9N4",
+	"PE2h6BmucyzpcNRU3ERQjfkKhM5K23RTEe7yw/pK8NwH3nRUgVKm64Ua+KHe+A9j90XNmH8sle1Jjwwk",
+	"fWTnOQyc9GP7I0IrEX32E57O9dRL96QPwoWQnvaS2p8vLKcLWNJA9KCdul4U6uLGDw5GrT+9oeTNJQmb",
+	"m8Vev++TmutXgk66OoJ7WMz0kPjjDoAL4Cxmcj63w3bgBtMlF3d341yhBcNJG6wNVNaeHRlmL2hKOvXc",
+	"ywG/FcTeBEY/hdi5vmDwBSSdCRmRzX8QsrJihHg8FLmCSuylBHUhQZupKGK7Mgz9NH/SF/kYWdmt4L7G",
+	"241sglOIFoC7O3dB0Fir4JCap3xjEtSCRIoCEvtnaVwc+FSZ9TXbuA11mfF0ncC/ocdm/R06olFWQ7CG",
+	"UWYDIEJoz+IleQ4v14jHoUiQF/Nwd8WBHjTSArRWWZ4Ph6WDhMbXSWtdbZgHoOmmiCUedj1gs4Kx4kbu",
+	"q6EOlRviDM6KwrI2h/BWFiB9sKF/bmDe2txDc3dmlbUMHJuYNQKF3Mr9FAa/ieup9vfdzJmqYdsEFmNv",
+	"Dg5jfWj15pBEA7NxdTKdvxvKRn3SUPwIn8X9IxbMdehZnY3qeVesdF7AoRyrCCvrWOaG4b1lz1vGvXoP",
+	"fcPgOXCr5wX8vVGAqnXpuMMcJPomcPVMqzF0Y1QZsL0JTc4LurFgacZ+3D74YHagNhYPRz4t+iZ3wGHx",
+	"Oltgbp6n13DdAFNv7MdiPGIp8ozNwHIciXFW5OWEMnsLpW3IBnX+sV1GXntn6gQKAUYzuPvCZ2j5rHL3",
+	"OeOhFlw88HRWcCuipVpC0TdSMn3ius/GE6YZVlZb5DXaP+J2Vyfe9OLgZS5ccM+p5npOiQIe7CrvciLs",
+	"tDzdTdVsLxene8Wv7Pxg92B/9689OZgzfunqT/ZO+kZoY1kp6wX49WnIgRvoiy5dMeyHPANj2VqjNk74",
+	"8pskvJg092418a1zPm6o6EwIPxgS+Ni4kju1UEuRUSlOpHLvRdDG0wRRposRc74AxWZioslpqaJoNqU0",
+	"YNe6xnvXFU3xDOEZ62kcyySSJsgLflNgfGxBs4up8AUgG26jGZ+Tt43SOLOh5SU62xtAS9pLi254x4Ow",
+	"caTawgPnVOy1/hoxkdyWeoA9y9969RdtdWzJsj4uODY6qXbqgp2iXa7j1ENvj4XAxv50wLYyjC3W20xp",
+	"9u9si06EUJKCZoLhXSpJoNGbo2QUXopa3V+J8fglmZ6vX9qJxqJv/IgR7dDHwG1ufXGpKcuKdS2AsU52",
+	"Ww5jOi3tlAeEQUymsSd9qV80UPisD8whheUW6z1gtVT/ArIt4uUadlmzopMLIJCtt9mpslNmRAbB1e6u",
+	"9eG6/RnMIzC99LB4f9gcL3uO/vtnIY5cSRcrh3MvryaxtDxe2JxVRHhUGd0HFrwLYToUc6CB++p2Nczs",
+	"Of1vI0KBEjtccXp3ldBOMs293MMlhbOVqcNGwbUVPGeZGI8d1tcslzfjlyd1pbJ6MUuXkgtjIWNFKIKQ",
+	"BIZOQlKomj/RqiwWK/itgqg6EcO3w6ocdIina8P9/NSovLRAGPJFAkqZVfeTSysxjKyLjBsGv5Y8v25W",
+	"SuuUevruP6zXUlrcCBuX/vNZY3dd/a8B9sKyiaTiqeb06CReWe8NZsLhI1ZooDRWH9vsDhJuRWsl60WU",
+	"d+sJOhqPQ+kfVnAOv+V677dexh2YJGlZF+AraXJfmnJ9Fjz8C6TxE0TxyUapgvR5wFCECziGsvThRoSA",
+	"894cHRBZXQML7vt+NFhdSpJvYxH+PlE7sHCWlpbyBrW6IBbNzFRp+8zxNsOM5XMGWNo0rg6XcglVL4pL",
+	"plVXcpEaoshp7ntr9f5sj+qdrw9ZE7QWD+hQQufkNbHXx4OOeuL5a354MtC9sLTGbohqxYsSLx9/U854",
+	"qtUOXBZcZkARmEKyVjTeFxmb6xo1bjXw7JoFbpORFTM40UEIXsbVMIL5U2Bq51wLnMlc31Fa701sZ19v",
+	"mjnQ1HcyOHcVHSYuWA7Frqiu0+6ZEJG5B1X2cnWv8OW+ql51+cpo1xWMwOTGiLGAjO7zU278sIZSTXhp",
+	"pyeu5AxmYlJJrZPwYsIK0DNhjFDyJAMp3EsZjIWE7MThFtVDM5eWX57QuD1pJ5uVGGuDvHatsbjatUEB",
+	"sk3h6FCpAypGnS7HaYFOQljNyvQojLShxSHFmmX+++Vu5NEPMN9x7THcUIxby9NpKGoGjJKhfMD7R61m",
+	"YKdQGjYDq0XqP9qOpoqu9Cd0pFOOnv4a9fhWqCOxlQlT5HxOt3g8jYcW0bp5Vwmm3ubiQ4n8972b9UM0",
+	"4ukIZlxakTpo1Zhxh7AET1vmc0yR29Mq+KUwzEAOqauT6C4UK+SkZWXxBjCvV4TQz1FSXdQxDvSmmRzX",
+	"MQEJ6drtTNUF7WmVq4fSQZlnaI47F6bkufgNshYo3NcWQW5vXAXLZJSriYkCcUjBwZ8oeL1q7tQm8XUP",
+	"6tvGAQ2a8KnK5pRn46pmgw+XT5xqfTDgZNJcyZIDGlbSWz3Eh1/HQsxd6L43sC1hV8MVg0W8Li1SFgeo",
+	"Vg/QDePB85mG2DaHSwbS4tUv/ZUxAJEVHqr5q8XF0LqYzBbxUWYbWag7B5MyW63Cc6iNb3AQEi+5BqYB",
+	"YarKVHwZfS4mmjuKUuyjS+w5+vEtO/iux5FDhZ6WFz+X6mJD+zZiIYbA991UvW4Oaq4uXopMr6mDZCDn",
+	"G3y2oDGuk8O8yJ2Xr3ZJeYrmoju3ihvBJEjVLw9ffULi9/V1NErbRshJXiVrUgWAqLvC8XOfUbYhYuOg",
+	"xSZ0vaqa0yZod5Uu+4djFaU1TTILuG33Sukp83RjNZB6OrPc4oStVi6/typWPY1o7rGIVeVn+rtQeY+X",
+	"kW+UYHGYrcid6M3AyIUFzfOetDH/1Be2Y2PKYhOSUuer/nXmmd9ldyv8Wiq7rtmsR+s/rjQK5DLl6UxY",
+	"um5CRRFvDjDuSg42gZ566e6uWh4M4SbDfgYaxsQ3mh4DXVbMw1uIeyxABvSwGHzXmS283tnRSIKKw1Nj",
+	"1xoL+2UQxd1UNM3iyLF9Lao7NkjDajweOQN7BfvqSkx+mKTfEP5RnCt7rLk0eHQilFRq6W8JYj+pDTRd",
+	"VYVjQlrl/za7zHWYmXJdCcjq4sSVtQifpujDLaj6hFVMSfCSYApYEHMy0TAh6qTXYzE61Tstt9HIlLOG",
+	"/uD+xc8nznXiHEGNMoRjoY2N52z1O1LrxawnrYSamoO6NQWVML5jWs3UoAiSzetShUPY1ZJnlTpUOCiy",
+	"ZhGQlqf2y+hLub//beqeUQkS+gHYlnvQgM492Hay7h2kmoT2r9aVyG5AwraqQg61uaub5F9XXogZHxaE",
+	"6RqzyxNNyPD62lgxi8ZPnsbbmLjKfTUPFgZ7pxBnw20wKZcDW5ekKpYUHgBCw5mx3nfZzc9xz+hKr4u4",
+	"CjtFD58v3jqgwGizAmKs2pnvgLUYY4LggIeTLqEZb5gxfe5x9caXEYrfX0ZjkQN1f+nRsZY00OpFtwae",
+	"tcvYekPnJiUN3I5XS++lmcO61cBiZYLSQvZjXEp4I7AhtZcTKGaKUy1eZyVHucFYYcvgg43U5Lagz3ke",
+	"C99Mz9A9JDI7dWrE6Zz96YRsyX9Dt3y1OU9mX0YLdX89TJkCQ/YC8uTjEPj9UlDe9chj4TnKYDOR58LX",
+	"KRl4NDS/6MHhBy0mhMbAEoKMNRyNg90SETcjNV2+tLXFrzkb26r7u52WIrc7QrKTkwo0M4B9VStPOtTU",
+	"S42fYKzBTJe2eugKktz6+AhTEQY6EV1QRIjncAa2R/t4zg4O/uJO7fAb2HGXlTehiwo4KmdYb89H6Y7t",
+	"qoZetOTQN8ovxQGMT5UnkrjUG56uDZmuAhg2bNPkBwhrTKotagBVI27VjvfKIWcwf7kqfsAZydIppGem",
+	"nNU9ObnGJmGcSD28CdKFeV/XCtEG/jpRMM2RhgehLG8Q1RvjFQ+iwllOnLMoUjYXf2enZYbiDrKJJi+u",
+	"bmou8S7LRSpsxTB3GbLP0yY/F+7+MzOe52AsSoQHJmFPTMIO9vE/+Ne39NcsYU9m+DP+B//6lv6aJuzb",
+	"acK+mybs4BH+J0vYnzO8lb/dz5ytuLZ7IJwuir4OsBd4xcu5d461BU/Ekg9FWxrnVcekrsd3/x5Uek1C",
+	"bdaqMMNE4wLzvMxVozkiwrzCMJC6Uo3DbsP+htGyhjzhCPCOOw844swnOzxjVEobj4ez01UMnyrJWFVP",
+	"T00JdtkHDD4JpvJO5qTTyvGLRqEVVmUgzVv+/04h1ohoxC+qmd1luMuO6jo97OvX+la8umpfVcIwXhS5",
+	"gCxcoO66wUuTvQiXV/jcsK2TE9/Ua5uQIaTT5tHLrGbc+mRxx4KrcIFdhgEBO/S3i34wbMufBdczYsux",
+	"me0kHJE3ZP/0/zhW9GcpxeXrQqXTyDf1s/Bh9cuxqgaig+e/+zmpTtsv2241lnhciMsQclHgRu9A5qKw",
+	"e4I0NgySsH013zyjgsydSGJPjYpvjpRhPIbU+VJDdECEXeABThbX1Kw5585A1ewqPA3RCEMOuA1WjZgU",
+	"D2bKC0rfsVBUtJdUxXeTOtrY3+pUpdPLyfUZ06U0nXDjlRdFbW6JGgo2kgY/G9A7PlqicUyQS339iqet",
+	"kk975NEe+e/XVcLeDVybIXr0rlqmnULKSwONXZzyjLqMVFTXYMlIv3yCAugXp2a4sgD9aovmEs2uS32w",
+	"a7ddu+1mid1LizMKxpET5pCoJOMs53oCLlrrNoJym+SwWL0Lj996Dj9XEngVOH7gXoB6il6cYt+fmDbz",
+	"nuwZyOb8K8EIikIt2qGIoW81ytWd5ipFL2HD8ZuW2ijNxkAjbC+Smi+NUKnIPrNReM44QKcl6wKWJR9c",
+	"syNmHsLavbTWMNr9WYiCfSd7scpS1jjd1UdNNwbFpRhXp49kEWHxVD+rAnqRrS6IUbyxRifE4ghhgsH7",
+	"UkH00iN8AF4qWQlvz8FJ/0RdR+I3iFu5dgrQO0TDzDhnYIfJ0aq3Fnaahj1Bw9oSuvUVPtBYV2Lw4Jd4",
+	"7zU8NqGz7yb1XLqI6YzYe+hbHpH2wS/QY7LSy9N2qzQCz4J3wg2TjErp/or2dZSDJvssi850sfT22Frb",
+	"JoWIxcar49cI8vseLiu1vmorFDR7r9R/EzxDKH9lUIDMKGx+TKHkJI9Fo/w2C3n3Zt11SSmEeFeR5DV2",
+	"oqhVF1UVoq7fvNDqsrLyLzMreQOYmoG3Jpm6i1rTwcrZGK2Bwdgfaxu/RrO0Hks7LceJzJpbmMzp5FYG",
+	"u2JykubcmF0NuS2LHIyrS2zmxsIM67pa/wsBEzW6L3jDfCGmBsaWmsMD0q8nllZbN1hyOaI1fg88t9No",
+	"1mNOyul6+c9Wi3S4wONAeEdfxcheqix2Kb7MS2NBsxlQHhrFhbJTnp6BzKrutiRaoBFicJMRB817lUWz",
+	"iQxQnPe6iztyn62U6KrhaywmrU1YtYXXI5/mSOuSkN+/3jp8nc3jUkm0dJD5JhhNpXQxviah1JzWD053",
+	"Oak6zpSFj64lHT5BMlB6fkLSnEumxIDsk6mwJ9Qh9Vmgjbp6vkcxS7lGP5K3yjBTplNUg5Ax7AaXWzrd",
+	"/TLqsVdUHvINuwv2e8wbxBhL9tNgzPD6qMkoF+cQD3jKVcrRbBYxwFYFGbz/XDYTLTRMhJL/Vpod4MYe",
+	"JL8pCf92uiz0c+Py7AMqiwaU+JU2Z+zHbjib0RNzyk08iAooDCR7Z+J+3Vx5mTM0OnKyp9J0DwJFDp8C",
+	"yFCLZaA3L1ba/4Uja+Z5B6vqqDcr7xciS7wdSvR09KhMo7F9s7BkYloR5TRUdBEsDe7wiCzvc1WXBuJx",
+	"7Rdc2Nfn0Twk8ro524hbsjBOE6VS/QkTY0zmo+Z+q29qIhyCIql3PKy5Dr+q9ztGSZQk9onk8xu0Kkm4",
+	"tC9JnY4wUK9m+3gQfJUVfAKV3hKSNrlxD/pO5LrGHKoz+UldrPyslkVuwwJ0G1acYzB2UOf3DXtrbdAp",
+	"a0CLrNoGHrE8qVm0v4OuuHnto9plz8nVYhh27fzLd/sHbOvL6NH+o8c7+4939g+O9/ef0v//vy+j7YR9",
+	"luKSobvTMI61AECLtEo8+jI6+PPBo4Pv9t3/0QdKM85cm9Zz9JEU2p9efJt9r0ptGJ+oL6PtPreAisT4",
+	"yGzZSvB3g8Zvx1uNu9YRLSjPF3mJ/3yvLnqu9pjrdUFl7Qk8DE5f8mxt4U1/4nOc6Lp3/9gm61rCNBTA",
+	"KzsFehCZjzv0hTp2mYutDqPOAH2GzspPb5IpTUj69saa0uJg631Rr7Md39gs/rkgOsU+oAcDd6TI1uxW",
+	"ujJq/VYi1pfk9t1kP9Ne/NRx8T0Y8jOu6nkW6ft/VQO36utYn+xV/cz8clcMHUvNuKrQt+rjSOJDZ2MG",
+	"o/ph9YZlz33QSSBxl/lvmOj2jd2KNo7d/vee1rHDG9CtEb/7AFrD/tG99b66tw45Uv9qHVQHrbku09ob",
+	"MN97Gheiut2bi6LkFTmyxyre0435bqjs0+ujY/b84+EoGVlhc+g+d48qxX20v3uwu18x4kKMno6+3d3f",
+	"/dbxnimBv8ezmZCNzlxUAJ0eTSBy5j7LXJwBW/ggYS5uFAzLhKGFUjgSJnc4HDgCpukadRucxIQIJeUC",
+	"c6tGWFs8sgUuAcdpeQTgo/19J2BJ69kdhWQ4NWUPK7jjb3XhkTUL1tYqJW1QJ4b3B9pfE9wgBDTdEZz6",
+	"4WftZnx11JTQzBMCC5quI9xmkRAz+gVH79mcva/4P1dEizG++Ly9BaikT0WWgXT+gIXxyGhHnQhrAPDa",
+	"Q8H2NGQho5PWipx6SppqCXzChXRhPLQamguFYiG9XZAG12DAkkitgSxDG1DFEcSIYtQOyvv560ggCpC+",
+	"Q3Hmp0GXqw+j4yC9JWmufnEvg7EvVDa/MSJbyV2urq66YF7dLdGvovlk9Hh/v2/cCtC9Fzyr1oSfPF79",
+	"yXtl32DKY+dcvSZKQx3WEzXj3cM14AhVNLJzXiXnLeFxIRqh+oy5rLiEykg6ag3yoYtC/un7159eJ+z7",
+	"538/fP83hPbDe4ZSvaHUNWm5kN0Ez4ZA6ToWdiuectfjkqwyJD6ECgDuSKVKZ5CxKWhImIQLMJZRfpo7",
+	"j+6FxQOZNOJkZ8pYfNEVFrK0HhRQkhs7tcgWI4mRt8nKl+VhDubkYXddEQqtLDDNL8IWmipsVuhGbu5y",
+	"QhQ7KenJTcJbRFbQpm8VRWGSVheP3hP/ZMiJP5SuLbUv97GIUYzHf37Igl4XEusM25KKeROBPxrbDUQ2",
+	"0PYLJbmaCOJcs/qwqtHtcO/2JGux7IMb37llu/bStxvcjFlfe7fd9JgZENnuvp1tn5C9ad1cceVJCa36",
+	"4pJAcCN4UcBlSDTv/sry9WS/ofw9WVX09SqJT6DGYwM9M6zQJp3YcctHPtY08W5Ovttbn5rF/A6zLR2u",
+	"lDpO5QQfwfZAWvkqsiuH5hwsLNLKK/q9xRxaOH4c842wlx7pN4EFB4E/EWkAo4fDRen9b2D7F7B/p9wl",
+	"SIFriXQ3gMS/gW1hENNSDl8tuSlWqgUiW1cpKMrI3rSt4KPbVB02unzuhzxuW0m4AYpyOG0T1ZYz2AZ5",
+	"pO2UWIcj7ZEjPmTl3wYtRiWh537Wa7C7u98ILAtMCoVLA2rsRpoD14YpOwVt1kL/BhLEi3mFsz8kiQcp",
+	"SXRkhzF5tquosgGX680fRKS9hkGtiuvou8a7XTrvxLzT7i56e5uEd3SNjIZEt1QzbqCvqjK19Nwu+iTu",
+	"yjYc6355yzTfwKdtrDaOzuUK8uJCblVV7ncd3bHSvKQr6MNVn2Mbv/Yx2vtaDtKOeihjXcHhr6uXjndH",
+	"LtKbVazWwlUygDf3Y2H/nqhyI7VrUYGKYQo1qc8tVWqBqay8NssV9+aKXgQN5apzGOnGdwZxdLzyCfdl",
+	"k1wIVmM5vtYWBlpUVVSqJYYei3wGjJK8eerqBKCp3DU/di0EfEkAF8naHLMxk0v2A5kxXwkDdQghz3ku",
+	"Mi+COAu6a4pEbzze/ysOqiRUxWkupiKHNpgXFJ2M0zmYsphNvc8xP7ojr9UmzPy+j83tq6N3z/2C/nqt",
+	"m6Lj7V/lib9DJ7y5Kwmr7jDa9dqvhcWGWz7qVvzk+9C6Jl7OHs5zNgbqoGjYFiaeJuz1Pz6+fX74PmHG",
+	"auAzIScJcwhipxizSj9caGFd/FDFysy2YyXUhsCtyDCjWEotYV0cm3Pbu9owBWgWYrxDCQPX/xZTK10Q",
+	"CT3wdRBCn9RGkZdlF6jzy96Wb/5OCPCur2PcuRCK0enovTYV7vlCL73U+GOpLF1FmqeWsvhqZ7ex8xwS",
+	"8v9qDIW8oHr8dKH5pqQsU2k5A+lLk4dyfXWuN2TC+iwJ132FTcVkmmPzJCJgxFQONtDYVLnKBKlZSVm+",
+	"j+jvmbj8Em6fvur98OTgWn6sEz/R/GXYNWEWt6ZbQzJHKjudRwCJGcL8o/5dS/pn8CZFY7ktTc/4de/2",
+	"hSkasXz9c6QauFW6Z/TUqZMv5psuYaHjE9vK4Dxhvs1TQs01t3vX1iwluxkKKWI6Pnx4dt8n6q7dmVmL",
+	"2q9ni7kjG8y9215uz+Zy96J3xEgzlIvunZb5Gc5eRIvjfAi0YiiivQoNJ8GgLnSRz59iBQdqYcOEhVld",
+	"1sdYVfguQxgL9hqLZ/h6ZCnXPqoJ2PfHxx89X6R/I0Wc8xy5jO8x4qnSq7tTfh6a/sbV0hdlfta+BW6D",
+	"rNuz3JMO2gXixvXPFrF9KqUrXdm4LlVNJkI6qwLP88E0CJeOtve+hr8Os37N5bMX74Qca26sLlNbatjh",
+	"ZidVGTCrVE5lNMWMqj5UOVyNGUNMoltsRYhcstfHfFLXlAwJDl4BWSkNvpi/rhZwN6rpQ4yNeKvUGdqi",
+	"WqIdbphF57X7jl3TwgdtPPdJ1DN+GZI7Hj15sqI0f6/Z7zCDWaFw21gGac61y10tCwO6im913Ml9eBoS",
+	"ZJxeAfgzAkgcDp4x5dqZNNRul+XpWmsZoNjWHaQP17Mdi7AHOx2VSBRe7fFM9rScOSYbCJUdgczY4Xjn",
+	"HVW/8tW9Cg3nQpUmn1es0xG8VcS8vW3w4BHaBo2aAZ5kyE1tJOxWFnR2zBlwSYXNI+fjOS6iJV50NjdG",
+	"Z/Ure4djWsLotqLgO/Dduxlx2YF2JjYqM1zRA57Yf3kJ6fHBo9UffNRQBT+/IVHkJoUrCnmn3LcFvjaE",
+	"p3WvvCEBIfVW/BFUuh7p3lNYaYMsNoorXU4yNmT8RfW4dtGKW43+i9fHuEfHi7G34nS5NmEcQztiInTv",
+	"CWVDDT93rWmHEUDEe96xkrQdfM6jR9FqTIMfhWkYgwaZQucyf8YMAFuccK/6wOyyj9xQ9nAK/4YbjIKD",
+	"A4ZZSjyq32Wcah8RMKGm63Jf/zDuRpPHec+Y5wYWawdGeM7vI3jgWjED/3vVjwVXxoMKKFjuPH9w4nFf",
+	"JYsHKR8/YPf6fUqwEc/8enfOHpc8n/82MHj8Js5K1BjpqvyK37xyPRHY0jdUQiJXkg3Zuc7N9D//9d+u",
+	"AG3CZJnnJmEzIammZEI6K3ktZMY1atXnwpeU/7Xk2oocDH3vndFCs4ILfSEMsI/AtVE4tXZ1rBR2jWzU",
+	"PsdvGtXRccNKCy5GiMrRBStZ1QXSAfzMX9aNDXC1qNGaYhXGCxU5nLjGFa4eu8yq4e20GePKTKNvyZar",
+	"OIu1bGmIqrhWR1d323zrzgA/z33ljYTZH0bayKNBc/yNW7hwtbWe7D++MVwQu1iGCbRt0ek31CcwBaB+",
+	"LtY0eqjsLogyfoTzFkE6Wq2PjGtA5CvG1UWp1+FLqTJ2p6qz1RdU+jIHrpu6kbHv6JsHc2F9u5oG3ih9",
+	"SmUz7ikpBt04hRYpcq+Qjk71NowdKrTeX9zmT93uBkm1hro2tNCNXgpIrDy1Jc/dV87SKVzdVlPHKJWG",
+	"T6AaRFnMxqcPihYfp/dLA3plLZE2dd6CI7Ya/19NhPsdHCKwbKoulp4fH1ssUsjWZIaNqqjLuOFnGV6E",
+	"P7RFNN6NOxvQxONDZGY/LlJP2NBWzCiVD71Q+sx1Tw08rkJLVe/cV+X1JZXIKlSjgAnDcjHuCf5+1UNK",
+	"N8+3IjP9oYvezBF4x/VZhweZBk2tyYY28my8mK9rB/zDywEPLuX1oUiBccIUchI8KPdnw6D4J+oq6Su3",
+	"nqqMerArCew/jj68Z64MI9bFmrs4F6caoVXDV3jmM0hCcz7m+6VaricUYqtVOXHBK77PxTeGYYwX1mNk",
+	"W7xplzh8f/T60zHb3d1lbz58evf8mABACD+pi+1d9lbIUHOKurUq24TQNAp/meaEPm0pRDP4ZIYCtFs3",
+	"fpUpGo1iDkJsWIUQtGd2anvVm/nUWXbqMl/PfCiDYU/2DzptzUKxQ3JNuGyCUEnQEUPsSjukJ8vYUUdD",
+	"JpvTVgjlyF1g1Ck3sEvmqG3cOSEzuMS9wm0DZtVuX3Qx7ePymJZVMSzDrt/LHZktcp7ucHd6szrU/17V",
+	"g8cH396tXYZOCrUQsUq5mLxwHLEFEvUQJM5/MGghh2gZnIG0sLllagCS33G8JyWXKXRuGIxj3MkVz9j7",
+	"V8RowmqovF3DukunaU2xZNaYdritqAns/1oV6SUuP0fEI3dvCYoNrLILITN18btQmTphbO6i8rm25LF+",
+	"sv8t26Jw0i+jxhq/jLYry49b7jeGzcCQEahOoHWP6FYvYHX52C6R3bzy1JjhJ7dLf+hM1zTlpFPIym79",
+	"1zWOQ5xLSbCose+4Aq9rMKr37sOP7rs/DNuDN/ITdUFobeM3hvl98IV26cyfwdw8SObmz4Hvr4nWICkg",
+	"q5bgmNu4NIG3PW7wNv/SiVsnugXZT1OQGM2mLupBSKBABNBoBmwSAp4Nm5UU/WYUdfn0QCyMgAM4e5Oq",
+	"dZ8Wyml4Idk/dnwF+Z2a1HZ+gPkue93oxuIgOYPi2sW7F0/OzfPf1hz/qmar38NZx8OUUjNn3WHdrle2",
+	"yx1GGr4W494jZnG/loZj6iA1d3VEfLC/khjnR00/hDWoOkzxOQWiZhscpG5W3w9wnRMUbxi6Tps0+ibe",
+	"0eJmcwzX7+nnI+bbuBrYku7m4+8f/lmtchqRhskGlYM19bVTmm4Aa7h65tXdo7SYCHn9k7z39Qzmh+tV",
+	"rgpH4Q8xbCBrPldnHZbsm67dm8CVREclUrhOVcZAaIVWM2XvO6jOnTNq3ggXrbJSIQnSVamaF5BU9XwS",
+	"1hgkYbhFLmICE/AZdxdIKOdCVQLyIH02jNXNUgIuPiJ0PjQlHgwI5atiV89Hh71b9zsuzPMvnbl+51xe",
+	"FfOu971Kb3RmP+liItulI9Zh6FVD4zs9ZW1qpd65t06rvm/wvagXfu6HYdx5uAbvKnxz+Td+jtBssNOE",
+	"6BLSkmQj5wwLTr9rBHjTQHvczGV6z/fR37FsIQ/N+zXIDLSpSwcl+Ce1SzdMWMbHFkJIS4Z9vtlHlefM",
+	"rWfH1ZlwJbjJNYhKjy8ygaO72GrvgMSKFK4j2eLHhLFd9hbvLf+uN4UUgq4274V111WpUfYk2d2pVn4p",
+	"PCMXqdO/LnAZBABkzyh7MIwLl4XQ4JdGW3LiH+1am0ftGeXpTNjn+OqP3p34MNjLo5sL2q4Wt4zHuH7P",
+	"kD14TnMtPxlW4Ahn32fgI11NNIG1ycEnT+I9X5EvEIa7uSfrqe6rYksDgD9uzDVIP1x9szK3osih0Rlt",
+	"8Q70bNeWWjYZ7EYnJESG3/Pt+NksRMR8Y1jmGw4r7X3kddZFwoy7msICgqHzAvKcqbGPuZlbaFyyPvKd",
+	"yoTwzGDraHMBOhZi4+ODfFRNmCR2Sb32z+rT/bDuqv1bNgASRAEJm1v+/mAKi0whkDbG1s/KdFrdjq5W",
+	"mEm5THz6kbFJlX6vS0lxz2JdjbLOch8Y5fqp/uCObIJ+vmGxofcRdU+tPls8pMLqA8oniu9/PcCAviru",
+	"3btprEK/3Mp+b8AKlvZiIUh9/PAD3+y5sTDbmQLP7XRJOW3ejoL9xjB1IV3FV2FdCMEMrBapcZe0YVvF",
+	"5MRYbk9aL4UfQxQpIalOLk6YA2i30CoFY7yi7H8MM+A3daTvdkMSMq7etkAVQiJGcvEbZMxMeQHoAdwg",
+	"ltZnvzkMrayRd0SQfk8v3+axaM7zUI7EBrdjh22e12HWDt/OrkCGC0cDnroe/FHaM0B+ZbP31f91uLy6",
+	"zadSGjwdFvRMSG7hJGBiS2l8kFKcYvUruRtIDvgg8znVq9nuHCY6Fz8cvn3Lfvz8+tP/7RybFQ5x+lgY",
+	"H6UKWdDFG2fG50nEzsRxWEXjZDg0rAo49+GYOFWQYJy0gzleOWBhVHzmkcpSJSVFNvaFnFcouoOqOr+H",
+	"Q1btDOPVWQvIpCgmQr+whgVE3quLsmMBY47veVpiwvOGVaexIo42fNXJvAmHp2NMe1/pfzF5v5T9HQFe",
+	"zJknQW9ZLaXF08ZJFfVBOvX+1GUGXGxP3T+ibj8hLF25ac6N2dWQ27LIwSTNlJhwt3JtTcK+nxeg36rJ",
+	"WzVh5oxstcbdpeOcT7AeJi8KrS6dzsswvRcueWqr2lhUrAorI5d5TmpINIgGl0ZZJJ/UhRmW/WaCVLlG",
+	"DXUK7KPYPYfMDBEmU+vbnLugPGFCvpGQxuLVosZUaqSPe7i3V0ES+5JQdV2ec3Pywid1QTtxK7LCdfgR",
+	"QeUNO7gTCXKhyOZxVu3FQ4uZIOBvgYUgOpapYUtOVae0kNNHvMRLYjcSFRcyhCTQjH2HYJPj+NIdM6uY",
+	"Udr63jaA3qFd9v7z27fG/+6EjxxdT8Zvdy8YStu1sLwA1Gcpfi3BE1LCYHey61ipFkiNGJKTOG2d6sAb",
+	"IkhUHnBAxh3IBCMZHgs+AcMkCHLhmzNRMOkKqQInvj7zJQVdvIi2gflQoobzkQli+6UDTFgD+dhx0RgG",
+	"zmC+LgJiw9Di4qxpxE06SkYgyxmeMPcvRGLDeteP3w8FJ/yW2hAefFwmRyRd2pfuZ9+6JlRrJiz2sl/6",
+	"ZBP2uySB+mC/mUF9sL8/JId6MBMWFmZmdZkSpAPX9gNZ6IxfHrrvHtUJ11xrPo/x6R9LkZ6xMX1N8Ujc",
+	"Za7SBwl7/v4V+WwngHTpi2VXoU7hM9IfXF8/3AHPYgnf/QQ49hDf5k1VcbXfaQmrdtIfchBoXG8O7xh2",
+	"CZZtIUvcRvwLef8VOW77cruP9PRlVYRHD6p+713bqBGuNWJqxHjc30eFbBeAuf9Ujy/kIaISWQ+VsBkn",
+	"HcOdA+XMCRj/HIocOjOe02b8O/k8vJjD2GKhacxnyraT1jONrc7YFs8yyJwGoyQ7Vda3BkDgga7wMNOW",
+	"Ly+/vcteKOvANmzG51XgNfHKGvhodRgxHuM+31ZJGDEe31cyDU39+y4geI0ItJdqVnANzF4ob3uqXM8h",
+	"ikmrCxRk9IoUyMVYq2XyfCfE6bYKRA6KNbo3V8qRC2BDcUS6+LAbMESJDSw8PXFyS3wklQOCtdDrRCtT",
+	"pr68pI/0U9pFLmhNbiLfXkpJ6r2clWkIKUcu6lI9eB7C+1KVQY8rorW9KO8+hKjY22/vew+WVIdft7sY",
+	"1W2mkD00itUw1mAGBr+tO2eyTBywjbZBpzAVMmsdggaenLyQMGpLWqdkiBkwjbd0UtUB5sFZgZzZr/Mb",
+	"4sVk8zNJS5QoQO+4f7N0CumZKWdml70Mf4YyPa7NG0oMOBDpxq7pEWHOT1PnETIeskkaSyBzAA+uQh+D",
+	"dA5ajDHN4xTGSoNX9c2U0yi45tgR/uR27K7CXWmyhphxB0H0fsrfaR/xf+3gexd8G+Nm3mhFRxGPEN1L",
+	"IbAcLYtajJdHGhmwmN9u9rhYKgUdHvkXb1UKqmYhEf72CsthaEhotPf8kAUksC2pmIFUQzByN1vlhLfc",
+	"ZdHfWqGDq9tqkFBPs5ZCMsBjevdda47Qg9zaCJgVGJxSiBNURrGuqKnbKtLe9GwNDUx16GNGd6xId34w",
+	"SkalzkdPR3u8EHvnB2Q782N1v3jVLLUi+QR8Opi/npsnKuIarfe4XltsmPAwNsYhyp7nIgNdJXu4EWMD",
+	"cbHjXjKjq1+u/v8A",
 }
 
 // decodeSpec returns the embedded OpenAPI spec as raw JSON bytes,
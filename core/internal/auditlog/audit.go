@@ -0,0 +1,78 @@
+package auditlog
+
+import (
+	"context"
+
+	"data-voyager/core/internal/export"
+	"data-voyager/core/internal/rowedit"
+	"data-voyager/core/internal/user"
+)
+
+// The audit repositories below store a record as before and then publish
+// it, so every audit trail is forwarded without its service knowing.
+
+// ExportAudit returns repo, forwarding each record it stores. It returns
+// repo itself when f is nil.
+func (f *Forwarder) ExportAudit(repo export.AuditRepository) export.AuditRepository {
+	if f == nil {
+		return repo
+	}
+	return exportAudit{repo, f}
+}
+
+type exportAudit struct {
+	export.AuditRepository
+	f *Forwarder
+}
+
+func (a exportAudit) Record(ctx context.Context, r *export.AuditRecord) error {
+	if err := a.AuditRepository.Record(ctx, r); err != nil {
+		return err
+	}
+	a.f.Publish(&Event{Type: TypeExport, At: r.At, Actor: r.Actor, DatasourceID: r.DatasourceID, Record: r})
+	return nil
+}
+
+// RowEditAudit returns repo, forwarding each record it stores. It returns
+// repo itself when f is nil.
+func (f *Forwarder) RowEditAudit(repo rowedit.AuditRepository) rowedit.AuditRepository {
+	if f == nil {
+		return repo
+	}
+	return rowEditAudit{repo, f}
+}
+
+type rowEditAudit struct {
+	rowedit.AuditRepository
+	f *Forwarder
+}
+
+func (a rowEditAudit) Record(ctx context.Context, r *rowedit.AuditRecord) error {
+	if err := a.AuditRepository.Record(ctx, r); err != nil {
+		return err
+	}
+	a.f.Publish(&Event{Type: TypeRowEdit, At: r.At, Actor: r.Actor, DatasourceID: r.DatasourceID, Record: r})
+	return nil
+}
+
+// ImpersonationAudit returns repo, forwarding each record it stores. It
+// returns repo itself when f is nil.
+func (f *Forwarder) ImpersonationAudit(repo user.AuditRepository) user.AuditRepository {
+	if f == nil {
+		return repo
+	}
+	return impersonationAudit{repo, f}
+}
+
+type impersonationAudit struct {
+	user.AuditRepository
+	f *Forwarder
+}
+
+func (a impersonationAudit) Record(ctx context.Context, e *user.Impersonation) error {
+	if err := a.AuditRepository.Record(ctx, e); err != nil {
+		return err
+	}
+	a.f.Publish(&Event{Type: TypeImpersonation, At: e.At, Actor: e.Actor, Record: e})
+	return nil
+}
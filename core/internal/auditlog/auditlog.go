@@ -0,0 +1,276 @@
+// Package auditlog forwards query history and audit events, one JSON object
+// per line, to the systems security teams keep logs in: Kafka, Grafana Loki
+// or an S3 bucket. It copies what is recorded elsewhere — the audit tables
+// in the metadata store stay the record — so forwarding never holds up a
+// request and a sink that is down loses events rather than blocking.
+package auditlog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/identity"
+	"data-voyager/sdk"
+)
+
+// Event types.
+const (
+	TypeQuery         = "query"
+	TypeExport        = "export"
+	TypeRowEdit       = "row_edit"
+	TypeImpersonation = "impersonation"
+)
+
+const (
+	queueSize = 8192
+	// attempts is how many times a batch is offered to a sink.
+	attempts = 3
+)
+
+// Event is one line of the audit log.
+type Event struct {
+	Type  string    `json:"type"`
+	At    time.Time `json:"at"`
+	Actor string    `json:"actor,omitempty"`
+	// DatasourceID, DatasourceName and Query describe a query event.
+	DatasourceID   string `json:"datasource_id,omitempty"`
+	DatasourceName string `json:"datasource_name,omitempty"`
+	DatasourceType string `json:"datasource_type,omitempty"`
+	Query          string `json:"query,omitempty"`
+	// BytesRead is what the query scanned, when the driver reports it;
+	// Cost and Currency price it under the datasource's cost model.
+	BytesRead int64    `json:"bytes_read,omitempty"`
+	Cost      *float64 `json:"cost,omitempty"`
+	Currency  string   `json:"currency,omitempty"`
+	// Record is the audit record of an export, row edit or impersonation
+	// event, as the admin audit endpoints return it.
+	Record any `json:"record,omitempty"`
+}
+
+// Sink delivers batches of events to one destination.
+type Sink interface {
+	Write(ctx context.Context, events []*Event) error
+	Close() error
+}
+
+// sink is a configured Sink and the event types it receives.
+type sink struct {
+	name  string
+	types []string
+	Sink
+}
+
+func (s *sink) wants(typ string) bool {
+	return len(s.types) == 0 || slices.Contains(s.types, typ)
+}
+
+// Forwarder queues events and writes them to its sinks in batches. A nil
+// *Forwarder forwards nothing, so callers need not check whether any sink
+// is configured.
+type Forwarder struct {
+	sinks     []*sink
+	batchSize int
+	interval  time.Duration
+	events    chan *Event
+	dropped   atomic.Int64
+	now       func() time.Time
+
+	running atomic.Bool
+	done    chan struct{}
+	close   sync.Once
+}
+
+var _ connection.UsageRecorder = (*Forwarder)(nil)
+
+// New creates a Forwarder for the configured sinks, or returns nil when
+// there are none. Nothing is written until Run is started.
+func New(cfg config.AuditLogConfig) (*Forwarder, error) {
+	if len(cfg.Sinks) == 0 {
+		return nil, nil
+	}
+	var sinks []*sink
+	for _, sc := range cfg.Sinks {
+		var s Sink
+		var err error
+		switch sc.Type {
+		case config.AuditLogKafka:
+			s, err = NewKafka(sc.Kafka)
+		case config.AuditLogLoki:
+			s = NewLoki(sc.Loki)
+		case config.AuditLogS3:
+			s = NewS3(sc.S3)
+		default:
+			err = fmt.Errorf("unknown sink type %q", sc.Type)
+		}
+		if err != nil {
+			for _, done := range sinks {
+				_ = done.Close()
+			}
+			return nil, fmt.Errorf("audit log sink %s: %w", sc.Name, err)
+		}
+		sinks = append(sinks, &sink{name: sc.Name, types: sc.Events, Sink: s})
+	}
+	return newForwarder(sinks, cfg.BatchSize, time.Duration(cfg.FlushInterval)*time.Second), nil
+}
+
+func newForwarder(sinks []*sink, batchSize int, interval time.Duration) *Forwarder {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	return &Forwarder{
+		sinks:     sinks,
+		batchSize: batchSize,
+		interval:  interval,
+		events:    make(chan *Event, queueSize),
+		now:       time.Now,
+		done:      make(chan struct{}),
+	}
+}
+
+// Publish queues e. When the queue is full e is dropped and counted.
+func (f *Forwarder) Publish(e *Event) {
+	if f == nil {
+		return
+	}
+	if e.At.IsZero() {
+		e.At = f.now()
+	}
+	e.At = e.At.UTC()
+	select {
+	case f.events <- e:
+	default:
+		f.dropped.Add(1)
+	}
+}
+
+// RecordQuery implements connection.UsageRecorder, logging every query a
+// datasource ran.
+func (f *Forwarder) RecordQuery(ctx context.Context, conn *connection.Connection, query string, stats sdk.QueryStats) {
+	if f == nil {
+		return
+	}
+	e := &Event{
+		Type:           TypeQuery,
+		DatasourceID:   conn.ID,
+		DatasourceName: conn.Name,
+		DatasourceType: string(conn.Type),
+		Query:          query,
+		BytesRead:      stats.BytesRead,
+	}
+	if m := conn.CostModel; m != nil {
+		cost := m.Cost(stats.BytesRead)
+		e.Cost, e.Currency = &cost, m.CurrencyOrDefault()
+	}
+	if id := identity.FromContext(ctx); id != nil {
+		e.Actor = id.Username
+	}
+	f.Publish(e)
+}
+
+// Run writes queued events until ctx is cancelled, then flushes what is
+// left.
+func (f *Forwarder) Run(ctx context.Context) {
+	if f == nil || !f.running.CompareAndSwap(false, true) {
+		return
+	}
+	defer close(f.done)
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+	batch := make([]*Event, 0, f.batchSize)
+	flush := func(ctx context.Context) {
+		if n := f.dropped.Swap(0); n > 0 {
+			slog.Warn("audit log events dropped: queue full", "count", n)
+		}
+		if len(batch) == 0 {
+			return
+		}
+		f.write(ctx, batch)
+		batch = make([]*Event, 0, f.batchSize)
+	}
+	for {
+		select {
+		case e := <-f.events:
+			batch = append(batch, e)
+			if len(batch) >= f.batchSize {
+				flush(ctx)
+			}
+		case <-ticker.C:
+			flush(ctx)
+		case <-ctx.Done():
+			// Run is the only receiver, so the queue cannot shrink under us.
+			for len(f.events) > 0 {
+				batch = append(batch, <-f.events)
+			}
+			done, cancel := context.WithTimeout(context.Background(), f.interval)
+			flush(done)
+			cancel()
+			return
+		}
+	}
+}
+
+// write hands each sink the events it receives, retrying a failed write
+// with a short backoff before giving the batch up.
+func (f *Forwarder) write(ctx context.Context, batch []*Event) {
+	for _, s := range f.sinks {
+		events := batch
+		if len(s.types) > 0 {
+			events = slices.DeleteFunc(slices.Clone(batch), func(e *Event) bool { return !s.wants(e.Type) })
+		}
+		if len(events) == 0 {
+			continue
+		}
+		var err error
+		for attempt := range attempts {
+			if attempt > 0 {
+				select {
+				case <-ctx.Done():
+				case <-time.After(time.Duration(attempt) * time.Second):
+				}
+			}
+			if err = s.Write(ctx, events); err == nil || ctx.Err() != nil {
+				break
+			}
+		}
+		if err != nil {
+			slog.Warn("failed to forward audit log events", "sink", s.name, "count", len(events), "err", err)
+		}
+	}
+}
+
+// Close waits for a running Run to flush, then closes the sinks.
+func (f *Forwarder) Close() error {
+	if f == nil {
+		return nil
+	}
+	var errs []error
+	f.close.Do(func() {
+		if f.running.Load() {
+			select {
+			case <-f.done:
+			case <-time.After(2 * f.interval):
+			}
+		}
+		for _, s := range f.sinks {
+			errs = append(errs, s.Close())
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// encode is the JSON line of e, without its newline.
+func encode(e *Event) ([]byte, error) {
+	return json.Marshal(e)
+}
@@ -0,0 +1,199 @@
+package auditlog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/export"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/objstore"
+	"data-voyager/sdk"
+)
+
+type memSink struct {
+	mu      sync.Mutex
+	batches [][]*Event
+	fail    int
+	closed  bool
+}
+
+func (s *memSink) Write(_ context.Context, events []*Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail > 0 {
+		s.fail--
+		return errors.New("unavailable")
+	}
+	s.batches = append(s.batches, events)
+	return nil
+}
+
+func (s *memSink) Close() error { s.closed = true; return nil }
+
+func (s *memSink) types() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []string
+	for _, b := range s.batches {
+		for _, e := range b {
+			out = append(out, e.Type)
+		}
+	}
+	return out
+}
+
+func TestForwarder(t *testing.T) {
+	all, exports := &memSink{}, &memSink{fail: 1}
+	f := newForwarder([]*sink{
+		{name: "all", Sink: all},
+		{name: "exports", types: []string{TypeExport}, Sink: exports},
+	}, 2, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	go f.Run(ctx)
+
+	conn := &connection.Connection{ID: "ds1", Name: "warehouse", Type: "postgresql", CostModel: &connection.CostModel{PerTiB: 4}}
+	f.RecordQuery(identity.With(context.Background(), &identity.Identity{Username: "ann"}), conn, "SELECT 1",
+		sdk.QueryStats{BytesRead: 1 << 39})
+	f.Publish(&Event{Type: TypeExport, Actor: "bob"})
+	// The first batch is full; the third event waits for the final flush.
+	f.Publish(&Event{Type: TypeRowEdit})
+
+	require.Eventually(t, func() bool { return len(all.types()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, f.Close())
+
+	assert.Equal(t, []string{TypeQuery, TypeExport, TypeRowEdit}, all.types())
+	// The exports sink failed once and got the batch on its retry.
+	assert.Equal(t, []string{TypeExport}, exports.types())
+	assert.True(t, all.closed)
+	assert.True(t, exports.closed)
+
+	q := all.batches[0][0]
+	assert.Equal(t, "ann", q.Actor)
+	assert.Equal(t, "ds1", q.DatasourceID)
+	assert.Equal(t, "postgresql", q.DatasourceType)
+	assert.Equal(t, "SELECT 1", q.Query)
+	assert.EqualValues(t, 1<<39, q.BytesRead)
+	require.NotNil(t, q.Cost)
+	assert.InDelta(t, 2, *q.Cost, 1e-9)
+	assert.Equal(t, "USD", q.Currency)
+	assert.Equal(t, time.UTC, q.At.Location())
+}
+
+func TestForwarder_Nil(t *testing.T) {
+	f, err := New(config.AuditLogConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, f)
+
+	f.Publish(&Event{Type: TypeQuery})
+	f.RecordQuery(context.Background(), &connection.Connection{}, "SELECT 1", sdk.QueryStats{})
+	f.Run(context.Background())
+	assert.NoError(t, f.Close())
+
+	repo := &memExportAudit{}
+	assert.Same(t, repo, f.ExportAudit(repo))
+}
+
+type memExportAudit struct {
+	export.AuditRepository
+	records []*export.AuditRecord
+}
+
+func (r *memExportAudit) Record(_ context.Context, rec *export.AuditRecord) error {
+	r.records = append(r.records, rec)
+	return nil
+}
+
+func TestExportAudit(t *testing.T) {
+	f := newForwarder(nil, 0, 0)
+	repo := &memExportAudit{}
+	rec := &export.AuditRecord{DatasourceID: "ds1", Table: "orders", Actor: "ann", At: time.Now()}
+	require.NoError(t, f.ExportAudit(repo).Record(context.Background(), rec))
+
+	assert.Len(t, repo.records, 1)
+	require.Len(t, f.events, 1)
+	e := <-f.events
+	assert.Equal(t, TypeExport, e.Type)
+	assert.Equal(t, "ann", e.Actor)
+	assert.Same(t, rec, e.Record)
+}
+
+func TestLoki(t *testing.T) {
+	var got struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+	var tenant string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/loki/api/v1/push", r.URL.Path)
+		tenant = r.Header.Get("X-Scope-OrgID")
+		body, _ := io.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(body, &got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := NewLoki(config.AuditLogLokiSink{URL: srv.URL + "/", Labels: map[string]string{"app": "dv"}, TenantID: "sec"})
+	require.NoError(t, l.Write(context.Background(), []*Event{
+		{Type: TypeQuery, At: at, Query: "SELECT 1"},
+		{Type: TypeExport, At: at},
+		{Type: TypeQuery, At: at.Add(time.Second), Query: "SELECT 2"},
+	}))
+
+	assert.Equal(t, "sec", tenant)
+	require.Len(t, got.Streams, 2)
+	assert.Equal(t, map[string]string{"app": "dv", "type": TypeQuery}, got.Streams[0].Stream)
+	require.Len(t, got.Streams[0].Values, 2)
+	assert.Equal(t, "1772366400000000000", got.Streams[0].Values[0][0])
+	assert.JSONEq(t, `{"type":"query","at":"2026-03-01T12:00:00Z","query":"SELECT 1"}`, got.Streams[0].Values[0][1])
+	assert.Equal(t, TypeExport, got.Streams[1].Stream["type"])
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "entry too far behind", http.StatusBadRequest)
+	}))
+	defer failing.Close()
+	err := NewLoki(config.AuditLogLokiSink{URL: failing.URL}).Write(context.Background(), []*Event{{Type: TypeQuery, At: at}})
+	assert.ErrorContains(t, err, "entry too far behind")
+}
+
+func TestS3(t *testing.T) {
+	root := t.TempDir()
+	s := &S3{store: objstore.NewDir(root)}
+	at := time.Date(2026, 3, 1, 12, 30, 5, 0, time.UTC)
+	require.NoError(t, s.Write(context.Background(), []*Event{
+		{Type: TypeQuery, At: at, Query: "SELECT 1"},
+		{Type: TypeRowEdit, At: at.Add(time.Second)},
+	}))
+
+	files, err := filepath.Glob(filepath.Join(root, "audit", "2026", "03", "01", "123005-*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	fh, err := os.Open(files[0])
+	require.NoError(t, err)
+	defer func() { _ = fh.Close() }()
+	var types []string
+	for sc := bufio.NewScanner(fh); sc.Scan(); {
+		var e Event
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &e))
+		types = append(types, e.Type)
+	}
+	assert.Equal(t, []string{TypeQuery, TypeRowEdit}, types)
+}
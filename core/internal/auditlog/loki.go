@@ -0,0 +1,94 @@
+package auditlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"data-voyager/core/internal/config"
+)
+
+// Loki pushes events to Grafana Loki's push API.
+type Loki struct {
+	cfg    config.AuditLogLokiSink
+	client *http.Client
+}
+
+// NewLoki returns a Sink pushing to the configured Loki.
+func NewLoki(cfg config.AuditLogLokiSink) *Loki {
+	timeout := time.Duration(cfg.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	cfg.URL = strings.TrimRight(cfg.URL, "/")
+	return &Loki{cfg: cfg, client: &http.Client{Timeout: timeout}}
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// Write pushes events in one request, as one stream per event type.
+func (l *Loki) Write(ctx context.Context, events []*Event) error {
+	streams := map[string]*lokiStream{}
+	var order []string
+	for _, e := range events {
+		line, err := encode(e)
+		if err != nil {
+			return err
+		}
+		s, ok := streams[e.Type]
+		if !ok {
+			labels := maps.Clone(l.cfg.Labels)
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels["type"] = e.Type
+			s = &lokiStream{Stream: labels}
+			streams[e.Type] = s
+			order = append(order, e.Type)
+		}
+		s.Values = append(s.Values, [2]string{strconv.FormatInt(e.At.UnixNano(), 10), string(line)})
+	}
+	push := struct {
+		Streams []*lokiStream `json:"streams"`
+	}{}
+	for _, typ := range order {
+		push.Streams = append(push.Streams, streams[typ])
+	}
+	body, err := json.Marshal(push)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.cfg.URL+"/loki/api/v1/push", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if l.cfg.TenantID != "" {
+		req.Header.Set("X-Scope-OrgID", l.cfg.TenantID)
+	}
+	if l.cfg.Username != "" {
+		req.SetBasicAuth(l.cfg.Username, l.cfg.Password)
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("push to loki: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("push to loki: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+func (l *Loki) Close() error { return nil }
@@ -0,0 +1,41 @@
+package auditlog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/objstore"
+)
+
+// S3 writes each batch of events as one JSON Lines object.
+type S3 struct {
+	store objstore.Store
+}
+
+// NewS3 returns a Sink writing to the configured bucket.
+func NewS3(cfg config.S3Config) *S3 {
+	return &S3{store: objstore.NewS3(cfg)}
+}
+
+// Write stores events as audit/<yyyy>/<mm>/<dd>/<hhmmss>-<id>.jsonl, dated
+// by the first of them, so objects list in time order and a day can be
+// fetched by prefix.
+func (s *S3) Write(ctx context.Context, events []*Event) error {
+	var body bytes.Buffer
+	for _, e := range events {
+		line, err := encode(e)
+		if err != nil {
+			return err
+		}
+		body.Write(line)
+		body.WriteByte('\n')
+	}
+	key := fmt.Sprintf("audit/%s-%s.jsonl", events[0].At.Format("2006/01/02/150405"), uuid.NewString())
+	return s.store.Put(ctx, key, body.Bytes(), "application/x-ndjson")
+}
+
+func (s *S3) Close() error { return nil }
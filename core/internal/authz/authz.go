@@ -0,0 +1,243 @@
+// Package authz evaluates the access policies configured under
+// security.policies. Roles say what a user may do anywhere; policies narrow
+// that per endpoint, user and datasource, e.g. "contractors may only query
+// datasources tagged public".
+//
+// Policies are checked in order and the first that matches a request
+// decides it. An allow policy only ends the evaluation: it never grants what
+// the caller's role does not, because role checks still run afterwards.
+package authz
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/identity"
+)
+
+// Effects.
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// Datasource is what policies can see of a datasource.
+type Datasource struct {
+	ID   string
+	Type string
+	Tags []string
+}
+
+// DatasourceLookup returns a datasource by ID, or nil when there is none.
+type DatasourceLookup func(ctx context.Context, id string) (*Datasource, error)
+
+// GroupLookup returns the names of the groups a user belongs to.
+type GroupLookup func(ctx context.Context, userID string) ([]string, error)
+
+// Request is what a policy is evaluated against.
+type Request struct {
+	Identity *identity.Identity
+	Method   string
+	// Route is the matched route template, e.g.
+	// "/api/v1/datasources/:uid/query".
+	Route string
+	// DatasourceID is the datasource the request addresses, if any.
+	DatasourceID string
+	// Action overrides the action derived from Method and Route.
+	Action string
+}
+
+// Decision is the outcome of evaluating a request.
+type Decision struct {
+	Allowed bool
+	// Policy names the policy that decided; empty when none matched.
+	Policy string
+}
+
+// Engine evaluates policies.
+type Engine struct {
+	policies    []config.PolicyConfig
+	datasources DatasourceLookup
+	groups      GroupLookup
+}
+
+// New compiles policies. datasources and groups may be nil, in which case
+// policies with datasource or group conditions never match.
+func New(policies []config.PolicyConfig, datasources DatasourceLookup, groups GroupLookup) (*Engine, error) {
+	for _, p := range policies {
+		if p.Effect != EffectAllow && p.Effect != EffectDeny {
+			return nil, fmt.Errorf("policy %s: effect must be allow or deny", p.Name)
+		}
+		for _, role := range p.Roles {
+			if !identity.ValidRole(role) {
+				return nil, fmt.Errorf("policy %s: unknown role %q", p.Name, role)
+			}
+		}
+		for _, patterns := range [][]string{p.Actions, p.Endpoints, p.Users} {
+			for _, pattern := range patterns {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return nil, fmt.Errorf("policy %s: bad pattern %q", p.Name, pattern)
+				}
+			}
+		}
+	}
+	return &Engine{policies: policies, datasources: datasources, groups: groups}, nil
+}
+
+// Empty reports whether there are no policies to evaluate.
+func (e *Engine) Empty() bool { return e == nil || len(e.policies) == 0 }
+
+// Decide evaluates req against the policies in order. Lookups are made at
+// most once per call and only when a policy needs them.
+func (e *Engine) Decide(ctx context.Context, req Request) (Decision, error) {
+	action := req.Action
+	if action == "" {
+		action = Action(req.Method, req.Route)
+	}
+	ev := &evaluation{engine: e, ctx: ctx, req: req, action: action}
+	for _, p := range e.policies {
+		ok, err := ev.matches(p)
+		if err != nil {
+			return Decision{}, fmt.Errorf("policy %s: %w", p.Name, err)
+		}
+		if ok {
+			return Decision{Allowed: p.Effect == EffectAllow, Policy: p.Name}, nil
+		}
+	}
+	return Decision{Allowed: true}, nil
+}
+
+// Action names what a request does as "<resource>:<verb>": the resource is
+// the first path segment after the API prefix and the verb is query for
+// routes that run queries, read for GET and write otherwise. For example
+// "POST /api/v1/datasources/:uid/query" is "datasources:query" and
+// "DELETE /api/v1/dashboards/:id" is "dashboards:write".
+func Action(method, route string) string {
+	rest := route
+	if _, after, ok := strings.Cut(route, "/api/v1/"); ok {
+		rest = after
+	}
+	rest = strings.TrimPrefix(rest, "/")
+	resource, _, _ := strings.Cut(rest, "/")
+	verb := "write"
+	switch {
+	case runsQuery(rest):
+		verb = "query"
+	case method == http.MethodGet || method == http.MethodHead:
+		verb = "read"
+	}
+	return resource + ":" + verb
+}
+
+// runsQuery reports whether a route, without the API prefix, runs queries
+// against a datasource.
+func runsQuery(route string) bool {
+	segments := strings.Split(route, "/")
+	for i, s := range segments {
+		if s == "query" {
+			return true
+		}
+		if s == "tables" && i+2 < len(segments) && (segments[i+2] == "rows" || segments[i+2] == "count") {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluation holds what one Decide call has looked up.
+type evaluation struct {
+	engine *Engine
+	ctx    context.Context
+	req    Request
+	action string
+
+	datasource       *Datasource
+	datasourceLoaded bool
+	groups           []string
+	groupsLoaded     bool
+}
+
+func (ev *evaluation) matches(p config.PolicyConfig) (bool, error) {
+	id := ev.req.Identity
+	switch {
+	case len(p.Actions) > 0 && !anyGlob(p.Actions, ev.action):
+		return false, nil
+	case len(p.Endpoints) > 0 && !anyGlob(p.Endpoints, ev.req.Method+" "+ev.req.Route):
+		return false, nil
+	case len(p.Users) > 0 && (id == nil || !anyGlob(p.Users, id.Username)):
+		return false, nil
+	case len(p.Roles) > 0 && (id == nil || !slices.Contains(p.Roles, id.Role)):
+		return false, nil
+	}
+	if len(p.Groups) > 0 {
+		groups, err := ev.userGroups()
+		if err != nil {
+			return false, err
+		}
+		if !slices.ContainsFunc(p.Groups, func(g string) bool {
+			return slices.ContainsFunc(groups, func(name string) bool { return strings.EqualFold(name, g) })
+		}) {
+			return false, nil
+		}
+	}
+	if len(p.Datasources) == 0 && len(p.DatasourceTags) == 0 && len(p.DatasourceTypes) == 0 {
+		return true, nil
+	}
+	ds, err := ev.lookupDatasource()
+	if err != nil || ds == nil {
+		return false, err
+	}
+	switch {
+	case len(p.Datasources) > 0 && !slices.Contains(p.Datasources, ds.ID):
+		return false, nil
+	case len(p.DatasourceTypes) > 0 && !slices.Contains(p.DatasourceTypes, ds.Type):
+		return false, nil
+	}
+	for _, tag := range p.DatasourceTags {
+		if !slices.Contains(ds.Tags, tag) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (ev *evaluation) userGroups() ([]string, error) {
+	if !ev.groupsLoaded {
+		ev.groupsLoaded = true
+		id := ev.req.Identity
+		if id != nil && id.UserID != "" && ev.engine.groups != nil {
+			groups, err := ev.engine.groups(ev.ctx, id.UserID)
+			if err != nil {
+				return nil, fmt.Errorf("look up groups: %w", err)
+			}
+			ev.groups = groups
+		}
+	}
+	return ev.groups, nil
+}
+
+func (ev *evaluation) lookupDatasource() (*Datasource, error) {
+	if !ev.datasourceLoaded {
+		ev.datasourceLoaded = true
+		if ev.req.DatasourceID != "" && ev.engine.datasources != nil {
+			ds, err := ev.engine.datasources(ev.ctx, ev.req.DatasourceID)
+			if err != nil {
+				return nil, fmt.Errorf("look up datasource: %w", err)
+			}
+			ev.datasource = ds
+		}
+	}
+	return ev.datasource, nil
+}
+
+func anyGlob(patterns []string, s string) bool {
+	return slices.ContainsFunc(patterns, func(p string) bool {
+		ok, _ := path.Match(p, s)
+		return ok
+	})
+}
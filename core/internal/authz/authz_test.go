@@ -0,0 +1,198 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/identity"
+)
+
+var datasources = map[string]*Datasource{
+	"pub":  {ID: "pub", Type: "postgresql", Tags: []string{"public", "sales"}},
+	"priv": {ID: "priv", Type: "postgresql", Tags: []string{"finance"}},
+	"logs": {ID: "logs", Type: "clickhouse"},
+}
+
+func lookup(_ context.Context, id string) (*Datasource, error) {
+	return datasources[id], nil
+}
+
+func groups(_ context.Context, userID string) ([]string, error) {
+	if userID == "u-contractor" {
+		return []string{"Contractors"}, nil
+	}
+	return nil, nil
+}
+
+// contractorPolicies let contractors query public datasources and nothing
+// else.
+var contractorPolicies = []config.PolicyConfig{
+	{Name: "contractors-public", Effect: EffectAllow, Actions: []string{"datasources:query"}, Groups: []string{"contractors"}, DatasourceTags: []string{"public"}},
+	{Name: "contractors-no-query", Effect: EffectDeny, Actions: []string{"*:query"}, Groups: []string{"contractors"}},
+}
+
+var (
+	contractor = &identity.Identity{UserID: "u-contractor", Username: "carol", Role: identity.RoleViewer}
+	employee   = &identity.Identity{UserID: "u-employee", Username: "erin", Role: identity.RoleViewer}
+)
+
+func TestAction(t *testing.T) {
+	for _, tc := range []struct{ method, route, want string }{
+		{"POST", "/api/v1/datasources/:uid/query", "datasources:query"},
+		{"POST", "/api/v1/datasources/:uid/query/async", "datasources:query"},
+		{"GET", "/api/v1/datasources/:uid/tables/:table/rows", "datasources:query"},
+		{"GET", "/api/v1/datasources/:uid/schema", "datasources:read"},
+		{"PUT", "/api/v1/datasources/:uid", "datasources:write"},
+		{"DELETE", "/api/v1/dashboards/:id", "dashboards:write"},
+		{"GET", "/api/v1/admin/users", "admin:read"},
+		{"GET", "/api/v1/query-results/:id", "query-results:read"},
+	} {
+		assert.Equal(t, tc.want, Action(tc.method, tc.route), "%s %s", tc.method, tc.route)
+	}
+}
+
+func TestDecide(t *testing.T) {
+	e, err := New(contractorPolicies, lookup, groups)
+	require.NoError(t, err)
+	query := "/api/v1/datasources/:uid/query"
+
+	for _, tc := range []struct {
+		name    string
+		req     Request
+		allowed bool
+		policy  string
+	}{
+		{"contractor queries public", Request{Identity: contractor, Method: "POST", Route: query, DatasourceID: "pub"}, true, "contractors-public"},
+		{"contractor queries private", Request{Identity: contractor, Method: "POST", Route: query, DatasourceID: "priv"}, false, "contractors-no-query"},
+		{"contractor queries untagged", Request{Identity: contractor, Method: "POST", Route: query, DatasourceID: "logs"}, false, "contractors-no-query"},
+		{"contractor reads schema", Request{Identity: contractor, Method: "GET", Route: "/api/v1/datasources/:uid/schema", DatasourceID: "priv"}, true, ""},
+		{"employee queries private", Request{Identity: employee, Method: "POST", Route: query, DatasourceID: "priv"}, true, ""},
+		{"no identity", Request{Method: "POST", Route: query, DatasourceID: "priv"}, true, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := e.Decide(context.Background(), tc.req)
+			require.NoError(t, err)
+			assert.Equal(t, tc.allowed, d.Allowed)
+			assert.Equal(t, tc.policy, d.Policy)
+		})
+	}
+}
+
+func TestDecide_Conditions(t *testing.T) {
+	req := Request{Identity: employee, Method: "POST", Route: "/api/v1/datasources/:uid/query", DatasourceID: "logs"}
+	for _, tc := range []struct {
+		name   string
+		policy config.PolicyConfig
+		match  bool
+	}{
+		{"everything", config.PolicyConfig{}, true},
+		{"endpoint glob", config.PolicyConfig{Endpoints: []string{"POST /api/v1/datasources/*/query"}}, true},
+		{"other endpoint", config.PolicyConfig{Endpoints: []string{"GET /api/v1/datasources/*/query"}}, false},
+		{"username glob", config.PolicyConfig{Users: []string{"er*"}}, true},
+		{"other user", config.PolicyConfig{Users: []string{"carol"}}, false},
+		{"role", config.PolicyConfig{Roles: []string{identity.RoleViewer}}, true},
+		{"other role", config.PolicyConfig{Roles: []string{identity.RoleAdmin}}, false},
+		{"not in group", config.PolicyConfig{Groups: []string{"contractors"}}, false},
+		{"datasource id", config.PolicyConfig{Datasources: []string{"logs"}}, true},
+		{"datasource type", config.PolicyConfig{DatasourceTypes: []string{"postgresql"}}, false},
+		{"missing tag", config.PolicyConfig{DatasourceTags: []string{"public"}}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.policy.Name, tc.policy.Effect = "p", EffectDeny
+			e, err := New([]config.PolicyConfig{tc.policy}, lookup, groups)
+			require.NoError(t, err)
+			d, err := e.Decide(context.Background(), req)
+			require.NoError(t, err)
+			assert.Equal(t, tc.match, !d.Allowed)
+		})
+	}
+}
+
+func TestDecide_DatasourceConditionsNeedADatasource(t *testing.T) {
+	e, err := New([]config.PolicyConfig{{Name: "p", Effect: EffectDeny, DatasourceTags: []string{"finance"}}}, lookup, groups)
+	require.NoError(t, err)
+	for _, id := range []string{"", "missing"} {
+		d, err := e.Decide(context.Background(), Request{Method: "GET", Route: "/api/v1/dashboards", DatasourceID: id})
+		require.NoError(t, err)
+		assert.True(t, d.Allowed)
+	}
+}
+
+func TestDecide_LookupError(t *testing.T) {
+	failing := func(context.Context, string) (*Datasource, error) { return nil, errors.New("store down") }
+	e, err := New([]config.PolicyConfig{{Name: "p", Effect: EffectDeny, DatasourceTags: []string{"finance"}}}, failing, nil)
+	require.NoError(t, err)
+	_, err = e.Decide(context.Background(), Request{Method: "GET", Route: "/api/v1/datasources/:uid", DatasourceID: "priv"})
+	assert.ErrorContains(t, err, "store down")
+}
+
+func TestNew_Invalid(t *testing.T) {
+	for _, p := range []config.PolicyConfig{
+		{Name: "p", Effect: "maybe"},
+		{Name: "p", Effect: EffectDeny, Roles: []string{"superuser"}},
+		{Name: "p", Effect: EffectDeny, Actions: []string{"datasources:["}},
+	} {
+		_, err := New([]config.PolicyConfig{p}, nil, nil)
+		assert.Error(t, err, "%+v", p)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	e, err := New(contractorPolicies, lookup, groups)
+	require.NoError(t, err)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(identity.With(c.Request.Context(), contractor))
+	})
+	api := r.Group("/api/v1", Middleware(e))
+	api.POST("/datasources/:uid/query", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/datasources/pub/query", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/datasources/priv/query", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	var body struct {
+		Policy string `json:"policy"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "contractors-no-query", body.Policy)
+}
+
+func TestCheckQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	e, err := New(contractorPolicies, lookup, groups)
+	require.NoError(t, err)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(identity.With(c.Request.Context(), contractor))
+	})
+	api := r.Group("/api/v1", Middleware(e))
+	api.POST("/diff", func(c *gin.Context) {
+		if err := CheckQuery(c.Request.Context(), c.Query("ds")); err != nil {
+			c.Status(http.StatusForbidden)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	for ds, want := range map[string]int{"pub": http.StatusOK, "priv": http.StatusForbidden} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/diff?ds="+ds, nil))
+		assert.Equal(t, want, w.Code, "a route without :uid is checked against the datasource it queries: %s", ds)
+	}
+
+	assert.NoError(t, CheckQuery(identity.With(context.Background(), contractor), "priv"), "scheduled jobs are not checked")
+}
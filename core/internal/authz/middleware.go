@@ -0,0 +1,98 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// ErrDenied is returned by CheckQuery when a policy denies the query.
+var ErrDenied = errors.New("denied by access policy")
+
+// QueryAction is the action of running a query on a datasource.
+const QueryAction = "datasources:query"
+
+type checkKey struct{}
+
+// check is what CheckQuery needs of the request Middleware let through.
+type check struct {
+	engine        *Engine
+	method, route string
+}
+
+// Middleware enforces e on every matched route. It must run after the
+// middleware that sets the caller's identity.
+func Middleware(e *Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if e.Empty() || route == "" {
+			c.Next()
+			return
+		}
+		ctx := context.WithValue(c.Request.Context(), checkKey{}, check{engine: e, method: c.Request.Method, route: route})
+		c.Request = c.Request.WithContext(ctx)
+		d, err := e.Decide(ctx, Request{
+			Identity:     identity.FromContext(ctx),
+			Method:       c.Request.Method,
+			Route:        route,
+			DatasourceID: datasourceParam(c, route),
+		})
+		if err != nil {
+			slog.Error("access policy evaluation failed", "route", route, "err", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !d.Allowed {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":  i18n.T(c, "denied by access policy"),
+				"policy": d.Policy,
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// CheckQuery evaluates the policies against running a query on
+// datasourceID as part of the request ctx belongs to. Routes that take
+// their datasource from the body or from a stored dashboard, monitor or
+// result are matched by Middleware without one, so they call this once
+// they know it. Contexts that did not pass through Middleware, such as
+// those of scheduled jobs, pass.
+func CheckQuery(ctx context.Context, datasourceID string) error {
+	ch, ok := ctx.Value(checkKey{}).(check)
+	if !ok || ch.engine.Empty() {
+		return nil
+	}
+	d, err := ch.engine.Decide(ctx, Request{
+		Identity:     identity.FromContext(ctx),
+		Method:       ch.method,
+		Route:        ch.route,
+		DatasourceID: datasourceID,
+		Action:       QueryAction,
+	})
+	if err != nil {
+		return err
+	}
+	if !d.Allowed {
+		return fmt.Errorf("%w: %s", ErrDenied, d.Policy)
+	}
+	return nil
+}
+
+// datasourceParam returns the datasource ID of routes under
+// /datasources/:uid.
+func datasourceParam(c *gin.Context, route string) string {
+	if strings.Contains(route, "/datasources/:uid") {
+		return c.Param("uid")
+	}
+	return ""
+}
@@ -0,0 +1,267 @@
+// Package bench load-tests a datasource through a running server's query
+// API, the same path the UI uses, so results include the server's own
+// overhead (auth, templating, transforms, pooling) and not just the driver.
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options describes one benchmark run.
+type Options struct {
+	// Server is the base URL of a running server, e.g. http://localhost:8080.
+	Server string
+	// Datasource is the name or uid of the datasource to query.
+	Datasource  string
+	Query       string
+	Concurrency int
+	Duration    time.Duration
+	// Limit caps rows per response; 0 uses the server default.
+	Limit int
+	// Headers are sent with every request, e.g. an Authorization header.
+	Headers http.Header
+}
+
+// Report summarises a run. Latencies only cover successful requests.
+type Report struct {
+	Datasource  string         `json:"datasource"`
+	Concurrency int            `json:"concurrency"`
+	Duration    time.Duration  `json:"duration_ns"`
+	Requests    int            `json:"requests"`
+	Errors      int            `json:"errors"`
+	ErrorRate   float64        `json:"error_rate"`
+	Throughput  float64        `json:"requests_per_second"`
+	Latency     Latency        `json:"latency"`
+	ErrorKinds  map[string]int `json:"error_kinds,omitempty"`
+}
+
+// Latency holds percentiles over successful requests.
+type Latency struct {
+	Min time.Duration `json:"min_ns"`
+	P50 time.Duration `json:"p50_ns"`
+	P90 time.Duration `json:"p90_ns"`
+	P95 time.Duration `json:"p95_ns"`
+	P99 time.Duration `json:"p99_ns"`
+	Max time.Duration `json:"max_ns"`
+}
+
+type sample struct {
+	latency time.Duration
+	// kind is empty for a success, otherwise the HTTP status or "transport".
+	kind string
+}
+
+// Run resolves the datasource, then sends the query from opts.Concurrency
+// workers back to back until opts.Duration has passed or ctx is cancelled.
+func Run(ctx context.Context, client *http.Client, opts Options) (*Report, error) {
+	if strings.TrimSpace(opts.Query) == "" {
+		return nil, errors.New("query is empty")
+	}
+	if opts.Concurrency < 1 {
+		return nil, errors.New("concurrency must be at least 1")
+	}
+	if opts.Duration <= 0 {
+		return nil, errors.New("duration must be positive")
+	}
+	base := strings.TrimRight(opts.Server, "/") + "/api/v1"
+
+	uid, err := resolveDatasource(ctx, client, base, opts)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]any{"query": opts.Query}
+	if opts.Limit > 0 {
+		body["limit"] = opts.Limit
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	url := base + "/datasources/" + uid + "/query"
+
+	runCtx, cancel := context.WithTimeout(ctx, opts.Duration)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		samples []sample
+		wg      sync.WaitGroup
+	)
+	start := time.Now()
+	for range opts.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var local []sample
+			for runCtx.Err() == nil {
+				s := send(runCtx, client, url, payload, opts.Headers)
+				// A request cut off by the end of the run says nothing about
+				// the backend, so it is not counted.
+				if runCtx.Err() != nil && s.kind != "" {
+					break
+				}
+				local = append(local, s)
+			}
+			mu.Lock()
+			samples = append(samples, local...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return summarise(opts, time.Since(start), samples), nil
+}
+
+func send(ctx context.Context, client *http.Client, url string, payload []byte, headers http.Header) sample {
+	req, err := newRequest(ctx, http.MethodPost, url, bytes.NewReader(payload), headers)
+	if err != nil {
+		return sample{kind: "transport"}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return sample{kind: "transport"}
+	}
+	// Read the whole body so the latency includes transferring the result,
+	// as it does for the UI.
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	s := sample{latency: time.Since(start)}
+	if resp.StatusCode >= 300 {
+		s.kind = fmt.Sprintf("HTTP %d", resp.StatusCode)
+	}
+	return s
+}
+
+func newRequest(ctx context.Context, method, url string, body io.Reader, headers http.Header) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header[k] = v
+	}
+	return req, nil
+}
+
+func resolveDatasource(ctx context.Context, client *http.Client, base string, opts Options) (string, error) {
+	req, err := newRequest(ctx, http.MethodGet, base+"/datasources", nil, opts.Headers)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach server: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to list datasources: HTTP %d", resp.StatusCode)
+	}
+	var list struct {
+		Data []struct {
+			UID  string `json:"uid"`
+			Name string `json:"name"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return "", fmt.Errorf("failed to decode datasources: %w", err)
+	}
+	for _, ds := range list.Data {
+		if ds.Name == opts.Datasource || ds.UID == opts.Datasource {
+			return ds.UID, nil
+		}
+	}
+	return "", fmt.Errorf("datasource %q not found", opts.Datasource)
+}
+
+func summarise(opts Options, elapsed time.Duration, samples []sample) *Report {
+	r := &Report{
+		Datasource:  opts.Datasource,
+		Concurrency: opts.Concurrency,
+		Duration:    elapsed,
+		Requests:    len(samples),
+	}
+	var ok []time.Duration
+	for _, s := range samples {
+		if s.kind == "" {
+			ok = append(ok, s.latency)
+			continue
+		}
+		r.Errors++
+		if r.ErrorKinds == nil {
+			r.ErrorKinds = map[string]int{}
+		}
+		r.ErrorKinds[s.kind]++
+	}
+	if r.Requests > 0 {
+		r.ErrorRate = float64(r.Errors) / float64(r.Requests)
+	}
+	if elapsed > 0 {
+		r.Throughput = float64(r.Requests) / elapsed.Seconds()
+	}
+	if len(ok) > 0 {
+		slices.Sort(ok)
+		r.Latency = Latency{
+			Min: ok[0],
+			P50: percentile(ok, 50),
+			P90: percentile(ok, 90),
+			P95: percentile(ok, 95),
+			P99: percentile(ok, 99),
+			Max: ok[len(ok)-1],
+		}
+	}
+	return r
+}
+
+// percentile uses the nearest-rank method on sorted values.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// WriteText prints r as a short human-readable summary.
+func (r *Report) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Datasource:   %s\n", r.Datasource)
+	fmt.Fprintf(&b, "Concurrency:  %d\n", r.Concurrency)
+	fmt.Fprintf(&b, "Duration:     %s\n", r.Duration.Round(time.Millisecond))
+	fmt.Fprintf(&b, "Requests:     %d (%.1f/s)\n", r.Requests, r.Throughput)
+	fmt.Fprintf(&b, "Errors:       %d (%.2f%%)\n", r.Errors, r.ErrorRate*100)
+	kinds := make([]string, 0, len(r.ErrorKinds))
+	for k := range r.ErrorKinds {
+		kinds = append(kinds, k)
+	}
+	slices.Sort(kinds)
+	for _, k := range kinds {
+		fmt.Fprintf(&b, "  %-12s %d\n", k, r.ErrorKinds[k])
+	}
+	if r.Errors < r.Requests {
+		b.WriteString("Latency:\n")
+		for _, row := range []struct {
+			name string
+			d    time.Duration
+		}{
+			{"min", r.Latency.Min}, {"p50", r.Latency.P50}, {"p90", r.Latency.P90},
+			{"p95", r.Latency.P95}, {"p99", r.Latency.P99}, {"max", r.Latency.Max},
+		} {
+			fmt.Fprintf(&b, "  %-4s %s\n", row.name, row.d.Round(time.Microsecond))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
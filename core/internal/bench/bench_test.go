@@ -0,0 +1,97 @@
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(t *testing.T) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var calls atomic.Int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/datasources", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[{"uid":"u1","name":"analytics"}]}`))
+	})
+	mux.HandleFunc("POST /api/v1/datasources/u1/query", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["query"] != "SELECT 1" || body["limit"] != float64(5) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// Every fourth request fails so the error rate is predictable.
+		if calls.Add(1)%4 == 0 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestRun(t *testing.T) {
+	srv, calls := newServer(t)
+
+	report, err := Run(context.Background(), srv.Client(), Options{
+		Server:      srv.URL + "/",
+		Datasource:  "analytics",
+		Query:       "SELECT 1",
+		Concurrency: 3,
+		Duration:    200 * time.Millisecond,
+		Limit:       5,
+		Headers:     http.Header{"Authorization": {"Bearer t"}},
+	})
+	require.NoError(t, err)
+
+	assert.Positive(t, report.Requests)
+	assert.LessOrEqual(t, int64(report.Requests), calls.Load())
+	assert.InDelta(t, 0.25, report.ErrorRate, 0.1)
+	assert.Equal(t, report.Errors, report.ErrorKinds["HTTP 502"])
+	l := report.Latency
+	assert.Positive(t, l.Min)
+	assert.True(t, l.Min <= l.P50 && l.P50 <= l.P90 && l.P90 <= l.P95 && l.P95 <= l.P99 && l.P99 <= l.Max)
+
+	var b strings.Builder
+	require.NoError(t, report.WriteText(&b))
+	assert.Contains(t, b.String(), "HTTP 502")
+	assert.Contains(t, b.String(), "p99")
+}
+
+func TestRun_UnknownDatasource(t *testing.T) {
+	srv, _ := newServer(t)
+	_, err := Run(context.Background(), srv.Client(), Options{
+		Server: srv.URL, Datasource: "nope", Query: "SELECT 1", Concurrency: 1, Duration: time.Second,
+		Headers: http.Header{"Authorization": {"Bearer t"}},
+	})
+	assert.ErrorContains(t, err, `datasource "nope" not found`)
+
+	_, err = Run(context.Background(), srv.Client(), Options{
+		Server: srv.URL, Datasource: "analytics", Query: "SELECT 1", Concurrency: 1, Duration: time.Second,
+	})
+	assert.ErrorContains(t, err, "HTTP 401")
+}
+
+func TestPercentile(t *testing.T) {
+	values := make([]time.Duration, 100)
+	for i := range values {
+		values[i] = time.Duration(i + 1)
+	}
+	assert.Equal(t, time.Duration(50), percentile(values, 50))
+	assert.Equal(t, time.Duration(99), percentile(values, 99))
+	assert.Equal(t, time.Duration(1), percentile(values[:1], 99))
+}
@@ -0,0 +1,9 @@
+// Package buildinfo holds the server's version, set at link time with
+// -ldflags "-X data-voyager/core/internal/buildinfo.Version=...".
+package buildinfo
+
+var (
+	Version   = "0.1.0"
+	BuildTime = "unknown"
+	GitCommit = "unknown"
+)
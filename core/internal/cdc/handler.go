@@ -0,0 +1,44 @@
+package cdc
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/user"
+)
+
+// Handler serves the change stream status endpoints.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates a change stream HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// Streams handles GET /admin/cdc/streams
+func (h *Handler) Streams(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.svc.Streams()})
+}
+
+// Events handles GET /admin/cdc/streams/:name/events
+func (h *Handler) Events(c *gin.Context) {
+	events, err := h.svc.Recent(c.Param("name"))
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "change stream not found")})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": events})
+}
+
+// RegisterRoutes wires the handler onto r. Both endpoints require the admin
+// permission.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	admin := r.Group("/admin", user.RequireAdmin)
+	admin.GET("/cdc/streams", h.Streams)
+	admin.GET("/cdc/streams/:name/events", h.Events)
+}
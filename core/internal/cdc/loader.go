@@ -0,0 +1,23 @@
+package cdc
+
+import (
+	"github.com/gin-gonic/gin"
+
+	apploader "data-voyager/core/internal/app"
+)
+
+type loader struct {
+	handler *Handler
+}
+
+// NewLoader wires the change stream routes. Polling is started separately
+// with Service.Schedule so it can share the server's lifetime.
+func NewLoader(svc *Service) apploader.Loader {
+	return &loader{handler: NewHandler(svc)}
+}
+
+func (l *loader) Load() error { return nil }
+
+func (l *loader) RegisterRoutes(r *gin.RouterGroup) {
+	RegisterRoutes(r, l.handler)
+}
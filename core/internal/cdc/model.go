@@ -0,0 +1,32 @@
+package cdc
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned for unknown streams.
+var ErrNotFound = errors.New("change stream not found")
+
+// Stream describes a configured stream and how it is doing.
+type Stream struct {
+	Name                    string `json:"name"`
+	DatasourceID            string `json:"datasource_id"`
+	Publication             string `json:"publication"`
+	Slot                    string `json:"slot"`
+	Target                  string `json:"target,omitempty"`
+	MaterializeDatasourceID string `json:"materialize_datasource_id,omitempty"`
+	MaterializeTable        string `json:"materialize_table,omitempty"`
+	// Position is the last position confirmed to the source.
+	Position string `json:"position,omitempty"`
+	// Events counts the changes handled since start-up.
+	Events int64 `json:"events"`
+	// Rejected counts changes the materialize datasource refused; they are
+	// logged and skipped.
+	Rejected int64      `json:"rejected"`
+	LastPoll *time.Time `json:"last_poll,omitempty"`
+	// LastChange is when the newest handled change was committed.
+	LastChange *time.Time `json:"last_change,omitempty"`
+	// Error is why the last poll failed; empty after a successful one.
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,287 @@
+package cdc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/webhook"
+	"data-voyager/sdk"
+)
+
+const (
+	defaultBatchSize = 1000
+	// maxRounds bounds the batches one stream reads per poll, so a backlog
+	// on one stream does not hold up the others.
+	maxRounds = 10
+	// recentSize is how many changes per stream are kept for the API.
+	recentSize = 200
+)
+
+// Publisher sends a result to a webhook target; *webhook.Service is one.
+type Publisher interface {
+	Publish(ctx context.Context, target string, d *webhook.Delivery, res *sdk.QueryResult) (*webhook.Delivery, error)
+}
+
+// Service polls the configured streams and passes their changes on. A batch
+// is confirmed to the source only once it has been handed to the webhook
+// and materialized, so a failure means it is read again on the next poll:
+// consumers see each change at least once.
+type Service struct {
+	streams   []config.CDCStream
+	conns     connection.Repository
+	registry  *datasource.Registry
+	publisher Publisher
+	now       func() time.Time
+
+	mu     sync.Mutex
+	status map[string]*Stream
+	recent map[string][]sdk.ChangeEvent // oldest first
+}
+
+// NewService creates a Service for cfg's streams. publisher may be nil when
+// no stream has a target.
+func NewService(cfg config.CDCConfig, conns connection.Repository, registry *datasource.Registry, publisher Publisher) *Service {
+	s := &Service{
+		conns:     conns,
+		registry:  registry,
+		publisher: publisher,
+		now:       time.Now,
+		status:    map[string]*Stream{},
+		recent:    map[string][]sdk.ChangeEvent{},
+	}
+	if !cfg.Enabled {
+		return s
+	}
+	for _, st := range cfg.Streams {
+		if st.Slot == "" {
+			st.Slot = "dv_cdc_" + st.Name
+		}
+		if st.BatchSize <= 0 {
+			st.BatchSize = defaultBatchSize
+		}
+		s.streams = append(s.streams, st)
+		s.status[st.Name] = &Stream{
+			Name: st.Name, DatasourceID: st.DatasourceID, Publication: st.Publication, Slot: st.Slot,
+			Target: st.Target, MaterializeDatasourceID: st.MaterializeDatasourceID, MaterializeTable: st.MaterializeTable,
+		}
+	}
+	return s
+}
+
+// Streams lists the streams in configuration order.
+func (s *Service) Streams() []Stream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Stream, 0, len(s.streams))
+	for _, st := range s.streams {
+		out = append(out, *s.status[st.Name])
+	}
+	return out
+}
+
+// Recent returns the latest changes a stream handled, newest first.
+func (s *Service) Recent(name string) ([]sdk.ChangeEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.status[name]; !ok {
+		return nil, ErrNotFound
+	}
+	events := s.recent[name]
+	out := make([]sdk.ChangeEvent, len(events))
+	for i, ev := range events {
+		out[len(events)-1-i] = ev
+	}
+	return out, nil
+}
+
+// Schedule polls every stream each tick until ctx is done.
+func (s *Service) Schedule(ctx context.Context, tick time.Duration) {
+	if len(s.streams) == 0 {
+		return
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s.Poll(ctx)
+	}
+}
+
+// Poll reads and passes on what each stream has waiting, one stream after
+// another. Failures are recorded on the stream.
+func (s *Service) Poll(ctx context.Context) {
+	for _, st := range s.streams {
+		if ctx.Err() != nil {
+			return
+		}
+		err := s.poll(ctx, st)
+		now := s.now().UTC()
+		s.mu.Lock()
+		status := s.status[st.Name]
+		status.LastPoll = &now
+		status.Error = ""
+		if err != nil {
+			status.Error = err.Error()
+		}
+		s.mu.Unlock()
+		if err != nil {
+			slog.Warn("change stream poll failed", "stream", st.Name, "err", err)
+		}
+	}
+}
+
+func (s *Service) poll(ctx context.Context, st config.CDCStream) error {
+	src, err := s.open(ctx, st.DatasourceID)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+	streamer, ok := src.(sdk.ChangeStreamer)
+	if !ok {
+		return fmt.Errorf("datasource %q cannot stream changes", st.DatasourceID)
+	}
+	for range maxRounds {
+		batch, err := streamer.ReadChanges(ctx, st.Slot, st.Publication, st.BatchSize)
+		if err != nil {
+			return err
+		}
+		if batch.Position == "" {
+			return nil
+		}
+		if err := s.handle(ctx, st, batch.Events); err != nil {
+			return err
+		}
+		if err := streamer.ConfirmChanges(ctx, st.Slot, batch.Position); err != nil {
+			return err
+		}
+		s.record(st.Name, batch)
+		// The source stops at a transaction boundary past the limit, so a
+		// short batch means nothing more is waiting.
+		if len(batch.Events) < st.BatchSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+// handle publishes events to the stream's target and ingests them into its
+// materialize table.
+func (s *Service) handle(ctx context.Context, st config.CDCStream, events []sdk.ChangeEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if st.Target != "" {
+		if s.publisher == nil {
+			return fmt.Errorf("webhooks are not available")
+		}
+		d := &webhook.Delivery{Source: webhook.SourceCDC, Stream: st.Name, DatasourceID: st.DatasourceID}
+		if _, err := s.publisher.Publish(ctx, st.Target, d, toResult(events)); err != nil {
+			return fmt.Errorf("publish to %s: %w", st.Target, err)
+		}
+	}
+	if st.MaterializeDatasourceID == "" {
+		return nil
+	}
+	dst, err := s.open(ctx, st.MaterializeDatasourceID)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dst.Close() }()
+	ingester, ok := dst.(sdk.Ingester)
+	if !ok {
+		return fmt.Errorf("datasource %q does not support ingestion", st.MaterializeDatasourceID)
+	}
+	records := make([]json.RawMessage, len(events))
+	for i, ev := range events {
+		if records[i], err = json.Marshal(ev); err != nil {
+			return err
+		}
+	}
+	res, err := ingester.Ingest(ctx, st.MaterializeTable, records)
+	if err != nil {
+		return fmt.Errorf("materialize into %s: %w", st.MaterializeTable, err)
+	}
+	// A rejected change would be rejected again on every retry, so it is
+	// logged and skipped rather than holding the stream up.
+	for _, e := range res.Errors {
+		ev := events[e.Index]
+		slog.Warn("change stream materialize rejected a change", "stream", st.Name,
+			"position", ev.Position, "table", ev.Schema+"."+ev.Table, "err", e.Error)
+	}
+	if n := len(res.Errors); n > 0 {
+		s.mu.Lock()
+		s.status[st.Name].Rejected += int64(n)
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// record notes a confirmed batch on the stream's status.
+func (s *Service) record(name string, batch *sdk.ChangeBatch) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.status[name]
+	status.Position = batch.Position
+	status.Events += int64(len(batch.Events))
+	if n := len(batch.Events); n > 0 {
+		t := batch.Events[n-1].CommitTime
+		status.LastChange = &t
+	}
+	recent := append(s.recent[name], batch.Events...)
+	if len(recent) > recentSize {
+		recent = append([]sdk.ChangeEvent(nil), recent[len(recent)-recentSize:]...)
+	}
+	s.recent[name] = recent
+}
+
+// open connects to a datasource the way webhook schedules do.
+func (s *Service) open(ctx context.Context, datasourceID string) (sdk.Connection, error) {
+	conn, err := s.conns.GetByID(ctx, datasourceID)
+	if err != nil {
+		return nil, fmt.Errorf("datasource %q not found", datasourceID)
+	}
+	if err := connection.CheckOrigin(ctx, conn); err != nil {
+		return nil, err
+	}
+	plugin, ok := s.registry.Get(conn.Type)
+	if !ok {
+		return nil, fmt.Errorf("plugin not found for type %s", conn.Type)
+	}
+	cfg, err := plugin.ParseConfig(conn.Config)
+	if err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	dbConn, err := plugin.Connect(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	return dbConn, nil
+}
+
+// toResult lays events out as one row each, row and old as JSON.
+func toResult(events []sdk.ChangeEvent) *sdk.QueryResult {
+	names := []string{"position", "op", "schema", "table", "commit_time", "row", "old"}
+	fields := make([]sdk.Field, len(names))
+	for i, n := range names {
+		fields[i] = sdk.Field{Name: n, Values: make([]any, len(events))}
+	}
+	for r, ev := range events {
+		row, _ := json.Marshal(ev.Row)
+		old, _ := json.Marshal(ev.Old)
+		for i, v := range []any{ev.Position, ev.Op, ev.Schema, ev.Table, ev.CommitTime, json.RawMessage(row), json.RawMessage(old)} {
+			fields[i].Values[r] = v
+		}
+	}
+	return &sdk.QueryResult{Frames: []*sdk.DataFrame{{Fields: fields}}}
+}
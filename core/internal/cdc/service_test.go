@@ -0,0 +1,205 @@
+package cdc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/webhook"
+	"data-voyager/sdk"
+)
+
+type stubConns struct{ connection.Repository }
+
+func (stubConns) GetByID(_ context.Context, id string) (*connection.Connection, error) {
+	switch id {
+	case "src":
+		return &connection.Connection{ID: id, Type: "postgresql", Config: json.RawMessage(`{}`)}, nil
+	case "dst":
+		return &connection.Connection{ID: id, Type: "clickhouse", Config: json.RawMessage(`{}`)}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+type stubConfig struct{}
+
+func (stubConfig) Validate() error             { return nil }
+func (stubConfig) GetConnectionString() string { return "" }
+
+type stubPlugin struct {
+	sdk.DatasourcePlugin
+	typ  sdk.DataSourceType
+	conn sdk.Connection
+}
+
+func (p *stubPlugin) GetType() sdk.DataSourceType { return p.typ }
+func (p *stubPlugin) ParseConfig(json.RawMessage) (sdk.ConnectionConfig, error) {
+	return stubConfig{}, nil
+}
+func (p *stubPlugin) Connect(context.Context, sdk.ConnectionConfig) (sdk.Connection, error) {
+	return p.conn, nil
+}
+
+// source hands out its batches in turn and records what was confirmed.
+type source struct {
+	sdk.Connection
+	batches   []*sdk.ChangeBatch
+	confirmed []string
+}
+
+func (s *source) ReadChanges(context.Context, string, string, int) (*sdk.ChangeBatch, error) {
+	if len(s.confirmed) >= len(s.batches) {
+		return &sdk.ChangeBatch{}, nil
+	}
+	// Unconfirmed changes are read again, as from a replication slot.
+	return s.batches[len(s.confirmed)], nil
+}
+
+func (s *source) ConfirmChanges(_ context.Context, _, position string) error {
+	s.confirmed = append(s.confirmed, position)
+	return nil
+}
+
+func (s *source) Close() error { return nil }
+
+// sink ingests records, rejecting those for the "bad" table.
+type sink struct {
+	sdk.Connection
+	err     error
+	records []json.RawMessage
+}
+
+func (s *sink) Ingest(_ context.Context, _ string, records []json.RawMessage) (*sdk.IngestResult, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	res := &sdk.IngestResult{}
+	for i, r := range records {
+		if strings.Contains(string(r), `"table":"bad"`) {
+			res.Errors = append(res.Errors, sdk.IngestError{Index: i, Error: "cannot convert"})
+			continue
+		}
+		s.records = append(s.records, r)
+		res.Accepted++
+	}
+	return res, nil
+}
+
+func (s *sink) Close() error { return nil }
+
+type publisher struct {
+	deliveries []*webhook.Delivery
+	results    []*sdk.QueryResult
+}
+
+func (p *publisher) Publish(_ context.Context, target string, d *webhook.Delivery, res *sdk.QueryResult) (*webhook.Delivery, error) {
+	if target != "hook" {
+		return nil, webhook.ErrNotFound
+	}
+	p.deliveries = append(p.deliveries, d)
+	p.results = append(p.results, res)
+	return d, nil
+}
+
+func change(lsn, op, table string) sdk.ChangeEvent {
+	return sdk.ChangeEvent{Position: lsn, Op: op, Schema: "public", Table: table,
+		Row: map[string]any{"id": 1}, CommitTime: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func newTestService(src *source, dst *sink, streams ...config.CDCStream) (*Service, *publisher) {
+	reg := datasource.NewRegistry()
+	reg.Register(&stubPlugin{typ: "postgresql", conn: src})
+	reg.Register(&stubPlugin{typ: "clickhouse", conn: dst})
+	pub := &publisher{}
+	return NewService(config.CDCConfig{Enabled: true, Streams: streams}, stubConns{}, reg, pub), pub
+}
+
+func TestPoll_PublishesMaterializesAndConfirms(t *testing.T) {
+	src := &source{batches: []*sdk.ChangeBatch{{
+		Events:   []sdk.ChangeEvent{change("0/10", sdk.ChangeInsert, "orders"), change("0/10", sdk.ChangeUpdate, "bad")},
+		Position: "0/20",
+	}}}
+	dst := &sink{}
+	svc, pub := newTestService(src, dst, config.CDCStream{Name: "orders", DatasourceID: "src", Publication: "pub",
+		Target: "hook", MaterializeDatasourceID: "dst", MaterializeTable: "changes"})
+
+	svc.Poll(context.Background())
+
+	assert.Equal(t, []string{"0/20"}, src.confirmed)
+	require.Len(t, pub.deliveries, 1)
+	assert.Equal(t, webhook.SourceCDC, pub.deliveries[0].Source)
+	assert.Equal(t, "orders", pub.deliveries[0].Stream)
+	fields := pub.results[0].Frames[0].Fields
+	assert.Equal(t, "op", fields[1].Name)
+	assert.Equal(t, []any{sdk.ChangeInsert, sdk.ChangeUpdate}, fields[1].Values)
+	assert.JSONEq(t, `{"id":1}`, string(fields[5].Values[0].(json.RawMessage)))
+
+	require.Len(t, dst.records, 1, "the rejected change is skipped")
+	assert.Contains(t, string(dst.records[0]), `"table":"orders"`)
+
+	streams := svc.Streams()
+	require.Len(t, streams, 1)
+	st := streams[0]
+	assert.Equal(t, "dv_cdc_orders", st.Slot)
+	assert.Equal(t, "0/20", st.Position)
+	assert.Equal(t, int64(2), st.Events)
+	assert.Equal(t, int64(1), st.Rejected)
+	assert.Empty(t, st.Error)
+	require.NotNil(t, st.LastPoll)
+
+	recent, err := svc.Recent("orders")
+	require.NoError(t, err)
+	require.Len(t, recent, 2)
+	assert.Equal(t, "bad", recent[0].Table, "newest first")
+	_, err = svc.Recent("missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestPoll_FailureLeavesChangesUnconfirmed(t *testing.T) {
+	src := &source{batches: []*sdk.ChangeBatch{{
+		Events: []sdk.ChangeEvent{change("0/10", sdk.ChangeInsert, "orders")}, Position: "0/20",
+	}}}
+	dst := &sink{err: errors.New("unknown table")}
+	svc, pub := newTestService(src, dst, config.CDCStream{Name: "orders", DatasourceID: "src", Publication: "pub",
+		Target: "hook", MaterializeDatasourceID: "dst", MaterializeTable: "changes"})
+
+	svc.Poll(context.Background())
+	assert.Empty(t, src.confirmed)
+	assert.Contains(t, svc.Streams()[0].Error, "unknown table")
+
+	// The batch is read again once the datasource recovers; the webhook
+	// sees it twice.
+	dst.err = nil
+	svc.Poll(context.Background())
+	assert.Equal(t, []string{"0/20"}, src.confirmed)
+	assert.Len(t, pub.deliveries, 2)
+	assert.Empty(t, svc.Streams()[0].Error)
+}
+
+func TestPoll_DrainsFullBatches(t *testing.T) {
+	src := &source{batches: []*sdk.ChangeBatch{
+		{Events: []sdk.ChangeEvent{change("0/1", sdk.ChangeInsert, "a"), change("0/2", sdk.ChangeInsert, "a")}, Position: "0/3"},
+		{Events: []sdk.ChangeEvent{change("0/4", sdk.ChangeDelete, "a")}, Position: "0/5"},
+	}}
+	svc, pub := newTestService(src, &sink{}, config.CDCStream{Name: "a", DatasourceID: "src", Publication: "pub",
+		Target: "hook", BatchSize: 2})
+
+	svc.Poll(context.Background())
+	assert.Equal(t, []string{"0/3", "0/5"}, src.confirmed)
+	assert.Len(t, pub.deliveries, 2)
+	assert.Equal(t, int64(3), svc.Streams()[0].Events)
+}
+
+func TestNewService_Disabled(t *testing.T) {
+	svc := NewService(config.CDCConfig{Streams: []config.CDCStream{{Name: "a"}}}, stubConns{}, datasource.NewRegistry(), nil)
+	assert.Empty(t, svc.Streams())
+}
@@ -1,16 +1,28 @@
 package config
 
-import "fmt"
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
 
 // DBConfig is the top-level metadata store configuration.
 // Type selects which sub-section is active; only that section needs to be
 // populated in the config file.
 type DBConfig struct {
-	Type           string           `toml:"type"             mapstructure:"type"`
-	MigrateOnStart bool             `toml:"migrate_on_start" mapstructure:"migrate_on_start"`
-	SQLite         SQLiteConfig     `toml:"sqlite"           mapstructure:"sqlite"`
-	PostgreSQL     PostgreSQLConfig `toml:"postgresql"       mapstructure:"postgresql"`
-	MySQL          MySQLConfig      `toml:"mysql"            mapstructure:"mysql"`
+	Type            string           `toml:"type"             mapstructure:"type"`
+	MigrateOnStart  bool             `toml:"migrate_on_start" mapstructure:"migrate_on_start"`
+	MigrationBackup bool             `toml:"migration_backup" mapstructure:"migration_backup"` // PostgreSQL only; see store.Migrate
+	SQLite          SQLiteConfig     `toml:"sqlite"           mapstructure:"sqlite"`
+	PostgreSQL      PostgreSQLConfig `toml:"postgresql"       mapstructure:"postgresql"`
+	MySQL           MySQLConfig      `toml:"mysql"            mapstructure:"mysql"`
 }
 
 // SQLiteConfig holds SQLite-specific settings.
@@ -135,6 +147,355 @@ type Config struct {
 	Logging         LoggingConfig         `toml:"logging"`
 	Security        SecurityConfig        `toml:"security"`
 	AI              AIConfig              `toml:"ai"`
+	Telemetry       TelemetryConfig       `toml:"telemetry"`
+	Retention       RetentionConfig       `toml:"retention"`
+	GitSync         GitSyncConfig         `toml:"gitsync"`
+	AsyncResults    AsyncResultsConfig    `toml:"async_results"`
+	Exports         ExportsConfig         `toml:"exports"`
+	Frontend        FrontendConfig        `toml:"frontend"`
+	Webhooks        WebhooksConfig        `toml:"webhooks"`
+	Email           EmailConfig           `toml:"email"`
+	Notifications   NotificationsConfig   `toml:"notifications"`
+	Demo            DemoConfig            `toml:"demo"`
+	Rendering       RenderingConfig       `toml:"rendering"`
+	Updates         UpdatesConfig         `toml:"updates"`
+	CDC             CDCConfig             `toml:"cdc"`
+	RowEditing      RowEditingConfig      `toml:"row_editing"`
+	AuditLog        AuditLogConfig        `toml:"audit_log"`
+	Warehouses      WarehousesConfig      `toml:"warehouses"`
+}
+
+// WarehousesConfig controls how scheduled runs treat datasources whose
+// backend suspends idle compute, such as ClickHouse Cloud services. With
+// PreResume set, monitors and reconciliation jobs ask a suspended warehouse
+// to resume that many seconds before they fall due, so the run does not
+// wait out the resume; 0 leaves the warehouse to resume on the run itself.
+type WarehousesConfig struct {
+	PreResume int `toml:"pre_resume" mapstructure:"pre_resume"` // seconds
+}
+
+// RenderingConfig points at the headless browser service that turns
+// rendered dashboards and query results into PNG and PDF. HTML needs no
+// service; PNG and PDF are unavailable while URL is empty. The service must
+// speak Gotenberg's Chromium routes.
+type RenderingConfig struct {
+	URL     string `toml:"url"     mapstructure:"url"`
+	Timeout int    `toml:"timeout" mapstructure:"timeout"` // seconds per render
+}
+
+// UpdatesConfig controls checking GitHub releases for a newer server. When
+// Check is set the server looks once per Interval and reports what it found
+// in /health; nothing is downloaded until an admin asks for an upgrade.
+type UpdatesConfig struct {
+	Check      bool   `toml:"check"       mapstructure:"check"`
+	Interval   int    `toml:"interval"    mapstructure:"interval"`   // hours between checks
+	Repository string `toml:"repository"  mapstructure:"repository"` // owner/name on GitHub
+	APIURL     string `toml:"api_url"     mapstructure:"api_url"`    // GitHub API, or a mirror of it
+	// PublicKey is the base64 Ed25519 key release checksums are signed
+	// with. When set, unsigned releases are refused; when empty only the
+	// checksums are verified.
+	PublicKey string `toml:"public_key" mapstructure:"public_key"`
+	// AllowApply lets admins replace the binary from the API. The command
+	// line upgrade works either way. It requires PublicKey and an https
+	// APIURL, so only signed releases fetched over TLS replace the server.
+	AllowApply bool `toml:"allow_apply" mapstructure:"allow_apply"`
+}
+
+// CDCConfig tails PostgreSQL publications through logical replication
+// slots and passes the changes on. Experimental: delivery is at least once,
+// so consumers must tolerate repeats.
+type CDCConfig struct {
+	Enabled  bool        `toml:"enabled"  mapstructure:"enabled"`
+	Interval int         `toml:"interval" mapstructure:"interval"` // seconds between polls (default 5)
+	Streams  []CDCStream `toml:"streams"  mapstructure:"streams"`
+}
+
+// CDCStream reads the changes to Publication on a datasource and sends them
+// to a webhook target, inserts them into a table of another datasource, or
+// both.
+type CDCStream struct {
+	Name         string `toml:"name"          mapstructure:"name"`
+	DatasourceID string `toml:"datasource_id" mapstructure:"datasource_id"`
+	Publication  string `toml:"publication"   mapstructure:"publication"`
+	// Slot is the replication slot, created on first use. It defaults to
+	// dv_cdc_<name>; two streams must not share one.
+	Slot      string `toml:"slot"       mapstructure:"slot"`
+	BatchSize int    `toml:"batch_size" mapstructure:"batch_size"` // changes per poll (default 1000)
+	Target    string `toml:"target"     mapstructure:"target"`     // webhook target
+	// MaterializeDatasourceID and MaterializeTable name where changes are
+	// ingested as one row each; the datasource must support ingestion.
+	MaterializeDatasourceID string `toml:"materialize_datasource_id" mapstructure:"materialize_datasource_id"`
+	MaterializeTable        string `toml:"materialize_table"         mapstructure:"materialize_table"`
+}
+
+// RowEditingConfig lists the tables whose rows may be inserted, updated and
+// deleted one at a time through the API, typically small lookup tables.
+// Tables not listed cannot be edited this way.
+type RowEditingConfig struct {
+	Tables []EditableTable `toml:"tables" mapstructure:"tables"`
+}
+
+// EditableTable is a table open to row editing. Rows are addressed by Key,
+// which must identify at most one row.
+type EditableTable struct {
+	Name         string   `toml:"name"          mapstructure:"name"`
+	DatasourceID string   `toml:"datasource_id" mapstructure:"datasource_id"`
+	Schema       string   `toml:"schema"        mapstructure:"schema"`
+	Table        string   `toml:"table"         mapstructure:"table"`
+	Key          []string `toml:"key"           mapstructure:"key"`     // primary key columns
+	Columns      []string `toml:"columns"       mapstructure:"columns"` // editable columns; empty means all but the key
+}
+
+// AuditLogConfig forwards query history and audit events (exports, row
+// edits, impersonation) as JSON lines to the systems security teams keep
+// logs in. Events are sent in batches of BatchSize, or every FlushInterval
+// seconds when fewer are waiting. Delivery is best effort: a batch a sink
+// still rejects after a few attempts is dropped and logged, and the audit
+// tables in the metadata store remain the record.
+type AuditLogConfig struct {
+	BatchSize     int            `toml:"batch_size"     mapstructure:"batch_size"`     // events per write (default 500)
+	FlushInterval int            `toml:"flush_interval" mapstructure:"flush_interval"` // seconds (default 10)
+	Sinks         []AuditLogSink `toml:"sinks"          mapstructure:"sinks"`
+}
+
+// Audit log sink types.
+const (
+	AuditLogKafka = "kafka"
+	AuditLogLoki  = "loki"
+	AuditLogS3    = "s3"
+)
+
+// AuditLogSink is one destination of the audit log. Events lists the event
+// types it receives (query, export, row_edit, impersonation); empty means
+// all of them.
+type AuditLogSink struct {
+	Name   string            `toml:"name"   mapstructure:"name"`
+	Type   string            `toml:"type"   mapstructure:"type"` // kafka | loki | s3
+	Events []string          `toml:"events" mapstructure:"events"`
+	Kafka  AuditLogKafkaSink `toml:"kafka"  mapstructure:"kafka"`
+	Loki   AuditLogLokiSink  `toml:"loki"   mapstructure:"loki"`
+	// S3 receives one object per batch, keyed by the UTC date and time of
+	// its first event below Prefix.
+	S3 S3Config `toml:"s3" mapstructure:"s3"`
+}
+
+// AuditLogKafkaSink produces each event as one record to Topic, keyed by
+// event type.
+type AuditLogKafkaSink struct {
+	Brokers []string `toml:"brokers" mapstructure:"brokers"`
+	Topic   string   `toml:"topic"   mapstructure:"topic"`
+	TLS     bool     `toml:"tls"     mapstructure:"tls"`
+	// SASLMechanism is PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512; empty
+	// connects without authentication.
+	SASLMechanism string `toml:"sasl_mechanism" mapstructure:"sasl_mechanism"`
+	Username      string `toml:"username"       mapstructure:"username"`
+	Password      string `toml:"password"       mapstructure:"password"`
+}
+
+// AuditLogLokiSink pushes events to Grafana Loki, in one stream per event
+// type carrying Labels and a "type" label.
+type AuditLogLokiSink struct {
+	URL      string            `toml:"url"       mapstructure:"url"` // base URL, e.g. http://loki:3100
+	Labels   map[string]string `toml:"labels"    mapstructure:"labels"`
+	TenantID string            `toml:"tenant_id" mapstructure:"tenant_id"` // sent as X-Scope-OrgID
+	Username string            `toml:"username"  mapstructure:"username"`
+	Password string            `toml:"password"  mapstructure:"password"`
+	Timeout  int               `toml:"timeout"   mapstructure:"timeout"` // seconds per push (default 10)
+}
+
+// DemoConfig turns the server into a public, read-only demo: visitors
+// without a session browse the listed datasources and dashboards as a
+// viewer that can only run plain reads, capped at RowLimit rows, and cannot
+// export or change anything.
+type DemoConfig struct {
+	Enabled     bool     `toml:"enabled"     mapstructure:"enabled"`
+	Datasources []string `toml:"datasources" mapstructure:"datasources"` // datasource IDs
+	Dashboards  []string `toml:"dashboards"  mapstructure:"dashboards"`  // dashboard IDs
+	RowLimit    int      `toml:"row_limit"   mapstructure:"row_limit"`
+}
+
+// EmailConfig configures outbound email through an SMTP relay. Email is
+// off while Host is empty.
+type EmailConfig struct {
+	Host     string `toml:"host"     mapstructure:"host"`
+	Port     int    `toml:"port"     mapstructure:"port"`
+	Username string `toml:"username" mapstructure:"username"`
+	Password string `toml:"password" mapstructure:"password"`
+	// From is the sender, e.g. "Data Voyager <voyager@example.com>".
+	From    string `toml:"from"    mapstructure:"from"`
+	TLS     string `toml:"tls"     mapstructure:"tls"`     // starttls | tls | none
+	Timeout int    `toml:"timeout" mapstructure:"timeout"` // seconds per message
+	// TemplatesDir holds templates that replace the built-in ones, laid out
+	// like them: <name>/subject.txt, <name>/body.txt and <name>/body.html.
+	TemplatesDir string `toml:"templates_dir" mapstructure:"templates_dir"`
+}
+
+// NotificationsConfig governs where notification channels may deliver.
+// Webhook URLs and SMTP hosts on loopback, private, link-local (including
+// the 169.254.169.254 cloud metadata service) and unspecified addresses are
+// refused unless they fall inside AllowedNetworks, given as addresses or
+// CIDRs, so a channel cannot be pointed at the server's own network.
+type NotificationsConfig struct {
+	AllowedNetworks []string `toml:"allowed_networks" mapstructure:"allowed_networks"`
+}
+
+// Networks parses AllowedNetworks. A bare address is a single-host network.
+func (c *NotificationsConfig) Networks() ([]netip.Prefix, error) {
+	networks := make([]netip.Prefix, 0, len(c.AllowedNetworks))
+	for _, s := range c.AllowedNetworks {
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			addr, aerr := netip.ParseAddr(s)
+			if aerr != nil {
+				return nil, fmt.Errorf("invalid notifications.allowed_networks entry %q", s)
+			}
+			p = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		networks = append(networks, p.Masked())
+	}
+	return networks, nil
+}
+
+// WebhooksConfig lists where query results may be delivered and the queries
+// that deliver them on a schedule.
+type WebhooksConfig struct {
+	Targets   []WebhookTarget   `toml:"targets"   mapstructure:"targets"`
+	Schedules []WebhookSchedule `toml:"schedules" mapstructure:"schedules"`
+}
+
+// WebhookTarget is an endpoint query results are POSTed to. Deliveries are
+// signed with Secret when it is set.
+type WebhookTarget struct {
+	Name        string            `toml:"name"         mapstructure:"name"`
+	URL         string            `toml:"url"          mapstructure:"url"`
+	Secret      string            `toml:"secret"       mapstructure:"secret"`
+	Format      string            `toml:"format"       mapstructure:"format"`       // json | csv | html | png | pdf (default json)
+	Headers     map[string]string `toml:"headers"      mapstructure:"headers"`      // sent with every delivery
+	Timeout     int               `toml:"timeout"      mapstructure:"timeout"`      // seconds per attempt (default 10)
+	MaxAttempts int               `toml:"max_attempts" mapstructure:"max_attempts"` // attempts before giving up (default 5)
+}
+
+// WebhookSchedule runs Query against a datasource every Interval seconds and
+// delivers the result to Target.
+type WebhookSchedule struct {
+	Name         string `toml:"name"          mapstructure:"name"`
+	Target       string `toml:"target"        mapstructure:"target"`
+	DatasourceID string `toml:"datasource_id" mapstructure:"datasource_id"`
+	Query        string `toml:"query"         mapstructure:"query"`
+	Interval     int    `toml:"interval"      mapstructure:"interval"`
+}
+
+// Frontend modes.
+const (
+	FrontendEmbedded = "embedded" // the SPA compiled into the binary
+	FrontendDir      = "dir"      // a built SPA in Dir
+	FrontendNone     = "none"     // API only
+)
+
+// FrontendConfig selects where the web UI is served from. Binaries built with
+// the headless tag have no embedded UI and need Mode dir or none.
+type FrontendConfig struct {
+	Mode string `toml:"mode" mapstructure:"mode"`
+	Dir  string `toml:"dir"  mapstructure:"dir"`
+}
+
+// ExportsConfig controls background export jobs. Files are written to Dir in
+// chunks of ChunkSize rows and removed TTL after the job finishes. When
+// Watermark is set, every exported row carries a column of that name holding
+// the job's watermark, which the export audit traces back to who exported it.
+type ExportsConfig struct {
+	Dir       string `toml:"dir"        mapstructure:"dir"`
+	ChunkSize int    `toml:"chunk_size" mapstructure:"chunk_size"` // rows read per query
+	TTL       int    `toml:"ttl"        mapstructure:"ttl"`        // hours a finished export is kept
+	Watermark string `toml:"watermark"  mapstructure:"watermark"`  // column name; empty adds none
+}
+
+// AsyncResultsConfig controls where results of async queries are kept.
+// Results over SpillThreshold are written to Archive and read back only when
+// fetched; smaller ones stay in memory. Both expire after TTL.
+type AsyncResultsConfig struct {
+	SpillThreshold int           `toml:"spill_threshold" mapstructure:"spill_threshold"` // KiB of encoded result
+	TTL            int           `toml:"ttl"             mapstructure:"ttl"`             // minutes a result is kept
+	Archive        ArchiveConfig `toml:"archive"         mapstructure:"archive"`
+}
+
+// GitSyncConfig mirrors dashboards to a Git repository. Credentials come
+// from the server user's git setup (SSH keys, credential helper) or the
+// remote URL.
+type GitSyncConfig struct {
+	Enabled bool   `toml:"enabled" mapstructure:"enabled"`
+	Remote  string `toml:"remote"  mapstructure:"remote"` // URL or path git can fetch from and push to
+	Branch  string `toml:"branch"  mapstructure:"branch"`
+	// Path is the directory within the repository holding the files; empty
+	// means the repository root.
+	Path string `toml:"path" mapstructure:"path"`
+	// Dir is the server's working copy.
+	Dir         string `toml:"dir"          mapstructure:"dir"`
+	AuthorName  string `toml:"author_name"  mapstructure:"author_name"`
+	AuthorEmail string `toml:"author_email" mapstructure:"author_email"`
+}
+
+// RetentionConfig bounds the growth of history and run tables. Tables
+// without a policy are never pruned but still appear in the size report.
+type RetentionConfig struct {
+	Enabled   bool `toml:"enabled"    mapstructure:"enabled"`
+	Interval  int  `toml:"interval"   mapstructure:"interval"`   // minutes between pruning passes
+	BatchSize int  `toml:"batch_size" mapstructure:"batch_size"` // rows archived and deleted per statement
+	// Policies is keyed by table name, e.g. "connection_history".
+	Policies map[string]RetentionPolicy `toml:"policies" mapstructure:"policies"`
+	Archive  ArchiveConfig              `toml:"archive"  mapstructure:"archive"`
+	// Labels are the sensitivity classes dashboard snapshots can carry,
+	// keyed by name, e.g. "confidential". DefaultLabel is given to snapshots
+	// taken without one. Labels apply whether or not Enabled is set.
+	Labels       map[string]RetentionLabel `toml:"labels"        mapstructure:"labels"`
+	DefaultLabel string                    `toml:"default_label" mapstructure:"default_label"`
+}
+
+// RetentionLabel is a sensitivity and retention class for saved results.
+// Snapshots carrying it are deleted MaxAgeDays after they are taken (0 keeps
+// them), and with NoExport they can be viewed but not rendered to files.
+type RetentionLabel struct {
+	Description string `toml:"description"  mapstructure:"description"`
+	MaxAgeDays  int    `toml:"max_age_days" mapstructure:"max_age_days"`
+	NoExport    bool   `toml:"no_export"    mapstructure:"no_export"`
+}
+
+// RetentionPolicy prunes rows older than MaxAgeDays and beyond the newest
+// MaxRows. Zero disables either limit.
+type RetentionPolicy struct {
+	MaxAgeDays int `toml:"max_age_days" mapstructure:"max_age_days"`
+	MaxRows    int `toml:"max_rows"     mapstructure:"max_rows"`
+	// Archive exports pruned rows to the archive store before deleting them.
+	Archive bool `toml:"archive" mapstructure:"archive"`
+}
+
+// ArchiveConfig selects where pruned rows are exported.
+type ArchiveConfig struct {
+	Type string   `toml:"type" mapstructure:"type"` // dir | s3 (empty disables archiving)
+	Dir  string   `toml:"dir"  mapstructure:"dir"`
+	S3   S3Config `toml:"s3"   mapstructure:"s3"`
+}
+
+// S3Config addresses an S3-compatible bucket (AWS, MinIO, R2, ...).
+type S3Config struct {
+	Endpoint        string `toml:"endpoint"          mapstructure:"endpoint"` // defaults to AWS for the region
+	Region          string `toml:"region"            mapstructure:"region"`
+	Bucket          string `toml:"bucket"            mapstructure:"bucket"`
+	Prefix          string `toml:"prefix"            mapstructure:"prefix"`
+	AccessKeyID     string `toml:"access_key_id"     mapstructure:"access_key_id"`
+	SecretAccessKey string `toml:"secret_access_key" mapstructure:"secret_access_key"`
+	// PathStyle addresses the bucket as endpoint/bucket instead of
+	// bucket.endpoint, as MinIO and most self-hosted stores need.
+	PathStyle bool `toml:"path_style" mapstructure:"path_style"`
+}
+
+// TelemetryConfig controls opt-in anonymous usage reporting. Reports contain
+// only counters (datasource types, routes used, error classes) — never
+// queries, names, hosts or credentials.
+type TelemetryConfig struct {
+	Enabled  bool   `toml:"enabled"  mapstructure:"enabled"`
+	Endpoint string `toml:"endpoint" mapstructure:"endpoint"` // reports are not sent when empty
+	Interval int    `toml:"interval" mapstructure:"interval"` // hours between reports
 }
 
 // AIConfig holds AI provider configuration.
@@ -174,6 +535,46 @@ type ServerConfig struct {
 	ReadTimeout  int    `toml:"read_timeout"`
 	WriteTimeout int    `toml:"write_timeout"`
 	MaxBodySize  int64  `toml:"max_body_size"`
+	// ShutdownDelay is how long, in seconds, the server keeps serving after
+	// SIGTERM while reporting not-ready, so load balancers stop routing to it
+	// before connections are closed.
+	ShutdownDelay int `toml:"shutdown_delay" mapstructure:"shutdown_delay"`
+	// ShutdownTimeout bounds, in seconds, how long in-flight requests get to
+	// finish once draining starts.
+	ShutdownTimeout int `toml:"shutdown_timeout" mapstructure:"shutdown_timeout"`
+	// QueryTimeout caps, in seconds, how long any datasource query may run.
+	// Datasources and requests may set a shorter limit; 0 disables the cap.
+	QueryTimeout int `toml:"query_timeout" mapstructure:"query_timeout"`
+	// PreloadConcurrency is how many datasources tagged "preload" are warmed
+	// up at once during startup; 0 disables warm-up.
+	PreloadConcurrency int `toml:"preload_concurrency" mapstructure:"preload_concurrency"`
+	// PreloadTimeout bounds, in seconds, how long startup waits for warm-up;
+	// 0 waits until every datasource has answered or failed.
+	PreloadTimeout int `toml:"preload_timeout" mapstructure:"preload_timeout"`
+	// QueryMemoryLimit caps, in MiB, the estimated size of one query's
+	// result while it is read from the datasource; QueryMemoryTotal caps
+	// all results being read at once. 0 disables either limit.
+	QueryMemoryLimit int `toml:"query_memory_limit" mapstructure:"query_memory_limit"`
+	QueryMemoryTotal int `toml:"query_memory_total" mapstructure:"query_memory_total"`
+	// DefaultRowLimit is the LIMIT added to queries without one on
+	// datasources tagged "interactive", unless the user sets their own;
+	// 0 turns the injection off.
+	DefaultRowLimit int `toml:"default_row_limit" mapstructure:"default_row_limit"`
+	// ParameterPolicy decides what happens to queries from non-admin users
+	// that write literal values into their conditions instead of binding
+	// parameters: "off" runs them, "warn" runs them with a warning and
+	// records them for the admin report, "reject" refuses and records them.
+	// It covers datasource queries, diffs, embeds, webhook deliveries and
+	// dashboard panels.
+	ParameterPolicy string `toml:"parameter_policy" mapstructure:"parameter_policy"`
+	// Environment is the deployment stage this server runs as (dev,
+	// staging, prod), which datasource aliases in saved queries resolve in
+	// unless a request names another. Empty resolves to datasources with no
+	// environment label.
+	Environment string `toml:"environment" mapstructure:"environment"`
+	// PublicURL is the address users reach the server at, used for links in
+	// notifications. Empty leaves links relative.
+	PublicURL string `toml:"public_url" mapstructure:"public_url"`
 }
 
 // LoggingConfig represents logging configuration.
@@ -185,14 +586,79 @@ type LoggingConfig struct {
 
 // SecurityConfig represents security configuration.
 type SecurityConfig struct {
-	EnableCORS     bool     `toml:"enable_cors"`
-	AllowedOrigins []string `toml:"allowed_origins"`
-	AllowedMethods []string `toml:"allowed_methods"`
-	AllowedHeaders []string `toml:"allowed_headers"`
-	RateLimitRPS   int      `toml:"rate_limit_rps"`
-	EnableAuth     bool     `toml:"enable_auth"`
-	JWTSecret      string   `toml:"jwt_secret"`
-	SessionTimeout int      `toml:"session_timeout"`
+	EnableCORS     bool     `toml:"enable_cors"     mapstructure:"enable_cors"`
+	AllowedOrigins []string `toml:"allowed_origins" mapstructure:"allowed_origins"`
+	AllowedMethods []string `toml:"allowed_methods" mapstructure:"allowed_methods"`
+	AllowedHeaders []string `toml:"allowed_headers" mapstructure:"allowed_headers"`
+	RateLimitRPS   int      `toml:"rate_limit_rps"  mapstructure:"rate_limit_rps"`
+	EnableAuth     bool     `toml:"enable_auth"     mapstructure:"enable_auth"`
+	JWTSecret      string   `toml:"jwt_secret"      mapstructure:"jwt_secret"`
+	SessionTimeout int      `toml:"session_timeout" mapstructure:"session_timeout"` // seconds a login session lasts
+	InvitationTTL  int      `toml:"invitation_ttl"  mapstructure:"invitation_ttl"`  // hours an invitation link is valid
+	// UserHeader names the request header a trusted authenticating proxy
+	// sets to the caller's username. It is believed only from the peers
+	// in TrustedProxies; empty, the default, ignores it.
+	UserHeader string `toml:"user_header" mapstructure:"user_header"`
+	// TrustedProxies lists the proxies, as addresses or CIDRs, whose
+	// X-Forwarded-For header names the client. Without any the client is
+	// the peer address, so datasource network policies cannot be spoofed.
+	TrustedProxies []string `toml:"trusted_proxies" mapstructure:"trusted_proxies"`
+	// Password governs local account passwords.
+	Password PasswordPolicy `toml:"password" mapstructure:"password"`
+	// SCIM lets an identity provider provision users and groups.
+	SCIM SCIMConfig `toml:"scim" mapstructure:"scim"`
+	// Policies refine what roles allow, per endpoint and datasource. The
+	// first policy matching a request decides it; requests no policy
+	// matches are left to the roles.
+	Policies []PolicyConfig `toml:"policies" mapstructure:"policies"`
+}
+
+// PolicyConfig is one access rule. Every condition that is set must hold
+// for the rule to match; an empty list matches anything.
+type PolicyConfig struct {
+	Name   string `toml:"name"   mapstructure:"name"`
+	Effect string `toml:"effect" mapstructure:"effect"` // allow | deny
+	// Actions are "<resource>:<verb>" names such as "datasources:query";
+	// Endpoints are "METHOD /route/:param" templates. Both take * globs.
+	Actions   []string `toml:"actions"   mapstructure:"actions"`
+	Endpoints []string `toml:"endpoints" mapstructure:"endpoints"`
+	// Users (username globs), Roles and Groups (SCIM group names) select
+	// who the rule applies to.
+	Users  []string `toml:"users"  mapstructure:"users"`
+	Roles  []string `toml:"roles"  mapstructure:"roles"`
+	Groups []string `toml:"groups" mapstructure:"groups"`
+	// Datasource conditions only match requests addressing one datasource,
+	// either under /datasources/:uid or, as the action datasources:query,
+	// wherever another route runs a query on one. The datasource must carry
+	// every tag in DatasourceTags.
+	Datasources     []string `toml:"datasources"      mapstructure:"datasources"`
+	DatasourceTags  []string `toml:"datasource_tags"  mapstructure:"datasource_tags"`
+	DatasourceTypes []string `toml:"datasource_types" mapstructure:"datasource_types"`
+}
+
+// SCIMConfig configures the SCIM 2.0 provisioning endpoint.
+type SCIMConfig struct {
+	Enabled bool `toml:"enabled" mapstructure:"enabled"`
+	// Token is the bearer token the identity provider authenticates with.
+	Token string `toml:"token" mapstructure:"token"`
+	// DefaultRole is given to provisioned users in no mapped group.
+	DefaultRole string `toml:"default_role" mapstructure:"default_role"`
+	// GroupRoles maps SCIM group display names to Voyager roles. A user in
+	// several mapped groups gets the most privileged role.
+	GroupRoles map[string]string `toml:"group_roles" mapstructure:"group_roles"`
+}
+
+// PasswordPolicy sets complexity and lockout rules for local accounts.
+type PasswordPolicy struct {
+	MinLength     int  `toml:"min_length"     mapstructure:"min_length"`
+	RequireUpper  bool `toml:"require_upper"  mapstructure:"require_upper"`
+	RequireLower  bool `toml:"require_lower"  mapstructure:"require_lower"`
+	RequireDigit  bool `toml:"require_digit"  mapstructure:"require_digit"`
+	RequireSymbol bool `toml:"require_symbol" mapstructure:"require_symbol"`
+	// MaxFailedAttempts locks an account after that many consecutive failed
+	// logins; 0 disables lockout.
+	MaxFailedAttempts int `toml:"max_failed_attempts" mapstructure:"max_failed_attempts"`
+	LockoutMinutes    int `toml:"lockout_minutes"     mapstructure:"lockout_minutes"`
 }
 
 // Validate validates the configuration.
@@ -205,6 +671,17 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	switch c.Server.Environment {
+	case "", "dev", "staging", "prod":
+	default:
+		return fmt.Errorf("invalid server.environment: %s", c.Server.Environment)
+	}
+	switch c.Server.ParameterPolicy {
+	case "", "off", "warn", "reject":
+	default:
+		return fmt.Errorf("invalid server.parameter_policy: %s", c.Server.ParameterPolicy)
+	}
+
 	validLevels := map[string]bool{
 		"debug": true, "info": true, "warn": true, "error": true,
 	}
@@ -217,6 +694,72 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid log format: %s", c.Logging.Format)
 	}
 
+	if err := c.GitSync.Validate(); err != nil {
+		return err
+	}
+	if err := c.AsyncResults.Validate(); err != nil {
+		return err
+	}
+	if err := c.Retention.Validate(); err != nil {
+		return err
+	}
+	if err := c.Exports.Validate(); err != nil {
+		return err
+	}
+	if err := c.Frontend.Validate(); err != nil {
+		return err
+	}
+	if err := c.Webhooks.Validate(); err != nil {
+		return err
+	}
+	if err := c.Email.Validate(); err != nil {
+		return err
+	}
+	if _, err := c.Notifications.Networks(); err != nil {
+		return err
+	}
+	if err := c.Demo.Validate(); err != nil {
+		return err
+	}
+	if err := c.Rendering.Validate(); err != nil {
+		return err
+	}
+	if err := c.Updates.Validate(); err != nil {
+		return err
+	}
+	if err := c.CDC.Validate(); err != nil {
+		return err
+	}
+	if err := c.RowEditing.Validate(); err != nil {
+		return err
+	}
+	if err := c.AuditLog.Validate(); err != nil {
+		return err
+	}
+	if c.Warehouses.PreResume < 0 {
+		return fmt.Errorf("invalid warehouses.pre_resume: %d", c.Warehouses.PreResume)
+	}
+	for _, st := range c.CDC.Streams {
+		if st.Target != "" && !slices.ContainsFunc(c.Webhooks.Targets, func(t WebhookTarget) bool { return t.Name == st.Target }) {
+			return fmt.Errorf("cdc.streams.%s: unknown webhook target %q", st.Name, st.Target)
+		}
+	}
+	for _, t := range c.Webhooks.Targets {
+		if (t.Format == "png" || t.Format == "pdf") && c.Rendering.URL == "" {
+			return fmt.Errorf("webhooks.targets.%s: format %s needs rendering.url", t.Name, t.Format)
+		}
+	}
+
+	if err := validatePolicies(c.Security.Policies); err != nil {
+		return err
+	}
+	if c.Security.UserHeader != "" && len(c.Security.TrustedProxies) == 0 {
+		return fmt.Errorf("security.user_header needs security.trusted_proxies, the proxies allowed to set it")
+	}
+	if c.Security.SCIM.Enabled && c.Security.SCIM.Token == "" {
+		return fmt.Errorf("security.scim.token is required when SCIM is enabled")
+	}
+
 	return nil
 }
 
@@ -251,8 +794,377 @@ func (c *DBConfig) Validate() error {
 		}
 	case "":
 		return fmt.Errorf("metadata_store.type is required")
+	}
+	// Other types name pluggable backends, which check their own settings
+	// when opened.
+	return nil
+}
+
+// Validate checks that an enabled sync has somewhere to sync to.
+func (c *GitSyncConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Remote == "" || c.Branch == "" || c.Dir == "" {
+		return fmt.Errorf("gitsync.remote, branch and dir are required when gitsync is enabled")
+	}
+	if filepath.IsAbs(c.Path) || strings.HasPrefix(filepath.Clean(c.Path), "..") {
+		return fmt.Errorf("gitsync.path must be relative to the repository root")
+	}
+	return nil
+}
+
+// Validate validates the retention policies and archive store.
+func (c *RetentionConfig) Validate() error {
+	for name, p := range c.Policies {
+		if p.MaxAgeDays < 0 || p.MaxRows < 0 {
+			return fmt.Errorf("retention.policies.%s: limits must not be negative", name)
+		}
+		if p.Archive && c.Archive.Type == "" {
+			return fmt.Errorf("retention.policies.%s: archive requires retention.archive.type", name)
+		}
+	}
+	for name, l := range c.Labels {
+		if l.MaxAgeDays < 0 {
+			return fmt.Errorf("retention.labels.%s: max_age_days must not be negative", name)
+		}
+	}
+	if _, ok := c.Labels[c.DefaultLabel]; c.DefaultLabel != "" && !ok {
+		return fmt.Errorf("retention.default_label %q is not one of retention.labels", c.DefaultLabel)
+	}
+	return c.Archive.validate("retention.archive")
+}
+
+// Validate checks the sender and transport when email is on.
+func (c *EmailConfig) Validate() error {
+	if c.Host == "" {
+		return nil
+	}
+	if _, err := mail.ParseAddress(c.From); err != nil {
+		return fmt.Errorf("email.from: %w", err)
+	}
+	switch c.TLS {
+	case "", "starttls", "tls", "none":
+	default:
+		return fmt.Errorf("invalid email.tls: %s", c.TLS)
+	}
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("invalid email.port: %d", c.Port)
+	}
+	return nil
+}
+
+// Validate checks the rendering service URL when one is set.
+func (c *RenderingConfig) Validate() error {
+	if c.URL != "" {
+		u, err := url.Parse(c.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("rendering.url must be an http or https URL")
+		}
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("invalid rendering.timeout: %d", c.Timeout)
+	}
+	return nil
+}
+
+// validatePolicies checks that policies are named uniquely and have an
+// effect.
+func validatePolicies(policies []PolicyConfig) error {
+	names := make(map[string]bool, len(policies))
+	for i, p := range policies {
+		if p.Name == "" {
+			return fmt.Errorf("security.policies[%d]: name is required", i)
+		}
+		if names[p.Name] {
+			return fmt.Errorf("security.policies: duplicate name %q", p.Name)
+		}
+		names[p.Name] = true
+		if p.Effect != "allow" && p.Effect != "deny" {
+			return fmt.Errorf("security.policies.%s: effect must be allow or deny", p.Name)
+		}
+	}
+	return nil
+}
+
+// Validate checks the release source and the signing key.
+func (c *UpdatesConfig) Validate() error {
+	if c.Interval < 0 {
+		return fmt.Errorf("invalid updates.interval: %d", c.Interval)
+	}
+	if c.APIURL != "" {
+		u, err := url.Parse(c.APIURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("updates.api_url must be an http or https URL")
+		}
+	}
+	if c.Repository != "" && strings.Count(c.Repository, "/") != 1 {
+		return fmt.Errorf("updates.repository must be owner/name: %s", c.Repository)
+	}
+	if c.PublicKey != "" {
+		key, err := base64.StdEncoding.DecodeString(c.PublicKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return fmt.Errorf("updates.public_key must be a base64 Ed25519 public key")
+		}
+	}
+	if c.AllowApply {
+		if c.PublicKey == "" {
+			return fmt.Errorf("updates.allow_apply requires updates.public_key")
+		}
+		if c.APIURL != "" && !strings.HasPrefix(strings.ToLower(c.APIURL), "https://") {
+			return fmt.Errorf("updates.allow_apply requires an https updates.api_url")
+		}
+	}
+	return nil
+}
+
+// Validate checks that an enabled demo shows something and bounds its rows.
+func (c *DemoConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if len(c.Datasources) == 0 {
+		return fmt.Errorf("demo.datasources is required when the demo is enabled")
+	}
+	if c.RowLimit <= 0 {
+		return fmt.Errorf("invalid demo.row_limit: %d", c.RowLimit)
+	}
+	return nil
+}
+
+// Validate checks that targets are reachable URLs with unique names and that
+// every schedule names a known target.
+func (c *WebhooksConfig) Validate() error {
+	targets := make(map[string]bool, len(c.Targets))
+	for i, t := range c.Targets {
+		if t.Name == "" {
+			return fmt.Errorf("webhooks.targets[%d]: name is required", i)
+		}
+		if targets[t.Name] {
+			return fmt.Errorf("webhooks.targets: duplicate name %q", t.Name)
+		}
+		targets[t.Name] = true
+		u, err := url.Parse(t.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhooks.targets.%s: url must be an http or https URL", t.Name)
+		}
+		switch t.Format {
+		case "", "json", "csv", "html", "png", "pdf":
+		default:
+			return fmt.Errorf("webhooks.targets.%s: invalid format: %s", t.Name, t.Format)
+		}
+		if t.Timeout < 0 || t.MaxAttempts < 0 {
+			return fmt.Errorf("webhooks.targets.%s: timeout and max_attempts must not be negative", t.Name)
+		}
+	}
+	schedules := make(map[string]bool, len(c.Schedules))
+	for i, s := range c.Schedules {
+		if s.Name == "" {
+			return fmt.Errorf("webhooks.schedules[%d]: name is required", i)
+		}
+		if schedules[s.Name] {
+			return fmt.Errorf("webhooks.schedules: duplicate name %q", s.Name)
+		}
+		schedules[s.Name] = true
+		if !targets[s.Target] {
+			return fmt.Errorf("webhooks.schedules.%s: unknown target %q", s.Name, s.Target)
+		}
+		if s.DatasourceID == "" || s.Query == "" {
+			return fmt.Errorf("webhooks.schedules.%s: datasource_id and query are required", s.Name)
+		}
+		if s.Interval <= 0 {
+			return fmt.Errorf("webhooks.schedules.%s: interval must be positive", s.Name)
+		}
+	}
+	return nil
+}
+
+// Validate checks each table has a unique name, a location and a key.
+func (c *RowEditingConfig) Validate() error {
+	names := make(map[string]bool, len(c.Tables))
+	for i, t := range c.Tables {
+		if t.Name == "" {
+			return fmt.Errorf("row_editing.tables[%d]: name is required", i)
+		}
+		if names[t.Name] {
+			return fmt.Errorf("row_editing.tables: duplicate name %q", t.Name)
+		}
+		names[t.Name] = true
+		if t.DatasourceID == "" || t.Table == "" {
+			return fmt.Errorf("row_editing.tables.%s: datasource_id and table are required", t.Name)
+		}
+		if len(t.Key) == 0 {
+			return fmt.Errorf("row_editing.tables.%s: key is required", t.Name)
+		}
+		for _, k := range t.Key {
+			if slices.Contains(t.Columns, k) {
+				return fmt.Errorf("row_editing.tables.%s: key column %s cannot be listed as editable", t.Name, k)
+			}
+		}
+	}
+	return nil
+}
+
+// auditLogEvents are the event types an audit log sink may select.
+var auditLogEvents = []string{"query", "export", "row_edit", "impersonation"}
+
+// Validate checks each sink has a unique name, a known type and the
+// settings its type needs.
+func (c *AuditLogConfig) Validate() error {
+	if c.BatchSize < 0 || c.FlushInterval < 0 {
+		return fmt.Errorf("audit_log.batch_size and audit_log.flush_interval must not be negative")
+	}
+	names := make(map[string]bool, len(c.Sinks))
+	for i, s := range c.Sinks {
+		if s.Name == "" {
+			return fmt.Errorf("audit_log.sinks[%d]: name is required", i)
+		}
+		if names[s.Name] {
+			return fmt.Errorf("audit_log.sinks: duplicate name %q", s.Name)
+		}
+		names[s.Name] = true
+		for _, e := range s.Events {
+			if !slices.Contains(auditLogEvents, e) {
+				return fmt.Errorf("audit_log.sinks.%s: unknown event type %q (want one of %s)", s.Name, e, strings.Join(auditLogEvents, ", "))
+			}
+		}
+		switch s.Type {
+		case AuditLogKafka:
+			if len(s.Kafka.Brokers) == 0 || s.Kafka.Topic == "" {
+				return fmt.Errorf("audit_log.sinks.%s: kafka.brokers and kafka.topic are required", s.Name)
+			}
+			switch strings.ToUpper(s.Kafka.SASLMechanism) {
+			case "":
+			case "PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512":
+				if s.Kafka.Username == "" {
+					return fmt.Errorf("audit_log.sinks.%s: kafka.username is required for SASL", s.Name)
+				}
+			default:
+				return fmt.Errorf("audit_log.sinks.%s: invalid kafka.sasl_mechanism %q", s.Name, s.Kafka.SASLMechanism)
+			}
+		case AuditLogLoki:
+			u, err := url.Parse(s.Loki.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("audit_log.sinks.%s: loki.url must be an http or https URL", s.Name)
+			}
+			if s.Loki.Timeout < 0 {
+				return fmt.Errorf("audit_log.sinks.%s: invalid loki.timeout: %d", s.Name, s.Loki.Timeout)
+			}
+		case AuditLogS3:
+			if s.S3.Bucket == "" {
+				return fmt.Errorf("audit_log.sinks.%s: s3.bucket is required", s.Name)
+			}
+		default:
+			return fmt.Errorf("audit_log.sinks.%s: invalid type %q (want kafka, loki or s3)", s.Name, s.Type)
+		}
+	}
+	return nil
+}
+
+// slotNameRe matches what PostgreSQL accepts as a replication slot name.
+var slotNameRe = regexp.MustCompile(`^[a-z0-9_]{1,63}$`)
+
+// Validate checks each stream has a unique, slot-safe name, a source and
+// somewhere to send its changes.
+func (c *CDCConfig) Validate() error {
+	if c.Interval < 0 {
+		return fmt.Errorf("invalid cdc.interval: %d", c.Interval)
+	}
+	names := make(map[string]bool, len(c.Streams))
+	slots := make(map[string]bool, len(c.Streams))
+	for i, s := range c.Streams {
+		if !slotNameRe.MatchString(s.Name) {
+			return fmt.Errorf("cdc.streams[%d]: name must be lower case letters, digits and underscores", i)
+		}
+		if names[s.Name] {
+			return fmt.Errorf("cdc.streams: duplicate name %q", s.Name)
+		}
+		names[s.Name] = true
+		slot := s.Slot
+		if slot == "" {
+			slot = "dv_cdc_" + s.Name
+		}
+		if !slotNameRe.MatchString(slot) {
+			return fmt.Errorf("cdc.streams.%s: invalid slot name %q", s.Name, slot)
+		}
+		if slots[slot] {
+			return fmt.Errorf("cdc.streams.%s: slot %q is used by another stream", s.Name, slot)
+		}
+		slots[slot] = true
+		if s.DatasourceID == "" || s.Publication == "" {
+			return fmt.Errorf("cdc.streams.%s: datasource_id and publication are required", s.Name)
+		}
+		// A webhook delivery carries at most 10000 rows.
+		if s.BatchSize < 0 || s.BatchSize > 10000 {
+			return fmt.Errorf("cdc.streams.%s: batch_size must be between 1 and 10000", s.Name)
+		}
+		if (s.MaterializeDatasourceID == "") != (s.MaterializeTable == "") {
+			return fmt.Errorf("cdc.streams.%s: materialize_datasource_id and materialize_table go together", s.Name)
+		}
+		if s.Target == "" && s.MaterializeDatasourceID == "" {
+			return fmt.Errorf("cdc.streams.%s: set a target, a materialize table or both", s.Name)
+		}
+	}
+	return nil
+}
+
+// Validate validates the spill settings and archive store.
+func (c *AsyncResultsConfig) Validate() error {
+	if c.SpillThreshold < 0 {
+		return fmt.Errorf("async_results.spill_threshold must not be negative")
+	}
+	if c.TTL <= 0 {
+		return fmt.Errorf("async_results.ttl must be positive")
+	}
+	return c.Archive.validate("async_results.archive")
+}
+
+// Validate validates the export settings.
+func (c *ExportsConfig) Validate() error {
+	if c.Dir == "" {
+		return fmt.Errorf("exports.dir is required")
+	}
+	if c.ChunkSize <= 0 {
+		return fmt.Errorf("exports.chunk_size must be positive")
+	}
+	if c.TTL <= 0 {
+		return fmt.Errorf("exports.ttl must be positive")
+	}
+	if c.Watermark != strings.TrimSpace(c.Watermark) {
+		return fmt.Errorf("exports.watermark must not start or end with spaces")
+	}
+	return nil
+}
+
+// Validate validates the frontend mode.
+func (c *FrontendConfig) Validate() error {
+	switch c.Mode {
+	case FrontendEmbedded, FrontendNone:
+	case FrontendDir:
+		if c.Dir == "" {
+			return fmt.Errorf("frontend.dir is required when frontend.mode is %q", FrontendDir)
+		}
+	default:
+		return fmt.Errorf("unsupported frontend.mode: %s", c.Mode)
+	}
+	return nil
+}
+
+// validate checks the store settings; section names the config table for
+// error messages.
+func (c *ArchiveConfig) validate(section string) error {
+	switch c.Type {
+	case "":
+	case "dir":
+		if c.Dir == "" {
+			return fmt.Errorf("%s.dir is required", section)
+		}
+	case "s3":
+		if c.S3.Bucket == "" || c.S3.Region == "" {
+			return fmt.Errorf("%s.s3.bucket and region are required", section)
+		}
 	default:
-		return fmt.Errorf("unsupported metadata_store.type: %s", c.Type)
+		return fmt.Errorf("unsupported %s.type: %s", section, c.Type)
 	}
 	return nil
 }
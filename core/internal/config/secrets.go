@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// filePrefix marks a config value that should be read from a file, e.g.
+// password = "file:/var/run/secrets/db/password". This lets Kubernetes
+// secrets be mounted as files instead of being copied into the config or
+// the environment.
+const filePrefix = "file:"
+
+// secretFields lists the settings that may use a file: reference. Paths are
+// deliberately not included: "file:" is a valid SQLite DSN prefix.
+func (c *ViperConfig) secretFields() map[string]*string {
+	fields := map[string]*string{
+		"metadata_store.postgresql.user":         &c.MetadataStore.PostgreSQL.User,
+		"metadata_store.postgresql.password":     &c.MetadataStore.PostgreSQL.Password,
+		"metadata_store.mysql.user":              &c.MetadataStore.MySQL.User,
+		"metadata_store.mysql.password":          &c.MetadataStore.MySQL.Password,
+		"statistics_store.postgresql.user":       &c.StatisticsStore.PostgreSQL.User,
+		"statistics_store.postgresql.password":   &c.StatisticsStore.PostgreSQL.Password,
+		"statistics_store.mysql.user":            &c.StatisticsStore.MySQL.User,
+		"statistics_store.mysql.password":        &c.StatisticsStore.MySQL.Password,
+		"statistics_store.clickhouse.username":   &c.StatisticsStore.ClickHouse.Username,
+		"statistics_store.clickhouse.password":   &c.StatisticsStore.ClickHouse.Password,
+		"security.jwt_secret":                    &c.Security.JWTSecret,
+		"security.scim.token":                    &c.Security.SCIM.Token,
+		"ai.claude.api_key":                      &c.AI.Claude.APIKey,
+		"ai.openai.api_key":                      &c.AI.OpenAI.APIKey,
+		"ai.copilot.api_key":                     &c.AI.Copilot.APIKey,
+		"retention.archive.s3.access_key_id":     &c.Retention.Archive.S3.AccessKeyID,
+		"retention.archive.s3.secret_access_key": &c.Retention.Archive.S3.SecretAccessKey,
+		"email.username":                         &c.Email.Username,
+		"email.password":                         &c.Email.Password,
+	}
+	for i := range c.Webhooks.Targets {
+		fields[fmt.Sprintf("webhooks.targets[%d].secret", i)] = &c.Webhooks.Targets[i].Secret
+	}
+	for i := range c.AuditLog.Sinks {
+		s := &c.AuditLog.Sinks[i]
+		fields[fmt.Sprintf("audit_log.sinks[%d].kafka.password", i)] = &s.Kafka.Password
+		fields[fmt.Sprintf("audit_log.sinks[%d].loki.password", i)] = &s.Loki.Password
+		fields[fmt.Sprintf("audit_log.sinks[%d].s3.secret_access_key", i)] = &s.S3.SecretAccessKey
+	}
+	return fields
+}
+
+// ResolveFileRefs replaces file: references in secret settings with the
+// referenced file's contents, minus trailing newlines.
+func (c *ViperConfig) ResolveFileRefs() error {
+	for key, field := range c.secretFields() {
+		path, ok := strings.CutPrefix(*field, filePrefix)
+		if !ok {
+			continue
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("%s: read secret file: %w", key, err)
+		}
+		*field = strings.TrimRight(string(b), "\r\n")
+	}
+	return nil
+}
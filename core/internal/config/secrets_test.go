@@ -0,0 +1,34 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveFileRefs(t *testing.T) {
+	dir := t.TempDir()
+	pwFile := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(pwFile, []byte("s3cret\n"), 0o600))
+
+	cfg := &ViperConfig{}
+	cfg.MetadataStore.SQLite.Path = "file:voyager.db?cache=shared"
+	cfg.MetadataStore.PostgreSQL.Password = "file:" + pwFile
+	cfg.Security.JWTSecret = "inline-secret"
+
+	require.NoError(t, cfg.ResolveFileRefs())
+	assert.Equal(t, "s3cret", cfg.MetadataStore.PostgreSQL.Password)
+	assert.Equal(t, "inline-secret", cfg.Security.JWTSecret)
+	assert.Equal(t, "file:voyager.db?cache=shared", cfg.MetadataStore.SQLite.Path, "paths are not secret fields")
+}
+
+func TestResolveFileRefs_MissingFile(t *testing.T) {
+	cfg := &ViperConfig{}
+	cfg.Security.JWTSecret = "file:/nonexistent/jwt"
+	err := cfg.ResolveFileRefs()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "security.jwt_secret")
+}
@@ -16,6 +16,26 @@ type ViperConfig struct {
 	Logging         LoggingConfig         `mapstructure:"logging"`
 	Security        SecurityConfig        `mapstructure:"security"`
 	AI              AIConfig              `mapstructure:"ai"`
+	Telemetry       TelemetryConfig       `mapstructure:"telemetry"`
+	Retention       RetentionConfig       `mapstructure:"retention"`
+	GitSync         GitSyncConfig         `mapstructure:"gitsync"`
+	AsyncResults    AsyncResultsConfig    `mapstructure:"async_results"`
+	Exports         ExportsConfig         `mapstructure:"exports"`
+	Frontend        FrontendConfig        `mapstructure:"frontend"`
+	Webhooks        WebhooksConfig        `mapstructure:"webhooks"`
+	Email           EmailConfig           `mapstructure:"email"`
+	Notifications   NotificationsConfig   `mapstructure:"notifications"`
+	Demo            DemoConfig            `mapstructure:"demo"`
+	Rendering       RenderingConfig       `mapstructure:"rendering"`
+	Updates         UpdatesConfig         `mapstructure:"updates"`
+	CDC             CDCConfig             `mapstructure:"cdc"`
+	RowEditing      RowEditingConfig      `mapstructure:"row_editing"`
+	AuditLog        AuditLogConfig        `mapstructure:"audit_log"`
+	Warehouses      WarehousesConfig      `mapstructure:"warehouses"`
+
+	// ConfigFile is the path the configuration was read from; empty when no
+	// file was found and only defaults and environment variables apply.
+	ConfigFile string `mapstructure:"-"`
 }
 
 // InitViper initializes Viper configuration.
@@ -51,6 +71,12 @@ func InitViper(configName, configPath string) (*ViperConfig, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	cfg.ConfigFile = v.ConfigFileUsed()
+
+	if err := cfg.ResolveFileRefs(); err != nil {
+		return nil, fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
@@ -58,15 +84,37 @@ func InitViper(configName, configPath string) (*ViperConfig, error) {
 	return &cfg, nil
 }
 
+// Defaults returns the configuration used when no file or environment
+// variable sets anything.
+func Defaults() (*ViperConfig, error) {
+	v := viper.New()
+	setDefaults(v)
+	var cfg ViperConfig
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	return &cfg, nil
+}
+
 func setDefaults(v *viper.Viper) {
 	v.SetDefault("server.host", "localhost")
 	v.SetDefault("server.port", 8080)
 	v.SetDefault("server.read_timeout", 30)
 	v.SetDefault("server.write_timeout", 30)
 	v.SetDefault("server.max_body_size", 10*1024*1024)
+	v.SetDefault("server.shutdown_delay", 5)
+	v.SetDefault("server.shutdown_timeout", 30)
+	v.SetDefault("server.query_timeout", 300)
+	v.SetDefault("server.preload_concurrency", 4)
+	v.SetDefault("server.preload_timeout", 60)
+	v.SetDefault("server.query_memory_limit", 512)
+	v.SetDefault("server.query_memory_total", 2048)
+	v.SetDefault("server.default_row_limit", 10000)
+	v.SetDefault("server.parameter_policy", "off")
 
 	v.SetDefault("metadata_store.type", "sqlite")
 	v.SetDefault("metadata_store.migrate_on_start", true)
+	v.SetDefault("metadata_store.migration_backup", true)
 	v.SetDefault("metadata_store.sqlite.path", "./data/voyager.db")
 	v.SetDefault("metadata_store.postgresql.port", 5432)
 	v.SetDefault("metadata_store.postgresql.ssl_mode", "disable")
@@ -83,6 +131,60 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("security.rate_limit_rps", 100)
 	v.SetDefault("security.enable_auth", false)
 	v.SetDefault("security.session_timeout", 3600)
+	v.SetDefault("security.invitation_ttl", 72)
+	v.SetDefault("security.user_header", "")
+	v.SetDefault("security.password.min_length", 12)
+	v.SetDefault("security.password.require_upper", true)
+	v.SetDefault("security.password.require_lower", true)
+	v.SetDefault("security.password.require_digit", true)
+	v.SetDefault("security.password.max_failed_attempts", 5)
+	v.SetDefault("security.password.lockout_minutes", 15)
+	v.SetDefault("security.scim.default_role", "viewer")
+
+	v.SetDefault("telemetry.enabled", false)
+	v.SetDefault("telemetry.interval", 24)
+
+	v.SetDefault("retention.enabled", false)
+	v.SetDefault("retention.interval", 60)
+	v.SetDefault("retention.batch_size", 5000)
+
+	v.SetDefault("gitsync.enabled", false)
+	v.SetDefault("gitsync.branch", "main")
+	v.SetDefault("gitsync.dir", "./data/gitsync")
+	v.SetDefault("gitsync.author_name", "Data Voyager")
+	v.SetDefault("gitsync.author_email", "data-voyager@localhost")
+
+	v.SetDefault("async_results.spill_threshold", 1024)
+	v.SetDefault("async_results.ttl", 1440)
+	v.SetDefault("async_results.archive.type", "dir")
+	v.SetDefault("async_results.archive.dir", "./data/results")
+
+	v.SetDefault("exports.dir", "./data/exports")
+	v.SetDefault("exports.chunk_size", 10000)
+	v.SetDefault("exports.ttl", 72)
+
+	v.SetDefault("frontend.mode", FrontendEmbedded)
+
+	v.SetDefault("email.port", 587)
+	v.SetDefault("email.tls", "starttls")
+	v.SetDefault("email.timeout", 30)
+
+	v.SetDefault("demo.row_limit", 100)
+
+	v.SetDefault("rendering.timeout", 60)
+
+	v.SetDefault("updates.check", false)
+	v.SetDefault("updates.interval", 24)
+	v.SetDefault("updates.repository", "loykin/data-voyager")
+	v.SetDefault("updates.api_url", "https://api.github.com")
+
+	v.SetDefault("cdc.enabled", false)
+	v.SetDefault("cdc.interval", 5)
+
+	v.SetDefault("audit_log.batch_size", 500)
+	v.SetDefault("audit_log.flush_interval", 10)
+
+	v.SetDefault("warehouses.pre_resume", 0)
 }
 
 // Validate validates the Viper configuration.
@@ -98,6 +200,23 @@ func (c *ViperConfig) Config() *Config {
 		StatisticsStore: c.StatisticsStore,
 		Logging:         c.Logging,
 		Security:        c.Security,
+		AI:              c.AI,
+		Telemetry:       c.Telemetry,
+		Retention:       c.Retention,
+		GitSync:         c.GitSync,
+		AsyncResults:    c.AsyncResults,
+		Exports:         c.Exports,
+		Frontend:        c.Frontend,
+		Webhooks:        c.Webhooks,
+		Email:           c.Email,
+		Notifications:   c.Notifications,
+		Demo:            c.Demo,
+		Rendering:       c.Rendering,
+		Updates:         c.Updates,
+		CDC:             c.CDC,
+		RowEditing:      c.RowEditing,
+		AuditLog:        c.AuditLog,
+		Warehouses:      c.Warehouses,
 	}
 }
 
@@ -0,0 +1,34 @@
+package configupgrade
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/user"
+)
+
+// Handler serves the config issue report.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates a config upgrade HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// ListIssues handles GET /admin/config-issues
+func (h *Handler) ListIssues(c *gin.Context) {
+	issues, err := h.svc.Issues(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": issues})
+}
+
+// RegisterRoutes wires the handler onto r.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/admin/config-issues", user.RequireAdmin, h.ListIssues)
+}
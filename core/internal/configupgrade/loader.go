@@ -0,0 +1,36 @@
+package configupgrade
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	apploader "data-voyager/core/internal/app"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+)
+
+type loader struct {
+	svc *Service
+}
+
+// NewLoader upgrades stored datasource configs at startup and serves the
+// report of those needing manual attention.
+func NewLoader(conns connection.Repository, registry *datasource.Registry) apploader.Loader {
+	return &loader{svc: NewService(conns, registry)}
+}
+
+// Load upgrades what it can. Configs that cannot be upgraded are logged and
+// reported, not fatal: the rest of the server stays usable.
+func (l *loader) Load() error {
+	upgraded, issues, err := l.svc.Upgrade(context.Background())
+	if err != nil {
+		return err
+	}
+	logResult(upgraded, issues)
+	return nil
+}
+
+func (l *loader) RegisterRoutes(r *gin.RouterGroup) {
+	RegisterRoutes(r, NewHandler(l.svc))
+}
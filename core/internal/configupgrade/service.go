@@ -0,0 +1,162 @@
+// Package configupgrade brings stored datasource configs up to the config
+// version their plugin currently writes, and reports the ones it cannot.
+package configupgrade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/sdk"
+)
+
+// IssueKind classifies why a stored config needs manual attention.
+type IssueKind string
+
+const (
+	// IssueUnknownType means no installed plugin handles the datasource type.
+	IssueUnknownType IssueKind = "unknown_type"
+	// IssueNewerVersion means the config was written by a newer plugin than
+	// the one installed, typically after a downgrade.
+	IssueNewerVersion IssueKind = "newer_version"
+	// IssueUpgradeFailed means a plugin upgrade step returned an error.
+	IssueUpgradeFailed IssueKind = "upgrade_failed"
+	// IssueInvalidConfig means the (upgraded) config fails plugin validation.
+	IssueInvalidConfig IssueKind = "invalid_config"
+)
+
+// Issue is a datasource whose config could not be brought to the current
+// version automatically.
+type Issue struct {
+	DatasourceID   string             `json:"datasource_id"`
+	Name           string             `json:"name"`
+	Type           sdk.DataSourceType `json:"type"`
+	ConfigVersion  int                `json:"config_version"`
+	CurrentVersion int                `json:"current_version"`
+	Kind           IssueKind          `json:"kind"`
+	Message        string             `json:"message"`
+}
+
+// Service upgrades and checks stored datasource configs.
+type Service struct {
+	conns    connection.Repository
+	registry *datasource.Registry
+}
+
+// NewService creates a Service.
+func NewService(conns connection.Repository, registry *datasource.Registry) *Service {
+	return &Service{conns: conns, registry: registry}
+}
+
+// Upgrade rewrites every stored config that is behind its plugin's config
+// version and returns the datasources that still need attention. It is run
+// once at startup; a failure on one datasource never blocks the others.
+func (s *Service) Upgrade(ctx context.Context) (upgraded int, issues []Issue, err error) {
+	conns, err := s.conns.List(ctx, connection.Filter{})
+	if err != nil {
+		return 0, nil, fmt.Errorf("list datasources: %w", err)
+	}
+	for _, conn := range conns {
+		cfg, version, issue := s.inspect(conn)
+		if issue != nil {
+			issues = append(issues, *issue)
+			continue
+		}
+		if version == conn.ConfigVersion {
+			continue
+		}
+		conn.Config, conn.ConfigVersion = cfg, version
+		if err := s.conns.Update(ctx, conn); err != nil {
+			issues = append(issues, newIssue(conn, version, IssueUpgradeFailed, fmt.Sprintf("save upgraded config: %v", err)))
+			continue
+		}
+		upgraded++
+	}
+	return upgraded, issues, nil
+}
+
+// Issues reports the stored configs that need manual attention without
+// changing anything.
+func (s *Service) Issues(ctx context.Context) ([]Issue, error) {
+	conns, err := s.conns.List(ctx, connection.Filter{})
+	if err != nil {
+		return nil, fmt.Errorf("list datasources: %w", err)
+	}
+	issues := []Issue{}
+	for _, conn := range conns {
+		if _, _, issue := s.inspect(conn); issue != nil {
+			issues = append(issues, *issue)
+		}
+	}
+	return issues, nil
+}
+
+// inspect returns conn's config upgraded to the plugin's current version and
+// that version, or the Issue preventing it.
+func (s *Service) inspect(conn *connection.Connection) (json.RawMessage, int, *Issue) {
+	plugin, ok := s.registry.Get(conn.Type)
+	if !ok {
+		issue := newIssue(conn, 0, IssueUnknownType, fmt.Sprintf("no plugin installed for type %q", conn.Type))
+		return nil, 0, &issue
+	}
+	current := sdk.PluginConfigVersion(plugin)
+	if conn.ConfigVersion > current {
+		issue := newIssue(conn, current, IssueNewerVersion,
+			fmt.Sprintf("config version %d is newer than the installed plugin supports (%d)", conn.ConfigVersion, current))
+		return nil, 0, &issue
+	}
+
+	cfg := conn.Config
+	for v := conn.ConfigVersion; v < current; v++ {
+		next, err := plugin.(sdk.ConfigUpgrader).UpgradeConfig(v, cfg)
+		if err != nil {
+			issue := newIssue(conn, current, IssueUpgradeFailed, err.Error())
+			return nil, 0, &issue
+		}
+		cfg = next
+	}
+
+	if err := validate(plugin, cfg); err != nil {
+		issue := newIssue(conn, current, IssueInvalidConfig, err.Error())
+		return nil, 0, &issue
+	}
+	return cfg, current, nil
+}
+
+func validate(plugin sdk.DatasourcePlugin, data json.RawMessage) error {
+	parsed, err := plugin.ParseConfig(data)
+	if err != nil {
+		return err
+	}
+	if err := plugin.ValidateConfig(parsed); err != nil {
+		return errors.Join(errors.New("config no longer validates"), err)
+	}
+	return nil
+}
+
+func newIssue(conn *connection.Connection, current int, kind IssueKind, msg string) Issue {
+	return Issue{
+		DatasourceID:   conn.ID,
+		Name:           conn.Name,
+		Type:           conn.Type,
+		ConfigVersion:  conn.ConfigVersion,
+		CurrentVersion: current,
+		Kind:           kind,
+		Message:        msg,
+	}
+}
+
+// logResult reports the outcome of a startup Upgrade.
+func logResult(upgraded int, issues []Issue) {
+	if upgraded > 0 {
+		slog.Info("upgraded datasource configs", "count", upgraded)
+	}
+	for _, is := range issues {
+		slog.Warn("datasource config needs attention",
+			"datasource", is.Name, "id", is.DatasourceID, "kind", is.Kind, "error", is.Message)
+	}
+}
@@ -0,0 +1,117 @@
+package configupgrade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/sdk"
+	"data-voyager/sdk/pluginsdk"
+)
+
+type stubConns struct {
+	connection.Repository
+	conns   []*connection.Connection
+	updated []string
+}
+
+func (s *stubConns) List(context.Context, connection.Filter) ([]*connection.Connection, error) {
+	return s.conns, nil
+}
+
+func (s *stubConns) Update(_ context.Context, c *connection.Connection) error {
+	s.updated = append(s.updated, c.ID)
+	return nil
+}
+
+type stubConfig struct{ Host string }
+
+func (stubConfig) Validate() error             { return nil }
+func (stubConfig) GetConnectionString() string { return "" }
+
+// versionedPlugin is at config v2: v1 defaulted "port", v2 renamed "hostname"
+// to "host". Configs without a host fail validation.
+type versionedPlugin struct {
+	sdk.DatasourcePlugin
+	pluginsdk.ConfigSteps
+}
+
+func newVersionedPlugin() *versionedPlugin {
+	return &versionedPlugin{ConfigSteps: pluginsdk.ConfigSteps{
+		func(cfg map[string]any) error { pluginsdk.SetDefault(cfg, "port", 9000); return nil },
+		func(cfg map[string]any) error { pluginsdk.RenameKey(cfg, "hostname", "host"); return nil },
+	}}
+}
+
+func (p *versionedPlugin) GetType() sdk.DataSourceType { return "versioned" }
+func (p *versionedPlugin) GetName() string             { return "Versioned" }
+func (p *versionedPlugin) ParseConfig(data json.RawMessage) (sdk.ConnectionConfig, error) {
+	var cfg stubConfig
+	err := json.Unmarshal(data, &cfg)
+	return cfg, err
+}
+func (p *versionedPlugin) ValidateConfig(cfg any) error {
+	if cfg.(stubConfig).Host == "" {
+		return errors.New("host is required")
+	}
+	return nil
+}
+
+func newService(conns ...*connection.Connection) (*Service, *stubConns) {
+	repo := &stubConns{conns: conns}
+	reg := datasource.NewRegistry()
+	reg.Register(newVersionedPlugin())
+	return NewService(repo, reg), repo
+}
+
+func TestUpgrade_RewritesOldConfigs(t *testing.T) {
+	old := &connection.Connection{ID: "a", Type: "versioned", Config: json.RawMessage(`{"hostname":"db"}`)}
+	current := &connection.Connection{ID: "b", Type: "versioned", ConfigVersion: 2, Config: json.RawMessage(`{"host":"db","port":1}`)}
+	svc, repo := newService(old, current)
+
+	upgraded, issues, err := svc.Upgrade(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+	assert.Equal(t, 1, upgraded)
+	assert.Equal(t, []string{"a"}, repo.updated)
+	assert.Equal(t, 2, old.ConfigVersion)
+	assert.JSONEq(t, `{"host":"db","port":9000}`, string(old.Config))
+}
+
+func TestUpgrade_ReportsIssues(t *testing.T) {
+	svc, repo := newService(
+		&connection.Connection{ID: "missing", Type: "gone", Config: json.RawMessage(`{}`)},
+		&connection.Connection{ID: "future", Type: "versioned", ConfigVersion: 3, Config: json.RawMessage(`{"host":"db"}`)},
+		&connection.Connection{ID: "invalid", Type: "versioned", Config: json.RawMessage(`{"port":1}`)},
+	)
+
+	upgraded, issues, err := svc.Upgrade(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, upgraded)
+	assert.Empty(t, repo.updated, "configs with issues must be left untouched")
+
+	kinds := map[string]IssueKind{}
+	for _, is := range issues {
+		kinds[is.DatasourceID] = is.Kind
+	}
+	assert.Equal(t, map[string]IssueKind{
+		"missing": IssueUnknownType,
+		"future":  IssueNewerVersion,
+		"invalid": IssueInvalidConfig,
+	}, kinds)
+}
+
+func TestIssues_DoesNotWrite(t *testing.T) {
+	svc, repo := newService(&connection.Connection{ID: "a", Type: "versioned", Config: json.RawMessage(`{"hostname":"db"}`)})
+
+	issues, err := svc.Issues(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+	assert.Empty(t, repo.updated)
+}
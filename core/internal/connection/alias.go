@@ -0,0 +1,98 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+)
+
+// DefaultAlias is the alias a saved query that names no datasource runs
+// against.
+const DefaultAlias = "default"
+
+// ErrAliasNotFound is returned by ResolveAlias when no datasource answers to
+// an alias in the environment.
+var ErrAliasNotFound = errors.New("datasource alias not found")
+
+var aliasRe = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,62}$`)
+
+// ValidAlias reports whether alias is a usable datasource alias: lower case
+// letters, digits, '-' and '_', starting with a letter.
+func ValidAlias(alias string) bool {
+	return aliasRe.MatchString(alias)
+}
+
+// ResolveAlias returns the datasource alias names in env. A datasource with
+// no environment label stands in for every environment without one of its
+// own, so a single unlabeled datasource serves them all.
+func ResolveAlias(ctx context.Context, repo Repository, alias, env string) (*Connection, error) {
+	conns, err := repo.List(ctx, Filter{Alias: alias})
+	if err != nil {
+		return nil, err
+	}
+	var unlabeled *Connection
+	for _, c := range conns {
+		if c.Alias != alias {
+			continue
+		}
+		if c.Environment == env {
+			return c, nil
+		}
+		if c.Environment == "" {
+			unlabeled = c
+		}
+	}
+	if unlabeled != nil {
+		return unlabeled, nil
+	}
+	if env == "" {
+		return nil, fmt.Errorf("%w: %q", ErrAliasNotFound, alias)
+	}
+	return nil, fmt.Errorf("%w: %q in environment %s", ErrAliasNotFound, alias, env)
+}
+
+// applyAlias validates and sets a requested alias; an empty one clears it.
+// It writes the error response itself when the alias is unusable.
+func applyAlias(c *gin.Context, conn *Connection, alias *string) bool {
+	if alias == nil {
+		return true
+	}
+	if *alias != "" && !ValidAlias(*alias) {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: i18n.T(c, "invalid datasource alias")})
+		return false
+	}
+	conn.Alias = *alias
+	return true
+}
+
+// checkAliasAvailable reports whether conn's alias is free in its
+// environment, answering 409 itself when another datasource holds it.
+func (h *Handler) checkAliasAvailable(c *gin.Context, conn *Connection) bool {
+	if conn.Alias == "" {
+		return true
+	}
+	// Look past visibility, as for names, so hidden datasources count too.
+	repo := h.repo
+	if v, ok := repo.(visibleRepository); ok {
+		repo = v.Repository
+	}
+	conns, err := repo.List(c.Request.Context(), Filter{Alias: conn.Alias})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return false
+	}
+	for _, other := range conns {
+		if other.ID != conn.ID && other.Alias == conn.Alias && other.Environment == conn.Environment {
+			c.JSON(http.StatusConflict, api.ErrorResponse{Error: fmt.Sprintf("alias %q is already used by datasource %s", conn.Alias, other.ID)})
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package connection
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/api"
+)
+
+// aliasRepo keeps connections in memory and lists them by alias.
+type aliasRepo struct {
+	mockRepo
+	conns []*Connection
+}
+
+func (r *aliasRepo) Create(_ context.Context, c *Connection) error {
+	c.ID = uuid.NewString()
+	r.conns = append(r.conns, c)
+	return nil
+}
+
+func (r *aliasRepo) List(_ context.Context, f Filter) ([]*Connection, error) {
+	var out []*Connection
+	for _, c := range r.conns {
+		if f.Alias == "" || c.Alias == f.Alias {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func TestResolveAlias(t *testing.T) {
+	repo := &aliasRepo{conns: []*Connection{
+		{ID: "dev", Alias: "analytics", Environment: "dev"},
+		{ID: "prod", Alias: "analytics", Environment: "prod"},
+		{ID: "any", Alias: "analytics"},
+		{ID: "other", Alias: "billing", Environment: "prod"},
+	}}
+	ctx := context.Background()
+
+	for env, want := range map[string]string{"dev": "dev", "prod": "prod", "staging": "any", "": "any"} {
+		conn, err := ResolveAlias(ctx, repo, "analytics", env)
+		require.NoError(t, err, env)
+		assert.Equal(t, want, conn.ID, env)
+	}
+
+	_, err := ResolveAlias(ctx, repo, "billing", "dev")
+	assert.ErrorIs(t, err, ErrAliasNotFound)
+	assert.ErrorContains(t, err, `"billing" in environment dev`)
+}
+
+func TestValidAlias(t *testing.T) {
+	for _, ok := range []string{"analytics", "a", "main-db_2"} {
+		assert.True(t, ValidAlias(ok), ok)
+	}
+	for _, bad := range []string{"", "Analytics", "2db", "-x", "a b", "a.b"} {
+		assert.False(t, ValidAlias(bad), bad)
+	}
+}
+
+func TestCreateDatasource_AliasTakenInEnvironment(t *testing.T) {
+	repo := &aliasRepo{conns: []*Connection{{ID: "prod", Alias: "analytics", Environment: "prod"}}}
+	h := newHandler(repo, &mockPlugin{})
+
+	create := func(env api.Environment) int {
+		alias := "analytics"
+		raw, _ := json.Marshal(api.CreateDatasourceRequest{
+			Name: "warehouse " + string(env), Type: "mock", Options: map[string]interface{}{},
+			Environment: &env, Alias: &alias,
+		})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/datasources", bytes.NewReader(raw))
+		c.Request.Header.Set("Content-Type", "application/json")
+		h.CreateDatasource(c)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusConflict, create(api.Prod))
+	assert.Equal(t, http.StatusCreated, create(api.Dev))
+}
@@ -0,0 +1,106 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"math"
+	"regexp"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/sdk"
+)
+
+// tebibyte is the unit scan pricing is quoted in.
+const tebibyte = 1 << 40
+
+// DefaultCurrency is the currency of a cost model that names none.
+const DefaultCurrency = "USD"
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// CostModel prices queries by the bytes they scan, as BigQuery on-demand
+// and similar warehouses bill.
+type CostModel struct {
+	// PerTiB is the price of scanning one tebibyte (2^40 bytes).
+	PerTiB float64 `json:"per_tib"`
+	// Currency is an ISO 4217 code; empty means DefaultCurrency.
+	Currency string `json:"currency,omitempty"`
+	// MinBytesBilled is what a query scanning less, but more than nothing,
+	// is billed for.
+	MinBytesBilled int64 `json:"min_bytes_billed,omitempty"`
+}
+
+// Validate checks the price and minimum are usable and the currency, if
+// set, is an ISO 4217 code.
+func (m *CostModel) Validate() error {
+	if m.PerTiB < 0 || math.IsNaN(m.PerTiB) || math.IsInf(m.PerTiB, 0) {
+		return errors.New("price per TiB must be a non-negative number")
+	}
+	if m.MinBytesBilled < 0 {
+		return errors.New("minimum bytes billed must not be negative")
+	}
+	if m.Currency != "" && !currencyCode.MatchString(m.Currency) {
+		return errors.New("currency must be a three-letter ISO 4217 code, e.g. USD")
+	}
+	return nil
+}
+
+// CurrencyOrDefault is the currency costs are reported in.
+func (m *CostModel) CurrencyOrDefault() string {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+// Cost prices a query that scanned bytes. A query that scanned nothing,
+// such as one answered from a cache, costs nothing.
+func (m *CostModel) Cost(bytes int64) float64 {
+	if bytes <= 0 {
+		return 0
+	}
+	return float64(max(bytes, m.MinBytesBilled)) / tebibyte * m.PerTiB
+}
+
+// estimateScan asks dbConn how much query would scan. It reports false
+// when the driver cannot estimate or the estimate fails; an estimate is
+// advisory, so the query runs regardless.
+func estimateScan(ctx context.Context, dbConn sdk.Connection, query string, params []any) (*sdk.ScanEstimate, bool) {
+	est, ok := dbConn.(sdk.ScanEstimator)
+	if !ok {
+		return nil, false
+	}
+	e, err := est.EstimateScan(ctx, query, params...)
+	if err != nil || e == nil {
+		return nil, false
+	}
+	return e, true
+}
+
+// withCost prices a query's estimate and actual scan under conn's cost
+// model. Without one, out is left as it is.
+func withCost(out *api.QueryStats, conn *Connection, estimate *sdk.ScanEstimate, bytesRead int64) *api.QueryStats {
+	m := conn.CostModel
+	if m == nil {
+		return out
+	}
+	currency := m.CurrencyOrDefault()
+	out.Currency = &currency
+	if estimate != nil {
+		bytes, cost := estimate.Bytes, m.Cost(estimate.Bytes)
+		out.EstimatedBytes, out.EstimatedCost = &bytes, &cost
+	}
+	cost := m.Cost(bytesRead)
+	out.Cost = &cost
+	return out
+}
+
+func toAPICostModel(m *CostModel) *api.CostModel {
+	out := &api.CostModel{PerTib: m.PerTiB}
+	currency := m.CurrencyOrDefault()
+	out.Currency = &currency
+	if m.MinBytesBilled > 0 {
+		out.MinBytesBilled = &m.MinBytesBilled
+	}
+	return out
+}
@@ -0,0 +1,131 @@
+package connection
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/sdk"
+)
+
+// scanEstimatingConn is a mockConn that can estimate its scans.
+type scanEstimatingConn struct {
+	*mockConn
+	estimate  *sdk.ScanEstimate
+	estimated []string
+}
+
+func (e *scanEstimatingConn) EstimateScan(_ context.Context, query string, _ ...any) (*sdk.ScanEstimate, error) {
+	e.estimated = append(e.estimated, query)
+	return e.estimate, nil
+}
+
+func TestCostModel(t *testing.T) {
+	m := &CostModel{PerTiB: 6.25, MinBytesBilled: 10 << 20}
+	require.NoError(t, m.Validate())
+	assert.Equal(t, "USD", m.CurrencyOrDefault())
+	assert.InDelta(t, 6.25, m.Cost(1<<40), 1e-12)
+	assert.InDelta(t, m.Cost(10<<20), m.Cost(1), 1e-12, "small scans bill the minimum")
+	assert.Zero(t, m.Cost(0), "cached results cost nothing")
+
+	for _, bad := range []CostModel{{PerTiB: -1}, {PerTiB: 1, MinBytesBilled: -1}, {PerTiB: 1, Currency: "usd"}} {
+		assert.Error(t, bad.Validate(), "%+v", bad)
+	}
+}
+
+func TestQueryDatasource_ReportsCost(t *testing.T) {
+	conn := storedConn()
+	dbConn := &scanEstimatingConn{
+		mockConn: &mockConn{result: &sdk.QueryResult{Stats: sdk.QueryStats{BytesRead: 1 << 39}}},
+		estimate: &sdk.ScanEstimate{Bytes: 1 << 40, Method: "explain estimate"},
+	}
+	h := newHandler(&mockRepo{conn: conn}, &mockPlugin{dbConn: dbConn})
+
+	// Without a cost model nothing is estimated or priced.
+	w := post(h, api.QueryRequest{Query: "SELECT 1"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Empty(t, dbConn.estimated)
+	assert.NotContains(t, w.Body.String(), "cost")
+
+	conn.CostModel = &CostModel{PerTiB: 5, Currency: "EUR"}
+	w = post(h, api.QueryRequest{Query: "SELECT 1"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []string{"SELECT 1"}, dbConn.estimated)
+	var resp api.QueryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	stats := resp.Stats
+	require.NotNil(t, stats.EstimatedBytes)
+	assert.EqualValues(t, 1<<40, *stats.EstimatedBytes)
+	assert.InDelta(t, 5, *stats.EstimatedCost, 1e-9)
+	assert.InDelta(t, 2.5, *stats.Cost, 1e-9)
+	assert.Equal(t, "EUR", *stats.Currency)
+}
+
+func TestEstimateDatasourceQuery(t *testing.T) {
+	conn := storedConn()
+	conn.CostModel = &CostModel{PerTiB: 6.25}
+	estimate := func(dbConn sdk.Connection) *httptest.ResponseRecorder {
+		h := newHandler(&mockRepo{conn: conn}, &mockPlugin{dbConn: dbConn})
+		raw, _ := json.Marshal(api.QueryRequest{Query: "SELECT * FROM events"})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/datasources/1/query/estimate", bytes.NewReader(raw))
+		c.Request.Header.Set("Content-Type", "application/json")
+		h.EstimateDatasourceQuery(c, uuid.MustParse(testConnID))
+		return w
+	}
+
+	w := estimate(&scanEstimatingConn{mockConn: &mockConn{}, estimate: &sdk.ScanEstimate{Bytes: 1 << 41, Rows: 900, Method: "dry run"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct{ Data api.QueryEstimate }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.EqualValues(t, 1<<41, resp.Data.Bytes)
+	assert.EqualValues(t, 900, *resp.Data.Rows)
+	assert.Equal(t, "dry run", resp.Data.Method)
+	assert.InDelta(t, 12.5, *resp.Data.Cost, 1e-9)
+	assert.Equal(t, "USD", *resp.Data.Currency)
+
+	w = estimate(&mockConn{})
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
+
+func TestSetDatasourceCostModel(t *testing.T) {
+	repo := &updatingRepo{mockRepo: mockRepo{conn: storedConn()}}
+	h := newHandler(repo, &mockPlugin{})
+	put := func(body any) *httptest.ResponseRecorder {
+		raw, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPut, "/datasources/1/cost-model", bytes.NewReader(raw))
+		c.Request.Header.Set("Content-Type", "application/json")
+		h.SetDatasourceCostModel(c, uuid.MustParse(testConnID))
+		return w
+	}
+
+	w := put(map[string]any{"perTib": 6.25, "minBytesBilled": 10 << 20})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, &CostModel{PerTiB: 6.25, MinBytesBilled: 10 << 20}, repo.updated.CostModel)
+	var resp api.DatasourceResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Data.CostModel)
+	assert.Equal(t, "USD", *resp.Data.CostModel.Currency)
+
+	w = put(map[string]any{"perTib": 1, "currency": "dollars"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodDelete, "/datasources/1/cost-model", nil)
+	h.ClearDatasourceCostModel(c, uuid.MustParse(testConnID))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, repo.updated.CostModel)
+}
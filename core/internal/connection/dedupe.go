@@ -0,0 +1,142 @@
+package connection
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"data-voyager/core/internal/api"
+)
+
+// Response headers for deduplicated queries. FingerprintHeader is set on
+// every read query; SharedHeader is "true" when the response came from a
+// run started by another request.
+const (
+	FingerprintHeader = "X-Voyager-Query-Fingerprint"
+	SharedHeader      = "X-Voyager-Query-Shared"
+)
+
+// normalizeSQL collapses whitespace and drops trailing semicolons so that
+// queries differing only in layout share a fingerprint. Literals, quoted
+// identifiers and comments (which may carry optimizer hints) are kept as is.
+func normalizeSQL(sql string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range sqlNoiseRe.FindAllStringIndex(sql, -1) {
+		b.WriteString(strings.Join(strings.Fields(sql[last:m[0]]), " "))
+		if m[0] > last && isSpace(sql[m[0]-1]) {
+			b.WriteByte(' ')
+		}
+		b.WriteString(sql[m[0]:m[1]])
+		if m[1] < len(sql) && isSpace(sql[m[1]]) {
+			b.WriteByte(' ')
+		}
+		last = m[1]
+	}
+	b.WriteString(strings.Join(strings.Fields(sql[last:]), " "))
+	return strings.TrimRight(strings.TrimSpace(b.String()), "; \t\n")
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// queryFingerprint identifies what a prepared query will execute: the
+// datasource as currently configured, the normalized SQL and its bound
+// parameters, the transforms applied to the result and the deadline it runs
+// under.
+func (h *Handler) queryFingerprint(q *preparedQuery) string {
+	sum := sha256.New()
+	write := func(s string) {
+		sum.Write([]byte(s))
+		sum.Write([]byte{0})
+	}
+	write(q.conn.ID)
+	write(q.conn.UpdatedAt.UTC().Format(time.RFC3339Nano))
+	write(normalizeSQL(q.sql))
+	transforms, _ := json.Marshal(q.transforms)
+	write(string(transforms))
+	params, _ := json.Marshal(q.body.Parameters)
+	write(string(params))
+	write(strconv.FormatInt(int64(h.effectiveTimeout(q.conn, q.body.Timeout)), 10))
+	write(strconv.Itoa(q.maxRows))
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// queryFlights runs identical read queries that arrive while one is already
+// executing only once and hands its result to every caller, so dashboards
+// whose panels share SQL do not multiply the load on the warehouse. Results
+// are not kept once the run finishes; this is not a cache. The zero value is
+// ready to use.
+type queryFlights struct {
+	mu sync.Mutex
+	m  map[string]*flight
+}
+
+type flight struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+
+	resp    *api.QueryResponse
+	replica string
+	fail    *queryFailure
+}
+
+// do runs fn for key unless a run is already in flight, in which case it
+// waits for that one. The run is detached from any single caller: it keeps
+// going while at least one caller is still waiting and is cancelled when the
+// last one leaves. shared reports whether another caller started the run.
+func (f *queryFlights) do(ctx context.Context, key string, fn func(context.Context) (*api.QueryResponse, string, *queryFailure)) (resp *api.QueryResponse, replica string, fail *queryFailure, shared bool) {
+	f.mu.Lock()
+	if f.m == nil {
+		f.m = map[string]*flight{}
+	}
+	fl, shared := f.m[key]
+	if shared {
+		fl.waiters++
+	} else {
+		runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		fl = &flight{done: make(chan struct{}), cancel: cancel, waiters: 1}
+		f.m[key] = fl
+		go func() {
+			defer cancel()
+			fl.resp, fl.replica, fl.fail = fn(runCtx)
+			f.mu.Lock()
+			if f.m[key] == fl {
+				delete(f.m, key)
+			}
+			f.mu.Unlock()
+			close(fl.done)
+		}()
+	}
+	f.mu.Unlock()
+
+	select {
+	case <-fl.done:
+		return fl.resp, fl.replica, fl.fail, shared
+	case <-ctx.Done():
+		f.mu.Lock()
+		fl.waiters--
+		if fl.waiters == 0 {
+			// Nobody wants the result any more. Forget the run so a new
+			// caller does not join one that is being cancelled.
+			fl.cancel()
+			if f.m[key] == fl {
+				delete(f.m, key)
+			}
+		}
+		f.mu.Unlock()
+		return nil, "", &queryFailure{statusClientClosedRequest, api.ErrorResponse{Error: ctx.Err().Error()}}, shared
+	}
+}
+
+// statusClientClosedRequest is the nginx convention for a client that went
+// away before the response was ready; nobody reads it, but it keeps logs
+// from blaming the datasource.
+const statusClientClosedRequest = 499
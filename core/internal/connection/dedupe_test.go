@@ -0,0 +1,133 @@
+package connection
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/sdk"
+)
+
+func TestNormalizeSQL(t *testing.T) {
+	assert.Equal(t, "SELECT a, b FROM t WHERE x = 1",
+		normalizeSQL("SELECT a,  b\n\tFROM t\nWHERE x = 1;\n"))
+	assert.Equal(t, "SELECT 'a  b' FROM t /*+ hint  */ WHERE y = \"c  d\"",
+		normalizeSQL("SELECT 'a  b'  FROM t  /*+ hint  */\nWHERE y = \"c  d\""))
+	assert.NotEqual(t, normalizeSQL("SELECT 'a b'"), normalizeSQL("SELECT 'a  b'"))
+}
+
+// gatedConn counts queries and holds each one until release is closed.
+type gatedConn struct {
+	mockConn
+	queries atomic.Int32
+	release chan struct{}
+}
+
+func (g *gatedConn) Query(ctx context.Context, _ string, _ ...any) (*sdk.QueryResult, error) {
+	g.queries.Add(1)
+	select {
+	case <-g.release:
+		return &sdk.QueryResult{Stats: sdk.QueryStats{RowsReturned: 1}}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestQueryDatasource_DeduplicatesConcurrentReads(t *testing.T) {
+	gc := &gatedConn{release: make(chan struct{})}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: gc})
+
+	bodies := []api.QueryRequest{
+		{Query: "SELECT count(*) FROM events"},
+		{Query: "SELECT count(*)\n  FROM events;"},
+		{Query: "SELECT count(*) FROM {{ table }}", Variables: &map[string]any{"table": "events"}},
+	}
+	var wg sync.WaitGroup
+	codes := make([]int, len(bodies))
+	shared := make([]string, len(bodies))
+	fingerprints := make([]string, len(bodies))
+	for i, body := range bodies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := post(h, body)
+			codes[i], shared[i], fingerprints[i] = w.Code, w.Header().Get(SharedHeader), w.Header().Get(FingerprintHeader)
+		}()
+	}
+	require.Eventually(t, func() bool {
+		h.flights.mu.Lock()
+		defer h.flights.mu.Unlock()
+		for _, fl := range h.flights.m {
+			return fl.waiters == len(bodies)
+		}
+		return false
+	}, 2*time.Second, 5*time.Millisecond)
+	close(gc.release)
+	wg.Wait()
+
+	assert.EqualValues(t, 1, gc.queries.Load())
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusOK}, codes)
+	assert.ElementsMatch(t, []string{"", "true", "true"}, shared)
+	assert.NotEmpty(t, fingerprints[0])
+	assert.Equal(t, fingerprints[0], fingerprints[1])
+	assert.Equal(t, fingerprints[0], fingerprints[2])
+}
+
+func TestQueryDatasource_WritesAreNotShared(t *testing.T) {
+	gc := &gatedConn{release: make(chan struct{})}
+	close(gc.release)
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: gc})
+
+	w := post(h, api.QueryRequest{Query: "DELETE FROM events"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(FingerprintHeader))
+}
+
+func TestQueryFlights_CancelledWhenEveryCallerLeaves(t *testing.T) {
+	var f queryFlights
+	started := make(chan struct{})
+	runErr := make(chan error, 1)
+	fn := func(ctx context.Context) (*api.QueryResponse, string, *queryFailure) {
+		close(started)
+		<-ctx.Done()
+		runErr <- ctx.Err()
+		return nil, "", nil
+	}
+
+	first, cancelFirst := context.WithCancel(context.Background())
+	second, cancelSecond := context.WithCancel(context.Background())
+	results := make(chan *queryFailure, 2)
+	go func() { _, _, fail, _ := f.do(first, "k", fn); results <- fail }()
+	<-started
+	go func() {
+		_, _, fail, _ := f.do(second, "k", func(context.Context) (*api.QueryResponse, string, *queryFailure) {
+			t.Error("second caller must join the running query")
+			return nil, "", nil
+		})
+		results <- fail
+	}()
+	require.Eventually(t, func() bool {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return f.m["k"] != nil && f.m["k"].waiters == 2
+	}, 2*time.Second, 5*time.Millisecond)
+
+	cancelFirst()
+	assert.Equal(t, statusClientClosedRequest, (<-results).status)
+	select {
+	case <-runErr:
+		t.Fatal("run cancelled while a caller was still waiting")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancelSecond()
+	<-results
+	assert.ErrorIs(t, <-runErr, context.Canceled)
+}
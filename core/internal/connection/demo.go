@@ -0,0 +1,80 @@
+package connection
+
+import (
+	"context"
+	"errors"
+
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/sqllint"
+	"data-voyager/sdk"
+)
+
+// ErrDemoWrite is returned by DemoGuard for a query that is not a plain
+// read.
+var ErrDemoWrite = errors.New("only read-only queries can run in the demo")
+
+// DemoGuard holds a query to the public demo's rules when ctx's identity is
+// a demo visitor: anything but a plain read is refused and a query without
+// a LIMIT gets the demo's. Other identities get sql back as written.
+func DemoGuard(ctx context.Context, sql string) (string, error) {
+	id := identity.FromContext(ctx)
+	if id == nil || !id.Demo {
+		return sql, nil
+	}
+	if !IsReadOnlyQuery(sql) {
+		return "", ErrDemoWrite
+	}
+	if limited, ok := sqllint.AddLimit(sql, id.RowLimit); ok {
+		return limited, nil
+	}
+	return sql, nil
+}
+
+// DemoContext marks ctx read-only for the datasource when its identity is
+// a demo visitor, so plugins that can run the query in a read-only
+// transaction do. Checking the query text cannot tell what a function
+// called from a SELECT does, so datasources shared with the demo should
+// still use credentials that can only read.
+func DemoContext(ctx context.Context) context.Context {
+	if id := identity.FromContext(ctx); id != nil && id.Demo {
+		return sdk.WithReadOnly(ctx)
+	}
+	return ctx
+}
+
+// demoRefuses reports whether ctx's identity is a demo visitor and sql is
+// not a plain read.
+func demoRefuses(ctx context.Context, sql string) bool {
+	id := identity.FromContext(ctx)
+	return id != nil && id.Demo && !IsReadOnlyQuery(sql)
+}
+
+// CapDemoRows trims result to the demo's row limit when ctx's identity is a
+// demo visitor, so a query with a LIMIT of its own cannot return more.
+func CapDemoRows(ctx context.Context, result *sdk.QueryResult) {
+	CapRows(result, demoRowLimit(ctx))
+}
+
+// demoRowLimit is the most rows a query may return to ctx's identity: the
+// demo's limit for a demo visitor, 0 (no cap) for anyone else.
+func demoRowLimit(ctx context.Context) int {
+	if id := identity.FromContext(ctx); id != nil && id.Demo {
+		return id.RowLimit
+	}
+	return 0
+}
+
+// CapRows trims every frame of result to n rows; 0 leaves it whole.
+func CapRows(result *sdk.QueryResult, n int) {
+	if n <= 0 || result == nil {
+		return
+	}
+	for _, f := range result.Frames {
+		for i := range f.Fields {
+			if len(f.Fields[i].Values) > n {
+				f.Fields[i].Values = f.Fields[i].Values[:n]
+			}
+		}
+	}
+	result.Stats.RowsReturned = min(result.Stats.RowsReturned, int64(n))
+}
@@ -0,0 +1,99 @@
+package connection
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/identity"
+	"data-voyager/sdk"
+)
+
+func demoVisitor() *identity.Identity {
+	return &identity.Identity{Username: "demo", Role: identity.RoleViewer, Datasources: []string{testConnID}, RowLimit: 2, Demo: true}
+}
+
+func postDemo(h *Handler, id *identity.Identity, body any) *httptest.ResponseRecorder {
+	raw, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/datasources/1/query", bytes.NewReader(raw))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request = c.Request.WithContext(identity.With(c.Request.Context(), id))
+	h.QueryDatasource(c, uuid.MustParse(testConnID))
+	return w
+}
+
+func TestQueryDatasource_DemoReadsOnly(t *testing.T) {
+	var captured string
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{
+		dbConn: &capturingConn{mockConn: &mockConn{}, captured: &captured},
+	})
+
+	w := postDemo(h, demoVisitor(), api.QueryRequest{Query: "DELETE FROM events"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, captured, "the write never reaches the datasource")
+
+	w = postDemo(h, demoVisitor(), api.QueryRequest{Query: "SELECT * FROM events"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "SELECT * FROM events\nLIMIT 2", captured, "the demo limit applies to any datasource")
+}
+
+func TestQueryDatasource_DemoCapsRows(t *testing.T) {
+	result := &sdk.QueryResult{
+		Frames: []*sdk.DataFrame{{Fields: []sdk.Field{{Name: "n", Values: []any{1, 2, 3, 4}}}}},
+		Stats:  sdk.QueryStats{RowsReturned: 4},
+	}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &mockConn{result: result}})
+
+	w := postDemo(h, demoVisitor(), api.QueryRequest{Query: "SELECT n FROM t LIMIT 1000"})
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp api.QueryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(2), resp.Stats.RowsReturned)
+	assert.Len(t, result.Frames[0].Fields[0].Values, 2)
+}
+
+// readOnlyConn records whether each query it runs was marked read-only.
+type readOnlyConn struct {
+	*mockConn
+	readOnly []bool
+}
+
+func (c *readOnlyConn) Query(ctx context.Context, _ string, _ ...any) (*sdk.QueryResult, error) {
+	c.readOnly = append(c.readOnly, sdk.ReadOnly(ctx))
+	return &sdk.QueryResult{}, nil
+}
+
+func TestQueryDatasource_DemoRunsReadOnly(t *testing.T) {
+	dbConn := &readOnlyConn{mockConn: &mockConn{}}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: dbConn})
+
+	require.Equal(t, http.StatusOK, postDemo(h, demoVisitor(), api.QueryRequest{Query: "SELECT nextval('s')"}).Code)
+	viewer := &identity.Identity{Username: "ann", Role: identity.RoleViewer}
+	require.Equal(t, http.StatusOK, postDemo(h, viewer, api.QueryRequest{Query: "SELECT nextval('s')"}).Code)
+	assert.Equal(t, []bool{true, false}, dbConn.readOnly, "only demo queries reach the plugin marked read-only")
+}
+
+func TestDemoGuard(t *testing.T) {
+	ctx := identity.With(context.Background(), demoVisitor())
+
+	sql, err := DemoGuard(ctx, "SELECT * FROM events")
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM events\nLIMIT 2", sql)
+	_, err = DemoGuard(ctx, "DROP TABLE events")
+	assert.ErrorIs(t, err, ErrDemoWrite)
+
+	sql, err = DemoGuard(context.Background(), "DROP TABLE events")
+	require.NoError(t, err, "only demo visitors are held to it")
+	assert.Equal(t, "DROP TABLE events", sql)
+}
@@ -0,0 +1,128 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/notification"
+	"data-voyager/sdk"
+)
+
+// maxListedTables caps how many table names a schema change event lists
+// per kind of change.
+const maxListedTables = 10
+
+// WithNotifier raises activity events through n: new datasources, and
+// schema changes found when a cached schema is refreshed.
+func (h *Handler) WithNotifier(n notification.Notifier) *Handler {
+	h.notifier = n
+	return h
+}
+
+func (h *Handler) notify(ctx context.Context, ev notification.Event) {
+	if h.notifier == nil {
+		return
+	}
+	if err := h.notifier.Notify(ctx, ev); err != nil {
+		slog.Warn("datasource notification failed", "event", ev.Type, "err", err)
+	}
+}
+
+// notifyCreated raises a datasource_created event for a new datasource.
+func (h *Handler) notifyCreated(ctx context.Context, connID, name, connType string) {
+	labels := map[string]string{"datasource_id": connID, "datasource_type": connType}
+	if caller := identity.FromContext(ctx); caller != nil {
+		labels["user"] = caller.Username
+	}
+	h.notify(ctx, notification.Event{
+		Type:     notification.EventDatasourceCreated,
+		Severity: notification.SeverityInfo,
+		Title:    fmt.Sprintf("Datasource %s was added", name),
+		Source:   name,
+		Labels:   labels,
+	})
+}
+
+// notifySchemaChange raises a schema_changed event when the tables of conn
+// differ between two reads of its schema.
+func (h *Handler) notifySchemaChange(ctx context.Context, conn *Connection, before, after *sdk.SchemaInfo) {
+	added, removed, changed := schemaChanges(before, after)
+	if len(added)+len(removed)+len(changed) == 0 {
+		return
+	}
+	var body []string
+	for _, c := range []struct {
+		label  string
+		tables []string
+	}{{"Added", added}, {"Removed", removed}, {"Changed", changed}} {
+		if len(c.tables) > 0 {
+			body = append(body, c.label+": "+listTables(c.tables))
+		}
+	}
+	h.notify(ctx, notification.Event{
+		Type:     notification.EventSchemaChanged,
+		Severity: notification.SeverityInfo,
+		Title:    fmt.Sprintf("Schema of %s changed", conn.Name),
+		Body:     strings.Join(body, "\n"),
+		Source:   conn.Name,
+		Labels: map[string]string{
+			"datasource_id": conn.ID,
+			"added":         fmt.Sprint(len(added)),
+			"removed":       fmt.Sprint(len(removed)),
+			"changed":       fmt.Sprint(len(changed)),
+		},
+	})
+}
+
+// schemaChanges compares two schemas table by table, naming tables as
+// database.table. A table changed when its columns or their types did.
+func schemaChanges(before, after *sdk.SchemaInfo) (added, removed, changed []string) {
+	old, cur := schemaTables(before), schemaTables(after)
+	for name, cols := range cur {
+		prev, ok := old[name]
+		switch {
+		case !ok:
+			added = append(added, name)
+		case !slices.Equal(prev, cols):
+			changed = append(changed, name)
+		}
+	}
+	for name := range old {
+		if _, ok := cur[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+	slices.Sort(changed)
+	return added, removed, changed
+}
+
+// schemaTables maps database.table to its columns as "name type".
+func schemaTables(s *sdk.SchemaInfo) map[string][]string {
+	out := map[string][]string{}
+	if s == nil {
+		return out
+	}
+	for _, db := range s.Databases {
+		for _, t := range db.Tables {
+			cols := make([]string, len(t.Columns))
+			for i, c := range t.Columns {
+				cols[i] = c.Name + " " + c.Type
+			}
+			out[db.Name+"."+t.Name] = cols
+		}
+	}
+	return out
+}
+
+func listTables(tables []string) string {
+	if len(tables) <= maxListedTables {
+		return strings.Join(tables, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(tables[:maxListedTables], ", "), len(tables)-maxListedTables)
+}
@@ -0,0 +1,54 @@
+package connection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/notification"
+	"data-voyager/sdk"
+)
+
+type eventLog struct{ events []notification.Event }
+
+func (l *eventLog) Notify(_ context.Context, ev notification.Event) error {
+	l.events = append(l.events, ev)
+	return nil
+}
+
+func schemaOf(tables ...sdk.TableInfo) *sdk.SchemaInfo {
+	return &sdk.SchemaInfo{Databases: []sdk.DatabaseInfo{{Name: "shop", Tables: tables}}}
+}
+
+func TestSchemaChanges(t *testing.T) {
+	id := sdk.ColumnInfo{Name: "id", Type: "int"}
+	before := schemaOf(
+		sdk.TableInfo{Name: "orders", Columns: []sdk.ColumnInfo{id}},
+		sdk.TableInfo{Name: "carts", Columns: []sdk.ColumnInfo{id}},
+		sdk.TableInfo{Name: "users", Columns: []sdk.ColumnInfo{id}},
+	)
+	after := schemaOf(
+		sdk.TableInfo{Name: "orders", Columns: []sdk.ColumnInfo{{Name: "id", Type: "bigint"}}},
+		sdk.TableInfo{Name: "users", Columns: []sdk.ColumnInfo{id}},
+		sdk.TableInfo{Name: "refunds", Columns: []sdk.ColumnInfo{id}},
+	)
+	added, removed, changed := schemaChanges(before, after)
+	assert.Equal(t, []string{"shop.refunds"}, added)
+	assert.Equal(t, []string{"shop.carts"}, removed)
+	assert.Equal(t, []string{"shop.orders"}, changed)
+
+	log := &eventLog{}
+	h := (&Handler{}).WithNotifier(log)
+	conn := &Connection{ID: "pg", Name: "orders db"}
+	h.notifySchemaChange(context.Background(), conn, before, before)
+	assert.Empty(t, log.events, "an unchanged schema raises nothing")
+
+	h.notifySchemaChange(context.Background(), conn, before, after)
+	require.Len(t, log.events, 1)
+	ev := log.events[0]
+	assert.Equal(t, notification.EventSchemaChanged, ev.Type)
+	assert.Equal(t, "Added: shop.refunds\nRemoved: shop.carts\nChanged: shop.orders", ev.Body)
+	assert.Equal(t, "pg", ev.Labels["datasource_id"])
+}
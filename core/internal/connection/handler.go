@@ -3,6 +3,7 @@ package connection
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -13,7 +14,13 @@ import (
 
 	"data-voyager/core/internal/api"
 	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/memlimit"
+	"data-voyager/core/internal/notification"
 	qb "data-voyager/core/internal/query_builder"
+	"data-voyager/core/internal/resultstore"
+	"data-voyager/core/internal/transform"
 	"data-voyager/sdk"
 )
 
@@ -22,11 +29,26 @@ type Handler struct {
 	repo        Repository
 	registry    *datasource.Registry
 	historyRepo HistoryRepository
+	templates   TemplateRepository
+
+	queryTimeout time.Duration // server-wide limit; 0 means none
+	refSources   []ReferenceSource
+	replicas     replicaHealth
+	warehouses   warehouseActivity
+	schemas      schemaCache
+	usage        UsageRecorder
+	notifier     notification.Notifier
+	results      *resultstore.Store
+	flights      queryFlights
+	memory       *memlimit.Pool // nil means result memory is not limited
+
+	defaultRowLimit int            // LIMIT added on interactive datasources; 0 means none
+	paramPolicy     *LiteralPolicy // nil means ParamPolicyOff
 }
 
 // NewHandler creates a new Handler.
 func NewHandler(repo Repository, registry *datasource.Registry) *Handler {
-	return &Handler{repo: repo, registry: registry, historyRepo: NoopHistoryRepository{}}
+	return &Handler{repo: Visible(repo), registry: registry, historyRepo: NoopHistoryRepository{}}
 }
 
 // WithHistoryRepo attaches a HistoryRepository for audit logging.
@@ -35,29 +57,73 @@ func (h *Handler) WithHistoryRepo(r HistoryRepository) *Handler {
 	return h
 }
 
+// WithTemplateRepo enables datasource templates.
+func (h *Handler) WithTemplateRepo(r TemplateRepository) *Handler {
+	h.templates = r
+	return h
+}
+
 // parseAndValidateConfig marshals config map → JSON, parses and validates via plugin.
 // Returns the serialized JSON on success, or writes an error response and returns nil.
 func (h *Handler) parseAndValidateConfig(c *gin.Context, dsType sdk.DataSourceType, rawConfig map[string]interface{}) (json.RawMessage, bool) {
+	configJSON, cfg, ok := h.parseConfig(c, dsType, rawConfig)
+	if !ok {
+		return nil, false
+	}
+	plugin, _ := h.registry.Get(dsType)
+	if err := plugin.ValidateConfig(cfg); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: fmt.Sprintf("invalid config: %s", err)})
+		return nil, false
+	}
+	return configJSON, true
+}
+
+// parseConfig marshals config map → JSON and parses it via plugin without
+// validating it, for partial configs such as template options. Returns the
+// serialized JSON on success, or writes an error response and returns nil.
+func (h *Handler) parseConfig(c *gin.Context, dsType sdk.DataSourceType, rawConfig map[string]interface{}) (json.RawMessage, sdk.ConnectionConfig, bool) {
 	plugin, exists := h.registry.Get(dsType)
 	if !exists {
-		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: "unsupported datasource type"})
-		return nil, false
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: i18n.T(c, "unsupported datasource type")})
+		return nil, nil, false
 	}
 	configJSON, err := json.Marshal(rawConfig)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: "failed to serialize config"})
-		return nil, false
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: i18n.T(c, "failed to serialize config")})
+		return nil, nil, false
 	}
 	cfg, err := plugin.ParseConfig(configJSON)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: fmt.Sprintf("failed to parse config: %s", err)})
+		return nil, nil, false
+	}
+	return configJSON, cfg, true
+}
+
+// openDatasource resolves the plugin for conn and opens a live session for
+// the read-only statements the caller generates, on a replica when conn has
+// one. On failure — or during a maintenance window — it writes the error
+// response and returns false; callers must close the returned connection.
+func (h *Handler) openDatasource(c *gin.Context, conn *Connection) (sdk.Connection, bool) {
+	if rejectByPolicy(c, conn) || rejectByNetwork(c, conn) || rejectDuringMaintenance(c, conn) {
 		return nil, false
 	}
-	if err := plugin.ValidateConfig(cfg); err != nil {
-		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: fmt.Sprintf("invalid config: %s", err)})
+	plugin, exists := h.registry.Get(conn.Type)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: i18n.T(c, "plugin not found for type")})
 		return nil, false
 	}
-	return configJSON, true
+	if _, err := plugin.ParseConfig(conn.Config); err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: i18n.T(c, "failed to parse config")})
+		return nil, false
+	}
+	dbConn, replica, err := h.connect(c.Request.Context(), plugin, conn, true)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, datasourceError("datasource failed", err))
+		return nil, false
+	}
+	setReplicaHeader(c, replica)
+	return dbConn, true
 }
 
 func (h *Handler) ListDatasources(c *gin.Context, params api.ListDatasourcesParams) {
@@ -71,6 +137,12 @@ func (h *Handler) ListDatasources(c *gin.Context, params api.ListDatasourcesPara
 	if params.CreatedBy != nil {
 		filter.CreatedBy = *params.CreatedBy
 	}
+	if params.Environment != nil {
+		filter.Environment = *params.Environment
+	}
+	if params.Alias != nil {
+		filter.Alias = *params.Alias
+	}
 
 	conns, err := h.repo.List(c.Request.Context(), filter)
 	if err != nil {
@@ -86,26 +158,71 @@ func (h *Handler) ListDatasources(c *gin.Context, params api.ListDatasourcesPara
 }
 
 func (h *Handler) CreateDatasource(c *gin.Context) {
+	if !requirePermission(c, identity.PermDatasourceWrite) {
+		return
+	}
 	var body api.CreateDatasourceRequest
 	if err := c.ShouldBindJSON(&body); err != nil {
 		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	configJSON, ok := h.parseAndValidateConfig(c, sdk.DataSourceType(body.Type), body.Options)
-	if !ok {
-		return
-	}
-
 	conn := &Connection{
 		Name:        body.Name,
 		Type:        sdk.DataSourceType(body.Type),
-		Config:      configJSON,
 		Description: metaString(body.Meta, "description"),
 		Tags:        metaStringSlice(body.Meta, "tags"),
 		CreatedBy:   metaString(body.Meta, "createdBy"),
 		IsActive:    true,
 	}
+	if body.Environment != nil {
+		if !body.Environment.Valid() {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: i18n.T(c, "invalid environment")})
+			return
+		}
+		conn.Environment = string(*body.Environment)
+	}
+	if !applyAlias(c, conn, body.Alias) {
+		return
+	}
+	if body.ExternalId != nil {
+		if existing, err := h.repo.GetByExternalID(c.Request.Context(), *body.ExternalId); err == nil {
+			c.JSON(http.StatusConflict, api.ErrorResponse{Error: fmt.Sprintf("external id %q is already used by datasource %s", *body.ExternalId, existing.ID)})
+			return
+		}
+		conn.ExternalID = *body.ExternalId
+	}
+	if err := validQueryTimeout(body.QueryTimeout); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if body.QueryTimeout != nil {
+		conn.QueryTimeout = *body.QueryTimeout
+	}
+	if body.TemplateUid != nil {
+		tmpl, ok := h.loadTemplate(c, body.TemplateUid.String())
+		if !ok {
+			return
+		}
+		if tmpl.Type != conn.Type {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: fmt.Sprintf("template type %q does not match datasource type %q", tmpl.Type, conn.Type)})
+			return
+		}
+		conn.TemplateID = tmpl.ID
+		conn.Tags = mergeTags(tmpl.Tags, conn.Tags)
+	}
+	if !h.applyOptions(c, conn, body.Options) {
+		return
+	}
+	if body.Replicas != nil && !h.applyReplicas(c, conn, *body.Replicas) {
+		return
+	}
+	if !h.checkNameAvailable(c, conn.Name, "") {
+		return
+	}
+	if !h.checkAliasAvailable(c, conn) {
+		return
+	}
 	if err := h.repo.Create(c.Request.Context(), conn); err != nil {
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
 		return
@@ -117,16 +234,23 @@ func (h *Handler) CreateDatasource(c *gin.Context) {
 func (h *Handler) GetDatasource(c *gin.Context, id openapi_types.UUID) {
 	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
 	if err != nil {
-		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: "datasource not found"})
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
 		return
 	}
+	c.Header("ETag", etag(conn))
 	c.JSON(http.StatusOK, api.DatasourceResponse{Data: toAPIDatasource(conn)})
 }
 
-func (h *Handler) UpdateDatasource(c *gin.Context, id openapi_types.UUID) {
+func (h *Handler) UpdateDatasource(c *gin.Context, id openapi_types.UUID, params api.UpdateDatasourceParams) {
+	if !requirePermission(c, identity.PermDatasourceWrite) {
+		return
+	}
 	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
 	if err != nil {
-		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: "datasource not found"})
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return
+	}
+	if !checkIfMatch(c, params.IfMatch, conn) {
 		return
 	}
 
@@ -137,6 +261,9 @@ func (h *Handler) UpdateDatasource(c *gin.Context, id openapi_types.UUID) {
 	}
 
 	if body.Name != nil {
+		if !h.checkNameAvailable(c, *body.Name, conn.ID) {
+			return
+		}
 		conn.Name = *body.Name
 	}
 	if body.Meta != nil {
@@ -149,28 +276,50 @@ func (h *Handler) UpdateDatasource(c *gin.Context, id openapi_types.UUID) {
 	if body.Enabled != nil {
 		conn.IsActive = *body.Enabled
 	}
-	if body.Options != nil {
-		configJSON, ok := h.parseAndValidateConfig(c, conn.Type, *body.Options)
-		if !ok {
+	if body.Environment != nil {
+		if !body.Environment.Valid() {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: i18n.T(c, "invalid environment")})
 			return
 		}
-		conn.Config = configJSON
+		conn.Environment = string(*body.Environment)
+	}
+	if !applyAlias(c, conn, body.Alias) || !h.checkAliasAvailable(c, conn) {
+		return
+	}
+	if err := validQueryTimeout(body.QueryTimeout); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if body.QueryTimeout != nil {
+		conn.QueryTimeout = *body.QueryTimeout
+	}
+	if body.Options != nil && !h.applyOptions(c, conn, *body.Options) {
+		return
+	}
+	if body.Replicas != nil && !h.applyReplicas(c, conn, *body.Replicas) {
+		return
 	}
 
-	if err := h.repo.Update(c.Request.Context(), conn); err != nil {
-		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+	if !h.update(c, conn) {
 		return
 	}
-	h.recordHistory(c.Request.Context(), conn.ID, conn.Name, string(conn.Type), "updated")
+	c.Header("ETag", etag(conn))
 	c.JSON(http.StatusOK, api.DatasourceResponse{Data: toAPIDatasource(conn)})
 }
 
-func (h *Handler) DeleteDatasource(c *gin.Context, id openapi_types.UUID) {
+func (h *Handler) DeleteDatasource(c *gin.Context, id openapi_types.UUID, params api.DeleteDatasourceParams) {
+	if !requirePermission(c, identity.PermDatasourceWrite) {
+		return
+	}
 	existing, _ := h.repo.GetByID(c.Request.Context(), id.String())
+	if !h.clearReferences(c, id.String(), params.Force != nil && *params.Force) {
+		return
+	}
 	if err := h.repo.Delete(c.Request.Context(), id.String()); err != nil {
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
 		return
 	}
+	h.schemas.forget(id.String())
 	if existing != nil {
 		h.recordHistory(c.Request.Context(), existing.ID, existing.Name, string(existing.Type), "deleted")
 	}
@@ -190,22 +339,25 @@ func (h *Handler) recordHistory(ctx context.Context, connID, name, connType, act
 		Action:         action,
 		ChangedAt:      time.Now().UTC(),
 	})
+	if action == "created" {
+		h.notifyCreated(ctx, connID, name, connType)
+	}
 }
 
 func (h *Handler) TestDatasource(c *gin.Context, id openapi_types.UUID) {
 	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
 	if err != nil {
-		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: "datasource not found"})
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
 		return
 	}
 	plugin, exists := h.registry.Get(conn.Type)
 	if !exists {
-		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: "plugin not found for type"})
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: i18n.T(c, "plugin not found for type")})
 		return
 	}
 	cfg, err := plugin.ParseConfig(conn.Config)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: "failed to parse config"})
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: i18n.T(c, "failed to parse config")})
 		return
 	}
 	result, err := plugin.TestConnection(c.Request.Context(), cfg)
@@ -222,6 +374,9 @@ func (h *Handler) TestDatasource(c *gin.Context, id openapi_types.UUID) {
 }
 
 func (h *Handler) TestDatasourceConfig(c *gin.Context) {
+	if !requirePermission(c, identity.PermDatasourceWrite) {
+		return
+	}
 	var body api.TestDatasourceRequest
 	if err := c.ShouldBindJSON(&body); err != nil {
 		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
@@ -235,7 +390,7 @@ func (h *Handler) TestDatasourceConfig(c *gin.Context) {
 	plugin, _ := h.registry.Get(dsType)
 	cfg, err := plugin.ParseConfig(configJSON)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: "failed to parse config"})
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: i18n.T(c, "failed to parse config")})
 		return
 	}
 	result, err := plugin.TestConnection(c.Request.Context(), cfg)
@@ -254,30 +409,26 @@ func (h *Handler) TestDatasourceConfig(c *gin.Context) {
 func (h *Handler) GetDatasourceSchema(c *gin.Context, id openapi_types.UUID) {
 	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
 	if err != nil {
-		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: "datasource not found"})
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
 		return
 	}
 
 	plugin, exists := h.registry.Get(conn.Type)
 	if !exists {
-		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: "plugin not found for type"})
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: i18n.T(c, "plugin not found for type")})
 		return
 	}
 
-	cfg, err := plugin.ParseConfig(conn.Config)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: "failed to parse config"})
+	if _, err := plugin.ParseConfig(conn.Config); err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: i18n.T(c, "failed to parse config")})
 		return
 	}
 
-	dbConn, err := plugin.Connect(c.Request.Context(), cfg)
-	if err != nil {
-		c.JSON(http.StatusBadGateway, api.ErrorResponse{Error: fmt.Sprintf("datasource failed: %s", err)})
+	schema, err := h.schema(c.Request.Context(), plugin, conn)
+	if errors.Is(err, errConnect) {
+		c.JSON(http.StatusBadGateway, api.ErrorResponse{Error: err.Error()})
 		return
 	}
-	defer func() { _ = dbConn.Close() }()
-
-	schema, err := dbConn.GetSchema(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: fmt.Sprintf("failed to get schema: %s", err)})
 		return
@@ -287,36 +438,86 @@ func (h *Handler) GetDatasourceSchema(c *gin.Context, id openapi_types.UUID) {
 }
 
 func (h *Handler) QueryDatasource(c *gin.Context, id openapi_types.UUID) {
-	var body api.QueryRequest
-	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+	q, ok := h.prepareQuery(c, id)
+	if !ok {
 		return
 	}
-
-	// 1. Load the stored datasource.
-	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
-	if err != nil {
-		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: "datasource not found"})
+	resp, replica, fail := h.runSharedQuery(c, q)
+	setReplicaHeader(c, replica)
+	if fail != nil {
+		setWarehouseRetry(c, fail.resp)
+		c.JSON(fail.status, fail.resp)
 		return
 	}
+	c.JSON(http.StatusOK, resp)
+}
 
-	// 2. Resolve plugin and open a live datasource session.
-	plugin, exists := h.registry.Get(conn.Type)
-	if !exists {
-		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: "plugin not found for type"})
-		return
+// runSharedQuery runs q, joining an identical read query that is already in
+// flight instead of sending a second one to the datasource. Writes always
+// run on their own.
+func (h *Handler) runSharedQuery(c *gin.Context, q *preparedQuery) (*api.QueryResponse, string, *queryFailure) {
+	if !allReadOnly(q.sql) {
+		return h.runQuery(c.Request.Context(), q)
+	}
+	key := h.queryFingerprint(q)
+	c.Header(FingerprintHeader, key)
+	resp, replica, fail, shared := h.flights.do(c.Request.Context(), key, func(ctx context.Context) (*api.QueryResponse, string, *queryFailure) {
+		return h.runQuery(ctx, q)
+	})
+	if shared {
+		c.Header(SharedHeader, "true")
+		if resp != nil {
+			// The rendered SQL matched, but the template and variables that
+			// produced it are this caller's own.
+			own := *resp
+			own.Inspect = queryInspect(q.body.Query, q.sql, q.vars, q.interval)
+			resp = &own
+		}
 	}
-	cfg, err := plugin.ParseConfig(conn.Config)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: "failed to parse config"})
-		return
+	return resp, replica, fail
+}
+
+// preparedQuery is a QueryRequest resolved against its datasource and
+// rendered, ready to run.
+type preparedQuery struct {
+	conn       *Connection
+	plugin     sdk.DatasourcePlugin
+	body       api.QueryRequest
+	sql        string
+	vars       map[string]interface{}
+	timeRange  qb.TimeRange
+	interval   time.Duration
+	transforms []transform.Spec
+	rowLimit   int      // LIMIT added to sql, 0 if none
+	maxRows    int      // rows returned at most, 0 for all; see demoRowLimit
+	params     []any    // bound by the datasource, see queryParams
+	literals   []string // found by checkLiterals, warned about
+}
+
+// queryFailure is the status and body a failed run answers with.
+type queryFailure struct {
+	status int
+	resp   api.ErrorResponse
+}
+
+// prepareQuery binds the request body and does everything short of touching
+// the datasource, writing the error response itself when that fails.
+func (h *Handler) prepareQuery(c *gin.Context, id openapi_types.UUID) (*preparedQuery, bool) {
+	var body api.QueryRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return nil, false
 	}
-	dbConn, err := plugin.Connect(c.Request.Context(), cfg)
-	if err != nil {
-		c.JSON(http.StatusBadGateway, api.ErrorResponse{Error: fmt.Sprintf("datasource failed: %s", err)})
-		return
+	return h.prepareRequest(c, id.String(), body)
+}
+
+// prepareRequest is prepareQuery for a request that is already bound.
+func (h *Handler) prepareRequest(c *gin.Context, id string, body api.QueryRequest) (*preparedQuery, bool) {
+	// 1. Load the stored datasource and resolve its plugin.
+	conn, plugin, ok := h.resolveForQuery(c, id)
+	if !ok {
+		return nil, false
 	}
-	defer func() { _ = dbConn.Close() }()
 
 	// 3. Parse time range and build template context.
 	var fromStr, toStr string
@@ -331,7 +532,7 @@ func (h *Handler) QueryDatasource(c *gin.Context, id openapi_types.UUID) {
 	tr, err := qb.ParseTimeRange(fromStr, toStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
-		return
+		return nil, false
 	}
 
 	limit := 1000
@@ -349,79 +550,222 @@ func (h *Handler) QueryDatasource(c *gin.Context, id openapi_types.UUID) {
 
 	tmplCtx := qb.BuildContext(tr, userVars, limit)
 
-	// 4. Render the query template.
+	// 4. Render the query template and expand time-range macros.
 	renderedSQL, err := qb.RenderQuery(body.Query, tmplCtx)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
-		return
+		return nil, false
+	}
+	renderedSQL, interval, err := qb.ExpandMacros(renderedSQL, qb.DialectFor(string(conn.Type)), tr, maxDataPoints(body.MaxDataPoints))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return nil, false
+	}
+	renderedSQL, rowLimit, literals, ok := h.guardSQL(c, conn, body.Query, renderedSQL)
+	if !ok {
+		return nil, false
+	}
+	transforms, err := transformSpecs(body.Transforms)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return nil, false
+	}
+	params, ok := queryParams(plugin, body.Parameters)
+	if !ok {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: i18n.T(c, "datasource does not support query parameters")})
+		return nil, false
+	}
+	ctxAsMap := map[string]interface{}{}
+	for k, v := range tmplCtx {
+		ctxAsMap[k] = v
+	}
+	return &preparedQuery{
+		conn:       conn,
+		plugin:     plugin,
+		body:       body,
+		sql:        renderedSQL,
+		vars:       ctxAsMap,
+		timeRange:  tr,
+		interval:   interval,
+		transforms: transforms,
+		rowLimit:   rowLimit,
+		maxRows:    demoRowLimit(c.Request.Context()),
+		params:     params,
+		literals:   literals,
+	}, true
+}
+
+// resolveForQuery loads datasource id and its plugin for a query the
+// caller is about to run, after checking the query may run now: the
+// caller's permission, access and network policies, maintenance windows and
+// a resuming warehouse. It writes the error response itself when any fails.
+func (h *Handler) resolveForQuery(c *gin.Context, id string) (*Connection, sdk.DatasourcePlugin, bool) {
+	if !requirePermission(c, identity.PermDatasourceQuery) {
+		return nil, nil, false
+	}
+	conn, err := h.repo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return nil, nil, false
+	}
+	if rejectByPolicy(c, conn) || rejectByNetwork(c, conn) || rejectDuringMaintenance(c, conn) {
+		return nil, nil, false
+	}
+	plugin, exists := h.registry.Get(conn.Type)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: i18n.T(c, "plugin not found for type")})
+		return nil, nil, false
+	}
+	if _, err := plugin.ParseConfig(conn.Config); err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: i18n.T(c, "failed to parse config")})
+		return nil, nil, false
+	}
+	if h.rejectWhileResuming(c, plugin, conn) {
+		return nil, nil, false
+	}
+	return conn, plugin, true
+}
+
+// guardSQL holds sql, rendered from the caller's query, to the rules every
+// query a caller writes follows: demo visitors only run reads, interactive
+// datasources get a row limit and literals are checked against the
+// parameter policy. It returns sql with any LIMIT it added, that limit and
+// the literals to warn about, or writes the error response itself.
+func (h *Handler) guardSQL(c *gin.Context, conn *Connection, query, sql string) (string, int, []string, bool) {
+	ctx := c.Request.Context()
+	if demoRefuses(ctx, sql) {
+		c.JSON(http.StatusForbidden, api.ErrorResponse{Error: i18n.T(c, "only read-only queries can run in the demo")})
+		return "", 0, nil, false
+	}
+	sql, rowLimit := h.limitRows(ctx, conn, sql)
+	literals, reject := h.checkLiterals(ctx, conn, query)
+	if reject {
+		c.JSON(http.StatusBadRequest, rawLiteralsError(c, literals))
+		return "", 0, nil, false
+	}
+	return sql, rowLimit, literals, true
+}
+
+// runQuery opens a session — on a replica for reads — and executes q under
+// its deadline. replica names the replica that served it, if any.
+func (h *Handler) runQuery(ctx context.Context, q *preparedQuery) (resp *api.QueryResponse, replica string, fail *queryFailure) {
+	// 5. Open a session and execute.
+	dbConn, replica, err := h.connect(ctx, q.plugin, q.conn, allReadOnly(q.sql))
+	if err != nil {
+		if fail := h.resumingFailure(ctx, q); fail != nil {
+			return nil, "", fail
+		}
+		return nil, "", &queryFailure{http.StatusBadGateway, datasourceError("datasource failed", err)}
 	}
+	defer func() { _ = dbConn.Close() }()
 
-	// 5. Execute the query.
+	qctx, cancel, timeout := h.withQueryDeadline(DemoContext(ctx), q.conn, q.body.Timeout)
+	defer cancel()
+	// Estimating costs the datasource a planning round trip, so it is only
+	// done when there is a price to put on the answer.
+	var estimate *sdk.ScanEstimate
+	if q.conn.CostModel != nil {
+		estimate, _ = estimateScan(qctx, dbConn, q.sql, q.params)
+	}
 	start := time.Now()
-	result, err := dbConn.Query(c.Request.Context(), renderedSQL)
+	result, err := dbConn.Query(qctx, q.sql, q.params...)
 	elapsed := time.Since(start)
 	if err != nil {
-		c.JSON(http.StatusBadGateway, api.ErrorResponse{Error: fmt.Sprintf("query failed: %s", err)})
-		return
+		if deadlineExceeded(qctx, err) {
+			if fail := h.resumingFailure(ctx, q); fail != nil {
+				return nil, replica, fail
+			}
+			return nil, replica, &queryFailure{http.StatusGatewayTimeout, queryTimeoutError(timeout)}
+		}
+		return nil, replica, &queryFailure{queryErrorStatus(err), datasourceError("query failed", err)}
 	}
+	CapRows(result, q.maxRows)
+	if err := transform.ApplyResult(result, q.transforms); err != nil {
+		return nil, replica, &queryFailure{http.StatusBadRequest, api.ErrorResponse{Error: err.Error()}}
+	}
+
+	h.warehouses.touch(q.conn.ID, time.Now())
+	h.recordUsage(ctx, q.conn, q.sql, result.Stats)
 
 	// 6. Map sdk.QueryResult → API response.
-	bytesRead := result.Stats.BytesRead
-	ctxAsMap := map[string]interface{}{}
-	for k, v := range tmplCtx {
-		ctxAsMap[k] = v
-	}
+	warnings := h.withLint(queryWarnings(q.conn), q.conn, q.sql, result.Stats.RowsReturned, "")
+	return &api.QueryResponse{
+		Data:     sdkResultToAPI(result),
+		Stats:    withCost(queryStats(result.Stats, elapsed), q.conn, estimate, result.Stats.BytesRead),
+		Inspect:  queryInspect(q.body.Query, q.sql, q.vars, q.interval),
+		Warnings: withLiterals(withRowLimit(warnings, q.rowLimit, ""), q.literals, ""),
+		RowLimit: optionalInt(q.rowLimit),
+	}, replica, nil
+}
 
-	c.JSON(http.StatusOK, api.QueryResponse{
-		Data: sdkResultToAPI(result),
-		Stats: &api.QueryStats{
-			ExecutionTimeMs: elapsed.Milliseconds(),
-			RowsReturned:    result.Stats.RowsReturned,
-			BytesRead:       &bytesRead,
-		},
-		Inspect: &api.QueryInspect{
-			RawQuery:      body.Query,
-			ExecutedQuery: renderedSQL,
-			Variables:     &ctxAsMap,
-		},
-	})
+// queryStats maps a driver's statistics onto the API, timing the query by
+// elapsed as measured around the call.
+func queryStats(stats sdk.QueryStats, elapsed time.Duration) *api.QueryStats {
+	out := &api.QueryStats{
+		ExecutionTimeMs: elapsed.Milliseconds(),
+		RowsReturned:    stats.RowsReturned,
+		BytesRead:       &stats.BytesRead,
+	}
+	if stats.FetchSize > 0 {
+		out.FetchSize = &stats.FetchSize
+	}
+	if stats.Batches > 0 {
+		out.Batches = &stats.Batches
+	}
+	return out
 }
 
 func (h *Handler) BatchQueryDatasource(c *gin.Context, id openapi_types.UUID) {
+	if !requirePermission(c, identity.PermDatasourceQuery) {
+		return
+	}
 	var body api.BatchQueryRequest
 	if err := c.ShouldBindJSON(&body); err != nil {
 		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
 		return
 	}
 	if len(body.Queries) == 0 {
-		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: "queries must not be empty"})
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: i18n.T(c, "queries must not be empty")})
 		return
 	}
 
 	// Resolve datasource + plugin once for all queries.
 	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
 	if err != nil {
-		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: "datasource not found"})
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return
+	}
+	if rejectDuringMaintenance(c, conn) {
 		return
 	}
 	plugin, exists := h.registry.Get(conn.Type)
 	if !exists {
-		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: "plugin not found for type"})
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: i18n.T(c, "plugin not found for type")})
 		return
 	}
-	cfg, err := plugin.ParseConfig(conn.Config)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: "failed to parse config"})
+	if _, err := plugin.ParseConfig(conn.Config); err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: i18n.T(c, "failed to parse config")})
 		return
 	}
-	dbConn, err := plugin.Connect(c.Request.Context(), cfg)
+	if h.rejectWhileResuming(c, plugin, conn) {
+		return
+	}
+	// One session serves every query, so the batch goes to a replica only
+	// when all of its queries are reads.
+	raw := make([]string, len(body.Queries))
+	for i, item := range body.Queries {
+		raw[i] = item.Request.Query
+	}
+	dbConn, replica, err := h.connect(c.Request.Context(), plugin, conn, allReadOnly(raw...))
 	if err != nil {
-		c.JSON(http.StatusBadGateway, api.ErrorResponse{Error: fmt.Sprintf("datasource failed: %s", err)})
+		c.JSON(http.StatusBadGateway, datasourceError("datasource failed", err))
 		return
 	}
 	defer func() { _ = dbConn.Close() }()
+	setReplicaHeader(c, replica)
 
 	results := make([]api.BatchQueryResultItem, len(body.Queries))
+	warnings := queryWarnings(conn)
 	for idx, item := range body.Queries {
 		refID := item.Id
 		req := item.Request
@@ -461,39 +805,108 @@ func (h *Handler) BatchQueryDatasource(c *gin.Context, id openapi_types.UUID) {
 			results[idx] = api.BatchQueryResultItem{Id: refID, Error: &errMsg}
 			continue
 		}
+		renderedSQL, interval, err := qb.ExpandMacros(renderedSQL, qb.DialectFor(string(conn.Type)), tr, maxDataPoints(req.MaxDataPoints))
+		if err != nil {
+			errMsg := err.Error()
+			results[idx] = api.BatchQueryResultItem{Id: refID, Error: &errMsg}
+			continue
+		}
+		if demoRefuses(c.Request.Context(), renderedSQL) {
+			errMsg := i18n.T(c, "only read-only queries can run in the demo")
+			results[idx] = api.BatchQueryResultItem{Id: refID, Error: &errMsg}
+			continue
+		}
+		renderedSQL, rowLimit := h.limitRows(c.Request.Context(), conn, renderedSQL)
+		transforms, err := transformSpecs(req.Transforms)
+		if err != nil {
+			errMsg := err.Error()
+			results[idx] = api.BatchQueryResultItem{Id: refID, Error: &errMsg}
+			continue
+		}
+		params, ok := queryParams(plugin, req.Parameters)
+		if !ok {
+			errMsg := i18n.T(c, "datasource does not support query parameters")
+			results[idx] = api.BatchQueryResultItem{Id: refID, Error: &errMsg}
+			continue
+		}
+		literals, reject := h.checkLiterals(c.Request.Context(), conn, req.Query)
+		if reject {
+			resp := rawLiteralsError(c, literals)
+			results[idx] = api.BatchQueryResultItem{Id: refID, Error: &resp.Error, ErrorCode: resp.Code}
+			continue
+		}
 
+		ctx, cancel, timeout := h.withQueryDeadline(DemoContext(c.Request.Context()), conn, req.Timeout)
+		var estimate *sdk.ScanEstimate
+		if conn.CostModel != nil {
+			estimate, _ = estimateScan(ctx, dbConn, renderedSQL, params)
+		}
 		start := time.Now()
-		result, err := dbConn.Query(c.Request.Context(), renderedSQL)
+		result, err := dbConn.Query(ctx, renderedSQL, params...)
 		elapsed := time.Since(start)
+		timedOut := err != nil && deadlineExceeded(ctx, err)
+		cancel()
 		if err != nil {
-			errMsg := fmt.Sprintf("query failed: %s", err)
+			item := api.BatchQueryResultItem{Id: refID}
+			if timedOut {
+				resp := queryTimeoutError(timeout)
+				item.Error, item.ErrorCode = &resp.Error, resp.Code
+			} else {
+				resp := datasourceError("query failed", err)
+				item.Error, item.ErrorCode = &resp.Error, resp.Code
+				item.ErrorLine, item.ErrorColumn = resp.Line, resp.Column
+			}
+			results[idx] = item
+			continue
+		}
+		CapRows(result, demoRowLimit(c.Request.Context()))
+		if err := transform.ApplyResult(result, transforms); err != nil {
+			errMsg := err.Error()
 			results[idx] = api.BatchQueryResultItem{Id: refID, Error: &errMsg}
 			continue
 		}
 
-		bytesRead := result.Stats.BytesRead
+		h.warehouses.touch(conn.ID, time.Now())
+		h.recordUsage(c.Request.Context(), conn, renderedSQL, result.Stats)
+
 		ctxAsMap := map[string]interface{}{}
 		for k, v := range tmplCtx {
 			ctxAsMap[k] = v
 		}
 		apiResult := sdkResultToAPI(result)
 		results[idx] = api.BatchQueryResultItem{
-			Id:   refID,
-			Data: &apiResult,
-			Stats: &api.QueryStats{
-				ExecutionTimeMs: elapsed.Milliseconds(),
-				RowsReturned:    result.Stats.RowsReturned,
-				BytesRead:       &bytesRead,
-			},
-			Inspect: &api.QueryInspect{
-				RawQuery:      req.Query,
-				ExecutedQuery: renderedSQL,
-				Variables:     &ctxAsMap,
-			},
+			Id:       refID,
+			Data:     &apiResult,
+			Stats:    withCost(queryStats(result.Stats, elapsed), conn, estimate, result.Stats.BytesRead),
+			Inspect:  queryInspect(req.Query, renderedSQL, ctxAsMap, interval),
+			RowLimit: optionalInt(rowLimit),
 		}
+		warnings = h.withLint(warnings, conn, renderedSQL, result.Stats.RowsReturned, refID+": ")
+		warnings = withRowLimit(warnings, rowLimit, refID+": ")
+		warnings = withLiterals(warnings, literals, refID+": ")
+	}
+
+	c.JSON(http.StatusOK, api.BatchQueryResponse{Results: results, Warnings: warnings})
+}
+
+// maxDataPoints returns the requested $__timeGroup point budget, or 0 for the
+// default.
+func maxDataPoints(p *int) int {
+	if p == nil {
+		return 0
 	}
+	return *p
+}
 
-	c.JSON(http.StatusOK, api.BatchQueryResponse{Results: results})
+// queryInspect builds the inspect block of a query response, reporting the
+// $__timeGroup interval when the query grouped by time.
+func queryInspect(raw, executed string, vars map[string]interface{}, interval time.Duration) *api.QueryInspect {
+	ins := &api.QueryInspect{RawQuery: raw, ExecutedQuery: executed, Variables: &vars}
+	if interval > 0 {
+		label, ms := qb.FormatInterval(interval), interval.Milliseconds()
+		ins.Interval, ins.IntervalMs = &label, &ms
+	}
+	return ins
 }
 
 func (h *Handler) ListDatasourceTypes(c *gin.Context) {
@@ -505,6 +918,53 @@ func (h *Handler) ListDatasourceTypes(c *gin.Context) {
 	c.JSON(http.StatusOK, api.DatasourceTypesResponse{Data: strs})
 }
 
+func (h *Handler) GetDatasourceType(c *gin.Context, pType string) {
+	plugin, ok := h.registry.Get(sdk.DataSourceType(pType))
+	if !ok {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: fmt.Sprintf("unsupported datasource type: %s", pType)})
+		return
+	}
+	caps := sdk.PluginCapabilities(plugin)
+	c.JSON(http.StatusOK, api.DatasourceTypeResponse{Data: api.DatasourceTypeInfo{
+		Type: string(plugin.GetType()),
+		Name: plugin.GetName(),
+		Capabilities: api.DatasourceCapabilities{
+			Exec:         caps.Exec,
+			Explain:      caps.Explain,
+			Streaming:    caps.Streaming,
+			Schemas:      caps.Schemas,
+			Writes:       caps.Writes,
+			Transactions: caps.Transactions,
+			NamedParams:  caps.NamedParams,
+			Ingest:       caps.Ingest,
+		},
+		Version: toAPITypeVersion(sdk.PluginVersion(plugin)),
+	}})
+}
+
+func (h *Handler) GetDatasourceDialect(c *gin.Context, pType string) {
+	plugin, ok := h.registry.Get(sdk.DataSourceType(pType))
+	if !ok {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: fmt.Sprintf("unsupported datasource type: %s", pType)})
+		return
+	}
+	d := sdk.PluginDialect(plugin)
+	functions := make([]api.DialectFunction, len(d.Functions))
+	for i, f := range d.Functions {
+		functions[i] = api.DialectFunction{Name: f.Name, Signature: f.Signature, Description: f.Description}
+		if f.ReturnType != "" {
+			functions[i].ReturnType = &d.Functions[i].ReturnType
+		}
+	}
+	c.JSON(http.StatusOK, api.DatasourceDialectResponse{Data: api.DatasourceDialect{
+		IdentifierQuote: d.IdentifierQuote,
+		StringQuote:     d.StringQuote,
+		ParameterStyle:  api.DialectParameterStyle(d.ParamStyle),
+		ReservedWords:   d.ReservedWords,
+		Functions:       functions,
+	}})
+}
+
 func (h *Handler) GetDatasourceStats(c *gin.Context) {
 	stats, err := h.repo.Stats(c.Request.Context())
 	if err != nil {
@@ -537,7 +997,9 @@ func toAPIDatasource(c *Connection) api.Datasource {
 	if len(c.Tags) > 0 {
 		meta["tags"] = c.Tags
 	}
+	version := c.Version
 	conn := api.Datasource{
+		Version:   &version,
 		Uid:       uuid.MustParse(c.ID),
 		Name:      c.Name,
 		Type:      string(c.Type),
@@ -546,6 +1008,48 @@ func toAPIDatasource(c *Connection) api.Datasource {
 		CreatedAt: c.CreatedAt,
 		UpdatedAt: c.UpdatedAt,
 	}
+	if c.Environment != "" {
+		env := c.Environment
+		conn.Environment = &env
+	}
+	if c.Alias != "" {
+		alias := c.Alias
+		conn.Alias = &alias
+	}
+	if c.Deprecation != nil {
+		conn.Deprecation = toAPIDeprecation(c.Deprecation)
+	}
+	if c.ExternalID != "" {
+		ext := c.ExternalID
+		conn.ExternalId = &ext
+	}
+	if c.QueryTimeout > 0 {
+		qt := c.QueryTimeout
+		conn.QueryTimeout = &qt
+	}
+	if c.Maintenance != nil {
+		conn.Maintenance = toAPIMaintenance(c.Maintenance)
+	}
+	if !c.NetworkPolicy.Empty() {
+		conn.NetworkPolicy = toAPINetworkPolicy(c.NetworkPolicy)
+	}
+	if c.CostModel != nil {
+		conn.CostModel = toAPICostModel(c.CostModel)
+	}
+	if len(c.Replicas) > 0 {
+		replicas := make([]api.DatasourceReplica, len(c.Replicas))
+		for i, r := range c.Replicas {
+			replicas[i] = api.DatasourceReplica{Name: r.Name, Options: r.Config}
+		}
+		conn.Replicas = &replicas
+	}
+	if c.TemplateID != "" {
+		if tid, err := uuid.Parse(c.TemplateID); err == nil {
+			conn.TemplateUid = &tid
+		}
+		overrides := c.Overrides
+		conn.Overrides = &overrides
+	}
 	if len(meta) > 0 {
 		conn.Meta = &meta
 	}
@@ -588,7 +1092,7 @@ func (h *Handler) ListDatasourceHistory(c *gin.Context, params api.ListDatasourc
 	limit, offset := historyPage(params.Limit, params.Offset)
 	records, err := h.historyRepo.List(c.Request.Context(), limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: "failed to list datasource history"})
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: i18n.T(c, "failed to list datasource history")})
 		return
 	}
 	resp := make([]api.DatasourceHistory, len(records))
@@ -603,7 +1107,7 @@ func (h *Handler) ListDatasourceHistoryByDatasource(c *gin.Context, id openapi_t
 	limit, offset := historyPage(params.Limit, params.Offset)
 	records, err := h.historyRepo.ListByConnection(c.Request.Context(), id.String(), limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: "failed to list datasource history"})
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: i18n.T(c, "failed to list datasource history")})
 		return
 	}
 	resp := make([]api.DatasourceHistory, len(records))
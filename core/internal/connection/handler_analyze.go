@@ -0,0 +1,288 @@
+package connection
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+	qb "data-voyager/core/internal/query_builder"
+	"data-voyager/sdk"
+)
+
+const (
+	defaultAnalyzeSample = 10000
+	maxAnalyzeSample     = 100000
+	maxAnalyzeColumns    = 20
+)
+
+// AnalyzeDatasource computes descriptive statistics and a correlation matrix
+// for numeric columns. Dialects with the needed aggregates do the work in a
+// single SQL scan; others are sampled and summarized here.
+func (h *Handler) AnalyzeDatasource(c *gin.Context, id openapi_types.UUID) {
+	if !requirePermission(c, identity.PermDatasourceQuery) {
+		return
+	}
+	var body api.AnalyzeRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if len(body.Columns) == 0 || len(body.Columns) > maxAnalyzeColumns {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: fmt.Sprintf("columns must list between 1 and %d columns", maxAnalyzeColumns)})
+		return
+	}
+	for i, col := range body.Columns {
+		if col == "" || slices.Contains(body.Columns[:i], col) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: fmt.Sprintf("column %q is empty or repeated", col)})
+			return
+		}
+	}
+	sample := defaultAnalyzeSample
+	if body.SampleLimit != nil {
+		sample = *body.SampleLimit
+	}
+	if sample < 1 || sample > maxAnalyzeSample {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: fmt.Sprintf("sample_limit must be between 1 and %d", maxAnalyzeSample)})
+		return
+	}
+
+	sq := qb.StatsQuery{Columns: body.Columns}
+	if body.Schema != nil {
+		sq.Schema = *body.Schema
+	}
+	if body.Table != nil {
+		sq.Table = *body.Table
+	}
+	if body.Query != nil {
+		sq.Query = *body.Query
+	}
+
+	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return
+	}
+	dialect := qb.DialectFor(string(conn.Type))
+	method := api.Sql
+	query, err := sq.Build(dialect)
+	if !sq.Supports(dialect) {
+		method = api.Sample
+		query, err = sq.SampleQuery(dialect, sample)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	dbConn, ok := h.openDatasource(c, conn)
+	if !ok {
+		return
+	}
+	defer func() { _ = dbConn.Close() }()
+
+	ctx, cancel, timeout := h.withQueryDeadline(c.Request.Context(), conn, nil)
+	defer cancel()
+	result, err := dbConn.Query(ctx, query)
+	if err != nil {
+		if deadlineExceeded(ctx, err) {
+			c.JSON(http.StatusGatewayTimeout, queryTimeoutError(timeout))
+			return
+		}
+		c.JSON(queryErrorStatus(err), datasourceError("query failed", err))
+		return
+	}
+	if len(result.Frames) == 0 {
+		c.JSON(http.StatusBadGateway, api.ErrorResponse{Error: "analysis returned no result"})
+		return
+	}
+
+	var out *api.AnalyzeResult
+	if method == api.Sql {
+		out, err = statsFromAggregate(result.Frames[0], body.Columns)
+	} else {
+		out, err = statsFromSample(result.Frames[0], body.Columns)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	out.Method = method
+	c.JSON(http.StatusOK, api.AnalyzeResponse{Data: *out, Warnings: queryWarnings(conn)})
+}
+
+// statsFromAggregate reads the single row produced by qb.StatsQuery.Build.
+func statsFromAggregate(f *sdk.DataFrame, columns []string) (*api.AnalyzeResult, error) {
+	row := make(map[string]any, len(f.Fields))
+	for _, fd := range f.Fields {
+		if len(fd.Values) != 1 {
+			return nil, fmt.Errorf("analysis returned %d rows, want 1", len(fd.Values))
+		}
+		row[fd.Name] = fd.Values[0]
+	}
+	num := func(name string) *float64 {
+		if v, ok := sdk.Float64Value(row[name]); ok && !math.IsNaN(v) && !math.IsInf(v, 0) {
+			return &v
+		}
+		return nil
+	}
+	count := func(name string) int64 {
+		if v := num(name); v != nil {
+			return int64(*v)
+		}
+		return 0
+	}
+
+	out := &api.AnalyzeResult{Rows: count("row_count")}
+	for i, name := range columns {
+		p := fmt.Sprintf("c%d_", i)
+		n := count(p + "count")
+		out.Columns = append(out.Columns, api.ColumnStats{
+			Name:   name,
+			Count:  n,
+			Nulls:  out.Rows - n,
+			Min:    num(p + "min"),
+			Max:    num(p + "max"),
+			Mean:   num(p + "mean"),
+			Stddev: num(p + "stddev"),
+			P25:    num(p + "p25"),
+			Median: num(p + "median"),
+			P75:    num(p + "p75"),
+		})
+	}
+	out.Correlation = correlationMatrix(out.Columns, func(i, j int) *float64 {
+		return num(fmt.Sprintf("corr_%d_%d", i, j))
+	})
+	return out, nil
+}
+
+// statsFromSample summarizes raw sampled rows, matching the SQL aggregates:
+// sample standard deviation and linearly interpolated quartiles.
+func statsFromSample(f *sdk.DataFrame, columns []string) (*api.AnalyzeResult, error) {
+	if len(f.Fields) != len(columns) {
+		return nil, fmt.Errorf("analysis returned %d columns, want %d", len(f.Fields), len(columns))
+	}
+	rows := 0
+	if len(f.Fields) > 0 {
+		rows = len(f.Fields[0].Values)
+	}
+
+	// vals[i][r] is column i of row r; NaN marks null.
+	vals := make([][]float64, len(columns))
+	for i, fd := range f.Fields {
+		vals[i] = make([]float64, rows)
+		for r, v := range fd.Values {
+			if v == nil {
+				vals[i][r] = math.NaN()
+				continue
+			}
+			x, ok := sdk.Float64Value(v)
+			if !ok {
+				return nil, fmt.Errorf("column %q is not numeric", columns[i])
+			}
+			vals[i][r] = x
+		}
+	}
+
+	out := &api.AnalyzeResult{Rows: int64(rows)}
+	for i, name := range columns {
+		var xs []float64
+		for _, x := range vals[i] {
+			if !math.IsNaN(x) {
+				xs = append(xs, x)
+			}
+		}
+		cs := api.ColumnStats{Name: name, Count: int64(len(xs)), Nulls: int64(rows - len(xs))}
+		if len(xs) > 0 {
+			slices.Sort(xs)
+			mean := 0.0
+			for _, x := range xs {
+				mean += x
+			}
+			mean /= float64(len(xs))
+			cs.Min, cs.Max, cs.Mean = &xs[0], &xs[len(xs)-1], &mean
+			if len(xs) > 1 {
+				ss := 0.0
+				for _, x := range xs {
+					ss += (x - mean) * (x - mean)
+				}
+				sd := math.Sqrt(ss / float64(len(xs)-1))
+				cs.Stddev = &sd
+			}
+			p25, med, p75 := percentile(xs, 0.25), percentile(xs, 0.5), percentile(xs, 0.75)
+			cs.P25, cs.Median, cs.P75 = &p25, &med, &p75
+		}
+		out.Columns = append(out.Columns, cs)
+	}
+	out.Correlation = correlationMatrix(out.Columns, func(i, j int) *float64 {
+		return pearson(vals[i], vals[j])
+	})
+	return out, nil
+}
+
+// correlationMatrix assembles a symmetric matrix from the upper-triangle
+// coefficients returned by corr(i, j) for i < j. The diagonal is 1 for every
+// column that varies and null for constant or empty ones.
+func correlationMatrix(cols []api.ColumnStats, corr func(i, j int) *float64) api.CorrelationMatrix {
+	n := len(cols)
+	names := make([]string, n)
+	m := make([][]*float64, n)
+	for i := range m {
+		names[i] = cols[i].Name
+		m[i] = make([]*float64, n)
+	}
+	for i := 0; i < n; i++ {
+		if sd := cols[i].Stddev; sd != nil && *sd > 0 {
+			one := 1.0
+			m[i][i] = &one
+		}
+		for j := i + 1; j < n; j++ {
+			m[i][j] = corr(i, j)
+			m[j][i] = m[i][j]
+		}
+	}
+	return api.CorrelationMatrix{Columns: names, Matrix: m}
+}
+
+// percentile interpolates linearly between the closest ranks of sorted xs,
+// like percentile_cont.
+func percentile(xs []float64, p float64) float64 {
+	pos := p * float64(len(xs)-1)
+	lo := int(math.Floor(pos))
+	if lo+1 >= len(xs) {
+		return xs[lo]
+	}
+	return xs[lo] + (pos-float64(lo))*(xs[lo+1]-xs[lo])
+}
+
+// pearson correlates a and b over the rows where both are non-null.
+func pearson(a, b []float64) *float64 {
+	var n, sa, sb, saa, sbb, sab float64
+	for r := range a {
+		if math.IsNaN(a[r]) || math.IsNaN(b[r]) {
+			continue
+		}
+		n++
+		sa += a[r]
+		sb += b[r]
+		saa += a[r] * a[r]
+		sbb += b[r] * b[r]
+		sab += a[r] * b[r]
+	}
+	if n < 2 {
+		return nil
+	}
+	cov := sab - sa*sb/n
+	va, vb := saa-sa*sa/n, sbb-sb*sb/n
+	if va <= 0 || vb <= 0 {
+		return nil
+	}
+	r := cov / math.Sqrt(va*vb)
+	return &r
+}
@@ -0,0 +1,108 @@
+package connection
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/sdk"
+)
+
+func postAnalyze(h *Handler, body api.AnalyzeRequest) *httptest.ResponseRecorder {
+	raw, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/datasources/1/analyze", bytes.NewReader(raw))
+	c.Request.Header.Set("Content-Type", "application/json")
+	h.AnalyzeDatasource(c, uuid.MustParse(testConnID))
+	return w
+}
+
+func TestAnalyzeDatasource_Sample(t *testing.T) {
+	// The mock plugin has no SQL dialect, so statistics come from a sample.
+	result := &sdk.QueryResult{Frames: []*sdk.DataFrame{{Fields: []sdk.Field{
+		{Name: "x", Values: []any{int64(1), int64(2), int64(3), int64(4), nil}},
+		{Name: "y", Values: []any{2.0, 4.0, 6.0, 8.0, 1.0}},
+		{Name: "z", Values: []any{"7", "7", "7", "7", "7"}},
+	}}}}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &mockConn{result: result}})
+
+	points := "points"
+	w := postAnalyze(h, api.AnalyzeRequest{Table: &points, Columns: []string{"x", "y", "z"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp api.AnalyzeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	d := resp.Data
+	assert.Equal(t, api.Sample, d.Method)
+	assert.Equal(t, int64(5), d.Rows)
+
+	x := d.Columns[0]
+	assert.Equal(t, int64(4), x.Count)
+	assert.Equal(t, int64(1), x.Nulls)
+	assert.Equal(t, 2.5, *x.Mean)
+	assert.Equal(t, 1.75, *x.P25)
+	assert.Equal(t, 2.5, *x.Median)
+	assert.InDelta(t, 1.2910, *x.Stddev, 1e-4)
+
+	m := d.Correlation.Matrix
+	assert.InDelta(t, 1.0, *m[0][1], 1e-9, "x and y are perfectly correlated where both are present")
+	assert.Equal(t, m[0][1], m[1][0])
+	assert.Equal(t, 1.0, *m[0][0])
+	assert.Nil(t, m[2][2], "a constant column has no correlation")
+	assert.Nil(t, m[0][2])
+}
+
+func TestAnalyzeDatasource_NonNumeric(t *testing.T) {
+	result := &sdk.QueryResult{Frames: []*sdk.DataFrame{{Fields: []sdk.Field{
+		{Name: "name", Values: []any{"alice"}},
+	}}}}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &mockConn{result: result}})
+
+	users := "users"
+	w := postAnalyze(h, api.AnalyzeRequest{Table: &users, Columns: []string{"name"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assertErrorContains(t, w, "not numeric")
+}
+
+func TestAnalyzeDatasource_Validation(t *testing.T) {
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &mockConn{}})
+
+	points := "points"
+	w := postAnalyze(h, api.AnalyzeRequest{Table: &points, Columns: []string{"a", "a"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = postAnalyze(h, api.AnalyzeRequest{Columns: []string{"a"}})
+	assertErrorContains(t, w, "table or query is required")
+}
+
+func TestStatsFromAggregate(t *testing.T) {
+	f := &sdk.DataFrame{Fields: []sdk.Field{
+		{Name: "row_count", Values: []any{int64(10)}},
+		{Name: "c0_count", Values: []any{uint64(8)}},
+		{Name: "c0_min", Values: []any{"1.5"}},
+		{Name: "c0_max", Values: []any{9.0}},
+		{Name: "c0_stddev", Values: []any{2.0}},
+		{Name: "c0_median", Values: []any{nil}},
+		{Name: "c1_count", Values: []any{int64(0)}},
+		{Name: "corr_0_1", Values: []any{nil}},
+	}}
+	out, err := statsFromAggregate(f, []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), out.Rows)
+	assert.Equal(t, int64(2), out.Columns[0].Nulls)
+	assert.Equal(t, 1.5, *out.Columns[0].Min)
+	assert.Nil(t, out.Columns[0].Median)
+	assert.Equal(t, int64(10), out.Columns[1].Nulls)
+	assert.Equal(t, 1.0, *out.Correlation.Matrix[0][0])
+	assert.Nil(t, out.Correlation.Matrix[1][1])
+	assert.Nil(t, out.Correlation.Matrix[0][1])
+}
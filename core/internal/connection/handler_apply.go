@@ -0,0 +1,187 @@
+package connection
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+	"data-voyager/sdk"
+)
+
+// GetDatasourceByExternalId resolves an external ID, e.g. for
+// `terraform import`.
+func (h *Handler) GetDatasourceByExternalId(c *gin.Context, externalID string) {
+	conn, err := h.repo.GetByExternalID(c.Request.Context(), externalID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return
+	}
+	c.Header("ETag", etag(conn))
+	c.JSON(http.StatusOK, api.DatasourceResponse{Data: toAPIDatasource(conn)})
+}
+
+// ApplyDatasource creates or replaces the datasource with the given external
+// ID so that applying the same definition twice is a no-op.
+func (h *Handler) ApplyDatasource(c *gin.Context, externalID string, params api.ApplyDatasourceParams) {
+	if !requirePermission(c, identity.PermDatasourceWrite) {
+		return
+	}
+	var body api.ApplyDatasourceRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if body.Environment != nil && !body.Environment.Valid() {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: i18n.T(c, "invalid environment")})
+		return
+	}
+
+	existing, err := h.repo.GetByExternalID(c.Request.Context(), externalID)
+	if err != nil {
+		existing = nil
+	}
+	if existing == nil && params.IfMatch != nil {
+		c.JSON(http.StatusPreconditionFailed, api.ErrorResponse{Error: i18n.T(c, "datasource does not exist")})
+		return
+	}
+	if existing != nil && !checkIfMatch(c, params.IfMatch, existing) {
+		return
+	}
+
+	desired := &Connection{
+		Name:        body.Name,
+		Type:        sdk.DataSourceType(body.Type),
+		Description: metaString(body.Meta, "description"),
+		Tags:        metaStringSlice(body.Meta, "tags"),
+		CreatedBy:   metaString(body.Meta, "createdBy"),
+		IsActive:    body.Enabled == nil || *body.Enabled,
+		ExternalID:  externalID,
+	}
+	if body.Environment != nil {
+		desired.Environment = string(*body.Environment)
+	}
+	if err := validQueryTimeout(body.QueryTimeout); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if body.QueryTimeout != nil {
+		desired.QueryTimeout = *body.QueryTimeout
+	}
+	if existing != nil && existing.Type != desired.Type {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: fmt.Sprintf("type cannot change from %q to %q; delete and recreate the datasource", existing.Type, desired.Type)})
+		return
+	}
+	if body.TemplateUid != nil {
+		tmpl, ok := h.loadTemplate(c, body.TemplateUid.String())
+		if !ok {
+			return
+		}
+		if tmpl.Type != desired.Type {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: fmt.Sprintf("template type %q does not match datasource type %q", tmpl.Type, desired.Type)})
+			return
+		}
+		desired.TemplateID = tmpl.ID
+	}
+	if !h.applyOptions(c, desired, body.Options) {
+		return
+	}
+	selfID := ""
+	if existing != nil {
+		selfID = existing.ID
+	}
+	if !h.checkNameAvailable(c, desired.Name, selfID) {
+		return
+	}
+
+	if existing == nil {
+		if err := h.repo.Create(c.Request.Context(), desired); err != nil {
+			c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+			return
+		}
+		h.recordHistory(c.Request.Context(), desired.ID, desired.Name, string(desired.Type), "created")
+		c.Header("ETag", etag(desired))
+		c.JSON(http.StatusCreated, api.DatasourceResponse{Data: toAPIDatasource(desired)})
+		return
+	}
+
+	if !sameDefinition(existing, desired) {
+		existing.Name = desired.Name
+		existing.Description = desired.Description
+		existing.Tags = desired.Tags
+		if desired.CreatedBy != "" {
+			existing.CreatedBy = desired.CreatedBy
+		}
+		existing.IsActive = desired.IsActive
+		existing.Environment = desired.Environment
+		existing.TemplateID = desired.TemplateID
+		existing.QueryTimeout = desired.QueryTimeout
+		existing.Config = desired.Config
+		existing.Overrides = desired.Overrides
+		if !h.update(c, existing) {
+			return
+		}
+	}
+	c.Header("ETag", etag(existing))
+	c.JSON(http.StatusOK, api.DatasourceResponse{Data: toAPIDatasource(existing)})
+}
+
+// update persists conn, mapping a lost optimistic-concurrency race to 412.
+func (h *Handler) update(c *gin.Context, conn *Connection) bool {
+	if err := h.repo.Update(c.Request.Context(), conn); err != nil {
+		if errors.Is(err, ErrVersionConflict) {
+			c.JSON(http.StatusPreconditionFailed, api.ErrorResponse{Error: err.Error()})
+			return false
+		}
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return false
+	}
+	h.recordHistory(c.Request.Context(), conn.ID, conn.Name, string(conn.Type), "updated")
+	return true
+}
+
+// sameDefinition reports whether applying b over a would change anything a
+// client can set.
+func sameDefinition(a, b *Connection) bool {
+	return a.Name == b.Name &&
+		a.Description == b.Description &&
+		slices.Equal(a.Tags, b.Tags) &&
+		(b.CreatedBy == "" || a.CreatedBy == b.CreatedBy) &&
+		a.IsActive == b.IsActive &&
+		a.Environment == b.Environment &&
+		a.TemplateID == b.TemplateID &&
+		a.QueryTimeout == b.QueryTimeout &&
+		bytes.Equal(a.Config, b.Config) &&
+		bytes.Equal(a.Overrides, b.Overrides)
+}
+
+// etag renders the connection version as a strong entity tag.
+func etag(conn *Connection) string {
+	return strconv.Quote(strconv.FormatInt(conn.Version, 10))
+}
+
+// checkIfMatch enforces an If-Match precondition, writing 412 on mismatch.
+// A missing header or "*" always passes.
+func checkIfMatch(c *gin.Context, ifMatch *string, conn *Connection) bool {
+	if ifMatch == nil {
+		return true
+	}
+	current := etag(conn)
+	for _, tag := range strings.Split(*ifMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+	c.Header("ETag", current)
+	c.JSON(http.StatusPreconditionFailed, api.ErrorResponse{Error: i18n.T(c, "datasource has been modified; re-read it and retry")})
+	return false
+}
@@ -0,0 +1,159 @@
+package connection
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"data-voyager/core/internal/api"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// versionedRepo is an in-memory Repository with the stores' optimistic
+// concurrency behaviour.
+type versionedRepo struct {
+	mockRepo
+	byID map[string]*Connection
+}
+
+func newVersionedRepo() *versionedRepo { return &versionedRepo{byID: map[string]*Connection{}} }
+
+func (r *versionedRepo) Create(_ context.Context, c *Connection) error {
+	c.ID = uuid.NewString()
+	c.Version = 1
+	cp := *c
+	r.byID[c.ID] = &cp
+	return nil
+}
+
+func (r *versionedRepo) GetByExternalID(_ context.Context, ext string) (*Connection, error) {
+	for _, c := range r.byID {
+		if c.ExternalID == ext {
+			cp := *c
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *versionedRepo) GetByID(_ context.Context, id string) (*Connection, error) {
+	c, ok := r.byID[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (r *versionedRepo) GetByName(_ context.Context, name string) (*Connection, error) {
+	for _, c := range r.byID {
+		if c.Name == name {
+			cp := *c
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *versionedRepo) Update(_ context.Context, c *Connection) error {
+	stored, ok := r.byID[c.ID]
+	if !ok || stored.Version != c.Version {
+		return ErrVersionConflict
+	}
+	c.Version++
+	cp := *c
+	r.byID[c.ID] = &cp
+	return nil
+}
+
+func apply(h *Handler, ext string, body any, ifMatch *string) *httptest.ResponseRecorder {
+	raw, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPut, "/datasources/external/"+ext, bytes.NewReader(raw))
+	c.Request.Header.Set("Content-Type", "application/json")
+	h.ApplyDatasource(c, ext, api.ApplyDatasourceParams{IfMatch: ifMatch})
+	return w
+}
+
+func applyBody(host string) map[string]any {
+	return map[string]any{"name": "orders", "type": "mock", "options": map[string]any{"host": host}}
+}
+
+func TestApplyDatasource_Idempotent(t *testing.T) {
+	repo := newVersionedRepo()
+	h := newHandler(repo, &mockPlugin{})
+
+	w := apply(h, "tf-orders", applyBody("db1"), nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+
+	// Re-applying the same definition changes nothing.
+	w = apply(h, "tf-orders", applyBody("db1"), nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	assert.Len(t, repo.byID, 1)
+
+	etag := `"1"`
+	w = apply(h, "tf-orders", applyBody("db2"), &etag)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+
+	var resp api.DatasourceResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Data.ExternalId)
+	assert.Equal(t, "tf-orders", *resp.Data.ExternalId)
+	assert.JSONEq(t, `{"host":"db2"}`, string(resp.Data.Options))
+}
+
+func TestApplyDatasource_StaleIfMatch(t *testing.T) {
+	repo := newVersionedRepo()
+	h := newHandler(repo, &mockPlugin{})
+	require.Equal(t, http.StatusCreated, apply(h, "tf-orders", applyBody("db1"), nil).Code)
+	require.Equal(t, http.StatusOK, apply(h, "tf-orders", applyBody("db2"), nil).Code)
+
+	stale := `"1"`
+	w := apply(h, "tf-orders", applyBody("db3"), &stale)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+
+	// If-Match on a resource that does not exist yet cannot succeed.
+	w = apply(h, "tf-missing", applyBody("db1"), &stale)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+}
+
+func TestApplyDatasource_TypeIsImmutable(t *testing.T) {
+	repo := newVersionedRepo()
+	h := newHandler(repo, &mockPlugin{})
+	require.Equal(t, http.StatusCreated, apply(h, "tf-orders", applyBody("db1"), nil).Code)
+
+	body := applyBody("db1")
+	body["type"] = "other"
+	w := apply(h, "tf-orders", body, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUpdateDatasource_LostRace(t *testing.T) {
+	repo := newVersionedRepo()
+	h := newHandler(repo, &mockPlugin{})
+	require.Equal(t, http.StatusCreated, apply(h, "tf-orders", applyBody("db1"), nil).Code)
+	conn, err := repo.GetByExternalID(context.Background(), "tf-orders")
+	require.NoError(t, err)
+
+	// Simulate a concurrent writer between read and write.
+	conn.Version = 0
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPut, "/datasources/"+conn.ID, nil)
+	assert.False(t, h.update(c, conn))
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+}
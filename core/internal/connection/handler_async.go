@@ -0,0 +1,152 @@
+package connection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/resultstore"
+)
+
+// WithResultStore enables async queries, keeping their results in s.
+func (h *Handler) WithResultStore(s *resultstore.Store) *Handler {
+	h.results = s
+	return h
+}
+
+// SubmitAsyncQuery validates and renders the query like QueryDatasource,
+// then runs it after answering 202. The run outlives the request but keeps
+// its identity, so usage and permissions are attributed to the submitter.
+func (h *Handler) SubmitAsyncQuery(c *gin.Context, id openapi_types.UUID) {
+	if h.results == nil {
+		c.JSON(http.StatusNotImplemented, api.ErrorResponse{Error: i18n.T(c, "async queries are not enabled")})
+		return
+	}
+	q, ok := h.prepareQuery(c, id)
+	if !ok {
+		return
+	}
+	entry, err := h.results.Start(q.conn.ID, callerName(c.Request.Context()), pinnedRequest(q))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	go h.runAsync(context.WithoutCancel(c.Request.Context()), q, entry.ID)
+	c.JSON(http.StatusAccepted, api.AsyncQueryResponse{Data: toAPIAsyncQuery(entry)})
+}
+
+// pinnedRequest is the request behind q with its time range resolved to the
+// instants it ran over, so running it again reads the same window.
+func pinnedRequest(q *preparedQuery) []byte {
+	body := q.body
+	if body.TimeRange != nil {
+		tr := *body.TimeRange
+		if !q.timeRange.From.IsZero() {
+			from := q.timeRange.From.UTC().Format(time.RFC3339Nano)
+			tr.From = &from
+		}
+		if !q.timeRange.To.IsZero() {
+			to := q.timeRange.To.UTC().Format(time.RFC3339Nano)
+			tr.To = &to
+		}
+		body.TimeRange = &tr
+	}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil
+	}
+	return raw
+}
+
+func (h *Handler) runAsync(ctx context.Context, q *preparedQuery, id string) {
+	resp, _, fail := h.runQuery(ctx, q)
+	status, code := resultstore.StatusSucceeded, http.StatusOK
+	var v any = resp
+	if fail != nil {
+		status, code, v = resultstore.StatusFailed, fail.status, fail.resp
+	}
+	body, err := json.Marshal(v)
+	if err != nil {
+		status, code = resultstore.StatusFailed, http.StatusInternalServerError
+		body, _ = json.Marshal(api.ErrorResponse{Error: err.Error()})
+	}
+	h.results.Finish(ctx, id, status, code, body)
+}
+
+// GetAsyncQuery reports the status of an async query.
+func (h *Handler) GetAsyncQuery(c *gin.Context, id string) {
+	entry, ok := h.asyncEntry(c, id)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, api.AsyncQueryResponse{Data: toAPIAsyncQuery(entry)})
+}
+
+// GetAsyncQueryResult returns the stored response of a finished async query
+// as it was produced, reading it back from object storage if it was spilled.
+func (h *Handler) GetAsyncQueryResult(c *gin.Context, id string) {
+	entry, ok := h.asyncEntry(c, id)
+	if !ok {
+		return
+	}
+	body, err := h.results.Body(c.Request.Context(), entry.ID)
+	switch {
+	case errors.Is(err, resultstore.ErrNotReady):
+		c.JSON(http.StatusConflict, api.ErrorResponse{Error: i18n.T(c, "query is still running")})
+		return
+	case errors.Is(err, resultstore.ErrNotFound):
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "query result not found")})
+		return
+	case err != nil:
+		c.JSON(http.StatusBadGateway, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Data(entry.Code, "application/json; charset=utf-8", body)
+}
+
+// asyncEntry looks up id, hiding other users' results from non-admins.
+func (h *Handler) asyncEntry(c *gin.Context, id string) (resultstore.Entry, bool) {
+	if h.results == nil {
+		c.JSON(http.StatusNotImplemented, api.ErrorResponse{Error: i18n.T(c, "async queries are not enabled")})
+		return resultstore.Entry{}, false
+	}
+	entry, err := h.results.Get(id)
+	caller := identity.FromContext(c.Request.Context())
+	if err != nil || (entry.Owner != callerName(c.Request.Context()) && !caller.Can(identity.PermAdmin)) {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "query result not found")})
+		return resultstore.Entry{}, false
+	}
+	return entry, true
+}
+
+func callerName(ctx context.Context) string {
+	if id := identity.FromContext(ctx); id != nil {
+		return id.Username
+	}
+	return ""
+}
+
+func toAPIAsyncQuery(e resultstore.Entry) api.AsyncQuery {
+	out := api.AsyncQuery{
+		Id:            e.ID,
+		DatasourceUid: uuid.MustParse(e.DatasourceID),
+		Status:        api.AsyncQueryStatus(e.Status),
+		CreatedAt:     e.CreatedAt,
+		ExpiresAt:     e.ExpiresAt,
+		Archived:      e.Location != "",
+	}
+	if e.Status != resultstore.StatusRunning {
+		completed, size := e.CompletedAt, e.Size
+		out.CompletedAt, out.SizeBytes = &completed, &size
+	}
+	return out
+}
@@ -0,0 +1,90 @@
+package connection
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/resultstore"
+	"data-voyager/sdk"
+)
+
+func submitAsync(h *Handler, id *identity.Identity, body any) *httptest.ResponseRecorder {
+	raw, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/datasources/1/query/async", bytes.NewReader(raw))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request = c.Request.WithContext(identity.With(c.Request.Context(), id))
+	h.SubmitAsyncQuery(c, uuid.MustParse(testConnID))
+	return w
+}
+
+func TestSubmitAsyncQuery_NotEnabled(t *testing.T) {
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &mockConn{}})
+	w := submitAsync(h, nil, api.QueryRequest{Query: "SELECT 1"})
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
+
+func TestAsyncQuery_SubmitPollFetch(t *testing.T) {
+	mc := &mockConn{result: &sdk.QueryResult{Frames: []*sdk.DataFrame{{
+		FrameType: sdk.FrameTypeTable,
+		Fields:    []sdk.Field{{Name: "n", Kind: sdk.FieldKindNumber, Type: "int8", Values: []any{int64(1)}}},
+	}}}}
+	results := resultstore.New(config.AsyncResultsConfig{TTL: 60}, nil)
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: mc}).WithResultStore(results)
+	ann := &identity.Identity{Username: "ann", Role: identity.RoleViewer}
+
+	w := submitAsync(h, ann, api.QueryRequest{Query: "SELECT 1"})
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var submitted api.AsyncQueryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &submitted))
+	id := submitted.Data.Id
+
+	require.Eventually(t, func() bool {
+		e, err := results.Get(id)
+		return err == nil && e.Status != resultstore.StatusRunning
+	}, time.Second, 10*time.Millisecond)
+
+	c, w := requestAs(ann, http.MethodGet, "/query-results/"+id)
+	h.GetAsyncQuery(c, id)
+	require.Equal(t, http.StatusOK, w.Code)
+	var status api.AsyncQueryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.Equal(t, api.AsyncQueryStatus("succeeded"), status.Data.Status)
+	assert.False(t, status.Data.Archived)
+
+	c, w = requestAs(ann, http.MethodGet, "/query-results/"+id+"/data")
+	h.GetAsyncQueryResult(c, id)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp api.QueryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data.Frames, 1)
+
+	// Other users cannot see ann's result.
+	c, w = requestAs(&identity.Identity{Username: "bob", Role: identity.RoleViewer}, http.MethodGet, "/query-results/"+id)
+	h.GetAsyncQuery(c, id)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetAsyncQueryResult_NotReady(t *testing.T) {
+	results := resultstore.New(config.AsyncResultsConfig{TTL: 60}, nil)
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{}).WithResultStore(results)
+	entry, err := results.Start(testConnID, "", nil)
+	require.NoError(t, err)
+
+	c, w := requestAs(nil, http.MethodGet, "/query-results/"+entry.ID+"/data")
+	h.GetAsyncQueryResult(c, entry.ID)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
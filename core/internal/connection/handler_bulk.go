@@ -0,0 +1,136 @@
+package connection
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// maxBulkOperations bounds one bulk request, matching the OpenAPI maxItems.
+const maxBulkOperations = 100
+
+// BulkDatasources handles POST /datasources/bulk. Each operation is
+// dispatched to the same handler as its single-item endpoint, so validation,
+// history and reference checks behave identically; only the response is
+// collected per item instead of written.
+func (h *Handler) BulkDatasources(c *gin.Context) {
+	if !requirePermission(c, identity.PermDatasourceWrite) {
+		return
+	}
+	var body api.BulkDatasourceRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if len(body.Operations) == 0 || len(body.Operations) > maxBulkOperations {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: fmt.Sprintf("operations must contain between 1 and %d items", maxBulkOperations)})
+		return
+	}
+
+	resp := api.BulkDatasourceResponse{Data: make([]api.BulkDatasourceResult, len(body.Operations))}
+	for i, op := range body.Operations {
+		result := h.runBulkOperation(c, op)
+		result.Index = i
+		if result.Status < 300 {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+		resp.Data[i] = result
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) runBulkOperation(c *gin.Context, op api.BulkDatasourceOperation) api.BulkDatasourceResult {
+	result := api.BulkDatasourceResult{Op: string(op.Op), Uid: op.Uid}
+	fail := func(status int, msg string) api.BulkDatasourceResult {
+		result.Status, result.Error = status, &msg
+		return result
+	}
+	if op.Op != api.Create && op.Uid == nil {
+		return fail(http.StatusBadRequest, i18n.T(c, "uid is required"))
+	}
+
+	var sub *gin.Context
+	var w *bulkResponseWriter
+	switch op.Op {
+	case api.Create:
+		if op.Datasource == nil {
+			return fail(http.StatusBadRequest, i18n.T(c, "datasource is required for create"))
+		}
+		sub, w = subRequest(c, op.Datasource)
+		h.CreateDatasource(sub)
+	case api.Update:
+		if op.Changes == nil {
+			return fail(http.StatusBadRequest, i18n.T(c, "changes are required for update"))
+		}
+		sub, w = subRequest(c, op.Changes)
+		h.UpdateDatasource(sub, *op.Uid, api.UpdateDatasourceParams{})
+	case api.Activate, api.Deactivate:
+		enabled := op.Op == api.Activate
+		sub, w = subRequest(c, api.UpdateDatasourceRequest{Enabled: &enabled})
+		h.UpdateDatasource(sub, *op.Uid, api.UpdateDatasourceParams{})
+	case api.Delete:
+		sub, w = subRequest(c, nil)
+		h.DeleteDatasource(sub, *op.Uid, api.DeleteDatasourceParams{Force: op.Force})
+	default:
+		return fail(http.StatusBadRequest, fmt.Sprintf("unknown op %q", op.Op))
+	}
+
+	result.Status = sub.Writer.Status()
+	if result.Status >= 300 {
+		var e api.ErrorResponse
+		_ = json.Unmarshal(w.body.Bytes(), &e)
+		result.Error, result.ErrorCode = &e.Error, e.Code
+		return result
+	}
+	if w.body.Len() > 0 {
+		var d api.DatasourceResponse
+		if err := json.Unmarshal(w.body.Bytes(), &d); err == nil {
+			result.Data = &d.Data
+			if result.Uid == nil {
+				uid := d.Data.Uid
+				result.Uid = &uid
+			}
+		}
+	}
+	return result
+}
+
+// subRequest builds a context for dispatching one bulk item to a single-item
+// handler. It shares the parent's request context and keys (locale, auth) and
+// captures the response instead of writing it.
+func subRequest(c *gin.Context, body any) (*gin.Context, *bulkResponseWriter) {
+	var raw []byte
+	if body != nil {
+		raw, _ = json.Marshal(body)
+	}
+	req, _ := http.NewRequestWithContext(c.Request.Context(), http.MethodPost, c.Request.URL.Path, bytes.NewReader(raw))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := &bulkResponseWriter{header: http.Header{}}
+	sub, _ := gin.CreateTestContext(w)
+	sub.Request = req
+	for k, v := range c.Keys {
+		sub.Set(k, v)
+	}
+	return sub, w
+}
+
+// bulkResponseWriter is an in-memory http.ResponseWriter; the status is read
+// from the gin writer wrapping it.
+type bulkResponseWriter struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (w *bulkResponseWriter) Header() http.Header         { return w.header }
+func (w *bulkResponseWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
+func (w *bulkResponseWriter) WriteHeader(int)             {}
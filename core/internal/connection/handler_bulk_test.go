@@ -0,0 +1,69 @@
+package connection
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"data-voyager/core/internal/api"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bulk(h *Handler, ops ...map[string]any) *httptest.ResponseRecorder {
+	raw, _ := json.Marshal(map[string]any{"operations": ops})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/datasources/bulk", bytes.NewReader(raw))
+	c.Request.Header.Set("Content-Type", "application/json")
+	h.BulkDatasources(c)
+	return w
+}
+
+func TestBulkDatasources_PerItemResults(t *testing.T) {
+	repo := newVersionedRepo()
+	h := newHandler(repo, &mockPlugin{})
+	create := func(name string) map[string]any {
+		return map[string]any{"op": "create", "datasource": map[string]any{
+			"name": name, "type": "mock", "options": map[string]any{"host": "db"},
+		}}
+	}
+
+	w := bulk(h, create("a"), create("b"), create("a"), map[string]any{"op": "delete"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp api.BulkDatasourceResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 4)
+	assert.Equal(t, 2, resp.Succeeded)
+	assert.Equal(t, 2, resp.Failed)
+
+	assert.Equal(t, http.StatusCreated, resp.Data[0].Status)
+	require.NotNil(t, resp.Data[0].Uid)
+	require.NotNil(t, resp.Data[0].Data)
+	assert.Equal(t, "a", resp.Data[0].Data.Name)
+
+	assert.Equal(t, http.StatusConflict, resp.Data[2].Status, "duplicate name fails only its own item")
+	assert.Equal(t, http.StatusBadRequest, resp.Data[3].Status)
+	assert.Equal(t, 3, resp.Data[3].Index)
+	assert.Len(t, repo.byID, 2)
+}
+
+func TestBulkDatasources_Deactivate(t *testing.T) {
+	repo := newVersionedRepo()
+	h := newHandler(repo, &mockPlugin{})
+	conn := &Connection{Name: "a", Type: "mock", IsActive: true}
+	require.NoError(t, repo.Create(t.Context(), conn))
+
+	w := bulk(h, map[string]any{"op": "deactivate", "uid": conn.ID})
+	var resp api.BulkDatasourceResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, http.StatusOK, resp.Data[0].Status, w.Body.String())
+	assert.False(t, repo.byID[conn.ID].IsActive)
+}
@@ -0,0 +1,106 @@
+package connection
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+	"data-voyager/sdk"
+)
+
+// SetDatasourceCostModel sets how queries against the datasource are
+// priced, replacing any existing cost model.
+func (h *Handler) SetDatasourceCostModel(c *gin.Context, id openapi_types.UUID) {
+	if !requirePermission(c, identity.PermDatasourceWrite) {
+		return
+	}
+	var body api.CostModel
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return
+	}
+
+	m := &CostModel{PerTiB: body.PerTib}
+	if body.Currency != nil {
+		m.Currency = *body.Currency
+	}
+	if body.MinBytesBilled != nil {
+		m.MinBytesBilled = *body.MinBytesBilled
+	}
+	if err := m.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	conn.CostModel = m
+	if !h.update(c, conn) {
+		return
+	}
+	c.JSON(http.StatusOK, api.DatasourceResponse{Data: toAPIDatasource(conn)})
+}
+
+// ClearDatasourceCostModel stops pricing queries against the datasource.
+// Costs already recorded in the usage report are kept.
+func (h *Handler) ClearDatasourceCostModel(c *gin.Context, id openapi_types.UUID) {
+	if !requirePermission(c, identity.PermDatasourceWrite) {
+		return
+	}
+	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return
+	}
+	if conn.CostModel != nil {
+		conn.CostModel = nil
+		if !h.update(c, conn) {
+			return
+		}
+	}
+	c.JSON(http.StatusOK, api.DatasourceResponse{Data: toAPIDatasource(conn)})
+}
+
+// EstimateDatasourceQuery renders a query as QueryDatasource would and asks
+// the datasource how much it would scan, without running it.
+func (h *Handler) EstimateDatasourceQuery(c *gin.Context, id openapi_types.UUID) {
+	q, ok := h.prepareQuery(c, id)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	dbConn, _, err := h.connect(ctx, q.plugin, q.conn, allReadOnly(q.sql))
+	if err != nil {
+		c.JSON(http.StatusBadGateway, datasourceError("datasource failed", err))
+		return
+	}
+	defer func() { _ = dbConn.Close() }()
+	est, ok := dbConn.(sdk.ScanEstimator)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, api.ErrorResponse{Error: i18n.T(c, "datasource cannot estimate query cost")})
+		return
+	}
+
+	qctx, cancel, _ := h.withQueryDeadline(ctx, q.conn, q.body.Timeout)
+	defer cancel()
+	e, err := est.EstimateScan(qctx, q.sql, q.params...)
+	if err != nil {
+		c.JSON(queryErrorStatus(err), datasourceError("estimate failed", err))
+		return
+	}
+	out := api.QueryEstimate{Bytes: e.Bytes, Method: e.Method}
+	if e.Rows > 0 {
+		out.Rows = &e.Rows
+	}
+	if m := q.conn.CostModel; m != nil {
+		cost, currency := m.Cost(e.Bytes), m.CurrencyOrDefault()
+		out.Cost, out.Currency = &cost, &currency
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
@@ -0,0 +1,104 @@
+package connection
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+	qb "data-voyager/core/internal/query_builder"
+	"data-voyager/sdk"
+)
+
+// CountTableRows counts rows (or distinct column values) in a table. Plugins
+// implementing sdk.CardinalityEstimator answer from statistics unless an
+// exact distinct count is asked for; others fall back to an exact COUNT
+// built for the datasource's dialect.
+func (h *Handler) CountTableRows(c *gin.Context, id openapi_types.UUID, table string, params api.CountTableRowsParams) {
+	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return
+	}
+	dbConn, ok := h.openDatasource(c, conn)
+	if !ok {
+		return
+	}
+	defer func() { _ = dbConn.Close() }()
+
+	var schema, column string
+	if params.Schema != nil {
+		schema = *params.Schema
+	}
+	if params.Column != nil {
+		column = *params.Column
+	}
+	exact := params.Exact != nil && *params.Exact
+
+	ctx := c.Request.Context()
+	var rc *sdk.RowCount
+	est, estimates := dbConn.(sdk.CardinalityEstimator)
+	switch {
+	case estimates && column == "":
+		rc, err = est.CountRows(ctx, schema, table, exact)
+	case estimates && !exact:
+		rc, err = est.CountDistinct(ctx, schema, table, column)
+	default:
+		// CountDistinct only estimates, so exact distinct counts scan.
+		rc, err = exactCount(c, dbConn, qb.DialectFor(string(conn.Type)), schema, table, column)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, datasourceError("count failed", err))
+		return
+	}
+	c.JSON(http.StatusOK, api.RowCountResponse{Data: *toAPIRowCount(rc)})
+}
+
+// exactCount runs COUNT(*) or COUNT(DISTINCT column) against the table.
+func exactCount(c *gin.Context, dbConn sdk.Connection, d qb.Dialect, schema, table, column string) (*sdk.RowCount, error) {
+	count, method := qb.Column{Func: "COUNT"}, "count"
+	if column != "" {
+		count, method = qb.Column{Name: column, Func: "COUNT", Distinct: true}, "count_distinct"
+	}
+	sql, args, err := qb.Select{Columns: []qb.Column{count}, Schema: schema, Table: table}.Build(d)
+	if err != nil {
+		return nil, err
+	}
+	result, err := dbConn.Query(c.Request.Context(), sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Frames) == 0 || len(result.Frames[0].Fields) == 0 || len(result.Frames[0].Fields[0].Values) == 0 {
+		return nil, fmt.Errorf("count returned no rows")
+	}
+	n, err := toInt64(result.Frames[0].Fields[0].Values[0])
+	if err != nil {
+		return nil, err
+	}
+	return &sdk.RowCount{Count: n, Method: method}, nil
+}
+
+func toInt64(v any) (int64, error) {
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case int:
+		return int64(n), nil
+	case int32:
+		return int64(n), nil
+	case uint64:
+		return int64(n), nil
+	case uint32:
+		return int64(n), nil
+	case float64:
+		return int64(n), nil
+	}
+	return 0, fmt.Errorf("unexpected count type %T", v)
+}
+
+func toAPIRowCount(rc *sdk.RowCount) *api.RowCount {
+	return &api.RowCount{Count: rc.Count, Approximate: rc.Approximate, Method: rc.Method}
+}
@@ -0,0 +1,89 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"data-voyager/core/internal/api"
+)
+
+// ErrCodeQueryTimeout is the ErrorResponse code for queries stopped by their
+// deadline, so clients can tell a timeout from other upstream failures.
+const ErrCodeQueryTimeout = "query_timeout"
+
+// WithQueryTimeout sets the server-wide query time limit; 0 disables it.
+func (h *Handler) WithQueryTimeout(d time.Duration) *Handler {
+	h.queryTimeout = d
+	return h
+}
+
+// effectiveTimeout returns the smallest positive limit among the server
+// setting, the connection's QueryTimeout and the caller's requested seconds.
+// Zero means the query runs unbounded (until the client goes away).
+func (h *Handler) effectiveTimeout(conn *Connection, requested *int) time.Duration {
+	return smallestTimeout(h.queryTimeout, time.Duration(conn.QueryTimeout)*time.Second, requestedTimeout(requested))
+}
+
+func smallestTimeout(limits ...time.Duration) time.Duration {
+	var limit time.Duration
+	for _, d := range limits {
+		if d > 0 && (limit == 0 || d < limit) {
+			limit = d
+		}
+	}
+	return limit
+}
+
+func requestedTimeout(seconds *int) time.Duration {
+	if seconds == nil || *seconds <= 0 {
+		return 0
+	}
+	return time.Duration(*seconds) * time.Second
+}
+
+// withQueryDeadline derives the context a query runs under, carrying its
+// deadline and memory budget; cancel also returns the budget's bytes. The
+// returned timeout is what the deadline was computed from, for error
+// messages.
+func (h *Handler) withQueryDeadline(ctx context.Context, conn *Connection, requested *int) (context.Context, context.CancelFunc, time.Duration) {
+	ctx, release := h.withMemoryBudget(ctx)
+	timeout := h.effectiveTimeout(conn, requested)
+	var cancel context.CancelFunc
+	if timeout == 0 {
+		ctx, cancel = context.WithCancel(ctx)
+	} else {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	return ctx, func() { cancel(); release() }, timeout
+}
+
+// WithDeadline bounds ctx by the smaller of the server-wide limit and
+// conn's QueryTimeout, for queries run outside the datasource handler; with
+// neither set it only adds a cancel.
+func WithDeadline(ctx context.Context, conn *Connection, server time.Duration) (context.Context, context.CancelFunc) {
+	if timeout := smallestTimeout(server, time.Duration(conn.QueryTimeout)*time.Second); timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// deadlineExceeded reports whether a query failed because ctx's deadline
+// passed. Drivers do not always wrap context errors, so ctx is checked too.
+func deadlineExceeded(ctx context.Context, err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded)
+}
+
+func queryTimeoutError(timeout time.Duration) api.ErrorResponse {
+	code := ErrCodeQueryTimeout
+	return api.ErrorResponse{Error: fmt.Sprintf("query exceeded its %s deadline", timeout), Code: &code}
+}
+
+// validQueryTimeout checks a queryTimeout value from a request body.
+func validQueryTimeout(seconds *int) error {
+	if seconds != nil && (*seconds < 0 || *seconds > 86400) {
+		return fmt.Errorf("queryTimeout must be between 0 and 86400 seconds")
+	}
+	return nil
+}
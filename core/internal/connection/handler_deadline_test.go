@@ -0,0 +1,66 @@
+package connection
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/sdk"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingConn waits for its context to end, like a query that outlives its
+// deadline, and records the deadline it was given.
+type blockingConn struct {
+	mockConn
+	deadline time.Time
+}
+
+func (b *blockingConn) Query(ctx context.Context, _ string, _ ...any) (*sdk.QueryResult, error) {
+	b.deadline, _ = ctx.Deadline()
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestEffectiveTimeout(t *testing.T) {
+	ptr := func(v int) *int { return &v }
+	h := (&Handler{}).WithQueryTimeout(time.Minute)
+
+	assert.Equal(t, time.Minute, h.effectiveTimeout(&Connection{}, nil))
+	assert.Equal(t, 10*time.Second, h.effectiveTimeout(&Connection{QueryTimeout: 10}, nil))
+	assert.Equal(t, 5*time.Second, h.effectiveTimeout(&Connection{QueryTimeout: 10}, ptr(5)))
+	assert.Equal(t, time.Minute, h.effectiveTimeout(&Connection{}, ptr(600)), "requests cannot raise the server cap")
+	assert.Equal(t, time.Duration(0), (&Handler{}).effectiveTimeout(&Connection{}, nil))
+}
+
+func TestQueryDatasource_DeadlineExceeded(t *testing.T) {
+	bc := &blockingConn{}
+	conn := storedConn()
+	conn.QueryTimeout = 30
+	h := newHandler(&mockRepo{conn: conn}, &mockPlugin{dbConn: bc})
+
+	timeout := 1
+	start := time.Now()
+	w := post(h, api.QueryRequest{Query: "SELECT pg_sleep(60)", Timeout: &timeout})
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.WithinDuration(t, start.Add(time.Second), bc.deadline, 500*time.Millisecond)
+	var body api.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.NotNil(t, body.Code)
+	assert.Equal(t, ErrCodeQueryTimeout, *body.Code)
+}
+
+func TestValidQueryTimeout(t *testing.T) {
+	ptr := func(v int) *int { return &v }
+	assert.NoError(t, validQueryTimeout(nil))
+	assert.NoError(t, validQueryTimeout(ptr(0)))
+	assert.NoError(t, validQueryTimeout(ptr(86400)))
+	assert.Error(t, validQueryTimeout(ptr(-1)))
+	assert.Error(t, validQueryTimeout(ptr(86401)))
+}
@@ -0,0 +1,94 @@
+package connection
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// DeprecateDatasource marks a datasource deprecated. Calling it again
+// replaces the message and sunset date but keeps the original deprecation
+// time.
+func (h *Handler) DeprecateDatasource(c *gin.Context, id openapi_types.UUID) {
+	if !requirePermission(c, identity.PermDatasourceWrite) {
+		return
+	}
+	var body api.DeprecateDatasourceRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return
+	}
+
+	dep := &Deprecation{DeprecatedAt: time.Now().UTC()}
+	if conn.Deprecation != nil {
+		dep.DeprecatedAt = conn.Deprecation.DeprecatedAt
+	}
+	if body.Message != nil {
+		dep.Message = *body.Message
+	}
+	if body.SunsetAt != nil {
+		sunset := body.SunsetAt.UTC()
+		if sunset.Before(dep.DeprecatedAt) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: i18n.T(c, "sunsetAt must be after the deprecation date")})
+			return
+		}
+		dep.SunsetAt = &sunset
+	}
+	conn.Deprecation = dep
+
+	if err := h.repo.Update(c.Request.Context(), conn); err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	h.recordHistory(c.Request.Context(), conn.ID, conn.Name, string(conn.Type), "updated")
+	c.JSON(http.StatusOK, api.DatasourceResponse{Data: toAPIDatasource(conn)})
+}
+
+// UndeprecateDatasource clears a datasource's deprecation.
+func (h *Handler) UndeprecateDatasource(c *gin.Context, id openapi_types.UUID) {
+	if !requirePermission(c, identity.PermDatasourceWrite) {
+		return
+	}
+	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return
+	}
+	if conn.Deprecation != nil {
+		conn.Deprecation = nil
+		if err := h.repo.Update(c.Request.Context(), conn); err != nil {
+			c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+			return
+		}
+		h.recordHistory(c.Request.Context(), conn.ID, conn.Name, string(conn.Type), "updated")
+	}
+	c.JSON(http.StatusOK, api.DatasourceResponse{Data: toAPIDatasource(conn)})
+}
+
+// queryWarnings returns the warnings to attach to query responses against conn.
+func queryWarnings(conn *Connection) *[]string {
+	if conn.Deprecation == nil {
+		return nil
+	}
+	return &[]string{conn.Deprecation.Warning(conn.Name, time.Now())}
+}
+
+func toAPIDeprecation(d *Deprecation) *api.Deprecation {
+	return &api.Deprecation{
+		Message:      d.Message,
+		DeprecatedAt: d.DeprecatedAt,
+		SunsetAt:     d.SunsetAt,
+	}
+}
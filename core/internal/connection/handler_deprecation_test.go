@@ -0,0 +1,80 @@
+package connection
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/sdk"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type updatingRepo struct {
+	mockRepo
+	updated *Connection
+}
+
+func (r *updatingRepo) Update(_ context.Context, c *Connection) error {
+	r.updated = c
+	return nil
+}
+
+func deprecate(h *Handler, body any) *httptest.ResponseRecorder {
+	raw, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPut, "/datasources/1/deprecation", bytes.NewReader(raw))
+	c.Request.Header.Set("Content-Type", "application/json")
+	h.DeprecateDatasource(c, uuid.MustParse(testConnID))
+	return w
+}
+
+func TestDeprecateDatasource(t *testing.T) {
+	repo := &updatingRepo{mockRepo: mockRepo{conn: storedConn()}}
+	h := newHandler(repo, &mockPlugin{})
+
+	sunset := time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(time.Second)
+	w := deprecate(h, map[string]any{"message": "use warehouse-v2", "sunsetAt": sunset})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	require.NotNil(t, repo.updated.Deprecation)
+	assert.Equal(t, "use warehouse-v2", repo.updated.Deprecation.Message)
+	assert.True(t, sunset.Equal(*repo.updated.Deprecation.SunsetAt))
+
+	var resp api.DatasourceResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Data.Deprecation)
+	assert.Equal(t, "use warehouse-v2", resp.Data.Deprecation.Message)
+}
+
+func TestDeprecateDatasource_SunsetInPast(t *testing.T) {
+	h := newHandler(&updatingRepo{mockRepo: mockRepo{conn: storedConn()}}, &mockPlugin{})
+	w := deprecate(h, map[string]any{"sunsetAt": time.Now().Add(-time.Hour)})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestQueryDatasource_DeprecationWarning(t *testing.T) {
+	conn := storedConn()
+	sunset := time.Date(2099, 1, 31, 0, 0, 0, 0, time.UTC)
+	conn.Deprecation = &Deprecation{Message: "use warehouse-v2", DeprecatedAt: time.Now(), SunsetAt: &sunset}
+	mc := &mockConn{result: &sdk.QueryResult{}}
+	h := newHandler(&mockRepo{conn: conn}, &mockPlugin{dbConn: mc})
+
+	w := post(h, api.QueryRequest{Query: "SELECT 1"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp api.QueryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Warnings)
+	assert.Equal(t, []string{`datasource "test" is deprecated and will be sunset on 2099-01-31: use warehouse-v2`}, *resp.Warnings)
+}
@@ -0,0 +1,216 @@
+package connection
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/diff"
+	qb "data-voyager/core/internal/query_builder"
+	"data-voyager/sdk"
+)
+
+const (
+	defaultDiffLimit   = 10000
+	maxDiffLimit       = 100000
+	defaultDiffMaxRows = 100
+	maxDiffMaxRows     = 10000
+)
+
+// DiffData compares two result sets, from the same or different datasources,
+// row by row on the requested key columns.
+func (h *Handler) DiffData(c *gin.Context) {
+	var body api.DiffRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if len(body.Keys) == 0 {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: "keys must name at least one column"})
+		return
+	}
+	limit := defaultDiffLimit
+	if body.Limit != nil {
+		limit = *body.Limit
+	}
+	if limit < 1 || limit > maxDiffLimit {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: fmt.Sprintf("limit must be between 1 and %d", maxDiffLimit)})
+		return
+	}
+	opts := diff.Options{Keys: body.Keys, MaxRows: defaultDiffMaxRows}
+	if body.MaxRows != nil {
+		opts.MaxRows = *body.MaxRows
+	}
+	if opts.MaxRows < 1 || opts.MaxRows > maxDiffMaxRows {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: fmt.Sprintf("max_rows must be between 1 and %d", maxDiffMaxRows)})
+		return
+	}
+	if body.Columns != nil {
+		opts.Columns = *body.Columns
+	}
+	if body.Tolerance != nil {
+		if *body.Tolerance < 0 {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: "tolerance must not be negative"})
+			return
+		}
+		opts.Tolerance = *body.Tolerance
+	}
+
+	var warnings []string
+	left, ok := h.diffSide(c, "left", body.Left, limit, &warnings)
+	if !ok {
+		return
+	}
+	right, ok := h.diffSide(c, "right", body.Right, limit, &warnings)
+	if !ok {
+		return
+	}
+
+	res, err := diff.Compare(left, right, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	resp := api.DiffResponse{Data: diffResultToAPI(res)}
+	if len(warnings) > 0 {
+		resp.Warnings = &warnings
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// diffSide runs one side of a diff and returns its first frame, reading at
+// most limit rows. Each side is checked like any other query: resolved
+// through resolveForQuery and its SQL held to guardSQL. It writes the error
+// response itself when it fails.
+func (h *Handler) diffSide(c *gin.Context, name string, src api.DiffSource, limit int, warnings *[]string) (*sdk.DataFrame, bool) {
+	fail := func(status int, msg string) (*sdk.DataFrame, bool) {
+		c.JSON(status, api.ErrorResponse{Error: fmt.Sprintf("%s: %s", name, msg)})
+		return nil, false
+	}
+	conn, plugin, ok := h.resolveForQuery(c, src.DatasourceId.String())
+	if !ok {
+		return nil, false
+	}
+	if w := queryWarnings(conn); w != nil {
+		*warnings = append(*warnings, *w...)
+	}
+
+	dialect := qb.DialectFor(string(conn.Type))
+	var schema, table, raw, query string
+	if src.Schema != nil {
+		schema = *src.Schema
+	}
+	if src.Table != nil {
+		table = *src.Table
+	}
+	if src.Query != nil && *src.Query != "" {
+		raw = *src.Query
+		var fromStr, toStr string
+		if src.TimeRange != nil {
+			if src.TimeRange.From != nil {
+				fromStr = *src.TimeRange.From
+			}
+			if src.TimeRange.To != nil {
+				toStr = *src.TimeRange.To
+			}
+		}
+		tr, err := qb.ParseTimeRange(fromStr, toStr)
+		if err != nil {
+			return fail(http.StatusBadRequest, err.Error())
+		}
+		var vars map[string]any
+		if src.Variables != nil {
+			vars = *src.Variables
+		}
+		query, err = qb.RenderQuery(raw, qb.BuildContext(tr, vars, limit))
+		if err == nil {
+			query, _, err = qb.ExpandMacros(query, dialect, tr, 0)
+		}
+		if err != nil {
+			return fail(http.StatusBadRequest, err.Error())
+		}
+	}
+	// One extra row tells a side that fits from one that was cut off.
+	sql, _, err := qb.Select{Schema: schema, Table: table, Query: query, Limit: limit + 1}.Build(dialect)
+	if err != nil {
+		return fail(http.StatusBadRequest, err.Error())
+	}
+	sql, rowLimit, literals, ok := h.guardSQL(c, conn, raw, sql)
+	if !ok {
+		return nil, false
+	}
+	*warnings = *withLiterals(withRowLimit(warnings, rowLimit, name+": "), literals, name+": ")
+
+	dbConn, replica, err := h.connect(c.Request.Context(), plugin, conn, true)
+	if err != nil {
+		return fail(http.StatusBadGateway, datasourceError("datasource failed", err).Error)
+	}
+	defer func() { _ = dbConn.Close() }()
+	setReplicaHeader(c, replica)
+
+	ctx, cancel, timeout := h.withQueryDeadline(c.Request.Context(), conn, nil)
+	defer cancel()
+	result, err := dbConn.Query(ctx, sql)
+	if err != nil {
+		if deadlineExceeded(ctx, err) {
+			resp := queryTimeoutError(timeout)
+			resp.Error = fmt.Sprintf("%s: %s", name, resp.Error)
+			c.JSON(http.StatusGatewayTimeout, resp)
+			return nil, false
+		}
+		return fail(queryErrorStatus(err), datasourceError("query failed", err).Error)
+	}
+	h.warehouses.touch(conn.ID, time.Now())
+	h.recordUsage(c.Request.Context(), conn, sql, result.Stats)
+	if len(result.Frames) == 0 || result.Frames[0] == nil {
+		return fail(http.StatusBadGateway, "query returned no result")
+	}
+	f := result.Frames[0]
+	if len(f.Fields) > 0 && len(f.Fields[0].Values) > limit {
+		return fail(http.StatusBadRequest, fmt.Sprintf("more than %d rows; narrow the source or raise limit", limit))
+	}
+	return f, true
+}
+
+func diffResultToAPI(r *diff.Result) api.DiffResult {
+	rows := func(in []diff.Row) []map[string]any {
+		out := make([]map[string]any, len(in))
+		for i, row := range in {
+			out[i] = row
+		}
+		return out
+	}
+	nonNil := func(s []string) []string {
+		if s == nil {
+			return []string{}
+		}
+		return s
+	}
+	changed := make([]api.DiffChange, len(r.ChangedRows))
+	for i, ch := range r.ChangedRows {
+		cols := make([]api.DiffColumnChange, len(ch.Columns))
+		for j, cc := range ch.Columns {
+			cols[j] = api.DiffColumnChange{Column: cc.Column, Left: cc.Left, Right: cc.Right}
+		}
+		changed[i] = api.DiffChange{Key: ch.Key, Columns: cols}
+	}
+	return api.DiffResult{
+		Keys:             r.Keys,
+		Columns:          nonNil(r.Columns),
+		LeftOnlyColumns:  nonNil(r.LeftOnly),
+		RightOnlyColumns: nonNil(r.RightOnly),
+		LeftRows:         r.LeftRows,
+		RightRows:        r.RightRows,
+		Added:            r.Added,
+		Removed:          r.Removed,
+		Changed:          r.Changed,
+		Unchanged:        r.Unchanged,
+		AddedRows:        rows(r.AddedRows),
+		RemovedRows:      rows(r.RemovedRows),
+		ChangedRows:      changed,
+		Truncated:        r.Truncated,
+	}
+}
@@ -0,0 +1,132 @@
+package connection
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/identity"
+	"data-voyager/sdk"
+)
+
+// seqConn answers successive queries with successive results.
+type seqConn struct {
+	mockConn
+	results []*sdk.QueryResult
+	queries []string
+}
+
+func (s *seqConn) Query(_ context.Context, q string, _ ...any) (*sdk.QueryResult, error) {
+	s.queries = append(s.queries, q)
+	r := s.results[0]
+	s.results = s.results[1:]
+	return r, nil
+}
+
+func postDiff(h *Handler, body any) *httptest.ResponseRecorder {
+	raw, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/diff", bytes.NewReader(raw))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	h.DiffData(c)
+	return w
+}
+
+func idFrame(ids ...any) *sdk.QueryResult {
+	names := make([]any, len(ids))
+	for i := range ids {
+		names[i] = "n"
+	}
+	return &sdk.QueryResult{Frames: []*sdk.DataFrame{{Fields: []sdk.Field{
+		{Name: "id", Values: ids},
+		{Name: "name", Values: names},
+	}}}}
+}
+
+func TestDiffData(t *testing.T) {
+	sc := &seqConn{results: []*sdk.QueryResult{idFrame(int64(1), int64(2)), idFrame(int64(2), int64(3))}}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: sc})
+
+	w := postDiff(h, map[string]any{
+		"left":  map[string]any{"datasource_id": testConnID, "schema": "public", "table": "users"},
+		"right": map[string]any{"datasource_id": testConnID, "query": "SELECT * FROM users_v2 WHERE org = '{{ org }}';", "variables": map[string]any{"org": "acme"}},
+		"keys":  []string{"id"},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp api.DiffResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Data.Added)
+	assert.Equal(t, 1, resp.Data.Removed)
+	assert.Equal(t, 1, resp.Data.Unchanged)
+	assert.Equal(t, []string{"name"}, resp.Data.Columns)
+	assert.Equal(t, []string{
+		`SELECT * FROM "public"."users" LIMIT 10001`,
+		`SELECT * FROM (SELECT * FROM users_v2 WHERE org = 'acme') AS src LIMIT 10001`,
+	}, sc.queries)
+}
+
+func TestDiffData_SideOverLimit(t *testing.T) {
+	sc := &seqConn{results: []*sdk.QueryResult{idFrame(int64(1), int64(2), int64(3))}}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: sc})
+
+	w := postDiff(h, map[string]any{
+		"left":  map[string]any{"datasource_id": testConnID, "table": "a"},
+		"right": map[string]any{"datasource_id": testConnID, "table": "b"},
+		"keys":  []string{"id"},
+		"limit": 2,
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assertErrorContains(t, w, "left: more than 2 rows")
+}
+
+func TestDiffData_Validation(t *testing.T) {
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &seqConn{}})
+
+	w := postDiff(h, map[string]any{
+		"left":  map[string]any{"datasource_id": testConnID, "table": "a", "query": "SELECT 1"},
+		"right": map[string]any{"datasource_id": testConnID, "table": "b"},
+		"keys":  []string{"id"},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assertErrorContains(t, w, "left: set either table or query")
+
+	w = postDiff(h, map[string]any{
+		"left":     map[string]any{"datasource_id": testConnID, "table": "a"},
+		"right":    map[string]any{"datasource_id": testConnID, "table": "b"},
+		"keys":     []string{"id"},
+		"max_rows": 0,
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assertErrorContains(t, w, "max_rows")
+}
+
+func TestDiffData_SidesAreCheckedLikeQueries(t *testing.T) {
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &seqConn{}}).WithParamPolicy(ParamPolicyReject)
+	body, _ := json.Marshal(map[string]any{
+		"left":  map[string]any{"datasource_id": testConnID, "query": literalQuery},
+		"right": map[string]any{"datasource_id": testConnID, "table": "b"},
+		"keys":  []string{"id"},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/diff", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(identity.With(req.Context(), &identity.Identity{Role: identity.RoleEditor}))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	h.DiffData(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), ErrCodeRawLiterals)
+	assert.True(t, listViolations(t, h).Data[0].Rejected)
+}
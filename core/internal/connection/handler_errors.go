@@ -0,0 +1,40 @@
+package connection
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/sdk"
+)
+
+// datasourceError renders a failed connect or query as prefix: err, adding
+// the code and position when the plugin classified the error.
+func datasourceError(prefix string, err error) api.ErrorResponse {
+	resp := api.ErrorResponse{Error: fmt.Sprintf("%s: %s", prefix, err)}
+	if errors.Is(err, sdk.ErrMemoryLimit) {
+		code := ErrCodeMemoryLimit
+		resp.Error += "; narrow the query or add a LIMIT"
+		resp.Code = &code
+		return resp
+	}
+	var qe *sdk.QueryError
+	if errors.As(err, &qe) {
+		resp.Code = &qe.Code
+		if qe.Line > 0 {
+			resp.Line, resp.Column = &qe.Line, &qe.Column
+		}
+	}
+	return resp
+}
+
+// queryErrorStatus is the HTTP status for a failed query: 507 when its
+// result outgrew the memory limits, since retrying unchanged will not help
+// the datasource, and 502 otherwise.
+func queryErrorStatus(err error) int {
+	if errors.Is(err, sdk.ErrMemoryLimit) {
+		return http.StatusInsufficientStorage
+	}
+	return http.StatusBadGateway
+}
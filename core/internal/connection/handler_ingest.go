@@ -0,0 +1,113 @@
+package connection
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+	"data-voyager/sdk"
+)
+
+// Bounds on one ingestion request. Larger loads are sent as several.
+const (
+	maxIngestRecords = 50_000
+	maxIngestBytes   = 64 << 20
+	maxIngestLine    = 4 << 20
+)
+
+// IngestDatasource handles POST /datasources/:uid/ingest. Lines that are
+// not JSON objects are reported without reaching the datasource; the rest
+// go to the plugin's Ingester in one batch on the primary, and the records
+// it rejects are mapped back to their lines.
+func (h *Handler) IngestDatasource(c *gin.Context, id openapi_types.UUID, params api.IngestDatasourceParams) {
+	if !requirePermission(c, identity.PermDatasourceQuery) {
+		return
+	}
+	if caller := identity.FromContext(c.Request.Context()); caller != nil && caller.Demo {
+		c.JSON(http.StatusForbidden, api.ErrorResponse{Error: i18n.T(c, "only read-only queries can run in the demo")})
+		return
+	}
+
+	resp := api.IngestResponse{Errors: []api.IngestRecordError{}}
+	var (
+		records []json.RawMessage
+		lines   []int // lines[i] is the body line records[i] came from
+	)
+	scanner := bufio.NewScanner(http.MaxBytesReader(c.Writer, c.Request.Body, maxIngestBytes))
+	scanner.Buffer(make([]byte, 0, 64<<10), maxIngestLine)
+	for line := 1; scanner.Scan(); line++ {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		if len(records) == maxIngestRecords {
+			c.JSON(http.StatusRequestEntityTooLarge, api.ErrorResponse{Error: fmt.Sprintf("a batch holds at most %d records", maxIngestRecords)})
+			return
+		}
+		if raw[0] != '{' || !json.Valid(raw) {
+			resp.Errors = append(resp.Errors, api.IngestRecordError{Line: line, Error: i18n.T(c, "not a JSON object")})
+			continue
+		}
+		records = append(records, bytes.Clone(raw))
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) || errors.Is(err, bufio.ErrTooLong) {
+			c.JSON(http.StatusRequestEntityTooLarge, api.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	conn, dbConn, ok := h.openPrimary(c, id)
+	if !ok {
+		return
+	}
+	defer func() { _ = dbConn.Close() }()
+	ingester, ok := dbConn.(sdk.Ingester)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, api.ErrorResponse{Error: i18n.T(c, "ingestion is not supported for this datasource type")})
+		return
+	}
+	if len(records) > 0 {
+		result, err := ingester.Ingest(c.Request.Context(), params.Target, records)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, datasourceError("ingest failed", err))
+			return
+		}
+		resp.Accepted = result.Accepted
+		for _, e := range result.Errors {
+			line := 0
+			if e.Index >= 0 && e.Index < len(lines) {
+				line = lines[e.Index]
+			}
+			resp.Errors = append(resp.Errors, api.IngestRecordError{Line: line, Error: e.Error})
+		}
+	}
+	resp.Failed = len(resp.Errors)
+	// Lines rejected before the batch was sent would otherwise come first.
+	slices.SortStableFunc(resp.Errors, func(a, b api.IngestRecordError) int { return cmp.Compare(a.Line, b.Line) })
+
+	actor := identity.FromContext(c.Request.Context())
+	var by string
+	if actor != nil {
+		by = actor.Username
+	}
+	slog.Info("datasource ingest", "datasource", conn.Name, "target", params.Target,
+		"accepted", resp.Accepted, "failed", resp.Failed, "by", by)
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,96 @@
+package connection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/identity"
+	"data-voyager/sdk"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ingestConn struct {
+	mockConn
+	target  string
+	records []json.RawMessage
+	err     error
+}
+
+// Ingest rejects records with a "bad" field, as a backend would a value it
+// cannot convert.
+func (i *ingestConn) Ingest(_ context.Context, target string, records []json.RawMessage) (*sdk.IngestResult, error) {
+	if i.err != nil {
+		return nil, i.err
+	}
+	i.target, i.records = target, records
+	res := &sdk.IngestResult{}
+	for n, r := range records {
+		if strings.Contains(string(r), `"bad"`) {
+			res.Errors = append(res.Errors, sdk.IngestError{Index: n, Error: "cannot convert"})
+			continue
+		}
+		res.Accepted++
+	}
+	return res, nil
+}
+
+func ingest(h *Handler, body string, id *identity.Identity) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/datasources/1/ingest?target=events", strings.NewReader(body))
+	if id != nil {
+		c.Request = c.Request.WithContext(identity.With(c.Request.Context(), id))
+	}
+	h.IngestDatasource(c, uuid.MustParse(testConnID), api.IngestDatasourceParams{Target: "events"})
+	return w
+}
+
+func TestIngestDatasource(t *testing.T) {
+	ic := &ingestConn{}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: ic})
+
+	body := `{"id": 1}
+
+[1, 2]
+{"id": 2, "bad": true}
+{"id": 3
+{"id": 4}
+`
+	w := ingest(h, body, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp api.IngestResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Accepted)
+	assert.Equal(t, 3, resp.Failed)
+	assert.Equal(t, []api.IngestRecordError{
+		{Line: 3, Error: "not a JSON object"},
+		{Line: 4, Error: "cannot convert"},
+		{Line: 5, Error: "not a JSON object"},
+	}, resp.Errors)
+	assert.Equal(t, "events", ic.target)
+	assert.Len(t, ic.records, 3, "invalid lines never reach the datasource")
+}
+
+func TestIngestDatasource_Refused(t *testing.T) {
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &mockConn{}})
+	w := ingest(h, `{"id": 1}`, nil)
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+
+	h = newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &ingestConn{err: errors.New("unknown table")}})
+	w = ingest(h, `{"id": 1}`, nil)
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+
+	w = ingest(h, `{"id": 1}`, &identity.Identity{Role: identity.RoleViewer, Demo: true})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
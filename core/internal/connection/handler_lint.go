@@ -0,0 +1,54 @@
+package connection
+
+import (
+	"time"
+
+	"data-voyager/core/internal/sqllint"
+	"data-voyager/sdk"
+)
+
+// withLint appends the linter's findings on a query that returned rows rows
+// to warnings, prefixing each with prefix. Table sizes come from the cached
+// schema when the datasource has one.
+func (h *Handler) withLint(warnings *[]string, conn *Connection, query string, rows int64, prefix string) *[]string {
+	opts := sqllint.Options{ResultRows: int(rows)}
+	if schema, ok := h.schemas.get(conn, time.Now()); ok {
+		opts.TableRows = func(db, table string) (int64, bool) { return tableRows(schema, db, table) }
+	}
+	found := sqllint.Lint(query, opts)
+	if len(found) == 0 {
+		return warnings
+	}
+	var out []string
+	if warnings != nil {
+		out = *warnings
+	}
+	for _, w := range found {
+		out = append(out, prefix+w.Message)
+	}
+	return &out
+}
+
+// tableRows looks up a table's row count in schema. An unqualified name
+// matches only when exactly one database has such a table.
+func tableRows(schema *sdk.SchemaInfo, db, table string) (int64, bool) {
+	var found *sdk.TableInfo
+	for _, d := range schema.Databases {
+		if db != "" && d.Name != db {
+			continue
+		}
+		for i, t := range d.Tables {
+			if t.Name != table {
+				continue
+			}
+			if found != nil {
+				return 0, false
+			}
+			found = &d.Tables[i]
+		}
+	}
+	if found == nil || found.RowCount == nil {
+		return 0, false
+	}
+	return *found.RowCount, true
+}
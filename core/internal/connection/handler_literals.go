@@ -0,0 +1,195 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/sqllint"
+)
+
+// Parameter policies decide what happens to a query from a non-admin user
+// that writes literal values into its conditions rather than binding them
+// as parameters.
+const (
+	ParamPolicyOff    = "off"    // run it as written
+	ParamPolicyWarn   = "warn"   // run it, warn, and record it
+	ParamPolicyReject = "reject" // refuse it and record it
+)
+
+// ErrCodeRawLiterals is the ErrorResponse code for queries refused under
+// ParamPolicyReject.
+const ErrCodeRawLiterals = "raw_literals"
+
+const (
+	// violationLogSize is how many violations ListParameterViolations keeps.
+	violationLogSize = 500
+	// violationQueryMax bounds, in bytes, the query text kept per violation.
+	violationQueryMax = 4096
+)
+
+// ErrRawLiterals is returned by LiteralPolicy.Enforce for a query refused
+// under ParamPolicyReject.
+var ErrRawLiterals = errors.New("query conditions contain literal values; bind them as parameters")
+
+// LiteralPolicy applies a parameter policy and keeps the log of the queries
+// it caught. The datasource handler and every other service that runs SQL
+// a user wrote share one, so the log covers them all. A nil LiteralPolicy
+// is ParamPolicyOff.
+type LiteralPolicy struct {
+	policy     string
+	violations paramViolations
+}
+
+// NewLiteralPolicy creates a LiteralPolicy; an empty policy is
+// ParamPolicyOff.
+func NewLiteralPolicy(policy string) *LiteralPolicy {
+	return &LiteralPolicy{policy: policy}
+}
+
+// Check applies the policy to query as the caller wrote it, before
+// templates are rendered, so values filled in from variables do not count.
+// It returns the literals found when the query breaks the policy, and
+// whether it must be refused. Admins, and contexts without an identity, are
+// exempt.
+func (p *LiteralPolicy) Check(ctx context.Context, conn *Connection, query string) (literals []string, reject bool) {
+	if p == nil || p.policy == "" || p.policy == ParamPolicyOff {
+		return nil, false
+	}
+	id := identity.FromContext(ctx)
+	if id == nil || id.Can(identity.PermAdmin) {
+		return nil, false
+	}
+	literals = sqllint.FilterLiterals(query)
+	if len(literals) == 0 {
+		return nil, false
+	}
+	reject = p.policy == ParamPolicyReject
+	if len(query) > violationQueryMax {
+		query = strings.ToValidUTF8(query[:violationQueryMax], "")
+	}
+	p.violations.add(api.ParameterViolation{
+		At:             time.Now().UTC(),
+		Username:       id.Username,
+		DatasourceId:   conn.ID,
+		DatasourceName: conn.Name,
+		Query:          query,
+		Literals:       literals,
+		Rejected:       reject,
+	})
+	return literals, reject
+}
+
+// Enforce is Check for services that only need to know whether the query
+// may run: it returns ErrRawLiterals, naming the literals, when it may not.
+func (p *LiteralPolicy) Enforce(ctx context.Context, conn *Connection, query string) error {
+	if literals, reject := p.Check(ctx, conn, query); reject {
+		return fmt.Errorf("%w: %s", ErrRawLiterals, strings.Join(literals, ", "))
+	}
+	return nil
+}
+
+// WithParamPolicy sets the parameter policy; an empty policy is
+// ParamPolicyOff.
+func (h *Handler) WithParamPolicy(policy string) *Handler {
+	return h.WithLiteralPolicy(NewLiteralPolicy(policy))
+}
+
+// WithLiteralPolicy sets the parameter policy to p, shared with the other
+// services that run user SQL.
+func (h *Handler) WithLiteralPolicy(p *LiteralPolicy) *Handler {
+	h.paramPolicy = p
+	return h
+}
+
+// ListParameterViolations reports the most recent queries the parameter
+// policy caught, newest first.
+func (h *Handler) ListParameterViolations(c *gin.Context) {
+	if !requirePermission(c, identity.PermAdmin) {
+		return
+	}
+	policy := ParamPolicyOff
+	var violations []api.ParameterViolation
+	if p := h.paramPolicy; p != nil {
+		if p.policy != "" {
+			policy = p.policy
+		}
+		violations = p.violations.list()
+	} else {
+		violations = []api.ParameterViolation{}
+	}
+	c.JSON(http.StatusOK, api.ParameterViolationsResponse{
+		Policy: api.ParameterViolationsResponsePolicy(policy),
+		Data:   violations,
+	})
+}
+
+// checkLiterals applies the handler's parameter policy to query; see
+// LiteralPolicy.Check.
+func (h *Handler) checkLiterals(ctx context.Context, conn *Connection, query string) (literals []string, reject bool) {
+	return h.paramPolicy.Check(ctx, conn, query)
+}
+
+// rawLiteralsError is the response for a query refused by checkLiterals.
+func rawLiteralsError(c *gin.Context, literals []string) api.ErrorResponse {
+	code := ErrCodeRawLiterals
+	return api.ErrorResponse{
+		Error: i18n.T(c, "query conditions contain literal values; bind them as parameters") + ": " + strings.Join(literals, ", "),
+		Code:  &code,
+	}
+}
+
+// withLiterals warns about literals checkLiterals found in a query it let
+// run.
+func withLiterals(warnings *[]string, literals []string, prefix string) *[]string {
+	if len(literals) == 0 {
+		return warnings
+	}
+	var out []string
+	if warnings != nil {
+		out = *warnings
+	}
+	out = append(out, fmt.Sprintf("%sthe query's conditions contain the literal values %s; bind them as parameters instead",
+		prefix, strings.Join(literals, ", ")))
+	return &out
+}
+
+// paramViolations is a fixed-size log of policy violations, dropping the
+// oldest when full.
+type paramViolations struct {
+	mu      sync.Mutex
+	entries []api.ParameterViolation
+	next    int // where the next entry goes once entries is full
+}
+
+func (v *paramViolations) add(e api.ParameterViolation) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.entries) < violationLogSize {
+		v.entries = append(v.entries, e)
+		return
+	}
+	v.entries[v.next] = e
+	v.next = (v.next + 1) % violationLogSize
+}
+
+// list returns the log newest first.
+func (v *paramViolations) list() []api.ParameterViolation {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	out := make([]api.ParameterViolation, 0, len(v.entries))
+	for i := range v.entries {
+		j := (v.next - 1 - i + 2*len(v.entries)) % len(v.entries)
+		out = append(out, v.entries[j])
+	}
+	return out
+}
@@ -0,0 +1,96 @@
+package connection
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/identity"
+	"data-voyager/sdk"
+)
+
+// postAs runs QueryDatasource for a user with role.
+func postAs(h *Handler, role string, body api.QueryRequest) *httptest.ResponseRecorder {
+	raw, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/datasources/1/query", bytes.NewReader(raw))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request = c.Request.WithContext(identity.With(c.Request.Context(), &identity.Identity{Username: "ann", Role: role}))
+	h.QueryDatasource(c, uuid.MustParse(testConnID))
+	return w
+}
+
+func listViolations(t *testing.T, h *Handler) api.ParameterViolationsResponse {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/parameter-violations", nil)
+	h.ListParameterViolations(c)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp api.ParameterViolationsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+const literalQuery = "SELECT * FROM events WHERE kind = 'click'"
+
+func TestQueryDatasource_ParamPolicyWarn(t *testing.T) {
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &mockConn{result: &sdk.QueryResult{}}}).WithParamPolicy(ParamPolicyWarn)
+
+	w := postAs(h, identity.RoleViewer, api.QueryRequest{Query: literalQuery})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp api.QueryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Warnings)
+	assert.Contains(t, *resp.Warnings, "the query's conditions contain the literal values 'click'; bind them as parameters instead")
+
+	report := listViolations(t, h)
+	assert.Equal(t, api.Warn, report.Policy)
+	require.Len(t, report.Data, 1)
+	assert.Equal(t, "ann", report.Data[0].Username)
+	assert.Equal(t, []string{"'click'"}, report.Data[0].Literals)
+	assert.False(t, report.Data[0].Rejected)
+}
+
+func TestQueryDatasource_ParamPolicyReject(t *testing.T) {
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &mockConn{result: &sdk.QueryResult{}}}).WithParamPolicy(ParamPolicyReject)
+
+	w := postAs(h, identity.RoleEditor, api.QueryRequest{Query: literalQuery})
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var resp api.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Code)
+	assert.Equal(t, ErrCodeRawLiterals, *resp.Code)
+	assert.Contains(t, resp.Error, "'click'")
+	assert.True(t, listViolations(t, h).Data[0].Rejected)
+}
+
+func TestQueryDatasource_ParamPolicyExemptions(t *testing.T) {
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &mockConn{result: &sdk.QueryResult{}}}).WithParamPolicy(ParamPolicyReject)
+
+	assert.Equal(t, http.StatusOK, postAs(h, identity.RoleAdmin, api.QueryRequest{Query: literalQuery}).Code, "admin")
+	assert.Equal(t, http.StatusOK, post(h, api.QueryRequest{Query: literalQuery}).Code, "no identity")
+	assert.Equal(t, http.StatusOK, postAs(h, identity.RoleViewer, api.QueryRequest{Query: "SELECT * FROM events LIMIT 10"}).Code, "no conditions")
+	assert.Empty(t, listViolations(t, h).Data)
+}
+
+func TestParamViolations_KeepsNewest(t *testing.T) {
+	var v paramViolations
+	for i := range violationLogSize + 3 {
+		v.add(api.ParameterViolation{Query: string(rune('a' + i%26))})
+	}
+	got := v.list()
+	require.Len(t, got, violationLogSize)
+	assert.Equal(t, string(rune('a'+(violationLogSize+2)%26)), got[0].Query)
+	assert.Equal(t, string(rune('a'+3%26)), got[len(got)-1].Query)
+}
@@ -0,0 +1,97 @@
+package connection
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// ErrCodeMaintenance is the ErrorResponse code for queries refused during a
+// maintenance window.
+const ErrCodeMaintenance = "maintenance"
+
+// SetDatasourceMaintenance schedules a maintenance window, replacing any
+// existing one.
+func (h *Handler) SetDatasourceMaintenance(c *gin.Context, id openapi_types.UUID) {
+	if !requirePermission(c, identity.PermDatasourceWrite) {
+		return
+	}
+	var body api.MaintenanceWindow
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return
+	}
+
+	w := &MaintenanceWindow{Start: time.Now().UTC(), End: body.End.UTC()}
+	if body.Start != nil {
+		w.Start = body.Start.UTC()
+	}
+	if body.Message != nil {
+		w.Message = *body.Message
+	}
+	if !w.End.After(w.Start) {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: i18n.T(c, "maintenance end must be after its start")})
+		return
+	}
+	conn.Maintenance = w
+	if !h.update(c, conn) {
+		return
+	}
+	c.JSON(http.StatusOK, api.DatasourceResponse{Data: toAPIDatasource(conn)})
+}
+
+// ClearDatasourceMaintenance removes the maintenance window, ending it early
+// if it is open.
+func (h *Handler) ClearDatasourceMaintenance(c *gin.Context, id openapi_types.UUID) {
+	if !requirePermission(c, identity.PermDatasourceWrite) {
+		return
+	}
+	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return
+	}
+	if conn.Maintenance != nil {
+		conn.Maintenance = nil
+		if !h.update(c, conn) {
+			return
+		}
+	}
+	c.JSON(http.StatusOK, api.DatasourceResponse{Data: toAPIDatasource(conn)})
+}
+
+// rejectDuringMaintenance answers 503 with the window's notice when conn is
+// under maintenance, and reports whether it did.
+func rejectDuringMaintenance(c *gin.Context, conn *Connection) bool {
+	now := time.Now()
+	if !conn.Maintenance.Active(now) {
+		return false
+	}
+	code := ErrCodeMaintenance
+	retry := int(conn.Maintenance.End.Sub(now).Seconds()) + 1
+	c.Header("Retry-After", strconv.Itoa(retry))
+	c.JSON(http.StatusServiceUnavailable, api.ErrorResponse{Error: conn.Maintenance.Notice(conn.Name), Code: &code})
+	return true
+}
+
+func toAPIMaintenance(m *MaintenanceWindow) *api.MaintenanceWindow {
+	start := m.Start
+	w := &api.MaintenanceWindow{Start: &start, End: m.End}
+	if m.Message != "" {
+		msg := m.Message
+		w.Message = &msg
+	}
+	return w
+}
@@ -0,0 +1,152 @@
+package connection
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/sdk"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetDatasourceMaintenance(t *testing.T) {
+	repo := &updatingRepo{mockRepo: mockRepo{conn: storedConn()}}
+	h := newHandler(repo, &mockPlugin{})
+	put := func(body any) *httptest.ResponseRecorder {
+		raw, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPut, "/datasources/1/maintenance", bytes.NewReader(raw))
+		c.Request.Header.Set("Content-Type", "application/json")
+		h.SetDatasourceMaintenance(c, uuid.MustParse(testConnID))
+		return w
+	}
+
+	end := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	w := put(map[string]any{"end": end, "message": "upgrading to v16"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NotNil(t, repo.updated.Maintenance)
+	assert.True(t, repo.updated.Maintenance.Active(time.Now()))
+
+	var resp api.DatasourceResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Data.Maintenance)
+	assert.True(t, end.Equal(resp.Data.Maintenance.End))
+
+	w = put(map[string]any{"start": end, "end": end.Add(-time.Minute)})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestQueryDatasource_RejectedDuringMaintenance(t *testing.T) {
+	conn := storedConn()
+	conn.Maintenance = &MaintenanceWindow{Start: time.Now().Add(-time.Minute), End: time.Now().Add(time.Hour), Message: "upgrading"}
+	mc := &mockConn{result: &sdk.QueryResult{}}
+	h := newHandler(&mockRepo{conn: conn}, &mockPlugin{dbConn: mc})
+
+	w := post(h, api.QueryRequest{Query: "SELECT 1"})
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var resp api.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Code)
+	assert.Equal(t, ErrCodeMaintenance, *resp.Code)
+	assert.Contains(t, resp.Error, "upgrading")
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	// A window that has ended no longer blocks.
+	conn.Maintenance.End = time.Now().Add(-time.Second)
+	w = post(h, api.QueryRequest{Query: "SELECT 1"})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+// hostPlugin connects only to hosts not listed in down, recording each
+// attempt.
+type hostPlugin struct {
+	mockPlugin
+	down     map[string]bool
+	attempts []string
+}
+
+type hostConfig struct {
+	mockConfig
+	Host string `json:"host"`
+}
+
+func (p *hostPlugin) ParseConfig(raw json.RawMessage) (sdk.ConnectionConfig, error) {
+	var cfg hostConfig
+	return cfg, json.Unmarshal(raw, &cfg)
+}
+
+func (p *hostPlugin) Connect(_ context.Context, cfg sdk.ConnectionConfig) (sdk.Connection, error) {
+	host := cfg.(hostConfig).Host
+	p.attempts = append(p.attempts, host)
+	if p.down[host] {
+		return nil, errors.New("connection refused")
+	}
+	return p.dbConn, nil
+}
+
+func TestQueryDatasource_ReplicaRouting(t *testing.T) {
+	conn := storedConn()
+	conn.Replicas = []Replica{
+		{Name: "r1", Config: json.RawMessage(`{"host":"replica-1"}`)},
+		{Name: "r2", Config: json.RawMessage(`{"host":"replica-2"}`)},
+	}
+	plugin := &hostPlugin{mockPlugin: mockPlugin{dbConn: &mockConn{result: &sdk.QueryResult{}}}, down: map[string]bool{"replica-1": true}}
+	h := newHandler(&mockRepo{conn: conn}, plugin)
+
+	w := post(h, api.QueryRequest{Query: "SELECT * FROM orders"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "r2", w.Header().Get(ReplicaHeader))
+	assert.Equal(t, []string{"replica-1", "replica-2"}, plugin.attempts)
+
+	// The failed replica is skipped for a while instead of retried.
+	plugin.attempts = nil
+	post(h, api.QueryRequest{Query: "SELECT 1"})
+	assert.Equal(t, []string{"replica-2"}, plugin.attempts)
+
+	// Writes always go to the primary.
+	plugin.attempts = nil
+	w = post(h, api.QueryRequest{Query: "DELETE FROM orders"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(ReplicaHeader))
+	assert.Equal(t, []string{"localhost"}, plugin.attempts)
+
+	// With every replica down the primary serves reads.
+	plugin.down["replica-2"] = true
+	h.replicas = replicaHealth{}
+	plugin.attempts = nil
+	w = post(h, api.QueryRequest{Query: "SELECT 1"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(ReplicaHeader))
+	assert.Equal(t, []string{"replica-1", "replica-2", "localhost"}, plugin.attempts)
+}
+
+func TestIsReadOnlyQuery(t *testing.T) {
+	for q, want := range map[string]bool{
+		"SELECT * FROM t":                                       true,
+		"  -- report\nselect count(*) from t":                   true,
+		"WITH x AS (SELECT 1) SELECT * FROM x":                  true,
+		"SELECT 'delete me' AS note":                            true,
+		`SELECT "update" FROM t`:                                true,
+		"SHOW TABLES":                                           true,
+		"EXPLAIN SELECT 1":                                      true,
+		"INSERT INTO t VALUES (1)":                              false,
+		"WITH d AS (DELETE FROM t RETURNING *) SELECT * FROM d": false,
+		"SELECT * INTO backup FROM t":                           false,
+		"SELECT * FROM t FOR UPDATE":                            false,
+		"VACUUM":                                                false,
+	} {
+		assert.Equal(t, want, IsReadOnlyQuery(q), q)
+	}
+}
@@ -0,0 +1,29 @@
+package connection
+
+import (
+	"context"
+
+	"data-voyager/core/internal/memlimit"
+	"data-voyager/sdk"
+)
+
+// ErrCodeMemoryLimit is the ErrorResponse code for queries whose result
+// outgrew the per-query or server-wide memory limit.
+const ErrCodeMemoryLimit = "memory_limit"
+
+// WithMemoryPool bounds the memory held by results being read; nil
+// disables the limits.
+func (h *Handler) WithMemoryPool(p *memlimit.Pool) *Handler {
+	h.memory = p
+	return h
+}
+
+// withMemoryBudget attaches a fresh tracker from the handler's pool to ctx.
+// release returns its bytes once the result has been written out.
+func (h *Handler) withMemoryBudget(ctx context.Context) (context.Context, func()) {
+	if h.memory == nil {
+		return ctx, func() {}
+	}
+	t := h.memory.Track()
+	return sdk.WithMemoryBudget(ctx, t), t.Release
+}
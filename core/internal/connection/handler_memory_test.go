@@ -0,0 +1,56 @@
+package connection
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/memlimit"
+	"data-voyager/sdk"
+)
+
+// hungryConn reserves size bytes from the query's budget, like a plugin
+// buffering a large result.
+type hungryConn struct {
+	mockConn
+	size int64
+}
+
+func (g *hungryConn) Query(ctx context.Context, _ string, _ ...any) (*sdk.QueryResult, error) {
+	if b := sdk.MemoryBudgetFrom(ctx); b != nil {
+		if err := b.Reserve(g.size); err != nil {
+			return nil, err
+		}
+	}
+	return &sdk.QueryResult{}, nil
+}
+
+func TestQueryDatasource_MemoryLimit(t *testing.T) {
+	pool := memlimit.NewPool(0, 1<<20)
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &hungryConn{size: 2 << 20}}).WithMemoryPool(pool)
+
+	w := post(h, api.QueryRequest{Query: "SELECT * FROM events"})
+
+	assert.Equal(t, http.StatusInsufficientStorage, w.Code)
+	var body api.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.NotNil(t, body.Code)
+	assert.Equal(t, ErrCodeMemoryLimit, *body.Code)
+	assert.Contains(t, body.Error, "add a LIMIT")
+	assert.Zero(t, pool.InUse())
+}
+
+func TestQueryDatasource_MemoryReleasedAfterQuery(t *testing.T) {
+	pool := memlimit.NewPool(0, 1<<20)
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &hungryConn{size: 512 << 10}}).WithMemoryPool(pool)
+
+	w := post(h, api.QueryRequest{Query: "SELECT * FROM events"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Zero(t, pool.InUse())
+}
@@ -0,0 +1,154 @@
+package connection
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// SetDatasourceNetworkPolicy replaces the allowed and denied networks,
+// keeping the datasource's keys.
+func (h *Handler) SetDatasourceNetworkPolicy(c *gin.Context, id openapi_types.UUID) {
+	if !requirePermission(c, identity.PermAdmin) {
+		return
+	}
+	var body api.NetworkPolicyRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return
+	}
+
+	p := &NetworkPolicy{}
+	if conn.NetworkPolicy != nil {
+		p.Keys = conn.NetworkPolicy.Keys
+	}
+	if body.AllowCidrs != nil {
+		p.AllowCIDRs = *body.AllowCidrs
+	}
+	if body.DenyCidrs != nil {
+		p.DenyCIDRs = *body.DenyCidrs
+	}
+	if err := p.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	conn.NetworkPolicy = p
+	if p.Empty() {
+		conn.NetworkPolicy = nil
+	}
+	if !h.update(c, conn) {
+		return
+	}
+	c.JSON(http.StatusOK, api.DatasourceResponse{Data: toAPIDatasource(conn)})
+}
+
+// ClearDatasourceNetworkPolicy removes the network policy and revokes every
+// key.
+func (h *Handler) ClearDatasourceNetworkPolicy(c *gin.Context, id openapi_types.UUID) {
+	if !requirePermission(c, identity.PermAdmin) {
+		return
+	}
+	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return
+	}
+	if conn.NetworkPolicy != nil {
+		conn.NetworkPolicy = nil
+		if !h.update(c, conn) {
+			return
+		}
+	}
+	c.JSON(http.StatusOK, api.DatasourceResponse{Data: toAPIDatasource(conn)})
+}
+
+// CreateDatasourceKey adds a key and returns its secret, once.
+func (h *Handler) CreateDatasourceKey(c *gin.Context, id openapi_types.UUID) {
+	if !requirePermission(c, identity.PermAdmin) {
+		return
+	}
+	var body api.CreateDatasourceKeyJSONBody
+	if err := c.ShouldBindJSON(&body); err != nil || body.Name == "" {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: i18n.T(c, "key name is required")})
+		return
+	}
+	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return
+	}
+	if conn.NetworkPolicy == nil {
+		conn.NetworkPolicy = &NetworkPolicy{}
+	}
+	k, raw, err := conn.NetworkPolicy.addKey(body.Name, time.Now().UTC())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if !h.update(c, conn) {
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": api.CreatedDatasourceKey{
+		Id: k.ID, Name: k.Name, CreatedAt: k.CreatedAt, Key: raw,
+	}})
+}
+
+// DeleteDatasourceKey revokes a key.
+func (h *Handler) DeleteDatasourceKey(c *gin.Context, id openapi_types.UUID, keyID string) {
+	if !requirePermission(c, identity.PermAdmin) {
+		return
+	}
+	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return
+	}
+	p := conn.NetworkPolicy
+	i := -1
+	if p != nil {
+		i = slices.IndexFunc(p.Keys, func(k NetworkKey) bool { return k.ID == keyID })
+	}
+	if i < 0 {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "key not found")})
+		return
+	}
+	p.Keys = slices.Delete(p.Keys, i, i+1)
+	if p.Empty() {
+		conn.NetworkPolicy = nil
+	}
+	if !h.update(c, conn) {
+		return
+	}
+	c.JSON(http.StatusOK, api.DatasourceResponse{Data: toAPIDatasource(conn)})
+}
+
+// toAPINetworkPolicy leaves out key hashes.
+func toAPINetworkPolicy(p *NetworkPolicy) *api.NetworkPolicy {
+	out := &api.NetworkPolicy{}
+	if len(p.AllowCIDRs) > 0 {
+		out.AllowCidrs = &p.AllowCIDRs
+	}
+	if len(p.DenyCIDRs) > 0 {
+		out.DenyCidrs = &p.DenyCIDRs
+	}
+	if len(p.Keys) > 0 {
+		keys := make([]api.DatasourceKey, len(p.Keys))
+		for i, k := range p.Keys {
+			keys[i] = api.DatasourceKey{Id: k.ID, Name: k.Name, CreatedAt: k.CreatedAt}
+		}
+		out.Keys = &keys
+	}
+	return out
+}
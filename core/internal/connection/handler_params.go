@@ -0,0 +1,30 @@
+package connection
+
+import (
+	"database/sql"
+	"slices"
+
+	"data-voyager/sdk"
+)
+
+// queryParams returns the request's parameters as sql.NamedArg arguments
+// for Connection.Query, sorted by name. ok is false when there are some but
+// the plugin cannot bind them, since running the query without them would
+// fail less clearly or, worse, match placeholders by accident.
+func queryParams(plugin sdk.DatasourcePlugin, params *map[string]any) (args []any, ok bool) {
+	if params == nil || len(*params) == 0 {
+		return nil, true
+	}
+	if !sdk.PluginCapabilities(plugin).NamedParams {
+		return nil, false
+	}
+	names := make([]string, 0, len(*params))
+	for name := range *params {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		args = append(args, sql.Named(name, (*params)[name]))
+	}
+	return args, true
+}
@@ -0,0 +1,52 @@
+package connection
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/sdk"
+)
+
+// paramPlugin binds named parameters.
+type paramPlugin struct{ mockPlugin }
+
+func (p *paramPlugin) Capabilities() sdk.Capabilities { return sdk.Capabilities{NamedParams: true} }
+
+// argsConn records the arguments its queries were given.
+type argsConn struct {
+	mockConn
+	args []any
+}
+
+func (a *argsConn) Query(_ context.Context, _ string, args ...any) (*sdk.QueryResult, error) {
+	a.args = args
+	return &sdk.QueryResult{}, nil
+}
+
+func TestQueryDatasource_BindsParameters(t *testing.T) {
+	ac := &argsConn{}
+	h := newHandler(&mockRepo{conn: storedConn()}, &paramPlugin{mockPlugin{dbConn: ac}})
+
+	params := map[string]any{"name": "O'Brien", "age": 42}
+	w := post(h, api.QueryRequest{Query: "SELECT * FROM users WHERE name = {name:String} AND age > {age:UInt8}", Parameters: &params})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []any{sql.Named("age", float64(42)), sql.Named("name", "O'Brien")}, ac.args)
+}
+
+func TestQueryDatasource_ParametersUnsupported(t *testing.T) {
+	ac := &argsConn{}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: ac})
+
+	params := map[string]any{"name": "x"}
+	w := post(h, api.QueryRequest{Query: "SELECT {name:String}", Parameters: &params})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assertErrorContains(t, w, "does not support query parameters")
+	assert.Nil(t, ac.args)
+}
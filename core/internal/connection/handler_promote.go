@@ -0,0 +1,71 @@
+package connection
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// PromoteDatasource copies a datasource definition into another environment
+// with a config blob specific to that environment. The source is untouched.
+func (h *Handler) PromoteDatasource(c *gin.Context, id openapi_types.UUID) {
+	if !requirePermission(c, identity.PermDatasourceWrite) {
+		return
+	}
+	var body api.PromoteDatasourceRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if !body.Environment.Valid() {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: i18n.T(c, "invalid environment")})
+		return
+	}
+
+	src, err := h.repo.GetByID(c.Request.Context(), id.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return
+	}
+	env := string(body.Environment)
+	if src.Environment == env {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: fmt.Sprintf("datasource is already in %s", env)})
+		return
+	}
+
+	name := fmt.Sprintf("%s (%s)", src.Name, env)
+	if body.Name != nil {
+		name = *body.Name
+	}
+	conn := &Connection{
+		Name:        name,
+		Type:        src.Type,
+		Description: src.Description,
+		Tags:        src.Tags,
+		CreatedBy:   src.CreatedBy,
+		TemplateID:  src.TemplateID,
+		Environment: env,
+		Alias:       src.Alias,
+		// A sensitive source stays restricted in every environment.
+		NetworkPolicy: src.NetworkPolicy,
+		IsActive:      true,
+	}
+	if !h.checkAliasAvailable(c, conn) {
+		return
+	}
+	if !h.applyOptions(c, conn, body.Options) {
+		return
+	}
+	if err := h.repo.Create(c.Request.Context(), conn); err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	h.recordHistory(c.Request.Context(), conn.ID, conn.Name, string(conn.Type), "created")
+	c.JSON(http.StatusCreated, api.DatasourceResponse{Data: toAPIDatasource(conn)})
+}
@@ -0,0 +1,80 @@
+package connection
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"data-voyager/core/internal/api"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type creatingRepo struct {
+	mockRepo
+	created *Connection
+}
+
+func (r *creatingRepo) Create(_ context.Context, c *Connection) error {
+	c.ID = uuid.NewString()
+	r.created = c
+	return nil
+}
+
+func promote(h *Handler, body any) *httptest.ResponseRecorder {
+	raw, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/datasources/1/promote", bytes.NewReader(raw))
+	c.Request.Header.Set("Content-Type", "application/json")
+	h.PromoteDatasource(c, uuid.MustParse(testConnID))
+	return w
+}
+
+func TestPromoteDatasource_CopiesDefinition(t *testing.T) {
+	src := storedConn()
+	src.Environment = "staging"
+	src.Description = "orders db"
+	src.Tags = []string{"finance"}
+	repo := &creatingRepo{mockRepo: mockRepo{conn: src}}
+	h := newHandler(repo, &mockPlugin{})
+
+	w := promote(h, map[string]any{"environment": "prod", "options": map[string]any{"host": "prod-db"}})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	require.NotNil(t, repo.created)
+	assert.Equal(t, "test (prod)", repo.created.Name)
+	assert.Equal(t, "prod", repo.created.Environment)
+	assert.Equal(t, "orders db", repo.created.Description)
+	assert.Equal(t, []string{"finance"}, repo.created.Tags)
+	assert.JSONEq(t, `{"host":"prod-db"}`, string(repo.created.Config))
+
+	var resp api.DatasourceResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Data.Environment)
+	assert.Equal(t, "prod", *resp.Data.Environment)
+}
+
+func TestPromoteDatasource_SameEnvironment(t *testing.T) {
+	src := storedConn()
+	src.Environment = "prod"
+	h := newHandler(&creatingRepo{mockRepo: mockRepo{conn: src}}, &mockPlugin{})
+
+	w := promote(h, map[string]any{"environment": "prod", "options": map[string]any{}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPromoteDatasource_InvalidEnvironment(t *testing.T) {
+	h := newHandler(&creatingRepo{mockRepo: mockRepo{conn: storedConn()}}, &mockPlugin{})
+
+	w := promote(h, map[string]any{"environment": "qa", "options": map[string]any{}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assertErrorContains(t, w, "invalid environment")
+}
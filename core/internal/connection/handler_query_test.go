@@ -35,11 +35,14 @@ type mockRepo struct {
 func (m *mockRepo) GetByID(_ context.Context, _ string) (*Connection, error)   { return m.conn, m.err }
 func (m *mockRepo) Create(_ context.Context, _ *Connection) error              { return nil }
 func (m *mockRepo) GetByName(_ context.Context, _ string) (*Connection, error) { return nil, nil }
-func (m *mockRepo) List(_ context.Context, _ Filter) ([]*Connection, error)    { return nil, nil }
-func (m *mockRepo) Update(_ context.Context, _ *Connection) error              { return nil }
-func (m *mockRepo) Delete(_ context.Context, _ string) error                   { return nil }
-func (m *mockRepo) Stats(_ context.Context) (*Stats, error)                    { return nil, nil }
-func (m *mockRepo) Health(_ context.Context) error                             { return nil }
+func (m *mockRepo) GetByExternalID(_ context.Context, _ string) (*Connection, error) {
+	return nil, errors.New("not found")
+}
+func (m *mockRepo) List(_ context.Context, _ Filter) ([]*Connection, error) { return nil, nil }
+func (m *mockRepo) Update(_ context.Context, _ *Connection) error           { return nil }
+func (m *mockRepo) Delete(_ context.Context, _ string) error                { return nil }
+func (m *mockRepo) Stats(_ context.Context) (*Stats, error)                 { return nil, nil }
+func (m *mockRepo) Health(_ context.Context) error                          { return nil }
 
 type mockConfig struct{}
 
@@ -251,6 +254,43 @@ func TestQueryDatasource_QueryError(t *testing.T) {
 	assert.True(t, mc.closed, "connection must be closed even on query error")
 }
 
+func TestQueryDatasource_LintWarnings(t *testing.T) {
+	mc := &mockConn{result: &sdk.QueryResult{}}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: mc})
+	w := post(h, api.QueryRequest{Query: "SELECT * FROM orders, customers"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp api.QueryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Warnings)
+	require.Len(t, *resp.Warnings, 1)
+	assert.Contains(t, (*resp.Warnings)[0], "orders, customers")
+
+	w = post(h, api.QueryRequest{Query: "SELECT * FROM orders o JOIN customers c ON c.id = o.customer_id"})
+	var clean api.QueryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &clean))
+	assert.Nil(t, clean.Warnings)
+}
+
+func TestQueryDatasource_ClassifiedError(t *testing.T) {
+	mc := &mockConn{queryErr: &sdk.QueryError{
+		Code: sdk.ErrCodeSyntaxError, Line: 2, Column: 1,
+		Err: errors.New(`syntax error at or near "FORM"`),
+	}}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: mc})
+	w := post(h, api.QueryRequest{Query: "SELECT *\nFORM t"})
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+
+	var resp api.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Contains(t, resp.Error, `near "FORM"`)
+	require.NotNil(t, resp.Code)
+	assert.Equal(t, sdk.ErrCodeSyntaxError, *resp.Code)
+	require.NotNil(t, resp.Line)
+	assert.Equal(t, 2, *resp.Line)
+	assert.Equal(t, 1, *resp.Column)
+}
+
 func TestQueryDatasource_InspectPayload(t *testing.T) {
 	mc := &mockConn{result: &sdk.QueryResult{}}
 	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: mc})
@@ -306,3 +346,42 @@ func (s *slowConn) Query(_ context.Context, _ string, _ ...any) (*sdk.QueryResul
 	time.Sleep(s.delay)
 	return s.mockConn.result, s.mockConn.queryErr
 }
+
+type usageLog struct{ queries []string }
+
+func (u *usageLog) RecordQuery(_ context.Context, _ *Connection, query string, _ sdk.QueryStats) {
+	u.queries = append(u.queries, query)
+}
+
+func TestQueryDatasource_RecordsUsage(t *testing.T) {
+	usage := &usageLog{}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &mockConn{result: &sdk.QueryResult{}}}).
+		WithUsageRecorder(usage)
+	w := post(h, api.QueryRequest{Query: "SELECT 1 FROM events"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"SELECT 1 FROM events"}, usage.queries)
+
+	failing := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &mockConn{queryErr: errors.New("boom")}}).
+		WithUsageRecorder(usage)
+	post(failing, api.QueryRequest{Query: "SELECT 2"})
+	assert.Len(t, usage.queries, 1, "failed queries are not usage")
+}
+
+func TestQueryDatasource_ReportsFetchBatches(t *testing.T) {
+	mc := &mockConn{result: &sdk.QueryResult{Stats: sdk.QueryStats{RowsReturned: 100, FetchSize: 10, Batches: 4}}}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: mc})
+	w := post(h, api.QueryRequest{Query: "SELECT 1"})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp api.QueryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Stats.FetchSize)
+	require.NotNil(t, resp.Stats.Batches)
+	assert.Equal(t, 10, *resp.Stats.FetchSize)
+	assert.Equal(t, int64(4), *resp.Stats.Batches)
+
+	mc.result.Stats = sdk.QueryStats{RowsReturned: 1}
+	w = post(h, api.QueryRequest{Query: "SELECT 2"})
+	assert.NotContains(t, w.Body.String(), "fetchSize")
+	assert.NotContains(t, w.Body.String(), "batches")
+}
@@ -0,0 +1,112 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+)
+
+// ErrCodeDatasourceReferenced is the ErrorResponse code for a delete refused
+// because other resources still reference the datasource.
+const ErrCodeDatasourceReferenced = "datasource_referenced"
+
+// Reference is a resource that depends on a datasource.
+type Reference struct {
+	Kind string
+	ID   string
+	Name string
+}
+
+// ReferenceSource reports, and on a forced delete removes, the resources of
+// one kind that point at a datasource. Domains that store datasource IDs
+// register one with the Handler so deletes do not orphan their records.
+type ReferenceSource interface {
+	References(ctx context.Context, datasourceID string) ([]Reference, error)
+	DeleteReferences(ctx context.Context, datasourceID string) error
+}
+
+// WithReferenceSources registers the sources consulted before a delete.
+func (h *Handler) WithReferenceSources(sources ...ReferenceSource) *Handler {
+	h.refSources = append(h.refSources, sources...)
+	return h
+}
+
+func (h *Handler) references(ctx context.Context, datasourceID string) ([]Reference, error) {
+	refs := []Reference{}
+	for _, src := range h.refSources {
+		r, err := src.References(ctx, datasourceID)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, r...)
+	}
+	return refs, nil
+}
+
+// ListDatasourceReferences handles GET /datasources/{uid}/references
+func (h *Handler) ListDatasourceReferences(c *gin.Context, uid openapi_types.UUID) {
+	if _, err := h.repo.GetByID(c.Request.Context(), uid.String()); err != nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return
+	}
+	refs, err := h.references(c.Request.Context(), uid.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	data := make([]api.DatasourceReference, len(refs))
+	for i, r := range refs {
+		data[i] = api.DatasourceReference{Kind: r.Kind, Id: r.ID}
+		if r.Name != "" {
+			name := r.Name
+			data[i].Name = &name
+		}
+	}
+	c.JSON(http.StatusOK, api.DatasourceReferenceListResponse{Data: data})
+}
+
+// clearReferences checks the datasource's references before a delete. Without
+// force it answers 409 when any exist; with force it deletes them. It returns
+// false once a response has been written.
+func (h *Handler) clearReferences(c *gin.Context, datasourceID string, force bool) bool {
+	refs, err := h.references(c.Request.Context(), datasourceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return false
+	}
+	if len(refs) == 0 {
+		return true
+	}
+	if !force {
+		code := ErrCodeDatasourceReferenced
+		c.JSON(http.StatusConflict, api.ErrorResponse{
+			Error: fmt.Sprintf("datasource is referenced by %d resource(s); remove them first or delete with force=true", len(refs)),
+			Code:  &code,
+		})
+		return false
+	}
+	for _, src := range h.refSources {
+		if err := src.DeleteReferences(c.Request.Context(), datasourceID); err != nil {
+			c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: fmt.Sprintf("failed to delete references: %s", err)})
+			return false
+		}
+	}
+	return true
+}
+
+// checkNameAvailable answers 409 when another datasource than selfID already
+// uses name, instead of letting the unique index fail with a 500.
+func (h *Handler) checkNameAvailable(c *gin.Context, name, selfID string) bool {
+	existing, err := h.repo.GetByName(c.Request.Context(), name)
+	if err != nil || existing == nil || existing.ID == selfID {
+		return true
+	}
+	c.JSON(http.StatusConflict, api.ErrorResponse{Error: fmt.Sprintf("a datasource named %q already exists", name)})
+	return false
+}
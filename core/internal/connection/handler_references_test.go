@@ -0,0 +1,84 @@
+package connection
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"data-voyager/core/internal/api"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRefSource struct {
+	refs    []Reference
+	deleted []string
+}
+
+func (f *fakeRefSource) References(context.Context, string) ([]Reference, error) {
+	return f.refs, nil
+}
+
+func (f *fakeRefSource) DeleteReferences(_ context.Context, id string) error {
+	f.deleted = append(f.deleted, id)
+	f.refs = nil
+	return nil
+}
+
+func deleteDatasource(h *Handler, force bool) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodDelete, "/datasources/"+testConnID, nil)
+	h.DeleteDatasource(c, uuid.MustParse(testConnID), api.DeleteDatasourceParams{Force: &force})
+	c.Writer.WriteHeaderNow()
+	return w
+}
+
+func TestDeleteDatasource_RefusesWhileReferenced(t *testing.T) {
+	src := &fakeRefSource{refs: []Reference{{Kind: "ownership", ID: "o1"}}}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{}).WithReferenceSources(src)
+
+	w := deleteDatasource(h, false)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var body api.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.NotNil(t, body.Code)
+	assert.Equal(t, ErrCodeDatasourceReferenced, *body.Code)
+	assert.Empty(t, src.deleted)
+
+	w = deleteDatasource(h, true)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []string{testConnID}, src.deleted)
+}
+
+func TestListDatasourceReferences(t *testing.T) {
+	src := &fakeRefSource{refs: []Reference{{Kind: "ownership", ID: "o1", Name: "datasource:x"}}}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{}).WithReferenceSources(src)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/datasources/"+testConnID+"/references", nil)
+	h.ListDatasourceReferences(c, uuid.MustParse(testConnID))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp api.DatasourceReferenceListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "ownership", resp.Data[0].Kind)
+}
+
+func TestApplyDatasource_DuplicateName(t *testing.T) {
+	repo := newVersionedRepo()
+	h := newHandler(repo, &mockPlugin{})
+
+	require.Equal(t, http.StatusCreated, apply(h, "tf-a", applyBody("db1"), nil).Code)
+	w := apply(h, "tf-b", applyBody("db1"), nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assertErrorContains(t, w, "already exists")
+}
@@ -0,0 +1,201 @@
+package connection
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/resultstore"
+)
+
+// RefreshAsyncQuery runs the request behind a succeeded async query again
+// and compares the two results, flagging drift in the data they read. The
+// fresh result is stored as a new async query.
+func (h *Handler) RefreshAsyncQuery(c *gin.Context, id string) {
+	entry, ok := h.asyncEntry(c, id)
+	if !ok {
+		return
+	}
+	var body api.QueryRefreshRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+	if entry.Status != resultstore.StatusSucceeded || entry.Request == nil {
+		c.JSON(http.StatusConflict, api.ErrorResponse{Error: i18n.T(c, "only a succeeded query result can be refreshed")})
+		return
+	}
+	ctx := c.Request.Context()
+	raw, err := h.results.Body(ctx, entry.ID)
+	if errors.Is(err, resultstore.ErrNotFound) {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "query result not found")})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	original, err := summarizeResult(raw)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	var keys []string
+	if body.KeyColumns != nil {
+		keys = *body.KeyColumns
+	}
+	for _, k := range keys {
+		if !slices.Contains(original.columns, k) {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: fmt.Sprintf("key column %q is not in the result", k)})
+			return
+		}
+	}
+
+	var req api.QueryRequest
+	if err := json.Unmarshal(entry.Request, &req); err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	q, ok := h.prepareRequest(c, entry.DatasourceID, req)
+	if !ok {
+		return
+	}
+	resp, replica, fail := h.runQuery(ctx, q)
+	setReplicaHeader(c, replica)
+	if fail != nil {
+		setWarehouseRetry(c, fail.resp)
+		c.JSON(fail.status, fail.resp)
+		return
+	}
+	fresh, err := json.Marshal(resp)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	current, err := summarizeResult(fresh)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	next, err := h.results.Start(entry.DatasourceID, callerName(ctx), entry.Request)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	h.results.Finish(ctx, next.ID, resultstore.StatusSucceeded, http.StatusOK, fresh)
+	if next, err = h.results.Get(next.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if len(keys) == 0 {
+		keys = original.columns
+	}
+	changes := resultDrift(original, current, keys)
+	c.JSON(http.StatusOK, api.QueryRefreshResponse{Data: api.QueryRefresh{
+		Result:   toAPIAsyncQuery(next),
+		Drift:    len(changes) > 0,
+		Changes:  changes,
+		Original: original.toAPI(keys),
+		Current:  current.toAPI(keys),
+	}})
+}
+
+// resultSummary is what drift between two results of a query is judged on.
+type resultSummary struct {
+	rows    int64
+	columns []string
+	// checksums sums a hash of every value per column, so they do not
+	// change when the rows come back in another order.
+	checksums map[string]uint64
+}
+
+// summarizeResult summarizes an encoded QueryResponse. Both sides of a
+// comparison are summarized from their encoding, so values hash alike
+// however the driver typed them.
+func summarizeResult(body []byte) (*resultSummary, error) {
+	var resp struct {
+		Data api.QueryResult `json:"data"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&resp); err != nil {
+		return nil, fmt.Errorf("read stored result: %w", err)
+	}
+	s := &resultSummary{checksums: map[string]uint64{}}
+	for _, f := range resp.Data.Frames {
+		if len(f.Fields) > 0 {
+			s.rows += int64(len(f.Fields[0].Values))
+		}
+		for _, field := range f.Fields {
+			if _, seen := s.checksums[field.Name]; !seen {
+				s.columns = append(s.columns, field.Name)
+			}
+			sum := s.checksums[field.Name]
+			for _, v := range field.Values {
+				sum += hashValue(v)
+			}
+			s.checksums[field.Name] = sum
+		}
+	}
+	return s, nil
+}
+
+func hashValue(v any) uint64 {
+	raw, _ := json.Marshal(v)
+	h := fnv.New64a()
+	_, _ = h.Write(raw)
+	return h.Sum64()
+}
+
+func (s *resultSummary) toAPI(keys []string) api.ResultSummary {
+	out := api.ResultSummary{Rows: s.rows, Columns: s.columns, Checksums: map[string]string{}}
+	if out.Columns == nil {
+		out.Columns = []string{}
+	}
+	for _, k := range keys {
+		if sum, ok := s.checksums[k]; ok {
+			out.Checksums[k] = strconv.FormatUint(sum, 16)
+		}
+	}
+	return out
+}
+
+// resultDrift lists how current differs from original, comparing the
+// checksums of keys only.
+func resultDrift(original, current *resultSummary, keys []string) []string {
+	changes := []string{}
+	if original.rows != current.rows {
+		changes = append(changes, fmt.Sprintf("row count changed from %d to %d", original.rows, current.rows))
+	}
+	for _, col := range original.columns {
+		if !slices.Contains(current.columns, col) {
+			changes = append(changes, fmt.Sprintf("column %q was removed", col))
+		}
+	}
+	for _, col := range current.columns {
+		if !slices.Contains(original.columns, col) {
+			changes = append(changes, fmt.Sprintf("column %q was added", col))
+		}
+	}
+	for _, k := range keys {
+		before, ok1 := original.checksums[k]
+		after, ok2 := current.checksums[k]
+		if ok1 && ok2 && before != after {
+			changes = append(changes, fmt.Sprintf("values of column %q changed", k))
+		}
+	}
+	return changes
+}
@@ -0,0 +1,112 @@
+package connection
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/resultstore"
+	"data-voyager/sdk"
+)
+
+func regionTotals(regions []any, totals []any) *sdk.QueryResult {
+	return &sdk.QueryResult{Frames: []*sdk.DataFrame{{
+		FrameType: sdk.FrameTypeTable,
+		Fields: []sdk.Field{
+			{Name: "region", Kind: sdk.FieldKindString, Values: regions},
+			{Name: "total", Kind: sdk.FieldKindNumber, Values: totals},
+		},
+	}}}
+}
+
+func refresh(h *Handler, id *identity.Identity, resultID string, body any) *httptest.ResponseRecorder {
+	var raw []byte
+	if body != nil {
+		raw, _ = json.Marshal(body)
+	}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/query-results/"+resultID+"/refresh", bytes.NewReader(raw))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request = c.Request.WithContext(identity.With(c.Request.Context(), id))
+	h.RefreshAsyncQuery(c, resultID)
+	return w
+}
+
+func TestRefreshAsyncQuery_Drift(t *testing.T) {
+	mc := &mockConn{result: regionTotals([]any{"eu", "us"}, []any{int64(10), int64(20)})}
+	results := resultstore.New(config.AsyncResultsConfig{TTL: 60}, nil)
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: mc}).WithResultStore(results)
+	ann := &identity.Identity{Username: "ann", Role: identity.RoleViewer}
+
+	from := "1 Hours ago"
+	w := submitAsync(h, ann, api.QueryRequest{Query: "SELECT region, total FROM sales", TimeRange: &api.TimeRange{From: &from}})
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var submitted api.AsyncQueryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &submitted))
+	id := submitted.Data.Id
+	require.Eventually(t, func() bool {
+		e, err := results.Get(id)
+		return err == nil && e.Status == resultstore.StatusSucceeded
+	}, time.Second, 10*time.Millisecond)
+
+	e, _ := results.Get(id)
+	var stored api.QueryRequest
+	require.NoError(t, json.Unmarshal(e.Request, &stored))
+	_, err := time.Parse(time.RFC3339Nano, *stored.TimeRange.From)
+	assert.NoError(t, err, "the time range is pinned to when the query ran")
+
+	// The same rows in another order are not drift.
+	mc.result = regionTotals([]any{"us", "eu"}, []any{int64(20), int64(10)})
+	w = refresh(h, ann, id, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp api.QueryRefreshResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.False(t, resp.Data.Drift, resp.Data.Changes)
+	assert.Equal(t, resp.Data.Original.Checksums, resp.Data.Current.Checksums)
+	assert.NotEqual(t, id, resp.Data.Result.Id, "the fresh result is stored on its own")
+
+	mc.result = regionTotals([]any{"eu", "us", "apac"}, []any{int64(10), int64(25), int64(5)})
+	w = refresh(h, ann, id, api.QueryRefreshRequest{KeyColumns: &[]string{"total"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	resp = api.QueryRefreshResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Data.Drift)
+	assert.Equal(t, []string{"row count changed from 2 to 3", `values of column "total" changed`}, resp.Data.Changes)
+	assert.EqualValues(t, 3, resp.Data.Current.Rows)
+	assert.Len(t, resp.Data.Current.Checksums, 1, "only key columns are compared")
+
+	body, err := results.Body(t.Context(), resp.Data.Result.Id)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "apac")
+
+	w = refresh(h, ann, id, api.QueryRefreshRequest{KeyColumns: &[]string{"nope"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = refresh(h, &identity.Identity{Username: "bob", Role: identity.RoleViewer}, id, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code, "other users' results stay hidden")
+}
+
+func TestRefreshAsyncQuery_OnlySucceeded(t *testing.T) {
+	results := resultstore.New(config.AsyncResultsConfig{TTL: 60}, nil)
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{}).WithResultStore(results)
+	entry, err := results.Start(testConnID, "", []byte(`{"query":"SELECT 1"}`))
+	require.NoError(t, err)
+
+	w := refresh(h, nil, entry.ID, nil)
+	assert.Equal(t, http.StatusConflict, w.Code, "still running")
+
+	results.Finish(t.Context(), entry.ID, resultstore.StatusFailed, http.StatusBadGateway, []byte(`{"error":"boom"}`))
+	w = refresh(h, nil, entry.ID, nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
@@ -0,0 +1,62 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/sqllint"
+)
+
+// InteractiveTag marks datasources used for ad-hoc exploration. Queries on
+// them that have no LIMIT get one, so an unfiltered SELECT cannot pull a
+// whole table by accident.
+const InteractiveTag = "interactive"
+
+// WithDefaultRowLimit sets the LIMIT added to unbounded queries on
+// interactive datasources for users without their own; 0 disables it.
+func (h *Handler) WithDefaultRowLimit(n int) *Handler {
+	h.defaultRowLimit = n
+	return h
+}
+
+// limitRows adds the caller's row limit to sql when conn is interactive, or
+// on any datasource for a demo visitor, and sql has no LIMIT of its own. It
+// returns the limit added, 0 when sql runs as written.
+func (h *Handler) limitRows(ctx context.Context, conn *Connection, sql string) (string, int) {
+	id := identity.FromContext(ctx)
+	if !slices.Contains(conn.Tags, InteractiveTag) && (id == nil || !id.Demo) {
+		return sql, 0
+	}
+	n := h.defaultRowLimit
+	if id != nil && id.RowLimit > 0 {
+		n = id.RowLimit
+	}
+	limited, ok := sqllint.AddLimit(sql, n)
+	if !ok {
+		return sql, 0
+	}
+	return limited, n
+}
+
+// withRowLimit tells the caller about a limit limitRows added.
+func withRowLimit(warnings *[]string, n int, prefix string) *[]string {
+	if n == 0 {
+		return warnings
+	}
+	var out []string
+	if warnings != nil {
+		out = *warnings
+	}
+	out = append(out, fmt.Sprintf("%sthe query had no LIMIT, so at most %d rows were read; add a LIMIT to choose how many", prefix, n))
+	return &out
+}
+
+// optionalInt returns a pointer to n, or nil for 0.
+func optionalInt(n int) *int {
+	if n == 0 {
+		return nil
+	}
+	return &n
+}
@@ -0,0 +1,83 @@
+package connection
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/identity"
+)
+
+func interactiveConn() *Connection {
+	conn := storedConn()
+	conn.Tags = []string{InteractiveTag}
+	return conn
+}
+
+func TestQueryDatasource_AddsRowLimit(t *testing.T) {
+	var captured string
+	h := newHandler(&mockRepo{conn: interactiveConn()}, &mockPlugin{
+		dbConn: &capturingConn{mockConn: &mockConn{}, captured: &captured},
+	}).WithDefaultRowLimit(10000)
+
+	w := post(h, api.QueryRequest{Query: "SELECT * FROM events"})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "SELECT * FROM events\nLIMIT 10000", captured)
+	var resp api.QueryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.RowLimit)
+	assert.Equal(t, 10000, *resp.RowLimit)
+	require.NotNil(t, resp.Warnings)
+	assert.Contains(t, (*resp.Warnings)[0], "at most 10000 rows")
+}
+
+func TestQueryDatasource_RowLimitLeavesOthersAlone(t *testing.T) {
+	for name, tc := range map[string]struct {
+		conn  *Connection
+		query string
+	}{
+		"not interactive": {storedConn(), "SELECT * FROM events"},
+		"has a limit":     {interactiveConn(), "SELECT * FROM events LIMIT 5"},
+		"not a select":    {interactiveConn(), "DELETE FROM events"},
+	} {
+		var captured string
+		h := newHandler(&mockRepo{conn: tc.conn}, &mockPlugin{
+			dbConn: &capturingConn{mockConn: &mockConn{}, captured: &captured},
+		}).WithDefaultRowLimit(10000)
+
+		w := post(h, api.QueryRequest{Query: tc.query})
+
+		require.Equal(t, http.StatusOK, w.Code, name)
+		assert.Equal(t, tc.query, captured, name)
+		var resp api.QueryResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Nil(t, resp.RowLimit, name)
+	}
+}
+
+func TestQueryDatasource_UserRowLimit(t *testing.T) {
+	var captured string
+	h := newHandler(&mockRepo{conn: interactiveConn()}, &mockPlugin{
+		dbConn: &capturingConn{mockConn: &mockConn{}, captured: &captured},
+	}).WithDefaultRowLimit(10000)
+
+	raw, _ := json.Marshal(api.QueryRequest{Query: "SELECT * FROM events"})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/datasources/1/query", bytes.NewReader(raw))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request = c.Request.WithContext(identity.With(c.Request.Context(), &identity.Identity{Username: "ann", Role: identity.RoleViewer, RowLimit: 200}))
+	h.QueryDatasource(c, uuid.MustParse(testConnID))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "SELECT * FROM events\nLIMIT 200", captured)
+}
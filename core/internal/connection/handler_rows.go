@@ -0,0 +1,164 @@
+package connection
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+	qb "data-voyager/core/internal/query_builder"
+	"data-voyager/sdk"
+)
+
+const (
+	defaultTableRowsLimit = 100
+	maxTableRowsLimit     = 10000
+)
+
+// GetTableRows pages through a table using keyset pagination on the sort
+// column and the unique key breaking its ties, so deep pages cost the same
+// as the first one. Quick filters from the grid are compiled into the WHERE
+// clause alongside the cursor.
+func (h *Handler) GetTableRows(c *gin.Context, id openapi_types.UUID, table string, params api.GetTableRowsParams) {
+	limit := defaultTableRowsLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit < 1 || limit > maxTableRowsLimit {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: fmt.Sprintf("limit must be between 1 and %d", maxTableRowsLimit)})
+		return
+	}
+
+	page := qb.KeysetPage{
+		Table:      table,
+		SortColumn: params.Sort,
+		KeyColumn:  params.Key,
+		Desc:       params.Order != nil && *params.Order == api.Desc,
+		Limit:      limit,
+	}
+	if params.Schema != nil {
+		page.Schema = *params.Schema
+	}
+
+	if params.Filter != nil {
+		for _, f := range *params.Filter {
+			page.Filters = append(page.Filters, qb.ColumnFilter{Column: f.Column, Op: string(f.Op), Value: f.Value})
+		}
+	}
+	if params.Cursor != nil && *params.Cursor != "" {
+		after, err := qb.DecodeCursor(*params.Cursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+			return
+		}
+		page.After = after
+	}
+
+	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return
+	}
+
+	dialect := qb.DialectFor(string(conn.Type))
+	query, args, err := page.Build(dialect)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	dbConn, ok := h.openDatasource(c, conn)
+	if !ok {
+		return
+	}
+	defer func() { _ = dbConn.Close() }()
+
+	ctx, cancel, timeout := h.withQueryDeadline(c.Request.Context(), conn, nil)
+	defer cancel()
+	start := time.Now()
+	result, err := dbConn.Query(ctx, query, args...)
+	elapsed := time.Since(start)
+	if err != nil {
+		if deadlineExceeded(ctx, err) {
+			c.JSON(http.StatusGatewayTimeout, queryTimeoutError(timeout))
+			return
+		}
+		c.JSON(queryErrorStatus(err), datasourceError("query failed", err))
+		return
+	}
+	h.recordUsage(c.Request.Context(), conn, query, result.Stats)
+
+	nextCursor, err := trimKeysetPage(result, page, dialect)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// On the first unfiltered page, attach a cheap total when the plugin can
+	// estimate one. Estimation failures are not fatal for browsing.
+	var totalRows *api.RowCount
+	if est, ok := dbConn.(sdk.CardinalityEstimator); ok && page.After == nil && len(page.Filters) == 0 {
+		if rc, err := est.CountRows(c.Request.Context(), page.Schema, table, false); err == nil {
+			totalRows = toAPIRowCount(rc)
+		}
+	}
+
+	c.JSON(http.StatusOK, api.TableRowsResponse{
+		Data:       sdkResultToAPI(result),
+		NextCursor: nextCursor,
+		TotalRows:  totalRows,
+		Warnings:   queryWarnings(conn),
+		Stats:      withCost(queryStats(result.Stats, elapsed), conn, nil, result.Stats.BytesRead),
+	})
+}
+
+// trimKeysetPage drops the look-ahead row fetched by KeysetPage.Build and
+// returns the cursor for the next page, or nil when this is the last page.
+func trimKeysetPage(result *sdk.QueryResult, page qb.KeysetPage, dialect qb.Dialect) (*string, error) {
+	if len(result.Frames) == 0 || len(result.Frames[0].Fields) == 0 {
+		return nil, nil
+	}
+	fields := result.Frames[0].Fields
+	limit := page.Limit
+	if len(fields[0].Values) <= limit {
+		return nil, nil
+	}
+
+	var next qb.Cursor
+	foundSort, foundKey := false, page.KeyColumn == ""
+	for i, f := range fields {
+		if f.Name == page.SortColumn {
+			next.Sort, foundSort = cursorValue(f.Values[limit-1], dialect), true
+		}
+		if page.KeyColumn != "" && f.Name == page.KeyColumn {
+			next.Key, foundKey = cursorValue(f.Values[limit-1], dialect), true
+		}
+		fields[i].Values = f.Values[:limit]
+	}
+	result.Stats.RowsReturned = int64(limit)
+	if !foundSort {
+		return nil, fmt.Errorf("sort column %q not present in result", page.SortColumn)
+	}
+	if !foundKey {
+		return nil, fmt.Errorf("key column %q not present in result", page.KeyColumn)
+	}
+
+	cursor, err := qb.EncodeCursor(next)
+	if err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// cursorValue encodes times in the dialect's time layout, which the
+// database parses back when the cursor is compared against the column.
+func cursorValue(v any, dialect qb.Dialect) any {
+	if t, ok := v.(time.Time); ok {
+		return t.UTC().Format(dialect.TimeLayout)
+	}
+	return v
+}
@@ -0,0 +1,237 @@
+package connection
+
+import (
+	"context"
+	dbsql "database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"data-voyager/core/internal/api"
+	qb "data-voyager/core/internal/query_builder"
+	"data-voyager/sdk"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getRows(h *Handler, params api.GetTableRowsParams) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/datasources/1/tables/events/rows", nil)
+	h.GetTableRows(c, uuid.MustParse(testConnID), "events", params)
+	return w
+}
+
+func TestGetTableRows_ReturnsNextCursor(t *testing.T) {
+	mc := &mockConn{result: &sdk.QueryResult{
+		Frames: []*sdk.DataFrame{{Fields: []sdk.Field{
+			{Name: "id", Kind: sdk.FieldKindNumber, Values: []any{int64(1), int64(2), int64(3)}},
+		}}},
+		Stats: sdk.QueryStats{RowsReturned: 3},
+	}}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: mc})
+
+	limit := 2
+	w := getRows(h, api.GetTableRowsParams{Sort: "id", Key: "id", Limit: &limit})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp api.TableRowsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Data.Frames[0].Fields[0].Values, 2)
+	assert.Equal(t, int64(2), resp.Stats.RowsReturned)
+	require.NotNil(t, resp.NextCursor)
+	after, err := qb.DecodeCursor(*resp.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, qb.Cursor{Sort: int64(2), Key: int64(2)}, *after)
+}
+
+func TestGetTableRows_CursorCarriesKeyAndNull(t *testing.T) {
+	rc := &recordingConn{mockConn: mockConn{result: &sdk.QueryResult{
+		Frames: []*sdk.DataFrame{{Fields: []sdk.Field{
+			{Name: "id", Kind: sdk.FieldKindNumber, Values: []any{int64(1), int64(2), int64(3)}},
+			{Name: "due", Kind: sdk.FieldKindTime, Values: []any{nil, nil, nil}},
+		}}},
+	}}}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: rc})
+
+	limit := 2
+	w := getRows(h, api.GetTableRowsParams{Sort: "due", Key: "id", Limit: &limit})
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp api.TableRowsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.NextCursor)
+	after, err := qb.DecodeCursor(*resp.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, qb.Cursor{Key: int64(2)}, *after)
+
+	// The next page seeks within the NULLs instead of starting over.
+	w = getRows(h, api.GetTableRowsParams{Sort: "due", Key: "id", Limit: &limit, Cursor: resp.NextCursor})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, rc.query, `WHERE ("due" IS NULL AND "id" > ?)`)
+	assert.Equal(t, []any{int64(2)}, rc.args)
+}
+
+func TestGetTableRows_LastPage(t *testing.T) {
+	mc := &mockConn{result: &sdk.QueryResult{
+		Frames: []*sdk.DataFrame{{Fields: []sdk.Field{
+			{Name: "id", Kind: sdk.FieldKindNumber, Values: []any{int64(1)}},
+		}}},
+		Stats: sdk.QueryStats{RowsReturned: 1},
+	}}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: mc})
+
+	w := getRows(h, api.GetTableRowsParams{Sort: "id", Key: "id"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp api.TableRowsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Nil(t, resp.NextCursor)
+}
+
+func TestGetTableRows_InvalidCursor(t *testing.T) {
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &mockConn{}})
+	cursor := "%%%"
+	w := getRows(h, api.GetTableRowsParams{Sort: "id", Key: "id", Cursor: &cursor})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assertErrorContains(t, w, "invalid cursor")
+}
+
+type recordingConn struct {
+	mockConn
+	query string
+	args  []any
+}
+
+func (r *recordingConn) Query(ctx context.Context, query string, args ...any) (*sdk.QueryResult, error) {
+	r.query, r.args = query, args
+	return r.mockConn.Query(ctx, query, args...)
+}
+
+func TestGetTableRows_PushesDownFilters(t *testing.T) {
+	rc := &recordingConn{mockConn: mockConn{result: &sdk.QueryResult{}}}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: rc})
+
+	desc := api.Desc
+	w := getRows(h, api.GetTableRowsParams{Sort: "id", Key: "id", Order: &desc, Filter: &[]api.ColumnFilter{
+		{Column: "name", Op: "contains", Value: "ann"},
+		{Column: "age", Op: "gt", Value: float64(30)},
+	}})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `SELECT * FROM "events" WHERE LOWER("name") LIKE LOWER(?) ESCAPE '\' AND "age" > ? ORDER BY CASE WHEN "id" IS NULL THEN 0 ELSE 1 END, "id" DESC LIMIT 101`, rc.query)
+	assert.Equal(t, []any{"%ann%", int64(30)}, rc.args)
+
+	w = getRows(h, api.GetTableRowsParams{Sort: "id", Key: "id", Filter: &[]api.ColumnFilter{{Column: "name", Op: "contains"}}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assertErrorContains(t, w, "needs a text value")
+}
+
+type clickHousePlugin struct{ mockPlugin }
+
+func (p *clickHousePlugin) GetType() sdk.DataSourceType { return "clickhouse" }
+
+func TestGetTableRows_ClickHouseBindsFilters(t *testing.T) {
+	rc := &recordingConn{mockConn: mockConn{result: &sdk.QueryResult{}}}
+	conn := storedConn()
+	conn.Type = "clickhouse"
+	h := newHandler(&mockRepo{conn: conn}, &clickHousePlugin{mockPlugin{dbConn: rc}})
+
+	w := getRows(h, api.GetTableRowsParams{Sort: "status", Key: "id", Filter: &[]api.ColumnFilter{
+		{Column: "name", Op: "eq", Value: "x' OR 1=1 --"},
+	}})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "SELECT * FROM `events` WHERE `name` = {p1:String} ORDER BY `status` ASC NULLS LAST, `id` ASC LIMIT 101", rc.query)
+	assert.Equal(t, []any{dbsql.Named("p1", "x' OR 1=1 --")}, rc.args)
+}
+
+// ─── CountTableRows ───────────────────────────────────────────────────────────
+
+type estimatingConn struct {
+	mockConn
+	exact bool
+}
+
+func (e *estimatingConn) CountRows(_ context.Context, _, _ string, exact bool) (*sdk.RowCount, error) {
+	e.exact = exact
+	return &sdk.RowCount{Count: 1000, Approximate: !exact, Method: "stats"}, nil
+}
+func (e *estimatingConn) CountDistinct(_ context.Context, _, _, _ string) (*sdk.RowCount, error) {
+	return &sdk.RowCount{Count: 7, Approximate: true, Method: "hll"}, nil
+}
+
+func countRows(h *Handler, params api.CountTableRowsParams) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/datasources/1/tables/events/count", nil)
+	h.CountTableRows(c, uuid.MustParse(testConnID), "events", params)
+	return w
+}
+
+func TestCountTableRows_UsesEstimator(t *testing.T) {
+	ec := &estimatingConn{}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: ec})
+
+	w := countRows(h, api.CountTableRowsParams{})
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp api.RowCountResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(1000), resp.Data.Count)
+	assert.True(t, resp.Data.Approximate)
+	assert.False(t, ec.exact)
+}
+
+func TestCountTableRows_FallsBackToExactCount(t *testing.T) {
+	var captured string
+	cc := &capturingConn{mockConn: &mockConn{result: &sdk.QueryResult{Frames: []*sdk.DataFrame{{Fields: []sdk.Field{
+		{Name: "count", Values: []any{int64(42)}},
+	}}}}}, captured: &captured}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: cc})
+
+	w := countRows(h, api.CountTableRowsParams{})
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp api.RowCountResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(42), resp.Data.Count)
+	assert.False(t, resp.Data.Approximate)
+	assert.Equal(t, `SELECT COUNT(*) FROM "events"`, captured)
+}
+
+// exactEstimatingConn estimates counts but also answers the COUNT query
+// it is sent.
+type exactEstimatingConn struct {
+	estimatingConn
+	captured string
+}
+
+func (e *exactEstimatingConn) Query(_ context.Context, query string, _ ...any) (*sdk.QueryResult, error) {
+	e.captured = query
+	return &sdk.QueryResult{Frames: []*sdk.DataFrame{{Fields: []sdk.Field{
+		{Name: "count", Values: []any{int64(42)}},
+	}}}}, nil
+}
+
+func TestCountTableRows_ExactDistinctScans(t *testing.T) {
+	ec := &exactEstimatingConn{}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: ec})
+	column, exact := "user_id", true
+
+	w := countRows(h, api.CountTableRowsParams{Column: &column})
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp api.RowCountResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(7), resp.Data.Count)
+	assert.True(t, resp.Data.Approximate)
+	assert.Empty(t, ec.captured, "without exact the estimator answers")
+
+	w = countRows(h, api.CountTableRowsParams{Column: &column, Exact: &exact})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(42), resp.Data.Count)
+	assert.False(t, resp.Data.Approximate)
+	assert.Equal(t, "count_distinct", resp.Data.Method)
+	assert.Equal(t, `SELECT COUNT(DISTINCT "user_id") FROM "events"`, ec.captured)
+}
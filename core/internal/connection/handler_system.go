@@ -0,0 +1,146 @@
+package connection
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+	"data-voyager/sdk"
+)
+
+// GetDatasourceSystemHealth returns a normalized snapshot of the backend's
+// system tables, read from the primary.
+func (h *Handler) GetDatasourceSystemHealth(c *gin.Context, id openapi_types.UUID) {
+	_, dbConn, ok := h.openPrimary(c, id)
+	if !ok {
+		return
+	}
+	defer func() { _ = dbConn.Close() }()
+
+	reporter, ok := dbConn.(sdk.SystemHealthReporter)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, api.ErrorResponse{Error: i18n.T(c, "system health is not supported for this datasource type")})
+		return
+	}
+	health, err := reporter.SystemHealth(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadGateway, datasourceError("system health failed", err))
+		return
+	}
+	c.JSON(http.StatusOK, api.SystemHealthResponse{Data: toAPISystemHealth(health, time.Now())})
+}
+
+// TerminateDatasourceSession kills a backend session, or only its running
+// query, on the primary. It needs the admin permission because it affects
+// other users' work, and every successful call lands in the datasource
+// history and the server log with the caller's name.
+func (h *Handler) TerminateDatasourceSession(c *gin.Context, id openapi_types.UUID, sessionID string, params api.TerminateDatasourceSessionParams) {
+	if !requirePermission(c, identity.PermAdmin) {
+		return
+	}
+	conn, dbConn, ok := h.openPrimary(c, id)
+	if !ok {
+		return
+	}
+	defer func() { _ = dbConn.Close() }()
+
+	terminator, ok := dbConn.(sdk.SessionTerminator)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, api.ErrorResponse{Error: i18n.T(c, "terminating sessions is not supported for this datasource type")})
+		return
+	}
+	queryOnly := params.QueryOnly != nil && *params.QueryOnly
+	if err := terminator.TerminateSession(c.Request.Context(), sessionID, queryOnly); err != nil {
+		if errors.Is(err, sdk.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "session not found")})
+			return
+		}
+		c.JSON(http.StatusBadGateway, datasourceError("terminate session failed", err))
+		return
+	}
+
+	action := "session_terminated"
+	if queryOnly {
+		action = "query_cancelled"
+	}
+	h.recordHistory(c.Request.Context(), conn.ID, conn.Name, string(conn.Type), action)
+	actor := identity.FromContext(c.Request.Context())
+	var by, impersonatedBy string
+	if actor != nil {
+		by, impersonatedBy = actor.Username, actor.ImpersonatedBy
+	}
+	slog.Info("datasource session stopped", "datasource", conn.Name, "session", sessionID,
+		"action", action, "by", by, "impersonated_by", impersonatedBy)
+	c.Status(http.StatusNoContent)
+}
+
+// openPrimary loads the datasource and connects to its primary, bypassing
+// replica routing: a replica's sessions and counters say nothing about the
+// server clients write to.
+func (h *Handler) openPrimary(c *gin.Context, id openapi_types.UUID) (*Connection, sdk.Connection, bool) {
+	conn, err := h.repo.GetByID(c.Request.Context(), id.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource not found")})
+		return nil, nil, false
+	}
+	if rejectDuringMaintenance(c, conn) {
+		return nil, nil, false
+	}
+	plugin, exists := h.registry.Get(conn.Type)
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: i18n.T(c, "plugin not found for type")})
+		return nil, nil, false
+	}
+	dbConn, _, err := h.connect(c.Request.Context(), plugin, conn, false)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, datasourceError("datasource failed", err))
+		return nil, nil, false
+	}
+	return conn, dbConn, true
+}
+
+func toAPISystemHealth(health *sdk.SystemHealth, collectedAt time.Time) api.SystemHealth {
+	out := api.SystemHealth{
+		Sessions:    make([]api.SystemSession, 0, len(health.Sessions)),
+		Metrics:     make([]api.SystemMetric, 0, len(health.Metrics)),
+		CollectedAt: collectedAt.UTC(),
+	}
+	for _, s := range health.Sessions {
+		session := api.SystemSession{
+			Id:        s.ID,
+			User:      s.User,
+			Database:  s.Database,
+			State:     s.State,
+			Query:     s.Query,
+			ElapsedMs: s.Elapsed.Milliseconds(),
+		}
+		if s.WaitEvent != "" {
+			session.WaitEvent = &s.WaitEvent
+		}
+		out.Sessions = append(out.Sessions, session)
+	}
+	for _, m := range health.Metrics {
+		out.Metrics = append(out.Metrics, api.SystemMetric{Name: m.Name, Value: m.Value})
+	}
+	if len(health.Nodes) > 0 {
+		nodes := make([]api.SystemNode, len(health.Nodes))
+		for i, n := range health.Nodes {
+			nodes[i] = api.SystemNode{Id: n.ID, Address: n.Address, Live: n.Live, StartedAt: n.StartedAt.UTC()}
+			if n.Version != "" {
+				nodes[i].Version = &n.Version
+			}
+			if n.Locality != "" {
+				nodes[i].Locality = &n.Locality
+			}
+		}
+		out.Nodes = &nodes
+	}
+	return out
+}
@@ -0,0 +1,156 @@
+package connection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/identity"
+	"data-voyager/sdk"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type healthConn struct {
+	mockConn
+	health *sdk.SystemHealth
+	err    error
+
+	sessions   map[string]bool
+	terminated string
+	queryOnly  bool
+}
+
+func (h *healthConn) SystemHealth(_ context.Context) (*sdk.SystemHealth, error) {
+	return h.health, h.err
+}
+
+func (h *healthConn) TerminateSession(_ context.Context, id string, queryOnly bool) error {
+	if !h.sessions[id] {
+		return sdk.ErrSessionNotFound
+	}
+	h.terminated, h.queryOnly = id, queryOnly
+	return nil
+}
+
+type recordingHistory struct {
+	NoopHistoryRepository
+	actions []string
+}
+
+func (r *recordingHistory) Record(_ context.Context, h *ConnectionHistory) error {
+	r.actions = append(r.actions, h.Action)
+	return nil
+}
+
+func getSystemHealth(h *Handler) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/datasources/1/system-health", nil)
+	h.GetDatasourceSystemHealth(c, uuid.MustParse(testConnID))
+	return w
+}
+
+func TestGetDatasourceSystemHealth(t *testing.T) {
+	hc := &healthConn{health: &sdk.SystemHealth{
+		Sessions: []sdk.SystemSession{
+			{ID: "42", User: "app", Database: "shop", State: "active", Query: "SELECT 1", Elapsed: 1500 * time.Millisecond, WaitEvent: "Lock:relation"},
+			{ID: "43", User: "app", Database: "shop", State: "idle"},
+		},
+		Metrics: []sdk.SystemMetric{{Name: sdk.MetricConnections, Value: 2}},
+		Nodes: []sdk.SystemNode{{ID: "1", Address: "crdb-1:26257", Locality: "region=eu", Live: true,
+			StartedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}},
+	}}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: hc})
+
+	w := getSystemHealth(h)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp api.SystemHealthResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data.Sessions, 2)
+	assert.Equal(t, int64(1500), resp.Data.Sessions[0].ElapsedMs)
+	require.NotNil(t, resp.Data.Sessions[0].WaitEvent)
+	assert.Equal(t, "Lock:relation", *resp.Data.Sessions[0].WaitEvent)
+	assert.Nil(t, resp.Data.Sessions[1].WaitEvent)
+	assert.Equal(t, []api.SystemMetric{{Name: "connections", Value: 2}}, resp.Data.Metrics)
+	assert.False(t, resp.Data.CollectedAt.IsZero())
+	require.NotNil(t, resp.Data.Nodes)
+	require.Len(t, *resp.Data.Nodes, 1)
+	node := (*resp.Data.Nodes)[0]
+	assert.Equal(t, "crdb-1:26257", node.Address)
+	assert.Equal(t, "region=eu", *node.Locality)
+	assert.Nil(t, node.Version)
+	assert.True(t, node.Live)
+	assert.True(t, hc.closed)
+}
+
+func TestGetDatasourceSystemHealth_Unsupported(t *testing.T) {
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &mockConn{}})
+	w := getSystemHealth(h)
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
+
+func TestGetDatasourceSystemHealth_BackendError(t *testing.T) {
+	hc := &healthConn{err: errors.New("permission denied for pg_stat_activity")}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: hc})
+	w := getSystemHealth(h)
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assertErrorContains(t, w, "permission denied")
+}
+
+func terminateSession(h *Handler, as *identity.Identity, session string, queryOnly bool) *httptest.ResponseRecorder {
+	c, w := requestAs(as, http.MethodDelete, "/datasources/1/system-health/sessions/"+session)
+	h.TerminateDatasourceSession(c, uuid.MustParse(testConnID), session, api.TerminateDatasourceSessionParams{QueryOnly: &queryOnly})
+	c.Writer.WriteHeaderNow()
+	return w
+}
+
+func TestTerminateDatasourceSession(t *testing.T) {
+	admin := &identity.Identity{Username: "root", Role: identity.RoleAdmin}
+
+	t.Run("cancels query and records history", func(t *testing.T) {
+		hc := &healthConn{sessions: map[string]bool{"42": true}}
+		history := &recordingHistory{}
+		h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: hc}).WithHistoryRepo(history)
+
+		w := terminateSession(h, admin, "42", true)
+		require.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "42", hc.terminated)
+		assert.True(t, hc.queryOnly)
+		assert.Equal(t, []string{"query_cancelled"}, history.actions)
+	})
+
+	t.Run("unknown session", func(t *testing.T) {
+		history := &recordingHistory{}
+		h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &healthConn{}}).WithHistoryRepo(history)
+
+		w := terminateSession(h, admin, "7", false)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Empty(t, history.actions)
+	})
+
+	t.Run("requires admin", func(t *testing.T) {
+		hc := &healthConn{sessions: map[string]bool{"42": true}}
+		h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: hc})
+
+		w := terminateSession(h, &identity.Identity{Username: "ed", Role: identity.RoleEditor}, "42", false)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, hc.terminated)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &mockConn{}})
+		w := terminateSession(h, admin, "42", false)
+		assert.Equal(t, http.StatusNotImplemented, w.Code)
+	})
+}
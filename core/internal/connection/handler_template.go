@@ -0,0 +1,274 @@
+package connection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+	"data-voyager/sdk"
+)
+
+// loadTemplate fetches a template, writing an error response on failure.
+func (h *Handler) loadTemplate(c *gin.Context, id string) (*Template, bool) {
+	if h.templates == nil {
+		c.JSON(http.StatusNotImplemented, api.ErrorResponse{Error: i18n.T(c, "datasource templates not available")})
+		return nil, false
+	}
+	tmpl, err := h.templates.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "datasource template not found")})
+		return nil, false
+	}
+	return tmpl, true
+}
+
+// applyOptions sets conn.Config from client-supplied options. For templated
+// connections the options are stored as overrides and merged onto the
+// template's config before validation.
+func (h *Handler) applyOptions(c *gin.Context, conn *Connection, options map[string]interface{}) bool {
+	if conn.TemplateID == "" {
+		configJSON, ok := h.parseAndValidateConfig(c, conn.Type, options)
+		if !ok {
+			return false
+		}
+		conn.Config = configJSON
+		conn.ConfigVersion = h.configVersion(conn.Type)
+		return true
+	}
+
+	tmpl, ok := h.loadTemplate(c, conn.TemplateID)
+	if !ok {
+		return false
+	}
+	var base map[string]any
+	if err := json.Unmarshal(tmpl.Config, &base); err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: i18n.T(c, "invalid template config")})
+		return false
+	}
+	configJSON, ok := h.parseAndValidateConfig(c, conn.Type, mergeMaps(base, options))
+	if !ok {
+		return false
+	}
+	overrides, err := json.Marshal(options)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: i18n.T(c, "failed to serialize overrides")})
+		return false
+	}
+	conn.Config = configJSON
+	conn.Overrides = overrides
+	conn.ConfigVersion = h.configVersion(conn.Type)
+	return true
+}
+
+// configVersion is the config version newly written configs of dsType get.
+func (h *Handler) configVersion(dsType sdk.DataSourceType) int {
+	plugin, ok := h.registry.Get(dsType)
+	if !ok {
+		return 0
+	}
+	return sdk.PluginConfigVersion(plugin)
+}
+
+func (h *Handler) ListDatasourceTemplates(c *gin.Context) {
+	if h.templates == nil {
+		c.JSON(http.StatusOK, api.DatasourceTemplateListResponse{Data: []api.DatasourceTemplate{}})
+		return
+	}
+	list, err := h.templates.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	resp := make([]api.DatasourceTemplate, len(list))
+	for i, t := range list {
+		resp[i] = toAPITemplate(t)
+	}
+	c.JSON(http.StatusOK, api.DatasourceTemplateListResponse{Data: resp})
+}
+
+func (h *Handler) CreateDatasourceTemplate(c *gin.Context) {
+	if !requirePermission(c, identity.PermDatasourceWrite) {
+		return
+	}
+	if h.templates == nil {
+		c.JSON(http.StatusNotImplemented, api.ErrorResponse{Error: i18n.T(c, "datasource templates not available")})
+		return
+	}
+	var body api.CreateDatasourceTemplateRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	dsType := sdk.DataSourceType(body.Type)
+	// Options are a partial config, so only their types are checked; the
+	// merged config is validated when a datasource uses the template.
+	configJSON, _, ok := h.parseConfig(c, dsType, body.Options)
+	if !ok {
+		return
+	}
+
+	tmpl := &Template{
+		Name:   body.Name,
+		Type:   dsType,
+		Config: configJSON,
+	}
+	if body.Description != nil {
+		tmpl.Description = *body.Description
+	}
+	if body.Tags != nil {
+		tmpl.Tags = *body.Tags
+	}
+	if err := h.templates.Create(c.Request.Context(), tmpl); err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, api.DatasourceTemplateResponse{Data: toAPITemplate(tmpl)})
+}
+
+func (h *Handler) GetDatasourceTemplate(c *gin.Context, id openapi_types.UUID) {
+	tmpl, ok := h.loadTemplate(c, id.String())
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, api.DatasourceTemplateResponse{Data: toAPITemplate(tmpl)})
+}
+
+// UpdateDatasourceTemplate updates a template and re-derives the config of
+// every inheriting datasource. All derived configs are validated first, and
+// the template and its datasources are written in one transaction, so a bad
+// template change never leaves datasources half-updated.
+func (h *Handler) UpdateDatasourceTemplate(c *gin.Context, id openapi_types.UUID) {
+	if !requirePermission(c, identity.PermDatasourceWrite) {
+		return
+	}
+	tmpl, ok := h.loadTemplate(c, id.String())
+	if !ok {
+		return
+	}
+	var body api.UpdateDatasourceTemplateRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if body.Name != nil {
+		tmpl.Name = *body.Name
+	}
+	if body.Description != nil {
+		tmpl.Description = *body.Description
+	}
+	if body.Tags != nil {
+		tmpl.Tags = *body.Tags
+	}
+
+	ctx := c.Request.Context()
+	var dependents []*Connection
+	if body.Options != nil {
+		configJSON, _, ok := h.parseConfig(c, tmpl.Type, *body.Options)
+		if !ok {
+			return
+		}
+		tmpl.Config = configJSON
+
+		var err error
+		dependents, err = h.repo.List(ctx, Filter{TemplateID: tmpl.ID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+			return
+		}
+		var invalid []string
+		for _, conn := range dependents {
+			merged, err := MergeConfig(tmpl.Config, conn.Overrides)
+			if err == nil {
+				err = h.validateConfig(conn.Type, merged)
+			}
+			if err != nil {
+				invalid = append(invalid, fmt.Sprintf("%s: %s", conn.Name, err))
+				continue
+			}
+			conn.Config = merged
+		}
+		if len(invalid) > 0 {
+			c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: "template change invalidates datasources: " + strings.Join(invalid, "; ")})
+			return
+		}
+	}
+
+	if err := h.templates.Update(ctx, tmpl, dependents); err != nil {
+		if errors.Is(err, ErrVersionConflict) {
+			c.JSON(http.StatusConflict, api.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	for _, conn := range dependents {
+		h.recordHistory(ctx, conn.ID, conn.Name, string(conn.Type), "updated")
+	}
+	c.JSON(http.StatusOK, api.DatasourceTemplateResponse{Data: toAPITemplate(tmpl)})
+}
+
+func (h *Handler) DeleteDatasourceTemplate(c *gin.Context, id openapi_types.UUID) {
+	if !requirePermission(c, identity.PermDatasourceWrite) {
+		return
+	}
+	if h.templates == nil {
+		c.JSON(http.StatusNotImplemented, api.ErrorResponse{Error: i18n.T(c, "datasource templates not available")})
+		return
+	}
+	ctx := c.Request.Context()
+	dependents, err := h.repo.List(ctx, Filter{TemplateID: id.String()})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if len(dependents) > 0 {
+		c.JSON(http.StatusConflict, api.ErrorResponse{Error: fmt.Sprintf("template is used by %d datasource(s)", len(dependents))})
+		return
+	}
+	if err := h.templates.Delete(ctx, id.String()); err != nil {
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// validateConfig parses and validates a stored config blob via its plugin.
+func (h *Handler) validateConfig(dsType sdk.DataSourceType, configJSON json.RawMessage) error {
+	plugin, exists := h.registry.Get(dsType)
+	if !exists {
+		return fmt.Errorf("unsupported datasource type")
+	}
+	cfg, err := plugin.ParseConfig(configJSON)
+	if err != nil {
+		return err
+	}
+	return plugin.ValidateConfig(cfg)
+}
+
+func toAPITemplate(t *Template) api.DatasourceTemplate {
+	out := api.DatasourceTemplate{
+		Uid:       uuid.MustParse(t.ID),
+		Name:      t.Name,
+		Type:      string(t.Type),
+		Options:   t.Config,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+	if t.Description != "" {
+		out.Description = &t.Description
+	}
+	if len(t.Tags) > 0 {
+		tags := t.Tags
+		out.Tags = &tags
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+package connection
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/api"
+)
+
+// requireHostPlugin is a hostPlugin whose configs need a host.
+type requireHostPlugin struct{ hostPlugin }
+
+func (p *requireHostPlugin) ValidateConfig(cfg any) error {
+	if cfg.(hostConfig).Host == "" {
+		return errors.New("host is required")
+	}
+	return nil
+}
+
+type memTemplates struct{ list []*Template }
+
+func (m *memTemplates) Create(_ context.Context, t *Template) error {
+	t.ID = "0190f6a2-0000-7000-8000-000000000001"
+	m.list = append(m.list, t)
+	return nil
+}
+func (m *memTemplates) GetByID(context.Context, string) (*Template, error) { return nil, nil }
+func (m *memTemplates) List(context.Context) ([]*Template, error)          { return m.list, nil }
+func (m *memTemplates) Update(context.Context, *Template, []*Connection) error {
+	return nil
+}
+func (m *memTemplates) Delete(context.Context, string) error { return nil }
+
+func TestCreateDatasourceTemplate_ParsesOptions(t *testing.T) {
+	templates := &memTemplates{}
+	h := newHandler(&mockRepo{}, &requireHostPlugin{}).WithTemplateRepo(templates)
+	create := func(options map[string]any) *httptest.ResponseRecorder {
+		raw, _ := json.Marshal(api.CreateDatasourceTemplateRequest{Name: "pg", Type: "mock", Options: options})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/datasource-templates", bytes.NewReader(raw))
+		c.Request.Header.Set("Content-Type", "application/json")
+		h.CreateDatasourceTemplate(c)
+		return w
+	}
+
+	w := create(map[string]any{"host": 5432})
+	assert.Equal(t, http.StatusBadRequest, w.Code, "options must still parse")
+	assert.Empty(t, templates.list)
+
+	w = create(map[string]any{"port": 5432})
+	require.Equal(t, http.StatusCreated, w.Code, "a template may leave required fields to its datasources")
+	require.Len(t, templates.list, 1)
+	assert.JSONEq(t, `{"port":5432}`, string(templates.list[0].Config))
+}
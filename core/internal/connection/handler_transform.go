@@ -0,0 +1,48 @@
+package connection
+
+import (
+	"fmt"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/transform"
+)
+
+// transformSpecs converts and validates the transforms of a query request.
+func transformSpecs(in *[]api.QueryTransform) ([]transform.Spec, error) {
+	if in == nil {
+		return nil, nil
+	}
+	specs := make([]transform.Spec, len(*in))
+	for i, t := range *in {
+		s := transform.Spec{Type: transform.Type(t.Type)}
+		if p := t.Pivot; p != nil {
+			s.Pivot = &transform.Pivot{Column: p.Column, Value: p.Value}
+			if p.RowFields != nil {
+				s.Pivot.RowFields = *p.RowFields
+			}
+			if p.Aggregate != nil {
+				s.Pivot.Aggregate = transform.Aggregate(*p.Aggregate)
+			}
+		}
+		if u := t.Unpivot; u != nil {
+			s.Unpivot = &transform.Unpivot{}
+			if u.Keep != nil {
+				s.Unpivot.Keep = *u.Keep
+			}
+			if u.Columns != nil {
+				s.Unpivot.Columns = *u.Columns
+			}
+			if u.NameField != nil {
+				s.Unpivot.NameField = *u.NameField
+			}
+			if u.ValueField != nil {
+				s.Unpivot.ValueField = *u.ValueField
+			}
+		}
+		if err := s.Validate(); err != nil {
+			return nil, fmt.Errorf("transforms[%d]: %w", i, err)
+		}
+		specs[i] = s
+	}
+	return specs, nil
+}
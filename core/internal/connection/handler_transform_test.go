@@ -0,0 +1,53 @@
+package connection
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/sdk"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryDatasource_Pivot(t *testing.T) {
+	result := &sdk.QueryResult{Frames: []*sdk.DataFrame{{
+		FrameType: sdk.FrameTypeTable,
+		Fields: []sdk.Field{
+			{Name: "day", Kind: sdk.FieldKindString, Values: []any{"mon", "mon", "tue"}},
+			{Name: "status", Kind: sdk.FieldKindString, Values: []any{"ok", "err", "ok"}},
+			{Name: "n", Kind: sdk.FieldKindNumber, Values: []any{int64(3), int64(1), int64(4)}},
+		},
+	}}}
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &mockConn{result: result}})
+
+	w := post(h, api.QueryRequest{
+		Query: "SELECT day, status, count(*) AS n FROM requests GROUP BY 1, 2",
+		Transforms: &[]api.QueryTransform{{
+			Type:  api.Pivot,
+			Pivot: &api.PivotTransform{RowFields: &[]string{"day"}, Column: "status", Value: "n"},
+		}},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp api.QueryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	fields := resp.Data.Frames[0].Fields
+	require.Len(t, fields, 3)
+	assert.Equal(t, "ok", fields[1].Name)
+	assert.Equal(t, []any{3.0, 4.0}, fields[1].Values)
+	assert.Equal(t, []any{1.0, nil}, fields[2].Values)
+}
+
+func TestQueryDatasource_InvalidTransform(t *testing.T) {
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{dbConn: &mockConn{result: &sdk.QueryResult{}}})
+
+	w := post(h, api.QueryRequest{
+		Query:      "SELECT 1",
+		Transforms: &[]api.QueryTransform{{Type: api.Pivot}},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assertErrorContains(t, w, "transforms[0]")
+}
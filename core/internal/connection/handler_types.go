@@ -0,0 +1,83 @@
+package connection
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/buildinfo"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+	"data-voyager/sdk"
+)
+
+// ListDatasourceTypeStates lists every registered plugin, including disabled
+// ones.
+func (h *Handler) ListDatasourceTypeStates(c *gin.Context) {
+	if !requirePermission(c, identity.PermAdmin) {
+		return
+	}
+	states := h.registry.States()
+	out := make([]api.DatasourceTypeState, len(states))
+	for i, s := range states {
+		out[i] = toAPITypeState(s)
+	}
+	c.JSON(http.StatusOK, api.DatasourceTypeStatesResponse{Data: out})
+}
+
+// SetDatasourceTypeState enables or disables a plugin at runtime.
+func (h *Handler) SetDatasourceTypeState(c *gin.Context, pType string) {
+	if !requirePermission(c, identity.PermAdmin) {
+		return
+	}
+	var req api.UpdateDatasourceTypeStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	dsType := sdk.DataSourceType(pType)
+	if err := h.registry.SetEnabled(dsType, req.Enabled); err != nil {
+		if errors.Is(err, datasource.ErrUnknownType) {
+			c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "unsupported datasource type")})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+		return
+	}
+	slog.Info("datasource type state changed", "type", pType, "enabled", req.Enabled, "by", callerName(c.Request.Context()))
+
+	for _, s := range h.registry.States() {
+		if s.Type == dsType {
+			c.JSON(http.StatusOK, api.DatasourceTypeStateResponse{Data: toAPITypeState(s)})
+			return
+		}
+	}
+	// Deregistered between the two calls.
+	c.JSON(http.StatusNotFound, api.ErrorResponse{Error: i18n.T(c, "unsupported datasource type")})
+}
+
+func toAPITypeState(s datasource.PluginState) api.DatasourceTypeState {
+	return api.DatasourceTypeState{Type: string(s.Type), Name: s.Name, Enabled: s.Enabled, Version: toAPITypeVersion(s.Version)}
+}
+
+func toAPITypeVersion(v sdk.VersionInfo) api.DatasourceTypeVersion {
+	return api.DatasourceTypeVersion{
+		Version:          v.Version,
+		MinServerVersion: optString(v.MinServerVersion),
+		MaxServerVersion: optString(v.MaxServerVersion),
+		DriverVersion:    optString(v.DriverVersion),
+		Compatible:       v.SupportsServer(buildinfo.Version),
+	}
+}
+
+// optString returns nil for "" so empty fields are omitted from responses.
+func optString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
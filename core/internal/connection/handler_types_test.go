@@ -0,0 +1,53 @@
+package connection
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/identity"
+)
+
+func jsonBody(t *testing.T, v any) io.ReadCloser {
+	t.Helper()
+	raw, err := json.Marshal(v)
+	require.NoError(t, err)
+	return io.NopCloser(bytes.NewReader(raw))
+}
+
+func TestSetDatasourceTypeState(t *testing.T) {
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{})
+	admin := &identity.Identity{Username: "root", Role: identity.RoleAdmin}
+
+	c, w := requestAs(admin, http.MethodPut, "/admin/datasource-types/mock")
+	c.Request.Body = jsonBody(t, api.UpdateDatasourceTypeStateRequest{Enabled: false})
+	h.SetDatasourceTypeState(c, "mock")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp api.DatasourceTypeStateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.False(t, resp.Data.Enabled)
+
+	// The disabled type disappears from the public list but not the admin one.
+	c, w = requestAs(admin, http.MethodGet, "/datasource-types")
+	h.ListDatasourceTypes(c)
+	assert.JSONEq(t, `{"data":[]}`, w.Body.String())
+	c, w = requestAs(admin, http.MethodGet, "/admin/datasource-types")
+	h.ListDatasourceTypeStates(c)
+	assert.JSONEq(t, `{"data":[{"type":"mock","name":"Mock","enabled":false,"version":{"version":"","compatible":true}}]}`, w.Body.String())
+
+	c, w = requestAs(admin, http.MethodPut, "/admin/datasource-types/nope")
+	c.Request.Body = jsonBody(t, api.UpdateDatasourceTypeStateRequest{Enabled: true})
+	h.SetDatasourceTypeState(c, "nope")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	c, w = requestAs(&identity.Identity{Username: "ann", Role: identity.RoleEditor}, http.MethodPut, "/admin/datasource-types/mock")
+	c.Request.Body = jsonBody(t, api.UpdateDatasourceTypeStateRequest{Enabled: true})
+	h.SetDatasourceTypeState(c, "mock")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
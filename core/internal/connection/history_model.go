@@ -11,7 +11,7 @@ type ConnectionHistory struct {
 	ConnectionID   string    `db:"connection_id"`
 	ConnectionName string    `db:"connection_name"`
 	ConnectionType string    `db:"connection_type"`
-	Action         string    `db:"action"` // created | updated | deleted
+	Action         string    `db:"action"` // created | updated | deleted | session_terminated | query_cancelled
 	ChangedAt      time.Time `db:"changed_at"`
 }
 
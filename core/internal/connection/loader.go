@@ -2,6 +2,8 @@ package connection
 
 import (
 	"context"
+	"log/slog"
+	"time"
 
 	"data-voyager/core/internal/ai"
 	"data-voyager/core/internal/aiconfig"
@@ -9,6 +11,11 @@ import (
 	apploader "data-voyager/core/internal/app"
 	"data-voyager/core/internal/config"
 	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/memlimit"
+	"data-voyager/core/internal/notification"
+	"data-voyager/core/internal/resultstore"
 	"data-voyager/core/internal/settings"
 
 	"github.com/gin-gonic/gin"
@@ -55,25 +62,48 @@ type combinedHandler struct {
 	aiconfigHandler *aiconfig.Handler
 }
 
-func (h *combinedHandler) GetAISettings(c *gin.Context)    { h.settingsHandler.GetAISettings(c) }
-func (h *combinedHandler) UpdateAISettings(c *gin.Context) { h.settingsHandler.UpdateAISettings(c) }
+// AI settings and configs hold provider credentials shared by every user,
+// so only admins change them.
 
-func (h *combinedHandler) ListAIConfigs(c *gin.Context)              { h.aiconfigHandler.List(c) }
-func (h *combinedHandler) CreateAIConfig(c *gin.Context)             { h.aiconfigHandler.Create(c) }
-func (h *combinedHandler) GetAIConfig(c *gin.Context, _ string)      { h.aiconfigHandler.GetByID(c) }
-func (h *combinedHandler) UpdateAIConfig(c *gin.Context, _ string)   { h.aiconfigHandler.Update(c) }
-func (h *combinedHandler) DeleteAIConfig(c *gin.Context, _ string)   { h.aiconfigHandler.Delete(c) }
-func (h *combinedHandler) ActivateAIConfig(c *gin.Context, _ string) { h.aiconfigHandler.Activate(c) }
+func (h *combinedHandler) GetAISettings(c *gin.Context) { h.settingsHandler.GetAISettings(c) }
+func (h *combinedHandler) UpdateAISettings(c *gin.Context) {
+	if requirePermission(c, identity.PermAdmin) {
+		h.settingsHandler.UpdateAISettings(c)
+	}
+}
+
+func (h *combinedHandler) ListAIConfigs(c *gin.Context)         { h.aiconfigHandler.List(c) }
+func (h *combinedHandler) GetAIConfig(c *gin.Context, _ string) { h.aiconfigHandler.GetByID(c) }
+func (h *combinedHandler) CreateAIConfig(c *gin.Context) {
+	if requirePermission(c, identity.PermAdmin) {
+		h.aiconfigHandler.Create(c)
+	}
+}
+func (h *combinedHandler) UpdateAIConfig(c *gin.Context, _ string) {
+	if requirePermission(c, identity.PermAdmin) {
+		h.aiconfigHandler.Update(c)
+	}
+}
+func (h *combinedHandler) DeleteAIConfig(c *gin.Context, _ string) {
+	if requirePermission(c, identity.PermAdmin) {
+		h.aiconfigHandler.Delete(c)
+	}
+}
+func (h *combinedHandler) ActivateAIConfig(c *gin.Context, _ string) {
+	if requirePermission(c, identity.PermAdmin) {
+		h.aiconfigHandler.Activate(c)
+	}
+}
 func (h *combinedHandler) ListAIConfigHistory(c *gin.Context, _ api.ListAIConfigHistoryParams) {
 	if h.aiconfigHandler == nil {
-		c.JSON(503, gin.H{"error": "AI config service not available"})
+		c.JSON(503, gin.H{"error": i18n.T(c, "AI config service not available")})
 		return
 	}
 	h.aiconfigHandler.ListHistory(c)
 }
 func (h *combinedHandler) ListAIConfigHistoryByConfig(c *gin.Context, _ string, _ api.ListAIConfigHistoryByConfigParams) {
 	if h.aiconfigHandler == nil {
-		c.JSON(503, gin.H{"error": "AI config service not available"})
+		c.JSON(503, gin.H{"error": i18n.T(c, "AI config service not available")})
 		return
 	}
 	h.aiconfigHandler.ListHistoryByConfig(c)
@@ -82,17 +112,28 @@ func (h *combinedHandler) ListAIConfigHistoryByConfig(c *gin.Context, _ string,
 // loader wires Service and Handler together and satisfies app.Loader.
 type loader struct {
 	svc             *Service
+	server          config.ServerConfig
 	handler         *combinedHandler
 	aiHandler       *ai.Handler
 	aiconfigHandler *aiconfig.Handler
 }
 
 // NewLoaderWithHistory creates a loader with a connection HistoryRepository for audit logging.
-func NewLoaderWithHistory(repo Repository, registry *datasource.Registry, cfg *config.ViperConfig, settingsSvc *settings.Service, aiConfigSvc *aiconfig.Service, connHistoryRepo HistoryRepository) apploader.Loader {
+// usage, when non-nil, is told about executed queries; results, when non-nil,
+// enables async queries; notifier, when non-nil, hears about new datasources
+// and schema changes; literals is the parameter policy, shared with the
+// other services that run user SQL. refSources are consulted before a
+// datasource is deleted.
+func NewLoaderWithHistory(repo Repository, templateRepo TemplateRepository, registry *datasource.Registry, cfg *config.ViperConfig, settingsSvc *settings.Service, aiConfigSvc *aiconfig.Service, connHistoryRepo HistoryRepository, usage UsageRecorder, results *resultstore.Store, notifier notification.Notifier, literals *LiteralPolicy, refSources ...ReferenceSource) apploader.Loader {
 	svc := NewService(repo, registry)
-	connHandler := NewHandler(repo, registry).WithHistoryRepo(connHistoryRepo)
+	connHandler := NewHandler(repo, registry).WithHistoryRepo(connHistoryRepo).WithTemplateRepo(templateRepo).
+		WithQueryTimeout(time.Duration(cfg.Server.QueryTimeout) * time.Second).
+		WithMemoryPool(memlimit.NewPool(int64(cfg.Server.QueryMemoryTotal)<<20, int64(cfg.Server.QueryMemoryLimit)<<20)).
+		WithDefaultRowLimit(cfg.Server.DefaultRowLimit).WithLiteralPolicy(literals).
+		WithReferenceSources(refSources...).WithUsageRecorder(usage).WithResultStore(results).
+		WithNotifier(notifier)
 	settingsHandler := settings.NewHandler(settingsSvc, &cfg.AI)
-	aiHandler := ai.NewHandler(&aiRepoAdapter{inner: repo}, registry, &cfg.AI)
+	aiHandler := ai.NewHandler(&aiRepoAdapter{inner: Visible(repo)}, registry, &cfg.AI)
 
 	// Prefer new aiconfig system; fall back to legacy settings for backward compat.
 	if aiConfigSvc != nil {
@@ -107,7 +148,8 @@ func NewLoaderWithHistory(repo Repository, registry *datasource.Registry, cfg *c
 	}
 
 	return &loader{
-		svc: svc,
+		svc:    svc,
+		server: cfg.Server,
 		handler: &combinedHandler{
 			Handler:         connHandler,
 			settingsHandler: settingsHandler,
@@ -118,9 +160,22 @@ func NewLoaderWithHistory(repo Repository, registry *datasource.Registry, cfg *c
 	}
 }
 
-// Load initialises the connection domain: registers all datasource plugins.
+// Load initialises the connection domain: registers all datasource plugins
+// and warms up the datasources tagged for preload.
 func (l *loader) Load() error {
 	l.svc.InitializePlugins()
+	if l.server.PreloadConcurrency > 0 {
+		ctx := context.Background()
+		if l.server.PreloadTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(l.server.PreloadTimeout)*time.Second)
+			defer cancel()
+		}
+		start := time.Now()
+		if warmed, failed := l.handler.Preload(ctx, l.server.PreloadConcurrency); warmed+failed > 0 {
+			slog.Info("datasources preloaded", "warmed", warmed, "failed", failed, "duration", time.Since(start))
+		}
+	}
 	return nil
 }
 
@@ -2,6 +2,7 @@ package connection
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"data-voyager/sdk"
@@ -19,6 +20,105 @@ type Connection struct {
 	UpdatedAt   time.Time          `json:"updated_at"  db:"updated_at"`
 	CreatedBy   string             `json:"created_by"  db:"created_by"`
 
+	// TemplateID links the connection to a Template. When set, Config is the
+	// template's config merged with Overrides and is re-derived whenever the
+	// template changes.
+	TemplateID string          `json:"template_id,omitempty" db:"template_id"`
+	Overrides  json.RawMessage `json:"overrides,omitempty"   db:"overrides"`
+
+	// Environment labels the deployment stage (dev, staging, prod); empty
+	// means unlabeled.
+	Environment string `json:"environment,omitempty" db:"environment"`
+	// Alias is a stable name, e.g. "analytics", that saved queries can use
+	// instead of the ID; each environment resolves it to its own datasource.
+	// Empty means none.
+	Alias string `json:"alias,omitempty" db:"alias"`
+
+	// ExternalID is an optional caller-assigned identifier, unique when set,
+	// that lets infrastructure-as-code tools address the connection without
+	// knowing its generated ID.
+	ExternalID string `json:"external_id,omitempty" db:"external_id"`
+	// Version increments on every update and backs optimistic concurrency:
+	// Repository.Update fails with ErrVersionConflict if it has moved on.
+	Version int64 `json:"version" db:"version"`
+
+	// QueryTimeout caps, in seconds, how long a query against this connection
+	// may run; 0 leaves it to the server and request limits.
+	QueryTimeout int `json:"query_timeout,omitempty" db:"query_timeout"`
+
+	// ConfigVersion is the plugin config version Config was written at; see
+	// sdk.ConfigUpgrader. 0 means the config predates versioning.
+	ConfigVersion int `json:"config_version" db:"config_version"`
+
+	// Deprecation is non-nil once the connection has been marked deprecated.
+	Deprecation *Deprecation `json:"deprecation,omitempty" db:"deprecation"`
+
+	// Maintenance is the scheduled maintenance window, if any; queries are
+	// refused while it is open.
+	Maintenance *MaintenanceWindow `json:"maintenance,omitempty" db:"maintenance"`
+	// Replicas are read endpoints that read-only queries are routed to, in
+	// order, before falling back to the primary.
+	Replicas []Replica `json:"replicas,omitempty" db:"replicas"`
+	// NetworkPolicy restricts which networks and keys may use the
+	// connection; nil means any.
+	NetworkPolicy *NetworkPolicy `json:"network_policy,omitempty" db:"network_policy"`
+	// CostModel prices queries by the bytes they scan; nil leaves them
+	// unpriced.
+	CostModel *CostModel `json:"cost_model,omitempty" db:"cost_model"`
+
 	Tags       []string                  `json:"tags,omitempty"        db:"-"`
 	TestResult *sdk.ConnectionTestResult `json:"test_result,omitempty" db:"-"`
 }
+
+// Deprecation records that a resource is being retired. Queries against a
+// deprecated resource still run but carry a warning; SunsetAt is the date
+// after which it may be removed.
+type Deprecation struct {
+	Message      string     `json:"message"`
+	DeprecatedAt time.Time  `json:"deprecated_at"`
+	SunsetAt     *time.Time `json:"sunset_at,omitempty"`
+}
+
+// Warning returns the human-readable notice attached to query responses.
+func (d *Deprecation) Warning(name string, now time.Time) string {
+	msg := fmt.Sprintf("datasource %q is deprecated", name)
+	if d.SunsetAt != nil {
+		if now.Before(*d.SunsetAt) {
+			msg += fmt.Sprintf(" and will be sunset on %s", d.SunsetAt.Format("2006-01-02"))
+		} else {
+			msg += fmt.Sprintf(" and was sunset on %s", d.SunsetAt.Format("2006-01-02"))
+		}
+	}
+	if d.Message != "" {
+		msg += ": " + d.Message
+	}
+	return msg
+}
+
+// MaintenanceWindow is a period during which the datasource is unavailable.
+type MaintenanceWindow struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Message string    `json:"message,omitempty"`
+}
+
+// Active reports whether the window is open at now.
+func (m *MaintenanceWindow) Active(now time.Time) bool {
+	return m != nil && !now.Before(m.Start) && now.Before(m.End)
+}
+
+// Notice returns the message shown to users whose queries are refused.
+func (m *MaintenanceWindow) Notice(name string) string {
+	msg := fmt.Sprintf("datasource %q is under maintenance until %s", name, m.End.UTC().Format("2006-01-02 15:04 MST"))
+	if m.Message != "" {
+		msg += ": " + m.Message
+	}
+	return msg
+}
+
+// Replica is a read endpoint of a datasource. Config holds driver options
+// merged over the datasource's own, typically just a different host.
+type Replica struct {
+	Name   string          `json:"name"`
+	Config json.RawMessage `json:"config"`
+}
@@ -0,0 +1,220 @@
+package connection
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+)
+
+// KeyHeader carries a datasource key.
+const KeyHeader = "X-Voyager-Datasource-Key"
+
+// ErrCodeNetworkPolicy is the ErrorResponse code for requests a datasource's
+// network policy refuses.
+const ErrCodeNetworkPolicy = "network_policy"
+
+// ErrNetworkPolicy is returned when a request's origin may not use a
+// datasource.
+var ErrNetworkPolicy = errors.New("datasource cannot be used from this network")
+
+// NetworkPolicy restricts where a sensitive datasource can be used from.
+// Denied networks are always refused. When allowed networks or keys are
+// set, a request must also come from an allowed network or carry a key.
+// Keys are a second factor, not a login: the caller still authenticates
+// as usual.
+type NetworkPolicy struct {
+	AllowCIDRs []string     `json:"allow_cidrs,omitempty"`
+	DenyCIDRs  []string     `json:"deny_cidrs,omitempty"`
+	Keys       []NetworkKey `json:"keys,omitempty"`
+}
+
+// NetworkKey is a datasource key. Only its hash is stored.
+type NetworkKey struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Hash      string    `json:"hash"` // hex SHA-256
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Empty reports whether the policy restricts nothing.
+func (p *NetworkPolicy) Empty() bool {
+	return p == nil || (len(p.AllowCIDRs) == 0 && len(p.DenyCIDRs) == 0 && len(p.Keys) == 0)
+}
+
+// Validate checks that every network parses, as a CIDR or a single address.
+func (p *NetworkPolicy) Validate() error {
+	for _, list := range [][]string{p.AllowCIDRs, p.DenyCIDRs} {
+		for _, s := range list {
+			if _, err := parsePrefix(s); err != nil {
+				return fmt.Errorf("invalid network %q", s)
+			}
+		}
+	}
+	return nil
+}
+
+// Allows reports whether a request from o may use the datasource.
+func (p *NetworkPolicy) Allows(o Origin) bool {
+	if p.Empty() {
+		return true
+	}
+	if o.IP.IsValid() && inAny(p.DenyCIDRs, o.IP) {
+		return false
+	}
+	if len(p.AllowCIDRs) == 0 && len(p.Keys) == 0 {
+		return true
+	}
+	if o.IP.IsValid() && inAny(p.AllowCIDRs, o.IP) {
+		return true
+	}
+	return o.Key != "" && p.keyID(o.Key) != ""
+}
+
+// keyID returns the ID of the key matching raw, or "".
+func (p *NetworkPolicy) keyID(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	hash := hex.EncodeToString(sum[:])
+	for _, k := range p.Keys {
+		if subtle.ConstantTimeCompare([]byte(k.Hash), []byte(hash)) == 1 {
+			return k.ID
+		}
+	}
+	return ""
+}
+
+// addKey creates a key named name and returns it with its secret, which is
+// not stored.
+func (p *NetworkPolicy) addKey(name string, now time.Time) (NetworkKey, string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return NetworkKey{}, "", err
+	}
+	raw := "dvk_" + hex.EncodeToString(b)
+	sum := sha256.Sum256([]byte(raw))
+	k := NetworkKey{ID: uuid.NewString(), Name: name, Hash: hex.EncodeToString(sum[:]), CreatedAt: now}
+	p.Keys = append(p.Keys, k)
+	return k, raw, nil
+}
+
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		p, err := netip.ParsePrefix(s)
+		return p.Masked(), err
+	}
+	a, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(a, a.BitLen()), nil
+}
+
+func inAny(cidrs []string, ip netip.Addr) bool {
+	for _, s := range cidrs {
+		if p, err := parsePrefix(s); err == nil && p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Origin is where a request came from.
+type Origin struct {
+	IP  netip.Addr
+	Key string
+}
+
+type originKey struct{}
+
+// WithOrigin returns a context carrying the request origin o.
+func WithOrigin(ctx context.Context, o Origin) context.Context {
+	return context.WithValue(ctx, originKey{}, o)
+}
+
+// OriginFrom returns the request origin of ctx, if it has one.
+func OriginFrom(ctx context.Context) (Origin, bool) {
+	o, ok := ctx.Value(originKey{}).(Origin)
+	return o, ok
+}
+
+// CheckOrigin returns ErrNetworkPolicy when ctx carries a request origin
+// conn's network policy refuses. Contexts without an origin, such as those
+// of scheduled jobs, pass.
+func CheckOrigin(ctx context.Context, conn *Connection) error {
+	o, ok := OriginFrom(ctx)
+	if !ok || conn.NetworkPolicy.Allows(o) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrNetworkPolicy, conn.Name)
+}
+
+// RequestOrigin returns where c came from: the client address and the
+// datasource key it presented.
+func RequestOrigin(c *gin.Context) Origin {
+	o := Origin{Key: c.GetHeader(KeyHeader)}
+	if ip, err := netip.ParseAddr(c.ClientIP()); err == nil {
+		o.IP = ip.Unmap()
+	}
+	return o
+}
+
+// NetworkMiddleware records every request's origin for CheckOrigin and
+// enforces network policies on the routes under /datasources/:uid. Reading
+// a datasource's settings and managing its network policy stay reachable
+// so an admin can correct a policy from anywhere.
+func NetworkMiddleware(repo Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := WithOrigin(c.Request.Context(), RequestOrigin(c))
+		c.Request = c.Request.WithContext(ctx)
+
+		route := c.FullPath()
+		_, rest, ok := strings.Cut(route, "/datasources/:uid")
+		if !ok || strings.HasPrefix(rest, "/network-policy") || (rest == "" && c.Request.Method == http.MethodGet) {
+			c.Next()
+			return
+		}
+		conn, err := repo.GetByID(ctx, c.Param("uid"))
+		if err != nil {
+			// Unknown datasources are left to the handler to report.
+			c.Next()
+			return
+		}
+		if err := CheckOrigin(ctx, conn); err != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, networkPolicyError(c))
+			return
+		}
+		c.Next()
+	}
+}
+
+// rejectByNetwork answers 403 and reports true when conn's network policy
+// refuses the request's origin. NetworkMiddleware only covers routes under
+// /datasources/:uid; this covers the ones naming conn elsewhere.
+func rejectByNetwork(c *gin.Context, conn *Connection) bool {
+	if CheckOrigin(c.Request.Context(), conn) == nil {
+		return false
+	}
+	c.JSON(http.StatusForbidden, networkPolicyError(c))
+	return true
+}
+
+func networkPolicyError(c *gin.Context) api.ErrorResponse {
+	code := ErrCodeNetworkPolicy
+	return api.ErrorResponse{
+		Error: i18n.T(c, "this datasource cannot be used from your network"),
+		Code:  &code,
+	}
+}
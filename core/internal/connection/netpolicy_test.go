@@ -0,0 +1,160 @@
+package connection
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/identity"
+)
+
+func TestNetworkPolicy_Allows(t *testing.T) {
+	p := &NetworkPolicy{AllowCIDRs: []string{"10.0.0.0/8", "192.0.2.7"}, DenyCIDRs: []string{"10.9.0.0/16"}}
+	key, raw, err := p.addKey("ci", time.Now())
+	require.NoError(t, err)
+	assert.NotContains(t, key.Hash, raw)
+
+	ip := netip.MustParseAddr
+	for _, tc := range []struct {
+		name string
+		o    Origin
+		want bool
+	}{
+		{"allowed network", Origin{IP: ip("10.1.2.3")}, true},
+		{"allowed address", Origin{IP: ip("192.0.2.7")}, true},
+		{"other network", Origin{IP: ip("203.0.113.5")}, false},
+		{"other network with key", Origin{IP: ip("203.0.113.5"), Key: raw}, true},
+		{"other network with wrong key", Origin{IP: ip("203.0.113.5"), Key: "dvk_nope"}, false},
+		{"denied network", Origin{IP: ip("10.9.1.1")}, false},
+		{"denied network with key", Origin{IP: ip("10.9.1.1"), Key: raw}, false},
+	} {
+		assert.Equal(t, tc.want, p.Allows(tc.o), tc.name)
+	}
+
+	var none *NetworkPolicy
+	assert.True(t, none.Allows(Origin{IP: ip("203.0.113.5")}))
+	denyOnly := &NetworkPolicy{DenyCIDRs: []string{"203.0.113.0/24"}}
+	assert.False(t, denyOnly.Allows(Origin{IP: ip("203.0.113.5")}))
+	assert.True(t, denyOnly.Allows(Origin{IP: ip("198.51.100.1")}))
+}
+
+func TestNetworkPolicy_Validate(t *testing.T) {
+	assert.NoError(t, (&NetworkPolicy{AllowCIDRs: []string{"10.0.0.0/8", "::1", "2001:db8::/32"}}).Validate())
+	assert.Error(t, (&NetworkPolicy{AllowCIDRs: []string{"10.0.0.0/33"}}).Validate())
+	assert.Error(t, (&NetworkPolicy{DenyCIDRs: []string{"office"}}).Validate())
+}
+
+func TestNetworkMiddleware(t *testing.T) {
+	conn := storedConn()
+	conn.NetworkPolicy = &NetworkPolicy{AllowCIDRs: []string{"10.0.0.0/8"}}
+	_, raw, err := conn.NetworkPolicy.addKey("ci", time.Now())
+	require.NoError(t, err)
+
+	r := gin.New()
+	require.NoError(t, r.SetTrustedProxies(nil))
+	v1 := r.Group("/api/v1", NetworkMiddleware(&mockRepo{conn: conn}))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	v1.GET("/datasources/:uid", ok)
+	v1.POST("/datasources/:uid/query", ok)
+	v1.PUT("/datasources/:uid/network-policy", ok)
+
+	do := func(method, path, remote, key string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = remote + ":1234"
+		req.Header.Set("X-Forwarded-For", "10.0.0.1")
+		if key != "" {
+			req.Header.Set(KeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	query := "/api/v1/datasources/" + testConnID + "/query"
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, query, "10.2.3.4", ""))
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPost, query, "203.0.113.5", ""), "X-Forwarded-For from an untrusted peer is ignored")
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, query, "203.0.113.5", raw))
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/datasources/"+testConnID, "203.0.113.5", ""))
+	assert.Equal(t, http.StatusOK, do(http.MethodPut, "/api/v1/datasources/"+testConnID+"/network-policy", "203.0.113.5", ""))
+}
+
+func TestDatasourceNetworkPolicyHandlers(t *testing.T) {
+	repo := &updatingRepo{mockRepo: mockRepo{conn: storedConn()}}
+	h := newHandler(repo, &mockPlugin{})
+	id := uuid.MustParse(testConnID)
+	role := identity.RoleAdmin
+	call := func(method string, body any, fn func(c *gin.Context)) *httptest.ResponseRecorder {
+		raw, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(method, "/", bytes.NewReader(raw))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request = c.Request.WithContext(identity.With(c.Request.Context(), &identity.Identity{Username: "u", Role: role}))
+		fn(c)
+		return w
+	}
+
+	role = identity.RoleEditor
+	w := call(http.MethodPost, map[string]any{"name": "etl"}, func(c *gin.Context) { h.CreateDatasourceKey(c, id) })
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	role = identity.RoleAdmin
+
+	w = call(http.MethodPut, map[string]any{"allowCidrs": []string{"not a network"}}, func(c *gin.Context) { h.SetDatasourceNetworkPolicy(c, id) })
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = call(http.MethodPost, map[string]any{"name": "etl"}, func(c *gin.Context) { h.CreateDatasourceKey(c, id) })
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created struct {
+		Data api.CreatedDatasourceKey `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.NotEmpty(t, created.Data.Key)
+	require.NotNil(t, repo.updated.NetworkPolicy)
+	assert.True(t, repo.updated.NetworkPolicy.Allows(Origin{Key: created.Data.Key}))
+
+	w = call(http.MethodPut, map[string]any{"allowCidrs": []string{"10.0.0.0/8"}}, func(c *gin.Context) { h.SetDatasourceNetworkPolicy(c, id) })
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Len(t, repo.updated.NetworkPolicy.Keys, 1, "setting networks keeps the keys")
+	var resp api.DatasourceResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Data.NetworkPolicy)
+	assert.NotContains(t, w.Body.String(), repo.updated.NetworkPolicy.Keys[0].Hash, "hashes are not returned")
+
+	w = call(http.MethodDelete, nil, func(c *gin.Context) { h.DeleteDatasourceKey(c, id, created.Data.Id) })
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Empty(t, repo.updated.NetworkPolicy.Keys)
+
+	w = call(http.MethodDelete, nil, func(c *gin.Context) { h.ClearDatasourceNetworkPolicy(c, id) })
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Nil(t, repo.updated.NetworkPolicy)
+}
+
+func TestNetworkPolicy_RoutesOutsideDatasources(t *testing.T) {
+	conn := storedConn()
+	conn.NetworkPolicy = &NetworkPolicy{AllowCIDRs: []string{"10.0.0.0/8"}}
+	h := newHandler(&mockRepo{conn: conn}, &mockPlugin{})
+
+	raw, _ := json.Marshal(map[string]any{
+		"keys":  []string{"id"},
+		"left":  map[string]any{"datasource_id": testConnID, "table": "users"},
+		"right": map[string]any{"datasource_id": testConnID, "table": "users"},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/diff", bytes.NewReader(raw))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(WithOrigin(req.Context(), Origin{IP: netip.MustParseAddr("203.0.113.5")}))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	h.DiffData(c)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), ErrCodeNetworkPolicy)
+}
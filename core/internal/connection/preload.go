@@ -0,0 +1,158 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"data-voyager/sdk"
+)
+
+// PreloadTag marks a datasource for warm-up at server start: it is
+// connected to and has its schema cached before traffic arrives.
+const PreloadTag = "preload"
+
+// schemaCacheTTL bounds how stale a cached schema may be served.
+const schemaCacheTTL = 5 * time.Minute
+
+// schemaCache holds the schemas of preloaded datasources. Entries are keyed
+// by ID and remember the version they were read at, so an edited datasource
+// is re-read rather than served from cache.
+type schemaCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedSchema
+}
+
+type cachedSchema struct {
+	version int64
+	schema  *sdk.SchemaInfo
+	fetched time.Time
+}
+
+func (s *schemaCache) get(conn *Connection, now time.Time) (*sdk.SchemaInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[conn.ID]
+	if !ok || e.version != conn.Version || now.Sub(e.fetched) > schemaCacheTTL {
+		return nil, false
+	}
+	return e.schema, true
+}
+
+// put caches schema and returns the one it replaces, if any was read under
+// the same configuration version: a changed configuration may point at
+// another database altogether.
+func (s *schemaCache) put(conn *Connection, schema *sdk.SchemaInfo, now time.Time) *sdk.SchemaInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.entries == nil {
+		s.entries = map[string]cachedSchema{}
+	}
+	prev, ok := s.entries[conn.ID]
+	s.entries[conn.ID] = cachedSchema{version: conn.Version, schema: schema, fetched: now}
+	if !ok || prev.version != conn.Version {
+		return nil
+	}
+	return prev.schema
+}
+
+func (s *schemaCache) forget(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, id)
+}
+
+// preloaded reports whether conn is tagged for warm-up and schema caching.
+func preloaded(conn *Connection) bool {
+	return slices.Contains(conn.Tags, PreloadTag)
+}
+
+// errConnect wraps failures to reach the datasource, as opposed to failures
+// reading its schema once connected.
+var errConnect = errors.New("datasource failed")
+
+// schema reads a datasource's schema, from cache when conn is preloaded.
+func (h *Handler) schema(ctx context.Context, plugin sdk.DatasourcePlugin, conn *Connection) (*sdk.SchemaInfo, error) {
+	if !preloaded(conn) {
+		return h.readSchema(ctx, plugin, conn)
+	}
+	if s, ok := h.schemas.get(conn, time.Now()); ok {
+		return s, nil
+	}
+	s, err := h.readSchema(ctx, plugin, conn)
+	if err == nil {
+		if prev := h.schemas.put(conn, s, time.Now()); prev != nil {
+			h.notifySchemaChange(ctx, conn, prev, s)
+		}
+	}
+	return s, err
+}
+
+func (h *Handler) readSchema(ctx context.Context, plugin sdk.DatasourcePlugin, conn *Connection) (*sdk.SchemaInfo, error) {
+	dbConn, _, err := h.connect(ctx, plugin, conn, true)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errConnect, err)
+	}
+	defer func() { _ = dbConn.Close() }()
+	return dbConn.GetSchema(ctx)
+}
+
+// Preload warms up every active datasource tagged PreloadTag, at most
+// concurrency at a time. Connecting surfaces bad credentials and unreachable
+// hosts in the startup log, and warms DNS, TLS and replica health; the
+// schema is then cached so the first schema browse after a deploy is fast.
+// Failures are logged and never abort startup. It returns how many
+// datasources warmed up and how many failed.
+func (h *Handler) Preload(ctx context.Context, concurrency int) (warmed, failed int) {
+	if concurrency < 1 {
+		return 0, 0
+	}
+	active := true
+	conns, err := h.repo.List(ctx, Filter{Tags: []string{PreloadTag}, IsActive: &active})
+	if err != nil {
+		slog.Warn("datasource preload skipped", "err", err)
+		return 0, 0
+	}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, concurrency)
+	)
+	for _, conn := range conns {
+		if conn.Maintenance.Active(time.Now()) {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			start := time.Now()
+			err := h.preloadOne(ctx, conn)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed++
+				slog.Warn("datasource preload failed", "datasource", conn.Name, "err", err)
+				return
+			}
+			warmed++
+			slog.Debug("datasource preloaded", "datasource", conn.Name, "duration", time.Since(start))
+		}()
+	}
+	wg.Wait()
+	return warmed, failed
+}
+
+func (h *Handler) preloadOne(ctx context.Context, conn *Connection) error {
+	plugin, ok := h.registry.Get(conn.Type)
+	if !ok {
+		return fmt.Errorf("plugin not found for type %s", conn.Type)
+	}
+	_, err := h.schema(ctx, plugin, conn)
+	return err
+}
@@ -0,0 +1,61 @@
+package connection
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type listRepo struct {
+	mockRepo
+	conns []*Connection
+}
+
+func (r *listRepo) List(_ context.Context, _ Filter) ([]*Connection, error) { return r.conns, nil }
+
+func preloadConn(id, host string) *Connection {
+	return &Connection{ID: id, Name: id, Type: "mock", Config: json.RawMessage(`{"host":"` + host + `"}`), Tags: []string{PreloadTag}}
+}
+
+func TestPreload(t *testing.T) {
+	a, b := preloadConn("a", "host-a"), preloadConn("b", "host-b")
+	plugin := &hostPlugin{mockPlugin: mockPlugin{dbConn: &mockConn{}}, down: map[string]bool{"host-b": true}}
+	h := newHandler(&listRepo{conns: []*Connection{a, b}}, plugin)
+
+	warmed, failed := h.Preload(context.Background(), 1)
+	assert.Equal(t, 1, warmed)
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, []string{"host-a", "host-b"}, plugin.attempts)
+
+	// The warmed schema is served from cache; the failed one is retried.
+	plugin.attempts = nil
+	_, err := h.schema(context.Background(), plugin, a)
+	assert.NoError(t, err)
+	_, err = h.schema(context.Background(), plugin, b)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"host-b"}, plugin.attempts)
+
+	// Editing the datasource invalidates its cached schema.
+	plugin.attempts = nil
+	a.Version++
+	_, _ = h.schema(context.Background(), plugin, a)
+	assert.Equal(t, []string{"host-a"}, plugin.attempts)
+}
+
+func TestPreload_DisabledAndUntagged(t *testing.T) {
+	plugin := &hostPlugin{mockPlugin: mockPlugin{dbConn: &mockConn{}}}
+	h := newHandler(&listRepo{conns: []*Connection{preloadConn("a", "host-a")}}, plugin)
+	warmed, failed := h.Preload(context.Background(), 0)
+	assert.Zero(t, warmed+failed)
+	assert.Empty(t, plugin.attempts)
+
+	// Datasources that are not preloaded always read a fresh schema.
+	conn := preloadConn("b", "host-b")
+	conn.Tags = nil
+	for range 2 {
+		_, _ = h.schema(context.Background(), plugin, conn)
+	}
+	assert.Equal(t, []string{"host-b", "host-b"}, plugin.attempts)
+}
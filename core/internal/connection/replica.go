@@ -0,0 +1,165 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/sdk"
+)
+
+// ReplicaHeader names the replica a query ran on in query responses; it is
+// absent when the query ran on the primary.
+const ReplicaHeader = "X-Voyager-Replica"
+
+// replicaRetryAfter is how long a replica that failed to connect is skipped
+// before being tried again.
+const replicaRetryAfter = 30 * time.Second
+
+var (
+	// sqlNoiseRe matches comments, string literals and quoted identifiers,
+	// which are blanked before looking for keywords.
+	sqlNoiseRe   = regexp.MustCompile(`(?s)--[^\n]*|/\*.*?\*/|'(?:[^'\\]|''|\\.)*'|"(?:[^"]|"")*"|` + "`[^`]*`")
+	readPrefixRe = regexp.MustCompile(`(?i)^\s*(SELECT|WITH|SHOW|DESCRIBE|DESC|EXPLAIN|VALUES)\b`)
+	// writeKeywordRe catches writes hidden in read-looking statements, such
+	// as data-modifying CTEs, SELECT INTO and SELECT ... FOR UPDATE.
+	writeKeywordRe = regexp.MustCompile(`(?i)\b(INSERT|UPDATE|DELETE|MERGE|UPSERT|CREATE|ALTER|DROP|TRUNCATE|RENAME|GRANT|REVOKE|INTO|CALL|EXEC|EXECUTE|OPTIMIZE|SYSTEM|KILL|VACUUM|COPY|LOCK)\b`)
+)
+
+// IsReadOnlyQuery reports whether query is safe to run on a replica. It errs
+// towards the primary: anything it cannot classify as a plain read is not.
+func IsReadOnlyQuery(query string) bool {
+	q := sqlNoiseRe.ReplaceAllString(query, " ")
+	return readPrefixRe.MatchString(q) && !writeKeywordRe.MatchString(q)
+}
+
+// replicaHealth remembers replicas that recently failed to connect so that
+// queries do not wait on them. The zero value is ready to use.
+type replicaHealth struct {
+	mu   sync.Mutex
+	down map[string]time.Time // "<conn id>/<replica>" → retry after
+}
+
+func (r *replicaHealth) available(key string, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !now.Before(r.down[key])
+}
+
+func (r *replicaHealth) mark(key string, ok bool, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ok {
+		delete(r.down, key)
+		return
+	}
+	if r.down == nil {
+		r.down = map[string]time.Time{}
+	}
+	r.down[key] = now.Add(replicaRetryAfter)
+}
+
+// connect opens a session on conn: the first reachable replica when
+// readOnly is set, otherwise — or when no replica can be reached — the
+// primary. replica names the replica used and is empty for the primary.
+func (h *Handler) connect(ctx context.Context, plugin sdk.DatasourcePlugin, conn *Connection, readOnly bool) (dbConn sdk.Connection, replica string, err error) {
+	if readOnly && len(conn.Replicas) > 0 {
+		for _, r := range conn.Replicas {
+			key := conn.ID + "/" + r.Name
+			if !h.replicas.available(key, time.Now()) {
+				continue
+			}
+			dbConn, err := connectReplica(ctx, plugin, conn, r)
+			h.replicas.mark(key, err == nil, time.Now())
+			if err == nil {
+				return dbConn, r.Name, nil
+			}
+			slog.Warn("replica unavailable, failing over", "datasource", conn.Name, "replica", r.Name, "err", err)
+		}
+	}
+	cfg, err := plugin.ParseConfig(conn.Config)
+	if err != nil {
+		return nil, "", fmt.Errorf("parse config: %w", err)
+	}
+	dbConn, err = plugin.Connect(ctx, cfg)
+	return dbConn, "", err
+}
+
+func connectReplica(ctx context.Context, plugin sdk.DatasourcePlugin, conn *Connection, r Replica) (sdk.Connection, error) {
+	merged, err := MergeConfig(conn.Config, r.Config)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := plugin.ParseConfig(merged)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.Connect(ctx, cfg)
+}
+
+// allReadOnly reports whether every query may run on a replica.
+func allReadOnly(queries ...string) bool {
+	for _, q := range queries {
+		if !IsReadOnlyQuery(q) {
+			return false
+		}
+	}
+	return len(queries) > 0
+}
+
+// applyReplicas validates replicas against conn's config and sets them. On
+// failure it writes the error response and returns false.
+func (h *Handler) applyReplicas(c *gin.Context, conn *Connection, replicas []api.DatasourceReplica) bool {
+	plugin, ok := h.registry.Get(conn.Type)
+	if !ok {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: i18n.T(c, "plugin not found for type")})
+		return false
+	}
+	list := make([]Replica, len(replicas))
+	for i, r := range replicas {
+		list[i] = Replica{Name: strings.TrimSpace(r.Name), Config: r.Options}
+	}
+	if err := validReplicas(plugin, conn.Config, list); err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse{Error: err.Error()})
+		return false
+	}
+	conn.Replicas = list
+	return true
+}
+
+func setReplicaHeader(c *gin.Context, replica string) {
+	if replica != "" {
+		c.Header(ReplicaHeader, replica)
+	}
+}
+
+// validReplicas checks a replica list: names must be unique and non-empty
+// and each merged config must parse.
+func validReplicas(plugin sdk.DatasourcePlugin, base []byte, replicas []Replica) error {
+	seen := map[string]bool{}
+	for _, r := range replicas {
+		name := strings.TrimSpace(r.Name)
+		if name == "" || seen[name] {
+			return errors.New("replica names must be unique and non-empty")
+		}
+		seen[name] = true
+		merged, err := MergeConfig(base, r.Config)
+		if err != nil {
+			return fmt.Errorf("replica %s: %w", name, err)
+		}
+		if _, err := plugin.ParseConfig(merged); err != nil {
+			return fmt.Errorf("replica %s: %w", name, err)
+		}
+	}
+	return nil
+}
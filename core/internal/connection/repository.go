@@ -2,16 +2,25 @@ package connection
 
 import (
 	"context"
+	"errors"
 
 	"data-voyager/sdk"
 )
 
+// ErrVersionConflict is returned by Repository.Update when the stored
+// connection's version no longer matches the one being written.
+var ErrVersionConflict = errors.New("datasource was modified concurrently")
+
+// ErrNotFound is returned by Repository.GetByID for an unknown ID.
+var ErrNotFound = errors.New("not found")
+
 // Repository is the persistence interface for Connection.
 // Implemented per-driver in store/{postgres,sqlite,mysql}/.
 type Repository interface {
 	Create(ctx context.Context, c *Connection) error
 	GetByID(ctx context.Context, id string) (*Connection, error)
 	GetByName(ctx context.Context, name string) (*Connection, error)
+	GetByExternalID(ctx context.Context, externalID string) (*Connection, error)
 	List(ctx context.Context, filter Filter) ([]*Connection, error)
 	Update(ctx context.Context, c *Connection) error
 	Delete(ctx context.Context, id string) error
@@ -21,10 +30,13 @@ type Repository interface {
 
 // Filter holds optional filters for List.
 type Filter struct {
-	Type      sdk.DataSourceType
-	IsActive  *bool
-	CreatedBy string
-	Tags      []string
+	Type        sdk.DataSourceType
+	IsActive    *bool
+	CreatedBy   string
+	Tags        []string
+	TemplateID  string
+	Environment string
+	Alias       string
 }
 
 // Stats holds aggregate counts.
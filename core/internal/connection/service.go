@@ -2,7 +2,9 @@ package connection
 
 import (
 	"context"
+	"log/slog"
 
+	"data-voyager/core/internal/buildinfo"
 	"data-voyager/core/internal/datasource"
 	"data-voyager/sdk"
 )
@@ -18,10 +20,20 @@ func NewService(repo Repository, registry *datasource.Registry) *Service {
 	return &Service{repo: repo, registry: registry}
 }
 
-// InitializePlugins loads all extensions registered via sdk.RegisterDatasource.
+// InitializePlugins loads all extensions registered via sdk.RegisterDatasource
+// and logs their versions. A plugin that does not support this server
+// release is still loaded, with a warning.
 func (s *Service) InitializePlugins() {
 	for _, p := range sdk.GetDatasourcePlugins() {
 		s.registry.Register(p)
+		v := sdk.PluginVersion(p)
+		attrs := []any{"type", p.GetType(), "version", v.Version, "driver", v.DriverVersion}
+		if !v.SupportsServer(buildinfo.Version) {
+			slog.Warn("datasource plugin does not support this server version", append(attrs,
+				"server", buildinfo.Version, "min_server", v.MinServerVersion, "max_server", v.MaxServerVersion)...)
+			continue
+		}
+		slog.Info("datasource plugin loaded", attrs...)
 	}
 }
 
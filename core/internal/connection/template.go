@@ -0,0 +1,87 @@
+package connection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"data-voyager/sdk"
+)
+
+// Template holds shared defaults (pool sizes, SSL options, guardrails, tags)
+// that datasources of the same type can inherit and selectively override.
+type Template struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Type        sdk.DataSourceType `json:"type"`
+	Config      json.RawMessage    `json:"config"`
+	Description string             `json:"description"`
+	Tags        []string           `json:"tags,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// TemplateRepository is the persistence interface for Template.
+// Implemented per-driver in store/{postgres,sqlite,mysql}/.
+type TemplateRepository interface {
+	Create(ctx context.Context, t *Template) error
+	GetByID(ctx context.Context, id string) (*Template, error)
+	List(ctx context.Context) ([]*Template, error)
+	// Update writes t and, in the same transaction, the configs derived
+	// for its dependents, so a failure leaves every one of them as it was.
+	// A dependent that changed since it was read fails the whole update
+	// with ErrVersionConflict.
+	Update(ctx context.Context, t *Template, dependents []*Connection) error
+	Delete(ctx context.Context, id string) error
+}
+
+// MergeConfig deep-merges overrides onto base. Keys in overrides win; nested
+// objects are merged recursively and any other value replaces the base value.
+func MergeConfig(base, overrides json.RawMessage) (json.RawMessage, error) {
+	var b, o map[string]any
+	if len(base) > 0 {
+		if err := json.Unmarshal(base, &b); err != nil {
+			return nil, fmt.Errorf("invalid template config: %w", err)
+		}
+	}
+	if len(overrides) > 0 {
+		if err := json.Unmarshal(overrides, &o); err != nil {
+			return nil, fmt.Errorf("invalid config overrides: %w", err)
+		}
+	}
+	return json.Marshal(mergeMaps(b, o))
+}
+
+func mergeMaps(base, overrides map[string]any) map[string]any {
+	out := make(map[string]any, len(base)+len(overrides))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range overrides {
+		if bm, ok := out[k].(map[string]any); ok {
+			if om, ok := v.(map[string]any); ok {
+				out[k] = mergeMaps(bm, om)
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// mergeTags returns the union of template and datasource tags, preserving order.
+func mergeTags(templateTags, tags []string) []string {
+	seen := make(map[string]struct{}, len(templateTags)+len(tags))
+	var out []string
+	for _, list := range [][]string{templateTags, tags} {
+		for _, t := range list {
+			if _, ok := seen[t]; ok {
+				continue
+			}
+			seen[t] = struct{}{}
+			out = append(out, t)
+		}
+	}
+	return out
+}
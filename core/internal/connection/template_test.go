@@ -0,0 +1,38 @@
+package connection
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeConfig_OverridesWinAndNestedMerge(t *testing.T) {
+	base := json.RawMessage(`{"host":"db.internal","port":5432,"ssl_mode":"require","pool":{"max_open":25,"max_idle":5}}`)
+	overrides := json.RawMessage(`{"host":"db-eu.internal","pool":{"max_open":50}}`)
+
+	merged, err := MergeConfig(base, overrides)
+	require.NoError(t, err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(merged, &got))
+	assert.Equal(t, "db-eu.internal", got["host"])
+	assert.Equal(t, "require", got["ssl_mode"])
+	assert.Equal(t, map[string]any{"max_open": float64(50), "max_idle": float64(5)}, got["pool"])
+}
+
+func TestMergeConfig_EmptyOverrides(t *testing.T) {
+	merged, err := MergeConfig(json.RawMessage(`{"host":"a"}`), nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"host":"a"}`, string(merged))
+}
+
+func TestMergeConfig_InvalidJSON(t *testing.T) {
+	_, err := MergeConfig(json.RawMessage(`{`), nil)
+	assert.Error(t, err)
+}
+
+func TestMergeTags_UnionPreservesOrder(t *testing.T) {
+	assert.Equal(t, []string{"prod", "eu", "finance"}, mergeTags([]string{"prod", "eu"}, []string{"eu", "finance"}))
+}
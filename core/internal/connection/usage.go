@@ -0,0 +1,36 @@
+package connection
+
+import (
+	"context"
+
+	"data-voyager/sdk"
+)
+
+// UsageRecorder is told about every query a datasource ran successfully so
+// usage can be reported without this package knowing how it is stored.
+// stats are the driver's statistics for the query, which price it under
+// the datasource's cost model. Implementations must not block the request.
+type UsageRecorder interface {
+	RecordQuery(ctx context.Context, conn *Connection, query string, stats sdk.QueryStats)
+}
+
+// WithUsageRecorder reports executed queries to r.
+func (h *Handler) WithUsageRecorder(r UsageRecorder) *Handler {
+	h.usage = r
+	return h
+}
+
+func (h *Handler) recordUsage(ctx context.Context, conn *Connection, query string, stats sdk.QueryStats) {
+	if h.usage != nil {
+		h.usage.RecordQuery(ctx, conn, query, stats)
+	}
+}
+
+// UsageRecorders reports every query to each of its recorders in turn.
+type UsageRecorders []UsageRecorder
+
+func (rs UsageRecorders) RecordQuery(ctx context.Context, conn *Connection, query string, stats sdk.QueryStats) {
+	for _, r := range rs {
+		r.RecordQuery(ctx, conn, query, stats)
+	}
+}
@@ -0,0 +1,95 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/authz"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// visibleRepository hides the datasources a request's identity may not
+// see, so every handler treats them as missing. Requests without an
+// identity, and background jobs using the plain repository, see everything.
+// GetByName is left unfiltered so name uniqueness still covers hidden rows.
+type visibleRepository struct {
+	Repository
+}
+
+func (r visibleRepository) GetByID(ctx context.Context, id string) (*Connection, error) {
+	conn, err := r.Repository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !identity.FromContext(ctx).CanSee(conn.ID) {
+		return nil, fmt.Errorf("connection %s %w", id, ErrNotFound)
+	}
+	return conn, nil
+}
+
+func (r visibleRepository) GetByExternalID(ctx context.Context, externalID string) (*Connection, error) {
+	conn, err := r.Repository.GetByExternalID(ctx, externalID)
+	if err != nil {
+		return nil, err
+	}
+	if !identity.FromContext(ctx).CanSee(conn.ID) {
+		return nil, fmt.Errorf("connection with external id %s %w", externalID, ErrNotFound)
+	}
+	return conn, nil
+}
+
+func (r visibleRepository) List(ctx context.Context, filter Filter) ([]*Connection, error) {
+	conns, err := r.Repository.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	id := identity.FromContext(ctx)
+	out := conns[:0]
+	for _, c := range conns {
+		if id.CanSee(c.ID) {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+// Visible wraps repo so lookups made on behalf of a request only find the
+// datasources its identity may see. Services outside this package use it to
+// resolve datasources named in requests.
+func Visible(repo Repository) Repository {
+	if _, ok := repo.(visibleRepository); ok {
+		return repo
+	}
+	return visibleRepository{repo}
+}
+
+// requirePermission answers 403 unless the request's identity holds perm.
+func requirePermission(c *gin.Context, perm string) bool {
+	if identity.FromContext(c.Request.Context()).Can(perm) {
+		return true
+	}
+	c.JSON(http.StatusForbidden, api.ErrorResponse{Error: i18n.T(c, "permission denied")})
+	return false
+}
+
+// rejectByPolicy answers 403 and reports true when an access policy denies
+// querying conn. Routes under /datasources/:uid were already checked by the
+// policy middleware; this covers the ones naming conn elsewhere.
+func rejectByPolicy(c *gin.Context, conn *Connection) bool {
+	err := authz.CheckQuery(c.Request.Context(), conn.ID)
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, authz.ErrDenied):
+		c.JSON(http.StatusForbidden, api.ErrorResponse{Error: i18n.T(c, "denied by access policy")})
+	default:
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse{Error: err.Error()})
+	}
+	return true
+}
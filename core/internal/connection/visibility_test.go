@@ -0,0 +1,67 @@
+package connection
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/core/internal/identity"
+)
+
+func requestAs(id *identity.Identity, method, path string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, path, nil)
+	c.Request = c.Request.WithContext(identity.With(c.Request.Context(), id))
+	return c, w
+}
+
+func TestGetDatasource_HiddenFromRestrictedUser(t *testing.T) {
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{})
+
+	c, w := requestAs(&identity.Identity{Username: "ann", Role: identity.RoleViewer, Datasources: []string{"other"}},
+		http.MethodGet, "/datasources/"+testConnID)
+	h.GetDatasource(c, uuid.MustParse(testConnID))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	c, w = requestAs(&identity.Identity{Username: "bob", Role: identity.RoleViewer, Datasources: []string{testConnID}},
+		http.MethodGet, "/datasources/"+testConnID)
+	h.GetDatasource(c, uuid.MustParse(testConnID))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestDeleteDatasource_ViewerForbidden(t *testing.T) {
+	h := newHandler(&mockRepo{conn: storedConn()}, &mockPlugin{})
+	c, w := requestAs(&identity.Identity{Username: "ann", Role: identity.RoleViewer},
+		http.MethodDelete, "/datasources/"+testConnID)
+	h.DeleteDatasource(c, uuid.MustParse(testConnID), api.DeleteDatasourceParams{})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestVisible_HidesFromRestrictedCallers(t *testing.T) {
+	repo := Visible(&mockRepo{conn: storedConn()})
+	restricted := identity.With(context.Background(), &identity.Identity{Username: "ann", Role: identity.RoleViewer, Datasources: []string{"other"}})
+
+	_, err := repo.GetByID(restricted, testConnID)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	conn, err := repo.GetByID(context.Background(), testConnID)
+	require.NoError(t, err, "work without a caller sees every datasource")
+	assert.Equal(t, testConnID, conn.ID)
+	assert.Equal(t, repo, Visible(repo), "wrapping twice changes nothing")
+}
+
+func TestTestDatasourceConfig_ViewerForbidden(t *testing.T) {
+	h := newHandler(&mockRepo{}, &mockPlugin{})
+	c, w := requestAs(&identity.Identity{Username: "ann", Role: identity.RoleViewer},
+		http.MethodPost, "/datasources/test")
+	h.TestDatasourceConfig(c)
+	assert.Equal(t, http.StatusForbidden, w.Code, "testing a config reaches any host it names")
+}
@@ -0,0 +1,145 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/sdk"
+)
+
+// ErrCodeWarehouseResuming is the ErrorResponse code for queries refused
+// while the datasource's warehouse resumes from an idle suspend.
+const ErrCodeWarehouseResuming = "warehouse_resuming"
+
+const (
+	// warehouseRetryAfter is how long clients are told to wait for a
+	// resuming warehouse; most resume well within it.
+	warehouseRetryAfter = 15 * time.Second
+	// warehouseCheckTimeout bounds asking the backend for the state, which
+	// is advisory: a query goes ahead when the check fails.
+	warehouseCheckTimeout = 5 * time.Second
+	// warehouseFresh is how long after a successful query the warehouse is
+	// taken to still be running without asking again.
+	warehouseFresh = time.Minute
+)
+
+// warehouseActivity remembers when each datasource last answered a query,
+// so a busy datasource is not asked for its warehouse state on every query.
+// The zero value is ready to use.
+type warehouseActivity struct {
+	mu         sync.Mutex
+	lastActive map[string]time.Time
+}
+
+func (a *warehouseActivity) active(id string, now time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return now.Sub(a.lastActive[id]) < warehouseFresh
+}
+
+func (a *warehouseActivity) touch(id string, now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.lastActive == nil {
+		a.lastActive = map[string]time.Time{}
+	}
+	a.lastActive[id] = now
+}
+
+// WakeWarehouse checks the compute behind conn and, if it is suspended,
+// asks it to resume. It returns the state the warehouse is in afterwards,
+// or nil when the plugin does not suspend compute, the datasource names
+// none, or the backend could not be asked.
+func WakeWarehouse(ctx context.Context, plugin sdk.DatasourcePlugin, conn *Connection) *sdk.WarehouseStatus {
+	wc, ok := plugin.(sdk.WarehouseController)
+	if !ok {
+		return nil
+	}
+	cfg, err := plugin.ParseConfig(conn.Config)
+	if err != nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, warehouseCheckTimeout)
+	defer cancel()
+	st, err := wc.WarehouseStatus(ctx, cfg)
+	if err != nil {
+		slog.Warn("warehouse status check failed", "datasource", conn.Name, "err", err)
+		return nil
+	}
+	if st == nil || st.State != sdk.WarehouseSuspended {
+		return st
+	}
+	if err := wc.ResumeWarehouse(ctx, cfg); err != nil {
+		slog.Warn("warehouse resume failed", "datasource", conn.Name, "err", err)
+		return st
+	}
+	slog.Info("resuming suspended warehouse", "datasource", conn.Name, "state", st.Detail)
+	return &sdk.WarehouseStatus{State: sdk.WarehouseResuming, Detail: st.Detail}
+}
+
+// warehouseWaking reports whether the warehouse behind conn is not running,
+// waking it if it is suspended. Datasources that answered recently are
+// taken to be running.
+func (h *Handler) warehouseWaking(ctx context.Context, plugin sdk.DatasourcePlugin, conn *Connection) (*sdk.WarehouseStatus, bool) {
+	if _, ok := plugin.(sdk.WarehouseController); !ok || h.warehouses.active(conn.ID, time.Now()) {
+		return nil, false
+	}
+	st := WakeWarehouse(ctx, plugin, conn)
+	if st == nil || st.State == sdk.WarehouseRunning {
+		return st, false
+	}
+	return st, true
+}
+
+// rejectWhileResuming answers 503 when conn's warehouse is suspended or
+// resuming, rather than letting the query wait on it until it times out,
+// and reports whether it did.
+func (h *Handler) rejectWhileResuming(c *gin.Context, plugin sdk.DatasourcePlugin, conn *Connection) bool {
+	st, waking := h.warehouseWaking(c.Request.Context(), plugin, conn)
+	if !waking {
+		return false
+	}
+	c.Header("Retry-After", strconv.Itoa(int(warehouseRetryAfter.Seconds())))
+	c.JSON(http.StatusServiceUnavailable, warehouseResumingError(conn, st))
+	return true
+}
+
+func warehouseResumingError(conn *Connection, st *sdk.WarehouseStatus) api.ErrorResponse {
+	code := ErrCodeWarehouseResuming
+	detail := string(st.State)
+	if st.Detail != "" {
+		detail = st.Detail
+	}
+	msg := fmt.Sprintf("datasource %q is resuming its warehouse (%s); retry in about %d seconds",
+		conn.Name, detail, int(warehouseRetryAfter.Seconds()))
+	return api.ErrorResponse{Error: msg, Code: &code}
+}
+
+// setWarehouseRetry adds Retry-After to a failure caused by a resuming
+// warehouse.
+func setWarehouseRetry(c *gin.Context, resp api.ErrorResponse) {
+	if resp.Code != nil && *resp.Code == ErrCodeWarehouseResuming {
+		c.Header("Retry-After", strconv.Itoa(int(warehouseRetryAfter.Seconds())))
+	}
+}
+
+// resumingFailure explains a failed connect or timed-out query by the
+// warehouse having been suspended, which the check before the query can
+// miss when the warehouse suspended since it last answered. It returns nil
+// when the warehouse is running or its state is unknown.
+func (h *Handler) resumingFailure(ctx context.Context, q *preparedQuery) *queryFailure {
+	// ctx may be the one that ran out; the check gets its own deadline.
+	st := WakeWarehouse(context.WithoutCancel(ctx), q.plugin, q.conn)
+	if st == nil || st.State == sdk.WarehouseRunning {
+		return nil
+	}
+	return &queryFailure{http.StatusServiceUnavailable, warehouseResumingError(q.conn, st)}
+}
@@ -0,0 +1,104 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/api"
+	"data-voyager/sdk"
+)
+
+// warehousePlugin is a mockPlugin whose backend suspends idle compute.
+type warehousePlugin struct {
+	*mockPlugin
+	state   sdk.WarehouseState
+	err     error
+	resumed int
+}
+
+func (p *warehousePlugin) WarehouseStatus(context.Context, sdk.ConnectionConfig) (*sdk.WarehouseStatus, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &sdk.WarehouseStatus{State: p.state, Detail: "idle"}, nil
+}
+
+func (p *warehousePlugin) ResumeWarehouse(context.Context, sdk.ConnectionConfig) error {
+	p.resumed++
+	p.state = sdk.WarehouseResuming
+	return nil
+}
+
+func TestQueryDatasource_WarehouseResuming(t *testing.T) {
+	plugin := &warehousePlugin{
+		mockPlugin: &mockPlugin{dbConn: &mockConn{result: &sdk.QueryResult{}}},
+		state:      sdk.WarehouseSuspended,
+	}
+	h := newHandler(&mockRepo{conn: storedConn()}, plugin)
+
+	w := post(h, api.QueryRequest{Query: "SELECT 1"})
+	require.Equal(t, http.StatusServiceUnavailable, w.Code, w.Body.String())
+	assert.Equal(t, "15", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), ErrCodeWarehouseResuming)
+	assert.Equal(t, 1, plugin.resumed)
+
+	// Still resuming: refused again, but not asked to resume twice.
+	w = post(h, api.QueryRequest{Query: "SELECT 1"})
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, 1, plugin.resumed)
+
+	plugin.state = sdk.WarehouseRunning
+	w = post(h, api.QueryRequest{Query: "SELECT 1"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// A datasource that just answered is not checked again.
+	plugin.state = sdk.WarehouseSuspended
+	w = post(h, api.QueryRequest{Query: "SELECT 1"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 1, plugin.resumed)
+}
+
+func TestQueryDatasource_WarehouseCheckIsAdvisory(t *testing.T) {
+	plugin := &warehousePlugin{
+		mockPlugin: &mockPlugin{dbConn: &mockConn{result: &sdk.QueryResult{}}},
+		err:        errors.New("cloud API unreachable"),
+	}
+	h := newHandler(&mockRepo{conn: storedConn()}, plugin)
+	w := post(h, api.QueryRequest{Query: "SELECT 1"})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestQueryDatasource_ConnectFailsWhileSuspended(t *testing.T) {
+	plugin := &warehousePlugin{
+		mockPlugin: &mockPlugin{connectErr: errors.New("dial tcp: i/o timeout")},
+		state:      sdk.WarehouseRunning,
+	}
+	h := newHandler(&mockRepo{conn: storedConn()}, plugin)
+	w := post(h, api.QueryRequest{Query: "SELECT 1"})
+	assert.Equal(t, http.StatusBadGateway, w.Code, "a running warehouse does not explain the failure")
+
+	// The warehouse suspends between the check and the connect.
+	h = newHandler(&mockRepo{conn: storedConn()}, &flakyWarehouse{warehousePlugin: plugin})
+	w = post(h, api.QueryRequest{Query: "SELECT 1"})
+	require.Equal(t, http.StatusServiceUnavailable, w.Code, w.Body.String())
+	assert.Equal(t, "15", w.Header().Get("Retry-After"))
+}
+
+// flakyWarehouse reports running on the first check and suspended after.
+type flakyWarehouse struct {
+	*warehousePlugin
+	checks int
+}
+
+func (p *flakyWarehouse) WarehouseStatus(ctx context.Context, cfg sdk.ConnectionConfig) (*sdk.WarehouseStatus, error) {
+	p.checks++
+	if p.checks > 1 {
+		p.state = sdk.WarehouseSuspended
+	}
+	return p.warehousePlugin.WarehouseStatus(ctx, cfg)
+}
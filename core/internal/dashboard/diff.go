@@ -0,0 +1,189 @@
+package dashboard
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Change kinds of a VariableChange or PanelChange.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// Diff is what changed between two versions of a dashboard.
+type Diff struct {
+	DashboardID string           `json:"dashboard_id"`
+	From        int              `json:"from"`
+	To          int              `json:"to"`
+	Fields      []FieldChange    `json:"fields"`
+	Variables   []VariableChange `json:"variables"`
+	Panels      []PanelChange    `json:"panels"`
+}
+
+// FieldChange is one field with different values in the two versions.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// VariableChange is a variable added, removed or changed, matched by name.
+type VariableChange struct {
+	Name   string    `json:"name"`
+	Change string    `json:"change"`
+	From   *Variable `json:"from,omitempty"`
+	To     *Variable `json:"to,omitempty"`
+}
+
+// PanelChange is a panel added, removed or changed, matched by ID. Its
+// query is compared line by line; an added or removed panel's query is
+// listed in full.
+type PanelChange struct {
+	ID     string        `json:"id"`
+	Title  string        `json:"title"`
+	Change string        `json:"change"`
+	Fields []FieldChange `json:"fields,omitempty"`
+	Query  []LineChange  `json:"query,omitempty"`
+}
+
+// LineChange is one line of a query diff. Op is " " for a line both
+// versions have, "-" for one only the older has and "+" for one only the
+// newer has.
+type LineChange struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// Compare lists what changed from a to b. Unchanged variables and panels are
+// left out.
+func Compare(a, b *Dashboard) *Diff {
+	d := &Diff{Fields: []FieldChange{}, Variables: []VariableChange{}, Panels: []PanelChange{}}
+	d.Fields = appendField(d.Fields, "name", a.Name, b.Name)
+	d.Fields = appendField(d.Fields, "description", a.Description, b.Description)
+	d.Fields = appendField(d.Fields, "time_range", a.TimeRange, b.TimeRange)
+
+	older := make(map[string]*Variable, len(a.Variables))
+	for i := range a.Variables {
+		older[a.Variables[i].Name] = &a.Variables[i]
+	}
+	for i := range b.Variables {
+		v := &b.Variables[i]
+		switch old, ok := older[v.Name]; {
+		case !ok:
+			d.Variables = append(d.Variables, VariableChange{Name: v.Name, Change: ChangeAdded, To: v})
+		case !reflect.DeepEqual(old, v):
+			d.Variables = append(d.Variables, VariableChange{Name: v.Name, Change: ChangeChanged, From: old, To: v})
+		}
+		delete(older, v.Name)
+	}
+	for i := range a.Variables {
+		if v, ok := older[a.Variables[i].Name]; ok {
+			d.Variables = append(d.Variables, VariableChange{Name: v.Name, Change: ChangeRemoved, From: v})
+		}
+	}
+
+	panels := make(map[string]int, len(a.Panels))
+	for i, p := range a.Panels {
+		panels[p.ID] = i
+	}
+	for i, p := range b.Panels {
+		j, ok := panels[p.ID]
+		if !ok {
+			d.Panels = append(d.Panels, PanelChange{ID: p.ID, Title: p.Title, Change: ChangeAdded, Query: diffLines("", p.Query)})
+			continue
+		}
+		delete(panels, p.ID)
+		if c := comparePanels(&a.Panels[j], &p); c != nil {
+			d.Panels = append(d.Panels, *c)
+		} else if i != j {
+			d.Panels = append(d.Panels, PanelChange{ID: p.ID, Title: p.Title, Change: ChangeChanged,
+				Fields: []FieldChange{{Field: "position", From: j, To: i}}})
+		}
+	}
+	for _, p := range a.Panels {
+		if _, ok := panels[p.ID]; ok {
+			d.Panels = append(d.Panels, PanelChange{ID: p.ID, Title: p.Title, Change: ChangeRemoved, Query: diffLines(p.Query, "")})
+		}
+	}
+	return d
+}
+
+// comparePanels returns what changed from a to b, or nil when only their
+// position may have.
+func comparePanels(a, b *Panel) *PanelChange {
+	var fields []FieldChange
+	fields = appendField(fields, "title", a.Title, b.Title)
+	fields = appendField(fields, "datasource_id", a.DatasourceID, b.DatasourceID)
+	fields = appendField(fields, "datasource_alias", a.DatasourceAlias, b.DatasourceAlias)
+	fields = appendField(fields, "limit", a.Limit, b.Limit)
+	fields = appendField(fields, "max_data_points", a.MaxDataPoints, b.MaxDataPoints)
+	fields = appendField(fields, "transforms", a.Transforms, b.Transforms)
+	if len(fields) == 0 && a.Query == b.Query {
+		return nil
+	}
+	c := &PanelChange{ID: b.ID, Title: b.Title, Change: ChangeChanged, Fields: fields}
+	if a.Query != b.Query {
+		c.Query = diffLines(a.Query, b.Query)
+	}
+	return c
+}
+
+func appendField(fields []FieldChange, name string, from, to any) []FieldChange {
+	if reflect.DeepEqual(from, to) {
+		return fields
+	}
+	return append(fields, FieldChange{Field: name, From: from, To: to})
+}
+
+// diffLines compares two texts line by line along their longest common
+// subsequence of lines.
+func diffLines(a, b string) []LineChange {
+	x, y := splitLines(a), splitLines(b)
+	// lcs[i][j] is the length of the longest common subsequence of x[i:]
+	// and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var out []LineChange
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			out = append(out, LineChange{Op: " ", Text: x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, LineChange{Op: "-", Text: x[i]})
+			i++
+		default:
+			out = append(out, LineChange{Op: "+", Text: y[j]})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		out = append(out, LineChange{Op: "-", Text: x[i]})
+	}
+	for ; j < len(y); j++ {
+		out = append(out, LineChange{Op: "+", Text: y[j]})
+	}
+	return out
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
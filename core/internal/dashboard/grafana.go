@@ -0,0 +1,480 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Grafana dashboards are translated to and from the subset Voyager shares
+// with them: SQL panels, custom/textbox/constant variables and the time
+// range. Grafana's $__ time macros run here unchanged; variable references
+// ($var, ${var}, [[var]]) become template expressions and back. Anything
+// else is dropped with a warning so the caller can see what did not carry
+// over.
+
+// grafanaSchemaVersion is the dashboard schema version exports declare.
+const grafanaSchemaVersion = 39
+
+type grafanaDashboard struct {
+	UID           string            `json:"uid,omitempty"`
+	Title         string            `json:"title"`
+	Description   string            `json:"description,omitempty"`
+	Time          *grafanaTime      `json:"time,omitempty"`
+	Templating    grafanaTemplating `json:"templating"`
+	Panels        []grafanaPanel    `json:"panels"`
+	SchemaVersion int               `json:"schemaVersion,omitempty"`
+}
+
+type grafanaTime struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type grafanaTemplating struct {
+	List []grafanaVariable `json:"list"`
+}
+
+type grafanaVariable struct {
+	Name    string          `json:"name"`
+	Label   string          `json:"label,omitempty"`
+	Type    string          `json:"type"`
+	Query   json.RawMessage `json:"query,omitempty"` // a string, or an object for query variables
+	Current *grafanaOption  `json:"current,omitempty"`
+	Options []grafanaOption `json:"options,omitempty"`
+	Multi   bool            `json:"multi,omitempty"`
+}
+
+type grafanaOption struct {
+	Text     any  `json:"text"`
+	Value    any  `json:"value"`
+	Selected bool `json:"selected,omitempty"`
+}
+
+type grafanaPanel struct {
+	ID            int             `json:"id"`
+	Title         string          `json:"title"`
+	Type          string          `json:"type"`
+	Datasource    json.RawMessage `json:"datasource,omitempty"` // {"uid": ...} or, in old dashboards, a name
+	Targets       []grafanaTarget `json:"targets,omitempty"`
+	MaxDataPoints int             `json:"maxDataPoints,omitempty"`
+	GridPos       *grafanaGridPos `json:"gridPos,omitempty"`
+	Panels        []grafanaPanel  `json:"panels,omitempty"` // a collapsed row's panels
+}
+
+type grafanaTarget struct {
+	RefID      string          `json:"refId"`
+	RawSQL     string          `json:"rawSql,omitempty"`
+	RawQuery   bool            `json:"rawQuery,omitempty"`
+	Format     string          `json:"format,omitempty"`
+	EditorMode string          `json:"editorMode,omitempty"`
+	Datasource json.RawMessage `json:"datasource,omitempty"`
+	Hide       bool            `json:"hide,omitempty"`
+}
+
+type grafanaGridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type grafanaDatasourceRef struct {
+	UID  string `json:"uid,omitempty"`
+	Type string `json:"type,omitempty"`
+}
+
+// grafanaTypes maps datasource types to the Grafana plugin that reads them;
+// other types are exported as named.
+var grafanaTypes = map[string]string{
+	"postgresql": "grafana-postgresql-datasource",
+	"mysql":      "mysql",
+	"mssql":      "mssql",
+	"clickhouse": "grafana-clickhouse-datasource",
+}
+
+// grafanaAll is the value Grafana stores for an "All" selection.
+const grafanaAll = "$__all"
+
+var (
+	// grafanaVarRe matches Grafana variable references: ${name[:format]},
+	// [[name[:format]]] and $name.
+	grafanaVarRe = regexp.MustCompile(`\$\{(\w+)(?::[\w-]+)?\}|\[\[(\w+)(?::[\w-]+)?\]\]|\$(\w+)`)
+	// templateVarRe matches the expressions FromGrafana writes.
+	templateVarRe = regexp.MustCompile(`'\{\{ (\w+)\|join:"','" \}\}'|\{\{ (\w+) \}\}`)
+)
+
+// grafanaBuiltins translates Grafana's interval variables; its time macros
+// need no translation.
+var grafanaBuiltins = map[string]string{
+	"__interval":    "__interval_string",
+	"__interval_ms": "__interval_ms",
+}
+
+// parseGrafana decodes a Grafana dashboard, accepting the bare model or the
+// {"dashboard": ...} envelope Grafana's API returns.
+func parseGrafana(raw []byte) (*grafanaDashboard, error) {
+	var envelope struct {
+		Dashboard json.RawMessage `json:"dashboard"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: grafana dashboard: %v", ErrInvalid, err)
+	}
+	if len(envelope.Dashboard) > 0 {
+		raw = envelope.Dashboard
+	}
+	var g grafanaDashboard
+	if err := json.Unmarshal(raw, &g); err != nil {
+		return nil, fmt.Errorf("%w: grafana dashboard: %v", ErrInvalid, err)
+	}
+	return &g, nil
+}
+
+// FromGrafana converts a Grafana dashboard. datasources maps Grafana
+// datasource UIDs (or, for old dashboards, names) to the datasource IDs
+// their panels should query; panels on an unmapped datasource use the
+// default alias. warnings lists what could not be carried over.
+func FromGrafana(raw []byte, datasources map[string]string) (d *Dashboard, warnings []string, err error) {
+	g, err := parseGrafana(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	d = &Dashboard{Name: g.Title, Description: g.Description, Variables: []Variable{}, Panels: []Panel{}}
+	if g.Time != nil {
+		d.TimeRange = TimeRange{From: g.Time.From, To: g.Time.To}
+	}
+
+	multi := map[string]bool{}
+	for _, gv := range g.Templating.List {
+		v, ok := fromGrafanaVariable(gv)
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("variable %q: %s variables are not supported, imported as a textbox", gv.Name, gv.Type))
+		}
+		multi[v.Name] = v.Multi
+		d.Variables = append(d.Variables, v)
+	}
+
+	unmapped := map[string]bool{}
+	var add func(ps []grafanaPanel)
+	add = func(ps []grafanaPanel) {
+		for _, gp := range ps {
+			if gp.Type == "row" {
+				add(gp.Panels)
+				continue
+			}
+			var targets []grafanaTarget
+			for _, t := range gp.Targets {
+				switch {
+				case t.Hide:
+				case t.RawSQL == "":
+					warnings = append(warnings, fmt.Sprintf("panel %q: query %s is not SQL, skipped", gp.Title, t.RefID))
+				default:
+					targets = append(targets, t)
+				}
+			}
+			if len(targets) == 0 {
+				if len(gp.Targets) == 0 {
+					warnings = append(warnings, fmt.Sprintf("panel %q: %s panels have no query, skipped", gp.Title, gp.Type))
+				}
+				continue
+			}
+			for _, t := range targets {
+				p := Panel{
+					Title:         gp.Title,
+					Query:         fromGrafanaQuery(t.RawSQL, multi),
+					MaxDataPoints: gp.MaxDataPoints,
+				}
+				if gp.ID > 0 {
+					p.ID = strconv.Itoa(gp.ID)
+				}
+				if len(targets) > 1 {
+					if p.ID != "" {
+						p.ID += "-" + t.RefID
+					}
+					p.Title = fmt.Sprintf("%s (%s)", gp.Title, t.RefID)
+				}
+				ref := grafanaDatasourceKey(t.Datasource)
+				if ref == "" || strings.HasPrefix(ref, "-- ") {
+					ref = grafanaDatasourceKey(gp.Datasource)
+				}
+				if id, ok := datasources[ref]; ok {
+					p.DatasourceID = id
+				} else if ref != "" && !unmapped[ref] {
+					unmapped[ref] = true
+					warnings = append(warnings, fmt.Sprintf("grafana datasource %q is not mapped, its panels use the default alias", ref))
+				}
+				d.Panels = append(d.Panels, p)
+			}
+		}
+	}
+	add(g.Panels)
+	return d, warnings, nil
+}
+
+// fromGrafanaVariable converts a variable; ok is false when its type has no
+// counterpart and it became a textbox.
+func fromGrafanaVariable(gv grafanaVariable) (v Variable, ok bool) {
+	v = Variable{Name: gv.Name, Label: gv.Label, Multi: gv.Multi}
+	query := grafanaString(gv.Query)
+	switch gv.Type {
+	case "custom":
+		v.Type = VarCustom
+		for _, o := range strings.Split(query, ",") {
+			// "label : value" pairs keep the value.
+			if _, val, found := strings.Cut(o, " : "); found {
+				o = val
+			}
+			if o = strings.TrimSpace(o); o != "" {
+				v.Options = append(v.Options, o)
+			}
+		}
+		if len(v.Options) == 0 {
+			for _, o := range gv.Options {
+				if val, ok := o.Value.(string); ok && val != grafanaAll {
+					v.Options = append(v.Options, val)
+				}
+			}
+		}
+	case "constant":
+		v.Type = VarConstant
+		v.Default = query
+		v.Multi = false
+		return v, true
+	case "textbox":
+		v.Type = VarTextbox
+		v.Default = query
+		v.Multi = false
+	default:
+		v.Type = VarTextbox
+		v.Multi = false
+	}
+	if gv.Current != nil {
+		if def := grafanaCurrent(gv.Current.Value, v.Multi); def != nil {
+			v.Default = def
+		} else if v.Type == VarCustom && v.Multi && grafanaIsAll(gv.Current.Value) {
+			all := make([]any, len(v.Options))
+			for i, o := range v.Options {
+				all[i] = o
+			}
+			v.Default = all
+		}
+	}
+	return v, gv.Type == "custom" || gv.Type == "textbox"
+}
+
+// grafanaCurrent returns a variable's selected value, nil when it has none
+// or is "All".
+func grafanaCurrent(value any, multi bool) any {
+	switch val := value.(type) {
+	case string:
+		if val == "" || val == grafanaAll {
+			return nil
+		}
+		if multi {
+			return []any{val}
+		}
+		return val
+	case []any:
+		if len(val) == 0 || slices.Contains(val, any(grafanaAll)) {
+			return nil
+		}
+		if !multi {
+			return val[0]
+		}
+		return val
+	}
+	return nil
+}
+
+// grafanaIsAll reports whether a selection is Grafana's "All".
+func grafanaIsAll(value any) bool {
+	switch val := value.(type) {
+	case string:
+		return val == grafanaAll
+	case []any:
+		return slices.Contains(val, any(grafanaAll))
+	}
+	return false
+}
+
+// fromGrafanaQuery rewrites Grafana variable references as template
+// expressions. Multi-value variables expand, as Grafana's SQL datasources
+// do, to a quoted comma-separated list. References to unknown names are
+// left alone.
+func fromGrafanaQuery(sql string, multi map[string]bool) string {
+	return grafanaVarRe.ReplaceAllStringFunc(sql, func(m string) string {
+		sub := grafanaVarRe.FindStringSubmatch(m)
+		name := sub[1] + sub[2] + sub[3]
+		if builtin, ok := grafanaBuiltins[name]; ok {
+			return "{{ " + builtin + " }}"
+		}
+		isMulti, known := multi[name]
+		switch {
+		case !known:
+			return m
+		case isMulti:
+			return `'{{ ` + name + `|join:"','" }}'`
+		default:
+			return "{{ " + name + " }}"
+		}
+	})
+}
+
+// grafanaDatasourceKey returns the UID or name a datasource reference uses.
+func grafanaDatasourceKey(raw json.RawMessage) string {
+	if s := grafanaString(raw); s != "" {
+		return s
+	}
+	var ref grafanaDatasourceRef
+	if json.Unmarshal(raw, &ref) == nil {
+		return ref.UID
+	}
+	return ""
+}
+
+// grafanaString decodes raw as a JSON string, "" if it is not one.
+func grafanaString(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) != nil {
+		return ""
+	}
+	return s
+}
+
+// ToGrafana converts d to a Grafana dashboard. datasourceType returns the
+// type of a datasource ID, "" when unknown. Panels are laid out two to a
+// row; panels grouping by time become time series, the rest tables.
+func ToGrafana(d *Dashboard, datasourceType func(id string) string) ([]byte, error) {
+	g := grafanaDashboard{
+		UID:           d.ID,
+		Title:         d.Name,
+		Description:   d.Description,
+		Time:          &grafanaTime{From: d.TimeRange.From, To: d.TimeRange.To},
+		Templating:    grafanaTemplating{List: []grafanaVariable{}},
+		Panels:        []grafanaPanel{},
+		SchemaVersion: grafanaSchemaVersion,
+	}
+	for _, v := range d.Variables {
+		g.Templating.List = append(g.Templating.List, toGrafanaVariable(v))
+	}
+	for i, p := range d.Panels {
+		ref := grafanaDatasourceRef{UID: p.DatasourceID}
+		if p.DatasourceID == "" {
+			ref.UID = p.DatasourceAlias
+			if ref.UID == "" {
+				ref.UID = "default"
+			}
+		} else if t := datasourceType(p.DatasourceID); t != "" {
+			ref.Type = t
+			if gt, ok := grafanaTypes[t]; ok {
+				ref.Type = gt
+			}
+		}
+		dsRaw, err := json.Marshal(ref)
+		if err != nil {
+			return nil, err
+		}
+		panelType, format := "table", "table"
+		if strings.Contains(p.Query, "$__timeGroup") {
+			panelType, format = "timeseries", "time_series"
+		}
+		g.Panels = append(g.Panels, grafanaPanel{
+			ID:            i + 1,
+			Title:         p.Title,
+			Type:          panelType,
+			Datasource:    dsRaw,
+			MaxDataPoints: p.MaxDataPoints,
+			GridPos:       &grafanaGridPos{H: 8, W: 12, X: (i % 2) * 12, Y: (i / 2) * 8},
+			Targets: []grafanaTarget{{
+				RefID:      "A",
+				RawSQL:     toGrafanaQuery(p.Query),
+				RawQuery:   true,
+				Format:     format,
+				EditorMode: "code",
+				Datasource: dsRaw,
+			}},
+		})
+	}
+	return json.MarshalIndent(g, "", "  ")
+}
+
+func toGrafanaVariable(v Variable) grafanaVariable {
+	gv := grafanaVariable{Name: v.Name, Label: v.Label, Type: v.Type, Multi: v.Multi}
+	var query string
+	switch v.Type {
+	case VarCustom:
+		query = strings.Join(v.Options, ",")
+		for _, o := range v.Options {
+			gv.Options = append(gv.Options, grafanaOption{Text: o, Value: o, Selected: isDefault(v.Default, o)})
+		}
+	default:
+		if s, ok := v.Default.(string); ok {
+			query = s
+		}
+	}
+	gv.Query, _ = json.Marshal(query)
+	if v.Default != nil {
+		gv.Current = &grafanaOption{Text: v.Default, Value: v.Default}
+	}
+	return gv
+}
+
+// isDefault reports whether option is, or is among, a variable's default.
+func isDefault(def any, option string) bool {
+	if list, ok := def.([]any); ok {
+		return slices.Contains(list, any(option))
+	}
+	return def == option
+}
+
+// toGrafanaQuery is the inverse of fromGrafanaQuery for the expressions it
+// writes; other template syntax is kept as is.
+func toGrafanaQuery(query string) string {
+	return templateVarRe.ReplaceAllStringFunc(query, func(m string) string {
+		sub := templateVarRe.FindStringSubmatch(m)
+		name := sub[1] + sub[2]
+		for gname, builtin := range grafanaBuiltins {
+			if builtin == name {
+				return "$" + gname
+			}
+		}
+		return "${" + name + "}"
+	})
+}
+
+// ImportGrafana converts a Grafana dashboard and saves it as a new
+// dashboard. Every datasource ID in datasources must exist.
+func (s *Service) ImportGrafana(ctx context.Context, raw []byte, datasources map[string]string) (*Dashboard, []string, error) {
+	for ref, id := range datasources {
+		if _, err := s.conns.GetByID(ctx, id); err != nil {
+			return nil, nil, fmt.Errorf("%w: grafana datasource %q maps to unknown datasource %s", ErrInvalid, ref, id)
+		}
+	}
+	d, warnings, err := FromGrafana(raw, datasources)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.Create(ctx, d); err != nil {
+		return nil, nil, err
+	}
+	return d, warnings, nil
+}
+
+// ExportGrafana returns a dashboard as Grafana dashboard JSON.
+func (s *Service) ExportGrafana(ctx context.Context, id string) ([]byte, error) {
+	d, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	return ToGrafana(d, func(dsID string) string {
+		conn, err := s.conns.GetByID(ctx, dsID)
+		if err != nil {
+			return ""
+		}
+		return string(conn.Type)
+	})
+}
@@ -0,0 +1,89 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const grafanaSample = `{
+  "dashboard": {
+    "uid": "abc",
+    "title": "Sales",
+    "time": {"from": "now-24h", "to": "now"},
+    "templating": {"list": [
+      {"name": "region", "type": "custom", "query": "eu,us,apac", "multi": true,
+       "current": {"text": ["eu", "us"], "value": ["eu", "us"]}},
+      {"name": "tenant", "type": "constant", "query": "acme"},
+      {"name": "host", "type": "query", "query": {"rawSql": "SELECT host FROM hosts"}, "current": {"value": "h1"}}
+    ]},
+    "panels": [
+      {"id": 1, "title": "Orders", "type": "timeseries", "datasource": {"type": "grafana-postgresql-datasource", "uid": "pg"},
+       "targets": [{"refId": "A", "rawSql": "SELECT $__timeGroup(ts, $__interval), count(*) FROM orders WHERE region IN ($region) AND tenant = '${tenant}' AND $__timeFilter(ts) GROUP BY 1"}]},
+      {"id": 2, "type": "row", "title": "More", "panels": [
+        {"id": 3, "title": "Hosts", "type": "table", "datasource": "legacy",
+         "targets": [{"refId": "A", "rawSql": "SELECT * FROM hosts WHERE host = '[[host]]'"}, {"refId": "B", "rawSql": "SELECT 1"}]}
+      ]},
+      {"id": 4, "title": "Notes", "type": "text"},
+      {"id": 5, "title": "Metrics", "type": "timeseries", "datasource": {"uid": "prom"}, "targets": [{"refId": "A", "expr": "up"}]}
+    ]
+  }
+}`
+
+func TestFromGrafana(t *testing.T) {
+	d, warnings, err := FromGrafana([]byte(grafanaSample), map[string]string{"pg": "ds1"})
+	require.NoError(t, err)
+	require.NoError(t, validate(d))
+
+	assert.Equal(t, "Sales", d.Name)
+	assert.Equal(t, TimeRange{From: "now-24h", To: "now"}, d.TimeRange)
+	assert.Equal(t, []Variable{
+		{Name: "region", Type: VarCustom, Options: []string{"eu", "us", "apac"}, Multi: true, Default: []any{"eu", "us"}},
+		{Name: "tenant", Type: VarConstant, Default: "acme"},
+		{Name: "host", Type: VarTextbox, Default: "h1"},
+	}, d.Variables)
+
+	require.Len(t, d.Panels, 3)
+	assert.Equal(t, "1", d.Panels[0].ID)
+	assert.Equal(t, "ds1", d.Panels[0].DatasourceID)
+	assert.Equal(t, `SELECT $__timeGroup(ts, {{ __interval_string }}), count(*) FROM orders WHERE region IN ('{{ region|join:"','" }}') AND tenant = '{{ tenant }}' AND $__timeFilter(ts) GROUP BY 1`, d.Panels[0].Query)
+	assert.Equal(t, "3-A", d.Panels[1].ID)
+	assert.Equal(t, "Hosts (A)", d.Panels[1].Title)
+	assert.Equal(t, "SELECT * FROM hosts WHERE host = '{{ host }}'", d.Panels[1].Query)
+	assert.Empty(t, d.Panels[1].DatasourceID)
+	assert.Equal(t, "3-B", d.Panels[2].ID)
+
+	assert.Equal(t, []string{
+		`variable "host": query variables are not supported, imported as a textbox`,
+		`grafana datasource "legacy" is not mapped, its panels use the default alias`,
+		`panel "Notes": text panels have no query, skipped`,
+		`panel "Metrics": query A is not SQL, skipped`,
+	}, warnings)
+}
+
+func TestToGrafana_RoundTrip(t *testing.T) {
+	d, _, err := FromGrafana([]byte(grafanaSample), map[string]string{"pg": "ds1"})
+	require.NoError(t, err)
+	d.ID = "d1"
+
+	out, err := ToGrafana(d, func(id string) string { return "postgresql" })
+	require.NoError(t, err)
+	var g grafanaDashboard
+	require.NoError(t, json.Unmarshal(out, &g))
+	assert.Equal(t, "d1", g.UID)
+	require.Len(t, g.Panels, 3)
+	assert.Equal(t, "timeseries", g.Panels[0].Type)
+	assert.JSONEq(t, `{"uid": "ds1", "type": "grafana-postgresql-datasource"}`, string(g.Panels[0].Datasource))
+	assert.Equal(t, "SELECT $__timeGroup(ts, $__interval), count(*) FROM orders WHERE region IN (${region}) AND tenant = '${tenant}' AND $__timeFilter(ts) GROUP BY 1",
+		g.Panels[0].Targets[0].RawSQL)
+	assert.JSONEq(t, `{"uid": "default"}`, string(g.Panels[1].Datasource))
+	assert.Equal(t, "table", g.Panels[1].Type)
+	assert.Equal(t, &grafanaGridPos{H: 8, W: 12, X: 12, Y: 0}, g.Panels[1].GridPos)
+
+	back, _, err := FromGrafana(out, map[string]string{"ds1": "ds1"})
+	require.NoError(t, err)
+	assert.Equal(t, d.Variables, back.Variables)
+	assert.Equal(t, d.Panels[0].Query, back.Panels[0].Query)
+}
@@ -0,0 +1,432 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/render"
+)
+
+// Handler serves the dashboard endpoints.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates a dashboard HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// ─── wire types ───────────────────────────────────────────────────────────────
+
+type dashboardRequest struct {
+	Name        string     `json:"name" binding:"required"`
+	Description string     `json:"description"`
+	Variables   []Variable `json:"variables"`
+	Panels      []Panel    `json:"panels"`
+	TimeRange   TimeRange  `json:"time_range"`
+}
+
+func (r dashboardRequest) apply(d *Dashboard) {
+	d.Name, d.Description = r.Name, r.Description
+	d.Variables, d.Panels, d.TimeRange = r.Variables, r.Panels, r.TimeRange
+	if d.Variables == nil {
+		d.Variables = []Variable{}
+	}
+	if d.Panels == nil {
+		d.Panels = []Panel{}
+	}
+}
+
+type grafanaImportRequest struct {
+	// Dashboard is the Grafana dashboard JSON, bare or as Grafana's API
+	// returns it.
+	Dashboard json.RawMessage `json:"dashboard" binding:"required"`
+	// Datasources maps Grafana datasource UIDs to datasource IDs.
+	Datasources map[string]string `json:"datasources"`
+}
+
+type dataRequest struct {
+	TimeRange   *TimeRange     `json:"time_range"`
+	Variables   map[string]any `json:"variables"`
+	Panels      []string       `json:"panels"`
+	Environment string         `json:"environment"`
+}
+
+func (r dataRequest) toService() DataRequest {
+	return DataRequest{
+		TimeRange:   r.TimeRange,
+		Variables:   r.Variables,
+		PanelIDs:    r.Panels,
+		Environment: r.Environment,
+	}
+}
+
+type renderRequest struct {
+	dataRequest
+	Format string `json:"format" binding:"required"`
+}
+
+type snapshotRequest struct {
+	dataRequest
+	Name  string `json:"name"`
+	Label string `json:"label"`
+}
+
+// ─── handlers ─────────────────────────────────────────────────────────────────
+
+// List handles GET /dashboards
+func (h *Handler) List(c *gin.Context) {
+	list, err := h.svc.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// Get handles GET /dashboards/:id
+func (h *Handler) Get(c *gin.Context) {
+	d, err := h.svc.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "dashboard not found")})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": d})
+}
+
+// Create handles POST /dashboards
+func (h *Handler) Create(c *gin.Context) {
+	if !requireWrite(c) {
+		return
+	}
+	var req dashboardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	d := &Dashboard{}
+	req.apply(d)
+	if err := h.svc.Create(c.Request.Context(), d); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": d})
+}
+
+// Update handles PUT /dashboards/:id
+func (h *Handler) Update(c *gin.Context) {
+	if !requireWrite(c) {
+		return
+	}
+	d, err := h.svc.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "dashboard not found")})
+		return
+	}
+	var req dashboardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.apply(d)
+	if err := h.svc.Update(c.Request.Context(), d); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": d})
+}
+
+// Delete handles DELETE /dashboards/:id
+func (h *Handler) Delete(c *gin.Context) {
+	if !requireWrite(c) {
+		return
+	}
+	err := h.svc.Delete(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "dashboard not found")})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Data handles POST /dashboards/:id/data
+func (h *Handler) Data(c *gin.Context) {
+	var req dataRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	result, err := h.svc.Data(c.Request.Context(), c.Param("id"), req.toService())
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "dashboard not found")})
+		return
+	}
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// Render handles POST /dashboards/:id/render
+func (h *Handler) Render(c *gin.Context) {
+	var req renderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	out, err := h.svc.Render(c.Request.Context(), c.Param("id"), req.Format, req.toService())
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "dashboard not found")})
+		return
+	}
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", "dashboard-"+c.Param("id")+"."+req.Format))
+	c.Data(http.StatusOK, render.ContentType(req.Format), out)
+}
+
+// ImportGrafana handles POST /dashboards/grafana
+func (h *Handler) ImportGrafana(c *gin.Context) {
+	if !requireWrite(c) {
+		return
+	}
+	var req grafanaImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	d, warnings, err := h.svc.ImportGrafana(c.Request.Context(), req.Dashboard, req.Datasources)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if warnings == nil {
+		warnings = []string{}
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": d, "warnings": warnings})
+}
+
+// ExportGrafana handles GET /dashboards/:id/grafana
+func (h *Handler) ExportGrafana(c *gin.Context) {
+	out, err := h.svc.ExportGrafana(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "dashboard not found")})
+		return
+	}
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "dashboard-"+c.Param("id")+".json"))
+	c.Data(http.StatusOK, "application/json", out)
+}
+
+// TakeSnapshot handles POST /dashboards/:id/snapshot
+func (h *Handler) TakeSnapshot(c *gin.Context) {
+	if !requireWrite(c) {
+		return
+	}
+	var req snapshotRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	snap, err := h.svc.TakeSnapshot(c.Request.Context(), c.Param("id"), req.Name, req.Label, req.toService())
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "dashboard not found")})
+		return
+	}
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": snap})
+}
+
+// ListSnapshots handles GET /dashboards/:id/snapshots
+func (h *Handler) ListSnapshots(c *gin.Context) {
+	list, err := h.svc.Snapshots(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// ListVersions handles GET /dashboards/:id/versions
+func (h *Handler) ListVersions(c *gin.Context) {
+	list, err := h.svc.Versions(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "dashboard not found")})
+		return
+	}
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// GetVersion handles GET /dashboards/:id/versions/:version
+func (h *Handler) GetVersion(c *gin.Context) {
+	n, err := strconv.Atoi(c.Param("version"))
+	if err != nil || n < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "version must be a positive integer")})
+		return
+	}
+	v, err := h.svc.Version(c.Request.Context(), c.Param("id"), n)
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "dashboard version not found")})
+		return
+	}
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": v})
+}
+
+// Diff handles GET /dashboards/:id/diff?from=&to=
+//
+// to defaults to the latest version and from to the one before to.
+func (h *Handler) Diff(c *gin.Context) {
+	var versions [2]int
+	for i, name := range []string{"from", "to"} {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "version must be a positive integer")})
+			return
+		}
+		versions[i] = n
+	}
+	d, err := h.svc.DiffVersions(c.Request.Context(), c.Param("id"), versions[0], versions[1])
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "dashboard version not found")})
+		return
+	}
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": d})
+}
+
+// GetSnapshot handles GET /snapshots/:id
+func (h *Handler) GetSnapshot(c *gin.Context) {
+	snap, err := h.svc.GetSnapshot(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "snapshot not found")})
+		return
+	}
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": snap})
+}
+
+// RenderSnapshot handles GET /snapshots/:id/render?format=
+func (h *Handler) RenderSnapshot(c *gin.Context) {
+	format := c.DefaultQuery("format", render.FormatHTML)
+	out, err := h.svc.RenderSnapshot(c.Request.Context(), c.Param("id"), format)
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "snapshot not found")})
+		return
+	}
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", "snapshot-"+c.Param("id")+"."+format))
+	c.Data(http.StatusOK, render.ContentType(format), out)
+}
+
+// SnapshotLabels handles GET /snapshot-labels
+func (h *Handler) SnapshotLabels(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.svc.Labels()})
+}
+
+// DeleteSnapshot handles DELETE /snapshots/:id
+func (h *Handler) DeleteSnapshot(c *gin.Context) {
+	if !requireWrite(c) {
+		return
+	}
+	err := h.svc.DeleteSnapshot(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "snapshot not found")})
+		return
+	}
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// requireWrite answers 403 and reports false unless the caller may change
+// dashboards.
+func requireWrite(c *gin.Context) bool {
+	if identity.FromContext(c.Request.Context()).Can(identity.PermDashboardWrite) {
+		return true
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "permission denied")})
+	return false
+}
+
+func errorStatus(err error) int {
+	if errors.Is(err, ErrInvalid) || errors.Is(err, render.ErrInvalid) {
+		return http.StatusBadRequest
+	}
+	if errors.Is(err, ErrSnapshotsDisabled) || errors.Is(err, ErrVersionsDisabled) || errors.Is(err, render.ErrUnavailable) {
+		return http.StatusNotImplemented
+	}
+	if errors.Is(err, ErrRestricted) {
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// RegisterRoutes wires the handler onto r.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/dashboards", h.List)
+	r.POST("/dashboards", h.Create)
+	r.GET("/dashboards/:id", h.Get)
+	r.PUT("/dashboards/:id", h.Update)
+	r.DELETE("/dashboards/:id", h.Delete)
+	r.POST("/dashboards/:id/data", h.Data)
+	r.POST("/dashboards/:id/render", h.Render)
+	r.POST("/dashboards/grafana", h.ImportGrafana)
+	r.GET("/dashboards/:id/grafana", h.ExportGrafana)
+	r.POST("/dashboards/:id/snapshot", h.TakeSnapshot)
+	r.GET("/dashboards/:id/snapshots", h.ListSnapshots)
+	r.GET("/dashboards/:id/versions", h.ListVersions)
+	r.GET("/dashboards/:id/versions/:version", h.GetVersion)
+	r.GET("/dashboards/:id/diff", h.Diff)
+	r.GET("/snapshots/:id", h.GetSnapshot)
+	r.GET("/snapshots/:id/render", h.RenderSnapshot)
+	r.DELETE("/snapshots/:id", h.DeleteSnapshot)
+	r.GET("/snapshot-labels", h.SnapshotLabels)
+}
@@ -0,0 +1,140 @@
+// Package dashboard stores dashboards — panels of queries that share a time
+// range and a set of variables — and executes their panels together.
+package dashboard
+
+import (
+	"context"
+	"time"
+
+	"data-voyager/core/internal/transform"
+)
+
+// Variable types.
+const (
+	// VarCustom takes one of Options (or several when Multi is set).
+	VarCustom = "custom"
+	// VarTextbox takes any string.
+	VarTextbox = "textbox"
+	// VarConstant always has its Default; callers cannot override it.
+	VarConstant = "constant"
+)
+
+// Dashboard groups panels that are rendered against one shared variable
+// context.
+type Dashboard struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Variables   []Variable `json:"variables"`
+	Panels      []Panel    `json:"panels"`
+	// TimeRange is the default range, in the relative syntax the query
+	// endpoint accepts ("now-6h").
+	TimeRange TimeRange `json:"time_range"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TimeRange is a from/to pair of absolute or relative time expressions.
+type TimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Variable is a dashboard-level template variable, available to every panel
+// query under its Name.
+type Variable struct {
+	Name    string   `json:"name"`
+	Label   string   `json:"label,omitempty"`
+	Type    string   `json:"type"` // custom | textbox | constant
+	Options []string `json:"options,omitempty"`
+	Default any      `json:"default,omitempty"`
+	Multi   bool     `json:"multi,omitempty"`
+}
+
+// Panel is one query on a dashboard.
+type Panel struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	// DatasourceID names the datasource the panel queries. Alternatively
+	// DatasourceAlias names it by alias, resolved in the environment the
+	// dashboard runs in, so one dashboard serves dev and prod alike. With
+	// neither set the panel uses the "default" alias.
+	DatasourceID    string `json:"datasource_id,omitempty"`
+	DatasourceAlias string `json:"datasource_alias,omitempty"`
+	Query           string `json:"query"`
+	// Limit overrides the {{ __limit }} value for this panel.
+	Limit int `json:"limit,omitempty"`
+	// MaxDataPoints is the point budget $__timeGroup buckets the time range
+	// into; 0 uses the query builder's default.
+	MaxDataPoints int `json:"max_data_points,omitempty"`
+	// Transforms reshape the panel's result, e.g. pivoting a category into
+	// one series per value.
+	Transforms []transform.Spec `json:"transforms,omitempty"`
+}
+
+// Repository persists dashboards.
+// Implemented per-driver in store/{postgres,sqlite,mysql}/.
+type Repository interface {
+	List(ctx context.Context) ([]*Dashboard, error)
+	GetByID(ctx context.Context, id string) (*Dashboard, error)
+	Create(ctx context.Context, d *Dashboard) error
+	Update(ctx context.Context, d *Dashboard) error
+	Delete(ctx context.Context, id string) error
+}
+
+// Definition is the part of a Dashboard stores keep as one JSON document.
+type Definition struct {
+	Variables []Variable `json:"variables"`
+	Panels    []Panel    `json:"panels"`
+	TimeRange TimeRange  `json:"time_range"`
+}
+
+// Definition returns d's stored JSON document.
+func (d *Dashboard) Definition() Definition {
+	return Definition{Variables: d.Variables, Panels: d.Panels, TimeRange: d.TimeRange}
+}
+
+// SetDefinition copies a stored JSON document into d.
+func (d *Dashboard) SetDefinition(def Definition) {
+	d.Variables, d.Panels, d.TimeRange = def.Variables, def.Panels, def.TimeRange
+}
+
+// Snapshot is a dashboard frozen together with the data its panels
+// returned, so it can be viewed later without querying any datasource.
+// Snapshots outlive their dashboard.
+type Snapshot struct {
+	ID          string    `json:"id"`
+	DashboardID string    `json:"dashboard_id"`
+	Name        string    `json:"name"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	// Label is the snapshot's sensitivity class, one of retention.labels;
+	// empty when it has none.
+	Label string `json:"label,omitempty"`
+	// ExpiresAt is when the label's retention deletes the snapshot; nil
+	// keeps it until it is deleted by hand.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// Content is left out of lists.
+	Content *SnapshotContent `json:"content,omitempty"`
+}
+
+// SnapshotContent is what a snapshot froze: the dashboard as it was and
+// its rendered data.
+type SnapshotContent struct {
+	Dashboard Dashboard  `json:"dashboard"`
+	Result    DataResult `json:"result"`
+}
+
+// SnapshotRepository persists snapshots.
+// Implemented per-driver in store/{postgres,sqlite,mysql}/.
+type SnapshotRepository interface {
+	// List returns the snapshots of a dashboard, newest first and without
+	// their content.
+	List(ctx context.Context, dashboardID string) ([]*Snapshot, error)
+	GetByID(ctx context.Context, id string) (*Snapshot, error)
+	Create(ctx context.Context, s *Snapshot) error
+	Delete(ctx context.Context, id string) error
+	// DeleteExpired removes snapshots that expired before now and returns
+	// how many it removed.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,58 @@
+package dashboard
+
+import (
+	"context"
+
+	"data-voyager/core/internal/connection"
+)
+
+// referenceSource reports dashboard panels that query a datasource.
+type referenceSource struct {
+	repo Repository
+}
+
+// NewReferenceSource returns the connection.ReferenceSource for dashboards.
+// A forced datasource delete removes the panels that use it; the dashboards
+// themselves are kept.
+func NewReferenceSource(repo Repository) connection.ReferenceSource {
+	return &referenceSource{repo: repo}
+}
+
+func (s *referenceSource) References(ctx context.Context, datasourceID string) ([]connection.Reference, error) {
+	all, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var refs []connection.Reference
+	for _, d := range all {
+		for _, p := range d.Panels {
+			if p.DatasourceID == datasourceID {
+				refs = append(refs, connection.Reference{Kind: "dashboard", ID: d.ID, Name: d.Name + " / " + p.Title})
+			}
+		}
+	}
+	return refs, nil
+}
+
+func (s *referenceSource) DeleteReferences(ctx context.Context, datasourceID string) error {
+	all, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, d := range all {
+		kept := d.Panels[:0]
+		for _, p := range d.Panels {
+			if p.DatasourceID != datasourceID {
+				kept = append(kept, p)
+			}
+		}
+		if len(kept) == len(d.Panels) {
+			continue
+		}
+		d.Panels = kept
+		if err := s.repo.Update(ctx, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
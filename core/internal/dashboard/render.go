@@ -0,0 +1,63 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"data-voyager/core/internal/render"
+)
+
+// WithRenderer sets how dashboards are rendered to HTML, PNG and PDF.
+func (s *Service) WithRenderer(r *render.Renderer) *Service {
+	s.renderer = r
+	return s
+}
+
+// Render runs the dashboard as Data does and renders the result in format.
+func (s *Service) Render(ctx context.Context, id, format string, req DataRequest) ([]byte, error) {
+	d, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	result, err := s.Data(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.renderer.Render(ctx, Document(d, result, time.Now()), format)
+}
+
+// RenderSnapshot renders a snapshot in format, as it was when taken.
+func (s *Service) RenderSnapshot(ctx context.Context, id, format string) ([]byte, error) {
+	snap, err := s.GetSnapshot(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.exportable(snap); err != nil {
+		return nil, err
+	}
+	doc := Document(&snap.Content.Dashboard, &snap.Content.Result, snap.CreatedAt)
+	doc.Title = snap.Name
+	return s.renderer.Render(ctx, doc, format)
+}
+
+// Document lays out a dashboard's data for rendering, one panel per
+// result, titled from d's panels.
+func Document(d *Dashboard, result *DataResult, at time.Time) *render.Document {
+	titles := make(map[string]string, len(d.Panels))
+	for _, p := range d.Panels {
+		titles[p.ID] = p.Title
+	}
+	doc := &render.Document{Title: d.Name, At: at}
+	if tr := result.TimeRange; tr.From != "" || tr.To != "" {
+		doc.Subtitle = tr.From + " – " + tr.To
+	}
+	for _, p := range result.Panels {
+		title := titles[p.PanelID]
+		if title == "" {
+			title = p.PanelID
+		}
+		doc.Panels = append(doc.Panels, render.Panel{Title: title, Result: p.Data, Error: p.Error})
+	}
+	return doc
+}
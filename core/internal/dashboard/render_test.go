@@ -0,0 +1,32 @@
+package dashboard
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/render"
+)
+
+func TestRender(t *testing.T) {
+	d := salesDashboard()
+	d.Name = "Sales"
+	d.Panels[0].Title = "By region"
+	svc, _ := newTestService(d)
+
+	out, err := svc.Render(context.Background(), "d1", render.FormatHTML, DataRequest{})
+	require.NoError(t, err)
+	html := string(out)
+	assert.Contains(t, html, "<h1>Sales</h1>")
+	assert.Contains(t, html, "2024-01-01T00:00:00Z – 2024-01-02T00:00:00Z")
+	assert.Contains(t, html, "<h2>By region</h2>")
+	assert.Contains(t, html, "<h2>b</h2>", "untitled panels fall back to their ID")
+	assert.Contains(t, html, `class="error"`, "failed panels show their error")
+
+	_, err = svc.Render(context.Background(), "d1", render.FormatPDF, DataRequest{})
+	assert.ErrorIs(t, err, render.ErrUnavailable)
+	_, err = svc.Render(context.Background(), "missing", render.FormatHTML, DataRequest{})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,480 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"data-voyager/core/internal/authz"
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/identity"
+	qb "data-voyager/core/internal/query_builder"
+	"data-voyager/core/internal/render"
+	"data-voyager/core/internal/transform"
+	"data-voyager/sdk"
+)
+
+var (
+	// ErrInvalid wraps validation failures so handlers can map them to 400.
+	ErrInvalid = errors.New("invalid dashboard")
+	// ErrNotFound is returned by Data for an unknown dashboard.
+	ErrNotFound = errors.New("dashboard not found")
+)
+
+const (
+	defaultLimit = 1000
+	// maxParallelDatasources bounds how many datasources one data request
+	// queries at once; panels on the same datasource share a connection and
+	// run one after another.
+	maxParallelDatasources = 4
+)
+
+// Service manages dashboards and runs their panels.
+type Service struct {
+	repo     Repository
+	conns    connection.Repository
+	registry *datasource.Registry
+	env      string // environment datasource aliases resolve in by default
+
+	snapshots    SnapshotRepository               // see WithSnapshots
+	renderer     *render.Renderer                 // see WithRenderer
+	versions     VersionRepository                // see WithVersions
+	labels       map[string]config.RetentionLabel // see WithLabels
+	defaultLabel string
+	literals     *connection.LiteralPolicy // see WithLiteralPolicy
+}
+
+// NewService creates a Service.
+func NewService(repo Repository, conns connection.Repository, registry *datasource.Registry) *Service {
+	return &Service{repo: repo, conns: conns, registry: registry}
+}
+
+// WithEnvironment sets the environment panels' datasource aliases resolve
+// in when a request does not name one.
+func (s *Service) WithEnvironment(env string) *Service {
+	s.env = env
+	return s
+}
+
+// WithLiteralPolicy holds panel queries to the parameter policy p when they
+// are saved and when they run.
+func (s *Service) WithLiteralPolicy(p *connection.LiteralPolicy) *Service {
+	s.literals = p
+	return s
+}
+
+// List returns the dashboards visible to ctx's identity.
+func (s *Service) List(ctx context.Context) ([]*Dashboard, error) {
+	list, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id := identity.FromContext(ctx)
+	out := list[:0]
+	for _, d := range list {
+		if id.CanSeeDashboard(d.ID) {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+// Get returns a dashboard, treating one hidden from ctx's identity as
+// missing.
+func (s *Service) Get(ctx context.Context, id string) (*Dashboard, error) {
+	if !identity.FromContext(ctx).CanSeeDashboard(id) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *Service) Create(ctx context.Context, d *Dashboard) error {
+	if err := s.validate(ctx, d); err != nil {
+		return err
+	}
+	d.ID = uuid.NewString()
+	if err := s.repo.Create(ctx, d); err != nil {
+		return err
+	}
+	s.record(ctx, d)
+	return nil
+}
+
+func (s *Service) Update(ctx context.Context, d *Dashboard) error {
+	if !identity.FromContext(ctx).CanSeeDashboard(d.ID) {
+		return fmt.Errorf("%w: %s", ErrNotFound, d.ID)
+	}
+	if err := s.validate(ctx, d); err != nil {
+		return err
+	}
+	s.recordBaseline(ctx, d.ID)
+	if err := s.repo.Update(ctx, d); err != nil {
+		return err
+	}
+	s.record(ctx, d)
+	return nil
+}
+
+// Put creates or replaces the dashboard with d's ID, for imports that carry
+// their own IDs. created reports whether it was new.
+func (s *Service) Put(ctx context.Context, d *Dashboard) (created bool, err error) {
+	if d.ID == "" {
+		return false, fmt.Errorf("%w: id is required", ErrInvalid)
+	}
+	if !identity.FromContext(ctx).CanSeeDashboard(d.ID) {
+		return false, fmt.Errorf("%w: %s", ErrNotFound, d.ID)
+	}
+	if _, err := s.repo.GetByID(ctx, d.ID); err != nil {
+		if err := s.validate(ctx, d); err != nil {
+			return false, err
+		}
+		if err := s.repo.Create(ctx, d); err != nil {
+			return true, err
+		}
+		s.record(ctx, d)
+		return true, nil
+	}
+	return false, s.Update(ctx, d)
+}
+
+// Delete removes a dashboard and its versions; its snapshots are kept.
+func (s *Service) Delete(ctx context.Context, id string) error {
+	if !identity.FromContext(ctx).CanSeeDashboard(id) {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if s.versions != nil {
+		return s.versions.DeleteAll(ctx, id)
+	}
+	return nil
+}
+
+// validate is validate plus the parameter policy on every panel's query.
+func (s *Service) validate(ctx context.Context, d *Dashboard) error {
+	if err := validate(d); err != nil {
+		return err
+	}
+	for _, p := range d.Panels {
+		conn := &connection.Connection{ID: p.DatasourceID, Name: p.DatasourceAlias}
+		if p.DatasourceID != "" {
+			if c, err := s.conns.GetByID(ctx, p.DatasourceID); err == nil {
+				conn = c
+			}
+		}
+		if err := s.literals.Enforce(ctx, conn, p.Query); err != nil {
+			return fmt.Errorf("%w: panel %q: %w", ErrInvalid, p.ID, err)
+		}
+	}
+	return nil
+}
+
+func validate(d *Dashboard) error {
+	if d.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalid)
+	}
+	if d.TimeRange.From == "" && d.TimeRange.To == "" {
+		d.TimeRange = TimeRange{From: "now-6h", To: "now"}
+	}
+	seen := map[string]bool{}
+	for i, v := range d.Variables {
+		if v.Name == "" || len(v.Name) >= 2 && v.Name[:2] == "__" {
+			return fmt.Errorf("%w: variable %d needs a name not starting with __", ErrInvalid, i)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("%w: duplicate variable %q", ErrInvalid, v.Name)
+		}
+		seen[v.Name] = true
+		switch v.Type {
+		case VarCustom:
+			if len(v.Options) == 0 {
+				return fmt.Errorf("%w: custom variable %q needs options", ErrInvalid, v.Name)
+			}
+		case VarTextbox, VarConstant:
+		default:
+			return fmt.Errorf("%w: variable %q has unknown type %q", ErrInvalid, v.Name, v.Type)
+		}
+	}
+	panels := map[string]bool{}
+	for i := range d.Panels {
+		p := &d.Panels[i]
+		if p.ID == "" {
+			p.ID = uuid.NewString()
+		}
+		if panels[p.ID] {
+			return fmt.Errorf("%w: duplicate panel id %q", ErrInvalid, p.ID)
+		}
+		panels[p.ID] = true
+		if p.Query == "" {
+			return fmt.Errorf("%w: panel %q needs a query", ErrInvalid, p.ID)
+		}
+		if p.DatasourceID != "" && p.DatasourceAlias != "" {
+			return fmt.Errorf("%w: panel %q names both a datasource and an alias", ErrInvalid, p.ID)
+		}
+		if p.DatasourceAlias != "" && !connection.ValidAlias(p.DatasourceAlias) {
+			return fmt.Errorf("%w: panel %q has an invalid datasource alias %q", ErrInvalid, p.ID, p.DatasourceAlias)
+		}
+		for j, t := range p.Transforms {
+			if err := t.Validate(); err != nil {
+				return fmt.Errorf("%w: panel %q transform %d: %v", ErrInvalid, p.ID, j, err)
+			}
+		}
+	}
+	return nil
+}
+
+// DataRequest selects what a dashboard data call renders.
+type DataRequest struct {
+	// TimeRange overrides the dashboard default.
+	TimeRange *TimeRange
+	// Variables overrides variable defaults by name.
+	Variables map[string]any
+	// PanelIDs limits execution to these panels; empty runs all.
+	PanelIDs []string
+	// Environment is where datasource aliases resolve; empty uses the
+	// service's.
+	Environment string
+}
+
+// PanelResult is the outcome of one panel query.
+type PanelResult struct {
+	PanelID       string           `json:"panel_id"`
+	ExecutedQuery string           `json:"executed_query,omitempty"`
+	Data          *sdk.QueryResult `json:"data,omitempty"`
+	Error         string           `json:"error,omitempty"`
+	DurationMS    int64            `json:"duration_ms"`
+	// Interval is the bucket width $__timeGroup used, e.g. "5m".
+	Interval string `json:"interval,omitempty"`
+}
+
+// DataResult is a rendered dashboard.
+type DataResult struct {
+	TimeRange TimeRange      `json:"time_range"` // resolved to RFC 3339
+	Variables map[string]any `json:"variables"`
+	Panels    []PanelResult  `json:"panels"`
+}
+
+// Data resolves the dashboard's variables and time range once and runs every
+// selected panel against that shared context. A failing panel is reported in
+// its result and does not affect the others.
+func (s *Service) Data(ctx context.Context, id string, req DataRequest) (*DataResult, error) {
+	d, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	trExpr := d.TimeRange
+	if req.TimeRange != nil {
+		trExpr = *req.TimeRange
+	}
+	tr, err := qb.ParseTimeRange(trExpr.From, trExpr.To)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	vars, err := resolveVariables(d.Variables, req.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	var panels []Panel
+	for _, p := range d.Panels {
+		if len(req.PanelIDs) == 0 || slices.Contains(req.PanelIDs, p.ID) {
+			panels = append(panels, p)
+		}
+	}
+
+	result := &DataResult{Variables: vars, Panels: make([]PanelResult, len(panels))}
+	if !tr.From.IsZero() {
+		result.TimeRange.From = tr.From.UTC().Format(time.RFC3339)
+	}
+	if !tr.To.IsZero() {
+		result.TimeRange.To = tr.To.UTC().Format(time.RFC3339)
+	}
+
+	env := req.Environment
+	if env == "" {
+		env = s.env
+	}
+	// Group panel indexes by datasource so each source is connected once.
+	byDatasource := map[string][]int{}
+	aliases := map[string]string{} // alias → datasource ID
+	for i, p := range panels {
+		dsID, err := s.datasourceID(ctx, p, env, aliases)
+		if err != nil {
+			result.Panels[i] = PanelResult{PanelID: p.ID, Error: err.Error()}
+			continue
+		}
+		byDatasource[dsID] = append(byDatasource[dsID], i)
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxParallelDatasources)
+	for dsID, idxs := range byDatasource {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			s.runPanels(ctx, dsID, panels, idxs, tr, vars, result.Panels)
+		}()
+	}
+	wg.Wait()
+	return result, nil
+}
+
+// datasourceID returns the ID of the datasource p queries, resolving its
+// alias in env. resolved caches aliases across the panels of one request.
+func (s *Service) datasourceID(ctx context.Context, p Panel, env string, resolved map[string]string) (string, error) {
+	if p.DatasourceID != "" {
+		return p.DatasourceID, nil
+	}
+	alias := p.DatasourceAlias
+	if alias == "" {
+		alias = connection.DefaultAlias
+	}
+	if id, ok := resolved[alias]; ok {
+		return id, nil
+	}
+	conn, err := connection.ResolveAlias(ctx, s.conns, alias, env)
+	if err != nil {
+		return "", err
+	}
+	resolved[alias] = conn.ID
+	return conn.ID, nil
+}
+
+// runPanels executes the panels at idxs, which all use datasource dsID, over
+// one connection and writes their results into out.
+func (s *Service) runPanels(ctx context.Context, dsID string, panels []Panel, idxs []int, tr qb.TimeRange, vars map[string]any, out []PanelResult) {
+	fail := func(err error) {
+		for _, i := range idxs {
+			out[i] = PanelResult{PanelID: panels[i].ID, Error: err.Error()}
+		}
+	}
+	conn, err := s.conns.GetByID(ctx, dsID)
+	if err != nil || !identity.FromContext(ctx).CanSee(conn.ID) {
+		fail(fmt.Errorf("datasource %s not found", dsID))
+		return
+	}
+	if err := connection.CheckOrigin(ctx, conn); err != nil {
+		fail(err)
+		return
+	}
+	if err := authz.CheckQuery(ctx, conn.ID); err != nil {
+		fail(err)
+		return
+	}
+	plugin, ok := s.registry.Get(conn.Type)
+	if !ok {
+		fail(fmt.Errorf("plugin not found for type %s", conn.Type))
+		return
+	}
+	cfg, err := plugin.ParseConfig(conn.Config)
+	if err != nil {
+		fail(fmt.Errorf("parse config: %w", err))
+		return
+	}
+	dbConn, err := plugin.Connect(ctx, cfg)
+	if err != nil {
+		fail(fmt.Errorf("connect: %w", err))
+		return
+	}
+	defer func() { _ = dbConn.Close() }()
+
+	dialect := qb.DialectFor(string(conn.Type))
+	for _, i := range idxs {
+		p := panels[i]
+		res := PanelResult{PanelID: p.ID}
+		limit := p.Limit
+		if limit <= 0 {
+			limit = defaultLimit
+		}
+		err := s.literals.Enforce(ctx, conn, p.Query)
+		var query string
+		if err == nil {
+			query, err = qb.RenderQuery(p.Query, qb.BuildContext(tr, vars, limit))
+		}
+		var interval time.Duration
+		if err == nil {
+			query, interval, err = qb.ExpandMacros(query, dialect, tr, p.MaxDataPoints)
+		}
+		if err == nil {
+			query, err = connection.DemoGuard(ctx, query)
+		}
+		if err != nil {
+			res.Error = err.Error()
+			out[i] = res
+			continue
+		}
+		res.ExecutedQuery = query
+		if interval > 0 {
+			res.Interval = qb.FormatInterval(interval)
+		}
+		start := time.Now()
+		data, err := dbConn.Query(connection.DemoContext(ctx), query)
+		res.DurationMS = time.Since(start).Milliseconds()
+		if err == nil {
+			connection.CapDemoRows(ctx, data)
+		}
+		if err != nil {
+			res.Error = fmt.Sprintf("query failed: %s", err)
+		} else if err := transform.ApplyResult(data, p.Transforms); err != nil {
+			res.Error = err.Error()
+		} else {
+			res.Data = data
+		}
+		out[i] = res
+	}
+}
+
+// resolveVariables merges requested values over variable defaults and
+// checks them against each variable's type.
+func resolveVariables(defs []Variable, requested map[string]any) (map[string]any, error) {
+	known := make(map[string]Variable, len(defs))
+	for _, v := range defs {
+		known[v.Name] = v
+	}
+	for name := range requested {
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("%w: unknown variable %q", ErrInvalid, name)
+		}
+	}
+
+	vars := make(map[string]any, len(defs))
+	for _, v := range defs {
+		val, ok := requested[v.Name]
+		if !ok || v.Type == VarConstant {
+			val = v.Default
+		}
+		if v.Type == VarCustom && val != nil {
+			if err := checkOptions(v, val); err != nil {
+				return nil, err
+			}
+		}
+		vars[v.Name] = val
+	}
+	return vars, nil
+}
+
+func checkOptions(v Variable, val any) error {
+	values := []any{val}
+	if list, ok := val.([]any); ok {
+		if !v.Multi {
+			return fmt.Errorf("%w: variable %q takes a single value", ErrInvalid, v.Name)
+		}
+		values = list
+	}
+	for _, x := range values {
+		s, _ := x.(string)
+		if !slices.Contains(v.Options, s) {
+			return fmt.Errorf("%w: %v is not an option of variable %q", ErrInvalid, x, v.Name)
+		}
+	}
+	return nil
+}
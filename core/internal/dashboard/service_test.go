@@ -0,0 +1,253 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/identity"
+	"data-voyager/sdk"
+)
+
+type memRepo struct {
+	Repository
+	d *Dashboard
+}
+
+func (m *memRepo) GetByID(_ context.Context, id string) (*Dashboard, error) {
+	if m.d == nil || m.d.ID != id {
+		return nil, errors.New("not found")
+	}
+	return m.d, nil
+}
+
+type stubConns struct{ connection.Repository }
+
+func (stubConns) GetByID(_ context.Context, id string) (*connection.Connection, error) {
+	if id == "missing" {
+		return nil, errors.New("not found")
+	}
+	return &connection.Connection{ID: id, Type: "stub", Config: json.RawMessage(`{}`)}, nil
+}
+
+// List answers alias lookups: "analytics" is ds-dev in dev and ds-prod in
+// prod, and "default" is ds-default everywhere.
+func (stubConns) List(_ context.Context, f connection.Filter) ([]*connection.Connection, error) {
+	all := []*connection.Connection{
+		{ID: "ds-dev", Alias: "analytics", Environment: "dev"},
+		{ID: "ds-prod", Alias: "analytics", Environment: "prod"},
+		{ID: "ds-default", Alias: connection.DefaultAlias},
+	}
+	var out []*connection.Connection
+	for _, c := range all {
+		if c.Alias == f.Alias {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+type stubConfig struct{}
+
+func (stubConfig) Validate() error             { return nil }
+func (stubConfig) GetConnectionString() string { return "" }
+
+// stubPlugin records executed queries and counts connections.
+type stubPlugin struct {
+	sdk.DatasourcePlugin
+	mu       sync.Mutex
+	connects int
+	queries  []string
+}
+
+func (p *stubPlugin) GetType() sdk.DataSourceType { return "stub" }
+func (p *stubPlugin) GetName() string             { return "Stub" }
+func (p *stubPlugin) ParseConfig(json.RawMessage) (sdk.ConnectionConfig, error) {
+	return stubConfig{}, nil
+}
+func (p *stubPlugin) Connect(context.Context, sdk.ConnectionConfig) (sdk.Connection, error) {
+	p.mu.Lock()
+	p.connects++
+	p.mu.Unlock()
+	return &stubConn{p: p}, nil
+}
+
+type stubConn struct {
+	sdk.Connection
+	p *stubPlugin
+}
+
+func (c *stubConn) Query(_ context.Context, q string, _ ...any) (*sdk.QueryResult, error) {
+	c.p.mu.Lock()
+	c.p.queries = append(c.p.queries, q)
+	c.p.mu.Unlock()
+	return &sdk.QueryResult{}, nil
+}
+func (c *stubConn) Close() error { return nil }
+
+func newTestService(d *Dashboard) (*Service, *stubPlugin) {
+	plugin := &stubPlugin{}
+	reg := datasource.NewRegistry()
+	reg.Register(plugin)
+	return NewService(&memRepo{d: d}, stubConns{}, reg), plugin
+}
+
+func salesDashboard() *Dashboard {
+	return &Dashboard{
+		ID: "d1",
+		Variables: []Variable{
+			{Name: "region", Type: VarCustom, Options: []string{"eu", "us"}, Default: "eu"},
+			{Name: "tenant", Type: VarConstant, Default: "acme"},
+		},
+		Panels: []Panel{
+			{ID: "a", DatasourceID: "ds1", Query: "SELECT '{{ region }}', '{{ tenant }}', {{ __start_time }}"},
+			{ID: "b", DatasourceID: "ds1", Query: "SELECT {{ __limit }}", Limit: 5},
+			{ID: "c", DatasourceID: "missing", Query: "SELECT 1"},
+		},
+		TimeRange: TimeRange{From: "2024-01-01T00:00:00Z", To: "2024-01-02T00:00:00Z"},
+	}
+}
+
+func TestData_SharedVariableContext(t *testing.T) {
+	svc, plugin := newTestService(salesDashboard())
+
+	res, err := svc.Data(context.Background(), "d1", DataRequest{
+		Variables: map[string]any{"region": "us", "tenant": "ignored"},
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Panels, 3)
+
+	assert.Equal(t, "SELECT 'us', 'acme', 1704067200", res.Panels[0].ExecutedQuery)
+	assert.Equal(t, "SELECT 5", res.Panels[1].ExecutedQuery)
+	assert.Contains(t, res.Panels[2].Error, "not found", "a broken panel does not fail the dashboard")
+	assert.Equal(t, 1, plugin.connects, "panels on one datasource share a connection")
+	assert.Equal(t, "2024-01-01T00:00:00Z", res.TimeRange.From)
+	assert.Equal(t, map[string]any{"region": "us", "tenant": "acme"}, res.Variables)
+}
+
+func TestData_PanelSelection(t *testing.T) {
+	svc, plugin := newTestService(salesDashboard())
+
+	res, err := svc.Data(context.Background(), "d1", DataRequest{PanelIDs: []string{"b"}})
+	require.NoError(t, err)
+	require.Len(t, res.Panels, 1)
+	assert.Equal(t, "b", res.Panels[0].PanelID)
+	assert.Equal(t, []string{"SELECT 5"}, plugin.queries)
+}
+
+func TestData_InvalidVariables(t *testing.T) {
+	svc, _ := newTestService(salesDashboard())
+
+	_, err := svc.Data(context.Background(), "d1", DataRequest{Variables: map[string]any{"region": "apac"}})
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = svc.Data(context.Background(), "d1", DataRequest{Variables: map[string]any{"nope": 1}})
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = svc.Data(context.Background(), "d1", DataRequest{Variables: map[string]any{"region": []any{"eu", "us"}}})
+	assert.ErrorIs(t, err, ErrInvalid, "region is not multi-valued")
+	_, err = svc.Data(context.Background(), "other", DataRequest{})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestData_Demo(t *testing.T) {
+	d := &Dashboard{ID: "d1", Panels: []Panel{
+		{ID: "a", DatasourceID: "ds1", Query: "SELECT * FROM sales"},
+		{ID: "b", DatasourceID: "ds1", Query: "DELETE FROM sales"},
+		{ID: "c", DatasourceID: "ds2", Query: "SELECT 1"},
+	}}
+	svc, plugin := newTestService(d)
+	demo := &identity.Identity{Role: identity.RoleViewer, Datasources: []string{"ds1"}, Dashboards: []string{"d1"}, RowLimit: 10, Demo: true}
+	ctx := identity.With(context.Background(), demo)
+
+	res, err := svc.Data(ctx, "d1", DataRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, res.Panels[0].Error)
+	assert.ErrorContains(t, errors.New(res.Panels[1].Error), "read-only")
+	assert.Contains(t, res.Panels[2].Error, "not found", "datasources outside the demo stay hidden")
+	assert.Equal(t, []string{"SELECT * FROM sales\nLIMIT 10"}, plugin.queries)
+
+	demo.Dashboards = nil
+	_, err = svc.Data(ctx, "d1", DataRequest{})
+	assert.ErrorIs(t, err, ErrNotFound, "only the listed dashboards are shown")
+}
+
+func TestWrites_NeedDashboardWrite(t *testing.T) {
+	svc, _ := newTestService(salesDashboard())
+	demo := &identity.Identity{Role: identity.RoleViewer, Dashboards: []string{"d1"}, Demo: true}
+	ctx := identity.With(context.Background(), demo)
+	assert.ErrorIs(t, svc.Delete(ctx, "d2"), ErrNotFound, "a hidden dashboard cannot be deleted")
+	assert.ErrorIs(t, svc.Update(ctx, &Dashboard{ID: "d2", Name: "x"}), ErrNotFound)
+	_, err := svc.Put(ctx, &Dashboard{ID: "d2", Name: "x"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/dashboards", strings.NewReader(`{"name":"x"}`))
+	c.Request = c.Request.WithContext(identity.With(c.Request.Context(), &identity.Identity{Role: identity.RoleViewer}))
+	NewHandler(svc).Create(c)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestLiteralPolicy(t *testing.T) {
+	d := &Dashboard{ID: "d1", Name: "events", Panels: []Panel{
+		{ID: "a", DatasourceID: "ds1", Query: "SELECT * FROM events WHERE kind = 'click'"},
+	}}
+	svc, plugin := newTestService(d)
+	svc.WithLiteralPolicy(connection.NewLiteralPolicy(connection.ParamPolicyReject))
+	ctx := identity.With(context.Background(), &identity.Identity{Role: identity.RoleEditor})
+
+	err := svc.Create(ctx, &Dashboard{Name: "copy", Panels: d.Panels})
+	assert.ErrorIs(t, err, ErrInvalid)
+	assert.ErrorIs(t, err, connection.ErrRawLiterals)
+
+	res, err := svc.Data(ctx, "d1", DataRequest{})
+	require.NoError(t, err)
+	assert.Contains(t, res.Panels[0].Error, "'click'", "panels saved before the policy are refused when run")
+	assert.Empty(t, plugin.queries)
+}
+
+func TestData_DatasourceAliases(t *testing.T) {
+	d := &Dashboard{ID: "d1", Panels: []Panel{
+		{ID: "a", DatasourceAlias: "analytics", Query: "SELECT 1"},
+		{ID: "b", Query: "SELECT 2"},
+		{ID: "c", DatasourceAlias: "billing", Query: "SELECT 3"},
+	}}
+	svc, _ := newTestService(d)
+	svc.WithEnvironment("prod")
+
+	res, err := svc.Data(context.Background(), "d1", DataRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, res.Panels[0].Error)
+	assert.Empty(t, res.Panels[1].Error, "a panel without a datasource uses the default alias")
+	assert.Contains(t, res.Panels[2].Error, `datasource alias not found: "billing"`)
+
+	var ids []string
+	for _, p := range d.Panels[:2] {
+		id, err := svc.datasourceID(context.Background(), p, "dev", map[string]string{})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	assert.Equal(t, []string{"ds-dev", "ds-default"}, ids)
+}
+
+func TestValidate(t *testing.T) {
+	d := &Dashboard{Name: "x", Panels: []Panel{{DatasourceID: "ds", Query: "SELECT 1"}}}
+	require.NoError(t, validate(d))
+	assert.NotEmpty(t, d.Panels[0].ID, "panel ids are assigned")
+	assert.Equal(t, TimeRange{From: "now-6h", To: "now"}, d.TimeRange)
+
+	assert.ErrorIs(t, validate(&Dashboard{Name: "x", Variables: []Variable{{Name: "__x", Type: VarTextbox}}}), ErrInvalid)
+	assert.ErrorIs(t, validate(&Dashboard{Name: "x", Variables: []Variable{{Name: "v", Type: VarCustom}}}), ErrInvalid)
+	assert.ErrorIs(t, validate(&Dashboard{Name: "x", Panels: []Panel{{DatasourceID: "ds", DatasourceAlias: "a", Query: "SELECT 1"}}}), ErrInvalid)
+	assert.ErrorIs(t, validate(&Dashboard{Name: "x", Panels: []Panel{{DatasourceAlias: "Not Valid", Query: "SELECT 1"}}}), ErrInvalid)
+}
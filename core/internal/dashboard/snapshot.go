@@ -0,0 +1,180 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/identity"
+)
+
+var (
+	// ErrSnapshotsDisabled is returned by the snapshot operations of a
+	// Service without a snapshot store.
+	ErrSnapshotsDisabled = errors.New("dashboard snapshots are not available")
+	// ErrRestricted is returned for exporting a snapshot whose label
+	// forbids it.
+	ErrRestricted = errors.New("snapshot label does not allow exporting")
+)
+
+// Label is a sensitivity class snapshots can be given.
+type Label struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// MaxAgeDays is how long snapshots with the label are kept; 0 keeps
+	// them.
+	MaxAgeDays int  `json:"max_age_days"`
+	NoExport   bool `json:"no_export"`
+	// Default is set on the label snapshots taken without one get.
+	Default bool `json:"default"`
+}
+
+// WithSnapshots sets where snapshots are kept.
+func (s *Service) WithSnapshots(repo SnapshotRepository) *Service {
+	s.snapshots = repo
+	return s
+}
+
+// WithLabels sets the labels snapshots can be given. A snapshot's expiry is
+// fixed when it is taken, so changing a label's max age only affects newer
+// snapshots; export restrictions follow the current configuration.
+func (s *Service) WithLabels(cfg config.RetentionConfig) *Service {
+	s.labels, s.defaultLabel = cfg.Labels, cfg.DefaultLabel
+	return s
+}
+
+// Labels lists the configured labels by name.
+func (s *Service) Labels() []Label {
+	out := make([]Label, 0, len(s.labels))
+	for name, l := range s.labels {
+		out = append(out, Label{Name: name, Description: l.Description, MaxAgeDays: l.MaxAgeDays,
+			NoExport: l.NoExport, Default: name == s.defaultLabel})
+	}
+	slices.SortFunc(out, func(a, b Label) int { return strings.Compare(a.Name, b.Name) })
+	return out
+}
+
+// TakeSnapshot runs the dashboard as Data does and stores the dashboard
+// together with the result. name defaults to the dashboard's name and the
+// time taken.
+func (s *Service) TakeSnapshot(ctx context.Context, id, name, label string, req DataRequest) (*Snapshot, error) {
+	if s.snapshots == nil {
+		return nil, ErrSnapshotsDisabled
+	}
+	if label == "" {
+		label = s.defaultLabel
+	}
+	class, ok := s.labels[label]
+	if label != "" && !ok {
+		return nil, fmt.Errorf("%w: unknown snapshot label %q", ErrInvalid, label)
+	}
+	d, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	result, err := s.Data(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if name == "" {
+		name = d.Name + " " + now.Format("2006-01-02 15:04 UTC")
+	}
+	snap := &Snapshot{
+		ID:          uuid.NewString(),
+		DashboardID: d.ID,
+		Name:        name,
+		CreatedAt:   now,
+		Label:       label,
+		Content:     &SnapshotContent{Dashboard: *d, Result: *result},
+	}
+	if class.MaxAgeDays > 0 {
+		expires := now.AddDate(0, 0, class.MaxAgeDays)
+		snap.ExpiresAt = &expires
+	}
+	if id := identity.FromContext(ctx); id != nil {
+		snap.CreatedBy = id.Username
+	}
+	if err := s.snapshots.Create(ctx, snap); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// Snapshots lists a dashboard's snapshots, newest first.
+func (s *Service) Snapshots(ctx context.Context, dashboardID string) ([]*Snapshot, error) {
+	if s.snapshots == nil {
+		return nil, ErrSnapshotsDisabled
+	}
+	if !identity.FromContext(ctx).CanSeeDashboard(dashboardID) {
+		return []*Snapshot{}, nil
+	}
+	return s.snapshots.List(ctx, dashboardID)
+}
+
+// GetSnapshot returns a snapshot with its content, treating one of a
+// dashboard hidden from ctx's identity as missing.
+func (s *Service) GetSnapshot(ctx context.Context, id string) (*Snapshot, error) {
+	if s.snapshots == nil {
+		return nil, ErrSnapshotsDisabled
+	}
+	snap, err := s.snapshots.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	if !identity.FromContext(ctx).CanSeeDashboard(snap.DashboardID) || snap.expired(time.Now()) {
+		return nil, fmt.Errorf("%w: snapshot %s", ErrNotFound, id)
+	}
+	return snap, nil
+}
+
+// expired reports whether the snapshot's retention ran out before now; it
+// may not have been swept yet.
+func (s *Snapshot) expired(now time.Time) bool {
+	return s.ExpiresAt != nil && !now.Before(*s.ExpiresAt)
+}
+
+// exportable reports whether snap may be rendered to a file.
+func (s *Service) exportable(snap *Snapshot) error {
+	if s.labels[snap.Label].NoExport {
+		return fmt.Errorf("%w: %s", ErrRestricted, snap.Label)
+	}
+	return nil
+}
+
+// Schedule deletes expired snapshots every interval until ctx is done.
+func (s *Service) Schedule(ctx context.Context, interval time.Duration) {
+	if s.snapshots == nil {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		n, err := s.snapshots.DeleteExpired(ctx, time.Now().UTC())
+		if err != nil {
+			slog.Warn("failed to delete expired snapshots", "err", err)
+		} else if n > 0 {
+			slog.Info("deleted expired snapshots", "count", n)
+		}
+	}
+}
+
+// DeleteSnapshot removes a snapshot.
+func (s *Service) DeleteSnapshot(ctx context.Context, id string) error {
+	if _, err := s.GetSnapshot(ctx, id); err != nil {
+		return err
+	}
+	return s.snapshots.Delete(ctx, id)
+}
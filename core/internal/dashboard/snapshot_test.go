@@ -0,0 +1,129 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/identity"
+)
+
+type memSnapshots struct{ m map[string]*Snapshot }
+
+func (r *memSnapshots) List(_ context.Context, dashboardID string) ([]*Snapshot, error) {
+	var out []*Snapshot
+	for _, s := range r.m {
+		if s.DashboardID == dashboardID {
+			out = append(out, &Snapshot{ID: s.ID, DashboardID: s.DashboardID, Name: s.Name})
+		}
+	}
+	return out, nil
+}
+
+func (r *memSnapshots) GetByID(_ context.Context, id string) (*Snapshot, error) {
+	if s, ok := r.m[id]; ok {
+		return s, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *memSnapshots) Create(_ context.Context, s *Snapshot) error { r.m[s.ID] = s; return nil }
+func (r *memSnapshots) Delete(_ context.Context, id string) error   { delete(r.m, id); return nil }
+
+func (r *memSnapshots) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	var n int64
+	for id, s := range r.m {
+		if s.expired(now) {
+			delete(r.m, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func TestTakeSnapshot(t *testing.T) {
+	svc, plugin := newTestService(salesDashboard())
+	_, err := svc.TakeSnapshot(context.Background(), "d1", "", "", DataRequest{})
+	assert.ErrorIs(t, err, ErrSnapshotsDisabled)
+
+	svc.WithSnapshots(&memSnapshots{m: map[string]*Snapshot{}})
+	ctx := identity.With(context.Background(), &identity.Identity{Username: "ann", Role: identity.RoleEditor})
+	snap, err := svc.TakeSnapshot(ctx, "d1", "Q4 close", "", DataRequest{Variables: map[string]any{"region": "us"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Q4 close", snap.Name)
+	assert.Equal(t, "ann", snap.CreatedBy)
+	assert.Equal(t, "d1", snap.Content.Dashboard.ID)
+	require.Len(t, snap.Content.Result.Panels, 3)
+	assert.Equal(t, "SELECT 'us', 'acme', 1704067200", snap.Content.Result.Panels[0].ExecutedQuery)
+
+	// Viewing it does not touch the datasource again.
+	ran := len(plugin.queries)
+	got, err := svc.GetSnapshot(ctx, snap.ID)
+	require.NoError(t, err)
+	assert.Equal(t, snap.Content.Result, got.Content.Result)
+	assert.Len(t, plugin.queries, ran)
+
+	list, err := svc.Snapshots(ctx, "d1")
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	demo := identity.With(context.Background(), &identity.Identity{Role: identity.RoleViewer, Demo: true})
+	_, err = svc.GetSnapshot(demo, snap.ID)
+	assert.ErrorIs(t, err, ErrNotFound, "snapshots of hidden dashboards are hidden")
+
+	_, err = svc.TakeSnapshot(ctx, "missing", "", "", DataRequest{})
+	assert.ErrorIs(t, err, ErrNotFound)
+	require.NoError(t, svc.DeleteSnapshot(ctx, snap.ID))
+	_, err = svc.GetSnapshot(ctx, snap.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSnapshotLabels(t *testing.T) {
+	svc, _ := newTestService(salesDashboard())
+	repo := &memSnapshots{m: map[string]*Snapshot{}}
+	svc.WithSnapshots(repo).WithLabels(config.RetentionConfig{
+		DefaultLabel: "internal",
+		Labels: map[string]config.RetentionLabel{
+			"internal":     {MaxAgeDays: 30},
+			"confidential": {MaxAgeDays: 7, NoExport: true},
+		},
+	})
+	ctx := context.Background()
+
+	labels := svc.Labels()
+	require.Len(t, labels, 2)
+	assert.Equal(t, "confidential", labels[0].Name)
+	assert.True(t, labels[1].Default)
+
+	snap, err := svc.TakeSnapshot(ctx, "d1", "", "", DataRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "internal", snap.Label)
+	require.NotNil(t, snap.ExpiresAt)
+	assert.Equal(t, snap.CreatedAt.AddDate(0, 0, 30), *snap.ExpiresAt)
+	assert.NoError(t, svc.exportable(snap))
+
+	secret, err := svc.TakeSnapshot(ctx, "d1", "", "confidential", DataRequest{})
+	require.NoError(t, err)
+	assert.ErrorIs(t, svc.exportable(secret), ErrRestricted)
+	_, err = svc.RenderSnapshot(ctx, secret.ID, "html")
+	assert.ErrorIs(t, err, ErrRestricted)
+
+	_, err = svc.TakeSnapshot(ctx, "d1", "", "top-secret", DataRequest{})
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	// Past its expiry a snapshot is gone even before the sweep runs.
+	past := time.Now().Add(-time.Minute)
+	secret.ExpiresAt = &past
+	_, err = svc.GetSnapshot(ctx, secret.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	n, err := repo.DeleteExpired(ctx, time.Now())
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+	_, err = svc.GetSnapshot(ctx, snap.ID)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,143 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"data-voyager/core/internal/identity"
+)
+
+// ErrVersionsDisabled is returned by the version operations of a Service
+// without a version store.
+var ErrVersionsDisabled = errors.New("dashboard versions are not available")
+
+// Version is one saved revision of a dashboard. Every create and update
+// records one, numbered from 1.
+type Version struct {
+	DashboardID string    `json:"dashboard_id"`
+	Version     int       `json:"version"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	// Dashboard is the dashboard as saved; it is left out of lists.
+	Dashboard *Dashboard `json:"dashboard,omitempty"`
+}
+
+// VersionRepository persists dashboard versions.
+// Implemented per-driver in store/{postgres,sqlite,mysql}/.
+type VersionRepository interface {
+	// List returns a dashboard's versions newest first, without their
+	// content.
+	List(ctx context.Context, dashboardID string) ([]*Version, error)
+	// Get returns one version; version 0 is the latest.
+	Get(ctx context.Context, dashboardID string, version int) (*Version, error)
+	// Create stores v, failing when its number is already taken.
+	Create(ctx context.Context, v *Version) error
+	DeleteAll(ctx context.Context, dashboardID string) error
+}
+
+// WithVersions sets where dashboard versions are kept.
+func (s *Service) WithVersions(repo VersionRepository) *Service {
+	s.versions = repo
+	return s
+}
+
+// record stores d as its dashboard's next version. The dashboard is saved
+// by then, so a failure is logged rather than returned.
+func (s *Service) record(ctx context.Context, d *Dashboard) {
+	if s.versions == nil {
+		return
+	}
+	next := 1
+	if latest, err := s.versions.List(ctx, d.ID); err == nil && len(latest) > 0 {
+		next = latest[0].Version + 1
+	}
+	saved := *d
+	v := &Version{DashboardID: d.ID, Version: next, CreatedAt: d.UpdatedAt.UTC(), Dashboard: &saved}
+	if v.CreatedAt.IsZero() {
+		v.CreatedAt = time.Now().UTC()
+	}
+	if caller := identity.FromContext(ctx); caller != nil {
+		v.CreatedBy = caller.Username
+	}
+	if err := s.versions.Create(ctx, v); err != nil {
+		slog.Warn("dashboard version not recorded", "dashboard", d.ID, "version", next, "err", err)
+	}
+}
+
+// recordBaseline stores the saved state of a dashboard that has no versions
+// yet, such as one created before versions were kept, so its first update
+// has something to be compared with.
+func (s *Service) recordBaseline(ctx context.Context, id string) {
+	if s.versions == nil {
+		return
+	}
+	if list, err := s.versions.List(ctx, id); err != nil || len(list) > 0 {
+		return
+	}
+	old, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	v := &Version{DashboardID: id, Version: 1, CreatedAt: old.UpdatedAt.UTC(), Dashboard: old}
+	if v.CreatedAt.IsZero() {
+		v.CreatedAt = time.Now().UTC()
+	}
+	if err := s.versions.Create(ctx, v); err != nil {
+		slog.Warn("dashboard version not recorded", "dashboard", id, "version", 1, "err", err)
+	}
+}
+
+// Versions lists the versions of a dashboard visible to ctx's identity,
+// newest first.
+func (s *Service) Versions(ctx context.Context, id string) ([]*Version, error) {
+	if s.versions == nil {
+		return nil, ErrVersionsDisabled
+	}
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return s.versions.List(ctx, id)
+}
+
+// Version returns one version of a dashboard; 0 is the latest.
+func (s *Service) Version(ctx context.Context, id string, version int) (*Version, error) {
+	if s.versions == nil {
+		return nil, ErrVersionsDisabled
+	}
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	v, err := s.versions.Get(ctx, id, version)
+	if err != nil {
+		return nil, fmt.Errorf("%w: version %d of %s", ErrNotFound, version, id)
+	}
+	return v, nil
+}
+
+// DiffVersions compares two versions of a dashboard. to 0 is the latest
+// version and from 0 the one before to.
+func (s *Service) DiffVersions(ctx context.Context, id string, from, to int) (*Diff, error) {
+	if from < 0 || to < 0 {
+		return nil, fmt.Errorf("%w: versions are numbered from 1", ErrInvalid)
+	}
+	newer, err := s.Version(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+	if from == 0 {
+		from = newer.Version - 1
+	}
+	if from < 1 {
+		return nil, fmt.Errorf("%w: version %d has no earlier version", ErrInvalid, newer.Version)
+	}
+	older, err := s.Version(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+	d := Compare(older.Dashboard, newer.Dashboard)
+	d.DashboardID, d.From, d.To = id, older.Version, newer.Version
+	return d, nil
+}
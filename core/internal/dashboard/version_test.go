@@ -0,0 +1,175 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/identity"
+)
+
+// savingRepo keeps copies of dashboards the way a database does, so that
+// changes to a loaded dashboard are not seen until it is saved.
+type savingRepo struct {
+	Repository
+	m map[string]Dashboard
+}
+
+func (r *savingRepo) GetByID(_ context.Context, id string) (*Dashboard, error) {
+	d, ok := r.m[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &d, nil
+}
+
+func (r *savingRepo) Create(_ context.Context, d *Dashboard) error { r.m[d.ID] = *d; return nil }
+func (r *savingRepo) Update(_ context.Context, d *Dashboard) error { r.m[d.ID] = *d; return nil }
+func (r *savingRepo) Delete(_ context.Context, id string) error    { delete(r.m, id); return nil }
+
+type memVersions struct{ m map[string][]*Version }
+
+func (r *memVersions) List(_ context.Context, dashboardID string) ([]*Version, error) {
+	var out []*Version
+	for i := len(r.m[dashboardID]) - 1; i >= 0; i-- {
+		v := *r.m[dashboardID][i]
+		v.Dashboard = nil
+		out = append(out, &v)
+	}
+	return out, nil
+}
+
+func (r *memVersions) Get(_ context.Context, dashboardID string, version int) (*Version, error) {
+	list := r.m[dashboardID]
+	if version == 0 && len(list) > 0 {
+		return list[len(list)-1], nil
+	}
+	for _, v := range list {
+		if v.Version == version {
+			return v, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *memVersions) Create(_ context.Context, v *Version) error {
+	if len(r.m[v.DashboardID]) != v.Version-1 {
+		return errors.New("version taken")
+	}
+	r.m[v.DashboardID] = append(r.m[v.DashboardID], v)
+	return nil
+}
+
+func (r *memVersions) DeleteAll(_ context.Context, dashboardID string) error {
+	delete(r.m, dashboardID)
+	return nil
+}
+
+func TestVersions(t *testing.T) {
+	repo := &savingRepo{m: map[string]Dashboard{}}
+	svc := NewService(repo, stubConns{}, datasource.NewRegistry())
+	ctx := identity.With(context.Background(), &identity.Identity{Username: "ann", Role: identity.RoleEditor})
+
+	_, err := svc.Versions(ctx, "d1")
+	assert.ErrorIs(t, err, ErrVersionsDisabled)
+
+	// A dashboard saved before versions were kept gets its saved state as
+	// version 1 on its first update.
+	old := salesDashboard()
+	old.Name = "Sales"
+	repo.m["d1"] = *old
+	versions := &memVersions{m: map[string][]*Version{}}
+	svc.WithVersions(versions)
+
+	d, err := svc.Get(ctx, "d1")
+	require.NoError(t, err)
+	d.Name = "Sales by region"
+	d.Panels = append([]Panel{}, d.Panels...)
+	d.Panels[0].Query = "SELECT '{{ region }}',\n  {{ __start_time }}"
+	d.Panels[1].Limit = 10
+	d.Panels = append(d.Panels[:2], Panel{ID: "e", DatasourceID: "ds1", Query: "SELECT 2"})
+	d.Variables = d.Variables[:1]
+	require.NoError(t, svc.Update(ctx, d))
+
+	list, err := svc.Versions(ctx, "d1")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, 2, list[0].Version)
+	assert.Equal(t, "ann", list[0].CreatedBy)
+	assert.Empty(t, list[1].CreatedBy)
+	assert.Nil(t, list[0].Dashboard)
+
+	v1, err := svc.Version(ctx, "d1", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Sales", v1.Dashboard.Name)
+
+	diff, err := svc.DiffVersions(ctx, "d1", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, diff.From)
+	assert.Equal(t, 2, diff.To)
+	assert.Equal(t, []FieldChange{{Field: "name", From: "Sales", To: "Sales by region"}}, diff.Fields)
+	require.Len(t, diff.Variables, 1)
+	assert.Equal(t, VariableChange{Name: "tenant", Change: ChangeRemoved, From: &old.Variables[1]}, diff.Variables[0])
+
+	require.Len(t, diff.Panels, 4)
+	assert.Equal(t, PanelChange{ID: "a", Change: ChangeChanged, Query: []LineChange{
+		{Op: "-", Text: "SELECT '{{ region }}', '{{ tenant }}', {{ __start_time }}"},
+		{Op: "+", Text: "SELECT '{{ region }}',"},
+		{Op: "+", Text: "  {{ __start_time }}"},
+	}}, diff.Panels[0])
+	assert.Equal(t, PanelChange{ID: "b", Change: ChangeChanged,
+		Fields: []FieldChange{{Field: "limit", From: 5, To: 10}}}, diff.Panels[1])
+	assert.Equal(t, PanelChange{ID: "e", Change: ChangeAdded, Query: []LineChange{{Op: "+", Text: "SELECT 2"}}}, diff.Panels[2])
+	assert.Equal(t, ChangeRemoved, diff.Panels[3].Change)
+	assert.Equal(t, "c", diff.Panels[3].ID)
+
+	_, err = svc.DiffVersions(ctx, "d1", 1, 1)
+	require.NoError(t, err)
+	_, err = svc.DiffVersions(ctx, "d1", 0, 1)
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = svc.Version(ctx, "d1", 3)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	restricted := identity.With(context.Background(), &identity.Identity{Username: "bob", Role: identity.RoleViewer, Demo: true,
+		Dashboards: []string{"other"}})
+	_, err = svc.Versions(restricted, "d1")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, svc.Delete(ctx, "d1"))
+	assert.Empty(t, versions.m)
+}
+
+func TestVersions_Create(t *testing.T) {
+	versions := &memVersions{m: map[string][]*Version{}}
+	svc := NewService(&savingRepo{m: map[string]Dashboard{}}, stubConns{}, datasource.NewRegistry()).WithVersions(versions)
+	ctx := context.Background()
+
+	d := &Dashboard{Name: "Ops"}
+	require.NoError(t, svc.Create(ctx, d))
+	d.Description = "on-call"
+	require.NoError(t, svc.Update(ctx, d))
+
+	list, err := svc.Versions(ctx, d.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	diff, err := svc.DiffVersions(ctx, d.ID, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []FieldChange{{Field: "description", From: "", To: "on-call"}}, diff.Fields)
+	assert.Empty(t, diff.Panels)
+}
+
+func TestDiffLines(t *testing.T) {
+	got := diffLines("SELECT a\nFROM t\nWHERE x = 1\n", "SELECT a, b\nFROM t\nWHERE x = 1\nLIMIT 5")
+	assert.Equal(t, []LineChange{
+		{Op: "-", Text: "SELECT a"},
+		{Op: "+", Text: "SELECT a, b"},
+		{Op: " ", Text: "FROM t"},
+		{Op: " ", Text: "WHERE x = 1"},
+		{Op: "+", Text: "LIMIT 5"},
+	}, got)
+	assert.Nil(t, diffLines("", ""))
+}
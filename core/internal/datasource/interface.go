@@ -1,6 +1,14 @@
 package datasource
 
-import "data-voyager/sdk"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"data-voyager/sdk"
+)
 
 // Re-export sdk types so existing code in core can reference them
 // via the shorter "datasource." prefix without changing call sites.
@@ -16,43 +24,120 @@ type (
 	ConnectionMetrics = sdk.ConnectionMetrics
 )
 
-// Registry manages registered datasource plugins within core.
+// Registry manages registered datasource plugins within core. It is safe
+// for concurrent use: plugins may be registered, deregistered, enabled and
+// disabled while requests are being served.
 type Registry struct {
-	plugins map[sdk.DataSourceType]sdk.DatasourcePlugin
+	mu       sync.RWMutex
+	plugins  map[sdk.DataSourceType]sdk.DatasourcePlugin
+	disabled map[sdk.DataSourceType]bool
+}
+
+// PluginState describes a registered plugin for administration.
+type PluginState struct {
+	Type    sdk.DataSourceType
+	Name    string
+	Enabled bool
+	Version sdk.VersionInfo
 }
 
+// ErrUnknownType is returned for a datasource type with no registered plugin.
+var ErrUnknownType = errors.New("unknown datasource type")
+
 // NewRegistry creates an empty Registry.
 func NewRegistry() *Registry {
 	return &Registry{
-		plugins: make(map[sdk.DataSourceType]sdk.DatasourcePlugin),
+		plugins:  make(map[sdk.DataSourceType]sdk.DatasourcePlugin),
+		disabled: make(map[sdk.DataSourceType]bool),
 	}
 }
 
-// Register adds a plugin to the registry.
+// Register adds a plugin to the registry, replacing any plugin of the same
+// type. A replacement keeps the enabled state of the one it replaces.
 func (r *Registry) Register(plugin sdk.DatasourcePlugin) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.plugins[plugin.GetType()] = plugin
 }
 
-// Get retrieves a plugin by datasource type.
+// Deregister removes the plugin for dsType and reports whether there was one.
+// Datasources of that type stop working until a plugin is registered again.
+func (r *Registry) Deregister(dsType sdk.DataSourceType) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.plugins[dsType]
+	delete(r.plugins, dsType)
+	delete(r.disabled, dsType)
+	return ok
+}
+
+// SetEnabled enables or disables the plugin for dsType. A disabled plugin
+// stays registered but is hidden from Get, List and GetSupportedTypes.
+func (r *Registry) SetEnabled(dsType sdk.DataSourceType, enabled bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.plugins[dsType]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownType, dsType)
+	}
+	if enabled {
+		delete(r.disabled, dsType)
+	} else {
+		r.disabled[dsType] = true
+	}
+	return nil
+}
+
+// Get retrieves an enabled plugin by datasource type.
 func (r *Registry) Get(dsType sdk.DataSourceType) (sdk.DatasourcePlugin, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	plugin, exists := r.plugins[dsType]
-	return plugin, exists
+	if !exists || r.disabled[dsType] {
+		return nil, false
+	}
+	return plugin, true
 }
 
-// List returns all registered plugins.
+// List returns all enabled plugins.
 func (r *Registry) List() map[sdk.DataSourceType]sdk.DatasourcePlugin {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	result := make(map[sdk.DataSourceType]sdk.DatasourcePlugin, len(r.plugins))
 	for k, v := range r.plugins {
-		result[k] = v
+		if !r.disabled[k] {
+			result[k] = v
+		}
 	}
 	return result
 }
 
-// GetSupportedTypes returns all registered datasource type identifiers.
+// GetSupportedTypes returns the enabled datasource type identifiers, sorted.
 func (r *Registry) GetSupportedTypes() []sdk.DataSourceType {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	types := make([]sdk.DataSourceType, 0, len(r.plugins))
 	for dsType := range r.plugins {
-		types = append(types, dsType)
+		if !r.disabled[dsType] {
+			types = append(types, dsType)
+		}
 	}
+	slices.Sort(types)
 	return types
 }
+
+// States returns every registered plugin, enabled or not, sorted by type.
+func (r *Registry) States() []PluginState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	states := make([]PluginState, 0, len(r.plugins))
+	for dsType, p := range r.plugins {
+		states = append(states, PluginState{
+			Type:    dsType,
+			Name:    p.GetName(),
+			Enabled: !r.disabled[dsType],
+			Version: sdk.PluginVersion(p),
+		})
+	}
+	slices.SortFunc(states, func(a, b PluginState) int { return strings.Compare(string(a.Type), string(b.Type)) })
+	return states
+}
@@ -0,0 +1,81 @@
+package datasource
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/sdk"
+)
+
+type namedPlugin struct {
+	sdk.DatasourcePlugin
+	typ sdk.DataSourceType
+}
+
+func (p namedPlugin) GetType() sdk.DataSourceType { return p.typ }
+func (p namedPlugin) GetName() string             { return string(p.typ) }
+
+func TestRegistry_EnableDisable(t *testing.T) {
+	r := NewRegistry()
+	r.Register(namedPlugin{typ: "pg"})
+	r.Register(namedPlugin{typ: "ch"})
+
+	require.NoError(t, r.SetEnabled("pg", false))
+	_, ok := r.Get("pg")
+	assert.False(t, ok)
+	assert.Equal(t, []sdk.DataSourceType{"ch"}, r.GetSupportedTypes())
+	assert.Len(t, r.List(), 1)
+	assert.Equal(t, []PluginState{{Type: "ch", Name: "ch", Enabled: true}, {Type: "pg", Name: "pg", Enabled: false}}, r.States())
+
+	// Re-registering, as a plugin host does on reload, keeps the state.
+	r.Register(namedPlugin{typ: "pg"})
+	_, ok = r.Get("pg")
+	assert.False(t, ok)
+
+	require.NoError(t, r.SetEnabled("pg", true))
+	_, ok = r.Get("pg")
+	assert.True(t, ok)
+
+	assert.ErrorIs(t, r.SetEnabled("mongo", true), ErrUnknownType)
+}
+
+func TestRegistry_Deregister(t *testing.T) {
+	r := NewRegistry()
+	r.Register(namedPlugin{typ: "pg"})
+	require.NoError(t, r.SetEnabled("pg", false))
+
+	assert.True(t, r.Deregister("pg"))
+	assert.False(t, r.Deregister("pg"))
+	assert.Empty(t, r.States())
+
+	// A fresh registration starts enabled.
+	r.Register(namedPlugin{typ: "pg"})
+	_, ok := r.Get("pg")
+	assert.True(t, ok)
+}
+
+func TestRegistry_ConcurrentUse(t *testing.T) {
+	r := NewRegistry()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			typ := sdk.DataSourceType(fmt.Sprintf("t%d", i))
+			for n := 0; n < 100; n++ {
+				r.Register(namedPlugin{typ: typ})
+				_ = r.SetEnabled(typ, n%2 == 0)
+				r.Get(typ)
+				r.GetSupportedTypes()
+				r.States()
+				r.Deregister(typ)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Empty(t, r.States())
+}
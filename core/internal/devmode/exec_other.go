@@ -0,0 +1,9 @@
+//go:build !(linux || darwin)
+
+package devmode
+
+// Exec is not available on this platform; restart the server by hand.
+func (r *Restart) Exec() error {
+	_ = r.listener.Close()
+	return ErrRestartUnsupported
+}
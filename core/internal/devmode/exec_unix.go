@@ -0,0 +1,32 @@
+//go:build linux || darwin
+
+package devmode
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// Exec replaces the running process with the current binary at the same path,
+// passing the listening socket along. It only returns on failure.
+func (r *Restart) Exec() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate executable: %w", err)
+	}
+
+	// The duplicated socket is close-on-exec; clear the flag so the new
+	// process inherits it under the same descriptor number.
+	fd := r.listener.Fd()
+	if _, _, errno := syscall.Syscall(syscall.SYS_FCNTL, fd, syscall.F_SETFD, 0); errno != 0 {
+		return fmt.Errorf("failed to pass listener: %w", errno)
+	}
+
+	env := append(os.Environ(),
+		envListenFD+"="+strconv.FormatUint(uint64(fd), 10),
+		envListenAddr+"="+r.addr,
+	)
+	return syscall.Exec(exe, os.Args, env)
+}
@@ -0,0 +1,78 @@
+package devmode
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+)
+
+// Environment variables that pass the listening socket to a restarted
+// process. The address is passed along so a config change to the host or
+// port is honoured instead of silently serving on the old socket.
+const (
+	envListenFD   = "VOYAGER_LISTEN_FD"
+	envListenAddr = "VOYAGER_LISTEN_ADDR"
+)
+
+// Listen returns a TCP listener for addr, reusing the socket inherited from
+// a previous process when there is one for the same address.
+func Listen(addr string) (net.Listener, error) {
+	fdStr, inheritedAddr := os.Getenv(envListenFD), os.Getenv(envListenAddr)
+	_ = os.Unsetenv(envListenFD)
+	_ = os.Unsetenv(envListenAddr)
+	if fdStr == "" {
+		return net.Listen("tcp", addr)
+	}
+
+	fd, err := strconv.Atoi(fdStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", envListenFD, fdStr, err)
+	}
+	f := os.NewFile(uintptr(fd), "listener")
+	defer func() { _ = f.Close() }()
+	if inheritedAddr != addr {
+		slog.Info("listen address changed, opening a new socket", "old", inheritedAddr, "new", addr)
+		return net.Listen("tcp", addr)
+	}
+	ln, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reuse inherited listener: %w", err)
+	}
+	return ln, nil
+}
+
+// ErrRestartUnsupported is returned by Restart on platforms that cannot
+// replace the running process.
+var ErrRestartUnsupported = errors.New("in-place restart is not supported on this platform")
+
+// Restart is returned by the serve loop when a watched file changed. The
+// caller runs its cleanup (closing stores, stopping background jobs) and then
+// calls Exec to replace the process with a fresh copy of the binary.
+type Restart struct {
+	// Reason is the path of the file whose change triggered the restart.
+	Reason string
+
+	addr     string
+	listener *os.File
+}
+
+// NewRestart duplicates ln's socket so it outlives the server's shutdown,
+// which closes ln itself. addr is the address ln was opened for.
+func NewRestart(reason, addr string, ln net.Listener) (*Restart, error) {
+	tcp, ok := ln.(*net.TCPListener)
+	if !ok {
+		return nil, fmt.Errorf("cannot hand over a %T", ln)
+	}
+	f, err := tcp.File()
+	if err != nil {
+		return nil, fmt.Errorf("failed to duplicate listener: %w", err)
+	}
+	return &Restart{Reason: reason, addr: addr, listener: f}, nil
+}
+
+func (r *Restart) Error() string {
+	return "restart requested: " + r.Reason + " changed"
+}
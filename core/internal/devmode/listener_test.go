@@ -0,0 +1,64 @@
+//go:build linux || darwin
+
+package devmode
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// handOver returns a descriptor for the restart's socket that, like one
+// inherited across exec, no *os.File in this process owns.
+func handOver(t *testing.T, r *Restart) string {
+	t.Helper()
+	fd, err := syscall.Dup(int(r.listener.Fd()))
+	require.NoError(t, err)
+	require.NoError(t, r.listener.Close())
+	return strconv.Itoa(fd)
+}
+
+func TestListen_ReusesHandedOverSocket(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+
+	restart, err := NewRestart("config.toml", addr, ln)
+	require.NoError(t, err)
+	require.NoError(t, ln.Close())
+
+	t.Setenv(envListenFD, handOver(t, restart))
+	t.Setenv(envListenAddr, addr)
+	reused, err := Listen(addr)
+	require.NoError(t, err)
+	defer func() { _ = reused.Close() }()
+	assert.Equal(t, addr, reused.Addr().String())
+	assert.Empty(t, os.Getenv(envListenFD), "the socket is only inherited once")
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	_ = conn.Close()
+	accepted, err := reused.Accept()
+	require.NoError(t, err)
+	_ = accepted.Close()
+}
+
+func TestListen_NewAddressOpensNewSocket(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	restart, err := NewRestart("config.toml", ln.Addr().String(), ln)
+	require.NoError(t, err)
+	require.NoError(t, ln.Close())
+
+	t.Setenv(envListenFD, handOver(t, restart))
+	t.Setenv(envListenAddr, ln.Addr().String())
+	fresh, err := Listen("127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = fresh.Close() }()
+	assert.NotEqual(t, ln.Addr().String(), fresh.Addr().String())
+}
@@ -0,0 +1,64 @@
+// Package devmode implements `serve --dev`: it watches the config file and
+// the server binary and restarts the server in place when they change,
+// handing the listening socket to the new process so clients never see the
+// port go away.
+package devmode
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+	exists  bool
+}
+
+func stat(path string) fileState {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{modTime: fi.ModTime(), size: fi.Size(), exists: true}
+}
+
+// Watch polls paths every interval until ctx is cancelled and calls
+// onChange with each path whose size or modification time changed. A change
+// is only reported once the file has stayed the same for a full interval, so
+// a binary that is still being written by the linker is not picked up half
+// way through.
+func Watch(ctx context.Context, interval time.Duration, paths []string, onChange func(path string)) {
+	seen := make(map[string]fileState, len(paths))
+	pending := make(map[string]fileState, len(paths))
+	for _, p := range paths {
+		seen[p] = stat(p)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for _, p := range paths {
+			cur := stat(p)
+			if cur == seen[p] {
+				delete(pending, p)
+				continue
+			}
+			if last, ok := pending[p]; !ok || last != cur {
+				pending[p] = cur
+				continue
+			}
+			delete(pending, p)
+			seen[p] = cur
+			if cur.exists {
+				onChange(p)
+			}
+		}
+	}
+}
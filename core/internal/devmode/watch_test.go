@@ -0,0 +1,44 @@
+package devmode
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch_ReportsSettledChanges(t *testing.T) {
+	dir := t.TempDir()
+	cfg := filepath.Join(dir, "config.toml")
+	other := filepath.Join(dir, "voyager")
+	require.NoError(t, os.WriteFile(cfg, []byte("a"), 0o644))
+	require.NoError(t, os.WriteFile(other, []byte("bin"), 0o755))
+
+	var mu sync.Mutex
+	var got []string
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go Watch(ctx, 10*time.Millisecond, []string{cfg, other}, func(path string) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, path)
+	})
+
+	time.Sleep(30 * time.Millisecond)
+	require.NoError(t, os.WriteFile(cfg, []byte("changed"), 0o644))
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(got) > 0
+	}, 2*time.Second, 5*time.Millisecond)
+
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{cfg}, got, "one change is reported once")
+}
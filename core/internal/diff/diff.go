@@ -0,0 +1,268 @@
+// Package diff compares two query results row by row, matching rows on key
+// columns. It backs migration checks where the same data lives in two
+// tables or two datasources.
+package diff
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"data-voyager/sdk"
+)
+
+// Options controls a comparison.
+type Options struct {
+	// Keys identify a row; they must be present and unique on both sides.
+	Keys []string
+	// Columns restricts the compared columns. Empty compares every non-key
+	// column the two sides share.
+	Columns []string
+	// Tolerance is the absolute difference under which two numbers count as
+	// equal.
+	Tolerance float64
+	// MaxRows caps how many rows each of Added, Removed and Changed lists.
+	// Counts are always exact. Zero lists everything.
+	MaxRows int
+}
+
+// Row maps column names to values.
+type Row map[string]any
+
+// ColumnChange is one differing cell of a changed row.
+type ColumnChange struct {
+	Column string
+	Left   any
+	Right  any
+}
+
+// Change is a row present on both sides with at least one differing column.
+type Change struct {
+	Key     Row
+	Columns []ColumnChange
+}
+
+// Result is the outcome of Compare. Added rows exist only on the right,
+// removed rows only on the left.
+type Result struct {
+	Keys      []string
+	Columns   []string
+	LeftOnly  []string
+	RightOnly []string
+
+	LeftRows  int
+	RightRows int
+	Added     int
+	Removed   int
+	Changed   int
+	Unchanged int
+
+	AddedRows   []Row
+	RemovedRows []Row
+	ChangedRows []Change
+	// Truncated is set when MaxRows cut any of the row lists short.
+	Truncated bool
+}
+
+// side indexes one frame by key.
+type side struct {
+	name   string
+	frame  *sdk.DataFrame
+	fields map[string]int
+	rows   int
+	byKey  map[string]int
+	order  []string
+}
+
+func newSide(name string, f *sdk.DataFrame, keys []string) (*side, error) {
+	s := &side{name: name, frame: f, fields: make(map[string]int, len(f.Fields))}
+	for i, fd := range f.Fields {
+		s.fields[fd.Name] = i
+		if len(fd.Values) > s.rows {
+			s.rows = len(fd.Values)
+		}
+	}
+	keyIdx := make([]int, len(keys))
+	for i, k := range keys {
+		idx, ok := s.fields[k]
+		if !ok {
+			return nil, fmt.Errorf("key column %q is missing from the %s result", k, name)
+		}
+		keyIdx[i] = idx
+	}
+	s.byKey = make(map[string]int, s.rows)
+	s.order = make([]string, s.rows)
+	parts := make([]string, len(keys))
+	for r := 0; r < s.rows; r++ {
+		for i, idx := range keyIdx {
+			parts[i] = canonical(s.value(idx, r))
+		}
+		k := strings.Join(parts, "\x00")
+		if _, dup := s.byKey[k]; dup {
+			return nil, fmt.Errorf("key (%s) is not unique in the %s result", strings.Join(parts, ", "), name)
+		}
+		s.byKey[k] = r
+		s.order[r] = k
+	}
+	return s, nil
+}
+
+func (s *side) value(field, row int) any {
+	if vals := s.frame.Fields[field].Values; row < len(vals) {
+		return vals[row]
+	}
+	return nil
+}
+
+func (s *side) row(r int, names []string) Row {
+	out := make(Row, len(names))
+	for _, n := range names {
+		out[n] = s.value(s.fields[n], r)
+	}
+	return out
+}
+
+func (s *side) allRow(r int) Row {
+	out := make(Row, len(s.frame.Fields))
+	for i, fd := range s.frame.Fields {
+		out[fd.Name] = s.value(i, r)
+	}
+	return out
+}
+
+// Compare diffs left against right.
+func Compare(left, right *sdk.DataFrame, opts Options) (*Result, error) {
+	if len(opts.Keys) == 0 {
+		return nil, fmt.Errorf("at least one key column is required")
+	}
+	l, err := newSide("left", left, opts.Keys)
+	if err != nil {
+		return nil, err
+	}
+	r, err := newSide("right", right, opts.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{Keys: opts.Keys, LeftRows: l.rows, RightRows: r.rows}
+	for _, fd := range left.Fields {
+		if slices.Contains(opts.Keys, fd.Name) {
+			continue
+		}
+		if _, ok := r.fields[fd.Name]; ok {
+			res.Columns = append(res.Columns, fd.Name)
+		} else {
+			res.LeftOnly = append(res.LeftOnly, fd.Name)
+		}
+	}
+	for _, fd := range right.Fields {
+		if _, ok := l.fields[fd.Name]; !ok && !slices.Contains(opts.Keys, fd.Name) {
+			res.RightOnly = append(res.RightOnly, fd.Name)
+		}
+	}
+	if len(opts.Columns) > 0 {
+		for _, c := range opts.Columns {
+			if !slices.Contains(res.Columns, c) {
+				return nil, fmt.Errorf("column %q is not a non-key column of both results", c)
+			}
+		}
+		res.Columns = opts.Columns
+	}
+
+	room := func(n int) bool {
+		if opts.MaxRows > 0 && n >= opts.MaxRows {
+			res.Truncated = true
+			return false
+		}
+		return true
+	}
+	for lr, k := range l.order {
+		rr, ok := r.byKey[k]
+		if !ok {
+			res.Removed++
+			if room(len(res.RemovedRows)) {
+				res.RemovedRows = append(res.RemovedRows, l.allRow(lr))
+			}
+			continue
+		}
+		var changes []ColumnChange
+		for _, c := range res.Columns {
+			lv, rv := l.value(l.fields[c], lr), r.value(r.fields[c], rr)
+			if !Equal(lv, rv, opts.Tolerance) {
+				changes = append(changes, ColumnChange{Column: c, Left: lv, Right: rv})
+			}
+		}
+		if len(changes) == 0 {
+			res.Unchanged++
+			continue
+		}
+		res.Changed++
+		if room(len(res.ChangedRows)) {
+			res.ChangedRows = append(res.ChangedRows, Change{Key: l.row(lr, opts.Keys), Columns: changes})
+		}
+	}
+	for rr, k := range r.order {
+		if _, ok := l.byKey[k]; ok {
+			continue
+		}
+		res.Added++
+		if room(len(res.AddedRows)) {
+			res.AddedRows = append(res.AddedRows, r.allRow(rr))
+		}
+	}
+	return res, nil
+}
+
+// Equal reports whether two cell values match. Values from different drivers
+// rarely share a Go type, so numbers compare by value (within tolerance) when
+// at least one side is a non-string number, times compare as instants, and
+// everything else compares by its canonical text.
+func Equal(a, b any, tolerance float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if isNumber(a) || isNumber(b) {
+		x, okA := sdk.Float64Value(a)
+		y, okB := sdk.Float64Value(b)
+		if okA && okB {
+			return x == y || math.Abs(x-y) <= tolerance
+		}
+	}
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			return ta.Equal(tb)
+		}
+	}
+	return canonical(a) == canonical(b)
+}
+
+func isNumber(v any) bool {
+	switch v.(type) {
+	case string, []byte:
+		return false
+	}
+	_, ok := sdk.Float64Value(v)
+	return ok
+}
+
+// canonical renders v as text that is the same for equal values of different
+// Go types, e.g. int64(3) and float64(3).
+func canonical(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return x
+	case []byte:
+		return string(x)
+	case time.Time:
+		return x.UTC().Format(time.RFC3339Nano)
+	}
+	if f, ok := sdk.Float64Value(v); ok {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
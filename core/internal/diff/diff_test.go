@@ -0,0 +1,114 @@
+package diff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/sdk"
+)
+
+func frame(names []string, rows ...[]any) *sdk.DataFrame {
+	f := &sdk.DataFrame{}
+	for i, n := range names {
+		fd := sdk.Field{Name: n, Values: []any{}}
+		for _, r := range rows {
+			fd.Values = append(fd.Values, r[i])
+		}
+		f.Fields = append(f.Fields, fd)
+	}
+	return f
+}
+
+func TestCompare(t *testing.T) {
+	left := frame([]string{"id", "name", "amount", "legacy"},
+		[]any{int64(1), "alice", "10.50", "x"},
+		[]any{int64(2), "bob", "20.00", "y"},
+		[]any{int64(3), "carol", "30.00", "z"},
+	)
+	right := frame([]string{"id", "name", "amount", "region"},
+		[]any{1.0, "alice", 10.5, "eu"},
+		[]any{2.0, "bobby", 20.0, "us"},
+		[]any{4.0, "dave", 40.0, "eu"},
+	)
+
+	res, err := Compare(left, right, Options{Keys: []string{"id"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "amount"}, res.Columns)
+	assert.Equal(t, []string{"legacy"}, res.LeftOnly)
+	assert.Equal(t, []string{"region"}, res.RightOnly)
+	assert.Equal(t, 3, res.LeftRows)
+	assert.Equal(t, 1, res.Unchanged, "int and float keys match; decimal strings match floats")
+
+	assert.Equal(t, 1, res.Removed)
+	assert.Equal(t, Row{"id": int64(3), "name": "carol", "amount": "30.00", "legacy": "z"}, res.RemovedRows[0])
+	assert.Equal(t, 1, res.Added)
+	assert.Equal(t, "dave", res.AddedRows[0]["name"])
+	require.Equal(t, 1, res.Changed)
+	assert.Equal(t, Row{"id": int64(2)}, res.ChangedRows[0].Key)
+	assert.Equal(t, []ColumnChange{{Column: "name", Left: "bob", Right: "bobby"}}, res.ChangedRows[0].Columns)
+	assert.False(t, res.Truncated)
+}
+
+func TestCompare_CompositeKeyColumnsAndTolerance(t *testing.T) {
+	left := frame([]string{"day", "site", "hits", "avg"},
+		[]any{"2024-01-01", "a", int64(5), 1.0001},
+		[]any{"2024-01-01", "b", int64(7), 2.0},
+	)
+	right := frame([]string{"day", "site", "hits", "avg"},
+		[]any{"2024-01-01", "b", int64(8), 2.0},
+		[]any{"2024-01-01", "a", int64(5), 1.0},
+	)
+
+	res, err := Compare(left, right, Options{Keys: []string{"day", "site"}, Columns: []string{"avg"}, Tolerance: 0.001})
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Unchanged, "hits is not compared")
+
+	_, err = Compare(left, right, Options{Keys: []string{"day", "site"}, Columns: []string{"day"}})
+	assert.ErrorContains(t, err, "non-key column")
+}
+
+func TestCompare_Errors(t *testing.T) {
+	f := frame([]string{"id"}, []any{int64(1)}, []any{1.0})
+	_, err := Compare(f, f, Options{Keys: []string{"id"}})
+	assert.ErrorContains(t, err, "not unique in the left")
+
+	_, err = Compare(f, frame([]string{"other"}), Options{Keys: []string{"other"}})
+	assert.ErrorContains(t, err, `key column "other" is missing from the left`)
+
+	_, err = Compare(f, f, Options{})
+	assert.Error(t, err)
+}
+
+func TestCompare_MaxRowsKeepsCounts(t *testing.T) {
+	left := frame([]string{"id"}, []any{1}, []any{2}, []any{3})
+	right := frame([]string{"id"})
+	res, err := Compare(left, right, Options{Keys: []string{"id"}, MaxRows: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 3, res.Removed)
+	assert.Len(t, res.RemovedRows, 2)
+	assert.True(t, res.Truncated)
+}
+
+func TestEqual(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		a, b any
+		want bool
+	}{
+		{nil, nil, true},
+		{nil, "", false},
+		{int32(3), 3.0, true},
+		{"3.50", 3.5, true},
+		{"007", "7", false},
+		{ts, ts.In(time.FixedZone("x", 3600)), true},
+		{[]byte("abc"), "abc", true},
+		{true, true, true},
+		{true, false, false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, Equal(c.a, c.b, 0), "%#v vs %#v", c.a, c.b)
+	}
+}
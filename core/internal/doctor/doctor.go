@@ -0,0 +1,296 @@
+// Package doctor checks that a server can start with its configuration and
+// explains what to fix when it cannot. It does not apply migrations,
+// generate keys or keep the port.
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"data-voyager/core/internal/buildinfo"
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/settings"
+	"data-voyager/core/internal/store"
+	"data-voyager/sdk"
+)
+
+// Statuses.
+const (
+	StatusOK   = "ok"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+)
+
+// Result is the outcome of one check.
+type Result struct {
+	Check   string `json:"check"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	// Hint says what to do about a warning or failure.
+	Hint string `json:"hint,omitempty"`
+}
+
+// Report is the outcome of every check, in the order they ran.
+type Report struct {
+	Results []Result `json:"results"`
+}
+
+// Failed reports whether any check failed.
+func (r *Report) Failed() bool {
+	return slices.ContainsFunc(r.Results, func(res Result) bool { return res.Status == StatusFail })
+}
+
+// Count returns how many results have status.
+func (r *Report) Count(status string) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *Report) add(check, status, message, hint string) {
+	r.Results = append(r.Results, Result{Check: check, Status: status, Message: message, Hint: hint})
+}
+
+// Doctor runs the checks. Load and Frontend are required.
+type Doctor struct {
+	// Load reads the configuration the server would use.
+	Load func() (*config.ViperConfig, error)
+	// Frontend reports why the UI cannot be served, as core.CheckFrontend.
+	Frontend func(config.FrontendConfig) error
+	// Plugins lists the datasource plugins compiled in; nil means those
+	// registered with sdk.RegisterDatasource.
+	Plugins func() []sdk.DatasourcePlugin
+	// Getenv reads environment variables; nil means os.Getenv.
+	Getenv func(string) string
+}
+
+// Run runs every check. A configuration that does not load stops the rest.
+func (d *Doctor) Run(ctx context.Context) *Report {
+	r := &Report{}
+	cfg, err := d.Load()
+	if err != nil {
+		r.add("config", StatusFail, err.Error(),
+			"fix the setting named above in config.toml or its VOYAGER_* environment variable")
+		return r
+	}
+	if cfg.ConfigFile == "" {
+		r.add("config", StatusWarn, "no config file found, using defaults and environment variables",
+			"create one with `config init`, or run from the directory that holds config.toml")
+	} else {
+		r.add("config", StatusOK, "loaded "+cfg.ConfigFile, "")
+	}
+
+	plugins := d.plugins()
+	d.checkPlugins(r, plugins)
+	d.checkMetadataStore(ctx, r, cfg, plugins)
+	d.checkEncryptionKey(r, cfg)
+	d.checkPort(r, cfg)
+	d.checkFrontend(r, cfg)
+	return r
+}
+
+func (d *Doctor) plugins() []sdk.DatasourcePlugin {
+	if d.Plugins != nil {
+		return d.Plugins()
+	}
+	return sdk.GetDatasourcePlugins()
+}
+
+func (d *Doctor) getenv(key string) string {
+	if d.Getenv != nil {
+		return d.Getenv(key)
+	}
+	return os.Getenv(key)
+}
+
+func (d *Doctor) checkPlugins(r *Report, plugins []sdk.DatasourcePlugin) {
+	if len(plugins) == 0 {
+		r.add("plugins", StatusFail, "no datasource plugins are compiled in",
+			"run `make generate` before building so the extensions are linked in")
+		return
+	}
+	var names, unsupported []string
+	for _, p := range plugins {
+		names = append(names, string(p.GetType()))
+		if v := sdk.PluginVersion(p); !v.SupportsServer(buildinfo.Version) {
+			unsupported = append(unsupported, fmt.Sprintf("%s %s (needs server %s–%s)",
+				p.GetType(), v.Version, v.MinServerVersion, v.MaxServerVersion))
+		}
+	}
+	slices.Sort(names)
+	if len(unsupported) > 0 {
+		r.add("plugins", StatusWarn, "not built for server "+buildinfo.Version+": "+strings.Join(unsupported, ", "),
+			"rebuild with plugin versions that support this release")
+		return
+	}
+	r.add("plugins", StatusOK, fmt.Sprintf("%d loaded: %s", len(names), strings.Join(names, ", ")), "")
+}
+
+func (d *Doctor) checkMetadataStore(ctx context.Context, r *Report, cfg *config.ViperConfig, plugins []sdk.DatasourcePlugin) {
+	const check = "metadata store"
+	mc := cfg.MetadataStore
+	switch mc.Type {
+	case "sqlite", "sqlite3", "postgres", "postgresql", "mysql":
+	default:
+		ms, err := store.OpenMetadataStore(mc)
+		if err != nil {
+			r.add(check, StatusFail, err.Error(), "check the [metadata_store] section")
+			return
+		}
+		defer func() { _ = ms.Close() }()
+		if err := ms.Repos().Connection.Health(ctx); err != nil {
+			r.add(check, StatusFail, err.Error(), "check the [metadata_store] section")
+			return
+		}
+		r.add(check, StatusOK, mc.Type+" is reachable", "")
+		d.checkDatasources(ctx, r, ms.Repos().Connection, plugins)
+		return
+	}
+
+	if mc.Type == "sqlite" || mc.Type == "sqlite3" {
+		if dir := filepath.Dir(mc.SQLite.Path); !exists(dir) {
+			r.add(check, StatusFail, "directory "+dir+" for "+mc.SQLite.Path+" does not exist",
+				"create it, or point metadata_store.sqlite.path somewhere writable")
+			return
+		}
+		if !exists(mc.SQLite.Path) {
+			r.add(check, StatusOK, mc.SQLite.Path+" will be created on first start", "")
+			return
+		}
+	}
+	mc.MigrateOnStart = false
+	db, err := store.Open(mc)
+	if err != nil {
+		r.add(check, StatusFail, err.Error(),
+			"check that the database is running and the [metadata_store] host, port and credentials are right")
+		return
+	}
+	defer func() { _ = db.Close() }()
+
+	current, latest, err := store.MigrationStatus(db, mc.Type)
+	switch {
+	case err != nil:
+		r.add(check, StatusFail, "cannot read the schema version: "+err.Error(),
+			"check that the database user may read the goose_db_version table")
+		return
+	case current < latest && cfg.MetadataStore.MigrateOnStart:
+		r.add(check, StatusOK, fmt.Sprintf("%s is reachable; schema %d will be migrated to %d on start", mc.Type, current, latest), "")
+		return
+	case current < latest:
+		r.add(check, StatusFail, fmt.Sprintf("schema is at version %d, this release needs %d", current, latest),
+			"set metadata_store.migrate_on_start = true, or run `data-voyager migrate up` before starting")
+		return
+	case current > latest:
+		r.add(check, StatusWarn, fmt.Sprintf("schema version %d is newer than this release knows (%d)", current, latest),
+			"a newer server migrated this database; run that version or restore a backup")
+	default:
+		r.add(check, StatusOK, fmt.Sprintf("%s is reachable; schema is up to date (version %d)", mc.Type, current), "")
+	}
+	repos, err := store.NewRepos(db, mc)
+	if err != nil {
+		return
+	}
+	d.checkDatasources(ctx, r, repos.Connection, plugins)
+}
+
+// checkDatasources warns about datasources no compiled-in plugin can open.
+func (d *Doctor) checkDatasources(ctx context.Context, r *Report, conns connection.Repository, plugins []sdk.DatasourcePlugin) {
+	list, err := conns.List(ctx, connection.Filter{})
+	if err != nil {
+		r.add("datasources", StatusWarn, "cannot list datasources: "+err.Error(), "")
+		return
+	}
+	have := map[sdk.DataSourceType]bool{}
+	for _, p := range plugins {
+		have[p.GetType()] = true
+	}
+	var missing []string
+	for _, c := range list {
+		if !have[c.Type] {
+			missing = append(missing, fmt.Sprintf("%s (%s)", c.Name, c.Type))
+		}
+	}
+	if len(missing) > 0 {
+		r.add("datasources", StatusWarn, "no plugin for "+strings.Join(missing, ", "),
+			"build with those plugins, or remove the datasources")
+		return
+	}
+	r.add("datasources", StatusOK, fmt.Sprintf("%d configured, all have plugins", len(list)), "")
+}
+
+// checkEncryptionKey mirrors settings.ResolveEncryptionKey without
+// creating a key file.
+func (d *Doctor) checkEncryptionKey(r *Report, cfg *config.ViperConfig) {
+	const check = "encryption key"
+	if raw := d.getenv("VOYAGER_ENCRYPTION_KEY"); raw != "" {
+		if _, err := settings.ParseEncryptionKey(raw); err != nil {
+			r.add(check, StatusFail, "VOYAGER_ENCRYPTION_KEY is invalid: "+err.Error(),
+				"set it to 32 random bytes, hex or base64 encoded, e.g. `openssl rand -hex 32`")
+			return
+		}
+		r.add(check, StatusOK, "from VOYAGER_ENCRYPTION_KEY", "")
+		return
+	}
+	mc := cfg.MetadataStore
+	if mc.Type != "sqlite" && mc.Type != "sqlite3" {
+		r.add(check, StatusWarn, "VOYAGER_ENCRYPTION_KEY is not set; secrets will be stored in plaintext",
+			"set VOYAGER_ENCRYPTION_KEY, e.g. to the output of `openssl rand -hex 32`")
+		return
+	}
+	keyFile := filepath.Join(filepath.Dir(mc.SQLite.Path), ".encryption.key")
+	raw, err := os.ReadFile(keyFile)
+	if errors.Is(err, os.ErrNotExist) {
+		r.add(check, StatusOK, "will be generated at "+keyFile+" on first start", "")
+		return
+	}
+	if err != nil {
+		r.add(check, StatusFail, err.Error(), "make "+keyFile+" readable by the server")
+		return
+	}
+	if _, err := settings.ParseEncryptionKey(string(raw)); err != nil {
+		r.add(check, StatusFail, keyFile+" is invalid: "+err.Error(),
+			"restore the original key; stored secrets cannot be read without it")
+		return
+	}
+	r.add(check, StatusOK, "from "+keyFile, "")
+}
+
+func (d *Doctor) checkPort(r *Report, cfg *config.ViperConfig) {
+	addr := net.JoinHostPort(cfg.Server.Host, fmt.Sprint(cfg.Server.Port))
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		r.add("port", StatusFail, "cannot listen on "+addr+": "+err.Error(),
+			"stop whatever uses the port (perhaps a running server), or change server.port / --port")
+		return
+	}
+	_ = ln.Close()
+	r.add("port", StatusOK, addr+" is free", "")
+}
+
+func (d *Doctor) checkFrontend(r *Report, cfg *config.ViperConfig) {
+	if err := d.Frontend(cfg.Frontend); err != nil {
+		r.add("frontend", StatusFail, err.Error(), "")
+		return
+	}
+	if cfg.Frontend.Mode == config.FrontendNone {
+		r.add("frontend", StatusOK, "disabled; serving the API only", "")
+		return
+	}
+	r.add("frontend", StatusOK, "assets found", "")
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
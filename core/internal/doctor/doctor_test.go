@@ -0,0 +1,112 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/sdk"
+)
+
+type stubPlugin struct{ sdk.DatasourcePlugin }
+
+func (stubPlugin) GetType() sdk.DataSourceType { return "postgres" }
+
+func testDoctor(cfg *config.ViperConfig, env map[string]string) *Doctor {
+	return &Doctor{
+		Load:     func() (*config.ViperConfig, error) { return cfg, nil },
+		Frontend: func(config.FrontendConfig) error { return nil },
+		Plugins:  func() []sdk.DatasourcePlugin { return []sdk.DatasourcePlugin{stubPlugin{}} },
+		Getenv:   func(k string) string { return env[k] },
+	}
+}
+
+func byCheck(r *Report) map[string]Result {
+	out := map[string]Result{}
+	for _, res := range r.Results {
+		out[res.Check] = res
+	}
+	return out
+}
+
+func sqliteConfig(t *testing.T) *config.ViperConfig {
+	cfg, err := config.Defaults()
+	require.NoError(t, err)
+	cfg.ConfigFile = "config.toml"
+	cfg.MetadataStore.SQLite.Path = filepath.Join(t.TempDir(), "voyager.db")
+	cfg.Server.Host, cfg.Server.Port = "127.0.0.1", 0
+	return cfg
+}
+
+func TestRun_Healthy(t *testing.T) {
+	cfg := sqliteConfig(t)
+	r := testDoctor(cfg, nil).Run(context.Background())
+	assert.False(t, r.Failed(), "%+v", r.Results)
+
+	got := byCheck(r)
+	assert.Equal(t, "loaded config.toml", got["config"].Message)
+	assert.Equal(t, "1 loaded: postgres", got["plugins"].Message)
+	assert.Contains(t, got["metadata store"].Message, "will be created on first start")
+	assert.Contains(t, got["encryption key"].Message, "will be generated")
+	_, err := os.Stat(cfg.MetadataStore.SQLite.Path)
+	assert.True(t, os.IsNotExist(err), "the database is not created")
+}
+
+func TestRun_Problems(t *testing.T) {
+	cfg := sqliteConfig(t)
+	keyFile := filepath.Join(filepath.Dir(cfg.MetadataStore.SQLite.Path), ".encryption.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("short"), 0o600))
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+	cfg.Server.Port = ln.Addr().(*net.TCPAddr).Port
+
+	d := testDoctor(cfg, nil)
+	d.Frontend = func(config.FrontendConfig) error { return errors.New("no index.html") }
+	d.Plugins = func() []sdk.DatasourcePlugin { return nil }
+	r := d.Run(context.Background())
+	require.True(t, r.Failed())
+	assert.Equal(t, 4, r.Count(StatusFail))
+
+	got := byCheck(r)
+	assert.Equal(t, StatusFail, got["plugins"].Status)
+	assert.Equal(t, StatusFail, got["encryption key"].Status)
+	assert.Equal(t, StatusFail, got["port"].Status)
+	assert.NotEmpty(t, got["port"].Hint)
+	assert.Equal(t, StatusFail, got["frontend"].Status)
+}
+
+func TestRun_PendingMigrations(t *testing.T) {
+	cfg := sqliteConfig(t)
+	require.NoError(t, os.WriteFile(cfg.MetadataStore.SQLite.Path, nil, 0o600))
+
+	r := testDoctor(cfg, nil).Run(context.Background())
+	assert.Contains(t, byCheck(r)["metadata store"].Message, "will be migrated")
+
+	cfg.MetadataStore.MigrateOnStart = false
+	r = testDoctor(cfg, nil).Run(context.Background())
+	res := byCheck(r)["metadata store"]
+	assert.Equal(t, StatusFail, res.Status)
+	assert.Contains(t, res.Hint, "migrate_on_start")
+}
+
+func TestRun_ConfigError(t *testing.T) {
+	d := testDoctor(nil, nil)
+	d.Load = func() (*config.ViperConfig, error) { return nil, errors.New("invalid server port: 0") }
+	r := d.Run(context.Background())
+	require.Len(t, r.Results, 1)
+	assert.Equal(t, StatusFail, r.Results[0].Status)
+}
+
+func TestRun_EncryptionKeyFromEnv(t *testing.T) {
+	cfg := sqliteConfig(t)
+	r := testDoctor(cfg, map[string]string{"VOYAGER_ENCRYPTION_KEY": "nope"}).Run(context.Background())
+	assert.Equal(t, StatusFail, byCheck(r)["encryption key"].Status)
+}
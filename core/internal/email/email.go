@@ -0,0 +1,120 @@
+// Package email sends outbound email through the SMTP relay in the server
+// configuration, either as given or rendered from named templates.
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	netmail "net/mail"
+	"time"
+
+	"data-voyager/core/internal/config"
+)
+
+var (
+	// ErrDisabled is returned when no SMTP host is configured.
+	ErrDisabled = errors.New("email is not configured")
+	// ErrInvalid wraps unusable messages, e.g. a bad recipient.
+	ErrInvalid = errors.New("invalid email")
+)
+
+const defaultTimeout = 30 * time.Second
+
+// Message is one email. HTML is optional; when set the message carries both
+// parts and clients pick one.
+type Message struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer sends email with the server's SMTP settings. A Mailer built from a
+// configuration without a host is valid but refuses to send.
+type Mailer struct {
+	cfg       config.EmailConfig
+	from      *netmail.Address
+	publicURL string
+	templates *templates
+	now       func() time.Time
+}
+
+// New creates a Mailer. publicURL is offered to templates for links.
+func New(cfg config.EmailConfig, publicURL string) (*Mailer, error) {
+	m := &Mailer{cfg: cfg, publicURL: publicURL, now: time.Now}
+	if cfg.Host != "" {
+		from, err := netmail.ParseAddress(cfg.From)
+		if err != nil {
+			return nil, fmt.Errorf("email.from: %w", err)
+		}
+		m.from = from
+	}
+	t, err := loadTemplates(cfg.TemplatesDir)
+	if err != nil {
+		return nil, err
+	}
+	m.templates = t
+	return m, nil
+}
+
+// Enabled reports whether an SMTP host is configured.
+func (m *Mailer) Enabled() bool { return m.cfg.Host != "" }
+
+// Templates lists the template names SendTemplate accepts.
+func (m *Mailer) Templates() []string { return m.templates.names() }
+
+// Send delivers msg to all its recipients in one transaction.
+func (m *Mailer) Send(ctx context.Context, msg Message) error {
+	if !m.Enabled() {
+		return ErrDisabled
+	}
+	if len(msg.To) == 0 {
+		return fmt.Errorf("%w: no recipients", ErrInvalid)
+	}
+	to := make([]string, len(msg.To))
+	for i, addr := range msg.To {
+		a, err := netmail.ParseAddress(addr)
+		if err != nil {
+			return fmt.Errorf("%w: recipient %q: %v", ErrInvalid, addr, err)
+		}
+		to[i] = a.Address
+	}
+	data, err := m.build(msg)
+	if err != nil {
+		return err
+	}
+	timeout := time.Duration(m.cfg.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return m.deliver(ctx, to, data)
+}
+
+// SendText sends a plain-text message. It lets notification channels send
+// through the server's settings.
+func (m *Mailer) SendText(ctx context.Context, to []string, subject, body string) error {
+	return m.Send(ctx, Message{To: to, Subject: subject, Text: body})
+}
+
+// SendTemplate renders the named template with data and sends it. Templates
+// also see app_name and public_url.
+func (m *Mailer) SendTemplate(ctx context.Context, name string, to []string, data map[string]any) error {
+	msg, err := m.Render(name, data)
+	if err != nil {
+		return err
+	}
+	msg.To = to
+	return m.Send(ctx, msg)
+}
+
+// Render renders the named template without sending it.
+func (m *Mailer) Render(name string, data map[string]any) (Message, error) {
+	tctx := map[string]any{"app_name": "Data Voyager", "public_url": m.publicURL}
+	for k, v := range data {
+		tctx[k] = v
+	}
+	return m.templates.render(name, tctx)
+}
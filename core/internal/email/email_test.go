@@ -0,0 +1,193 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+)
+
+// smtpServer is a minimal SMTP server that accepts every message and keeps
+// the envelope and data of the last one.
+type smtpServer struct {
+	ln   net.Listener
+	port int
+
+	mu   sync.Mutex
+	from string
+	rcpt []string
+	data string
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &smtpServer{ln: ln, port: ln.Addr().(*net.TCPAddr).Port}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+	reply("220 test ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimSpace(line)
+		switch verb := strings.ToUpper(strings.Fields(cmd + " x")[0]); verb {
+		case "EHLO", "HELO":
+			reply("250 test")
+		case "MAIL":
+			s.mu.Lock()
+			s.from, s.rcpt = strings.Trim(cmd[len("MAIL FROM:"):], "<>"), nil
+			s.mu.Unlock()
+			reply("250 ok")
+		case "RCPT":
+			s.mu.Lock()
+			s.rcpt = append(s.rcpt, strings.Trim(cmd[len("RCPT TO:"):], "<>"))
+			s.mu.Unlock()
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.mu.Lock()
+			s.data = data.String()
+			s.mu.Unlock()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func newTestMailer(t *testing.T, srv *smtpServer, templatesDir string) *Mailer {
+	t.Helper()
+	m, err := New(config.EmailConfig{
+		Host:         "127.0.0.1",
+		Port:         srv.port,
+		From:         "Data Voyager <voyager@example.com>",
+		TLS:          "none",
+		TemplatesDir: templatesDir,
+	}, "https://voyager.example.com")
+	require.NoError(t, err)
+	return m
+}
+
+func TestSend_PlainText(t *testing.T) {
+	srv := newSMTPServer(t)
+	m := newTestMailer(t, srv, "")
+
+	err := m.SendText(context.Background(), []string{"Ann <ann@example.com>", "bob@example.com"}, "Disk almost full", "Volume /data is at 93%.\nTidy up.")
+	require.NoError(t, err)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	assert.Equal(t, "voyager@example.com", srv.from)
+	assert.Equal(t, []string{"ann@example.com", "bob@example.com"}, srv.rcpt)
+
+	msg, err := mail.ReadMessage(strings.NewReader(srv.data))
+	require.NoError(t, err)
+	assert.Equal(t, "Disk almost full", msg.Header.Get("Subject"))
+	assert.Equal(t, `"Data Voyager" <voyager@example.com>`, msg.Header.Get("From"))
+	assert.Equal(t, "text/plain; charset=UTF-8", msg.Header.Get("Content-Type"))
+	assert.Contains(t, msg.Header.Get("Message-ID"), "@example.com>")
+	body, _ := io.ReadAll(msg.Body)
+	assert.Equal(t, "Volume /data is at 93%.\r\nTidy up.", strings.TrimSpace(string(body)))
+}
+
+func TestSendTemplate_Multipart(t *testing.T) {
+	srv := newSMTPServer(t)
+	m := newTestMailer(t, srv, "")
+
+	err := m.SendTemplate(context.Background(), "invitation", []string{"ann@example.com"}, map[string]any{
+		"inviter": "Bob <admin>",
+		"link":    "https://voyager.example.com/invite/abc",
+	})
+	require.NoError(t, err)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	msg, err := mail.ReadMessage(strings.NewReader(srv.data))
+	require.NoError(t, err)
+	assert.Equal(t, "Bob <admin> invited you to Data Voyager", msg.Header.Get("Subject"))
+	assert.True(t, strings.HasPrefix(msg.Header.Get("Content-Type"), "multipart/alternative; boundary="))
+	assert.Contains(t, srv.data, "Bob &lt;admin&gt; invited you", "HTML part is escaped")
+	assert.Contains(t, srv.data, "Bob <admin> invited you", "text part is not")
+}
+
+func TestRender_OverrideDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "test"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test", "subject.txt"), []byte("Custom {{ app_name }}\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test", "body.txt"), []byte("hi {{ requested_by }}"), 0o644))
+
+	m, err := New(config.EmailConfig{TemplatesDir: dir}, "")
+	require.NoError(t, err)
+	msg, err := m.Render("test", map[string]any{"requested_by": "ann"})
+	require.NoError(t, err)
+	assert.Equal(t, "Custom Data Voyager", msg.Subject)
+	assert.Equal(t, "hi ann", msg.Text)
+	assert.Equal(t, []string{"invitation", "password_reset", "report", "test"}, m.Templates())
+
+	_, err = m.Render("missing", nil)
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestSend_Refuses(t *testing.T) {
+	off, err := New(config.EmailConfig{}, "")
+	require.NoError(t, err)
+	assert.False(t, off.Enabled())
+	assert.ErrorIs(t, off.SendText(context.Background(), []string{"ann@example.com"}, "s", "b"), ErrDisabled)
+
+	srv := newSMTPServer(t)
+	m := newTestMailer(t, srv, "")
+	assert.ErrorIs(t, m.SendText(context.Background(), nil, "s", "b"), ErrInvalid)
+	assert.ErrorIs(t, m.SendText(context.Background(), []string{"not an address"}, "s", "b"), ErrInvalid)
+
+	// STARTTLS is required by default, and the test server does not offer it.
+	strict, err := New(config.EmailConfig{Host: "127.0.0.1", Port: srv.port, From: "voyager@example.com"}, "")
+	require.NoError(t, err)
+	err = strict.SendText(context.Background(), []string{"ann@example.com"}, "s", "b")
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, ErrInvalid))
+	assert.Contains(t, err.Error(), "STARTTLS")
+}
@@ -0,0 +1,68 @@
+package email
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/user"
+)
+
+// Handler serves the email admin endpoints.
+type Handler struct {
+	mailer *Mailer
+}
+
+// NewHandler creates an email HTTP handler.
+func NewHandler(mailer *Mailer) *Handler {
+	return &Handler{mailer: mailer}
+}
+
+type testRequest struct {
+	To string `json:"to" binding:"required"`
+}
+
+// Status handles GET /admin/email
+func (h *Handler) Status(c *gin.Context) {
+	status := gin.H{"enabled": h.mailer.Enabled(), "templates": h.mailer.Templates()}
+	if h.mailer.Enabled() {
+		status["host"] = h.mailer.cfg.Host
+		status["from"] = h.mailer.from.String()
+	}
+	c.JSON(http.StatusOK, gin.H{"data": status})
+}
+
+// Test handles POST /admin/email/test, sending the test template to one
+// address.
+func (h *Handler) Test(c *gin.Context) {
+	var req testRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	data := map[string]any{}
+	if id := identity.FromContext(c.Request.Context()); id != nil {
+		data["requested_by"] = id.Username
+	}
+	err := h.mailer.SendTemplate(c.Request.Context(), "test", []string{req.To}, data)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{"ok": true}})
+	case errors.Is(err, ErrDisabled):
+		c.JSON(http.StatusConflict, gin.H{"error": i18n.T(c, "email is not configured")})
+	case errors.Is(err, ErrInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	}
+}
+
+// RegisterRoutes wires the handler onto r.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	admin := r.Group("/admin/email", user.RequireAdmin)
+	admin.GET("", h.Status)
+	admin.POST("/test", h.Test)
+}
@@ -0,0 +1,22 @@
+package email
+
+import (
+	"github.com/gin-gonic/gin"
+
+	apploader "data-voyager/core/internal/app"
+)
+
+type loader struct {
+	handler *Handler
+}
+
+// NewLoader wires the email admin endpoints.
+func NewLoader(mailer *Mailer) apploader.Loader {
+	return &loader{handler: NewHandler(mailer)}
+}
+
+func (l *loader) Load() error { return nil }
+
+func (l *loader) RegisterRoutes(r *gin.RouterGroup) {
+	RegisterRoutes(r, l.handler)
+}
@@ -0,0 +1,113 @@
+package email
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/flosch/pongo2/v6"
+)
+
+//go:embed templates
+var builtin embed.FS
+
+// Files making up a template; body.html is optional.
+const (
+	subjectFile = "subject.txt"
+	textFile    = "body.txt"
+	htmlFile    = "body.html"
+)
+
+type template struct {
+	subject, text, html *pongo2.Template
+}
+
+// templates are the built-in templates, each possibly replaced by one of the
+// same name in the override directory, which may also add new ones.
+type templates struct {
+	byName map[string]*template
+}
+
+func loadTemplates(dir string) (*templates, error) {
+	sub, err := fs.Sub(builtin, "templates")
+	if err != nil {
+		return nil, err
+	}
+	t := &templates{byName: map[string]*template{}}
+	if err := t.load(sub); err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		if err := t.load(os.DirFS(dir)); err != nil {
+			return nil, fmt.Errorf("email.templates_dir: %w", err)
+		}
+	}
+	return t, nil
+}
+
+// load compiles every template directory in fsys, replacing any already
+// loaded under the same name.
+func (t *templates) load(fsys fs.FS) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		tpl := &template{}
+		for file, dst := range map[string]**pongo2.Template{subjectFile: &tpl.subject, textFile: &tpl.text, htmlFile: &tpl.html} {
+			src, err := fs.ReadFile(fsys, path.Join(e.Name(), file))
+			if errors.Is(err, fs.ErrNotExist) && file == htmlFile {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if *dst, err = pongo2.FromString(string(src)); err != nil {
+				return fmt.Errorf("%s/%s: %w", e.Name(), file, err)
+			}
+		}
+		t.byName[e.Name()] = tpl
+	}
+	return nil
+}
+
+func (t *templates) names() []string {
+	names := make([]string, 0, len(t.byName))
+	for name := range t.byName {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (t *templates) render(name string, data map[string]any) (Message, error) {
+	tpl, ok := t.byName[name]
+	if !ok {
+		return Message{}, fmt.Errorf("%w: unknown template %q", ErrInvalid, name)
+	}
+	tctx := pongo2.Context(data)
+	var msg Message
+	subject, err := tpl.subject.Execute(tctx)
+	if err != nil {
+		return Message{}, fmt.Errorf("template %s: %w", name, err)
+	}
+	// A subject is one line however the template is laid out.
+	msg.Subject = strings.Join(strings.Fields(subject), " ")
+	if msg.Text, err = tpl.text.Execute(tctx); err != nil {
+		return Message{}, fmt.Errorf("template %s: %w", name, err)
+	}
+	if tpl.html != nil {
+		if msg.HTML, err = tpl.html.Execute(tctx); err != nil {
+			return Message{}, fmt.Errorf("template %s: %w", name, err)
+		}
+	}
+	return msg, nil
+}
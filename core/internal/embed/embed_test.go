@@ -0,0 +1,247 @@
+package embed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/identity"
+	"data-voyager/sdk"
+)
+
+type stubConns struct {
+	connection.Repository
+	conn *connection.Connection
+}
+
+func (s *stubConns) GetByID(_ context.Context, id string) (*connection.Connection, error) {
+	if s.conn == nil || s.conn.ID != id {
+		return nil, fmt.Errorf("connection %s not found", id)
+	}
+	return s.conn, nil
+}
+
+func newTestService() *Service {
+	conns := &stubConns{conn: &connection.Connection{ID: "ds1", Name: "warehouse", Type: "mock"}}
+	return NewService(conns, datasource.NewRegistry(), []byte("0123456789abcdef0123456789abcdef"))
+}
+
+type stubConfig struct{}
+
+func (stubConfig) Validate() error             { return nil }
+func (stubConfig) GetConnectionString() string { return "" }
+
+// stubPlugin answers every query with ids 1..rows, whatever its LIMIT, and
+// records the queries it ran.
+type stubPlugin struct {
+	sdk.DatasourcePlugin
+	rows      int
+	queries   []string
+	deadlines []bool
+}
+
+func (p *stubPlugin) GetType() sdk.DataSourceType { return "mock" }
+func (p *stubPlugin) ParseConfig(json.RawMessage) (sdk.ConnectionConfig, error) {
+	return stubConfig{}, nil
+}
+func (p *stubPlugin) Connect(context.Context, sdk.ConnectionConfig) (sdk.Connection, error) {
+	return &stubConn{p: p}, nil
+}
+
+type stubConn struct {
+	sdk.Connection
+	p *stubPlugin
+}
+
+func (c *stubConn) Query(ctx context.Context, q string, _ ...any) (*sdk.QueryResult, error) {
+	c.p.queries = append(c.p.queries, q)
+	_, ok := ctx.Deadline()
+	c.p.deadlines = append(c.p.deadlines, ok)
+	ids := make([]any, c.p.rows)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	return &sdk.QueryResult{
+		Frames: []*sdk.DataFrame{{Fields: []sdk.Field{{Name: "id", Kind: sdk.FieldKindNumber, Values: ids}}}},
+		Stats:  sdk.QueryStats{RowsReturned: int64(len(ids))},
+	}, nil
+}
+
+func (c *stubConn) Close() error { return nil }
+
+func TestIssueAndVerify(t *testing.T) {
+	svc := newTestService()
+	token, claims, err := svc.Issue(context.Background(), IssueRequest{
+		DatasourceID:   "ds1",
+		Query:          "SELECT 1",
+		AllowedDomains: []string{" Wiki.Example.com "},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"wiki.example.com"}, claims.AllowedDomains)
+	assert.Equal(t, defaultLimit, claims.Limit)
+
+	got, err := svc.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT 1", got.Query)
+
+	// Any change to the payload invalidates the signature.
+	body, sig, _ := strings.Cut(token, ".")
+	_, err = svc.Verify(body[:len(body)-2] + "AA." + sig)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// Tokens stop working after expiry.
+	svc.now = func() time.Time { return time.Now().Add(defaultTTL + time.Minute) }
+	_, err = svc.Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestIssue_Validation(t *testing.T) {
+	svc := newTestService()
+	ctx := context.Background()
+
+	_, _, err := svc.Issue(ctx, IssueRequest{DatasourceID: "missing", Query: "SELECT 1"})
+	assert.Error(t, err)
+	_, _, err = svc.Issue(ctx, IssueRequest{DatasourceID: "ds1", Query: "SELECT 1", TTL: 2 * maxTTL})
+	assert.Error(t, err)
+	_, _, err = svc.Issue(ctx, IssueRequest{DatasourceID: "ds1", Query: "SELECT 1", AllowedDomains: []string{"https://wiki.example.com"}})
+	assert.Error(t, err)
+
+	disabled := NewService(&stubConns{}, datasource.NewRegistry(), nil)
+	_, _, err = disabled.Issue(ctx, IssueRequest{DatasourceID: "ds1", Query: "SELECT 1"})
+	assert.ErrorIs(t, err, ErrDisabled)
+}
+
+func TestIssue_NeedsAVisibleDatasource(t *testing.T) {
+	svc := newTestService()
+	restricted := identity.With(context.Background(), &identity.Identity{Role: identity.RoleViewer, Datasources: []string{"ds2"}})
+	_, _, err := svc.Issue(restricted, IssueRequest{DatasourceID: "ds1", Query: "SELECT 1"})
+	assert.ErrorContains(t, err, "datasource not found")
+
+	body := strings.NewReader(`{"datasource_uid":"ds1","query":"SELECT 1"}`)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/embeds", body)
+	c.Request = c.Request.WithContext(identity.With(c.Request.Context(), &identity.Identity{Role: "guest"}))
+	NewHandler(svc).Create(c)
+	assert.Equal(t, http.StatusForbidden, w.Code, "issuing needs datasource:query")
+}
+
+func TestIssue_LiteralPolicy(t *testing.T) {
+	svc := newTestService().WithLiteralPolicy(connection.NewLiteralPolicy(connection.ParamPolicyReject))
+	editor := identity.With(context.Background(), &identity.Identity{Role: identity.RoleEditor})
+	_, _, err := svc.Issue(editor, IssueRequest{DatasourceID: "ds1", Query: "SELECT * FROM events WHERE kind = 'click'"})
+	assert.ErrorIs(t, err, connection.ErrRawLiterals)
+	_, _, err = svc.Issue(editor, IssueRequest{DatasourceID: "ds1", Query: "SELECT * FROM events WHERE kind = {{ kind }}"})
+	assert.NoError(t, err)
+}
+
+func TestRun_Deadline(t *testing.T) {
+	plugin := &stubPlugin{rows: 1}
+	registry := datasource.NewRegistry()
+	registry.Register(plugin)
+	conn := &connection.Connection{ID: "ds1", Name: "warehouse", Type: "mock"}
+	svc := NewService(&stubConns{conn: conn}, registry, []byte("0123456789abcdef0123456789abcdef"))
+	claims := &Claims{DatasourceID: "ds1", Query: "SELECT 1", Limit: 10}
+
+	_, err := svc.Run(context.Background(), claims)
+	require.NoError(t, err)
+	conn.QueryTimeout = 5
+	_, err = svc.Run(context.Background(), claims)
+	require.NoError(t, err)
+	svc.WithQueryTimeout(time.Minute)
+	conn.QueryTimeout = 0
+	_, err = svc.Run(context.Background(), claims)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{false, true, true}, plugin.deadlines)
+}
+
+func TestIssue_RejectsWrites(t *testing.T) {
+	svc := newTestService()
+	for _, q := range []string{
+		"DELETE FROM events",
+		"WITH gone AS (DELETE FROM events RETURNING id) SELECT * FROM gone",
+		"SELECT * INTO backup FROM events",
+	} {
+		_, _, err := svc.Issue(context.Background(), IssueRequest{DatasourceID: "ds1", Query: q})
+		assert.ErrorIs(t, err, ErrNotReadOnly, q)
+	}
+}
+
+func TestRun_ReadOnlyAndRowCap(t *testing.T) {
+	plugin := &stubPlugin{rows: 5}
+	registry := datasource.NewRegistry()
+	registry.Register(plugin)
+	conns := &stubConns{conn: &connection.Connection{ID: "ds1", Name: "warehouse", Type: "mock"}}
+	svc := NewService(conns, registry, []byte("0123456789abcdef0123456789abcdef"))
+	ctx := context.Background()
+
+	_, claims, err := svc.Issue(ctx, IssueRequest{DatasourceID: "ds1", Query: "SELECT id FROM events", Limit: 2})
+	require.NoError(t, err)
+	result, err := svc.Run(ctx, claims)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM events\nLIMIT 2", plugin.queries[0], "the token's limit is added to the query")
+	assert.Len(t, result.Frames[0].Fields[0].Values, 2)
+
+	// A LIMIT of the query's own cannot return more rows than the token.
+	_, claims, err = svc.Issue(ctx, IssueRequest{DatasourceID: "ds1", Query: "SELECT id FROM events LIMIT 100", Limit: 3})
+	require.NoError(t, err)
+	result, err = svc.Run(ctx, claims)
+	require.NoError(t, err)
+	assert.Equal(t, []any{int64(1), int64(2), int64(3)}, result.Frames[0].Fields[0].Values)
+	assert.EqualValues(t, 3, result.Stats.RowsReturned)
+
+	// Tokens are checked again when run, e.g. ones minted before the check.
+	plugin.queries = nil
+	_, err = svc.Run(ctx, &Claims{DatasourceID: "ds1", Query: "UPDATE events SET id = 0", Limit: 10})
+	assert.ErrorIs(t, err, ErrNotReadOnly)
+	assert.Empty(t, plugin.queries, "a write never reaches the datasource")
+}
+
+func TestFrameAllowed(t *testing.T) {
+	domains := []string{"wiki.example.com", "*.corp.example"}
+	cases := map[string]bool{
+		"https://wiki.example.com/page":    true,
+		"https://tools.corp.example":       true,
+		"https://corp.example":             false,
+		"https://evil-wiki.example.com":    false,
+		"https://wiki.example.com.evil.io": false,
+		"":                                 false,
+	}
+	for origin, want := range cases {
+		assert.Equal(t, want, FrameAllowed(domains, origin), origin)
+	}
+	assert.True(t, FrameAllowed(nil, ""))
+}
+
+func TestPanel_RejectsDisallowedOrigin(t *testing.T) {
+	svc := newTestService()
+	token, _, err := svc.Issue(context.Background(), IssueRequest{
+		DatasourceID: "ds1", Query: "SELECT 1", AllowedDomains: []string{"wiki.example.com"},
+	})
+	require.NoError(t, err)
+
+	r := gin.New()
+	RegisterPublicRoutes(r, NewHandler(svc))
+
+	req := httptest.NewRequest(http.MethodGet, "/embed/panel/"+token, nil)
+	req.Header.Set("Referer", "https://elsewhere.example.org/")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/embed/panel/not-a-token", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
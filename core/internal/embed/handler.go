@@ -0,0 +1,241 @@
+package embed
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/authz"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+	"data-voyager/sdk"
+)
+
+// Handler serves embed token issuance and the public panel endpoint.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates an embed HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// ─── wire types ───────────────────────────────────────────────────────────────
+
+type createEmbedRequest struct {
+	DatasourceUID  string         `json:"datasource_uid" binding:"required"`
+	Query          string         `json:"query"          binding:"required"`
+	Variables      map[string]any `json:"variables"`
+	From           string         `json:"from"`
+	To             string         `json:"to"`
+	Limit          int            `json:"limit"`
+	Title          string         `json:"title"`
+	AllowedDomains []string       `json:"allowed_domains"`
+	ExpiresAt      *time.Time     `json:"expires_at"`
+}
+
+type embedResponse struct {
+	Token          string    `json:"token"`
+	URL            string    `json:"url"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	AllowedDomains []string  `json:"allowed_domains"`
+}
+
+// ─── handlers ─────────────────────────────────────────────────────────────────
+
+// Create handles POST /embeds
+func (h *Handler) Create(c *gin.Context) {
+	if !identity.FromContext(c.Request.Context()).Can(identity.PermDatasourceQuery) {
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "permission denied")})
+		return
+	}
+	var body createEmbedRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "invalid request body")})
+		return
+	}
+	req := IssueRequest{
+		DatasourceID:   body.DatasourceUID,
+		Query:          body.Query,
+		Variables:      body.Variables,
+		From:           body.From,
+		To:             body.To,
+		Limit:          body.Limit,
+		Title:          body.Title,
+		AllowedDomains: body.AllowedDomains,
+	}
+	if body.ExpiresAt != nil {
+		req.TTL = time.Until(*body.ExpiresAt)
+		if req.TTL <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "expires_at must be in the future")})
+			return
+		}
+	}
+	token, claims, err := h.svc.Issue(c.Request.Context(), req)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, ErrDisabled):
+			status = http.StatusServiceUnavailable
+		case errors.Is(err, connection.ErrNetworkPolicy), errors.Is(err, authz.ErrDenied):
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	domains := claims.AllowedDomains
+	if domains == nil {
+		domains = []string{}
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": embedResponse{
+		Token:          token,
+		URL:            "/embed/panel/" + token,
+		ExpiresAt:      time.Unix(claims.ExpiresAt, 0).UTC(),
+		AllowedDomains: domains,
+	}})
+}
+
+// Panel handles GET /embed/panel/:token. It renders an HTML table by default
+// and the raw result with ?format=json.
+func (h *Handler) Panel(c *gin.Context) {
+	claims, err := h.svc.Verify(c.Param("token"))
+	if err != nil {
+		c.String(http.StatusForbidden, "This embed link is invalid or has expired.")
+		return
+	}
+
+	origin := c.GetHeader("Origin")
+	if origin == "" {
+		origin = c.GetHeader("Referer")
+	}
+	if !FrameAllowed(claims.AllowedDomains, origin) {
+		c.String(http.StatusForbidden, "This panel cannot be embedded on this site.")
+		return
+	}
+	c.Header("Content-Security-Policy", frameAncestors(claims.AllowedDomains))
+	c.Header("Cache-Control", "no-store")
+
+	// The panel route is outside /api, so record the viewer's origin here
+	// for the datasource's network policy.
+	ctx := connection.WithOrigin(c.Request.Context(), connection.RequestOrigin(c))
+	result, err := h.svc.Run(ctx, claims)
+	if err != nil {
+		status := http.StatusBadGateway
+		if errors.Is(err, ErrNotReadOnly) || errors.Is(err, connection.ErrNetworkPolicy) {
+			status = http.StatusForbidden
+		}
+		if c.Query("format") == "json" {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		c.String(status, "Query failed: %s", err)
+		return
+	}
+
+	if c.Query("format") == "json" {
+		c.JSON(http.StatusOK, gin.H{"data": result})
+		return
+	}
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := panelTemplate.Execute(c.Writer, panelView{Title: claims.Title, Frames: result.Frames}); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// frameAncestors builds the CSP directive that tells browsers which sites
+// may frame the panel.
+func frameAncestors(domains []string) string {
+	if len(domains) == 0 {
+		return "frame-ancestors *"
+	}
+	sources := make([]string, 0, len(domains)+1)
+	sources = append(sources, "'self'")
+	for _, d := range domains {
+		sources = append(sources, "https://"+d)
+	}
+	return "frame-ancestors " + strings.Join(sources, " ")
+}
+
+// RegisterRoutes wires the authenticated embed management routes onto r.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.POST("/embeds", h.Create)
+}
+
+// RegisterPublicRoutes wires the token-authenticated panel route. It lives
+// outside /api so it can be framed without API credentials.
+func RegisterPublicRoutes(r gin.IRoutes, h *Handler) {
+	r.GET("/embed/panel/:token", h.Panel)
+}
+
+// ─── rendering ────────────────────────────────────────────────────────────────
+
+type panelView struct {
+	Title  string
+	Frames []*sdk.DataFrame
+}
+
+var panelTemplate = template.Must(template.New("panel").Funcs(template.FuncMap{
+	"rows": frameRows,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ if .Title }}{{ .Title }}{{ else }}Data Voyager{{ end }}</title>
+<style>
+body { font: 13px system-ui, sans-serif; margin: 0; padding: 8px; color: #1f2328; }
+h1 { font-size: 15px; margin: 0 0 8px; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 12px; }
+th, td { border-bottom: 1px solid #d0d7de; padding: 4px 8px; text-align: left; white-space: nowrap; }
+th { background: #f6f8fa; font-weight: 600; }
+</style>
+</head>
+<body>
+{{ if .Title }}<h1>{{ .Title }}</h1>{{ end }}
+{{ range .Frames }}
+<table>
+<thead><tr>{{ range .Fields }}<th>{{ .Name }}</th>{{ end }}</tr></thead>
+<tbody>{{ range rows . }}<tr>{{ range . }}<td>{{ . }}</td>{{ end }}</tr>{{ end }}</tbody>
+</table>
+{{ else }}<p>No data.</p>{{ end }}
+</body>
+</html>
+`))
+
+// frameRows transposes a column-oriented frame into printable rows.
+func frameRows(f *sdk.DataFrame) [][]string {
+	if len(f.Fields) == 0 {
+		return nil
+	}
+	n := len(f.Fields[0].Values)
+	rows := make([][]string, n)
+	for i := 0; i < n; i++ {
+		row := make([]string, len(f.Fields))
+		for j, field := range f.Fields {
+			if i < len(field.Values) {
+				row[j] = formatValue(field.Values[i])
+			}
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+func formatValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return val.UTC().Format(time.RFC3339)
+	case []byte:
+		return string(val)
+	}
+	return fmt.Sprint(v)
+}
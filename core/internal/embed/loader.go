@@ -0,0 +1,23 @@
+package embed
+
+import (
+	"github.com/gin-gonic/gin"
+
+	apploader "data-voyager/core/internal/app"
+)
+
+type loader struct {
+	handler *Handler
+}
+
+// NewLoader wires the embed management routes. The public panel route is
+// registered separately with RegisterPublicRoutes.
+func NewLoader(h *Handler) apploader.Loader {
+	return &loader{handler: h}
+}
+
+func (l *loader) Load() error { return nil }
+
+func (l *loader) RegisterRoutes(r *gin.RouterGroup) {
+	RegisterRoutes(r, l.handler)
+}
@@ -0,0 +1,248 @@
+package embed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"data-voyager/core/internal/authz"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/identity"
+	qb "data-voyager/core/internal/query_builder"
+	"data-voyager/core/internal/sqllint"
+	"data-voyager/sdk"
+)
+
+const (
+	defaultTTL   = 7 * 24 * time.Hour
+	maxTTL       = 365 * 24 * time.Hour
+	defaultLimit = 1000
+	maxLimit     = 10000
+)
+
+// ErrDisabled is returned when no signing key is configured.
+var ErrDisabled = errors.New("embedding requires an encryption key (set VOYAGER_ENCRYPTION_KEY)")
+
+// ErrNotReadOnly is returned for a panel query that is not a plain read.
+// Embedded panels run for anonymous viewers, so only reads are allowed.
+var ErrNotReadOnly = errors.New("embedded panels can only run read-only queries")
+
+// Service issues embed tokens and runs the queries they describe.
+type Service struct {
+	conns    connection.Repository
+	registry *datasource.Registry
+	signer   *signer // nil when embedding is disabled
+	now      func() time.Time
+	timeout  time.Duration             // see WithQueryTimeout
+	literals *connection.LiteralPolicy // see WithLiteralPolicy
+}
+
+// NewService creates a Service. secret is the server encryption key; when it
+// is nil, embedding is disabled.
+func NewService(conns connection.Repository, registry *datasource.Registry, secret []byte) *Service {
+	s := &Service{conns: conns, registry: registry, now: time.Now}
+	if len(secret) > 0 {
+		s.signer = newSigner(secret)
+	}
+	return s
+}
+
+// WithQueryTimeout sets the server-wide query time limit panels run under;
+// 0 leaves only the datasource's own limit.
+func (s *Service) WithQueryTimeout(d time.Duration) *Service {
+	s.timeout = d
+	return s
+}
+
+// WithLiteralPolicy holds the queries embeds are issued for to the
+// parameter policy p.
+func (s *Service) WithLiteralPolicy(p *connection.LiteralPolicy) *Service {
+	s.literals = p
+	return s
+}
+
+// IssueRequest describes the panel to embed.
+type IssueRequest struct {
+	DatasourceID   string
+	Query          string
+	Variables      map[string]any
+	From, To       string
+	Limit          int
+	Title          string
+	AllowedDomains []string
+	TTL            time.Duration
+}
+
+// Issue validates req and returns a signed token for it.
+func (s *Service) Issue(ctx context.Context, req IssueRequest) (string, *Claims, error) {
+	if s.signer == nil {
+		return "", nil, ErrDisabled
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		return "", nil, fmt.Errorf("query is required")
+	}
+	if !identity.FromContext(ctx).CanSee(req.DatasourceID) {
+		return "", nil, fmt.Errorf("datasource not found")
+	}
+	conn, err := s.conns.GetByID(ctx, req.DatasourceID)
+	if err != nil {
+		return "", nil, fmt.Errorf("datasource not found")
+	}
+	if err := connection.CheckOrigin(ctx, conn); err != nil {
+		return "", nil, err
+	}
+	if err := authz.CheckQuery(ctx, conn.ID); err != nil {
+		return "", nil, err
+	}
+	if err := s.literals.Enforce(ctx, conn, req.Query); err != nil {
+		return "", nil, err
+	}
+	ttl := req.TTL
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+	if ttl < 0 || ttl > maxTTL {
+		return "", nil, fmt.Errorf("expiry must be within %d days", int(maxTTL.Hours()/24))
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	if limit < 1 || limit > maxLimit {
+		return "", nil, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+	}
+	domains := make([]string, 0, len(req.AllowedDomains))
+	for _, d := range req.AllowedDomains {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d == "" || strings.ContainsAny(d, "/:") {
+			return "", nil, fmt.Errorf("invalid domain %q: use a host name such as wiki.example.com or *.example.com", d)
+		}
+		domains = append(domains, d)
+	}
+
+	now := s.now()
+	c := &Claims{
+		DatasourceID:   req.DatasourceID,
+		Query:          req.Query,
+		Variables:      req.Variables,
+		From:           req.From,
+		To:             req.To,
+		Limit:          limit,
+		Title:          req.Title,
+		AllowedDomains: domains,
+		IssuedAt:       now.Unix(),
+		ExpiresAt:      now.Add(ttl).Unix(),
+	}
+	if _, err := render(conn, c); err != nil {
+		return "", nil, err
+	}
+	token, err := s.signer.sign(c)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, c, nil
+}
+
+// Verify checks a token's signature and expiry.
+func (s *Service) Verify(token string) (*Claims, error) {
+	if s.signer == nil {
+		return nil, ErrDisabled
+	}
+	return s.signer.verify(token, s.now())
+}
+
+// Run executes the query described by c, returning at most c.Limit rows,
+// under the datasource's network policy and query deadline.
+func (s *Service) Run(ctx context.Context, c *Claims) (*sdk.QueryResult, error) {
+	conn, err := s.conns.GetByID(ctx, c.DatasourceID)
+	if err != nil {
+		return nil, fmt.Errorf("datasource not found")
+	}
+	if err := connection.CheckOrigin(ctx, conn); err != nil {
+		return nil, err
+	}
+	plugin, ok := s.registry.Get(conn.Type)
+	if !ok {
+		return nil, fmt.Errorf("plugin not found for type %s", conn.Type)
+	}
+	cfg, err := plugin.ParseConfig(conn.Config)
+	if err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+
+	rendered, err := render(conn, c)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := connection.WithDeadline(ctx, conn, s.timeout)
+	defer cancel()
+	dbConn, err := plugin.Connect(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer func() { _ = dbConn.Close() }()
+	result, err := dbConn.Query(ctx, rendered)
+	if err != nil {
+		return nil, err
+	}
+	// A LIMIT in the query itself may allow more rows than the token.
+	connection.CapRows(result, c.Limit)
+	return result, nil
+}
+
+// render builds the SQL that c runs on conn: its template and macros
+// expanded, checked to be a plain read and given the token's row limit when
+// it has none of its own. Issue renders too, so a token is never minted for
+// a query Run would refuse.
+func render(conn *connection.Connection, c *Claims) (string, error) {
+	tr, err := qb.ParseTimeRange(c.From, c.To)
+	if err != nil {
+		return "", err
+	}
+	rendered, err := qb.RenderQuery(c.Query, qb.BuildContext(tr, c.Variables, c.Limit))
+	if err != nil {
+		return "", err
+	}
+	rendered, _, err = qb.ExpandMacros(rendered, qb.DialectFor(string(conn.Type)), tr, 0)
+	if err != nil {
+		return "", err
+	}
+	if !connection.IsReadOnlyQuery(rendered) {
+		return "", ErrNotReadOnly
+	}
+	if limited, ok := sqllint.AddLimit(rendered, c.Limit); ok {
+		rendered = limited
+	}
+	return rendered, nil
+}
+
+// FrameAllowed reports whether a page at origin (the embedding page's URL or
+// origin, from the Origin or Referer header) may show the panel. An empty
+// allow-list permits any origin.
+func FrameAllowed(domains []string, origin string) bool {
+	if len(domains) == 0 {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, d := range domains {
+		if suffix, ok := strings.CutPrefix(d, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if host == d {
+			return true
+		}
+	}
+	return false
+}
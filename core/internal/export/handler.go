@@ -0,0 +1,230 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/user"
+)
+
+// Handler serves the export job endpoints.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates an export HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+type exportRequest struct {
+	DatasourceID string `json:"datasource_id" binding:"required"`
+	Schema       string `json:"schema"`
+	Table        string `json:"table" binding:"required"`
+	KeyColumn    string `json:"key_column" binding:"required"`
+}
+
+// List handles GET /exports. Admins see every job, others their own.
+func (h *Handler) List(c *gin.Context) {
+	caller := identity.FromContext(c.Request.Context())
+	c.JSON(http.StatusOK, gin.H{"data": h.svc.List(username(caller), caller.Can(identity.PermAdmin))})
+}
+
+// Create handles POST /exports
+func (h *Handler) Create(c *gin.Context) {
+	if !identity.FromContext(c.Request.Context()).Can(identity.PermDatasourceQuery) {
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "permission denied")})
+		return
+	}
+	var req exportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	j, err := h.svc.Start(c.Request.Context(), Request{
+		DatasourceID: req.DatasourceID,
+		Schema:       req.Schema,
+		Table:        req.Table,
+		KeyColumn:    req.KeyColumn,
+		Owner:        username(identity.FromContext(c.Request.Context())),
+	})
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"data": j})
+}
+
+// Get handles GET /exports/:id
+func (h *Handler) Get(c *gin.Context) {
+	j, ok := h.job(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": j})
+}
+
+// Pause handles POST /exports/:id/pause
+func (h *Handler) Pause(c *gin.Context) {
+	if _, ok := h.job(c); !ok {
+		return
+	}
+	j, err := h.svc.Pause(c.Param("id"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": j})
+}
+
+// Resume handles POST /exports/:id/resume
+func (h *Handler) Resume(c *gin.Context) {
+	if _, ok := h.job(c); !ok {
+		return
+	}
+	j, err := h.svc.Resume(c.Param("id"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": j})
+}
+
+// Delete handles DELETE /exports/:id
+func (h *Handler) Delete(c *gin.Context) {
+	if _, ok := h.job(c); !ok {
+		return
+	}
+	if err := h.svc.Delete(c.Param("id")); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Download handles GET /exports/:id/download
+func (h *Handler) Download(c *gin.Context) {
+	j, ok := h.job(c)
+	if !ok {
+		return
+	}
+	path, err := h.svc.File(j.ID)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	h.svc.RecordDownload(c.Request.Context(), j, c.ClientIP())
+	c.FileAttachment(path, j.Table+".csv")
+}
+
+// Audit handles GET /admin/export-audit?user=NAME&datasource_id=ID&watermark=W&from=T&to=T&limit=N[&format=csv]
+func (h *Handler) Audit(c *gin.Context) {
+	f := AuditFilter{Actor: c.Query("user"), DatasourceID: c.Query("datasource_id"), Watermark: c.Query("watermark")}
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &f.From}, {"to", &f.To}} {
+		v := c.Query(p.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": p.name + " must be an RFC 3339 time"})
+			return
+		}
+		*p.dst = t
+	}
+	f.Limit, _ = strconv.Atoi(c.Query("limit"))
+	if f.Limit <= 0 || f.Limit > 10000 {
+		f.Limit = 1000
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or csv"})
+		return
+	}
+
+	list, err := h.svc.Audit(c.Request.Context(), f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if format == "json" {
+		c.JSON(http.StatusOK, gin.H{"data": list})
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="export-audit.csv"`)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+	writeAuditCSV(csv.NewWriter(c.Writer), list)
+}
+
+func writeAuditCSV(w *csv.Writer, list []*AuditRecord) {
+	_ = w.Write([]string{"at", "actor", "delivery", "address", "datasource_id", "schema", "table",
+		"rows", "size_bytes", "watermark", "export_id"})
+	for _, r := range list {
+		_ = w.Write([]string{r.At.Format(time.RFC3339), r.Actor, string(r.Delivery), r.Address,
+			r.DatasourceID, r.Schema, r.Table, strconv.FormatInt(r.Rows, 10),
+			strconv.FormatInt(r.SizeBytes, 10), r.Watermark, r.ExportID})
+	}
+	w.Flush()
+}
+
+// job loads the job named in the path, hiding other users' jobs from
+// non-admins.
+func (h *Handler) job(c *gin.Context) (*Job, bool) {
+	j, err := h.svc.Get(c.Param("id"))
+	caller := identity.FromContext(c.Request.Context())
+	if err == nil && j.Owner != username(caller) && !caller.Can(identity.PermAdmin) {
+		err = ErrNotFound
+	}
+	if err != nil {
+		h.fail(c, err)
+		return nil, false
+	}
+	return j, true
+}
+
+func (h *Handler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "export not found")})
+	case errors.Is(err, ErrInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, connection.ErrNetworkPolicy):
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "this datasource cannot be used from your network")})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func username(id *identity.Identity) string {
+	if id == nil {
+		return ""
+	}
+	return id.Username
+}
+
+// RegisterRoutes wires the handler onto r. The export audit requires the
+// admin permission.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/exports", h.List)
+	r.POST("/exports", h.Create)
+	r.GET("/exports/:id", h.Get)
+	r.DELETE("/exports/:id", h.Delete)
+	r.POST("/exports/:id/pause", h.Pause)
+	r.POST("/exports/:id/resume", h.Resume)
+	r.GET("/exports/:id/download", h.Download)
+	r.GET("/admin/export-audit", user.RequireAdmin, h.Audit)
+}
@@ -0,0 +1,25 @@
+package export
+
+import (
+	"github.com/gin-gonic/gin"
+
+	apploader "data-voyager/core/internal/app"
+)
+
+type loader struct {
+	svc     *Service
+	handler *Handler
+}
+
+// NewLoader wires the export domain. Expiry is started separately with
+// Service.Schedule so it can share the server's lifetime.
+func NewLoader(svc *Service) apploader.Loader {
+	return &loader{svc: svc, handler: NewHandler(svc)}
+}
+
+// Load restores jobs left by a previous run.
+func (l *loader) Load() error { return l.svc.Load() }
+
+func (l *loader) RegisterRoutes(r *gin.RouterGroup) {
+	RegisterRoutes(r, l.handler)
+}
@@ -0,0 +1,127 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Status is where a job is in its lifecycle.
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusPaused    Status = "paused"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+var (
+	// ErrInvalid wraps validation failures so handlers can map them to 400.
+	ErrInvalid = errors.New("invalid export")
+	// ErrNotFound is returned for unknown and expired jobs.
+	ErrNotFound = errors.New("export not found")
+	// ErrConflict is returned when a job is in the wrong state for a request,
+	// e.g. pausing one that is not running.
+	ErrConflict = errors.New("export state conflict")
+)
+
+// Job exports one table to CSV. Rows are read in keyset order on KeyColumn,
+// so the column should be unique (typically the primary key); rows sharing a
+// key value with the end of a chunk would otherwise be skipped.
+//
+// A job checkpoints after every chunk. Pausing, a failure or a server
+// restart leave it resumable from the last checkpoint.
+type Job struct {
+	ID           string `json:"id"`
+	DatasourceID string `json:"datasource_id"`
+	Schema       string `json:"schema,omitempty"`
+	Table        string `json:"table"`
+	KeyColumn    string `json:"key_column"`
+	// Owner is the username that started the job; empty when authentication
+	// is off.
+	Owner  string `json:"owner,omitempty"`
+	Status Status `json:"status"`
+	Error  string `json:"error,omitempty"`
+
+	// TotalRows is the row count estimated when the job was created; 0 when
+	// the datasource cannot estimate one.
+	TotalRows   int64 `json:"total_rows"`
+	RowsWritten int64 `json:"rows_written"`
+	// Progress is the percentage done, or nil without a row estimate. It
+	// stays below 100 until the job succeeds since the estimate may be low.
+	Progress  *float64 `json:"progress,omitempty"`
+	SizeBytes int64    `json:"size_bytes"`
+
+	// Columns fixes the CSV header so resumed chunks line up with it.
+	Columns []string `json:"columns,omitempty"`
+	// Cursor is the keyset position after the last checkpointed row.
+	Cursor string `json:"cursor,omitempty"`
+	// Watermark is the value written into every row's watermark column, or
+	// empty when exports are not watermarked. It is fixed when the job is
+	// created so resumed chunks carry the same one.
+	Watermark string `json:"watermark,omitempty"`
+
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// ExpiresAt is when a job that is not running is removed with its file.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Request describes a new export.
+type Request struct {
+	DatasourceID string
+	Schema       string
+	Table        string
+	KeyColumn    string
+	Owner        string
+}
+
+// Delivery is how exported rows left the server.
+type Delivery string
+
+const (
+	// DeliveryFile is a finished export written to the export directory.
+	DeliveryFile Delivery = "file"
+	// DeliveryNotification is a finished export whose download link was sent
+	// through the notification channels.
+	DeliveryNotification Delivery = "notification"
+	// DeliveryDownload is a finished export's file being downloaded.
+	DeliveryDownload Delivery = "download"
+)
+
+// AuditRecord is one entry of the export audit: rows leaving a datasource,
+// or an export file leaving the server. Records outlive their job.
+type AuditRecord struct {
+	ID           string `json:"id"`
+	ExportID     string `json:"export_id"`
+	DatasourceID string `json:"datasource_id"`
+	Schema       string `json:"schema,omitempty"`
+	Table        string `json:"table"`
+	// Actor is who exported or downloaded; empty when authentication is off.
+	Actor     string   `json:"actor"`
+	Delivery  Delivery `json:"delivery"`
+	Rows      int64    `json:"rows"`
+	SizeBytes int64    `json:"size_bytes"`
+	Watermark string   `json:"watermark,omitempty"`
+	// Address is the client address of a download.
+	Address string    `json:"address,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+// AuditFilter narrows an audit report. Zero fields match everything.
+type AuditFilter struct {
+	Actor        string
+	DatasourceID string
+	Watermark    string
+	From, To     time.Time
+	Limit        int
+}
+
+// AuditRepository persists the export audit.
+type AuditRepository interface {
+	Record(ctx context.Context, r *AuditRecord) error
+	// List returns the newest records first.
+	List(ctx context.Context, f AuditFilter) ([]*AuditRecord, error)
+}
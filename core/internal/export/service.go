@@ -0,0 +1,649 @@
+// Package export runs large table exports as background jobs. A job pages
+// through the table in keyset order, appending each chunk to a CSV file and
+// checkpointing after it, so it can be paused and resumed — including
+// across restarts — without re-reading what it already wrote.
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/notification"
+	qb "data-voyager/core/internal/query_builder"
+	"data-voyager/sdk"
+)
+
+// Service manages export jobs. Job state lives in a JSON manifest next to
+// each export file, so Dir is the only storage it needs.
+type Service struct {
+	dir       string
+	chunkSize int
+	ttl       time.Duration
+	publicURL string
+	conns     connection.Repository
+	registry  *datasource.Registry
+	notifier  notification.Notifier
+	watermark string
+	audit     AuditRepository
+	now       func() time.Time
+
+	mu   sync.Mutex
+	jobs map[string]*Job
+	runs map[string]*run
+}
+
+// run is a job's active worker.
+type run struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewService creates a Service. notifier may be nil to skip completion
+// notices; publicURL prefixes the download link they carry.
+func NewService(cfg config.ExportsConfig, publicURL string, conns connection.Repository, registry *datasource.Registry, notifier notification.Notifier) *Service {
+	return &Service{
+		dir:       cfg.Dir,
+		chunkSize: cfg.ChunkSize,
+		ttl:       time.Duration(cfg.TTL) * time.Hour,
+		publicURL: strings.TrimRight(publicURL, "/"),
+		conns:     conns,
+		registry:  registry,
+		notifier:  notifier,
+		watermark: cfg.Watermark,
+		now:       time.Now,
+		jobs:      map[string]*Job{},
+		runs:      map[string]*run{},
+	}
+}
+
+// WithAudit records finished exports and downloads in repo.
+func (s *Service) WithAudit(repo AuditRepository) *Service {
+	s.audit = repo
+	return s
+}
+
+// Load reads the manifests in Dir. Jobs that were running when the server
+// stopped come back paused.
+func (s *Service) Load() error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("create export dir: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range paths {
+		raw, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		var j Job
+		if err := json.Unmarshal(raw, &j); err != nil {
+			slog.Warn("skipping unreadable export manifest", "path", p, "err", err)
+			continue
+		}
+		if j.Status == StatusRunning {
+			s.stop(&j, StatusPaused, "")
+			if err := s.save(&j); err != nil {
+				return err
+			}
+		}
+		s.jobs[j.ID] = &j
+	}
+	return nil
+}
+
+// Start creates a job and begins exporting in the background. The job
+// outlives ctx; it runs until it finishes, is paused or the server stops.
+func (s *Service) Start(ctx context.Context, req Request) (*Job, error) {
+	if req.Table == "" || req.KeyColumn == "" {
+		return nil, fmt.Errorf("%w: table and key_column are required", ErrInvalid)
+	}
+	conn, err := s.conns.GetByID(ctx, req.DatasourceID)
+	if err != nil || !identity.FromContext(ctx).CanSee(req.DatasourceID) {
+		return nil, fmt.Errorf("%w: datasource %q not found", ErrInvalid, req.DatasourceID)
+	}
+	if err := connection.CheckOrigin(ctx, conn); err != nil {
+		return nil, err
+	}
+	if _, ok := s.registry.Get(conn.Type); !ok {
+		return nil, fmt.Errorf("%w: plugin not found for type %s", ErrInvalid, conn.Type)
+	}
+
+	now := s.now().UTC()
+	j := &Job{
+		ID:           uuid.NewString(),
+		DatasourceID: req.DatasourceID,
+		Schema:       req.Schema,
+		Table:        req.Table,
+		KeyColumn:    req.KeyColumn,
+		Owner:        req.Owner,
+		Status:       StatusRunning,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if s.watermark != "" {
+		j.Watermark = uuid.NewString()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.save(j); err != nil {
+		return nil, err
+	}
+	s.jobs[j.ID] = j
+	s.launch(j)
+	return s.copy(j), nil
+}
+
+// Get returns the job with id.
+func (s *Service) Get(id string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return s.copy(j), nil
+}
+
+// List returns jobs started by owner, or every job when all is set, newest
+// first.
+func (s *Service) List(owner string, all bool) []*Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []*Job{}
+	for _, j := range s.jobs {
+		if all || j.Owner == owner {
+			out = append(out, s.copy(j))
+		}
+	}
+	slices.SortFunc(out, func(a, b *Job) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return out
+}
+
+// Reassign moves every job owned by from to to, e.g. when from's personal
+// data is erased. It returns how many jobs it moved.
+func (s *Service) Reassign(from, to string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, j := range s.jobs {
+		if j.Owner != from {
+			continue
+		}
+		j.Owner = to
+		if err := s.save(j); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// Pause stops a running job at its last checkpoint. The chunk in flight is
+// abandoned and read again on resume.
+func (s *Service) Pause(id string) (*Job, error) {
+	s.mu.Lock()
+	if _, ok := s.jobs[id]; !ok {
+		s.mu.Unlock()
+		return nil, ErrNotFound
+	}
+	r, ok := s.runs[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: export is not running", ErrConflict)
+	}
+	r.cancel()
+	<-r.done
+	return s.Get(id)
+}
+
+// Resume restarts a paused or failed job from its last checkpoint.
+func (s *Service) Resume(id string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if j.Status != StatusPaused && j.Status != StatusFailed {
+		return nil, fmt.Errorf("%w: only paused or failed exports can be resumed", ErrConflict)
+	}
+	j.Status, j.Error, j.ExpiresAt = StatusRunning, "", nil
+	j.UpdatedAt = s.now().UTC()
+	if err := s.save(j); err != nil {
+		return nil, err
+	}
+	s.launch(j)
+	return s.copy(j), nil
+}
+
+// Delete stops the job if it is running and removes it with its file.
+func (s *Service) Delete(id string) error {
+	s.mu.Lock()
+	if _, ok := s.jobs[id]; !ok {
+		s.mu.Unlock()
+		return ErrNotFound
+	}
+	r, running := s.runs[id]
+	s.mu.Unlock()
+	if running {
+		r.cancel()
+		<-r.done
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, id)
+	return s.remove(id)
+}
+
+// File returns the path of a finished export's CSV file.
+func (s *Service) File(id string) (string, error) {
+	j, err := s.Get(id)
+	if err != nil {
+		return "", err
+	}
+	if j.Status != StatusSucceeded {
+		return "", fmt.Errorf("%w: export has not finished", ErrConflict)
+	}
+	return s.dataPath(id), nil
+}
+
+// RecordDownload adds a download of j's file by the caller in ctx to the
+// export audit.
+func (s *Service) RecordDownload(ctx context.Context, j *Job, address string) {
+	s.record(ctx, j, username(identity.FromContext(ctx)), DeliveryDownload, address)
+}
+
+// Audit returns the export audit records matching f.
+func (s *Service) Audit(ctx context.Context, f AuditFilter) ([]*AuditRecord, error) {
+	if s.audit == nil {
+		return []*AuditRecord{}, nil
+	}
+	return s.audit.List(ctx, f)
+}
+
+// record adds j to the export audit. A failure is logged rather than
+// returned: the rows have already left by the time it is recorded.
+func (s *Service) record(ctx context.Context, j *Job, actor string, delivery Delivery, address string) {
+	if s.audit == nil {
+		return
+	}
+	err := s.audit.Record(ctx, &AuditRecord{
+		ID:           uuid.NewString(),
+		ExportID:     j.ID,
+		DatasourceID: j.DatasourceID,
+		Schema:       j.Schema,
+		Table:        j.Table,
+		Actor:        actor,
+		Delivery:     delivery,
+		Rows:         j.RowsWritten,
+		SizeBytes:    j.SizeBytes,
+		Watermark:    j.Watermark,
+		Address:      address,
+		At:           s.now().UTC(),
+	})
+	if err != nil {
+		slog.Warn("failed to record export audit", "export", j.ID, "err", err)
+	}
+}
+
+// Schedule removes expired jobs every interval until ctx is done. Running
+// jobs are stopped when ctx is done and come back paused on the next Load.
+func (s *Service) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.stopAll()
+			return
+		case <-ticker.C:
+		}
+		s.sweep()
+	}
+}
+
+func (s *Service) sweep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for id, j := range s.jobs {
+		if j.ExpiresAt == nil || now.Before(*j.ExpiresAt) {
+			continue
+		}
+		delete(s.jobs, id)
+		if err := s.remove(id); err != nil {
+			slog.Warn("failed to remove expired export", "id", id, "err", err)
+		}
+	}
+}
+
+func (s *Service) stopAll() {
+	s.mu.Lock()
+	runs := make([]*run, 0, len(s.runs))
+	for _, r := range s.runs {
+		runs = append(runs, r)
+	}
+	s.mu.Unlock()
+	for _, r := range runs {
+		r.cancel()
+		<-r.done
+	}
+}
+
+// launch starts a worker for j. Callers hold s.mu.
+func (s *Service) launch(j *Job) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &run{cancel: cancel, done: make(chan struct{})}
+	s.runs[j.ID] = r
+	go func() {
+		defer close(r.done)
+		defer cancel()
+		err := s.export(ctx, j)
+
+		s.mu.Lock()
+		delete(s.runs, j.ID)
+		switch {
+		case err == nil:
+			s.stop(j, StatusSucceeded, "")
+		case ctx.Err() != nil:
+			s.stop(j, StatusPaused, "")
+		default:
+			s.stop(j, StatusFailed, err.Error())
+		}
+		if err := s.save(j); err != nil {
+			slog.Warn("failed to save export manifest", "id", j.ID, "err", err)
+		}
+		done := s.copy(j)
+		s.mu.Unlock()
+
+		if done.Status == StatusSucceeded {
+			delivery := DeliveryFile
+			if s.notifier != nil {
+				delivery = DeliveryNotification
+			}
+			s.record(context.Background(), done, done.Owner, delivery, "")
+		}
+		if done.Status != StatusPaused {
+			s.notify(done)
+		}
+	}()
+}
+
+// stop moves j out of running. Callers hold s.mu.
+func (s *Service) stop(j *Job, status Status, msg string) {
+	now := s.now().UTC()
+	expires := now.Add(s.ttl)
+	j.Status, j.Error, j.UpdatedAt, j.ExpiresAt = status, msg, now, &expires
+	if status == StatusSucceeded {
+		j.CompletedAt = &now
+		done := 100.0
+		j.Progress = &done
+	}
+}
+
+// export appends chunks to the job's file until the table is exhausted or
+// ctx is cancelled.
+func (s *Service) export(ctx context.Context, j *Job) error {
+	conn, err := s.conns.GetByID(ctx, j.DatasourceID)
+	if err != nil {
+		return fmt.Errorf("datasource not found")
+	}
+	plugin, ok := s.registry.Get(conn.Type)
+	if !ok {
+		return fmt.Errorf("plugin not found for type %s", conn.Type)
+	}
+	cfg, err := plugin.ParseConfig(conn.Config)
+	if err != nil {
+		return fmt.Errorf("parse config: %w", err)
+	}
+	dbConn, err := plugin.Connect(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer func() { _ = dbConn.Close() }()
+
+	s.mu.Lock()
+	if j.TotalRows == 0 {
+		if est, ok := dbConn.(sdk.CardinalityEstimator); ok {
+			if rc, err := est.CountRows(ctx, j.Schema, j.Table, false); err == nil {
+				j.TotalRows = rc.Count
+			}
+		}
+	}
+	size, cursor := j.SizeBytes, j.Cursor
+	s.mu.Unlock()
+
+	f, err := os.OpenFile(s.dataPath(j.ID), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	// Drop whatever an interrupted chunk wrote past the checkpoint.
+	if err := f.Truncate(size); err != nil {
+		return err
+	}
+	if _, err := f.Seek(size, io.SeekStart); err != nil {
+		return err
+	}
+
+	dialect := qb.DialectFor(string(conn.Type))
+	for {
+		page := qb.KeysetPage{Schema: j.Schema, Table: j.Table, SortColumn: j.KeyColumn, Limit: s.chunkSize}
+		if cursor != "" {
+			if page.After, err = qb.DecodeCursor(cursor); err != nil {
+				return err
+			}
+		}
+		query, args, err := page.Build(dialect)
+		if err != nil {
+			return err
+		}
+		res, err := dbConn.Query(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("query failed: %w", err)
+		}
+		rows, more, err := s.writeChunk(f, j, res, size == 0)
+		if err != nil {
+			return err
+		}
+		if rows > 0 {
+			if cursor, err = lastKey(res, j.KeyColumn, rows, dialect); err != nil {
+				return err
+			}
+		}
+		if size, err = f.Seek(0, io.SeekCurrent); err != nil {
+			return err
+		}
+		if err := f.Sync(); err != nil {
+			return err
+		}
+		if err := s.checkpoint(j, int64(rows), size, cursor); err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// writeChunk appends up to chunkSize rows of res to w, plus the header when
+// header is set. It reports how many rows were written and whether the page
+// held more.
+func (s *Service) writeChunk(w io.Writer, j *Job, res *sdk.QueryResult, header bool) (int, bool, error) {
+	if len(res.Frames) == 0 || len(res.Frames[0].Fields) == 0 {
+		return 0, false, nil
+	}
+	fields := res.Frames[0].Fields
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+	}
+	s.mu.Lock()
+	if j.Columns == nil {
+		j.Columns = names
+	}
+	columns := j.Columns
+	s.mu.Unlock()
+	if !slices.Equal(columns, names) {
+		return 0, false, fmt.Errorf("table columns changed since the export started")
+	}
+
+	n := len(fields[0].Values)
+	more := n > s.chunkSize
+	if more {
+		n = s.chunkSize
+	}
+	cw := csv.NewWriter(w)
+	if header {
+		if j.Watermark != "" {
+			columns = append(slices.Clip(columns), s.watermark)
+		}
+		if err := cw.Write(columns); err != nil {
+			return 0, false, err
+		}
+	}
+	record := make([]string, len(fields), len(fields)+1)
+	if j.Watermark != "" {
+		record = append(record, j.Watermark)
+	}
+	for r := 0; r < n; r++ {
+		for i, f := range fields {
+			record[i] = cell(f.Values[r])
+		}
+		if err := cw.Write(record); err != nil {
+			return 0, false, err
+		}
+	}
+	cw.Flush()
+	return n, more, cw.Error()
+}
+
+// checkpoint records a written chunk. A job's file and manifest always agree
+// at a checkpoint, which is what makes resuming safe.
+func (s *Service) checkpoint(j *Job, rows, size int64, cursor string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j.RowsWritten += rows
+	j.SizeBytes, j.Cursor = size, cursor
+	j.UpdatedAt = s.now().UTC()
+	if j.TotalRows > 0 {
+		p := math.Min(99, math.Floor(float64(j.RowsWritten)*1000/float64(j.TotalRows))/10)
+		j.Progress = &p
+	}
+	return s.save(j)
+}
+
+func (s *Service) notify(j *Job) {
+	if s.notifier == nil {
+		return
+	}
+	ev := notification.Event{
+		Type:   notification.EventExport,
+		Source: j.Table,
+		Labels: map[string]string{"export_id": j.ID, "datasource_id": j.DatasourceID, "owner": j.Owner},
+		Time:   j.UpdatedAt,
+	}
+	if j.Status == StatusSucceeded {
+		link := s.publicURL + "/api/v1/exports/" + j.ID + "/download"
+		ev.Severity = notification.SeverityInfo
+		ev.Title = fmt.Sprintf("Export of %s is ready", j.Table)
+		ev.Body = fmt.Sprintf("%d rows exported. Download: %s", j.RowsWritten, link)
+		ev.Labels["download_url"] = link
+	} else {
+		ev.Severity = notification.SeverityWarning
+		ev.Title = fmt.Sprintf("Export of %s failed", j.Table)
+		ev.Body = fmt.Sprintf("%s. %d rows were written; the export can be resumed.", j.Error, j.RowsWritten)
+	}
+	if err := s.notifier.Notify(context.Background(), ev); err != nil {
+		slog.Warn("export notification failed", "export", j.ID, "err", err)
+	}
+}
+
+// lastKey encodes the key of row n-1 as the cursor for the next page.
+func lastKey(res *sdk.QueryResult, keyColumn string, n int, dialect qb.Dialect) (string, error) {
+	for _, f := range res.Frames[0].Fields {
+		if f.Name != keyColumn {
+			continue
+		}
+		last := f.Values[n-1]
+		if t, ok := last.(time.Time); ok {
+			// Same encoding as table browsing: the dialect's time layout.
+			last = t.UTC().Format(dialect.TimeLayout)
+		}
+		return qb.EncodeCursor(qb.Cursor{Sort: last})
+	}
+	return "", fmt.Errorf("key column %q not present in result", keyColumn)
+}
+
+func cell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// save writes j's manifest atomically. Callers hold s.mu.
+func (s *Service) save(j *Job) error {
+	raw, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	tmp := s.manifestPath(j.ID) + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.manifestPath(j.ID))
+}
+
+func (s *Service) remove(id string) error {
+	var errs []error
+	for _, p := range []string{s.dataPath(id), s.manifestPath(id)} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// copy returns a snapshot of j safe to hand out. Callers hold s.mu.
+func (s *Service) copy(j *Job) *Job {
+	c := *j
+	c.Columns = slices.Clone(j.Columns)
+	return &c
+}
+
+func (s *Service) dataPath(id string) string     { return filepath.Join(s.dir, id+".csv") }
+func (s *Service) manifestPath(id string) string { return filepath.Join(s.dir, id+".json") }
@@ -0,0 +1,313 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/notification"
+	"data-voyager/sdk"
+)
+
+type stubConns struct{ connection.Repository }
+
+func (stubConns) GetByID(_ context.Context, id string) (*connection.Connection, error) {
+	if id == "ds" {
+		return &connection.Connection{ID: id, Type: "postgresql", Config: json.RawMessage(`{}`)}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+type stubConfig struct{}
+
+func (stubConfig) Validate() error             { return nil }
+func (stubConfig) GetConnectionString() string { return "" }
+
+// stubPlugin serves a table with ids 1..rows in keyset pages. When block is
+// set, the query numbered blockAt waits until it is closed or the query is
+// cancelled.
+type stubPlugin struct {
+	sdk.DatasourcePlugin
+	rows    int
+	blockAt int
+	block   chan struct{}
+
+	mu      sync.Mutex
+	queries int
+}
+
+func (p *stubPlugin) GetType() sdk.DataSourceType { return "postgresql" }
+func (p *stubPlugin) ParseConfig(json.RawMessage) (sdk.ConnectionConfig, error) {
+	return stubConfig{}, nil
+}
+func (p *stubPlugin) Connect(context.Context, sdk.ConnectionConfig) (sdk.Connection, error) {
+	return &stubConn{p: p}, nil
+}
+
+type stubConn struct {
+	sdk.Connection
+	p *stubPlugin
+}
+
+var limitRe = regexp.MustCompile(`LIMIT (\d+)$`)
+
+func (c *stubConn) Query(ctx context.Context, q string, args ...any) (*sdk.QueryResult, error) {
+	c.p.mu.Lock()
+	c.p.queries++
+	n := c.p.queries
+	c.p.mu.Unlock()
+	if c.p.block != nil && n == c.p.blockAt {
+		select {
+		case <-c.p.block:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	limit, _ := strconv.Atoi(limitRe.FindStringSubmatch(q)[1])
+	after := int64(0)
+	if len(args) > 0 {
+		after = args[0].(int64)
+	}
+	var ids, names []any
+	for id := after + 1; id <= int64(c.p.rows) && len(ids) < limit; id++ {
+		ids = append(ids, id)
+		names = append(names, "row "+strconv.FormatInt(id, 10))
+	}
+	return &sdk.QueryResult{Frames: []*sdk.DataFrame{{Fields: []sdk.Field{
+		{Name: "id", Values: ids},
+		{Name: "name", Values: names},
+	}}}}, nil
+}
+func (c *stubConn) CountRows(context.Context, string, string, bool) (*sdk.RowCount, error) {
+	return &sdk.RowCount{Count: int64(c.p.rows), Approximate: true}, nil
+}
+func (c *stubConn) CountDistinct(context.Context, string, string, string) (*sdk.RowCount, error) {
+	return nil, errors.New("unsupported")
+}
+func (c *stubConn) Close() error { return nil }
+
+type recorder struct {
+	mu     sync.Mutex
+	events []notification.Event
+}
+
+func (r *recorder) Notify(_ context.Context, ev notification.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, ev)
+	return nil
+}
+
+// wait returns the events once n have arrived; notices are sent just after
+// a job's status changes.
+func (r *recorder) wait(t *testing.T, n int) []notification.Event {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return len(r.events) >= n
+	}, 2*time.Second, 5*time.Millisecond)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]notification.Event(nil), r.events...)
+}
+
+func newTestService(t *testing.T, plugin *stubPlugin) (*Service, *recorder) {
+	t.Helper()
+	reg := datasource.NewRegistry()
+	reg.Register(plugin)
+	rec := &recorder{}
+	svc := NewService(config.ExportsConfig{Dir: t.TempDir(), ChunkSize: 10, TTL: 1}, "https://voyager.example.com/", stubConns{}, reg, rec)
+	require.NoError(t, svc.Load())
+	return svc, rec
+}
+
+func waitFor(t *testing.T, svc *Service, id string, status Status) *Job {
+	t.Helper()
+	var j *Job
+	require.Eventually(t, func() bool {
+		var err error
+		j, err = svc.Get(id)
+		return err == nil && j.Status == status
+	}, 2*time.Second, 5*time.Millisecond)
+	return j
+}
+
+func readLines(t *testing.T, svc *Service, id string) []string {
+	t.Helper()
+	path, err := svc.File(id)
+	require.NoError(t, err)
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSuffix(string(raw), "\n"), "\n")
+}
+
+func TestExport_WritesChunks(t *testing.T) {
+	svc, rec := newTestService(t, &stubPlugin{rows: 25})
+
+	j, err := svc.Start(context.Background(), Request{DatasourceID: "ds", Table: "events", KeyColumn: "id", Owner: "ann"})
+	require.NoError(t, err)
+	j = waitFor(t, svc, j.ID, StatusSucceeded)
+
+	assert.EqualValues(t, 25, j.RowsWritten)
+	assert.EqualValues(t, 25, j.TotalRows)
+	require.NotNil(t, j.Progress)
+	assert.Equal(t, 100.0, *j.Progress)
+	require.NotNil(t, j.ExpiresAt)
+
+	lines := readLines(t, svc, j.ID)
+	require.Len(t, lines, 26)
+	assert.Equal(t, "id,name", lines[0])
+	assert.Equal(t, "25,row 25", lines[25])
+
+	events := rec.wait(t, 1)
+	require.Len(t, events, 1)
+	assert.Equal(t, notification.EventExport, events[0].Type)
+	assert.Equal(t, "https://voyager.example.com/api/v1/exports/"+j.ID+"/download", events[0].Labels["download_url"])
+}
+
+func TestExport_PauseAndResume(t *testing.T) {
+	plugin := &stubPlugin{rows: 25, blockAt: 2, block: make(chan struct{})}
+	svc, rec := newTestService(t, plugin)
+
+	j, err := svc.Start(context.Background(), Request{DatasourceID: "ds", Table: "events", KeyColumn: "id"})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		j, _ = svc.Get(j.ID)
+		return j.RowsWritten == 10
+	}, 2*time.Second, 5*time.Millisecond)
+
+	j, err = svc.Pause(j.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusPaused, j.Status)
+	assert.EqualValues(t, 10, j.RowsWritten)
+	require.NotNil(t, j.Progress)
+	assert.Equal(t, 40.0, *j.Progress)
+	_, err = svc.File(j.ID)
+	assert.ErrorIs(t, err, ErrConflict)
+	_, err = svc.Pause(j.ID)
+	assert.ErrorIs(t, err, ErrConflict)
+
+	close(plugin.block)
+	_, err = svc.Resume(j.ID)
+	require.NoError(t, err)
+	j = waitFor(t, svc, j.ID, StatusSucceeded)
+
+	lines := readLines(t, svc, j.ID)
+	require.Len(t, lines, 26, "resumed export must not repeat rows or the header")
+	assert.Equal(t, "11,row 11", lines[11])
+	assert.Len(t, rec.wait(t, 1), 1, "pausing does not notify")
+}
+
+func TestLoad_InterruptedJobComesBackPaused(t *testing.T) {
+	dir := t.TempDir()
+	raw, err := json.Marshal(Job{ID: "j1", DatasourceID: "ds", Table: "events", KeyColumn: "id", Status: StatusRunning, RowsWritten: 10})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "j1.json"), raw, 0o644))
+
+	svc := NewService(config.ExportsConfig{Dir: dir, ChunkSize: 10, TTL: 1}, "", stubConns{}, datasource.NewRegistry(), nil)
+	require.NoError(t, svc.Load())
+
+	j, err := svc.Get("j1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusPaused, j.Status)
+	assert.EqualValues(t, 10, j.RowsWritten)
+
+	svc.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	svc.sweep()
+	_, err = svc.Get("j1")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = os.Stat(filepath.Join(dir, "j1.json"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestStart_Validates(t *testing.T) {
+	svc, _ := newTestService(t, &stubPlugin{})
+
+	_, err := svc.Start(context.Background(), Request{DatasourceID: "ds", Table: "events"})
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = svc.Start(context.Background(), Request{DatasourceID: "missing", Table: "events", KeyColumn: "id"})
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+type auditLog struct {
+	mu      sync.Mutex
+	records []*AuditRecord
+}
+
+func (a *auditLog) Record(_ context.Context, r *AuditRecord) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.records = append(a.records, r)
+	return nil
+}
+
+func (a *auditLog) List(context.Context, AuditFilter) ([]*AuditRecord, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]*AuditRecord(nil), a.records...), nil
+}
+
+func TestExport_WatermarkAndAudit(t *testing.T) {
+	reg := datasource.NewRegistry()
+	reg.Register(&stubPlugin{rows: 15})
+	audit := &auditLog{}
+	svc := NewService(config.ExportsConfig{Dir: t.TempDir(), ChunkSize: 10, TTL: 1, Watermark: "_wm"}, "", stubConns{}, reg, nil).
+		WithAudit(audit)
+	require.NoError(t, svc.Load())
+
+	j, err := svc.Start(context.Background(), Request{DatasourceID: "ds", Table: "events", KeyColumn: "id", Owner: "ann"})
+	require.NoError(t, err)
+	require.NotEmpty(t, j.Watermark)
+	j = waitFor(t, svc, j.ID, StatusSucceeded)
+
+	lines := readLines(t, svc, j.ID)
+	require.Len(t, lines, 16)
+	assert.Equal(t, "id,name,_wm", lines[0])
+	assert.Equal(t, "15,row 15,"+j.Watermark, lines[15], "chunks after the first carry it too")
+
+	require.Eventually(t, func() bool {
+		list, _ := svc.Audit(context.Background(), AuditFilter{})
+		return len(list) == 1
+	}, 2*time.Second, 5*time.Millisecond)
+	svc.RecordDownload(context.Background(), j, "10.0.0.7")
+
+	list, err := svc.Audit(context.Background(), AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, DeliveryFile, list[0].Delivery, "without a notifier the file is only stored")
+	assert.Equal(t, "ann", list[0].Actor)
+	assert.EqualValues(t, 15, list[0].Rows)
+	assert.Equal(t, j.SizeBytes, list[0].SizeBytes)
+	assert.Equal(t, j.Watermark, list[0].Watermark)
+	assert.Equal(t, DeliveryDownload, list[1].Delivery)
+	assert.Equal(t, "10.0.0.7", list[1].Address)
+}
+
+func TestExport_NoWatermarkByDefault(t *testing.T) {
+	svc, _ := newTestService(t, &stubPlugin{rows: 3})
+	j, err := svc.Start(context.Background(), Request{DatasourceID: "ds", Table: "events", KeyColumn: "id"})
+	require.NoError(t, err)
+	assert.Empty(t, j.Watermark)
+	waitFor(t, svc, j.ID, StatusSucceeded)
+	assert.Equal(t, "id,name", readLines(t, svc, j.ID)[0])
+
+	list, err := svc.Audit(context.Background(), AuditFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, list, "nothing is recorded without an audit repository")
+}
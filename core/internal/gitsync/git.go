@@ -0,0 +1,97 @@
+package gitsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// repo drives the git binary in a working copy. Credentials come from the
+// usual git setup of the server's user: SSH keys, a credential helper, or a
+// token in the remote URL.
+type repo struct {
+	dir, remote, branch string
+	// identity is the -c flags naming the author and committer of exports.
+	identity []string
+}
+
+func (r *repo) run(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append(slices.Clone(r.identity), args...)...)
+	cmd.Dir = r.dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var out bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &out, &out
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(out.String()))
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// sync makes the working copy match the remote branch exactly, discarding
+// anything left behind by an earlier failed run. An empty remote leaves an
+// empty working copy on an unborn branch.
+func (r *repo) sync(ctx context.Context) error {
+	if _, err := os.Stat(filepath.Join(r.dir, ".git")); errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll(r.dir, 0o750); err != nil {
+			return err
+		}
+		if _, err := r.run(ctx, "init", "--quiet"); err != nil {
+			return err
+		}
+		if _, err := r.run(ctx, "remote", "add", "origin", r.remote); err != nil {
+			return err
+		}
+	}
+	if _, err := r.run(ctx, "remote", "set-url", "origin", r.remote); err != nil {
+		return err
+	}
+	if _, err := r.run(ctx, "fetch", "--quiet", "--prune", "origin"); err != nil {
+		return err
+	}
+	if _, err := r.run(ctx, "rev-parse", "--verify", "--quiet", "refs/remotes/origin/"+r.branch); err != nil {
+		// Nothing pushed yet: start the branch from scratch.
+		if _, err := r.run(ctx, "symbolic-ref", "HEAD", "refs/heads/"+r.branch); err != nil {
+			return err
+		}
+		if _, err := r.run(ctx, "rm", "-r", "--cached", "--quiet", "--ignore-unmatch", "."); err != nil {
+			return err
+		}
+	} else if _, err := r.run(ctx, "checkout", "--quiet", "--force", "-B", r.branch, "origin/"+r.branch); err != nil {
+		return err
+	}
+	_, err := r.run(ctx, "clean", "--quiet", "-fdx")
+	return err
+}
+
+// commitAndPush commits every change under path and pushes it. It returns
+// the new commit, or "" when there was nothing to commit.
+func (r *repo) commitAndPush(ctx context.Context, path, message string) (string, error) {
+	if _, err := r.run(ctx, "add", "--all", "--", path); err != nil {
+		return "", err
+	}
+	if _, err := r.run(ctx, "diff", "--cached", "--quiet"); err == nil {
+		return "", nil
+	}
+	if _, err := r.run(ctx, "commit", "--quiet", "-m", message); err != nil {
+		return "", err
+	}
+	if _, err := r.run(ctx, "push", "--quiet", "origin", "HEAD:refs/heads/"+r.branch); err != nil {
+		return "", err
+	}
+	return r.run(ctx, "rev-parse", "HEAD")
+}
+
+// head returns the checked-out commit, or "" on an unborn branch.
+func (r *repo) head(ctx context.Context) string {
+	sha, err := r.run(ctx, "rev-parse", "--verify", "--quiet", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return sha
+}
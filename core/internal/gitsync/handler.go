@@ -0,0 +1,69 @@
+package gitsync
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/user"
+)
+
+// Handler serves the export and import triggers.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates a gitsync HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+type exportRequest struct {
+	Message string `json:"message"`
+}
+
+type importRequest struct {
+	Prune bool `json:"prune"`
+}
+
+// Export handles POST /admin/gitsync/export
+func (h *Handler) Export(c *gin.Context) {
+	var req exportRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	res, err := h.svc.Export(c.Request.Context(), req.Message)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// Import handles POST /admin/gitsync/import
+func (h *Handler) Import(c *gin.Context) {
+	var req importRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	res, err := h.svc.Import(c.Request.Context(), req.Prune)
+	switch {
+	case errors.Is(err, ErrInvalid):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, gin.H{"data": res})
+	}
+}
+
+// RegisterRoutes wires the handler onto r. Both routes require the admin
+// permission.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	admin := r.Group("/admin/gitsync", user.RequireAdmin)
+	admin.POST("/export", h.Export)
+	admin.POST("/import", h.Import)
+}
@@ -0,0 +1,139 @@
+package gitsync
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"data-voyager/core/internal/dashboard"
+	"data-voyager/core/internal/transform"
+)
+
+// dashboardsDir holds one <id>.yaml file per dashboard. Naming files by ID
+// keeps renames to a one-line diff.
+const dashboardsDir = "dashboards"
+
+// dashboardFile is the YAML form of a dashboard. Fields are written in
+// struct order and server-managed timestamps are left out, so exporting an
+// unchanged dashboard reproduces its file byte for byte.
+type dashboardFile struct {
+	ID          string         `yaml:"id"`
+	Name        string         `yaml:"name"`
+	Description string         `yaml:"description,omitempty"`
+	TimeRange   timeRangeFile  `yaml:"time_range"`
+	Variables   []variableFile `yaml:"variables,omitempty"`
+	Panels      []panelFile    `yaml:"panels,omitempty"`
+}
+
+type timeRangeFile struct {
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+}
+
+type variableFile struct {
+	Name    string   `yaml:"name"`
+	Label   string   `yaml:"label,omitempty"`
+	Type    string   `yaml:"type"`
+	Options []string `yaml:"options,omitempty"`
+	Default any      `yaml:"default,omitempty"`
+	Multi   bool     `yaml:"multi,omitempty"`
+}
+
+// panelFile names its datasource rather than giving its ID, so a repository
+// can be imported into another environment whose datasources share names.
+// DatasourceID is written only when the datasource no longer exists. A
+// panel using an alias keeps it as is, since aliases already travel between
+// environments.
+type panelFile struct {
+	ID              string `yaml:"id"`
+	Title           string `yaml:"title"`
+	Datasource      string `yaml:"datasource,omitempty"`
+	DatasourceID    string `yaml:"datasource_id,omitempty"`
+	DatasourceAlias string `yaml:"datasource_alias,omitempty"`
+	Query           string `yaml:"query"`
+	Limit           int    `yaml:"limit,omitempty"`
+	MaxDataPoints   int    `yaml:"max_data_points,omitempty"`
+	// Transforms keep their JSON shape; map keys are written sorted.
+	Transforms []any `yaml:"transforms,omitempty"`
+}
+
+// encodeDashboard renders d, naming datasources with name.
+func encodeDashboard(d *dashboard.Dashboard, name func(id string) (string, bool)) ([]byte, error) {
+	f := dashboardFile{
+		ID:          d.ID,
+		Name:        d.Name,
+		Description: d.Description,
+		TimeRange:   timeRangeFile(d.TimeRange),
+	}
+	for _, v := range d.Variables {
+		f.Variables = append(f.Variables, variableFile(v))
+	}
+	for _, p := range d.Panels {
+		pf := panelFile{ID: p.ID, Title: p.Title, DatasourceAlias: p.DatasourceAlias, Query: p.Query, Limit: p.Limit, MaxDataPoints: p.MaxDataPoints}
+		if p.DatasourceID != "" {
+			if n, ok := name(p.DatasourceID); ok {
+				pf.Datasource = n
+			} else {
+				pf.DatasourceID = p.DatasourceID
+			}
+		}
+		if len(p.Transforms) > 0 {
+			if err := convert(p.Transforms, &pf.Transforms); err != nil {
+				return nil, fmt.Errorf("panel %s transforms: %w", p.ID, err)
+			}
+		}
+		f.Panels = append(f.Panels, pf)
+	}
+	return yaml.Marshal(f)
+}
+
+// decodeDashboard parses a file, resolving datasource names with id.
+func decodeDashboard(data []byte, id func(name string) (string, bool)) (*dashboard.Dashboard, error) {
+	var f dashboardFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	d := &dashboard.Dashboard{
+		ID:          f.ID,
+		Name:        f.Name,
+		Description: f.Description,
+		TimeRange:   dashboard.TimeRange(f.TimeRange),
+		Variables:   []dashboard.Variable{},
+		Panels:      []dashboard.Panel{},
+	}
+	for _, v := range f.Variables {
+		d.Variables = append(d.Variables, dashboard.Variable(v))
+	}
+	for _, pf := range f.Panels {
+		p := dashboard.Panel{
+			ID: pf.ID, Title: pf.Title, DatasourceID: pf.DatasourceID, DatasourceAlias: pf.DatasourceAlias,
+			Query: pf.Query, Limit: pf.Limit, MaxDataPoints: pf.MaxDataPoints,
+		}
+		if pf.Datasource != "" {
+			dsID, ok := id(pf.Datasource)
+			if !ok {
+				return nil, fmt.Errorf("panel %s: unknown datasource %q", pf.ID, pf.Datasource)
+			}
+			p.DatasourceID = dsID
+		}
+		if len(pf.Transforms) > 0 {
+			var specs []transform.Spec
+			if err := convert(pf.Transforms, &specs); err != nil {
+				return nil, fmt.Errorf("panel %s transforms: %w", pf.ID, err)
+			}
+			p.Transforms = specs
+		}
+		d.Panels = append(d.Panels, p)
+	}
+	return d, nil
+}
+
+// convert copies in to out through JSON, the shape both sides agree on.
+func convert(in, out any) error {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
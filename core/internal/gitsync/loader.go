@@ -0,0 +1,22 @@
+package gitsync
+
+import (
+	"github.com/gin-gonic/gin"
+
+	apploader "data-voyager/core/internal/app"
+)
+
+type loader struct {
+	handler *Handler
+}
+
+// NewLoader wires the gitsync routes.
+func NewLoader(svc *Service) apploader.Loader {
+	return &loader{handler: NewHandler(svc)}
+}
+
+func (l *loader) Load() error { return nil }
+
+func (l *loader) RegisterRoutes(r *gin.RouterGroup) {
+	RegisterRoutes(r, l.handler)
+}
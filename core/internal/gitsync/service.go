@@ -0,0 +1,258 @@
+// Package gitsync mirrors dashboards to a Git repository and back, so they
+// can be reviewed and versioned like code. Export commits the server's
+// dashboards as YAML files; Import applies the repository's files to the
+// server.
+package gitsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/dashboard"
+)
+
+// ErrInvalid wraps repository files that cannot be imported, so handlers
+// can map them to 400.
+var ErrInvalid = errors.New("invalid repository content")
+
+// Result summarizes an export or import. Names are dashboard names.
+type Result struct {
+	// Commit is the commit exported or imported; empty when an export found
+	// nothing to commit or the repository is empty.
+	Commit  string   `json:"commit,omitempty"`
+	Created []string `json:"created,omitempty"`
+	Updated []string `json:"updated,omitempty"`
+	Deleted []string `json:"deleted,omitempty"`
+}
+
+// Service runs exports and imports. They share one working copy, so they
+// run one at a time.
+type Service struct {
+	dashboards *dashboard.Service
+	conns      connection.Repository
+	repo       *repo
+	path       string // directory within the repository holding the files
+
+	mu sync.Mutex
+}
+
+// NewService creates a Service for cfg.
+func NewService(cfg config.GitSyncConfig, dashboards *dashboard.Service, conns connection.Repository) *Service {
+	return &Service{
+		dashboards: dashboards,
+		conns:      conns,
+		path:       filepath.Clean(filepath.Join(".", cfg.Path)),
+		repo: &repo{
+			dir:      cfg.Dir,
+			remote:   cfg.Remote,
+			branch:   cfg.Branch,
+			identity: []string{"-c", "user.name=" + cfg.AuthorName, "-c", "user.email=" + cfg.AuthorEmail},
+		},
+	}
+}
+
+func (s *Service) dashboardsPath() string {
+	return filepath.Join(s.repo.dir, s.path, dashboardsDir)
+}
+
+// Export writes every dashboard to the repository, removes files of
+// dashboards that no longer exist, and pushes the result as one commit.
+func (s *Service) Export(ctx context.Context, message string) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if message == "" {
+		message = "Export dashboards from Data Voyager"
+	}
+	if err := s.repo.sync(ctx); err != nil {
+		return nil, err
+	}
+	list, err := s.dashboards.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list dashboards: %w", err)
+	}
+	dir := s.dashboardsPath()
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	existing, err := readDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]string{}
+	name := func(id string) (string, bool) {
+		if n, ok := names[id]; ok {
+			return n, n != ""
+		}
+		conn, err := s.conns.GetByID(ctx, id)
+		if err != nil {
+			names[id] = ""
+			return "", false
+		}
+		names[id] = conn.Name
+		return conn.Name, true
+	}
+
+	res := &Result{}
+	written := map[string]bool{}
+	for _, d := range list {
+		data, err := encodeDashboard(d, name)
+		if err != nil {
+			return nil, fmt.Errorf("dashboard %s: %w", d.Name, err)
+		}
+		file := d.ID + ".yaml"
+		written[file] = true
+		old, had := existing[file]
+		if had && string(old) == string(data) {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, file), data, 0o640); err != nil {
+			return nil, err
+		}
+		if had {
+			res.Updated = append(res.Updated, d.Name)
+		} else {
+			res.Created = append(res.Created, d.Name)
+		}
+	}
+	for file, old := range existing {
+		if written[file] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, file)); err != nil {
+			return nil, err
+		}
+		res.Deleted = append(res.Deleted, fileName(old, file))
+	}
+	sortResult(res)
+
+	res.Commit, err = s.repo.commitAndPush(ctx, filepath.Join(s.path, dashboardsDir), message)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Import applies the repository's dashboards to the server: files create or
+// replace the dashboard with their ID. With prune, dashboards that have no
+// file are deleted. Every file is parsed before anything is written, so a
+// bad file leaves the server untouched.
+func (s *Service) Import(ctx context.Context, prune bool) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.repo.sync(ctx); err != nil {
+		return nil, err
+	}
+	files, err := readDir(s.dashboardsPath())
+	if err != nil {
+		return nil, err
+	}
+
+	id := func(name string) (string, bool) {
+		conn, err := s.conns.GetByName(ctx, name)
+		if err != nil || conn == nil {
+			return "", false
+		}
+		return conn.ID, true
+	}
+	var incoming []*dashboard.Dashboard
+	seen := map[string]bool{}
+	for _, file := range sortedKeys(files) {
+		d, err := decodeDashboard(files[file], id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalid, file, err)
+		}
+		if d.ID == "" || d.ID+".yaml" != file {
+			return nil, fmt.Errorf("%w: %s: id must match the file name", ErrInvalid, file)
+		}
+		seen[d.ID] = true
+		incoming = append(incoming, d)
+	}
+
+	res := &Result{Commit: s.repo.head(ctx)}
+	for _, d := range incoming {
+		created, err := s.dashboards.Put(ctx, d)
+		if err != nil {
+			return nil, fmt.Errorf("dashboard %s: %w", d.Name, err)
+		}
+		if created {
+			res.Created = append(res.Created, d.Name)
+		} else {
+			res.Updated = append(res.Updated, d.Name)
+		}
+	}
+	if prune {
+		list, err := s.dashboards.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list dashboards: %w", err)
+		}
+		for _, d := range list {
+			if seen[d.ID] {
+				continue
+			}
+			if err := s.dashboards.Delete(ctx, d.ID); err != nil {
+				return nil, fmt.Errorf("delete dashboard %s: %w", d.Name, err)
+			}
+			res.Deleted = append(res.Deleted, d.Name)
+		}
+	}
+	sortResult(res)
+	return res, nil
+}
+
+// readDir returns the contents of the .yaml files in dir, keyed by file
+// name. A missing directory is empty.
+func readDir(dir string) (map[string][]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string][]byte{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := map[string][]byte{}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".yaml") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		out[e.Name()] = data
+	}
+	return out, nil
+}
+
+// fileName returns the dashboard name recorded in a file, or the file name
+// when it cannot be read.
+func fileName(data []byte, file string) string {
+	d, err := decodeDashboard(data, func(string) (string, bool) { return "", true })
+	if err != nil || d.Name == "" {
+		return file
+	}
+	return d.Name
+}
+
+func sortedKeys(m map[string][]byte) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func sortResult(r *Result) {
+	slices.Sort(r.Created)
+	slices.Sort(r.Updated)
+	slices.Sort(r.Deleted)
+}
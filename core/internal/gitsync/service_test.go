@@ -0,0 +1,182 @@
+package gitsync
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/dashboard"
+	"data-voyager/core/internal/transform"
+)
+
+type memDashboards struct {
+	dashboard.Repository
+	byID map[string]*dashboard.Dashboard
+}
+
+func (m *memDashboards) List(context.Context) ([]*dashboard.Dashboard, error) {
+	var out []*dashboard.Dashboard
+	for _, d := range m.byID {
+		out = append(out, d)
+	}
+	slices.SortFunc(out, func(a, b *dashboard.Dashboard) int { return strings.Compare(a.ID, b.ID) })
+	return out, nil
+}
+
+func (m *memDashboards) GetByID(_ context.Context, id string) (*dashboard.Dashboard, error) {
+	if d, ok := m.byID[id]; ok {
+		return d, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *memDashboards) Create(_ context.Context, d *dashboard.Dashboard) error {
+	m.byID[d.ID] = d
+	return nil
+}
+
+func (m *memDashboards) Update(_ context.Context, d *dashboard.Dashboard) error {
+	m.byID[d.ID] = d
+	return nil
+}
+
+func (m *memDashboards) Delete(_ context.Context, id string) error {
+	delete(m.byID, id)
+	return nil
+}
+
+// namedConns knows datasources by ID and name.
+type namedConns struct {
+	connection.Repository
+	names map[string]string // id → name
+}
+
+func (c namedConns) GetByID(_ context.Context, id string) (*connection.Connection, error) {
+	if n, ok := c.names[id]; ok {
+		return &connection.Connection{ID: id, Name: n}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (c namedConns) GetByName(_ context.Context, name string) (*connection.Connection, error) {
+	for id, n := range c.names {
+		if n == name {
+			return &connection.Connection{ID: id, Name: n}, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return strings.TrimSpace(string(out))
+}
+
+func newTestService(t *testing.T, remote string, repo *memDashboards, conns namedConns) *Service {
+	cfg := config.GitSyncConfig{
+		Remote: remote, Branch: "main", Path: "analytics", Dir: filepath.Join(t.TempDir(), "work"),
+		AuthorName: "Voyager", AuthorEmail: "voyager@example.com",
+	}
+	return NewService(cfg, dashboard.NewService(repo, conns, nil), conns)
+}
+
+func TestExportImport(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	git(t, t.TempDir(), "init", "--quiet", "--bare", remote)
+
+	conns := namedConns{names: map[string]string{"ds-1": "warehouse"}}
+	prod := &memDashboards{byID: map[string]*dashboard.Dashboard{
+		"d1": {ID: "d1", Name: "Sales", TimeRange: dashboard.TimeRange{From: "now-6h", To: "now"}, Panels: []dashboard.Panel{{
+			ID: "p1", Title: "Revenue", DatasourceID: "ds-1", Query: "SELECT day, sum(amount)\nFROM orders\nGROUP BY day",
+			Transforms: []transform.Spec{{Type: transform.TypePivot, Pivot: &transform.Pivot{Column: "region", Value: "amount"}}},
+		}}},
+	}}
+	svc := newTestService(t, remote, prod, conns)
+
+	res, err := svc.Export(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Sales"}, res.Created)
+	require.NotEmpty(t, res.Commit)
+
+	// The file is readable YAML naming the datasource, and re-exporting an
+	// unchanged dashboard commits nothing.
+	clone := filepath.Join(t.TempDir(), "clone")
+	git(t, t.TempDir(), "clone", "--quiet", "--branch", "main", remote, clone)
+	data, err := os.ReadFile(filepath.Join(clone, "analytics", "dashboards", "d1.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "datasource: warehouse\n")
+	assert.Contains(t, string(data), "query: |-\n")
+	res, err = svc.Export(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, res.Commit)
+
+	// A reviewed change in the repository imports into another server whose
+	// datasource has a different ID but the same name.
+	edited := strings.Replace(string(data), "title: Revenue", "title: Net revenue", 1)
+	require.NoError(t, os.WriteFile(filepath.Join(clone, "analytics", "dashboards", "d1.yaml"), []byte(edited), 0o644))
+	git(t, clone, "commit", "--quiet", "-am", "Rename panel")
+	git(t, clone, "push", "--quiet", "origin", "HEAD:main")
+
+	stagingConns := namedConns{names: map[string]string{"ds-9": "warehouse"}}
+	staging := &memDashboards{byID: map[string]*dashboard.Dashboard{"old": {ID: "old", Name: "Scratch"}}}
+	res, err = newTestService(t, remote, staging, stagingConns).Import(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Sales"}, res.Created)
+	assert.Equal(t, []string{"Scratch"}, res.Deleted)
+	assert.Equal(t, git(t, clone, "rev-parse", "HEAD"), res.Commit)
+	d := staging.byID["d1"]
+	require.NotNil(t, d)
+	assert.Equal(t, "Net revenue", d.Panels[0].Title)
+	assert.Equal(t, "ds-9", d.Panels[0].DatasourceID)
+	assert.Equal(t, "region", d.Panels[0].Transforms[0].Pivot.Column)
+
+	// Deleting a dashboard removes its file on the next export.
+	delete(prod.byID, "d1")
+	res, err = svc.Export(ctx, "Remove sales")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Sales"}, res.Deleted)
+	git(t, clone, "pull", "--quiet", "origin", "main")
+	_, err = os.Stat(filepath.Join(clone, "analytics", "dashboards", "d1.yaml"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestImport_RejectsBadFile(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	git(t, t.TempDir(), "init", "--quiet", "--bare", remote)
+	clone := filepath.Join(t.TempDir(), "clone")
+	git(t, t.TempDir(), "clone", "--quiet", remote, clone)
+	dir := filepath.Join(clone, "analytics", "dashboards")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("id: a\nname: A\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte("id: b\nname: B\npanels:\n  - id: p\n    datasource: missing\n"), 0o644))
+	git(t, clone, "add", ".")
+	git(t, clone, "commit", "--quiet", "-m", "Add")
+	git(t, clone, "push", "--quiet", "origin", "HEAD:main")
+
+	repo := &memDashboards{byID: map[string]*dashboard.Dashboard{}}
+	_, err := newTestService(t, remote, repo, namedConns{}).Import(context.Background(), false)
+	assert.ErrorIs(t, err, ErrInvalid)
+	assert.ErrorContains(t, err, `unknown datasource "missing"`)
+	assert.Empty(t, repo.byID, "nothing is written when any file is bad")
+}
@@ -0,0 +1,30 @@
+package i18n
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListLocales handles GET /i18n
+func ListLocales(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"locales": Locales(), "default": DefaultLocale, "negotiated": Locale(c)}})
+}
+
+// GetBundle handles GET /i18n/:locale
+func GetBundle(c *gin.Context) {
+	locale := c.Param("locale")
+	b, ok := Lookup(locale)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": T(c, "unsupported locale")})
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"locale": locale, "messages": b.Messages, "ui": b.UI}})
+}
+
+// RegisterRoutes wires i18n routes onto r.
+func RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/i18n", ListLocales)
+	r.GET("/i18n/:locale", GetBundle)
+}
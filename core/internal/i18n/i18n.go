@@ -0,0 +1,146 @@
+// Package i18n localizes user-facing API messages and serves UI string
+// bundles to the frontend. API messages are keyed by their English text, so
+// untranslated messages fall back to English unchanged.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultLocale is used when negotiation finds no supported locale.
+const DefaultLocale = "en"
+
+// contextKey is the gin context key holding the negotiated locale.
+const contextKey = "i18n.locale"
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+// Bundle holds one locale's strings.
+type Bundle struct {
+	// Messages maps English API messages to their translation.
+	Messages map[string]string `json:"messages"`
+	// UI holds frontend strings keyed by dotted identifiers.
+	UI map[string]string `json:"ui"`
+}
+
+var bundles = mustLoad()
+
+func mustLoad() map[string]*Bundle {
+	entries, err := localeFS.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	out := make(map[string]*Bundle, len(entries))
+	for _, e := range entries {
+		data, err := localeFS.ReadFile(path.Join("locales", e.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var b Bundle
+		if err := json.Unmarshal(data, &b); err != nil {
+			panic(fmt.Sprintf("i18n: %s: %v", e.Name(), err))
+		}
+		out[strings.TrimSuffix(e.Name(), ".json")] = &b
+	}
+	return out
+}
+
+// Locales returns the supported locale codes, sorted.
+func Locales() []string {
+	out := make([]string, 0, len(bundles))
+	for l := range bundles {
+		out = append(out, l)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Lookup returns the bundle for locale with missing keys filled from the
+// default locale, or false when the locale is not supported.
+func Lookup(locale string) (*Bundle, bool) {
+	b, ok := bundles[locale]
+	if !ok {
+		return nil, false
+	}
+	def := bundles[DefaultLocale]
+	merged := &Bundle{Messages: map[string]string{}, UI: map[string]string{}}
+	for _, src := range []*Bundle{def, b} {
+		for k, v := range src.Messages {
+			merged.Messages[k] = v
+		}
+		for k, v := range src.UI {
+			merged.UI[k] = v
+		}
+	}
+	return merged, true
+}
+
+// Translate returns msg in locale, or msg itself when there is no
+// translation.
+func Translate(locale, msg string) string {
+	if b, ok := bundles[locale]; ok {
+		if t, ok := b.Messages[msg]; ok {
+			return t
+		}
+	}
+	return msg
+}
+
+// T translates msg into the locale negotiated for the request.
+func T(c *gin.Context, msg string) string {
+	return Translate(Locale(c), msg)
+}
+
+// Locale returns the locale negotiated by Middleware, or DefaultLocale.
+func Locale(c *gin.Context) string {
+	if l := c.GetString(contextKey); l != "" {
+		return l
+	}
+	return DefaultLocale
+}
+
+// Middleware negotiates the request locale from Accept-Language and sets the
+// Content-Language response header.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := Negotiate(c.GetHeader("Accept-Language"))
+		c.Set(contextKey, locale)
+		c.Header("Content-Language", locale)
+		c.Next()
+	}
+}
+
+// Negotiate picks the supported locale with the highest quality from an
+// Accept-Language header. Region subtags match their base language, so
+// "ko-KR" selects "ko".
+func Negotiate(header string) string {
+	best, bestQ := DefaultLocale, 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if _, ok := bundles[base]; ok && q > bestQ {
+			best, bestQ = base, q
+		}
+	}
+	return best
+}
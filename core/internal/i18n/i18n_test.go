@@ -0,0 +1,78 @@
+package i18n
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := map[string]string{
+		"":                           "en",
+		"ko-KR,ko;q=0.9,en;q=0.8":    "ko",
+		"fr-FR,en;q=0.5,ko;q=0.7":    "ko",
+		"fr":                         "en",
+		"en-US,ko;q=bad":             "en",
+		"de;q=0.9, KO;q=0.8, en;q=0": "ko",
+	}
+	for header, want := range tests {
+		assert.Equal(t, want, Negotiate(header), "Accept-Language: %q", header)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	assert.Equal(t, "데이터소스를 찾을 수 없습니다", Translate("ko", "datasource not found"))
+	assert.Equal(t, "datasource not found", Translate("en", "datasource not found"))
+	assert.Equal(t, "some new message", Translate("ko", "some new message"))
+}
+
+func TestLocalesHaveSameKeys(t *testing.T) {
+	en := bundles[DefaultLocale]
+	for _, locale := range Locales() {
+		b := bundles[locale]
+		for k := range en.Messages {
+			assert.Contains(t, b.Messages, k, "%s is missing message %q", locale, k)
+		}
+		for k := range en.UI {
+			assert.Contains(t, b.UI, k, "%s is missing UI string %q", locale, k)
+		}
+	}
+}
+
+func TestMiddlewareAndBundle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/err", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": T(c, "datasource not found")})
+	})
+	RegisterRoutes(&r.RouterGroup)
+
+	req := httptest.NewRequest(http.MethodGet, "/err", nil)
+	req.Header.Set("Accept-Language", "ko-KR")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "ko", w.Header().Get("Content-Language"))
+	assert.Contains(t, w.Body.String(), "데이터소스를 찾을 수 없습니다")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/i18n/ko", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data struct {
+			Locale string            `json:"locale"`
+			UI     map[string]string `json:"ui"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "쿼리 실행", resp.Data.UI["query.run"])
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/i18n/xx", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
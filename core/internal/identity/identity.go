@@ -0,0 +1,91 @@
+// Package identity carries the caller of a request through its context and
+// answers what that caller may do. A request without an identity comes from
+// an unauthenticated deployment and is allowed everything, matching the
+// behaviour before users existed.
+package identity
+
+import (
+	"context"
+	"slices"
+)
+
+// Roles.
+const (
+	RoleAdmin  = "admin"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+// Permissions.
+const (
+	PermDatasourceRead  = "datasource:read"
+	PermDatasourceQuery = "datasource:query"
+	PermDatasourceWrite = "datasource:write"
+	PermDashboardWrite  = "dashboard:write"
+	PermAdmin           = "admin"
+)
+
+var rolePermissions = map[string][]string{
+	RoleAdmin:  {PermDatasourceRead, PermDatasourceQuery, PermDatasourceWrite, PermDashboardWrite, PermAdmin},
+	RoleEditor: {PermDatasourceRead, PermDatasourceQuery, PermDatasourceWrite, PermDashboardWrite},
+	RoleViewer: {PermDatasourceRead, PermDatasourceQuery},
+}
+
+// ValidRole reports whether role is known.
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// Permissions returns the permissions a role grants.
+func Permissions(role string) []string {
+	return slices.Clone(rolePermissions[role])
+}
+
+// Identity is the user a request acts as.
+type Identity struct {
+	UserID   string
+	Username string
+	Role     string
+	// Datasources limits which datasources are visible; empty means all.
+	Datasources []string
+	// RowLimit overrides the server's default LIMIT for unbounded queries
+	// on interactive datasources; 0 keeps the default.
+	RowLimit int
+	// ImpersonatedBy names the admin acting as this user, if any.
+	ImpersonatedBy string
+	// Demo marks the anonymous visitor of a public demo, who may only read.
+	Demo bool
+	// Dashboards lists the dashboards a demo visitor sees; other identities
+	// see all of them.
+	Dashboards []string
+}
+
+// Can reports whether the identity holds perm. A nil identity can do
+// anything.
+func (i *Identity) Can(perm string) bool {
+	return i == nil || slices.Contains(rolePermissions[i.Role], perm)
+}
+
+// CanSee reports whether a datasource is visible to the identity.
+func (i *Identity) CanSee(datasourceID string) bool {
+	return i == nil || len(i.Datasources) == 0 || slices.Contains(i.Datasources, datasourceID)
+}
+
+// CanSeeDashboard reports whether a dashboard is visible to the identity.
+func (i *Identity) CanSeeDashboard(dashboardID string) bool {
+	return i == nil || !i.Demo || slices.Contains(i.Dashboards, dashboardID)
+}
+
+type ctxKey struct{}
+
+// With returns a context carrying id.
+func With(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the identity of ctx, or nil when there is none.
+func FromContext(ctx context.Context) *Identity {
+	id, _ := ctx.Value(ctxKey{}).(*Identity)
+	return id
+}
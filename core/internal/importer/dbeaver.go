@@ -0,0 +1,81 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// dbeaverFile is DBeaver's legacy data-sources.xml workspace file.
+type dbeaverFile struct {
+	DataSources []dbeaverDataSource `xml:"data-source"`
+}
+
+type dbeaverDataSource struct {
+	Name       string `xml:"name,attr"`
+	Provider   string `xml:"provider,attr"`
+	Driver     string `xml:"driver,attr"`
+	Connection struct {
+		Host       string `xml:"host,attr"`
+		Port       string `xml:"port,attr"`
+		Database   string `xml:"database,attr"`
+		User       string `xml:"user,attr"`
+		Password   string `xml:"password,attr"`
+		Type       string `xml:"type,attr"` // dev | test | prod
+		Properties []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:"value,attr"`
+		} `xml:"property"`
+	} `xml:"connection"`
+}
+
+// DBeaver connection types → Voyager environments.
+var dbeaverEnvironments = map[string]string{"dev": "dev", "test": "staging", "prod": "prod"}
+
+func parseDBeaver(data []byte) ([]*Candidate, error) {
+	var f dbeaverFile
+	if err := xml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse dbeaver data-sources.xml: %w", err)
+	}
+	out := make([]*Candidate, 0, len(f.DataSources))
+	for _, ds := range f.DataSources {
+		c := &Candidate{Name: ds.Name, SourceType: ds.Provider + "/" + ds.Driver}
+		out = append(out, c)
+
+		switch {
+		case ds.Provider == "postgresql":
+			c.Type = typePostgreSQL
+		case ds.Provider == "clickhouse" || strings.Contains(ds.Driver, "clickhouse"):
+			c.Type = typeClickHouse
+		default:
+			c.Status = StatusUnsupported
+			c.warnf("dbeaver provider %q has no Voyager equivalent", ds.Provider)
+			continue
+		}
+
+		conn := ds.Connection
+		port, _ := strconv.Atoi(conn.Port)
+		c.Options = map[string]any{
+			"host": conn.Host, "port": port, "database": conn.Database,
+			"username": conn.User, "password": conn.Password,
+		}
+		if conn.Password == "" {
+			c.warnf("password is not stored in data-sources.xml; set it after import")
+		}
+		if env, ok := dbeaverEnvironments[conn.Type]; ok {
+			c.Environment = env
+		}
+		for _, p := range conn.Properties {
+			switch {
+			case c.Type == typePostgreSQL && p.Name == "sslmode":
+				c.Options["ssl_mode"] = p.Value
+			case c.Type == typeClickHouse && p.Name == "ssl":
+				c.Options["secure"] = p.Value == "true"
+			default:
+				c.warnf("driver property %s is not supported and was dropped", p.Name)
+			}
+		}
+	}
+	return out, nil
+}
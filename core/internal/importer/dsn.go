@@ -0,0 +1,84 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"data-voyager/sdk"
+)
+
+var dsnSchemes = map[string]sdk.DataSourceType{
+	"postgres":   typePostgreSQL,
+	"postgresql": typePostgreSQL,
+	"clickhouse": typeClickHouse,
+}
+
+func parseDSN(data []byte) ([]*Candidate, error) {
+	var out []*Candidate
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		name, raw := "", text
+		if fields := strings.Fields(text); len(fields) == 2 {
+			name, raw = fields[0], fields[1]
+		}
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("line %d: not a connection URL", line)
+		}
+		c := &Candidate{Name: name, SourceType: u.Scheme}
+		out = append(out, c)
+
+		database := strings.TrimPrefix(u.Path, "/")
+		if c.Name == "" {
+			c.Name = u.Hostname()
+			if database != "" {
+				c.Name += "/" + database
+			}
+		}
+		typ, ok := dsnSchemes[u.Scheme]
+		if !ok {
+			c.Status = StatusUnsupported
+			c.warnf("scheme %q has no Voyager equivalent", u.Scheme)
+			continue
+		}
+		c.Type = typ
+
+		host, port := splitHostPort(u.Host)
+		password, _ := u.User.Password()
+		c.Options = map[string]any{
+			"host": host, "port": port, "database": database,
+			"username": u.User.Username(), "password": password,
+		}
+		query := u.Query()
+		keys := make([]string, 0, len(query))
+		for k := range query {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v := query[k]
+			switch {
+			case c.Type == typePostgreSQL && k == "sslmode":
+				c.Options["ssl_mode"] = v[0]
+			case c.Type == typeClickHouse && k == "secure":
+				c.Options["secure"] = v[0] == "true"
+			default:
+				c.warnf("parameter %s is not supported and was dropped", k)
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,143 @@
+package importer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"data-voyager/sdk"
+)
+
+// grafanaFile is the subset of Grafana's datasource provisioning format
+// (provisioning/datasources/*.yaml) that maps onto Voyager datasources.
+type grafanaFile struct {
+	Datasources []grafanaDatasource `yaml:"datasources"`
+}
+
+type grafanaDatasource struct {
+	Name           string            `yaml:"name"`
+	Type           string            `yaml:"type"`
+	URL            string            `yaml:"url"`
+	User           string            `yaml:"user"`
+	Password       string            `yaml:"password"` // pre-8.0 plain-text field
+	Database       string            `yaml:"database"`
+	BasicAuthUser  string            `yaml:"basicAuthUser"`
+	JSONData       map[string]any    `yaml:"jsonData"`
+	SecureJSONData map[string]string `yaml:"secureJsonData"`
+}
+
+var grafanaTypes = map[string]sdk.DataSourceType{
+	"postgres":                         typePostgreSQL,
+	"grafana-postgresql-datasource":    typePostgreSQL,
+	"grafana-clickhouse-datasource":    typeClickHouse,
+	"vertamedia-clickhouse-datasource": typeClickHouse,
+}
+
+// jsonData keys each Voyager type consumes; anything else is reported.
+var grafanaKnownJSONData = map[sdk.DataSourceType]map[string]bool{
+	typePostgreSQL: {"database": true, "sslmode": true},
+	typeClickHouse: {
+		"server": true, "host": true, "port": true, "username": true,
+		"defaultDatabase": true, "secure": true, "protocol": true,
+	},
+}
+
+func parseGrafana(data []byte) ([]*Candidate, error) {
+	var f grafanaFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse grafana provisioning file: %w", err)
+	}
+	out := make([]*Candidate, 0, len(f.Datasources))
+	for _, ds := range f.Datasources {
+		c := &Candidate{Name: ds.Name, SourceType: ds.Type}
+		out = append(out, c)
+
+		typ, ok := grafanaTypes[ds.Type]
+		if !ok {
+			c.Status = StatusUnsupported
+			c.warnf("grafana type %q has no Voyager equivalent", ds.Type)
+			continue
+		}
+		c.Type = typ
+
+		password := ds.SecureJSONData["password"]
+		if password == "" {
+			password = ds.Password
+		}
+		if isEnvReference(password) {
+			c.warnf("password references %s; set it after import", password)
+			password = ""
+		}
+
+		switch c.Type {
+		case typePostgreSQL:
+			host, port := splitHostPort(ds.URL)
+			database := ds.Database
+			if v, ok := ds.JSONData["database"].(string); ok && v != "" {
+				database = v
+			}
+			c.Options = map[string]any{
+				"host": host, "port": port, "database": database,
+				"username": ds.User, "password": password,
+			}
+			if v, ok := ds.JSONData["sslmode"].(string); ok {
+				c.Options["ssl_mode"] = v
+			}
+		case typeClickHouse:
+			c.Options = grafanaClickHouse(c, ds, password)
+		}
+		for _, k := range unknownKeys(ds.JSONData, grafanaKnownJSONData[typ]) {
+			c.warnf("jsonData.%s is not supported and was dropped", k)
+		}
+	}
+	return out, nil
+}
+
+func grafanaClickHouse(c *Candidate, ds grafanaDatasource, password string) map[string]any {
+	if ds.Type == "vertamedia-clickhouse-datasource" {
+		// The legacy plugin talks to the HTTP interface; Voyager uses the
+		// native protocol, whose port differs.
+		host, _ := splitHostPort(ds.URL)
+		c.warnf("HTTP endpoint %s converted to the native protocol on the default port", ds.URL)
+		return map[string]any{
+			"host": host, "database": "default",
+			"username": ds.BasicAuthUser, "password": password,
+			"secure": strings.HasPrefix(ds.URL, "https://"),
+		}
+	}
+
+	str := func(k string) string { v, _ := ds.JSONData[k].(string); return v }
+	host := str("server")
+	if host == "" {
+		host = str("host")
+	}
+	port := 0
+	switch v := ds.JSONData["port"].(type) {
+	case int:
+		port = v
+	case float64:
+		port = int(v)
+	}
+	if p := str("protocol"); p == "http" {
+		c.warnf("HTTP protocol is not supported; the native protocol on the default port is used instead")
+		port = 0
+	}
+	secure, _ := ds.JSONData["secure"].(bool)
+	return map[string]any{
+		"host": host, "port": port, "database": str("defaultDatabase"),
+		"username": str("username"), "password": password, "secure": secure,
+	}
+}
+
+func unknownKeys(m map[string]any, known map[string]bool) []string {
+	var out []string
+	for k := range m {
+		if !known[k] {
+			out = append(out, k)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
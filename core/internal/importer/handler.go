@@ -0,0 +1,82 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// Handler serves the datasource import endpoints.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates an importer HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+type importRequest struct {
+	Format  Format `json:"format"  binding:"required"`
+	Content string `json:"content" binding:"required"`
+	// Apply creates the ready datasources; without it the call is a preview.
+	Apply bool `json:"apply"`
+}
+
+type importSummary struct {
+	Total   int `json:"total"`
+	Ready   int `json:"ready"`
+	Created int `json:"created"`
+	Skipped int `json:"skipped"`
+}
+
+// Import handles POST /datasources/import. A preview is open to anyone;
+// applying creates datasources and needs the datasource write permission.
+func (h *Handler) Import(c *gin.Context) {
+	var req importRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Apply && !identity.FromContext(c.Request.Context()).Can(identity.PermDatasourceWrite) {
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "permission denied")})
+		return
+	}
+	cands, err := h.svc.Import(c.Request.Context(), req.Format, []byte(req.Content), req.Apply)
+	if err != nil {
+		if !errors.Is(err, ErrUnknownFormat) {
+			err = fmt.Errorf("could not read %s input: %w", req.Format, err)
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sum := importSummary{Total: len(cands)}
+	for _, cand := range cands {
+		switch cand.Status {
+		case StatusReady:
+			sum.Ready++
+		case StatusCreated:
+			sum.Created++
+		default:
+			sum.Skipped++
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": cands, "summary": sum})
+}
+
+// ListFormats handles GET /datasources/import/formats
+func (h *Handler) ListFormats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": Formats()})
+}
+
+// RegisterRoutes wires the handler onto r.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.POST("/datasources/import", h.Import)
+	r.GET("/datasources/import/formats", h.ListFormats)
+}
@@ -0,0 +1,120 @@
+// Package importer converts datasource definitions exported from other tools
+// into Voyager datasources.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"data-voyager/sdk"
+)
+
+// Format names a supported source format.
+type Format string
+
+const (
+	// FormatGrafana is a Grafana datasource provisioning YAML file.
+	FormatGrafana Format = "grafana"
+	// FormatDBeaver is a DBeaver data-sources.xml file.
+	FormatDBeaver Format = "dbeaver"
+	// FormatDSN is a list of connection URLs, one per line, each optionally
+	// preceded by a name: "orders postgres://app@db:5432/orders".
+	FormatDSN Format = "dsn"
+)
+
+// Formats lists the supported formats.
+func Formats() []Format { return []Format{FormatGrafana, FormatDBeaver, FormatDSN} }
+
+// ErrUnknownFormat is returned by Parse for an unsupported format.
+var ErrUnknownFormat = errors.New("unknown import format")
+
+const (
+	typePostgreSQL sdk.DataSourceType = "postgresql"
+	typeClickHouse sdk.DataSourceType = "clickhouse"
+)
+
+// Status is what happened, or would happen, to one imported definition.
+type Status string
+
+const (
+	StatusReady       Status = "ready"       // valid; created on apply
+	StatusUnsupported Status = "unsupported" // no matching Voyager datasource type
+	StatusInvalid     Status = "invalid"     // rejected by the plugin's config validation
+	StatusExists      Status = "exists"      // a datasource with the same name already exists
+	StatusCreated     Status = "created"
+	StatusFailed      Status = "failed"
+)
+
+// Candidate is one converted definition.
+type Candidate struct {
+	Name        string             `json:"name"`
+	Type        sdk.DataSourceType `json:"type,omitempty"`
+	SourceType  string             `json:"source_type"` // type as named by the source tool
+	Environment string             `json:"environment,omitempty"`
+	Options     map[string]any     `json:"options,omitempty"`
+	// Warnings lists source options that were dropped or need follow-up,
+	// such as passwords that were not part of the export.
+	Warnings []string `json:"warnings"`
+	Status   Status   `json:"status"`
+	Error    string   `json:"error,omitempty"`
+	ID       string   `json:"id,omitempty"` // set once created
+}
+
+func (c *Candidate) warnf(format string, args ...any) {
+	c.Warnings = append(c.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Parse converts data in the given format into candidates. Every definition
+// found becomes a Candidate; ones that cannot be mapped are marked
+// StatusUnsupported rather than dropped, so the report is complete.
+func Parse(format Format, data []byte) ([]*Candidate, error) {
+	var (
+		cands []*Candidate
+		err   error
+	)
+	switch format {
+	case FormatGrafana:
+		cands, err = parseGrafana(data)
+	case FormatDBeaver:
+		cands, err = parseDBeaver(data)
+	case FormatDSN:
+		cands, err = parseDSN(data)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range cands {
+		if c.Warnings == nil {
+			c.Warnings = []string{}
+		}
+		if c.Status == "" {
+			c.Status = StatusReady
+		}
+	}
+	return cands, nil
+}
+
+// splitHostPort splits "host:port", tolerating a missing port and a scheme
+// prefix such as "http://". A zero port means the plugin default.
+func splitHostPort(addr string) (string, int) {
+	if i := strings.Index(addr, "://"); i >= 0 {
+		addr = addr[i+3:]
+	}
+	addr = strings.TrimSuffix(addr, "/")
+	if i := strings.LastIndex(addr, ":"); i >= 0 && !strings.Contains(addr[i:], "]") {
+		if port, err := strconv.Atoi(addr[i+1:]); err == nil {
+			return addr[:i], port
+		}
+	}
+	return addr, 0
+}
+
+// isEnvReference reports whether v is a ${VAR} or $VAR placeholder, which
+// provisioning files use for secrets.
+func isEnvReference(v string) bool {
+	return strings.HasPrefix(v, "$")
+}
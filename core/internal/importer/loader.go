@@ -0,0 +1,24 @@
+package importer
+
+import (
+	"github.com/gin-gonic/gin"
+
+	apploader "data-voyager/core/internal/app"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+)
+
+type loader struct {
+	handler *Handler
+}
+
+// NewLoader wires the datasource importers.
+func NewLoader(conns connection.Repository, registry *datasource.Registry) apploader.Loader {
+	return &loader{handler: NewHandler(NewService(conns, registry))}
+}
+
+func (l *loader) Load() error { return nil }
+
+func (l *loader) RegisterRoutes(r *gin.RouterGroup) {
+	RegisterRoutes(r, l.handler)
+}
@@ -0,0 +1,84 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/sdk"
+)
+
+// Service validates imported candidates against the installed plugins and
+// creates them.
+type Service struct {
+	conns    connection.Repository
+	registry *datasource.Registry
+}
+
+// NewService creates a Service.
+func NewService(conns connection.Repository, registry *datasource.Registry) *Service {
+	return &Service{conns: conns, registry: registry}
+}
+
+// Import parses data and checks every candidate. With apply set, ready
+// candidates are created; otherwise nothing is written and the result is a
+// preview of what apply would do.
+func (s *Service) Import(ctx context.Context, format Format, data []byte, apply bool) ([]*Candidate, error) {
+	cands, err := Parse(format, data)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range cands {
+		if c.Status != StatusReady {
+			continue
+		}
+		cfg, version, err := s.validate(c)
+		if err != nil {
+			c.Status, c.Error = StatusInvalid, err.Error()
+			continue
+		}
+		if existing, err := s.conns.GetByName(ctx, c.Name); err == nil && existing != nil {
+			c.Status, c.ID = StatusExists, existing.ID
+			continue
+		}
+		if !apply {
+			continue
+		}
+		conn := &connection.Connection{
+			Name:          c.Name,
+			Type:          c.Type,
+			Config:        cfg,
+			ConfigVersion: version,
+			Environment:   c.Environment,
+			Description:   "Imported from " + c.SourceType,
+			IsActive:      true,
+		}
+		if err := s.conns.Create(ctx, conn); err != nil {
+			c.Status, c.Error = StatusFailed, err.Error()
+			continue
+		}
+		c.Status, c.ID = StatusCreated, conn.ID
+	}
+	return cands, nil
+}
+
+func (s *Service) validate(c *Candidate) (json.RawMessage, int, error) {
+	plugin, ok := s.registry.Get(c.Type)
+	if !ok {
+		return nil, 0, fmt.Errorf("datasource type %q is not installed", c.Type)
+	}
+	raw, err := json.Marshal(c.Options)
+	if err != nil {
+		return nil, 0, err
+	}
+	cfg, err := plugin.ParseConfig(raw)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := plugin.ValidateConfig(cfg); err != nil {
+		return nil, 0, err
+	}
+	return raw, sdk.PluginConfigVersion(plugin), nil
+}
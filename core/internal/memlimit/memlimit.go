@@ -0,0 +1,121 @@
+// Package memlimit bounds the memory held by query results being read from
+// datasources, per query and across the server, so one huge result or many
+// large ones at once fail with a clear error instead of exhausting memory.
+package memlimit
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"data-voyager/sdk"
+)
+
+var (
+	// ErrQueryLimit is returned when one query's result outgrows the
+	// per-query limit.
+	ErrQueryLimit = fmt.Errorf("%w: query result is larger than the per-query limit", sdk.ErrMemoryLimit)
+	// ErrServerLimit is returned when the results being read across the
+	// server would exceed the total; retrying later may succeed.
+	ErrServerLimit = fmt.Errorf("%w: the server is holding too many query results", sdk.ErrMemoryLimit)
+)
+
+// Pool is the server-wide budget. A nil *Pool imposes no limits.
+type Pool struct {
+	total    int64
+	perQuery int64
+	used     atomic.Int64
+}
+
+// NewPool returns a pool allowing total bytes across all queries and
+// perQuery bytes for any one of them. Zero disables either limit; nil is
+// returned when both are disabled.
+func NewPool(total, perQuery int64) *Pool {
+	if total <= 0 && perQuery <= 0 {
+		return nil
+	}
+	return &Pool{total: total, perQuery: perQuery}
+}
+
+// InUse reports the bytes currently reserved across all queries.
+func (p *Pool) InUse() int64 {
+	if p == nil {
+		return 0
+	}
+	return p.used.Load()
+}
+
+// Track starts accounting for one query. The Tracker implements
+// sdk.MemoryBudget; Release must be called once the result is no longer
+// held.
+func (p *Pool) Track() *Tracker {
+	return &Tracker{pool: p}
+}
+
+// Tracker accounts for one query's result.
+type Tracker struct {
+	pool *Pool
+
+	mu       sync.Mutex
+	used     int64
+	released bool
+}
+
+// Reserve charges n more bytes to the query, failing with ErrQueryLimit or
+// ErrServerLimit when that would exceed either limit.
+func (t *Tracker) Reserve(n int64) error {
+	p := t.pool
+	if p == nil || n <= 0 {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.released {
+		return nil
+	}
+	if p.perQuery > 0 && t.used+n > p.perQuery {
+		return fmt.Errorf("%w (%s)", ErrQueryLimit, FormatBytes(p.perQuery))
+	}
+	if p.used.Add(n) > p.total && p.total > 0 {
+		p.used.Add(-n)
+		return fmt.Errorf("%w (%s)", ErrServerLimit, FormatBytes(p.total))
+	}
+	t.used += n
+	return nil
+}
+
+// Used reports the bytes reserved by this query.
+func (t *Tracker) Used() int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.used
+}
+
+// Release returns the query's bytes to the pool. Later reservations are
+// ignored, so a driver still unwinding cannot leak into the pool.
+func (t *Tracker) Release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.released {
+		return
+	}
+	t.released = true
+	if t.pool != nil {
+		t.pool.used.Add(-t.used)
+	}
+	t.used = 0
+}
+
+// FormatBytes renders n in binary units, e.g. 512 MiB.
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.4g %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,56 @@
+package memlimit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/sdk"
+)
+
+func TestTracker_PerQueryLimit(t *testing.T) {
+	p := NewPool(0, 100)
+	tr := p.Track()
+	require.NoError(t, tr.Reserve(60))
+	err := tr.Reserve(60)
+	assert.ErrorIs(t, err, ErrQueryLimit)
+	assert.ErrorIs(t, err, sdk.ErrMemoryLimit)
+	assert.EqualValues(t, 60, tr.Used())
+	assert.EqualValues(t, 60, p.InUse())
+
+	tr.Release()
+	assert.Zero(t, p.InUse())
+	require.NoError(t, tr.Reserve(1000), "reservations after release are ignored")
+	assert.Zero(t, p.InUse())
+}
+
+func TestTracker_ServerLimit(t *testing.T) {
+	p := NewPool(100, 0)
+	a, b := p.Track(), p.Track()
+	require.NoError(t, a.Reserve(70))
+	err := b.Reserve(40)
+	assert.True(t, errors.Is(err, ErrServerLimit))
+	assert.EqualValues(t, 70, p.InUse(), "a failed reservation is not kept")
+
+	a.Release()
+	require.NoError(t, b.Reserve(40))
+	b.Release()
+	assert.Zero(t, p.InUse())
+}
+
+func TestNilPool(t *testing.T) {
+	p := NewPool(0, 0)
+	assert.Nil(t, p)
+	tr := p.Track()
+	require.NoError(t, tr.Reserve(1<<40))
+	tr.Release()
+	assert.Zero(t, p.InUse())
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", FormatBytes(512))
+	assert.Equal(t, "512 MiB", FormatBytes(512<<20))
+	assert.Equal(t, "1.5 GiB", FormatBytes(3<<29))
+}
@@ -0,0 +1,100 @@
+package monitor
+
+import "math"
+
+const (
+	defaultThreshold = 3.0
+	defaultWindow    = 30
+	defaultMinRuns   = 10
+	defaultAlpha     = 0.3
+)
+
+// withDefaults fills unset fields.
+func (d Detector) withDefaults() Detector {
+	if d.Method == "" {
+		d.Method = MethodZScore
+	}
+	if d.Threshold == 0 {
+		d.Threshold = defaultThreshold
+	}
+	if d.Window == 0 {
+		d.Window = defaultWindow
+	}
+	if d.MinRuns == 0 {
+		d.MinRuns = min(defaultMinRuns, d.Window)
+	}
+	if d.Alpha == 0 {
+		d.Alpha = defaultAlpha
+	}
+	return d
+}
+
+// Verdict is the outcome of judging one value against history.
+type Verdict struct {
+	Expected, Lower, Upper float64
+	// Score is the deviation in standard deviations; NaN when history has
+	// no variance.
+	Score     float64
+	Anomalous bool
+}
+
+// Evaluate judges v against history (oldest first). ok is false while there
+// is too little history to judge.
+func (d Detector) Evaluate(history []float64, v float64) (Verdict, bool) {
+	d = d.withDefaults()
+	if len(history) > d.Window {
+		history = history[len(history)-d.Window:]
+	}
+	if len(history) < d.MinRuns {
+		return Verdict{}, false
+	}
+
+	var center, sd float64
+	if d.Method == MethodEWMA {
+		center, sd = ewma(history, d.Alpha)
+	} else {
+		center, sd = meanStddev(history)
+	}
+	vd := Verdict{
+		Expected: center,
+		Lower:    center - d.Threshold*sd,
+		Upper:    center + d.Threshold*sd,
+		Score:    math.NaN(),
+	}
+	if sd > 0 {
+		vd.Score = (v - center) / sd
+	}
+	// With no variance at all, any change is a departure from the norm.
+	vd.Anomalous = v < vd.Lower || v > vd.Upper
+	return vd, true
+}
+
+// meanStddev returns the mean and sample standard deviation of xs.
+func meanStddev(xs []float64) (float64, float64) {
+	var mean float64
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= float64(len(xs))
+	if len(xs) < 2 {
+		return mean, 0
+	}
+	var ss float64
+	for _, x := range xs {
+		ss += (x - mean) * (x - mean)
+	}
+	return mean, math.Sqrt(ss / float64(len(xs)-1))
+}
+
+// ewma returns the exponentially weighted moving mean and standard deviation
+// of xs using the incremental update from Finch (2009).
+func ewma(xs []float64, alpha float64) (float64, float64) {
+	mean, variance := xs[0], 0.0
+	for _, x := range xs[1:] {
+		diff := x - mean
+		incr := alpha * diff
+		mean += incr
+		variance = (1 - alpha) * (variance + diff*incr)
+	}
+	return mean, math.Sqrt(variance)
+}
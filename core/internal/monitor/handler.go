@@ -0,0 +1,178 @@
+package monitor
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/authz"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// Handler serves the monitor endpoints.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates a monitor HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// ─── wire types ───────────────────────────────────────────────────────────────
+
+type monitorRequest struct {
+	Name            string   `json:"name" binding:"required"`
+	Description     string   `json:"description"`
+	DatasourceID    string   `json:"datasource_id" binding:"required"`
+	Query           string   `json:"query" binding:"required"`
+	IntervalSeconds int      `json:"interval_seconds" binding:"required"`
+	Detector        Detector `json:"detector"`
+	Severity        string   `json:"severity"`
+	Enabled         *bool    `json:"enabled"`
+}
+
+func (r monitorRequest) apply(m *Monitor) {
+	m.Name, m.Description = r.Name, r.Description
+	m.DatasourceID, m.Query, m.IntervalSeconds = r.DatasourceID, r.Query, r.IntervalSeconds
+	m.Detector, m.Severity = r.Detector, r.Severity
+	m.Enabled = r.Enabled == nil || *r.Enabled
+}
+
+// ─── handlers ─────────────────────────────────────────────────────────────────
+
+// List handles GET /monitors
+func (h *Handler) List(c *gin.Context) {
+	list, err := h.svc.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// Get handles GET /monitors/:id
+func (h *Handler) Get(c *gin.Context) {
+	m, err := h.svc.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": m})
+}
+
+// requireWrite answers 403 unless the caller may change datasources:
+// monitors query theirs on a schedule.
+func requireWrite(c *gin.Context) bool {
+	if identity.FromContext(c.Request.Context()).Can(identity.PermDatasourceWrite) {
+		return true
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "permission denied")})
+	return false
+}
+
+// Create handles POST /monitors
+func (h *Handler) Create(c *gin.Context) {
+	if !requireWrite(c) {
+		return
+	}
+	var req monitorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	m := &Monitor{}
+	req.apply(m)
+	if err := h.svc.Create(c.Request.Context(), m); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": m})
+}
+
+// Update handles PUT /monitors/:id
+func (h *Handler) Update(c *gin.Context) {
+	if !requireWrite(c) {
+		return
+	}
+	m, err := h.svc.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	var req monitorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.apply(m)
+	if err := h.svc.Update(c.Request.Context(), m); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": m})
+}
+
+// Delete handles DELETE /monitors/:id
+func (h *Handler) Delete(c *gin.Context) {
+	if !requireWrite(c) {
+		return
+	}
+	if err := h.svc.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Runs handles GET /monitors/:id/runs?limit=N
+func (h *Handler) Runs(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	runs, err := h.svc.Runs(c.Request.Context(), c.Param("id"), limit)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": runs})
+}
+
+// Run handles POST /monitors/:id/run
+func (h *Handler) Run(c *gin.Context) {
+	if !requireWrite(c) {
+		return
+	}
+	r, err := h.svc.RunNow(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": r})
+}
+
+func (h *Handler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "monitor not found")})
+	case errors.Is(err, ErrInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, authz.ErrDenied), errors.Is(err, connection.ErrNetworkPolicy):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// RegisterRoutes wires the handler onto r.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/monitors", h.List)
+	r.POST("/monitors", h.Create)
+	r.GET("/monitors/:id", h.Get)
+	r.PUT("/monitors/:id", h.Update)
+	r.DELETE("/monitors/:id", h.Delete)
+	r.GET("/monitors/:id/runs", h.Runs)
+	r.POST("/monitors/:id/run", h.Run)
+}
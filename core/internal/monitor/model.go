@@ -0,0 +1,76 @@
+// Package monitor runs numeric queries on a schedule — business KPIs, queue
+// depths, row counts — and flags runs whose value falls outside the band
+// expected from recent history, raising alerts through the notifier.
+package monitor
+
+import (
+	"context"
+	"time"
+)
+
+// Detection methods.
+const (
+	// MethodZScore flags values more than Threshold standard deviations from
+	// the mean of the window.
+	MethodZScore = "zscore"
+	// MethodEWMA tracks an exponentially weighted mean and variance, so the
+	// band follows trends and recent runs count more than old ones.
+	MethodEWMA = "ewma"
+)
+
+// Detector configures anomaly detection. Zero fields take their defaults.
+type Detector struct {
+	Method    string  `json:"method"`              // zscore | ewma
+	Threshold float64 `json:"threshold,omitempty"` // band half-width in standard deviations; default 3
+	Window    int     `json:"window,omitempty"`    // successful runs considered; default 30
+	MinRuns   int     `json:"min_runs,omitempty"`  // history needed before flagging; default 10
+	Alpha     float64 `json:"alpha,omitempty"`     // EWMA smoothing factor in (0, 1]; default 0.3
+}
+
+// Monitor is a scheduled numeric query.
+type Monitor struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	DatasourceID string `json:"datasource_id"`
+	// Query must return a number in the first numeric column of its first
+	// row. Time macros see the range (now - interval, now].
+	Query           string    `json:"query"`
+	IntervalSeconds int       `json:"interval_seconds"`
+	Detector        Detector  `json:"detector"`
+	Severity        string    `json:"severity"` // of anomaly alerts; default warning
+	Enabled         bool      `json:"enabled"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Run records one execution of a monitor. Expected, Lower and Upper describe
+// the band the value was judged against; they are nil until the monitor has
+// MinRuns of history.
+type Run struct {
+	ID        string    `json:"id"`
+	MonitorID string    `json:"monitor_id"`
+	RanAt     time.Time `json:"ran_at"`
+	Value     *float64  `json:"value,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Expected  *float64  `json:"expected,omitempty"`
+	Lower     *float64  `json:"lower,omitempty"`
+	Upper     *float64  `json:"upper,omitempty"`
+	Score     *float64  `json:"score,omitempty"` // deviation in standard deviations
+	Anomalous bool      `json:"anomalous"`
+}
+
+// Repository persists monitors and their run history.
+// Implemented per-driver in store/{postgres,sqlite,mysql}/.
+type Repository interface {
+	List(ctx context.Context) ([]*Monitor, error)
+	GetByID(ctx context.Context, id string) (*Monitor, error)
+	Create(ctx context.Context, m *Monitor) error
+	Update(ctx context.Context, m *Monitor) error
+	// Delete removes the monitor and its runs.
+	Delete(ctx context.Context, id string) error
+
+	AddRun(ctx context.Context, r *Run) error
+	// ListRuns returns up to limit runs of a monitor, newest first.
+	ListRuns(ctx context.Context, monitorID string, limit int) ([]*Run, error)
+}
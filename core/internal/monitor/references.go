@@ -0,0 +1,45 @@
+package monitor
+
+import (
+	"context"
+
+	"data-voyager/core/internal/connection"
+)
+
+// referenceSource reports monitors that query a datasource.
+type referenceSource struct {
+	repo Repository
+}
+
+// NewReferenceSource returns the connection.ReferenceSource for monitors. A
+// forced datasource delete removes the monitors that use it, with their runs.
+func NewReferenceSource(repo Repository) connection.ReferenceSource {
+	return &referenceSource{repo: repo}
+}
+
+func (s *referenceSource) References(ctx context.Context, datasourceID string) ([]connection.Reference, error) {
+	all, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var refs []connection.Reference
+	for _, m := range all {
+		if m.DatasourceID == datasourceID {
+			refs = append(refs, connection.Reference{Kind: "monitor", ID: m.ID, Name: m.Name})
+		}
+	}
+	return refs, nil
+}
+
+func (s *referenceSource) DeleteReferences(ctx context.Context, datasourceID string) error {
+	refs, err := s.References(ctx, datasourceID)
+	if err != nil {
+		return err
+	}
+	for _, r := range refs {
+		if err := s.repo.Delete(ctx, r.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,411 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"data-voyager/core/internal/authz"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/notification"
+	qb "data-voyager/core/internal/query_builder"
+	"data-voyager/sdk"
+)
+
+var (
+	// ErrInvalid wraps validation failures so handlers can map them to 400.
+	ErrInvalid = errors.New("invalid monitor")
+	// ErrNotFound is returned for an unknown monitor.
+	ErrNotFound = errors.New("monitor not found")
+)
+
+const (
+	minInterval     = 10 * time.Second
+	maxRunsListed   = 1000
+	defaultSeverity = notification.SeverityWarning
+)
+
+// Service manages monitors and executes them.
+type Service struct {
+	repo     Repository
+	conns    connection.Repository
+	registry *datasource.Registry
+	notifier notification.Notifier
+	now      func() time.Time
+	// preResume is how long before a run a suspended warehouse is woken;
+	// 0 never wakes one ahead.
+	preResume time.Duration
+
+	mu sync.Mutex
+	// next holds when each monitor is due, filled lazily by the scheduler.
+	next map[string]time.Time
+	// woken holds the due time each monitor's warehouse was last woken for.
+	woken map[string]time.Time
+}
+
+// NewService creates a Service. notifier may be nil to only record runs.
+func NewService(repo Repository, conns connection.Repository, registry *datasource.Registry, notifier notification.Notifier) *Service {
+	return &Service{
+		repo:     repo,
+		conns:    connection.Visible(conns),
+		registry: registry,
+		notifier: notifier,
+		now:      time.Now,
+		next:     map[string]time.Time{},
+		woken:    map[string]time.Time{},
+	}
+}
+
+// WithPreResume wakes suspended warehouses lead before a monitor falls due.
+func (s *Service) WithPreResume(lead time.Duration) *Service {
+	s.preResume = lead
+	return s
+}
+
+// List returns the monitors on datasources the caller may see.
+func (s *Service) List(ctx context.Context) ([]*Monitor, error) {
+	list, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id := identity.FromContext(ctx)
+	return slices.DeleteFunc(list, func(m *Monitor) bool { return !id.CanSee(m.DatasourceID) }), nil
+}
+
+// Get returns a monitor; those on datasources the caller may not see are
+// not found.
+func (s *Service) Get(ctx context.Context, id string) (*Monitor, error) {
+	m, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	if !identity.FromContext(ctx).CanSee(m.DatasourceID) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return m, nil
+}
+
+func (s *Service) Create(ctx context.Context, m *Monitor) error {
+	if err := s.validate(ctx, m); err != nil {
+		return err
+	}
+	m.ID = uuid.NewString()
+	return s.repo.Create(ctx, m)
+}
+
+func (s *Service) Update(ctx context.Context, m *Monitor) error {
+	if _, err := s.Get(ctx, m.ID); err != nil {
+		return err
+	}
+	if err := s.validate(ctx, m); err != nil {
+		return err
+	}
+	if err := s.repo.Update(ctx, m); err != nil {
+		return err
+	}
+	// Re-plan in case the interval changed.
+	s.mu.Lock()
+	delete(s.next, m.ID)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Service) Delete(ctx context.Context, id string) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	delete(s.next, id)
+	s.mu.Unlock()
+	return nil
+}
+
+// Runs returns up to limit recent runs of a monitor, newest first.
+func (s *Service) Runs(ctx context.Context, id string, limit int) ([]*Run, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxRunsListed {
+		limit = maxRunsListed
+	}
+	return s.repo.ListRuns(ctx, id, limit)
+}
+
+func (s *Service) validate(ctx context.Context, m *Monitor) error {
+	if m.Name == "" || m.Query == "" {
+		return fmt.Errorf("%w: name and query are required", ErrInvalid)
+	}
+	if _, err := s.conns.GetByID(ctx, m.DatasourceID); err != nil {
+		return fmt.Errorf("%w: datasource %q not found", ErrInvalid, m.DatasourceID)
+	}
+	if err := authz.CheckQuery(ctx, m.DatasourceID); err != nil {
+		return err
+	}
+	if time.Duration(m.IntervalSeconds)*time.Second < minInterval {
+		return fmt.Errorf("%w: interval_seconds must be at least %d", ErrInvalid, int(minInterval/time.Second))
+	}
+	switch m.Severity {
+	case "":
+		m.Severity = defaultSeverity
+	case notification.SeverityInfo, notification.SeverityWarning, notification.SeverityCritical:
+	default:
+		return fmt.Errorf("%w: unknown severity %q", ErrInvalid, m.Severity)
+	}
+	d := m.Detector.withDefaults()
+	switch {
+	case d.Method != MethodZScore && d.Method != MethodEWMA:
+		return fmt.Errorf("%w: unknown detector method %q", ErrInvalid, d.Method)
+	case d.Threshold <= 0:
+		return fmt.Errorf("%w: detector threshold must be positive", ErrInvalid)
+	case d.Window < 2 || d.MinRuns < 2 || d.MinRuns > d.Window:
+		return fmt.Errorf("%w: detector needs 2 <= min_runs <= window", ErrInvalid)
+	case d.Alpha <= 0 || d.Alpha > 1:
+		return fmt.Errorf("%w: detector alpha must be in (0, 1]", ErrInvalid)
+	}
+	m.Detector = d
+	return nil
+}
+
+// RunNow executes a monitor immediately, records the run and raises any
+// resulting alert.
+func (s *Service) RunNow(ctx context.Context, id string) (*Run, error) {
+	m, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.run(ctx, m)
+}
+
+func (s *Service) run(ctx context.Context, m *Monitor) (*Run, error) {
+	interval := time.Duration(m.IntervalSeconds) * time.Second
+	now := s.now().UTC()
+	r := &Run{ID: uuid.NewString(), MonitorID: m.ID, RanAt: now}
+
+	// History is read before the new run is stored so it is not judged
+	// against itself.
+	history, herr := s.history(ctx, m)
+	v, err := s.measure(ctx, m, qb.TimeRange{From: now.Add(-interval), To: now}, interval)
+	switch {
+	case err != nil:
+		r.Error = err.Error()
+	case herr != nil:
+		r.Value = &v
+		r.Error = fmt.Sprintf("load history: %s", herr)
+	default:
+		r.Value = &v
+		if vd, ok := m.Detector.Evaluate(history, v); ok {
+			r.Expected, r.Lower, r.Upper = &vd.Expected, &vd.Lower, &vd.Upper
+			if !math.IsNaN(vd.Score) {
+				r.Score = &vd.Score
+			}
+			r.Anomalous = vd.Anomalous
+		}
+	}
+	if err := s.repo.AddRun(ctx, r); err != nil {
+		return nil, fmt.Errorf("record run: %w", err)
+	}
+	s.alert(ctx, m, r)
+	return r, nil
+}
+
+// history returns the values of recent successful runs, oldest first.
+func (s *Service) history(ctx context.Context, m *Monitor) ([]float64, error) {
+	runs, err := s.repo.ListRuns(ctx, m.ID, m.Detector.withDefaults().Window)
+	if err != nil {
+		return nil, err
+	}
+	var vals []float64
+	for _, r := range runs {
+		if r.Value != nil && r.Error == "" {
+			vals = append(vals, *r.Value)
+		}
+	}
+	slices.Reverse(vals)
+	return vals, nil
+}
+
+// measure runs the monitor's query and extracts its value.
+func (s *Service) measure(ctx context.Context, m *Monitor, tr qb.TimeRange, timeout time.Duration) (float64, error) {
+	conn, err := s.conns.GetByID(ctx, m.DatasourceID)
+	if err != nil {
+		return 0, fmt.Errorf("datasource not found")
+	}
+	if err := authz.CheckQuery(ctx, conn.ID); err != nil {
+		return 0, err
+	}
+	if err := connection.CheckOrigin(ctx, conn); err != nil {
+		return 0, err
+	}
+	plugin, ok := s.registry.Get(conn.Type)
+	if !ok {
+		return 0, fmt.Errorf("plugin not found for type %s", conn.Type)
+	}
+	cfg, err := plugin.ParseConfig(conn.Config)
+	if err != nil {
+		return 0, fmt.Errorf("parse config: %w", err)
+	}
+	query, err := qb.RenderQuery(m.Query, qb.BuildContext(tr, nil, 1))
+	if err != nil {
+		return 0, err
+	}
+	if query, _, err = qb.ExpandMacros(query, qb.DialectFor(string(conn.Type)), tr, 0); err != nil {
+		return 0, err
+	}
+
+	// A run may take at most one interval so runs never pile up.
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	dbConn, err := plugin.Connect(ctx, cfg)
+	if err != nil {
+		return 0, fmt.Errorf("connect: %w", err)
+	}
+	defer func() { _ = dbConn.Close() }()
+	res, err := dbConn.Query(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("query failed: %w", err)
+	}
+	return firstNumber(res)
+}
+
+// firstNumber returns the first numeric value in the first row.
+func firstNumber(res *sdk.QueryResult) (float64, error) {
+	if res == nil || len(res.Frames) == 0 {
+		return 0, fmt.Errorf("query returned no result")
+	}
+	for _, f := range res.Frames[0].Fields {
+		if len(f.Values) == 0 {
+			return 0, fmt.Errorf("query returned no rows")
+		}
+		if v, ok := sdk.Float64Value(f.Values[0]); ok {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("query returned no numeric value in its first row")
+}
+
+// alert raises a notification for an anomalous or failed run.
+func (s *Service) alert(ctx context.Context, m *Monitor, r *Run) {
+	if s.notifier == nil || (!r.Anomalous && r.Error == "") {
+		return
+	}
+	ev := notification.Event{
+		Source: m.Name,
+		Labels: map[string]string{"monitor_id": m.ID, "datasource_id": m.DatasourceID},
+		Time:   r.RanAt,
+	}
+	if r.Error != "" {
+		ev.Type = notification.EventScheduleFailure
+		ev.Severity = notification.SeverityWarning
+		ev.Title = fmt.Sprintf("Monitor %s failed", m.Name)
+		ev.Body = r.Error
+	} else {
+		ev.Type = notification.EventAlert
+		ev.Severity = m.Severity
+		ev.Title = fmt.Sprintf("Monitor %s is anomalous", m.Name)
+		ev.Body = fmt.Sprintf("Value %g is outside the expected range %g to %g (expected %g).",
+			*r.Value, *r.Lower, *r.Upper, *r.Expected)
+	}
+	if err := s.notifier.Notify(ctx, ev); err != nil {
+		slog.Warn("monitor notification failed", "monitor", m.Name, "err", err)
+	}
+}
+
+// Schedule runs due monitors every tick until ctx is done. Monitors run one
+// after another; one that is still running when it next falls due simply
+// runs late.
+func (s *Service) Schedule(ctx context.Context, tick time.Duration) {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s.runDue(ctx)
+	}
+}
+
+func (s *Service) runDue(ctx context.Context) {
+	monitors, err := s.repo.List(ctx)
+	if err != nil {
+		slog.Warn("list monitors failed", "err", err)
+		return
+	}
+	for _, m := range monitors {
+		if ctx.Err() != nil {
+			return
+		}
+		if !m.Enabled {
+			continue
+		}
+		if !s.due(ctx, m) {
+			s.wakeAhead(ctx, m)
+			continue
+		}
+		if _, err := s.run(ctx, m); err != nil {
+			slog.Warn("monitor run failed", "monitor", m.Name, "err", err)
+		}
+		s.mu.Lock()
+		s.next[m.ID] = s.now().Add(time.Duration(m.IntervalSeconds) * time.Second)
+		s.mu.Unlock()
+	}
+}
+
+// due reports whether m should run now. The first check after start-up
+// resumes from the last recorded run rather than running everything at once.
+func (s *Service) due(ctx context.Context, m *Monitor) bool {
+	s.mu.Lock()
+	next, ok := s.next[m.ID]
+	s.mu.Unlock()
+	if !ok {
+		runs, err := s.repo.ListRuns(ctx, m.ID, 1)
+		if err != nil {
+			return false
+		}
+		if len(runs) > 0 {
+			next = runs[0].RanAt.Add(time.Duration(m.IntervalSeconds) * time.Second)
+		}
+		s.mu.Lock()
+		s.next[m.ID] = next
+		s.mu.Unlock()
+	}
+	return !s.now().Before(next)
+}
+
+// wakeAhead asks the warehouse behind m to resume when m falls due within
+// the pre-resume lead, once per due time, so the run finds it running.
+func (s *Service) wakeAhead(ctx context.Context, m *Monitor) {
+	if s.preResume <= 0 {
+		return
+	}
+	s.mu.Lock()
+	next := s.next[m.ID]
+	wake := !s.woken[m.ID].Equal(next) && next.Sub(s.now()) <= s.preResume
+	if wake {
+		s.woken[m.ID] = next
+	}
+	s.mu.Unlock()
+	if !wake {
+		return
+	}
+	conn, err := s.conns.GetByID(ctx, m.DatasourceID)
+	if err != nil {
+		return
+	}
+	if plugin, ok := s.registry.Get(conn.Type); ok {
+		connection.WakeWarehouse(ctx, plugin, conn)
+	}
+}
@@ -0,0 +1,351 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/notification"
+	"data-voyager/sdk"
+)
+
+// ─── Detector ─────────────────────────────────────────────────────────────────
+
+func TestDetector_ZScore(t *testing.T) {
+	history := []float64{10, 12, 11, 9, 10, 11, 10, 12, 9, 11}
+	d := Detector{Method: MethodZScore}
+
+	vd, ok := d.Evaluate(history, 10.5)
+	require.True(t, ok)
+	assert.False(t, vd.Anomalous)
+	assert.InDelta(t, 10.5, vd.Expected, 1e-9)
+
+	vd, _ = d.Evaluate(history, 30)
+	assert.True(t, vd.Anomalous)
+	assert.Greater(t, vd.Score, 3.0)
+
+	_, ok = d.Evaluate(history[:5], 30)
+	assert.False(t, ok, "too little history to judge")
+}
+
+func TestDetector_EWMAFollowsTrend(t *testing.T) {
+	var history []float64
+	for i := 0; i < 30; i++ {
+		history = append(history, float64(100+i*10)+float64(i%3))
+	}
+	// A z-score over the whole window sees the next step of the trend as
+	// unremarkable only because the window's spread is huge; EWMA centres
+	// near the recent values instead.
+	vd, ok := Detector{Method: MethodEWMA, Alpha: 0.5}.Evaluate(history, 400)
+	require.True(t, ok)
+	assert.Greater(t, vd.Expected, 350.0)
+	assert.False(t, vd.Anomalous)
+
+	vd, _ = Detector{Method: MethodEWMA, Alpha: 0.5}.Evaluate(history, 100)
+	assert.True(t, vd.Anomalous)
+}
+
+func TestDetector_ConstantHistory(t *testing.T) {
+	history := []float64{5, 5, 5, 5, 5, 5, 5, 5, 5, 5}
+	vd, ok := Detector{}.Evaluate(history, 5)
+	require.True(t, ok)
+	assert.False(t, vd.Anomalous)
+	assert.True(t, math.IsNaN(vd.Score))
+
+	vd, _ = Detector{}.Evaluate(history, 6)
+	assert.True(t, vd.Anomalous)
+}
+
+// ─── Service ──────────────────────────────────────────────────────────────────
+
+type memRepo struct {
+	mu       sync.Mutex
+	monitors map[string]*Monitor
+	runs     []*Run
+}
+
+func (r *memRepo) List(context.Context) ([]*Monitor, error) {
+	var out []*Monitor
+	for _, m := range r.monitors {
+		out = append(out, m)
+	}
+	return out, nil
+}
+func (r *memRepo) GetByID(_ context.Context, id string) (*Monitor, error) {
+	if m, ok := r.monitors[id]; ok {
+		return m, nil
+	}
+	return nil, errors.New("not found")
+}
+func (r *memRepo) Create(_ context.Context, m *Monitor) error { r.monitors[m.ID] = m; return nil }
+func (r *memRepo) Update(_ context.Context, m *Monitor) error { r.monitors[m.ID] = m; return nil }
+func (r *memRepo) Delete(_ context.Context, id string) error  { delete(r.monitors, id); return nil }
+func (r *memRepo) AddRun(_ context.Context, run *Run) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs = append(r.runs, run)
+	return nil
+}
+func (r *memRepo) ListRuns(_ context.Context, id string, limit int) ([]*Run, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*Run
+	for _, run := range r.runs {
+		if run.MonitorID == id {
+			out = append(out, run)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].RanAt.After(out[j].RanAt) })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+type stubConns struct{ connection.Repository }
+
+func (stubConns) GetByID(_ context.Context, id string) (*connection.Connection, error) {
+	if id != "ds1" {
+		return nil, errors.New("not found")
+	}
+	return &connection.Connection{ID: id, Type: "stub", Config: json.RawMessage(`{}`)}, nil
+}
+
+type stubConfig struct{}
+
+func (stubConfig) Validate() error             { return nil }
+func (stubConfig) GetConnectionString() string { return "" }
+
+// stubPlugin answers every query with the next value; a nil value fails.
+type stubPlugin struct {
+	sdk.DatasourcePlugin
+	values  []any
+	queries []string
+}
+
+func (p *stubPlugin) GetType() sdk.DataSourceType { return "stub" }
+func (p *stubPlugin) ParseConfig(json.RawMessage) (sdk.ConnectionConfig, error) {
+	return stubConfig{}, nil
+}
+func (p *stubPlugin) Connect(context.Context, sdk.ConnectionConfig) (sdk.Connection, error) {
+	return &stubConn{p: p}, nil
+}
+
+type stubConn struct {
+	sdk.Connection
+	p *stubPlugin
+}
+
+func (c *stubConn) Query(_ context.Context, q string, _ ...any) (*sdk.QueryResult, error) {
+	c.p.queries = append(c.p.queries, q)
+	v := c.p.values[0]
+	c.p.values = c.p.values[1:]
+	if v == nil {
+		return nil, errors.New("relation does not exist")
+	}
+	return &sdk.QueryResult{Frames: []*sdk.DataFrame{{Fields: []sdk.Field{
+		{Name: "label", Values: []any{"orders"}},
+		{Name: "n", Values: []any{v}},
+	}}}}, nil
+}
+func (c *stubConn) Close() error { return nil }
+
+type recorder struct{ events []notification.Event }
+
+func (r *recorder) Notify(_ context.Context, ev notification.Event) error {
+	r.events = append(r.events, ev)
+	return nil
+}
+
+func newTestService(values ...any) (*Service, *memRepo, *stubPlugin, *recorder) {
+	plugin := &stubPlugin{values: values}
+	reg := datasource.NewRegistry()
+	reg.Register(plugin)
+	repo := &memRepo{monitors: map[string]*Monitor{}}
+	rec := &recorder{}
+	return NewService(repo, stubConns{}, reg, rec), repo, plugin, rec
+}
+
+func TestService_FlagsAnomalyAndAlerts(t *testing.T) {
+	values := []any{}
+	for i := 0; i < 5; i++ {
+		values = append(values, int64(100+i%2))
+	}
+	values = append(values, "500.5", nil)
+	svc, _, plugin, rec := newTestService(values...)
+
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { now = now.Add(time.Minute); return now }
+
+	m := &Monitor{
+		Name: "Orders", DatasourceID: "ds1", IntervalSeconds: 60, Enabled: true,
+		Query:    "SELECT 'orders', count(*) FROM orders WHERE $__timeFilter(created_at)",
+		Detector: Detector{MinRuns: 5},
+	}
+	require.NoError(t, svc.Create(context.Background(), m))
+	assert.Equal(t, notification.SeverityWarning, m.Severity)
+	assert.Equal(t, MethodZScore, m.Detector.Method, "defaults are stored explicitly")
+
+	for i := 0; i < 5; i++ {
+		r, err := svc.RunNow(context.Background(), m.ID)
+		require.NoError(t, err)
+		assert.False(t, r.Anomalous)
+		assert.Nil(t, r.Expected, "no band until min_runs of history")
+	}
+	assert.Contains(t, plugin.queries[0], "created_at BETWEEN '2024-05-01T00:00:00Z' AND '2024-05-01T00:01:00Z'")
+
+	r, err := svc.RunNow(context.Background(), m.ID)
+	require.NoError(t, err)
+	assert.True(t, r.Anomalous)
+	assert.Equal(t, 500.5, *r.Value)
+	require.Len(t, rec.events, 1)
+	assert.Equal(t, notification.EventAlert, rec.events[0].Type)
+	assert.Equal(t, "Orders", rec.events[0].Source)
+
+	r, err = svc.RunNow(context.Background(), m.ID)
+	require.NoError(t, err)
+	assert.Contains(t, r.Error, "relation does not exist")
+	require.Len(t, rec.events, 2)
+	assert.Equal(t, notification.EventScheduleFailure, rec.events[1].Type)
+}
+
+func TestService_Validate(t *testing.T) {
+	svc, _, _, _ := newTestService()
+	ctx := context.Background()
+
+	err := svc.Create(ctx, &Monitor{Name: "x", Query: "SELECT 1", DatasourceID: "nope", IntervalSeconds: 60})
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	err = svc.Create(ctx, &Monitor{Name: "x", Query: "SELECT 1", DatasourceID: "ds1", IntervalSeconds: 1})
+	assert.ErrorContains(t, err, "interval_seconds")
+
+	err = svc.Create(ctx, &Monitor{Name: "x", Query: "SELECT 1", DatasourceID: "ds1", IntervalSeconds: 60,
+		Detector: Detector{Method: MethodEWMA, Alpha: 2}})
+	assert.ErrorContains(t, err, "alpha")
+
+	err = svc.Create(ctx, &Monitor{Name: "x", Query: "SELECT 1", DatasourceID: "ds1", IntervalSeconds: 60,
+		Detector: Detector{Window: 5, MinRuns: 10}})
+	assert.ErrorContains(t, err, "min_runs")
+}
+
+func TestService_RunDueResumesFromLastRun(t *testing.T) {
+	svc, repo, plugin, _ := newTestService(int64(1), int64(2))
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	repo.monitors["m1"] = &Monitor{ID: "m1", Name: "a", DatasourceID: "ds1", Query: "SELECT 1", IntervalSeconds: 600, Enabled: true}
+	repo.monitors["m2"] = &Monitor{ID: "m2", Name: "b", DatasourceID: "ds1", Query: "SELECT 2", IntervalSeconds: 600}
+	repo.runs = []*Run{{ID: "old", MonitorID: "m1", RanAt: now.Add(-5 * time.Minute)}}
+
+	svc.runDue(context.Background())
+	assert.Empty(t, plugin.queries, "last run was 5 minutes ago; disabled monitors never run")
+
+	now = now.Add(5 * time.Minute)
+	svc.runDue(context.Background())
+	assert.Equal(t, []string{"SELECT 1"}, plugin.queries)
+
+	svc.runDue(context.Background())
+	assert.Len(t, plugin.queries, 1, "not due again until the interval passes")
+}
+
+// suspendingPlugin is a stubPlugin whose warehouse is always suspended.
+type suspendingPlugin struct {
+	*stubPlugin
+	resumed int
+}
+
+func (p *suspendingPlugin) WarehouseStatus(context.Context, sdk.ConnectionConfig) (*sdk.WarehouseStatus, error) {
+	return &sdk.WarehouseStatus{State: sdk.WarehouseSuspended}, nil
+}
+
+func (p *suspendingPlugin) ResumeWarehouse(context.Context, sdk.ConnectionConfig) error {
+	p.resumed++
+	return nil
+}
+
+func TestService_WakesWarehouseBeforeRun(t *testing.T) {
+	plugin := &suspendingPlugin{stubPlugin: &stubPlugin{values: []any{int64(1)}}}
+	reg := datasource.NewRegistry()
+	reg.Register(plugin)
+	repo := &memRepo{monitors: map[string]*Monitor{}}
+	svc := NewService(repo, stubConns{}, reg, nil).WithPreResume(2 * time.Minute)
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	repo.monitors["m1"] = &Monitor{ID: "m1", Name: "a", DatasourceID: "ds1", Query: "SELECT 1", IntervalSeconds: 600, Enabled: true}
+	repo.runs = []*Run{{ID: "old", MonitorID: "m1", RanAt: now.Add(-5 * time.Minute)}}
+
+	svc.runDue(context.Background())
+	assert.Zero(t, plugin.resumed, "due in 5 minutes, beyond the lead")
+
+	now = now.Add(4 * time.Minute)
+	svc.runDue(context.Background())
+	svc.runDue(context.Background())
+	assert.Equal(t, 1, plugin.resumed, "woken once within the lead")
+	assert.Empty(t, plugin.queries)
+
+	now = now.Add(time.Minute)
+	svc.runDue(context.Background())
+	assert.Equal(t, []string{"SELECT 1"}, plugin.queries)
+}
+
+func TestService_HidesInvisibleDatasources(t *testing.T) {
+	svc, repo, _, _ := newTestService(int64(1))
+	m := &Monitor{Name: "x", Query: "SELECT 1", DatasourceID: "ds1", IntervalSeconds: 60}
+	require.NoError(t, svc.Create(context.Background(), m))
+	id := m.ID
+
+	ctx := identity.With(context.Background(), &identity.Identity{Username: "bob", Role: identity.RoleEditor, Datasources: []string{"other"}})
+	list, err := svc.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+	_, err = svc.Get(ctx, id)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = svc.RunNow(ctx, id)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, svc.Update(ctx, &Monitor{ID: id, Name: "x", Query: "SELECT 1", DatasourceID: "ds1", IntervalSeconds: 60}), ErrNotFound)
+	assert.ErrorIs(t, svc.Delete(ctx, id), ErrNotFound)
+	assert.ErrorIs(t, svc.Create(ctx, &Monitor{Name: "y", Query: "SELECT 1", DatasourceID: "ds1", IntervalSeconds: 60}), ErrInvalid)
+	assert.Len(t, repo.monitors, 1)
+}
+
+func TestRoutes_ChangesNeedDatasourceWrite(t *testing.T) {
+	svc, repo, _, _ := newTestService()
+	repo.monitors["m1"] = &Monitor{ID: "m1", Name: "x", Query: "SELECT 1", DatasourceID: "ds1", IntervalSeconds: 60}
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(identity.With(c.Request.Context(), &identity.Identity{Username: "v", Role: identity.RoleViewer}))
+	})
+	RegisterRoutes(&r.RouterGroup, NewHandler(svc))
+
+	body := `{"name":"x","query":"SELECT 1","datasource_id":"ds1","interval_seconds":60}`
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/monitors", strings.NewReader(body)),
+		httptest.NewRequest(http.MethodPut, "/monitors/m1", strings.NewReader(body)),
+		httptest.NewRequest(http.MethodDelete, "/monitors/m1", nil),
+		httptest.NewRequest(http.MethodPost, "/monitors/m1/run", nil),
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code, req.Method+" "+req.URL.Path)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/monitors/m1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, repo.monitors, 1)
+}
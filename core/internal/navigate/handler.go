@@ -0,0 +1,58 @@
+package navigate
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// Handler serves the command palette endpoint.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates a navigation HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// Navigate handles GET /navigate?q=TERM&kinds=datasource,table&limit=N
+func (h *Handler) Navigate(c *gin.Context) {
+	if !identity.FromContext(c.Request.Context()).Can(identity.PermDatasourceRead) {
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "permission denied")})
+		return
+	}
+	opts := Options{Query: c.Query("q")}
+	if v := c.Query("kinds"); v != "" {
+		opts.Kinds = strings.Split(v, ",")
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be an integer"})
+			return
+		}
+		opts.Limit = n
+	}
+	res, err := h.svc.Navigate(c.Request.Context(), opts)
+	if err != nil {
+		if errors.Is(err, ErrInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// RegisterRoutes wires the handler onto r.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/navigate", h.Navigate)
+}
@@ -0,0 +1,47 @@
+// Package navigate backs the command palette: one call that returns the
+// datasources, tables, saved views and dashboards a user is likely to want
+// to jump to, ranked by how well they match what was typed and by how
+// recently and often the user has used them.
+package navigate
+
+// Item kinds.
+const (
+	KindDatasource = "datasource"
+	KindTable      = "table"
+	KindSavedView  = "saved_view"
+	KindDashboard  = "dashboard"
+)
+
+// Kinds lists every item kind in the order equally ranked items are shown.
+var Kinds = []string{KindDatasource, KindTable, KindSavedView, KindDashboard}
+
+// Item is one palette entry. ID is the datasource, saved view or dashboard
+// ID, or the table name for a table; DatasourceID is set for tables and
+// saved views.
+type Item struct {
+	Kind         string `json:"kind"`
+	ID           string `json:"id"`
+	Title        string `json:"title"`
+	Subtitle     string `json:"subtitle,omitempty"`
+	DatasourceID string `json:"datasource_id,omitempty"`
+	// LastUsed is the latest day the caller queried the datasource or
+	// table, empty when they have not in the usage window.
+	LastUsed string  `json:"last_used,omitempty"`
+	Score    float64 `json:"score"`
+}
+
+// Result answers a palette request. Without a search term Matches is empty
+// and Recent lists what the caller used last; with one Recent is empty.
+type Result struct {
+	Recent  []Item `json:"recent"`
+	Matches []Item `json:"matches"`
+}
+
+// Options narrows a request.
+type Options struct {
+	// Query is the search term; empty asks for recent items.
+	Query string
+	// Kinds limits the item kinds returned; empty means all.
+	Kinds []string
+	Limit int
+}
@@ -0,0 +1,257 @@
+package navigate
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/dashboard"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/savedview"
+	"data-voyager/core/internal/usage"
+)
+
+const (
+	// DefaultLimit and MaxLimit bound how many items one list holds.
+	DefaultLimit = 20
+	MaxLimit     = 50
+	// usageDays is how far back usage counts towards ranking.
+	usageDays = 90
+)
+
+// ErrInvalid is returned for options out of range.
+var ErrInvalid = errors.New("invalid navigation options")
+
+// Service assembles palette results.
+type Service struct {
+	conns      connection.Repository
+	dashboards *dashboard.Service
+	views      *savedview.Service
+	// usage is nil without a statistics store; tables are then known only
+	// through saved views and nothing counts as recently used.
+	usage usage.Repository
+	now   func() time.Time
+}
+
+// NewService creates a navigation Service. usageRepo may be nil.
+func NewService(conns connection.Repository, dashboards *dashboard.Service, views *savedview.Service, usageRepo usage.Repository) *Service {
+	return &Service{conns: conns, dashboards: dashboards, views: views, usage: usageRepo, now: time.Now}
+}
+
+// use is how a datasource or table has been queried in the usage window:
+// by the caller (mine, last) and by everyone (all).
+type use struct {
+	mine, all int64
+	last      string
+}
+
+type useKey struct{ datasourceID, table string }
+
+// Navigate returns ctx's identity's recent items, or the items matching
+// opts.Query, ranked best first.
+func (s *Service) Navigate(ctx context.Context, opts Options) (*Result, error) {
+	if opts.Limit == 0 {
+		opts.Limit = DefaultLimit
+	}
+	if opts.Limit < 0 || opts.Limit > MaxLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalid, MaxLimit)
+	}
+	for _, k := range opts.Kinds {
+		if !slices.Contains(Kinds, k) {
+			return nil, fmt.Errorf("%w: unknown kind %q", ErrInvalid, k)
+		}
+	}
+	want := func(kind string) bool { return len(opts.Kinds) == 0 || slices.Contains(opts.Kinds, kind) }
+	q := strings.ToLower(strings.TrimSpace(opts.Query))
+	caller := identity.FromContext(ctx)
+	var username string
+	if caller != nil {
+		username = caller.Username
+	}
+	today := s.now().UTC().Truncate(24 * time.Hour)
+
+	conns, err := s.conns.List(ctx, connection.Filter{})
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]string{}
+	var visible []*connection.Connection
+	for _, c := range conns {
+		if caller.CanSee(c.ID) {
+			names[c.ID] = c.Name
+			visible = append(visible, c)
+		}
+	}
+
+	uses := map[useKey]*use{}
+	if s.usage != nil && (want(KindDatasource) || want(KindTable)) {
+		counts, err := s.usage.Counts(ctx, today.AddDate(0, 0, -usageDays).Format(usage.DayFormat))
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range counts {
+			if _, ok := names[c.DatasourceID]; !ok {
+				continue
+			}
+			k := useKey{c.DatasourceID, c.TableName}
+			u := uses[k]
+			if u == nil {
+				u = &use{}
+				uses[k] = u
+			}
+			u.all += c.Queries
+			if c.Username == username {
+				u.mine += c.Queries
+				u.last = max(u.last, c.Day)
+			}
+		}
+	}
+
+	var items []Item
+	add := func(it Item, u *use, terms ...string) {
+		quality := 0.0
+		for _, t := range terms {
+			quality = max(quality, match(q, t))
+		}
+		switch {
+		case q != "" && quality == 0:
+			return
+		case q == "" && (u == nil || u.mine == 0):
+			return
+		}
+		it.Score = quality * 100
+		if u != nil {
+			it.LastUsed = u.last
+			it.Score += boost(u, today)
+		}
+		it.Score = math.Round(it.Score*10) / 10
+		items = append(items, it)
+	}
+
+	if want(KindDatasource) {
+		for _, c := range visible {
+			add(Item{Kind: KindDatasource, ID: c.ID, Title: c.Name, Subtitle: string(c.Type)},
+				uses[useKey{c.ID, ""}], c.Name, c.Alias)
+		}
+	}
+	if want(KindTable) {
+		for k, u := range uses {
+			if k.table == "" {
+				continue
+			}
+			short := k.table[strings.LastIndexByte(k.table, '.')+1:]
+			add(Item{Kind: KindTable, ID: k.table, Title: k.table, Subtitle: names[k.datasourceID], DatasourceID: k.datasourceID},
+				u, k.table, short)
+		}
+	}
+	if want(KindSavedView) && s.views != nil {
+		views, err := s.views.List(ctx, savedview.ListFilter{})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range views {
+			table := v.Table
+			if v.Schema != "" {
+				table = v.Schema + "." + v.Table
+			}
+			// A view is used when its owner saves it; that is all that is
+			// known of its use.
+			var u *use
+			if v.Owner == username {
+				u = &use{mine: 1, last: v.UpdatedAt.UTC().Format(usage.DayFormat)}
+			}
+			add(Item{Kind: KindSavedView, ID: v.ID, Title: v.Name, Subtitle: names[v.DatasourceID] + " · " + table,
+				DatasourceID: v.DatasourceID}, u, v.Name)
+		}
+	}
+	if want(KindDashboard) && q != "" && s.dashboards != nil {
+		dashboards, err := s.dashboards.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range dashboards {
+			add(Item{Kind: KindDashboard, ID: d.ID, Title: d.Name, Subtitle: d.Description}, nil, d.Name)
+		}
+	}
+
+	slices.SortFunc(items, func(a, b Item) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score),
+			cmp.Compare(slices.Index(Kinds, a.Kind), slices.Index(Kinds, b.Kind)),
+			cmp.Compare(a.Title, b.Title), cmp.Compare(a.DatasourceID, b.DatasourceID))
+	})
+	if len(items) > opts.Limit {
+		items = items[:opts.Limit]
+	}
+	res := &Result{Recent: []Item{}, Matches: []Item{}}
+	if q == "" {
+		res.Recent = append(res.Recent, items...)
+	} else {
+		res.Matches = append(res.Matches, items...)
+	}
+	return res, nil
+}
+
+// match rates how well the lower-case term q matches name, from 1 for the
+// whole name through a prefix, a word prefix and a substring down to its
+// letters appearing in order; 0 is no match. An empty q matches nothing.
+func match(q, name string) float64 {
+	name = strings.ToLower(name)
+	switch {
+	case q == "" || name == "":
+		return 0
+	case name == q:
+		return 1
+	case strings.HasPrefix(name, q):
+		return 0.8
+	case strings.Contains(name, q):
+		for i := strings.Index(name, q); i >= 0; {
+			if !isWordChar(name[i-1]) {
+				return 0.6
+			}
+			j := strings.Index(name[i+1:], q)
+			if j < 0 {
+				break
+			}
+			i += j + 1
+		}
+		return 0.4
+	case subsequence(q, name):
+		return 0.2
+	}
+	return 0
+}
+
+func isWordChar(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= '0' && b <= '9'
+}
+
+// subsequence reports whether the runes of q appear in s in order.
+func subsequence(q, s string) bool {
+	for _, r := range q {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(string(r)):]
+	}
+	return true
+}
+
+// boost ranks use: up to 30 points for how recently the caller used it, up
+// to 20 for how often, and up to 10 for how popular it is with everyone.
+func boost(u *use, today time.Time) float64 {
+	var b float64
+	if last, err := time.Parse(usage.DayFormat, u.last); err == nil {
+		age := today.Sub(last).Hours() / 24
+		b += 30 * max(0, 1-age/usageDays)
+	}
+	b += min(20, 5*math.Log2(1+float64(u.mine)))
+	b += min(10, 2*math.Log2(1+float64(u.all)))
+	return b
+}
@@ -0,0 +1,182 @@
+package navigate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/dashboard"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/savedview"
+	"data-voyager/core/internal/usage"
+)
+
+type stubConns struct{ connection.Repository }
+
+func (stubConns) List(context.Context, connection.Filter) ([]*connection.Connection, error) {
+	return []*connection.Connection{
+		{ID: "pg", Name: "Orders DB", Type: "postgresql", Alias: "analytics"},
+		{ID: "ch", Name: "Events", Type: "clickhouse"},
+		{ID: "secret", Name: "Payroll", Type: "postgresql"},
+	}, nil
+}
+
+type stubDashboards struct{ dashboard.Repository }
+
+func (stubDashboards) List(context.Context) ([]*dashboard.Dashboard, error) {
+	return []*dashboard.Dashboard{{ID: "d1", Name: "Order funnel"}, {ID: "d2", Name: "Latency"}}, nil
+}
+
+type stubViews struct{ savedview.Repository }
+
+func (stubViews) List(_ context.Context, f savedview.ListFilter) ([]*savedview.View, error) {
+	views := []*savedview.View{
+		{ID: "v1", Name: "Open orders", DatasourceID: "pg", Schema: "public", Table: "orders", Owner: "ann",
+			UpdatedAt: time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)},
+		{ID: "v2", Name: "Bob's orders", DatasourceID: "pg", Table: "orders", Owner: "bob"},
+	}
+	var out []*savedview.View
+	for _, v := range views {
+		if f.Owner == "" || v.Owner == f.Owner || v.Shared {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+type stubUsage struct{ usage.Repository }
+
+func (stubUsage) Counts(_ context.Context, since string) ([]*usage.Count, error) {
+	all := []*usage.Count{
+		{Day: "2026-03-09", DatasourceID: "pg", Username: "ann", Queries: 4},
+		{Day: "2026-03-09", DatasourceID: "pg", TableName: "public.orders", Username: "ann", Queries: 4},
+		{Day: "2026-02-01", DatasourceID: "ch", Username: "ann", Queries: 1},
+		{Day: "2026-02-01", DatasourceID: "ch", TableName: "order_events", Username: "ann", Queries: 1},
+		{Day: "2026-03-01", DatasourceID: "ch", TableName: "order_events", Username: "bob", Queries: 50},
+		{Day: "2026-03-01", DatasourceID: "secret", TableName: "salaries", Username: "ann", Queries: 9},
+		{Day: "2025-01-01", DatasourceID: "pg", TableName: "old_orders", Username: "ann", Queries: 9},
+	}
+	var out []*usage.Count
+	for _, c := range all {
+		if c.Day >= since {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func newTestService(withUsage bool) *Service {
+	var u usage.Repository
+	if withUsage {
+		u = stubUsage{}
+	}
+	svc := NewService(stubConns{}, dashboard.NewService(stubDashboards{}, stubConns{}, nil),
+		savedview.NewService(stubViews{}, stubConns{}), u)
+	svc.now = func() time.Time { return time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC) }
+	return svc
+}
+
+func ann() context.Context {
+	return identity.With(context.Background(), &identity.Identity{Username: "ann", Role: identity.RoleViewer,
+		Datasources: []string{"pg", "ch"}})
+}
+
+func titles(items []Item) []string {
+	out := make([]string, len(items))
+	for i, it := range items {
+		out[i] = it.Kind + ":" + it.Title
+	}
+	return out
+}
+
+func TestNavigate_Recent(t *testing.T) {
+	res, err := newTestService(true).Navigate(ann(), Options{})
+	require.NoError(t, err)
+	assert.Empty(t, res.Matches)
+	assert.Equal(t, []string{
+		"datasource:Orders DB", "table:public.orders", "saved_view:Open orders",
+		"table:order_events", "datasource:Events",
+	}, titles(res.Recent), "hidden datasources, other users' views and old usage are left out")
+	assert.Equal(t, "2026-03-09", res.Recent[0].LastUsed)
+	assert.Equal(t, "pg", res.Recent[1].DatasourceID)
+	assert.Equal(t, "Orders DB · public.orders", res.Recent[2].Subtitle)
+
+	res, err = newTestService(false).Navigate(ann(), Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"saved_view:Open orders"}, titles(res.Recent))
+}
+
+func TestNavigate_Search(t *testing.T) {
+	svc := newTestService(true)
+	res, err := svc.Navigate(ann(), Options{Query: "Order"})
+	require.NoError(t, err)
+	assert.Empty(t, res.Recent)
+	assert.Equal(t, []string{
+		"datasource:Orders DB",   // prefix, used recently; datasources win ties
+		"table:public.orders",    // prefix of the unqualified name, used recently
+		"table:order_events",     // prefix, used long ago but popular with others
+		"saved_view:Open orders", // word prefix, saved recently
+		"dashboard:Order funnel", // prefix, never used
+	}, titles(res.Matches))
+	for i := 1; i < len(res.Matches); i++ {
+		assert.GreaterOrEqual(t, res.Matches[i-1].Score, res.Matches[i].Score)
+	}
+
+	res, err = svc.Navigate(ann(), Options{Query: "analytics"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"datasource:Orders DB"}, titles(res.Matches), "aliases match")
+
+	res, err = svc.Navigate(ann(), Options{Query: "ordr", Kinds: []string{KindDashboard}, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dashboard:Order funnel"}, titles(res.Matches))
+
+	_, err = svc.Navigate(ann(), Options{Kinds: []string{"monitor"}})
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = svc.Navigate(ann(), Options{Limit: MaxLimit + 1})
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestMatch(t *testing.T) {
+	for _, tc := range []struct {
+		q, name string
+		want    float64
+	}{
+		{"orders", "Orders", 1},
+		{"ord", "orders", 0.8},
+		{"ord", "open_orders", 0.6},
+		{"ord", "recorded", 0.4},
+		{"oe", "order_events", 0.2},
+		{"xyz", "orders", 0},
+		{"", "orders", 0},
+	} {
+		assert.Equal(t, tc.want, match(tc.q, tc.name), "%q in %q", tc.q, tc.name)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(ann())
+	})
+	RegisterRoutes(r.Group("/api/v1"), NewHandler(newTestService(true)))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/navigate?q=events&kinds=table,datasource&limit=5", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var body struct{ Data Result }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, []string{"datasource:Events", "table:order_events"}, titles(body.Data.Matches))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/navigate?limit=many", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
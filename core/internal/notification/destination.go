@@ -0,0 +1,87 @@
+package notification
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// destinations decides which hosts channels may deliver to: public
+// addresses, plus the networks an operator allowed. Hosts are checked when a
+// channel is validated and again, after name resolution, when dialing, so a
+// name resolving to an internal address is refused too.
+type destinations struct {
+	allowed []netip.Prefix
+}
+
+// permits reports whether ip may be dialed.
+func (d *destinations) permits(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	for _, p := range d.allowed {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// checkHost refuses a host given as a blocked address or as localhost.
+// Other names are left to the dialer.
+func (d *destinations) checkHost(host string) error {
+	ip, err := netip.ParseAddr(strings.Trim(host, "[]"))
+	if err != nil {
+		name := strings.ToLower(strings.TrimSuffix(host, "."))
+		if name != "localhost" && !strings.HasSuffix(name, ".localhost") {
+			return nil
+		}
+		ip = netip.AddrFrom4([4]byte{127, 0, 0, 1})
+	}
+	if !d.permits(ip) {
+		return fmt.Errorf("host %s is not an allowed destination", host)
+	}
+	return nil
+}
+
+// checkURL requires an http or https URL whose host passes checkHost.
+func (d *destinations) checkURL(field, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("%s must be an http or https URL", field)
+	}
+	return d.checkHost(u.Hostname())
+}
+
+// dialer connects only to permitted addresses.
+func (d *destinations) dialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			if !d.permits(ip) {
+				return fmt.Errorf("%s is not an allowed destination", ip)
+			}
+			return nil
+		},
+	}
+}
+
+// client is an HTTP client whose connections, redirects included, go
+// through dialer.
+func (d *destinations) client(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = d.dialer(timeout).DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
@@ -0,0 +1,196 @@
+package notification
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// Handler serves notification channel endpoints.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates a notification channel HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// ─── wire types ───────────────────────────────────────────────────────────────
+
+type channelResponse struct {
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Type          string          `json:"type"`
+	Config        json.RawMessage `json:"config"`
+	Events        []string        `json:"events"`
+	TitleTemplate string          `json:"title_template,omitempty"`
+	BodyTemplate  string          `json:"body_template,omitempty"`
+	Enabled       bool            `json:"enabled"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+type channelRequest struct {
+	Name          string          `json:"name"`
+	Type          string          `json:"type"`
+	Config        json.RawMessage `json:"config"`
+	Events        []string        `json:"events"`
+	TitleTemplate string          `json:"title_template"`
+	BodyTemplate  string          `json:"body_template"`
+	Enabled       *bool           `json:"enabled"`
+}
+
+func (r channelRequest) toModel() *Channel {
+	ch := &Channel{
+		Name:          r.Name,
+		Type:          r.Type,
+		Config:        r.Config,
+		Events:        r.Events,
+		TitleTemplate: r.TitleTemplate,
+		BodyTemplate:  r.BodyTemplate,
+		Enabled:       true,
+	}
+	if r.Enabled != nil {
+		ch.Enabled = *r.Enabled
+	}
+	return ch
+}
+
+func toResponse(ch *Channel) channelResponse {
+	events := ch.Events
+	if events == nil {
+		events = []string{}
+	}
+	return channelResponse{
+		ID:            ch.ID,
+		Name:          ch.Name,
+		Type:          ch.Type,
+		Config:        ch.Config,
+		Events:        events,
+		TitleTemplate: ch.TitleTemplate,
+		BodyTemplate:  ch.BodyTemplate,
+		Enabled:       ch.Enabled,
+		CreatedAt:     ch.CreatedAt,
+		UpdatedAt:     ch.UpdatedAt,
+	}
+}
+
+// ─── handlers ─────────────────────────────────────────────────────────────────
+
+// List handles GET /notification-channels
+func (h *Handler) List(c *gin.Context) {
+	chs, err := h.svc.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to list notification channels")})
+		return
+	}
+	resp := make([]channelResponse, len(chs))
+	for i, ch := range chs {
+		resp[i] = toResponse(ch)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// GetByID handles GET /notification-channels/:id
+func (h *Handler) GetByID(c *gin.Context) {
+	ch, err := h.svc.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "notification channel not found")})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toResponse(ch)})
+}
+
+// Create handles POST /notification-channels
+func (h *Handler) Create(c *gin.Context) {
+	var body channelRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "invalid request body")})
+		return
+	}
+	ch := body.toModel()
+	if err := h.svc.Create(c.Request.Context(), ch); err != nil {
+		if errors.Is(err, ErrInvalidChannel) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to create notification channel")})
+		return
+	}
+	h.respondRedacted(c, http.StatusCreated, ch.ID)
+}
+
+// Update handles PUT /notification-channels/:id
+func (h *Handler) Update(c *gin.Context) {
+	id := c.Param("id")
+	var body channelRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "invalid request body")})
+		return
+	}
+	if err := h.svc.Update(c.Request.Context(), id, body.toModel()); err != nil {
+		if errors.Is(err, ErrInvalidChannel) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to update notification channel")})
+		return
+	}
+	h.respondRedacted(c, http.StatusOK, id)
+}
+
+// Delete handles DELETE /notification-channels/:id
+func (h *Handler) Delete(c *gin.Context) {
+	if err := h.svc.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to delete notification channel")})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Test handles POST /notification-channels/:id/test
+func (h *Handler) Test(c *gin.Context) {
+	if err := h.svc.Test(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"ok": true}})
+}
+
+// respondRedacted reloads the channel so secrets never echo back.
+func (h *Handler) respondRedacted(c *gin.Context, status int, id string) {
+	ch, err := h.svc.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to reload notification channel")})
+		return
+	}
+	c.JSON(status, gin.H{"data": toResponse(ch)})
+}
+
+// requireAdmin refuses channel changes and test sends to non-admins: a
+// channel decides where the server sends data. The user package cannot be
+// imported here, so this mirrors user.RequireAdmin.
+func requireAdmin(c *gin.Context) {
+	if !identity.FromContext(c.Request.Context()).Can(identity.PermAdmin) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "admin permission required")})
+		return
+	}
+	c.Next()
+}
+
+// RegisterRoutes wires notification channel routes onto r.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/notification-channels", h.List)
+	r.POST("/notification-channels", requireAdmin, h.Create)
+	r.GET("/notification-channels/:id", h.GetByID)
+	r.PUT("/notification-channels/:id", requireAdmin, h.Update)
+	r.DELETE("/notification-channels/:id", requireAdmin, h.Delete)
+	r.POST("/notification-channels/:id/test", requireAdmin, h.Test)
+}
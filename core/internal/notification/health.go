@@ -0,0 +1,50 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// WatchHealth runs check every interval until ctx is done and raises a
+// health event on each transition between healthy and unhealthy, so a
+// sustained outage produces one notification rather than one per tick.
+func WatchHealth(ctx context.Context, n Notifier, component string, interval time.Duration, check func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	healthy := true
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := check(ctx)
+		if (err == nil) == healthy {
+			continue
+		}
+		healthy = err == nil
+
+		ev := Event{
+			Type:     EventHealth,
+			Severity: SeverityCritical,
+			Title:    fmt.Sprintf("%s is unhealthy", component),
+			Source:   component,
+			Labels:   map[string]string{"component": component},
+			Time:     time.Now().UTC(),
+		}
+		if err != nil {
+			ev.Body = err.Error()
+		} else {
+			ev.Severity = SeverityInfo
+			ev.Title = fmt.Sprintf("%s recovered", component)
+			ev.Body = fmt.Sprintf("%s is healthy again.", component)
+		}
+		if nerr := n.Notify(ctx, ev); nerr != nil {
+			slog.Warn("health notification failed", "component", component, "err", nerr)
+		}
+	}
+}
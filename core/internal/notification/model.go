@@ -0,0 +1,117 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Channel types.
+const (
+	TypeEmail     = "email"
+	TypeSlack     = "slack"
+	TypeTeams     = "teams"
+	TypePagerDuty = "pagerduty"
+)
+
+// Event types a channel can subscribe to.
+const (
+	EventAlert           = "alert"
+	EventScheduleFailure = "schedule_failure"
+	EventHealth          = "health"
+	EventExport          = "export"
+	EventTest            = "test"
+)
+
+// Activity event types record changes worth showing in the activity feed.
+// Channels receive them only when they list them.
+const (
+	EventDatasourceCreated = "datasource_created"
+	EventQueryEdited       = "query_edited"
+	EventSchemaChanged     = "schema_changed"
+)
+
+// IsActivity reports whether eventType is an activity event type.
+func IsActivity(eventType string) bool {
+	switch eventType {
+	case EventDatasourceCreated, EventQueryEdited, EventSchemaChanged:
+		return true
+	}
+	return false
+}
+
+// Severities, lowest to highest.
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Channel is a configured notification destination.
+type Channel struct {
+	ID   string `db:"id"`
+	Name string `db:"name"`
+	Type string `db:"type"` // email | slack | teams | pagerduty
+	// Config is the type-specific JSON configuration. Repositories see it
+	// encrypted and store it as opaque text.
+	Config json.RawMessage `db:"config"`
+	// Events limits the channel to the listed event types; empty means all
+	// but the activity ones.
+	Events []string `db:"-"`
+	// TitleTemplate and BodyTemplate are pongo2 templates rendered against
+	// the event; empty uses the event's own title and body.
+	TitleTemplate string    `db:"title_template"`
+	BodyTemplate  string    `db:"body_template"`
+	Enabled       bool      `db:"enabled"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+// Subscribed reports whether the channel wants events of eventType.
+func (c *Channel) Subscribed(eventType string) bool {
+	if len(c.Events) == 0 {
+		return !IsActivity(eventType)
+	}
+	for _, e := range c.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is something worth telling people about. Subsystems raise events
+// through a Notifier; the service fans them out to subscribed channels.
+type Event struct {
+	Type     string
+	Severity string
+	Title    string
+	Body     string
+	// Source identifies what raised the event, e.g. a datasource name.
+	Source string
+	Labels map[string]string
+	Time   time.Time
+}
+
+// Message is an event rendered for one channel.
+type Message struct {
+	Title    string
+	Body     string
+	Severity string
+	Source   string
+}
+
+// Notifier delivers events. Alerting, schedulers and health checks depend on
+// this rather than on Service directly.
+type Notifier interface {
+	Notify(ctx context.Context, ev Event) error
+}
+
+// Repository defines persistence operations for notification channels.
+type Repository interface {
+	List(ctx context.Context) ([]*Channel, error)
+	GetByID(ctx context.Context, id string) (*Channel, error)
+	Create(ctx context.Context, ch *Channel) error
+	Update(ctx context.Context, ch *Channel) error
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,355 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sender delivers a rendered message using a channel's typed config.
+type Sender interface {
+	// Validate parses config and checks required fields.
+	Validate(config json.RawMessage) error
+	Send(ctx context.Context, config json.RawMessage, msg Message) error
+	// SecretFields lists config keys that are masked in API responses.
+	SecretFields() []string
+}
+
+// defaultSenders returns the built-in senders keyed by channel type. They
+// deliver only to destinations dest permits.
+func defaultSenders(dest *destinations) map[string]Sender {
+	client := dest.client(sendTimeout)
+	return map[string]Sender{
+		TypeEmail:     emailSender{dest: dest},
+		TypeSlack:     slackSender{client: client, dest: dest},
+		TypeTeams:     teamsSender{client: client, dest: dest},
+		TypePagerDuty: pagerDutySender{client: client, url: pagerDutyEventsURL},
+	}
+}
+
+// sendTimeout bounds one delivery attempt.
+const sendTimeout = 10 * time.Second
+
+func decodeConfig(raw json.RawMessage, v any) error {
+	if len(raw) == 0 {
+		return fmt.Errorf("config is required")
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	return nil
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, body any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// ─── email ────────────────────────────────────────────────────────────────────
+
+// EmailConfig configures delivery through an SMTP relay. With no Host the
+// channel sends through the server's email settings instead, and From and
+// Port are ignored.
+type EmailConfig struct {
+	Host     string   `json:"host"`
+	Port     int      `json:"port"`
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
+// Mailer sends email with the server's SMTP settings.
+type Mailer interface {
+	Enabled() bool
+	SendText(ctx context.Context, to []string, subject, body string) error
+}
+
+type emailSender struct {
+	mailer Mailer // nil when the server has no email settings
+	dest   *destinations
+}
+
+func (emailSender) SecretFields() []string { return []string{"password"} }
+
+func (s emailSender) parse(raw json.RawMessage) (*EmailConfig, error) {
+	var cfg EmailConfig
+	if err := decodeConfig(raw, &cfg); err != nil {
+		return nil, err
+	}
+	if len(cfg.To) == 0 {
+		return nil, fmt.Errorf("at least one recipient is required")
+	}
+	if cfg.Host == "" {
+		if s.mailer == nil || !s.mailer.Enabled() {
+			return nil, fmt.Errorf("host is required unless server email is configured")
+		}
+		return &cfg, nil
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	if cfg.From == "" {
+		return nil, fmt.Errorf("from is required")
+	}
+	if err := s.dest.checkHost(cfg.Host); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (s emailSender) Validate(raw json.RawMessage) error {
+	_, err := s.parse(raw)
+	return err
+}
+
+func (s emailSender) Send(ctx context.Context, raw json.RawMessage, msg Message) error {
+	cfg, err := s.parse(raw)
+	if err != nil {
+		return err
+	}
+	if cfg.Host == "" {
+		return s.mailer.SendText(ctx, cfg.To, msg.Title, msg.Body)
+	}
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&body, "To: %s\r\n", strings.Join(cfg.To, ", "))
+	fmt.Fprintf(&body, "Subject: %s\r\n", strings.ReplaceAll(msg.Title, "\n", " "))
+	body.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n")
+	body.WriteString(msg.Body)
+	return s.sendMail(ctx, cfg, auth, []byte(body.String()))
+}
+
+// sendMail is smtp.SendMail over a connection from the destination dialer.
+func (s emailSender) sendMail(ctx context.Context, cfg *EmailConfig, auth smtp.Auth, msg []byte) error {
+	for _, line := range append([]string{cfg.From}, cfg.To...) {
+		if strings.ContainsAny(line, "\r\n") {
+			return fmt.Errorf("smtp: a line must not contain CR or LF")
+		}
+	}
+	conn, err := s.dest.dialer(sendTimeout).DialContext(ctx, "tcp", net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)))
+	if err != nil {
+		return err
+	}
+	c, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer func() { _ = c.Close() }()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return fmt.Errorf("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(cfg.From); err != nil {
+		return err
+	}
+	for _, rcpt := range cfg.To {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// ─── Slack ────────────────────────────────────────────────────────────────────
+
+// SlackConfig configures an incoming webhook.
+type SlackConfig struct {
+	WebhookURL string `json:"webhook_url"`
+	Channel    string `json:"channel,omitempty"`
+}
+
+type slackSender struct {
+	client *http.Client
+	dest   *destinations
+}
+
+func (slackSender) SecretFields() []string { return []string{"webhook_url"} }
+
+func (s slackSender) parse(raw json.RawMessage) (*SlackConfig, error) {
+	var cfg SlackConfig
+	if err := decodeConfig(raw, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.WebhookURL == "" {
+		return nil, fmt.Errorf("webhook_url is required")
+	}
+	if err := s.dest.checkURL("webhook_url", cfg.WebhookURL); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (s slackSender) Validate(raw json.RawMessage) error {
+	_, err := s.parse(raw)
+	return err
+}
+
+func (s slackSender) Send(ctx context.Context, raw json.RawMessage, msg Message) error {
+	cfg, err := s.parse(raw)
+	if err != nil {
+		return err
+	}
+	payload := map[string]any{"text": fmt.Sprintf("*%s*\n%s", msg.Title, msg.Body)}
+	if cfg.Channel != "" {
+		payload["channel"] = cfg.Channel
+	}
+	return postJSON(ctx, s.client, cfg.WebhookURL, payload)
+}
+
+// ─── Microsoft Teams ──────────────────────────────────────────────────────────
+
+// TeamsConfig configures an incoming webhook.
+type TeamsConfig struct {
+	WebhookURL string `json:"webhook_url"`
+}
+
+type teamsSender struct {
+	client *http.Client
+	dest   *destinations
+}
+
+func (teamsSender) SecretFields() []string { return []string{"webhook_url"} }
+
+func (s teamsSender) parse(raw json.RawMessage) (*TeamsConfig, error) {
+	var cfg TeamsConfig
+	if err := decodeConfig(raw, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.WebhookURL == "" {
+		return nil, fmt.Errorf("webhook_url is required")
+	}
+	if err := s.dest.checkURL("webhook_url", cfg.WebhookURL); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (s teamsSender) Validate(raw json.RawMessage) error {
+	_, err := s.parse(raw)
+	return err
+}
+
+func (s teamsSender) Send(ctx context.Context, raw json.RawMessage, msg Message) error {
+	cfg, err := s.parse(raw)
+	if err != nil {
+		return err
+	}
+	colors := map[string]string{SeverityCritical: "D13438", SeverityWarning: "FFB900", SeverityInfo: "0078D4"}
+	return postJSON(ctx, s.client, cfg.WebhookURL, map[string]any{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    msg.Title,
+		"title":      msg.Title,
+		"text":       msg.Body,
+		"themeColor": colors[msg.Severity],
+	})
+}
+
+// ─── PagerDuty ────────────────────────────────────────────────────────────────
+
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutyConfig configures the Events API v2 integration.
+type PagerDutyConfig struct {
+	RoutingKey string `json:"routing_key"`
+}
+
+type pagerDutySender struct {
+	client *http.Client
+	url    string
+}
+
+func (pagerDutySender) SecretFields() []string { return []string{"routing_key"} }
+
+func (pagerDutySender) parse(raw json.RawMessage) (*PagerDutyConfig, error) {
+	var cfg PagerDutyConfig
+	if err := decodeConfig(raw, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.RoutingKey == "" {
+		return nil, fmt.Errorf("routing_key is required")
+	}
+	return &cfg, nil
+}
+
+func (s pagerDutySender) Validate(raw json.RawMessage) error {
+	_, err := s.parse(raw)
+	return err
+}
+
+func (s pagerDutySender) Send(ctx context.Context, raw json.RawMessage, msg Message) error {
+	cfg, err := s.parse(raw)
+	if err != nil {
+		return err
+	}
+	source := msg.Source
+	if source == "" {
+		source = "data-voyager"
+	}
+	severity := msg.Severity
+	if severity == "" {
+		severity = SeverityInfo
+	}
+	return postJSON(ctx, s.client, s.url, map[string]any{
+		"routing_key":  cfg.RoutingKey,
+		"event_action": "trigger",
+		"payload": map[string]any{
+			"summary":        msg.Title,
+			"source":         source,
+			"severity":       severity,
+			"timestamp":      time.Now().UTC().Format(time.RFC3339),
+			"custom_details": map[string]string{"body": msg.Body},
+		},
+	})
+}
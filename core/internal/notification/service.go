@@ -0,0 +1,397 @@
+package notification
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/netip"
+	"time"
+
+	"github.com/flosch/pongo2/v6"
+	"github.com/google/uuid"
+)
+
+// redactedValue replaces secret config values in API responses. Sending it
+// back on update keeps the stored secret.
+const redactedValue = "********"
+
+// ErrInvalidChannel wraps channel validation failures.
+var ErrInvalidChannel = errors.New("invalid channel")
+
+// Service manages notification channels and delivers events to them.
+type Service struct {
+	repo       Repository
+	encryptKey []byte // 32 bytes; nil means store plaintext
+	senders    map[string]Sender
+	dest       *destinations
+	listeners  []func(context.Context, Event)
+}
+
+// NewService creates a Service. encryptKey must be 32 bytes or nil.
+func NewService(repo Repository, encryptKey []byte) (*Service, error) {
+	if encryptKey != nil && len(encryptKey) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(encryptKey))
+	}
+	dest := &destinations{}
+	return &Service{repo: repo, encryptKey: encryptKey, senders: defaultSenders(dest), dest: dest}, nil
+}
+
+// WithAllowedNetworks lets channels deliver to hosts in networks even where
+// they are loopback, private or link-local addresses, which are refused
+// otherwise.
+func (s *Service) WithAllowedNetworks(networks []netip.Prefix) *Service {
+	s.dest.allowed = networks
+	return s
+}
+
+// WithSender registers or replaces the sender for a channel type.
+func (s *Service) WithSender(channelType string, sender Sender) *Service {
+	s.senders[channelType] = sender
+	return s
+}
+
+// WithMailer lets email channels without an SMTP host of their own send
+// through the server's email settings.
+func (s *Service) WithMailer(m Mailer) *Service {
+	s.senders[TypeEmail] = emailSender{mailer: m, dest: s.dest}
+	return s
+}
+
+// WithListener has fn called with every event before it is delivered to
+// channels, whether or not any channel subscribes to it.
+func (s *Service) WithListener(fn func(context.Context, Event)) *Service {
+	s.listeners = append(s.listeners, fn)
+	return s
+}
+
+// List returns all channels with secrets redacted.
+func (s *Service) List(ctx context.Context) ([]*Channel, error) {
+	chs, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, ch := range chs {
+		if err := s.redact(ch); err != nil {
+			return nil, err
+		}
+	}
+	return chs, nil
+}
+
+// GetByID returns one channel with secrets redacted.
+func (s *Service) GetByID(ctx context.Context, id string) (*Channel, error) {
+	ch, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.redact(ch); err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+// Create validates and stores a new channel.
+func (s *Service) Create(ctx context.Context, ch *Channel) error {
+	if err := s.validate(ch); err != nil {
+		return err
+	}
+	id, err := uuid.NewV7()
+	if err != nil {
+		return fmt.Errorf("generate uuid: %w", err)
+	}
+	ch.ID = id.String()
+	return s.store(ctx, ch, s.repo.Create)
+}
+
+// Update replaces a channel's settings. Redacted secret values in
+// input.Config are replaced with the stored ones.
+func (s *Service) Update(ctx context.Context, id string, input *Channel) error {
+	existing, err := s.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	input.ID = existing.ID
+	input.CreatedAt = existing.CreatedAt
+	if input.Type == "" {
+		input.Type = existing.Type
+	}
+	if input.Type == existing.Type {
+		merged, err := s.keepSecrets(input.Type, input.Config, existing.Config)
+		if err != nil {
+			return err
+		}
+		input.Config = merged
+	}
+	if err := s.validate(input); err != nil {
+		return err
+	}
+	return s.store(ctx, input, s.repo.Update)
+}
+
+// Delete removes a channel.
+func (s *Service) Delete(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// Test sends a test message to one channel regardless of its subscriptions
+// or enabled flag.
+func (s *Service) Test(ctx context.Context, id string) error {
+	ch, err := s.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.send(ctx, ch, Event{
+		Type:     EventTest,
+		Severity: SeverityInfo,
+		Title:    "Data Voyager test notification",
+		Body:     fmt.Sprintf("Channel %q is configured correctly.", ch.Name),
+		Source:   "data-voyager",
+		Time:     time.Now().UTC(),
+	})
+}
+
+// Notify delivers ev to every enabled channel subscribed to its type. A
+// failing channel does not stop delivery to the others; all failures are
+// returned together.
+func (s *Service) Notify(ctx context.Context, ev Event) error {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now().UTC()
+	}
+	for _, fn := range s.listeners {
+		fn(ctx, ev)
+	}
+	chs, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, ch := range chs {
+		if !ch.Enabled || !ch.Subscribed(ev.Type) {
+			continue
+		}
+		if err := s.decryptConfig(ch); err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: %w", ch.Name, err))
+			continue
+		}
+		if err := s.send(ctx, ch, ev); err != nil {
+			slog.Warn("notification delivery failed", "channel", ch.Name, "type", ch.Type, "event", ev.Type, "err", err)
+			errs = append(errs, fmt.Errorf("channel %s: %w", ch.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Service) send(ctx context.Context, ch *Channel, ev Event) error {
+	sender, ok := s.senders[ch.Type]
+	if !ok {
+		return fmt.Errorf("unsupported channel type %q", ch.Type)
+	}
+	msg, err := render(ch, ev)
+	if err != nil {
+		return err
+	}
+	return sender.Send(ctx, ch.Config, msg)
+}
+
+// render applies the channel's templates to ev.
+func render(ch *Channel, ev Event) (Message, error) {
+	msg := Message{Title: ev.Title, Body: ev.Body, Severity: ev.Severity, Source: ev.Source}
+	tctx := pongo2.Context{
+		"type":     ev.Type,
+		"severity": ev.Severity,
+		"title":    ev.Title,
+		"body":     ev.Body,
+		"source":   ev.Source,
+		"labels":   ev.Labels,
+		"time":     ev.Time,
+		"channel":  ch.Name,
+	}
+	var err error
+	if ch.TitleTemplate != "" {
+		if msg.Title, err = renderTemplate(ch.TitleTemplate, tctx); err != nil {
+			return Message{}, fmt.Errorf("title template: %w", err)
+		}
+	}
+	if ch.BodyTemplate != "" {
+		if msg.Body, err = renderTemplate(ch.BodyTemplate, tctx); err != nil {
+			return Message{}, fmt.Errorf("body template: %w", err)
+		}
+	}
+	return msg, nil
+}
+
+func renderTemplate(src string, tctx pongo2.Context) (string, error) {
+	tpl, err := pongo2.FromString(src)
+	if err != nil {
+		return "", err
+	}
+	return tpl.Execute(tctx)
+}
+
+func (s *Service) validate(ch *Channel) error {
+	if err := s.check(ch); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidChannel, err)
+	}
+	return nil
+}
+
+func (s *Service) check(ch *Channel) error {
+	if ch.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	sender, ok := s.senders[ch.Type]
+	if !ok {
+		return fmt.Errorf("unsupported channel type %q", ch.Type)
+	}
+	if err := sender.Validate(ch.Config); err != nil {
+		return err
+	}
+	for _, e := range ch.Events {
+		switch e {
+		case EventAlert, EventScheduleFailure, EventHealth, EventExport,
+			EventDatasourceCreated, EventQueryEdited, EventSchemaChanged:
+		default:
+			return fmt.Errorf("unknown event type %q", e)
+		}
+	}
+	for _, t := range []string{ch.TitleTemplate, ch.BodyTemplate} {
+		if t == "" {
+			continue
+		}
+		if _, err := pongo2.FromString(t); err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+	}
+	return nil
+}
+
+// store encrypts the config and persists ch with save, leaving ch.Config in
+// plaintext for the caller.
+func (s *Service) store(ctx context.Context, ch *Channel, save func(context.Context, *Channel) error) error {
+	plain := ch.Config
+	enc, err := s.encrypt(string(plain))
+	if err != nil {
+		return fmt.Errorf("encrypt config: %w", err)
+	}
+	ch.Config = json.RawMessage(enc)
+	err = save(ctx, ch)
+	ch.Config = plain
+	return err
+}
+
+// load returns a channel with its config decrypted.
+func (s *Service) load(ctx context.Context, id string) (*Channel, error) {
+	ch, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.decryptConfig(ch); err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+// decryptConfig replaces the stored (encrypted) config with its plaintext.
+func (s *Service) decryptConfig(ch *Channel) error {
+	plain, err := s.decrypt(string(ch.Config))
+	if err != nil {
+		return fmt.Errorf("decrypt config: %w", err)
+	}
+	ch.Config = json.RawMessage(plain)
+	return nil
+}
+
+func (s *Service) redact(ch *Channel) error {
+	if err := s.decryptConfig(ch); err != nil {
+		return err
+	}
+	sender, ok := s.senders[ch.Type]
+	if !ok {
+		return nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal(ch.Config, &m); err != nil {
+		return fmt.Errorf("decode config: %w", err)
+	}
+	for _, k := range sender.SecretFields() {
+		if v, ok := m[k].(string); ok && v != "" {
+			m[k] = redactedValue
+		}
+	}
+	ch.Config, _ = json.Marshal(m)
+	return nil
+}
+
+func (s *Service) keepSecrets(channelType string, input, existing json.RawMessage) (json.RawMessage, error) {
+	sender, ok := s.senders[channelType]
+	if !ok || len(input) == 0 {
+		return input, nil
+	}
+	var in, prev map[string]any
+	if err := json.Unmarshal(input, &in); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := json.Unmarshal(existing, &prev); err != nil {
+		return nil, fmt.Errorf("decode stored config: %w", err)
+	}
+	for _, k := range sender.SecretFields() {
+		if in[k] == redactedValue {
+			in[k] = prev[k]
+		}
+	}
+	return json.Marshal(in)
+}
+
+func (s *Service) encrypt(plaintext string) (string, error) {
+	if s.encryptKey == nil {
+		return plaintext, nil // store plaintext when no key configured
+	}
+	block, err := aes.NewCipher(s.encryptKey)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (s *Service) decrypt(ciphertext string) (string, error) {
+	if s.encryptKey == nil {
+		return ciphertext, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("base64 decode: %w", err)
+	}
+	block, err := aes.NewCipher(s.encryptKey)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	ns := gcm.NonceSize()
+	if len(data) < ns {
+		return "", errors.New("ciphertext too short")
+	}
+	pt, err := gcm.Open(nil, data[:ns], data[ns:], nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt: %w", err)
+	}
+	return string(pt), nil
+}
@@ -0,0 +1,324 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/identity"
+)
+
+type memRepo struct {
+	mu  sync.Mutex
+	chs map[string]*Channel
+}
+
+func newMemRepo() *memRepo { return &memRepo{chs: map[string]*Channel{}} }
+
+func (m *memRepo) List(_ context.Context) ([]*Channel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]*Channel, 0, len(m.chs))
+	for _, ch := range m.chs {
+		cp := *ch
+		out = append(out, &cp)
+	}
+	return out, nil
+}
+
+func (m *memRepo) GetByID(_ context.Context, id string) (*Channel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ch, ok := m.chs[id]
+	if !ok {
+		return nil, fmt.Errorf("notification channel %s not found", id)
+	}
+	cp := *ch
+	return &cp, nil
+}
+
+func (m *memRepo) Create(_ context.Context, ch *Channel) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *ch
+	m.chs[ch.ID] = &cp
+	return nil
+}
+
+func (m *memRepo) Update(ctx context.Context, ch *Channel) error { return m.Create(ctx, ch) }
+
+func (m *memRepo) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.chs, id)
+	return nil
+}
+
+// recordingServer captures Slack-style webhook payloads.
+func recordingServer(t *testing.T) (*httptest.Server, *[]map[string]any) {
+	t.Helper()
+	var mu sync.Mutex
+	var got []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		got = append(got, body)
+		mu.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &got
+}
+
+// loopback lets channels reach httptest servers.
+var loopback = []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}
+
+func testKey() []byte { return []byte("0123456789abcdef0123456789abcdef") }
+
+func slackConfig(url string) json.RawMessage {
+	b, _ := json.Marshal(SlackConfig{WebhookURL: url})
+	return b
+}
+
+func TestService_SecretsEncryptedAndRedacted(t *testing.T) {
+	repo := newMemRepo()
+	svc, err := NewService(repo, testKey())
+	require.NoError(t, err)
+
+	ch := &Channel{Name: "ops", Type: TypeSlack, Config: slackConfig("https://hooks.example/secret"), Enabled: true}
+	require.NoError(t, svc.Create(context.Background(), ch))
+
+	stored := repo.chs[ch.ID]
+	assert.NotContains(t, string(stored.Config), "secret", "config must be encrypted at rest")
+
+	got, err := svc.GetByID(context.Background(), ch.ID)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"webhook_url":"********"}`, string(got.Config))
+
+	// Sending the redacted value back keeps the stored secret.
+	update := &Channel{Name: "ops-renamed", Config: got.Config, Enabled: true}
+	require.NoError(t, svc.Update(context.Background(), ch.ID, update))
+	loaded, err := svc.load(context.Background(), ch.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "ops-renamed", loaded.Name)
+	assert.JSONEq(t, `{"webhook_url":"https://hooks.example/secret"}`, string(loaded.Config))
+}
+
+func TestService_ValidationErrors(t *testing.T) {
+	svc, err := NewService(newMemRepo(), nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	cases := map[string]*Channel{
+		"unknown type":   {Name: "x", Type: "carrier-pigeon", Config: json.RawMessage(`{}`)},
+		"missing config": {Name: "x", Type: TypeSlack, Config: json.RawMessage(`{}`)},
+		"unknown event":  {Name: "x", Type: TypeSlack, Config: slackConfig("http://h"), Events: []string{"coffee"}},
+		"bad template":   {Name: "x", Type: TypeSlack, Config: slackConfig("http://h"), TitleTemplate: "{{ title "},
+	}
+	for name, ch := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, svc.Create(ctx, ch), ErrInvalidChannel)
+		})
+	}
+}
+
+func TestService_NotifyRoutesAndRenders(t *testing.T) {
+	srv, got := recordingServer(t)
+	svc, err := NewService(newMemRepo(), testKey())
+	require.NoError(t, err)
+	svc.WithAllowedNetworks(loopback)
+	ctx := context.Background()
+
+	require.NoError(t, svc.Create(ctx, &Channel{
+		Name: "alerts", Type: TypeSlack, Config: slackConfig(srv.URL), Enabled: true,
+		Events: []string{EventAlert}, TitleTemplate: "[{{ severity|upper }}] {{ title }}",
+	}))
+	require.NoError(t, svc.Create(ctx, &Channel{
+		Name: "health-only", Type: TypeSlack, Config: slackConfig(srv.URL), Enabled: true,
+		Events: []string{EventHealth},
+	}))
+	require.NoError(t, svc.Create(ctx, &Channel{
+		Name: "disabled", Type: TypeSlack, Config: slackConfig(srv.URL), Enabled: false,
+	}))
+
+	require.NoError(t, svc.Notify(ctx, Event{Type: EventAlert, Severity: SeverityCritical, Title: "disk full", Body: "99%"}))
+	require.Len(t, *got, 1)
+	assert.Equal(t, "*[CRITICAL] disk full*\n99%", (*got)[0]["text"])
+}
+
+func TestService_ActivityEventsAreOptIn(t *testing.T) {
+	srv, got := recordingServer(t)
+	svc, err := NewService(newMemRepo(), nil)
+	require.NoError(t, err)
+	svc.WithAllowedNetworks(loopback)
+	ctx := context.Background()
+
+	require.NoError(t, svc.Create(ctx, &Channel{Name: "everything", Type: TypeSlack, Config: slackConfig(srv.URL), Enabled: true}))
+	require.NoError(t, svc.Create(ctx, &Channel{
+		Name: "new-sources", Type: TypeSlack, Config: slackConfig(srv.URL), Enabled: true,
+		Events: []string{EventDatasourceCreated},
+	}))
+
+	require.NoError(t, svc.Notify(ctx, Event{Type: EventDatasourceCreated, Title: "orders db added"}))
+	require.Len(t, *got, 1, "only the channel listing the type hears it")
+	require.NoError(t, svc.Notify(ctx, Event{Type: EventSchemaChanged, Title: "schema changed"}))
+	assert.Len(t, *got, 1)
+}
+
+func TestService_TestSend(t *testing.T) {
+	srv, got := recordingServer(t)
+	svc, err := NewService(newMemRepo(), nil)
+	require.NoError(t, err)
+	svc.WithAllowedNetworks(loopback)
+	ch := &Channel{Name: "ops", Type: TypeSlack, Config: slackConfig(srv.URL), Events: []string{EventAlert}}
+	require.NoError(t, svc.Create(context.Background(), ch))
+
+	require.NoError(t, svc.Test(context.Background(), ch.ID))
+	require.Len(t, *got, 1)
+	assert.Contains(t, (*got)[0]["text"], "Data Voyager test notification")
+}
+
+func TestService_RefusesInternalDestinations(t *testing.T) {
+	svc, err := NewService(newMemRepo(), nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+	for _, url := range []string{
+		"http://169.254.169.254/latest/meta-data/",
+		"http://localhost:8080/hook",
+		"https://[::1]/hook",
+		"http://10.0.0.5/hook",
+		"file:///etc/passwd",
+	} {
+		err := svc.Create(ctx, &Channel{Name: "x", Type: TypeSlack, Config: slackConfig(url)})
+		assert.ErrorIs(t, err, ErrInvalidChannel, url)
+	}
+	smtp := json.RawMessage(`{"host":"127.0.0.1","from":"a@example.com","to":["b@example.com"]}`)
+	assert.ErrorIs(t, svc.Create(ctx, &Channel{Name: "mail", Type: TypeEmail, Config: smtp}), ErrInvalidChannel)
+
+	svc.WithAllowedNetworks(loopback)
+	require.NoError(t, svc.Create(ctx, &Channel{Name: "mail", Type: TypeEmail, Config: smtp}))
+	err = svc.Create(ctx, &Channel{Name: "x", Type: TypeSlack, Config: slackConfig("http://169.254.169.254/")})
+	assert.ErrorIs(t, err, ErrInvalidChannel, "allowing loopback leaves link-local refused")
+}
+
+func TestDestinations_CheckedWhenDialing(t *testing.T) {
+	srv, got := recordingServer(t)
+	// A name that resolves to an internal address passes validation; the
+	// dialer refuses it.
+	err := postJSON(context.Background(), (&destinations{}).client(time.Second), srv.URL, map[string]any{})
+	assert.ErrorContains(t, err, "not an allowed destination")
+	assert.Empty(t, *got)
+}
+
+func TestRoutes_ChangesNeedAdmin(t *testing.T) {
+	svc, err := NewService(newMemRepo(), nil)
+	require.NoError(t, err)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(identity.With(c.Request.Context(), &identity.Identity{Username: "v", Role: identity.RoleViewer}))
+	})
+	RegisterRoutes(&r.RouterGroup, NewHandler(svc))
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/notification-channels", strings.NewReader(`{}`)),
+		httptest.NewRequest(http.MethodPut, "/notification-channels/1", strings.NewReader(`{}`)),
+		httptest.NewRequest(http.MethodDelete, "/notification-channels/1", nil),
+		httptest.NewRequest(http.MethodPost, "/notification-channels/1/test", nil),
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code, req.Method+" "+req.URL.Path)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notification-channels", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+type stubMailer struct {
+	enabled bool
+	sent    []string
+}
+
+func (m *stubMailer) Enabled() bool { return m.enabled }
+
+func (m *stubMailer) SendText(_ context.Context, to []string, subject, body string) error {
+	m.sent = append(m.sent, strings.Join(to, ",")+": "+subject+" / "+body)
+	return nil
+}
+
+func TestService_EmailUsesServerMailer(t *testing.T) {
+	ctx := context.Background()
+	hostless := &Channel{Name: "mail", Type: TypeEmail, Config: json.RawMessage(`{"to":["ops@example.com"]}`), Enabled: true}
+
+	svc, err := NewService(newMemRepo(), nil)
+	require.NoError(t, err)
+	assert.ErrorIs(t, svc.Create(ctx, hostless), ErrInvalidChannel, "needs a host without server email")
+	assert.ErrorIs(t, svc.WithMailer(&stubMailer{}).Create(ctx, hostless), ErrInvalidChannel, "nor with it switched off")
+
+	mailer := &stubMailer{enabled: true}
+	svc.WithMailer(mailer)
+	require.NoError(t, svc.Create(ctx, hostless))
+	require.NoError(t, svc.Notify(ctx, Event{Type: EventAlert, Title: "disk full", Body: "99%"}))
+	assert.Equal(t, []string{"ops@example.com: disk full / 99%"}, mailer.sent)
+}
+
+func TestService_Listener(t *testing.T) {
+	svc, err := NewService(newMemRepo(), nil)
+	require.NoError(t, err)
+	var got []Event
+	svc.WithListener(func(_ context.Context, ev Event) { got = append(got, ev) })
+
+	require.NoError(t, svc.Notify(context.Background(), Event{Type: EventAlert, Title: "disk full"}))
+	require.Len(t, got, 1, "listeners hear events no channel subscribes to")
+	assert.Equal(t, "disk full", got[0].Title)
+	assert.False(t, got[0].Time.IsZero())
+}
+
+type countingNotifier struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (n *countingNotifier) Notify(_ context.Context, ev Event) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.events = append(n.events, ev)
+	return nil
+}
+
+func TestWatchHealth_NotifiesOnTransitions(t *testing.T) {
+	results := []error{nil, fmt.Errorf("down"), fmt.Errorf("down"), nil, nil}
+	var mu sync.Mutex
+	i := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	check := func(context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if i >= len(results) {
+			cancel()
+			return nil
+		}
+		err := results[i]
+		i++
+		return err
+	}
+
+	n := &countingNotifier{}
+	WatchHealth(ctx, n, "metadata store", time.Millisecond, check)
+
+	require.Len(t, n.events, 2)
+	assert.Equal(t, SeverityCritical, n.events[0].Severity)
+	assert.Equal(t, "down", n.events[0].Body)
+	assert.Equal(t, "metadata store recovered", n.events[1].Title)
+}
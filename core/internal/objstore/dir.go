@@ -0,0 +1,83 @@
+package objstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Dir stores objects as files below a root directory.
+type Dir struct {
+	root string
+}
+
+// NewDir returns a Store rooted at root.
+func NewDir(root string) *Dir {
+	return &Dir{root: root}
+}
+
+func (d *Dir) path(key string) (string, error) {
+	p := filepath.Join(d.root, filepath.FromSlash(key))
+	if rel, err := filepath.Rel(d.root, p); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+	return p, nil
+}
+
+// Put writes the object through a temporary file so readers never see a
+// partial archive.
+func (d *Dir) Put(_ context.Context, key string, body []byte, _ string) error {
+	p, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return fmt.Errorf("create archive dir: %w", err)
+	}
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, body, 0o640); err != nil {
+		return fmt.Errorf("write %s: %w", key, err)
+	}
+	if err := os.Rename(tmp, p); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("write %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get reads the file for key.
+func (d *Dir) Get(_ context.Context, key string) ([]byte, error) {
+	p, err := d.path(key)
+	if err != nil {
+		return nil, err
+	}
+	body, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("read %s: %w", key, ErrNotExist)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", key, err)
+	}
+	return body, nil
+}
+
+// Delete removes the file for key.
+func (d *Dir) Delete(_ context.Context, key string) error {
+	p, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// Location returns the file path of key.
+func (d *Dir) Location(key string) string {
+	return filepath.Join(d.root, filepath.FromSlash(key))
+}
@@ -0,0 +1,42 @@
+// Package objstore keeps blobs in object storage: a local directory or an
+// S3-compatible bucket. It covers only what archiving and result spilling
+// need and talks to S3 directly with SigV4 rather than pulling in the AWS SDK.
+package objstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"data-voyager/core/internal/config"
+)
+
+// ErrNotExist is returned by Get for a key with no object.
+var ErrNotExist = errors.New("object does not exist")
+
+// Store saves objects under slash-separated keys.
+type Store interface {
+	// Put writes body to key, replacing any existing object.
+	Put(ctx context.Context, key string, body []byte, contentType string) error
+	// Get reads the object at key.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes key; deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// Location describes where key is stored, for logs and reports.
+	Location(key string) string
+}
+
+// New returns the store an ArchiveConfig selects, or nil when archiving is
+// disabled.
+func New(cfg config.ArchiveConfig) (Store, error) {
+	switch cfg.Type {
+	case "":
+		return nil, nil
+	case "dir":
+		return NewDir(cfg.Dir), nil
+	case "s3":
+		return NewS3(cfg.S3), nil
+	default:
+		return nil, fmt.Errorf("unsupported archive type: %s", cfg.Type)
+	}
+}
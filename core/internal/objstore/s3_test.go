@@ -0,0 +1,104 @@
+package objstore
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+)
+
+func TestS3Put(t *testing.T) {
+	var got *http.Request
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got, body = r, string(b)
+	}))
+	defer srv.Close()
+
+	s := NewS3(config.S3Config{
+		Endpoint: srv.URL, Region: "us-east-1", Bucket: "archive", Prefix: "/voyager/",
+		AccessKeyID: "AKID", SecretAccessKey: "secret", PathStyle: true,
+	})
+	s.now = func() time.Time { return time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC) }
+
+	require.NoError(t, s.Put(context.Background(), "runs/a b.jsonl.gz", []byte("data"), "application/gzip"))
+	assert.Equal(t, http.MethodPut, got.Method)
+	assert.Equal(t, "/archive/voyager/runs/a%20b.jsonl.gz", got.URL.EscapedPath())
+	assert.Equal(t, "data", body)
+	assert.Equal(t, "20240601T000000Z", got.Header.Get("X-Amz-Date"))
+	auth := got.Header.Get("Authorization")
+	assert.True(t, strings.HasPrefix(auth,
+		"AWS4-HMAC-SHA256 Credential=AKID/20240601/us-east-1/s3/aws4_request, SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, Signature="), auth)
+	assert.Equal(t, "s3://archive/voyager/runs/a b.jsonl.gz", s.Location("runs/a b.jsonl.gz"))
+}
+
+func TestS3PutError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("<Error><Code>AccessDenied</Code></Error>"))
+	}))
+	defer srv.Close()
+	s := NewS3(config.S3Config{Endpoint: srv.URL, Region: "us-east-1", Bucket: "b", PathStyle: true})
+	err := s.Put(context.Background(), "k", nil, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AccessDenied")
+}
+
+func TestDirRejectsEscapingKeys(t *testing.T) {
+	d := NewDir(t.TempDir())
+	assert.Error(t, d.Put(context.Background(), "../outside", []byte("x"), ""))
+	assert.NoError(t, d.Put(context.Background(), "a/b/c.txt", []byte("x"), ""))
+}
+
+func TestS3GetDelete(t *testing.T) {
+	objects := map[string]string{"/b/k": "payload"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			body, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(body))
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+	s := NewS3(config.S3Config{Endpoint: srv.URL, Region: "us-east-1", Bucket: "b", PathStyle: true})
+	ctx := context.Background()
+
+	body, err := s.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.Equal(t, "payload", string(body))
+
+	require.NoError(t, s.Delete(ctx, "k"))
+	_, err = s.Get(ctx, "k")
+	assert.ErrorIs(t, err, ErrNotExist)
+}
+
+func TestDirGetDelete(t *testing.T) {
+	d := NewDir(t.TempDir())
+	ctx := context.Background()
+	require.NoError(t, d.Put(ctx, "a/b.txt", []byte("x"), ""))
+
+	body, err := d.Get(ctx, "a/b.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "x", string(body))
+
+	require.NoError(t, d.Delete(ctx, "a/b.txt"))
+	require.NoError(t, d.Delete(ctx, "a/b.txt"))
+	_, err = d.Get(ctx, "a/b.txt")
+	assert.ErrorIs(t, err, ErrNotExist)
+}
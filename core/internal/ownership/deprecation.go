@@ -0,0 +1,104 @@
+package ownership
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"data-voyager/core/internal/connection"
+)
+
+// Contact is someone to notify about a deprecated resource, derived from the
+// ownership records of the resource and of the tables beneath it.
+type Contact struct {
+	Name         string `json:"name"`
+	Kind         string `json:"kind"`
+	Role         string `json:"role"` // owner | steward
+	ResourceType string `json:"resource_type"`
+	ResourceID   string `json:"resource_id"`
+}
+
+// DeprecatedResource is one entry of the deprecation report.
+type DeprecatedResource struct {
+	ResourceType string     `json:"resource_type"`
+	ResourceID   string     `json:"resource_id"`
+	Name         string     `json:"name"`
+	Message      string     `json:"message"`
+	DeprecatedAt time.Time  `json:"deprecated_at"`
+	SunsetAt     *time.Time `json:"sunset_at,omitempty"`
+	SunsetPassed bool       `json:"sunset_passed"`
+	Consumers    []Contact  `json:"consumers"`
+}
+
+// Deprecated lists deprecated datasources, soonest sunset first, together
+// with the owners and stewards recorded for them and their tables.
+func (s *Service) Deprecated(ctx context.Context, now time.Time) ([]DeprecatedResource, error) {
+	conns, err := s.conns.List(ctx, connection.Filter{})
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.repo.List(ctx, Filter{})
+	if err != nil {
+		return nil, err
+	}
+
+	out := []DeprecatedResource{}
+	for _, c := range conns {
+		if c.Deprecation == nil {
+			continue
+		}
+		d := DeprecatedResource{
+			ResourceType: ResourceDatasource,
+			ResourceID:   c.ID,
+			Name:         c.Name,
+			Message:      c.Deprecation.Message,
+			DeprecatedAt: c.Deprecation.DeprecatedAt,
+			SunsetAt:     c.Deprecation.SunsetAt,
+			SunsetPassed: c.Deprecation.SunsetAt != nil && !now.Before(*c.Deprecation.SunsetAt),
+			Consumers:    []Contact{},
+		}
+		for _, r := range records {
+			if !belongsTo(r, c.ID) {
+				continue
+			}
+			if r.Owner != "" {
+				d.Consumers = append(d.Consumers, Contact{
+					Name: r.Owner, Kind: r.OwnerKind, Role: "owner",
+					ResourceType: r.ResourceType, ResourceID: r.ResourceID,
+				})
+			}
+			if r.Steward != "" {
+				d.Consumers = append(d.Consumers, Contact{
+					Name: r.Steward, Kind: KindUser, Role: "steward",
+					ResourceType: r.ResourceType, ResourceID: r.ResourceID,
+				})
+			}
+		}
+		out = append(out, d)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i].SunsetAt, out[j].SunsetAt
+		switch {
+		case a == nil:
+			return false
+		case b == nil:
+			return true
+		}
+		return a.Before(*b)
+	})
+	return out, nil
+}
+
+// belongsTo reports whether r is about the datasource itself or one of its
+// tables.
+func belongsTo(r *Ownership, datasourceID string) bool {
+	switch r.ResourceType {
+	case ResourceDatasource:
+		return r.ResourceID == datasourceID
+	case ResourceTable:
+		return strings.HasPrefix(r.ResourceID, datasourceID+"/")
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package ownership
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// Handler serves ownership endpoints.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates an ownership HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// ─── wire types ───────────────────────────────────────────────────────────────
+
+type ownershipResponse struct {
+	ResourceType string    `json:"resource_type"`
+	ResourceID   string    `json:"resource_id"`
+	Owner        string    `json:"owner"`
+	OwnerKind    string    `json:"owner_kind"`
+	Steward      string    `json:"steward"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	UpdatedBy    string    `json:"updated_by,omitempty"`
+}
+
+type setOwnershipRequest struct {
+	ResourceType string `json:"resource_type" binding:"required"`
+	ResourceID   string `json:"resource_id"   binding:"required"`
+	Owner        string `json:"owner"`
+	OwnerKind    string `json:"owner_kind"`
+	Steward      string `json:"steward"`
+	UpdatedBy    string `json:"updated_by"`
+}
+
+func toResponse(o *Ownership) ownershipResponse {
+	return ownershipResponse{
+		ResourceType: o.ResourceType,
+		ResourceID:   o.ResourceID,
+		Owner:        o.Owner,
+		OwnerKind:    o.OwnerKind,
+		Steward:      o.Steward,
+		UpdatedAt:    o.UpdatedAt,
+		UpdatedBy:    o.UpdatedBy,
+	}
+}
+
+// ─── handlers ─────────────────────────────────────────────────────────────────
+
+// List handles GET /ownership?resource_type=&owner=&steward=
+func (h *Handler) List(c *gin.Context) {
+	records, err := h.svc.List(c.Request.Context(), Filter{
+		ResourceType: c.Query("resource_type"),
+		Owner:        c.Query("owner"),
+		Steward:      c.Query("steward"),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to list ownership")})
+		return
+	}
+	resp := make([]ownershipResponse, len(records))
+	for i, r := range records {
+		resp[i] = toResponse(r)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// Get handles GET /ownership/resource?resource_type=&resource_id=
+func (h *Handler) Get(c *gin.Context) {
+	o, err := h.svc.Get(c.Request.Context(), c.Query("resource_type"), c.Query("resource_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "ownership not found")})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toResponse(o)})
+}
+
+// requireWrite answers 403 unless the caller may change datasources;
+// editors and admins may.
+func requireWrite(c *gin.Context) bool {
+	if identity.FromContext(c.Request.Context()).Can(identity.PermDatasourceWrite) {
+		return true
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "permission denied")})
+	return false
+}
+
+// Set handles PUT /ownership
+func (h *Handler) Set(c *gin.Context) {
+	if !requireWrite(c) {
+		return
+	}
+	var body setOwnershipRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "invalid request body")})
+		return
+	}
+	o := &Ownership{
+		ResourceType: body.ResourceType,
+		ResourceID:   body.ResourceID,
+		Owner:        body.Owner,
+		OwnerKind:    body.OwnerKind,
+		Steward:      body.Steward,
+		UpdatedBy:    body.UpdatedBy,
+	}
+	if err := validate(o); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.svc.Set(c.Request.Context(), o); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to set ownership")})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toResponse(o)})
+}
+
+// Delete handles DELETE /ownership?resource_type=&resource_id=
+func (h *Handler) Delete(c *gin.Context) {
+	if !requireWrite(c) {
+		return
+	}
+	if err := h.svc.Delete(c.Request.Context(), c.Query("resource_type"), c.Query("resource_id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to delete ownership")})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Unowned handles GET /ownership/unowned?resource_type=datasource|table&datasource_uid=
+func (h *Handler) Unowned(c *gin.Context) {
+	resourceType := c.DefaultQuery("resource_type", ResourceDatasource)
+	report, err := h.svc.Unowned(c.Request.Context(), resourceType, c.Query("datasource_uid"))
+	if errors.Is(err, connection.ErrNetworkPolicy) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if report == nil {
+		report = []UnownedResource{}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// Deprecated handles GET /ownership/deprecated
+func (h *Handler) Deprecated(c *gin.Context) {
+	report, err := h.svc.Deprecated(c.Request.Context(), time.Now().UTC())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to build deprecation report")})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// RegisterRoutes wires ownership routes onto r.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/ownership", h.List)
+	r.PUT("/ownership", h.Set)
+	r.DELETE("/ownership", h.Delete)
+	r.GET("/ownership/resource", h.Get)
+	r.GET("/ownership/unowned", h.Unowned)
+	r.GET("/ownership/deprecated", h.Deprecated)
+}
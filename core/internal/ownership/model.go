@@ -0,0 +1,57 @@
+package ownership
+
+import (
+	"context"
+	"time"
+)
+
+// Resource types that can carry ownership metadata.
+const (
+	ResourceDatasource = "datasource"
+	ResourceTable      = "table"
+	ResourceSavedQuery = "saved_query"
+)
+
+// Owner kinds.
+const (
+	KindUser = "user"
+	KindTeam = "team"
+)
+
+// Ownership records who owns and who stewards a resource.
+// Tables are identified as "<datasource id>/<schema>.<table>".
+type Ownership struct {
+	ID           string    `db:"id"`
+	ResourceType string    `db:"resource_type"`
+	ResourceID   string    `db:"resource_id"`
+	Owner        string    `db:"owner"`
+	OwnerKind    string    `db:"owner_kind"` // user | team
+	Steward      string    `db:"steward"`
+	UpdatedAt    time.Time `db:"updated_at"`
+	UpdatedBy    string    `db:"updated_by"`
+}
+
+// Filter holds optional filters for List. Empty fields match everything.
+type Filter struct {
+	ResourceType string
+	Owner        string
+	Steward      string
+}
+
+// Repository persists ownership records.
+// Implemented per-driver in store/{postgres,sqlite,mysql}/.
+type Repository interface {
+	// Upsert creates or replaces the record for (ResourceType, ResourceID).
+	Upsert(ctx context.Context, o *Ownership) error
+	Get(ctx context.Context, resourceType, resourceID string) (*Ownership, error)
+	List(ctx context.Context, filter Filter) ([]*Ownership, error)
+	Delete(ctx context.Context, resourceType, resourceID string) error
+}
+
+// TableResourceID builds the resource id used for table ownership.
+func TableResourceID(datasourceID, schema, table string) string {
+	if schema == "" {
+		return datasourceID + "/" + table
+	}
+	return datasourceID + "/" + schema + "." + table
+}
@@ -0,0 +1,64 @@
+package ownership
+
+import (
+	"context"
+	"strings"
+
+	"data-voyager/core/internal/connection"
+)
+
+// referenceSource exposes ownership records of a datasource and its tables
+// as datasource references.
+type referenceSource struct {
+	repo Repository
+}
+
+// NewReferenceSource returns the connection.ReferenceSource for ownership
+// records, so deleting a datasource does not leave them orphaned.
+func NewReferenceSource(repo Repository) connection.ReferenceSource {
+	return &referenceSource{repo: repo}
+}
+
+func (s *referenceSource) References(ctx context.Context, datasourceID string) ([]connection.Reference, error) {
+	records, err := s.records(ctx, datasourceID)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]connection.Reference, len(records))
+	for i, o := range records {
+		refs[i] = connection.Reference{Kind: "ownership", ID: o.ID, Name: o.ResourceType + ":" + o.ResourceID}
+	}
+	return refs, nil
+}
+
+func (s *referenceSource) DeleteReferences(ctx context.Context, datasourceID string) error {
+	records, err := s.records(ctx, datasourceID)
+	if err != nil {
+		return err
+	}
+	for _, o := range records {
+		if err := s.repo.Delete(ctx, o.ResourceType, o.ResourceID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// records returns the ownership of the datasource itself and of its tables.
+func (s *referenceSource) records(ctx context.Context, datasourceID string) ([]*Ownership, error) {
+	var out []*Ownership
+	if o, err := s.repo.Get(ctx, ResourceDatasource, datasourceID); err == nil && o != nil {
+		out = append(out, o)
+	}
+	tables, err := s.repo.List(ctx, Filter{ResourceType: ResourceTable})
+	if err != nil {
+		return nil, err
+	}
+	prefix := datasourceID + "/"
+	for _, o := range tables {
+		if strings.HasPrefix(o.ResourceID, prefix) {
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,168 @@
+package ownership
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+)
+
+// UnownedResource is one entry of the unowned resources report.
+type UnownedResource struct {
+	ResourceType string `json:"resource_type"`
+	ResourceID   string `json:"resource_id"`
+	Name         string `json:"name"`
+	// Steward is set when the resource has a steward but no owner.
+	Steward string `json:"steward,omitempty"`
+}
+
+// Service manages ownership records and builds governance reports.
+type Service struct {
+	repo     Repository
+	conns    connection.Repository
+	registry *datasource.Registry
+}
+
+// NewService creates a Service.
+func NewService(repo Repository, conns connection.Repository, registry *datasource.Registry) *Service {
+	return &Service{repo: repo, conns: conns, registry: registry}
+}
+
+// Set assigns owner/steward to a resource, replacing any previous record.
+func (s *Service) Set(ctx context.Context, o *Ownership) error {
+	if err := validate(o); err != nil {
+		return err
+	}
+	if o.ID == "" {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return fmt.Errorf("generate uuid: %w", err)
+		}
+		o.ID = id.String()
+	}
+	o.UpdatedAt = time.Now().UTC()
+	return s.repo.Upsert(ctx, o)
+}
+
+// Get returns the ownership record for a resource.
+func (s *Service) Get(ctx context.Context, resourceType, resourceID string) (*Ownership, error) {
+	return s.repo.Get(ctx, resourceType, resourceID)
+}
+
+// List returns ownership records matching filter.
+func (s *Service) List(ctx context.Context, filter Filter) ([]*Ownership, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// Delete removes ownership from a resource.
+func (s *Service) Delete(ctx context.Context, resourceType, resourceID string) error {
+	return s.repo.Delete(ctx, resourceType, resourceID)
+}
+
+// Unowned lists resources of resourceType that have no owner. Tables are
+// discovered by introspecting datasourceID's schema, so it is required for
+// ResourceTable.
+func (s *Service) Unowned(ctx context.Context, resourceType, datasourceID string) ([]UnownedResource, error) {
+	records, err := s.repo.List(ctx, Filter{ResourceType: resourceType})
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]*Ownership, len(records))
+	for _, r := range records {
+		known[r.ResourceID] = r
+	}
+	unowned := func(id, name string) (UnownedResource, bool) {
+		r, ok := known[id]
+		if ok && r.Owner != "" {
+			return UnownedResource{}, false
+		}
+		u := UnownedResource{ResourceType: resourceType, ResourceID: id, Name: name}
+		if ok {
+			u.Steward = r.Steward
+		}
+		return u, true
+	}
+
+	var out []UnownedResource
+	switch resourceType {
+	case ResourceDatasource:
+		conns, err := s.conns.List(ctx, connection.Filter{})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range conns {
+			if u, ok := unowned(c.ID, c.Name); ok {
+				out = append(out, u)
+			}
+		}
+	case ResourceTable:
+		if datasourceID == "" {
+			return nil, fmt.Errorf("datasource_uid is required for table reports")
+		}
+		schema, err := s.loadSchema(ctx, datasourceID)
+		if err != nil {
+			return nil, err
+		}
+		for _, db := range schema.Databases {
+			for _, t := range db.Tables {
+				id := TableResourceID(datasourceID, db.Name, t.Name)
+				if u, ok := unowned(id, db.Name+"."+t.Name); ok {
+					out = append(out, u)
+				}
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unowned report not supported for resource type %q", resourceType)
+	}
+	return out, nil
+}
+
+func (s *Service) loadSchema(ctx context.Context, datasourceID string) (*datasource.SchemaInfo, error) {
+	conn, err := s.conns.GetByID(ctx, datasourceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := connection.CheckOrigin(ctx, conn); err != nil {
+		return nil, err
+	}
+	plugin, ok := s.registry.Get(conn.Type)
+	if !ok {
+		return nil, fmt.Errorf("plugin not found for type %s", conn.Type)
+	}
+	cfg, err := plugin.ParseConfig(conn.Config)
+	if err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	dbConn, err := plugin.Connect(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer func() { _ = dbConn.Close() }()
+	return dbConn.GetSchema(ctx)
+}
+
+func validate(o *Ownership) error {
+	switch o.ResourceType {
+	case ResourceDatasource, ResourceTable, ResourceSavedQuery:
+	default:
+		return fmt.Errorf("resource_type must be one of: datasource, table, saved_query")
+	}
+	if o.ResourceID == "" {
+		return fmt.Errorf("resource_id is required")
+	}
+	if o.Owner == "" && o.Steward == "" {
+		return fmt.Errorf("owner or steward is required")
+	}
+	switch o.OwnerKind {
+	case "":
+		o.OwnerKind = KindUser
+	case KindUser, KindTeam:
+	default:
+		return fmt.Errorf("owner_kind must be one of: user, team")
+	}
+	return nil
+}
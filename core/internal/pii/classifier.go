@@ -0,0 +1,185 @@
+package pii
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// detector recognises one category by value and by column name.
+type detector struct {
+	category string
+	name     *regexp.Regexp
+	match    func(s string) bool
+}
+
+var (
+	emailRe = regexp.MustCompile(`^[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}$`)
+	// phoneRe accepts digits with the usual separators; isPhone additionally
+	// requires a "+" or a separator so plain integer IDs are not phones, and
+	// rejects the national ID shapes, which would otherwise pass as phones.
+	phoneRe = regexp.MustCompile(`^\+?\(?\d[\d\s().\-]{5,18}\d$`)
+	ssnRe   = regexp.MustCompile(`^(\d{3})-(\d{2})-(\d{4})$`)
+	// rrnRe is a Korean resident registration number: birth date, then a
+	// gender/century digit.
+	rrnRe = regexp.MustCompile(`^\d{2}(0[1-9]|1[0-2])(0[1-9]|[12]\d|3[01])-[1-8]\d{6}$`)
+)
+
+var detectors = []detector{
+	{CategoryEmail, regexp.MustCompile(`e_?mail`), emailRe.MatchString},
+	{CategoryPhone, regexp.MustCompile(`phone|mobile|cell|fax|^tel`), isPhone},
+	{CategoryCreditCard, regexp.MustCompile(`card|^cc_|_cc$|^pan$`), isCreditCard},
+	{CategoryNationalID, regexp.MustCompile(`ssn|social_?sec|national_?id|rrn|jumin|resident_?(no|num)`), isNationalID},
+}
+
+func isPhone(s string) bool {
+	if !phoneRe.MatchString(s) || ssnRe.MatchString(s) || rrnRe.MatchString(s) {
+		return false
+	}
+	digits := digitsOf(s)
+	return len(digits) >= 7 && len(digits) <= 15 && strings.ContainsAny(s, "+ -.()")
+}
+
+func isCreditCard(s string) bool {
+	if strings.Trim(s, "0123456789 -") != "" {
+		return false
+	}
+	d := digitsOf(s)
+	if len(d) < 13 || len(d) > 19 {
+		return false
+	}
+	// Issuer prefixes: Visa, Mastercard, Amex, Discover, JCB, UnionPay.
+	switch {
+	case d[0] == '4',
+		d[:2] >= "51" && d[:2] <= "55",
+		d[:4] >= "2221" && d[:4] <= "2720",
+		d[:2] == "34", d[:2] == "37",
+		d[:4] == "6011", d[:2] == "65",
+		d[:2] == "35", d[:2] == "62":
+	default:
+		return false
+	}
+	return luhn(d)
+}
+
+func luhn(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		n := int(digits[i] - '0')
+		if double {
+			if n *= 2; n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func isNationalID(s string) bool {
+	if m := ssnRe.FindStringSubmatch(s); m != nil {
+		// Area 000, 666 and 900-999, group 00 and serial 0000 are never issued.
+		return m[1] != "000" && m[1] != "666" && m[1][0] != '9' && m[2] != "00" && m[3] != "0000"
+	}
+	return rrnRe.MatchString(s)
+}
+
+func digitsOf(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Match is the verdict on one column.
+type Match struct {
+	Category string
+	// Confidence is in [0, 1]: the share of sampled values that matched,
+	// raised slightly when the column name agrees.
+	Confidence float64
+}
+
+const (
+	// minValueShare is the share of non-null sampled values that must match
+	// for a column to be tagged on values alone.
+	minValueShare = 0.6
+	// minHintedShare is the share needed when the column name agrees.
+	minHintedShare = 0.2
+	// nameOnlyConfidence is given to a hinted column with no sampled values.
+	nameOnlyConfidence = 0.3
+	nameBonus          = 0.1
+)
+
+// Classify decides whether a column holds PII from its name and a sample of
+// its values. ok is false when no category is likely.
+func Classify(column string, values []any) (m Match, ok bool) {
+	var samples []string
+	for _, v := range values {
+		if s, ok := textValue(v); ok && s != "" {
+			samples = append(samples, s)
+		}
+	}
+	name := strings.ToLower(column)
+
+	for _, d := range detectors {
+		hinted := d.name.MatchString(name)
+		if len(samples) == 0 {
+			if hinted && len(values) == countNil(values) && nameOnlyConfidence > m.Confidence {
+				m, ok = Match{Category: d.category, Confidence: nameOnlyConfidence}, true
+			}
+			continue
+		}
+		n := 0
+		for _, s := range samples {
+			if d.match(s) {
+				n++
+			}
+		}
+		share := float64(n) / float64(len(samples))
+		if share < minValueShare && !(hinted && share >= minHintedShare) {
+			continue
+		}
+		conf := share
+		if hinted {
+			conf = math.Min(1, conf+nameBonus)
+		}
+		conf = math.Round(conf*100) / 100
+		if conf > m.Confidence {
+			m, ok = Match{Category: d.category, Confidence: conf}, true
+		}
+	}
+	return m, ok
+}
+
+// textValue renders strings and integers, the types PII is stored as.
+func textValue(v any) (string, bool) {
+	switch x := v.(type) {
+	case string:
+		return strings.TrimSpace(x), true
+	case []byte:
+		return strings.TrimSpace(string(x)), true
+	case int64:
+		return strconv.FormatInt(x, 10), true
+	case uint64:
+		return strconv.FormatUint(x, 10), true
+	case int:
+		return strconv.Itoa(x), true
+	}
+	return "", false
+}
+
+func countNil(values []any) int {
+	n := 0
+	for _, v := range values {
+		if v == nil {
+			n++
+		}
+	}
+	return n
+}
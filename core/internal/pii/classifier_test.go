@@ -0,0 +1,57 @@
+package pii
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		name     string
+		column   string
+		values   []any
+		category string
+	}{
+		{"emails", "contact", []any{"a@example.com", "b.c+d@mail.example.co.kr", nil}, CategoryEmail},
+		{"phones", "contact_no", []any{"+1 415 555 0100", "(02) 123-4567", "010-1234-5678"}, CategoryPhone},
+		{"cards as text", "pan", []any{"4111 1111 1111 1111", "5555-5555-5555-4444"}, CategoryCreditCard},
+		{"cards as integers", "payment", []any{int64(4012888888881881), int64(378282246310005)}, CategoryCreditCard},
+		{"ssn", "tax_ref", []any{"123-45-6789", "234-56-7890"}, CategoryNationalID},
+		{"korean rrn", "jumin_no", []any{"900101-1234567", "851231-2345678"}, CategoryNationalID},
+		{"hinted mostly empty", "email", []any{"a@example.com", "n/a", "unknown", "-", "?"}, CategoryEmail},
+		{"hinted without data", "mobile_phone", []any{nil, nil}, CategoryPhone},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m, ok := Classify(c.column, c.values)
+			assert.True(t, ok)
+			assert.Equal(t, c.category, m.Category)
+			assert.Greater(t, m.Confidence, 0.0)
+			assert.LessOrEqual(t, m.Confidence, 1.0)
+		})
+	}
+}
+
+func TestClassify_NotPII(t *testing.T) {
+	for name, c := range map[string]struct {
+		column string
+		values []any
+	}{
+		"integer ids":          {"id", []any{int64(1234567), int64(7654321)}},
+		"luhn-invalid numbers": {"ref", []any{"4111 1111 1111 1112", "5555-5555-5555-4445"}},
+		"never-issued ssn":     {"code", []any{"000-12-3456", "666-12-3456", "900-12-3456"}},
+		"hinted flags":         {"email_verified", []any{true, false}},
+		"mostly other text":    {"notes", []any{"call me", "a@example.com", "later", "ok", "fine"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			m, ok := Classify(c.column, c.values)
+			assert.False(t, ok, "got %+v", m)
+		})
+	}
+}
+
+func TestLuhn(t *testing.T) {
+	assert.True(t, luhn("79927398713"))
+	assert.False(t, luhn("79927398710"))
+}
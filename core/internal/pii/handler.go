@@ -0,0 +1,156 @@
+package pii
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// Handler serves the PII scan and column tag endpoints.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates a PII HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// ─── wire types ───────────────────────────────────────────────────────────────
+
+type scanRequest struct {
+	Schema     string   `json:"schema"`
+	Tables     []string `json:"tables"`
+	SampleSize int      `json:"sample_size"`
+}
+
+type tagRequest struct {
+	Schema   string `json:"schema"`
+	Table    string `json:"table" binding:"required"`
+	Column   string `json:"column" binding:"required"`
+	Category string `json:"category" binding:"required"`
+}
+
+// ─── handlers ─────────────────────────────────────────────────────────────────
+
+// requireWrite answers 403 unless the caller may change datasources. Tags
+// feed masking, and scans replace the scanner's tags.
+func requireWrite(c *gin.Context) bool {
+	if identity.FromContext(c.Request.Context()).Can(identity.PermDatasourceWrite) {
+		return true
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "permission denied")})
+	return false
+}
+
+// StartScan handles POST /datasources/:uid/pii-scans
+func (h *Handler) StartScan(c *gin.Context) {
+	if !requireWrite(c) {
+		return
+	}
+	var body scanRequest
+	// An empty body scans everything.
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	sc, err := h.svc.StartScan(c.Request.Context(), c.Param("uid"), ScanRequest{
+		Schema:     body.Schema,
+		Tables:     body.Tables,
+		SampleSize: body.SampleSize,
+	})
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"data": sc})
+}
+
+// GetScan handles GET /pii-scans/:id
+func (h *Handler) GetScan(c *gin.Context) {
+	sc, err := h.svc.GetScan(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "scan not found")})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": sc})
+}
+
+// ListTags handles GET /datasources/:uid/column-tags?sensitive=true
+func (h *Handler) ListTags(c *gin.Context) {
+	list := h.svc.Tags
+	if c.Query("sensitive") == "true" {
+		list = h.svc.SensitiveColumns
+	}
+	tags, err := list(c.Request.Context(), c.Param("uid"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": tags})
+}
+
+// SetTag handles PUT /datasources/:uid/column-tags
+func (h *Handler) SetTag(c *gin.Context) {
+	if !requireWrite(c) {
+		return
+	}
+	var body tagRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	t := &ColumnTag{
+		DatasourceID: c.Param("uid"),
+		Schema:       body.Schema,
+		Table:        body.Table,
+		Column:       body.Column,
+		Category:     body.Category,
+	}
+	if err := h.svc.SetTag(c.Request.Context(), t); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": t})
+}
+
+// DeleteTag handles DELETE /datasources/:uid/column-tags?schema=&table=&column=
+func (h *Handler) DeleteTag(c *gin.Context) {
+	if !requireWrite(c) {
+		return
+	}
+	err := h.svc.DeleteTag(c.Request.Context(), c.Param("uid"), c.Query("schema"), c.Query("table"), c.Query("column"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "datasource not found")})
+	case errors.Is(err, ErrInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrScanRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// RegisterRoutes wires the handler onto r.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.POST("/datasources/:uid/pii-scans", h.StartScan)
+	r.GET("/pii-scans/:id", h.GetScan)
+	r.GET("/datasources/:uid/column-tags", h.ListTags)
+	r.PUT("/datasources/:uid/column-tags", h.SetTag)
+	r.DELETE("/datasources/:uid/column-tags", h.DeleteTag)
+}
@@ -0,0 +1,54 @@
+// Package pii finds columns that likely hold personal data. A scan samples
+// every column of a datasource, classifies values with patterns and name
+// heuristics, and records the result as column tags — the data dictionary
+// entries masking policies consult to decide what to redact.
+package pii
+
+import (
+	"context"
+	"time"
+)
+
+// Categories.
+const (
+	CategoryEmail      = "email"
+	CategoryPhone      = "phone"
+	CategoryCreditCard = "credit_card"
+	CategoryNationalID = "national_id"
+	// CategoryNone marks a column reviewed as not sensitive. Only manual tags
+	// use it; it keeps later scans from tagging the column.
+	CategoryNone = "none"
+)
+
+// Tag sources.
+const (
+	SourceScanner = "scanner"
+	SourceManual  = "manual"
+)
+
+// ColumnTag classifies one column. Manual tags always win over scanner tags
+// and are never changed by a scan.
+type ColumnTag struct {
+	DatasourceID string    `json:"datasource_id"`
+	Schema       string    `json:"schema"`
+	Table        string    `json:"table"`
+	Column       string    `json:"column"`
+	Category     string    `json:"category"`
+	Confidence   float64   `json:"confidence"`
+	Source       string    `json:"source"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Repository persists column tags.
+// Implemented per-driver in store/{postgres,sqlite,mysql}/.
+type Repository interface {
+	// List returns the tags of a datasource ordered by schema, table and
+	// column.
+	List(ctx context.Context, datasourceID string) ([]*ColumnTag, error)
+	// Upsert stores t, replacing any tag on the same column.
+	Upsert(ctx context.Context, t *ColumnTag) error
+	Delete(ctx context.Context, datasourceID, schema, table, column string) error
+	// DeleteScanned removes the scanner tags of one table so a rescan can
+	// replace them.
+	DeleteScanned(ctx context.Context, datasourceID, schema, table string) error
+}
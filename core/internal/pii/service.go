@@ -0,0 +1,339 @@
+package pii
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	qb "data-voyager/core/internal/query_builder"
+	"data-voyager/sdk"
+)
+
+var (
+	// ErrInvalid wraps validation failures so handlers can map them to 400.
+	ErrInvalid = errors.New("invalid request")
+	// ErrNotFound is returned for an unknown datasource or scan.
+	ErrNotFound = errors.New("not found")
+	// ErrScanRunning is returned when the datasource is already being scanned.
+	ErrScanRunning = errors.New("a scan of this datasource is already running")
+)
+
+const (
+	defaultSampleSize = 200
+	maxSampleSize     = 10000
+	maxScanDuration   = 30 * time.Minute
+	// keptScans bounds how many finished scans are remembered for polling.
+	keptScans = 100
+)
+
+// Scan statuses.
+const (
+	ScanRunning = "running"
+	ScanDone    = "done"
+	ScanFailed  = "failed"
+)
+
+// ScanRequest narrows a scan. The zero value scans every table.
+type ScanRequest struct {
+	Schema     string
+	Tables     []string
+	SampleSize int
+}
+
+// Scan is the progress of one scanner job. Failures lists tables that could
+// not be sampled; the rest of the scan carries on without them.
+type Scan struct {
+	ID            string     `json:"id"`
+	DatasourceID  string     `json:"datasource_id"`
+	Status        string     `json:"status"`
+	Error         string     `json:"error,omitempty"`
+	TablesTotal   int        `json:"tables_total"`
+	TablesScanned int        `json:"tables_scanned"`
+	Tagged        int        `json:"tagged"`
+	Failures      []string   `json:"failures,omitempty"`
+	StartedAt     time.Time  `json:"started_at"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
+
+// table identifies one scanned table.
+type table struct{ schema, name string }
+
+// Service runs scans and manages column tags.
+type Service struct {
+	repo     Repository
+	conns    connection.Repository
+	registry *datasource.Registry
+
+	mu    sync.Mutex
+	scans map[string]*Scan
+	order []string // scan IDs, oldest first
+}
+
+// NewService creates a Service. Datasources are resolved through
+// connection.Visible, so callers only reach those they may see.
+func NewService(repo Repository, conns connection.Repository, registry *datasource.Registry) *Service {
+	return &Service{repo: repo, conns: connection.Visible(conns), registry: registry, scans: map[string]*Scan{}}
+}
+
+// datasource loads a datasource the caller may see.
+func (s *Service) datasource(ctx context.Context, id string) (*connection.Connection, error) {
+	conn, err := s.conns.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: datasource %s", ErrNotFound, id)
+	}
+	return conn, nil
+}
+
+// Tags returns every tag of a datasource.
+func (s *Service) Tags(ctx context.Context, datasourceID string) ([]*ColumnTag, error) {
+	if _, err := s.datasource(ctx, datasourceID); err != nil {
+		return nil, err
+	}
+	return s.repo.List(ctx, datasourceID)
+}
+
+// SensitiveColumns returns the columns of a datasource tagged with a PII
+// category, for masking policies to redact.
+func (s *Service) SensitiveColumns(ctx context.Context, datasourceID string) ([]*ColumnTag, error) {
+	tags, err := s.Tags(ctx, datasourceID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(tags, func(t *ColumnTag) bool { return t.Category == CategoryNone }), nil
+}
+
+// SetTag records a manual classification, which later scans leave alone.
+func (s *Service) SetTag(ctx context.Context, t *ColumnTag) error {
+	if t.Table == "" || t.Column == "" {
+		return fmt.Errorf("%w: table and column are required", ErrInvalid)
+	}
+	switch t.Category {
+	case CategoryEmail, CategoryPhone, CategoryCreditCard, CategoryNationalID, CategoryNone:
+	default:
+		return fmt.Errorf("%w: unknown category %q", ErrInvalid, t.Category)
+	}
+	if _, err := s.datasource(ctx, t.DatasourceID); err != nil {
+		return err
+	}
+	t.Source, t.Confidence, t.UpdatedAt = SourceManual, 1, time.Now().UTC()
+	return s.repo.Upsert(ctx, t)
+}
+
+// DeleteTag removes a tag of either source.
+func (s *Service) DeleteTag(ctx context.Context, datasourceID, schema, tbl, column string) error {
+	if _, err := s.datasource(ctx, datasourceID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, datasourceID, schema, tbl, column)
+}
+
+// GetScan returns a snapshot of a scan's progress. Scans of datasources the
+// caller may not see are not found.
+func (s *Service) GetScan(ctx context.Context, id string) (*Scan, error) {
+	s.mu.Lock()
+	sc, ok := s.scans[id]
+	var cp Scan
+	if ok {
+		cp = *sc
+		cp.Failures = slices.Clone(sc.Failures)
+	}
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: scan %s", ErrNotFound, id)
+	}
+	if _, err := s.datasource(ctx, cp.DatasourceID); err != nil {
+		return nil, fmt.Errorf("%w: scan %s", ErrNotFound, id)
+	}
+	return &cp, nil
+}
+
+// StartScan begins scanning a datasource in the background and returns the
+// scan to poll with GetScan.
+func (s *Service) StartScan(ctx context.Context, datasourceID string, req ScanRequest) (*Scan, error) {
+	if req.SampleSize == 0 {
+		req.SampleSize = defaultSampleSize
+	}
+	if req.SampleSize < 1 || req.SampleSize > maxSampleSize {
+		return nil, fmt.Errorf("%w: sample_size must be between 1 and %d", ErrInvalid, maxSampleSize)
+	}
+	conn, err := s.datasource(ctx, datasourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	for _, sc := range s.scans {
+		if sc.DatasourceID == datasourceID && sc.Status == ScanRunning {
+			s.mu.Unlock()
+			return nil, ErrScanRunning
+		}
+	}
+	sc := &Scan{ID: uuid.NewString(), DatasourceID: datasourceID, Status: ScanRunning, StartedAt: time.Now().UTC()}
+	s.scans[sc.ID] = sc
+	s.order = append(s.order, sc.ID)
+	s.pruneLocked()
+	s.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), maxScanDuration)
+		defer cancel()
+		err := s.scan(ctx, sc, conn, req)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		now := time.Now().UTC()
+		sc.FinishedAt = &now
+		sc.Status = ScanDone
+		if err != nil {
+			sc.Status, sc.Error = ScanFailed, err.Error()
+			slog.Warn("pii scan failed", "datasource", conn.Name, "err", err)
+		}
+	}()
+	return s.GetScan(ctx, sc.ID)
+}
+
+// pruneLocked forgets the oldest finished scans beyond keptScans.
+func (s *Service) pruneLocked() {
+	for len(s.order) > keptScans {
+		i := slices.IndexFunc(s.order, func(id string) bool { return s.scans[id].Status != ScanRunning })
+		if i < 0 {
+			return
+		}
+		delete(s.scans, s.order[i])
+		s.order = slices.Delete(s.order, i, i+1)
+	}
+}
+
+func (s *Service) scan(ctx context.Context, sc *Scan, conn *connection.Connection, req ScanRequest) error {
+	plugin, ok := s.registry.Get(conn.Type)
+	if !ok {
+		return fmt.Errorf("plugin not found for type %s", conn.Type)
+	}
+	cfg, err := plugin.ParseConfig(conn.Config)
+	if err != nil {
+		return fmt.Errorf("parse config: %w", err)
+	}
+	dbConn, err := plugin.Connect(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer func() { _ = dbConn.Close() }()
+
+	tables, err := listTables(ctx, dbConn, req)
+	if err != nil {
+		return err
+	}
+	existing, err := s.repo.List(ctx, conn.ID)
+	if err != nil {
+		return fmt.Errorf("load tags: %w", err)
+	}
+	manual := map[string]bool{}
+	for _, t := range existing {
+		if t.Source == SourceManual {
+			manual[t.Schema+"\x00"+t.Table+"\x00"+t.Column] = true
+		}
+	}
+	s.mu.Lock()
+	sc.TablesTotal = len(tables)
+	s.mu.Unlock()
+
+	dialect := qb.DialectFor(string(conn.Type))
+	for _, t := range tables {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		tags, err := s.scanTable(ctx, dbConn, dialect, conn.ID, t, req.SampleSize)
+		if err == nil {
+			err = s.repo.DeleteScanned(ctx, conn.ID, t.schema, t.name)
+		}
+		tagged := 0
+		for _, tag := range tags {
+			if err != nil {
+				break
+			}
+			if manual[tag.Schema+"\x00"+tag.Table+"\x00"+tag.Column] {
+				continue
+			}
+			if err = s.repo.Upsert(ctx, tag); err == nil {
+				tagged++
+			}
+		}
+		s.mu.Lock()
+		sc.TablesScanned++
+		sc.Tagged += tagged
+		if err != nil {
+			sc.Failures = append(sc.Failures, fmt.Sprintf("%s: %s", dialect.QualifiedTable(t.schema, t.name), err))
+		}
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// scanTable samples one table and classifies its columns.
+func (s *Service) scanTable(ctx context.Context, dbConn sdk.Connection, d qb.Dialect, datasourceID string, t table, sample int) ([]*ColumnTag, error) {
+	query, args, err := qb.Select{Schema: t.schema, Table: t.name, Limit: sample}.Build(d)
+	if err != nil {
+		return nil, err
+	}
+	res, err := dbConn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sample failed: %w", err)
+	}
+	if len(res.Frames) == 0 || res.Frames[0] == nil {
+		return nil, nil
+	}
+	now := time.Now().UTC()
+	var tags []*ColumnTag
+	for _, f := range res.Frames[0].Fields {
+		m, ok := Classify(f.Name, f.Values)
+		if !ok {
+			continue
+		}
+		tags = append(tags, &ColumnTag{
+			DatasourceID: datasourceID,
+			Schema:       t.schema,
+			Table:        t.name,
+			Column:       f.Name,
+			Category:     m.Category,
+			Confidence:   m.Confidence,
+			Source:       SourceScanner,
+			UpdatedAt:    now,
+		})
+	}
+	return tags, nil
+}
+
+// listTables resolves the tables a request covers.
+func listTables(ctx context.Context, dbConn sdk.Connection, req ScanRequest) ([]table, error) {
+	var out []table
+	add := func(schema string, infos []sdk.TableInfo) {
+		for _, ti := range infos {
+			if len(req.Tables) == 0 || slices.Contains(req.Tables, ti.Name) {
+				out = append(out, table{schema: schema, name: ti.Name})
+			}
+		}
+	}
+	if req.Schema != "" {
+		infos, err := dbConn.GetTables(ctx, req.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("list tables: %w", err)
+		}
+		add(req.Schema, infos)
+		return out, nil
+	}
+	schema, err := dbConn.GetSchema(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read schema: %w", err)
+	}
+	for _, db := range schema.Databases {
+		add(db.Name, db.Tables)
+	}
+	return out, nil
+}
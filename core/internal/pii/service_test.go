@@ -0,0 +1,218 @@
+package pii
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/identity"
+	"data-voyager/sdk"
+)
+
+type memRepo struct{ tags map[string]*ColumnTag }
+
+func key(ds, schema, table, column string) string {
+	return strings.Join([]string{ds, schema, table, column}, "/")
+}
+
+func (r *memRepo) List(_ context.Context, ds string) ([]*ColumnTag, error) {
+	var out []*ColumnTag
+	for _, t := range r.tags {
+		if t.DatasourceID == ds {
+			cp := *t
+			out = append(out, &cp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Table+out[i].Column < out[j].Table+out[j].Column })
+	return out, nil
+}
+func (r *memRepo) Upsert(_ context.Context, t *ColumnTag) error {
+	r.tags[key(t.DatasourceID, t.Schema, t.Table, t.Column)] = t
+	return nil
+}
+func (r *memRepo) Delete(_ context.Context, ds, schema, table, column string) error {
+	delete(r.tags, key(ds, schema, table, column))
+	return nil
+}
+func (r *memRepo) DeleteScanned(_ context.Context, ds, schema, table string) error {
+	for k, t := range r.tags {
+		if t.DatasourceID == ds && t.Schema == schema && t.Table == table && t.Source == SourceScanner {
+			delete(r.tags, k)
+		}
+	}
+	return nil
+}
+
+type stubConns struct{ connection.Repository }
+
+func (stubConns) GetByID(_ context.Context, id string) (*connection.Connection, error) {
+	if id != "ds1" {
+		return nil, errors.New("not found")
+	}
+	return &connection.Connection{ID: id, Type: "postgresql", Config: json.RawMessage(`{}`)}, nil
+}
+
+type stubConfig struct{}
+
+func (stubConfig) Validate() error             { return nil }
+func (stubConfig) GetConnectionString() string { return "" }
+
+type stubPlugin struct {
+	sdk.DatasourcePlugin
+	conn *stubConn
+}
+
+func (p *stubPlugin) GetType() sdk.DataSourceType { return "postgresql" }
+func (p *stubPlugin) ParseConfig(json.RawMessage) (sdk.ConnectionConfig, error) {
+	return stubConfig{}, nil
+}
+func (p *stubPlugin) Connect(context.Context, sdk.ConnectionConfig) (sdk.Connection, error) {
+	return p.conn, nil
+}
+
+// stubConn serves a fixed schema; tables maps a sample query to its frame.
+type stubConn struct {
+	sdk.Connection
+	schema *sdk.SchemaInfo
+	tables map[string]*sdk.DataFrame
+}
+
+func (c *stubConn) GetSchema(context.Context) (*sdk.SchemaInfo, error) { return c.schema, nil }
+func (c *stubConn) Query(_ context.Context, q string, _ ...any) (*sdk.QueryResult, error) {
+	f, ok := c.tables[q]
+	if !ok {
+		return nil, errors.New("relation does not exist")
+	}
+	return &sdk.QueryResult{Frames: []*sdk.DataFrame{f}}, nil
+}
+func (c *stubConn) Close() error { return nil }
+
+func waitScan(t *testing.T, svc *Service, id string) *Scan {
+	t.Helper()
+	var sc *Scan
+	require.Eventually(t, func() bool {
+		var err error
+		sc, err = svc.GetScan(context.Background(), id)
+		return err == nil && sc.Status != ScanRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	return sc
+}
+
+func TestService_Scan(t *testing.T) {
+	conn := &stubConn{
+		schema: &sdk.SchemaInfo{Databases: []sdk.DatabaseInfo{{Name: "public", Tables: []sdk.TableInfo{
+			{Name: "users"}, {Name: "orders"}, {Name: "gone"},
+		}}}},
+		tables: map[string]*sdk.DataFrame{
+			`SELECT * FROM "public"."users" LIMIT 200`: {Fields: []sdk.Field{
+				{Name: "id", Values: []any{int64(1), int64(2)}},
+				{Name: "email", Values: []any{"a@example.com", "b@example.com"}},
+				{Name: "phone", Values: []any{"+82 10 1234 5678", "010-2345-6789"}},
+			}},
+			`SELECT * FROM "public"."orders" LIMIT 200`: {Fields: []sdk.Field{
+				{Name: "id", Values: []any{int64(1)}},
+				{Name: "card", Values: []any{"4111111111111111"}},
+			}},
+		},
+	}
+	reg := datasource.NewRegistry()
+	reg.Register(&stubPlugin{conn: conn})
+	repo := &memRepo{tags: map[string]*ColumnTag{}}
+	svc := NewService(repo, stubConns{}, reg)
+	ctx := context.Background()
+
+	// A reviewer has already decided the phone column is not sensitive, and
+	// a stale scanner tag remains from an earlier scan.
+	require.NoError(t, svc.SetTag(ctx, &ColumnTag{DatasourceID: "ds1", Schema: "public", Table: "users", Column: "phone", Category: CategoryNone}))
+	require.NoError(t, repo.Upsert(ctx, &ColumnTag{DatasourceID: "ds1", Schema: "public", Table: "users", Column: "old", Category: CategoryEmail, Source: SourceScanner}))
+
+	sc, err := svc.StartScan(ctx, "ds1", ScanRequest{})
+	require.NoError(t, err)
+	sc = waitScan(t, svc, sc.ID)
+	assert.Equal(t, ScanDone, sc.Status)
+	assert.Equal(t, 3, sc.TablesTotal)
+	assert.Equal(t, 3, sc.TablesScanned)
+	assert.Equal(t, 2, sc.Tagged)
+	require.Len(t, sc.Failures, 1)
+	assert.Contains(t, sc.Failures[0], `"public"."gone"`)
+
+	tags, err := svc.Tags(ctx, "ds1")
+	require.NoError(t, err)
+	got := map[string]string{}
+	for _, tag := range tags {
+		got[tag.Table+"."+tag.Column] = tag.Category + "/" + tag.Source
+	}
+	assert.Equal(t, map[string]string{
+		"orders.card": "credit_card/scanner",
+		"users.email": "email/scanner",
+		"users.phone": "none/manual",
+	}, got)
+
+	sensitive, err := svc.SensitiveColumns(ctx, "ds1")
+	require.NoError(t, err)
+	assert.Len(t, sensitive, 2)
+}
+
+func TestService_Validation(t *testing.T) {
+	svc := NewService(&memRepo{tags: map[string]*ColumnTag{}}, stubConns{}, datasource.NewRegistry())
+	ctx := context.Background()
+
+	_, err := svc.StartScan(ctx, "missing", ScanRequest{})
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = svc.StartScan(ctx, "ds1", ScanRequest{SampleSize: maxSampleSize + 1})
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	err = svc.SetTag(ctx, &ColumnTag{DatasourceID: "ds1", Table: "t", Column: "c", Category: "passport"})
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	_, err = svc.GetScan(ctx, "nope")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestService_HidesInvisibleDatasources(t *testing.T) {
+	repo := &memRepo{tags: map[string]*ColumnTag{}}
+	svc := NewService(repo, stubConns{}, datasource.NewRegistry())
+	require.NoError(t, svc.SetTag(context.Background(), &ColumnTag{DatasourceID: "ds1", Table: "users", Column: "email", Category: CategoryEmail}))
+
+	ctx := identity.With(context.Background(), &identity.Identity{Username: "bob", Role: identity.RoleEditor, Datasources: []string{"other"}})
+	_, err := svc.Tags(ctx, "ds1")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = svc.StartScan(ctx, "ds1", ScanRequest{})
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, svc.DeleteTag(ctx, "ds1", "", "users", "email"), ErrNotFound)
+	assert.Len(t, repo.tags, 1)
+}
+
+func TestRoutes_ChangesNeedDatasourceWrite(t *testing.T) {
+	svc := NewService(&memRepo{tags: map[string]*ColumnTag{}}, stubConns{}, datasource.NewRegistry())
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(identity.With(c.Request.Context(), &identity.Identity{Username: "v", Role: identity.RoleViewer}))
+	})
+	RegisterRoutes(&r.RouterGroup, NewHandler(svc))
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/datasources/ds1/pii-scans", nil),
+		httptest.NewRequest(http.MethodPut, "/datasources/ds1/column-tags", strings.NewReader(`{"table":"t","column":"c","category":"none"}`)),
+		httptest.NewRequest(http.MethodDelete, "/datasources/ds1/column-tags?table=t&column=c", nil),
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code, req.Method+" "+req.URL.Path)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/datasources/ds1/column-tags", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
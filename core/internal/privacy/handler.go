@@ -0,0 +1,61 @@
+package privacy
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/user"
+)
+
+// Handler serves the personal data endpoints.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates a privacy HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// Export handles GET /admin/users/:id/personal-data
+func (h *Handler) Export(c *gin.Context) {
+	b, err := h.svc.Export(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="personal-data-`+b.User.ID+`.json"`)
+	c.JSON(http.StatusOK, gin.H{"data": b})
+}
+
+// Erase handles POST /admin/users/:id/erase
+func (h *Handler) Erase(c *gin.Context) {
+	e, err := h.svc.Erase(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": e})
+}
+
+func (h *Handler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, user.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "user not found")})
+	case errors.Is(err, ErrInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// RegisterRoutes wires the handler onto r. Both endpoints require the admin
+// permission.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	admin := r.Group("/admin", user.RequireAdmin)
+	admin.GET("/users/:id/personal-data", h.Export)
+	admin.POST("/users/:id/erase", h.Erase)
+}
@@ -0,0 +1,134 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"data-voyager/core/internal/export"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/user"
+)
+
+// ErrInvalid wraps requests that cannot be carried out, e.g. an admin
+// erasing their own account.
+var ErrInvalid = errors.New("invalid privacy request")
+
+// pseudonymPrefix starts an erased user's new username, which is otherwise
+// their ID so it stays unique and erasing twice renames nothing.
+const pseudonymPrefix = "erased-"
+
+// Users is the part of user.Service privacy requests need.
+type Users interface {
+	Get(ctx context.Context, id string) (*user.User, error)
+	Anonymize(ctx context.Context, id string) (*user.User, error)
+}
+
+// Exports is the part of export.Service privacy requests need.
+type Exports interface {
+	List(owner string, all bool) []*export.Job
+	Reassign(from, to string) (int, error)
+}
+
+// TableData is what one table holds about a user.
+type TableData struct {
+	Store string `json:"store"`
+	Table string `json:"table"`
+	Rows  []Row  `json:"rows"`
+}
+
+// Bundle is everything the app keeps about one user.
+type Bundle struct {
+	User        *user.User    `json:"user"`
+	Exports     []*export.Job `json:"exports"`
+	Tables      []TableData   `json:"tables"`
+	GeneratedAt time.Time     `json:"generated_at"`
+}
+
+// TableResult is how many values erasure rewrote in one table.
+type TableResult struct {
+	Store string `json:"store"`
+	Table string `json:"table"`
+	Rows  int64  `json:"rows"`
+}
+
+// Erasure reports what erasing a user changed.
+type Erasure struct {
+	UserID    string        `json:"user_id"`
+	Pseudonym string        `json:"pseudonym"`
+	Exports   int           `json:"exports"`
+	Tables    []TableResult `json:"tables"`
+	ErasedAt  time.Time     `json:"erased_at"`
+}
+
+// Service exports and erases users' personal data.
+type Service struct {
+	users   Users
+	exports Exports
+	tables  []Table
+	now     func() time.Time
+}
+
+// NewService creates a Service over tables. exports may be nil when export
+// jobs are not kept.
+func NewService(users Users, exports Exports, tables []Table) *Service {
+	return &Service{users: users, exports: exports, tables: tables, now: time.Now}
+}
+
+// Export gathers the user with id and every record that names them.
+func (s *Service) Export(ctx context.Context, id string) (*Bundle, error) {
+	u, err := s.users.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	b := &Bundle{User: u, Exports: []*export.Job{}, Tables: []TableData{}, GeneratedAt: s.now().UTC()}
+	if s.exports != nil {
+		b.Exports = s.exports.List(u.Username, false)
+	}
+	for _, t := range s.tables {
+		rows, err := t.rows(ctx, u.Username, u.Email)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) > 0 {
+			b.Tables = append(b.Tables, TableData{Store: t.Store, Table: t.Name, Rows: rows})
+		}
+	}
+	return b, nil
+}
+
+// Erase anonymizes the user with id: their account is stripped and
+// disabled, and their username is replaced by a pseudonym in every table
+// and export job. Erasing an already erased user changes nothing.
+func (s *Service) Erase(ctx context.Context, id string) (*Erasure, error) {
+	u, err := s.users.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if caller := identity.FromContext(ctx); caller != nil && caller.Username == u.Username {
+		return nil, fmt.Errorf("%w: you cannot erase your own account", ErrInvalid)
+	}
+	username, email, pseudonym := u.Username, u.Email, pseudonymPrefix+u.ID
+	if _, err := s.users.Anonymize(ctx, id); err != nil {
+		return nil, err
+	}
+
+	e := &Erasure{UserID: u.ID, Pseudonym: pseudonym, Tables: []TableResult{}}
+	if s.exports != nil {
+		if e.Exports, err = s.exports.Reassign(username, pseudonym); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range s.tables {
+		n, err := t.erase(ctx, username, email, pseudonym)
+		if err != nil {
+			return nil, err
+		}
+		e.Tables = append(e.Tables, TableResult{Store: t.Store, Table: t.Name, Rows: n})
+	}
+	e.ErasedAt = s.now().UTC()
+	slog.Info("erased personal data", "user_id", u.ID, "pseudonym", pseudonym)
+	return e, nil
+}
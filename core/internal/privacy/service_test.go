@@ -0,0 +1,194 @@
+package privacy_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/dashboard"
+	"data-voyager/core/internal/export"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/privacy"
+	"data-voyager/core/internal/store"
+	"data-voyager/core/internal/user"
+)
+
+type stubExports struct{ jobs []*export.Job }
+
+func (e *stubExports) List(owner string, _ bool) []*export.Job {
+	out := []*export.Job{}
+	for _, j := range e.jobs {
+		if j.Owner == owner {
+			out = append(out, j)
+		}
+	}
+	return out
+}
+
+func (e *stubExports) Reassign(from, to string) (int, error) {
+	n := 0
+	for _, j := range e.jobs {
+		if j.Owner == from {
+			j.Owner = to
+			n++
+		}
+	}
+	return n, nil
+}
+
+type fixture struct {
+	svc     *privacy.Service
+	users   *user.Service
+	repos   *store.Repos
+	exports *stubExports
+	ann     *user.User
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	ms, err := store.OpenMetadataStore(config.DBConfig{Type: "memory"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ms.Close() })
+	repos := ms.Repos()
+	ctx := context.Background()
+
+	users := user.NewService(repos.Users, repos.Impersonations, repos.Connection)
+	ann := &user.User{Username: "ann", Email: "ann@example.com", Name: "Ann", Role: identity.RoleEditor}
+	require.NoError(t, users.Create(ctx, ann))
+	require.NoError(t, users.Create(ctx, &user.User{Username: "root", Role: identity.RoleAdmin}))
+
+	at := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, repos.Snapshots.Create(ctx, &dashboard.Snapshot{ID: "s1", DashboardID: "d1", Name: "Q1", CreatedBy: "ann",
+		Content: &dashboard.SnapshotContent{}}))
+	require.NoError(t, repos.Impersonations.Record(ctx, &user.Impersonation{ID: "i1", Actor: "root", Target: "ann",
+		Method: "GET", Path: "/api/v1/whoami", Status: 200, At: at}))
+	require.NoError(t, repos.ExportAudit.Record(ctx, &export.AuditRecord{ID: "a1", ExportID: "e1", DatasourceID: "ds",
+		Table: "orders", Actor: "ann", Delivery: export.DeliveryDownload, At: at}))
+	require.NoError(t, repos.Invitations.Create(ctx, &user.Invitation{ID: "inv1", Email: "ann@example.com", Role: identity.RoleViewer,
+		InvitedBy: "root", TokenHash: "h1", ExpiresAt: at, Status: "accepted"}))
+	require.NoError(t, repos.Invitations.Create(ctx, &user.Invitation{ID: "inv2", Email: "bob@example.com", Role: identity.RoleViewer,
+		InvitedBy: "ann", TokenHash: "h2", ExpiresAt: at}))
+
+	exports := &stubExports{jobs: []*export.Job{{ID: "e1", Owner: "ann"}, {ID: "e2", Owner: "root"}}}
+	return &fixture{
+		svc:     privacy.NewService(users, exports, ms.PersonalDataTables()),
+		users:   users,
+		repos:   repos,
+		exports: exports,
+		ann:     ann,
+	}
+}
+
+func tableNames(data []privacy.TableData) map[string]int {
+	out := map[string]int{}
+	for _, d := range data {
+		out[d.Table] = len(d.Rows)
+	}
+	return out
+}
+
+func TestExport(t *testing.T) {
+	f := newFixture(t)
+
+	b, err := f.svc.Export(context.Background(), f.ann.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "ann", b.User.Username)
+	require.Len(t, b.Exports, 1)
+	assert.Equal(t, "e1", b.Exports[0].ID)
+	assert.Equal(t, map[string]int{
+		"users":               1,
+		"dashboard_snapshots": 1,
+		"impersonation_audit": 1,
+		"export_audit":        1,
+		"user_invitations":    2, // one to ann's email, one ann sent
+	}, tableNames(b.Tables))
+
+	_, err = f.svc.Export(context.Background(), "missing")
+	assert.ErrorIs(t, err, user.ErrNotFound)
+}
+
+func TestErase(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	e, err := f.svc.Erase(ctx, f.ann.ID)
+	require.NoError(t, err)
+	pseudonym := "erased-" + f.ann.ID
+	assert.Equal(t, pseudonym, e.Pseudonym)
+	assert.Equal(t, 1, e.Exports)
+	assert.Equal(t, pseudonym, f.exports.jobs[0].Owner)
+	assert.Equal(t, "root", f.exports.jobs[1].Owner)
+
+	u, err := f.users.Get(ctx, f.ann.ID)
+	require.NoError(t, err)
+	assert.Equal(t, pseudonym, u.Username)
+	assert.Empty(t, u.Email)
+	assert.Empty(t, u.Name)
+	assert.True(t, u.Disabled)
+
+	snap, err := f.repos.Snapshots.GetByID(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, pseudonym, snap.CreatedBy, "the snapshot is kept, attributed to the pseudonym")
+	imps, err := f.repos.Impersonations.List(ctx, pseudonym, 10)
+	require.NoError(t, err)
+	require.Len(t, imps, 1)
+	assert.Equal(t, "root", imps[0].Actor)
+	inv, err := f.repos.Invitations.GetByID(ctx, "inv1")
+	require.NoError(t, err)
+	assert.Empty(t, inv.Email)
+
+	b, err := f.svc.Export(ctx, f.ann.ID)
+	require.NoError(t, err)
+	assert.Len(t, b.Tables, 5, "records stay, under the pseudonym")
+	_, err = f.users.GetByUsername(ctx, "ann")
+	assert.Error(t, err)
+
+	again, err := f.svc.Erase(ctx, f.ann.ID)
+	require.NoError(t, err)
+	for _, r := range again.Tables {
+		assert.Zero(t, r.Rows, r.Table)
+	}
+}
+
+func TestErase_NotYourself(t *testing.T) {
+	f := newFixture(t)
+	ctx := identity.With(context.Background(), &identity.Identity{Username: "ann", Role: identity.RoleAdmin})
+	_, err := f.svc.Erase(ctx, f.ann.ID)
+	assert.ErrorIs(t, err, privacy.ErrInvalid)
+}
+
+func TestRoutes_RequireAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	f := newFixture(t)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(identity.With(c.Request.Context(),
+			&identity.Identity{Username: c.GetHeader("X-User"), Role: c.GetHeader("X-Role")}))
+	})
+	privacy.RegisterRoutes(r.Group("/api/v1"), privacy.NewHandler(f.svc))
+
+	for _, tc := range []struct {
+		role, method, path string
+		want               int
+	}{
+		{identity.RoleEditor, http.MethodGet, "/api/v1/admin/users/" + f.ann.ID + "/personal-data", http.StatusForbidden},
+		{identity.RoleAdmin, http.MethodGet, "/api/v1/admin/users/" + f.ann.ID + "/personal-data", http.StatusOK},
+		{identity.RoleAdmin, http.MethodGet, "/api/v1/admin/users/missing/personal-data", http.StatusNotFound},
+		{identity.RoleEditor, http.MethodPost, "/api/v1/admin/users/" + f.ann.ID + "/erase", http.StatusForbidden},
+		{identity.RoleAdmin, http.MethodPost, "/api/v1/admin/users/" + f.ann.ID + "/erase", http.StatusOK},
+	} {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		req.Header.Set("X-User", "root")
+		req.Header.Set("X-Role", tc.role)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, tc.want, w.Code, "%s %s as %s: %s", tc.method, tc.path, tc.role, w.Body)
+	}
+}
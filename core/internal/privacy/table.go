@@ -0,0 +1,116 @@
+// Package privacy answers data subject requests: it gathers everything the
+// app keeps about one user into a single export, and erases a departing
+// user's personal data. Erasure rewrites the username to a pseudonym rather
+// than deleting rows, so audit trails, ownership and history keep their
+// shape and still refer to one (now anonymous) account.
+package privacy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Table is a table holding usernames or email addresses. Name and the
+// column lists are trusted identifiers supplied by the store packages.
+type Table struct {
+	Store string
+	Name  string
+	// UserColumns hold usernames; erasure replaces them with the pseudonym.
+	UserColumns []string
+	// EmailColumns hold email addresses; erasure blanks them.
+	EmailColumns []string
+	DB           *sqlx.DB
+	// AlterUpdate rewrites rows with ALTER TABLE ... UPDATE, for stores such
+	// as ClickHouse that have no plain UPDATE.
+	AlterUpdate bool
+}
+
+// Row is one table row keyed by column name.
+type Row map[string]any
+
+// match returns the WHERE clause and arguments selecting username's rows.
+// An empty email matches nothing, since blank emails are not the user's.
+func (t Table) match(username, email string) (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+	for _, c := range t.UserColumns {
+		conds = append(conds, c+" = ?")
+		args = append(args, username)
+	}
+	if email != "" {
+		for _, c := range t.EmailColumns {
+			conds = append(conds, c+" = ?")
+			args = append(args, email)
+		}
+	}
+	return strings.Join(conds, " OR "), args
+}
+
+// rows returns every row that mentions username or email.
+func (t Table) rows(ctx context.Context, username, email string) ([]Row, error) {
+	where, args := t.match(username, email)
+	if where == "" {
+		return nil, nil
+	}
+	rs, err := t.DB.QueryxContext(ctx, t.DB.Rebind("SELECT * FROM "+t.Name+" WHERE "+where), args...)
+	if err != nil {
+		return nil, fmt.Errorf("query %s: %w", t.Name, err)
+	}
+	defer func() { _ = rs.Close() }()
+	var out []Row
+	for rs.Next() {
+		m := map[string]any{}
+		if err := rs.MapScan(m); err != nil {
+			return nil, fmt.Errorf("scan %s: %w", t.Name, err)
+		}
+		for k, v := range m {
+			if b, ok := v.([]byte); ok {
+				m[k] = string(b)
+			}
+		}
+		out = append(out, m)
+	}
+	return out, rs.Err()
+}
+
+// erase replaces username with pseudonym and blanks email, one column at a
+// time. It returns how many column values it rewrote; stores that rewrite
+// asynchronously report none.
+func (t Table) erase(ctx context.Context, username, email, pseudonym string) (int64, error) {
+	var n int64
+	set := func(column string, from, to any) error {
+		query := "UPDATE " + t.Name + " SET " + column + " = ? WHERE " + column + " = ?"
+		if t.AlterUpdate {
+			query = "ALTER TABLE " + t.Name + " UPDATE " + column + " = ? WHERE " + column + " = ?"
+		}
+		res, err := t.DB.ExecContext(ctx, t.DB.Rebind(query), to, from)
+		if err != nil {
+			return fmt.Errorf("update %s.%s: %w", t.Name, column, err)
+		}
+		if rows, err := res.RowsAffected(); err == nil {
+			n += rows
+		}
+		return nil
+	}
+	if username != pseudonym {
+		for _, c := range t.UserColumns {
+			if err := set(c, username, pseudonym); err != nil {
+				return n, err
+			}
+		}
+	}
+	if email == "" {
+		return n, nil
+	}
+	for _, c := range t.EmailColumns {
+		if err := set(c, email, ""); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,81 @@
+// Package probe serves the Kubernetes liveness, readiness and startup
+// endpoints and tracks the drain state used during graceful shutdown.
+package probe
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CheckFunc reports whether a dependency needed to serve traffic is healthy.
+type CheckFunc func(ctx context.Context) error
+
+// Probes holds the process lifecycle state behind the probe endpoints.
+type Probes struct {
+	started  atomic.Bool
+	draining atomic.Bool
+	ready    CheckFunc
+	timeout  time.Duration
+}
+
+// New creates Probes whose readiness depends on ready. ready may be nil.
+func New(ready CheckFunc) *Probes {
+	return &Probes{ready: ready, timeout: 2 * time.Second}
+}
+
+// MarkStarted records that startup work (migrations, loaders) has finished.
+func (p *Probes) MarkStarted() { p.started.Store(true) }
+
+// Drain makes readiness fail so the instance is taken out of rotation while
+// in-flight requests finish.
+func (p *Probes) Drain() { p.draining.Store(true) }
+
+// Draining reports whether Drain has been called.
+func (p *Probes) Draining() bool { return p.draining.Load() }
+
+// Live handles GET /livez. It only fails if the process cannot serve HTTP at
+// all, so Kubernetes does not restart a pod that is merely draining.
+func (p *Probes) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Startup handles GET /startupz.
+func (p *Probes) Startup(c *gin.Context) {
+	if !p.started.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "starting"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Ready handles GET /readyz.
+func (p *Probes) Ready(c *gin.Context) {
+	switch {
+	case !p.started.Load():
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "starting"})
+		return
+	case p.draining.Load():
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+		return
+	}
+	if p.ready != nil {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), p.timeout)
+		defer cancel()
+		if err := p.ready(ctx); err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "error": err.Error()})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// RegisterRoutes wires the probe endpoints onto r.
+func RegisterRoutes(r gin.IRoutes, p *Probes) {
+	r.GET("/livez", p.Live)
+	r.GET("/readyz", p.Ready)
+	r.GET("/startupz", p.Startup)
+}
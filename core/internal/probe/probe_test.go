@@ -0,0 +1,45 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(t *testing.T, p *Probes, path string) int {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	RegisterRoutes(r, p)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w.Code
+}
+
+func TestProbes_Lifecycle(t *testing.T) {
+	p := New(func(context.Context) error { return nil })
+
+	assert.Equal(t, http.StatusOK, serve(t, p, "/livez"))
+	assert.Equal(t, http.StatusServiceUnavailable, serve(t, p, "/startupz"))
+	assert.Equal(t, http.StatusServiceUnavailable, serve(t, p, "/readyz"))
+
+	p.MarkStarted()
+	assert.Equal(t, http.StatusOK, serve(t, p, "/startupz"))
+	assert.Equal(t, http.StatusOK, serve(t, p, "/readyz"))
+
+	p.Drain()
+	assert.Equal(t, http.StatusServiceUnavailable, serve(t, p, "/readyz"))
+	assert.Equal(t, http.StatusOK, serve(t, p, "/livez"), "draining must not fail liveness")
+}
+
+func TestProbes_ReadyCheckFails(t *testing.T) {
+	p := New(func(context.Context) error { return errors.New("db down") })
+	p.MarkStarted()
+	assert.Equal(t, http.StatusServiceUnavailable, serve(t, p, "/readyz"))
+	assert.Equal(t, http.StatusOK, serve(t, p, "/livez"))
+}
@@ -0,0 +1,103 @@
+package query_builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StatsQuery builds a single-row aggregate that summarizes numeric columns
+// and their pairwise Pearson correlations in one scan of the source.
+//
+// Output columns are named positionally so arbitrary column names never leak
+// into aliases: "row_count", then "c<i>_count", "c<i>_min", "c<i>_max",
+// "c<i>_mean", "c<i>_stddev", "c<i>_p25", "c<i>_median", "c<i>_p75" for each
+// column i, then "corr_<i>_<j>" for each pair i < j.
+type StatsQuery struct {
+	Schema string
+	Table  string
+	// Query, when set, is analyzed as a subquery instead of Table.
+	Query   string
+	Columns []string
+}
+
+// Supports reports whether the dialect has the aggregates StatsQuery needs.
+// Callers fall back to computing statistics client-side when it does not.
+func (q StatsQuery) Supports(d Dialect) bool {
+	return d.Name == PostgresDialect.Name || d.Name == ClickHouseDialect.Name
+}
+
+// Source renders the FROM target.
+func (q StatsQuery) Source(d Dialect) (string, error) {
+	return d.FromSource(q.Schema, q.Table, q.Query)
+}
+
+// Build renders the aggregate for d.
+func (q StatsQuery) Build(d Dialect) (string, error) {
+	if len(q.Columns) == 0 {
+		return "", fmt.Errorf("at least one column is required")
+	}
+	if !q.Supports(d) {
+		return "", fmt.Errorf("statistics are not supported in SQL for %s datasources", d.Name)
+	}
+	src, err := q.Source(d)
+	if err != nil {
+		return "", err
+	}
+
+	cols := d.quoteAll(q.Columns)
+	exprs := []string{"count(*) AS row_count"}
+	for i, c := range cols {
+		exprs = append(exprs,
+			fmt.Sprintf("count(%s) AS c%d_count", c, i),
+			fmt.Sprintf("min(%s) AS c%d_min", c, i),
+			fmt.Sprintf("max(%s) AS c%d_max", c, i),
+			fmt.Sprintf("avg(%s) AS c%d_mean", c, i),
+			fmt.Sprintf("%s AS c%d_stddev", d.stddev(c), i),
+			fmt.Sprintf("%s AS c%d_p25", d.quantile(c, 0.25), i),
+			fmt.Sprintf("%s AS c%d_median", d.quantile(c, 0.5), i),
+			fmt.Sprintf("%s AS c%d_p75", d.quantile(c, 0.75), i),
+		)
+	}
+	for i := range cols {
+		for j := i + 1; j < len(cols); j++ {
+			exprs = append(exprs, fmt.Sprintf("corr(%s, %s) AS corr_%d_%d", d.float(cols[i]), d.float(cols[j]), i, j))
+		}
+	}
+	return fmt.Sprintf("SELECT %s FROM %s", strings.Join(exprs, ", "), src), nil
+}
+
+// SampleQuery selects the raw column values, capped at limit rows, for
+// dialects where Build is unsupported.
+func (q StatsQuery) SampleQuery(d Dialect, limit int) (string, error) {
+	if len(q.Columns) == 0 {
+		return "", fmt.Errorf("at least one column is required")
+	}
+	cols := make([]Column, len(q.Columns))
+	for i, c := range q.Columns {
+		cols[i] = Column{Name: c}
+	}
+	sql, _, err := Select{Columns: cols, Schema: q.Schema, Table: q.Table, Query: q.Query, Limit: limit}.Build(d)
+	return sql, err
+}
+
+func (d Dialect) stddev(col string) string {
+	if d.Name == ClickHouseDialect.Name {
+		return fmt.Sprintf("stddevSamp(%s)", col)
+	}
+	return fmt.Sprintf("stddev_samp(%s)", col)
+}
+
+func (d Dialect) quantile(col string, p float64) string {
+	if d.Name == ClickHouseDialect.Name {
+		return fmt.Sprintf("quantile(%g)(%s)", p, col)
+	}
+	return fmt.Sprintf("percentile_cont(%g) WITHIN GROUP (ORDER BY %s)", p, col)
+}
+
+// float casts col to double precision where corr requires it.
+func (d Dialect) float(col string) string {
+	if d.Name == ClickHouseDialect.Name {
+		return fmt.Sprintf("toFloat64(%s)", col)
+	}
+	return col + "::float8"
+}
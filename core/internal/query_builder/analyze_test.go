@@ -0,0 +1,49 @@
+package query_builder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ─── StatsQuery ───────────────────────────────────────────────────────────────
+
+func TestStatsQuery_Postgres(t *testing.T) {
+	sql, err := StatsQuery{Schema: "public", Table: "orders", Columns: []string{"amount", "qty"}}.Build(PostgresDialect)
+	require.NoError(t, err)
+	assert.Contains(t, sql, `SELECT count(*) AS row_count, count("amount") AS c0_count`)
+	assert.Contains(t, sql, `stddev_samp("qty") AS c1_stddev`)
+	assert.Contains(t, sql, `percentile_cont(0.5) WITHIN GROUP (ORDER BY "amount") AS c0_median`)
+	assert.Contains(t, sql, `corr("amount"::float8, "qty"::float8) AS corr_0_1`)
+	assert.True(t, strings.HasSuffix(sql, `FROM "public"."orders"`))
+}
+
+func TestStatsQuery_ClickHouseSubquery(t *testing.T) {
+	sql, err := StatsQuery{Query: "SELECT a, b FROM t;", Columns: []string{"a", "b"}}.Build(ClickHouseDialect)
+	require.NoError(t, err)
+	assert.Contains(t, sql, "stddevSamp(`a`) AS c0_stddev")
+	assert.Contains(t, sql, "quantile(0.75)(`b`) AS c1_p75")
+	assert.Contains(t, sql, "corr(toFloat64(`a`), toFloat64(`b`)) AS corr_0_1")
+	assert.Contains(t, sql, "FROM (SELECT a, b FROM t) AS src")
+}
+
+func TestStatsQuery_GenericFallsBackToSample(t *testing.T) {
+	q := StatsQuery{Table: "t", Columns: []string{"x"}}
+	assert.False(t, q.Supports(GenericDialect))
+	_, err := q.Build(GenericDialect)
+	assert.Error(t, err)
+
+	sql, err := q.SampleQuery(GenericDialect, 500)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "x" FROM "t" LIMIT 500`, sql)
+}
+
+func TestStatsQuery_SourceErrors(t *testing.T) {
+	_, err := StatsQuery{Columns: []string{"x"}}.Build(PostgresDialect)
+	assert.ErrorContains(t, err, "table or query is required")
+
+	_, err = StatsQuery{Table: "t", Query: "SELECT 1", Columns: []string{"x"}}.Build(PostgresDialect)
+	assert.ErrorContains(t, err, "not both")
+}
@@ -0,0 +1,181 @@
+package query_builder
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dialect describes the SQL syntax differences the query builder needs to
+// generate statements for a given datasource type.
+type Dialect struct {
+	Name string
+	// IdentQuote is the character used to quote identifiers.
+	IdentQuote byte
+	// Placeholder returns the bind marker for the n-th (1-based) parameter.
+	// Nil means the driver does not accept bind parameters and values are
+	// rendered inline as escaped literals.
+	Placeholder func(n int) string
+	// Param, when set, takes precedence over Placeholder: it returns the
+	// marker for the n-th (1-based) parameter holding v and the argument to
+	// bind for it, for drivers whose markers carry a name and a type.
+	Param func(n int, v any) (string, any, error)
+	// TimeLayout is used when a time.Time value is rendered inline.
+	TimeLayout string
+}
+
+var (
+	// PostgresDialect uses "ident" quoting and $N parameters.
+	PostgresDialect = Dialect{
+		Name:        "postgresql",
+		IdentQuote:  '"',
+		Placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+		TimeLayout:  time.RFC3339Nano,
+	}
+	// ClickHouseDialect uses `ident` quoting and {pN:Type} server-side
+	// parameters, bound as sql.Named("pN", v).
+	ClickHouseDialect = Dialect{
+		Name:       "clickhouse",
+		IdentQuote: '`',
+		Param:      clickHouseParam,
+		TimeLayout: "2006-01-02 15:04:05.999999999",
+	}
+	// GenericDialect is ANSI SQL with ? parameters, used for unknown types.
+	GenericDialect = Dialect{
+		Name:        "generic",
+		IdentQuote:  '"',
+		Placeholder: func(int) string { return "?" },
+		TimeLayout:  time.RFC3339Nano,
+	}
+)
+
+// DialectFor returns the dialect for a datasource type string.
+func DialectFor(dsType string) Dialect {
+	switch dsType {
+	case "postgresql", "cockroachdb":
+		return PostgresDialect
+	case "clickhouse":
+		return ClickHouseDialect
+	}
+	return GenericDialect
+}
+
+// QuoteIdent quotes a single identifier, doubling any embedded quote chars.
+func (d Dialect) QuoteIdent(name string) string {
+	if d.Name == ClickHouseDialect.Name {
+		// Backslash escapes inside ClickHouse quoted identifiers too.
+		name = strings.ReplaceAll(name, `\`, `\\`)
+	}
+	q := string(d.IdentQuote)
+	return q + strings.ReplaceAll(name, q, q+q) + q
+}
+
+// clickHouseParam binds v as the query parameter pN, declared with the
+// ClickHouse type matching its Go type.
+func clickHouseParam(n int, v any) (string, any, error) {
+	var typ string
+	switch v.(type) {
+	case nil:
+		typ = "Nullable(String)"
+	case string:
+		typ = "String"
+	case bool:
+		typ = "Bool"
+	case int, int8, int16, int32, int64:
+		typ = "Int64"
+	case uint, uint8, uint16, uint32, uint64:
+		typ = "UInt64"
+	case float32, float64:
+		typ = "Float64"
+	case time.Time:
+		typ = "DateTime64(9, 'UTC')"
+	default:
+		return "", nil, fmt.Errorf("unsupported parameter type %T", v)
+	}
+	name := "p" + strconv.Itoa(n)
+	return "{" + name + ":" + typ + "}", sql.Named(name, v), nil
+}
+
+// QualifiedTable quotes an optional schema and a table name.
+func (d Dialect) QualifiedTable(schema, table string) string {
+	if schema == "" {
+		return d.QuoteIdent(table)
+	}
+	return d.QuoteIdent(schema) + "." + d.QuoteIdent(table)
+}
+
+// FromSource renders a FROM target: the quoted table, or query wrapped as a
+// subquery when set. Exactly one of table and query must be given.
+func (d Dialect) FromSource(schema, table, query string) (string, error) {
+	if query != "" {
+		if table != "" {
+			return "", fmt.Errorf("set either table or query, not both")
+		}
+		return "(" + strings.TrimRight(strings.TrimSpace(query), ";") + ") AS src", nil
+	}
+	if table == "" {
+		return "", fmt.Errorf("table or query is required")
+	}
+	return d.QualifiedTable(schema, table), nil
+}
+
+// Literal renders v as an escaped SQL literal.
+func (d Dialect) Literal(v any) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "NULL", nil
+	case bool:
+		if val {
+			return "1", nil
+		}
+		return "0", nil
+	case int:
+		return strconv.Itoa(val), nil
+	case int32:
+		return strconv.FormatInt(int64(val), 10), nil
+	case int64:
+		return strconv.FormatInt(val, 10), nil
+	case uint32:
+		return strconv.FormatUint(uint64(val), 10), nil
+	case uint64:
+		return strconv.FormatUint(val, 10), nil
+	case float32:
+		return strconv.FormatFloat(float64(val), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64), nil
+	case time.Time:
+		return d.quoteString(val.UTC().Format(d.TimeLayout)), nil
+	case string:
+		return d.quoteString(val), nil
+	}
+	return "", fmt.Errorf("unsupported literal type %T", v)
+}
+
+func (d Dialect) quoteString(s string) string {
+	if d.Name == ClickHouseDialect.Name {
+		// ClickHouse treats backslash as an escape character inside strings.
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// ilike renders a case-insensitive LIKE. Postgres and ClickHouse have ILIKE
+// with backslash as the default escape; elsewhere both sides are lowered and
+// the escape is spelled out, as ANSI LIKE has none by default.
+func (d Dialect) ilike(col, pattern string) string {
+	switch d.Name {
+	case PostgresDialect.Name, ClickHouseDialect.Name:
+		return col + " ILIKE " + pattern
+	}
+	return "LOWER(" + col + ") LIKE LOWER(" + pattern + `) ESCAPE '\'`
+}
+
+// EscapeLike escapes the LIKE wildcards in s, so it matches literally once
+// embedded in a pattern.
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
@@ -0,0 +1,84 @@
+package query_builder
+
+import (
+	"fmt"
+	"math"
+)
+
+// ColumnFilter is a quick filter from a spreadsheet-style grid: a column, an
+// operator and, for most operators, a value. Filters compile to Conds, so
+// values are bound or escaped like any other.
+type ColumnFilter struct {
+	Column string
+	// Op is one of eq ne lt le gt ge, contains starts_with ends_with (text,
+	// case-insensitive), in (Value is a []any) or is_null not_null (no Value).
+	Op    string
+	Value any
+}
+
+var filterOps = map[string]string{
+	"eq": "=", "ne": "<>", "lt": "<", "le": "<=", "gt": ">", "ge": ">=",
+	"in": "IN", "is_null": "IS NULL", "not_null": "IS NOT NULL",
+}
+
+// Cond compiles the filter.
+func (f ColumnFilter) Cond() (Cond, error) {
+	if f.Column == "" {
+		return Cond{}, fmt.Errorf("filter column is required")
+	}
+	switch f.Op {
+	case "contains", "starts_with", "ends_with":
+		s, ok := f.Value.(string)
+		if !ok {
+			return Cond{}, fmt.Errorf("filter %s on %q needs a text value", f.Op, f.Column)
+		}
+		pattern := EscapeLike(s)
+		if f.Op != "starts_with" {
+			pattern = "%" + pattern
+		}
+		if f.Op != "ends_with" {
+			pattern += "%"
+		}
+		return Cond{Column: f.Column, Op: "ILIKE", Value: pattern}, nil
+	}
+	op, ok := filterOps[f.Op]
+	if !ok {
+		return Cond{}, fmt.Errorf("unsupported filter operator %q", f.Op)
+	}
+	switch op {
+	case "IS NULL", "IS NOT NULL":
+		return Cond{Column: f.Column, Op: op}, nil
+	case "IN":
+		values, ok := f.Value.([]any)
+		if !ok || len(values) == 0 {
+			return Cond{}, fmt.Errorf("filter in on %q needs a non-empty list of values", f.Column)
+		}
+		out := make([]any, len(values))
+		for i, v := range values {
+			if out[i], ok = filterValue(v); !ok {
+				return Cond{}, fmt.Errorf("filter in on %q: unsupported value %v", f.Column, v)
+			}
+		}
+		return Cond{Column: f.Column, Op: op, Value: out}, nil
+	}
+	v, ok := filterValue(f.Value)
+	if !ok || v == nil {
+		return Cond{}, fmt.Errorf("filter %s on %q needs a text, number or boolean value", f.Op, f.Column)
+	}
+	return Cond{Column: f.Column, Op: op, Value: v}, nil
+}
+
+// filterValue accepts the scalars JSON decodes to. Integral numbers become
+// int64 so they bind against integer columns without a cast.
+func filterValue(v any) (any, bool) {
+	switch val := v.(type) {
+	case nil, string, bool, int64:
+		return val, true
+	case float64:
+		if val == math.Trunc(val) && math.Abs(val) < 1<<53 {
+			return int64(val), true
+		}
+		return val, true
+	}
+	return nil, false
+}
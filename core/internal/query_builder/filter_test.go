@@ -0,0 +1,68 @@
+package query_builder
+
+import (
+	dbsql "database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ─── ColumnFilter ─────────────────────────────────────────────────────────────
+
+func TestColumnFilter_Postgres(t *testing.T) {
+	sql, args, err := Select{Table: "users", Where: conds(t,
+		ColumnFilter{Column: "name", Op: "contains", Value: "50%_off"},
+		ColumnFilter{Column: "email", Op: "ends_with", Value: "@acme.io"},
+		ColumnFilter{Column: "age", Op: "ge", Value: float64(18)},
+		ColumnFilter{Column: "team", Op: "in", Value: []any{"a", "b"}},
+		ColumnFilter{Column: "deleted_at", Op: "is_null"},
+	)}.Build(PostgresDialect)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "users" WHERE "name" ILIKE $1 AND "email" ILIKE $2 AND "age" >= $3 AND "team" IN ($4, $5) AND "deleted_at" IS NULL`, sql)
+	assert.Equal(t, []any{`%50\%\_off%`, "%@acme.io", int64(18), "a", "b"}, args)
+}
+
+func TestColumnFilter_ClickHouseBinds(t *testing.T) {
+	sql, args, err := Select{Table: "logs", Where: conds(t,
+		ColumnFilter{Column: "path", Op: "starts_with", Value: `C:\it's`},
+		ColumnFilter{Column: "level", Op: "in", Value: []any{float64(1), 2.5}},
+		ColumnFilter{Column: "host", Op: "not_null"},
+	)}.Build(ClickHouseDialect)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `logs` WHERE `path` ILIKE {p1:String} AND `level` IN ({p2:Int64}, {p3:Float64}) AND `host` IS NOT NULL", sql)
+	assert.Equal(t, []any{dbsql.Named("p1", `C:\\it's%`), dbsql.Named("p2", int64(1)), dbsql.Named("p3", 2.5)}, args)
+}
+
+func TestColumnFilter_GenericLowersAndEscapes(t *testing.T) {
+	sql, args, err := Select{Table: "t", Where: conds(t, ColumnFilter{Column: "name", Op: "contains", Value: "Ann"})}.Build(GenericDialect)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "t" WHERE LOWER("name") LIKE LOWER(?) ESCAPE '\'`, sql)
+	assert.Equal(t, []any{"%Ann%"}, args)
+}
+
+func TestColumnFilter_Rejects(t *testing.T) {
+	for name, f := range map[string]ColumnFilter{
+		"no column":        {Op: "eq", Value: 1},
+		"unknown operator": {Column: "a", Op: "like", Value: "x"},
+		"contains number":  {Column: "a", Op: "contains", Value: float64(1)},
+		"empty in":         {Column: "a", Op: "in", Value: []any{}},
+		"in scalar":        {Column: "a", Op: "in", Value: "x"},
+		"eq without value": {Column: "a", Op: "eq"},
+		"eq object":        {Column: "a", Op: "eq", Value: map[string]any{}},
+	} {
+		_, err := f.Cond()
+		assert.Error(t, err, name)
+	}
+}
+
+func conds(t *testing.T, filters ...ColumnFilter) []Cond {
+	t.Helper()
+	out := make([]Cond, len(filters))
+	for i, f := range filters {
+		var err error
+		out[i], err = f.Cond()
+		require.NoError(t, err)
+	}
+	return out
+}
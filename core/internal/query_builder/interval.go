@@ -0,0 +1,96 @@
+package query_builder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxDataPoints is the point budget AutoInterval uses when the caller
+// does not supply one — roughly one point per pixel of a wide chart.
+const DefaultMaxDataPoints = 1000
+
+// niceIntervals are the bucket widths AutoInterval chooses from, so charts
+// line up on familiar boundaries instead of e.g. 437-second buckets.
+var niceIntervals = []time.Duration{
+	time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	15 * time.Second,
+	30 * time.Second,
+	time.Minute,
+	5 * time.Minute,
+	10 * time.Minute,
+	15 * time.Minute,
+	30 * time.Minute,
+	time.Hour,
+	3 * time.Hour,
+	6 * time.Hour,
+	12 * time.Hour,
+	24 * time.Hour,
+	7 * 24 * time.Hour,
+	30 * 24 * time.Hour,
+}
+
+// AutoInterval returns the smallest nice bucket width that keeps a series over
+// tr within maxPoints points. Ranges wider than the largest nice interval
+// allows are bucketed in whole days.
+func AutoInterval(tr TimeRange, maxPoints int) time.Duration {
+	if maxPoints <= 0 {
+		maxPoints = DefaultMaxDataPoints
+	}
+	span := tr.To.Sub(tr.From)
+	if span <= 0 {
+		return niceIntervals[0]
+	}
+	raw := span / time.Duration(maxPoints)
+	for _, iv := range niceIntervals {
+		if iv >= raw {
+			return iv
+		}
+	}
+	day := 24 * time.Hour
+	return (raw + day - 1) / day * day
+}
+
+// intervalUnits maps interval suffixes to their length, largest first so
+// FormatInterval picks the coarsest exact unit.
+var intervalUnits = []struct {
+	suffix string
+	unit   time.Duration
+}{
+	{"w", 7 * 24 * time.Hour},
+	{"d", 24 * time.Hour},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+}
+
+// ParseInterval parses a bucket width such as "30s", "5m", "1h", "1d" or "2w".
+// Intervals must be a whole number of seconds of at least one second.
+func ParseInterval(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for _, u := range intervalUnits {
+		num, ok := strings.CutSuffix(s, u.suffix)
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(num, 10, 64)
+		if err != nil || n <= 0 {
+			break
+		}
+		return time.Duration(n) * u.unit, nil
+	}
+	return 0, fmt.Errorf("invalid interval %q: use a positive count of s, m, h, d or w", s)
+}
+
+// FormatInterval renders d in the form ParseInterval accepts, e.g. "5m".
+func FormatInterval(d time.Duration) string {
+	for _, u := range intervalUnits {
+		if d >= u.unit && d%u.unit == 0 {
+			return strconv.FormatInt(int64(d/u.unit), 10) + u.suffix
+		}
+	}
+	return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+}
@@ -0,0 +1,183 @@
+package query_builder
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// KeysetPage describes one page of a keyset (seek) paginated table scan.
+// Rows are ordered by the sort column, then by the key column, which must be
+// unique (typically the primary key) so that rows sharing a sort value are
+// neither skipped nor repeated across page boundaries. NULL sort values
+// order as the largest value: last ascending, first descending.
+type KeysetPage struct {
+	Schema     string
+	Table      string
+	SortColumn string
+	// KeyColumn breaks ties between rows sharing a sort value. Empty means
+	// SortColumn is unique itself.
+	KeyColumn string
+	Desc      bool
+	// After is the position of the last row of the previous page; nil for
+	// the first page.
+	After *Cursor
+	Limit int
+	// Filters narrow the scan; they must be the same for every page.
+	Filters []ColumnFilter
+}
+
+// Cursor is a row's position in a keyset scan: its sort value, which may be
+// nil for NULL, and its key when the page has a KeyColumn.
+type Cursor struct {
+	Sort any
+	Key  any
+}
+
+// Build renders the SELECT for the page. One extra row is requested so the
+// caller can tell whether a next page exists.
+func (p KeysetPage) Build(d Dialect) (string, []any, error) {
+	if p.Table == "" {
+		return "", nil, fmt.Errorf("table is required")
+	}
+	if p.SortColumn == "" {
+		return "", nil, fmt.Errorf("sort column is required")
+	}
+	if p.Limit <= 0 {
+		return "", nil, fmt.Errorf("limit must be positive")
+	}
+
+	nulls := "LAST"
+	if p.Desc {
+		nulls = "FIRST"
+	}
+	sel := Select{
+		Schema:  p.Schema,
+		Table:   p.Table,
+		OrderBy: []Order{{Column: p.SortColumn, Desc: p.Desc, Nulls: nulls}},
+		Limit:   p.Limit + 1,
+	}
+	if p.hasKey() {
+		sel.OrderBy = append(sel.OrderBy, Order{Column: p.KeyColumn, Desc: p.Desc})
+	}
+	for _, f := range p.Filters {
+		cond, err := f.Cond()
+		if err != nil {
+			return "", nil, err
+		}
+		sel.Where = append(sel.Where, cond)
+	}
+	if p.After != nil {
+		seek, err := p.seek(*p.After)
+		if err != nil {
+			return "", nil, err
+		}
+		sel.Where = append(sel.Where, seek)
+	}
+	return sel.Build(d)
+}
+
+func (p KeysetPage) hasKey() bool {
+	return p.KeyColumn != "" && p.KeyColumn != p.SortColumn
+}
+
+// seek matches the rows after c in the page's order.
+func (p KeysetPage) seek(c Cursor) (Cond, error) {
+	op := ">"
+	if p.Desc {
+		op = "<"
+	}
+	sortCol, isNull, notNull := p.SortColumn, Cond{Column: p.SortColumn, Op: "IS NULL"}, Cond{Column: p.SortColumn, Op: "IS NOT NULL"}
+	if !p.hasKey() {
+		if c.Sort == nil {
+			return Cond{}, fmt.Errorf("cannot page past NULL values of %q without a key column", sortCol)
+		}
+		past := Cond{Column: sortCol, Op: op, Value: c.Sort}
+		if p.Desc {
+			return past, nil
+		}
+		return Cond{Op: "OR", Conds: []Cond{past, isNull}}, nil
+	}
+	if c.Key == nil {
+		return Cond{}, fmt.Errorf("invalid cursor: no key")
+	}
+	tie := Cond{Column: p.KeyColumn, Op: op, Value: c.Key}
+	if c.Sort == nil {
+		// NULLs come last ascending, so only NULLs remain; descending they
+		// come first and every non-NULL row is still ahead.
+		nulls := Cond{Op: "AND", Conds: []Cond{isNull, tie}}
+		if p.Desc {
+			return Cond{Op: "OR", Conds: []Cond{nulls, notNull}}, nil
+		}
+		return nulls, nil
+	}
+	seek := Cond{Op: "OR", Conds: []Cond{
+		{Column: sortCol, Op: op, Value: c.Sort},
+		{Op: "AND", Conds: []Cond{{Column: sortCol, Op: "=", Value: c.Sort}, tie}},
+	}}
+	if !p.Desc {
+		seek.Conds = append(seek.Conds, isNull)
+	}
+	return seek, nil
+}
+
+// EncodeCursor serializes a cursor into an opaque, URL-safe string: the
+// JSON array [sort, key].
+func EncodeCursor(c Cursor) (string, error) {
+	b, err := json.Marshal([]any{c.Sort, c.Key})
+	if err != nil {
+		return "", fmt.Errorf("encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor reverses EncodeCursor. A bare JSON value, as written before
+// cursors carried a key, decodes as the sort value alone. JSON numbers are
+// decoded as int64 when integral so they bind against integer keys without
+// a cast.
+func DecodeCursor(cursor string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	dec := json.NewDecoder(strings.NewReader(string(b)))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	pair, ok := v.([]any)
+	if !ok {
+		sort, err := cursorValue(v)
+		if err != nil {
+			return nil, err
+		}
+		return &Cursor{Sort: sort}, nil
+	}
+	if len(pair) != 2 {
+		return nil, fmt.Errorf("invalid cursor: want [sort, key], got %d values", len(pair))
+	}
+	var c Cursor
+	if c.Sort, err = cursorValue(pair[0]); err != nil {
+		return nil, err
+	}
+	if c.Key, err = cursorValue(pair[1]); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func cursorValue(v any) (any, error) {
+	if n, ok := v.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return i, nil
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %w", err)
+		}
+		return f, nil
+	}
+	return v, nil
+}
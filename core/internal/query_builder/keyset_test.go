@@ -0,0 +1,120 @@
+package query_builder
+
+import (
+	dbsql "database/sql"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ─── KeysetPage ───────────────────────────────────────────────────────────────
+
+func TestKeysetPage_FirstPage(t *testing.T) {
+	sql, args, err := KeysetPage{Schema: "public", Table: "events", SortColumn: "id", Limit: 50}.Build(PostgresDialect)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "public"."events" ORDER BY "id" ASC NULLS LAST LIMIT 51`, sql)
+	assert.Empty(t, args)
+}
+
+func TestKeysetPage_PostgresBindsCursor(t *testing.T) {
+	sql, args, err := KeysetPage{Table: "events", SortColumn: "id", Desc: true, After: &Cursor{Sort: int64(42)}, Limit: 10}.Build(PostgresDialect)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "events" WHERE "id" < $1 ORDER BY "id" DESC NULLS FIRST LIMIT 11`, sql)
+	assert.Equal(t, []any{int64(42)}, args)
+}
+
+func TestKeysetPage_ClickHouseBindsCursor(t *testing.T) {
+	sql, args, err := KeysetPage{Schema: "db", Table: "logs", SortColumn: "msg", After: &Cursor{Sort: `it's\`}, Limit: 10}.Build(ClickHouseDialect)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `db`.`logs` WHERE (`msg` > {p1:String} OR `msg` IS NULL) ORDER BY `msg` ASC NULLS LAST LIMIT 11", sql)
+	assert.Equal(t, []any{dbsql.Named("p1", `it's\`)}, args)
+}
+
+func TestKeysetPage_FiltersBeforeCursor(t *testing.T) {
+	sql, args, err := KeysetPage{
+		Table: "events", SortColumn: "id", After: &Cursor{Sort: int64(7)}, Limit: 10,
+		Filters: []ColumnFilter{{Column: "kind", Op: "eq", Value: "click"}},
+	}.Build(PostgresDialect)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "events" WHERE "kind" = $1 AND ("id" > $2 OR "id" IS NULL) ORDER BY "id" ASC NULLS LAST LIMIT 11`, sql)
+	assert.Equal(t, []any{"click", int64(7)}, args)
+
+	_, _, err = KeysetPage{Table: "events", SortColumn: "id", Limit: 10,
+		Filters: []ColumnFilter{{Column: "kind", Op: "regex", Value: "."}}}.Build(PostgresDialect)
+	assert.Error(t, err)
+}
+
+func TestKeysetPage_QuotesHostileIdentifiers(t *testing.T) {
+	sql, _, err := KeysetPage{Table: `x"; DROP TABLE y; --`, SortColumn: "id", Limit: 1}.Build(PostgresDialect)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "x""; DROP TABLE y; --" ORDER BY "id" ASC NULLS LAST LIMIT 2`, sql)
+}
+
+func TestKeysetPage_RequiresSortColumn(t *testing.T) {
+	_, _, err := KeysetPage{Table: "t", Limit: 1}.Build(GenericDialect)
+	assert.Error(t, err)
+}
+
+func TestKeysetPage_TieBreaksOnKey(t *testing.T) {
+	page := KeysetPage{Table: "tasks", SortColumn: "status", KeyColumn: "id", After: &Cursor{Sort: "open", Key: int64(7)}, Limit: 10}
+	sql, args, err := page.Build(PostgresDialect)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "tasks" WHERE ("status" > $1 OR ("status" = $2 AND "id" > $3) OR "status" IS NULL) ORDER BY "status" ASC NULLS LAST, "id" ASC LIMIT 11`, sql)
+	assert.Equal(t, []any{"open", "open", int64(7)}, args)
+
+	page.Desc = true
+	sql, _, err = page.Build(PostgresDialect)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "tasks" WHERE ("status" < $1 OR ("status" = $2 AND "id" < $3)) ORDER BY "status" DESC NULLS FIRST, "id" DESC LIMIT 11`, sql)
+}
+
+func TestKeysetPage_SeeksPastNull(t *testing.T) {
+	page := KeysetPage{Table: "tasks", SortColumn: "due", KeyColumn: "id", After: &Cursor{Key: int64(7)}, Limit: 10}
+	sql, args, err := page.Build(PostgresDialect)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "tasks" WHERE ("due" IS NULL AND "id" > $1) ORDER BY "due" ASC NULLS LAST, "id" ASC LIMIT 11`, sql)
+	assert.Equal(t, []any{int64(7)}, args)
+
+	page.Desc = true
+	sql, _, err = page.Build(PostgresDialect)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "tasks" WHERE (("due" IS NULL AND "id" < $1) OR "due" IS NOT NULL) ORDER BY "due" DESC NULLS FIRST, "id" DESC LIMIT 11`, sql)
+
+	page.KeyColumn = ""
+	_, _, err = page.Build(PostgresDialect)
+	assert.ErrorContains(t, err, "without a key column")
+}
+
+func TestKeysetPage_GenericOrdersNullsExplicitly(t *testing.T) {
+	sql, _, err := KeysetPage{Table: "t", SortColumn: "due", KeyColumn: "id", Desc: true, Limit: 1}.Build(GenericDialect)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "t" ORDER BY CASE WHEN "due" IS NULL THEN 0 ELSE 1 END, "due" DESC, "id" DESC LIMIT 2`, sql)
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	for _, c := range []Cursor{
+		{Sort: int64(9007199254740993)},
+		{Sort: 1.5, Key: "a"},
+		{Sort: "2024-01-01 00:00:00", Key: int64(3)},
+		{Key: int64(4)},
+	} {
+		s, err := EncodeCursor(c)
+		require.NoError(t, err)
+		got, err := DecodeCursor(s)
+		require.NoError(t, err)
+		assert.Equal(t, c, *got)
+	}
+
+	legacy, err := DecodeCursor(base64.RawURLEncoding.EncodeToString([]byte("42")))
+	require.NoError(t, err)
+	assert.Equal(t, Cursor{Sort: int64(42)}, *legacy)
+}
+
+func TestCursor_Invalid(t *testing.T) {
+	_, err := DecodeCursor("!!not-base64")
+	assert.Error(t, err)
+	_, err = DecodeCursor(base64.RawURLEncoding.EncodeToString([]byte("[1,2,3]")))
+	assert.Error(t, err)
+}
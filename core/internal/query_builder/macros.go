@@ -0,0 +1,234 @@
+package query_builder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// macroPrefix introduces a time-range macro, e.g. $__timeFilter(ts).
+const macroPrefix = "$__"
+
+// ExpandMacros replaces the time-range macros in query with SQL for dialect d,
+// so the same statement works across PostgreSQL (and TimescaleDB), ClickHouse
+// and generic SQL backends:
+//
+//	$__timeFilter(col)       col BETWEEN <from> AND <to>
+//	$__timeFrom, $__timeTo   the range bounds as timestamps
+//	$__unixEpochFilter(col)  col BETWEEN <from seconds> AND <to seconds>
+//	$__unixEpochFrom/To      the range bounds as Unix seconds
+//	$__timeGroup(col[, iv])  col truncated to buckets of iv, e.g. 5m
+//
+// Without an explicit interval $__timeGroup buckets by AutoInterval(tr,
+// maxPoints); the interval used is returned so callers can report it, and is
+// zero when the query does not group by time.
+//
+// The trailing "()" is optional on argument-less macros. Unknown $__ names are
+// left untouched. A macro needing a bound the range does not have is an error.
+func ExpandMacros(query string, d Dialect, tr TimeRange, maxPoints int) (string, time.Duration, error) {
+	if !strings.Contains(query, macroPrefix) {
+		return query, 0, nil
+	}
+	m := macroExpander{d: d, tr: tr, maxPoints: maxPoints}
+
+	var sb strings.Builder
+	rest := query
+	for {
+		i := strings.Index(rest, macroPrefix)
+		if i < 0 {
+			sb.WriteString(rest)
+			break
+		}
+		sb.WriteString(rest[:i])
+		rest = rest[i:]
+
+		name := macroName(rest[len(macroPrefix):])
+		if !timeMacros[name] {
+			sb.WriteString(macroPrefix)
+			rest = rest[len(macroPrefix):]
+			continue
+		}
+		after := rest[len(macroPrefix)+len(name):]
+		arg, hasArgs, n, err := macroArgs(after)
+		if err != nil {
+			return "", 0, fmt.Errorf("macro $__%s: %w", name, err)
+		}
+		expanded, err := m.expand(name, arg, hasArgs)
+		if err != nil {
+			return "", 0, fmt.Errorf("macro $__%s: %w", name, err)
+		}
+		sb.WriteString(expanded)
+		rest = after[n:]
+	}
+	return sb.String(), m.interval, nil
+}
+
+// timeMacros lists the macro names ExpandMacros understands.
+var timeMacros = map[string]bool{
+	"timeFilter": true, "timeFrom": true, "timeTo": true,
+	"unixEpochFilter": true, "unixEpochFrom": true, "unixEpochTo": true,
+	"timeGroup": true,
+}
+
+// macroExpander carries the inputs of one ExpandMacros call and records the
+// $__timeGroup interval it used.
+type macroExpander struct {
+	d         Dialect
+	tr        TimeRange
+	maxPoints int
+	interval  time.Duration
+}
+
+// expand renders a single macro named in timeMacros.
+func (m *macroExpander) expand(name, arg string, hasArgs bool) (string, error) {
+	d, tr := m.d, m.tr
+	switch name {
+	case "timeGroup":
+		return m.timeGroup(arg)
+	case "timeFilter", "unixEpochFilter":
+		if !hasArgs || arg == "" {
+			return "", fmt.Errorf("a column argument is required")
+		}
+		if tr.From.IsZero() || tr.To.IsZero() {
+			return "", fmt.Errorf("a time range with from and to is required")
+		}
+		if name == "timeFilter" {
+			return fmt.Sprintf("%s BETWEEN %s AND %s", arg, d.timeValue(tr.From), d.timeValue(tr.To)), nil
+		}
+		return fmt.Sprintf("%s BETWEEN %d AND %d", arg, tr.From.Unix(), tr.To.Unix()), nil
+	case "timeFrom", "timeTo", "unixEpochFrom", "unixEpochTo":
+		if hasArgs && arg != "" {
+			return "", fmt.Errorf("takes no arguments")
+		}
+		t, bound := tr.From, "from"
+		if strings.HasSuffix(name, "To") {
+			t, bound = tr.To, "to"
+		}
+		if t.IsZero() {
+			return "", fmt.Errorf("the time range has no %s bound", bound)
+		}
+		if strings.HasPrefix(name, "unixEpoch") {
+			return strconv.FormatInt(t.Unix(), 10), nil
+		}
+		return d.timeValue(t), nil
+	}
+	return "", fmt.Errorf("unknown macro")
+}
+
+// timeGroup renders $__timeGroup(col[, interval]).
+func (m *macroExpander) timeGroup(arg string) (string, error) {
+	args := splitArgs(arg)
+	if len(args) == 0 || args[0] == "" || len(args) > 2 {
+		return "", fmt.Errorf("expects a column and an optional interval")
+	}
+	var iv time.Duration
+	if len(args) == 2 {
+		var err error
+		if iv, err = ParseInterval(strings.Trim(args[1], `'"`)); err != nil {
+			return "", err
+		}
+	} else {
+		if m.tr.From.IsZero() || m.tr.To.IsZero() {
+			return "", fmt.Errorf("a time range with from and to is required to pick an interval")
+		}
+		iv = AutoInterval(m.tr, m.maxPoints)
+	}
+	expr, err := m.d.timeBucket(args[0], iv)
+	if err != nil {
+		return "", err
+	}
+	m.interval = iv
+	return expr, nil
+}
+
+// timeBucket truncates the time expression col to multiples of iv.
+func (d Dialect) timeBucket(col string, iv time.Duration) (string, error) {
+	secs := int64(iv / time.Second)
+	switch d.Name {
+	case PostgresDialect.Name:
+		// Plain epoch arithmetic rather than date_bin or time_bucket so it
+		// runs on any PostgreSQL version, with or without TimescaleDB.
+		return fmt.Sprintf("to_timestamp(floor(extract(epoch from %s) / %d) * %d)", col, secs, secs), nil
+	case ClickHouseDialect.Name:
+		return fmt.Sprintf("toStartOfInterval(%s, INTERVAL %d second)", col, secs), nil
+	}
+	return "", fmt.Errorf("time grouping is not supported for %s datasources", d.Name)
+}
+
+// timeValue renders t as a timestamp expression comparable with the
+// dialect's native time columns.
+func (d Dialect) timeValue(t time.Time) string {
+	if d.Name == ClickHouseDialect.Name {
+		// toDateTime compares correctly against DateTime and DateTime64
+		// columns regardless of the server's time zone.
+		return fmt.Sprintf("toDateTime(%d)", t.Unix())
+	}
+	return d.quoteString(t.UTC().Format(d.TimeLayout))
+}
+
+// macroName returns the leading identifier of s.
+func macroName(s string) string {
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		if c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') {
+			i++
+			continue
+		}
+		break
+	}
+	return s[:i]
+}
+
+// macroArgs parses an optional parenthesised argument list at the start of s,
+// allowing nested parentheses such as $__timeFilter(toDateTime(ts)). It
+// returns the trimmed argument text and how many bytes of s it consumed.
+func macroArgs(s string) (arg string, hasArgs bool, n int, err error) {
+	if !strings.HasPrefix(s, "(") {
+		return "", false, 0, nil
+	}
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return strings.TrimSpace(s[1:i]), true, i + 1, nil
+			}
+		}
+	}
+	return "", true, 0, fmt.Errorf("unclosed parenthesis")
+}
+
+// splitArgs splits a macro argument list on top-level commas, ignoring
+// commas inside parentheses and quoted strings.
+func splitArgs(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	var args []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			args = append(args, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(args, strings.TrimSpace(s[start:]))
+}
@@ -0,0 +1,137 @@
+package query_builder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ─── ExpandMacros ─────────────────────────────────────────────────────────────
+
+var macroRange = TimeRange{
+	From: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+	To:   time.Date(2024, 4, 1, 1, 0, 0, 0, time.UTC),
+}
+
+func TestExpandMacros_TimeFilterPerDialect(t *testing.T) {
+	const q = "SELECT * FROM events WHERE $__timeFilter(ts) AND kind = 'x'"
+
+	pg, _, err := ExpandMacros(q, PostgresDialect, macroRange, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM events WHERE ts BETWEEN '2024-04-01T00:00:00Z' AND '2024-04-01T01:00:00Z' AND kind = 'x'", pg)
+
+	ch, _, err := ExpandMacros(q, ClickHouseDialect, macroRange, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM events WHERE ts BETWEEN toDateTime(1711929600) AND toDateTime(1711933200) AND kind = 'x'", ch)
+}
+
+func TestExpandMacros_BoundsAndEpoch(t *testing.T) {
+	got, _, err := ExpandMacros("$__timeFrom() $__timeTo $__unixEpochFrom $__unixEpochTo() $__unixEpochFilter(created)", PostgresDialect, macroRange, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "'2024-04-01T00:00:00Z' '2024-04-01T01:00:00Z' 1711929600 1711933200 created BETWEEN 1711929600 AND 1711933200", got)
+}
+
+func TestExpandMacros_NestedArgument(t *testing.T) {
+	got, _, err := ExpandMacros("WHERE $__timeFilter(toDateTime(ts, 'UTC'))", ClickHouseDialect, macroRange, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "WHERE toDateTime(ts, 'UTC') BETWEEN toDateTime(1711929600) AND toDateTime(1711933200)", got)
+}
+
+func TestExpandMacros_UnknownLeftAlone(t *testing.T) {
+	got, _, err := ExpandMacros("SELECT '$__other(x)', $__timeFrom", GenericDialect, macroRange, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT '$__other(x)', '2024-04-01T00:00:00Z'", got)
+}
+
+func TestExpandMacros_Errors(t *testing.T) {
+	_, _, err := ExpandMacros("WHERE $__timeFilter(ts)", PostgresDialect, TimeRange{}, 0)
+	assert.ErrorContains(t, err, "time range")
+
+	_, _, err = ExpandMacros("WHERE $__timeFilter()", PostgresDialect, macroRange, 0)
+	assert.ErrorContains(t, err, "column argument")
+
+	_, _, err = ExpandMacros("WHERE $__timeFilter(ts", PostgresDialect, macroRange, 0)
+	assert.ErrorContains(t, err, "unclosed")
+
+	_, _, err = ExpandMacros("$__timeTo", PostgresDialect, TimeRange{From: macroRange.From}, 0)
+	assert.ErrorContains(t, err, "no to bound")
+}
+
+func TestExpandMacros_TimeGroupAuto(t *testing.T) {
+	const q = "SELECT $__timeGroup(ts) AS t, count(*) FROM events WHERE $__timeFilter(ts) GROUP BY 1"
+
+	pg, iv, err := ExpandMacros(q, PostgresDialect, macroRange, 100)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, iv, "1h over 100 points rounds 36s up to 1m")
+	assert.Contains(t, pg, "SELECT to_timestamp(floor(extract(epoch from ts) / 60) * 60) AS t")
+
+	ch, iv, err := ExpandMacros(q, ClickHouseDialect, macroRange, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, iv, "default budget of 1000 points")
+	assert.Contains(t, ch, "SELECT toStartOfInterval(ts, INTERVAL 5 second) AS t")
+}
+
+func TestExpandMacros_TimeGroupExplicit(t *testing.T) {
+	got, iv, err := ExpandMacros("$__timeGroup(toDateTime(ts, 'UTC'), '1h')", ClickHouseDialect, TimeRange{}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, iv)
+	assert.Equal(t, "toStartOfInterval(toDateTime(ts, 'UTC'), INTERVAL 3600 second)", got)
+}
+
+func TestExpandMacros_TimeGroupErrors(t *testing.T) {
+	_, _, err := ExpandMacros("$__timeGroup(ts)", PostgresDialect, TimeRange{}, 0)
+	assert.ErrorContains(t, err, "time range")
+
+	_, _, err = ExpandMacros("$__timeGroup(ts, 5x)", PostgresDialect, macroRange, 0)
+	assert.ErrorContains(t, err, "invalid interval")
+
+	_, _, err = ExpandMacros("$__timeGroup(ts)", GenericDialect, macroRange, 0)
+	assert.ErrorContains(t, err, "not supported")
+}
+
+func TestExpandMacros_NoTimeGroupReportsNoInterval(t *testing.T) {
+	_, iv, err := ExpandMacros("SELECT 1 WHERE $__timeFilter(ts)", PostgresDialect, macroRange, 0)
+	require.NoError(t, err)
+	assert.Zero(t, iv)
+}
+
+// ─── Intervals ────────────────────────────────────────────────────────────────
+
+func TestAutoInterval(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		span   time.Duration
+		points int
+		want   time.Duration
+	}{
+		{time.Minute, 1000, time.Second},
+		{24 * time.Hour, 1000, 5 * time.Minute},
+		{24 * time.Hour, 24, time.Hour},
+		{365 * 24 * time.Hour, 100, 7 * 24 * time.Hour},
+		{10 * 365 * 24 * time.Hour, 10, 365 * 24 * time.Hour},
+	}
+	for _, c := range cases {
+		got := AutoInterval(TimeRange{From: from, To: from.Add(c.span)}, c.points)
+		assert.Equal(t, c.want, got, "span %s / %d points", c.span, c.points)
+	}
+}
+
+func TestParseAndFormatInterval(t *testing.T) {
+	for s, want := range map[string]time.Duration{
+		"30s": 30 * time.Second, "5m": 5 * time.Minute, "1h": time.Hour,
+		"1d": 24 * time.Hour, "2w": 14 * 24 * time.Hour,
+	} {
+		got, err := ParseInterval(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, got)
+		assert.Equal(t, s, FormatInterval(got))
+	}
+	assert.Equal(t, "90s", FormatInterval(90*time.Second))
+
+	for _, bad := range []string{"", "5", "0m", "-1h", "1.5h", "m"} {
+		_, err := ParseInterval(bad)
+		assert.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,246 @@
+package query_builder
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Select is a SELECT assembled from parts, so callers never splice
+// identifiers or values into SQL by hand: names are quoted for the dialect
+// and values are bound as parameters, or rendered as escaped literals where
+// the dialect has no placeholders.
+type Select struct {
+	// Columns is the select list; empty selects *.
+	Columns []Column
+	// Schema and Table name the source; Query, when set instead, is read
+	// as a subquery. See Dialect.FromSource.
+	Schema string
+	Table  string
+	Query  string
+	// Where conditions are ANDed together.
+	Where []Cond
+	// Filter is an additional predicate in SQL, ANDed with Where. It is
+	// included verbatim, so it must come from the user's own query text,
+	// never from values a caller assembled.
+	Filter  string
+	GroupBy []string
+	OrderBy []Order
+	// Limit caps the rows returned; 0 means no LIMIT.
+	Limit int
+}
+
+// Column is one entry of a select list: a column, optionally aggregated.
+type Column struct {
+	// Name is the column read; empty reads * (only with Func).
+	Name string
+	// Func is an aggregate applied to the column, e.g. count or sum.
+	Func string
+	// Distinct aggregates distinct values only.
+	Distinct bool
+	// As aliases the entry.
+	As string
+}
+
+// Cond compares a column with a value.
+type Cond struct {
+	Column string
+	// Op is one of = <> < <= > >=, IN (Value is a non-empty []any), IS NULL
+	// and IS NOT NULL (Value is ignored), or ILIKE, matching a LIKE pattern
+	// case-insensitively. See EscapeLike. AND and OR instead group Conds,
+	// and Column and Value are ignored.
+	Op    string
+	Value any
+	Conds []Cond
+}
+
+// Order sorts by a column.
+type Order struct {
+	Column string
+	Desc   bool
+	// Nulls is FIRST or LAST; empty leaves NULLs where the database puts
+	// them.
+	Nulls string
+}
+
+var (
+	funcNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	condOps    = map[string]bool{"=": true, "<>": true, "<": true, "<=": true, ">": true, ">=": true, "ILIKE": true}
+)
+
+// Build renders the statement for d, returning the arguments to bind
+// alongside it.
+func (s Select) Build(d Dialect) (string, []any, error) {
+	src, err := d.FromSource(s.Schema, s.Table, s.Query)
+	if err != nil {
+		return "", nil, err
+	}
+	cols := []string{"*"}
+	if len(s.Columns) > 0 {
+		cols = make([]string, len(s.Columns))
+		for i, c := range s.Columns {
+			if cols[i], err = c.render(d); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+
+	var sb strings.Builder
+	var args []any
+	fmt.Fprintf(&sb, "SELECT %s FROM %s", strings.Join(cols, ", "), src)
+
+	var preds []string
+	for _, c := range s.Where {
+		pred, err := c.render(d, &args)
+		if err != nil {
+			return "", nil, err
+		}
+		preds = append(preds, pred)
+	}
+	if s.Filter != "" {
+		if len(preds) > 0 {
+			preds = append(preds, "("+s.Filter+")")
+		} else {
+			preds = append(preds, s.Filter)
+		}
+	}
+	if len(preds) > 0 {
+		fmt.Fprintf(&sb, " WHERE %s", strings.Join(preds, " AND "))
+	}
+	if len(s.GroupBy) > 0 {
+		fmt.Fprintf(&sb, " GROUP BY %s", strings.Join(d.quoteAll(s.GroupBy), ", "))
+	}
+	if len(s.OrderBy) > 0 {
+		orders := make([]string, len(s.OrderBy))
+		for i, o := range s.OrderBy {
+			dir := "ASC"
+			if o.Desc {
+				dir = "DESC"
+			}
+			if orders[i], err = o.render(d, dir); err != nil {
+				return "", nil, err
+			}
+		}
+		fmt.Fprintf(&sb, " ORDER BY %s", strings.Join(orders, ", "))
+	}
+	if s.Limit < 0 {
+		return "", nil, fmt.Errorf("limit must not be negative")
+	}
+	if s.Limit > 0 {
+		sb.WriteString(" LIMIT " + strconv.Itoa(s.Limit))
+	}
+	return sb.String(), args, nil
+}
+
+func (c Cond) render(d Dialect, args *[]any) (string, error) {
+	col := d.QuoteIdent(c.Column)
+	bind := func(v any) (string, error) {
+		if d.Param != nil {
+			marker, arg, err := d.Param(len(*args)+1, v)
+			if err != nil {
+				return "", err
+			}
+			*args = append(*args, arg)
+			return marker, nil
+		}
+		if d.Placeholder != nil {
+			*args = append(*args, v)
+			return d.Placeholder(len(*args)), nil
+		}
+		return d.Literal(v)
+	}
+	switch c.Op {
+	case "AND", "OR":
+		if len(c.Conds) == 0 {
+			return "", fmt.Errorf("%s needs at least one condition", c.Op)
+		}
+		preds := make([]string, len(c.Conds))
+		for i, sub := range c.Conds {
+			var err error
+			if preds[i], err = sub.render(d, args); err != nil {
+				return "", err
+			}
+		}
+		return "(" + strings.Join(preds, " "+c.Op+" ") + ")", nil
+	case "IS NULL", "IS NOT NULL":
+		return col + " " + c.Op, nil
+	case "IN":
+		values, ok := c.Value.([]any)
+		if !ok || len(values) == 0 {
+			return "", fmt.Errorf("IN needs a non-empty list of values")
+		}
+		rhs := make([]string, len(values))
+		for i, v := range values {
+			var err error
+			if rhs[i], err = bind(v); err != nil {
+				return "", err
+			}
+		}
+		return col + " IN (" + strings.Join(rhs, ", ") + ")", nil
+	}
+	if !condOps[c.Op] {
+		return "", fmt.Errorf("unsupported operator %q", c.Op)
+	}
+	rhs, err := bind(c.Value)
+	if err != nil {
+		return "", err
+	}
+	if c.Op == "ILIKE" {
+		return d.ilike(col, rhs), nil
+	}
+	return fmt.Sprintf("%s %s %s", col, c.Op, rhs), nil
+}
+
+func (o Order) render(d Dialect, dir string) (string, error) {
+	col := d.QuoteIdent(o.Column)
+	switch o.Nulls {
+	case "":
+		return col + " " + dir, nil
+	case "FIRST", "LAST":
+	default:
+		return "", fmt.Errorf("unsupported NULLS placement %q", o.Nulls)
+	}
+	switch d.Name {
+	case PostgresDialect.Name, ClickHouseDialect.Name:
+		return col + " " + dir + " NULLS " + o.Nulls, nil
+	}
+	// ANSI NULLS FIRST/LAST is missing from MySQL and others; sorting on an
+	// IS NULL flag first works everywhere.
+	first, rest := "0", "1"
+	if o.Nulls == "LAST" {
+		first, rest = "1", "0"
+	}
+	return "CASE WHEN " + col + " IS NULL THEN " + first + " ELSE " + rest + " END, " + col + " " + dir, nil
+}
+
+func (c Column) render(d Dialect) (string, error) {
+	arg := "*"
+	if c.Name != "" {
+		arg = d.QuoteIdent(c.Name)
+	}
+	expr := arg
+	if c.Func != "" {
+		if !funcNameRe.MatchString(c.Func) {
+			return "", fmt.Errorf("invalid function name %q", c.Func)
+		}
+		if c.Distinct {
+			arg = "DISTINCT " + arg
+		}
+		expr = c.Func + "(" + arg + ")"
+	} else if c.Name == "" {
+		return "", fmt.Errorf("column name is required")
+	}
+	if c.As != "" {
+		expr += " AS " + d.QuoteIdent(c.As)
+	}
+	return expr, nil
+}
+
+func (d Dialect) quoteAll(names []string) []string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		out[i] = d.QuoteIdent(n)
+	}
+	return out
+}
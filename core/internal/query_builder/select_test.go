@@ -0,0 +1,77 @@
+package query_builder
+
+import (
+	dbsql "database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ─── Select ───────────────────────────────────────────────────────────────────
+
+func TestSelect_BindsConditions(t *testing.T) {
+	sql, args, err := Select{
+		Columns: []Column{{Name: "id"}, {Name: "name", As: "label"}},
+		Schema:  "public",
+		Table:   "users",
+		Where:   []Cond{{Column: "org", Op: "=", Value: "acme"}, {Column: "age", Op: ">=", Value: 18}},
+		OrderBy: []Order{{Column: "id", Desc: true}},
+		Limit:   5,
+	}.Build(PostgresDialect)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "id", "name" AS "label" FROM "public"."users" WHERE "org" = $1 AND "age" >= $2 ORDER BY "id" DESC LIMIT 5`, sql)
+	assert.Equal(t, []any{"acme", 18}, args)
+}
+
+func TestSelect_ClickHouseNamedParams(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	sql, args, err := Select{
+		Table: "logs",
+		Where: []Cond{
+			{Column: "msg", Op: "<>", Value: "it's"},
+			{Column: "ts", Op: ">=", Value: at},
+			{Column: "level", Op: "IN", Value: []any{int64(1), 2.5}},
+		},
+	}.Build(ClickHouseDialect)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `logs` WHERE `msg` <> {p1:String} AND `ts` >= {p2:DateTime64(9, 'UTC')} AND `level` IN ({p3:Int64}, {p4:Float64})", sql)
+	assert.Equal(t, []any{dbsql.Named("p1", "it's"), dbsql.Named("p2", at), dbsql.Named("p3", int64(1)), dbsql.Named("p4", 2.5)}, args)
+
+	_, _, err = Select{Table: "logs", Where: []Cond{{Column: "msg", Op: "=", Value: struct{}{}}}}.Build(ClickHouseDialect)
+	assert.Error(t, err)
+}
+
+func TestQuoteIdent_ClickHouseEscapesBackslash(t *testing.T) {
+	assert.Equal(t, "`a\\\\`` OR 1`", ClickHouseDialect.QuoteIdent("a\\` OR 1"))
+	assert.Equal(t, `"a\""b"`, PostgresDialect.QuoteIdent(`a\"b`))
+}
+
+func TestSelect_Aggregates(t *testing.T) {
+	sql, _, err := Select{
+		Columns: []Column{{Name: "day"}, {Func: "count", As: "n"}, {Name: "user_id", Func: "COUNT", Distinct: true}},
+		Table:   "events",
+		Filter:  "kind = 'click' OR kind = 'view'",
+		Where:   []Cond{{Column: "day", Op: ">", Value: "2024-01-01"}},
+		GroupBy: []string{"day"},
+	}.Build(PostgresDialect)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "day", count(*) AS "n", COUNT(DISTINCT "user_id") FROM "events" WHERE "day" > $1 AND (kind = 'click' OR kind = 'view') GROUP BY "day"`, sql)
+}
+
+func TestSelect_Rejects(t *testing.T) {
+	cases := map[string]Select{
+		"operator":       {Table: "t", Where: []Cond{{Column: "a", Op: "; DROP", Value: 1}}},
+		"function":       {Table: "t", Columns: []Column{{Name: "a", Func: "sum(a)); --"}}},
+		"empty column":   {Table: "t", Columns: []Column{{}}},
+		"negative limit": {Table: "t", Limit: -1},
+		"no source":      {},
+	}
+	for name, s := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := s.Build(PostgresDialect)
+			assert.Error(t, err)
+		})
+	}
+}
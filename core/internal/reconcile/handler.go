@@ -0,0 +1,178 @@
+package reconcile
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/authz"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// Handler serves the reconciliation endpoints.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates a reconciliation HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// ─── wire types ───────────────────────────────────────────────────────────────
+
+type jobRequest struct {
+	Name            string     `json:"name" binding:"required"`
+	Description     string     `json:"description"`
+	Source          Side       `json:"source"`
+	Target          Side       `json:"target"`
+	Compare         Comparison `json:"compare"`
+	IntervalSeconds int        `json:"interval_seconds" binding:"required"`
+	Severity        string     `json:"severity"`
+	Enabled         *bool      `json:"enabled"`
+}
+
+func (r jobRequest) apply(j *Job) {
+	j.Name, j.Description = r.Name, r.Description
+	j.Source, j.Target, j.Compare = r.Source, r.Target, r.Compare
+	j.IntervalSeconds, j.Severity = r.IntervalSeconds, r.Severity
+	j.Enabled = r.Enabled == nil || *r.Enabled
+}
+
+// ─── handlers ─────────────────────────────────────────────────────────────────
+
+// List handles GET /reconciliations
+func (h *Handler) List(c *gin.Context) {
+	list, err := h.svc.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// Get handles GET /reconciliations/:id
+func (h *Handler) Get(c *gin.Context) {
+	j, err := h.svc.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": j})
+}
+
+// requireWrite answers 403 unless the caller may change datasources:
+// jobs query theirs on a schedule.
+func requireWrite(c *gin.Context) bool {
+	if identity.FromContext(c.Request.Context()).Can(identity.PermDatasourceWrite) {
+		return true
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "permission denied")})
+	return false
+}
+
+// Create handles POST /reconciliations
+func (h *Handler) Create(c *gin.Context) {
+	if !requireWrite(c) {
+		return
+	}
+	var req jobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	j := &Job{}
+	req.apply(j)
+	if err := h.svc.Create(c.Request.Context(), j); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": j})
+}
+
+// Update handles PUT /reconciliations/:id
+func (h *Handler) Update(c *gin.Context) {
+	if !requireWrite(c) {
+		return
+	}
+	j, err := h.svc.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	var req jobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.apply(j)
+	if err := h.svc.Update(c.Request.Context(), j); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": j})
+}
+
+// Delete handles DELETE /reconciliations/:id
+func (h *Handler) Delete(c *gin.Context) {
+	if !requireWrite(c) {
+		return
+	}
+	if err := h.svc.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Runs handles GET /reconciliations/:id/runs?limit=N
+func (h *Handler) Runs(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	runs, err := h.svc.Runs(c.Request.Context(), c.Param("id"), limit)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": runs})
+}
+
+// Run handles POST /reconciliations/:id/run
+func (h *Handler) Run(c *gin.Context) {
+	if !requireWrite(c) {
+		return
+	}
+	r, err := h.svc.RunNow(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": r})
+}
+
+func (h *Handler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "reconciliation job not found")})
+	case errors.Is(err, ErrInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, authz.ErrDenied), errors.Is(err, connection.ErrNetworkPolicy):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// RegisterRoutes wires the handler onto r.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/reconciliations", h.List)
+	r.POST("/reconciliations", h.Create)
+	r.GET("/reconciliations/:id", h.Get)
+	r.PUT("/reconciliations/:id", h.Update)
+	r.DELETE("/reconciliations/:id", h.Delete)
+	r.GET("/reconciliations/:id/runs", h.Runs)
+	r.POST("/reconciliations/:id/run", h.Run)
+}
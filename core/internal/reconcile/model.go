@@ -0,0 +1,132 @@
+// Package reconcile checks that data copied between datasources still agrees.
+// A job aggregates the same table on a source and a target — row counts and
+// sums per key — on a schedule, records the groups that disagree and alerts
+// through the notifier, for verifying ETL and replication pipelines.
+package reconcile
+
+import (
+	"context"
+	"time"
+)
+
+// Measure functions.
+const (
+	FuncCount = "count"
+	FuncSum   = "sum"
+)
+
+// Run statuses.
+const (
+	StatusOK       = "ok"
+	StatusMismatch = "mismatch"
+	StatusError    = "error"
+)
+
+// Discrepancy kinds.
+const (
+	// KindMissing is a group present on the source but not the target.
+	KindMissing = "missing"
+	// KindExtra is a group present on the target but not the source.
+	KindExtra = "extra"
+	// KindMismatch is a group whose measures differ.
+	KindMismatch = "mismatch"
+)
+
+// Side is where one half of a job reads from: a table or a query, optionally
+// narrowed by a filter. Query and Filter may use template variables and time
+// macros, which see the job's window.
+type Side struct {
+	DatasourceID string `json:"datasource_id"`
+	Schema       string `json:"schema,omitempty"`
+	Table        string `json:"table,omitempty"`
+	Query        string `json:"query,omitempty"`
+	Filter       string `json:"filter,omitempty"` // SQL condition appended as WHERE
+}
+
+// Measure is one aggregate compared between the sides. Count without a
+// column counts rows.
+type Measure struct {
+	Func   string `json:"func"` // count | sum
+	Column string `json:"column,omitempty"`
+}
+
+// Name is the result column the measure is selected as, e.g. "sum_amount".
+func (m Measure) Name() string {
+	if m.Column == "" {
+		return m.Func
+	}
+	return m.Func + "_" + m.Column
+}
+
+// Comparison says what a job aggregates and how close the sides must be.
+type Comparison struct {
+	// GroupBy are the key columns aggregates are computed per. Empty
+	// compares whole-table totals.
+	GroupBy  []string  `json:"group_by,omitempty"`
+	Measures []Measure `json:"measures"`
+	// Tolerance is the absolute difference allowed between measures.
+	Tolerance float64 `json:"tolerance,omitempty"`
+	// WindowSeconds is the span time macros cover, ending DelaySeconds
+	// before the run; it defaults to the job interval.
+	WindowSeconds int `json:"window_seconds,omitempty"`
+	// DelaySeconds leaves the most recent data out of the window so rows
+	// still in flight through the pipeline are not reported.
+	DelaySeconds int `json:"delay_seconds,omitempty"`
+}
+
+// Job is a scheduled reconciliation between two datasources.
+type Job struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	Description     string     `json:"description"`
+	Source          Side       `json:"source"`
+	Target          Side       `json:"target"`
+	Compare         Comparison `json:"compare"`
+	IntervalSeconds int        `json:"interval_seconds"`
+	Severity        string     `json:"severity"` // of mismatch alerts; default warning
+	Enabled         bool       `json:"enabled"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// Discrepancy is one group on which the sides disagree. Source and Target
+// hold each side's measures — only the differing ones for a mismatch — and
+// the side a missing or extra group lacks is nil.
+type Discrepancy struct {
+	Kind   string         `json:"kind"`
+	Key    map[string]any `json:"key"`
+	Source map[string]any `json:"source,omitempty"`
+	Target map[string]any `json:"target,omitempty"`
+}
+
+// Run records one execution of a job. Discrepancies lists at most the first
+// hundred disagreeing groups; the counts are exact.
+type Run struct {
+	ID            string        `json:"id"`
+	JobID         string        `json:"job_id"`
+	RanAt         time.Time     `json:"ran_at"`
+	Status        string        `json:"status"`
+	Error         string        `json:"error,omitempty"`
+	SourceGroups  int           `json:"source_groups"`
+	TargetGroups  int           `json:"target_groups"`
+	Matched       int           `json:"matched"`
+	Missing       int           `json:"missing"`
+	Extra         int           `json:"extra"`
+	Mismatched    int           `json:"mismatched"`
+	Discrepancies []Discrepancy `json:"discrepancies"`
+}
+
+// Repository persists jobs and their run history.
+// Implemented per-driver in store/{postgres,sqlite,mysql}/.
+type Repository interface {
+	List(ctx context.Context) ([]*Job, error)
+	GetByID(ctx context.Context, id string) (*Job, error)
+	Create(ctx context.Context, j *Job) error
+	Update(ctx context.Context, j *Job) error
+	// Delete removes the job and its runs.
+	Delete(ctx context.Context, id string) error
+
+	AddRun(ctx context.Context, r *Run) error
+	// ListRuns returns up to limit runs of a job, newest first.
+	ListRuns(ctx context.Context, jobID string, limit int) ([]*Run, error)
+}
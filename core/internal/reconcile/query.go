@@ -0,0 +1,45 @@
+package reconcile
+
+import (
+	qb "data-voyager/core/internal/query_builder"
+)
+
+// aggregateQuery renders the GROUP BY query for one side of a job.
+func aggregateQuery(side Side, cmp Comparison, d qb.Dialect, tr qb.TimeRange) (string, error) {
+	render := func(tmpl string) (string, error) {
+		if tmpl == "" {
+			return "", nil
+		}
+		out, err := qb.RenderQuery(tmpl, qb.BuildContext(tr, nil, 0))
+		if err != nil {
+			return "", err
+		}
+		out, _, err = qb.ExpandMacros(out, d, tr, 0)
+		return out, err
+	}
+	query, err := render(side.Query)
+	if err != nil {
+		return "", err
+	}
+	filter, err := render(side.Filter)
+	if err != nil {
+		return "", err
+	}
+
+	cols := make([]qb.Column, 0, len(cmp.GroupBy)+len(cmp.Measures))
+	for _, g := range cmp.GroupBy {
+		cols = append(cols, qb.Column{Name: g})
+	}
+	for _, m := range cmp.Measures {
+		cols = append(cols, qb.Column{Name: m.Column, Func: m.Func, As: m.Name()})
+	}
+	sql, _, err := qb.Select{
+		Columns: cols,
+		Schema:  side.Schema,
+		Table:   side.Table,
+		Query:   query,
+		Filter:  filter,
+		GroupBy: cmp.GroupBy,
+	}.Build(d)
+	return sql, err
+}
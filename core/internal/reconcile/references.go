@@ -0,0 +1,47 @@
+package reconcile
+
+import (
+	"context"
+
+	"data-voyager/core/internal/connection"
+)
+
+// referenceSource reports reconciliation jobs reading from a datasource on
+// either side.
+type referenceSource struct {
+	repo Repository
+}
+
+// NewReferenceSource returns the connection.ReferenceSource for
+// reconciliation jobs. A forced datasource delete removes the jobs that use
+// it, with their runs.
+func NewReferenceSource(repo Repository) connection.ReferenceSource {
+	return &referenceSource{repo: repo}
+}
+
+func (s *referenceSource) References(ctx context.Context, datasourceID string) ([]connection.Reference, error) {
+	all, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var refs []connection.Reference
+	for _, j := range all {
+		if j.Source.DatasourceID == datasourceID || j.Target.DatasourceID == datasourceID {
+			refs = append(refs, connection.Reference{Kind: "reconciliation", ID: j.ID, Name: j.Name})
+		}
+	}
+	return refs, nil
+}
+
+func (s *referenceSource) DeleteReferences(ctx context.Context, datasourceID string) error {
+	refs, err := s.References(ctx, datasourceID)
+	if err != nil {
+		return err
+	}
+	for _, r := range refs {
+		if err := s.repo.Delete(ctx, r.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
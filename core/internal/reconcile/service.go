@@ -0,0 +1,504 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"data-voyager/core/internal/authz"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/diff"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/notification"
+	qb "data-voyager/core/internal/query_builder"
+	"data-voyager/sdk"
+)
+
+var (
+	// ErrInvalid wraps validation failures so handlers can map them to 400.
+	ErrInvalid = errors.New("invalid reconciliation job")
+	// ErrNotFound is returned for an unknown job.
+	ErrNotFound = errors.New("reconciliation job not found")
+)
+
+const (
+	minInterval      = 10 * time.Second
+	maxRunsListed    = 1000
+	maxDiscrepancies = 100
+	defaultSeverity  = notification.SeverityWarning
+	// totalKey names the single group of a job without GroupBy.
+	totalKey = "scope"
+)
+
+// Service manages reconciliation jobs and executes them.
+type Service struct {
+	repo     Repository
+	conns    connection.Repository
+	registry *datasource.Registry
+	notifier notification.Notifier
+	now      func() time.Time
+	// preResume is how long before a run a suspended warehouse is woken;
+	// 0 never wakes one ahead.
+	preResume time.Duration
+
+	mu sync.Mutex
+	// next holds when each job is due, filled lazily by the scheduler.
+	next map[string]time.Time
+	// woken holds the due time each job's warehouse was last woken for.
+	woken map[string]time.Time
+}
+
+// NewService creates a Service. notifier may be nil to only record runs.
+func NewService(repo Repository, conns connection.Repository, registry *datasource.Registry, notifier notification.Notifier) *Service {
+	return &Service{
+		repo:     repo,
+		conns:    connection.Visible(conns),
+		registry: registry,
+		notifier: notifier,
+		now:      time.Now,
+		next:     map[string]time.Time{},
+		woken:    map[string]time.Time{},
+	}
+}
+
+// WithPreResume wakes suspended warehouses lead before a job falls due.
+func (s *Service) WithPreResume(lead time.Duration) *Service {
+	s.preResume = lead
+	return s
+}
+
+// List returns the jobs whose source and target the caller may both see.
+func (s *Service) List(ctx context.Context) ([]*Job, error) {
+	list, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(list, func(j *Job) bool { return !visible(ctx, j) }), nil
+}
+
+// Get returns a job; those touching a datasource the caller may not see are
+// not found.
+func (s *Service) Get(ctx context.Context, id string) (*Job, error) {
+	j, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	if !visible(ctx, j) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return j, nil
+}
+
+func visible(ctx context.Context, j *Job) bool {
+	id := identity.FromContext(ctx)
+	return id.CanSee(j.Source.DatasourceID) && id.CanSee(j.Target.DatasourceID)
+}
+
+func (s *Service) Create(ctx context.Context, j *Job) error {
+	if err := s.validate(ctx, j); err != nil {
+		return err
+	}
+	j.ID = uuid.NewString()
+	return s.repo.Create(ctx, j)
+}
+
+func (s *Service) Update(ctx context.Context, j *Job) error {
+	if _, err := s.Get(ctx, j.ID); err != nil {
+		return err
+	}
+	if err := s.validate(ctx, j); err != nil {
+		return err
+	}
+	if err := s.repo.Update(ctx, j); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	delete(s.next, j.ID)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Service) Delete(ctx context.Context, id string) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	delete(s.next, id)
+	s.mu.Unlock()
+	return nil
+}
+
+// Runs returns up to limit recent runs of a job, newest first.
+func (s *Service) Runs(ctx context.Context, id string, limit int) ([]*Run, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxRunsListed {
+		limit = maxRunsListed
+	}
+	return s.repo.ListRuns(ctx, id, limit)
+}
+
+func (s *Service) validate(ctx context.Context, j *Job) error {
+	if j.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalid)
+	}
+	for name, side := range map[string]Side{"source": j.Source, "target": j.Target} {
+		if _, err := s.conns.GetByID(ctx, side.DatasourceID); err != nil {
+			return fmt.Errorf("%w: %s datasource %q not found", ErrInvalid, name, side.DatasourceID)
+		}
+		if err := authz.CheckQuery(ctx, side.DatasourceID); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if _, err := qb.GenericDialect.FromSource(side.Schema, side.Table, side.Query); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalid, name, err)
+		}
+	}
+	if time.Duration(j.IntervalSeconds)*time.Second < minInterval {
+		return fmt.Errorf("%w: interval_seconds must be at least %d", ErrInvalid, int(minInterval/time.Second))
+	}
+	switch j.Severity {
+	case "":
+		j.Severity = defaultSeverity
+	case notification.SeverityInfo, notification.SeverityWarning, notification.SeverityCritical:
+	default:
+		return fmt.Errorf("%w: unknown severity %q", ErrInvalid, j.Severity)
+	}
+
+	cmp := j.Compare
+	if len(cmp.Measures) == 0 {
+		return fmt.Errorf("%w: at least one measure is required", ErrInvalid)
+	}
+	names := slices.Clone(cmp.GroupBy)
+	if len(names) == 0 {
+		names = []string{totalKey}
+	}
+	for _, g := range cmp.GroupBy {
+		if g == "" {
+			return fmt.Errorf("%w: group_by columns must not be empty", ErrInvalid)
+		}
+	}
+	for _, m := range cmp.Measures {
+		switch {
+		case m.Func != FuncCount && m.Func != FuncSum:
+			return fmt.Errorf("%w: unknown measure function %q", ErrInvalid, m.Func)
+		case m.Func == FuncSum && m.Column == "":
+			return fmt.Errorf("%w: sum needs a column", ErrInvalid)
+		case slices.Contains(names, m.Name()):
+			return fmt.Errorf("%w: %q is repeated across group_by and measures", ErrInvalid, m.Name())
+		}
+		names = append(names, m.Name())
+	}
+	if cmp.Tolerance < 0 || cmp.WindowSeconds < 0 || cmp.DelaySeconds < 0 {
+		return fmt.Errorf("%w: tolerance, window_seconds and delay_seconds must not be negative", ErrInvalid)
+	}
+	return nil
+}
+
+// RunNow executes a job immediately, records the run and raises any
+// resulting alert.
+func (s *Service) RunNow(ctx context.Context, id string) (*Run, error) {
+	j, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.run(ctx, j)
+}
+
+func (s *Service) run(ctx context.Context, j *Job) (*Run, error) {
+	interval := time.Duration(j.IntervalSeconds) * time.Second
+	now := s.now().UTC()
+	r := &Run{ID: uuid.NewString(), JobID: j.ID, RanAt: now}
+
+	window := time.Duration(j.Compare.WindowSeconds) * time.Second
+	if window == 0 {
+		window = interval
+	}
+	end := now.Add(-time.Duration(j.Compare.DelaySeconds) * time.Second)
+	tr := qb.TimeRange{From: end.Add(-window), To: end}
+
+	// Both sides together may take at most one interval so runs never pile up.
+	qctx, cancel := context.WithTimeout(ctx, interval)
+	src, err := s.aggregate(qctx, j.Source, j.Compare, tr)
+	var tgt *sdk.DataFrame
+	if err != nil {
+		err = fmt.Errorf("source: %w", err)
+	} else if tgt, err = s.aggregate(qctx, j.Target, j.Compare, tr); err != nil {
+		err = fmt.Errorf("target: %w", err)
+	}
+	cancel()
+	if err == nil {
+		err = compare(r, j.Compare, src, tgt)
+	}
+	if err != nil {
+		r.Status, r.Error = StatusError, err.Error()
+	}
+
+	if err := s.repo.AddRun(ctx, r); err != nil {
+		return nil, fmt.Errorf("record run: %w", err)
+	}
+	s.alert(ctx, j, r)
+	return r, nil
+}
+
+// aggregate runs the job's aggregate on one side and returns its frame.
+func (s *Service) aggregate(ctx context.Context, side Side, cmp Comparison, tr qb.TimeRange) (*sdk.DataFrame, error) {
+	conn, err := s.conns.GetByID(ctx, side.DatasourceID)
+	if err != nil {
+		return nil, fmt.Errorf("datasource not found")
+	}
+	if err := authz.CheckQuery(ctx, conn.ID); err != nil {
+		return nil, err
+	}
+	if err := connection.CheckOrigin(ctx, conn); err != nil {
+		return nil, err
+	}
+	plugin, ok := s.registry.Get(conn.Type)
+	if !ok {
+		return nil, fmt.Errorf("plugin not found for type %s", conn.Type)
+	}
+	cfg, err := plugin.ParseConfig(conn.Config)
+	if err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	query, err := aggregateQuery(side, cmp, qb.DialectFor(string(conn.Type)), tr)
+	if err != nil {
+		return nil, err
+	}
+	dbConn, err := plugin.Connect(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer func() { _ = dbConn.Close() }()
+	res, err := dbConn.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	if len(res.Frames) == 0 || res.Frames[0] == nil {
+		return nil, fmt.Errorf("query returned no result")
+	}
+	return res.Frames[0], nil
+}
+
+// compare fills r with the differences between the source and target
+// aggregates.
+func compare(r *Run, cmp Comparison, src, tgt *sdk.DataFrame) error {
+	keys := cmp.GroupBy
+	if len(keys) == 0 {
+		keys = []string{totalKey}
+	}
+	measures := make([]string, len(cmp.Measures))
+	for i, m := range cmp.Measures {
+		measures[i] = m.Name()
+	}
+	res, err := diff.Compare(normalize(src, cmp), normalize(tgt, cmp), diff.Options{
+		Keys:      keys,
+		Columns:   measures,
+		Tolerance: cmp.Tolerance,
+		MaxRows:   maxDiscrepancies,
+	})
+	if err != nil {
+		return err
+	}
+
+	r.SourceGroups, r.TargetGroups = res.LeftRows, res.RightRows
+	r.Matched, r.Missing, r.Extra, r.Mismatched = res.Unchanged, res.Removed, res.Added, res.Changed
+	r.Status = StatusOK
+	if res.Removed+res.Added+res.Changed > 0 {
+		r.Status = StatusMismatch
+	}
+
+	split := func(row diff.Row) (key, vals map[string]any) {
+		key, vals = map[string]any{}, map[string]any{}
+		for _, k := range keys {
+			key[k] = row[k]
+		}
+		for _, m := range measures {
+			vals[m] = row[m]
+		}
+		return key, vals
+	}
+	for _, ch := range res.ChangedRows {
+		d := Discrepancy{Kind: KindMismatch, Key: ch.Key, Source: map[string]any{}, Target: map[string]any{}}
+		for _, c := range ch.Columns {
+			d.Source[c.Column], d.Target[c.Column] = c.Left, c.Right
+		}
+		r.Discrepancies = append(r.Discrepancies, d)
+	}
+	for _, row := range res.RemovedRows {
+		key, vals := split(row)
+		r.Discrepancies = append(r.Discrepancies, Discrepancy{Kind: KindMissing, Key: key, Source: vals})
+	}
+	for _, row := range res.AddedRows {
+		key, vals := split(row)
+		r.Discrepancies = append(r.Discrepancies, Discrepancy{Kind: KindExtra, Key: key, Target: vals})
+	}
+	if len(r.Discrepancies) > maxDiscrepancies {
+		r.Discrepancies = r.Discrepancies[:maxDiscrepancies]
+	}
+	return nil
+}
+
+// normalize returns f with measure values as float64, since drivers return
+// counts and sums as integers, floats or decimal strings depending on the
+// backend, and adds the constant total key when the job has no GroupBy.
+func normalize(f *sdk.DataFrame, cmp Comparison) *sdk.DataFrame {
+	out := &sdk.DataFrame{Name: f.Name, FrameType: f.FrameType}
+	rows := 0
+	for _, fd := range f.Fields {
+		rows = max(rows, len(fd.Values))
+		if !slices.ContainsFunc(cmp.Measures, func(m Measure) bool { return m.Name() == fd.Name }) {
+			out.Fields = append(out.Fields, fd)
+			continue
+		}
+		vals := make([]any, len(fd.Values))
+		for i, v := range fd.Values {
+			if x, ok := sdk.Float64Value(v); ok {
+				vals[i] = x
+			} else {
+				vals[i] = v
+			}
+		}
+		fd.Values = vals
+		out.Fields = append(out.Fields, fd)
+	}
+	if len(cmp.GroupBy) == 0 {
+		vals := make([]any, rows)
+		for i := range vals {
+			vals[i] = "total"
+		}
+		out.Fields = append(out.Fields, sdk.Field{Name: totalKey, Kind: sdk.FieldKindString, Values: vals})
+	}
+	return out
+}
+
+// alert raises a notification for a mismatched or failed run.
+func (s *Service) alert(ctx context.Context, j *Job, r *Run) {
+	if s.notifier == nil || r.Status == StatusOK {
+		return
+	}
+	ev := notification.Event{
+		Source: j.Name,
+		Labels: map[string]string{
+			"reconciliation_id":    j.ID,
+			"source_datasource_id": j.Source.DatasourceID,
+			"target_datasource_id": j.Target.DatasourceID,
+		},
+		Time: r.RanAt,
+	}
+	if r.Status == StatusError {
+		ev.Type = notification.EventScheduleFailure
+		ev.Severity = notification.SeverityWarning
+		ev.Title = fmt.Sprintf("Reconciliation %s failed", j.Name)
+		ev.Body = r.Error
+	} else {
+		ev.Type = notification.EventAlert
+		ev.Severity = j.Severity
+		ev.Title = fmt.Sprintf("Reconciliation %s found discrepancies", j.Name)
+		ev.Body = fmt.Sprintf("%d of %d source groups matched: %d missing from the target, %d only on the target, %d with differing values.",
+			r.Matched, r.SourceGroups, r.Missing, r.Extra, r.Mismatched)
+	}
+	if err := s.notifier.Notify(ctx, ev); err != nil {
+		slog.Warn("reconciliation notification failed", "job", j.Name, "err", err)
+	}
+}
+
+// Schedule runs due jobs every tick until ctx is done. Jobs run one after
+// another; one that is still running when it next falls due simply runs
+// late.
+func (s *Service) Schedule(ctx context.Context, tick time.Duration) {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s.runDue(ctx)
+	}
+}
+
+func (s *Service) runDue(ctx context.Context) {
+	jobs, err := s.repo.List(ctx)
+	if err != nil {
+		slog.Warn("list reconciliation jobs failed", "err", err)
+		return
+	}
+	for _, j := range jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		if !j.Enabled {
+			continue
+		}
+		if !s.due(ctx, j) {
+			s.wakeAhead(ctx, j)
+			continue
+		}
+		if _, err := s.run(ctx, j); err != nil {
+			slog.Warn("reconciliation run failed", "job", j.Name, "err", err)
+		}
+		s.mu.Lock()
+		s.next[j.ID] = s.now().Add(time.Duration(j.IntervalSeconds) * time.Second)
+		s.mu.Unlock()
+	}
+}
+
+// due reports whether j should run now. The first check after start-up
+// resumes from the last recorded run rather than running everything at once.
+func (s *Service) due(ctx context.Context, j *Job) bool {
+	s.mu.Lock()
+	next, ok := s.next[j.ID]
+	s.mu.Unlock()
+	if !ok {
+		runs, err := s.repo.ListRuns(ctx, j.ID, 1)
+		if err != nil {
+			return false
+		}
+		if len(runs) > 0 {
+			next = runs[0].RanAt.Add(time.Duration(j.IntervalSeconds) * time.Second)
+		}
+		s.mu.Lock()
+		s.next[j.ID] = next
+		s.mu.Unlock()
+	}
+	return !s.now().Before(next)
+}
+
+// wakeAhead asks the warehouses behind j to resume when j falls due within
+// the pre-resume lead, once per due time, so the run finds them running.
+func (s *Service) wakeAhead(ctx context.Context, j *Job) {
+	if s.preResume <= 0 {
+		return
+	}
+	s.mu.Lock()
+	next := s.next[j.ID]
+	wake := !s.woken[j.ID].Equal(next) && next.Sub(s.now()) <= s.preResume
+	if wake {
+		s.woken[j.ID] = next
+	}
+	s.mu.Unlock()
+	if !wake {
+		return
+	}
+	for _, side := range []Side{j.Source, j.Target} {
+		conn, err := s.conns.GetByID(ctx, side.DatasourceID)
+		if err != nil {
+			continue
+		}
+		if plugin, ok := s.registry.Get(conn.Type); ok {
+			connection.WakeWarehouse(ctx, plugin, conn)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/notification"
+	qb "data-voyager/core/internal/query_builder"
+	"data-voyager/sdk"
+)
+
+func TestAggregateQuery(t *testing.T) {
+	tr := qb.TimeRange{
+		From: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+	}
+	cmp := Comparison{
+		GroupBy:  []string{"day"},
+		Measures: []Measure{{Func: FuncCount}, {Func: FuncSum, Column: "amount"}},
+	}
+	q, err := aggregateQuery(Side{Schema: "public", Table: "orders", Filter: "$__timeFilter(created_at)"}, cmp, qb.PostgresDialect, tr)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "day", count(*) AS "count", sum("amount") AS "sum_amount" FROM "public"."orders"`+
+		` WHERE created_at BETWEEN '2024-05-01T00:00:00Z' AND '2024-05-02T00:00:00Z' GROUP BY "day"`, q)
+
+	q, err = aggregateQuery(Side{Query: "SELECT * FROM orders_v2;"}, Comparison{Measures: []Measure{{Func: FuncCount}}}, qb.PostgresDialect, tr)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT count(*) AS "count" FROM (SELECT * FROM orders_v2) AS src`, q)
+}
+
+// ─── Service ──────────────────────────────────────────────────────────────────
+
+type memRepo struct {
+	jobs map[string]*Job
+	runs []*Run
+}
+
+func (r *memRepo) List(context.Context) ([]*Job, error) {
+	var out []*Job
+	for _, j := range r.jobs {
+		out = append(out, j)
+	}
+	return out, nil
+}
+func (r *memRepo) GetByID(_ context.Context, id string) (*Job, error) {
+	if j, ok := r.jobs[id]; ok {
+		return j, nil
+	}
+	return nil, errors.New("not found")
+}
+func (r *memRepo) Create(_ context.Context, j *Job) error { r.jobs[j.ID] = j; return nil }
+func (r *memRepo) Update(_ context.Context, j *Job) error { r.jobs[j.ID] = j; return nil }
+func (r *memRepo) Delete(_ context.Context, id string) error {
+	delete(r.jobs, id)
+	return nil
+}
+func (r *memRepo) AddRun(_ context.Context, run *Run) error { r.runs = append(r.runs, run); return nil }
+func (r *memRepo) ListRuns(_ context.Context, id string, limit int) ([]*Run, error) {
+	var out []*Run
+	for i := len(r.runs) - 1; i >= 0 && len(out) < limit; i-- {
+		if r.runs[i].JobID == id {
+			out = append(out, r.runs[i])
+		}
+	}
+	return out, nil
+}
+
+type stubConns struct{ connection.Repository }
+
+func (stubConns) GetByID(_ context.Context, id string) (*connection.Connection, error) {
+	switch id {
+	case "src", "dst":
+		return &connection.Connection{ID: id, Type: "stub", Config: json.RawMessage(`{}`)}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+type stubConfig struct{}
+
+func (stubConfig) Validate() error             { return nil }
+func (stubConfig) GetConnectionString() string { return "" }
+
+// stubPlugin answers successive queries with successive frames; a nil frame
+// fails the query.
+type stubPlugin struct {
+	sdk.DatasourcePlugin
+	frames  []*sdk.DataFrame
+	queries []string
+}
+
+func (p *stubPlugin) GetType() sdk.DataSourceType { return "stub" }
+func (p *stubPlugin) ParseConfig(json.RawMessage) (sdk.ConnectionConfig, error) {
+	return stubConfig{}, nil
+}
+func (p *stubPlugin) Connect(context.Context, sdk.ConnectionConfig) (sdk.Connection, error) {
+	return &stubConn{p: p}, nil
+}
+
+type stubConn struct {
+	sdk.Connection
+	p *stubPlugin
+}
+
+func (c *stubConn) Query(_ context.Context, q string, _ ...any) (*sdk.QueryResult, error) {
+	c.p.queries = append(c.p.queries, q)
+	f := c.p.frames[0]
+	c.p.frames = c.p.frames[1:]
+	if f == nil {
+		return nil, errors.New("permission denied")
+	}
+	return &sdk.QueryResult{Frames: []*sdk.DataFrame{f}}, nil
+}
+func (c *stubConn) Close() error { return nil }
+
+type recorder struct{ events []notification.Event }
+
+func (r *recorder) Notify(_ context.Context, ev notification.Event) error {
+	r.events = append(r.events, ev)
+	return nil
+}
+
+func newTestService(frames ...*sdk.DataFrame) (*Service, *stubPlugin, *recorder) {
+	plugin := &stubPlugin{frames: frames}
+	reg := datasource.NewRegistry()
+	reg.Register(plugin)
+	rec := &recorder{}
+	return NewService(&memRepo{jobs: map[string]*Job{}}, stubConns{}, reg, rec), plugin, rec
+}
+
+func dayFrame(days []string, counts []any, sums []any) *sdk.DataFrame {
+	d := make([]any, len(days))
+	for i, x := range days {
+		d[i] = x
+	}
+	return &sdk.DataFrame{Fields: []sdk.Field{
+		{Name: "day", Values: d},
+		{Name: "count", Values: counts},
+		{Name: "sum_amount", Values: sums},
+	}}
+}
+
+func testJob() *Job {
+	return &Job{
+		Name:            "orders to warehouse",
+		Source:          Side{DatasourceID: "src", Table: "orders"},
+		Target:          Side{DatasourceID: "dst", Table: "fact_orders"},
+		IntervalSeconds: 3600,
+		Enabled:         true,
+		Compare: Comparison{
+			GroupBy:   []string{"day"},
+			Measures:  []Measure{{Func: FuncCount}, {Func: FuncSum, Column: "amount"}},
+			Tolerance: 0.01,
+		},
+	}
+}
+
+func TestService_ReportsDiscrepancies(t *testing.T) {
+	svc, _, rec := newTestService(
+		dayFrame([]string{"d1", "d2", "d3"}, []any{int64(10), int64(20), int64(5)}, []any{"100.00", "200.00", "50.00"}),
+		dayFrame([]string{"d1", "d2", "d4"}, []any{uint64(10), uint64(19), uint64(1)}, []any{100.004, 190.0, 1.0}),
+	)
+	j := testJob()
+	require.NoError(t, svc.Create(context.Background(), j))
+
+	r, err := svc.RunNow(context.Background(), j.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusMismatch, r.Status)
+	assert.Equal(t, 1, r.Matched, "decimal strings, floats and mixed integer types compare by value")
+	assert.Equal(t, 1, r.Missing)
+	assert.Equal(t, 1, r.Extra)
+	assert.Equal(t, 1, r.Mismatched)
+	require.Len(t, r.Discrepancies, 3)
+	assert.Equal(t, Discrepancy{
+		Kind:   KindMismatch,
+		Key:    map[string]any{"day": "d2"},
+		Source: map[string]any{"count": 20.0, "sum_amount": 200.0},
+		Target: map[string]any{"count": 19.0, "sum_amount": 190.0},
+	}, r.Discrepancies[0])
+	assert.Equal(t, KindMissing, r.Discrepancies[1].Kind)
+	assert.Nil(t, r.Discrepancies[1].Target)
+	assert.Equal(t, KindExtra, r.Discrepancies[2].Kind)
+
+	require.Len(t, rec.events, 1)
+	assert.Equal(t, notification.EventAlert, rec.events[0].Type)
+	assert.Equal(t, notification.SeverityWarning, rec.events[0].Severity)
+}
+
+func TestService_TotalsWithoutGroupBy(t *testing.T) {
+	total := func(n any) *sdk.DataFrame {
+		return &sdk.DataFrame{Fields: []sdk.Field{{Name: "count", Values: []any{n}}}}
+	}
+	svc, plugin, rec := newTestService(total(int64(42)), total("42"))
+	j := testJob()
+	j.Compare = Comparison{Measures: []Measure{{Func: FuncCount}}, DelaySeconds: 600}
+	require.NoError(t, svc.Create(context.Background(), j))
+
+	r, err := svc.RunNow(context.Background(), j.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusOK, r.Status)
+	assert.Equal(t, 1, r.Matched)
+	assert.Empty(t, rec.events)
+	assert.Equal(t, `SELECT count(*) AS "count" FROM "orders"`, plugin.queries[0])
+}
+
+func TestService_FailureAlerts(t *testing.T) {
+	svc, _, rec := newTestService(dayFrame(nil, nil, nil), nil)
+	j := testJob()
+	require.NoError(t, svc.Create(context.Background(), j))
+
+	r, err := svc.RunNow(context.Background(), j.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusError, r.Status)
+	assert.Equal(t, "target: query failed: permission denied", r.Error)
+	require.Len(t, rec.events, 1)
+	assert.Equal(t, notification.EventScheduleFailure, rec.events[0].Type)
+}
+
+func TestService_Validate(t *testing.T) {
+	svc, _, _ := newTestService()
+	ctx := context.Background()
+
+	for name, mutate := range map[string]func(*Job){
+		"unknown target":     func(j *Job) { j.Target.DatasourceID = "nope" },
+		"table and query":    func(j *Job) { j.Source.Query = "SELECT 1" },
+		"no measures":        func(j *Job) { j.Compare.Measures = nil },
+		"sum without column": func(j *Job) { j.Compare.Measures = []Measure{{Func: FuncSum}} },
+		"unknown function":   func(j *Job) { j.Compare.Measures = []Measure{{Func: "avg", Column: "x"}} },
+		"name collision":     func(j *Job) { j.Compare.GroupBy = []string{"count"} },
+		"short interval":     func(j *Job) { j.IntervalSeconds = 5 },
+		"negative tolerance": func(j *Job) { j.Compare.Tolerance = -1 },
+		"unknown severity":   func(j *Job) { j.Severity = "loud" },
+		"repeated measure":   func(j *Job) { j.Compare.Measures = append(j.Compare.Measures, Measure{Func: FuncCount}) },
+	} {
+		j := testJob()
+		mutate(j)
+		assert.ErrorIs(t, svc.Create(ctx, j), ErrInvalid, name)
+	}
+}
+
+func TestService_HidesInvisibleDatasources(t *testing.T) {
+	repo := &memRepo{jobs: map[string]*Job{}}
+	svc := NewService(repo, stubConns{}, datasource.NewRegistry(), nil)
+	j := testJob()
+	require.NoError(t, svc.Create(context.Background(), j))
+
+	// Seeing the source alone is not enough.
+	ctx := identity.With(context.Background(), &identity.Identity{Username: "bob", Role: identity.RoleEditor, Datasources: []string{"src"}})
+	list, err := svc.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+	_, err = svc.Get(ctx, j.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = svc.RunNow(ctx, j.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, svc.Update(ctx, j), ErrNotFound)
+	assert.ErrorIs(t, svc.Delete(ctx, j.ID), ErrNotFound)
+	assert.ErrorIs(t, svc.Create(ctx, testJob()), ErrInvalid)
+	assert.Len(t, repo.jobs, 1)
+}
+
+func TestRoutes_ChangesNeedDatasourceWrite(t *testing.T) {
+	repo := &memRepo{jobs: map[string]*Job{}}
+	j := testJob()
+	j.ID = "j1"
+	repo.jobs[j.ID] = j
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(identity.With(c.Request.Context(), &identity.Identity{Username: "v", Role: identity.RoleViewer}))
+	})
+	RegisterRoutes(&r.RouterGroup, NewHandler(NewService(repo, stubConns{}, datasource.NewRegistry(), nil)))
+
+	body := `{"name":"x","source":{"datasource_id":"src","table":"a"},"target":{"datasource_id":"dst","table":"b"},"interval_seconds":3600}`
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/reconciliations", strings.NewReader(body)),
+		httptest.NewRequest(http.MethodPut, "/reconciliations/j1", strings.NewReader(body)),
+		httptest.NewRequest(http.MethodDelete, "/reconciliations/j1", nil),
+		httptest.NewRequest(http.MethodPost, "/reconciliations/j1/run", nil),
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code, req.Method+" "+req.URL.Path)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/reconciliations/j1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, repo.jobs, 1)
+}
@@ -0,0 +1,214 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"data-voyager/sdk"
+)
+
+// Chart geometry, in pixels.
+const (
+	chartWidth  = 940
+	chartHeight = 260
+	padLeft     = 64
+	padRight    = 12
+	padTop      = 10
+	padBottom   = 24
+	legendLine  = 16
+)
+
+var palette = []string{"#2680c2", "#e12d39", "#3ebd93", "#f0b429", "#8662c7", "#f368e0", "#40c3f7", "#7b8794"}
+
+type point struct {
+	x time.Time
+	y float64
+}
+
+type series struct {
+	name   string
+	points []point
+}
+
+// lineChart draws every numeric column of frames against the frame's time
+// column as an SVG line chart. ok is false when there is nothing to plot.
+func lineChart(frames []*sdk.DataFrame) (chart template.HTML, ok bool) {
+	var all []series
+	for _, f := range frames {
+		ts := timeField(f)
+		if ts < 0 {
+			continue
+		}
+		for i, field := range f.Fields {
+			if i == ts {
+				continue
+			}
+			s := series{name: field.Name}
+			if len(frames) > 1 && f.Name != "" {
+				s.name = f.Name + " " + field.Name
+			}
+			times := f.Fields[ts].Values
+			numeric := false
+			for r, v := range field.Values[:min(len(field.Values), len(times))] {
+				t, tok := asTime(times[r])
+				y, yok := asNumber(v)
+				if v != nil && !yok {
+					numeric = false
+					break
+				}
+				if tok && yok {
+					numeric = true
+					s.points = append(s.points, point{t, y})
+				}
+			}
+			if numeric {
+				all = append(all, s)
+			}
+		}
+	}
+	if len(all) == 0 {
+		return "", false
+	}
+
+	x0, x1 := all[0].points[0].x, all[0].points[0].x
+	y0, y1 := math.Inf(1), math.Inf(-1)
+	for _, s := range all {
+		for _, p := range s.points {
+			if p.x.Before(x0) {
+				x0 = p.x
+			}
+			if p.x.After(x1) {
+				x1 = p.x
+			}
+			y0, y1 = math.Min(y0, p.y), math.Max(y1, p.y)
+		}
+	}
+	if y0 == y1 {
+		y0, y1 = y0-1, y1+1
+	}
+	plotW := float64(chartWidth - padLeft - padRight)
+	plotH := float64(chartHeight - padTop - padBottom)
+	span := x1.Sub(x0)
+	px := func(t time.Time) float64 {
+		if span <= 0 {
+			return padLeft + plotW/2
+		}
+		return padLeft + plotW*float64(t.Sub(x0))/float64(span)
+	}
+	py := func(y float64) float64 { return padTop + plotH*(1-(y-y0)/(y1-y0)) }
+
+	height := chartHeight + legendLine*len(all)
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="100%%" viewBox="0 0 %d %d">`, chartWidth, height)
+	fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%.0f" height="%.0f" fill="none" stroke="#d9e2ec"/>`, padLeft, padTop, plotW, plotH)
+	for _, y := range []float64{y0, (y0 + y1) / 2, y1} {
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end">%s</text>`, padLeft-6, py(y)+4, formatNumber(y))
+	}
+	layout := timeLayout(span)
+	fmt.Fprintf(&b, `<text x="%d" y="%d">%s</text>`, padLeft, chartHeight-6, x0.UTC().Format(layout))
+	fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end">%s</text>`, chartWidth-padRight, chartHeight-6, x1.UTC().Format(layout))
+	for i, s := range all {
+		color := palette[i%len(palette)]
+		pts := make([]string, len(s.points))
+		for j, p := range s.points {
+			pts[j] = fmt.Sprintf("%.1f,%.1f", px(p.x), py(p.y))
+		}
+		if len(pts) == 1 {
+			fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"/>`, px(s.points[0].x), py(s.points[0].y), color)
+		} else {
+			fmt.Fprintf(&b, `<polyline fill="none" stroke="%s" stroke-width="1.5" points="%s"/>`, color, strings.Join(pts, " "))
+		}
+		ly := chartHeight + legendLine*i + 10
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="10" height="10" fill="%s"/>`, padLeft, ly-9, color)
+		fmt.Fprintf(&b, `<text x="%d" y="%d">%s</text>`, padLeft+16, ly, template.HTMLEscapeString(s.name))
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String()), true
+}
+
+// timeField returns the index of f's first time column, or -1.
+func timeField(f *sdk.DataFrame) int {
+	for i, field := range f.Fields {
+		if field.Kind == sdk.FieldKindTime {
+			return i
+		}
+		if len(field.Values) > 0 {
+			if _, ok := field.Values[0].(time.Time); ok {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func asTime(v any) (time.Time, bool) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+func asNumber(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case []byte:
+		f, err := strconv.ParseFloat(string(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func formatNumber(f float64) string {
+	if math.Abs(f) >= 1e6 || (f != 0 && math.Abs(f) < 1e-2) {
+		return strconv.FormatFloat(f, 'g', 3, 64)
+	}
+	s := strconv.FormatFloat(f, 'f', 2, 64)
+	return strings.TrimRight(strings.TrimRight(s, "0"), ".")
+}
+
+// timeLayout picks how precisely axis times are printed for a span.
+func timeLayout(span time.Duration) string {
+	switch {
+	case span >= 72*time.Hour:
+		return "2006-01-02"
+	case span >= time.Hour:
+		return "2006-01-02 15:04"
+	default:
+		return "15:04:05"
+	}
+}
@@ -0,0 +1,156 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"time"
+
+	"data-voyager/sdk"
+)
+
+// maxTableRows bounds the rows a table shows; the rest are counted.
+const maxTableRows = 200
+
+// Document is what gets rendered: a title and a list of panels.
+type Document struct {
+	Title string
+	// Subtitle is shown under the title, e.g. the time range.
+	Subtitle string
+	Panels   []Panel
+	// At is when the data was read; zero means now.
+	At time.Time
+}
+
+// Panel is one query result. Results with a time column and numeric
+// columns are drawn as a line chart, everything else as a table.
+type Panel struct {
+	Title  string
+	Result *sdk.QueryResult
+	Error  string
+}
+
+type panelView struct {
+	Title string
+	Error string
+	Chart template.HTML
+	Table *tableView
+	Empty bool
+}
+
+type tableView struct {
+	Columns []string
+	Rows    [][]string
+	More    int
+}
+
+var page = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2933; margin: 24px; }
+h1 { font-size: 22px; margin: 0 0 4px; }
+.sub { color: #616e7c; font-size: 13px; margin-bottom: 20px; }
+.panel { border: 1px solid #d9e2ec; border-radius: 6px; padding: 12px 16px; margin-bottom: 16px; break-inside: avoid; }
+.panel h2 { font-size: 15px; margin: 0 0 10px; }
+.error { color: #ba2525; font-size: 13px; }
+.empty, .more { color: #616e7c; font-size: 12px; }
+table { border-collapse: collapse; font-size: 12px; width: 100%; }
+th, td { border-bottom: 1px solid #e4e7eb; padding: 4px 8px; text-align: left; white-space: nowrap; }
+th { background: #f5f7fa; }
+svg text { font-size: 11px; fill: #52606d; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+<div class="sub">{{ if .Subtitle }}{{ .Subtitle }} · {{ end }}Rendered {{ .At }}</div>
+{{ range .Panels }}<div class="panel">
+{{ if .Title }}<h2>{{ .Title }}</h2>{{ end }}
+{{ if .Error }}<div class="error">{{ .Error }}</div>
+{{ else if .Chart }}{{ .Chart }}
+{{ else if .Table }}<table>
+<tr>{{ range .Table.Columns }}<th>{{ . }}</th>{{ end }}</tr>
+{{ range .Table.Rows }}<tr>{{ range . }}<td>{{ . }}</td>{{ end }}</tr>
+{{ end }}</table>
+{{ if .Table.More }}<div class="more">{{ .Table.More }} more rows not shown</div>{{ end }}
+{{ else }}<div class="empty">No data</div>
+{{ end }}</div>
+{{ end }}</body>
+</html>
+`))
+
+// HTML renders doc as a standalone page: no scripts and no external
+// resources, so it prints the same anywhere.
+func HTML(doc *Document) ([]byte, error) {
+	at := doc.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+	data := struct {
+		Title    string
+		Subtitle string
+		At       string
+		Panels   []panelView
+	}{Title: doc.Title, Subtitle: doc.Subtitle, At: at.UTC().Format("2006-01-02 15:04 UTC")}
+	for _, p := range doc.Panels {
+		data.Panels = append(data.Panels, viewPanel(p))
+	}
+	var buf bytes.Buffer
+	if err := page.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func viewPanel(p Panel) panelView {
+	v := panelView{Title: p.Title, Error: p.Error}
+	if p.Error != "" || p.Result == nil || len(p.Result.Frames) == 0 {
+		return v
+	}
+	frames := p.Result.Frames
+	if chart, ok := lineChart(frames); ok {
+		v.Chart = chart
+		return v
+	}
+	v.Table = table(frames[0])
+	return v
+}
+
+// table lays out the first maxTableRows rows of f.
+func table(f *sdk.DataFrame) *tableView {
+	t := &tableView{}
+	n := 0
+	for _, field := range f.Fields {
+		t.Columns = append(t.Columns, field.Name)
+		n = max(n, len(field.Values))
+	}
+	shown := min(n, maxTableRows)
+	t.More = n - shown
+	for r := range shown {
+		row := make([]string, len(f.Fields))
+		for i, field := range f.Fields {
+			if r < len(field.Values) {
+				row[i] = cell(field.Values[r])
+			}
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
+
+func cell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,150 @@
+// Package render turns dashboards and query results into documents people
+// can read outside the app: self-contained HTML with SVG charts, built
+// here, and PNG or PDF, printed from that HTML by a headless browser
+// service.
+package render
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"data-voyager/core/internal/config"
+)
+
+// Formats.
+const (
+	FormatHTML = "html"
+	FormatPNG  = "png"
+	FormatPDF  = "pdf"
+)
+
+var (
+	// ErrUnavailable is returned for PNG and PDF when no rendering service
+	// is configured.
+	ErrUnavailable = errors.New("rendering service is not configured")
+	// ErrInvalid wraps unusable requests, e.g. an unknown format.
+	ErrInvalid = errors.New("invalid render request")
+)
+
+const (
+	defaultTimeout = 60 * time.Second
+	// pageWidth is the viewport, in CSS pixels, screenshots are taken at.
+	pageWidth = 1024
+	// maxOutput bounds what is read back from the rendering service.
+	maxOutput = 64 << 20
+)
+
+// ContentType returns the MIME type of a format.
+func ContentType(format string) string {
+	switch format {
+	case FormatPNG:
+		return "image/png"
+	case FormatPDF:
+		return "application/pdf"
+	default:
+		return "text/html; charset=utf-8"
+	}
+}
+
+// ValidFormat reports whether format is one Render accepts.
+func ValidFormat(format string) bool {
+	return format == FormatHTML || format == FormatPNG || format == FormatPDF
+}
+
+// Renderer renders documents. A Renderer without a service URL renders
+// HTML only.
+type Renderer struct {
+	url     string
+	timeout time.Duration
+	client  *http.Client
+}
+
+// New creates a Renderer for the configured service.
+func New(cfg config.RenderingConfig) *Renderer {
+	timeout := defaultTimeout
+	if cfg.Timeout > 0 {
+		timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+	return &Renderer{url: strings.TrimRight(cfg.URL, "/"), timeout: timeout, client: &http.Client{}}
+}
+
+// Enabled reports whether PNG and PDF can be rendered.
+func (r *Renderer) Enabled() bool { return r != nil && r.url != "" }
+
+// Render returns doc in format.
+func (r *Renderer) Render(ctx context.Context, doc *Document, format string) ([]byte, error) {
+	if !ValidFormat(format) {
+		return nil, fmt.Errorf("%w: unknown format %q", ErrInvalid, format)
+	}
+	html, err := HTML(doc)
+	if err != nil {
+		return nil, err
+	}
+	if format == FormatHTML {
+		return html, nil
+	}
+	if !r.Enabled() {
+		return nil, ErrUnavailable
+	}
+	return r.print(ctx, html, format)
+}
+
+// print sends html to the service's Chromium route for format: a
+// screenshot of the full page for PNG, a printout for PDF.
+func (r *Renderer) print(ctx context.Context, html []byte, format string) ([]byte, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("files", "index.html")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(html); err != nil {
+		return nil, err
+	}
+	route := "/forms/chromium/convert/html"
+	if format == FormatPNG {
+		route = "/forms/chromium/screenshot/html"
+		for k, v := range map[string]string{"format": "png", "width": fmt.Sprint(pageWidth)} {
+			if err := w.WriteField(k, v); err != nil {
+				return nil, err
+			}
+		}
+	} else if err := w.WriteField("printBackground", "true"); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url+route, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("rendering service: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	out, err := io.ReadAll(io.LimitReader(resp.Body, maxOutput))
+	if err != nil {
+		return nil, fmt.Errorf("rendering service: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg := strings.TrimSpace(string(out))
+		if len(msg) > 200 {
+			msg = msg[:200]
+		}
+		return nil, fmt.Errorf("rendering service answered %d: %s", resp.StatusCode, msg)
+	}
+	return out, nil
+}
@@ -0,0 +1,95 @@
+package render
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/sdk"
+)
+
+func sampleDoc() *Document {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &Document{
+		Title:    "Sales <Q1>",
+		Subtitle: "2024-01-01 – 2024-01-02",
+		At:       t0,
+		Panels: []Panel{
+			{Title: "Orders", Result: &sdk.QueryResult{Frames: []*sdk.DataFrame{{Fields: []sdk.Field{
+				{Name: "time", Kind: sdk.FieldKindTime, Values: []any{t0, t0.Add(time.Hour), t0.Add(2 * time.Hour)}},
+				{Name: "orders", Kind: sdk.FieldKindNumber, Values: []any{int64(3), int64(7), int64(5)}},
+			}}}}},
+			{Title: "Top regions", Result: &sdk.QueryResult{Frames: []*sdk.DataFrame{{Fields: []sdk.Field{
+				{Name: "region", Values: []any{"eu", "us"}},
+				{Name: "total", Values: []any{10.5, 3}},
+			}}}}},
+			{Title: "Broken", Error: "query failed: boom"},
+		},
+	}
+}
+
+func TestHTML(t *testing.T) {
+	out, err := HTML(sampleDoc())
+	require.NoError(t, err)
+	html := string(out)
+
+	assert.Contains(t, html, "<h1>Sales &lt;Q1&gt;</h1>")
+	assert.Contains(t, html, "Rendered 2024-01-01 00:00 UTC")
+	assert.Contains(t, html, "<polyline", "time series become a chart")
+	assert.Contains(t, html, ">orders</text>")
+	assert.Contains(t, html, "<td>eu</td><td>10.5</td>", "other results become a table")
+	assert.Contains(t, html, "query failed: boom")
+	assert.NotContains(t, html, "<script")
+}
+
+func TestRender_HTMLNeedsNoService(t *testing.T) {
+	r := New(config.RenderingConfig{})
+	assert.False(t, r.Enabled())
+	out, err := r.Render(context.Background(), sampleDoc(), FormatHTML)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(out), "<!DOCTYPE html>"))
+
+	_, err = r.Render(context.Background(), sampleDoc(), FormatPDF)
+	assert.ErrorIs(t, err, ErrUnavailable)
+	_, err = r.Render(context.Background(), sampleDoc(), "gif")
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestRender_Service(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		paths = append(paths, req.URL.Path)
+		f, _, err := req.FormFile("files")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		html, _ := io.ReadAll(f)
+		if !strings.Contains(string(html), "Sales") {
+			http.Error(w, "missing page", http.StatusBadRequest)
+			return
+		}
+		if req.URL.Path == "/forms/chromium/screenshot/html" && req.FormValue("format") != "png" {
+			http.Error(w, "bad format", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte("rendered"))
+	}))
+	defer srv.Close()
+
+	r := New(config.RenderingConfig{URL: srv.URL + "/"})
+	out, err := r.Render(context.Background(), sampleDoc(), FormatPDF)
+	require.NoError(t, err)
+	assert.Equal(t, "rendered", string(out))
+	_, err = r.Render(context.Background(), sampleDoc(), FormatPNG)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/forms/chromium/convert/html", "/forms/chromium/screenshot/html"}, paths)
+}
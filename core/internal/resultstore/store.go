@@ -0,0 +1,246 @@
+// Package resultstore keeps the results of async queries until they are
+// fetched or expire. Small results stay in memory; large ones are spilled,
+// gzipped, to object storage and read back only when a client asks for them.
+package resultstore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/objstore"
+)
+
+// Status is where a result is in its lifecycle.
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+var (
+	// ErrNotFound is returned for unknown and expired results.
+	ErrNotFound = errors.New("result not found")
+	// ErrNotReady is returned by Body while the query is still running.
+	ErrNotReady = errors.New("result not ready")
+)
+
+// Entry describes one async result. Its body is held by the Store.
+type Entry struct {
+	ID           string
+	DatasourceID string
+	// Owner is the username that submitted the query; empty when
+	// authentication is off.
+	Owner       string
+	Status      Status
+	CreatedAt   time.Time
+	CompletedAt time.Time
+	ExpiresAt   time.Time
+	// Code is the HTTP status the body was produced with.
+	Code int
+	// Size is the encoded body size in bytes.
+	Size int64
+	// Location is where a spilled body lives; empty while in memory.
+	Location string
+	// Request is the request that produced the result, kept so the query
+	// can be run again; nil when it cannot be.
+	Request []byte
+}
+
+type entry struct {
+	Entry
+	key  string // archive key once spilled
+	body []byte // in-memory body; nil once spilled
+}
+
+// Store holds async results.
+type Store struct {
+	mu        sync.Mutex
+	entries   map[string]*entry
+	archive   objstore.Store
+	threshold int64
+	ttl       time.Duration
+	now       func() time.Time
+}
+
+// New creates a Store. A nil archive keeps every result in memory.
+func New(cfg config.AsyncResultsConfig, archive objstore.Store) *Store {
+	return &Store{
+		entries:   map[string]*entry{},
+		archive:   archive,
+		threshold: int64(cfg.SpillThreshold) * 1024,
+		ttl:       time.Duration(cfg.TTL) * time.Minute,
+		now:       time.Now,
+	}
+}
+
+// Start registers a running query, submitted as request, and returns its
+// entry.
+func (s *Store) Start(datasourceID, owner string, request []byte) (Entry, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return Entry{}, err
+	}
+	now := s.now().UTC()
+	e := &entry{Entry: Entry{
+		ID:           id.String(),
+		DatasourceID: datasourceID,
+		Owner:        owner,
+		Request:      request,
+		Status:       StatusRunning,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(s.ttl),
+	}}
+	s.mu.Lock()
+	s.entries[e.ID] = e
+	s.mu.Unlock()
+	return e.Entry, nil
+}
+
+// Finish stores the body of a completed query. Bodies over the spill
+// threshold go to the archive; if that fails they are kept in memory rather
+// than lost. The TTL restarts from completion.
+func (s *Store) Finish(ctx context.Context, id string, status Status, code int, body []byte) {
+	key := "results/" + id + ".json.gz"
+	var location string
+	if s.archive != nil && int64(len(body)) > s.threshold {
+		if err := s.spill(ctx, key, body); err != nil {
+			slog.Warn("failed to spill async result, keeping it in memory", "id", id, "size", len(body), "err", err)
+		} else {
+			location = s.archive.Location(key)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[id]
+	if !ok {
+		return
+	}
+	now := s.now().UTC()
+	e.Status, e.Code, e.Size = status, code, int64(len(body))
+	e.CompletedAt, e.ExpiresAt = now, now.Add(s.ttl)
+	if location != "" {
+		e.key, e.Location = key, location
+	} else {
+		e.body = body
+	}
+}
+
+func (s *Store) spill(ctx context.Context, key string, body []byte) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return s.archive.Put(ctx, key, buf.Bytes(), "application/gzip")
+}
+
+// Get returns the entry for id.
+func (s *Store) Get(id string) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[id]
+	if !ok || s.expired(e) {
+		return Entry{}, ErrNotFound
+	}
+	return e.Entry, nil
+}
+
+// Body returns the stored body of a finished query, fetching it from the
+// archive when it was spilled.
+func (s *Store) Body(ctx context.Context, id string) ([]byte, error) {
+	s.mu.Lock()
+	e, ok := s.entries[id]
+	if !ok || s.expired(e) {
+		s.mu.Unlock()
+		return nil, ErrNotFound
+	}
+	if e.Status == StatusRunning {
+		s.mu.Unlock()
+		return nil, ErrNotReady
+	}
+	body, key := e.body, e.key
+	s.mu.Unlock()
+	if key == "" {
+		return body, nil
+	}
+
+	gz, err := s.archive.Get(ctx, key)
+	if errors.Is(err, objstore.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read spilled result: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(gz))
+	if err != nil {
+		return nil, fmt.Errorf("read spilled result: %w", err)
+	}
+	body, err = io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("read spilled result: %w", err)
+	}
+	return body, nil
+}
+
+// expired reports whether e is past its TTL. Running queries never expire.
+func (s *Store) expired(e *entry) bool {
+	return e.Status != StatusRunning && !s.now().Before(e.ExpiresAt)
+}
+
+// Sweep drops expired results and deletes their spilled objects. It returns
+// how many results were dropped.
+func (s *Store) Sweep(ctx context.Context) int {
+	s.mu.Lock()
+	var keys []string
+	n := 0
+	for id, e := range s.entries {
+		if !s.expired(e) {
+			continue
+		}
+		if e.key != "" {
+			keys = append(keys, e.key)
+		}
+		delete(s.entries, id)
+		n++
+	}
+	s.mu.Unlock()
+
+	for _, key := range keys {
+		if err := s.archive.Delete(ctx, key); err != nil {
+			slog.Warn("failed to delete expired async result", "key", key, "err", err)
+		}
+	}
+	return n
+}
+
+// Schedule sweeps every interval until ctx is cancelled.
+func (s *Store) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n := s.Sweep(ctx); n > 0 {
+				slog.Debug("expired async results", "count", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package resultstore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/objstore"
+)
+
+func newStore(t *testing.T, archive objstore.Store) *Store {
+	t.Helper()
+	return New(config.AsyncResultsConfig{SpillThreshold: 1, TTL: 10}, archive)
+}
+
+func TestFinish_SmallResultStaysInMemory(t *testing.T) {
+	dir := t.TempDir()
+	s := newStore(t, objstore.NewDir(dir))
+	e, err := s.Start("ds", "ann", nil)
+	require.NoError(t, err)
+
+	s.Finish(context.Background(), e.ID, StatusSucceeded, 200, []byte(`{"data":{}}`))
+
+	got, err := s.Get(e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusSucceeded, got.Status)
+	assert.Empty(t, got.Location)
+	body, err := s.Body(context.Background(), e.ID)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data":{}}`, string(body))
+}
+
+func TestFinish_SpillsAndSweeps(t *testing.T) {
+	dir := t.TempDir()
+	s := newStore(t, objstore.NewDir(dir))
+	e, err := s.Start("ds", "ann", nil)
+	require.NoError(t, err)
+	large := make([]byte, 4096)
+	for i := range large {
+		large[i] = 'x'
+	}
+
+	s.Finish(context.Background(), e.ID, StatusSucceeded, 200, large)
+
+	got, err := s.Get(e.ID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, got.Location)
+	assert.EqualValues(t, len(large), got.Size)
+	path := filepath.Join(dir, "results", e.ID+".json.gz")
+	require.FileExists(t, path)
+
+	body, err := s.Body(context.Background(), e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, large, body)
+
+	s.now = func() time.Time { return time.Now().Add(time.Hour) }
+	assert.Equal(t, 1, s.Sweep(context.Background()))
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+	_, err = s.Get(e.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+type failingStore struct{ objstore.Store }
+
+func (failingStore) Put(context.Context, string, []byte, string) error {
+	return errors.New("bucket unavailable")
+}
+
+func TestFinish_KeepsResultWhenSpillFails(t *testing.T) {
+	s := newStore(t, failingStore{})
+	e, err := s.Start("ds", "", nil)
+	require.NoError(t, err)
+
+	s.Finish(context.Background(), e.ID, StatusSucceeded, 200, make([]byte, 4096))
+
+	got, err := s.Get(e.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.Location)
+	body, err := s.Body(context.Background(), e.ID)
+	require.NoError(t, err)
+	assert.Len(t, body, 4096)
+}
+
+func TestBody_NotReadyWhileRunning(t *testing.T) {
+	s := newStore(t, nil)
+	e, err := s.Start("ds", "", nil)
+	require.NoError(t, err)
+
+	_, err = s.Body(context.Background(), e.ID)
+	assert.ErrorIs(t, err, ErrNotReady)
+
+	// Running queries never expire.
+	s.now = func() time.Time { return time.Now().Add(time.Hour) }
+	assert.Zero(t, s.Sweep(context.Background()))
+}
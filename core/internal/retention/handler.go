@@ -0,0 +1,36 @@
+package retention
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/user"
+)
+
+// Handler serves the retention report and manual prune trigger.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates a retention HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// Status handles GET /admin/retention
+func (h *Handler) Status(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.svc.Status(c.Request.Context())})
+}
+
+// Run handles POST /admin/retention/run
+func (h *Handler) Run(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.svc.Run(c.Request.Context())})
+}
+
+// RegisterRoutes wires the handler onto r.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	admin := r.Group("/admin", user.RequireAdmin)
+	admin.GET("/retention", h.Status)
+	admin.POST("/retention/run", h.Run)
+}
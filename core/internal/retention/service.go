@@ -0,0 +1,263 @@
+package retention
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/objstore"
+)
+
+const defaultBatchSize = 5000
+
+// Result is the outcome of pruning one table.
+type Result struct {
+	Store  string     `json:"store"`
+	Table  string     `json:"table"`
+	RanAt  time.Time  `json:"ran_at"`
+	Cutoff *time.Time `json:"cutoff,omitempty"`
+	// Deleted counts rows removed; Archived those exported beforehand.
+	Deleted  int64    `json:"deleted"`
+	Archived int64    `json:"archived"`
+	Objects  []string `json:"objects,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// TableStatus reports the size of one table and its most recent prune.
+type TableStatus struct {
+	Store   string                  `json:"store"`
+	Table   string                  `json:"table"`
+	Rows    int64                   `json:"rows"`
+	Oldest  *time.Time              `json:"oldest,omitempty"`
+	Policy  *config.RetentionPolicy `json:"policy,omitempty"`
+	LastRun *Result                 `json:"last_run,omitempty"`
+	Error   string                  `json:"error,omitempty"`
+}
+
+// Service prunes and reports on a fixed set of tables.
+type Service struct {
+	tables   []Table
+	policies map[string]config.RetentionPolicy
+	archive  objstore.Store
+	batch    int
+	now      func() time.Time
+
+	// runMu serialises prune passes; mu guards last.
+	runMu sync.Mutex
+	mu    sync.Mutex
+	last  map[string]*Result
+}
+
+// NewService creates a Service. archive may be nil when no policy archives.
+func NewService(tables []Table, cfg config.RetentionConfig, archive objstore.Store) *Service {
+	batch := cfg.BatchSize
+	if batch <= 0 {
+		batch = defaultBatchSize
+	}
+	return &Service{
+		tables:   tables,
+		policies: cfg.Policies,
+		archive:  archive,
+		batch:    batch,
+		now:      time.Now,
+		last:     map[string]*Result{},
+	}
+}
+
+func tableKey(t Table) string { return t.Store + "." + t.Name }
+
+// Status reports every table's size. A table that cannot be read is listed
+// with its error rather than failing the whole report.
+func (s *Service) Status(ctx context.Context) []TableStatus {
+	out := make([]TableStatus, 0, len(s.tables))
+	for _, t := range s.tables {
+		st := TableStatus{Store: t.Store, Table: t.Name}
+		if p, ok := s.policies[t.Name]; ok {
+			st.Policy = &p
+		}
+		s.mu.Lock()
+		if r := s.last[tableKey(t)]; r != nil {
+			cp := *r
+			st.LastRun = &cp
+		}
+		s.mu.Unlock()
+		n, err := t.count(ctx)
+		if err == nil {
+			st.Rows = n
+			var oldest time.Time
+			var ok bool
+			if oldest, ok, err = t.oldest(ctx); ok {
+				st.Oldest = &oldest
+			}
+		}
+		if err != nil {
+			st.Error = err.Error()
+		}
+		out = append(out, st)
+	}
+	return out
+}
+
+// Run prunes every table that has a policy.
+func (s *Service) Run(ctx context.Context) []Result {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+	var out []Result
+	for _, t := range s.tables {
+		p, ok := s.policies[t.Name]
+		if !ok || (p.MaxAgeDays == 0 && p.MaxRows == 0) {
+			continue
+		}
+		r := s.prune(ctx, t, p)
+		if r.Error != "" {
+			slog.Warn("retention prune failed", "table", t.Name, "err", r.Error)
+		} else if r.Deleted > 0 {
+			slog.Info("retention pruned rows", "table", t.Name, "deleted", r.Deleted, "archived", r.Archived)
+		}
+		s.mu.Lock()
+		s.last[tableKey(t)] = &r
+		s.mu.Unlock()
+		out = append(out, r)
+	}
+	return out
+}
+
+// Schedule runs Run every interval until ctx is cancelled.
+func (s *Service) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s.Run(ctx)
+	}
+}
+
+func (s *Service) prune(ctx context.Context, t Table, p config.RetentionPolicy) Result {
+	r := Result{Store: t.Store, Table: t.Name, RanAt: s.now().UTC()}
+	cutoff, at, err := s.cutoff(ctx, t, p)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	if cutoff == nil {
+		return r
+	}
+	r.Cutoff = &at
+	if !p.Archive {
+		r.Deleted, err = t.deleteWhere(ctx, "<", cutoff)
+	} else {
+		err = s.archiveAndDelete(ctx, t, cutoff, &r)
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	return r
+}
+
+// cutoff resolves the policy to a raw timestamp below which rows go. A
+// row-count limit cuts at the n-th newest row's own stored value so ties
+// with it are kept.
+func (s *Service) cutoff(ctx context.Context, t Table, p config.RetentionPolicy) (any, time.Time, error) {
+	var (
+		raw any
+		at  time.Time
+	)
+	if p.MaxAgeDays > 0 {
+		at = s.now().UTC().AddDate(0, 0, -p.MaxAgeDays)
+		raw = t.arg(at)
+	}
+	if p.MaxRows > 0 {
+		v, err := t.nthNewest(ctx, p.MaxRows)
+		if err != nil {
+			return nil, at, err
+		}
+		if ts, ok := asTime(v); ok && (raw == nil || ts.After(at)) {
+			raw, at = v, ts
+		}
+	}
+	return raw, at, nil
+}
+
+// archiveAndDelete exports expired rows batch by batch, deleting each batch
+// only once its archive object is written. A batch always ends on a whole
+// timestamp so the delete never removes a row that was not archived.
+func (s *Service) archiveAndDelete(ctx context.Context, t Table, cutoff any, r *Result) error {
+	if s.archive == nil {
+		return fmt.Errorf("no archive store configured")
+	}
+	for seq := 0; ; seq++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rows, err := t.expired(ctx, cutoff, s.batch)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		last := t.param(rows[len(rows)-1][t.TimeColumn])
+		full := len(rows) == s.batch
+		if full {
+			for len(rows) > 0 && sameTime(t.param(rows[len(rows)-1][t.TimeColumn]), last) {
+				rows = rows[:len(rows)-1]
+			}
+			ties, err := t.at(ctx, last)
+			if err != nil {
+				return err
+			}
+			rows = append(rows, ties...)
+		}
+
+		key, err := s.writeArchive(ctx, t, r.RanAt, seq, rows)
+		if err != nil {
+			return err
+		}
+		r.Objects = append(r.Objects, s.archive.Location(key))
+		r.Archived += int64(len(rows))
+
+		n, err := t.deleteWhere(ctx, "<=", last)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			// Drivers that don't report affected rows (ClickHouse).
+			n = int64(len(rows))
+		}
+		r.Deleted += n
+		if !full {
+			return nil
+		}
+	}
+}
+
+// writeArchive stores rows as gzipped JSON lines and returns the object key.
+func (s *Service) writeArchive(ctx context.Context, t Table, ranAt time.Time, seq int, rows []Row) (string, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(zw)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			return "", fmt.Errorf("encode %s row: %w", t.Name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	key := fmt.Sprintf("%s/%s/%s/%s-%s-%04d.jsonl.gz",
+		t.Store, t.Name, ranAt.Format("2006/01/02"), t.Name, ranAt.Format("20060102T150405Z"), seq)
+	if err := s.archive.Put(ctx, key, buf.Bytes(), "application/gzip"); err != nil {
+		return "", fmt.Errorf("archive %s: %w", t.Name, err)
+	}
+	return key, nil
+}
@@ -0,0 +1,120 @@
+package retention
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/objstore"
+)
+
+const layout = "2006-01-02 15:04:05"
+
+var now = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// newTable creates an events table with one row per offset (in days before
+// now).
+func newTable(t *testing.T, daysAgo ...int) Table {
+	t.Helper()
+	db, err := sqlx.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	_, err = db.Exec(`CREATE TABLE events (id TEXT PRIMARY KEY, at DATETIME NOT NULL)`)
+	require.NoError(t, err)
+	for i, d := range daysAgo {
+		_, err = db.Exec(`INSERT INTO events VALUES (?, ?)`, fmt.Sprintf("e%d", i), now.AddDate(0, 0, -d).Format(layout))
+		require.NoError(t, err)
+	}
+	return Table{Store: StoreStatistics, Name: "events", TimeColumn: "at", DB: db,
+		Format: func(ts time.Time) any { return ts.Format(layout) }}
+}
+
+func newService(tbl Table, p config.RetentionPolicy, batch int, archive objstore.Store) *Service {
+	svc := NewService([]Table{tbl}, config.RetentionConfig{
+		BatchSize: batch,
+		Policies:  map[string]config.RetentionPolicy{"events": p},
+	}, archive)
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func remaining(t *testing.T, tbl Table) []string {
+	t.Helper()
+	var ids []string
+	require.NoError(t, tbl.DB.Select(&ids, `SELECT id FROM events ORDER BY id`))
+	return ids
+}
+
+func TestRun_MaxAge(t *testing.T) {
+	tbl := newTable(t, 1, 10, 40, 90)
+	res := newService(tbl, config.RetentionPolicy{MaxAgeDays: 30}, 0, nil).Run(context.Background())
+	require.Len(t, res, 1)
+	assert.Empty(t, res[0].Error)
+	assert.EqualValues(t, 2, res[0].Deleted)
+	assert.Equal(t, now.AddDate(0, 0, -30), *res[0].Cutoff)
+	assert.Equal(t, []string{"e0", "e1"}, remaining(t, tbl))
+}
+
+func TestRun_MaxRowsKeepsTies(t *testing.T) {
+	// e1 and e2 share a timestamp; keeping two rows keeps both.
+	tbl := newTable(t, 1, 5, 5, 9, 20)
+	res := newService(tbl, config.RetentionPolicy{MaxRows: 2, MaxAgeDays: 365}, 0, nil).Run(context.Background())
+	assert.EqualValues(t, 2, res[0].Deleted)
+	assert.Equal(t, []string{"e0", "e1", "e2"}, remaining(t, tbl))
+}
+
+func TestRun_ArchivesBeforeDeleting(t *testing.T) {
+	dir := t.TempDir()
+	// Batches of two must not split the three rows stamped 50 days ago.
+	tbl := newTable(t, 1, 50, 50, 50, 60, 70)
+	svc := newService(tbl, config.RetentionPolicy{MaxAgeDays: 30, Archive: true}, 2, objstore.NewDir(dir))
+	res := svc.Run(context.Background())
+	require.Empty(t, res[0].Error)
+	assert.EqualValues(t, 5, res[0].Deleted)
+	assert.EqualValues(t, 5, res[0].Archived)
+	assert.Equal(t, []string{"e0"}, remaining(t, tbl))
+
+	var archived []string
+	for _, obj := range res[0].Objects {
+		assert.Contains(t, obj, filepath.FromSlash("statistics/events/2024/06/01/"))
+		f, err := os.Open(obj)
+		require.NoError(t, err)
+		zr, err := gzip.NewReader(f)
+		require.NoError(t, err)
+		sc := bufio.NewScanner(zr)
+		for sc.Scan() {
+			var row map[string]any
+			require.NoError(t, json.Unmarshal(sc.Bytes(), &row))
+			archived = append(archived, row["id"].(string))
+		}
+		_ = f.Close()
+	}
+	assert.ElementsMatch(t, []string{"e1", "e2", "e3", "e4", "e5"}, archived)
+
+	st := svc.Status(context.Background())
+	require.Len(t, st, 1)
+	assert.EqualValues(t, 1, st[0].Rows)
+	assert.Equal(t, now.AddDate(0, 0, -1), *st[0].Oldest)
+	require.NotNil(t, st[0].LastRun)
+	assert.EqualValues(t, 5, st[0].LastRun.Deleted)
+}
+
+func TestRun_ArchiveWithoutStoreKeepsRows(t *testing.T) {
+	tbl := newTable(t, 40)
+	res := newService(tbl, config.RetentionPolicy{MaxAgeDays: 30, Archive: true}, 0, nil).Run(context.Background())
+	assert.NotEmpty(t, res[0].Error)
+	assert.Equal(t, []string{"e0"}, remaining(t, tbl))
+}
@@ -0,0 +1,153 @@
+// Package retention keeps history and run tables from growing without
+// bound. A periodic pass deletes rows past each table's age and row-count
+// limits, optionally exporting them to an archive store first, and reports
+// table sizes so operators can tune the limits.
+package retention
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Store names.
+const (
+	StoreMetadata   = "metadata"
+	StoreStatistics = "statistics"
+)
+
+// Table is a table whose rows age out by a timestamp column. Name and
+// TimeColumn are trusted identifiers supplied by the store packages.
+type Table struct {
+	Store      string
+	Name       string
+	TimeColumn string
+	DB         *sqlx.DB
+	// Format converts a cutoff to the column's stored representation; nil
+	// passes time.Time through to the driver.
+	Format func(time.Time) any
+}
+
+// Row is one table row keyed by column name.
+type Row map[string]any
+
+func (t Table) arg(ts time.Time) any {
+	if t.Format == nil {
+		return ts
+	}
+	return t.Format(ts.UTC())
+}
+
+// param turns a timestamp read from the table back into a query argument.
+// Drivers may parse text columns into time.Time, which would not compare
+// equal to the stored text when passed back unformatted.
+func (t Table) param(v any) any {
+	if ts, ok := asTime(v); ok {
+		return t.arg(ts)
+	}
+	return v
+}
+
+func (t Table) count(ctx context.Context) (int64, error) {
+	var n int64
+	if err := t.DB.GetContext(ctx, &n, "SELECT COUNT(*) FROM "+t.Name); err != nil {
+		return 0, fmt.Errorf("count %s: %w", t.Name, err)
+	}
+	return n, nil
+}
+
+// oldest returns the earliest timestamp, or ok=false for an empty table.
+func (t Table) oldest(ctx context.Context) (time.Time, bool, error) {
+	rows, err := t.selectRows(ctx, fmt.Sprintf("SELECT MIN(%s) AS ts FROM %s", t.TimeColumn, t.Name))
+	if err != nil || len(rows) == 0 {
+		return time.Time{}, false, err
+	}
+	ts, ok := asTime(rows[0]["ts"])
+	return ts, ok, nil
+}
+
+// nthNewest returns the raw timestamp of the n-th newest row (1-based), or
+// nil when the table has fewer rows.
+func (t Table) nthNewest(ctx context.Context, n int) (any, error) {
+	rows, err := t.selectRows(ctx, t.DB.Rebind(fmt.Sprintf(
+		"SELECT %[1]s AS ts FROM %[2]s ORDER BY %[1]s DESC LIMIT 1 OFFSET ?", t.TimeColumn, t.Name)), n-1)
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	return t.param(rows[0]["ts"]), nil
+}
+
+// expired returns up to limit rows older than cutoff, oldest first.
+func (t Table) expired(ctx context.Context, cutoff any, limit int) ([]Row, error) {
+	return t.selectRows(ctx, t.DB.Rebind(fmt.Sprintf(
+		"SELECT * FROM %[2]s WHERE %[1]s < ? ORDER BY %[1]s LIMIT ?", t.TimeColumn, t.Name)), cutoff, limit)
+}
+
+// at returns every row stamped exactly ts.
+func (t Table) at(ctx context.Context, ts any) ([]Row, error) {
+	return t.selectRows(ctx, t.DB.Rebind(fmt.Sprintf(
+		"SELECT * FROM %s WHERE %s = ?", t.Name, t.TimeColumn)), ts)
+}
+
+func (t Table) deleteWhere(ctx context.Context, op string, ts any) (int64, error) {
+	res, err := t.DB.ExecContext(ctx, t.DB.Rebind(fmt.Sprintf(
+		"DELETE FROM %s WHERE %s %s ?", t.Name, t.TimeColumn, op)), ts)
+	if err != nil {
+		return 0, fmt.Errorf("delete from %s: %w", t.Name, err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+func (t Table) selectRows(ctx context.Context, query string, args ...any) ([]Row, error) {
+	rs, err := t.DB.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query %s: %w", t.Name, err)
+	}
+	defer func() { _ = rs.Close() }()
+	var out []Row
+	for rs.Next() {
+		m := map[string]any{}
+		if err := rs.MapScan(m); err != nil {
+			return nil, fmt.Errorf("scan %s: %w", t.Name, err)
+		}
+		for k, v := range m {
+			if b, ok := v.([]byte); ok {
+				m[k] = string(b)
+			}
+		}
+		out = append(out, m)
+	}
+	return out, rs.Err()
+}
+
+// timeLayouts are the text encodings the SQLite stores use.
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02 15:04:05.999999999-07:00"}
+
+// asTime reads a timestamp as returned by any of the store drivers.
+func asTime(v any) (time.Time, bool) {
+	switch x := v.(type) {
+	case time.Time:
+		return x.UTC(), true
+	case string:
+		for _, l := range timeLayouts {
+			if ts, err := time.Parse(l, strings.TrimSpace(x)); err == nil {
+				return ts.UTC(), true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// sameTime reports whether two raw timestamps are equal.
+func sameTime(a, b any) bool {
+	ta, okA := a.(time.Time)
+	tb, okB := b.(time.Time)
+	if okA && okB {
+		return ta.Equal(tb)
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
@@ -0,0 +1,196 @@
+package rowedit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/user"
+)
+
+// Handler serves the row editing endpoints.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates a row editing HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// Tables handles GET /editable-tables
+func (h *Handler) Tables(c *gin.Context) {
+	if !h.allowed(c, identity.PermDatasourceRead) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": h.svc.Tables(c.Request.Context())})
+}
+
+// Describe handles GET /editable-tables/:name
+func (h *Handler) Describe(c *gin.Context) {
+	if !h.allowed(c, identity.PermDatasourceRead) {
+		return
+	}
+	t, err := h.svc.Describe(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": t})
+}
+
+// Get handles GET /editable-tables/:name/row?<key column>=<value>...
+func (h *Handler) Get(c *gin.Context) {
+	if !h.allowed(c, identity.PermDatasourceRead) {
+		return
+	}
+	row, err := h.svc.Get(c.Request.Context(), c.Param("name"), queryKey(c))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": row})
+}
+
+// Insert handles POST /editable-tables/:name/row with the row's values as a
+// JSON object.
+func (h *Handler) Insert(c *gin.Context) {
+	if !h.allowed(c, identity.PermDatasourceWrite) {
+		return
+	}
+	values, ok := h.bindValues(c)
+	if !ok {
+		return
+	}
+	row, err := h.svc.Insert(c.Request.Context(), c.Param("name"), values)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": row})
+}
+
+// Update handles PATCH /editable-tables/:name/row?<key column>=<value>...
+// with the changed values as a JSON object.
+func (h *Handler) Update(c *gin.Context) {
+	if !h.allowed(c, identity.PermDatasourceWrite) {
+		return
+	}
+	values, ok := h.bindValues(c)
+	if !ok {
+		return
+	}
+	row, err := h.svc.Update(c.Request.Context(), c.Param("name"), queryKey(c), values)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": row})
+}
+
+// Delete handles DELETE /editable-tables/:name/row?<key column>=<value>...
+func (h *Handler) Delete(c *gin.Context) {
+	if !h.allowed(c, identity.PermDatasourceWrite) {
+		return
+	}
+	if err := h.svc.Delete(c.Request.Context(), c.Param("name"), queryKey(c)); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Audit handles GET /admin/row-edit-audit?table=NAME&user=NAME&from=T&to=T&limit=N
+func (h *Handler) Audit(c *gin.Context) {
+	f := AuditFilter{Table: c.Query("table"), Actor: c.Query("user")}
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &f.From}, {"to", &f.To}} {
+		v := c.Query(p.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": p.name + " must be an RFC 3339 time"})
+			return
+		}
+		*p.dst = t
+	}
+	f.Limit, _ = strconv.Atoi(c.Query("limit"))
+	if f.Limit <= 0 || f.Limit > 10000 {
+		f.Limit = 1000
+	}
+	list, err := h.svc.Audit(c.Request.Context(), f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// allowed checks perm, refusing every change from a demo visitor.
+func (h *Handler) allowed(c *gin.Context, perm string) bool {
+	caller := identity.FromContext(c.Request.Context())
+	if !caller.Can(perm) || (perm != identity.PermDatasourceRead && caller != nil && caller.Demo) {
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "permission denied")})
+		return false
+	}
+	return true
+}
+
+// bindValues reads a JSON object of column values, keeping numbers exact.
+func (h *Handler) bindValues(c *gin.Context) (map[string]any, bool) {
+	dec := json.NewDecoder(c.Request.Body)
+	dec.UseNumber()
+	var values map[string]any
+	if err := dec.Decode(&values); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return values, true
+}
+
+// queryKey returns the query parameters as key column values.
+func queryKey(c *gin.Context) map[string]string {
+	key := map[string]string{}
+	for name, values := range c.Request.URL.Query() {
+		key[name] = values[0]
+	}
+	return key
+}
+
+func (h *Handler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrUnsupported):
+		c.JSON(http.StatusNotImplemented, gin.H{"error": i18n.T(c, "row editing is not supported for this datasource")})
+	case errors.Is(err, connection.ErrNetworkPolicy):
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "this datasource cannot be used from your network")})
+	default:
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	}
+}
+
+// RegisterRoutes wires the handler onto r. The audit requires the admin
+// permission.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/editable-tables", h.Tables)
+	r.GET("/editable-tables/:name", h.Describe)
+	r.GET("/editable-tables/:name/row", h.Get)
+	r.POST("/editable-tables/:name/row", h.Insert)
+	r.PATCH("/editable-tables/:name/row", h.Update)
+	r.DELETE("/editable-tables/:name/row", h.Delete)
+	r.GET("/admin/row-edit-audit", user.RequireAdmin, h.Audit)
+}
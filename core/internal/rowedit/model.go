@@ -0,0 +1,84 @@
+package rowedit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"data-voyager/sdk"
+)
+
+// Op is a kind of row edit.
+type Op string
+
+const (
+	OpInsert Op = "insert"
+	OpUpdate Op = "update"
+	OpDelete Op = "delete"
+)
+
+var (
+	// ErrInvalid wraps validation failures so handlers can map them to 400.
+	ErrInvalid = errors.New("invalid row edit")
+	// ErrNotFound is returned for unknown tables and rows.
+	ErrNotFound = errors.New("row not found")
+	// ErrUnsupported is returned when the datasource cannot take
+	// parameterized writes.
+	ErrUnsupported = errors.New("row editing is not supported for this datasource")
+)
+
+// Table describes an editable table.
+type Table struct {
+	Name         string `json:"name"`
+	DatasourceID string `json:"datasource_id"`
+	Schema       string `json:"schema,omitempty"`
+	Table        string `json:"table"`
+	// Key lists the columns that address a row.
+	Key []string `json:"key"`
+	// Columns is filled in when a single table is described.
+	Columns []Column `json:"columns,omitempty"`
+}
+
+// Column is a column of an editable table.
+type Column struct {
+	Name     string        `json:"name"`
+	Type     string        `json:"type"`
+	Kind     sdk.FieldKind `json:"kind"`
+	Nullable bool          `json:"nullable"`
+	Key      bool          `json:"key"`
+	// Editable is false for key columns and columns left out of the
+	// table's editable list.
+	Editable bool `json:"editable"`
+}
+
+// AuditRecord is one row edit. Before and After are the whole row as read
+// around the edit; Before is empty for inserts and After for deletes.
+type AuditRecord struct {
+	ID           string         `json:"id"`
+	Table        string         `json:"table"`
+	DatasourceID string         `json:"datasource_id"`
+	Schema       string         `json:"schema,omitempty"`
+	TableName    string         `json:"table_name"`
+	Op           Op             `json:"op"`
+	Key          map[string]any `json:"key"`
+	Before       map[string]any `json:"before,omitempty"`
+	After        map[string]any `json:"after,omitempty"`
+	// Actor is who made the edit; empty when authentication is off.
+	Actor string    `json:"actor"`
+	At    time.Time `json:"at"`
+}
+
+// AuditFilter narrows an audit report. Zero fields match everything.
+type AuditFilter struct {
+	Table    string
+	Actor    string
+	From, To time.Time
+	Limit    int
+}
+
+// AuditRepository persists the row edit audit.
+type AuditRepository interface {
+	Record(ctx context.Context, r *AuditRecord) error
+	// List returns the newest records first.
+	List(ctx context.Context, f AuditFilter) ([]*AuditRecord, error)
+}
@@ -0,0 +1,478 @@
+package rowedit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/identity"
+	qb "data-voyager/core/internal/query_builder"
+	"data-voyager/sdk"
+)
+
+// timeLayouts are the forms accepted for date and time columns.
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"}
+
+// Service edits single rows of the configured tables and audits each edit.
+type Service struct {
+	tables   []config.EditableTable
+	conns    connection.Repository
+	registry *datasource.Registry
+	audit    AuditRepository // see WithAudit
+	now      func() time.Time
+}
+
+// NewService creates a Service for cfg's tables.
+func NewService(cfg config.RowEditingConfig, conns connection.Repository, registry *datasource.Registry) *Service {
+	return &Service{tables: cfg.Tables, conns: conns, registry: registry, now: time.Now}
+}
+
+// WithAudit records every edit in repo.
+func (s *Service) WithAudit(repo AuditRepository) *Service {
+	s.audit = repo
+	return s
+}
+
+// Tables lists the editable tables on datasources the caller can see.
+func (s *Service) Tables(ctx context.Context) []Table {
+	caller := identity.FromContext(ctx)
+	out := []Table{}
+	for _, t := range s.tables {
+		if caller.CanSee(t.DatasourceID) {
+			out = append(out, Table{Name: t.Name, DatasourceID: t.DatasourceID, Schema: t.Schema, Table: t.Table, Key: t.Key})
+		}
+	}
+	return out
+}
+
+// Describe returns a table with its columns as the datasource reports them.
+func (s *Service) Describe(ctx context.Context, name string) (*Table, error) {
+	e, err := s.open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer e.close()
+	t := &Table{Name: e.tbl.Name, DatasourceID: e.tbl.DatasourceID, Schema: e.tbl.Schema, Table: e.tbl.Table, Key: e.tbl.Key}
+	for _, c := range e.columns {
+		t.Columns = append(t.Columns, Column{
+			Name: c.Name, Type: c.Type, Kind: sdk.InferFieldKind(c.Type), Nullable: c.Nullable,
+			Key: slices.Contains(e.tbl.Key, c.Name), Editable: e.editable(c.Name),
+		})
+	}
+	return t, nil
+}
+
+// Get returns the row key addresses. Key values are given as text, as they
+// arrive in a URL.
+func (s *Service) Get(ctx context.Context, name string, key map[string]string) (map[string]any, error) {
+	e, err := s.open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer e.close()
+	k, err := e.key(key)
+	if err != nil {
+		return nil, err
+	}
+	return e.get(ctx, k)
+}
+
+// Insert adds a row. values must include the key; the row is returned as
+// read back after the insert.
+func (s *Service) Insert(ctx context.Context, name string, values map[string]any) (map[string]any, error) {
+	e, err := s.open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer e.close()
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%w: no values given", ErrInvalid)
+	}
+	key := map[string]any{}
+	for _, k := range e.tbl.Key {
+		v, ok := values[k]
+		if !ok || v == nil {
+			return nil, fmt.Errorf("%w: key column %s is required", ErrInvalid, k)
+		}
+		key[k] = v
+	}
+	cols, args, err := e.values(values, true)
+	if err != nil {
+		return nil, err
+	}
+	if key, err = e.coerceAll(key); err != nil {
+		return nil, err
+	}
+	marks := make([]string, len(cols))
+	for i := range cols {
+		marks[i] = e.dialect.Placeholder(i + 1)
+		cols[i] = e.dialect.QuoteIdent(cols[i])
+	}
+	stmt := "INSERT INTO " + e.qualified() + " (" + strings.Join(cols, ", ") + ") VALUES (" + strings.Join(marks, ", ") + ")"
+	if _, err := e.conn.Query(ctx, stmt, args...); err != nil {
+		return nil, fmt.Errorf("insert failed: %w", err)
+	}
+	after, err := e.get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	s.record(ctx, e, OpInsert, key, nil, after)
+	return after, nil
+}
+
+// Update changes the given columns of the row key addresses and returns the
+// row as read back after the update.
+func (s *Service) Update(ctx context.Context, name string, key map[string]string, values map[string]any) (map[string]any, error) {
+	e, err := s.open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer e.close()
+	k, err := e.key(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%w: no values given", ErrInvalid)
+	}
+	cols, args, err := e.values(values, false)
+	if err != nil {
+		return nil, err
+	}
+	before, err := e.get(ctx, k)
+	if err != nil {
+		return nil, err
+	}
+	sets := make([]string, len(cols))
+	for i, c := range cols {
+		sets[i] = e.dialect.QuoteIdent(c) + " = " + e.dialect.Placeholder(i+1)
+	}
+	where, whereArgs := e.where(k, len(args))
+	stmt := "UPDATE " + e.qualified() + " SET " + strings.Join(sets, ", ") + " WHERE " + where
+	if _, err := e.conn.Query(ctx, stmt, append(args, whereArgs...)...); err != nil {
+		return nil, fmt.Errorf("update failed: %w", err)
+	}
+	after, err := e.get(ctx, k)
+	if err != nil {
+		return nil, err
+	}
+	s.record(ctx, e, OpUpdate, k, before, after)
+	return after, nil
+}
+
+// Delete removes the row key addresses.
+func (s *Service) Delete(ctx context.Context, name string, key map[string]string) error {
+	e, err := s.open(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer e.close()
+	k, err := e.key(key)
+	if err != nil {
+		return err
+	}
+	before, err := e.get(ctx, k)
+	if err != nil {
+		return err
+	}
+	where, args := e.where(k, 0)
+	if _, err := e.conn.Query(ctx, "DELETE FROM "+e.qualified()+" WHERE "+where, args...); err != nil {
+		return fmt.Errorf("delete failed: %w", err)
+	}
+	s.record(ctx, e, OpDelete, k, before, nil)
+	return nil
+}
+
+// Audit returns the audit records matching f.
+func (s *Service) Audit(ctx context.Context, f AuditFilter) ([]*AuditRecord, error) {
+	if s.audit == nil {
+		return []*AuditRecord{}, nil
+	}
+	return s.audit.List(ctx, f)
+}
+
+// record adds an edit to the audit. The edit has already happened, so a
+// failure is logged rather than returned.
+func (s *Service) record(ctx context.Context, e *editor, op Op, key, before, after map[string]any) {
+	var actor string
+	if caller := identity.FromContext(ctx); caller != nil {
+		actor = caller.Username
+	}
+	slog.Info("row edited", "table", e.tbl.Name, "op", op, "key", key, "by", actor)
+	if s.audit == nil {
+		return
+	}
+	err := s.audit.Record(ctx, &AuditRecord{
+		ID:           uuid.NewString(),
+		Table:        e.tbl.Name,
+		DatasourceID: e.tbl.DatasourceID,
+		Schema:       e.tbl.Schema,
+		TableName:    e.tbl.Table,
+		Op:           op,
+		Key:          key,
+		Before:       before,
+		After:        after,
+		Actor:        actor,
+		At:           s.now().UTC(),
+	})
+	if err != nil {
+		slog.Error("failed to record row edit audit", "table", e.tbl.Name, "op", op, "err", err)
+	}
+}
+
+// editor is an open connection to one editable table.
+type editor struct {
+	tbl     config.EditableTable
+	conn    sdk.Connection
+	dialect qb.Dialect
+	columns []sdk.ColumnInfo // in table order
+}
+
+// open connects to the datasource of the named table and reads its columns.
+func (s *Service) open(ctx context.Context, name string) (*editor, error) {
+	i := slices.IndexFunc(s.tables, func(t config.EditableTable) bool { return t.Name == name })
+	if i < 0 || !identity.FromContext(ctx).CanSee(s.tables[i].DatasourceID) {
+		return nil, fmt.Errorf("%w: table %q", ErrNotFound, name)
+	}
+	tbl := s.tables[i]
+	conn, err := s.conns.GetByID(ctx, tbl.DatasourceID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: datasource %q not found", ErrInvalid, tbl.DatasourceID)
+	}
+	if err := connection.CheckOrigin(ctx, conn); err != nil {
+		return nil, err
+	}
+	plugin, ok := s.registry.Get(conn.Type)
+	if !ok {
+		return nil, fmt.Errorf("%w: plugin not found for type %s", ErrInvalid, conn.Type)
+	}
+	dialect := qb.DialectFor(string(conn.Type))
+	if caps := sdk.PluginCapabilities(plugin); !caps.Writes || dialect.Placeholder == nil {
+		return nil, ErrUnsupported
+	}
+	cfg, err := plugin.ParseConfig(conn.Config)
+	if err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	dbConn, err := plugin.Connect(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	e := &editor{tbl: tbl, conn: dbConn, dialect: dialect}
+	tables, err := dbConn.GetTables(ctx, tbl.Schema)
+	if err != nil {
+		e.close()
+		return nil, fmt.Errorf("read columns: %w", err)
+	}
+	for _, t := range tables {
+		if t.Name == tbl.Table {
+			e.columns = t.Columns
+		}
+	}
+	for _, k := range tbl.Key {
+		if _, ok := e.column(k); !ok {
+			e.close()
+			return nil, fmt.Errorf("%w: table %s has no key column %s", ErrInvalid, tbl.Table, k)
+		}
+	}
+	return e, nil
+}
+
+func (e *editor) close() { _ = e.conn.Close() }
+
+func (e *editor) qualified() string { return e.dialect.QualifiedTable(e.tbl.Schema, e.tbl.Table) }
+
+func (e *editor) column(name string) (sdk.ColumnInfo, bool) {
+	i := slices.IndexFunc(e.columns, func(c sdk.ColumnInfo) bool { return c.Name == name })
+	if i < 0 {
+		return sdk.ColumnInfo{}, false
+	}
+	return e.columns[i], true
+}
+
+func (e *editor) editable(name string) bool {
+	if slices.Contains(e.tbl.Key, name) {
+		return false
+	}
+	return len(e.tbl.Columns) == 0 || slices.Contains(e.tbl.Columns, name)
+}
+
+// key converts text key values to their columns' types.
+func (e *editor) key(text map[string]string) (map[string]any, error) {
+	raw := make(map[string]any, len(e.tbl.Key))
+	for _, k := range e.tbl.Key {
+		v, ok := text[k]
+		if !ok {
+			return nil, fmt.Errorf("%w: key column %s is required", ErrInvalid, k)
+		}
+		raw[k] = v
+	}
+	return e.coerceAll(raw)
+}
+
+func (e *editor) coerceAll(values map[string]any) (map[string]any, error) {
+	out := make(map[string]any, len(values))
+	for name, v := range values {
+		col, _ := e.column(name)
+		c, err := coerce(col, v)
+		if err != nil {
+			return nil, err
+		}
+		out[name] = c
+	}
+	return out, nil
+}
+
+// values checks values against the table and returns the columns in table
+// order with their converted values. Key columns are accepted only on
+// insert.
+func (e *editor) values(values map[string]any, insert bool) ([]string, []any, error) {
+	for name := range values {
+		col, ok := e.column(name)
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: unknown column %s", ErrInvalid, name)
+		}
+		if !e.editable(col.Name) && !(insert && slices.Contains(e.tbl.Key, col.Name)) {
+			return nil, nil, fmt.Errorf("%w: column %s cannot be edited", ErrInvalid, name)
+		}
+	}
+	var (
+		cols []string
+		args []any
+	)
+	for _, col := range e.columns {
+		v, ok := values[col.Name]
+		if !ok {
+			continue
+		}
+		c, err := coerce(col, v)
+		if err != nil {
+			return nil, nil, err
+		}
+		cols, args = append(cols, col.Name), append(args, c)
+	}
+	return cols, args, nil
+}
+
+// where renders the key condition, numbering parameters after offset.
+func (e *editor) where(key map[string]any, offset int) (string, []any) {
+	conds := make([]string, len(e.tbl.Key))
+	args := make([]any, len(e.tbl.Key))
+	for i, k := range e.tbl.Key {
+		conds[i] = e.dialect.QuoteIdent(k) + " = " + e.dialect.Placeholder(offset+i+1)
+		args[i] = key[k]
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// get reads the row key addresses.
+func (e *editor) get(ctx context.Context, key map[string]any) (map[string]any, error) {
+	where, args := e.where(key, 0)
+	res, err := e.conn.Query(ctx, "SELECT * FROM "+e.qualified()+" WHERE "+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("read row: %w", err)
+	}
+	if res == nil || len(res.Frames) == 0 || len(res.Frames[0].Fields) == 0 {
+		return nil, ErrNotFound
+	}
+	fields := res.Frames[0].Fields
+	switch n := len(fields[0].Values); {
+	case n == 0:
+		return nil, ErrNotFound
+	case n > 1:
+		return nil, fmt.Errorf("%w: the key matches %d rows", ErrInvalid, n)
+	}
+	row := make(map[string]any, len(fields))
+	for _, f := range fields {
+		v := f.Values[0]
+		if b, ok := v.([]byte); ok {
+			v = string(b)
+		}
+		row[f.Name] = v
+	}
+	return row, nil
+}
+
+// coerce checks v against col's type and converts it to what the driver
+// takes for it. v is a JSON value decoded with UseNumber, or text from a
+// URL.
+func coerce(col sdk.ColumnInfo, v any) (any, error) {
+	if v == nil {
+		if !col.Nullable {
+			return nil, fmt.Errorf("%w: column %s cannot be null", ErrInvalid, col.Name)
+		}
+		return nil, nil
+	}
+	bad := func(want string) error {
+		return fmt.Errorf("%w: column %s expects %s", ErrInvalid, col.Name, want)
+	}
+	text, isText := v.(string)
+	if n, ok := v.(json.Number); ok {
+		text, isText = n.String(), false
+	}
+	switch sdk.InferFieldKind(col.Type) {
+	case sdk.FieldKindBoolean:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+		if b, err := strconv.ParseBool(text); isText && err == nil {
+			return b, nil
+		}
+		return nil, bad("a boolean")
+	case sdk.FieldKindNumber:
+		if _, ok := v.(json.Number); !ok && !isText {
+			return nil, bad("a number")
+		}
+		if integerType(col.Type) {
+			n, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+			if err != nil {
+				return nil, bad("an integer")
+			}
+			return n, nil
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return nil, bad("a number")
+		}
+		return f, nil
+	case sdk.FieldKindTime:
+		if !isText {
+			return nil, bad("a date or time")
+		}
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, text); err == nil {
+				return t, nil
+			}
+		}
+		return nil, bad("a date or time")
+	}
+	switch v.(type) {
+	case string:
+		return text, nil
+	case map[string]any, []any:
+		if strings.Contains(strings.ToUpper(col.Type), "JSON") {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			return string(b), nil
+		}
+	}
+	return nil, bad("text")
+}
+
+// integerType reports whether a native type holds whole numbers only.
+func integerType(dbType string) bool {
+	t := strings.ToUpper(dbType)
+	return strings.Contains(t, "INT") || strings.Contains(t, "SERIAL")
+}
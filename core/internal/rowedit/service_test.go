@@ -0,0 +1,260 @@
+package rowedit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/identity"
+	"data-voyager/sdk"
+)
+
+type stubConns struct{ connection.Repository }
+
+func (stubConns) GetByID(_ context.Context, id string) (*connection.Connection, error) {
+	switch id {
+	case "pg":
+		return &connection.Connection{ID: id, Type: "postgresql", Config: json.RawMessage(`{}`)}, nil
+	case "ch":
+		return &connection.Connection{ID: id, Type: "clickhouse", Config: json.RawMessage(`{}`)}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+type stubConfig struct{}
+
+func (stubConfig) Validate() error             { return nil }
+func (stubConfig) GetConnectionString() string { return "" }
+
+type stubPlugin struct {
+	sdk.DatasourcePlugin
+	typ   sdk.DataSourceType
+	table *fakeTable
+}
+
+func (p *stubPlugin) GetType() sdk.DataSourceType { return p.typ }
+func (p *stubPlugin) Capabilities() sdk.Capabilities {
+	return sdk.Capabilities{Exec: true, Writes: true}
+}
+func (p *stubPlugin) ParseConfig(json.RawMessage) (sdk.ConnectionConfig, error) {
+	return stubConfig{}, nil
+}
+func (p *stubPlugin) Connect(context.Context, sdk.ConnectionConfig) (sdk.Connection, error) {
+	return &stubConn{t: p.table}, nil
+}
+
+var (
+	columns = []sdk.ColumnInfo{
+		{Name: "code", Type: "text"},
+		{Name: "name", Type: "varchar(64)"},
+		{Name: "population", Type: "int8", Nullable: true},
+		{Name: "active", Type: "bool"},
+		{Name: "founded", Type: "date", Nullable: true},
+		{Name: "internal", Type: "text", Nullable: true},
+	}
+	quotedRe = regexp.MustCompile(`"(\w+)"`)
+)
+
+// fakeTable is ref.countries keyed by code. It understands the statements
+// the service writes, with columns named in order before the WHERE.
+type fakeTable struct {
+	rows  map[string]map[string]any
+	stmts []string
+}
+
+type stubConn struct {
+	sdk.Connection
+	t *fakeTable
+}
+
+func (c *stubConn) GetTables(_ context.Context, schema string) ([]sdk.TableInfo, error) {
+	if schema != "ref" {
+		return nil, nil
+	}
+	return []sdk.TableInfo{{Name: "countries", Columns: columns}}, nil
+}
+
+func (c *stubConn) Query(_ context.Context, q string, params ...any) (*sdk.QueryResult, error) {
+	c.t.stmts = append(c.t.stmts, q)
+	head, _, _ := strings.Cut(q, " WHERE ")
+	names := quotedRe.FindAllStringSubmatch(head, -1)[2:] // past "ref"."countries"
+	switch {
+	case strings.HasPrefix(q, "SELECT"):
+		fields := make([]sdk.Field, len(columns))
+		for i, col := range columns {
+			fields[i].Name = col.Name
+			if row, ok := c.t.rows[params[0].(string)]; ok {
+				fields[i].Values = []any{row[col.Name]}
+			}
+		}
+		return &sdk.QueryResult{Frames: []*sdk.DataFrame{{Fields: fields}}}, nil
+	case strings.HasPrefix(q, "INSERT"):
+		row := map[string]any{}
+		for i, n := range names {
+			row[n[1]] = params[i]
+		}
+		if _, ok := c.t.rows[row["code"].(string)]; ok {
+			return nil, errors.New("duplicate key value violates unique constraint")
+		}
+		c.t.rows[row["code"].(string)] = row
+	case strings.HasPrefix(q, "UPDATE"):
+		row := c.t.rows[params[len(params)-1].(string)]
+		for i, n := range names {
+			row[n[1]] = params[i]
+		}
+	case strings.HasPrefix(q, "DELETE"):
+		delete(c.t.rows, params[0].(string))
+	}
+	return &sdk.QueryResult{}, nil
+}
+
+func (c *stubConn) Close() error { return nil }
+
+type memAudit struct{ records []*AuditRecord }
+
+func (m *memAudit) Record(_ context.Context, r *AuditRecord) error {
+	m.records = append(m.records, r)
+	return nil
+}
+
+func (m *memAudit) List(context.Context, AuditFilter) ([]*AuditRecord, error) {
+	return m.records, nil
+}
+
+func newTestService() (*Service, *fakeTable, *memAudit) {
+	table := &fakeTable{rows: map[string]map[string]any{
+		"KR": {"code": "KR", "name": "Korea", "population": int64(51), "active": true},
+	}}
+	reg := datasource.NewRegistry()
+	reg.Register(&stubPlugin{typ: "postgresql", table: table})
+	reg.Register(&stubPlugin{typ: "clickhouse", table: table})
+	audit := &memAudit{}
+	svc := NewService(config.RowEditingConfig{Tables: []config.EditableTable{
+		{Name: "countries", DatasourceID: "pg", Schema: "ref", Table: "countries", Key: []string{"code"},
+			Columns: []string{"name", "population", "active", "founded"}},
+		{Name: "events", DatasourceID: "ch", Schema: "ref", Table: "countries", Key: []string{"code"}},
+	}}, stubConns{}, reg).WithAudit(audit)
+	svc.now = func() time.Time { return time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC) }
+	return svc, table, audit
+}
+
+// values decodes a request body the way the handler does.
+func values(t *testing.T, body string) map[string]any {
+	t.Helper()
+	dec := json.NewDecoder(strings.NewReader(body))
+	dec.UseNumber()
+	var v map[string]any
+	require.NoError(t, dec.Decode(&v))
+	return v
+}
+
+func TestEdit_InsertUpdateDelete(t *testing.T) {
+	svc, table, audit := newTestService()
+	ctx := identity.With(context.Background(), &identity.Identity{Username: "ann", Role: identity.RoleEditor})
+
+	row, err := svc.Insert(ctx, "countries", values(t, `{"code": "JP", "name": "Japan", "population": 124, "active": true, "founded": "1947-05-03"}`))
+	require.NoError(t, err)
+	assert.Equal(t, int64(124), row["population"])
+	assert.Equal(t, time.Date(1947, 5, 3, 0, 0, 0, 0, time.UTC), row["founded"])
+	assert.Equal(t, `INSERT INTO "ref"."countries" ("code", "name", "population", "active", "founded") VALUES ($1, $2, $3, $4, $5)`,
+		table.stmts[len(table.stmts)-2])
+
+	_, err = svc.Insert(ctx, "countries", values(t, `{"code": "JP", "name": "Japan", "active": true}`))
+	assert.ErrorContains(t, err, "duplicate key")
+
+	row, err = svc.Update(ctx, "countries", map[string]string{"code": "KR"}, values(t, `{"name": "South Korea", "population": null}`))
+	require.NoError(t, err)
+	assert.Equal(t, "South Korea", row["name"])
+	assert.Nil(t, row["population"])
+	assert.Contains(t, table.stmts, `UPDATE "ref"."countries" SET "name" = $1, "population" = $2 WHERE "code" = $3`)
+
+	require.NoError(t, svc.Delete(ctx, "countries", map[string]string{"code": "JP"}))
+	assert.NotContains(t, table.rows, "JP")
+	_, err = svc.Get(ctx, "countries", map[string]string{"code": "JP"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.Len(t, audit.records, 3, "the failed insert is not audited")
+	ins, upd, del := audit.records[0], audit.records[1], audit.records[2]
+	assert.Equal(t, OpInsert, ins.Op)
+	assert.Equal(t, "ann", ins.Actor)
+	assert.Equal(t, map[string]any{"code": "JP"}, ins.Key)
+	assert.Nil(t, ins.Before)
+	assert.Equal(t, "Japan", ins.After["name"])
+	assert.Equal(t, OpUpdate, upd.Op)
+	assert.Equal(t, "Korea", upd.Before["name"])
+	assert.Equal(t, "South Korea", upd.After["name"])
+	assert.Equal(t, OpDelete, del.Op)
+	assert.Equal(t, "Japan", del.Before["name"])
+	assert.Nil(t, del.After)
+	assert.Equal(t, "ref", del.Schema)
+	assert.Equal(t, "countries", del.TableName)
+}
+
+func TestEdit_Validates(t *testing.T) {
+	svc, table, audit := newTestService()
+	ctx := context.Background()
+	kr := map[string]string{"code": "KR"}
+
+	for name, body := range map[string]string{
+		"wrong type":       `{"population": "many"}`,
+		"fraction for int": `{"population": 1.5}`,
+		"number for bool":  `{"active": 1}`,
+		"bad date":         `{"founded": "yesterday"}`,
+		"null not allowed": `{"name": null}`,
+		"number for text":  `{"name": 7}`,
+		"unknown column":   `{"capital": "Seoul"}`,
+		"not editable":     `{"internal": "x"}`,
+		"key column":       `{"code": "XX"}`,
+		"no values":        `{}`,
+	} {
+		_, err := svc.Update(ctx, "countries", kr, values(t, body))
+		assert.ErrorIs(t, err, ErrInvalid, name)
+	}
+	_, err := svc.Insert(ctx, "countries", values(t, `{"name": "Nowhere", "active": false}`))
+	assert.ErrorIs(t, err, ErrInvalid, "insert without the key")
+	_, err = svc.Update(ctx, "countries", map[string]string{}, values(t, `{"name": "x"}`))
+	assert.ErrorIs(t, err, ErrInvalid, "update without the key")
+	_, err = svc.Update(ctx, "countries", map[string]string{"code": "ZZ"}, values(t, `{"name": "x"}`))
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	for _, q := range table.stmts {
+		assert.True(t, strings.HasPrefix(q, "SELECT"), "nothing was written: %s", q)
+	}
+	assert.Empty(t, audit.records)
+}
+
+func TestEdit_Refuses(t *testing.T) {
+	svc, _, _ := newTestService()
+
+	_, err := svc.Get(context.Background(), "missing", map[string]string{"code": "KR"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	hidden := identity.With(context.Background(), &identity.Identity{Role: identity.RoleEditor, Datasources: []string{"other"}})
+	_, err = svc.Get(hidden, "countries", map[string]string{"code": "KR"})
+	assert.ErrorIs(t, err, ErrNotFound, "tables on hidden datasources do not exist")
+	assert.Empty(t, svc.Tables(hidden))
+
+	_, err = svc.Get(context.Background(), "events", map[string]string{"code": "KR"})
+	assert.ErrorIs(t, err, ErrUnsupported, "no bind parameters")
+}
+
+func TestDescribe(t *testing.T) {
+	svc, _, _ := newTestService()
+	tbl, err := svc.Describe(context.Background(), "countries")
+	require.NoError(t, err)
+	require.Len(t, tbl.Columns, len(columns))
+	assert.Equal(t, Column{Name: "code", Type: "text", Kind: sdk.FieldKindString, Key: true}, tbl.Columns[0])
+	assert.Equal(t, sdk.FieldKindNumber, tbl.Columns[2].Kind)
+	assert.True(t, tbl.Columns[2].Editable)
+	assert.False(t, tbl.Columns[5].Editable, "not in the editable list")
+}
@@ -0,0 +1,130 @@
+package savedview
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// Handler serves the saved view endpoints.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates a saved view HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// List handles GET /saved-views?datasource_id=ID&schema=NAME&table=NAME
+func (h *Handler) List(c *gin.Context) {
+	if !h.allowed(c, false) {
+		return
+	}
+	views, err := h.svc.List(c.Request.Context(), ListFilter{
+		DatasourceID: c.Query("datasource_id"),
+		Schema:       c.Query("schema"),
+		Table:        c.Query("table"),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": views})
+}
+
+// Get handles GET /saved-views/:id
+func (h *Handler) Get(c *gin.Context) {
+	if !h.allowed(c, false) {
+		return
+	}
+	v, err := h.svc.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": v})
+}
+
+// Create handles POST /saved-views
+func (h *Handler) Create(c *gin.Context) {
+	if !h.allowed(c, true) {
+		return
+	}
+	var v View
+	if err := c.ShouldBindJSON(&v); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.svc.Create(c.Request.Context(), &v); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": v})
+}
+
+// Update handles PUT /saved-views/:id
+func (h *Handler) Update(c *gin.Context) {
+	if !h.allowed(c, true) {
+		return
+	}
+	var v View
+	if err := c.ShouldBindJSON(&v); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.svc.Update(c.Request.Context(), c.Param("id"), &v); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": v})
+}
+
+// Delete handles DELETE /saved-views/:id
+func (h *Handler) Delete(c *gin.Context) {
+	if !h.allowed(c, true) {
+		return
+	}
+	if err := h.svc.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// allowed checks that the caller can browse datasources, refusing every
+// change from a demo visitor.
+func (h *Handler) allowed(c *gin.Context, change bool) bool {
+	caller := identity.FromContext(c.Request.Context())
+	if !caller.Can(identity.PermDatasourceRead) || (change && caller != nil && caller.Demo) {
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "permission denied")})
+		return false
+	}
+	return true
+}
+
+func (h *Handler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "saved view not found")})
+	case errors.Is(err, ErrInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "only the owner of a saved view can change it")})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// RegisterRoutes wires the handler onto r.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/saved-views", h.List)
+	r.POST("/saved-views", h.Create)
+	r.GET("/saved-views/:id", h.Get)
+	r.PUT("/saved-views/:id", h.Update)
+	r.DELETE("/saved-views/:id", h.Delete)
+}
@@ -0,0 +1,71 @@
+package savedview
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInvalid wraps validation failures so handlers can map them to 400.
+	ErrInvalid = errors.New("invalid saved view")
+	// ErrNotFound is returned for unknown views and views hidden from the
+	// caller.
+	ErrNotFound = errors.New("saved view not found")
+	// ErrForbidden is returned for changing a view the caller does not own.
+	ErrForbidden = errors.New("only the owner of a saved view can change it")
+)
+
+// View is a named slice of a table, as the table browser shows it.
+type View struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	DatasourceID string `json:"datasource_id"`
+	Schema       string `json:"schema,omitempty"`
+	Table        string `json:"table"`
+	Spec         Spec   `json:"spec"`
+	// Shared views are listed for everyone who can see the datasource;
+	// others only for their owner.
+	Shared    bool      `json:"shared"`
+	Owner     string    `json:"owner"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Spec holds the browse parameters a view applies. They mirror those of
+// the table rows endpoint.
+type Spec struct {
+	Filters []Filter `json:"filters"`
+	Sort    string   `json:"sort,omitempty"`
+	// Order is asc or desc; empty is asc.
+	Order string `json:"order,omitempty"`
+	// Columns lists the visible columns in display order; empty shows all.
+	Columns []string `json:"columns"`
+	// Limit is the page size; 0 leaves the default.
+	Limit int `json:"limit,omitempty"`
+}
+
+// Filter is a quick filter on one column. See query_builder.ColumnFilter.
+type Filter struct {
+	Column string `json:"column"`
+	Op     string `json:"op"`
+	Value  any    `json:"value,omitempty"`
+}
+
+// ListFilter selects views. Empty fields match everything.
+type ListFilter struct {
+	DatasourceID string
+	Schema       string
+	Table        string
+	// Owner limits the list to the owner's views and shared ones.
+	Owner string
+}
+
+// Repository persists saved views.
+type Repository interface {
+	List(ctx context.Context, f ListFilter) ([]*View, error)
+	GetByID(ctx context.Context, id string) (*View, error)
+	Create(ctx context.Context, v *View) error
+	Update(ctx context.Context, v *View) error
+	Delete(ctx context.Context, id string) error
+}
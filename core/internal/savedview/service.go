@@ -0,0 +1,196 @@
+package savedview
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/notification"
+	qb "data-voyager/core/internal/query_builder"
+)
+
+const (
+	maxNameLength = 255
+	maxFilters    = 20
+	maxLimit      = 10000
+)
+
+// Service manages saved table views. Views live with their owner: anyone
+// may save one, only the owner (or an admin) changes it, and a view on a
+// datasource hidden from the caller does not exist for them.
+type Service struct {
+	repo     Repository
+	conns    connection.Repository
+	notifier notification.Notifier // nil raises no events
+	now      func() time.Time
+}
+
+// NewService creates a saved view service.
+func NewService(repo Repository, conns connection.Repository) *Service {
+	return &Service{repo: repo, conns: conns, now: time.Now}
+}
+
+// WithNotifier raises a query_edited event through n whenever a shared
+// view is saved. Private views are nobody else's business.
+func (s *Service) WithNotifier(n notification.Notifier) *Service {
+	s.notifier = n
+	return s
+}
+
+// List returns the caller's views and the shared ones, optionally of one
+// table. Views on hidden datasources are left out.
+func (s *Service) List(ctx context.Context, f ListFilter) ([]*View, error) {
+	caller := identity.FromContext(ctx)
+	if caller != nil {
+		f.Owner = caller.Username
+	}
+	views, err := s.repo.List(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(views, func(v *View) bool { return !caller.CanSee(v.DatasourceID) }), nil
+}
+
+// Get returns a view visible to the caller.
+func (s *Service) Get(ctx context.Context, id string) (*View, error) {
+	v, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	caller := identity.FromContext(ctx)
+	if !caller.CanSee(v.DatasourceID) || (!v.Shared && caller != nil && v.Owner != caller.Username) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return v, nil
+}
+
+// Create saves a view owned by the caller.
+func (s *Service) Create(ctx context.Context, v *View) error {
+	if err := s.validate(ctx, v); err != nil {
+		return err
+	}
+	now := s.now().UTC()
+	v.ID = uuid.NewString()
+	v.Owner = ""
+	if caller := identity.FromContext(ctx); caller != nil {
+		v.Owner = caller.Username
+	}
+	v.CreatedAt, v.UpdatedAt = now, now
+	if err := s.repo.Create(ctx, v); err != nil {
+		return err
+	}
+	s.notify(ctx, v, "created")
+	return nil
+}
+
+// Update replaces the name, table, spec and sharing of a view. Its owner
+// and creation time are kept.
+func (s *Service) Update(ctx context.Context, id string, v *View) error {
+	existing, err := s.editable(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.validate(ctx, v); err != nil {
+		return err
+	}
+	v.ID, v.Owner, v.CreatedAt = existing.ID, existing.Owner, existing.CreatedAt
+	v.UpdatedAt = s.now().UTC()
+	if err := s.repo.Update(ctx, v); err != nil {
+		return err
+	}
+	s.notify(ctx, v, "updated")
+	return nil
+}
+
+// Delete removes a view.
+func (s *Service) Delete(ctx context.Context, id string) error {
+	if _, err := s.editable(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// notify raises a query_edited event for a shared view that was created or
+// updated.
+func (s *Service) notify(ctx context.Context, v *View, action string) {
+	if s.notifier == nil || !v.Shared {
+		return
+	}
+	labels := map[string]string{"datasource_id": v.DatasourceID, "view_id": v.ID, "table": v.Table, "action": action}
+	if caller := identity.FromContext(ctx); caller != nil {
+		labels["user"] = caller.Username
+	}
+	ev := notification.Event{
+		Type:     notification.EventQueryEdited,
+		Severity: notification.SeverityInfo,
+		Title:    fmt.Sprintf("Saved view %s was %s", v.Name, action),
+		Source:   v.Name,
+		Labels:   labels,
+		Time:     v.UpdatedAt,
+	}
+	if err := s.notifier.Notify(ctx, ev); err != nil {
+		slog.Warn("saved view notification failed", "view", v.ID, "err", err)
+	}
+}
+
+// editable returns the view id if the caller may change it.
+func (s *Service) editable(ctx context.Context, id string) (*View, error) {
+	v, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	caller := identity.FromContext(ctx)
+	if caller != nil && v.Owner != caller.Username && !caller.Can(identity.PermAdmin) {
+		return nil, ErrForbidden
+	}
+	return v, nil
+}
+
+func (s *Service) validate(ctx context.Context, v *View) error {
+	v.Name = strings.TrimSpace(v.Name)
+	switch {
+	case v.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalid)
+	case len(v.Name) > maxNameLength:
+		return fmt.Errorf("%w: name must be at most %d characters", ErrInvalid, maxNameLength)
+	case v.Table == "":
+		return fmt.Errorf("%w: table is required", ErrInvalid)
+	case v.Spec.Order != "" && v.Spec.Order != "asc" && v.Spec.Order != "desc":
+		return fmt.Errorf("%w: order must be asc or desc", ErrInvalid)
+	case v.Spec.Limit < 0 || v.Spec.Limit > maxLimit:
+		return fmt.Errorf("%w: limit must be between 0 and %d", ErrInvalid, maxLimit)
+	case len(v.Spec.Filters) > maxFilters:
+		return fmt.Errorf("%w: at most %d filters", ErrInvalid, maxFilters)
+	}
+	for _, f := range v.Spec.Filters {
+		if _, err := (qb.ColumnFilter{Column: f.Column, Op: f.Op, Value: f.Value}).Cond(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+	}
+	for i, col := range v.Spec.Columns {
+		if col == "" || slices.Contains(v.Spec.Columns[:i], col) {
+			return fmt.Errorf("%w: columns must be distinct names", ErrInvalid)
+		}
+	}
+	if v.Spec.Filters == nil {
+		v.Spec.Filters = []Filter{}
+	}
+	if v.Spec.Columns == nil {
+		v.Spec.Columns = []string{}
+	}
+	// A hidden datasource is reported as unknown, like a missing one.
+	if !identity.FromContext(ctx).CanSee(v.DatasourceID) {
+		return fmt.Errorf("%w: unknown datasource %q", ErrInvalid, v.DatasourceID)
+	}
+	if _, err := s.conns.GetByID(ctx, v.DatasourceID); err != nil {
+		return fmt.Errorf("%w: unknown datasource %q", ErrInvalid, v.DatasourceID)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package savedview
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/identity"
+)
+
+type stubConns struct{ connection.Repository }
+
+func (stubConns) GetByID(_ context.Context, id string) (*connection.Connection, error) {
+	if id == "pg" || id == "ch" {
+		return &connection.Connection{ID: id, Type: "postgresql"}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+// memRepo keeps views in memory, filtering them like the SQL stores.
+type memRepo struct{ views []*View }
+
+func (m *memRepo) List(_ context.Context, f ListFilter) ([]*View, error) {
+	var out []*View
+	for _, v := range m.views {
+		if (f.DatasourceID == "" || v.DatasourceID == f.DatasourceID) && (f.Table == "" || v.Table == f.Table) &&
+			(f.Owner == "" || v.Owner == f.Owner || v.Shared) {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func (m *memRepo) GetByID(_ context.Context, id string) (*View, error) {
+	i := slices.IndexFunc(m.views, func(v *View) bool { return v.ID == id })
+	if i < 0 {
+		return nil, errors.New("missing")
+	}
+	v := *m.views[i]
+	return &v, nil
+}
+
+func (m *memRepo) Create(_ context.Context, v *View) error {
+	m.views = append(m.views, v)
+	return nil
+}
+
+func (m *memRepo) Update(_ context.Context, v *View) error {
+	i := slices.IndexFunc(m.views, func(old *View) bool { return old.ID == v.ID })
+	m.views[i] = v
+	return nil
+}
+
+func (m *memRepo) Delete(_ context.Context, id string) error {
+	m.views = slices.DeleteFunc(m.views, func(v *View) bool { return v.ID == id })
+	return nil
+}
+
+func as(name, role string, datasources ...string) context.Context {
+	return identity.With(context.Background(), &identity.Identity{Username: name, Role: role, Datasources: datasources})
+}
+
+func newTestService() *Service {
+	svc := NewService(&memRepo{}, stubConns{})
+	svc.now = func() time.Time { return time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC) }
+	return svc
+}
+
+func TestSavedViews_Visibility(t *testing.T) {
+	svc := newTestService()
+	ann, bob := as("ann", identity.RoleEditor), as("bob", identity.RoleViewer)
+
+	private := &View{Name: " Errors last 24h ", DatasourceID: "pg", Table: "logs", Spec: Spec{
+		Filters: []Filter{{Column: "level", Op: "eq", Value: "error"}}, Sort: "ts", Order: "desc",
+	}}
+	require.NoError(t, svc.Create(ann, private))
+	assert.Equal(t, "Errors last 24h", private.Name)
+	assert.Equal(t, "ann", private.Owner)
+	assert.NotEmpty(t, private.ID)
+	assert.Equal(t, []string{}, private.Spec.Columns)
+	shared := &View{Name: "Slow", DatasourceID: "ch", Table: "logs", Shared: true}
+	require.NoError(t, svc.Create(ann, shared))
+
+	views, err := svc.List(ann, ListFilter{Table: "logs"})
+	require.NoError(t, err)
+	assert.Len(t, views, 2)
+	views, err = svc.List(bob, ListFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []*View{shared}, views, "only the shared view")
+	views, err = svc.List(as("eve", identity.RoleViewer, "pg"), ListFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, views, "the shared view's datasource is hidden")
+
+	_, err = svc.Get(bob, private.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	got, err := svc.Get(bob, shared.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Slow", got.Name)
+}
+
+func TestSavedViews_OnlyOwnerChanges(t *testing.T) {
+	svc := newTestService()
+	ann, bob := as("ann", identity.RoleEditor), as("bob", identity.RoleEditor)
+	v := &View{Name: "Slow", DatasourceID: "pg", Table: "logs", Shared: true}
+	require.NoError(t, svc.Create(ann, v))
+
+	assert.ErrorIs(t, svc.Update(bob, v.ID, &View{Name: "Mine", DatasourceID: "pg", Table: "logs"}), ErrForbidden)
+	assert.ErrorIs(t, svc.Delete(bob, v.ID), ErrForbidden)
+
+	svc.now = func() time.Time { return time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC) }
+	upd := &View{Name: "Slower", DatasourceID: "pg", Table: "logs", Owner: "bob"}
+	require.NoError(t, svc.Update(as("root", identity.RoleAdmin), v.ID, upd))
+	assert.Equal(t, "ann", upd.Owner, "the owner is kept")
+	assert.Equal(t, v.CreatedAt, upd.CreatedAt)
+	assert.True(t, upd.UpdatedAt.After(upd.CreatedAt))
+
+	_, err := svc.Get(bob, v.ID)
+	assert.ErrorIs(t, err, ErrNotFound, "no longer shared")
+	require.NoError(t, svc.Delete(ann, v.ID))
+	_, err = svc.Get(ann, v.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSavedViews_Validates(t *testing.T) {
+	svc := newTestService()
+	for name, v := range map[string]View{
+		"no name":           {DatasourceID: "pg", Table: "t"},
+		"no table":          {Name: "x", DatasourceID: "pg"},
+		"unknown source":    {Name: "x", DatasourceID: "nope", Table: "t"},
+		"bad order":         {Name: "x", DatasourceID: "pg", Table: "t", Spec: Spec{Order: "up"}},
+		"limit too large":   {Name: "x", DatasourceID: "pg", Table: "t", Spec: Spec{Limit: 100000}},
+		"bad filter":        {Name: "x", DatasourceID: "pg", Table: "t", Spec: Spec{Filters: []Filter{{Column: "a", Op: "matches"}}}},
+		"duplicate columns": {Name: "x", DatasourceID: "pg", Table: "t", Spec: Spec{Columns: []string{"a", "a"}}},
+	} {
+		assert.ErrorIs(t, svc.Create(context.Background(), &v), ErrInvalid, name)
+	}
+	hidden := &View{Name: "x", DatasourceID: "ch", Table: "t"}
+	assert.ErrorIs(t, svc.Create(as("eve", identity.RoleViewer, "pg"), hidden), ErrInvalid)
+}
@@ -0,0 +1,290 @@
+package scim
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// contentType is the SCIM media type (RFC 7644 §3.1).
+const contentType = "application/scim+json"
+
+// Handler serves the SCIM endpoints.
+type Handler struct {
+	svc   *Service
+	token string
+}
+
+// NewHandler creates a SCIM HTTP handler authenticating with token.
+func NewHandler(svc *Service, token string) *Handler {
+	return &Handler{svc: svc, token: token}
+}
+
+// errorResponse is the SCIM error body (RFC 7644 §3.12).
+type errorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+func write(c *gin.Context, status int, v any) {
+	c.Header("Content-Type", contentType)
+	c.JSON(status, v)
+}
+
+func writeError(c *gin.Context, status int, scimType, detail string) {
+	c.Header("Content-Type", contentType)
+	c.AbortWithStatusJSON(status, errorResponse{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+func fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		writeError(c, http.StatusNotFound, "", err.Error())
+	case errors.Is(err, ErrConflict):
+		writeError(c, http.StatusConflict, "uniqueness", err.Error())
+	case errors.Is(err, ErrInvalidFilter):
+		writeError(c, http.StatusBadRequest, "invalidFilter", err.Error())
+	case errors.Is(err, ErrInvalid):
+		writeError(c, http.StatusBadRequest, "invalidValue", err.Error())
+	default:
+		writeError(c, http.StatusInternalServerError, "", err.Error())
+	}
+}
+
+// authenticate requires the configured bearer token.
+func (h *Handler) authenticate(c *gin.Context) {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		writeError(c, http.StatusUnauthorized, "", "invalid bearer token")
+		return
+	}
+	c.Next()
+}
+
+func bind(c *gin.Context, v any) bool {
+	if err := c.ShouldBindJSON(v); err != nil {
+		writeError(c, http.StatusBadRequest, "invalidSyntax", err.Error())
+		return false
+	}
+	return true
+}
+
+func pageParams(c *gin.Context) (start, count int) {
+	start, _ = strconv.Atoi(c.DefaultQuery("startIndex", "1"))
+	count, err := strconv.Atoi(c.Query("count"))
+	if err != nil {
+		count = -1
+	}
+	return start, count
+}
+
+// ─── discovery ────────────────────────────────────────────────────────────────
+
+// ServiceProviderConfig handles GET /ServiceProviderConfig
+func (h *Handler) ServiceProviderConfig(c *gin.Context) {
+	supported := func(b bool) gin.H { return gin.H{"supported": b} }
+	write(c, http.StatusOK, gin.H{
+		"schemas":        []string{SchemaSPConfig},
+		"patch":          supported(true),
+		"bulk":           gin.H{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         gin.H{"supported": true, "maxResults": maxCount},
+		"changePassword": supported(false),
+		"sort":           supported(false),
+		"etag":           supported(false),
+		"authenticationSchemes": []gin.H{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer token",
+			"description": "The token configured as security.scim.token",
+			"primary":     true,
+		}},
+	})
+}
+
+// ResourceTypes handles GET /ResourceTypes
+func (h *Handler) ResourceTypes(c *gin.Context) {
+	types := []any{
+		gin.H{"schemas": []string{SchemaResourceType}, "id": "User", "name": "User", "endpoint": "/Users", "schema": SchemaUser},
+		gin.H{"schemas": []string{SchemaResourceType}, "id": "Group", "name": "Group", "endpoint": "/Groups", "schema": SchemaGroup},
+	}
+	write(c, http.StatusOK, page(types, 1, len(types)))
+}
+
+// ─── users ────────────────────────────────────────────────────────────────────
+
+// ListUsers handles GET /Users?filter=&startIndex=&count=
+func (h *Handler) ListUsers(c *gin.Context) {
+	start, count := pageParams(c)
+	res, err := h.svc.ListUsers(c.Request.Context(), c.Query("filter"), start, count)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	write(c, http.StatusOK, res)
+}
+
+// GetUser handles GET /Users/:id
+func (h *Handler) GetUser(c *gin.Context) {
+	res, err := h.svc.GetUser(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	write(c, http.StatusOK, res)
+}
+
+// CreateUser handles POST /Users
+func (h *Handler) CreateUser(c *gin.Context) {
+	var req UserResource
+	if !bind(c, &req) {
+		return
+	}
+	res, err := h.svc.CreateUser(c.Request.Context(), &req)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	write(c, http.StatusCreated, res)
+}
+
+// ReplaceUser handles PUT /Users/:id
+func (h *Handler) ReplaceUser(c *gin.Context) {
+	var req UserResource
+	if !bind(c, &req) {
+		return
+	}
+	res, err := h.svc.ReplaceUser(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	write(c, http.StatusOK, res)
+}
+
+// PatchUser handles PATCH /Users/:id
+func (h *Handler) PatchUser(c *gin.Context) {
+	var req PatchRequest
+	if !bind(c, &req) {
+		return
+	}
+	res, err := h.svc.PatchUser(c.Request.Context(), c.Param("id"), req.Operations)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	write(c, http.StatusOK, res)
+}
+
+// DeleteUser handles DELETE /Users/:id
+func (h *Handler) DeleteUser(c *gin.Context) {
+	if err := h.svc.DeleteUser(c.Request.Context(), c.Param("id")); err != nil {
+		fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ─── groups ───────────────────────────────────────────────────────────────────
+
+// ListGroups handles GET /Groups?filter=&startIndex=&count=
+func (h *Handler) ListGroups(c *gin.Context) {
+	start, count := pageParams(c)
+	res, err := h.svc.ListGroups(c.Request.Context(), c.Query("filter"), start, count)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	write(c, http.StatusOK, res)
+}
+
+// GetGroup handles GET /Groups/:id
+func (h *Handler) GetGroup(c *gin.Context) {
+	res, err := h.svc.GetGroup(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	write(c, http.StatusOK, res)
+}
+
+// CreateGroup handles POST /Groups
+func (h *Handler) CreateGroup(c *gin.Context) {
+	var req GroupResource
+	if !bind(c, &req) {
+		return
+	}
+	res, err := h.svc.CreateGroup(c.Request.Context(), &req)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	write(c, http.StatusCreated, res)
+}
+
+// ReplaceGroup handles PUT /Groups/:id
+func (h *Handler) ReplaceGroup(c *gin.Context) {
+	var req GroupResource
+	if !bind(c, &req) {
+		return
+	}
+	res, err := h.svc.ReplaceGroup(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	write(c, http.StatusOK, res)
+}
+
+// PatchGroup handles PATCH /Groups/:id
+func (h *Handler) PatchGroup(c *gin.Context) {
+	var req PatchRequest
+	if !bind(c, &req) {
+		return
+	}
+	res, err := h.svc.PatchGroup(c.Request.Context(), c.Param("id"), req.Operations)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	write(c, http.StatusOK, res)
+}
+
+// DeleteGroup handles DELETE /Groups/:id
+func (h *Handler) DeleteGroup(c *gin.Context) {
+	if err := h.svc.DeleteGroup(c.Request.Context(), c.Param("id")); err != nil {
+		fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RegisterRoutes mounts the endpoints under /scim/v2 on r, outside the API
+// group: identity providers authenticate with the SCIM token rather than
+// as a Voyager user.
+func RegisterRoutes(r gin.IRouter, h *Handler) {
+	g := r.Group(basePath, h.authenticate)
+	g.GET("/ServiceProviderConfig", h.ServiceProviderConfig)
+	g.GET("/ResourceTypes", h.ResourceTypes)
+	g.GET("/Users", h.ListUsers)
+	g.POST("/Users", h.CreateUser)
+	g.GET("/Users/:id", h.GetUser)
+	g.PUT("/Users/:id", h.ReplaceUser)
+	g.PATCH("/Users/:id", h.PatchUser)
+	g.DELETE("/Users/:id", h.DeleteUser)
+	g.GET("/Groups", h.ListGroups)
+	g.POST("/Groups", h.CreateGroup)
+	g.GET("/Groups/:id", h.GetGroup)
+	g.PUT("/Groups/:id", h.ReplaceGroup)
+	g.PATCH("/Groups/:id", h.PatchGroup)
+	g.DELETE("/Groups/:id", h.DeleteGroup)
+}
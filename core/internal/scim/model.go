@@ -0,0 +1,31 @@
+// Package scim implements a SCIM 2.0 (RFC 7643/7644) service provider so an
+// identity provider can provision and deprovision users and groups. Groups
+// exist only for role mapping: membership in a group named in the
+// configured group_roles sets the member's Voyager role.
+package scim
+
+import (
+	"context"
+	"time"
+)
+
+// Group is a provisioned group. Members are user IDs.
+type Group struct {
+	ID          string
+	DisplayName string
+	ExternalID  string
+	Members     []string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// GroupRepository persists groups.
+// Implemented per-driver in store/{postgres,sqlite,mysql}/.
+type GroupRepository interface {
+	// List returns every group ordered by display name.
+	List(ctx context.Context) ([]*Group, error)
+	Get(ctx context.Context, id string) (*Group, error)
+	Create(ctx context.Context, g *Group) error
+	Update(ctx context.Context, g *Group) error
+	Delete(ctx context.Context, id string) error
+}
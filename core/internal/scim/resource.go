@@ -0,0 +1,167 @@
+package scim
+
+import (
+	"strings"
+	"time"
+
+	"data-voyager/core/internal/user"
+)
+
+// Schema URNs.
+const (
+	SchemaUser         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError        = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaSPConfig     = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaResourceType = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+)
+
+// basePath is where the endpoints are mounted; resource locations use it.
+const basePath = "/scim/v2"
+
+// Meta is the common resource metadata.
+type Meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+// Name is a user's name. Voyager keeps a single display name, so only
+// Formatted round-trips; the parts are combined when it is absent.
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// Email is one address of a user. Voyager keeps the primary one.
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// Ref points at another resource: a user's group or a group's member.
+type Ref struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// UserResource is the SCIM representation of a user.
+type UserResource struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	UserName    string   `json:"userName"`
+	Name        *Name    `json:"name,omitempty"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Emails      []Email  `json:"emails,omitempty"`
+	// Active defaults to true when omitted.
+	Active *bool `json:"active,omitempty"`
+	// Groups is read-only; membership is managed through groups.
+	Groups []Ref `json:"groups,omitempty"`
+	Meta   *Meta `json:"meta,omitempty"`
+}
+
+// GroupResource is the SCIM representation of a group.
+type GroupResource struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	DisplayName string   `json:"displayName"`
+	Members     []Ref    `json:"members"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// ListResponse is a page of resources.
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+func userResource(u *user.User, groups []*Group) *UserResource {
+	active := !u.Disabled
+	r := &UserResource{
+		Schemas:     []string{SchemaUser},
+		ID:          u.ID,
+		UserName:    u.Username,
+		DisplayName: u.Name,
+		Active:      &active,
+		Meta: &Meta{
+			ResourceType: "User",
+			Created:      u.CreatedAt,
+			LastModified: u.UpdatedAt,
+			Location:     basePath + "/Users/" + u.ID,
+		},
+	}
+	if u.Name != "" {
+		r.Name = &Name{Formatted: u.Name}
+	}
+	if u.Email != "" {
+		r.Emails = []Email{{Value: u.Email, Type: "work", Primary: true}}
+	}
+	for _, g := range groups {
+		r.Groups = append(r.Groups, Ref{Value: g.ID, Display: g.DisplayName, Ref: basePath + "/Groups/" + g.ID})
+	}
+	return r
+}
+
+// apply copies the attributes Voyager keeps onto u. The role is left
+// alone: it follows group membership.
+func (r *UserResource) apply(u *user.User) {
+	u.Username = r.UserName
+	u.Name = r.DisplayName
+	if r.Name != nil {
+		switch {
+		case r.Name.Formatted != "":
+			u.Name = r.Name.Formatted
+		case r.Name.GivenName != "" || r.Name.FamilyName != "":
+			u.Name = strings.TrimSpace(r.Name.GivenName + " " + r.Name.FamilyName)
+		}
+	}
+	u.Email = ""
+	for i, e := range r.Emails {
+		if i == 0 || e.Primary {
+			u.Email = e.Value
+		}
+		if e.Primary {
+			break
+		}
+	}
+	u.Disabled = r.Active != nil && !*r.Active
+}
+
+func groupResource(g *Group, names map[string]string) *GroupResource {
+	r := &GroupResource{
+		Schemas:     []string{SchemaGroup},
+		ID:          g.ID,
+		ExternalID:  g.ExternalID,
+		DisplayName: g.DisplayName,
+		Members:     []Ref{},
+		Meta: &Meta{
+			ResourceType: "Group",
+			Created:      g.CreatedAt,
+			LastModified: g.UpdatedAt,
+			Location:     basePath + "/Groups/" + g.ID,
+		},
+	}
+	for _, id := range g.Members {
+		r.Members = append(r.Members, Ref{Value: id, Display: names[id], Ref: basePath + "/Users/" + id})
+	}
+	return r
+}
+
+func memberIDs(refs []Ref) []string {
+	ids := make([]string, 0, len(refs))
+	for _, m := range refs {
+		ids = append(ids, m.Value)
+	}
+	return ids
+}
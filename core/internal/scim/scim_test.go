@@ -0,0 +1,222 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/user"
+)
+
+type memUsers struct{ users []*user.User }
+
+func (r *memUsers) List(context.Context) ([]*user.User, error) { return r.users, nil }
+func (r *memUsers) GetByID(_ context.Context, id string) (*user.User, error) {
+	for _, u := range r.users {
+		if u.ID == id {
+			cp := *u
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+func (r *memUsers) GetByUsername(_ context.Context, name string) (*user.User, error) {
+	for _, u := range r.users {
+		if u.Username == name {
+			cp := *u
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+func (r *memUsers) Create(_ context.Context, u *user.User) error {
+	cp := *u
+	r.users = append(r.users, &cp)
+	return nil
+}
+func (r *memUsers) Update(_ context.Context, u *user.User) error {
+	for i, x := range r.users {
+		if x.ID == u.ID {
+			cp := *u
+			r.users[i] = &cp
+		}
+	}
+	return nil
+}
+func (r *memUsers) Delete(_ context.Context, id string) error {
+	r.users = slices.DeleteFunc(r.users, func(u *user.User) bool { return u.ID == id })
+	return nil
+}
+
+type memGroups struct{ groups []*Group }
+
+func (r *memGroups) List(context.Context) ([]*Group, error) {
+	out := make([]*Group, len(r.groups))
+	for i, g := range r.groups {
+		cp := *g
+		cp.Members = slices.Clone(g.Members)
+		out[i] = &cp
+	}
+	return out, nil
+}
+func (r *memGroups) Get(ctx context.Context, id string) (*Group, error) {
+	all, _ := r.List(ctx)
+	for _, g := range all {
+		if g.ID == id {
+			return g, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+func (r *memGroups) Create(_ context.Context, g *Group) error {
+	r.groups = append(r.groups, g)
+	return nil
+}
+func (r *memGroups) Update(_ context.Context, g *Group) error {
+	for i, x := range r.groups {
+		if x.ID == g.ID {
+			r.groups[i] = g
+		}
+	}
+	return nil
+}
+func (r *memGroups) Delete(_ context.Context, id string) error {
+	r.groups = slices.DeleteFunc(r.groups, func(g *Group) bool { return g.ID == id })
+	return nil
+}
+
+func newTestServer(t *testing.T) (*gin.Engine, *memUsers) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	users := &memUsers{}
+	svc, err := NewService(user.NewService(users, nil, connection.Repository(nil)), &memGroups{},
+		config.SCIMConfig{DefaultRole: "viewer", GroupRoles: map[string]string{"voyager admins": "admin", "engineers": "editor"}})
+	require.NoError(t, err)
+	r := gin.New()
+	RegisterRoutes(r, NewHandler(svc, "s3cret"))
+	return r, users
+}
+
+func do(t *testing.T, r http.Handler, method, path, body string) (int, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer s3cret")
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var out map[string]any
+	if w.Body.Len() > 0 {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &out), w.Body.String())
+	}
+	return w.Code, out
+}
+
+func TestNewService_RejectsUnknownRole(t *testing.T) {
+	_, err := NewService(nil, nil, config.SCIMConfig{GroupRoles: map[string]string{"x": "owner"}})
+	assert.Error(t, err)
+}
+
+func TestAuthentication(t *testing.T) {
+	r, _ := newTestServer(t)
+	req := httptest.NewRequest(http.MethodGet, "/scim/v2/Users", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), SchemaError)
+}
+
+func TestUserLifecycle(t *testing.T) {
+	r, users := newTestServer(t)
+
+	code, body := do(t, r, http.MethodPost, "/scim/v2/Users", `{
+		"schemas": ["`+SchemaUser+`"], "userName": "ann@example.com",
+		"name": {"givenName": "Ann", "familyName": "Lee"},
+		"emails": [{"value": "home@example.com"}, {"value": "ann@example.com", "primary": true}]}`)
+	require.Equal(t, http.StatusCreated, code, body)
+	id := body["id"].(string)
+	require.Len(t, users.users, 1)
+	assert.Equal(t, "Ann Lee", users.users[0].Name)
+	assert.Equal(t, "ann@example.com", users.users[0].Email)
+	assert.Equal(t, "viewer", users.users[0].Role)
+
+	code, _ = do(t, r, http.MethodPost, "/scim/v2/Users", `{"userName": "ann@example.com"}`)
+	assert.Equal(t, http.StatusConflict, code)
+
+	code, body = do(t, r, http.MethodGet, `/scim/v2/Users?filter=userName+eq+"ANN@example.com"`, "")
+	require.Equal(t, http.StatusOK, code)
+	assert.EqualValues(t, 1, body["totalResults"])
+	code, _ = do(t, r, http.MethodGet, `/scim/v2/Users?filter=emails+co+"x"`, "")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// Azure AD deactivates with a string boolean.
+	code, body = do(t, r, http.MethodPatch, "/scim/v2/Users/"+id, `{
+		"schemas": ["`+SchemaPatchOp+`"],
+		"Operations": [{"op": "Replace", "path": "active", "value": "False"}]}`)
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Equal(t, false, body["active"])
+	assert.True(t, users.users[0].Disabled)
+
+	code, _ = do(t, r, http.MethodPut, "/scim/v2/Users/"+id, `{"userName": "bob"}`)
+	assert.Equal(t, http.StatusBadRequest, code, "renames are refused")
+
+	code, _ = do(t, r, http.MethodDelete, "/scim/v2/Users/"+id, "")
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Empty(t, users.users)
+	code, _ = do(t, r, http.MethodGet, "/scim/v2/Users/"+id, "")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestGroupRoleMapping(t *testing.T) {
+	r, users := newTestServer(t)
+	_, ann := do(t, r, http.MethodPost, "/scim/v2/Users", `{"userName": "ann"}`)
+	_, bob := do(t, r, http.MethodPost, "/scim/v2/Users", `{"userName": "bob"}`)
+	annID, bobID := ann["id"].(string), bob["id"].(string)
+	role := func(id string) string {
+		u, _ := users.GetByID(context.Background(), id)
+		return u.Role
+	}
+
+	code, eng := do(t, r, http.MethodPost, "/scim/v2/Groups",
+		`{"displayName": "Engineers", "members": [{"value": "`+annID+`"}, {"value": "`+bobID+`"}]}`)
+	require.Equal(t, http.StatusCreated, code, eng)
+	assert.Equal(t, "editor", role(annID))
+	assert.Equal(t, "editor", role(bobID))
+
+	code, adm := do(t, r, http.MethodPost, "/scim/v2/Groups", `{"displayName": "Voyager Admins", "members": []}`)
+	require.Equal(t, http.StatusCreated, code)
+	code, _ = do(t, r, http.MethodPatch, "/scim/v2/Groups/"+adm["id"].(string),
+		`{"Operations": [{"op": "add", "path": "members", "value": [{"value": "`+annID+`"}]}]}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "admin", role(annID), "most privileged mapped group wins")
+
+	code, _ = do(t, r, http.MethodPatch, "/scim/v2/Groups/"+eng["id"].(string),
+		`{"Operations": [{"op": "remove", "path": "members[value eq \"`+bobID+`\"]"}]}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "viewer", role(bobID), "back to the default role")
+
+	code, _ = do(t, r, http.MethodDelete, "/scim/v2/Groups/"+adm["id"].(string), "")
+	require.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, "editor", role(annID))
+
+	_, got := do(t, r, http.MethodGet, "/scim/v2/Users/"+annID, "")
+	groups := got["groups"].([]any)
+	require.Len(t, groups, 1)
+	assert.Equal(t, "Engineers", groups[0].(map[string]any)["display"])
+
+	code, _ = do(t, r, http.MethodPost, "/scim/v2/Groups", `{"displayName": "engineers"}`)
+	assert.Equal(t, http.StatusConflict, code)
+	code, _ = do(t, r, http.MethodPost, "/scim/v2/Groups", `{"displayName": "x", "members": [{"value": "nobody"}]}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -0,0 +1,438 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/user"
+)
+
+var (
+	// ErrInvalid wraps malformed resources and patch operations (400).
+	ErrInvalid = errors.New("invalid value")
+	// ErrInvalidFilter is returned for filters this provider cannot
+	// evaluate (400).
+	ErrInvalidFilter = errors.New("unsupported filter")
+	// ErrNotFound is returned for an unknown user or group (404).
+	ErrNotFound = errors.New("resource not found")
+	// ErrConflict is returned when a userName or displayName is taken (409).
+	ErrConflict = errors.New("resource already exists")
+)
+
+const (
+	defaultCount = 100
+	// maxCount bounds a page; advertised in the ServiceProviderConfig.
+	maxCount = 200
+)
+
+// roleRank orders roles by privilege for users in several mapped groups.
+var roleRank = map[string]int{identity.RoleViewer: 1, identity.RoleEditor: 2, identity.RoleAdmin: 3}
+
+// filterRe matches the single-comparison filters identity providers send
+// to look resources up, e.g. userName eq "ann@example.com".
+var filterRe = regexp.MustCompile(`^\s*([A-Za-z.]+)\s+eq\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// Service provisions users and groups.
+type Service struct {
+	users       *user.Service
+	groups      GroupRepository
+	defaultRole string
+	// groupRoles is keyed by lower-cased display name: config keys are
+	// case-insensitive.
+	groupRoles map[string]string
+	now        func() time.Time
+}
+
+// NewService creates a Service from the SCIM configuration.
+func NewService(users *user.Service, groups GroupRepository, cfg config.SCIMConfig) (*Service, error) {
+	s := &Service{users: users, groups: groups, defaultRole: cfg.DefaultRole, groupRoles: map[string]string{}, now: time.Now}
+	if s.defaultRole == "" {
+		s.defaultRole = identity.RoleViewer
+	}
+	if !identity.ValidRole(s.defaultRole) {
+		return nil, fmt.Errorf("scim: unknown default_role %q", s.defaultRole)
+	}
+	for name, role := range cfg.GroupRoles {
+		if !identity.ValidRole(role) {
+			return nil, fmt.Errorf("scim: group %q maps to unknown role %q", name, role)
+		}
+		s.groupRoles[strings.ToLower(name)] = role
+	}
+	return s, nil
+}
+
+// parseFilter splits a filter into attribute and value. An empty filter
+// yields an empty attribute.
+func parseFilter(filter string) (attr, value string, err error) {
+	if strings.TrimSpace(filter) == "" {
+		return "", "", nil
+	}
+	m := filterRe.FindStringSubmatch(filter)
+	if m == nil {
+		return "", "", fmt.Errorf("%w: %s", ErrInvalidFilter, filter)
+	}
+	return m[1], strings.ReplaceAll(strings.ReplaceAll(m[2], `\"`, `"`), `\\`, `\`), nil
+}
+
+// page slices resources by 1-based startIndex and count.
+func page[T any](items []T, start, count int) *ListResponse {
+	if start < 1 {
+		start = 1
+	}
+	if count < 0 {
+		count = defaultCount
+	}
+	count = min(count, maxCount)
+	res := &ListResponse{Schemas: []string{SchemaListResponse}, TotalResults: len(items), StartIndex: start, Resources: []any{}}
+	for i := start - 1; i < len(items) && len(res.Resources) < count; i++ {
+		res.Resources = append(res.Resources, items[i])
+	}
+	res.ItemsPerPage = len(res.Resources)
+	return res
+}
+
+// ─── users ────────────────────────────────────────────────────────────────────
+
+// ListUsers returns a page of users matching filter, which may compare id
+// or userName (case-insensitively, as the schema defines it).
+func (s *Service) ListUsers(ctx context.Context, filter string, start, count int) (*ListResponse, error) {
+	attr, value, err := parseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.users.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.groups.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []*UserResource
+	for _, u := range users {
+		switch strings.ToLower(attr) {
+		case "":
+		case "id":
+			if u.ID != value {
+				continue
+			}
+		case "username":
+			if !strings.EqualFold(u.Username, value) {
+				continue
+			}
+		default:
+			return nil, fmt.Errorf("%w: cannot filter users by %s", ErrInvalidFilter, attr)
+		}
+		out = append(out, userResource(u, groupsOf(groups, u.ID)))
+	}
+	return page(out, start, count), nil
+}
+
+// GetUser returns one user.
+func (s *Service) GetUser(ctx context.Context, id string) (*UserResource, error) {
+	u, err := s.users.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: user %s", ErrNotFound, id)
+	}
+	groups, err := s.groups.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return userResource(u, groupsOf(groups, u.ID)), nil
+}
+
+// CreateUser provisions a user with the default role.
+func (s *Service) CreateUser(ctx context.Context, r *UserResource) (*UserResource, error) {
+	if r.UserName == "" {
+		return nil, fmt.Errorf("%w: userName is required", ErrInvalid)
+	}
+	if _, err := s.users.GetByUsername(ctx, r.UserName); err == nil {
+		return nil, fmt.Errorf("%w: userName %q", ErrConflict, r.UserName)
+	}
+	u := &user.User{Role: s.defaultRole}
+	r.apply(u)
+	if err := s.users.Create(ctx, u); err != nil {
+		return nil, mapUserErr(err)
+	}
+	return userResource(u, nil), nil
+}
+
+// ReplaceUser overwrites a user's attributes. A userName change is
+// rejected: Voyager keys ownership and audit records by username.
+func (s *Service) ReplaceUser(ctx context.Context, id string, r *UserResource) (*UserResource, error) {
+	u, err := s.users.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: user %s", ErrNotFound, id)
+	}
+	if r.UserName != "" && !strings.EqualFold(r.UserName, u.Username) {
+		return nil, fmt.Errorf("%w: userName cannot be changed", ErrInvalid)
+	}
+	r.UserName = u.Username
+	r.apply(u)
+	if err := s.users.Update(ctx, u); err != nil {
+		return nil, mapUserErr(err)
+	}
+	return s.GetUser(ctx, id)
+}
+
+// PatchUser applies patch operations to a user.
+func (s *Service) PatchUser(ctx context.Context, id string, ops []Operation) (*UserResource, error) {
+	r, err := s.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range ops {
+		if err := patchUser(r, op); err != nil {
+			return nil, err
+		}
+	}
+	return s.ReplaceUser(ctx, id, r)
+}
+
+// DeleteUser deprovisions a user, removing them from every group.
+func (s *Service) DeleteUser(ctx context.Context, id string) error {
+	if _, err := s.users.Get(ctx, id); err != nil {
+		return fmt.Errorf("%w: user %s", ErrNotFound, id)
+	}
+	groups, err := s.groups.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, g := range groupsOf(groups, id) {
+		g.Members = slices.DeleteFunc(g.Members, func(m string) bool { return m == id })
+		g.UpdatedAt = s.now().UTC()
+		if err := s.groups.Update(ctx, g); err != nil {
+			return err
+		}
+	}
+	return s.users.Delete(ctx, id)
+}
+
+func mapUserErr(err error) error {
+	switch {
+	case errors.Is(err, user.ErrInvalid):
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	case errors.Is(err, user.ErrNotFound):
+		return fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	return err
+}
+
+func groupsOf(groups []*Group, userID string) []*Group {
+	var out []*Group
+	for _, g := range groups {
+		if slices.Contains(g.Members, userID) {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// ─── groups ───────────────────────────────────────────────────────────────────
+
+// ListGroups returns a page of groups matching filter, which may compare
+// id, displayName or externalId.
+func (s *Service) ListGroups(ctx context.Context, filter string, start, count int) (*ListResponse, error) {
+	attr, value, err := parseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.groups.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names, err := s.usernames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []*GroupResource
+	for _, g := range groups {
+		switch strings.ToLower(attr) {
+		case "":
+		case "id":
+			if g.ID != value {
+				continue
+			}
+		case "displayname":
+			if !strings.EqualFold(g.DisplayName, value) {
+				continue
+			}
+		case "externalid":
+			if g.ExternalID != value {
+				continue
+			}
+		default:
+			return nil, fmt.Errorf("%w: cannot filter groups by %s", ErrInvalidFilter, attr)
+		}
+		out = append(out, groupResource(g, names))
+	}
+	return page(out, start, count), nil
+}
+
+// GetGroup returns one group.
+func (s *Service) GetGroup(ctx context.Context, id string) (*GroupResource, error) {
+	g, err := s.groups.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: group %s", ErrNotFound, id)
+	}
+	names, err := s.usernames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return groupResource(g, names), nil
+}
+
+// CreateGroup stores a group and applies its role to the members.
+func (s *Service) CreateGroup(ctx context.Context, r *GroupResource) (*GroupResource, error) {
+	now := s.now().UTC()
+	g := &Group{ID: uuid.NewString(), ExternalID: r.ExternalID, DisplayName: r.DisplayName, CreatedAt: now, UpdatedAt: now}
+	if err := s.setGroup(ctx, g, r.DisplayName, memberIDs(r.Members)); err != nil {
+		return nil, err
+	}
+	if err := s.groups.Create(ctx, g); err != nil {
+		return nil, err
+	}
+	if err := s.syncRoles(ctx, g.Members); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, g.ID)
+}
+
+// ReplaceGroup overwrites a group's name and members.
+func (s *Service) ReplaceGroup(ctx context.Context, id string, r *GroupResource) (*GroupResource, error) {
+	g, err := s.groups.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: group %s", ErrNotFound, id)
+	}
+	return s.saveGroup(ctx, g, r.DisplayName, r.ExternalID, memberIDs(r.Members))
+}
+
+// PatchGroup applies patch operations to a group. Identity providers
+// mostly use it to add and remove members one at a time.
+func (s *Service) PatchGroup(ctx context.Context, id string, ops []Operation) (*GroupResource, error) {
+	g, err := s.groups.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: group %s", ErrNotFound, id)
+	}
+	name, externalID, members := g.DisplayName, g.ExternalID, slices.Clone(g.Members)
+	for _, op := range ops {
+		if err := patchGroup(&name, &externalID, &members, op); err != nil {
+			return nil, err
+		}
+	}
+	return s.saveGroup(ctx, g, name, externalID, members)
+}
+
+// DeleteGroup removes a group; its former members' roles are recomputed.
+func (s *Service) DeleteGroup(ctx context.Context, id string) error {
+	g, err := s.groups.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%w: group %s", ErrNotFound, id)
+	}
+	if err := s.groups.Delete(ctx, id); err != nil {
+		return err
+	}
+	return s.syncRoles(ctx, g.Members)
+}
+
+func (s *Service) saveGroup(ctx context.Context, g *Group, name, externalID string, members []string) (*GroupResource, error) {
+	affected := slices.Clone(g.Members)
+	if err := s.setGroup(ctx, g, name, members); err != nil {
+		return nil, err
+	}
+	g.ExternalID, g.UpdatedAt = externalID, s.now().UTC()
+	if err := s.groups.Update(ctx, g); err != nil {
+		return nil, err
+	}
+	if err := s.syncRoles(ctx, append(affected, g.Members...)); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, g.ID)
+}
+
+// setGroup validates a new name and member list and sets them on g.
+func (s *Service) setGroup(ctx context.Context, g *Group, name string, members []string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%w: displayName is required", ErrInvalid)
+	}
+	groups, err := s.groups.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, other := range groups {
+		if other.ID != g.ID && strings.EqualFold(other.DisplayName, name) {
+			return fmt.Errorf("%w: displayName %q", ErrConflict, name)
+		}
+	}
+	slices.Sort(members)
+	members = slices.Compact(members)
+	for _, id := range members {
+		if _, err := s.users.Get(ctx, id); err != nil {
+			return fmt.Errorf("%w: member %s is not a user", ErrInvalid, id)
+		}
+	}
+	if members == nil {
+		members = []string{}
+	}
+	g.DisplayName, g.Members = name, members
+	return nil
+}
+
+// syncRoles gives each of userIDs the most privileged role of their mapped
+// groups, or the default role when they are in none. Nothing changes when
+// no group is mapped, so groups stay inert until configured.
+func (s *Service) syncRoles(ctx context.Context, userIDs []string) error {
+	if len(s.groupRoles) == 0 || len(userIDs) == 0 {
+		return nil
+	}
+	groups, err := s.groups.List(ctx)
+	if err != nil {
+		return err
+	}
+	slices.Sort(userIDs)
+	for _, id := range slices.Compact(userIDs) {
+		role := ""
+		for _, g := range groupsOf(groups, id) {
+			if r := s.groupRoles[strings.ToLower(g.DisplayName)]; roleRank[r] > roleRank[role] {
+				role = r
+			}
+		}
+		if role == "" {
+			role = s.defaultRole
+		}
+		u, err := s.users.Get(ctx, id)
+		if err != nil {
+			continue // deleted meanwhile
+		}
+		if u.Role == role {
+			continue
+		}
+		u.Role = role
+		if err := s.users.Update(ctx, u); err != nil {
+			return fmt.Errorf("set role of %s: %w", u.Username, err)
+		}
+	}
+	return nil
+}
+
+// usernames maps user IDs to usernames for member display values.
+func (s *Service) usernames(ctx context.Context) (map[string]string, error) {
+	users, err := s.users.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(users))
+	for _, u := range users {
+		names[u.ID] = u.Username
+	}
+	return names, nil
+}
@@ -0,0 +1,104 @@
+package seed
+
+// Kind selects how values for a column are generated and which SQL type the
+// column gets.
+type Kind string
+
+const (
+	KindID      Kind = "id"     // 1..n, primary key
+	KindRef     Kind = "ref"    // random id of another table in the dataset
+	KindInt     Kind = "int"    // small positive integer
+	KindFloat   Kind = "float"  // amount with two decimals
+	KindName    Kind = "name"   // person name
+	KindEmail   Kind = "email"  // derived from the row id, unique
+	KindChoice  Kind = "choice" // one of Column.Choices
+	KindTime    Kind = "time"   // within the last Request.Days days
+	KindBool    Kind = "bool"
+	KindProduct Kind = "product" // product name
+)
+
+// Column is one generated column.
+type Column struct {
+	Name    string
+	Kind    Kind
+	Ref     string   // table name for KindRef
+	Choices []string // values for KindChoice
+	Min     float64  // lower bound for KindInt/KindFloat
+	Max     float64  // upper bound for KindInt/KindFloat
+}
+
+// Table is one generated table. Tables are created in dataset order, so
+// referenced tables must come first.
+type Table struct {
+	Name        string
+	Columns     []Column
+	DefaultRows int
+}
+
+// Dataset is a named set of related demo tables.
+type Dataset struct {
+	Name        string
+	Description string
+	Tables      []Table
+}
+
+var (
+	cities    = []string{"Seoul", "Berlin", "Austin", "Lisbon", "Osaka", "Toronto", "Nairobi", "Melbourne", "Lyon", "Bogotá"}
+	countries = []string{"KR", "DE", "US", "PT", "JP", "CA", "KE", "AU", "FR", "CO"}
+)
+
+// datasets are the built-in datasets, keyed by name.
+var datasets = map[string]Dataset{
+	"ecommerce": {
+		Name:        "ecommerce",
+		Description: "Customers, products and orders for a small online shop.",
+		Tables: []Table{
+			{Name: "customers", DefaultRows: 1000, Columns: []Column{
+				{Name: "id", Kind: KindID},
+				{Name: "name", Kind: KindName},
+				{Name: "email", Kind: KindEmail},
+				{Name: "city", Kind: KindChoice, Choices: cities},
+				{Name: "country", Kind: KindChoice, Choices: countries},
+				{Name: "is_active", Kind: KindBool},
+				{Name: "created_at", Kind: KindTime},
+			}},
+			{Name: "products", DefaultRows: 100, Columns: []Column{
+				{Name: "id", Kind: KindID},
+				{Name: "name", Kind: KindProduct},
+				{Name: "category", Kind: KindChoice, Choices: []string{"books", "electronics", "garden", "kitchen", "outdoor", "toys"}},
+				{Name: "price", Kind: KindFloat, Min: 2, Max: 500},
+			}},
+			{Name: "orders", DefaultRows: 10000, Columns: []Column{
+				{Name: "id", Kind: KindID},
+				{Name: "customer_id", Kind: KindRef, Ref: "customers"},
+				{Name: "product_id", Kind: KindRef, Ref: "products"},
+				{Name: "quantity", Kind: KindInt, Min: 1, Max: 5},
+				{Name: "amount", Kind: KindFloat, Min: 2, Max: 2500},
+				{Name: "status", Kind: KindChoice, Choices: []string{"pending", "paid", "shipped", "delivered", "refunded"}},
+				{Name: "ordered_at", Kind: KindTime},
+			}},
+		},
+	},
+	"metrics": {
+		Name:        "metrics",
+		Description: "Host metrics as a single time-series table.",
+		Tables: []Table{
+			{Name: "host_metrics", DefaultRows: 50000, Columns: []Column{
+				{Name: "id", Kind: KindID},
+				{Name: "ts", Kind: KindTime},
+				{Name: "host", Kind: KindChoice, Choices: []string{"web-1", "web-2", "web-3", "db-1", "db-2", "worker-1"}},
+				{Name: "metric", Kind: KindChoice, Choices: []string{"cpu", "memory", "disk", "net_in", "net_out"}},
+				{Name: "value", Kind: KindFloat, Min: 0, Max: 100},
+			}},
+		},
+	},
+}
+
+// Datasets returns the built-in datasets.
+func Datasets() []Dataset {
+	out := make([]Dataset, 0, len(datasets))
+	for _, name := range []string{"ecommerce", "metrics"} {
+		out = append(out, datasets[name])
+	}
+	return out
+}
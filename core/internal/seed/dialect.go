@@ -0,0 +1,89 @@
+package seed
+
+import (
+	"fmt"
+	"strings"
+
+	"data-voyager/sdk"
+)
+
+// sqlTypes maps column kinds to column types per datasource type. Types not
+// listed use the "" entry, which is plain ANSI SQL.
+var sqlTypes = map[sdk.DataSourceType]map[Kind]string{
+	"": {
+		KindID: "BIGINT", KindRef: "BIGINT", KindInt: "INTEGER", KindFloat: "DECIMAL(12,2)",
+		KindTime: "TIMESTAMP", KindBool: "BOOLEAN", "": "VARCHAR(255)",
+	},
+	"postgresql": {
+		KindID: "BIGINT PRIMARY KEY", KindRef: "BIGINT", KindInt: "INTEGER", KindFloat: "NUMERIC(12,2)",
+		KindTime: "TIMESTAMP", KindBool: "BOOLEAN", "": "TEXT",
+	},
+	"cockroachdb": {
+		KindID: "INT8 PRIMARY KEY", KindRef: "INT8", KindInt: "INT8", KindFloat: "DECIMAL(12,2)",
+		KindTime: "TIMESTAMP", KindBool: "BOOL", "": "STRING",
+	},
+	"clickhouse": {
+		KindID: "UInt64", KindRef: "UInt64", KindInt: "Int32", KindFloat: "Float64",
+		KindTime: "DateTime", KindBool: "Bool", "": "String",
+	},
+}
+
+func columnType(dsType sdk.DataSourceType, kind Kind) string {
+	types, ok := sqlTypes[dsType]
+	if !ok {
+		types = sqlTypes[""]
+	}
+	if t, ok := types[kind]; ok {
+		return t
+	}
+	return types[""]
+}
+
+// quoteIdent quotes name with the dialect's identifier quote.
+func quoteIdent(d sdk.Dialect, name string) string {
+	q := d.IdentifierQuote
+	if q == "" {
+		q = `"`
+	}
+	return q + strings.ReplaceAll(name, q, q+q) + q
+}
+
+func createTableSQL(dsType sdk.DataSourceType, d sdk.Dialect, name string, t Table) string {
+	cols := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		cols[i] = quoteIdent(d, c.Name) + " " + columnType(dsType, c.Kind)
+	}
+	stmt := fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdent(d, name), strings.Join(cols, ", "))
+	if dsType == "clickhouse" {
+		stmt += " ENGINE = MergeTree ORDER BY " + quoteIdent(d, t.Columns[0].Name)
+	}
+	return stmt
+}
+
+func dropTableSQL(d sdk.Dialect, name string) string {
+	return "DROP TABLE IF EXISTS " + quoteIdent(d, name)
+}
+
+// insertSQL renders a multi-row INSERT for rows [from, to] of t.
+func insertSQL(d sdk.Dialect, g *generator, name string, t Table, from, to int) string {
+	cols := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		cols[i] = quoteIdent(d, c.Name)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "INSERT INTO %s (%s) VALUES ", quoteIdent(d, name), strings.Join(cols, ", "))
+	for id := from; id <= to; id++ {
+		if id > from {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for i, c := range t.Columns {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(g.value(c, id))
+		}
+		b.WriteByte(')')
+	}
+	return b.String()
+}
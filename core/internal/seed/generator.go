@@ -0,0 +1,76 @@
+package seed
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	firstNames = []string{"Ada", "Alan", "Grace", "Linus", "Margaret", "Dennis", "Barbara", "Ken", "Frances", "Edsger", "Radia", "Guido", "Hedy", "Donald", "Sophie", "Yukihiro"}
+	lastNames  = []string{"Lovelace", "Turing", "Hopper", "Torvalds", "Hamilton", "Ritchie", "Liskov", "Thompson", "Allen", "Dijkstra", "Perlman", "Rossum", "Lamarr", "Knuth", "Wilson", "Matsumoto"}
+	adjectives = []string{"Compact", "Deluxe", "Eco", "Classic", "Smart", "Portable", "Rugged", "Mini", "Pro", "Vintage"}
+	nouns      = []string{"Lamp", "Kettle", "Backpack", "Speaker", "Notebook", "Planter", "Blender", "Tent", "Puzzle", "Headphones"}
+)
+
+// generator produces SQL literals for column values. It is deterministic for
+// a given seed so demo data is reproducible.
+type generator struct {
+	rnd  *rand.Rand
+	now  time.Time
+	days int
+	rows map[string]int // row count per table, for KindRef
+}
+
+func newGenerator(seed uint64, now time.Time, days int, rows map[string]int) *generator {
+	return &generator{
+		rnd:  rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)),
+		now:  now.UTC().Truncate(time.Second),
+		days: days,
+		rows: rows,
+	}
+}
+
+// value returns the SQL literal for col in the row with the given 1-based id.
+func (g *generator) value(col Column, id int) string {
+	switch col.Kind {
+	case KindID:
+		return strconv.Itoa(id)
+	case KindRef:
+		return strconv.Itoa(1 + g.rnd.IntN(max(g.rows[col.Ref], 1)))
+	case KindInt:
+		lo, hi := int(col.Min), int(col.Max)
+		return strconv.Itoa(lo + g.rnd.IntN(max(hi-lo+1, 1)))
+	case KindFloat:
+		return strconv.FormatFloat(col.Min+g.rnd.Float64()*(col.Max-col.Min), 'f', 2, 64)
+	case KindName:
+		return quote(pick(g.rnd, firstNames) + " " + pick(g.rnd, lastNames))
+	case KindEmail:
+		return quote(fmt.Sprintf("%s.%d@example.com", strings.ToLower(pick(g.rnd, firstNames)), id))
+	case KindProduct:
+		return quote(pick(g.rnd, adjectives) + " " + pick(g.rnd, nouns))
+	case KindChoice:
+		return quote(pick(g.rnd, col.Choices))
+	case KindTime:
+		span := time.Duration(g.days) * 24 * time.Hour
+		t := g.now.Add(-time.Duration(g.rnd.Int64N(int64(span))))
+		return quote(t.Truncate(time.Second).Format("2006-01-02 15:04:05"))
+	case KindBool:
+		if g.rnd.IntN(10) < 8 {
+			return "true"
+		}
+		return "false"
+	}
+	return "NULL"
+}
+
+func pick(rnd *rand.Rand, values []string) string {
+	return values[rnd.IntN(len(values))]
+}
+
+// quote renders s as a single-quoted SQL string literal.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
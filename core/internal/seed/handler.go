@@ -0,0 +1,95 @@
+package seed
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// Handler serves the demo data endpoints.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates a seed HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// ─── wire types ───────────────────────────────────────────────────────────────
+
+type seedRequest struct {
+	Dataset      string         `json:"dataset" binding:"required"`
+	Prefix       string         `json:"prefix"`
+	Rows         map[string]int `json:"rows"`
+	Days         int            `json:"days"`
+	Seed         uint64         `json:"seed"`
+	DropExisting bool           `json:"drop_existing"`
+}
+
+type datasetResponse struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Tables      map[string]int `json:"tables"`
+}
+
+// ─── handlers ─────────────────────────────────────────────────────────────────
+
+// ListDatasets handles GET /seed/datasets
+func (h *Handler) ListDatasets(c *gin.Context) {
+	all := Datasets()
+	resp := make([]datasetResponse, len(all))
+	for i, ds := range all {
+		tables := make(map[string]int, len(ds.Tables))
+		for _, t := range ds.Tables {
+			tables[t.Name] = t.DefaultRows
+		}
+		resp[i] = datasetResponse{Name: ds.Name, Description: ds.Description, Tables: tables}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// Seed handles POST /datasources/:uid/seed. It creates, and may drop,
+// tables on the datasource, so it needs the datasource write permission.
+func (h *Handler) Seed(c *gin.Context) {
+	if !identity.FromContext(c.Request.Context()).Can(identity.PermDatasourceWrite) {
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "permission denied")})
+		return
+	}
+	var body seedRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "invalid request body")})
+		return
+	}
+	result, err := h.svc.Seed(c.Request.Context(), c.Param("uid"), Request{
+		Dataset:      body.Dataset,
+		Prefix:       body.Prefix,
+		Rows:         body.Rows,
+		Days:         body.Days,
+		Seed:         body.Seed,
+		DropExisting: body.DropExisting,
+	})
+	if err != nil {
+		status := http.StatusBadGateway
+		switch {
+		case errors.Is(err, ErrInvalidRequest):
+			status = http.StatusBadRequest
+		case errors.Is(err, connection.ErrNotFound):
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": result})
+}
+
+// RegisterRoutes wires seed routes onto r.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/seed/datasets", h.ListDatasets)
+	r.POST("/datasources/:uid/seed", h.Seed)
+}
@@ -0,0 +1,157 @@
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/identity"
+	"data-voyager/sdk"
+	"data-voyager/sdk/pluginsdk"
+)
+
+type stubConns struct {
+	connection.Repository
+	conn *connection.Connection
+}
+
+func (s *stubConns) GetByID(_ context.Context, id string) (*connection.Connection, error) {
+	if s.conn == nil || s.conn.ID != id {
+		return nil, fmt.Errorf("connection %s %w", id, connection.ErrNotFound)
+	}
+	return s.conn, nil
+}
+
+type stubConfig struct{}
+
+func (stubConfig) Validate() error             { return nil }
+func (stubConfig) GetConnectionString() string { return "" }
+
+// recordingPlugin captures the statements run against it.
+type recordingPlugin struct {
+	typ   sdk.DataSourceType
+	stmts []string
+}
+
+func (p *recordingPlugin) GetType() sdk.DataSourceType { return p.typ }
+func (p *recordingPlugin) GetName() string             { return "Recording" }
+func (p *recordingPlugin) ParseConfig(json.RawMessage) (sdk.ConnectionConfig, error) {
+	return stubConfig{}, nil
+}
+func (p *recordingPlugin) ValidateConfig(any) error { return nil }
+func (p *recordingPlugin) Connect(context.Context, sdk.ConnectionConfig) (sdk.Connection, error) {
+	return &recordingConn{p: p}, nil
+}
+func (p *recordingPlugin) TestConnection(context.Context, sdk.ConnectionConfig) (*sdk.ConnectionTestResult, error) {
+	return &sdk.ConnectionTestResult{IsConnected: true}, nil
+}
+func (p *recordingPlugin) Capabilities() sdk.Capabilities {
+	return sdk.Capabilities{Exec: true, Writes: true, Schemas: true}
+}
+
+type recordingConn struct {
+	sdk.Connection
+	p *recordingPlugin
+}
+
+func (c *recordingConn) Query(_ context.Context, q string, _ ...any) (*sdk.QueryResult, error) {
+	c.p.stmts = append(c.p.stmts, q)
+	return pluginsdk.EmptyResult(), nil
+}
+func (c *recordingConn) Close() error { return nil }
+
+func newTestService(p *recordingPlugin) *Service {
+	reg := datasource.NewRegistry()
+	reg.Register(p)
+	conns := &stubConns{conn: &connection.Connection{ID: "ds1", Type: p.typ, IsActive: true}}
+	svc := NewService(conns, reg)
+	svc.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+	return svc
+}
+
+func TestSeed_Ecommerce(t *testing.T) {
+	p := &recordingPlugin{typ: "postgresql"}
+	svc := newTestService(p)
+
+	res, err := svc.Seed(context.Background(), "ds1", Request{
+		Dataset:      "ecommerce",
+		Rows:         map[string]int{"customers": 10, "products": 5, "orders": 1200},
+		Seed:         42,
+		DropExisting: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []TableResult{
+		{Name: "demo_customers", Rows: 10},
+		{Name: "demo_products", Rows: 5},
+		{Name: "demo_orders", Rows: 1200},
+	}, res.Tables)
+
+	// drop + create per table, one insert for each small table, three batches for orders.
+	require.Len(t, p.stmts, 3*2+1+1+3)
+	assert.Equal(t, `DROP TABLE IF EXISTS "demo_customers"`, p.stmts[0])
+	assert.True(t, strings.HasPrefix(p.stmts[1], `CREATE TABLE "demo_customers" ("id" BIGINT PRIMARY KEY,`))
+	assert.Contains(t, p.stmts[2], `INSERT INTO "demo_customers"`)
+}
+
+func TestSeed_Deterministic(t *testing.T) {
+	run := func() []string {
+		p := &recordingPlugin{typ: "clickhouse"}
+		_, err := newTestService(p).Seed(context.Background(), "ds1", Request{
+			Dataset: "metrics", Rows: map[string]int{"host_metrics": 20}, Seed: 7,
+		})
+		require.NoError(t, err)
+		return p.stmts
+	}
+	first := run()
+	assert.Equal(t, first, run())
+	assert.Contains(t, first[0], `ENGINE = MergeTree ORDER BY "id"`)
+}
+
+func TestSeed_Validation(t *testing.T) {
+	svc := newTestService(&recordingPlugin{typ: "postgresql"})
+	ctx := context.Background()
+
+	_, err := svc.Seed(ctx, "ds1", Request{Dataset: "nope"})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	_, err = svc.Seed(ctx, "ds1", Request{Dataset: "ecommerce", Rows: map[string]int{"orders": 0}})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	_, err = svc.Seed(ctx, "ds1", Request{Dataset: "ecommerce", Rows: map[string]int{"invoices": 5}})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+}
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, `'O''Brien'`, quote("O'Brien"))
+}
+
+func TestHandler_SeedNeedsWriteOnAVisibleDatasource(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	p := &recordingPlugin{typ: "postgresql"}
+	h := NewHandler(newTestService(p))
+	seed := func(id *identity.Identity) int {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "uid", Value: "ds1"}}
+		c.Request = httptest.NewRequest(http.MethodPost, "/datasources/ds1/seed",
+			strings.NewReader(`{"dataset":"ecommerce","drop_existing":true}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request = c.Request.WithContext(identity.With(c.Request.Context(), id))
+		h.Seed(c)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, seed(&identity.Identity{Username: "ann", Role: identity.RoleViewer}))
+	assert.Equal(t, http.StatusNotFound, seed(&identity.Identity{Username: "ed", Role: identity.RoleEditor, Datasources: []string{"other"}}))
+	assert.Empty(t, p.stmts, "nothing reaches the datasource")
+	assert.Equal(t, http.StatusCreated, seed(&identity.Identity{Username: "ed", Role: identity.RoleEditor}))
+}
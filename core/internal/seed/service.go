@@ -0,0 +1,152 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/sdk"
+)
+
+const (
+	maxRowsPerTable = 1_000_000
+	batchSize       = 500
+	defaultDays     = 90
+)
+
+// ErrInvalidRequest wraps validation failures so handlers can map them to 400.
+var ErrInvalidRequest = errors.New("invalid seed request")
+
+// Request describes what to generate.
+type Request struct {
+	Dataset string
+	// Prefix is prepended to every table name. Defaults to "demo_".
+	Prefix string
+	// Rows overrides the default row count per table (unprefixed name).
+	Rows map[string]int
+	// Days is the window, ending now, that generated timestamps fall in.
+	Days int
+	// Seed makes the generated data reproducible; 0 uses the current time.
+	Seed uint64
+	// DropExisting drops the tables before creating them.
+	DropExisting bool
+}
+
+// TableResult reports one created table.
+type TableResult struct {
+	Name string `json:"name"`
+	Rows int    `json:"rows"`
+}
+
+// Result reports what Seed created.
+type Result struct {
+	Dataset    string        `json:"dataset"`
+	Seed       uint64        `json:"seed"`
+	Tables     []TableResult `json:"tables"`
+	DurationMS int64         `json:"duration_ms"`
+}
+
+// Service creates and populates demo tables on a datasource.
+type Service struct {
+	conns    connection.Repository
+	registry *datasource.Registry
+	now      func() time.Time
+}
+
+// NewService creates a Service.
+func NewService(conns connection.Repository, registry *datasource.Registry) *Service {
+	return &Service{conns: connection.Visible(conns), registry: registry, now: time.Now}
+}
+
+// Seed creates the tables of req.Dataset on the datasource and fills them
+// with generated rows. Tables that already exist make it fail unless
+// DropExisting is set.
+func (s *Service) Seed(ctx context.Context, datasourceID string, req Request) (*Result, error) {
+	ds, ok := datasets[req.Dataset]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown dataset %q", ErrInvalidRequest, req.Dataset)
+	}
+	rows, err := rowCounts(ds, req.Rows)
+	if err != nil {
+		return nil, err
+	}
+	if req.Prefix == "" {
+		req.Prefix = "demo_"
+	}
+	if req.Days <= 0 {
+		req.Days = defaultDays
+	}
+	start := s.now()
+	if req.Seed == 0 {
+		req.Seed = uint64(start.UnixNano())
+	}
+
+	conn, err := s.conns.GetByID(ctx, datasourceID)
+	if err != nil {
+		return nil, fmt.Errorf("datasource %w", connection.ErrNotFound)
+	}
+	if !conn.IsActive {
+		return nil, fmt.Errorf("%w: datasource is not active", ErrInvalidRequest)
+	}
+	plugin, ok := s.registry.Get(conn.Type)
+	if !ok {
+		return nil, fmt.Errorf("plugin not found for type %s", conn.Type)
+	}
+	if caps := sdk.PluginCapabilities(plugin); !caps.Exec || !caps.Writes {
+		return nil, fmt.Errorf("%w: %s datasources do not accept writes", ErrInvalidRequest, conn.Type)
+	}
+	cfg, err := plugin.ParseConfig(conn.Config)
+	if err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	dbConn, err := plugin.Connect(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer func() { _ = dbConn.Close() }()
+
+	dialect := sdk.PluginDialect(plugin)
+	gen := newGenerator(req.Seed, start, req.Days, rows)
+	result := &Result{Dataset: ds.Name, Seed: req.Seed}
+	for _, t := range ds.Tables {
+		name := req.Prefix + t.Name
+		if req.DropExisting {
+			if _, err := dbConn.Query(ctx, dropTableSQL(dialect, name)); err != nil {
+				return nil, fmt.Errorf("drop %s: %w", name, err)
+			}
+		}
+		if _, err := dbConn.Query(ctx, createTableSQL(conn.Type, dialect, name, t)); err != nil {
+			return nil, fmt.Errorf("create %s: %w", name, err)
+		}
+		n := rows[t.Name]
+		for from := 1; from <= n; from += batchSize {
+			to := min(from+batchSize-1, n)
+			if _, err := dbConn.Query(ctx, insertSQL(dialect, gen, name, t, from, to)); err != nil {
+				return nil, fmt.Errorf("insert into %s: %w", name, err)
+			}
+		}
+		result.Tables = append(result.Tables, TableResult{Name: name, Rows: n})
+	}
+	result.DurationMS = s.now().Sub(start).Milliseconds()
+	return result, nil
+}
+
+func rowCounts(ds Dataset, overrides map[string]int) (map[string]int, error) {
+	rows := make(map[string]int, len(ds.Tables))
+	for _, t := range ds.Tables {
+		rows[t.Name] = t.DefaultRows
+	}
+	for name, n := range overrides {
+		if _, ok := rows[name]; !ok {
+			return nil, fmt.Errorf("%w: dataset %s has no table %q", ErrInvalidRequest, ds.Name, name)
+		}
+		if n < 1 || n > maxRowsPerTable {
+			return nil, fmt.Errorf("%w: rows for %s must be between 1 and %d", ErrInvalidRequest, name, maxRowsPerTable)
+		}
+		rows[name] = n
+	}
+	return rows, nil
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/i18n"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,7 +24,7 @@ func NewHandler(svc *Service, tomlCfg *config.AIConfig) *Handler {
 func (h *Handler) GetAISettings(c *gin.Context) {
 	resp, err := h.svc.BuildAIConfigResponse(c.Request.Context(), h.tomlCfg)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load settings"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to load settings")})
 		return
 	}
 	c.JSON(http.StatusOK, resp)
@@ -54,7 +55,7 @@ type ollamaBody struct {
 func (h *Handler) UpdateAISettings(c *gin.Context) {
 	var body putAISettingsBody
 	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "invalid request body")})
 		return
 	}
 
@@ -87,7 +88,7 @@ func (h *Handler) UpdateAISettings(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save settings"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.T(c, "failed to save settings")})
 		return
 	}
 
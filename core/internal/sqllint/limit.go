@@ -0,0 +1,62 @@
+package sqllint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// limitBlockers are top-level keywords after which an appended LIMIT would
+// be misplaced or change what the statement does.
+var limitBlockers = []string{
+	"LIMIT", "FETCH", "TOP", "OFFSET", "FOR", "INTO", "SETTINGS", "FORMAT",
+	"UNION", "INTERSECT", "EXCEPT",
+}
+
+// writeKeywords mark a data-modifying statement, at any depth since a WITH
+// clause may hold one.
+var writeKeywords = []string{"INSERT", "UPDATE", "DELETE", "MERGE"}
+
+// AddLimit appends "LIMIT n" to sql when it is a single SELECT that reads a
+// table and has no row limit of its own, and reports whether it did. Like
+// Lint it stays out of anything it cannot read with confidence: several
+// statements, writes, set operations, locking or INTO clauses, dialect
+// trailers such as SETTINGS, and aggregates without GROUP BY, which return
+// one row anyway.
+func AddLimit(sql string, n int) (string, bool) {
+	tokens := tokenize(sql)
+	stmts := splitStatements(tokens)
+	if n <= 0 || len(stmts) != 1 || !stmts[0][0].is("SELECT", "WITH") {
+		return sql, false
+	}
+	stmt := stmts[0]
+	var top *scope
+	for i, t := range stmt {
+		if t.is(writeKeywords...) {
+			return sql, false
+		}
+		if t.depth != 0 {
+			continue
+		}
+		if t.is(limitBlockers...) {
+			return sql, false
+		}
+		if t.is("SELECT") && top == nil {
+			top = readScope(stmt, i)
+		}
+	}
+	if top == nil || len(top.tables) == 0 || (top.grouped && !top.has("group")) {
+		return sql, false
+	}
+
+	text := strings.TrimSpace(sql)
+	if tokens[len(tokens)-1].is(";") {
+		// A comment after the semicolon would need the token positions
+		// the tokenizer does not keep.
+		if !strings.HasSuffix(text, ";") {
+			return sql, false
+		}
+		text = strings.TrimSpace(strings.TrimSuffix(text, ";"))
+	}
+	// The newline ends a trailing line comment.
+	return fmt.Sprintf("%s\nLIMIT %d", text, n), true
+}
@@ -0,0 +1,46 @@
+package sqllint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddLimit(t *testing.T) {
+	for sql, want := range map[string]string{
+		"SELECT * FROM events":                                           "SELECT * FROM events\nLIMIT 100",
+		"SELECT * FROM events;\n":                                        "SELECT * FROM events\nLIMIT 100",
+		"SELECT * FROM events -- all of them":                            "SELECT * FROM events -- all of them\nLIMIT 100",
+		"SELECT kind, count(*) FROM events GROUP BY kind":                "SELECT kind, count(*) FROM events GROUP BY kind\nLIMIT 100",
+		"WITH e AS (SELECT * FROM events LIMIT 5) SELECT * FROM e":       "WITH e AS (SELECT * FROM events LIMIT 5) SELECT * FROM e\nLIMIT 100",
+		"SELECT * FROM users WHERE id IN (SELECT u FROM events LIMIT 3)": "SELECT * FROM users WHERE id IN (SELECT u FROM events LIMIT 3)\nLIMIT 100",
+	} {
+		got, ok := AddLimit(sql, 100)
+		assert.True(t, ok, sql)
+		assert.Equal(t, want, got, sql)
+	}
+
+	for _, sql := range []string{
+		"SELECT * FROM events LIMIT 10",
+		"SELECT * FROM events FETCH FIRST 10 ROWS ONLY",
+		"SELECT * FROM events OFFSET 10",
+		"SELECT count(*) FROM events",
+		"SELECT 1",
+		"SELECT * FROM a UNION ALL SELECT * FROM b",
+		"SELECT * FROM events FOR UPDATE",
+		"SELECT * INTO copy FROM events",
+		"SELECT * FROM events SETTINGS max_threads = 1",
+		"SELECT * FROM a; SELECT * FROM b",
+		"SELECT * FROM events; -- done",
+		"WITH d AS (DELETE FROM events RETURNING *) SELECT * FROM d",
+		"DELETE FROM events",
+		"",
+	} {
+		got, ok := AddLimit(sql, 100)
+		assert.False(t, ok, sql)
+		assert.Equal(t, sql, got)
+	}
+
+	_, ok := AddLimit("SELECT * FROM events", 0)
+	assert.False(t, ok)
+}
@@ -0,0 +1,375 @@
+// Package sqllint flags legal but risky SQL so users can be told about it
+// alongside their results. It works on tokens rather than a full parse,
+// which keeps it dialect-agnostic; each rule errs towards staying quiet.
+package sqllint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Rules a Warning may come from.
+const (
+	RuleNoLimit          = "no_limit"
+	RuleCrossJoin        = "cross_join"
+	RuleFunctionOnColumn = "function_on_column"
+)
+
+const (
+	// LargeTableRows is the table size from which an unbounded read warns.
+	LargeTableRows = 1_000_000
+	// LargeResultRows is the result size from which an unbounded read warns.
+	LargeResultRows = 10_000
+)
+
+// Warning is one finding.
+type Warning struct {
+	Rule    string
+	Message string
+}
+
+// Options supplies what the SQL text alone does not say. The zero value
+// lints the text alone.
+type Options struct {
+	// TableRows returns a table's approximate row count; schema is empty
+	// when the query does not qualify the table.
+	TableRows func(schema, table string) (int64, bool)
+	// ResultRows is how many rows the query returned; 0 when unknown.
+	ResultRows int
+}
+
+// Lint checks every statement in sql.
+func Lint(sql string, opts Options) []Warning {
+	tokens := tokenize(sql)
+	var out []Warning
+	add := func(w Warning) {
+		if !slices.Contains(out, w) {
+			out = append(out, w)
+		}
+	}
+	stmts := splitStatements(tokens)
+	for i, stmt := range stmts {
+		var top []*scope
+		for j, t := range stmt {
+			if !t.is("SELECT") {
+				continue
+			}
+			s := readScope(stmt, j)
+			if t.depth == 0 {
+				top = append(top, s)
+			}
+			for _, w := range s.crossJoins() {
+				add(w)
+			}
+			for _, w := range s.functionsOnColumns() {
+				add(w)
+			}
+		}
+		resultRows := 0
+		if i == len(stmts)-1 {
+			resultRows = opts.ResultRows
+		}
+		if w, ok := unbounded(stmt, top, opts.TableRows, resultRows); ok {
+			add(w)
+		}
+	}
+	return out
+}
+
+// splitStatements splits on top-level semicolons.
+func splitStatements(tokens []token) [][]token {
+	var out [][]token
+	start := 0
+	for i, t := range tokens {
+		if t.depth == 0 && t.is(";") {
+			if i > start {
+				out = append(out, tokens[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		out = append(out, tokens[start:])
+	}
+	return out
+}
+
+// tableRef is a table named in a FROM clause.
+type tableRef struct{ schema, name string }
+
+func (t tableRef) String() string {
+	if t.schema == "" {
+		return t.name
+	}
+	return t.schema + "." + t.name
+}
+
+// scope is one SELECT: the tokens from the keyword to the end of its query
+// block, with its clauses picked out at its own depth.
+type scope struct {
+	depth    int
+	tokens   []token
+	clauses  map[int]string // token index → clause it belongs to
+	tables   []tableRef
+	commas   bool // FROM lists tables separated by commas
+	grouped  bool // GROUP BY or an aggregate in the select list
+	bareJoin []int
+}
+
+var clauseKeywords = map[string]string{
+	"FROM": "from", "WHERE": "where", "GROUP": "group", "HAVING": "having",
+	"ORDER": "order", "LIMIT": "limit", "OFFSET": "limit", "FETCH": "limit",
+	"WINDOW": "other", "QUALIFY": "other", "SETTINGS": "other", "FORMAT": "other",
+}
+
+var aggregates = []string{"COUNT", "SUM", "AVG", "MIN", "MAX", "ARRAY_AGG", "STRING_AGG", "GROUP_CONCAT", "UNIQ", "ANY"}
+
+// readScope reads the query block whose SELECT is at stmt[start].
+func readScope(stmt []token, start int) *scope {
+	d := stmt[start].depth
+	s := &scope{depth: d, clauses: map[int]string{}}
+	clause := "select"
+	join := -1 // index in s.tokens of the pending JOIN without ON/USING
+	for i := start; i < len(stmt); i++ {
+		t := stmt[i]
+		if t.depth < d || (t.depth == d && i > start && t.is("UNION", "INTERSECT", "EXCEPT")) {
+			break
+		}
+		idx := len(s.tokens)
+		s.tokens = append(s.tokens, t)
+		if t.depth != d {
+			s.clauses[idx] = clause
+			continue
+		}
+		if c, ok := clauseKeywords[t.text]; ok && t.kind == tokWord {
+			clause = c
+		}
+		s.clauses[idx] = clause
+		switch {
+		case clause == "group":
+			s.grouped = true
+		case clause == "select" && t.kind == tokWord && slices.Contains(aggregates, t.text) && next(stmt, i).is("("):
+			s.grouped = true
+		case clause == "from" && t.is(","):
+			s.commas = true
+		case clause == "from" && t.is("JOIN"):
+			if join >= 0 {
+				s.bareJoin = append(s.bareJoin, join)
+			}
+			join = -1
+			if prev := prevWord(s.tokens, idx); prev != "CROSS" && prev != "NATURAL" && prev != "ARRAY" {
+				join = idx
+			}
+		case clause == "from" && t.is("ON", "USING"):
+			join = -1
+		}
+		if clause == "from" && (t.is("FROM", ",", "JOIN")) {
+			if ref, ok := readTable(stmt, i+1); ok {
+				s.tables = append(s.tables, ref)
+			}
+		}
+	}
+	if join >= 0 {
+		s.bareJoin = append(s.bareJoin, join)
+	}
+	return s
+}
+
+func next(tokens []token, i int) token {
+	if i+1 < len(tokens) {
+		return tokens[i+1]
+	}
+	return token{}
+}
+
+func prevWord(tokens []token, i int) string {
+	if i > 0 && tokens[i-1].kind == tokWord {
+		return tokens[i-1].text
+	}
+	return ""
+}
+
+// readTable reads a possibly schema-qualified table name at stmt[i]. It
+// fails for subqueries and table functions.
+func readTable(stmt []token, i int) (tableRef, bool) {
+	var parts []string
+	for ; i < len(stmt); i++ {
+		t := stmt[i]
+		if t.kind != tokWord && t.kind != tokQuoted {
+			return tableRef{}, false
+		}
+		name := t.text
+		if t.kind == tokWord {
+			name = strings.ToLower(name)
+		}
+		parts = append(parts, name)
+		if !next(stmt, i).is(".") {
+			break
+		}
+		i++
+	}
+	if len(parts) == 0 || next(stmt, i).is("(") || (len(parts) == 1 && stmt[i].kind == tokWord && reserved[stmt[i].text]) {
+		return tableRef{}, false
+	}
+	ref := tableRef{name: parts[len(parts)-1]}
+	if len(parts) > 1 {
+		ref.schema = parts[len(parts)-2]
+	}
+	return ref, true
+}
+
+func (s *scope) has(clause string) bool {
+	for _, c := range s.clauses {
+		if c == clause {
+			return true
+		}
+	}
+	return false
+}
+
+// crossJoins flags FROM lists that multiply tables together unconstrained:
+// comma-separated tables with no WHERE clause, and joins without ON/USING.
+func (s *scope) crossJoins() []Warning {
+	var out []Warning
+	if s.commas && len(s.tables) > 1 && !s.has("where") {
+		out = append(out, Warning{RuleCrossJoin, fmt.Sprintf(
+			"%s are listed in FROM without a WHERE clause, which pairs every row of each with every row of the others; use JOIN ... ON, or CROSS JOIN if that is intended",
+			joinNames(s.tables))})
+	}
+	for range s.bareJoin {
+		out = append(out, Warning{RuleCrossJoin,
+			"a JOIN has no ON or USING condition, which pairs every row on each side; add a condition, or write CROSS JOIN if that is intended"})
+	}
+	return out
+}
+
+func joinNames(tables []tableRef) string {
+	names := make([]string, len(tables))
+	for i, t := range tables {
+		names[i] = t.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+// comparisons are the operators a filtered expression is compared with.
+var comparisons = []string{"=", "<", ">", "<=", ">=", "<>", "!=", "LIKE", "ILIKE", "IN", "BETWEEN", "IS"}
+
+// functionsOnColumns flags WHERE and ON conditions that wrap a bare column
+// in a function, e.g. lower(email) = '...', which stops an index on the
+// column from being used.
+func (s *scope) functionsOnColumns() []Warning {
+	var out []Warning
+	for i, t := range s.tokens {
+		if t.depth != s.depth || t.kind != tokWord || reserved[t.text] || !next(s.tokens, i).is("(") {
+			continue
+		}
+		if c := s.clauses[i]; c != "where" && !(c == "from" && inOnCondition(s.tokens, i)) {
+			continue
+		}
+		col, end, ok := columnArg(s.tokens, i+2)
+		if !ok {
+			continue
+		}
+		before := token{}
+		if i > 0 {
+			before = s.tokens[i-1]
+		}
+		if !next(s.tokens, end).is(comparisons...) && !before.is(comparisons...) {
+			continue
+		}
+		out = append(out, Warning{RuleFunctionOnColumn, fmt.Sprintf(
+			"%s(%s) in a filter applies a function to the column, which keeps an index on %s from being used; compare the column itself, or index the expression",
+			strings.ToLower(t.text), col, col)})
+	}
+	return out
+}
+
+// inOnCondition reports whether tokens[i] follows an ON since the last JOIN.
+func inOnCondition(tokens []token, i int) bool {
+	d := tokens[i].depth
+	for j := i - 1; j >= 0; j-- {
+		if tokens[j].depth != d {
+			continue
+		}
+		switch {
+		case tokens[j].is("ON"):
+			return true
+		case tokens[j].is("JOIN", "FROM", ","):
+			return false
+		}
+	}
+	return false
+}
+
+// columnArg reads a lone column reference such as c or t.c followed by a
+// closing parenthesis, starting at tokens[i]. end is the parenthesis.
+func columnArg(tokens []token, i int) (col string, end int, ok bool) {
+	var parts []string
+	for ; i < len(tokens); i++ {
+		t := tokens[i]
+		if (t.kind != tokWord && t.kind != tokQuoted) || (t.kind == tokWord && reserved[t.text]) {
+			return "", 0, false
+		}
+		name := t.text
+		if t.kind == tokWord {
+			name = strings.ToLower(name)
+		}
+		parts = append(parts, name)
+		n := next(tokens, i)
+		if n.is(")") {
+			return strings.Join(parts, "."), i + 1, true
+		}
+		if !n.is(".") {
+			return "", 0, false
+		}
+		i++
+	}
+	return "", 0, false
+}
+
+// unbounded flags a statement that reads a large table, or returned many
+// rows, with no LIMIT and no aggregation.
+func unbounded(stmt []token, top []*scope, tableRows func(schema, table string) (int64, bool), resultRows int) (Warning, bool) {
+	if len(top) == 0 {
+		return Warning{}, false
+	}
+	for _, t := range stmt {
+		if t.depth == 0 && t.is("LIMIT", "FETCH", "TOP") {
+			return Warning{}, false
+		}
+	}
+	for _, s := range top {
+		if s.grouped || len(s.tables) == 0 {
+			return Warning{}, false
+		}
+	}
+	if tableRows != nil {
+		for _, s := range top {
+			for _, t := range s.tables {
+				if n, ok := tableRows(t.schema, t.name); ok && n >= LargeTableRows {
+					return Warning{RuleNoLimit, fmt.Sprintf(
+						"the query has no LIMIT and reads %s, which has about %d rows; add a LIMIT while exploring", t, n)}, true
+				}
+			}
+		}
+	}
+	if resultRows >= LargeResultRows {
+		return Warning{RuleNoLimit, fmt.Sprintf(
+			"the query has no LIMIT and returned %d rows; add a LIMIT while exploring", resultRows)}, true
+	}
+	return Warning{}, false
+}
+
+// reserved are the keywords that never name a table, column or function.
+var reserved = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`
+		ALL AND ANY ARRAY AS ASC BETWEEN BY CASE CAST CROSS DESC DISTINCT ELSE END
+		EXCEPT EXISTS FETCH FOR FROM FULL GROUP HAVING ILIKE IN INNER INTERSECT
+		INTERVAL IS JOIN LATERAL LEFT LIKE LIMIT NATURAL NOT NULL OFFSET ON OR
+		ORDER OUTER RIGHT SELECT SOME THEN UNION USING VALUES WHEN WHERE WITH`) {
+		reserved[w] = true
+	}
+}
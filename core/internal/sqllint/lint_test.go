@@ -0,0 +1,92 @@
+package sqllint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rules(ws []Warning) []string {
+	var out []string
+	for _, w := range ws {
+		out = append(out, w.Rule)
+	}
+	return out
+}
+
+func TestLint_CrossJoin(t *testing.T) {
+	for sql, want := range map[string][]string{
+		"SELECT * FROM a, b":                                  {RuleCrossJoin},
+		"SELECT * FROM a, b WHERE a.id = b.a_id":              nil,
+		"SELECT * FROM a JOIN b":                              {RuleCrossJoin},
+		"SELECT * FROM a JOIN b ON a.id = b.a_id JOIN c":      {RuleCrossJoin},
+		"SELECT * FROM a JOIN b USING (id)":                   nil,
+		"SELECT * FROM a CROSS JOIN b":                        nil,
+		"SELECT * FROM (SELECT * FROM a, b) x WHERE x.id > 1": {RuleCrossJoin},
+		"SELECT x, y FROM t WHERE x IN (1, 2)":                nil,
+		"SELECT 'a, b' FROM t -- FROM a, b":                   nil,
+	} {
+		assert.Equal(t, want, rules(Lint(sql, Options{})), sql)
+	}
+}
+
+func TestLint_FunctionOnColumn(t *testing.T) {
+	for sql, want := range map[string][]string{
+		"SELECT * FROM users WHERE lower(email) = 'a@b.c'":           {RuleFunctionOnColumn},
+		"SELECT * FROM e WHERE '2024-01-01' = date(e.created_at)":    {RuleFunctionOnColumn},
+		"SELECT * FROM a JOIN b ON upper(a.code) = b.code":           {RuleFunctionOnColumn},
+		"SELECT lower(email) FROM users WHERE email = 'a@b.c'":       nil,
+		"SELECT * FROM e WHERE created_at > now()":                   nil,
+		"SELECT * FROM e WHERE EXTRACT(YEAR FROM created_at) = 2024": nil,
+		"SELECT * FROM e WHERE id IN (SELECT id FROM f)":             nil,
+		"SELECT * FROM e WHERE coalesce(a, b) = 1":                   nil,
+		"SELECT count(*) FROM e GROUP BY k HAVING count(id) > 1":     nil,
+	} {
+		assert.Equal(t, want, rules(Lint(sql, Options{})), sql)
+	}
+}
+
+func TestLint_NoLimit(t *testing.T) {
+	rows := func(schema, table string) (int64, bool) {
+		if table == "events" {
+			return 50_000_000, true
+		}
+		return 10, true
+	}
+	opts := Options{TableRows: rows}
+	for sql, want := range map[string][]string{
+		"SELECT * FROM events":                                     {RuleNoLimit},
+		"SELECT * FROM analytics.events WHERE kind = 'x'":          {RuleNoLimit},
+		"SELECT * FROM events LIMIT 100":                           nil,
+		"SELECT * FROM events FETCH FIRST 10 ROWS ONLY":            nil,
+		"SELECT count(*) FROM events":                              nil,
+		"SELECT kind, count(*) FROM events GROUP BY kind":          nil,
+		"SELECT * FROM users":                                      nil,
+		"SELECT * FROM users WHERE id IN (SELECT u FROM events)":   nil,
+		"WITH e AS (SELECT * FROM events) SELECT * FROM e LIMIT 5": nil,
+	} {
+		assert.Equal(t, want, rules(Lint(sql, opts)), sql)
+	}
+
+	// Without table sizes, a large result gives the query away.
+	assert.Equal(t, []string{RuleNoLimit}, rules(Lint("SELECT * FROM users", Options{ResultRows: 20_000})))
+	assert.Empty(t, Lint("SELECT * FROM users", Options{ResultRows: 20}))
+}
+
+func TestLint_Statements(t *testing.T) {
+	ws := Lint("SELECT * FROM a, b; SELECT * FROM c WHERE lower(d) = 'x'", Options{})
+	assert.Equal(t, []string{RuleCrossJoin, RuleFunctionOnColumn}, rules(ws))
+	assert.Contains(t, ws[1].Message, "lower(d)")
+}
+
+func TestTables(t *testing.T) {
+	for sql, want := range map[string][]string{
+		"SELECT * FROM Orders o JOIN public.customers c ON c.id = o.customer_id":  {"orders", "public.customers"},
+		`SELECT * FROM "Events", events`:                                          {"Events", "events"},
+		"WITH recent AS (SELECT * FROM orders) SELECT * FROM recent, orders":      {"orders"},
+		"SELECT * FROM (SELECT id FROM a) sub JOIN b USING (id); SELECT 1 FROM a": {"b", "a"},
+		"SELECT 1": nil,
+	} {
+		assert.Equal(t, want, Tables(sql), sql)
+	}
+}
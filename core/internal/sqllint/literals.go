@@ -0,0 +1,47 @@
+package sqllint
+
+// filterStarts open a clause whose expressions filter rows; filterEnds
+// close one at the same depth.
+var (
+	filterStarts = []string{"WHERE", "HAVING", "ON", "QUALIFY"}
+	filterEnds   = []string{
+		"SELECT", "FROM", "JOIN", "GROUP", "ORDER", "LIMIT", "OFFSET", "FETCH",
+		"WINDOW", "UNION", "INTERSECT", "EXCEPT", "RETURNING", "SET", "SETTINGS", "FORMAT",
+	}
+)
+
+// FilterLiterals returns the string and number literals written into the
+// conditions of sql — its WHERE, HAVING and ON clauses, including any
+// subqueries or lists inside them — in the order they appear. Strings are
+// returned quoted; positional placeholders such as $1 are not literals.
+// Values there should usually be bound as parameters instead, both so the
+// server can reuse the plan and so user input never becomes SQL.
+func FilterLiterals(sql string) []string {
+	var out []string
+	// filtering[d] is whether tokens at depth d sit inside a condition; a
+	// parenthesised group starts out in the state of the depth around it.
+	filtering := []bool{false}
+	var prev token
+	for _, t := range tokenize(sql) {
+		placeholder := prev.is("$")
+		prev = t
+		for len(filtering) <= t.depth {
+			filtering = append(filtering, filtering[len(filtering)-1])
+		}
+		filtering = filtering[:t.depth+1]
+		switch {
+		case t.is(";"):
+			filtering[t.depth] = false
+		case t.is(filterStarts...):
+			filtering[t.depth] = true
+		case t.is(filterEnds...):
+			filtering[t.depth] = false
+		case !filtering[t.depth], placeholder:
+		case t.kind == tokString:
+			out = append(out, "'"+t.text+"'")
+		case t.kind == tokNumber:
+			out = append(out, t.text)
+		}
+	}
+	return out
+}
@@ -0,0 +1,23 @@
+package sqllint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterLiterals(t *testing.T) {
+	for sql, want := range map[string][]string{
+		"SELECT * FROM users WHERE name = 'bob' AND age > 30":                        {"'bob'", "30"},
+		"SELECT * FROM users WHERE id IN (1, 2) ORDER BY id LIMIT 10":                {"1", "2"},
+		"SELECT * FROM a JOIN b ON a.id = b.id AND b.kind = 'x' WHERE a.n = {n:Int}": {"'x'"},
+		"SELECT kind, count(*) FROM e GROUP BY kind HAVING count(*) > 5":             {"5"},
+		"SELECT * FROM u WHERE id IN (SELECT round(x, 2) FROM t WHERE y = 'z')":      {"'z'"},
+		"UPDATE t SET a = 1 WHERE id = 2":                                            {"2"},
+		"SELECT 'label', round(x, 2) FROM t LIMIT 5":                                 nil,
+		"SELECT * FROM t WHERE a = $1; SELECT 1":                                     nil,
+		"SELECT * FROM t -- WHERE a = 'x'":                                           nil,
+	} {
+		assert.Equal(t, want, FilterLiterals(sql), sql)
+	}
+}
@@ -0,0 +1,47 @@
+package sqllint
+
+import "slices"
+
+// Tables lists the tables sql reads from, "schema.table" when the query
+// qualifies them, each once; tables of an outer query come before those of
+// its subqueries. Unquoted names are folded to lower case; names defined by
+// WITH are left out.
+func Tables(sql string) []string {
+	tokens := tokenize(sql)
+	ctes := cteNames(tokens)
+	var out []string
+	for _, stmt := range splitStatements(tokens) {
+		for j, t := range stmt {
+			if !t.is("SELECT") {
+				continue
+			}
+			for _, ref := range readScope(stmt, j).tables {
+				name := ref.String()
+				if (ref.schema == "" && ctes[ref.name]) || slices.Contains(out, name) {
+					continue
+				}
+				out = append(out, name)
+			}
+		}
+	}
+	return out
+}
+
+// cteNames collects the names bound by "name AS (" following WITH or a
+// comma in a WITH list.
+func cteNames(tokens []token) map[string]bool {
+	names := map[string]bool{}
+	for i := 1; i+2 < len(tokens); i++ {
+		t := tokens[i]
+		if t.kind != tokWord && t.kind != tokQuoted {
+			continue
+		}
+		if !tokens[i+1].is("AS") || !tokens[i+2].is("(") || !tokens[i-1].is("WITH", "RECURSIVE", ",") {
+			continue
+		}
+		if ref, ok := readTable(tokens, i); ok {
+			names[ref.name] = true
+		}
+	}
+	return names
+}
@@ -0,0 +1,127 @@
+package sqllint
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokWord   tokenKind = iota // keyword or unquoted identifier, upper-cased
+	tokQuoted                  // quoted identifier, quotes removed
+	tokString
+	tokNumber
+	tokPunct
+)
+
+// token is one lexeme. depth is the parenthesis nesting it sits at; an
+// opening or closing parenthesis carries the depth outside it.
+type token struct {
+	kind  tokenKind
+	text  string
+	depth int
+}
+
+func (t token) is(words ...string) bool {
+	if t.kind != tokWord && t.kind != tokPunct {
+		return false
+	}
+	for _, w := range words {
+		if t.text == w {
+			return true
+		}
+	}
+	return false
+}
+
+// operators are the multi-character punctuation tokens.
+var operators = []string{"<=", ">=", "<>", "!=", "::", "||"}
+
+// tokenize splits sql into tokens, dropping whitespace and comments. It is
+// forgiving: unterminated literals run to the end of the input.
+func tokenize(sql string) []token {
+	var out []token
+	depth := 0
+	rs := []rune(sql)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(rs) && rs[i+1] == '-':
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '*':
+			i += 2
+			for i < len(rs) && (rs[i] != '*' || i+1 == len(rs) || rs[i+1] != '/') {
+				i++
+			}
+			i += 2
+		case r == '\'' || r == '"' || r == '`':
+			j, text := quoted(rs, i)
+			kind := tokQuoted
+			if r == '\'' {
+				kind = tokString
+			}
+			out = append(out, token{kind: kind, text: text, depth: depth})
+			i = j
+		case r == '_' || unicode.IsLetter(r):
+			j := i
+			for j < len(rs) && (rs[j] == '_' || rs[j] == '$' || unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j])) {
+				j++
+			}
+			out = append(out, token{kind: tokWord, text: strings.ToUpper(string(rs[i:j])), depth: depth})
+			i = j
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.' || rs[j] == 'e' || rs[j] == 'E') {
+				j++
+			}
+			out = append(out, token{kind: tokNumber, text: string(rs[i:j]), depth: depth})
+			i = j
+		case r == '(':
+			out = append(out, token{kind: tokPunct, text: "(", depth: depth})
+			depth++
+			i++
+		case r == ')':
+			depth = max(depth-1, 0)
+			out = append(out, token{kind: tokPunct, text: ")", depth: depth})
+			i++
+		default:
+			text := string(r)
+			for _, op := range operators {
+				if strings.HasPrefix(string(rs[i:min(i+2, len(rs))]), op) {
+					text = op
+					break
+				}
+			}
+			out = append(out, token{kind: tokPunct, text: text, depth: depth})
+			i += len([]rune(text))
+		}
+	}
+	return out
+}
+
+// quoted reads the literal opening at rs[i], returning the index after it
+// and its contents. A doubled quote escapes itself, as does a backslash.
+func quoted(rs []rune, i int) (int, string) {
+	q := rs[i]
+	var b strings.Builder
+	for j := i + 1; j < len(rs); j++ {
+		switch {
+		case rs[j] == '\\' && q == '\'' && j+1 < len(rs):
+			j++
+			b.WriteRune(rs[j])
+		case rs[j] == q && j+1 < len(rs) && rs[j+1] == q:
+			j++
+			b.WriteRune(q)
+		case rs[j] == q:
+			return j + 1, b.String()
+		default:
+			b.WriteRune(rs[j])
+		}
+	}
+	return len(rs), b.String()
+}
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS query_usage
+(
+    id               String,
+    datasource_id    String,
+    datasource_name  String,
+    datasource_type  String,
+    table_name       String,
+    username         String,
+    day              String,
+    used_at          DateTime DEFAULT now()
+) ENGINE = MergeTree()
+ORDER BY (day, datasource_id, table_name);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS query_usage;
+-- +goose StatementEnd
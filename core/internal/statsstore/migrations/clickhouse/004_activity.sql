@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS activity
+(
+    id               String,
+    type             String,
+    severity         String,
+    title            String,
+    body             String,
+    source           String,
+    datasource_id    String,
+    actor            String,
+    labels           String,
+    created_at       DateTime DEFAULT now()
+) ENGINE = MergeTree()
+ORDER BY id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS activity;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE query_usage
+    ADD COLUMN IF NOT EXISTS bytes_read Int64 DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS cost       Float64 DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS currency   String DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE query_usage
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS cost,
+    DROP COLUMN IF EXISTS bytes_read;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS query_usage (
+    id               VARCHAR(36)  NOT NULL PRIMARY KEY,
+    datasource_id    VARCHAR(36)  NOT NULL,
+    datasource_name  VARCHAR(255) NOT NULL,
+    datasource_type  VARCHAR(50)  NOT NULL,
+    table_name       VARCHAR(255) NOT NULL DEFAULT '',
+    username         VARCHAR(255) NOT NULL DEFAULT '',
+    day              CHAR(10)     NOT NULL,
+    used_at          DATETIME(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+);
+
+CREATE INDEX idx_query_usage_day ON query_usage (day);
+CREATE INDEX idx_query_usage_datasource ON query_usage (datasource_id, table_name);
+
+-- +goose Down
+DROP TABLE IF EXISTS query_usage;
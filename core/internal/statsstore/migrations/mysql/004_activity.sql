@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS activity (
+    id               VARCHAR(36)  NOT NULL PRIMARY KEY,
+    type             VARCHAR(50)  NOT NULL,
+    severity         VARCHAR(20)  NOT NULL DEFAULT '',
+    title            VARCHAR(512) NOT NULL,
+    body             TEXT         NOT NULL,
+    source           VARCHAR(255) NOT NULL DEFAULT '',
+    datasource_id    VARCHAR(36)  NOT NULL DEFAULT '',
+    actor            VARCHAR(255) NOT NULL DEFAULT '',
+    labels           TEXT         NOT NULL,
+    created_at       DATETIME(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+);
+
+CREATE INDEX idx_activity_datasource ON activity (datasource_id, id);
+
+-- +goose Down
+DROP TABLE IF EXISTS activity;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE query_usage ADD COLUMN bytes_read BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE query_usage ADD COLUMN cost DOUBLE NOT NULL DEFAULT 0;
+ALTER TABLE query_usage ADD COLUMN currency CHAR(3) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE query_usage DROP COLUMN currency;
+ALTER TABLE query_usage DROP COLUMN cost;
+ALTER TABLE query_usage DROP COLUMN bytes_read;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS query_usage (
+    id               TEXT     NOT NULL PRIMARY KEY,
+    datasource_id    TEXT     NOT NULL,
+    datasource_name  TEXT     NOT NULL,
+    datasource_type  TEXT     NOT NULL,
+    table_name       TEXT     NOT NULL DEFAULT '',
+    username         TEXT     NOT NULL DEFAULT '',
+    day              TEXT     NOT NULL,
+    used_at          DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_query_usage_day ON query_usage (day);
+CREATE INDEX IF NOT EXISTS idx_query_usage_datasource ON query_usage (datasource_id, table_name);
+
+-- +goose Down
+DROP TABLE IF EXISTS query_usage;
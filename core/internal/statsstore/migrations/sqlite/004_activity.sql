@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS activity (
+    id               TEXT     NOT NULL PRIMARY KEY,
+    type             TEXT     NOT NULL,
+    severity         TEXT     NOT NULL DEFAULT '',
+    title            TEXT     NOT NULL,
+    body             TEXT     NOT NULL DEFAULT '',
+    source           TEXT     NOT NULL DEFAULT '',
+    datasource_id    TEXT     NOT NULL DEFAULT '',
+    actor            TEXT     NOT NULL DEFAULT '',
+    labels           TEXT     NOT NULL DEFAULT '{}',
+    created_at       DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_activity_datasource ON activity (datasource_id, id);
+
+-- +goose Down
+DROP TABLE IF EXISTS activity;
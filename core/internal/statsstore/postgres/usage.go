@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/usage"
+)
+
+type usageRepo struct {
+	db *sqlx.DB
+}
+
+// NewUsageRepo returns a usage.Repository backed by PostgreSQL.
+func NewUsageRepo(db *sqlx.DB) usage.Repository {
+	return &usageRepo{db: db}
+}
+
+// Record inserts events in one transaction through a prepared statement.
+func (repo *usageRepo) Record(ctx context.Context, events []*usage.Event) error {
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("record query_usage: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	stmt, err := tx.PrepareContext(ctx,
+		`INSERT INTO query_usage (id, datasource_id, datasource_name, datasource_type, table_name, username, day, used_at,
+		                          bytes_read, cost, currency)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`)
+	if err != nil {
+		return fmt.Errorf("record query_usage: %w", err)
+	}
+	defer func() { _ = stmt.Close() }()
+	for _, e := range events {
+		if _, err := stmt.ExecContext(ctx, e.ID, e.DatasourceID, e.DatasourceName, e.DatasourceType,
+			e.TableName, e.Username, e.Day, e.UsedAt,
+			e.BytesRead, e.Cost, e.Currency); err != nil {
+			return fmt.Errorf("record query_usage: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("record query_usage: %w", err)
+	}
+	return nil
+}
+
+func (repo *usageRepo) Counts(ctx context.Context, since string) ([]*usage.Count, error) {
+	var rows []*usage.Count
+	err := repo.db.SelectContext(ctx, &rows,
+		`SELECT day, datasource_id, MAX(datasource_name) AS datasource_name, table_name, username, currency,
+		        COUNT(*) AS queries, COALESCE(SUM(bytes_read), 0)::BIGINT AS bytes_read, COALESCE(SUM(cost), 0) AS cost
+		   FROM query_usage
+		  WHERE day >= $1
+		  GROUP BY day, datasource_id, table_name, username, currency`,
+		since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("count query_usage: %w", err)
+	}
+	return rows, nil
+}
+
+func (repo *usageRepo) LastUsed(ctx context.Context) ([]*usage.LastUse, error) {
+	var rows []*usage.LastUse
+	err := repo.db.SelectContext(ctx, &rows,
+		`SELECT datasource_id, MAX(datasource_name) AS datasource_name, table_name, MAX(day) AS day
+		   FROM query_usage
+		  GROUP BY datasource_id, table_name`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("last use query_usage: %w", err)
+	}
+	return rows, nil
+}
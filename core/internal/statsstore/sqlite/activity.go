@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/activity"
+)
+
+type activityRepo struct {
+	db *sqlx.DB
+}
+
+// NewActivityRepo returns an activity.Repository backed by SQLite.
+func NewActivityRepo(db *sqlx.DB) activity.Repository {
+	return &activityRepo{db: db}
+}
+
+type activityRow struct {
+	ID           string `db:"id"`
+	Type         string `db:"type"`
+	Severity     string `db:"severity"`
+	Title        string `db:"title"`
+	Body         string `db:"body"`
+	Source       string `db:"source"`
+	DatasourceID string `db:"datasource_id"`
+	Actor        string `db:"actor"`
+	Labels       string `db:"labels"`
+	CreatedAt    string `db:"created_at"`
+}
+
+func (r activityRow) toModel() *activity.Entry {
+	t, _ := time.Parse("2006-01-02 15:04:05", r.CreatedAt)
+	if t.IsZero() {
+		t, _ = time.Parse(time.RFC3339, r.CreatedAt)
+	}
+	e := &activity.Entry{
+		ID:           r.ID,
+		Type:         r.Type,
+		Severity:     r.Severity,
+		Title:        r.Title,
+		Body:         r.Body,
+		Source:       r.Source,
+		DatasourceID: r.DatasourceID,
+		Actor:        r.Actor,
+		CreatedAt:    t,
+	}
+	_ = json.Unmarshal([]byte(r.Labels), &e.Labels)
+	return e
+}
+
+func (repo *activityRepo) Record(ctx context.Context, e *activity.Entry) error {
+	labels, err := json.Marshal(e.Labels)
+	if err != nil {
+		return fmt.Errorf("record activity: %w", err)
+	}
+	_, err = repo.db.ExecContext(ctx,
+		`INSERT INTO activity (id, type, severity, title, body, source, datasource_id, actor, labels, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.ID, e.Type, e.Severity, e.Title, e.Body, e.Source, e.DatasourceID, e.Actor, string(labels), e.CreatedAt.UTC().Format("2006-01-02 15:04:05"),
+	)
+	if err != nil {
+		return fmt.Errorf("record activity: %w", err)
+	}
+	return nil
+}
+
+func (repo *activityRepo) List(ctx context.Context, f activity.Filter) ([]*activity.Entry, error) {
+	query := `SELECT * FROM activity WHERE (? = '' OR datasource_id = ?) AND (? = '' OR id < ?)`
+	args := []any{f.DatasourceID, f.DatasourceID, f.Before, f.Before}
+	if len(f.Types) > 0 {
+		query += ` AND type IN (?)`
+		args = append(args, f.Types)
+	}
+	query += ` ORDER BY id DESC LIMIT ?`
+	query, args, err := sqlx.In(query, append(args, f.Limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("list activity: %w", err)
+	}
+	var rows []activityRow
+	if err := repo.db.SelectContext(ctx, &rows, repo.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("list activity: %w", err)
+	}
+	out := make([]*activity.Entry, len(rows))
+	for i, r := range rows {
+		out[i] = r.toModel()
+	}
+	return out, nil
+}
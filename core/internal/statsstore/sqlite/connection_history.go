@@ -10,6 +10,12 @@ import (
 	"data-voyager/core/internal/connection"
 )
 
+// FormatChangedAt renders t the way history tables store changed_at, for
+// callers that compare against the column directly.
+func FormatChangedAt(t time.Time) any {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
 type connectionHistoryRepo struct {
 	db *sqlx.DB
 }
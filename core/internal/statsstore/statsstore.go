@@ -3,17 +3,22 @@ package statsstore
 import (
 	"embed"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pressly/goose/v3"
 
+	"data-voyager/core/internal/activity"
 	"data-voyager/core/internal/aiconfig"
 	"data-voyager/core/internal/config"
 	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/privacy"
+	"data-voyager/core/internal/retention"
 	stclickhouse "data-voyager/core/internal/statsstore/clickhouse"
 	stmysql "data-voyager/core/internal/statsstore/mysql"
 	stpostgres "data-voyager/core/internal/statsstore/postgres"
 	stsqlite "data-voyager/core/internal/statsstore/sqlite"
+	"data-voyager/core/internal/usage"
 
 	_ "github.com/ClickHouse/clickhouse-go/v2"
 	_ "github.com/go-sql-driver/mysql"
@@ -35,8 +40,10 @@ var clickhouseMigrations embed.FS
 
 // Repos holds statistics repository implementations for the selected driver.
 type Repos struct {
+	Activity          activity.Repository
 	AIConfigHistory   aiconfig.HistoryRepository
 	ConnectionHistory connection.HistoryRepository
+	Usage             usage.Repository
 }
 
 // Open opens a sqlx.DB for the statistics store and optionally runs migrations.
@@ -68,29 +75,62 @@ func NewRepos(db *sqlx.DB, cfg config.StatisticsStoreConfig) (*Repos, error) {
 	switch cfg.Type {
 	case "sqlite", "sqlite3":
 		return &Repos{
+			Activity:          stsqlite.NewActivityRepo(db),
 			AIConfigHistory:   stsqlite.NewAIConfigHistoryRepo(db),
 			ConnectionHistory: stsqlite.NewConnectionHistoryRepo(db),
+			Usage:             stsqlite.NewUsageRepo(db),
 		}, nil
 	case "postgres", "postgresql":
 		return &Repos{
+			Activity:          stpostgres.NewActivityRepo(db),
 			AIConfigHistory:   stpostgres.NewAIConfigHistoryRepo(db),
 			ConnectionHistory: stpostgres.NewConnectionHistoryRepo(db),
+			Usage:             stpostgres.NewUsageRepo(db),
 		}, nil
 	case "mysql":
 		return &Repos{
+			Activity:          stmysql.NewActivityRepo(db),
 			AIConfigHistory:   stmysql.NewAIConfigHistoryRepo(db),
 			ConnectionHistory: stmysql.NewConnectionHistoryRepo(db),
+			Usage:             stmysql.NewUsageRepo(db),
 		}, nil
 	case "clickhouse":
 		return &Repos{
+			Activity:          stclickhouse.NewActivityRepo(db),
 			AIConfigHistory:   stclickhouse.NewAIConfigHistoryRepo(db),
 			ConnectionHistory: stclickhouse.NewConnectionHistoryRepo(db),
+			Usage:             stclickhouse.NewUsageRepo(db),
 		}, nil
 	default:
 		return nil, fmt.Errorf("unsupported statistics_store.type: %s", cfg.Type)
 	}
 }
 
+// RetentionTables lists the statistics tables retention policies may prune.
+func RetentionTables(db *sqlx.DB, cfg config.StatisticsStoreConfig) []retention.Table {
+	var format func(time.Time) any
+	if cfg.Type == "sqlite" || cfg.Type == "sqlite3" {
+		format = stsqlite.FormatChangedAt
+	}
+	return []retention.Table{
+		{Store: retention.StoreStatistics, Name: "connection_history", TimeColumn: "changed_at", DB: db, Format: format},
+		{Store: retention.StoreStatistics, Name: "ai_config_history", TimeColumn: "changed_at", DB: db, Format: format},
+		{Store: retention.StoreStatistics, Name: "query_usage", TimeColumn: "used_at", DB: db, Format: format},
+		{Store: retention.StoreStatistics, Name: "activity", TimeColumn: "created_at", DB: db, Format: format},
+	}
+}
+
+// PersonalDataTables lists the statistics tables that name users, for
+// personal data exports and erasure.
+func PersonalDataTables(db *sqlx.DB, cfg config.StatisticsStoreConfig) []privacy.Table {
+	return []privacy.Table{
+		{Store: retention.StoreStatistics, Name: "query_usage", UserColumns: []string{"username"}, DB: db,
+			AlterUpdate: cfg.Type == "clickhouse"},
+		{Store: retention.StoreStatistics, Name: "activity", UserColumns: []string{"actor"}, DB: db,
+			AlterUpdate: cfg.Type == "clickhouse"},
+	}
+}
+
 func runMigrations(db *sqlx.DB, dbType string) error {
 	var (
 		fs      embed.FS
@@ -1,8 +1,12 @@
 package store
 
 import (
+	"context"
 	"embed"
 	"fmt"
+	"io/fs"
+	"sync"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pressly/goose/v3"
@@ -10,10 +14,23 @@ import (
 	"data-voyager/core/internal/aiconfig"
 	"data-voyager/core/internal/config"
 	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/dashboard"
+	"data-voyager/core/internal/export"
+	"data-voyager/core/internal/monitor"
+	"data-voyager/core/internal/notification"
+	"data-voyager/core/internal/ownership"
+	"data-voyager/core/internal/pii"
+	"data-voyager/core/internal/privacy"
+	"data-voyager/core/internal/reconcile"
+	"data-voyager/core/internal/retention"
+	"data-voyager/core/internal/rowedit"
+	"data-voyager/core/internal/savedview"
+	"data-voyager/core/internal/scim"
 	"data-voyager/core/internal/settings"
 	stmysql "data-voyager/core/internal/store/mysql"
 	stpostgres "data-voyager/core/internal/store/postgres"
 	stsqlite "data-voyager/core/internal/store/sqlite"
+	"data-voyager/core/internal/user"
 
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/lib/pq"
@@ -32,9 +49,26 @@ var mysqlMigrations embed.FS
 // Repos holds all repository implementations for the selected driver.
 // New service repositories are added here as fields.
 type Repos struct {
-	Connection connection.Repository
-	Settings   settings.Repository
-	AIConfigs  aiconfig.Repository
+	Connection      connection.Repository
+	Templates       connection.TemplateRepository
+	Settings        settings.Repository
+	AIConfigs       aiconfig.Repository
+	Ownership       ownership.Repository
+	Notifications   notification.Repository
+	Dashboards      dashboard.Repository
+	Snapshots       dashboard.SnapshotRepository
+	Versions        dashboard.VersionRepository
+	Monitors        monitor.Repository
+	Reconciliations reconcile.Repository
+	ColumnTags      pii.Repository
+	Users           user.Repository
+	Impersonations  user.AuditRepository
+	Credentials     user.CredentialRepository
+	Invitations     user.InvitationRepository
+	SCIMGroups      scim.GroupRepository
+	ExportAudit     export.AuditRepository
+	RowEditAudit    rowedit.AuditRepository
+	SavedViews      savedview.Repository
 }
 
 // Open opens a sqlx.DB connection and optionally runs goose migrations.
@@ -55,7 +89,7 @@ func Open(cfg config.DBConfig) (*sqlx.DB, error) {
 		return nil, fmt.Errorf("ping db (%s): %w", driver, err)
 	}
 	if cfg.MigrateOnStart {
-		if err := runMigrations(db, cfg.Type); err != nil {
+		if _, err := Migrate(context.Background(), db, cfg.Type, cfg.MigrationBackup); err != nil {
 			_ = db.Close()
 			return nil, fmt.Errorf("migrate (%s): %w", driver, err)
 		}
@@ -69,47 +103,168 @@ func NewRepos(db *sqlx.DB, cfg config.DBConfig) (*Repos, error) {
 	switch cfg.Type {
 	case "postgres", "postgresql":
 		return &Repos{
-			Connection: stpostgres.NewConnectionRepo(db),
-			Settings:   stpostgres.NewSettingsRepo(db),
-			AIConfigs:  stpostgres.NewAIConfigRepo(db),
+			Connection:      stpostgres.NewConnectionRepo(db),
+			Templates:       stpostgres.NewTemplateRepo(db),
+			Settings:        stpostgres.NewSettingsRepo(db),
+			AIConfigs:       stpostgres.NewAIConfigRepo(db),
+			Ownership:       stpostgres.NewOwnershipRepo(db),
+			Notifications:   stpostgres.NewNotificationRepo(db),
+			Dashboards:      stpostgres.NewDashboardRepo(db),
+			Snapshots:       stpostgres.NewSnapshotRepo(db),
+			Versions:        stpostgres.NewDashboardVersionRepo(db),
+			Monitors:        stpostgres.NewMonitorRepo(db),
+			Reconciliations: stpostgres.NewReconcileRepo(db),
+			ColumnTags:      stpostgres.NewColumnTagRepo(db),
+			Users:           stpostgres.NewUserRepo(db),
+			Impersonations:  stpostgres.NewImpersonationRepo(db),
+			Credentials:     stpostgres.NewCredentialRepo(db),
+			Invitations:     stpostgres.NewInvitationRepo(db),
+			SCIMGroups:      stpostgres.NewSCIMGroupRepo(db),
+			ExportAudit:     stpostgres.NewExportAuditRepo(db),
+			RowEditAudit:    stpostgres.NewRowEditAuditRepo(db),
+			SavedViews:      stpostgres.NewSavedViewRepo(db),
 		}, nil
 	case "sqlite", "sqlite3":
 		return &Repos{
-			Connection: stsqlite.NewConnectionRepo(db),
-			Settings:   stsqlite.NewSettingsRepo(db),
-			AIConfigs:  stsqlite.NewAIConfigRepo(db),
+			Connection:      stsqlite.NewConnectionRepo(db),
+			Templates:       stsqlite.NewTemplateRepo(db),
+			Settings:        stsqlite.NewSettingsRepo(db),
+			AIConfigs:       stsqlite.NewAIConfigRepo(db),
+			Ownership:       stsqlite.NewOwnershipRepo(db),
+			Notifications:   stsqlite.NewNotificationRepo(db),
+			Dashboards:      stsqlite.NewDashboardRepo(db),
+			Snapshots:       stsqlite.NewSnapshotRepo(db),
+			Versions:        stsqlite.NewDashboardVersionRepo(db),
+			Monitors:        stsqlite.NewMonitorRepo(db),
+			Reconciliations: stsqlite.NewReconcileRepo(db),
+			ColumnTags:      stsqlite.NewColumnTagRepo(db),
+			Users:           stsqlite.NewUserRepo(db),
+			Impersonations:  stsqlite.NewImpersonationRepo(db),
+			Credentials:     stsqlite.NewCredentialRepo(db),
+			Invitations:     stsqlite.NewInvitationRepo(db),
+			SCIMGroups:      stsqlite.NewSCIMGroupRepo(db),
+			ExportAudit:     stsqlite.NewExportAuditRepo(db),
+			RowEditAudit:    stsqlite.NewRowEditAuditRepo(db),
+			SavedViews:      stsqlite.NewSavedViewRepo(db),
 		}, nil
 	case "mysql":
 		return &Repos{
-			Connection: stmysql.NewConnectionRepo(db),
-			Settings:   stmysql.NewSettingsRepo(db),
-			AIConfigs:  stmysql.NewAIConfigRepo(db),
+			Connection:      stmysql.NewConnectionRepo(db),
+			Templates:       stmysql.NewTemplateRepo(db),
+			Settings:        stmysql.NewSettingsRepo(db),
+			AIConfigs:       stmysql.NewAIConfigRepo(db),
+			Ownership:       stmysql.NewOwnershipRepo(db),
+			Notifications:   stmysql.NewNotificationRepo(db),
+			Dashboards:      stmysql.NewDashboardRepo(db),
+			Snapshots:       stmysql.NewSnapshotRepo(db),
+			Versions:        stmysql.NewDashboardVersionRepo(db),
+			Monitors:        stmysql.NewMonitorRepo(db),
+			Reconciliations: stmysql.NewReconcileRepo(db),
+			ColumnTags:      stmysql.NewColumnTagRepo(db),
+			Users:           stmysql.NewUserRepo(db),
+			Impersonations:  stmysql.NewImpersonationRepo(db),
+			Credentials:     stmysql.NewCredentialRepo(db),
+			Invitations:     stmysql.NewInvitationRepo(db),
+			SCIMGroups:      stmysql.NewSCIMGroupRepo(db),
+			ExportAudit:     stmysql.NewExportAuditRepo(db),
+			RowEditAudit:    stmysql.NewRowEditAuditRepo(db),
+			SavedViews:      stmysql.NewSavedViewRepo(db),
 		}, nil
 	default:
 		return nil, fmt.Errorf("unsupported metadata_store.type: %s", cfg.Type)
 	}
 }
 
+// RetentionTables lists the metadata tables retention policies may prune.
+func RetentionTables(db *sqlx.DB, cfg config.DBConfig) []retention.Table {
+	var format func(time.Time) any
+	if cfg.Type == "sqlite" || cfg.Type == "sqlite3" {
+		format = stsqlite.FormatRunTime
+	}
+	return []retention.Table{
+		{Store: retention.StoreMetadata, Name: "monitor_runs", TimeColumn: "ran_at", DB: db, Format: format},
+		{Store: retention.StoreMetadata, Name: "reconciliation_runs", TimeColumn: "ran_at", DB: db, Format: format},
+	}
+}
+
+// PersonalDataTables lists the metadata tables that name users, for
+// personal data exports and erasure.
+func PersonalDataTables(db *sqlx.DB) []privacy.Table {
+	tables := []privacy.Table{
+		{Name: "users", UserColumns: []string{"username"}},
+		{Name: "data_sources", UserColumns: []string{"created_by"}},
+		{Name: "resource_owners", UserColumns: []string{"owner", "steward", "updated_by"}},
+		{Name: "dashboard_snapshots", UserColumns: []string{"created_by"}},
+		{Name: "dashboard_versions", UserColumns: []string{"created_by"}},
+		{Name: "user_invitations", UserColumns: []string{"invited_by"}, EmailColumns: []string{"email"}},
+		{Name: "impersonation_audit", UserColumns: []string{"actor", "target"}},
+		{Name: "export_audit", UserColumns: []string{"actor"}},
+		{Name: "row_edit_audit", UserColumns: []string{"actor"}},
+		{Name: "saved_views", UserColumns: []string{"owner"}},
+	}
+	for i := range tables {
+		tables[i].Store, tables[i].DB = retention.StoreMetadata, db
+	}
+	return tables
+}
+
+// migrateMu serializes goose calls: goose keeps its settings in globals.
+var migrateMu sync.Mutex
+
 func runMigrations(db *sqlx.DB, dbType string) error {
+	migrateMu.Lock()
+	defer migrateMu.Unlock()
+	_, dir, err := useMigrations(dbType)
+	if err != nil {
+		return err
+	}
+	return goose.Up(db.DB, dir)
+}
+
+// MigrationStatus returns the schema version db is at and the latest one
+// this build ships, without applying anything.
+func MigrationStatus(db *sqlx.DB, dbType string) (current, latest int64, err error) {
+	migrateMu.Lock()
+	defer migrateMu.Unlock()
+	_, dir, err := useMigrations(dbType)
+	if err != nil {
+		return 0, 0, err
+	}
+	all, err := goose.CollectMigrations(dir, 0, goose.MaxVersion)
+	if err != nil {
+		return 0, 0, err
+	}
+	if last, err := all.Last(); err == nil {
+		latest = last.Version
+	}
+	current, err = goose.GetDBVersion(db.DB)
+	if err != nil {
+		return 0, 0, err
+	}
+	return current, latest, nil
+}
+
+// useMigrations points goose at dbType's migrations and returns them and
+// their directory. Callers hold migrateMu.
+func useMigrations(dbType string) (fs.FS, string, error) {
 	var (
-		fs      embed.FS
+		fsys    embed.FS
 		dir     string
 		dialect string
 	)
 	switch dbType {
 	case "postgres", "postgresql":
-		fs, dir, dialect = postgresMigrations, "migrations/postgres", "postgres"
+		fsys, dir, dialect = postgresMigrations, "migrations/postgres", "postgres"
 	case "sqlite", "sqlite3":
-		fs, dir, dialect = sqliteMigrations, "migrations/sqlite", "sqlite3"
+		fsys, dir, dialect = sqliteMigrations, "migrations/sqlite", "sqlite3"
 	case "mysql":
-		fs, dir, dialect = mysqlMigrations, "migrations/mysql", "mysql"
+		fsys, dir, dialect = mysqlMigrations, "migrations/mysql", "mysql"
 	default:
-		return fmt.Errorf("unsupported metadata_store.type: %s", dbType)
+		return nil, "", fmt.Errorf("unsupported metadata_store.type: %s", dbType)
 	}
-
-	goose.SetBaseFS(fs)
+	goose.SetBaseFS(fsys)
 	if err := goose.SetDialect(dialect); err != nil {
-		return err
+		return nil, "", err
 	}
-	return goose.Up(db.DB, dir)
+	return fsys, dir, nil
 }
@@ -0,0 +1,33 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/config"
+)
+
+// openMemory is the "memory" backend: a SQLite database that lives in the
+// process and is gone when it closes. It suits tests and throwaway demos.
+func openMemory(cfg config.DBConfig) (MetadataStore, error) {
+	db, err := sqlx.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("open db (memory): %w", err)
+	}
+	// Every connection to ":memory:" is a database of its own; keep one.
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxLifetime(0)
+	db.SetConnMaxIdleTime(0)
+	if err := runMigrations(db, "sqlite"); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("migrate (memory): %w", err)
+	}
+	cfg.Type = "sqlite"
+	repos, err := NewRepos(db, cfg)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &sqlStore{db: db, cfg: cfg, repos: repos}, nil
+}
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/privacy"
+	"data-voyager/core/internal/retention"
+)
+
+// MetadataStore is where the app keeps its own state: datasources, users,
+// dashboards and the rest of Repos. The SQL databases are one backend;
+// others (an embedded key-value file, a remote API, an in-memory fake for
+// tests) only need to provide the same repositories.
+type MetadataStore interface {
+	// Repos returns the repositories backed by the store. Every field is set.
+	Repos() *Repos
+	// RetentionTables lists what retention policies may prune; nil when
+	// the backend prunes nothing.
+	RetentionTables() []retention.Table
+	// PersonalDataTables lists the tables that name users, for personal
+	// data exports and erasure; nil when the backend keeps none.
+	PersonalDataTables() []privacy.Table
+	Close() error
+}
+
+// Backend opens a MetadataStore for cfg.
+type Backend func(cfg config.DBConfig) (MetadataStore, error)
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]Backend{
+		"sqlite":     openSQL,
+		"sqlite3":    openSQL,
+		"postgres":   openSQL,
+		"postgresql": openSQL,
+		"mysql":      openSQL,
+		"memory":     openMemory,
+	}
+)
+
+// RegisterBackend makes a backend available as metadata_store.type = typ,
+// replacing any backend already registered under that name.
+func RegisterBackend(typ string, b Backend) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	backends[typ] = b
+}
+
+// Backends lists the registered metadata_store types.
+func Backends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	out := make([]string, 0, len(backends))
+	for typ := range backends {
+		out = append(out, typ)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// OpenMetadataStore opens the backend cfg.Type names.
+func OpenMetadataStore(cfg config.DBConfig) (MetadataStore, error) {
+	backendsMu.RLock()
+	b, ok := backends[cfg.Type]
+	backendsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported metadata_store.type: %s", cfg.Type)
+	}
+	return b(cfg)
+}
+
+// sqlStore is the SQL backend: one database, migrated with goose.
+type sqlStore struct {
+	db    *sqlx.DB
+	cfg   config.DBConfig
+	repos *Repos
+}
+
+func openSQL(cfg config.DBConfig) (MetadataStore, error) {
+	db, err := Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	repos, err := NewRepos(db, cfg)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &sqlStore{db: db, cfg: cfg, repos: repos}, nil
+}
+
+func (s *sqlStore) Repos() *Repos { return s.repos }
+
+func (s *sqlStore) RetentionTables() []retention.Table { return RetentionTables(s.db, s.cfg) }
+
+func (s *sqlStore) PersonalDataTables() []privacy.Table { return PersonalDataTables(s.db) }
+
+func (s *sqlStore) Close() error { return s.db.Close() }
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/privacy"
+	"data-voyager/core/internal/retention"
+)
+
+func TestOpenMetadataStore_SQLite(t *testing.T) {
+	cfg := config.DBConfig{Type: "sqlite", MigrateOnStart: true}
+	cfg.SQLite.Path = filepath.Join(t.TempDir(), "meta.db")
+	ms, err := OpenMetadataStore(cfg)
+	require.NoError(t, err)
+	defer func() { _ = ms.Close() }()
+
+	_, err = ms.Repos().Dashboards.List(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, ms.RetentionTables(), 2)
+}
+
+type fakeStore struct{ closed bool }
+
+func (f *fakeStore) Repos() *Repos                       { return &Repos{} }
+func (f *fakeStore) RetentionTables() []retention.Table  { return nil }
+func (f *fakeStore) PersonalDataTables() []privacy.Table { return nil }
+func (f *fakeStore) Close() error                        { f.closed = true; return nil }
+
+func TestOpenMetadataStore_Registered(t *testing.T) {
+	_, err := OpenMetadataStore(config.DBConfig{Type: "bolt"})
+	assert.ErrorContains(t, err, "unsupported metadata_store.type: bolt")
+
+	fake := &fakeStore{}
+	RegisterBackend("bolt", func(config.DBConfig) (MetadataStore, error) { return fake, nil })
+	t.Cleanup(func() {
+		backendsMu.Lock()
+		delete(backends, "bolt")
+		backendsMu.Unlock()
+	})
+	assert.Contains(t, Backends(), "bolt")
+
+	ms, err := OpenMetadataStore(config.DBConfig{Type: "bolt"})
+	require.NoError(t, err)
+	require.NoError(t, ms.Close())
+	assert.True(t, fake.closed)
+}
+
+func TestOpenMetadataStore_Memory(t *testing.T) {
+	ms, err := OpenMetadataStore(config.DBConfig{Type: "memory"})
+	require.NoError(t, err)
+	defer func() { _ = ms.Close() }()
+
+	ctx := context.Background()
+	require.NoError(t, ms.Repos().Settings.Set(ctx, "k", "v", false))
+	got, err := ms.Repos().Settings.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.Equal(t, "v", got.Value)
+
+	other, err := OpenMetadataStore(config.DBConfig{Type: "memory"})
+	require.NoError(t, err)
+	defer func() { _ = other.Close() }()
+	_, err = other.Repos().Settings.Get(ctx, "k")
+	assert.Error(t, err, "each memory store is separate")
+}
+
+func TestMigrationStatus(t *testing.T) {
+	cfg := config.DBConfig{Type: "sqlite"}
+	cfg.SQLite.Path = filepath.Join(t.TempDir(), "meta.db")
+	db, err := Open(cfg)
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	current, latest, err := MigrationStatus(db, "sqlite")
+	require.NoError(t, err)
+	assert.Zero(t, current)
+	assert.Positive(t, latest)
+
+	require.NoError(t, runMigrations(db, "sqlite"))
+	current, latest2, err := MigrationStatus(db, "sqlite")
+	require.NoError(t, err)
+	assert.Equal(t, latest, current)
+	assert.Equal(t, latest, latest2)
+}
@@ -0,0 +1,309 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pressly/goose/v3"
+)
+
+// BackupSchema is the PostgreSQL schema pre-migration table snapshots are
+// kept in. Each snapshot is named <table>_v<version>, version being the
+// schema version it was taken at.
+const BackupSchema = "dv_migration_backup"
+
+// migrationLockKey is the PostgreSQL advisory lock held while migrating, so
+// servers starting together apply each migration once.
+const migrationLockKey = 7_316_402_115
+
+// Migration is a schema migration this build ships.
+type Migration struct {
+	Version int64  `json:"version"`
+	Name    string `json:"name"`
+	// Tables are the existing tables the migration alters or rewrites;
+	// tables it creates are not listed.
+	Tables []string `json:"tables"`
+}
+
+// Backup is a snapshot of one table taken before migrating.
+type Backup struct {
+	Version int64  `json:"version"`
+	Table   string `json:"table"`
+	// Name is the snapshot's table name within BackupSchema.
+	Name string `json:"name"`
+}
+
+// changedTableRe finds the tables a migration's statements change.
+var changedTableRe = regexp.MustCompile(`(?i)\b(?:ALTER\s+TABLE(?:\s+IF\s+EXISTS)?|UPDATE|DELETE\s+FROM|INSERT\s+INTO|DROP\s+TABLE(?:\s+IF\s+EXISTS)?|TRUNCATE(?:\s+TABLE)?)\s+"?([A-Za-z_][A-Za-z0-9_]*)"?`)
+
+var backupNameRe = regexp.MustCompile(`^(.+)_v(\d+)$`)
+
+// Pending returns the migrations db has not applied yet, oldest first.
+func Pending(db *sqlx.DB, dbType string) ([]Migration, error) {
+	migrateMu.Lock()
+	defer migrateMu.Unlock()
+	return pending(db, dbType)
+}
+
+// pending is Pending for callers holding migrateMu.
+func pending(db *sqlx.DB, dbType string) ([]Migration, error) {
+	fsys, dir, err := useMigrations(dbType)
+	if err != nil {
+		return nil, err
+	}
+	current, err := goose.GetDBVersion(db.DB)
+	if err != nil {
+		return nil, err
+	}
+	all, err := goose.CollectMigrations(dir, 0, goose.MaxVersion)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Migration, 0, len(all))
+	for _, m := range all {
+		if m.Version <= current {
+			continue
+		}
+		src, err := fs.ReadFile(fsys, m.Source)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, Migration{Version: m.Version, Name: m.Source[strings.LastIndex(m.Source, "/")+1:],
+			Tables: changedTables(string(src))})
+	}
+	return out, nil
+}
+
+// changedTables lists the tables the Up section of a migration changes.
+func changedTables(src string) []string {
+	up, _, _ := strings.Cut(src, "-- +goose Down")
+	tables := []string{}
+	for _, m := range changedTableRe.FindAllStringSubmatch(up, -1) {
+		name := strings.ToLower(m[1])
+		if !slices.Contains(tables, name) {
+			tables = append(tables, name)
+		}
+	}
+	return tables
+}
+
+// Migrate applies the pending migrations. Each runs in its own transaction
+// unless it is marked NO TRANSACTION, so a failing migration leaves the
+// schema at the last one that succeeded. On PostgreSQL it holds an advisory
+// lock while migrating and, with backup set, first copies every existing
+// table the pending migrations change into BackupSchema; Rollback restores
+// them. It returns the snapshots taken.
+func Migrate(ctx context.Context, db *sqlx.DB, dbType string, backup bool) ([]Backup, error) {
+	migrateMu.Lock()
+	defer migrateMu.Unlock()
+	if !isPostgres(dbType) {
+		_, dir, err := useMigrations(dbType)
+		if err != nil {
+			return nil, err
+		}
+		return nil, goose.UpContext(ctx, db.DB, dir)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return nil, fmt.Errorf("lock migrations: %w", err)
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+	}()
+
+	todo, err := pending(db, dbType)
+	if err != nil || len(todo) == 0 {
+		return nil, err
+	}
+	var backups []Backup
+	if backup {
+		if backups, err = snapshot(ctx, db, todo); err != nil {
+			return nil, fmt.Errorf("back up before migrating: %w", err)
+		}
+	}
+	_, dir, err := useMigrations(dbType)
+	if err != nil {
+		return backups, err
+	}
+	if err := goose.UpContext(ctx, db.DB, dir); err != nil {
+		return backups, err
+	}
+	return backups, nil
+}
+
+// snapshot copies the existing tables todo changes into BackupSchema. A
+// snapshot already taken at the current version is kept, so retrying a
+// failed migration does not overwrite it.
+func snapshot(ctx context.Context, db *sqlx.DB, todo []Migration) ([]Backup, error) {
+	current, err := goose.GetDBVersion(db.DB)
+	if err != nil {
+		return nil, err
+	}
+	var tables []string
+	for _, m := range todo {
+		for _, t := range m.Tables {
+			if !slices.Contains(tables, t) {
+				tables = append(tables, t)
+			}
+		}
+	}
+	var existing []string
+	if err := db.SelectContext(ctx, &existing, `
+		SELECT table_name FROM information_schema.tables
+		WHERE table_schema = current_schema() AND table_name = ANY($1)`, pq.Array(tables)); err != nil {
+		return nil, err
+	}
+	if len(existing) == 0 {
+		return nil, nil
+	}
+	if _, err := db.ExecContext(ctx, `CREATE SCHEMA IF NOT EXISTS `+pq.QuoteIdentifier(BackupSchema)); err != nil {
+		return nil, err
+	}
+	slices.Sort(existing)
+	backups := make([]Backup, 0, len(existing))
+	for _, t := range existing {
+		b := Backup{Version: current, Table: t, Name: t + "_v" + strconv.FormatInt(current, 10)}
+		if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+b.qualified()+
+			` AS TABLE `+pq.QuoteIdentifier(t)); err != nil {
+			return nil, fmt.Errorf("snapshot %s: %w", t, err)
+		}
+		slog.Info("backed up table before migrating", "table", t, "backup", BackupSchema+"."+b.Name)
+		backups = append(backups, b)
+	}
+	return backups, nil
+}
+
+func (b Backup) qualified() string {
+	return pq.QuoteIdentifier(BackupSchema) + "." + pq.QuoteIdentifier(b.Name)
+}
+
+// Backups lists the pre-migration snapshots kept in a PostgreSQL database,
+// newest first. Other databases have none.
+func Backups(ctx context.Context, db *sqlx.DB, dbType string) ([]Backup, error) {
+	if !isPostgres(dbType) {
+		return []Backup{}, nil
+	}
+	var names []string
+	if err := db.SelectContext(ctx, &names, `
+		SELECT table_name FROM information_schema.tables WHERE table_schema = $1`, BackupSchema); err != nil {
+		return nil, err
+	}
+	out := []Backup{}
+	for _, n := range names {
+		m := backupNameRe.FindStringSubmatch(n)
+		if m == nil {
+			continue
+		}
+		v, _ := strconv.ParseInt(m[2], 10, 64)
+		out = append(out, Backup{Version: v, Table: m[1], Name: n})
+	}
+	slices.SortFunc(out, func(a, b Backup) int {
+		if a.Version != b.Version {
+			return int(b.Version - a.Version)
+		}
+		return strings.Compare(a.Table, b.Table)
+	})
+	return out, nil
+}
+
+// Rollback migrates db down to version. On PostgreSQL it then replaces the
+// contents of each table snapshotted at version with the snapshot, in one
+// transaction, undoing data changes the down migrations cannot. Rows
+// written after the snapshot are lost, as with restoring any backup. It
+// returns the snapshots restored.
+func Rollback(ctx context.Context, db *sqlx.DB, dbType string, version int64) ([]Backup, error) {
+	migrateMu.Lock()
+	defer migrateMu.Unlock()
+	_, dir, err := useMigrations(dbType)
+	if err != nil {
+		return nil, err
+	}
+	current, err := goose.GetDBVersion(db.DB)
+	if err != nil {
+		return nil, err
+	}
+	if version < 0 || version > current {
+		return nil, fmt.Errorf("cannot roll back to version %d: the schema is at %d", version, current)
+	}
+	if err := goose.DownToContext(ctx, db.DB, dir, version); err != nil {
+		return nil, err
+	}
+	all, err := Backups(ctx, db, dbType)
+	if err != nil {
+		return nil, err
+	}
+	var restore []Backup
+	for _, b := range all {
+		if b.Version == version {
+			restore = append(restore, b)
+		}
+	}
+	if len(restore) == 0 {
+		return []Backup{}, nil
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	for _, b := range restore {
+		var columns []string
+		if err := tx.SelectContext(ctx, &columns, `
+			SELECT column_name FROM information_schema.columns
+			WHERE table_schema = $1 AND table_name = $2 ORDER BY ordinal_position`, BackupSchema, b.Name); err != nil {
+			return nil, err
+		}
+		for i, c := range columns {
+			columns[i] = pq.QuoteIdentifier(c)
+		}
+		list := strings.Join(columns, ", ")
+		table := pq.QuoteIdentifier(b.Table)
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+table); err != nil {
+			return nil, fmt.Errorf("restore %s: %w", b.Table, err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO `+table+` (`+list+`) SELECT `+list+` FROM `+b.qualified()); err != nil {
+			return nil, fmt.Errorf("restore %s: %w", b.Table, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return restore, nil
+}
+
+// DropBackups deletes the PostgreSQL snapshots taken at or before version.
+func DropBackups(ctx context.Context, db *sqlx.DB, dbType string, version int64) (int, error) {
+	all, err := Backups(ctx, db, dbType)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, b := range all {
+		if b.Version > version {
+			continue
+		}
+		if _, err := db.ExecContext(ctx, `DROP TABLE IF EXISTS `+b.qualified()); err != nil {
+			return n, fmt.Errorf("drop %s: %w", b.Name, err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+func isPostgres(dbType string) bool {
+	return dbType == "postgres" || dbType == "postgresql"
+}
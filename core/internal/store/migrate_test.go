@@ -0,0 +1,127 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"data-voyager/core/internal/config"
+)
+
+func TestChangedTables(t *testing.T) {
+	src := `-- +goose Up
+CREATE TABLE IF NOT EXISTS fresh (id TEXT);
+ALTER TABLE dashboard_snapshots ADD COLUMN label TEXT;
+ALTER TABLE "Users" ADD COLUMN x TEXT;
+UPDATE users SET x = '';
+INSERT INTO settings (key) VALUES ('a');
+
+-- +goose Down
+DROP TABLE fresh;
+DELETE FROM monitors;
+`
+	assert.Equal(t, []string{"dashboard_snapshots", "users", "settings"}, changedTables(src))
+}
+
+func TestMigrate_SQLite(t *testing.T) {
+	cfg := config.DBConfig{Type: "sqlite", MigrationBackup: true}
+	cfg.SQLite.Path = filepath.Join(t.TempDir(), "meta.db")
+	db, err := Open(cfg)
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	todo, err := Pending(db, "sqlite")
+	require.NoError(t, err)
+	_, latest, err := MigrationStatus(db, "sqlite")
+	require.NoError(t, err)
+	require.Len(t, todo, int(latest))
+	labels := todo[slices.IndexFunc(todo, func(m Migration) bool { return m.Version == 26 })]
+	assert.Equal(t, "026_snapshot_labels.sql", labels.Name)
+	assert.Equal(t, []string{"dashboard_snapshots"}, labels.Tables)
+
+	backups, err := Migrate(ctx, db, "sqlite", true)
+	require.NoError(t, err)
+	assert.Empty(t, backups, "only PostgreSQL is snapshotted")
+	todo, err = Pending(db, "sqlite")
+	require.NoError(t, err)
+	assert.Empty(t, todo)
+
+	restored, err := Rollback(ctx, db, "sqlite", latest-1)
+	require.NoError(t, err)
+	assert.Empty(t, restored)
+	current, _, err := MigrationStatus(db, "sqlite")
+	require.NoError(t, err)
+	assert.Equal(t, latest-1, current)
+
+	_, err = Rollback(ctx, db, "sqlite", latest+1)
+	assert.Error(t, err)
+}
+
+func TestMigrate_Postgres(t *testing.T) {
+	ctx := context.Background()
+	ctr, err := tcpostgres.Run(ctx,
+		"postgres:15-alpine",
+		tcpostgres.WithDatabase("testdb"),
+		tcpostgres.WithUsername("test"),
+		tcpostgres.WithPassword("test"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(30*time.Second),
+		),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := testcontainers.TerminateContainer(ctr); err != nil {
+			t.Logf("terminate container: %v", err)
+		}
+	})
+	dsn, err := ctr.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+	db, err := sqlx.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	// Start just before 026_snapshot_labels, which alters dashboard_snapshots.
+	const before int64 = 25
+	_, dir, err := useMigrations("postgres")
+	require.NoError(t, err)
+	require.NoError(t, goose.UpTo(db.DB, dir, before))
+	_, err = db.Exec(`INSERT INTO dashboard_snapshots (id, dashboard_id, name, created_by, content)
+		VALUES ('s1', 'd1', 'Q1', 'ann', '{}')`)
+	require.NoError(t, err)
+
+	backups, err := Migrate(ctx, db, "postgres", true)
+	require.NoError(t, err)
+	require.Equal(t, []Backup{{Version: before, Table: "dashboard_snapshots",
+		Name: "dashboard_snapshots_v" + strconv.FormatInt(before, 10)}}, backups)
+
+	// A row changed after migrating is put back by the rollback.
+	_, err = db.Exec(`UPDATE dashboard_snapshots SET name = 'changed', label = 'secret'`)
+	require.NoError(t, err)
+	restored, err := Rollback(ctx, db, "postgres", before)
+	require.NoError(t, err)
+	assert.Equal(t, backups, restored)
+	var name string
+	require.NoError(t, db.Get(&name, `SELECT name FROM dashboard_snapshots WHERE id = 's1'`))
+	assert.Equal(t, "Q1", name)
+
+	listed, err := Backups(ctx, db, "postgres")
+	require.NoError(t, err)
+	assert.Equal(t, backups, listed)
+	n, err := DropBackups(ctx, db, "postgres", before)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS datasource_templates (
+    id          VARCHAR(36)  NOT NULL PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL,
+    type        VARCHAR(64)  NOT NULL,
+    config      TEXT         NOT NULL,
+    description TEXT         NOT NULL,
+    tags        TEXT         NOT NULL,
+    created_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT uq_datasource_templates_name UNIQUE (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE data_sources ADD COLUMN template_id VARCHAR(36) NOT NULL DEFAULT '';
+ALTER TABLE data_sources ADD COLUMN overrides   TEXT NOT NULL;
+
+CREATE INDEX idx_data_sources_template_id ON data_sources (template_id);
+
+-- +goose Down
+DROP INDEX idx_data_sources_template_id ON data_sources;
+ALTER TABLE data_sources DROP COLUMN overrides;
+ALTER TABLE data_sources DROP COLUMN template_id;
+DROP TABLE IF EXISTS datasource_templates;
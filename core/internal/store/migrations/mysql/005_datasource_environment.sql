@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE data_sources ADD COLUMN environment VARCHAR(32) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_data_sources_environment ON data_sources (environment);
+
+-- +goose Down
+DROP INDEX idx_data_sources_environment ON data_sources;
+ALTER TABLE data_sources DROP COLUMN environment;
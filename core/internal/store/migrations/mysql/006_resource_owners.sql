@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS resource_owners (
+    id            VARCHAR(36)  NOT NULL PRIMARY KEY,
+    resource_type VARCHAR(32)  NOT NULL,
+    resource_id   VARCHAR(512) NOT NULL,
+    owner         VARCHAR(255) NOT NULL DEFAULT '',
+    owner_kind    VARCHAR(16)  NOT NULL DEFAULT 'user',
+    steward       VARCHAR(255) NOT NULL DEFAULT '',
+    updated_at    DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by    VARCHAR(255) NOT NULL DEFAULT '',
+    CONSTRAINT uq_resource_owners_resource UNIQUE (resource_type, resource_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE INDEX idx_resource_owners_owner   ON resource_owners (owner);
+CREATE INDEX idx_resource_owners_steward ON resource_owners (steward);
+
+-- +goose Down
+DROP TABLE IF EXISTS resource_owners;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE data_sources ADD COLUMN deprecation TEXT NOT NULL;
+
+-- +goose Down
+ALTER TABLE data_sources DROP COLUMN deprecation;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS notification_channels (
+    id             VARCHAR(36)  NOT NULL PRIMARY KEY,
+    name           VARCHAR(255) NOT NULL,
+    type           VARCHAR(32)  NOT NULL,
+    config         TEXT         NOT NULL,
+    events         TEXT         NOT NULL,
+    title_template TEXT         NOT NULL,
+    body_template  TEXT         NOT NULL,
+    enabled        TINYINT(1)   NOT NULL DEFAULT 1,
+    created_at     DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at     DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT uq_notification_channels_name UNIQUE (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS notification_channels;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- NULL rather than '' when unset so the unique index ignores it.
+ALTER TABLE data_sources ADD COLUMN external_id VARCHAR(255) NULL;
+ALTER TABLE data_sources ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+CREATE UNIQUE INDEX uq_data_sources_external_id ON data_sources (external_id);
+
+-- +goose Down
+DROP INDEX uq_data_sources_external_id ON data_sources;
+ALTER TABLE data_sources DROP COLUMN version;
+ALTER TABLE data_sources DROP COLUMN external_id;
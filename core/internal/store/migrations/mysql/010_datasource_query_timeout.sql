@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE data_sources ADD COLUMN query_timeout INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE data_sources DROP COLUMN query_timeout;
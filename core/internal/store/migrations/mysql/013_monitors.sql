@@ -0,0 +1,32 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS monitors (
+    id               VARCHAR(36)  NOT NULL PRIMARY KEY,
+    name             VARCHAR(255) NOT NULL,
+    description      TEXT         NOT NULL,
+    datasource_id    VARCHAR(36)  NOT NULL,
+    query            MEDIUMTEXT   NOT NULL,
+    interval_seconds INT          NOT NULL,
+    detector         TEXT         NOT NULL,
+    severity         VARCHAR(16)  NOT NULL DEFAULT 'warning',
+    enabled          TINYINT(1)   NOT NULL DEFAULT 1,
+    created_at       DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at       DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS monitor_runs (
+    id         VARCHAR(36) NOT NULL PRIMARY KEY,
+    monitor_id VARCHAR(36) NOT NULL,
+    ran_at     DATETIME(3) NOT NULL,
+    value      DOUBLE,
+    error      TEXT        NOT NULL,
+    expected   DOUBLE,
+    lower      DOUBLE,
+    upper      DOUBLE,
+    score      DOUBLE,
+    anomalous  TINYINT(1)  NOT NULL DEFAULT 0,
+    INDEX idx_monitor_runs_monitor (monitor_id, ran_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS monitor_runs;
+DROP TABLE IF EXISTS monitors;
@@ -0,0 +1,34 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS reconciliations (
+    id               VARCHAR(36)  NOT NULL PRIMARY KEY,
+    name             VARCHAR(255) NOT NULL,
+    description      TEXT         NOT NULL,
+    source           MEDIUMTEXT   NOT NULL,
+    target           MEDIUMTEXT   NOT NULL,
+    comparison       TEXT         NOT NULL,
+    interval_seconds INT          NOT NULL,
+    severity         VARCHAR(16)  NOT NULL DEFAULT 'warning',
+    enabled          TINYINT(1)   NOT NULL DEFAULT 1,
+    created_at       DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at       DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS reconciliation_runs (
+    id            VARCHAR(36) NOT NULL PRIMARY KEY,
+    job_id        VARCHAR(36) NOT NULL,
+    ran_at        DATETIME(3) NOT NULL,
+    status        VARCHAR(16) NOT NULL,
+    error         TEXT        NOT NULL,
+    source_groups INT         NOT NULL DEFAULT 0,
+    target_groups INT         NOT NULL DEFAULT 0,
+    matched       INT         NOT NULL DEFAULT 0,
+    missing       INT         NOT NULL DEFAULT 0,
+    extra         INT         NOT NULL DEFAULT 0,
+    mismatched    INT         NOT NULL DEFAULT 0,
+    discrepancies MEDIUMTEXT  NOT NULL,
+    INDEX idx_reconciliation_runs_job (job_id, ran_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS reconciliation_runs;
+DROP TABLE IF EXISTS reconciliations;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS column_tags (
+    datasource_id VARCHAR(36)  NOT NULL,
+    schema_name   VARCHAR(191) NOT NULL DEFAULT '',
+    table_name    VARCHAR(191) NOT NULL,
+    column_name   VARCHAR(191) NOT NULL,
+    category      VARCHAR(32)  NOT NULL,
+    confidence    DOUBLE       NOT NULL DEFAULT 0,
+    source        VARCHAR(16)  NOT NULL,
+    updated_at    DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (datasource_id, schema_name, table_name, column_name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS column_tags;
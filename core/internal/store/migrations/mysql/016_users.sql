@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS users (
+    id          VARCHAR(36)  NOT NULL PRIMARY KEY,
+    username    VARCHAR(128) NOT NULL UNIQUE,
+    email       VARCHAR(255) NOT NULL DEFAULT '',
+    name        VARCHAR(255) NOT NULL DEFAULT '',
+    role        VARCHAR(32)  NOT NULL,
+    datasources TEXT         NOT NULL,
+    disabled    BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS impersonation_audit (
+    id     VARCHAR(36)  NOT NULL PRIMARY KEY,
+    actor  VARCHAR(128) NOT NULL,
+    target VARCHAR(128) NOT NULL,
+    method VARCHAR(16)  NOT NULL,
+    path   TEXT         NOT NULL,
+    status INT          NOT NULL,
+    at     DATETIME(3)  NOT NULL,
+    INDEX idx_impersonation_audit_target (target, at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS impersonation_audit;
+DROP TABLE IF EXISTS users;
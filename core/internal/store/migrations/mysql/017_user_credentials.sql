@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_credentials (
+    user_id             VARCHAR(36)  NOT NULL PRIMARY KEY,
+    password_hash       VARCHAR(255) NOT NULL DEFAULT '',
+    failed_attempts     INT          NOT NULL DEFAULT 0,
+    locked_until        DATETIME(3)  NULL,
+    totp_secret         TEXT         NOT NULL,
+    totp_enabled        BOOLEAN      NOT NULL DEFAULT FALSE,
+    password_changed_at DATETIME(3)  NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS user_credentials;
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS scim_groups (
+    id           VARCHAR(36)  NOT NULL PRIMARY KEY,
+    display_name VARCHAR(255) NOT NULL UNIQUE,
+    external_id  VARCHAR(255) NOT NULL DEFAULT '',
+    members      MEDIUMTEXT   NOT NULL,
+    created_at   DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS scim_groups;
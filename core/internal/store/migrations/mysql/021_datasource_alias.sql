@@ -0,0 +1,10 @@
+-- +goose Up
+-- NULL rather than '' when unset so the unique index ignores it. An alias
+-- names one datasource per environment.
+ALTER TABLE data_sources ADD COLUMN alias VARCHAR(255) NULL;
+
+CREATE UNIQUE INDEX uq_data_sources_alias ON data_sources (alias, environment);
+
+-- +goose Down
+DROP INDEX uq_data_sources_alias ON data_sources;
+ALTER TABLE data_sources DROP COLUMN alias;
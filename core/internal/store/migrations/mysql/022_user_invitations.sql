@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_invitations (
+    id          VARCHAR(36)  NOT NULL PRIMARY KEY,
+    email       VARCHAR(255) NOT NULL,
+    name        VARCHAR(255) NOT NULL DEFAULT '',
+    role        VARCHAR(32)  NOT NULL,
+    datasources TEXT         NOT NULL,
+    token_hash  VARCHAR(64)  NOT NULL UNIQUE,
+    invited_by  VARCHAR(128) NOT NULL DEFAULT '',
+    sent_count  INT          NOT NULL DEFAULT 0,
+    expires_at  DATETIME(3)  NOT NULL,
+    accepted_at DATETIME(3)  NULL,
+    user_id     VARCHAR(36)  NOT NULL DEFAULT '',
+    created_at  DATETIME(3)  NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    updated_at  DATETIME(3)  NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    INDEX idx_user_invitations_email (email)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS user_invitations;
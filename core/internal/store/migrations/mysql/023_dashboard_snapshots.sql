@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS dashboard_snapshots (
+    id           VARCHAR(36)  NOT NULL PRIMARY KEY,
+    dashboard_id VARCHAR(36)  NOT NULL,
+    name         VARCHAR(255) NOT NULL,
+    created_by   VARCHAR(255) NOT NULL DEFAULT '',
+    content      LONGTEXT     NOT NULL,
+    created_at   DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+CREATE INDEX idx_dashboard_snapshots_dashboard ON dashboard_snapshots (dashboard_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS dashboard_snapshots;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS export_audit (
+    id            VARCHAR(36)  NOT NULL PRIMARY KEY,
+    export_id     VARCHAR(36)  NOT NULL,
+    datasource_id VARCHAR(36)  NOT NULL,
+    schema_name   VARCHAR(255) NOT NULL DEFAULT '',
+    table_name    VARCHAR(255) NOT NULL,
+    actor         VARCHAR(255) NOT NULL DEFAULT '',
+    delivery      VARCHAR(32)  NOT NULL,
+    row_count     BIGINT       NOT NULL DEFAULT 0,
+    size_bytes    BIGINT       NOT NULL DEFAULT 0,
+    watermark     VARCHAR(64)  NOT NULL DEFAULT '',
+    address       VARCHAR(64)  NOT NULL DEFAULT '',
+    at            DATETIME(3)  NOT NULL,
+    INDEX idx_export_audit_at (at),
+    INDEX idx_export_audit_watermark (watermark)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS export_audit;
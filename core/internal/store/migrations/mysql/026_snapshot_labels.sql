@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE dashboard_snapshots ADD COLUMN label VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE dashboard_snapshots ADD COLUMN expires_at DATETIME NULL;
+CREATE INDEX idx_dashboard_snapshots_expires ON dashboard_snapshots (expires_at);
+
+-- +goose Down
+DROP INDEX idx_dashboard_snapshots_expires ON dashboard_snapshots;
+ALTER TABLE dashboard_snapshots DROP COLUMN expires_at;
+ALTER TABLE dashboard_snapshots DROP COLUMN label;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS row_edit_audit (
+    id            VARCHAR(36)  NOT NULL PRIMARY KEY,
+    table_ref     VARCHAR(255) NOT NULL,
+    datasource_id VARCHAR(36)  NOT NULL,
+    schema_name   VARCHAR(255) NOT NULL DEFAULT '',
+    table_name    VARCHAR(255) NOT NULL,
+    op            VARCHAR(16)  NOT NULL,
+    row_key       TEXT         NOT NULL,
+    before_row    MEDIUMTEXT,
+    after_row     MEDIUMTEXT,
+    actor         VARCHAR(255) NOT NULL DEFAULT '',
+    at            DATETIME(3)  NOT NULL,
+    INDEX idx_row_edit_audit_at (at),
+    INDEX idx_row_edit_audit_table (table_ref)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS row_edit_audit;
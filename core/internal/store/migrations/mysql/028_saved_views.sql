@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS saved_views (
+    id            VARCHAR(36)  NOT NULL PRIMARY KEY,
+    name          VARCHAR(255) NOT NULL,
+    datasource_id VARCHAR(36)  NOT NULL,
+    schema_name   VARCHAR(255) NOT NULL DEFAULT '',
+    table_name    VARCHAR(255) NOT NULL,
+    spec          MEDIUMTEXT   NOT NULL,
+    shared        BOOLEAN      NOT NULL DEFAULT FALSE,
+    owner         VARCHAR(255) NOT NULL DEFAULT '',
+    created_at    DATETIME(3)  NOT NULL,
+    updated_at    DATETIME(3)  NOT NULL,
+    INDEX idx_saved_views_table (datasource_id, table_name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS saved_views;
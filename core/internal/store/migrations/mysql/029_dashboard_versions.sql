@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS dashboard_versions (
+    dashboard_id VARCHAR(36)  NOT NULL,
+    version      INT          NOT NULL,
+    created_by   VARCHAR(255) NOT NULL DEFAULT '',
+    content      LONGTEXT     NOT NULL,
+    created_at   DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (dashboard_id, version)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS dashboard_versions;
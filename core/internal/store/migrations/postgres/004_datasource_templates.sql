@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS datasource_templates (
+    id          TEXT         PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL,
+    type        VARCHAR(64)  NOT NULL,
+    config      TEXT         NOT NULL DEFAULT '{}',
+    description TEXT         NOT NULL DEFAULT '',
+    tags        TEXT         NOT NULL DEFAULT '[]',
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_datasource_templates_name UNIQUE (name)
+);
+
+ALTER TABLE data_sources ADD COLUMN IF NOT EXISTS template_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE data_sources ADD COLUMN IF NOT EXISTS overrides   TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_data_sources_template_id ON data_sources (template_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_data_sources_template_id;
+ALTER TABLE data_sources DROP COLUMN IF EXISTS overrides;
+ALTER TABLE data_sources DROP COLUMN IF EXISTS template_id;
+DROP TABLE IF EXISTS datasource_templates;
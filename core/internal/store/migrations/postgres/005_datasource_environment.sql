@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE data_sources ADD COLUMN IF NOT EXISTS environment VARCHAR(32) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_data_sources_environment ON data_sources (environment);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_data_sources_environment;
+ALTER TABLE data_sources DROP COLUMN IF EXISTS environment;
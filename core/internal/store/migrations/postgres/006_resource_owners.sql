@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS resource_owners (
+    id            TEXT         PRIMARY KEY,
+    resource_type VARCHAR(32)  NOT NULL,
+    resource_id   VARCHAR(512) NOT NULL,
+    owner         VARCHAR(255) NOT NULL DEFAULT '',
+    owner_kind    VARCHAR(16)  NOT NULL DEFAULT 'user',
+    steward       VARCHAR(255) NOT NULL DEFAULT '',
+    updated_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_by    VARCHAR(255) NOT NULL DEFAULT '',
+    CONSTRAINT uq_resource_owners_resource UNIQUE (resource_type, resource_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_resource_owners_owner   ON resource_owners (owner);
+CREATE INDEX IF NOT EXISTS idx_resource_owners_steward ON resource_owners (steward);
+
+-- +goose Down
+DROP TABLE IF EXISTS resource_owners;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS notification_channels (
+    id             TEXT         PRIMARY KEY,
+    name           VARCHAR(255) NOT NULL UNIQUE,
+    type           VARCHAR(32)  NOT NULL,
+    config         TEXT         NOT NULL DEFAULT '',
+    events         TEXT         NOT NULL DEFAULT '[]',
+    title_template TEXT         NOT NULL DEFAULT '',
+    body_template  TEXT         NOT NULL DEFAULT '',
+    enabled        BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS notification_channels;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE data_sources ADD COLUMN config_version INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE data_sources DROP COLUMN config_version;
@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS monitors (
+    id               TEXT             PRIMARY KEY,
+    name             VARCHAR(255)     NOT NULL,
+    description      TEXT             NOT NULL DEFAULT '',
+    datasource_id    TEXT             NOT NULL,
+    query            TEXT             NOT NULL,
+    interval_seconds INTEGER          NOT NULL,
+    detector         TEXT             NOT NULL DEFAULT '{}',
+    severity         VARCHAR(16)      NOT NULL DEFAULT 'warning',
+    enabled          BOOLEAN          NOT NULL DEFAULT TRUE,
+    created_at       TIMESTAMPTZ      NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ      NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS monitor_runs (
+    id         TEXT             PRIMARY KEY,
+    monitor_id TEXT             NOT NULL,
+    ran_at     TIMESTAMPTZ      NOT NULL,
+    value      DOUBLE PRECISION,
+    error      TEXT             NOT NULL DEFAULT '',
+    expected   DOUBLE PRECISION,
+    lower      DOUBLE PRECISION,
+    upper      DOUBLE PRECISION,
+    score      DOUBLE PRECISION,
+    anomalous  BOOLEAN          NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_monitor_runs_monitor ON monitor_runs(monitor_id, ran_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS monitor_runs;
+DROP TABLE IF EXISTS monitors;
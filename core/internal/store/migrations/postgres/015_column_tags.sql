@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS column_tags (
+    datasource_id TEXT             NOT NULL,
+    schema_name   TEXT             NOT NULL DEFAULT '',
+    table_name    TEXT             NOT NULL,
+    column_name   TEXT             NOT NULL,
+    category      VARCHAR(32)      NOT NULL,
+    confidence    DOUBLE PRECISION NOT NULL DEFAULT 0,
+    source        VARCHAR(16)      NOT NULL,
+    updated_at    TIMESTAMPTZ      NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (datasource_id, schema_name, table_name, column_name)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS column_tags;
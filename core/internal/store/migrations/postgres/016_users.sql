@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS users (
+    id          TEXT        PRIMARY KEY,
+    username    TEXT        NOT NULL UNIQUE,
+    email       TEXT        NOT NULL DEFAULT '',
+    name        TEXT        NOT NULL DEFAULT '',
+    role        VARCHAR(32) NOT NULL,
+    datasources TEXT        NOT NULL DEFAULT '[]',
+    disabled    BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS impersonation_audit (
+    id     TEXT        PRIMARY KEY,
+    actor  TEXT        NOT NULL,
+    target TEXT        NOT NULL,
+    method VARCHAR(16) NOT NULL,
+    path   TEXT        NOT NULL,
+    status INTEGER     NOT NULL,
+    at     TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_impersonation_audit_target ON impersonation_audit(target, at);
+
+-- +goose Down
+DROP TABLE IF EXISTS impersonation_audit;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_credentials (
+    user_id             TEXT        PRIMARY KEY,
+    password_hash       TEXT        NOT NULL DEFAULT '',
+    failed_attempts     INTEGER     NOT NULL DEFAULT 0,
+    locked_until        TIMESTAMPTZ,
+    totp_secret         TEXT        NOT NULL DEFAULT '',
+    totp_enabled        BOOLEAN     NOT NULL DEFAULT FALSE,
+    password_changed_at TIMESTAMPTZ NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_credentials;
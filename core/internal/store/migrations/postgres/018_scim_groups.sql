@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS scim_groups (
+    id           TEXT        PRIMARY KEY,
+    display_name TEXT        NOT NULL UNIQUE,
+    external_id  TEXT        NOT NULL DEFAULT '',
+    members      TEXT        NOT NULL DEFAULT '[]',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS scim_groups;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE data_sources ADD COLUMN maintenance TEXT NOT NULL DEFAULT '';
+ALTER TABLE data_sources ADD COLUMN replicas TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE data_sources DROP COLUMN replicas;
+ALTER TABLE data_sources DROP COLUMN maintenance;
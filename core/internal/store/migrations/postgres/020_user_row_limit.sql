@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN row_limit INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN row_limit;
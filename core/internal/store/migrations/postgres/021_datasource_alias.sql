@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE data_sources ADD COLUMN IF NOT EXISTS alias VARCHAR(255) NOT NULL DEFAULT '';
+
+-- An alias names one datasource per environment.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_data_sources_alias ON data_sources (alias, environment) WHERE alias <> '';
+
+-- +goose Down
+DROP INDEX IF EXISTS uq_data_sources_alias;
+ALTER TABLE data_sources DROP COLUMN IF EXISTS alias;
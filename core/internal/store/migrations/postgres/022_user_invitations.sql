@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_invitations (
+    id          TEXT        PRIMARY KEY,
+    email       TEXT        NOT NULL,
+    name        TEXT        NOT NULL DEFAULT '',
+    role        VARCHAR(32) NOT NULL,
+    datasources TEXT        NOT NULL DEFAULT '[]',
+    token_hash  VARCHAR(64) NOT NULL UNIQUE,
+    invited_by  TEXT        NOT NULL DEFAULT '',
+    sent_count  INTEGER     NOT NULL DEFAULT 0,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    accepted_at TIMESTAMPTZ,
+    user_id     TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_invitations_email ON user_invitations(email);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_invitations;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS export_audit (
+    id            TEXT         PRIMARY KEY,
+    export_id     TEXT         NOT NULL,
+    datasource_id TEXT         NOT NULL,
+    schema_name   VARCHAR(255) NOT NULL DEFAULT '',
+    table_name    VARCHAR(255) NOT NULL,
+    actor         VARCHAR(255) NOT NULL DEFAULT '',
+    delivery      VARCHAR(32)  NOT NULL,
+    row_count     BIGINT       NOT NULL DEFAULT 0,
+    size_bytes    BIGINT       NOT NULL DEFAULT 0,
+    watermark     VARCHAR(64)  NOT NULL DEFAULT '',
+    address       VARCHAR(64)  NOT NULL DEFAULT '',
+    at            TIMESTAMPTZ  NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_export_audit_at ON export_audit (at);
+CREATE INDEX IF NOT EXISTS idx_export_audit_watermark ON export_audit (watermark);
+
+-- +goose Down
+DROP TABLE IF EXISTS export_audit;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS saved_views (
+    id            TEXT         PRIMARY KEY,
+    name          VARCHAR(255) NOT NULL,
+    datasource_id TEXT         NOT NULL,
+    schema_name   VARCHAR(255) NOT NULL DEFAULT '',
+    table_name    VARCHAR(255) NOT NULL,
+    spec          TEXT         NOT NULL DEFAULT '{}',
+    shared        BOOLEAN      NOT NULL DEFAULT FALSE,
+    owner         VARCHAR(255) NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ  NOT NULL,
+    updated_at    TIMESTAMPTZ  NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_saved_views_table ON saved_views (datasource_id, table_name);
+
+-- +goose Down
+DROP TABLE IF EXISTS saved_views;
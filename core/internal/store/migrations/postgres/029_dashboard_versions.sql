@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS dashboard_versions (
+    dashboard_id TEXT         NOT NULL,
+    version      INTEGER      NOT NULL,
+    created_by   VARCHAR(255) NOT NULL DEFAULT '',
+    content      TEXT         NOT NULL DEFAULT '{}',
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (dashboard_id, version)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS dashboard_versions;
@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS datasource_templates (
+    id          TEXT     PRIMARY KEY,
+    name        TEXT     NOT NULL UNIQUE,
+    type        TEXT     NOT NULL,
+    config      TEXT     NOT NULL DEFAULT '{}',
+    description TEXT     NOT NULL DEFAULT '',
+    tags        TEXT     NOT NULL DEFAULT '[]',
+    created_at  DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at  DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+
+ALTER TABLE data_sources ADD COLUMN template_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE data_sources ADD COLUMN overrides   TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_data_sources_template_id ON data_sources (template_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_data_sources_template_id;
+ALTER TABLE data_sources DROP COLUMN overrides;
+ALTER TABLE data_sources DROP COLUMN template_id;
+DROP TABLE IF EXISTS datasource_templates;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS resource_owners (
+    id            TEXT     PRIMARY KEY,
+    resource_type TEXT     NOT NULL,
+    resource_id   TEXT     NOT NULL,
+    owner         TEXT     NOT NULL DEFAULT '',
+    owner_kind    TEXT     NOT NULL DEFAULT 'user',
+    steward       TEXT     NOT NULL DEFAULT '',
+    updated_at    DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_by    TEXT     NOT NULL DEFAULT '',
+    UNIQUE (resource_type, resource_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_resource_owners_owner   ON resource_owners (owner);
+CREATE INDEX IF NOT EXISTS idx_resource_owners_steward ON resource_owners (steward);
+
+-- +goose Down
+DROP TABLE IF EXISTS resource_owners;
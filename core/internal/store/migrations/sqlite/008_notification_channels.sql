@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS notification_channels (
+    id             TEXT     PRIMARY KEY,
+    name           TEXT     NOT NULL UNIQUE,
+    type           TEXT     NOT NULL,
+    config         TEXT     NOT NULL DEFAULT '',
+    events         TEXT     NOT NULL DEFAULT '[]',
+    title_template TEXT     NOT NULL DEFAULT '',
+    body_template  TEXT     NOT NULL DEFAULT '',
+    enabled        INTEGER  NOT NULL DEFAULT 1,
+    created_at     DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at     DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS notification_channels;
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE data_sources ADD COLUMN external_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE data_sources ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- External IDs are optional but must be unique when set.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_data_sources_external_id ON data_sources (external_id) WHERE external_id <> '';
+
+-- +goose Down
+DROP INDEX IF EXISTS uq_data_sources_external_id;
+ALTER TABLE data_sources DROP COLUMN version;
+ALTER TABLE data_sources DROP COLUMN external_id;
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS dashboards (
+    id          TEXT     PRIMARY KEY,
+    name        TEXT     NOT NULL,
+    description TEXT     NOT NULL DEFAULT '',
+    definition  TEXT     NOT NULL DEFAULT '{}',
+    created_at  DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at  DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS dashboards;
@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS monitors (
+    id               TEXT     PRIMARY KEY,
+    name             TEXT     NOT NULL,
+    description      TEXT     NOT NULL DEFAULT '',
+    datasource_id    TEXT     NOT NULL,
+    query            TEXT     NOT NULL,
+    interval_seconds INTEGER  NOT NULL,
+    detector         TEXT     NOT NULL DEFAULT '{}',
+    severity         TEXT     NOT NULL DEFAULT 'warning',
+    enabled          INTEGER  NOT NULL DEFAULT 1,
+    created_at       DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at       DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS monitor_runs (
+    id         TEXT     PRIMARY KEY,
+    monitor_id TEXT     NOT NULL,
+    ran_at     DATETIME NOT NULL,
+    value      REAL,
+    error      TEXT     NOT NULL DEFAULT '',
+    expected   REAL,
+    lower      REAL,
+    upper      REAL,
+    score      REAL,
+    anomalous  INTEGER  NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_monitor_runs_monitor ON monitor_runs(monitor_id, ran_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS monitor_runs;
+DROP TABLE IF EXISTS monitors;
@@ -0,0 +1,35 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS reconciliations (
+    id               TEXT     PRIMARY KEY,
+    name             TEXT     NOT NULL,
+    description      TEXT     NOT NULL DEFAULT '',
+    source           TEXT     NOT NULL,
+    target           TEXT     NOT NULL,
+    comparison       TEXT     NOT NULL,
+    interval_seconds INTEGER  NOT NULL,
+    severity         TEXT     NOT NULL DEFAULT 'warning',
+    enabled          INTEGER  NOT NULL DEFAULT 1,
+    created_at       DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at       DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS reconciliation_runs (
+    id            TEXT     PRIMARY KEY,
+    job_id        TEXT     NOT NULL,
+    ran_at        DATETIME NOT NULL,
+    status        TEXT     NOT NULL,
+    error         TEXT     NOT NULL DEFAULT '',
+    source_groups INTEGER  NOT NULL DEFAULT 0,
+    target_groups INTEGER  NOT NULL DEFAULT 0,
+    matched       INTEGER  NOT NULL DEFAULT 0,
+    missing       INTEGER  NOT NULL DEFAULT 0,
+    extra         INTEGER  NOT NULL DEFAULT 0,
+    mismatched    INTEGER  NOT NULL DEFAULT 0,
+    discrepancies TEXT     NOT NULL DEFAULT 'null'
+);
+
+CREATE INDEX IF NOT EXISTS idx_reconciliation_runs_job ON reconciliation_runs(job_id, ran_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS reconciliation_runs;
+DROP TABLE IF EXISTS reconciliations;
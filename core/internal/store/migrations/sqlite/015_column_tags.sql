@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS column_tags (
+    datasource_id TEXT     NOT NULL,
+    schema_name   TEXT     NOT NULL DEFAULT '',
+    table_name    TEXT     NOT NULL,
+    column_name   TEXT     NOT NULL,
+    category      TEXT     NOT NULL,
+    confidence    REAL     NOT NULL DEFAULT 0,
+    source        TEXT     NOT NULL,
+    updated_at    DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    PRIMARY KEY (datasource_id, schema_name, table_name, column_name)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS column_tags;
@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS users (
+    id          TEXT     PRIMARY KEY,
+    username    TEXT     NOT NULL UNIQUE,
+    email       TEXT     NOT NULL DEFAULT '',
+    name        TEXT     NOT NULL DEFAULT '',
+    role        TEXT     NOT NULL,
+    datasources TEXT     NOT NULL DEFAULT '[]',
+    disabled    INTEGER  NOT NULL DEFAULT 0,
+    created_at  DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at  DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS impersonation_audit (
+    id     TEXT     PRIMARY KEY,
+    actor  TEXT     NOT NULL,
+    target TEXT     NOT NULL,
+    method TEXT     NOT NULL,
+    path   TEXT     NOT NULL,
+    status INTEGER  NOT NULL,
+    at     DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_impersonation_audit_target ON impersonation_audit(target, at);
+
+-- +goose Down
+DROP TABLE IF EXISTS impersonation_audit;
+DROP TABLE IF EXISTS users;
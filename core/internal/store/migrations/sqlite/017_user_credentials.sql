@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_credentials (
+    user_id             TEXT     PRIMARY KEY,
+    password_hash       TEXT     NOT NULL DEFAULT '',
+    failed_attempts     INTEGER  NOT NULL DEFAULT 0,
+    locked_until        DATETIME,
+    totp_secret         TEXT     NOT NULL DEFAULT '',
+    totp_enabled        INTEGER  NOT NULL DEFAULT 0,
+    password_changed_at DATETIME NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_credentials;
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS scim_groups (
+    id           TEXT     PRIMARY KEY,
+    display_name TEXT     NOT NULL UNIQUE,
+    external_id  TEXT     NOT NULL DEFAULT '',
+    members      TEXT     NOT NULL DEFAULT '[]',
+    created_at   DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at   DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS scim_groups;
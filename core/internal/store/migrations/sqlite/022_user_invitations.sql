@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_invitations (
+    id          TEXT     PRIMARY KEY,
+    email       TEXT     NOT NULL,
+    name        TEXT     NOT NULL DEFAULT '',
+    role        TEXT     NOT NULL,
+    datasources TEXT     NOT NULL DEFAULT '[]',
+    token_hash  TEXT     NOT NULL UNIQUE,
+    invited_by  TEXT     NOT NULL DEFAULT '',
+    sent_count  INTEGER  NOT NULL DEFAULT 0,
+    expires_at  DATETIME NOT NULL,
+    accepted_at DATETIME,
+    user_id     TEXT     NOT NULL DEFAULT '',
+    created_at  DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at  DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_invitations_email ON user_invitations(email);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_invitations;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS dashboard_snapshots (
+    id           TEXT     PRIMARY KEY,
+    dashboard_id TEXT     NOT NULL,
+    name         TEXT     NOT NULL,
+    created_by   TEXT     NOT NULL DEFAULT '',
+    content      TEXT     NOT NULL DEFAULT '{}',
+    created_at   DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+CREATE INDEX IF NOT EXISTS idx_dashboard_snapshots_dashboard ON dashboard_snapshots (dashboard_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS dashboard_snapshots;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS export_audit (
+    id            TEXT     PRIMARY KEY,
+    export_id     TEXT     NOT NULL,
+    datasource_id TEXT     NOT NULL,
+    schema_name   TEXT     NOT NULL DEFAULT '',
+    table_name    TEXT     NOT NULL,
+    actor         TEXT     NOT NULL DEFAULT '',
+    delivery      TEXT     NOT NULL,
+    row_count     INTEGER  NOT NULL DEFAULT 0,
+    size_bytes    INTEGER  NOT NULL DEFAULT 0,
+    watermark     TEXT     NOT NULL DEFAULT '',
+    address       TEXT     NOT NULL DEFAULT '',
+    at            DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_export_audit_at ON export_audit (at);
+CREATE INDEX IF NOT EXISTS idx_export_audit_watermark ON export_audit (watermark);
+
+-- +goose Down
+DROP TABLE IF EXISTS export_audit;
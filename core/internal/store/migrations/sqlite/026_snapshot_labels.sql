@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE dashboard_snapshots ADD COLUMN label TEXT NOT NULL DEFAULT '';
+ALTER TABLE dashboard_snapshots ADD COLUMN expires_at DATETIME;
+CREATE INDEX IF NOT EXISTS idx_dashboard_snapshots_expires ON dashboard_snapshots (expires_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_dashboard_snapshots_expires;
+ALTER TABLE dashboard_snapshots DROP COLUMN expires_at;
+ALTER TABLE dashboard_snapshots DROP COLUMN label;
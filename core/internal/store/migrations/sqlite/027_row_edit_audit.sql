@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS row_edit_audit (
+    id            TEXT     PRIMARY KEY,
+    table_ref     TEXT     NOT NULL,
+    datasource_id TEXT     NOT NULL,
+    schema_name   TEXT     NOT NULL DEFAULT '',
+    table_name    TEXT     NOT NULL,
+    op            TEXT     NOT NULL,
+    row_key       TEXT     NOT NULL,
+    before_row    TEXT,
+    after_row     TEXT,
+    actor         TEXT     NOT NULL DEFAULT '',
+    at            DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_row_edit_audit_at ON row_edit_audit (at);
+CREATE INDEX IF NOT EXISTS idx_row_edit_audit_table ON row_edit_audit (table_ref);
+
+-- +goose Down
+DROP TABLE IF EXISTS row_edit_audit;
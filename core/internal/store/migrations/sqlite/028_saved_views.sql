@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS saved_views (
+    id            TEXT     PRIMARY KEY,
+    name          TEXT     NOT NULL,
+    datasource_id TEXT     NOT NULL,
+    schema_name   TEXT     NOT NULL DEFAULT '',
+    table_name    TEXT     NOT NULL,
+    spec          TEXT     NOT NULL DEFAULT '{}',
+    shared        BOOLEAN  NOT NULL DEFAULT 0,
+    owner         TEXT     NOT NULL DEFAULT '',
+    created_at    DATETIME NOT NULL,
+    updated_at    DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_saved_views_table ON saved_views (datasource_id, table_name);
+
+-- +goose Down
+DROP TABLE IF EXISTS saved_views;
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS dashboard_versions (
+    dashboard_id TEXT     NOT NULL,
+    version      INTEGER  NOT NULL,
+    created_by   TEXT     NOT NULL DEFAULT '',
+    content      TEXT     NOT NULL DEFAULT '{}',
+    created_at   DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    PRIMARY KEY (dashboard_id, version)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS dashboard_versions;
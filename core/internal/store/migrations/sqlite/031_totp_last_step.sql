@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE user_credentials ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE user_credentials DROP COLUMN totp_last_step;
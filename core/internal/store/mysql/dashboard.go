@@ -0,0 +1,110 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/dashboard"
+)
+
+type dashboardRepo struct {
+	db *sqlx.DB
+}
+
+// NewDashboardRepo returns a dashboard.Repository backed by MySQL.
+func NewDashboardRepo(db *sqlx.DB) dashboard.Repository {
+	return &dashboardRepo{db: db}
+}
+
+type dashboardRow struct {
+	ID          string    `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	Definition  string    `db:"definition"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func (r dashboardRow) toModel() *dashboard.Dashboard {
+	var def dashboard.Definition
+	_ = json.Unmarshal([]byte(r.Definition), &def)
+	d := &dashboard.Dashboard{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+	d.SetDefinition(def)
+	return d
+}
+
+func marshalDashboardDefinition(d *dashboard.Dashboard) string {
+	b, _ := json.Marshal(d.Definition())
+	return string(b)
+}
+
+func (r *dashboardRepo) List(ctx context.Context) ([]*dashboard.Dashboard, error) {
+	var rows []dashboardRow
+	if err := r.db.SelectContext(ctx, &rows, `SELECT * FROM dashboards ORDER BY name`); err != nil {
+		return nil, fmt.Errorf("list dashboards: %w", err)
+	}
+	result := make([]*dashboard.Dashboard, len(rows))
+	for i := range rows {
+		result[i] = rows[i].toModel()
+	}
+	return result, nil
+}
+
+func (r *dashboardRepo) GetByID(ctx context.Context, id string) (*dashboard.Dashboard, error) {
+	var row dashboardRow
+	err := r.db.GetContext(ctx, &row, `SELECT * FROM dashboards WHERE id = ?`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("dashboard %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get dashboard: %w", err)
+	}
+	return row.toModel(), nil
+}
+
+func (r *dashboardRepo) Create(ctx context.Context, d *dashboard.Dashboard) error {
+	now := time.Now().UTC()
+	d.CreatedAt = now
+	d.UpdatedAt = now
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO dashboards (id, name, description, definition, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		d.ID, d.Name, d.Description, marshalDashboardDefinition(d), now, now,
+	)
+	if err != nil {
+		return fmt.Errorf("create dashboard: %w", err)
+	}
+	return nil
+}
+
+func (r *dashboardRepo) Update(ctx context.Context, d *dashboard.Dashboard) error {
+	d.UpdatedAt = time.Now().UTC()
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE dashboards SET name=?, description=?, definition=?, updated_at=?
+		WHERE id=?`,
+		d.Name, d.Description, marshalDashboardDefinition(d), d.UpdatedAt, d.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update dashboard: %w", err)
+	}
+	return nil
+}
+
+func (r *dashboardRepo) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM dashboards WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete dashboard: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/dashboard"
+)
+
+type snapshotRepo struct {
+	db *sqlx.DB
+}
+
+// NewSnapshotRepo returns a dashboard.SnapshotRepository backed by MySQL.
+func NewSnapshotRepo(db *sqlx.DB) dashboard.SnapshotRepository {
+	return &snapshotRepo{db: db}
+}
+
+type snapshotRow struct {
+	ID          string     `db:"id"`
+	DashboardID string     `db:"dashboard_id"`
+	Name        string     `db:"name"`
+	CreatedBy   string     `db:"created_by"`
+	Content     string     `db:"content"`
+	CreatedAt   time.Time  `db:"created_at"`
+	Label       string     `db:"label"`
+	ExpiresAt   *time.Time `db:"expires_at"`
+}
+
+func (r snapshotRow) toModel() *dashboard.Snapshot {
+	s := &dashboard.Snapshot{
+		ID:          r.ID,
+		DashboardID: r.DashboardID,
+		Name:        r.Name,
+		CreatedBy:   r.CreatedBy,
+		CreatedAt:   r.CreatedAt,
+		Label:       r.Label,
+		ExpiresAt:   r.ExpiresAt,
+	}
+	if r.Content != "" {
+		var content dashboard.SnapshotContent
+		_ = json.Unmarshal([]byte(r.Content), &content)
+		s.Content = &content
+	}
+	return s
+}
+
+func (r *snapshotRepo) List(ctx context.Context, dashboardID string) ([]*dashboard.Snapshot, error) {
+	var rows []snapshotRow
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT id, dashboard_id, name, created_by, '' AS content, created_at, label, expires_at
+		FROM dashboard_snapshots WHERE dashboard_id = ? ORDER BY created_at DESC, id`, dashboardID)
+	if err != nil {
+		return nil, fmt.Errorf("list dashboard snapshots: %w", err)
+	}
+	result := make([]*dashboard.Snapshot, len(rows))
+	for i := range rows {
+		result[i] = rows[i].toModel()
+	}
+	return result, nil
+}
+
+func (r *snapshotRepo) GetByID(ctx context.Context, id string) (*dashboard.Snapshot, error) {
+	var row snapshotRow
+	err := r.db.GetContext(ctx, &row, `SELECT * FROM dashboard_snapshots WHERE id = ?`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("dashboard snapshot %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get dashboard snapshot: %w", err)
+	}
+	return row.toModel(), nil
+}
+
+func (r *snapshotRepo) Create(ctx context.Context, s *dashboard.Snapshot) error {
+	content, err := json.Marshal(s.Content)
+	if err != nil {
+		return fmt.Errorf("encode dashboard snapshot: %w", err)
+	}
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = time.Now().UTC()
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO dashboard_snapshots (id, dashboard_id, name, created_by, content, created_at, label, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.ID, s.DashboardID, s.Name, s.CreatedBy, string(content), s.CreatedAt, s.Label, s.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create dashboard snapshot: %w", err)
+	}
+	return nil
+}
+
+func (r *snapshotRepo) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM dashboard_snapshots WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete dashboard snapshot: %w", err)
+	}
+	return nil
+}
+
+func (r *snapshotRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		DELETE FROM dashboard_snapshots WHERE expires_at IS NOT NULL AND expires_at <= ?`, now.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("delete expired dashboard snapshots: %w", err)
+	}
+	return res.RowsAffected()
+}
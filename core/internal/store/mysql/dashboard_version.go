@@ -0,0 +1,106 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/dashboard"
+)
+
+type dashboardVersionRepo struct {
+	db *sqlx.DB
+}
+
+// NewDashboardVersionRepo returns a dashboard.VersionRepository backed by MySQL.
+func NewDashboardVersionRepo(db *sqlx.DB) dashboard.VersionRepository {
+	return &dashboardVersionRepo{db: db}
+}
+
+type dashboardVersionRow struct {
+	DashboardID string    `db:"dashboard_id"`
+	Version     int       `db:"version"`
+	CreatedBy   string    `db:"created_by"`
+	Content     string    `db:"content"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func (r dashboardVersionRow) toModel() *dashboard.Version {
+	v := &dashboard.Version{
+		DashboardID: r.DashboardID,
+		Version:     r.Version,
+		CreatedBy:   r.CreatedBy,
+		CreatedAt:   r.CreatedAt,
+	}
+	if r.Content != "" {
+		var d dashboard.Dashboard
+		_ = json.Unmarshal([]byte(r.Content), &d)
+		v.Dashboard = &d
+	}
+	return v
+}
+
+func (r *dashboardVersionRepo) List(ctx context.Context, dashboardID string) ([]*dashboard.Version, error) {
+	var rows []dashboardVersionRow
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT dashboard_id, version, created_by, '' AS content, created_at
+		FROM dashboard_versions WHERE dashboard_id = ? ORDER BY version DESC`, dashboardID)
+	if err != nil {
+		return nil, fmt.Errorf("list dashboard versions: %w", err)
+	}
+	result := make([]*dashboard.Version, len(rows))
+	for i := range rows {
+		result[i] = rows[i].toModel()
+	}
+	return result, nil
+}
+
+func (r *dashboardVersionRepo) Get(ctx context.Context, dashboardID string, version int) (*dashboard.Version, error) {
+	var row dashboardVersionRow
+	var err error
+	if version == 0 {
+		err = r.db.GetContext(ctx, &row, `
+			SELECT * FROM dashboard_versions WHERE dashboard_id = ? ORDER BY version DESC LIMIT 1`, dashboardID)
+	} else {
+		err = r.db.GetContext(ctx, &row, `
+			SELECT * FROM dashboard_versions WHERE dashboard_id = ? AND version = ?`, dashboardID, version)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("version %d of dashboard %s not found", version, dashboardID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get dashboard version: %w", err)
+	}
+	return row.toModel(), nil
+}
+
+func (r *dashboardVersionRepo) Create(ctx context.Context, v *dashboard.Version) error {
+	content, err := json.Marshal(v.Dashboard)
+	if err != nil {
+		return fmt.Errorf("encode dashboard version: %w", err)
+	}
+	if v.CreatedAt.IsZero() {
+		v.CreatedAt = time.Now().UTC()
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO dashboard_versions (dashboard_id, version, created_by, content, created_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		v.DashboardID, v.Version, v.CreatedBy, string(content), v.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create dashboard version: %w", err)
+	}
+	return nil
+}
+
+func (r *dashboardVersionRepo) DeleteAll(ctx context.Context, dashboardID string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM dashboard_versions WHERE dashboard_id = ?`, dashboardID); err != nil {
+		return fmt.Errorf("delete dashboard versions: %w", err)
+	}
+	return nil
+}
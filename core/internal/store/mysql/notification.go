@@ -0,0 +1,126 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/notification"
+)
+
+type notificationRepo struct {
+	db *sqlx.DB
+}
+
+// NewNotificationRepo returns a notification.Repository backed by MySQL.
+func NewNotificationRepo(db *sqlx.DB) notification.Repository {
+	return &notificationRepo{db: db}
+}
+
+type notificationRow struct {
+	ID            string    `db:"id"`
+	Name          string    `db:"name"`
+	Type          string    `db:"type"`
+	Config        string    `db:"config"`
+	Events        string    `db:"events"`
+	TitleTemplate string    `db:"title_template"`
+	BodyTemplate  string    `db:"body_template"`
+	Enabled       bool      `db:"enabled"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+func (r notificationRow) toModel() *notification.Channel {
+	var events []string
+	_ = json.Unmarshal([]byte(r.Events), &events)
+	return &notification.Channel{
+		ID:            r.ID,
+		Name:          r.Name,
+		Type:          r.Type,
+		Config:        json.RawMessage(r.Config),
+		Events:        events,
+		TitleTemplate: r.TitleTemplate,
+		BodyTemplate:  r.BodyTemplate,
+		Enabled:       r.Enabled,
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+	}
+}
+
+func marshalNotificationEvents(events []string) string {
+	if len(events) == 0 {
+		return "[]"
+	}
+	b, _ := json.Marshal(events)
+	return string(b)
+}
+
+func (r *notificationRepo) List(ctx context.Context) ([]*notification.Channel, error) {
+	var rows []notificationRow
+	if err := r.db.SelectContext(ctx, &rows, `SELECT * FROM notification_channels ORDER BY name`); err != nil {
+		return nil, fmt.Errorf("list notification_channels: %w", err)
+	}
+	result := make([]*notification.Channel, len(rows))
+	for i := range rows {
+		result[i] = rows[i].toModel()
+	}
+	return result, nil
+}
+
+func (r *notificationRepo) GetByID(ctx context.Context, id string) (*notification.Channel, error) {
+	var row notificationRow
+	err := r.db.GetContext(ctx, &row, `SELECT * FROM notification_channels WHERE id = ?`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("notification channel %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get notification_channel: %w", err)
+	}
+	return row.toModel(), nil
+}
+
+func (r *notificationRepo) Create(ctx context.Context, ch *notification.Channel) error {
+	now := time.Now().UTC()
+	ch.CreatedAt = now
+	ch.UpdatedAt = now
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO notification_channels
+			(id, name, type, config, events, title_template, body_template, enabled, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		ch.ID, ch.Name, ch.Type, string(ch.Config), marshalNotificationEvents(ch.Events),
+		ch.TitleTemplate, ch.BodyTemplate, ch.Enabled,
+		now, now,
+	)
+	if err != nil {
+		return fmt.Errorf("create notification_channel: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationRepo) Update(ctx context.Context, ch *notification.Channel) error {
+	ch.UpdatedAt = time.Now().UTC()
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE notification_channels
+		SET name=?, type=?, config=?, events=?, title_template=?, body_template=?, enabled=?, updated_at=?
+		WHERE id=?`,
+		ch.Name, ch.Type, string(ch.Config), marshalNotificationEvents(ch.Events),
+		ch.TitleTemplate, ch.BodyTemplate, ch.Enabled,
+		ch.UpdatedAt, ch.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update notification_channel: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationRepo) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM notification_channels WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete notification_channel: %w", err)
+	}
+	return nil
+}
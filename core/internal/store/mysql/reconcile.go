@@ -0,0 +1,191 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/reconcile"
+)
+
+type reconcileRepo struct {
+	db *sqlx.DB
+}
+
+// NewReconcileRepo returns a reconcile.Repository backed by MySQL.
+func NewReconcileRepo(db *sqlx.DB) reconcile.Repository {
+	return &reconcileRepo{db: db}
+}
+
+type reconcileJobRow struct {
+	ID              string    `db:"id"`
+	Name            string    `db:"name"`
+	Description     string    `db:"description"`
+	Source          string    `db:"source"`
+	Target          string    `db:"target"`
+	Comparison      string    `db:"comparison"`
+	IntervalSeconds int       `db:"interval_seconds"`
+	Severity        string    `db:"severity"`
+	Enabled         bool      `db:"enabled"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+func (r reconcileJobRow) toModel() *reconcile.Job {
+	j := &reconcile.Job{
+		ID:              r.ID,
+		Name:            r.Name,
+		Description:     r.Description,
+		IntervalSeconds: r.IntervalSeconds,
+		Severity:        r.Severity,
+		Enabled:         r.Enabled,
+		CreatedAt:       r.CreatedAt,
+		UpdatedAt:       r.UpdatedAt,
+	}
+	_ = json.Unmarshal([]byte(r.Source), &j.Source)
+	_ = json.Unmarshal([]byte(r.Target), &j.Target)
+	_ = json.Unmarshal([]byte(r.Comparison), &j.Compare)
+	return j
+}
+
+func marshalJSON(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+type reconcileRunRow struct {
+	ID            string    `db:"id"`
+	JobID         string    `db:"job_id"`
+	RanAt         time.Time `db:"ran_at"`
+	Status        string    `db:"status"`
+	Error         string    `db:"error"`
+	SourceGroups  int       `db:"source_groups"`
+	TargetGroups  int       `db:"target_groups"`
+	Matched       int       `db:"matched"`
+	Missing       int       `db:"missing"`
+	Extra         int       `db:"extra"`
+	Mismatched    int       `db:"mismatched"`
+	Discrepancies string    `db:"discrepancies"`
+}
+
+func (r reconcileRunRow) toModel() *reconcile.Run {
+	run := &reconcile.Run{
+		ID:           r.ID,
+		JobID:        r.JobID,
+		RanAt:        r.RanAt,
+		Status:       r.Status,
+		Error:        r.Error,
+		SourceGroups: r.SourceGroups,
+		TargetGroups: r.TargetGroups,
+		Matched:      r.Matched,
+		Missing:      r.Missing,
+		Extra:        r.Extra,
+		Mismatched:   r.Mismatched,
+	}
+	_ = json.Unmarshal([]byte(r.Discrepancies), &run.Discrepancies)
+	return run
+}
+
+func (r *reconcileRepo) List(ctx context.Context) ([]*reconcile.Job, error) {
+	var rows []reconcileJobRow
+	if err := r.db.SelectContext(ctx, &rows, `SELECT * FROM reconciliations ORDER BY name`); err != nil {
+		return nil, fmt.Errorf("list reconciliation jobs: %w", err)
+	}
+	result := make([]*reconcile.Job, len(rows))
+	for i := range rows {
+		result[i] = rows[i].toModel()
+	}
+	return result, nil
+}
+
+func (r *reconcileRepo) GetByID(ctx context.Context, id string) (*reconcile.Job, error) {
+	var row reconcileJobRow
+	err := r.db.GetContext(ctx, &row, `SELECT * FROM reconciliations WHERE id = ?`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("reconciliation job %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get reconciliation job: %w", err)
+	}
+	return row.toModel(), nil
+}
+
+func (r *reconcileRepo) Create(ctx context.Context, j *reconcile.Job) error {
+	now := time.Now().UTC()
+	j.CreatedAt = now
+	j.UpdatedAt = now
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO reconciliations
+			(id, name, description, source, target, comparison, interval_seconds, severity, enabled, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		j.ID, j.Name, j.Description, marshalJSON(j.Source), marshalJSON(j.Target), marshalJSON(j.Compare),
+		j.IntervalSeconds, j.Severity, j.Enabled, now, now,
+	)
+	if err != nil {
+		return fmt.Errorf("create reconciliation job: %w", err)
+	}
+	return nil
+}
+
+func (r *reconcileRepo) Update(ctx context.Context, j *reconcile.Job) error {
+	j.UpdatedAt = time.Now().UTC()
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE reconciliations SET name=?, description=?, source=?, target=?, comparison=?,
+			interval_seconds=?, severity=?, enabled=?, updated_at=?
+		WHERE id=?`,
+		j.Name, j.Description, marshalJSON(j.Source), marshalJSON(j.Target), marshalJSON(j.Compare),
+		j.IntervalSeconds, j.Severity, j.Enabled, j.UpdatedAt, j.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update reconciliation job: %w", err)
+	}
+	return nil
+}
+
+func (r *reconcileRepo) Delete(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("delete reconciliation job: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM reconciliation_runs WHERE job_id = ?`, id); err != nil {
+		return fmt.Errorf("delete reconciliation runs: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM reconciliations WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete reconciliation job: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *reconcileRepo) AddRun(ctx context.Context, run *reconcile.Run) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO reconciliation_runs
+			(id, job_id, ran_at, status, error, source_groups, target_groups, matched, missing, extra, mismatched, discrepancies)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		run.ID, run.JobID, run.RanAt, run.Status, run.Error, run.SourceGroups, run.TargetGroups,
+		run.Matched, run.Missing, run.Extra, run.Mismatched, marshalJSON(run.Discrepancies),
+	)
+	if err != nil {
+		return fmt.Errorf("add reconciliation run: %w", err)
+	}
+	return nil
+}
+
+func (r *reconcileRepo) ListRuns(ctx context.Context, jobID string, limit int) ([]*reconcile.Run, error) {
+	var rows []reconcileRunRow
+	err := r.db.SelectContext(ctx, &rows,
+		`SELECT * FROM reconciliation_runs WHERE job_id = ? ORDER BY ran_at DESC LIMIT ?`, jobID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list reconciliation runs: %w", err)
+	}
+	result := make([]*reconcile.Run, len(rows))
+	for i := range rows {
+		result[i] = rows[i].toModel()
+	}
+	return result, nil
+}
@@ -0,0 +1,120 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/savedview"
+)
+
+type savedViewRepo struct {
+	db *sqlx.DB
+}
+
+// NewSavedViewRepo returns a savedview.Repository backed by MySQL.
+func NewSavedViewRepo(db *sqlx.DB) savedview.Repository {
+	return &savedViewRepo{db: db}
+}
+
+type savedViewRow struct {
+	ID           string    `db:"id"`
+	Name         string    `db:"name"`
+	DatasourceID string    `db:"datasource_id"`
+	Schema       string    `db:"schema_name"`
+	Table        string    `db:"table_name"`
+	Spec         string    `db:"spec"`
+	Shared       bool      `db:"shared"`
+	Owner        string    `db:"owner"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+func (r savedViewRow) toModel() *savedview.View {
+	v := &savedview.View{
+		ID:           r.ID,
+		Name:         r.Name,
+		DatasourceID: r.DatasourceID,
+		Schema:       r.Schema,
+		Table:        r.Table,
+		Shared:       r.Shared,
+		Owner:        r.Owner,
+		CreatedAt:    r.CreatedAt.UTC(),
+		UpdatedAt:    r.UpdatedAt.UTC(),
+	}
+	_ = json.Unmarshal([]byte(r.Spec), &v.Spec)
+	return v
+}
+
+func (r *savedViewRepo) List(ctx context.Context, f savedview.ListFilter) ([]*savedview.View, error) {
+	var rows []savedViewRow
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT * FROM saved_views
+		WHERE (? = '' OR datasource_id = ?) AND (? = '' OR schema_name = ?) AND (? = '' OR table_name = ?)
+			AND (? = '' OR owner = ? OR shared)
+		ORDER BY name, id`,
+		f.DatasourceID, f.DatasourceID, f.Schema, f.Schema, f.Table, f.Table, f.Owner, f.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("list saved views: %w", err)
+	}
+	result := make([]*savedview.View, len(rows))
+	for i := range rows {
+		result[i] = rows[i].toModel()
+	}
+	return result, nil
+}
+
+func (r *savedViewRepo) GetByID(ctx context.Context, id string) (*savedview.View, error) {
+	var row savedViewRow
+	err := r.db.GetContext(ctx, &row, `SELECT * FROM saved_views WHERE id = ?`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("saved view %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get saved view: %w", err)
+	}
+	return row.toModel(), nil
+}
+
+func (r *savedViewRepo) Create(ctx context.Context, v *savedview.View) error {
+	spec, err := json.Marshal(v.Spec)
+	if err != nil {
+		return fmt.Errorf("encode saved view: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO saved_views (id, name, datasource_id, schema_name, table_name, spec, shared, owner, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		v.ID, v.Name, v.DatasourceID, v.Schema, v.Table, string(spec), v.Shared, v.Owner,
+		v.CreatedAt.UTC(), v.UpdatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("create saved view: %w", err)
+	}
+	return nil
+}
+
+func (r *savedViewRepo) Update(ctx context.Context, v *savedview.View) error {
+	spec, err := json.Marshal(v.Spec)
+	if err != nil {
+		return fmt.Errorf("encode saved view: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE saved_views SET name=?, datasource_id=?, schema_name=?, table_name=?, spec=?, shared=?, updated_at=?
+		WHERE id=?`,
+		v.Name, v.DatasourceID, v.Schema, v.Table, string(spec), v.Shared, v.UpdatedAt.UTC(), v.ID)
+	if err != nil {
+		return fmt.Errorf("update saved view: %w", err)
+	}
+	return nil
+}
+
+func (r *savedViewRepo) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM saved_views WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete saved view: %w", err)
+	}
+	return nil
+}
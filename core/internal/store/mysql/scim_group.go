@@ -0,0 +1,99 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/scim"
+)
+
+type scimGroupRepo struct {
+	db *sqlx.DB
+}
+
+// NewSCIMGroupRepo returns a scim.GroupRepository backed by MySQL.
+func NewSCIMGroupRepo(db *sqlx.DB) scim.GroupRepository {
+	return &scimGroupRepo{db: db}
+}
+
+type scimGroupRow struct {
+	ID          string    `db:"id"`
+	DisplayName string    `db:"display_name"`
+	ExternalID  string    `db:"external_id"`
+	Members     string    `db:"members"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func (r scimGroupRow) toModel() *scim.Group {
+	members := unmarshalTags(r.Members)
+	if members == nil {
+		members = []string{}
+	}
+	return &scim.Group{
+		ID:          r.ID,
+		DisplayName: r.DisplayName,
+		ExternalID:  r.ExternalID,
+		Members:     members,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+func (r *scimGroupRepo) List(ctx context.Context) ([]*scim.Group, error) {
+	var rows []scimGroupRow
+	if err := r.db.SelectContext(ctx, &rows, `SELECT * FROM scim_groups ORDER BY display_name`); err != nil {
+		return nil, fmt.Errorf("list scim groups: %w", err)
+	}
+	result := make([]*scim.Group, len(rows))
+	for i, row := range rows {
+		result[i] = row.toModel()
+	}
+	return result, nil
+}
+
+func (r *scimGroupRepo) Get(ctx context.Context, id string) (*scim.Group, error) {
+	var row scimGroupRow
+	err := r.db.GetContext(ctx, &row, `SELECT * FROM scim_groups WHERE id = ?`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("scim group %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get scim group: %w", err)
+	}
+	return row.toModel(), nil
+}
+
+func (r *scimGroupRepo) Create(ctx context.Context, g *scim.Group) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO scim_groups (id, display_name, external_id, members, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		g.ID, g.DisplayName, g.ExternalID, marshalTags(g.Members), g.CreatedAt, g.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("create scim group: %w", err)
+	}
+	return nil
+}
+
+func (r *scimGroupRepo) Update(ctx context.Context, g *scim.Group) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE scim_groups SET display_name = ?, external_id = ?, members = ?, updated_at = ?
+		WHERE id = ?`,
+		g.DisplayName, g.ExternalID, marshalTags(g.Members), g.UpdatedAt, g.ID)
+	if err != nil {
+		return fmt.Errorf("update scim group: %w", err)
+	}
+	return nil
+}
+
+func (r *scimGroupRepo) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM scim_groups WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete scim group: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/pii"
+)
+
+type columnTagRepo struct {
+	db *sqlx.DB
+}
+
+// NewColumnTagRepo returns a pii.Repository backed by PostgreSQL.
+func NewColumnTagRepo(db *sqlx.DB) pii.Repository {
+	return &columnTagRepo{db: db}
+}
+
+type columnTagRow struct {
+	DatasourceID string    `db:"datasource_id"`
+	Schema       string    `db:"schema_name"`
+	Table        string    `db:"table_name"`
+	Column       string    `db:"column_name"`
+	Category     string    `db:"category"`
+	Confidence   float64   `db:"confidence"`
+	Source       string    `db:"source"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+func (r *columnTagRepo) List(ctx context.Context, datasourceID string) ([]*pii.ColumnTag, error) {
+	var rows []columnTagRow
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT * FROM column_tags WHERE datasource_id = $1
+		ORDER BY schema_name, table_name, column_name`, datasourceID)
+	if err != nil {
+		return nil, fmt.Errorf("list column tags: %w", err)
+	}
+	result := make([]*pii.ColumnTag, len(rows))
+	for i, row := range rows {
+		result[i] = &pii.ColumnTag{
+			DatasourceID: row.DatasourceID,
+			Schema:       row.Schema,
+			Table:        row.Table,
+			Column:       row.Column,
+			Category:     row.Category,
+			Confidence:   row.Confidence,
+			Source:       row.Source,
+			UpdatedAt:    row.UpdatedAt,
+		}
+	}
+	return result, nil
+}
+
+func (r *columnTagRepo) Upsert(ctx context.Context, t *pii.ColumnTag) error {
+	const q = `
+		INSERT INTO column_tags
+			(datasource_id, schema_name, table_name, column_name, category, confidence, source, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (datasource_id, schema_name, table_name, column_name) DO UPDATE SET
+			category = excluded.category, confidence = excluded.confidence,
+			source = excluded.source, updated_at = excluded.updated_at`
+	_, err := r.db.ExecContext(ctx, q,
+		t.DatasourceID, t.Schema, t.Table, t.Column, t.Category, t.Confidence, t.Source, t.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("upsert column tag: %w", err)
+	}
+	return nil
+}
+
+func (r *columnTagRepo) Delete(ctx context.Context, datasourceID, schema, table, column string) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM column_tags
+		WHERE datasource_id = $1 AND schema_name = $2 AND table_name = $3 AND column_name = $4`,
+		datasourceID, schema, table, column)
+	if err != nil {
+		return fmt.Errorf("delete column tag: %w", err)
+	}
+	return nil
+}
+
+func (r *columnTagRepo) DeleteScanned(ctx context.Context, datasourceID, schema, table string) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM column_tags
+		WHERE datasource_id = $1 AND schema_name = $2 AND table_name = $3 AND source = $4`,
+		datasourceID, schema, table, pii.SourceScanner)
+	if err != nil {
+		return fmt.Errorf("delete scanned column tags: %w", err)
+	}
+	return nil
+}
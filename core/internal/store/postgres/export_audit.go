@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/export"
+)
+
+type exportAuditRepo struct {
+	db *sqlx.DB
+}
+
+// NewExportAuditRepo returns an export.AuditRepository backed by PostgreSQL.
+func NewExportAuditRepo(db *sqlx.DB) export.AuditRepository {
+	return &exportAuditRepo{db: db}
+}
+
+type exportAuditRow struct {
+	ID           string    `db:"id"`
+	ExportID     string    `db:"export_id"`
+	DatasourceID string    `db:"datasource_id"`
+	Schema       string    `db:"schema_name"`
+	Table        string    `db:"table_name"`
+	Actor        string    `db:"actor"`
+	Delivery     string    `db:"delivery"`
+	Rows         int64     `db:"row_count"`
+	SizeBytes    int64     `db:"size_bytes"`
+	Watermark    string    `db:"watermark"`
+	Address      string    `db:"address"`
+	At           time.Time `db:"at"`
+}
+
+func (r *exportAuditRepo) Record(ctx context.Context, e *export.AuditRecord) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO export_audit (id, export_id, datasource_id, schema_name, table_name, actor,
+			delivery, row_count, size_bytes, watermark, address, at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		e.ID, e.ExportID, e.DatasourceID, e.Schema, e.Table, e.Actor,
+		string(e.Delivery), e.Rows, e.SizeBytes, e.Watermark, e.Address, e.At)
+	if err != nil {
+		return fmt.Errorf("record export audit: %w", err)
+	}
+	return nil
+}
+
+func (r *exportAuditRepo) List(ctx context.Context, f export.AuditFilter) ([]*export.AuditRecord, error) {
+	var rows []exportAuditRow
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT * FROM export_audit
+		WHERE ($1 = '' OR actor = $1) AND ($2 = '' OR datasource_id = $2) AND ($3 = '' OR watermark = $3)
+			AND ($4::timestamptz IS NULL OR at >= $4) AND ($5::timestamptz IS NULL OR at < $5)
+		ORDER BY at DESC LIMIT $6`,
+		f.Actor, f.DatasourceID, f.Watermark, auditTime(f.From), auditTime(f.To), f.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("list export audit: %w", err)
+	}
+	result := make([]*export.AuditRecord, len(rows))
+	for i, row := range rows {
+		result[i] = &export.AuditRecord{
+			ID:           row.ID,
+			ExportID:     row.ExportID,
+			DatasourceID: row.DatasourceID,
+			Schema:       row.Schema,
+			Table:        row.Table,
+			Actor:        row.Actor,
+			Delivery:     export.Delivery(row.Delivery),
+			Rows:         row.Rows,
+			SizeBytes:    row.SizeBytes,
+			Watermark:    row.Watermark,
+			Address:      row.Address,
+			At:           row.At,
+		}
+	}
+	return result, nil
+}
+
+// auditTime returns a filter bound, or nil for an open one.
+func auditTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/ownership"
+)
+
+type ownershipRepo struct {
+	db *sqlx.DB
+}
+
+// NewOwnershipRepo returns an ownership.Repository backed by PostgreSQL.
+func NewOwnershipRepo(db *sqlx.DB) ownership.Repository {
+	return &ownershipRepo{db: db}
+}
+
+type ownershipRow struct {
+	ID           string    `db:"id"`
+	ResourceType string    `db:"resource_type"`
+	ResourceID   string    `db:"resource_id"`
+	Owner        string    `db:"owner"`
+	OwnerKind    string    `db:"owner_kind"`
+	Steward      string    `db:"steward"`
+	UpdatedAt    time.Time `db:"updated_at"`
+	UpdatedBy    string    `db:"updated_by"`
+}
+
+func (r ownershipRow) toModel() *ownership.Ownership {
+	return &ownership.Ownership{
+		ID:           r.ID,
+		ResourceType: r.ResourceType,
+		ResourceID:   r.ResourceID,
+		Owner:        r.Owner,
+		OwnerKind:    r.OwnerKind,
+		Steward:      r.Steward,
+		UpdatedAt:    r.UpdatedAt,
+		UpdatedBy:    r.UpdatedBy,
+	}
+}
+
+func (r *ownershipRepo) Upsert(ctx context.Context, o *ownership.Ownership) error {
+	const q = `
+		INSERT INTO resource_owners (id, resource_type, resource_id, owner, owner_kind, steward, updated_at, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (resource_type, resource_id) DO UPDATE SET
+			owner = excluded.owner, owner_kind = excluded.owner_kind, steward = excluded.steward,
+			updated_at = excluded.updated_at, updated_by = excluded.updated_by`
+	_, err := r.db.ExecContext(ctx, q,
+		o.ID, o.ResourceType, o.ResourceID, o.Owner, o.OwnerKind, o.Steward,
+		o.UpdatedAt, o.UpdatedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert ownership: %w", err)
+	}
+	return nil
+}
+
+func (r *ownershipRepo) Get(ctx context.Context, resourceType, resourceID string) (*ownership.Ownership, error) {
+	var row ownershipRow
+	err := r.db.GetContext(ctx, &row,
+		`SELECT * FROM resource_owners WHERE resource_type = $1 AND resource_id = $2`, resourceType, resourceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("ownership for %s %s not found", resourceType, resourceID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get ownership: %w", err)
+	}
+	return row.toModel(), nil
+}
+
+func (r *ownershipRepo) List(ctx context.Context, filter ownership.Filter) ([]*ownership.Ownership, error) {
+	q := `SELECT * FROM resource_owners WHERE 1=1`
+	args := []any{}
+	n := 1
+	if filter.ResourceType != "" {
+		q += fmt.Sprintf(` AND resource_type = $%d`, n)
+		args = append(args, filter.ResourceType)
+		n++
+	}
+	if filter.Owner != "" {
+		q += fmt.Sprintf(` AND owner = $%d`, n)
+		args = append(args, filter.Owner)
+		n++
+	}
+	if filter.Steward != "" {
+		q += fmt.Sprintf(` AND steward = $%d`, n)
+		args = append(args, filter.Steward)
+	}
+	q += ` ORDER BY resource_type, resource_id`
+
+	var rows []ownershipRow
+	if err := r.db.SelectContext(ctx, &rows, q, args...); err != nil {
+		return nil, fmt.Errorf("list ownership: %w", err)
+	}
+	result := make([]*ownership.Ownership, len(rows))
+	for i := range rows {
+		result[i] = rows[i].toModel()
+	}
+	return result, nil
+}
+
+func (r *ownershipRepo) Delete(ctx context.Context, resourceType, resourceID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM resource_owners WHERE resource_type = $1 AND resource_id = $2`, resourceType, resourceID)
+	if err != nil {
+		return fmt.Errorf("delete ownership: %w", err)
+	}
+	return nil
+}
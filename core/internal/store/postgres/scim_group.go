@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/scim"
+)
+
+type scimGroupRepo struct {
+	db *sqlx.DB
+}
+
+// NewSCIMGroupRepo returns a scim.GroupRepository backed by PostgreSQL.
+func NewSCIMGroupRepo(db *sqlx.DB) scim.GroupRepository {
+	return &scimGroupRepo{db: db}
+}
+
+type scimGroupRow struct {
+	ID          string    `db:"id"`
+	DisplayName string    `db:"display_name"`
+	ExternalID  string    `db:"external_id"`
+	Members     string    `db:"members"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func (r scimGroupRow) toModel() *scim.Group {
+	members := unmarshalTags(r.Members)
+	if members == nil {
+		members = []string{}
+	}
+	return &scim.Group{
+		ID:          r.ID,
+		DisplayName: r.DisplayName,
+		ExternalID:  r.ExternalID,
+		Members:     members,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+func (r *scimGroupRepo) List(ctx context.Context) ([]*scim.Group, error) {
+	var rows []scimGroupRow
+	if err := r.db.SelectContext(ctx, &rows, `SELECT * FROM scim_groups ORDER BY display_name`); err != nil {
+		return nil, fmt.Errorf("list scim groups: %w", err)
+	}
+	result := make([]*scim.Group, len(rows))
+	for i, row := range rows {
+		result[i] = row.toModel()
+	}
+	return result, nil
+}
+
+func (r *scimGroupRepo) Get(ctx context.Context, id string) (*scim.Group, error) {
+	var row scimGroupRow
+	err := r.db.GetContext(ctx, &row, `SELECT * FROM scim_groups WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("scim group %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get scim group: %w", err)
+	}
+	return row.toModel(), nil
+}
+
+func (r *scimGroupRepo) Create(ctx context.Context, g *scim.Group) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO scim_groups (id, display_name, external_id, members, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		g.ID, g.DisplayName, g.ExternalID, marshalTags(g.Members), g.CreatedAt, g.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("create scim group: %w", err)
+	}
+	return nil
+}
+
+func (r *scimGroupRepo) Update(ctx context.Context, g *scim.Group) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE scim_groups SET display_name = $1, external_id = $2, members = $3, updated_at = $4
+		WHERE id = $5`,
+		g.DisplayName, g.ExternalID, marshalTags(g.Members), g.UpdatedAt, g.ID)
+	if err != nil {
+		return fmt.Errorf("update scim group: %w", err)
+	}
+	return nil
+}
+
+func (r *scimGroupRepo) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM scim_groups WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("delete scim group: %w", err)
+	}
+	return nil
+}
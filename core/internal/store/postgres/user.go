@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/user"
+)
+
+type userRepo struct {
+	db *sqlx.DB
+}
+
+// NewUserRepo returns a user.Repository backed by PostgreSQL.
+func NewUserRepo(db *sqlx.DB) user.Repository {
+	return &userRepo{db: db}
+}
+
+type userRow struct {
+	ID          string    `db:"id"`
+	Username    string    `db:"username"`
+	Email       string    `db:"email"`
+	Name        string    `db:"name"`
+	Role        string    `db:"role"`
+	Datasources string    `db:"datasources"`
+	RowLimit    int       `db:"row_limit"`
+	Disabled    bool      `db:"disabled"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func (r userRow) toModel() *user.User {
+	ds := unmarshalTags(r.Datasources)
+	if ds == nil {
+		ds = []string{}
+	}
+	return &user.User{
+		ID:          r.ID,
+		Username:    r.Username,
+		Email:       r.Email,
+		Name:        r.Name,
+		Role:        r.Role,
+		Datasources: ds,
+		RowLimit:    r.RowLimit,
+		Disabled:    r.Disabled,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+func (r *userRepo) List(ctx context.Context) ([]*user.User, error) {
+	var rows []userRow
+	if err := r.db.SelectContext(ctx, &rows, `SELECT * FROM users ORDER BY username`); err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+	result := make([]*user.User, len(rows))
+	for i, row := range rows {
+		result[i] = row.toModel()
+	}
+	return result, nil
+}
+
+func (r *userRepo) GetByID(ctx context.Context, id string) (*user.User, error) {
+	return r.get(ctx, `SELECT * FROM users WHERE id = $1`, id)
+}
+
+func (r *userRepo) GetByUsername(ctx context.Context, username string) (*user.User, error) {
+	return r.get(ctx, `SELECT * FROM users WHERE username = $1`, username)
+}
+
+func (r *userRepo) get(ctx context.Context, q, arg string) (*user.User, error) {
+	var row userRow
+	err := r.db.GetContext(ctx, &row, q, arg)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("user %s not found", arg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	return row.toModel(), nil
+}
+
+func (r *userRepo) Create(ctx context.Context, u *user.User) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO users (id, username, email, name, role, datasources, row_limit, disabled, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		u.ID, u.Username, u.Email, u.Name, u.Role, marshalTags(u.Datasources), u.RowLimit, u.Disabled, u.CreatedAt, u.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("create user: %w", err)
+	}
+	return nil
+}
+
+func (r *userRepo) Update(ctx context.Context, u *user.User) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE users SET email = $1, name = $2, role = $3, datasources = $4, row_limit = $5, disabled = $6, updated_at = $7
+		WHERE id = $8`,
+		u.Email, u.Name, u.Role, marshalTags(u.Datasources), u.RowLimit, u.Disabled, u.UpdatedAt, u.ID)
+	if err != nil {
+		return fmt.Errorf("update user: %w", err)
+	}
+	return nil
+}
+
+func (r *userRepo) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("delete user: %w", err)
+	}
+	return nil
+}
+
+type impersonationRepo struct {
+	db *sqlx.DB
+}
+
+// NewImpersonationRepo returns a user.AuditRepository backed by PostgreSQL.
+func NewImpersonationRepo(db *sqlx.DB) user.AuditRepository {
+	return &impersonationRepo{db: db}
+}
+
+type impersonationRow struct {
+	ID     string    `db:"id"`
+	Actor  string    `db:"actor"`
+	Target string    `db:"target"`
+	Method string    `db:"method"`
+	Path   string    `db:"path"`
+	Status int       `db:"status"`
+	At     time.Time `db:"at"`
+}
+
+func (r *impersonationRepo) Record(ctx context.Context, e *user.Impersonation) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO impersonation_audit (id, actor, target, method, path, status, at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		e.ID, e.Actor, e.Target, e.Method, e.Path, e.Status, e.At)
+	if err != nil {
+		return fmt.Errorf("record impersonation: %w", err)
+	}
+	return nil
+}
+
+func (r *impersonationRepo) List(ctx context.Context, target string, limit int) ([]*user.Impersonation, error) {
+	var rows []impersonationRow
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT * FROM impersonation_audit WHERE ($1 = '' OR target = $1)
+		ORDER BY at DESC LIMIT $2`, target, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list impersonations: %w", err)
+	}
+	result := make([]*user.Impersonation, len(rows))
+	for i, row := range rows {
+		result[i] = &user.Impersonation{
+			ID:     row.ID,
+			Actor:  row.Actor,
+			Target: row.Target,
+			Method: row.Method,
+			Path:   row.Path,
+			Status: row.Status,
+			At:     row.At,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,54 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"data-voyager/core/internal/pii"
+	stsqlite "data-voyager/core/internal/store/sqlite"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnTagRepo_SQLite(t *testing.T) {
+	db, err := sqlx.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	goose.SetBaseFS(nil)
+	require.NoError(t, goose.SetDialect("sqlite3"))
+	require.NoError(t, goose.Up(db.DB, "../migrations/sqlite"))
+
+	repo := stsqlite.NewColumnTagRepo(db)
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tag := func(table, column, category, source string) *pii.ColumnTag {
+		return &pii.ColumnTag{DatasourceID: "ds1", Schema: "public", Table: table, Column: column,
+			Category: category, Confidence: 0.9, Source: source, UpdatedAt: now}
+	}
+	require.NoError(t, repo.Upsert(ctx, tag("users", "email", pii.CategoryEmail, pii.SourceScanner)))
+	require.NoError(t, repo.Upsert(ctx, tag("users", "phone", pii.CategoryNone, pii.SourceManual)))
+	require.NoError(t, repo.Upsert(ctx, tag("orders", "card", pii.CategoryCreditCard, pii.SourceScanner)))
+	// Upserting the same column replaces the tag.
+	require.NoError(t, repo.Upsert(ctx, tag("users", "email", pii.CategoryPhone, pii.SourceManual)))
+
+	tags, err := repo.List(ctx, "ds1")
+	require.NoError(t, err)
+	require.Len(t, tags, 3)
+	assert.Equal(t, "orders", tags[0].Table)
+	assert.Equal(t, pii.CategoryPhone, tags[1].Category)
+	assert.Equal(t, pii.SourceManual, tags[1].Source)
+	assert.Equal(t, now, tags[1].UpdatedAt)
+
+	require.NoError(t, repo.DeleteScanned(ctx, "ds1", "public", "orders"))
+	require.NoError(t, repo.Delete(ctx, "ds1", "public", "users", "phone"))
+	tags, err = repo.List(ctx, "ds1")
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, "email", tags[0].Column)
+}
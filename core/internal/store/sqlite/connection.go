@@ -31,6 +31,7 @@ func (r *connectionRepo) Create(ctx context.Context, c *connection.Connection) e
 	now := time.Now().UTC()
 	c.CreatedAt = now
 	c.UpdatedAt = now
+	c.Version = 1
 
 	isActive := 0
 	if c.IsActive {
@@ -45,8 +46,10 @@ func (r *connectionRepo) Create(ctx context.Context, c *connection.Connection) e
 
 	const q = `
 		INSERT INTO data_sources
-			(id, name, type, config, description, tags, is_active, created_at, updated_at, created_by)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			(id, name, type, config, description, tags, is_active, created_at, updated_at, created_by,
+			 template_id, overrides, environment, deprecation, external_id, query_timeout, config_version,
+			 maintenance, replicas, alias, network_policy, cost_model)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err = r.db.ExecContext(ctx, q,
 		c.ID, c.Name, string(c.Type), string(c.Config),
@@ -55,6 +58,11 @@ func (r *connectionRepo) Create(ctx context.Context, c *connection.Connection) e
 		c.CreatedAt.Format(time.RFC3339),
 		c.UpdatedAt.Format(time.RFC3339),
 		c.CreatedBy,
+		c.TemplateID, string(c.Overrides),
+		c.Environment, marshalDeprecation(c.Deprecation),
+		c.ExternalID, c.QueryTimeout, c.ConfigVersion,
+		marshalMaintenance(c.Maintenance), marshalReplicas(c.Replicas), c.Alias,
+		marshalNetworkPolicy(c.NetworkPolicy), marshalCostModel(c.CostModel),
 	)
 	if err != nil {
 		return fmt.Errorf("create connection: %w", err)
@@ -66,7 +74,7 @@ func (r *connectionRepo) GetByID(ctx context.Context, id string) (*connection.Co
 	var row row
 	err := r.db.GetContext(ctx, &row, `SELECT * FROM data_sources WHERE id = ?`, id)
 	if errors.Is(err, sql.ErrNoRows) {
-		return nil, fmt.Errorf("connection %s not found", id)
+		return nil, fmt.Errorf("connection %s %w", id, connection.ErrNotFound)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("get connection: %w", err)
@@ -86,6 +94,18 @@ func (r *connectionRepo) GetByName(ctx context.Context, name string) (*connectio
 	return row.toModel(), nil
 }
 
+func (r *connectionRepo) GetByExternalID(ctx context.Context, externalID string) (*connection.Connection, error) {
+	var row row
+	err := r.db.GetContext(ctx, &row, `SELECT * FROM data_sources WHERE external_id = ?`, externalID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("connection with external id %q not found", externalID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get connection by external id: %w", err)
+	}
+	return row.toModel(), nil
+}
+
 func (r *connectionRepo) List(ctx context.Context, filter connection.Filter) ([]*connection.Connection, error) {
 	q := `SELECT * FROM data_sources WHERE 1=1`
 	args := []any{}
@@ -106,6 +126,18 @@ func (r *connectionRepo) List(ctx context.Context, filter connection.Filter) ([]
 		q += ` AND created_by = ?`
 		args = append(args, filter.CreatedBy)
 	}
+	if filter.TemplateID != "" {
+		q += ` AND template_id = ?`
+		args = append(args, filter.TemplateID)
+	}
+	if filter.Environment != "" {
+		q += ` AND environment = ?`
+		args = append(args, filter.Environment)
+	}
+	if filter.Alias != "" {
+		q += ` AND alias = ?`
+		args = append(args, filter.Alias)
+	}
 	q += ` ORDER BY created_at DESC`
 
 	var rows []row
@@ -135,19 +167,45 @@ func (r *connectionRepo) Update(ctx context.Context, c *connection.Connection) e
 	const q = `
 		UPDATE data_sources SET
 			name = ?, type = ?, config = ?, description = ?,
-			tags = ?, is_active = ?, updated_at = ?, created_by = ?
-		WHERE id = ?`
+			tags = ?, is_active = ?, updated_at = ?, created_by = ?,
+			template_id = ?, overrides = ?,
+			environment = ?,
+			deprecation = ?,
+			external_id = ?,
+			query_timeout = ?,
+			config_version = ?,
+			maintenance = ?,
+			replicas = ?,
+			alias = ?,
+			network_policy = ?,
+			cost_model = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
 
-	_, err := r.db.ExecContext(ctx, q,
+	res, err := r.db.ExecContext(ctx, q,
 		c.Name, string(c.Type), string(c.Config),
 		c.Description, marshalTags(c.Tags),
 		isActive,
 		c.UpdatedAt.Format(time.RFC3339),
-		c.CreatedBy, c.ID,
+		c.CreatedBy,
+		c.TemplateID, string(c.Overrides),
+		c.Environment, marshalDeprecation(c.Deprecation),
+		c.ExternalID, c.QueryTimeout, c.ConfigVersion,
+		marshalMaintenance(c.Maintenance), marshalReplicas(c.Replicas), c.Alias,
+		marshalNetworkPolicy(c.NetworkPolicy), marshalCostModel(c.CostModel),
+		c.ID, c.Version,
 	)
 	if err != nil {
 		return fmt.Errorf("update connection: %w", err)
 	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update connection: %w", err)
+	}
+	if n == 0 {
+		return connection.ErrVersionConflict
+	}
+	c.Version++
 	return nil
 }
 
@@ -187,32 +245,58 @@ func (r *connectionRepo) Stats(ctx context.Context) (*connection.Stats, error) {
 
 // row is the sqlx scan target for SQLite.
 type row struct {
-	ID          string `db:"id"`
-	Name        string `db:"name"`
-	Type        string `db:"type"`
-	Config      string `db:"config"`
-	Description string `db:"description"`
-	Tags        string `db:"tags"`
-	IsActive    int8   `db:"is_active"`
-	CreatedAt   string `db:"created_at"`
-	UpdatedAt   string `db:"updated_at"`
-	CreatedBy   string `db:"created_by"`
+	ID            string `db:"id"`
+	Name          string `db:"name"`
+	Type          string `db:"type"`
+	Config        string `db:"config"`
+	Description   string `db:"description"`
+	Tags          string `db:"tags"`
+	IsActive      int8   `db:"is_active"`
+	CreatedAt     string `db:"created_at"`
+	UpdatedAt     string `db:"updated_at"`
+	CreatedBy     string `db:"created_by"`
+	TemplateID    string `db:"template_id"`
+	Overrides     string `db:"overrides"`
+	Environment   string `db:"environment"`
+	Deprecation   string `db:"deprecation"`
+	ExternalID    string `db:"external_id"`
+	QueryTimeout  int    `db:"query_timeout"`
+	ConfigVersion int    `db:"config_version"`
+	Maintenance   string `db:"maintenance"`
+	Replicas      string `db:"replicas"`
+	NetworkPolicy string `db:"network_policy"`
+	CostModel     string `db:"cost_model"`
+	Alias         string `db:"alias"`
+	Version       int64  `db:"version"`
 }
 
 func (r *row) toModel() *connection.Connection {
 	createdAt, _ := time.Parse(time.RFC3339, r.CreatedAt)
 	updatedAt, _ := time.Parse(time.RFC3339, r.UpdatedAt)
 	return &connection.Connection{
-		ID:          r.ID,
-		Name:        r.Name,
-		Type:        sdk.DataSourceType(r.Type),
-		Config:      json.RawMessage(r.Config),
-		Description: r.Description,
-		Tags:        unmarshalTags(r.Tags),
-		IsActive:    r.IsActive != 0,
-		CreatedAt:   createdAt,
-		UpdatedAt:   updatedAt,
-		CreatedBy:   r.CreatedBy,
+		ID:            r.ID,
+		Name:          r.Name,
+		Type:          sdk.DataSourceType(r.Type),
+		Config:        json.RawMessage(r.Config),
+		Description:   r.Description,
+		Tags:          unmarshalTags(r.Tags),
+		IsActive:      r.IsActive != 0,
+		CreatedAt:     createdAt,
+		UpdatedAt:     updatedAt,
+		CreatedBy:     r.CreatedBy,
+		TemplateID:    r.TemplateID,
+		Overrides:     unmarshalOverrides(r.Overrides),
+		Environment:   r.Environment,
+		Deprecation:   unmarshalDeprecation(r.Deprecation),
+		ExternalID:    r.ExternalID,
+		QueryTimeout:  r.QueryTimeout,
+		ConfigVersion: r.ConfigVersion,
+		Maintenance:   unmarshalMaintenance(r.Maintenance),
+		Replicas:      unmarshalReplicas(r.Replicas),
+		NetworkPolicy: unmarshalNetworkPolicy(r.NetworkPolicy),
+		CostModel:     unmarshalCostModel(r.CostModel),
+		Alias:         r.Alias,
+		Version:       r.Version,
 	}
 }
 
@@ -233,6 +317,13 @@ func unmarshalTags(s string) []string {
 	return tags
 }
 
+func unmarshalOverrides(s string) json.RawMessage {
+	if s == "" {
+		return nil
+	}
+	return json.RawMessage(s)
+}
+
 func hasAnyTag(dsTags, filterTags []string) bool {
 	set := make(map[string]struct{}, len(dsTags))
 	for _, t := range dsTags {
@@ -245,3 +336,96 @@ func hasAnyTag(dsTags, filterTags []string) bool {
 	}
 	return false
 }
+
+func marshalDeprecation(d *connection.Deprecation) string {
+	if d == nil {
+		return ""
+	}
+	b, _ := json.Marshal(d)
+	return string(b)
+}
+
+func unmarshalDeprecation(s string) *connection.Deprecation {
+	if s == "" {
+		return nil
+	}
+	var d connection.Deprecation
+	if err := json.Unmarshal([]byte(s), &d); err != nil {
+		return nil
+	}
+	return &d
+}
+
+func marshalMaintenance(m *connection.MaintenanceWindow) string {
+	if m == nil {
+		return ""
+	}
+	b, _ := json.Marshal(m)
+	return string(b)
+}
+
+func unmarshalMaintenance(s string) *connection.MaintenanceWindow {
+	if s == "" {
+		return nil
+	}
+	var m connection.MaintenanceWindow
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		return nil
+	}
+	return &m
+}
+
+func marshalReplicas(rs []connection.Replica) string {
+	if len(rs) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(rs)
+	return string(b)
+}
+
+func unmarshalReplicas(s string) []connection.Replica {
+	if s == "" {
+		return nil
+	}
+	var rs []connection.Replica
+	_ = json.Unmarshal([]byte(s), &rs)
+	return rs
+}
+
+func marshalNetworkPolicy(p *connection.NetworkPolicy) string {
+	if p == nil {
+		return ""
+	}
+	b, _ := json.Marshal(p)
+	return string(b)
+}
+
+func unmarshalNetworkPolicy(s string) *connection.NetworkPolicy {
+	if s == "" {
+		return nil
+	}
+	var p connection.NetworkPolicy
+	if err := json.Unmarshal([]byte(s), &p); err != nil {
+		return nil
+	}
+	return &p
+}
+
+func marshalCostModel(m *connection.CostModel) string {
+	if m == nil {
+		return ""
+	}
+	b, _ := json.Marshal(m)
+	return string(b)
+}
+
+func unmarshalCostModel(s string) *connection.CostModel {
+	if s == "" {
+		return nil
+	}
+	var m connection.CostModel
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		return nil
+	}
+	return &m
+}
@@ -0,0 +1,87 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/user"
+)
+
+type credentialRepo struct {
+	db *sqlx.DB
+}
+
+// NewCredentialRepo returns a user.CredentialRepository backed by SQLite.
+func NewCredentialRepo(db *sqlx.DB) user.CredentialRepository {
+	return &credentialRepo{db: db}
+}
+
+type credentialRow struct {
+	UserID            string         `db:"user_id"`
+	PasswordHash      string         `db:"password_hash"`
+	FailedAttempts    int            `db:"failed_attempts"`
+	LockedUntil       sql.NullString `db:"locked_until"`
+	TOTPSecret        string         `db:"totp_secret"`
+	TOTPEnabled       bool           `db:"totp_enabled"`
+	TOTPLastStep      int64          `db:"totp_last_step"`
+	PasswordChangedAt string         `db:"password_changed_at"`
+}
+
+func (r *credentialRepo) Get(ctx context.Context, userID string) (*user.Credentials, error) {
+	var row credentialRow
+	err := r.db.GetContext(ctx, &row, `SELECT * FROM user_credentials WHERE user_id = ?`, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("credentials of user %s not found", userID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get credentials: %w", err)
+	}
+	c := &user.Credentials{
+		UserID:            row.UserID,
+		PasswordHash:      row.PasswordHash,
+		FailedAttempts:    row.FailedAttempts,
+		TOTPSecret:        row.TOTPSecret,
+		TOTPEnabled:       row.TOTPEnabled,
+		TOTPLastStep:      row.TOTPLastStep,
+		PasswordChangedAt: parseTime(row.PasswordChangedAt),
+	}
+	if row.LockedUntil.Valid {
+		t := parseTime(row.LockedUntil.String)
+		c.LockedUntil = &t
+	}
+	return c, nil
+}
+
+func (r *credentialRepo) Upsert(ctx context.Context, c *user.Credentials) error {
+	var locked any
+	if c.LockedUntil != nil {
+		locked = c.LockedUntil.UTC().Format(time.RFC3339)
+	}
+	const q = `
+		INSERT INTO user_credentials
+			(user_id, password_hash, failed_attempts, locked_until, totp_secret, totp_enabled, totp_last_step, password_changed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET
+			password_hash = excluded.password_hash, failed_attempts = excluded.failed_attempts,
+			locked_until = excluded.locked_until, totp_secret = excluded.totp_secret,
+			totp_enabled = excluded.totp_enabled, totp_last_step = excluded.totp_last_step,
+			password_changed_at = excluded.password_changed_at`
+	_, err := r.db.ExecContext(ctx, q,
+		c.UserID, c.PasswordHash, c.FailedAttempts, locked, c.TOTPSecret, c.TOTPEnabled, c.TOTPLastStep, c.PasswordChangedAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("upsert credentials: %w", err)
+	}
+	return nil
+}
+
+func (r *credentialRepo) Delete(ctx context.Context, userID string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM user_credentials WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("delete credentials: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	stsqlite "data-voyager/core/internal/store/sqlite"
+	"data-voyager/core/internal/user"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialRepo_SQLite(t *testing.T) {
+	db, err := sqlx.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	goose.SetBaseFS(nil)
+	require.NoError(t, goose.SetDialect("sqlite3"))
+	require.NoError(t, goose.Up(db.DB, "../migrations/sqlite"))
+
+	ctx := context.Background()
+	repo := stsqlite.NewCredentialRepo(db)
+	_, err = repo.Get(ctx, "u1")
+	require.Error(t, err)
+
+	changed := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := &user.Credentials{UserID: "u1", PasswordHash: "h", FailedAttempts: 2, PasswordChangedAt: changed}
+	require.NoError(t, repo.Upsert(ctx, c))
+	got, err := repo.Get(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, c, got)
+
+	until := changed.Add(15 * time.Minute)
+	c.LockedUntil, c.FailedAttempts, c.TOTPSecret, c.TOTPEnabled, c.TOTPLastStep = &until, 0, "enc", true, 57000001
+	require.NoError(t, repo.Upsert(ctx, c))
+	got, err = repo.Get(ctx, "u1")
+	require.NoError(t, err)
+	require.NotNil(t, got.LockedUntil)
+	assert.Equal(t, until, *got.LockedUntil)
+	assert.True(t, got.TOTPEnabled)
+	assert.Equal(t, "enc", got.TOTPSecret)
+	assert.Equal(t, int64(57000001), got.TOTPLastStep)
+
+	require.NoError(t, repo.Delete(ctx, "u1"))
+	_, err = repo.Get(ctx, "u1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"data-voyager/core/internal/dashboard"
+	stsqlite "data-voyager/core/internal/store/sqlite"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRepo_SQLite(t *testing.T) {
+	db, err := sqlx.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	goose.SetBaseFS(nil)
+	require.NoError(t, goose.SetDialect("sqlite3"))
+	require.NoError(t, goose.Up(db.DB, "../migrations/sqlite"))
+
+	repo := stsqlite.NewSnapshotRepo(db)
+	ctx := context.Background()
+
+	content := &dashboard.SnapshotContent{
+		Dashboard: dashboard.Dashboard{ID: "d1", Name: "Sales", Panels: []dashboard.Panel{{ID: "p1", Query: "SELECT 1"}}},
+		Result: dashboard.DataResult{
+			TimeRange: dashboard.TimeRange{From: "2024-01-01T00:00:00Z", To: "2024-01-02T00:00:00Z"},
+			Panels:    []dashboard.PanelResult{{PanelID: "p1", ExecutedQuery: "SELECT 1"}},
+		},
+	}
+	older := &dashboard.Snapshot{ID: "s1", DashboardID: "d1", Name: "Q4", CreatedBy: "ann",
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Content: content}
+	expires := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	newer := &dashboard.Snapshot{ID: "s2", DashboardID: "d1", Name: "Incident", Label: "confidential",
+		CreatedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), ExpiresAt: &expires, Content: content}
+	require.NoError(t, repo.Create(ctx, older))
+	require.NoError(t, repo.Create(ctx, newer))
+	require.NoError(t, repo.Create(ctx, &dashboard.Snapshot{ID: "s3", DashboardID: "d2", Name: "Other", Content: content}))
+
+	got, err := repo.GetByID(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, "ann", got.CreatedBy)
+	assert.Equal(t, older.CreatedAt, got.CreatedAt)
+	require.NotNil(t, got.Content)
+	assert.Equal(t, content.Dashboard.Panels, got.Content.Dashboard.Panels)
+	assert.Equal(t, content.Result.Panels, got.Content.Result.Panels)
+
+	list, err := repo.List(ctx, "d1")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "s2", list[0].ID, "newest first")
+	assert.Nil(t, list[0].Content, "lists leave the content out")
+	assert.Equal(t, "confidential", list[0].Label)
+	require.NotNil(t, list[0].ExpiresAt)
+	assert.Equal(t, expires, *list[0].ExpiresAt)
+	assert.Nil(t, list[1].ExpiresAt)
+
+	n, err := repo.DeleteExpired(ctx, expires.Add(-time.Second))
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	n, err = repo.DeleteExpired(ctx, expires)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+	_, err = repo.GetByID(ctx, "s2")
+	assert.Error(t, err)
+
+	require.NoError(t, repo.Delete(ctx, "s1"))
+	_, err = repo.GetByID(ctx, "s1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,56 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"data-voyager/core/internal/dashboard"
+	stsqlite "data-voyager/core/internal/store/sqlite"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardRepo_SQLite(t *testing.T) {
+	db, err := sqlx.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	goose.SetBaseFS(nil)
+	require.NoError(t, goose.SetDialect("sqlite3"))
+	require.NoError(t, goose.Up(db.DB, "../migrations/sqlite"))
+
+	repo := stsqlite.NewDashboardRepo(db)
+	ctx := context.Background()
+
+	d := &dashboard.Dashboard{
+		ID:        "d1",
+		Name:      "Sales",
+		Variables: []dashboard.Variable{{Name: "region", Type: dashboard.VarCustom, Options: []string{"eu", "us"}, Default: "eu"}},
+		Panels:    []dashboard.Panel{{ID: "p1", Title: "Orders", DatasourceID: "ds1", Query: "SELECT 1"}},
+		TimeRange: dashboard.TimeRange{From: "now-1d", To: "now"},
+	}
+	require.NoError(t, repo.Create(ctx, d))
+
+	got, err := repo.GetByID(ctx, "d1")
+	require.NoError(t, err)
+	assert.Equal(t, d.Variables, got.Variables)
+	assert.Equal(t, d.Panels, got.Panels)
+	assert.Equal(t, d.TimeRange, got.TimeRange)
+	assert.False(t, got.CreatedAt.IsZero())
+
+	got.Name = "Revenue"
+	got.Panels = nil
+	require.NoError(t, repo.Update(ctx, got))
+	list, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "Revenue", list[0].Name)
+	assert.Empty(t, list[0].Panels)
+
+	require.NoError(t, repo.Delete(ctx, "d1"))
+	_, err = repo.GetByID(ctx, "d1")
+	assert.Error(t, err)
+}
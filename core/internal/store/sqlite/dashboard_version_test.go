@@ -0,0 +1,67 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"data-voyager/core/internal/dashboard"
+	stsqlite "data-voyager/core/internal/store/sqlite"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardVersionRepo_SQLite(t *testing.T) {
+	db, err := sqlx.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	goose.SetBaseFS(nil)
+	require.NoError(t, goose.SetDialect("sqlite3"))
+	require.NoError(t, goose.Up(db.DB, "../migrations/sqlite"))
+
+	repo := stsqlite.NewDashboardVersionRepo(db)
+	ctx := context.Background()
+
+	first := &dashboard.Version{DashboardID: "d1", Version: 1, CreatedBy: "ann",
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Dashboard: &dashboard.Dashboard{ID: "d1", Name: "Sales", Panels: []dashboard.Panel{{ID: "p1", Query: "SELECT 1"}}}}
+	second := &dashboard.Version{DashboardID: "d1", Version: 2,
+		Dashboard: &dashboard.Dashboard{ID: "d1", Name: "Sales by region"}}
+	require.NoError(t, repo.Create(ctx, first))
+	require.NoError(t, repo.Create(ctx, second))
+	require.NoError(t, repo.Create(ctx, &dashboard.Version{DashboardID: "d2", Version: 1, Dashboard: &dashboard.Dashboard{ID: "d2"}}))
+	assert.Error(t, repo.Create(ctx, &dashboard.Version{DashboardID: "d1", Version: 2, Dashboard: &dashboard.Dashboard{ID: "d1"}}),
+		"a version number is taken once")
+
+	list, err := repo.List(ctx, "d1")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, 2, list[0].Version)
+	assert.Equal(t, 1, list[1].Version)
+	assert.Nil(t, list[0].Dashboard, "lists leave out the content")
+
+	got, err := repo.Get(ctx, "d1", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "ann", got.CreatedBy)
+	assert.Equal(t, first.CreatedAt, got.CreatedAt)
+	require.NotNil(t, got.Dashboard)
+	assert.Equal(t, first.Dashboard.Panels, got.Dashboard.Panels)
+
+	latest, err := repo.Get(ctx, "d1", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "Sales by region", latest.Dashboard.Name)
+
+	_, err = repo.Get(ctx, "d1", 3)
+	assert.Error(t, err)
+
+	require.NoError(t, repo.DeleteAll(ctx, "d1"))
+	list, err = repo.List(ctx, "d1")
+	require.NoError(t, err)
+	assert.Empty(t, list)
+	_, err = repo.Get(ctx, "d2", 0)
+	assert.NoError(t, err)
+}
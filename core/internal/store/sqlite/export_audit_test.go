@@ -0,0 +1,71 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"data-voyager/core/internal/export"
+	stsqlite "data-voyager/core/internal/store/sqlite"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportAuditRepo_SQLite(t *testing.T) {
+	db, err := sqlx.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	goose.SetBaseFS(nil)
+	require.NoError(t, goose.SetDialect("sqlite3"))
+	require.NoError(t, goose.Up(db.DB, "../migrations/sqlite"))
+
+	repo := stsqlite.NewExportAuditRepo(db)
+	ctx := context.Background()
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i, r := range []*export.AuditRecord{
+		{Actor: "ann", Delivery: export.DeliveryNotification, Watermark: "wm1", At: day.Add(time.Hour)},
+		{Actor: "bob", Delivery: export.DeliveryDownload, Watermark: "wm1", Address: "10.0.0.7", At: day.Add(2 * time.Hour)},
+		{Actor: "ann", Delivery: export.DeliveryFile, Watermark: "wm2", At: day.Add(26 * time.Hour)},
+	} {
+		r.ID, r.ExportID, r.DatasourceID, r.Table, r.Rows = string(rune('a'+i)), "e", "ds", "orders", 15
+		require.NoError(t, repo.Record(ctx, r))
+	}
+
+	all, err := repo.List(ctx, export.AuditFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "c", all[0].ID, "newest first")
+	assert.Equal(t, day.Add(26*time.Hour), all[0].At)
+	assert.EqualValues(t, 15, all[0].Rows)
+
+	for name, tc := range map[string]struct {
+		f    export.AuditFilter
+		want []string
+	}{
+		"actor":     {export.AuditFilter{Actor: "ann"}, []string{"c", "a"}},
+		"watermark": {export.AuditFilter{Watermark: "wm1"}, []string{"b", "a"}},
+		"window":    {export.AuditFilter{From: day, To: day.Add(24 * time.Hour)}, []string{"b", "a"}},
+		"from":      {export.AuditFilter{From: day.Add(2 * time.Hour)}, []string{"c", "b"}},
+		"limit":     {export.AuditFilter{Limit: 1}, []string{"c"}},
+	} {
+		if tc.f.Limit == 0 {
+			tc.f.Limit = 10
+		}
+		got, err := repo.List(ctx, tc.f)
+		require.NoError(t, err, name)
+		var ids []string
+		for _, r := range got {
+			ids = append(ids, r.ID)
+		}
+		assert.Equal(t, tc.want, ids, name)
+	}
+	got, err := repo.List(ctx, export.AuditFilter{Watermark: "wm1", Actor: "bob", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "10.0.0.7", got[0].Address)
+}
@@ -0,0 +1,137 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/user"
+)
+
+type invitationRepo struct {
+	db *sqlx.DB
+}
+
+// NewInvitationRepo returns a user.InvitationRepository backed by SQLite.
+func NewInvitationRepo(db *sqlx.DB) user.InvitationRepository {
+	return &invitationRepo{db: db}
+}
+
+type invitationRow struct {
+	ID          string         `db:"id"`
+	Email       string         `db:"email"`
+	Name        string         `db:"name"`
+	Role        string         `db:"role"`
+	Datasources string         `db:"datasources"`
+	TokenHash   string         `db:"token_hash"`
+	InvitedBy   string         `db:"invited_by"`
+	SentCount   int            `db:"sent_count"`
+	ExpiresAt   string         `db:"expires_at"`
+	AcceptedAt  sql.NullString `db:"accepted_at"`
+	UserID      string         `db:"user_id"`
+	CreatedAt   string         `db:"created_at"`
+	UpdatedAt   string         `db:"updated_at"`
+}
+
+func (r invitationRow) toModel() *user.Invitation {
+	ds := unmarshalTags(r.Datasources)
+	if ds == nil {
+		ds = []string{}
+	}
+	inv := &user.Invitation{
+		ID:          r.ID,
+		Email:       r.Email,
+		Name:        r.Name,
+		Role:        r.Role,
+		Datasources: ds,
+		TokenHash:   r.TokenHash,
+		InvitedBy:   r.InvitedBy,
+		SentCount:   r.SentCount,
+		ExpiresAt:   parseTime(r.ExpiresAt),
+		UserID:      r.UserID,
+		CreatedAt:   parseTime(r.CreatedAt),
+		UpdatedAt:   parseTime(r.UpdatedAt),
+	}
+	if r.AcceptedAt.Valid {
+		t := parseTime(r.AcceptedAt.String)
+		inv.AcceptedAt = &t
+	}
+	return inv
+}
+
+func (r *invitationRepo) List(ctx context.Context) ([]*user.Invitation, error) {
+	var rows []invitationRow
+	if err := r.db.SelectContext(ctx, &rows, `SELECT * FROM user_invitations ORDER BY created_at DESC`); err != nil {
+		return nil, fmt.Errorf("list invitations: %w", err)
+	}
+	result := make([]*user.Invitation, len(rows))
+	for i, row := range rows {
+		result[i] = row.toModel()
+	}
+	return result, nil
+}
+
+func (r *invitationRepo) GetByID(ctx context.Context, id string) (*user.Invitation, error) {
+	return r.get(ctx, `SELECT * FROM user_invitations WHERE id = ?`, id)
+}
+
+func (r *invitationRepo) GetByTokenHash(ctx context.Context, hash string) (*user.Invitation, error) {
+	return r.get(ctx, `SELECT * FROM user_invitations WHERE token_hash = ?`, hash)
+}
+
+func (r *invitationRepo) get(ctx context.Context, q, arg string) (*user.Invitation, error) {
+	var row invitationRow
+	err := r.db.GetContext(ctx, &row, q, arg)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("invitation not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get invitation: %w", err)
+	}
+	return row.toModel(), nil
+}
+
+func (r *invitationRepo) Create(ctx context.Context, inv *user.Invitation) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_invitations
+			(id, email, name, role, datasources, token_hash, invited_by, sent_count, expires_at, accepted_at, user_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		inv.ID, inv.Email, inv.Name, inv.Role, marshalTags(inv.Datasources), inv.TokenHash, inv.InvitedBy,
+		inv.SentCount, formatTime(inv.ExpiresAt), nullableTime(inv.AcceptedAt), inv.UserID, formatTime(inv.CreatedAt), formatTime(inv.UpdatedAt))
+	if err != nil {
+		return fmt.Errorf("create invitation: %w", err)
+	}
+	return nil
+}
+
+func (r *invitationRepo) Update(ctx context.Context, inv *user.Invitation) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE user_invitations SET token_hash = ?, sent_count = ?, expires_at = ?, accepted_at = ?, user_id = ?, updated_at = ?
+		WHERE id = ?`,
+		inv.TokenHash, inv.SentCount, formatTime(inv.ExpiresAt), nullableTime(inv.AcceptedAt), inv.UserID, formatTime(inv.UpdatedAt), inv.ID)
+	if err != nil {
+		return fmt.Errorf("update invitation: %w", err)
+	}
+	return nil
+}
+
+func (r *invitationRepo) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM user_invitations WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete invitation: %w", err)
+	}
+	return nil
+}
+
+func formatTime(t time.Time) string { return t.UTC().Format(time.RFC3339) }
+
+// nullableTime formats t, or is NULL when t is nil.
+func nullableTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return formatTime(*t)
+}
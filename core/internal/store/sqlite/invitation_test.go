@@ -0,0 +1,58 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	stsqlite "data-voyager/core/internal/store/sqlite"
+	"data-voyager/core/internal/user"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvitationRepo_SQLite(t *testing.T) {
+	db, err := sqlx.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	goose.SetBaseFS(nil)
+	require.NoError(t, goose.SetDialect("sqlite3"))
+	require.NoError(t, goose.Up(db.DB, "../migrations/sqlite"))
+
+	ctx := context.Background()
+	repo := stsqlite.NewInvitationRepo(db)
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	inv := &user.Invitation{
+		ID: "i1", Email: "bob@example.com", Role: "viewer", Datasources: []string{"ds1"},
+		TokenHash: "abc", InvitedBy: "ann", SentCount: 1,
+		ExpiresAt: created.Add(72 * time.Hour), CreatedAt: created, UpdatedAt: created,
+	}
+	require.NoError(t, repo.Create(ctx, inv))
+
+	got, err := repo.GetByTokenHash(ctx, "abc")
+	require.NoError(t, err)
+	assert.Equal(t, inv, got)
+	_, err = repo.GetByTokenHash(ctx, "nope")
+	assert.Error(t, err)
+
+	accepted := created.Add(time.Hour)
+	inv.AcceptedAt, inv.UserID, inv.UpdatedAt = &accepted, "u1", accepted
+	require.NoError(t, repo.Update(ctx, inv))
+	got, err = repo.GetByID(ctx, "i1")
+	require.NoError(t, err)
+	require.NotNil(t, got.AcceptedAt)
+	assert.Equal(t, accepted, *got.AcceptedAt)
+	assert.Equal(t, "u1", got.UserID)
+
+	list, err := repo.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	require.NoError(t, repo.Delete(ctx, "i1"))
+	_, err = repo.GetByID(ctx, "i1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,208 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/monitor"
+)
+
+type monitorRepo struct {
+	db *sqlx.DB
+}
+
+// NewMonitorRepo returns a monitor.Repository backed by SQLite.
+func NewMonitorRepo(db *sqlx.DB) monitor.Repository {
+	return &monitorRepo{db: db}
+}
+
+type monitorRow struct {
+	ID              string `db:"id"`
+	Name            string `db:"name"`
+	Description     string `db:"description"`
+	DatasourceID    string `db:"datasource_id"`
+	Query           string `db:"query"`
+	IntervalSeconds int    `db:"interval_seconds"`
+	Detector        string `db:"detector"`
+	Severity        string `db:"severity"`
+	Enabled         bool   `db:"enabled"`
+	CreatedAt       string `db:"created_at"`
+	UpdatedAt       string `db:"updated_at"`
+}
+
+func (r monitorRow) toModel() *monitor.Monitor {
+	m := &monitor.Monitor{
+		ID:              r.ID,
+		Name:            r.Name,
+		Description:     r.Description,
+		DatasourceID:    r.DatasourceID,
+		Query:           r.Query,
+		IntervalSeconds: r.IntervalSeconds,
+		Severity:        r.Severity,
+		Enabled:         r.Enabled,
+		CreatedAt:       parseTime(r.CreatedAt),
+		UpdatedAt:       parseTime(r.UpdatedAt),
+	}
+	_ = json.Unmarshal([]byte(r.Detector), &m.Detector)
+	return m
+}
+
+func marshalDetector(d monitor.Detector) string {
+	b, _ := json.Marshal(d)
+	return string(b)
+}
+
+type monitorRunRow struct {
+	ID        string          `db:"id"`
+	MonitorID string          `db:"monitor_id"`
+	RanAt     string          `db:"ran_at"`
+	Value     sql.NullFloat64 `db:"value"`
+	Error     string          `db:"error"`
+	Expected  sql.NullFloat64 `db:"expected"`
+	Lower     sql.NullFloat64 `db:"lower"`
+	Upper     sql.NullFloat64 `db:"upper"`
+	Score     sql.NullFloat64 `db:"score"`
+	Anomalous bool            `db:"anomalous"`
+}
+
+func (r monitorRunRow) toModel() *monitor.Run {
+	return &monitor.Run{
+		ID:        r.ID,
+		MonitorID: r.MonitorID,
+		RanAt:     parseTime(r.RanAt),
+		Value:     nullFloat(r.Value),
+		Error:     r.Error,
+		Expected:  nullFloat(r.Expected),
+		Lower:     nullFloat(r.Lower),
+		Upper:     nullFloat(r.Upper),
+		Score:     nullFloat(r.Score),
+		Anomalous: r.Anomalous,
+	}
+}
+
+// runTimeLayout has fixed-width fractional seconds so ran_at sorts
+// chronologically as text.
+const runTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// FormatRunTime renders t the way run tables store ran_at, for callers that
+// compare against the column directly.
+func FormatRunTime(t time.Time) any {
+	return t.UTC().Format(runTimeLayout)
+}
+
+func parseTime(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339, s)
+	return t
+}
+
+func nullFloat(n sql.NullFloat64) *float64 {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Float64
+}
+
+func (r *monitorRepo) List(ctx context.Context) ([]*monitor.Monitor, error) {
+	var rows []monitorRow
+	if err := r.db.SelectContext(ctx, &rows, `SELECT * FROM monitors ORDER BY name`); err != nil {
+		return nil, fmt.Errorf("list monitors: %w", err)
+	}
+	result := make([]*monitor.Monitor, len(rows))
+	for i := range rows {
+		result[i] = rows[i].toModel()
+	}
+	return result, nil
+}
+
+func (r *monitorRepo) GetByID(ctx context.Context, id string) (*monitor.Monitor, error) {
+	var row monitorRow
+	err := r.db.GetContext(ctx, &row, `SELECT * FROM monitors WHERE id = ?`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("monitor %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get monitor: %w", err)
+	}
+	return row.toModel(), nil
+}
+
+func (r *monitorRepo) Create(ctx context.Context, m *monitor.Monitor) error {
+	now := time.Now().UTC()
+	m.CreatedAt = now
+	m.UpdatedAt = now
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO monitors
+			(id, name, description, datasource_id, query, interval_seconds, detector, severity, enabled, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		m.ID, m.Name, m.Description, m.DatasourceID, m.Query, m.IntervalSeconds,
+		marshalDetector(m.Detector), m.Severity, m.Enabled, now.Format(time.RFC3339), now.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("create monitor: %w", err)
+	}
+	return nil
+}
+
+func (r *monitorRepo) Update(ctx context.Context, m *monitor.Monitor) error {
+	m.UpdatedAt = time.Now().UTC()
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE monitors SET name=?, description=?, datasource_id=?, query=?, interval_seconds=?,
+			detector=?, severity=?, enabled=?, updated_at=?
+		WHERE id=?`,
+		m.Name, m.Description, m.DatasourceID, m.Query, m.IntervalSeconds,
+		marshalDetector(m.Detector), m.Severity, m.Enabled, m.UpdatedAt.Format(time.RFC3339), m.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update monitor: %w", err)
+	}
+	return nil
+}
+
+func (r *monitorRepo) Delete(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("delete monitor: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM monitor_runs WHERE monitor_id = ?`, id); err != nil {
+		return fmt.Errorf("delete monitor runs: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM monitors WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete monitor: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *monitorRepo) AddRun(ctx context.Context, run *monitor.Run) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO monitor_runs
+			(id, monitor_id, ran_at, value, error, expected, lower, upper, score, anomalous)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		run.ID, run.MonitorID, run.RanAt.UTC().Format(runTimeLayout), run.Value, run.Error,
+		run.Expected, run.Lower, run.Upper, run.Score, run.Anomalous,
+	)
+	if err != nil {
+		return fmt.Errorf("add monitor run: %w", err)
+	}
+	return nil
+}
+
+func (r *monitorRepo) ListRuns(ctx context.Context, monitorID string, limit int) ([]*monitor.Run, error) {
+	var rows []monitorRunRow
+	err := r.db.SelectContext(ctx, &rows,
+		`SELECT * FROM monitor_runs WHERE monitor_id = ? ORDER BY ran_at DESC LIMIT ?`, monitorID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list monitor runs: %w", err)
+	}
+	result := make([]*monitor.Run, len(rows))
+	for i := range rows {
+		result[i] = rows[i].toModel()
+	}
+	return result, nil
+}
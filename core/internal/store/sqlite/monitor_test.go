@@ -0,0 +1,64 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"data-voyager/core/internal/monitor"
+	stsqlite "data-voyager/core/internal/store/sqlite"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitorRepo_SQLite(t *testing.T) {
+	db, err := sqlx.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	goose.SetBaseFS(nil)
+	require.NoError(t, goose.SetDialect("sqlite3"))
+	require.NoError(t, goose.Up(db.DB, "../migrations/sqlite"))
+
+	repo := stsqlite.NewMonitorRepo(db)
+	ctx := context.Background()
+
+	m := &monitor.Monitor{
+		ID: "m1", Name: "Orders", DatasourceID: "ds1", Query: "SELECT count(*) FROM orders",
+		IntervalSeconds: 300, Severity: "warning", Enabled: true,
+		Detector: monitor.Detector{Method: monitor.MethodEWMA, Threshold: 2, Window: 20, MinRuns: 5, Alpha: 0.5},
+	}
+	require.NoError(t, repo.Create(ctx, m))
+
+	got, err := repo.GetByID(ctx, "m1")
+	require.NoError(t, err)
+	assert.Equal(t, m.Detector, got.Detector)
+	assert.True(t, got.Enabled)
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	v, lo := 42.0, 10.0
+	for i := 0; i < 3; i++ {
+		require.NoError(t, repo.AddRun(ctx, &monitor.Run{
+			ID: string(rune('a' + i)), MonitorID: "m1", RanAt: base.Add(time.Duration(i) * 500 * time.Millisecond),
+			Value: &v, Lower: &lo, Anomalous: i == 2,
+		}))
+	}
+	require.NoError(t, repo.AddRun(ctx, &monitor.Run{ID: "x", MonitorID: "m1", RanAt: base.Add(-time.Hour), Error: "boom"}))
+
+	runs, err := repo.ListRuns(ctx, "m1", 2)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "c", runs[0].ID, "newest first, with sub-second ordering")
+	assert.True(t, runs[0].Anomalous)
+	assert.Equal(t, 42.0, *runs[0].Value)
+	assert.Nil(t, runs[0].Upper)
+	assert.Equal(t, base.Add(time.Second), runs[0].RanAt)
+
+	require.NoError(t, repo.Delete(ctx, "m1"))
+	runs, err = repo.ListRuns(ctx, "m1", 10)
+	require.NoError(t, err)
+	assert.Empty(t, runs, "runs are deleted with their monitor")
+}
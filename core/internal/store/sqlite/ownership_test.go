@@ -0,0 +1,59 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"data-voyager/core/internal/ownership"
+	stsqlite "data-voyager/core/internal/store/sqlite"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwnershipRepo_SQLite(t *testing.T) {
+	db, err := sqlx.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	goose.SetBaseFS(nil)
+	require.NoError(t, goose.SetDialect("sqlite3"))
+	require.NoError(t, goose.Up(db.DB, "../migrations/sqlite"))
+
+	repo := stsqlite.NewOwnershipRepo(db)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, repo.Upsert(ctx, &ownership.Ownership{
+		ID: "o1", ResourceType: ownership.ResourceDatasource, ResourceID: "ds1",
+		Owner: "alice", OwnerKind: ownership.KindUser, UpdatedAt: now,
+	}))
+	// Re-assigning the same resource replaces the record instead of duplicating it.
+	require.NoError(t, repo.Upsert(ctx, &ownership.Ownership{
+		ID: "o2", ResourceType: ownership.ResourceDatasource, ResourceID: "ds1",
+		Owner: "data-team", OwnerKind: ownership.KindTeam, Steward: "bob", UpdatedAt: now,
+	}))
+
+	got, err := repo.Get(ctx, ownership.ResourceDatasource, "ds1")
+	require.NoError(t, err)
+	assert.Equal(t, "o1", got.ID)
+	assert.Equal(t, "data-team", got.Owner)
+	assert.Equal(t, ownership.KindTeam, got.OwnerKind)
+	assert.Equal(t, "bob", got.Steward)
+	assert.True(t, now.Equal(got.UpdatedAt))
+
+	list, err := repo.List(ctx, ownership.Filter{Steward: "bob"})
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	list, err = repo.List(ctx, ownership.Filter{Owner: "alice"})
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	require.NoError(t, repo.Delete(ctx, ownership.ResourceDatasource, "ds1"))
+	_, err = repo.Get(ctx, ownership.ResourceDatasource, "ds1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,65 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"data-voyager/core/internal/reconcile"
+	stsqlite "data-voyager/core/internal/store/sqlite"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcileRepo_SQLite(t *testing.T) {
+	db, err := sqlx.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	goose.SetBaseFS(nil)
+	require.NoError(t, goose.SetDialect("sqlite3"))
+	require.NoError(t, goose.Up(db.DB, "../migrations/sqlite"))
+
+	repo := stsqlite.NewReconcileRepo(db)
+	ctx := context.Background()
+
+	j := &reconcile.Job{
+		ID: "j1", Name: "orders", IntervalSeconds: 3600, Severity: "critical", Enabled: true,
+		Source: reconcile.Side{DatasourceID: "a", Table: "orders", Filter: "status = 'paid'"},
+		Target: reconcile.Side{DatasourceID: "b", Query: "SELECT * FROM fact_orders"},
+		Compare: reconcile.Comparison{
+			GroupBy:  []string{"day"},
+			Measures: []reconcile.Measure{{Func: reconcile.FuncCount}, {Func: reconcile.FuncSum, Column: "amount"}},
+		},
+	}
+	require.NoError(t, repo.Create(ctx, j))
+
+	got, err := repo.GetByID(ctx, "j1")
+	require.NoError(t, err)
+	assert.Equal(t, j.Source, got.Source)
+	assert.Equal(t, j.Target, got.Target)
+	assert.Equal(t, j.Compare, got.Compare)
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.AddRun(ctx, &reconcile.Run{ID: "r1", JobID: "j1", RanAt: base, Status: reconcile.StatusOK, Matched: 3}))
+	require.NoError(t, repo.AddRun(ctx, &reconcile.Run{
+		ID: "r2", JobID: "j1", RanAt: base.Add(time.Hour), Status: reconcile.StatusMismatch, Missing: 1,
+		Discrepancies: []reconcile.Discrepancy{{Kind: reconcile.KindMissing, Key: map[string]any{"day": "d1"}, Source: map[string]any{"count": 4.0}}},
+	}))
+
+	runs, err := repo.ListRuns(ctx, "j1", 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "r2", runs[0].ID)
+	assert.Equal(t, base.Add(time.Hour), runs[0].RanAt)
+	assert.Equal(t, map[string]any{"day": "d1"}, runs[0].Discrepancies[0].Key)
+	assert.Empty(t, runs[1].Discrepancies)
+
+	require.NoError(t, repo.Delete(ctx, "j1"))
+	runs, err = repo.ListRuns(ctx, "j1", 10)
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+}
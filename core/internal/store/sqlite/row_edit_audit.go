@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/rowedit"
+)
+
+type rowEditAuditRepo struct {
+	db *sqlx.DB
+}
+
+// NewRowEditAuditRepo returns a rowedit.AuditRepository backed by SQLite.
+func NewRowEditAuditRepo(db *sqlx.DB) rowedit.AuditRepository {
+	return &rowEditAuditRepo{db: db}
+}
+
+type rowEditAuditRow struct {
+	ID           string         `db:"id"`
+	TableRef     string         `db:"table_ref"`
+	DatasourceID string         `db:"datasource_id"`
+	Schema       string         `db:"schema_name"`
+	Table        string         `db:"table_name"`
+	Op           string         `db:"op"`
+	Key          string         `db:"row_key"`
+	Before       sql.NullString `db:"before_row"`
+	After        sql.NullString `db:"after_row"`
+	Actor        string         `db:"actor"`
+	At           string         `db:"at"`
+}
+
+func (r *rowEditAuditRepo) Record(ctx context.Context, e *rowedit.AuditRecord) error {
+	key, err := json.Marshal(e.Key)
+	if err != nil {
+		return fmt.Errorf("record row edit audit: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO row_edit_audit (id, table_ref, datasource_id, schema_name, table_name, op,
+			row_key, before_row, after_row, actor, at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.ID, e.Table, e.DatasourceID, e.Schema, e.TableName, string(e.Op),
+		string(key), rowJSON(e.Before), rowJSON(e.After), e.Actor, e.At.UTC().Format(runTimeLayout))
+	if err != nil {
+		return fmt.Errorf("record row edit audit: %w", err)
+	}
+	return nil
+}
+
+func (r *rowEditAuditRepo) List(ctx context.Context, f rowedit.AuditFilter) ([]*rowedit.AuditRecord, error) {
+	var rows []rowEditAuditRow
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT * FROM row_edit_audit
+		WHERE (? = '' OR table_ref = ?) AND (? = '' OR actor = ?)
+			AND (? = '' OR at >= ?) AND (? = '' OR at < ?)
+		ORDER BY at DESC LIMIT ?`,
+		f.Table, f.Table, f.Actor, f.Actor,
+		auditTime(f.From), auditTime(f.From), auditTime(f.To), auditTime(f.To), f.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("list row edit audit: %w", err)
+	}
+	result := make([]*rowedit.AuditRecord, len(rows))
+	for i, row := range rows {
+		rec := &rowedit.AuditRecord{
+			ID:           row.ID,
+			Table:        row.TableRef,
+			DatasourceID: row.DatasourceID,
+			Schema:       row.Schema,
+			TableName:    row.Table,
+			Op:           rowedit.Op(row.Op),
+			Actor:        row.Actor,
+			At:           parseTime(row.At),
+		}
+		_ = json.Unmarshal([]byte(row.Key), &rec.Key)
+		if row.Before.Valid {
+			_ = json.Unmarshal([]byte(row.Before.String), &rec.Before)
+		}
+		if row.After.Valid {
+			_ = json.Unmarshal([]byte(row.After.String), &rec.After)
+		}
+		result[i] = rec
+	}
+	return result, nil
+}
+
+// rowJSON encodes a row image, or NULL when there is none.
+func rowJSON(row map[string]any) sql.NullString {
+	if row == nil {
+		return sql.NullString{}
+	}
+	b, err := json.Marshal(row)
+	if err != nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(b), Valid: true}
+}
@@ -0,0 +1,73 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"data-voyager/core/internal/rowedit"
+	stsqlite "data-voyager/core/internal/store/sqlite"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowEditAuditRepo_SQLite(t *testing.T) {
+	db, err := sqlx.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	goose.SetBaseFS(nil)
+	require.NoError(t, goose.SetDialect("sqlite3"))
+	require.NoError(t, goose.Up(db.DB, "../migrations/sqlite"))
+
+	repo := stsqlite.NewRowEditAuditRepo(db)
+	ctx := context.Background()
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i, r := range []*rowedit.AuditRecord{
+		{Table: "countries", Op: rowedit.OpInsert, Actor: "ann", After: map[string]any{"code": "KR", "name": "Korea"}, At: day.Add(time.Hour)},
+		{Table: "countries", Op: rowedit.OpUpdate, Actor: "bob", Before: map[string]any{"name": "Korea"},
+			After: map[string]any{"name": "South Korea"}, At: day.Add(2 * time.Hour)},
+		{Table: "currencies", Op: rowedit.OpDelete, Actor: "ann", Before: map[string]any{"code": "KRW"}, At: day.Add(26 * time.Hour)},
+	} {
+		r.ID, r.DatasourceID, r.TableName = string(rune('a'+i)), "ds", r.Table
+		r.Key = map[string]any{"code": "KR"}
+		require.NoError(t, repo.Record(ctx, r))
+	}
+
+	all, err := repo.List(ctx, rowedit.AuditFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "c", all[0].ID, "newest first")
+	assert.Equal(t, day.Add(26*time.Hour), all[0].At)
+	assert.Equal(t, rowedit.OpDelete, all[0].Op)
+	assert.Nil(t, all[0].After)
+	assert.Equal(t, map[string]any{"code": "KR"}, all[1].Key)
+	assert.Equal(t, map[string]any{"name": "Korea"}, all[1].Before)
+	assert.Equal(t, map[string]any{"name": "South Korea"}, all[1].After)
+	assert.Nil(t, all[2].Before)
+
+	for name, tc := range map[string]struct {
+		f    rowedit.AuditFilter
+		want []string
+	}{
+		"table":  {rowedit.AuditFilter{Table: "countries"}, []string{"b", "a"}},
+		"actor":  {rowedit.AuditFilter{Actor: "ann"}, []string{"c", "a"}},
+		"window": {rowedit.AuditFilter{From: day, To: day.Add(24 * time.Hour)}, []string{"b", "a"}},
+		"limit":  {rowedit.AuditFilter{Limit: 1}, []string{"c"}},
+	} {
+		if tc.f.Limit == 0 {
+			tc.f.Limit = 10
+		}
+		got, err := repo.List(ctx, tc.f)
+		require.NoError(t, err, name)
+		var ids []string
+		for _, r := range got {
+			ids = append(ids, r.ID)
+		}
+		assert.Equal(t, tc.want, ids, name)
+	}
+}
@@ -0,0 +1,81 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"data-voyager/core/internal/savedview"
+	stsqlite "data-voyager/core/internal/store/sqlite"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavedViewRepo_SQLite(t *testing.T) {
+	db, err := sqlx.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	goose.SetBaseFS(nil)
+	require.NoError(t, goose.SetDialect("sqlite3"))
+	require.NoError(t, goose.Up(db.DB, "../migrations/sqlite"))
+
+	repo := stsqlite.NewSavedViewRepo(db)
+	ctx := context.Background()
+
+	at := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	for _, v := range []*savedview.View{
+		{ID: "a", Name: "Errors last 24h", Table: "logs", Owner: "ann", Spec: savedview.Spec{
+			Filters: []savedview.Filter{{Column: "level", Op: "eq", Value: "error"}},
+			Sort:    "ts", Order: "desc", Columns: []string{"ts", "msg"}, Limit: 50,
+		}},
+		{ID: "b", Name: "Slow requests", Table: "logs", Owner: "bob", Shared: true},
+		{ID: "c", Name: "Bob's drafts", Table: "logs", Owner: "bob"},
+		{ID: "d", Name: "All users", Table: "users", Owner: "ann"},
+	} {
+		v.DatasourceID, v.CreatedAt, v.UpdatedAt = "ds", at, at
+		require.NoError(t, repo.Create(ctx, v))
+	}
+
+	got, err := repo.GetByID(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "ts", got.Spec.Sort)
+	assert.Equal(t, []savedview.Filter{{Column: "level", Op: "eq", Value: "error"}}, got.Spec.Filters)
+	assert.Equal(t, []string{"ts", "msg"}, got.Spec.Columns)
+	assert.Equal(t, at, got.CreatedAt)
+
+	for name, tc := range map[string]struct {
+		f    savedview.ListFilter
+		want []string
+	}{
+		"all":           {savedview.ListFilter{}, []string{"d", "c", "a", "b"}},
+		"own or shared": {savedview.ListFilter{Owner: "ann"}, []string{"d", "a", "b"}},
+		"table":         {savedview.ListFilter{DatasourceID: "ds", Table: "logs", Owner: "ann"}, []string{"a", "b"}},
+		"other source":  {savedview.ListFilter{DatasourceID: "other"}, nil},
+	} {
+		views, err := repo.List(ctx, tc.f)
+		require.NoError(t, err, name)
+		var ids []string
+		for _, v := range views {
+			ids = append(ids, v.ID)
+		}
+		assert.Equal(t, tc.want, ids, name)
+	}
+
+	got.Name, got.Shared, got.UpdatedAt = "Errors today", true, at.Add(time.Hour)
+	got.Spec.Limit = 0
+	require.NoError(t, repo.Update(ctx, got))
+	got, err = repo.GetByID(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "Errors today", got.Name)
+	assert.True(t, got.Shared)
+	assert.Zero(t, got.Spec.Limit)
+	assert.Equal(t, at.Add(time.Hour), got.UpdatedAt)
+
+	require.NoError(t, repo.Delete(ctx, "a"))
+	_, err = repo.GetByID(ctx, "a")
+	assert.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"data-voyager/core/internal/scim"
+	stsqlite "data-voyager/core/internal/store/sqlite"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSCIMGroupRepo_SQLite(t *testing.T) {
+	db, err := sqlx.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	goose.SetBaseFS(nil)
+	require.NoError(t, goose.SetDialect("sqlite3"))
+	require.NoError(t, goose.Up(db.DB, "../migrations/sqlite"))
+
+	ctx := context.Background()
+	repo := stsqlite.NewSCIMGroupRepo(db)
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	g := &scim.Group{ID: "g1", DisplayName: "Engineers", ExternalID: "ext", Members: []string{"u1", "u2"}, CreatedAt: now, UpdatedAt: now}
+	require.NoError(t, repo.Create(ctx, g))
+	require.Error(t, repo.Create(ctx, &scim.Group{ID: "g2", DisplayName: "Engineers", CreatedAt: now, UpdatedAt: now}))
+
+	got, err := repo.Get(ctx, "g1")
+	require.NoError(t, err)
+	assert.Equal(t, g, got)
+
+	g.Members, g.DisplayName = nil, "Eng"
+	require.NoError(t, repo.Update(ctx, g))
+	list, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "Eng", list[0].DisplayName)
+	assert.Empty(t, list[0].Members)
+
+	require.NoError(t, repo.Delete(ctx, "g1"))
+	_, err = repo.Get(ctx, "g1")
+	assert.Error(t, err)
+}
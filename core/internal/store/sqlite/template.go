@@ -0,0 +1,159 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/sdk"
+)
+
+type templateRepo struct {
+	db *sqlx.DB
+}
+
+// NewTemplateRepo returns a connection.TemplateRepository backed by SQLite.
+func NewTemplateRepo(db *sqlx.DB) connection.TemplateRepository {
+	return &templateRepo{db: db}
+}
+
+// ─── row type ──────────────────────────────────────────────────────────────────
+
+type templateRow struct {
+	ID          string `db:"id"`
+	Name        string `db:"name"`
+	Type        string `db:"type"`
+	Config      string `db:"config"`
+	Description string `db:"description"`
+	Tags        string `db:"tags"`
+	CreatedAt   string `db:"created_at"`
+	UpdatedAt   string `db:"updated_at"`
+}
+
+func (r templateRow) toModel() *connection.Template {
+	createdAt, _ := time.Parse(time.RFC3339, r.CreatedAt)
+	updatedAt, _ := time.Parse(time.RFC3339, r.UpdatedAt)
+	return &connection.Template{
+		ID:          r.ID,
+		Name:        r.Name,
+		Type:        sdk.DataSourceType(r.Type),
+		Config:      json.RawMessage(r.Config),
+		Description: r.Description,
+		Tags:        unmarshalTags(r.Tags),
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+	}
+}
+
+// ─── Repository implementation ────────────────────────────────────────────────
+
+func (r *templateRepo) Create(ctx context.Context, t *connection.Template) error {
+	now := time.Now().UTC()
+	t.CreatedAt = now
+	t.UpdatedAt = now
+
+	newID, err := uuid.NewV7()
+	if err != nil {
+		return fmt.Errorf("generate uuid: %w", err)
+	}
+	t.ID = newID.String()
+
+	const q = `
+		INSERT INTO datasource_templates (id, name, type, config, description, tags, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err = r.db.ExecContext(ctx, q,
+		t.ID, t.Name, string(t.Type), string(t.Config), t.Description, marshalTags(t.Tags),
+		t.CreatedAt.Format(time.RFC3339),
+		t.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("create datasource template: %w", err)
+	}
+	return nil
+}
+
+func (r *templateRepo) GetByID(ctx context.Context, id string) (*connection.Template, error) {
+	var row templateRow
+	err := r.db.GetContext(ctx, &row, `SELECT * FROM datasource_templates WHERE id = ?`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("datasource template %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get datasource template: %w", err)
+	}
+	return row.toModel(), nil
+}
+
+func (r *templateRepo) List(ctx context.Context) ([]*connection.Template, error) {
+	var rows []templateRow
+	if err := r.db.SelectContext(ctx, &rows, `SELECT * FROM datasource_templates ORDER BY name`); err != nil {
+		return nil, fmt.Errorf("list datasource templates: %w", err)
+	}
+	result := make([]*connection.Template, len(rows))
+	for i := range rows {
+		result[i] = rows[i].toModel()
+	}
+	return result, nil
+}
+
+func (r *templateRepo) Update(ctx context.Context, t *connection.Template, dependents []*connection.Connection) error {
+	t.UpdatedAt = time.Now().UTC()
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("update datasource template: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	const q = `
+		UPDATE datasource_templates
+		SET name = ?, config = ?, description = ?, tags = ?, updated_at = ?
+		WHERE id = ?`
+	_, err = tx.ExecContext(ctx, q,
+		t.Name, string(t.Config), t.Description, marshalTags(t.Tags),
+		t.UpdatedAt.Format(time.RFC3339),
+		t.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update datasource template: %w", err)
+	}
+
+	const dq = `
+		UPDATE data_sources SET config = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND version = ?`
+	for _, c := range dependents {
+		res, err := tx.ExecContext(ctx, dq, string(c.Config), t.UpdatedAt.Format(time.RFC3339), c.ID, c.Version)
+		if err != nil {
+			return fmt.Errorf("update connection %s: %w", c.Name, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("update connection %s: %w", c.Name, err)
+		}
+		if n == 0 {
+			return fmt.Errorf("%s: %w", c.Name, connection.ErrVersionConflict)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("update datasource template: %w", err)
+	}
+	for _, c := range dependents {
+		c.UpdatedAt = t.UpdatedAt
+		c.Version++
+	}
+	return nil
+}
+
+func (r *templateRepo) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM datasource_templates WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete datasource template: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package sqlite_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"data-voyager/core/internal/connection"
+	stsqlite "data-voyager/core/internal/store/sqlite"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateRepo_UpdateWritesDependentsTogether(t *testing.T) {
+	db, err := sqlx.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	goose.SetBaseFS(nil)
+	require.NoError(t, goose.SetDialect("sqlite3"))
+	require.NoError(t, goose.Up(db.DB, "../migrations/sqlite"))
+
+	templates := stsqlite.NewTemplateRepo(db)
+	conns := stsqlite.NewConnectionRepo(db)
+	ctx := context.Background()
+
+	tmpl := &connection.Template{Name: "pg", Type: "postgresql", Config: json.RawMessage(`{"port":5432}`)}
+	require.NoError(t, templates.Create(ctx, tmpl))
+	a := &connection.Connection{Name: "a", Type: "postgresql", Config: json.RawMessage(`{"port":5432}`), TemplateID: tmpl.ID}
+	b := &connection.Connection{Name: "b", Type: "postgresql", Config: json.RawMessage(`{"port":5432}`), TemplateID: tmpl.ID}
+	require.NoError(t, conns.Create(ctx, a))
+	require.NoError(t, conns.Create(ctx, b))
+
+	// b changes after the template update read it, so the whole update fails.
+	staleB := *b
+	b.Description = "edited"
+	require.NoError(t, conns.Update(ctx, b))
+
+	tmpl.Config = json.RawMessage(`{"port":6432}`)
+	a.Config, b.Config, staleB.Config = tmpl.Config, tmpl.Config, tmpl.Config
+	err = templates.Update(ctx, tmpl, []*connection.Connection{a, &staleB})
+	assert.ErrorIs(t, err, connection.ErrVersionConflict)
+
+	got, err := templates.GetByID(ctx, tmpl.ID)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"port":5432}`, string(got.Config), "the template is rolled back with its dependents")
+	gotA, err := conns.GetByID(ctx, a.ID)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"port":5432}`, string(gotA.Config))
+	assert.EqualValues(t, 1, gotA.Version)
+
+	require.NoError(t, templates.Update(ctx, tmpl, []*connection.Connection{a, b}))
+	for _, c := range []*connection.Connection{a, b} {
+		got, err := conns.GetByID(ctx, c.ID)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"port":6432}`, string(got.Config))
+		assert.Equal(t, c.Version, got.Version)
+	}
+}
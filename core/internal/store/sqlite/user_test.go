@@ -0,0 +1,66 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	stsqlite "data-voyager/core/internal/store/sqlite"
+	"data-voyager/core/internal/user"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepo_SQLite(t *testing.T) {
+	db, err := sqlx.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	goose.SetBaseFS(nil)
+	require.NoError(t, goose.SetDialect("sqlite3"))
+	require.NoError(t, goose.Up(db.DB, "../migrations/sqlite"))
+
+	ctx := context.Background()
+	repo := stsqlite.NewUserRepo(db)
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	u := &user.User{ID: "u1", Username: "ann", Role: "viewer", Datasources: []string{"ds1"}, CreatedAt: now, UpdatedAt: now}
+	require.NoError(t, repo.Create(ctx, u))
+	require.Error(t, repo.Create(ctx, &user.User{ID: "u2", Username: "ann", Role: "viewer", CreatedAt: now, UpdatedAt: now}))
+
+	got, err := repo.GetByUsername(ctx, "ann")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ds1"}, got.Datasources)
+	assert.Equal(t, now, got.CreatedAt)
+
+	u.Role, u.Datasources, u.Disabled, u.RowLimit = "editor", nil, true, 500
+	require.NoError(t, repo.Update(ctx, u))
+	got, err = repo.GetByID(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "editor", got.Role)
+	assert.Equal(t, 500, got.RowLimit)
+	assert.Equal(t, []string{}, got.Datasources)
+	assert.True(t, got.Disabled)
+
+	require.NoError(t, repo.Delete(ctx, "u1"))
+	_, err = repo.GetByID(ctx, "u1")
+	assert.Error(t, err)
+
+	audit := stsqlite.NewImpersonationRepo(db)
+	for i, target := range []string{"ann", "bob", "ann"} {
+		require.NoError(t, audit.Record(ctx, &user.Impersonation{
+			ID: target + string(rune('0'+i)), Actor: "root", Target: target, Method: "GET", Path: "/x", Status: 200,
+			At: now.Add(time.Duration(i) * time.Millisecond),
+		}))
+	}
+	list, err := audit.List(ctx, "ann", 10)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "ann2", list[0].ID)
+	assert.Equal(t, now.Add(2*time.Millisecond), list[0].At)
+	list, err = audit.List(ctx, "", 2)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+}
@@ -0,0 +1,40 @@
+package telemetry
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the local telemetry preview.
+type Handler struct {
+	collector *Collector
+	enabled   bool
+	endpoint  string
+}
+
+// NewHandler creates a telemetry HTTP handler.
+func NewHandler(c *Collector, enabled bool, endpoint string) *Handler {
+	return &Handler{collector: c, enabled: enabled, endpoint: endpoint}
+}
+
+type previewResponse struct {
+	Enabled  bool   `json:"enabled"`
+	Endpoint string `json:"endpoint"`
+	Report   Report `json:"report"`
+}
+
+// Preview handles GET /telemetry/preview. It shows exactly the payload the
+// next report would contain, whether or not telemetry is enabled.
+func (h *Handler) Preview(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": previewResponse{
+		Enabled:  h.enabled,
+		Endpoint: h.endpoint,
+		Report:   h.collector.Snapshot(c.Request.Context()),
+	}})
+}
+
+// RegisterRoutes wires telemetry routes onto r.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/telemetry/preview", h.Preview)
+}
@@ -0,0 +1,70 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Reporter periodically sends the collector's report to an endpoint.
+type Reporter struct {
+	collector *Collector
+	endpoint  string
+	interval  time.Duration
+	client    *http.Client
+}
+
+// NewReporter creates a Reporter that posts to endpoint every interval.
+func NewReporter(c *Collector, endpoint string, interval time.Duration) *Reporter {
+	return &Reporter{
+		collector: c,
+		endpoint:  endpoint,
+		interval:  interval,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Run sends a report every interval until ctx is done. Failures are logged
+// at debug level and the counters carry over to the next attempt.
+func (r *Reporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Send(ctx); err != nil {
+				slog.Debug("telemetry report failed", "err", err)
+			}
+		}
+	}
+}
+
+// Send posts one report and, on success, resets the counters it contained.
+func (r *Reporter) Send(ctx context.Context) error {
+	report := r.collector.Snapshot(ctx)
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint returned %s", resp.Status)
+	}
+	r.collector.reset(report)
+	return nil
+}
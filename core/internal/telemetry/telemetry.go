@@ -0,0 +1,156 @@
+// Package telemetry collects opt-in, anonymous usage counters and reports them
+// to a configured endpoint. Only route templates (never concrete paths),
+// datasource type names and status classes are recorded.
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/sdk"
+)
+
+// Version is the application version included in reports.
+const Version = "0.1.0"
+
+// TypeCounter reports how many datasources exist per type.
+type TypeCounter func(ctx context.Context) (map[sdk.DataSourceType]int64, error)
+
+// Report is the payload sent to the telemetry endpoint. It is exactly what
+// the preview endpoint returns.
+type Report struct {
+	InstanceID      string           `json:"instance_id"`
+	Version         string           `json:"version"`
+	OS              string           `json:"os"`
+	Arch            string           `json:"arch"`
+	GoVersion       string           `json:"go_version"`
+	UptimeSeconds   int64            `json:"uptime_seconds"`
+	DatasourceTypes map[string]int64 `json:"datasource_types"`
+	Features        map[string]int64 `json:"features"`
+	Errors          map[string]int64 `json:"errors"`
+	GeneratedAt     time.Time        `json:"generated_at"`
+}
+
+// Collector accumulates counters between reports. It is safe for concurrent
+// use. Counters are kept even when telemetry is disabled so the preview shows
+// what would be sent after opting in; nothing leaves the process unless a
+// Reporter is running.
+type Collector struct {
+	instanceID string
+	started    time.Time
+	types      TypeCounter
+	now        func() time.Time
+
+	mu       sync.Mutex
+	features map[string]int64
+	errors   map[string]int64
+}
+
+// NewCollector creates a Collector. The instance ID is random per process and
+// not derived from any host or user attribute.
+func NewCollector(types TypeCounter) *Collector {
+	return &Collector{
+		instanceID: randomID(),
+		started:    time.Now(),
+		types:      types,
+		now:        time.Now,
+		features:   map[string]int64{},
+		errors:     map[string]int64{},
+	}
+}
+
+// Feature increments the usage counter for a named feature.
+func (c *Collector) Feature(name string) {
+	c.mu.Lock()
+	c.features[name]++
+	c.mu.Unlock()
+}
+
+// Error increments the counter for an error class such as "http_5xx".
+func (c *Collector) Error(class string) {
+	c.mu.Lock()
+	c.errors[class]++
+	c.mu.Unlock()
+}
+
+// Middleware records each request by its route template, e.g.
+// "POST /api/v1/datasources/:uid/query", and its error class when the
+// response status is 4xx or 5xx. Unmatched routes are ignored.
+func (c *Collector) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+		route := ctx.FullPath()
+		if route == "" {
+			return
+		}
+		feature := ctx.Request.Method + " " + route
+		c.Feature(feature)
+		if status := ctx.Writer.Status(); status >= 400 {
+			c.Error(fmt.Sprintf("http_%dxx", status/100))
+		}
+	}
+}
+
+// Snapshot builds the current report without resetting counters.
+func (c *Collector) Snapshot(ctx context.Context) Report {
+	r := Report{
+		InstanceID:      c.instanceID,
+		Version:         Version,
+		OS:              runtime.GOOS,
+		Arch:            runtime.GOARCH,
+		GoVersion:       runtime.Version(),
+		UptimeSeconds:   int64(c.now().Sub(c.started).Seconds()),
+		DatasourceTypes: map[string]int64{},
+		GeneratedAt:     c.now().UTC(),
+	}
+	if c.types != nil {
+		if counts, err := c.types(ctx); err == nil {
+			for t, n := range counts {
+				r.DatasourceTypes[string(t)] = n
+			}
+		}
+	}
+	c.mu.Lock()
+	r.Features = copyCounts(c.features)
+	r.Errors = copyCounts(c.errors)
+	c.mu.Unlock()
+	return r
+}
+
+// reset subtracts the counts in sent, so increments that raced with sending
+// are kept for the next report.
+func (c *Collector) reset(sent Report) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	subtract(c.features, sent.Features)
+	subtract(c.errors, sent.Errors)
+}
+
+func copyCounts(m map[string]int64) map[string]int64 {
+	out := make(map[string]int64, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+func subtract(m, sent map[string]int64) {
+	for k, v := range sent {
+		if m[k] -= v; m[k] <= 0 {
+			delete(m, k)
+		}
+	}
+}
+
+func randomID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,73 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/sdk"
+)
+
+func newTestCollector() *Collector {
+	return NewCollector(func(context.Context) (map[sdk.DataSourceType]int64, error) {
+		return map[sdk.DataSourceType]int64{"postgresql": 2}, nil
+	})
+}
+
+func TestMiddleware_RecordsRouteTemplates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c := newTestCollector()
+	r := gin.New()
+	r.Use(c.Middleware())
+	r.GET("/datasources/:uid", func(ctx *gin.Context) { ctx.Status(http.StatusNotFound) })
+
+	for _, path := range []string{"/datasources/abc", "/datasources/def", "/unknown"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	report := c.Snapshot(context.Background())
+	assert.Equal(t, map[string]int64{"GET /datasources/:uid": 2}, report.Features)
+	assert.Equal(t, map[string]int64{"http_4xx": 2}, report.Errors)
+	assert.Equal(t, map[string]int64{"postgresql": 2}, report.DatasourceTypes)
+	assert.Len(t, report.InstanceID, 32)
+}
+
+func TestReporter_SendResetsCounters(t *testing.T) {
+	var got Report
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	c := newTestCollector()
+	c.Feature("seed")
+	c.Error("http_5xx")
+
+	require.NoError(t, NewReporter(c, srv.URL, time.Hour).Send(context.Background()))
+	assert.Equal(t, int64(1), got.Features["seed"])
+	assert.Equal(t, int64(1), got.Errors["http_5xx"])
+
+	after := c.Snapshot(context.Background())
+	assert.Empty(t, after.Features)
+	assert.Empty(t, after.Errors)
+}
+
+func TestReporter_FailureKeepsCounters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := newTestCollector()
+	c.Feature("seed")
+	require.Error(t, NewReporter(c, srv.URL, time.Hour).Send(context.Background()))
+	assert.Equal(t, int64(1), c.Snapshot(context.Background()).Features["seed"])
+}
@@ -0,0 +1,378 @@
+// Package transform reshapes query results server-side, for backends whose
+// SQL lacks convenient PIVOT/UNPIVOT syntax.
+package transform
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"data-voyager/sdk"
+)
+
+// Type selects a transformation.
+type Type string
+
+const (
+	TypePivot   Type = "pivot"
+	TypeUnpivot Type = "unpivot"
+)
+
+// Aggregate combines the values that land in one pivot cell.
+type Aggregate string
+
+const (
+	AggSum   Aggregate = "sum"
+	AggAvg   Aggregate = "avg"
+	AggMin   Aggregate = "min"
+	AggMax   Aggregate = "max"
+	AggCount Aggregate = "count"
+	AggFirst Aggregate = "first"
+)
+
+// Spec is one step of a transformation pipeline. Exactly the field matching
+// Type must be set.
+type Spec struct {
+	Type    Type     `json:"type"`
+	Pivot   *Pivot   `json:"pivot,omitempty"`
+	Unpivot *Unpivot `json:"unpivot,omitempty"`
+}
+
+// Pivot turns the distinct values of Column into columns. Rows sharing the
+// same RowFields values collapse into one, and each cell aggregates Value
+// over the rows of that group and category.
+type Pivot struct {
+	RowFields []string  `json:"row_fields,omitempty"`
+	Column    string    `json:"column"`
+	Value     string    `json:"value"`
+	Aggregate Aggregate `json:"aggregate,omitempty"` // defaults to sum
+}
+
+// Unpivot turns Columns into rows of (NameField, ValueField) pairs, repeating
+// the Keep fields on each. An empty Columns melts every field not in Keep.
+type Unpivot struct {
+	Keep       []string `json:"keep,omitempty"`
+	Columns    []string `json:"columns,omitempty"`
+	NameField  string   `json:"name_field,omitempty"`  // defaults to "name"
+	ValueField string   `json:"value_field,omitempty"` // defaults to "value"
+}
+
+// Validate checks the spec without needing a frame.
+func (s Spec) Validate() error {
+	switch s.Type {
+	case TypePivot:
+		if s.Pivot == nil {
+			return fmt.Errorf("pivot transform needs pivot settings")
+		}
+		if s.Pivot.Column == "" || s.Pivot.Value == "" {
+			return fmt.Errorf("pivot needs a column and a value field")
+		}
+		switch s.Pivot.Aggregate {
+		case "", AggSum, AggAvg, AggMin, AggMax, AggCount, AggFirst:
+		default:
+			return fmt.Errorf("unknown pivot aggregate %q", s.Pivot.Aggregate)
+		}
+	case TypeUnpivot:
+		if s.Unpivot == nil {
+			return fmt.Errorf("unpivot transform needs unpivot settings")
+		}
+	default:
+		return fmt.Errorf("unknown transform type %q", s.Type)
+	}
+	return nil
+}
+
+// ApplyResult runs specs, in order, on every frame of res.
+func ApplyResult(res *sdk.QueryResult, specs []Spec) error {
+	if len(specs) == 0 {
+		return nil
+	}
+	for i, f := range res.Frames {
+		out, err := Apply(f, specs)
+		if err != nil {
+			return err
+		}
+		res.Frames[i] = out
+	}
+	return nil
+}
+
+// Apply runs specs, in order, on frame and returns the reshaped frame.
+func Apply(frame *sdk.DataFrame, specs []Spec) (*sdk.DataFrame, error) {
+	for i, s := range specs {
+		if err := s.Validate(); err != nil {
+			return nil, fmt.Errorf("transform %d: %w", i, err)
+		}
+		var err error
+		switch s.Type {
+		case TypePivot:
+			frame, err = pivot(frame, *s.Pivot)
+		case TypeUnpivot:
+			frame, err = unpivot(frame, *s.Unpivot)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("transform %d (%s): %w", i, s.Type, err)
+		}
+	}
+	return frame, nil
+}
+
+func pivot(f *sdk.DataFrame, p Pivot) (*sdk.DataFrame, error) {
+	idx, err := fieldIndex(f)
+	if err != nil {
+		return nil, err
+	}
+	lookup := func(name string) (*sdk.Field, error) {
+		i, ok := idx[name]
+		if !ok {
+			return nil, fmt.Errorf("field %q not found", name)
+		}
+		return &f.Fields[i], nil
+	}
+	rowFields := make([]*sdk.Field, len(p.RowFields))
+	for i, name := range p.RowFields {
+		if rowFields[i], err = lookup(name); err != nil {
+			return nil, err
+		}
+	}
+	catField, err := lookup(p.Column)
+	if err != nil {
+		return nil, err
+	}
+	valField, err := lookup(p.Value)
+	if err != nil {
+		return nil, err
+	}
+	agg := p.Aggregate
+	if agg == "" {
+		agg = AggSum
+	}
+
+	// Groups and categories keep first-appearance order so the output follows
+	// the query's ORDER BY.
+	var firstRow []int
+	var cats []string
+	groupRow := map[string]int{}
+	catIdx := map[string]int{}
+	cells := map[[2]int]*cell{}
+
+	for r := 0; r < frameLen(f); r++ {
+		gk := rowKey(rowFields, r)
+		g, ok := groupRow[gk]
+		if !ok {
+			g = len(firstRow)
+			groupRow[gk] = g
+			firstRow = append(firstRow, r)
+		}
+		name := valueName(catField.Values[r])
+		c, ok := catIdx[name]
+		if !ok {
+			c = len(cats)
+			catIdx[name] = c
+			cats = append(cats, name)
+		}
+		key := [2]int{g, c}
+		if cells[key] == nil {
+			cells[key] = &cell{}
+		}
+		if err := cells[key].add(agg, valField.Values[r]); err != nil {
+			return nil, fmt.Errorf("field %q row %d: %w", p.Value, r, err)
+		}
+	}
+
+	out := &sdk.DataFrame{Name: f.Name, FrameType: f.FrameType}
+	for _, rf := range rowFields {
+		nf := sdk.Field{Name: rf.Name, Kind: rf.Kind, Type: rf.Type, Values: make([]any, len(firstRow))}
+		for g, r := range firstRow {
+			nf.Values[g] = rf.Values[r]
+		}
+		out.Fields = append(out.Fields, nf)
+	}
+	kind, typ := sdk.FieldKindNumber, ""
+	if agg == AggFirst {
+		kind, typ = valField.Kind, valField.Type
+	}
+	for c, name := range cats {
+		if slices.Contains(p.RowFields, name) {
+			return nil, fmt.Errorf("category %q collides with a row field", name)
+		}
+		nf := sdk.Field{Name: name, Kind: kind, Type: typ, Values: make([]any, len(firstRow))}
+		for g := range firstRow {
+			if cl := cells[[2]int{g, c}]; cl != nil {
+				nf.Values[g] = cl.result(agg)
+			}
+		}
+		out.Fields = append(out.Fields, nf)
+	}
+	return out, nil
+}
+
+func unpivot(f *sdk.DataFrame, u Unpivot) (*sdk.DataFrame, error) {
+	idx, err := fieldIndex(f)
+	if err != nil {
+		return nil, err
+	}
+	nameField, valueField := u.NameField, u.ValueField
+	if nameField == "" {
+		nameField = "name"
+	}
+	if valueField == "" {
+		valueField = "value"
+	}
+	keep := make([]int, len(u.Keep))
+	for i, name := range u.Keep {
+		j, ok := idx[name]
+		if !ok {
+			return nil, fmt.Errorf("field %q not found", name)
+		}
+		keep[i] = j
+	}
+	var melt []int
+	if len(u.Columns) == 0 {
+		for j, fd := range f.Fields {
+			if !slices.Contains(u.Keep, fd.Name) {
+				melt = append(melt, j)
+			}
+		}
+	} else {
+		for _, name := range u.Columns {
+			j, ok := idx[name]
+			if !ok {
+				return nil, fmt.Errorf("field %q not found", name)
+			}
+			melt = append(melt, j)
+		}
+	}
+	if len(melt) == 0 {
+		return nil, fmt.Errorf("no columns to unpivot")
+	}
+	for _, name := range []string{nameField, valueField} {
+		if slices.Contains(u.Keep, name) {
+			return nil, fmt.Errorf("output field %q collides with a kept field", name)
+		}
+	}
+
+	// The value column keeps the melted fields' kind only when they agree.
+	kind, typ := f.Fields[melt[0]].Kind, f.Fields[melt[0]].Type
+	for _, j := range melt[1:] {
+		if f.Fields[j].Kind != kind {
+			kind, typ = sdk.FieldKindString, ""
+			break
+		}
+		if f.Fields[j].Type != typ {
+			typ = ""
+		}
+	}
+
+	n := frameLen(f) * len(melt)
+	out := &sdk.DataFrame{Name: f.Name, FrameType: f.FrameType}
+	for _, j := range keep {
+		src := f.Fields[j]
+		out.Fields = append(out.Fields, sdk.Field{Name: src.Name, Kind: src.Kind, Type: src.Type, Values: make([]any, 0, n)})
+	}
+	names := sdk.Field{Name: nameField, Kind: sdk.FieldKindString, Values: make([]any, 0, n)}
+	values := sdk.Field{Name: valueField, Kind: kind, Type: typ, Values: make([]any, 0, n)}
+	for r := 0; r < frameLen(f); r++ {
+		for _, j := range melt {
+			for k, kj := range keep {
+				out.Fields[k].Values = append(out.Fields[k].Values, f.Fields[kj].Values[r])
+			}
+			names.Values = append(names.Values, f.Fields[j].Name)
+			values.Values = append(values.Values, f.Fields[j].Values[r])
+		}
+	}
+	out.Fields = append(out.Fields, names, values)
+	return out, nil
+}
+
+// cell accumulates the values of one pivot cell.
+type cell struct {
+	n        int
+	sum      float64
+	min, max float64
+	first    any
+}
+
+func (c *cell) add(agg Aggregate, v any) error {
+	if v == nil {
+		return nil
+	}
+	c.n++
+	if c.n == 1 {
+		c.first = v
+	}
+	switch agg {
+	case AggCount, AggFirst:
+		return nil
+	}
+	x, ok := sdk.Float64Value(v)
+	if !ok {
+		return fmt.Errorf("%v is not numeric", v)
+	}
+	if c.n == 1 {
+		c.min, c.max = x, x
+	}
+	c.sum += x
+	c.min = math.Min(c.min, x)
+	c.max = math.Max(c.max, x)
+	return nil
+}
+
+func (c *cell) result(agg Aggregate) any {
+	if agg == AggCount {
+		return c.n
+	}
+	if c.n == 0 {
+		return nil
+	}
+	switch agg {
+	case AggAvg:
+		return c.sum / float64(c.n)
+	case AggMin:
+		return c.min
+	case AggMax:
+		return c.max
+	case AggFirst:
+		return c.first
+	}
+	return c.sum
+}
+
+func fieldIndex(f *sdk.DataFrame) (map[string]int, error) {
+	idx := make(map[string]int, len(f.Fields))
+	for i, fd := range f.Fields {
+		if _, dup := idx[fd.Name]; dup {
+			return nil, fmt.Errorf("duplicate field %q; alias it in the query", fd.Name)
+		}
+		idx[fd.Name] = i
+	}
+	return idx, nil
+}
+
+func frameLen(f *sdk.DataFrame) int {
+	if len(f.Fields) == 0 {
+		return 0
+	}
+	return len(f.Fields[0].Values)
+}
+
+// rowKey identifies the group of row r by the values of fields.
+func rowKey(fields []*sdk.Field, r int) string {
+	parts := make([]string, len(fields))
+	for i, fd := range fields {
+		parts[i] = fmt.Sprintf("%T:%v", fd.Values[r], fd.Values[r])
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// valueName renders a category value as a column name.
+func valueName(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case []byte:
+		return string(x)
+	}
+	return fmt.Sprint(v)
+}
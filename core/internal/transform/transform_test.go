@@ -0,0 +1,114 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/sdk"
+)
+
+func salesFrame() *sdk.DataFrame {
+	return &sdk.DataFrame{
+		FrameType: sdk.FrameTypeTable,
+		Fields: []sdk.Field{
+			{Name: "month", Kind: sdk.FieldKindString, Values: []any{"jan", "jan", "jan", "feb", "feb"}},
+			{Name: "region", Kind: sdk.FieldKindString, Values: []any{"eu", "us", "eu", "us", nil}},
+			{Name: "amount", Kind: sdk.FieldKindNumber, Type: "NUMERIC", Values: []any{int64(10), "2.5", 5.0, int32(7), 1}},
+		},
+	}
+}
+
+func values(f *sdk.DataFrame) map[string][]any {
+	m := map[string][]any{}
+	for _, fd := range f.Fields {
+		m[fd.Name] = fd.Values
+	}
+	return m
+}
+
+func TestPivot_Sum(t *testing.T) {
+	out, err := Apply(salesFrame(), []Spec{{
+		Type:  TypePivot,
+		Pivot: &Pivot{RowFields: []string{"month"}, Column: "region", Value: "amount"},
+	}})
+	require.NoError(t, err)
+
+	names := make([]string, len(out.Fields))
+	for i, f := range out.Fields {
+		names[i] = f.Name
+	}
+	assert.Equal(t, []string{"month", "eu", "us", "null"}, names, "categories keep first-appearance order")
+	v := values(out)
+	assert.Equal(t, []any{"jan", "feb"}, v["month"])
+	assert.Equal(t, []any{15.0, nil}, v["eu"])
+	assert.Equal(t, []any{2.5, 7.0}, v["us"])
+	assert.Equal(t, []any{nil, 1.0}, v["null"])
+	assert.Equal(t, sdk.FieldKindNumber, out.Fields[1].Kind)
+}
+
+func TestPivot_CountAndFirst(t *testing.T) {
+	out, err := Apply(salesFrame(), []Spec{{
+		Type:  TypePivot,
+		Pivot: &Pivot{RowFields: []string{"month"}, Column: "region", Value: "amount", Aggregate: AggCount},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []any{2, nil}, values(out)["eu"])
+
+	out, err = Apply(salesFrame(), []Spec{{
+		Type:  TypePivot,
+		Pivot: &Pivot{Column: "region", Value: "amount", Aggregate: AggFirst},
+	}})
+	require.NoError(t, err)
+	require.Len(t, out.Fields, 3, "no row fields collapses everything into one row")
+	assert.Equal(t, []any{int64(10)}, values(out)["eu"])
+	assert.Equal(t, "NUMERIC", out.Fields[0].Type, "first keeps the value field's type")
+}
+
+func TestPivot_Errors(t *testing.T) {
+	_, err := Apply(salesFrame(), []Spec{{Type: TypePivot, Pivot: &Pivot{Column: "nope", Value: "amount"}}})
+	assert.ErrorContains(t, err, `field "nope" not found`)
+
+	_, err = Apply(salesFrame(), []Spec{{Type: TypePivot, Pivot: &Pivot{Column: "month", Value: "region"}}})
+	assert.ErrorContains(t, err, "not numeric")
+
+	_, err = Apply(salesFrame(), []Spec{{Type: TypePivot, Pivot: &Pivot{Column: "region", Value: "amount", Aggregate: "median"}}})
+	assert.ErrorContains(t, err, "unknown pivot aggregate")
+}
+
+func TestUnpivot(t *testing.T) {
+	wide := &sdk.DataFrame{Fields: []sdk.Field{
+		{Name: "month", Kind: sdk.FieldKindString, Values: []any{"jan", "feb"}},
+		{Name: "eu", Kind: sdk.FieldKindNumber, Values: []any{1, 2}},
+		{Name: "us", Kind: sdk.FieldKindNumber, Values: []any{3, nil}},
+	}}
+	out, err := Apply(wide, []Spec{{Type: TypeUnpivot, Unpivot: &Unpivot{Keep: []string{"month"}, NameField: "region"}}})
+	require.NoError(t, err)
+
+	v := values(out)
+	assert.Equal(t, []any{"jan", "jan", "feb", "feb"}, v["month"])
+	assert.Equal(t, []any{"eu", "us", "eu", "us"}, v["region"])
+	assert.Equal(t, []any{1, 3, 2, nil}, v["value"])
+	assert.Equal(t, sdk.FieldKindNumber, out.Fields[2].Kind)
+}
+
+func TestPivotThenUnpivotRoundTrips(t *testing.T) {
+	out, err := Apply(salesFrame(), []Spec{
+		{Type: TypePivot, Pivot: &Pivot{RowFields: []string{"month"}, Column: "region", Value: "amount"}},
+		{Type: TypeUnpivot, Unpivot: &Unpivot{Keep: []string{"month"}, Columns: []string{"eu", "us"}}},
+	})
+	require.NoError(t, err)
+	v := values(out)
+	assert.Equal(t, []any{"eu", "us", "eu", "us"}, v["name"])
+	assert.Equal(t, []any{15.0, 2.5, nil, 7.0}, v["value"])
+}
+
+func TestApplyResult_AllFrames(t *testing.T) {
+	res := &sdk.QueryResult{Frames: []*sdk.DataFrame{salesFrame(), salesFrame()}}
+	err := ApplyResult(res, []Spec{{Type: TypeUnpivot, Unpivot: &Unpivot{Keep: []string{"month", "region"}}}})
+	require.NoError(t, err)
+	for _, f := range res.Frames {
+		assert.Len(t, f.Fields, 4)
+	}
+}
@@ -0,0 +1,86 @@
+package upgrade
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/user"
+)
+
+// Handler serves the update status, check and apply routes.
+type Handler struct {
+	svc        *Service
+	allowApply bool
+	// executable returns the path of the binary Apply replaces.
+	executable func() (string, error)
+}
+
+// NewHandler creates an upgrade HTTP handler. allowApply enables replacing
+// the binary from the API.
+func NewHandler(svc *Service, allowApply bool) *Handler {
+	return &Handler{svc: svc, allowApply: allowApply, executable: Executable}
+}
+
+// Executable returns the path of the running binary with symlinks resolved.
+func Executable() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(exe)
+}
+
+// Status handles GET /admin/upgrade. It returns the last check without
+// contacting GitHub.
+func (h *Handler) Status(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.svc.Status()})
+}
+
+// Check handles POST /admin/upgrade/check
+func (h *Handler) Check(c *gin.Context) {
+	st, _, err := h.svc.Check(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": st})
+}
+
+// Apply handles POST /admin/upgrade/apply. The new binary is used from the
+// next restart.
+func (h *Handler) Apply(c *gin.Context) {
+	if !h.allowApply {
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "upgrading from the API is disabled")})
+		return
+	}
+	exe, err := h.executable()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	applied, err := h.svc.Apply(c.Request.Context(), exe)
+	switch {
+	case errors.Is(err, ErrUpToDate):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrNoAsset):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, gin.H{"data": applied})
+	}
+}
+
+// RegisterRoutes wires the handler onto r. Every route requires the admin
+// permission.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	admin := r.Group("/admin/upgrade", user.RequireAdmin)
+	admin.GET("", h.Status)
+	admin.POST("/check", h.Check)
+	admin.POST("/apply", h.Apply)
+}
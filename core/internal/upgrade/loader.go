@@ -0,0 +1,23 @@
+package upgrade
+
+import (
+	"github.com/gin-gonic/gin"
+
+	apploader "data-voyager/core/internal/app"
+)
+
+type loader struct {
+	handler *Handler
+}
+
+// NewLoader wires the upgrade routes. Periodic checks are started
+// separately with Service.Schedule so they share the server's lifetime.
+func NewLoader(svc *Service, allowApply bool) apploader.Loader {
+	return &loader{handler: NewHandler(svc, allowApply)}
+}
+
+func (l *loader) Load() error { return nil }
+
+func (l *loader) RegisterRoutes(r *gin.RouterGroup) {
+	RegisterRoutes(r, l.handler)
+}
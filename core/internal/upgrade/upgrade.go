@@ -0,0 +1,384 @@
+// Package upgrade checks GitHub releases for a newer server and replaces
+// the running binary with a verified download.
+//
+// A release carries one raw binary per platform, named as AssetName
+// returns, and a checksums.txt in sha256sum format. When a public key is
+// configured, checksums.txt.sig must hold the base64 Ed25519 signature of
+// checksums.txt.
+package upgrade
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"data-voyager/core/internal/config"
+)
+
+// Release asset names besides the binaries.
+const (
+	ChecksumsAsset = "checksums.txt"
+	SignatureAsset = "checksums.txt.sig"
+)
+
+const (
+	// maxChecksums bounds checksums.txt and its signature.
+	maxChecksums = 1 << 20
+	// maxBinary bounds a downloaded binary.
+	maxBinary = 512 << 20
+)
+
+var (
+	// ErrUpToDate is returned by Apply when no newer release exists.
+	ErrUpToDate = errors.New("already running the latest release")
+	// ErrNoAsset is returned when a release has no binary for this platform.
+	ErrNoAsset = errors.New("release has no binary for this platform")
+	// ErrVerify wraps checksum and signature failures.
+	ErrVerify = errors.New("release verification failed")
+)
+
+// AssetName is the name of the release binary for a platform, e.g.
+// data-voyager_linux_amd64.
+func AssetName(goos, goarch string) string {
+	name := "data-voyager_" + goos + "_" + goarch
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Release is a published server release.
+type Release struct {
+	Version     string    `json:"version"`
+	URL         string    `json:"url"`
+	PublishedAt time.Time `json:"published_at"`
+	assets      map[string]string
+}
+
+// Status is the outcome of the last check.
+type Status struct {
+	Current         string     `json:"current"`
+	Latest          string     `json:"latest,omitempty"`
+	UpdateAvailable bool       `json:"update_available"`
+	ReleaseURL      string     `json:"release_url,omitempty"`
+	CheckedAt       *time.Time `json:"checked_at,omitempty"`
+	Error           string     `json:"error,omitempty"`
+}
+
+// Applied describes a binary replaced by Apply.
+type Applied struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Path string `json:"path"`
+	// Signed is false when no public key is configured and only the
+	// checksum was verified.
+	Signed bool `json:"signed"`
+}
+
+// Service checks for releases and applies them.
+type Service struct {
+	cfg       config.UpdatesConfig
+	current   string
+	publicKey ed25519.PublicKey
+	client    *http.Client
+	now       func() time.Time
+
+	mu     sync.RWMutex
+	status Status
+}
+
+// NewService creates a Service for a server running version current.
+func NewService(cfg config.UpdatesConfig, current string) (*Service, error) {
+	if cfg.APIURL == "" {
+		cfg.APIURL = "https://api.github.com"
+	}
+	if cfg.Repository == "" {
+		cfg.Repository = "loykin/data-voyager"
+	}
+	s := &Service{
+		cfg:     cfg,
+		current: current,
+		client:  &http.Client{Timeout: 5 * time.Minute},
+		now:     time.Now,
+		status:  Status{Current: current},
+	}
+	if cfg.PublicKey != "" {
+		key, err := base64.StdEncoding.DecodeString(cfg.PublicKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("updates.public_key must be a base64 Ed25519 public key")
+		}
+		s.publicKey = key
+	}
+	return s, nil
+}
+
+// Signed reports whether releases must carry a valid signature.
+func (s *Service) Signed() bool { return s.publicKey != nil }
+
+// Status returns the outcome of the last check.
+func (s *Service) Status() Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status
+}
+
+// Available returns the newer version the last check found, if any.
+func (s *Service) Available() (string, bool) {
+	st := s.Status()
+	return st.Latest, st.UpdateAvailable
+}
+
+// Check fetches the latest release and records the outcome.
+func (s *Service) Check(ctx context.Context) (Status, *Release, error) {
+	rel, err := s.latest(ctx)
+	now := s.now().UTC()
+	st := Status{Current: s.current, CheckedAt: &now}
+	if err != nil {
+		st.Error = err.Error()
+	} else {
+		st.Latest = rel.Version
+		st.ReleaseURL = rel.URL
+		st.UpdateAvailable = Newer(rel.Version, s.current)
+	}
+	s.mu.Lock()
+	s.status = st
+	s.mu.Unlock()
+	return st, rel, err
+}
+
+// Schedule checks now and then every interval until ctx is done. A newer
+// release is logged once per version.
+func (s *Service) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	announced := ""
+	for {
+		st, _, err := s.Check(ctx)
+		switch {
+		case err != nil:
+			slog.Debug("update check failed", "err", err)
+		case st.UpdateAvailable && st.Latest != announced:
+			announced = st.Latest
+			slog.Info("a newer release is available", "current", s.current, "latest", st.Latest, "url", st.ReleaseURL)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Apply downloads the latest release for this platform, verifies it and
+// replaces the binary at exe. The running process keeps the old code until
+// it is restarted.
+func (s *Service) Apply(ctx context.Context, exe string) (*Applied, error) {
+	st, rel, err := s.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !st.UpdateAvailable {
+		return nil, ErrUpToDate
+	}
+	tmp, err := s.Download(ctx, rel, filepath.Dir(exe), AssetName(runtime.GOOS, runtime.GOARCH))
+	if err != nil {
+		return nil, err
+	}
+	if err := replace(exe, tmp); err != nil {
+		_ = os.Remove(tmp)
+		return nil, err
+	}
+	return &Applied{From: s.current, To: rel.Version, Path: exe, Signed: s.Signed()}, nil
+}
+
+// Download fetches asset from rel into a temporary file in dir, verifies it
+// against the release checksums and returns its path. The caller removes or
+// renames the file.
+func (s *Service) Download(ctx context.Context, rel *Release, dir, asset string) (string, error) {
+	url, ok := rel.assets[asset]
+	if !ok {
+		return "", fmt.Errorf("%w: %s %s has no %s", ErrNoAsset, s.cfg.Repository, rel.Version, asset)
+	}
+	sums, err := s.checksums(ctx, rel)
+	if err != nil {
+		return "", err
+	}
+	want, ok := sums[asset]
+	if !ok {
+		return "", fmt.Errorf("%w: %s does not list %s", ErrVerify, ChecksumsAsset, asset)
+	}
+
+	f, err := os.CreateTemp(dir, ".data-voyager-upgrade-*")
+	if err != nil {
+		return "", err
+	}
+	path := f.Name()
+	fail := func(err error) (string, error) {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return "", err
+	}
+	body, err := s.get(ctx, url)
+	if err != nil {
+		return fail(err)
+	}
+	defer func() { _ = body.Close() }()
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, h), io.LimitReader(body, maxBinary+1))
+	if err != nil {
+		return fail(fmt.Errorf("download %s: %w", asset, err))
+	}
+	if n > maxBinary {
+		return fail(fmt.Errorf("download %s: larger than %d bytes", asset, maxBinary))
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		return fail(fmt.Errorf("%w: %s has checksum %s, expected %s", ErrVerify, asset, got, want))
+	}
+	if err := f.Chmod(0o755); err != nil {
+		return fail(err)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// checksums downloads checksums.txt, checks its signature when a public
+// key is configured and returns the hex SHA-256 of each listed file.
+func (s *Service) checksums(ctx context.Context, rel *Release) (map[string]string, error) {
+	url, ok := rel.assets[ChecksumsAsset]
+	if !ok {
+		return nil, fmt.Errorf("%w: release %s has no %s", ErrVerify, rel.Version, ChecksumsAsset)
+	}
+	raw, err := s.fetch(ctx, url, maxChecksums)
+	if err != nil {
+		return nil, err
+	}
+	if s.publicKey != nil {
+		sigURL, ok := rel.assets[SignatureAsset]
+		if !ok {
+			return nil, fmt.Errorf("%w: release %s is not signed", ErrVerify, rel.Version)
+		}
+		encoded, err := s.fetch(ctx, sigURL, maxChecksums)
+		if err != nil {
+			return nil, err
+		}
+		sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+		if err != nil || !ed25519.Verify(s.publicKey, raw, sig) {
+			return nil, fmt.Errorf("%w: bad signature on %s", ErrVerify, ChecksumsAsset)
+		}
+	}
+	sums := map[string]string{}
+	sc := bufio.NewScanner(bytes.NewReader(raw))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	return sums, sc.Err()
+}
+
+// latest asks the GitHub API for the newest release that is neither a
+// draft nor a prerelease.
+func (s *Service) latest(ctx context.Context) (*Release, error) {
+	url := strings.TrimRight(s.cfg.APIURL, "/") + "/repos/" + s.cfg.Repository + "/releases/latest"
+	raw, err := s.fetch(ctx, url, maxChecksums)
+	if err != nil {
+		return nil, err
+	}
+	var gh struct {
+		TagName     string    `json:"tag_name"`
+		HTMLURL     string    `json:"html_url"`
+		PublishedAt time.Time `json:"published_at"`
+		Assets      []struct {
+			Name string `json:"name"`
+			URL  string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+	if err := json.Unmarshal(raw, &gh); err != nil {
+		return nil, fmt.Errorf("decode release: %w", err)
+	}
+	if gh.TagName == "" {
+		return nil, fmt.Errorf("release has no tag")
+	}
+	rel := &Release{
+		Version:     strings.TrimPrefix(gh.TagName, "v"),
+		URL:         gh.HTMLURL,
+		PublishedAt: gh.PublishedAt,
+		assets:      make(map[string]string, len(gh.Assets)),
+	}
+	for _, a := range gh.Assets {
+		rel.assets[a.Name] = a.URL
+	}
+	return rel, nil
+}
+
+func (s *Service) fetch(ctx context.Context, url string, limit int64) ([]byte, error) {
+	body, err := s.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+	return io.ReadAll(io.LimitReader(body, limit))
+}
+
+func (s *Service) get(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json, application/octet-stream")
+	req.Header.Set("User-Agent", "data-voyager/"+s.current)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// replace swaps exe for the file at next, keeping exe's permissions. The
+// old binary is moved aside first, which Windows requires for a running
+// executable, and put back if the swap fails.
+func replace(exe, next string) error {
+	if info, err := os.Stat(exe); err == nil {
+		if err := os.Chmod(next, info.Mode().Perm()|0o100); err != nil {
+			return err
+		}
+	}
+	old := exe + ".old"
+	_ = os.Remove(old)
+	if err := os.Rename(exe, old); err != nil {
+		return fmt.Errorf("move %s aside: %w", exe, err)
+	}
+	if err := os.Rename(next, exe); err != nil {
+		_ = os.Rename(old, exe)
+		return fmt.Errorf("install %s: %w", exe, err)
+	}
+	// A running executable cannot be removed on Windows; the next upgrade
+	// removes it instead.
+	_ = os.Remove(old)
+	return nil
+}
@@ -0,0 +1,242 @@
+package upgrade
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/identity"
+)
+
+// asset is the binary Apply downloads on this platform.
+var asset = AssetName(runtime.GOOS, runtime.GOARCH)
+
+// fakeRelease serves a GitHub API and release assets for tag, with binary
+// as this platform's asset. files overrides or adds assets.
+func fakeRelease(t *testing.T, tag string, binary []byte, files map[string][]byte) *httptest.Server {
+	t.Helper()
+	sum := sha256.Sum256(binary)
+	assets := map[string][]byte{
+		asset:          binary,
+		ChecksumsAsset: []byte(hex.EncodeToString(sum[:]) + "  " + asset + "\n"),
+	}
+	for name, b := range files {
+		if b == nil {
+			delete(assets, name)
+			continue
+		}
+		assets[name] = b
+	}
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	mux.HandleFunc("/repos/acme/voyager/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		var list []map[string]string
+		for name := range assets {
+			list = append(list, map[string]string{"name": name, "browser_download_url": srv.URL + "/download/" + name})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"tag_name": tag,
+			"html_url": "https://example.com/releases/" + tag,
+			"assets":   list,
+		})
+	})
+	mux.HandleFunc("/download/", func(w http.ResponseWriter, r *http.Request) {
+		b, ok := assets[filepath.Base(r.URL.Path)]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(b)
+	})
+	return srv
+}
+
+func newService(t *testing.T, srv *httptest.Server, current, publicKey string) *Service {
+	t.Helper()
+	svc, err := NewService(config.UpdatesConfig{APIURL: srv.URL, Repository: "acme/voyager", PublicKey: publicKey}, current)
+	require.NoError(t, err)
+	return svc
+}
+
+func TestNewer(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want bool
+	}{
+		{"0.2.0", "0.1.0", true},
+		{"v1.0.0", "0.9.9", true},
+		{"0.1.0", "0.1.0", false},
+		{"0.1.0", "0.2.0", false},
+		{"1.0", "0.9.1", true},
+		{"1.0.0", "1.0.0-rc.1", true},
+		{"1.0.0-rc.2", "1.0.0-rc.1", true},
+		{"1.0.0-rc.10", "1.0.0-rc.9", true},
+		{"1.0.0-rc.1", "1.0.0", false},
+		{"1.0.0+build.5", "1.0.0", false},
+		{"1.0.0", "dev", false},
+		{"nightly", "0.1.0", false},
+	} {
+		assert.Equal(t, tc.want, Newer(tc.a, tc.b), "%s > %s", tc.a, tc.b)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	srv := fakeRelease(t, "v0.2.0", []byte("new"), nil)
+	svc := newService(t, srv, "0.1.0", "")
+
+	_, ok := svc.Available()
+	assert.False(t, ok, "nothing is available before a check")
+
+	st, _, err := svc.Check(context.Background())
+	require.NoError(t, err)
+	assert.True(t, st.UpdateAvailable)
+	assert.Equal(t, "0.2.0", st.Latest)
+	assert.Equal(t, "https://example.com/releases/v0.2.0", st.ReleaseURL)
+	latest, ok := svc.Available()
+	assert.True(t, ok)
+	assert.Equal(t, "0.2.0", latest)
+
+	current := newService(t, srv, "0.2.0", "")
+	st, _, err = current.Check(context.Background())
+	require.NoError(t, err)
+	assert.False(t, st.UpdateAvailable)
+}
+
+func TestCheck_RecordsErrors(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	svc := newService(t, srv, "0.1.0", "")
+
+	_, _, err := svc.Check(context.Background())
+	require.Error(t, err)
+	st := svc.Status()
+	assert.NotEmpty(t, st.Error)
+	assert.NotNil(t, st.CheckedAt)
+	assert.False(t, st.UpdateAvailable)
+}
+
+func TestApply(t *testing.T) {
+	srv := fakeRelease(t, "v0.2.0", []byte("new binary"), nil)
+	svc := newService(t, srv, "0.1.0", "")
+	_, rel, err := svc.Check(context.Background())
+	require.NoError(t, err)
+
+	exe := filepath.Join(t.TempDir(), "data-voyager")
+	require.NoError(t, os.WriteFile(exe, []byte("old binary"), 0o750))
+	tmp, err := svc.Download(context.Background(), rel, filepath.Dir(exe), asset)
+	require.NoError(t, err)
+	require.NoError(t, replace(exe, tmp))
+
+	got, err := os.ReadFile(exe)
+	require.NoError(t, err)
+	assert.Equal(t, "new binary", string(got))
+	info, err := os.Stat(exe)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o750), info.Mode().Perm())
+	entries, err := os.ReadDir(filepath.Dir(exe))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary or old files are left behind")
+}
+
+func TestApply_UpToDate(t *testing.T) {
+	srv := fakeRelease(t, "v0.1.0", []byte("same"), nil)
+	svc := newService(t, srv, "0.1.0", "")
+	_, err := svc.Apply(context.Background(), filepath.Join(t.TempDir(), "data-voyager"))
+	assert.ErrorIs(t, err, ErrUpToDate)
+}
+
+func TestDownload_Verification(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	key := base64.StdEncoding.EncodeToString(pub)
+	sum := sha256.Sum256([]byte("new"))
+	checksums := []byte(hex.EncodeToString(sum[:]) + "  " + asset + "\n")
+	sign := func(b []byte) []byte { return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, b))) }
+
+	for _, tc := range []struct {
+		name      string
+		files     map[string][]byte
+		publicKey string
+		wantErr   error
+	}{
+		{name: "checksum only", files: nil},
+		{name: "signed", files: map[string][]byte{SignatureAsset: sign(checksums)}, publicKey: key},
+		{name: "tampered binary", files: map[string][]byte{asset: []byte("evil")}, wantErr: ErrVerify},
+		{name: "no checksums", files: map[string][]byte{ChecksumsAsset: nil}, wantErr: ErrVerify},
+		{name: "unlisted asset", files: map[string][]byte{ChecksumsAsset: []byte("abc  other\n")}, wantErr: ErrVerify},
+		{name: "unsigned", publicKey: key, wantErr: ErrVerify},
+		{name: "bad signature", files: map[string][]byte{SignatureAsset: sign([]byte("other"))}, publicKey: key, wantErr: ErrVerify},
+		{name: "no binary", files: map[string][]byte{asset: nil}, wantErr: ErrNoAsset},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := fakeRelease(t, "v0.2.0", []byte("new"), tc.files)
+			svc := newService(t, srv, "0.1.0", tc.publicKey)
+			_, rel, err := svc.Check(context.Background())
+			require.NoError(t, err)
+
+			dir := t.TempDir()
+			path, err := svc.Download(context.Background(), rel, dir, asset)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				entries, _ := os.ReadDir(dir)
+				assert.Empty(t, entries, "failed downloads are removed")
+				return
+			}
+			require.NoError(t, err)
+			got, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, "new", string(got))
+		})
+	}
+}
+
+func TestHandler_Apply(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	srv := fakeRelease(t, "v0.2.0", []byte("new"), nil)
+	exe := filepath.Join(t.TempDir(), "data-voyager")
+	require.NoError(t, os.WriteFile(exe, []byte("old"), 0o755))
+
+	do := func(allow bool, role string) *httptest.ResponseRecorder {
+		h := NewHandler(newService(t, srv, "0.1.0", ""), allow)
+		h.executable = func() (string, error) { return exe, nil }
+		r := gin.New()
+		r.Use(func(c *gin.Context) {
+			c.Request = c.Request.WithContext(identity.With(c.Request.Context(), &identity.Identity{Username: "u", Role: role}))
+		})
+		RegisterRoutes(r.Group("/api/v1"), h)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/admin/upgrade/apply", nil))
+		return w
+	}
+
+	assert.Equal(t, http.StatusForbidden, do(true, identity.RoleViewer).Code)
+	assert.Equal(t, http.StatusForbidden, do(false, identity.RoleAdmin).Code)
+	got, _ := os.ReadFile(exe)
+	assert.Equal(t, "old", string(got))
+
+	w := do(true, identity.RoleAdmin)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data Applied `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "0.2.0", resp.Data.To)
+	assert.False(t, resp.Data.Signed)
+	got, _ = os.ReadFile(exe)
+	assert.Equal(t, "new", string(got))
+}
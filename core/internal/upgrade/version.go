@@ -0,0 +1,84 @@
+package upgrade
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Newer reports whether version a is newer than b. Versions are semantic
+// versions with an optional leading "v"; a release is newer than its
+// prereleases. When either does not parse, as for a development build,
+// nothing is newer.
+func Newer(a, b string) bool {
+	va, ok := parseVersion(a)
+	if !ok {
+		return false
+	}
+	vb, ok := parseVersion(b)
+	if !ok {
+		return false
+	}
+	for i := range va.nums {
+		if va.nums[i] != vb.nums[i] {
+			return va.nums[i] > vb.nums[i]
+		}
+	}
+	switch {
+	case va.pre == vb.pre:
+		return false
+	case va.pre == "":
+		return true
+	case vb.pre == "":
+		return false
+	}
+	return comparePrerelease(va.pre, vb.pre) > 0
+}
+
+type version struct {
+	nums [3]int
+	pre  string
+}
+
+func parseVersion(s string) (version, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	s, _, _ = strings.Cut(s, "+")
+	s, pre, _ := strings.Cut(s, "-")
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return version{}, false
+	}
+	v := version{pre: pre}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return version{}, false
+		}
+		v.nums[i] = n
+	}
+	return v, true
+}
+
+// comparePrerelease orders dot-separated prerelease identifiers, numeric
+// ones numerically, as semver does.
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aerr := strconv.Atoi(as[i])
+		bn, berr := strconv.Atoi(bs[i])
+		switch {
+		case aerr == nil && berr == nil:
+			if an != bn {
+				return an - bn
+			}
+		case aerr == nil:
+			return -1
+		case berr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return len(as) - len(bs)
+}
@@ -0,0 +1,102 @@
+package usage
+
+import (
+	"encoding/csv"
+	"errors"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/user"
+)
+
+const defaultDays = 30
+
+// Handler serves the usage report.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates a usage HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// Report handles GET /admin/usage-report?days=30[&format=csv]
+func (h *Handler) Report(c *gin.Context) {
+	days := defaultDays
+	if v := c.Query("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be an integer"})
+			return
+		}
+		days = n
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or csv"})
+		return
+	}
+
+	report, err := h.svc.Report(c.Request.Context(), days)
+	if err != nil {
+		if errors.Is(err, ErrInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if format == "json" {
+		c.JSON(http.StatusOK, gin.H{"data": report})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="usage-report-`+report.To+`.csv"`)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+	writeCSV(csv.NewWriter(c.Writer), report)
+}
+
+// writeCSV flattens the datasource and table lists into one sheet: a row per
+// datasource with an empty table column, then a row per table. Bytes and
+// cost are known per datasource only.
+func writeCSV(w *csv.Writer, r *Report) {
+	_ = w.Write([]string{"datasource_id", "datasource", "type", "table", "queries", "users", "last_used", "unused",
+		"bytes_read", "cost"})
+	for _, d := range r.Datasources {
+		_ = w.Write([]string{d.ID, d.Name, d.Type, "", strconv.FormatInt(d.Queries, 10),
+			strconv.Itoa(d.Users), d.LastUsed, strconv.FormatBool(d.Unused),
+			strconv.FormatInt(d.BytesRead, 10), formatCost(d.Cost)})
+	}
+	types := make(map[string]string, len(r.Datasources))
+	for _, d := range r.Datasources {
+		types[d.ID] = d.Type
+	}
+	for _, t := range r.Tables {
+		_ = w.Write([]string{t.DatasourceID, t.DatasourceName, types[t.DatasourceID], t.Table,
+			strconv.FormatInt(t.Queries, 10), strconv.Itoa(t.Users), t.LastUsed, strconv.FormatBool(t.Unused), "", ""})
+	}
+	w.Flush()
+}
+
+// formatCost writes costs as e.g. "12.50 USD", one per currency in
+// currency order.
+func formatCost(costs map[string]float64) string {
+	parts := make([]string, 0, len(costs))
+	for _, currency := range slices.Sorted(maps.Keys(costs)) {
+		parts = append(parts, strconv.FormatFloat(costs[currency], 'f', 2, 64)+" "+currency)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// RegisterRoutes wires the handler onto r.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	admin := r.Group("/admin", user.RequireAdmin)
+	admin.GET("/usage-report", h.Report)
+}
@@ -0,0 +1,63 @@
+// Package usage records which datasources and tables are queried, and by
+// whom, and reports it so unused sources can be found and decommissioned.
+package usage
+
+import (
+	"context"
+	"time"
+)
+
+// DayFormat is how Event.Day and the report's days are written. Storing the
+// day as text keeps per-day grouping portable across statistics stores.
+const DayFormat = "2006-01-02"
+
+// Event is one query against one datasource. A query is recorded once with
+// an empty TableName, counting towards the datasource, and once more for
+// each table it reads. BytesRead, Cost and Currency are set on the
+// datasource event only, so summing them never counts a query twice;
+// Currency is empty for a datasource without a cost model.
+type Event struct {
+	ID             string    `db:"id"`
+	DatasourceID   string    `db:"datasource_id"`
+	DatasourceName string    `db:"datasource_name"`
+	DatasourceType string    `db:"datasource_type"`
+	TableName      string    `db:"table_name"`
+	Username       string    `db:"username"`
+	Day            string    `db:"day"`
+	UsedAt         time.Time `db:"used_at"`
+	BytesRead      int64     `db:"bytes_read"`
+	Cost           float64   `db:"cost"`
+	Currency       string    `db:"currency"`
+}
+
+// Count is the number of events sharing a day, datasource, table, user and
+// currency, with the bytes they read and what they cost.
+type Count struct {
+	Day            string  `db:"day"`
+	DatasourceID   string  `db:"datasource_id"`
+	DatasourceName string  `db:"datasource_name"`
+	TableName      string  `db:"table_name"`
+	Username       string  `db:"username"`
+	Currency       string  `db:"currency"`
+	Queries        int64   `db:"queries"`
+	BytesRead      int64   `db:"bytes_read"`
+	Cost           float64 `db:"cost"`
+}
+
+// LastUse is the latest day a datasource, or one of its tables, was queried.
+type LastUse struct {
+	DatasourceID   string `db:"datasource_id"`
+	DatasourceName string `db:"datasource_name"`
+	TableName      string `db:"table_name"`
+	Day            string `db:"day"`
+}
+
+// Repository persists usage events in the statistics store.
+type Repository interface {
+	Record(ctx context.Context, events []*Event) error
+	// Counts aggregates events from day since (inclusive) onwards.
+	Counts(ctx context.Context, since string) ([]*Count, error)
+	// LastUsed returns the latest day of every datasource and table ever
+	// recorded.
+	LastUsed(ctx context.Context) ([]*LastUse, error)
+}
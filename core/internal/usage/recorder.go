@@ -0,0 +1,121 @@
+package usage
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/sqllint"
+	"data-voyager/sdk"
+)
+
+const (
+	recorderBuffer = 4096
+	flushSize      = 200
+	flushInterval  = 5 * time.Second
+)
+
+// Recorder implements connection.UsageRecorder. Events are queued and
+// written in batches by Run so that recording never delays a query; when
+// the queue is full, events are dropped and counted.
+type Recorder struct {
+	repo    Repository
+	events  chan *Event
+	dropped atomic.Int64
+	now     func() time.Time
+}
+
+var _ connection.UsageRecorder = (*Recorder)(nil)
+
+// NewRecorder creates a Recorder writing to repo. Nothing is written until
+// Run is started.
+func NewRecorder(repo Repository) *Recorder {
+	return &Recorder{repo: repo, events: make(chan *Event, recorderBuffer), now: time.Now}
+}
+
+// RecordQuery queues one event for the datasource, carrying what the query
+// read and cost, and one per table query reads.
+func (r *Recorder) RecordQuery(ctx context.Context, conn *connection.Connection, query string, stats sdk.QueryStats) {
+	var username string
+	if id := identity.FromContext(ctx); id != nil {
+		username = id.Username
+	}
+	at := r.now().UTC()
+	base := Event{
+		DatasourceID:   conn.ID,
+		DatasourceName: conn.Name,
+		DatasourceType: string(conn.Type),
+		Username:       username,
+		Day:            at.Format(DayFormat),
+		UsedAt:         at,
+	}
+	ds := base
+	ds.BytesRead = stats.BytesRead
+	if m := conn.CostModel; m != nil {
+		ds.Cost, ds.Currency = m.Cost(stats.BytesRead), m.CurrencyOrDefault()
+	}
+	r.enqueue(ds)
+	for _, table := range sqllint.Tables(query) {
+		e := base
+		e.TableName = table
+		r.enqueue(e)
+	}
+}
+
+func (r *Recorder) enqueue(e Event) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return
+	}
+	e.ID = id.String()
+	select {
+	case r.events <- &e:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// Run writes queued events until ctx is cancelled, then flushes what is
+// left.
+func (r *Recorder) Run(ctx context.Context) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	batch := make([]*Event, 0, flushSize)
+	flush := func(ctx context.Context) {
+		if n := r.dropped.Swap(0); n > 0 {
+			slog.Warn("usage events dropped: queue full", "count", n)
+		}
+		if len(batch) == 0 {
+			return
+		}
+		if err := r.repo.Record(ctx, batch); err != nil {
+			slog.Warn("failed to record usage events", "count", len(batch), "err", err)
+		}
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case e := <-r.events:
+			batch = append(batch, e)
+			if len(batch) >= flushSize {
+				flush(ctx)
+			}
+		case <-ticker.C:
+			flush(ctx)
+		case <-ctx.Done():
+			// Run is the only receiver, so the queue cannot shrink under us.
+			for len(r.events) > 0 {
+				batch = append(batch, <-r.events)
+			}
+			done, cancel := context.WithTimeout(context.Background(), flushInterval)
+			flush(done)
+			cancel()
+			return
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package usage
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"data-voyager/core/internal/connection"
+)
+
+// MaxDays bounds the report window.
+const MaxDays = 366
+
+// ErrInvalid is returned for report options out of range.
+var ErrInvalid = errors.New("invalid usage report options")
+
+// Report summarises usage over a window of whole days ending today (UTC).
+type Report struct {
+	From        string            `json:"from"`
+	To          string            `json:"to"`
+	Datasources []DatasourceUsage `json:"datasources"`
+	Tables      []TableUsage      `json:"tables"`
+	Users       []UserUsage       `json:"users"`
+	// Daily holds per-day query counts for each datasource: the heatmap.
+	Daily []DailyUsage `json:"daily"`
+}
+
+// DatasourceUsage is one datasource's usage in the window. Unused marks a
+// datasource with no queries in the window; LastUsed is empty when it has
+// never been queried since usage was first recorded. Cost totals queries
+// run under a cost model by currency, so it is empty for a datasource that
+// never had one.
+type DatasourceUsage struct {
+	ID        string             `json:"id"`
+	Name      string             `json:"name"`
+	Type      string             `json:"type"`
+	Queries   int64              `json:"queries"`
+	Users     int                `json:"users"`
+	BytesRead int64              `json:"bytes_read"`
+	Cost      map[string]float64 `json:"cost,omitempty"`
+	LastUsed  string             `json:"last_used,omitempty"`
+	Unused    bool               `json:"unused"`
+}
+
+// TableUsage is one table's usage in the window. Tables are known only once
+// queried, so Unused marks a table last read before the window.
+type TableUsage struct {
+	DatasourceID   string `json:"datasource_id"`
+	DatasourceName string `json:"datasource_name"`
+	Table          string `json:"table"`
+	Queries        int64  `json:"queries"`
+	Users          int    `json:"users"`
+	LastUsed       string `json:"last_used"`
+	Unused         bool   `json:"unused"`
+}
+
+// UserUsage is how much one user queried in the window, and what it cost
+// by currency.
+type UserUsage struct {
+	User        string             `json:"user"`
+	Queries     int64              `json:"queries"`
+	Datasources int                `json:"datasources"`
+	BytesRead   int64              `json:"bytes_read"`
+	Cost        map[string]float64 `json:"cost,omitempty"`
+}
+
+// DailyUsage is one heatmap cell.
+type DailyUsage struct {
+	Day          string `json:"day"`
+	DatasourceID string `json:"datasource_id"`
+	Queries      int64  `json:"queries"`
+}
+
+// Service builds usage reports.
+type Service struct {
+	repo  Repository
+	conns connection.Repository
+	now   func() time.Time
+}
+
+// NewService creates a usage Service.
+func NewService(repo Repository, conns connection.Repository) *Service {
+	return &Service{repo: repo, conns: conns, now: time.Now}
+}
+
+type tableKey struct{ datasource, table string }
+
+// Report covers the last days days, today included. Datasources are listed
+// whether or not they were used; deleted datasources are left out of the
+// datasource and table lists but still count towards users.
+func (s *Service) Report(ctx context.Context, days int) (*Report, error) {
+	if days < 1 || days > MaxDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", ErrInvalid, MaxDays)
+	}
+	today := s.now().UTC()
+	from := today.AddDate(0, 0, 1-days).Format(DayFormat)
+
+	conns, err := s.conns.List(ctx, connection.Filter{})
+	if err != nil {
+		return nil, fmt.Errorf("list datasources: %w", err)
+	}
+	counts, err := s.repo.Counts(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("read usage counts: %w", err)
+	}
+	lastUses, err := s.repo.LastUsed(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read last use: %w", err)
+	}
+
+	report := &Report{
+		From:        from,
+		To:          today.Format(DayFormat),
+		Datasources: []DatasourceUsage{},
+		Tables:      []TableUsage{},
+		Users:       []UserUsage{},
+		Daily:       []DailyUsage{},
+	}
+
+	byID := make(map[string]*DatasourceUsage, len(conns))
+	names := make(map[string]string, len(conns))
+	for _, c := range conns {
+		report.Datasources = append(report.Datasources, DatasourceUsage{ID: c.ID, Name: c.Name, Type: string(c.Type)})
+		names[c.ID] = c.Name
+	}
+	for i := range report.Datasources {
+		byID[report.Datasources[i].ID] = &report.Datasources[i]
+	}
+
+	tables := map[tableKey]*TableUsage{}
+	for _, lu := range lastUses {
+		if lu.TableName == "" {
+			if d := byID[lu.DatasourceID]; d != nil {
+				d.LastUsed = lu.Day
+			}
+			continue
+		}
+		if _, ok := names[lu.DatasourceID]; !ok {
+			continue
+		}
+		tables[tableKey{lu.DatasourceID, lu.TableName}] = &TableUsage{
+			DatasourceID:   lu.DatasourceID,
+			DatasourceName: names[lu.DatasourceID],
+			Table:          lu.TableName,
+			LastUsed:       lu.Day,
+		}
+	}
+
+	dsUsers := map[string]map[string]bool{}
+	tableUsers := map[tableKey]map[string]bool{}
+	users := map[string]*UserUsage{}
+	userDatasources := map[string]map[string]bool{}
+	daily := map[[2]string]int64{}
+	for _, c := range counts {
+		if c.TableName != "" {
+			k := tableKey{c.DatasourceID, c.TableName}
+			if t := tables[k]; t != nil {
+				t.Queries += c.Queries
+				addTo(tableUsers, k, c.Username)
+			}
+			continue
+		}
+		if d := byID[c.DatasourceID]; d != nil {
+			d.Queries += c.Queries
+			d.BytesRead += c.BytesRead
+			d.Cost = addCost(d.Cost, c)
+			addTo(dsUsers, c.DatasourceID, c.Username)
+			daily[[2]string{c.Day, c.DatasourceID}] += c.Queries
+		}
+		u := users[c.Username]
+		if u == nil {
+			u = &UserUsage{User: c.Username}
+			users[c.Username] = u
+		}
+		u.Queries += c.Queries
+		u.BytesRead += c.BytesRead
+		u.Cost = addCost(u.Cost, c)
+		addTo(userDatasources, c.Username, c.DatasourceID)
+	}
+
+	for i := range report.Datasources {
+		d := &report.Datasources[i]
+		d.Users = len(dsUsers[d.ID])
+		d.Unused = d.Queries == 0
+	}
+	for k, t := range tables {
+		t.Users = len(tableUsers[k])
+		t.Unused = t.Queries == 0
+		report.Tables = append(report.Tables, *t)
+	}
+	for name, u := range users {
+		u.Datasources = len(userDatasources[name])
+		report.Users = append(report.Users, *u)
+	}
+	for k, n := range daily {
+		report.Daily = append(report.Daily, DailyUsage{Day: k[0], DatasourceID: k[1], Queries: n})
+	}
+
+	slices.SortFunc(report.Datasources, func(a, b DatasourceUsage) int {
+		return cmp.Or(cmp.Compare(b.Queries, a.Queries), cmp.Compare(a.Name, b.Name))
+	})
+	slices.SortFunc(report.Tables, func(a, b TableUsage) int {
+		return cmp.Or(cmp.Compare(b.Queries, a.Queries), cmp.Compare(a.DatasourceName, b.DatasourceName), cmp.Compare(a.Table, b.Table))
+	})
+	slices.SortFunc(report.Users, func(a, b UserUsage) int {
+		return cmp.Or(cmp.Compare(b.Queries, a.Queries), cmp.Compare(a.User, b.User))
+	})
+	slices.SortFunc(report.Daily, func(a, b DailyUsage) int {
+		return cmp.Or(cmp.Compare(a.Day, b.Day), cmp.Compare(a.DatasourceID, b.DatasourceID))
+	})
+	return report, nil
+}
+
+func addTo[K comparable](m map[K]map[string]bool, k K, v string) {
+	if m[k] == nil {
+		m[k] = map[string]bool{}
+	}
+	m[k][v] = true
+}
+
+// addCost adds c's cost to costs under its currency. Counts without a
+// currency ran unpriced and add nothing.
+func addCost(costs map[string]float64, c *Count) map[string]float64 {
+	if c.Currency == "" {
+		return costs
+	}
+	if costs == nil {
+		costs = map[string]float64{}
+	}
+	costs[c.Currency] += c.Cost
+	return costs
+}
@@ -0,0 +1,186 @@
+package usage
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/identity"
+	"data-voyager/sdk"
+)
+
+// memRepo aggregates in Go what the stores aggregate in SQL.
+type memRepo struct {
+	mu     sync.Mutex
+	events []*Event
+}
+
+func (m *memRepo) Record(_ context.Context, events []*Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, events...)
+	return nil
+}
+
+func (m *memRepo) Counts(_ context.Context, since string) ([]*Count, error) {
+	byKey := map[Count]*Count{}
+	for _, e := range m.events {
+		if e.Day < since {
+			continue
+		}
+		k := Count{Day: e.Day, DatasourceID: e.DatasourceID, DatasourceName: e.DatasourceName, TableName: e.TableName,
+			Username: e.Username, Currency: e.Currency}
+		c := byKey[k]
+		if c == nil {
+			c = &k
+			byKey[k] = c
+		}
+		c.Queries++
+		c.BytesRead += e.BytesRead
+		c.Cost += e.Cost
+	}
+	var out []*Count
+	for _, c := range byKey {
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+func (m *memRepo) LastUsed(context.Context) ([]*LastUse, error) {
+	last := map[[2]string]*LastUse{}
+	for _, e := range m.events {
+		k := [2]string{e.DatasourceID, e.TableName}
+		if lu := last[k]; lu == nil || e.Day > lu.Day {
+			last[k] = &LastUse{DatasourceID: e.DatasourceID, DatasourceName: e.DatasourceName, TableName: e.TableName, Day: e.Day}
+		}
+	}
+	var out []*LastUse
+	for _, lu := range last {
+		out = append(out, lu)
+	}
+	return out, nil
+}
+
+type listConns struct {
+	connection.Repository
+	conns []*connection.Connection
+}
+
+func (l listConns) List(context.Context, connection.Filter) ([]*connection.Connection, error) {
+	return l.conns, nil
+}
+
+var (
+	warehouse = &connection.Connection{ID: "ds-1", Name: "warehouse", Type: "postgresql",
+		CostModel: &connection.CostModel{PerTiB: 5, MinBytesBilled: 10 << 20}}
+	legacy = &connection.Connection{ID: "ds-2", Name: "legacy", Type: "mysql"}
+	now    = time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+)
+
+func record(t *testing.T, repo *memRepo, at time.Time, user string, conn *connection.Connection, query string, bytesRead int64) {
+	t.Helper()
+	r := NewRecorder(repo)
+	r.now = func() time.Time { return at }
+	ctx := identity.With(context.Background(), &identity.Identity{Username: user, Role: identity.RoleViewer})
+	r.RecordQuery(ctx, conn, query, sdk.QueryStats{BytesRead: bytesRead})
+
+	// Run flushes whatever is queued once its context is done.
+	done, cancel := context.WithCancel(context.Background())
+	cancel()
+	r.Run(done)
+}
+
+func TestRecorder(t *testing.T) {
+	repo := &memRepo{}
+	record(t, repo, now, "ann", warehouse, "SELECT * FROM orders o JOIN public.customers c ON c.id = o.customer_id", 1<<40)
+
+	require.Len(t, repo.events, 3)
+	var tables []string
+	for _, e := range repo.events {
+		assert.Equal(t, "ds-1", e.DatasourceID)
+		assert.Equal(t, "ann", e.Username)
+		assert.Equal(t, "2026-03-31", e.Day)
+		assert.NotEmpty(t, e.ID)
+		tables = append(tables, e.TableName)
+	}
+	assert.Equal(t, []string{"", "orders", "public.customers"}, tables)
+
+	// Only the datasource event carries the scan, so it is counted once.
+	assert.EqualValues(t, 1<<40, repo.events[0].BytesRead)
+	assert.InDelta(t, 5, repo.events[0].Cost, 1e-9)
+	assert.Equal(t, "USD", repo.events[0].Currency)
+	assert.Zero(t, repo.events[1].BytesRead)
+	assert.Empty(t, repo.events[1].Currency)
+}
+
+func TestReport(t *testing.T) {
+	repo := &memRepo{}
+	record(t, repo, now, "ann", warehouse, "SELECT * FROM orders", 1<<39)
+	// Billed at the 10 MiB minimum.
+	record(t, repo, now.AddDate(0, 0, -1), "bob", warehouse, "SELECT * FROM orders", 1<<10)
+	record(t, repo, now.AddDate(0, 0, -60), "bob", warehouse, "SELECT * FROM old_audit", 1<<40)
+	record(t, repo, now.AddDate(0, 0, -90), "ann", legacy, "SELECT 1", 0)
+
+	svc := NewService(repo, listConns{conns: []*connection.Connection{legacy, warehouse}})
+	svc.now = func() time.Time { return now }
+
+	report, err := svc.Report(context.Background(), 30)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-02", report.From)
+	assert.Equal(t, "2026-03-31", report.To)
+
+	minCost := float64(10<<20) / (1 << 40) * 5
+	assert.Equal(t, []DatasourceUsage{
+		{ID: "ds-1", Name: "warehouse", Type: "postgresql", Queries: 2, Users: 2, BytesRead: 1<<39 + 1<<10,
+			Cost: map[string]float64{"USD": 2.5 + minCost}, LastUsed: "2026-03-31"},
+		{ID: "ds-2", Name: "legacy", Type: "mysql", LastUsed: "2025-12-31", Unused: true},
+	}, report.Datasources)
+	assert.Equal(t, []TableUsage{
+		{DatasourceID: "ds-1", DatasourceName: "warehouse", Table: "orders", Queries: 2, Users: 2, LastUsed: "2026-03-31"},
+		{DatasourceID: "ds-1", DatasourceName: "warehouse", Table: "old_audit", LastUsed: "2026-01-30", Unused: true},
+	}, report.Tables)
+	assert.Equal(t, []UserUsage{
+		{User: "ann", Queries: 1, Datasources: 1, BytesRead: 1 << 39, Cost: map[string]float64{"USD": 2.5}},
+		{User: "bob", Queries: 1, Datasources: 1, BytesRead: 1 << 10, Cost: map[string]float64{"USD": minCost}},
+	}, report.Users)
+	assert.Equal(t, []DailyUsage{
+		{Day: "2026-03-30", DatasourceID: "ds-1", Queries: 1},
+		{Day: "2026-03-31", DatasourceID: "ds-1", Queries: 1},
+	}, report.Daily)
+
+	_, err = svc.Report(context.Background(), 0)
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestHandler_CSV(t *testing.T) {
+	repo := &memRepo{}
+	record(t, repo, time.Now(), "ann", warehouse, "SELECT * FROM orders", 1<<40)
+	h := NewHandler(NewService(repo, listConns{conns: []*connection.Connection{warehouse, legacy}}))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/usage-report?days=7&format=csv", nil)
+	h.Report(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv"))
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	assert.Equal(t, []string{"datasource_id", "datasource", "type", "table", "queries", "users", "last_used", "unused",
+		"bytes_read", "cost"}, rows[0])
+	assert.Equal(t, []string{"ds-1", "warehouse", "postgresql", "", "1", "1"}, rows[1][:6])
+	assert.Equal(t, []string{"1099511627776", "5.00 USD"}, rows[1][8:])
+	assert.Equal(t, []string{"ds-2", "legacy", "mysql", "", "0", "0", "", "true", "0", ""}, rows[2])
+	assert.Equal(t, []string{"ds-1", "warehouse", "postgresql", "orders", "1", "1"}, rows[3][:6])
+}
@@ -0,0 +1,360 @@
+package user
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/identity"
+)
+
+var (
+	// ErrBadCredentials is returned for any failed login, without saying
+	// which part was wrong.
+	ErrBadCredentials = errors.New("invalid username or password")
+	// ErrLocked is returned while an account is locked out.
+	ErrLocked = errors.New("account is temporarily locked")
+	// ErrTOTPRequired is returned when the password was right but the user
+	// has two-factor authentication and sent no code.
+	ErrTOTPRequired = errors.New("two-factor code required")
+	// ErrNoPassword is returned for account operations on users who have no
+	// local password.
+	ErrNoPassword = errors.New("user has no local password")
+)
+
+// totpIssuer labels the account in authenticator apps.
+const totpIssuer = "Data Voyager"
+
+// WithAccounts enables local password accounts. TOTP secrets are encrypted
+// with encryptKey (32 bytes, or nil for plaintext); session tokens are
+// signed with a key derived from sessionSecret, or a random per-process
+// key when it is empty, which ends every session on restart.
+func (s *Service) WithAccounts(creds CredentialRepository, policy config.PasswordPolicy, encryptKey, sessionSecret []byte, ttl time.Duration) (*Service, error) {
+	if encryptKey != nil && len(encryptKey) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(encryptKey))
+	}
+	if len(sessionSecret) == 0 {
+		sessionSecret = make([]byte, 32)
+		if _, err := rand.Read(sessionSecret); err != nil {
+			return nil, err
+		}
+	}
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+	s.creds, s.policy, s.encryptKey = creds, policy, encryptKey
+	s.signer, s.sessionTTL = newSessionSigner(sessionSecret), ttl
+	return s, nil
+}
+
+// LoginResult is a successful login.
+type LoginResult struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	User      *User     `json:"user"`
+}
+
+// Login checks a username, password and — when enrolled — TOTP code, and
+// issues a session token. Consecutive failures lock the account for the
+// policy's lockout period.
+func (s *Service) Login(ctx context.Context, username, password, code string) (*LoginResult, error) {
+	if s.creds == nil {
+		return nil, ErrBadCredentials
+	}
+	u, err := s.repo.GetByUsername(ctx, username)
+	if err != nil || u.Disabled {
+		return nil, ErrBadCredentials
+	}
+	cr, err := s.creds.Get(ctx, u.ID)
+	if err != nil || cr.PasswordHash == "" {
+		return nil, ErrBadCredentials
+	}
+	now := s.now().UTC()
+	if cr.LockedUntil != nil && now.Before(*cr.LockedUntil) {
+		return nil, fmt.Errorf("%w until %s", ErrLocked, cr.LockedUntil.Format(time.RFC3339))
+	}
+	if !checkPassword(cr.PasswordHash, password) {
+		return nil, s.loginFailed(ctx, cr, now)
+	}
+	if cr.TOTPEnabled {
+		if code == "" {
+			return nil, ErrTOTPRequired
+		}
+		if err := s.checkCode(ctx, cr, code); errors.Is(err, ErrInvalid) {
+			return nil, s.loginFailed(ctx, cr, now)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	if cr.FailedAttempts > 0 || cr.LockedUntil != nil {
+		cr.FailedAttempts, cr.LockedUntil = 0, nil
+		if err := s.creds.Upsert(ctx, cr); err != nil {
+			return nil, err
+		}
+	}
+	return s.issueSession(u, cr, now), nil
+}
+
+// issueSession signs a session token for u.
+func (s *Service) issueSession(u *User, cr *Credentials, now time.Time) *LoginResult {
+	exp := now.Add(s.sessionTTL)
+	token := s.signer.sign(session{UserID: u.ID, PasswordChangedAt: cr.PasswordChangedAt.Unix(), ExpiresAt: exp.Unix()})
+	return &LoginResult{Token: token, ExpiresAt: exp, User: u}
+}
+
+// loginFailed counts a failed attempt and locks the account once the
+// policy's limit is reached.
+func (s *Service) loginFailed(ctx context.Context, cr *Credentials, now time.Time) error {
+	cr.FailedAttempts++
+	locked := false
+	if limit := s.policy.MaxFailedAttempts; limit > 0 && cr.FailedAttempts >= limit {
+		until := now.Add(time.Duration(s.policy.LockoutMinutes) * time.Minute)
+		cr.LockedUntil, cr.FailedAttempts, locked = &until, 0, true
+	}
+	if err := s.creds.Upsert(ctx, cr); err != nil {
+		return err
+	}
+	if locked {
+		return fmt.Errorf("%w until %s", ErrLocked, cr.LockedUntil.Format(time.RFC3339))
+	}
+	return ErrBadCredentials
+}
+
+// Authenticate returns the identity a session token was issued to. Tokens
+// issued before the user's last password change are rejected.
+func (s *Service) Authenticate(ctx context.Context, token string) (*identity.Identity, error) {
+	if s.signer == nil {
+		return nil, errInvalidSession
+	}
+	sess, err := s.signer.verify(token, s.now())
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.repo.GetByID(ctx, sess.UserID)
+	if err != nil || u.Disabled {
+		return nil, errInvalidSession
+	}
+	cr, err := s.creds.Get(ctx, u.ID)
+	if err != nil || cr.PasswordChangedAt.Unix() != sess.PasswordChangedAt {
+		return nil, errInvalidSession
+	}
+	return u.identity(), nil
+}
+
+// SetPassword sets a user's password without the current one, for admins.
+// It also clears any lockout.
+func (s *Service) SetPassword(ctx context.Context, userID, password string) error {
+	if _, err := s.Get(ctx, userID); err != nil {
+		return err
+	}
+	cr, err := s.creds.Get(ctx, userID)
+	if err != nil {
+		cr = &Credentials{UserID: userID}
+	}
+	cr.FailedAttempts, cr.LockedUntil = 0, nil
+	return s.storePassword(ctx, cr, password)
+}
+
+// ChangePassword replaces a user's own password after checking the current
+// one. Other sessions of the user end.
+func (s *Service) ChangePassword(ctx context.Context, userID, current, password string) error {
+	cr, err := s.verifiedCredentials(ctx, userID, current)
+	if err != nil {
+		return err
+	}
+	return s.storePassword(ctx, cr, password)
+}
+
+func (s *Service) storePassword(ctx context.Context, cr *Credentials, password string) error {
+	if err := checkPolicy(s.policy, password); err != nil {
+		return err
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	// Session tokens carry this in whole seconds.
+	cr.PasswordHash, cr.PasswordChangedAt = hash, s.now().UTC().Truncate(time.Second)
+	return s.creds.Upsert(ctx, cr)
+}
+
+// Unlock clears a lockout and the failed-attempt count.
+func (s *Service) Unlock(ctx context.Context, userID string) error {
+	cr, err := s.creds.Get(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	cr.FailedAttempts, cr.LockedUntil = 0, nil
+	return s.creds.Upsert(ctx, cr)
+}
+
+// TOTPEnrollment is the secret a user adds to their authenticator app.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// EnrollTOTP generates a new TOTP secret after re-checking the password.
+// Two-factor authentication is enforced only after ConfirmTOTP, so a user
+// who abandons enrollment can still log in.
+func (s *Service) EnrollTOTP(ctx context.Context, userID, password string) (*TOTPEnrollment, error) {
+	cr, err := s.verifiedCredentials(ctx, userID, password)
+	if err != nil {
+		return nil, err
+	}
+	if cr.TOTPEnabled {
+		return nil, fmt.Errorf("%w: two-factor authentication is already enabled", ErrInvalid)
+	}
+	u, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if cr.TOTPSecret, err = s.encrypt(secret); err != nil {
+		return nil, fmt.Errorf("encrypt totp secret: %w", err)
+	}
+	cr.TOTPLastStep = 0
+	if err := s.creds.Upsert(ctx, cr); err != nil {
+		return nil, err
+	}
+	return &TOTPEnrollment{Secret: secret, URI: totpURI(totpIssuer, u.Username, secret)}, nil
+}
+
+// ConfirmTOTP turns two-factor authentication on once the user proves
+// their authenticator produces valid codes.
+func (s *Service) ConfirmTOTP(ctx context.Context, userID, code string) error {
+	cr, err := s.credentials(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if cr.TOTPSecret == "" {
+		return fmt.Errorf("%w: start two-factor enrollment first", ErrInvalid)
+	}
+	if err := s.checkCode(ctx, cr, code); err != nil {
+		return err
+	}
+	cr.TOTPEnabled = true
+	return s.creds.Upsert(ctx, cr)
+}
+
+// DisableTOTP turns two-factor authentication off with a current code.
+func (s *Service) DisableTOTP(ctx context.Context, userID, code string) error {
+	cr, err := s.credentials(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !cr.TOTPEnabled {
+		return fmt.Errorf("%w: two-factor authentication is not enabled", ErrInvalid)
+	}
+	if err := s.checkCode(ctx, cr, code); err != nil {
+		return err
+	}
+	cr.TOTPEnabled, cr.TOTPSecret = false, ""
+	return s.creds.Upsert(ctx, cr)
+}
+
+// checkCode checks a TOTP code against cr and records its step, so that
+// the code cannot be used again. Concurrent checks are serialized and read
+// the step last recorded, so two requests cannot both spend one code.
+func (s *Service) checkCode(ctx context.Context, cr *Credentials, code string) error {
+	s.totpMu.Lock()
+	defer s.totpMu.Unlock()
+	current, err := s.creds.Get(ctx, cr.UserID)
+	if err != nil {
+		return err
+	}
+	// cr may have been read before another request spent a code; later
+	// writes of it must not bring the step back.
+	cr.TOTPLastStep = max(cr.TOTPLastStep, current.TOTPLastStep)
+	secret, err := s.decrypt(cr.TOTPSecret)
+	if err != nil {
+		return err
+	}
+	step, ok := checkTOTP(secret, code, s.now(), cr.TOTPLastStep)
+	if !ok {
+		return fmt.Errorf("%w: invalid two-factor code", ErrInvalid)
+	}
+	cr.TOTPLastStep = step
+	return s.creds.Upsert(ctx, cr)
+}
+
+func (s *Service) credentials(ctx context.Context, userID string) (*Credentials, error) {
+	if s.creds == nil {
+		return nil, ErrNoPassword
+	}
+	cr, err := s.creds.Get(ctx, userID)
+	if err != nil || cr.PasswordHash == "" {
+		return nil, ErrNoPassword
+	}
+	return cr, nil
+}
+
+// verifiedCredentials loads a user's credentials and checks password
+// against them.
+func (s *Service) verifiedCredentials(ctx context.Context, userID, password string) (*Credentials, error) {
+	cr, err := s.credentials(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !checkPassword(cr.PasswordHash, password) {
+		return nil, fmt.Errorf("%w: current password is incorrect", ErrInvalid)
+	}
+	return cr, nil
+}
+
+func (s *Service) encrypt(plaintext string) (string, error) {
+	if s.encryptKey == nil {
+		return plaintext, nil // store plaintext when no key configured
+	}
+	block, err := aes.NewCipher(s.encryptKey)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (s *Service) decrypt(ciphertext string) (string, error) {
+	if s.encryptKey == nil {
+		return ciphertext, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("base64 decode: %w", err)
+	}
+	block, err := aes.NewCipher(s.encryptKey)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	ns := gcm.NonceSize()
+	if len(data) < ns {
+		return "", errors.New("ciphertext too short")
+	}
+	pt, err := gcm.Open(nil, data[:ns], data[ns:], nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt: %w", err)
+	}
+	return string(pt), nil
+}
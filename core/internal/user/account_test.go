@@ -0,0 +1,167 @@
+package user
+
+import (
+	"context"
+	"encoding/base32"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/identity"
+)
+
+type memCreds struct{ m map[string]Credentials }
+
+func (r *memCreds) Get(_ context.Context, id string) (*Credentials, error) {
+	c, ok := r.m[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &c, nil
+}
+func (r *memCreds) Upsert(_ context.Context, c *Credentials) error { r.m[c.UserID] = *c; return nil }
+func (r *memCreds) Delete(_ context.Context, id string) error      { delete(r.m, id); return nil }
+
+func TestCheckPolicy(t *testing.T) {
+	p := config.PasswordPolicy{MinLength: 10, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}
+	assert.NoError(t, checkPolicy(p, "Correct-Horse1"))
+	err := checkPolicy(p, "short")
+	require.ErrorIs(t, err, ErrInvalid)
+	assert.Contains(t, err.Error(), "at least 10 characters")
+	assert.Contains(t, err.Error(), "an uppercase letter, a digit, a symbol")
+}
+
+func TestHashPassword(t *testing.T) {
+	h, err := hashPassword("s3cret!")
+	require.NoError(t, err)
+	assert.True(t, checkPassword(h, "s3cret!"))
+	assert.False(t, checkPassword(h, "s3cret"))
+	assert.False(t, checkPassword("garbage", "s3cret!"))
+	h2, _ := hashPassword("s3cret!")
+	assert.NotEqual(t, h, h2, "salted")
+}
+
+func TestTOTP_RFC6238(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	for _, tc := range []struct {
+		unix int64
+		code string
+	}{{59, "287082"}, {1111111109, "081804"}, {1234567890, "005924"}} {
+		got, err := totpCode(secret, time.Unix(tc.unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, tc.code, got, "T=%d", tc.unix)
+	}
+	now := time.Unix(1234567890, 0)
+	prev, _ := totpCode(secret, now.Add(-totpPeriod))
+	old, _ := totpCode(secret, now.Add(-3*totpPeriod))
+	step, ok := checkTOTP(secret, prev, now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, totpStep(now)-1, step)
+	_, ok = checkTOTP(secret, prev, now, step)
+	assert.False(t, ok, "replayed")
+	_, ok = checkTOTP(secret, old, now, 0)
+	assert.False(t, ok)
+	assert.Equal(t, "otpauth://totp/Data%20Voyager:ann@x?issuer=Data+Voyager&secret=ABC", totpURI("Data Voyager", "ann@x", "ABC"))
+}
+
+func newAccountService(t *testing.T) (*Service, *memCreds, *time.Time) {
+	t.Helper()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	creds := &memCreds{m: map[string]Credentials{}}
+	svc, err := NewService(&memRepo{}, &memAudit{}, stubConns{}).WithAccounts(creds,
+		config.PasswordPolicy{MinLength: 8, RequireDigit: true, MaxFailedAttempts: 3, LockoutMinutes: 10},
+		make([]byte, 32), []byte("session-secret"), time.Hour)
+	require.NoError(t, err)
+	svc.now = func() time.Time { return now }
+	require.NoError(t, svc.Create(context.Background(), &User{Username: "ann", Role: identity.RoleEditor}))
+	return svc, creds, &now
+}
+
+func TestLogin_LockoutAndSessions(t *testing.T) {
+	svc, creds, now := newAccountService(t)
+	ctx := context.Background()
+	u, _ := svc.repo.GetByUsername(ctx, "ann")
+
+	_, err := svc.Login(ctx, "ann", "whatever1", "")
+	assert.ErrorIs(t, err, ErrBadCredentials, "no local password yet")
+	assert.ErrorIs(t, svc.SetPassword(ctx, u.ID, "nodigits"), ErrInvalid)
+	require.NoError(t, svc.SetPassword(ctx, u.ID, "password1"))
+
+	res, err := svc.Login(ctx, "ann", "password1", "")
+	require.NoError(t, err)
+	id, err := svc.Authenticate(ctx, res.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "ann", id.Username)
+	_, err = svc.Authenticate(ctx, res.Token+"x")
+	assert.Error(t, err)
+
+	for range 2 {
+		_, err = svc.Login(ctx, "ann", "wrong", "")
+		assert.ErrorIs(t, err, ErrBadCredentials)
+	}
+	_, err = svc.Login(ctx, "ann", "wrong", "")
+	assert.ErrorIs(t, err, ErrLocked)
+	_, err = svc.Login(ctx, "ann", "password1", "")
+	assert.ErrorIs(t, err, ErrLocked, "right password is refused while locked")
+
+	*now = now.Add(11 * time.Minute)
+	_, err = svc.Login(ctx, "ann", "password1", "")
+	require.NoError(t, err)
+	assert.Zero(t, creds.m[u.ID].FailedAttempts)
+
+	// Changing the password ends earlier sessions.
+	*now = now.Add(time.Second)
+	require.NoError(t, svc.ChangePassword(ctx, u.ID, "password1", "password2"))
+	_, err = svc.Authenticate(ctx, res.Token)
+	assert.Error(t, err)
+
+	*now = now.Add(2 * time.Hour)
+	res, err = svc.Login(ctx, "ann", "password2", "")
+	require.NoError(t, err)
+	*now = now.Add(time.Hour)
+	_, err = svc.Authenticate(ctx, res.Token)
+	assert.Error(t, err, "expired")
+}
+
+func TestTOTPEnrollment(t *testing.T) {
+	svc, creds, now := newAccountService(t)
+	ctx := context.Background()
+	u, _ := svc.repo.GetByUsername(ctx, "ann")
+	require.NoError(t, svc.SetPassword(ctx, u.ID, "password1"))
+
+	_, err := svc.EnrollTOTP(ctx, u.ID, "wrong")
+	assert.ErrorIs(t, err, ErrInvalid)
+	enr, err := svc.EnrollTOTP(ctx, u.ID, "password1")
+	require.NoError(t, err)
+	assert.NotEqual(t, enr.Secret, creds.m[u.ID].TOTPSecret, "encrypted at rest")
+
+	// Not enforced until confirmed.
+	_, err = svc.Login(ctx, "ann", "password1", "")
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, svc.ConfirmTOTP(ctx, u.ID, "000000"), ErrInvalid)
+	code, _ := totpCode(enr.Secret, *now)
+	require.NoError(t, svc.ConfirmTOTP(ctx, u.ID, code))
+
+	_, err = svc.Login(ctx, "ann", "password1", "")
+	assert.ErrorIs(t, err, ErrTOTPRequired)
+	_, err = svc.Login(ctx, "ann", "password1", "123456")
+	assert.ErrorIs(t, err, ErrBadCredentials)
+	_, err = svc.Login(ctx, "ann", "password1", code)
+	assert.ErrorIs(t, err, ErrBadCredentials, "code already used to confirm")
+	*now = now.Add(totpPeriod)
+	code, _ = totpCode(enr.Secret, *now)
+	_, err = svc.Login(ctx, "ann", "password1", code)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, svc.DisableTOTP(ctx, u.ID, code), ErrInvalid)
+	*now = now.Add(totpPeriod)
+	code, _ = totpCode(enr.Secret, *now)
+	require.NoError(t, svc.DisableTOTP(ctx, u.ID, code))
+	_, err = svc.Login(ctx, "ann", "password1", "")
+	require.NoError(t, err)
+}
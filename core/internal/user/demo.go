@@ -0,0 +1,92 @@
+package user
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/identity"
+)
+
+// demoUsername is the name the anonymous demo visitor acts under.
+const demoUsername = "demo"
+
+// demoReads are the POST endpoints a demo visitor may call: each runs
+// queries and writes nothing. The query handlers refuse anything but plain
+// reads for demo identities and cap the rows returned.
+var demoReads = []string{
+	"/datasources/:uid/query",
+	"/datasources/:uid/query/batch",
+	"/dashboards/:id/data",
+}
+
+// demoBrowse are the GET endpoints a demo visitor may call: the ones the
+// query editor and dashboards need to browse what the demo shares. Anything
+// else, such as exports, result downloads, snapshot renders or Grafana
+// exports, hands out data the row cap does not cover and stays closed.
+var demoBrowse = []string{
+	"/whoami",
+	"/i18n",
+	"/i18n/:locale",
+	"/datasources",
+	"/datasources/:uid",
+	"/datasources/:uid/schema",
+	"/datasource-types",
+	"/datasource-types/:type",
+	"/datasource-types/:type/dialect",
+	"/dashboards",
+	"/dashboards/:id",
+}
+
+// demoAPIBase is the route group the demo endpoints are mounted under.
+const demoAPIBase = "/api/v1"
+
+// WithDemo lets requests without a caller browse as a read-only demo
+// visitor when cfg is enabled.
+func (s *Service) WithDemo(cfg config.DemoConfig) *Service {
+	if !cfg.Enabled {
+		s.demo = nil
+		return s
+	}
+	s.demo = &identity.Identity{
+		Username:    demoUsername,
+		Role:        identity.RoleViewer,
+		Datasources: slices.Clone(cfg.Datasources),
+		Dashboards:  slices.Clone(cfg.Dashboards),
+		RowLimit:    cfg.RowLimit,
+		Demo:        true,
+	}
+	return s
+}
+
+// demoIdentity returns a fresh copy of the demo visitor, so no request can
+// change what the next one sees.
+func (s *Service) demoIdentity() *identity.Identity {
+	id := *s.demo
+	id.Datasources = slices.Clone(s.demo.Datasources)
+	id.Dashboards = slices.Clone(s.demo.Dashboards)
+	return &id
+}
+
+// demoAllows reports whether a demo visitor may make the request: the
+// browse endpoints, queries, and logging in.
+func demoAllows(c *gin.Context) bool {
+	path := c.FullPath()
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return slices.Contains(demoBrowse, strings.TrimPrefix(path, demoAPIBase))
+	case http.MethodPost:
+		if public(path) {
+			return true
+		}
+		for _, p := range demoReads {
+			if strings.HasSuffix(path, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// Handler serves the user, whoami, local account, invitation and
+// impersonation audit endpoints.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates a user HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// ─── wire types ───────────────────────────────────────────────────────────────
+
+type userRequest struct {
+	Username    string   `json:"username"`
+	Email       string   `json:"email"`
+	Name        string   `json:"name"`
+	Role        string   `json:"role"`
+	Datasources []string `json:"datasources"`
+	RowLimit    int      `json:"row_limit"`
+	Disabled    bool     `json:"disabled"`
+}
+
+func (r userRequest) apply(u *User) {
+	u.Username, u.Email, u.Name, u.Role = r.Username, r.Email, r.Name, r.Role
+	u.Datasources, u.RowLimit, u.Disabled = r.Datasources, r.RowLimit, r.Disabled
+}
+
+type whoamiResponse struct {
+	Authenticated  bool     `json:"authenticated"`
+	Username       string   `json:"username,omitempty"`
+	Role           string   `json:"role,omitempty"`
+	Permissions    []string `json:"permissions"`
+	Datasources    []string `json:"datasources"`
+	ImpersonatedBy string   `json:"impersonated_by,omitempty"`
+	Demo           bool     `json:"demo,omitempty"`
+}
+
+// ─── handlers ─────────────────────────────────────────────────────────────────
+
+// WhoAmI handles GET /whoami, describing the identity the request acts as.
+func (h *Handler) WhoAmI(c *gin.Context) {
+	id := identity.FromContext(c.Request.Context())
+	if id == nil {
+		c.JSON(http.StatusOK, gin.H{"data": whoamiResponse{
+			Permissions: identity.Permissions(identity.RoleAdmin),
+			Datasources: []string{},
+		}})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": whoamiResponse{
+		Authenticated:  !id.Demo,
+		Username:       id.Username,
+		Role:           id.Role,
+		Permissions:    identity.Permissions(id.Role),
+		Datasources:    append([]string{}, id.Datasources...),
+		ImpersonatedBy: id.ImpersonatedBy,
+		Demo:           id.Demo,
+	}})
+}
+
+// WhoAmIAs handles GET /admin/whoami-as?user=NAME
+func (h *Handler) WhoAmIAs(c *gin.Context) {
+	name := c.Query("user")
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "user is required")})
+		return
+	}
+	a, err := h.svc.Access(c.Request.Context(), name)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": a})
+}
+
+// List handles GET /admin/users
+func (h *Handler) List(c *gin.Context) {
+	list, err := h.svc.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// Get handles GET /admin/users/:id
+func (h *Handler) Get(c *gin.Context) {
+	u, err := h.svc.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": u})
+}
+
+// Create handles POST /admin/users
+func (h *Handler) Create(c *gin.Context) {
+	var req userRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	u := &User{}
+	req.apply(u)
+	if err := h.svc.Create(c.Request.Context(), u); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": u})
+}
+
+// Update handles PUT /admin/users/:id
+func (h *Handler) Update(c *gin.Context) {
+	var req userRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	u := &User{ID: c.Param("id")}
+	req.apply(u)
+	if err := h.svc.Update(c.Request.Context(), u); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": u})
+}
+
+// Delete handles DELETE /admin/users/:id
+func (h *Handler) Delete(c *gin.Context) {
+	if err := h.svc.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Impersonations handles GET /admin/impersonations?user=NAME&limit=N
+func (h *Handler) Impersonations(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	list, err := h.svc.Impersonations(c.Request.Context(), c.Query("user"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+func (h *Handler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "user not found")})
+	case errors.Is(err, ErrInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// RegisterRoutes wires the handler onto r. Everything under /admin requires
+// the admin permission.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/whoami", h.WhoAmI)
+	r.POST(loginPath, h.Login)
+	r.POST(invitationLookupPath, h.LookupInvitation)
+	r.POST(invitationAcceptPath, h.AcceptInvitation)
+	r.POST("/account/password", h.ChangePassword)
+	r.POST("/account/totp/enroll", h.EnrollTOTP)
+	r.POST("/account/totp/confirm", h.ConfirmTOTP)
+	r.DELETE("/account/totp", h.DisableTOTP)
+	admin := r.Group("/admin", RequireAdmin)
+	admin.GET("/whoami-as", h.WhoAmIAs)
+	admin.GET("/users", h.List)
+	admin.POST("/users", h.Create)
+	admin.GET("/users/:id", h.Get)
+	admin.PUT("/users/:id", h.Update)
+	admin.DELETE("/users/:id", h.Delete)
+	admin.PUT("/users/:id/password", h.SetPassword)
+	admin.POST("/users/:id/unlock", h.Unlock)
+	admin.GET("/impersonations", h.Impersonations)
+	admin.GET("/invitations", h.ListInvitations)
+	admin.POST("/invitations", h.CreateInvitation)
+	admin.POST("/invitations/:id/resend", h.ResendInvitation)
+	admin.DELETE("/invitations/:id", h.RevokeInvitation)
+}
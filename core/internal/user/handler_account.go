@@ -0,0 +1,170 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// loginPath is exempt from the middleware's required-auth check.
+const loginPath = "/auth/login"
+
+type loginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	TOTPCode string `json:"totp_code"`
+}
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password"     binding:"required"`
+}
+
+type setPasswordRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type totpRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// Login handles POST /auth/login
+func (h *Handler) Login(c *gin.Context) {
+	var req loginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	res, err := h.svc.Login(c.Request.Context(), req.Username, req.Password, req.TOTPCode)
+	if err != nil {
+		h.failAccount(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// ChangePassword handles POST /account/password
+func (h *Handler) ChangePassword(c *gin.Context) {
+	id, ok := caller(c)
+	if !ok {
+		return
+	}
+	var req changePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.svc.ChangePassword(c.Request.Context(), id.UserID, req.CurrentPassword, req.NewPassword); err != nil {
+		h.failAccount(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// EnrollTOTP handles POST /account/totp/enroll, re-checking the password.
+func (h *Handler) EnrollTOTP(c *gin.Context) {
+	id, ok := caller(c)
+	if !ok {
+		return
+	}
+	var req setPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	enrollment, err := h.svc.EnrollTOTP(c.Request.Context(), id.UserID, req.Password)
+	if err != nil {
+		h.failAccount(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": enrollment})
+}
+
+// ConfirmTOTP handles POST /account/totp/confirm
+func (h *Handler) ConfirmTOTP(c *gin.Context) {
+	id, ok := caller(c)
+	if !ok {
+		return
+	}
+	var req totpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.svc.ConfirmTOTP(c.Request.Context(), id.UserID, req.Code); err != nil {
+		h.failAccount(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// DisableTOTP handles DELETE /account/totp
+func (h *Handler) DisableTOTP(c *gin.Context) {
+	id, ok := caller(c)
+	if !ok {
+		return
+	}
+	var req totpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.svc.DisableTOTP(c.Request.Context(), id.UserID, req.Code); err != nil {
+		h.failAccount(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// SetPassword handles PUT /admin/users/:id/password
+func (h *Handler) SetPassword(c *gin.Context) {
+	var req setPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.svc.SetPassword(c.Request.Context(), c.Param("id"), req.Password); err != nil {
+		h.failAccount(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Unlock handles POST /admin/users/:id/unlock
+func (h *Handler) Unlock(c *gin.Context) {
+	if err := h.svc.Unlock(c.Request.Context(), c.Param("id")); err != nil {
+		h.failAccount(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// caller returns the request's user, answering 401 when there is none:
+// account endpoints act on the caller's own credentials.
+func caller(c *gin.Context) (*identity.Identity, bool) {
+	id := identity.FromContext(c.Request.Context())
+	if id == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": i18n.T(c, "authentication required")})
+		return nil, false
+	}
+	return id, true
+}
+
+func (h *Handler) failAccount(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrLocked):
+		c.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrTOTPRequired):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": i18n.T(c, "two-factor code required"), "code": "totp_required"})
+	case errors.Is(err, ErrBadCredentials):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": i18n.T(c, "invalid username or password")})
+	case errors.Is(err, ErrNoPassword):
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "user has no local password")})
+	default:
+		h.fail(c, err)
+	}
+}
@@ -0,0 +1,144 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// Public invitation paths, exempt like loginPath from the middleware's
+// required-auth check.
+const (
+	invitationLookupPath = "/auth/invitations/lookup"
+	invitationAcceptPath = "/auth/invitations/accept"
+)
+
+type invitationRequest struct {
+	Email       string   `json:"email" binding:"required"`
+	Name        string   `json:"name"`
+	Role        string   `json:"role"`
+	Datasources []string `json:"datasources"`
+}
+
+type invitationTokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type acceptInvitationRequest struct {
+	Token    string `json:"token"    binding:"required"`
+	Username string `json:"username" binding:"required"`
+	Name     string `json:"name"`
+	Password string `json:"password" binding:"required"`
+}
+
+// invitationView is what an invitee sees of their invitation.
+type invitationView struct {
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	InvitedBy string    `json:"invited_by,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ListInvitations handles GET /admin/invitations?status=pending|accepted|expired
+func (h *Handler) ListInvitations(c *gin.Context) {
+	list, err := h.svc.Invitations(c.Request.Context(), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// CreateInvitation handles POST /admin/invitations
+func (h *Handler) CreateInvitation(c *gin.Context) {
+	var req invitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	inv := &Invitation{Email: req.Email, Name: req.Name, Role: req.Role, Datasources: req.Datasources}
+	var invitedBy string
+	if id := identity.FromContext(c.Request.Context()); id != nil {
+		invitedBy = id.Username
+	}
+	res, err := h.svc.Invite(c.Request.Context(), inv, invitedBy)
+	if err != nil {
+		h.failInvitation(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": res})
+}
+
+// ResendInvitation handles POST /admin/invitations/:id/resend
+func (h *Handler) ResendInvitation(c *gin.Context) {
+	res, err := h.svc.ResendInvitation(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.failInvitation(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// RevokeInvitation handles DELETE /admin/invitations/:id
+func (h *Handler) RevokeInvitation(c *gin.Context) {
+	if err := h.svc.RevokeInvitation(c.Request.Context(), c.Param("id")); err != nil {
+		h.failInvitation(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// LookupInvitation handles POST /auth/invitations/lookup. The token travels
+// in the body so it stays out of access logs.
+func (h *Handler) LookupInvitation(c *gin.Context) {
+	var req invitationTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	inv, err := h.svc.LookupInvitation(c.Request.Context(), req.Token)
+	if err != nil {
+		h.failInvitation(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": invitationView{
+		Email:     inv.Email,
+		Name:      inv.Name,
+		Role:      inv.Role,
+		InvitedBy: inv.InvitedBy,
+		ExpiresAt: inv.ExpiresAt,
+	}})
+}
+
+// AcceptInvitation handles POST /auth/invitations/accept, creating the
+// account and returning a session like Login.
+func (h *Handler) AcceptInvitation(c *gin.Context) {
+	var req acceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	res, err := h.svc.AcceptInvitation(c.Request.Context(), req.Token, req.Username, req.Name, req.Password)
+	if err != nil {
+		h.failInvitation(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": res})
+}
+
+func (h *Handler) failInvitation(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrInvitationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "invitation not found")})
+	case errors.Is(err, ErrInvitationClosed):
+		c.JSON(http.StatusGone, gin.H{"error": i18n.T(c, "invitation has expired or was already used")})
+	default:
+		h.fail(c, err)
+	}
+}
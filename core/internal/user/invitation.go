@@ -0,0 +1,296 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvitationNotFound is returned for unknown invitations and for
+	// tokens that match none, without saying which.
+	ErrInvitationNotFound = errors.New("invitation not found")
+	// ErrInvitationClosed is returned for a token whose invitation expired or
+	// was already accepted.
+	ErrInvitationClosed = errors.New("invitation has expired or was already used")
+)
+
+// invitationTemplate is the email template invitations are sent with.
+const invitationTemplate = "invitation"
+
+// Mailer sends templated email; see the email package.
+type Mailer interface {
+	Enabled() bool
+	SendTemplate(ctx context.Context, name string, to []string, data map[string]any) error
+}
+
+// WithInvitations enables invitations. Links are built on publicURL and
+// stay valid for ttl; mailer may be nil, in which case admins pass the link
+// on themselves. Invitees get local accounts, so WithAccounts must be set
+// for them to be accepted.
+func (s *Service) WithInvitations(repo InvitationRepository, mailer Mailer, publicURL string, ttl time.Duration) *Service {
+	if ttl <= 0 {
+		ttl = 72 * time.Hour
+	}
+	s.invitations, s.mailer, s.publicURL, s.invitationTTL = repo, mailer, publicURL, ttl
+	return s
+}
+
+// InvitationResult is an invitation just created or resent, with the link
+// it carries. The link is only ever shown here.
+type InvitationResult struct {
+	Invitation *Invitation `json:"invitation"`
+	Link       string      `json:"link"`
+	// Sent reports whether the link was emailed; when it was not, SendError
+	// says why and the admin has to pass the link on.
+	Sent      bool   `json:"sent"`
+	SendError string `json:"send_error,omitempty"`
+}
+
+// Invitations lists invitations, optionally only those in one state.
+func (s *Service) Invitations(ctx context.Context, status string) ([]*Invitation, error) {
+	if s.invitations == nil {
+		return []*Invitation{}, nil
+	}
+	list, err := s.invitations.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*Invitation, 0, len(list))
+	now := s.now()
+	for _, inv := range list {
+		inv.Status = invitationStatus(inv, now)
+		if status == "" || inv.Status == status {
+			out = append(out, inv)
+		}
+	}
+	return out, nil
+}
+
+// Invite creates an invitation for inv.Email with inv's name, role and
+// datasources, and emails the link. invitedBy names the admin.
+func (s *Service) Invite(ctx context.Context, inv *Invitation, invitedBy string) (*InvitationResult, error) {
+	if s.invitations == nil {
+		return nil, fmt.Errorf("%w: invitations are not enabled", ErrInvalid)
+	}
+	addr, err := mail.ParseAddress(inv.Email)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid email %q", ErrInvalid, inv.Email)
+	}
+	inv.Email = addr.Address
+	// Reuse user validation for the role and datasources.
+	probe := &User{Username: "invitee", Role: inv.Role, Datasources: inv.Datasources}
+	if err := s.validate(ctx, probe); err != nil {
+		return nil, err
+	}
+	inv.Role, inv.Datasources = probe.Role, probe.Datasources
+	if err := s.checkInvitable(ctx, inv.Email); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	inv.ID = uuid.NewString()
+	inv.InvitedBy = invitedBy
+	inv.AcceptedAt, inv.UserID, inv.SentCount = nil, "", 0
+	inv.CreatedAt = now
+	token, err := s.rotateToken(inv, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.invitations.Create(ctx, inv); err != nil {
+		return nil, err
+	}
+	return s.sendInvitation(ctx, inv, token)
+}
+
+// ResendInvitation issues a new link for an invitation not yet accepted,
+// restarting its expiry, and emails it. The previous link stops working.
+func (s *Service) ResendInvitation(ctx context.Context, id string) (*InvitationResult, error) {
+	inv, err := s.invitation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if inv.AcceptedAt != nil {
+		return nil, fmt.Errorf("%w: the invitation was already accepted", ErrInvalid)
+	}
+	token, err := s.rotateToken(inv, s.now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.invitations.Update(ctx, inv); err != nil {
+		return nil, err
+	}
+	return s.sendInvitation(ctx, inv, token)
+}
+
+// RevokeInvitation deletes an invitation, invalidating its link. Accounts
+// already created from it are kept.
+func (s *Service) RevokeInvitation(ctx context.Context, id string) error {
+	if _, err := s.invitation(ctx, id); err != nil {
+		return err
+	}
+	return s.invitations.Delete(ctx, id)
+}
+
+// LookupInvitation returns the open invitation a token belongs to, so the
+// signup form can show who it is for.
+func (s *Service) LookupInvitation(ctx context.Context, token string) (*Invitation, error) {
+	if s.invitations == nil {
+		return nil, ErrInvitationNotFound
+	}
+	inv, err := s.invitations.GetByTokenHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, ErrInvitationNotFound
+	}
+	inv.Status = invitationStatus(inv, s.now())
+	if inv.Status != InvitationPending {
+		return nil, ErrInvitationClosed
+	}
+	return inv, nil
+}
+
+// AcceptInvitation creates the invited account with the chosen username,
+// name and password and logs it in. The invitation cannot be used again.
+func (s *Service) AcceptInvitation(ctx context.Context, token, username, name, password string) (*LoginResult, error) {
+	if s.creds == nil {
+		return nil, fmt.Errorf("%w: local accounts are not enabled", ErrInvalid)
+	}
+	// One acceptance at a time, so a token cannot create two accounts.
+	s.acceptMu.Lock()
+	defer s.acceptMu.Unlock()
+
+	inv, err := s.LookupInvitation(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPolicy(s.policy, password); err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = inv.Name
+	}
+	u := &User{Username: username, Email: inv.Email, Name: name, Role: inv.Role, Datasources: inv.Datasources}
+	if err := s.Create(ctx, u); err != nil {
+		return nil, err
+	}
+	cr := &Credentials{UserID: u.ID}
+	if err := s.storePassword(ctx, cr, password); err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	inv.AcceptedAt, inv.UserID, inv.UpdatedAt = &now, u.ID, now
+	if err := s.invitations.Update(ctx, inv); err != nil {
+		return nil, err
+	}
+	slog.Info("invitation accepted", "email", inv.Email, "username", u.Username, "invited_by", inv.InvitedBy)
+	return s.issueSession(u, cr, now), nil
+}
+
+// checkInvitable refuses addresses that already have an account or an
+// open invitation.
+func (s *Service) checkInvitable(ctx context.Context, email string) error {
+	users, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		if strings.EqualFold(u.Email, email) {
+			return fmt.Errorf("%w: user %s already has the email %s", ErrInvalid, u.Username, email)
+		}
+	}
+	list, err := s.invitations.List(ctx)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	for _, inv := range list {
+		if strings.EqualFold(inv.Email, email) && invitationStatus(inv, now) == InvitationPending {
+			return fmt.Errorf("%w: %s already has an open invitation; resend it instead", ErrInvalid, email)
+		}
+	}
+	return nil
+}
+
+func (s *Service) invitation(ctx context.Context, id string) (*Invitation, error) {
+	if s.invitations == nil {
+		return nil, ErrInvitationNotFound
+	}
+	inv, err := s.invitations.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvitationNotFound, err)
+	}
+	return inv, nil
+}
+
+// rotateToken gives inv a new token and expiry, returning the token.
+func (s *Service) rotateToken(inv *Invitation, now time.Time) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	inv.TokenHash = hashToken(token)
+	inv.ExpiresAt = now.Add(s.invitationTTL)
+	inv.UpdatedAt = now
+	return token, nil
+}
+
+// sendInvitation emails the link when a mailer is configured. A failed send
+// leaves the invitation usable; the result carries the link either way.
+func (s *Service) sendInvitation(ctx context.Context, inv *Invitation, token string) (*InvitationResult, error) {
+	res := &InvitationResult{Invitation: inv, Link: s.invitationLink(token)}
+	inv.Status = invitationStatus(inv, s.now())
+	if s.mailer == nil || !s.mailer.Enabled() {
+		res.SendError = "email is not configured"
+		return res, nil
+	}
+	err := s.mailer.SendTemplate(ctx, invitationTemplate, []string{inv.Email}, map[string]any{
+		"inviter":    inv.InvitedBy,
+		"name":       inv.Name,
+		"role":       inv.Role,
+		"link":       res.Link,
+		"expires_at": inv.ExpiresAt,
+	})
+	if err != nil {
+		slog.Warn("failed to send invitation", "email", inv.Email, "err", err)
+		res.SendError = err.Error()
+		return res, nil
+	}
+	inv.SentCount++
+	if err := s.invitations.Update(ctx, inv); err != nil {
+		return nil, err
+	}
+	res.Sent = true
+	return res, nil
+}
+
+func (s *Service) invitationLink(token string) string {
+	return strings.TrimRight(s.publicURL, "/") + "/invitation?token=" + url.QueryEscape(token)
+}
+
+func invitationStatus(inv *Invitation, now time.Time) string {
+	switch {
+	case inv.AcceptedAt != nil:
+		return InvitationAccepted
+	case !now.Before(inv.ExpiresAt):
+		return InvitationExpired
+	default:
+		return InvitationPending
+	}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,180 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/identity"
+)
+
+type memInvitations struct{ m map[string]Invitation }
+
+func (r *memInvitations) List(context.Context) ([]*Invitation, error) {
+	var out []*Invitation
+	for _, inv := range r.m {
+		c := inv
+		out = append(out, &c)
+	}
+	return out, nil
+}
+func (r *memInvitations) GetByID(_ context.Context, id string) (*Invitation, error) {
+	inv, ok := r.m[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &inv, nil
+}
+func (r *memInvitations) GetByTokenHash(_ context.Context, hash string) (*Invitation, error) {
+	for _, inv := range r.m {
+		if inv.TokenHash == hash {
+			return &inv, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+func (r *memInvitations) Create(_ context.Context, inv *Invitation) error {
+	r.m[inv.ID] = *inv
+	return nil
+}
+func (r *memInvitations) Update(_ context.Context, inv *Invitation) error {
+	r.m[inv.ID] = *inv
+	return nil
+}
+func (r *memInvitations) Delete(_ context.Context, id string) error { delete(r.m, id); return nil }
+
+type sentMail struct {
+	template string
+	to       []string
+	data     map[string]any
+}
+
+type stubMailer struct {
+	enabled bool
+	err     error
+	sent    []sentMail
+}
+
+func (m *stubMailer) Enabled() bool { return m.enabled }
+func (m *stubMailer) SendTemplate(_ context.Context, name string, to []string, data map[string]any) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, sentMail{name, to, data})
+	return nil
+}
+
+func newInvitationService(t *testing.T) (*Service, *stubMailer, *time.Time) {
+	t.Helper()
+	svc, _, now := newAccountService(t)
+	mailer := &stubMailer{enabled: true}
+	svc.WithInvitations(&memInvitations{m: map[string]Invitation{}}, mailer, "https://voyager.example.com/", 48*time.Hour)
+	return svc, mailer, now
+}
+
+func tokenOf(t *testing.T, link string) string {
+	t.Helper()
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	assert.Equal(t, "/invitation", u.Path)
+	return u.Query().Get("token")
+}
+
+func TestInvitation_InviteAndAccept(t *testing.T) {
+	svc, mailer, now := newInvitationService(t)
+	ctx := context.Background()
+
+	res, err := svc.Invite(ctx, &Invitation{Email: "Bob <bob@example.com>", Role: identity.RoleViewer, Datasources: []string{"ds2", "ds1"}}, "ann")
+	require.NoError(t, err)
+	assert.True(t, res.Sent)
+	assert.Equal(t, "bob@example.com", res.Invitation.Email)
+	assert.Equal(t, []string{"ds1", "ds2"}, res.Invitation.Datasources)
+	assert.Equal(t, InvitationPending, res.Invitation.Status)
+	assert.Equal(t, now.Add(48*time.Hour), res.Invitation.ExpiresAt)
+	require.Len(t, mailer.sent, 1)
+	assert.Equal(t, "invitation", mailer.sent[0].template)
+	assert.Equal(t, []string{"bob@example.com"}, mailer.sent[0].to)
+	assert.Equal(t, res.Link, mailer.sent[0].data["link"])
+	assert.Equal(t, "ann", mailer.sent[0].data["inviter"])
+
+	_, err = svc.Invite(ctx, &Invitation{Email: "bob@example.com"}, "ann")
+	assert.ErrorIs(t, err, ErrInvalid, "one open invitation per address")
+
+	token := tokenOf(t, res.Link)
+	inv, err := svc.LookupInvitation(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, "bob@example.com", inv.Email)
+	_, err = svc.LookupInvitation(ctx, "wrong")
+	assert.ErrorIs(t, err, ErrInvitationNotFound)
+
+	_, err = svc.AcceptInvitation(ctx, token, "bob", "", "weak")
+	require.ErrorIs(t, err, ErrInvalid)
+	_, err = svc.GetByUsername(ctx, "bob")
+	assert.ErrorIs(t, err, ErrNotFound, "no account left behind by a rejected password")
+
+	login, err := svc.AcceptInvitation(ctx, token, "bob", "Bob B", "hunter2hunter2")
+	require.NoError(t, err)
+	assert.NotEmpty(t, login.Token)
+	assert.Equal(t, identity.RoleViewer, login.User.Role)
+	assert.Equal(t, []string{"ds1", "ds2"}, login.User.Datasources)
+	assert.Equal(t, "bob@example.com", login.User.Email)
+
+	id, err := svc.Authenticate(ctx, login.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", id.Username)
+
+	_, err = svc.AcceptInvitation(ctx, token, "bob2", "", "hunter2hunter2")
+	assert.ErrorIs(t, err, ErrInvitationClosed)
+	accepted, err := svc.Invitations(ctx, InvitationAccepted)
+	require.NoError(t, err)
+	require.Len(t, accepted, 1)
+	assert.Equal(t, login.User.ID, accepted[0].UserID)
+
+	_, err = svc.Invite(ctx, &Invitation{Email: "bob@example.com"}, "ann")
+	assert.ErrorIs(t, err, ErrInvalid, "address already has an account")
+}
+
+func TestInvitation_ExpiryAndResend(t *testing.T) {
+	svc, mailer, now := newInvitationService(t)
+	ctx := context.Background()
+
+	res, err := svc.Invite(ctx, &Invitation{Email: "carl@example.com"}, "ann")
+	require.NoError(t, err)
+	old := tokenOf(t, res.Link)
+
+	*now = now.Add(49 * time.Hour)
+	_, err = svc.LookupInvitation(ctx, old)
+	assert.ErrorIs(t, err, ErrInvitationClosed)
+	expired, _ := svc.Invitations(ctx, InvitationExpired)
+	assert.Len(t, expired, 1)
+
+	res, err = svc.ResendInvitation(ctx, res.Invitation.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Invitation.SentCount)
+	assert.Len(t, mailer.sent, 2)
+	_, err = svc.LookupInvitation(ctx, old)
+	assert.ErrorIs(t, err, ErrInvitationNotFound, "the old link stops working")
+	_, err = svc.LookupInvitation(ctx, tokenOf(t, res.Link))
+	assert.NoError(t, err)
+
+	require.NoError(t, svc.RevokeInvitation(ctx, res.Invitation.ID))
+	_, err = svc.LookupInvitation(ctx, tokenOf(t, res.Link))
+	assert.ErrorIs(t, err, ErrInvitationNotFound)
+}
+
+func TestInvitation_WithoutEmail(t *testing.T) {
+	svc, mailer, _ := newInvitationService(t)
+	mailer.enabled = false
+
+	res, err := svc.Invite(context.Background(), &Invitation{Email: "dora@example.com"}, "ann")
+	require.NoError(t, err)
+	assert.False(t, res.Sent)
+	assert.NotEmpty(t, res.SendError)
+	assert.NotEmpty(t, tokenOf(t, res.Link), "the admin can pass the link on")
+	assert.Equal(t, 0, res.Invitation.SentCount)
+}
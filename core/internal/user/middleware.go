@@ -0,0 +1,159 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// ImpersonateHeader names the user an admin wants a request to act as.
+const ImpersonateHeader = "X-Voyager-Impersonate"
+
+// ProxyAuth is the header an authenticating reverse proxy names the caller
+// in, and the peers allowed to set it. Any client can send the header, so
+// from other peers it is ignored. The zero value turns it off.
+type ProxyAuth struct {
+	Header  string
+	Trusted []netip.Prefix
+}
+
+// NewProxyAuth builds a ProxyAuth from a header name and the trusted
+// proxies' addresses or CIDRs.
+func NewProxyAuth(header string, trusted []string) (ProxyAuth, error) {
+	p := ProxyAuth{Header: header}
+	for _, s := range trusted {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			addr, aerr := netip.ParseAddr(s)
+			if aerr != nil {
+				return ProxyAuth{}, fmt.Errorf("trusted proxy %q: %w", s, err)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		p.Trusted = append(p.Trusted, prefix.Masked())
+	}
+	return p, nil
+}
+
+// username returns the name the proxy set, or "" when the header is off,
+// absent or sent by a peer that is not a trusted proxy. The peer is the
+// connection's remote address, never X-Forwarded-For, which it could forge.
+func (p ProxyAuth) username(c *gin.Context) string {
+	if p.Header == "" {
+		return ""
+	}
+	name := c.GetHeader(p.Header)
+	if name == "" {
+		return ""
+	}
+	peer, err := netip.ParseAddrPort(c.Request.RemoteAddr)
+	if err != nil {
+		return ""
+	}
+	addr := peer.Addr().Unmap()
+	if !slices.ContainsFunc(p.Trusted, func(t netip.Prefix) bool { return t.Contains(addr) }) {
+		return ""
+	}
+	return name
+}
+
+// Middleware resolves the caller from a session token issued by the login
+// endpoint, or else from the proxy's header, which a trusted reverse proxy
+// sets after authenticating them. Without either the request runs
+// unauthenticated unless required is set; the login and invitation
+// endpoints are always reachable. With the public demo on, such a request
+// acts as the demo visitor instead, required or not, and is held to what
+// the demo allows.
+//
+// An admin — or anyone while auth is off — may act as another user by
+// naming them in ImpersonateHeader. The request then sees exactly what that
+// user would, admin routes included, and is recorded in the audit log.
+func Middleware(svc *Service, proxy ProxyAuth, required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		var caller *identity.Identity
+		if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+			id, err := svc.Authenticate(ctx, token)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": i18n.T(c, "invalid or expired session")})
+				return
+			}
+			caller = id
+		} else if name := proxy.username(c); name != "" {
+			id, err := svc.Resolve(ctx, name)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": i18n.T(c, "unknown or disabled user")})
+				return
+			}
+			caller = id
+		} else if svc.demo != nil {
+			caller = svc.demoIdentity()
+		} else if required && !public(c.FullPath()) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": i18n.T(c, "authentication required")})
+			return
+		}
+
+		effective := caller
+		if target := c.GetHeader(ImpersonateHeader); target != "" {
+			if !caller.Can(identity.PermAdmin) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "only admins can impersonate users")})
+				return
+			}
+			id, err := svc.Resolve(ctx, target)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": i18n.T(c, "unknown or disabled user")})
+				return
+			}
+			id.ImpersonatedBy = "anonymous"
+			if caller != nil {
+				id.ImpersonatedBy = caller.Username
+			}
+			effective = id
+			defer func() {
+				svc.RecordImpersonation(context.WithoutCancel(ctx), &Impersonation{
+					Actor:  id.ImpersonatedBy,
+					Target: id.Username,
+					Method: c.Request.Method,
+					Path:   c.Request.URL.Path,
+					Status: c.Writer.Status(),
+				})
+			}()
+		}
+		if effective != nil && effective.Demo && !demoAllows(c) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "not available in the demo")})
+			return
+		}
+		if effective != nil {
+			c.Request = c.Request.WithContext(identity.With(ctx, effective))
+		}
+		c.Next()
+	}
+}
+
+// public reports whether path is reachable without authentication: logging
+// in and accepting an invitation.
+func public(path string) bool {
+	for _, p := range []string{loginPath, invitationLookupPath, invitationAcceptPath} {
+		if strings.HasSuffix(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireAdmin rejects requests whose identity lacks the admin permission.
+func RequireAdmin(c *gin.Context) {
+	if !identity.FromContext(c.Request.Context()).Can(identity.PermAdmin) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "admin permission required")})
+		return
+	}
+	c.Next()
+}
@@ -0,0 +1,127 @@
+// Package user manages the users requests act as, and lets admins
+// impersonate them to reproduce permission problems. Authentication itself
+// is delegated: a trusted reverse proxy names the caller in a header.
+package user
+
+import (
+	"context"
+	"time"
+
+	"data-voyager/core/internal/identity"
+)
+
+// User is an account with a role and an optional datasource allowlist.
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	// Datasources lists the visible datasource IDs; empty means all.
+	Datasources []string `json:"datasources"`
+	// RowLimit is the LIMIT added to the user's unbounded queries on
+	// interactive datasources; 0 uses the server default.
+	RowLimit  int       `json:"row_limit"`
+	Disabled  bool      `json:"disabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// identity is the identity requests made as u act with.
+func (u *User) identity() *identity.Identity {
+	return &identity.Identity{UserID: u.ID, Username: u.Username, Role: u.Role, Datasources: u.Datasources, RowLimit: u.RowLimit}
+}
+
+// Repository persists users.
+// Implemented per-driver in store/{postgres,sqlite,mysql}/.
+type Repository interface {
+	List(ctx context.Context) ([]*User, error)
+	GetByID(ctx context.Context, id string) (*User, error)
+	GetByUsername(ctx context.Context, username string) (*User, error)
+	Create(ctx context.Context, u *User) error
+	Update(ctx context.Context, u *User) error
+	Delete(ctx context.Context, id string) error
+}
+
+// Impersonation is the audit record of one request an admin made as
+// another user.
+type Impersonation struct {
+	ID     string    `json:"id"`
+	Actor  string    `json:"actor"`
+	Target string    `json:"target"`
+	Method string    `json:"method"`
+	Path   string    `json:"path"`
+	Status int       `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+// AuditRepository persists impersonation records.
+type AuditRepository interface {
+	Record(ctx context.Context, e *Impersonation) error
+	// List returns the newest records first, optionally for one target.
+	List(ctx context.Context, target string, limit int) ([]*Impersonation, error)
+}
+
+// Credentials hold a user's local login secrets. Users authenticated only
+// by the proxy header have none.
+type Credentials struct {
+	UserID       string
+	PasswordHash string
+	// FailedAttempts counts consecutive failed logins since the last success.
+	FailedAttempts int
+	LockedUntil    *time.Time
+	// TOTPSecret is encrypted at rest. It is set during enrollment but only
+	// enforced once TOTPEnabled is confirmed with a valid code.
+	TOTPSecret  string
+	TOTPEnabled bool
+	// TOTPLastStep is the time step of the last code accepted; codes for
+	// it or earlier steps are refused so that a code cannot be replayed.
+	TOTPLastStep      int64
+	PasswordChangedAt time.Time
+}
+
+// CredentialRepository persists credentials.
+type CredentialRepository interface {
+	Get(ctx context.Context, userID string) (*Credentials, error)
+	Upsert(ctx context.Context, c *Credentials) error
+	Delete(ctx context.Context, userID string) error
+}
+
+// Invitation states.
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationExpired  = "expired"
+)
+
+// Invitation lets someone create their own local account through an emailed
+// link, with the role and datasource allowlist an admin chose. Only a hash
+// of the link's token is stored.
+type Invitation struct {
+	ID          string   `json:"id"`
+	Email       string   `json:"email"`
+	Name        string   `json:"name"`
+	Role        string   `json:"role"`
+	Datasources []string `json:"datasources"`
+	InvitedBy   string   `json:"invited_by,omitempty"`
+	TokenHash   string   `json:"-"`
+	// SentCount is how many times the link was emailed.
+	SentCount  int        `json:"sent_count"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	// UserID is the account created on acceptance.
+	UserID    string    `json:"user_id,omitempty"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// InvitationRepository persists invitations.
+type InvitationRepository interface {
+	List(ctx context.Context) ([]*Invitation, error)
+	GetByID(ctx context.Context, id string) (*Invitation, error)
+	GetByTokenHash(ctx context.Context, hash string) (*Invitation, error)
+	Create(ctx context.Context, inv *Invitation) error
+	Update(ctx context.Context, inv *Invitation) error
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,243 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/identity"
+)
+
+var (
+	// ErrInvalid wraps validation failures so handlers can map them to 400.
+	ErrInvalid = errors.New("invalid user")
+	// ErrNotFound is returned for an unknown user.
+	ErrNotFound = errors.New("user not found")
+)
+
+var usernameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._@\-]{0,127}$`)
+
+// Service manages users and resolves request identities.
+type Service struct {
+	repo  Repository
+	audit AuditRepository
+	conns connection.Repository
+	now   func() time.Time
+
+	// Local accounts; see WithAccounts.
+	creds      CredentialRepository
+	policy     config.PasswordPolicy
+	encryptKey []byte
+	signer     *sessionSigner
+	sessionTTL time.Duration
+	totpMu     sync.Mutex
+
+	// Invitations; see WithInvitations.
+	invitations   InvitationRepository
+	mailer        Mailer
+	publicURL     string
+	invitationTTL time.Duration
+	acceptMu      sync.Mutex
+
+	// demo is who anonymous visitors act as; see WithDemo.
+	demo *identity.Identity
+}
+
+// NewService creates a Service.
+func NewService(repo Repository, audit AuditRepository, conns connection.Repository) *Service {
+	return &Service{repo: repo, audit: audit, conns: conns, now: time.Now}
+}
+
+// List returns every user.
+func (s *Service) List(ctx context.Context) ([]*User, error) {
+	return s.repo.List(ctx)
+}
+
+// Get returns a user by ID.
+func (s *Service) Get(ctx context.Context, id string) (*User, error) {
+	u, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	return u, nil
+}
+
+// GetByUsername returns a user by username.
+func (s *Service) GetByUsername(ctx context.Context, username string) (*User, error) {
+	u, err := s.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	return u, nil
+}
+
+// Create validates and stores a new user.
+func (s *Service) Create(ctx context.Context, u *User) error {
+	if err := s.validate(ctx, u); err != nil {
+		return err
+	}
+	if _, err := s.repo.GetByUsername(ctx, u.Username); err == nil {
+		return fmt.Errorf("%w: username %q is taken", ErrInvalid, u.Username)
+	}
+	u.ID = uuid.NewString()
+	u.CreatedAt = s.now().UTC()
+	u.UpdatedAt = u.CreatedAt
+	return s.repo.Create(ctx, u)
+}
+
+// Update replaces a user's attributes. The username cannot change.
+func (s *Service) Update(ctx context.Context, u *User) error {
+	existing, err := s.Get(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	u.Username, u.CreatedAt = existing.Username, existing.CreatedAt
+	if err := s.validate(ctx, u); err != nil {
+		return err
+	}
+	u.UpdatedAt = s.now().UTC()
+	return s.repo.Update(ctx, u)
+}
+
+// Delete removes a user.
+func (s *Service) Delete(ctx context.Context, id string) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	if s.creds != nil {
+		if err := s.creds.Delete(ctx, id); err != nil {
+			return err
+		}
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Anonymize strips a departing user's personal details and disables the
+// account, keeping the row so records that refer to its ID stay valid. The
+// username is left to the caller, which renames it everywhere at once.
+func (s *Service) Anonymize(ctx context.Context, id string) (*User, error) {
+	u, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if s.creds != nil {
+		if err := s.creds.Delete(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	u.Email, u.Name, u.Disabled = "", "", true
+	u.Datasources, u.RowLimit = []string{}, 0
+	u.UpdatedAt = s.now().UTC()
+	if err := s.repo.Update(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (s *Service) validate(ctx context.Context, u *User) error {
+	if !usernameRe.MatchString(u.Username) {
+		return fmt.Errorf("%w: username must be 1-128 letters, digits or . _ @ -", ErrInvalid)
+	}
+	if u.Role == "" {
+		u.Role = identity.RoleViewer
+	}
+	if !identity.ValidRole(u.Role) {
+		return fmt.Errorf("%w: unknown role %q", ErrInvalid, u.Role)
+	}
+	slices.Sort(u.Datasources)
+	u.Datasources = slices.Compact(u.Datasources)
+	for _, id := range u.Datasources {
+		if _, err := s.conns.GetByID(ctx, id); err != nil {
+			return fmt.Errorf("%w: datasource %s not found", ErrInvalid, id)
+		}
+	}
+	if u.Datasources == nil {
+		u.Datasources = []string{}
+	}
+	if u.RowLimit < 0 {
+		return fmt.Errorf("%w: row_limit must not be negative", ErrInvalid)
+	}
+	return nil
+}
+
+// Resolve returns the identity of an active user.
+func (s *Service) Resolve(ctx context.Context, username string) (*identity.Identity, error) {
+	u, err := s.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, username)
+	}
+	if u.Disabled {
+		return nil, fmt.Errorf("%w: %s is disabled", ErrNotFound, username)
+	}
+	return u.identity(), nil
+}
+
+// DatasourceRef names a datasource in an access report.
+type DatasourceRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Access is the effective access of one user.
+type Access struct {
+	User        *User           `json:"user"`
+	Permissions []string        `json:"permissions"`
+	Datasources []DatasourceRef `json:"datasources"`
+	// AllDatasources is true when the user has no datasource allowlist.
+	AllDatasources bool `json:"all_datasources"`
+}
+
+// Access reports what a user can do and which datasources they see,
+// including disabled users so admins can see why a login is refused.
+func (s *Service) Access(ctx context.Context, username string) (*Access, error) {
+	u, err := s.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, username)
+	}
+	id := u.identity()
+	conns, err := s.conns.List(ctx, connection.Filter{})
+	if err != nil {
+		return nil, err
+	}
+	a := &Access{
+		User:           u,
+		Permissions:    identity.Permissions(u.Role),
+		Datasources:    []DatasourceRef{},
+		AllDatasources: len(u.Datasources) == 0,
+	}
+	if u.Disabled {
+		a.Permissions = []string{}
+		return a, nil
+	}
+	for _, c := range conns {
+		if id.CanSee(c.ID) {
+			a.Datasources = append(a.Datasources, DatasourceRef{ID: c.ID, Name: c.Name, Type: string(c.Type)})
+		}
+	}
+	return a, nil
+}
+
+// RecordImpersonation writes an audit record. Failures are logged rather
+// than failing the request that was already served.
+func (s *Service) RecordImpersonation(ctx context.Context, e *Impersonation) {
+	e.ID = uuid.NewString()
+	e.At = s.now().UTC()
+	if err := s.audit.Record(ctx, e); err != nil {
+		slog.Error("failed to record impersonation", "actor", e.Actor, "target", e.Target, "err", err)
+	}
+}
+
+// Impersonations lists audit records, newest first.
+func (s *Service) Impersonations(ctx context.Context, target string, limit int) ([]*Impersonation, error) {
+	return s.audit.List(ctx, target, limit)
+}
@@ -0,0 +1,261 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/identity"
+)
+
+type memRepo struct{ users []*User }
+
+func (r *memRepo) List(context.Context) ([]*User, error) { return r.users, nil }
+func (r *memRepo) GetByID(_ context.Context, id string) (*User, error) {
+	for _, u := range r.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+func (r *memRepo) GetByUsername(_ context.Context, name string) (*User, error) {
+	for _, u := range r.users {
+		if u.Username == name {
+			return u, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+func (r *memRepo) Create(_ context.Context, u *User) error { r.users = append(r.users, u); return nil }
+func (r *memRepo) Update(context.Context, *User) error     { return nil }
+func (r *memRepo) Delete(context.Context, string) error    { return nil }
+
+type memAudit struct {
+	mu      sync.Mutex
+	records []*Impersonation
+}
+
+func (a *memAudit) Record(_ context.Context, e *Impersonation) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.records = append(a.records, e)
+	return nil
+}
+func (a *memAudit) List(context.Context, string, int) ([]*Impersonation, error) {
+	return a.records, nil
+}
+
+type stubConns struct{ connection.Repository }
+
+func (stubConns) GetByID(_ context.Context, id string) (*connection.Connection, error) {
+	if id == "ds1" || id == "ds2" {
+		return &connection.Connection{ID: id}, nil
+	}
+	return nil, errors.New("not found")
+}
+func (stubConns) List(context.Context, connection.Filter) ([]*connection.Connection, error) {
+	return []*connection.Connection{
+		{ID: "ds1", Name: "sales", Type: "postgresql"},
+		{ID: "ds2", Name: "events", Type: "clickhouse"},
+	}, nil
+}
+
+// testProxy trusts the peer address httptest requests come from.
+var testProxy = ProxyAuth{Header: "X-Forwarded-User", Trusted: []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")}}
+
+func newTestServer(t *testing.T, required bool) (*gin.Engine, *memAudit) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	audit := &memAudit{}
+	svc := NewService(&memRepo{}, audit, stubConns{})
+	ctx := context.Background()
+	require.NoError(t, svc.Create(ctx, &User{Username: "root", Role: identity.RoleAdmin}))
+	require.NoError(t, svc.Create(ctx, &User{Username: "ann", Role: identity.RoleViewer, Datasources: []string{"ds2"}}))
+	require.NoError(t, svc.Create(ctx, &User{Username: "gone", Disabled: true}))
+
+	r := gin.New()
+	api := r.Group("/api/v1", Middleware(svc, testProxy, required))
+	RegisterRoutes(api, NewHandler(svc))
+	return r, audit
+}
+
+func do(r *gin.Engine, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestImpersonation(t *testing.T) {
+	r, audit := newTestServer(t, true)
+
+	w := do(r, "/api/v1/whoami", map[string]string{"X-Forwarded-User": "root", ImpersonateHeader: "ann"})
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct{ Data whoamiResponse }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "ann", resp.Data.Username)
+	assert.Equal(t, identity.RoleViewer, resp.Data.Role)
+	assert.Equal(t, "root", resp.Data.ImpersonatedBy)
+	assert.Equal(t, []string{"ds2"}, resp.Data.Datasources)
+
+	// While impersonating, admin routes are judged as the target user.
+	w = do(r, "/api/v1/admin/users", map[string]string{"X-Forwarded-User": "root", ImpersonateHeader: "ann"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	require.Len(t, audit.records, 2)
+	assert.Equal(t, "root", audit.records[0].Actor)
+	assert.Equal(t, "ann", audit.records[0].Target)
+	assert.Equal(t, "/api/v1/whoami", audit.records[0].Path)
+	assert.Equal(t, http.StatusOK, audit.records[0].Status)
+	assert.Equal(t, http.StatusForbidden, audit.records[1].Status)
+}
+
+func TestMiddleware_Rejections(t *testing.T) {
+	r, audit := newTestServer(t, true)
+
+	assert.Equal(t, http.StatusUnauthorized, do(r, "/api/v1/whoami", nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, do(r, "/api/v1/whoami", map[string]string{"X-Forwarded-User": "nobody"}).Code)
+	assert.Equal(t, http.StatusUnauthorized, do(r, "/api/v1/whoami", map[string]string{"X-Forwarded-User": "gone"}).Code)
+	assert.Equal(t, http.StatusForbidden, do(r, "/api/v1/whoami",
+		map[string]string{"X-Forwarded-User": "ann", ImpersonateHeader: "root"}).Code)
+	assert.Equal(t, http.StatusBadRequest, do(r, "/api/v1/whoami",
+		map[string]string{"X-Forwarded-User": "root", ImpersonateHeader: "gone"}).Code)
+	assert.Empty(t, audit.records)
+
+	// Without required auth, anonymous requests pass and may impersonate.
+	r, audit = newTestServer(t, false)
+	assert.Equal(t, http.StatusOK, do(r, "/api/v1/whoami", nil).Code)
+	assert.Equal(t, http.StatusOK, do(r, "/api/v1/whoami", map[string]string{ImpersonateHeader: "ann"}).Code)
+	require.Len(t, audit.records, 1)
+	assert.Equal(t, "anonymous", audit.records[0].Actor)
+}
+
+func TestWhoAmIAs(t *testing.T) {
+	r, _ := newTestServer(t, true)
+
+	w := do(r, "/api/v1/admin/whoami-as?user=ann", map[string]string{"X-Forwarded-User": "root"})
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct{ Data Access }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []string{identity.PermDatasourceRead, identity.PermDatasourceQuery}, resp.Data.Permissions)
+	assert.False(t, resp.Data.AllDatasources)
+	assert.Equal(t, []DatasourceRef{{ID: "ds2", Name: "events", Type: "clickhouse"}}, resp.Data.Datasources)
+
+	w = do(r, "/api/v1/admin/whoami-as?user=gone", map[string]string{"X-Forwarded-User": "root"})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Empty(t, resp.Data.Permissions)
+	assert.Empty(t, resp.Data.Datasources)
+
+	assert.Equal(t, http.StatusNotFound, do(r, "/api/v1/admin/whoami-as?user=nobody", map[string]string{"X-Forwarded-User": "root"}).Code)
+	assert.Equal(t, http.StatusForbidden, do(r, "/api/v1/admin/whoami-as?user=root", map[string]string{"X-Forwarded-User": "ann"}).Code)
+}
+
+func TestService_Validate(t *testing.T) {
+	svc := NewService(&memRepo{}, &memAudit{}, stubConns{})
+	ctx := context.Background()
+	assert.ErrorIs(t, svc.Create(ctx, &User{Username: "bad name"}), ErrInvalid)
+	assert.ErrorIs(t, svc.Create(ctx, &User{Username: "x", Role: "owner"}), ErrInvalid)
+	assert.ErrorIs(t, svc.Create(ctx, &User{Username: "x", Datasources: []string{"missing"}}), ErrInvalid)
+
+	u := &User{Username: "x", Datasources: []string{"ds2", "ds1", "ds2"}}
+	require.NoError(t, svc.Create(ctx, u))
+	assert.Equal(t, identity.RoleViewer, u.Role)
+	assert.Equal(t, []string{"ds1", "ds2"}, u.Datasources)
+	assert.ErrorIs(t, svc.Create(ctx, &User{Username: "x"}), ErrInvalid)
+}
+
+func TestMiddleware_Demo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := NewService(&memRepo{}, &memAudit{}, stubConns{}).
+		WithDemo(config.DemoConfig{Enabled: true, Datasources: []string{"ds1"}, RowLimit: 50})
+	require.NoError(t, svc.Create(context.Background(), &User{Username: "root", Role: identity.RoleAdmin}))
+
+	r := gin.New()
+	api := r.Group("/api/v1", Middleware(svc, testProxy, true))
+	RegisterRoutes(api, NewHandler(svc))
+	var seen *identity.Identity
+	record := func(c *gin.Context) { seen = identity.FromContext(c.Request.Context()) }
+	api.POST("/datasources/:uid/query", record)
+	api.POST("/datasources/:uid/query/async", record)
+	api.GET("/exports/:id/download", record)
+	api.GET("/dashboards/:id", record)
+	api.GET("/dashboards/:id/grafana", record)
+	api.GET("/snapshots/:id/render", record)
+
+	w := do(r, "/api/v1/whoami", nil)
+	require.Equal(t, http.StatusOK, w.Code, "anonymous visitors browse the demo even with auth required")
+	var resp struct{ Data whoamiResponse }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Data.Demo)
+	assert.False(t, resp.Data.Authenticated)
+	assert.Equal(t, []string{"ds1"}, resp.Data.Datasources)
+
+	send := func(method, path string) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w.Code
+	}
+	assert.Equal(t, http.StatusOK, send(http.MethodPost, "/api/v1/datasources/ds1/query"))
+	require.NotNil(t, seen)
+	assert.True(t, seen.Demo)
+	assert.Equal(t, 50, seen.RowLimit)
+	assert.Equal(t, http.StatusForbidden, send(http.MethodPost, "/api/v1/datasources/ds1/query/async"))
+	assert.Equal(t, http.StatusForbidden, send(http.MethodGet, "/api/v1/exports/x/download"))
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/v1/dashboards/d1"))
+	assert.Equal(t, http.StatusForbidden, send(http.MethodGet, "/api/v1/dashboards/d1/grafana"))
+	assert.Equal(t, http.StatusForbidden, send(http.MethodGet, "/api/v1/snapshots/s1/render?format=pdf"))
+	assert.Equal(t, http.StatusForbidden, send(http.MethodGet, "/api/v1/snapshots/s1/render?format=png"))
+	assert.Equal(t, http.StatusForbidden, send(http.MethodPost, "/api/v1/account/password"))
+	assert.Equal(t, http.StatusForbidden, do(r, "/api/v1/whoami", map[string]string{ImpersonateHeader: "root"}).Code)
+
+	// Signed-in users are not held to the demo.
+	w = do(r, "/api/v1/whoami", map[string]string{"X-Forwarded-User": "root"})
+	var signedIn struct{ Data whoamiResponse }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &signedIn))
+	assert.False(t, signedIn.Data.Demo)
+	assert.Equal(t, "root", signedIn.Data.Username)
+}
+
+func TestMiddleware_ProxyHeaderOnlyFromTrustedPeers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := NewService(&memRepo{}, &memAudit{}, stubConns{})
+	require.NoError(t, svc.Create(context.Background(), &User{Username: "root", Role: identity.RoleAdmin}))
+	proxy, err := NewProxyAuth("X-Forwarded-User", []string{"10.0.0.0/8", "2001:db8::1"})
+	require.NoError(t, err)
+
+	whoami := func(proxy ProxyAuth, peer string) *httptest.ResponseRecorder {
+		r := gin.New()
+		RegisterRoutes(r.Group("/api/v1", Middleware(svc, proxy, true)), NewHandler(svc))
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/whoami", nil)
+		req.RemoteAddr = peer
+		req.Header.Set("X-Forwarded-User", "root")
+		req.Header.Set("X-Forwarded-For", "10.0.0.1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	assert.Equal(t, http.StatusOK, whoami(proxy, "10.1.2.3:5000").Code)
+	assert.Equal(t, http.StatusOK, whoami(proxy, "[2001:db8::1]:5000").Code)
+	assert.Equal(t, http.StatusUnauthorized, whoami(proxy, "203.0.113.9:5000").Code,
+		"the header from an untrusted peer is ignored, whatever X-Forwarded-For says")
+	assert.Equal(t, http.StatusUnauthorized, whoami(ProxyAuth{}, "10.1.2.3:5000").Code, "off by default")
+
+	_, err = NewProxyAuth("X-Forwarded-User", []string{"proxy.internal"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/authz"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/identity"
+)
+
+// Handler serves the webhook endpoints.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates a webhook HTTP handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+type deliverRequest struct {
+	DatasourceID string         `json:"datasource_id" binding:"required"`
+	Query        string         `json:"query" binding:"required"`
+	Variables    map[string]any `json:"variables"`
+	From         time.Time      `json:"from"`
+	To           time.Time      `json:"to"`
+}
+
+// Targets handles GET /webhooks
+func (h *Handler) Targets(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.svc.Targets()})
+}
+
+// Deliver handles POST /webhooks/:name/deliver. The query runs before the
+// response; sending happens in the background.
+func (h *Handler) Deliver(c *gin.Context) {
+	caller := identity.FromContext(c.Request.Context())
+	if !caller.Can(identity.PermDatasourceQuery) {
+		c.JSON(http.StatusForbidden, gin.H{"error": i18n.T(c, "permission denied")})
+		return
+	}
+	var req deliverRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	d, err := h.svc.Deliver(c.Request.Context(), Request{
+		Target:       c.Param("name"),
+		DatasourceID: req.DatasourceID,
+		Query:        req.Query,
+		Variables:    req.Variables,
+		From:         req.From,
+		To:           req.To,
+		Owner:        username(caller),
+	})
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"data": d})
+}
+
+// Deliveries handles GET /webhook-deliveries. Admins see every delivery,
+// others their own.
+func (h *Handler) Deliveries(c *gin.Context) {
+	caller := identity.FromContext(c.Request.Context())
+	c.JSON(http.StatusOK, gin.H{"data": h.svc.List(username(caller), caller.Can(identity.PermAdmin))})
+}
+
+// Delivery handles GET /webhook-deliveries/:id
+func (h *Handler) Delivery(c *gin.Context) {
+	d, err := h.svc.Get(c.Param("id"))
+	caller := identity.FromContext(c.Request.Context())
+	if err == nil && d.Owner != username(caller) && !caller.Can(identity.PermAdmin) {
+		err = ErrNotFound
+	}
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": d})
+}
+
+func (h *Handler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(c, "webhook not found")})
+	case errors.Is(err, ErrInvalid), errors.Is(err, connection.ErrRawLiterals):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, authz.ErrDenied), errors.Is(err, connection.ErrNetworkPolicy):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func username(id *identity.Identity) string {
+	if id == nil {
+		return ""
+	}
+	return id.Username
+}
+
+// RegisterRoutes wires the handler onto r.
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/webhooks", h.Targets)
+	r.POST("/webhooks/:name/deliver", h.Deliver)
+	r.GET("/webhook-deliveries", h.Deliveries)
+	r.GET("/webhook-deliveries/:id", h.Delivery)
+}
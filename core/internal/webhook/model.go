@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"errors"
+	"time"
+)
+
+// Status is where a delivery is in its lifecycle.
+type Status string
+
+const (
+	StatusPending   Status = "pending"   // sending or waiting to retry
+	StatusDelivered Status = "delivered" // the target answered 2xx
+	StatusFailed    Status = "failed"    // attempts ran out or the target refused it
+)
+
+// Sources of a delivery.
+const (
+	SourceAdhoc    = "adhoc"
+	SourceSchedule = "schedule"
+	SourceCDC      = "cdc"
+)
+
+var (
+	// ErrInvalid wraps validation and query failures so handlers can map
+	// them to 400.
+	ErrInvalid = errors.New("invalid webhook delivery")
+	// ErrNotFound is returned for unknown targets and deliveries.
+	ErrNotFound = errors.New("webhook not found")
+)
+
+// Delivery is one query result sent to a target, with the outcome of its
+// attempts so far. Deliveries are kept in memory only.
+type Delivery struct {
+	ID     string `json:"id"`
+	Target string `json:"target"`
+	Source string `json:"source"`
+	// Schedule names the schedule that made the delivery, if any.
+	Schedule string `json:"schedule,omitempty"`
+	// Stream names the change data capture stream that made the delivery,
+	// if any.
+	Stream       string `json:"stream,omitempty"`
+	DatasourceID string `json:"datasource_id"`
+	// Owner is the username that asked for an ad-hoc delivery; empty for
+	// schedules and when authentication is off.
+	Owner  string `json:"owner,omitempty"`
+	Format string `json:"format"`
+	Rows   int    `json:"rows"`
+	// Truncated is set when the result had more rows than are delivered.
+	Truncated bool   `json:"truncated,omitempty"`
+	Status    Status `json:"status"`
+	Attempts  int    `json:"attempts"`
+	// StatusCode is the HTTP status of the last attempt, 0 when it got no
+	// response.
+	StatusCode  int        `json:"status_code,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+}
+
+// Target describes a configured target without its secret.
+type Target struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Format string `json:"format"`
+	Signed bool   `json:"signed"`
+}
+
+// Request describes an ad-hoc delivery.
+type Request struct {
+	Target       string
+	DatasourceID string
+	Query        string
+	Variables    map[string]any
+	// From and To bound the time range macros and template variables see;
+	// zero values mean the hour before now.
+	From, To time.Time
+	Owner    string
+}
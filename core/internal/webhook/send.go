@@ -0,0 +1,253 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/render"
+	"data-voyager/sdk"
+)
+
+// Headers sent with every delivery.
+const (
+	HeaderDelivery  = "X-Voyager-Delivery"
+	HeaderTimestamp = "X-Voyager-Timestamp"
+	// HeaderSignature is "sha256=" and the hex HMAC-SHA256, keyed with the
+	// target's secret, of the timestamp header, a '.' and the body.
+	HeaderSignature = "X-Voyager-Signature"
+)
+
+// maxBackoff caps the wait between attempts.
+const maxBackoff = 5 * time.Minute
+
+// payload is the JSON body of a delivery. Rows are arrays in column order.
+type payload struct {
+	DeliveryID   string    `json:"delivery_id"`
+	Source       string    `json:"source"`
+	Schedule     string    `json:"schedule,omitempty"`
+	Stream       string    `json:"stream,omitempty"`
+	DatasourceID string    `json:"datasource_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	Columns      []string  `json:"columns"`
+	Rows         [][]any   `json:"rows"`
+	Truncated    bool      `json:"truncated,omitempty"`
+}
+
+// encode renders the first frame of res as the body of d. It returns the
+// rows included and whether rows past rowLimit were dropped. html, png and
+// pdf bodies are rendered documents of the whole result.
+func (s *Service) encode(ctx context.Context, format string, d *Delivery, res *sdk.QueryResult) ([]byte, int, bool, error) {
+	if render.ValidFormat(format) {
+		return s.renderBody(ctx, format, d, res)
+	}
+	var fields []sdk.Field
+	if res != nil && len(res.Frames) > 0 {
+		fields = res.Frames[0].Fields
+	}
+	columns := make([]string, len(fields))
+	n := 0
+	for i, f := range fields {
+		columns[i] = f.Name
+		n = max(n, len(f.Values))
+	}
+	truncated := n > rowLimit
+	n = min(n, rowLimit)
+	value := func(f sdk.Field, r int) any {
+		if r < len(f.Values) {
+			return f.Values[r]
+		}
+		return nil
+	}
+
+	if format == "csv" {
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if err := w.Write(columns); err != nil {
+			return nil, 0, false, err
+		}
+		record := make([]string, len(fields))
+		for r := range n {
+			for i, f := range fields {
+				record[i] = cell(value(f, r))
+			}
+			if err := w.Write(record); err != nil {
+				return nil, 0, false, err
+			}
+		}
+		w.Flush()
+		return buf.Bytes(), n, truncated, w.Error()
+	}
+
+	rows := make([][]any, n)
+	for r := range rows {
+		row := make([]any, len(fields))
+		for i, f := range fields {
+			v := value(f, r)
+			if b, ok := v.([]byte); ok {
+				v = string(b) // rather than base64
+			}
+			row[i] = v
+		}
+		rows[r] = row
+	}
+	body, err := json.Marshal(payload{
+		DeliveryID:   d.ID,
+		Source:       d.Source,
+		Schedule:     d.Schedule,
+		Stream:       d.Stream,
+		DatasourceID: d.DatasourceID,
+		CreatedAt:    d.CreatedAt,
+		Columns:      columns,
+		Rows:         rows,
+		Truncated:    truncated,
+	})
+	return body, n, truncated, err
+}
+
+// renderBody renders res as a one-panel document titled after d.
+func (s *Service) renderBody(ctx context.Context, format string, d *Delivery, res *sdk.QueryResult) ([]byte, int, bool, error) {
+	title := "Query result"
+	if d.Schedule != "" {
+		title = d.Schedule
+	}
+	doc := &render.Document{
+		Title:    title,
+		Subtitle: "Datasource " + d.DatasourceID,
+		At:       d.CreatedAt,
+		Panels:   []render.Panel{{Result: res}},
+	}
+	body, err := s.renderer.Render(ctx, doc, format)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	rows := 0
+	if res != nil && len(res.Frames) > 0 {
+		for _, f := range res.Frames[0].Fields {
+			rows = max(rows, len(f.Values))
+		}
+	}
+	return body, rows, false, nil
+}
+
+func cell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Sign returns the HeaderSignature value for a body sent at timestamp.
+// Receivers recompute it to check a delivery came from this server.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// send attempts d until the target accepts it, refuses it, or attempts run
+// out. Network errors, 429 and 5xx are retried with backoff; any other
+// status is final. A delivery that fails raises a notification.
+func (s *Service) send(ctx context.Context, target config.WebhookTarget, d *Delivery, body []byte) {
+	attempts := target.MaxAttempts
+	if attempts <= 0 {
+		attempts = defaultMaxAttempts
+	}
+	for n := 1; ; n++ {
+		code, err := s.attempt(ctx, target, d.ID, body)
+		if err == nil && code/100 == 2 {
+			s.update(d, func(d *Delivery) {
+				now := s.now().UTC()
+				d.Status, d.Attempts, d.StatusCode, d.Error, d.DeliveredAt = StatusDelivered, n, code, "", &now
+			})
+			return
+		}
+		if err == nil {
+			err = fmt.Errorf("target answered %d", code)
+		}
+		retry := code == 0 || code == http.StatusTooManyRequests || code >= 500
+		final := !retry || n >= attempts
+		s.update(d, func(d *Delivery) {
+			d.Attempts, d.StatusCode, d.Error = n, code, err.Error()
+			if final {
+				d.Status = StatusFailed
+			}
+		})
+		if final {
+			slog.Warn("webhook delivery failed", "target", target.Name, "delivery", d.ID, "attempts", n, "err", err)
+			s.alert(fmt.Sprintf("Webhook delivery to %s failed", target.Name),
+				fmt.Sprintf("Delivery %s failed after %d attempt(s): %v", d.ID, n, err),
+				map[string]string{"target": target.Name, "delivery_id": d.ID, "datasource_id": d.DatasourceID})
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.backoff(n)):
+		}
+	}
+}
+
+// attempt POSTs body once and returns the response status.
+func (s *Service) attempt(ctx context.Context, target config.WebhookTarget, id string, body []byte) (int, error) {
+	timeout := time.Duration(target.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range target.Headers {
+		req.Header.Set(k, v)
+	}
+	switch {
+	case target.Format == "csv":
+		req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+	case render.ValidFormat(target.Format):
+		req.Header.Set("Content-Type", render.ContentType(target.Format))
+	default:
+		req.Header.Set("Content-Type", "application/json")
+	}
+	ts := strconv.FormatInt(s.now().Unix(), 10)
+	req.Header.Set(HeaderDelivery, id)
+	req.Header.Set(HeaderTimestamp, ts)
+	if target.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(target.Secret, ts, body))
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
+
+// backoff doubles from one second up to maxBackoff.
+func backoff(n int) time.Duration {
+	return min(time.Second<<min(n-1, 20), maxBackoff)
+}
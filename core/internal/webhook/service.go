@@ -0,0 +1,345 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"data-voyager/core/internal/authz"
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/notification"
+	qb "data-voyager/core/internal/query_builder"
+	"data-voyager/core/internal/render"
+	"data-voyager/sdk"
+)
+
+const (
+	defaultTimeout     = 10 * time.Second
+	defaultMaxAttempts = 5
+	// rowLimit caps the rows sent per delivery; it is also {{ __limit }}.
+	rowLimit = 10000
+	// queryTimeout bounds an ad-hoc query.
+	queryTimeout = time.Minute
+	// deliveryLogSize is how many deliveries are kept for the status API.
+	deliveryLogSize = 500
+)
+
+// Service runs queries and delivers their results to configured webhooks.
+type Service struct {
+	targets   map[string]config.WebhookTarget
+	schedules []config.WebhookSchedule
+	conns     connection.Repository
+	registry  *datasource.Registry
+	notifier  notification.Notifier
+	renderer  *render.Renderer          // see WithRenderer
+	literals  *connection.LiteralPolicy // see WithLiteralPolicy
+	client    *http.Client
+	now       func() time.Time
+	// backoff is the wait before the attempt after attempt n.
+	backoff func(n int) time.Duration
+
+	mu         sync.Mutex
+	deliveries []*Delivery // oldest first
+	// next holds when each schedule is due, filled lazily by the scheduler.
+	next map[string]time.Time
+	// sending tracks deliveries still being attempted.
+	sending sync.WaitGroup
+}
+
+// NewService creates a Service for the configured targets and schedules.
+// notifier may be nil to only record failures.
+func NewService(cfg config.WebhooksConfig, conns connection.Repository, registry *datasource.Registry, notifier notification.Notifier) *Service {
+	targets := make(map[string]config.WebhookTarget, len(cfg.Targets))
+	for _, t := range cfg.Targets {
+		if t.Format == "" {
+			t.Format = "json"
+		}
+		targets[t.Name] = t
+	}
+	return &Service{
+		targets:   targets,
+		schedules: cfg.Schedules,
+		conns:     conns,
+		registry:  registry,
+		notifier:  notifier,
+		client:    &http.Client{},
+		now:       time.Now,
+		backoff:   backoff,
+		next:      map[string]time.Time{},
+	}
+}
+
+// WithRenderer sets how html, png and pdf deliveries are rendered.
+func (s *Service) WithRenderer(r *render.Renderer) *Service {
+	s.renderer = r
+	return s
+}
+
+// WithLiteralPolicy holds the queries callers deliver to the parameter
+// policy p. Schedules come from the config and run without a caller, so
+// they are exempt.
+func (s *Service) WithLiteralPolicy(p *connection.LiteralPolicy) *Service {
+	s.literals = p
+	return s
+}
+
+// Targets lists the configured targets, secrets left out.
+func (s *Service) Targets() []Target {
+	out := make([]Target, 0, len(s.targets))
+	for _, t := range s.targets {
+		out = append(out, Target{Name: t.Name, URL: t.URL, Format: t.Format, Signed: t.Secret != ""})
+	}
+	slices.SortFunc(out, func(a, b Target) int { return strings.Compare(a.Name, b.Name) })
+	return out
+}
+
+// Deliver runs req's query and sends the result to its target in the
+// background. The returned delivery is pending; poll Get for the outcome.
+// A query that fails is reported here and nothing is sent.
+func (s *Service) Deliver(ctx context.Context, req Request) (*Delivery, error) {
+	target, ok := s.targets[req.Target]
+	if !ok {
+		return nil, fmt.Errorf("%w: target %q", ErrNotFound, req.Target)
+	}
+	if req.Query == "" {
+		return nil, fmt.Errorf("%w: query is required", ErrInvalid)
+	}
+	if !identity.FromContext(ctx).CanSee(req.DatasourceID) {
+		return nil, fmt.Errorf("%w: datasource %q not found", ErrInvalid, req.DatasourceID)
+	}
+	tr := qb.TimeRange{From: req.From, To: req.To}
+	if tr.To.IsZero() {
+		tr.To = s.now()
+	}
+	if tr.From.IsZero() {
+		tr.From = tr.To.Add(-time.Hour)
+	}
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	res, err := s.query(ctx, req.DatasourceID, req.Query, req.Variables, tr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	d := &Delivery{Source: SourceAdhoc, DatasourceID: req.DatasourceID, Owner: req.Owner}
+	if err := s.start(ctx, target, d, res); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Publish sends res to the named target in the background, for callers
+// that produce results themselves rather than from a query. d supplies the
+// source and what made the delivery; it is filled in like Deliver's.
+func (s *Service) Publish(ctx context.Context, targetName string, d *Delivery, res *sdk.QueryResult) (*Delivery, error) {
+	target, ok := s.targets[targetName]
+	if !ok {
+		return nil, fmt.Errorf("%w: target %q", ErrNotFound, targetName)
+	}
+	if err := s.start(ctx, target, d, res); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// List returns recent deliveries, newest first. Non-admins see only their
+// own.
+func (s *Service) List(owner string, all bool) []*Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]*Delivery, 0, len(s.deliveries))
+	for i := len(s.deliveries) - 1; i >= 0; i-- {
+		if d := s.deliveries[i]; all || d.Owner == owner {
+			c := *d
+			out = append(out, &c)
+		}
+	}
+	return out
+}
+
+// Get returns a delivery.
+func (s *Service) Get(id string) (*Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, d := range s.deliveries {
+		if d.ID == id {
+			c := *d
+			return &c, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// start encodes res for target, records d and begins sending it. d is
+// filled in and is a snapshot; later progress shows in Get.
+func (s *Service) start(ctx context.Context, target config.WebhookTarget, d *Delivery, res *sdk.QueryResult) error {
+	now := s.now().UTC()
+	d.ID = uuid.NewString()
+	d.Target = target.Name
+	d.Format = target.Format
+	d.Status = StatusPending
+	d.CreatedAt, d.UpdatedAt = now, now
+	body, rows, truncated, err := s.encode(ctx, target.Format, d, res)
+	if err != nil {
+		return err
+	}
+	d.Rows, d.Truncated = rows, truncated
+
+	rec := *d
+	s.mu.Lock()
+	s.deliveries = append(s.deliveries, &rec)
+	if len(s.deliveries) > deliveryLogSize {
+		s.deliveries = s.deliveries[len(s.deliveries)-deliveryLogSize:]
+	}
+	s.mu.Unlock()
+
+	s.sending.Add(1)
+	go func() {
+		defer s.sending.Done()
+		s.send(context.Background(), target, &rec, body)
+	}()
+	return nil
+}
+
+// update applies fn to a recorded delivery under the lock.
+func (s *Service) update(d *Delivery, fn func(*Delivery)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(d)
+	d.UpdatedAt = s.now().UTC()
+}
+
+// query runs a query the way monitors do: templates, then macros, then the
+// datasource.
+func (s *Service) query(ctx context.Context, datasourceID, query string, vars map[string]any, tr qb.TimeRange) (*sdk.QueryResult, error) {
+	conn, err := s.conns.GetByID(ctx, datasourceID)
+	if err != nil {
+		return nil, fmt.Errorf("datasource %q not found", datasourceID)
+	}
+	if err := authz.CheckQuery(ctx, conn.ID); err != nil {
+		return nil, err
+	}
+	if err := connection.CheckOrigin(ctx, conn); err != nil {
+		return nil, err
+	}
+	if err := s.literals.Enforce(ctx, conn, query); err != nil {
+		return nil, err
+	}
+	plugin, ok := s.registry.Get(conn.Type)
+	if !ok {
+		return nil, fmt.Errorf("plugin not found for type %s", conn.Type)
+	}
+	cfg, err := plugin.ParseConfig(conn.Config)
+	if err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	query, err = qb.RenderQuery(query, qb.BuildContext(tr, vars, rowLimit))
+	if err != nil {
+		return nil, err
+	}
+	if query, _, err = qb.ExpandMacros(query, qb.DialectFor(string(conn.Type)), tr, 0); err != nil {
+		return nil, err
+	}
+	dbConn, err := plugin.Connect(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer func() { _ = dbConn.Close() }()
+	res, err := dbConn.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	return res, nil
+}
+
+// Schedule runs due schedules every tick until ctx is done. Schedules run
+// one after another; their deliveries are sent in the background.
+func (s *Service) Schedule(ctx context.Context, tick time.Duration) {
+	if len(s.schedules) == 0 {
+		return
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s.runDue(ctx)
+	}
+}
+
+func (s *Service) runDue(ctx context.Context) {
+	for _, sc := range s.schedules {
+		if ctx.Err() != nil {
+			return
+		}
+		now := s.now()
+		interval := time.Duration(sc.Interval) * time.Second
+		s.mu.Lock()
+		next, ok := s.next[sc.Name]
+		if !ok {
+			// Start one interval after start-up rather than delivering
+			// everything at once on every restart.
+			next = now.Add(interval)
+			s.next[sc.Name] = next
+		}
+		s.mu.Unlock()
+		if now.Before(next) {
+			continue
+		}
+		if err := s.runSchedule(ctx, sc, qb.TimeRange{From: now.Add(-interval), To: now}, interval); err != nil {
+			slog.Warn("webhook schedule failed", "schedule", sc.Name, "err", err)
+			s.alert(fmt.Sprintf("Webhook schedule %s failed", sc.Name), err.Error(),
+				map[string]string{"schedule": sc.Name, "target": sc.Target, "datasource_id": sc.DatasourceID})
+		}
+		s.mu.Lock()
+		s.next[sc.Name] = now.Add(interval)
+		s.mu.Unlock()
+	}
+}
+
+// runSchedule runs a schedule's query over tr and starts its delivery. The
+// query may take at most one interval so runs never pile up.
+func (s *Service) runSchedule(ctx context.Context, sc config.WebhookSchedule, tr qb.TimeRange, timeout time.Duration) error {
+	target, ok := s.targets[sc.Target]
+	if !ok {
+		return fmt.Errorf("unknown target %q", sc.Target)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	res, err := s.query(ctx, sc.DatasourceID, sc.Query, nil, tr)
+	if err != nil {
+		return err
+	}
+	return s.start(ctx, target, &Delivery{Source: SourceSchedule, Schedule: sc.Name, DatasourceID: sc.DatasourceID}, res)
+}
+
+// alert raises a schedule failure notification.
+func (s *Service) alert(title, body string, labels map[string]string) {
+	if s.notifier == nil {
+		return
+	}
+	ev := notification.Event{
+		Type:     notification.EventScheduleFailure,
+		Severity: notification.SeverityWarning,
+		Title:    title,
+		Body:     body,
+		Source:   "webhooks",
+		Labels:   labels,
+		Time:     s.now(),
+	}
+	if err := s.notifier.Notify(context.Background(), ev); err != nil {
+		slog.Warn("webhook notification failed", "err", err)
+	}
+}
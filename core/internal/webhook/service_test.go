@@ -0,0 +1,293 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/identity"
+	"data-voyager/core/internal/notification"
+	"data-voyager/core/internal/render"
+	"data-voyager/sdk"
+)
+
+type stubConns struct{ connection.Repository }
+
+func (stubConns) GetByID(_ context.Context, id string) (*connection.Connection, error) {
+	if id == "ds" {
+		return &connection.Connection{ID: id, Type: "postgresql", Config: json.RawMessage(`{}`)}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+type stubConfig struct{}
+
+func (stubConfig) Validate() error             { return nil }
+func (stubConfig) GetConnectionString() string { return "" }
+
+// stubPlugin answers every query with a fixed two-row result and records
+// the queries it ran.
+type stubPlugin struct {
+	sdk.DatasourcePlugin
+
+	mu      sync.Mutex
+	queries []string
+}
+
+func (p *stubPlugin) GetType() sdk.DataSourceType { return "postgresql" }
+func (p *stubPlugin) ParseConfig(json.RawMessage) (sdk.ConnectionConfig, error) {
+	return stubConfig{}, nil
+}
+func (p *stubPlugin) Connect(context.Context, sdk.ConnectionConfig) (sdk.Connection, error) {
+	return &stubConn{p: p}, nil
+}
+
+type stubConn struct {
+	sdk.Connection
+	p *stubPlugin
+}
+
+func (c *stubConn) Query(_ context.Context, q string, _ ...any) (*sdk.QueryResult, error) {
+	c.p.mu.Lock()
+	c.p.queries = append(c.p.queries, q)
+	c.p.mu.Unlock()
+	if q == "fail" {
+		return nil, errors.New("syntax error")
+	}
+	return &sdk.QueryResult{Frames: []*sdk.DataFrame{{Fields: []sdk.Field{
+		{Name: "id", Values: []any{int64(1), int64(2)}},
+		{Name: "name", Values: []any{"a, b", []byte("c")}},
+	}}}}, nil
+}
+func (c *stubConn) Close() error { return nil }
+
+type recorder struct {
+	mu     sync.Mutex
+	events []notification.Event
+}
+
+func (r *recorder) Notify(_ context.Context, ev notification.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, ev)
+	return nil
+}
+
+// receiver is a webhook endpoint answering with statuses in turn, then 200.
+type receiver struct {
+	statuses []int
+	calls    atomic.Int32
+
+	mu      sync.Mutex
+	headers http.Header
+	body    []byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := int(rc.calls.Add(1))
+	body, _ := io.ReadAll(r.Body)
+	rc.mu.Lock()
+	rc.headers, rc.body = r.Header.Clone(), body
+	rc.mu.Unlock()
+	if n <= len(rc.statuses) {
+		w.WriteHeader(rc.statuses[n-1])
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func newTestService(t *testing.T, target config.WebhookTarget, schedules ...config.WebhookSchedule) (*Service, *stubPlugin, *recorder) {
+	t.Helper()
+	plugin := &stubPlugin{}
+	reg := datasource.NewRegistry()
+	reg.Register(plugin)
+	rec := &recorder{}
+	target.Name = "hook"
+	svc := NewService(config.WebhooksConfig{Targets: []config.WebhookTarget{target}, Schedules: schedules}, stubConns{}, reg, rec)
+	svc.backoff = func(int) time.Duration { return time.Millisecond }
+	return svc, plugin, rec
+}
+
+func TestDeliver_SignsJSON(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+	svc, _, _ := newTestService(t, config.WebhookTarget{URL: srv.URL, Secret: "s3cret", Headers: map[string]string{"X-Team": "data"}})
+
+	d, err := svc.Deliver(context.Background(), Request{Target: "hook", DatasourceID: "ds", Query: "SELECT 1", Owner: "ann"})
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, d.Status)
+	assert.Equal(t, 2, d.Rows)
+	svc.sending.Wait()
+
+	got, err := svc.Get(d.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusDelivered, got.Status)
+	assert.Equal(t, 1, got.Attempts)
+	require.NotNil(t, got.DeliveredAt)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	assert.Equal(t, "application/json", rc.headers.Get("Content-Type"))
+	assert.Equal(t, "data", rc.headers.Get("X-Team"))
+	assert.Equal(t, d.ID, rc.headers.Get(HeaderDelivery))
+	assert.Equal(t, Sign("s3cret", rc.headers.Get(HeaderTimestamp), rc.body), rc.headers.Get(HeaderSignature))
+
+	var p payload
+	require.NoError(t, json.Unmarshal(rc.body, &p))
+	assert.Equal(t, d.ID, p.DeliveryID)
+	assert.Equal(t, SourceAdhoc, p.Source)
+	assert.Equal(t, []string{"id", "name"}, p.Columns)
+	assert.Equal(t, [][]any{{1.0, "a, b"}, {2.0, "c"}}, p.Rows)
+}
+
+func TestDeliver_CSV(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+	svc, _, _ := newTestService(t, config.WebhookTarget{URL: srv.URL, Format: "csv"})
+
+	_, err := svc.Deliver(context.Background(), Request{Target: "hook", DatasourceID: "ds", Query: "SELECT 1"})
+	require.NoError(t, err)
+	svc.sending.Wait()
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	assert.Equal(t, "text/csv; charset=utf-8", rc.headers.Get("Content-Type"))
+	assert.Empty(t, rc.headers.Get(HeaderSignature), "unsigned without a secret")
+	assert.Equal(t, "id,name\n1,\"a, b\"\n2,c\n", string(rc.body))
+}
+
+func TestDeliver_RetriesThenDelivers(t *testing.T) {
+	rc := &receiver{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+	svc, _, rec := newTestService(t, config.WebhookTarget{URL: srv.URL})
+
+	d, err := svc.Deliver(context.Background(), Request{Target: "hook", DatasourceID: "ds", Query: "SELECT 1"})
+	require.NoError(t, err)
+	svc.sending.Wait()
+
+	got, _ := svc.Get(d.ID)
+	assert.Equal(t, StatusDelivered, got.Status)
+	assert.Equal(t, 3, got.Attempts)
+	assert.Empty(t, got.Error)
+	assert.Empty(t, rec.events)
+}
+
+func TestDeliver_FailsAndNotifies(t *testing.T) {
+	t.Run("attempts run out", func(t *testing.T) {
+		rc := &receiver{statuses: []int{500, 500, 500}}
+		srv := httptest.NewServer(rc)
+		defer srv.Close()
+		svc, _, rec := newTestService(t, config.WebhookTarget{URL: srv.URL, MaxAttempts: 2})
+
+		d, err := svc.Deliver(context.Background(), Request{Target: "hook", DatasourceID: "ds", Query: "SELECT 1"})
+		require.NoError(t, err)
+		svc.sending.Wait()
+
+		got, _ := svc.Get(d.ID)
+		assert.Equal(t, StatusFailed, got.Status)
+		assert.Equal(t, 2, got.Attempts)
+		assert.Equal(t, 500, got.StatusCode)
+		assert.EqualValues(t, 2, rc.calls.Load())
+		require.Len(t, rec.events, 1)
+		assert.Equal(t, notification.EventScheduleFailure, rec.events[0].Type)
+	})
+
+	t.Run("client errors are final", func(t *testing.T) {
+		rc := &receiver{statuses: []int{http.StatusBadRequest}}
+		srv := httptest.NewServer(rc)
+		defer srv.Close()
+		svc, _, _ := newTestService(t, config.WebhookTarget{URL: srv.URL})
+
+		d, err := svc.Deliver(context.Background(), Request{Target: "hook", DatasourceID: "ds", Query: "SELECT 1"})
+		require.NoError(t, err)
+		svc.sending.Wait()
+
+		got, _ := svc.Get(d.ID)
+		assert.Equal(t, StatusFailed, got.Status)
+		assert.Equal(t, 1, got.Attempts)
+	})
+}
+
+func TestDeliver_Rejects(t *testing.T) {
+	svc, _, _ := newTestService(t, config.WebhookTarget{URL: "http://127.0.0.1:1"})
+	ctx := context.Background()
+
+	_, err := svc.Deliver(ctx, Request{Target: "other", DatasourceID: "ds", Query: "SELECT 1"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = svc.Deliver(ctx, Request{Target: "hook", DatasourceID: "ds", Query: "fail"})
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	hidden := identity.With(ctx, &identity.Identity{Username: "bob", Role: identity.RoleViewer, Datasources: []string{"other"}})
+	_, err = svc.Deliver(hidden, Request{Target: "hook", DatasourceID: "ds", Query: "SELECT 1"})
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	assert.Empty(t, svc.List("", true), "nothing is recorded for refused deliveries")
+}
+
+func TestSchedule_RunsWhenDue(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+	svc, plugin, _ := newTestService(t, config.WebhookTarget{URL: srv.URL},
+		config.WebhookSchedule{Name: "hourly", Target: "hook", DatasourceID: "ds", Query: "SELECT {{ __limit }}", Interval: 3600})
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	svc.runDue(context.Background())
+	assert.Empty(t, plugin.queries, "first run waits one interval")
+
+	now = now.Add(time.Hour)
+	svc.runDue(context.Background())
+	svc.sending.Wait()
+	require.Equal(t, []string{"SELECT 10000"}, plugin.queries)
+
+	list := svc.List("", true)
+	require.Len(t, list, 1)
+	assert.Equal(t, SourceSchedule, list[0].Source)
+	assert.Equal(t, "hourly", list[0].Schedule)
+	assert.Equal(t, StatusDelivered, list[0].Status)
+
+	svc.runDue(context.Background())
+	assert.Len(t, plugin.queries, 1, "not due again until the next interval")
+}
+
+func TestDeliver_HTML(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+	svc, _, _ := newTestService(t, config.WebhookTarget{URL: srv.URL, Format: "html"})
+
+	d, err := svc.Deliver(context.Background(), Request{Target: "hook", DatasourceID: "ds", Query: "SELECT 1"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, d.Rows)
+	svc.sending.Wait()
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	assert.Equal(t, "text/html; charset=utf-8", rc.headers.Get("Content-Type"))
+	assert.Contains(t, string(rc.body), "<td>1</td><td>a, b</td>")
+	assert.Contains(t, string(rc.body), "Datasource ds")
+}
+
+func TestDeliver_PDFWithoutRenderer(t *testing.T) {
+	svc, _, _ := newTestService(t, config.WebhookTarget{URL: "http://example.invalid", Format: "pdf"})
+	_, err := svc.Deliver(context.Background(), Request{Target: "hook", DatasourceID: "ds", Query: "SELECT 1"})
+	assert.ErrorIs(t, err, render.ErrUnavailable)
+}
@@ -0,0 +1,521 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/activity"
+	"data-voyager/core/internal/aiconfig"
+	"data-voyager/core/internal/app"
+	"data-voyager/core/internal/auditlog"
+	"data-voyager/core/internal/authz"
+	"data-voyager/core/internal/buildinfo"
+	"data-voyager/core/internal/cdc"
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/configupgrade"
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/dashboard"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/email"
+	"data-voyager/core/internal/embed"
+	"data-voyager/core/internal/export"
+	"data-voyager/core/internal/gitsync"
+	"data-voyager/core/internal/i18n"
+	"data-voyager/core/internal/importer"
+	"data-voyager/core/internal/logger"
+	"data-voyager/core/internal/monitor"
+	"data-voyager/core/internal/navigate"
+	"data-voyager/core/internal/notification"
+	"data-voyager/core/internal/objstore"
+	"data-voyager/core/internal/ownership"
+	"data-voyager/core/internal/pii"
+	"data-voyager/core/internal/privacy"
+	"data-voyager/core/internal/probe"
+	"data-voyager/core/internal/reconcile"
+	"data-voyager/core/internal/render"
+	"data-voyager/core/internal/resultstore"
+	"data-voyager/core/internal/retention"
+	"data-voyager/core/internal/rowedit"
+	"data-voyager/core/internal/savedview"
+	"data-voyager/core/internal/scim"
+	"data-voyager/core/internal/seed"
+	"data-voyager/core/internal/settings"
+	"data-voyager/core/internal/statsstore"
+	"data-voyager/core/internal/store"
+	"data-voyager/core/internal/telemetry"
+	"data-voyager/core/internal/upgrade"
+	"data-voyager/core/internal/usage"
+	"data-voyager/core/internal/user"
+	"data-voyager/core/internal/webhook"
+	"data-voyager/sdk"
+)
+
+// Config is the server configuration, as read from config.toml.
+type Config = config.ViperConfig
+
+// LoadConfig reads the configuration the way the CLI does: the file name
+// (without extension) found in path or the usual locations, then
+// VOYAGER_* environment variables. It is validated.
+func LoadConfig(name, path string) (*Config, error) {
+	return config.InitViper(name, path)
+}
+
+// DefaultConfig returns the built-in defaults, which keep their state in
+// ./data; callers adjust it before passing it to NewServer.
+func DefaultConfig() (*Config, error) {
+	return config.Defaults()
+}
+
+// Option customizes NewServer.
+type Option func(*serverOptions)
+
+type serverOptions struct {
+	plugins    []sdk.DatasourcePlugin
+	middleware []gin.HandlerFunc
+	routes     []func(api *gin.RouterGroup)
+}
+
+// WithPlugins adds datasource plugins next to the ones registered with
+// sdk.RegisterDatasource, replacing any of the same type.
+func WithPlugins(plugins ...sdk.DatasourcePlugin) Option {
+	return func(o *serverOptions) { o.plugins = append(o.plugins, plugins...) }
+}
+
+// WithMiddleware runs mw on every request, after logging and recovery and
+// before the API authenticates the caller.
+func WithMiddleware(mw ...gin.HandlerFunc) Option {
+	return func(o *serverOptions) { o.middleware = append(o.middleware, mw...) }
+}
+
+// WithRoutes lets register add routes to /api/v1. They sit behind the same
+// authentication as the built-in API.
+func WithRoutes(register func(api *gin.RouterGroup)) Option {
+	return func(o *serverOptions) { o.routes = append(o.routes, register) }
+}
+
+// Server is a complete Data Voyager instance: stores, plugin registry,
+// background jobs and the HTTP API and UI. It is an http.Handler, so it can
+// be mounted in another program's server.
+type Server struct {
+	engine  *gin.Engine
+	probes  *probe.Probes
+	stop    context.CancelFunc
+	closers []func() error
+}
+
+// NewServer assembles a server from cfg and starts its background jobs.
+// Close releases it. Datasource plugins are those registered with
+// sdk.RegisterDatasource, typically by importing their packages, plus any
+// given WithPlugins.
+func NewServer(cfg *Config, opts ...Option) (_ *Server, err error) {
+	o := &serverOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	routerHooks, registryHooks, eventListeners := hooks()
+	s := &Server{}
+	defer func() {
+		if err != nil {
+			_ = s.Close()
+		}
+	}()
+
+	metadata, err := store.OpenMetadataStore(cfg.MetadataStore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open metadata store: %w", err)
+	}
+	s.closers = append(s.closers, metadata.Close)
+	repos := metadata.Repos()
+
+	registry := datasource.NewRegistry()
+
+	// Derive data directory from the SQLite path so the encryption key file
+	// lives alongside the database. For non-SQLite stores the dataDir is empty
+	// and BuildService falls back to the VOYAGER_ENCRYPTION_KEY env var.
+	dataDir := ""
+	if cfg.MetadataStore.Type == "sqlite" || cfg.MetadataStore.Type == "sqlite3" {
+		dataDir = filepath.Dir(cfg.MetadataStore.SQLite.Path)
+	}
+
+	// Resolve encryption key once — shared by both settings and aiconfig packages.
+	encryptKey, err := settings.ResolveEncryptionKey(dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve encryption key: %w", err)
+	}
+
+	// Register all service loaders. Add new domains here as the app grows.
+	settingsSvc, err := settings.BuildService(repos.Settings, encryptKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize settings service: %w", err)
+	}
+
+	// Open statistics store (optional — noop when type is empty).
+	var aiHistoryRepo aiconfig.HistoryRepository = aiconfig.NoopHistoryRepository{}
+	var connHistoryRepo connection.HistoryRepository = connection.NoopHistoryRepository{}
+	var activityRepo activity.Repository = activity.NoopRepository{}
+	retentionTables := metadata.RetentionTables()
+	personalTables := metadata.PersonalDataTables()
+	var usageRepo usage.Repository
+	if cfg.StatisticsStore.Type != "" {
+		statsDB, err := statsstore.Open(cfg.StatisticsStore)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open statistics store: %w", err)
+		}
+		s.closers = append(s.closers, statsDB.Close)
+		statsRepos, err := statsstore.NewRepos(statsDB, cfg.StatisticsStore)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize statistics repositories: %w", err)
+		}
+		aiHistoryRepo = statsRepos.AIConfigHistory
+		connHistoryRepo = statsRepos.ConnectionHistory
+		activityRepo = statsRepos.Activity
+		usageRepo = statsRepos.Usage
+		retentionTables = append(retentionTables, statsstore.RetentionTables(statsDB, cfg.StatisticsStore)...)
+		personalTables = append(personalTables, statsstore.PersonalDataTables(statsDB, cfg.StatisticsStore)...)
+	}
+
+	aiConfigSvc, err := aiconfig.BuildService(repos.AIConfigs, encryptKey, aiHistoryRepo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize aiconfig service: %w", err)
+	}
+
+	mailer, err := email.New(cfg.Email, cfg.Server.PublicURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize email: %w", err)
+	}
+	notificationSvc, err := notification.NewService(repos.Notifications, encryptKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize notification service: %w", err)
+	}
+	notificationNetworks, err := cfg.Notifications.Networks()
+	if err != nil {
+		return nil, err
+	}
+	notificationSvc.WithMailer(mailer).WithAllowedNetworks(notificationNetworks)
+	activitySvc := activity.NewService(activityRepo)
+	notificationSvc.WithListener(activitySvc.Listen)
+	for _, fn := range eventListeners {
+		notificationSvc.WithListener(fn)
+	}
+
+	telemetryCollector := telemetry.NewCollector(func(ctx context.Context) (map[sdk.DataSourceType]int64, error) {
+		stats, err := repos.Connection.Stats(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return stats.CountByType, nil
+	})
+
+	updates, err := upgrade.NewService(cfg.Updates, buildinfo.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize update checks: %w", err)
+	}
+
+	// Services resolve the datasources a request names through conns, which
+	// hides those the caller may not see. Work without a caller, such as
+	// scheduled runs, sees them all.
+	conns := connection.Visible(repos.Connection)
+	preResume := time.Duration(cfg.Warehouses.PreResume) * time.Second
+	monitorSvc := monitor.NewService(repos.Monitors, conns, registry, notificationSvc).WithPreResume(preResume)
+	reconcileSvc := reconcile.NewService(repos.Reconciliations, conns, registry, notificationSvc).WithPreResume(preResume)
+
+	archive, err := objstore.New(cfg.Retention.Archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize retention archive: %w", err)
+	}
+	retentionSvc := retention.NewService(retentionTables, cfg.Retention, archive)
+
+	resultArchive, err := objstore.New(cfg.AsyncResults.Archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize async result archive: %w", err)
+	}
+	asyncResults := resultstore.New(cfg.AsyncResults, resultArchive)
+	auditLog, err := auditlog.New(cfg.AuditLog)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure audit log sinks: %w", err)
+	}
+	s.closers = append(s.closers, auditLog.Close)
+	exportSvc := export.NewService(cfg.Exports, cfg.Server.PublicURL, conns, registry, notificationSvc).
+		WithAudit(auditLog.ExportAudit(repos.ExportAudit))
+	renderer := render.New(cfg.Rendering)
+	// Every path that runs SQL a user wrote shares one parameter policy, so
+	// its violation log covers them all.
+	literals := connection.NewLiteralPolicy(cfg.Server.ParameterPolicy)
+	dashboardSvc := dashboard.NewService(repos.Dashboards, conns, registry).
+		WithEnvironment(cfg.Server.Environment).WithSnapshots(repos.Snapshots).WithRenderer(renderer).
+		WithLabels(cfg.Retention).WithVersions(repos.Versions).WithLiteralPolicy(literals)
+	webhookSvc := webhook.NewService(cfg.Webhooks, conns, registry, notificationSvc).WithRenderer(renderer).
+		WithLiteralPolicy(literals)
+	cdcSvc := cdc.NewService(cfg.CDC, conns, registry, webhookSvc)
+	rowEditSvc := rowedit.NewService(cfg.RowEditing, conns, registry).WithAudit(auditLog.RowEditAudit(repos.RowEditAudit))
+	savedViewSvc := savedview.NewService(repos.SavedViews, conns).WithNotifier(notificationSvc)
+
+	sessionSecret := encryptKey
+	if cfg.Security.JWTSecret != "" {
+		sessionSecret = []byte(cfg.Security.JWTSecret)
+	}
+	userSvc, err := user.NewService(repos.Users, auditLog.ImpersonationAudit(repos.Impersonations), repos.Connection).
+		WithAccounts(repos.Credentials, cfg.Security.Password, encryptKey, sessionSecret,
+			time.Duration(cfg.Security.SessionTimeout)*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize user service: %w", err)
+	}
+	userSvc.WithInvitations(repos.Invitations, mailer, cfg.Server.PublicURL,
+		time.Duration(cfg.Security.InvitationTTL)*time.Hour)
+	userSvc.WithDemo(cfg.Demo)
+
+	// Query usage is recorded only when there is a statistics store to hold it.
+	var usageRecorder *usage.Recorder
+	// Executed queries also go to the audit log sinks, if any.
+	var queryUsage connection.UsageRecorder
+	if usageRepo != nil {
+		usageRecorder = usage.NewRecorder(usageRepo)
+		queryUsage = usageRecorder
+	}
+	if auditLog != nil {
+		if queryUsage != nil {
+			queryUsage = connection.UsageRecorders{queryUsage, auditLog}
+		} else {
+			queryUsage = auditLog
+		}
+	}
+
+	embedHandler := embed.NewHandler(embed.NewService(conns, registry, encryptKey).
+		WithQueryTimeout(time.Duration(cfg.Server.QueryTimeout) * time.Second).WithLiteralPolicy(literals))
+
+	loaders := []app.Loader{
+		connection.NewLoaderWithHistory(repos.Connection, repos.Templates, registry, cfg, settingsSvc, aiConfigSvc, connHistoryRepo, queryUsage, asyncResults, notificationSvc, literals,
+			ownership.NewReferenceSource(repos.Ownership), dashboard.NewReferenceSource(repos.Dashboards),
+			monitor.NewReferenceSource(repos.Monitors), reconcile.NewReferenceSource(repos.Reconciliations)),
+		ownership.NewLoader(repos.Ownership, conns, registry),
+		notification.NewLoader(notificationSvc),
+		email.NewLoader(mailer),
+		embed.NewLoader(embedHandler),
+		seed.NewLoader(conns, registry),
+		configupgrade.NewLoader(conns, registry),
+		importer.NewLoader(conns, registry),
+		dashboard.NewLoader(dashboardSvc),
+		monitor.NewLoader(monitorSvc),
+		reconcile.NewLoader(reconcileSvc),
+		pii.NewLoader(pii.NewService(repos.ColumnTags, conns, registry)),
+		retention.NewLoader(retentionSvc),
+		export.NewLoader(exportSvc),
+		webhook.NewLoader(webhookSvc),
+		cdc.NewLoader(cdcSvc),
+		rowedit.NewLoader(rowEditSvc),
+		savedview.NewLoader(savedViewSvc),
+		activity.NewLoader(activitySvc),
+		navigate.NewLoader(navigate.NewService(conns, dashboardSvc, savedViewSvc, usageRepo)),
+		user.NewLoader(userSvc),
+		privacy.NewLoader(privacy.NewService(userSvc, exportSvc, personalTables)),
+		telemetry.NewLoader(telemetry.NewHandler(telemetryCollector, cfg.Telemetry.Enabled, cfg.Telemetry.Endpoint)),
+		upgrade.NewLoader(updates, cfg.Updates.AllowApply),
+	}
+	if cfg.GitSync.Enabled {
+		dashboards := dashboard.NewService(repos.Dashboards, repos.Connection, registry).WithVersions(repos.Versions)
+		loaders = append(loaders, gitsync.NewLoader(gitsync.NewService(cfg.GitSync, dashboards, repos.Connection)))
+	}
+	if usageRepo != nil {
+		loaders = append(loaders, usage.NewLoader(usage.NewService(usageRepo, conns)))
+	}
+	for _, l := range loaders {
+		if err := l.Load(); err != nil {
+			return nil, fmt.Errorf("loader failed: %w", err)
+		}
+	}
+	// Plugins given as options replace registered ones of the same type.
+	for _, p := range o.plugins {
+		registry.Register(p)
+	}
+	for _, fn := range registryHooks {
+		fn(registry)
+	}
+
+	if cfg.Logging.Level != "debug" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	r := gin.New()
+	if err := r.SetTrustedProxies(cfg.Security.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid security.trusted_proxies: %w", err)
+	}
+	r.Use(logger.GinMiddleware(), gin.Recovery(), telemetryCollector.Middleware(), i18n.Middleware())
+	r.Use(o.middleware...)
+	router := &Router{Engine: r}
+	for _, fn := range routerHooks {
+		fn(router)
+	}
+
+	s.engine = r
+	s.probes = probe.New(repos.Connection.Health)
+	probe.RegisterRoutes(r, s.probes)
+
+	r.GET("/health", func(c *gin.Context) {
+		ctx := context.Background()
+		if err := repos.Connection.Health(ctx); err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"status":  "unhealthy",
+				"message": "metadata store is unhealthy",
+				"error":   err.Error(),
+			})
+			return
+		}
+		body := gin.H{
+			"status":         "healthy",
+			"version":        buildinfo.Version,
+			"metadata_store": "connected",
+			"plugins_loaded": len(registry.GetSupportedTypes()),
+		}
+		if latest, ok := updates.Available(); ok {
+			body["update_available"] = true
+			body["latest_version"] = latest
+		}
+		c.JSON(http.StatusOK, body)
+	})
+
+	proxyAuth, err := user.NewProxyAuth(cfg.Security.UserHeader, cfg.Security.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid security.trusted_proxies: %w", err)
+	}
+	policies, err := authz.New(cfg.Security.Policies,
+		func(ctx context.Context, id string) (*authz.Datasource, error) {
+			conn, err := repos.Connection.GetByID(ctx, id)
+			if errors.Is(err, connection.ErrNotFound) {
+				return nil, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			return &authz.Datasource{ID: conn.ID, Type: string(conn.Type), Tags: conn.Tags}, nil
+		},
+		func(ctx context.Context, userID string) ([]string, error) {
+			groups, err := repos.SCIMGroups.List(ctx)
+			if err != nil {
+				return nil, err
+			}
+			var names []string
+			for _, g := range groups {
+				if slices.Contains(g.Members, userID) {
+					names = append(names, g.DisplayName)
+				}
+			}
+			return names, nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load access policies: %w", err)
+	}
+	apiV1 := r.Group("/api/v1", user.Middleware(userSvc, proxyAuth, cfg.Security.EnableAuth), authz.Middleware(policies),
+		connection.NetworkMiddleware(repos.Connection))
+	{
+		apiV1.GET("/ping", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{
+				"message":   "pong",
+				"timestamp": time.Now().UTC().Format(time.RFC3339),
+			})
+		})
+
+		i18n.RegisterRoutes(apiV1)
+
+		for _, l := range loaders {
+			l.RegisterRoutes(apiV1)
+		}
+		for _, fn := range append(o.routes, router.api...) {
+			fn(apiV1)
+		}
+	}
+
+	embed.RegisterPublicRoutes(r, embedHandler)
+
+	if cfg.Security.SCIM.Enabled {
+		scimSvc, err := scim.NewService(userSvc, repos.SCIMGroups, cfg.Security.SCIM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize SCIM: %w", err)
+		}
+		scim.RegisterRoutes(r, scim.NewHandler(scimSvc, cfg.Security.SCIM.Token))
+	}
+
+	if err := ServeFrontend(r, cfg.Frontend); err != nil {
+		return nil, fmt.Errorf("failed to set up frontend: %w", err)
+	}
+
+	monitorCtx, stopMonitor := context.WithCancel(context.Background())
+	s.stop = stopMonitor
+	go notification.WatchHealth(monitorCtx, notificationSvc, "metadata store", time.Minute, repos.Connection.Health)
+	go monitorSvc.Schedule(monitorCtx, 10*time.Second)
+	go reconcileSvc.Schedule(monitorCtx, 10*time.Second)
+	go asyncResults.Schedule(monitorCtx, time.Minute)
+	go exportSvc.Schedule(monitorCtx, time.Minute)
+	go dashboardSvc.Schedule(monitorCtx, time.Minute)
+	go webhookSvc.Schedule(monitorCtx, 10*time.Second)
+	if usageRecorder != nil {
+		go usageRecorder.Run(monitorCtx)
+	}
+	go auditLog.Run(monitorCtx)
+	if cfg.CDC.Enabled {
+		interval := time.Duration(cfg.CDC.Interval) * time.Second
+		if interval <= 0 {
+			interval = 5 * time.Second
+		}
+		slog.Warn("change data capture is experimental", "streams", len(cfg.CDC.Streams))
+		go cdcSvc.Schedule(monitorCtx, interval)
+	}
+	if cfg.Retention.Enabled {
+		interval := time.Duration(cfg.Retention.Interval) * time.Minute
+		if interval <= 0 {
+			interval = time.Hour
+		}
+		go retentionSvc.Schedule(monitorCtx, interval)
+	}
+
+	if cfg.Telemetry.Enabled && cfg.Telemetry.Endpoint != "" {
+		interval := time.Duration(cfg.Telemetry.Interval) * time.Hour
+		if interval <= 0 {
+			interval = 24 * time.Hour
+		}
+		slog.Info("anonymous telemetry enabled", "endpoint", cfg.Telemetry.Endpoint, "interval", interval)
+		go telemetry.NewReporter(telemetryCollector, cfg.Telemetry.Endpoint, interval).Run(monitorCtx)
+	}
+
+	if cfg.Updates.Check {
+		interval := time.Duration(cfg.Updates.Interval) * time.Hour
+		if interval <= 0 {
+			interval = 24 * time.Hour
+		}
+		go updates.Schedule(monitorCtx, interval)
+	}
+
+	return s, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.engine.ServeHTTP(w, r)
+}
+
+// MarkStarted makes the startup probe pass; call it once the server
+// listens.
+func (s *Server) MarkStarted() { s.probes.MarkStarted() }
+
+// Drain makes the readiness probe fail so load balancers stop sending
+// traffic before shutdown.
+func (s *Server) Drain() { s.probes.Drain() }
+
+// Close stops the background jobs and closes the stores.
+func (s *Server) Close() error {
+	if s.stop != nil {
+		s.stop()
+	}
+	var errs []error
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		errs = append(errs, s.closers[i]())
+	}
+	s.closers = nil
+	return errors.Join(errs...)
+}
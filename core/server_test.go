@@ -0,0 +1,83 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/sdk"
+)
+
+type testPlugin struct{ sdk.DatasourcePlugin }
+
+func (testPlugin) GetType() sdk.DataSourceType { return "embedded" }
+func (testPlugin) GetName() string             { return "Embedded" }
+
+// testConfig is a config for a server that keeps nothing on disk.
+func testConfig(t *testing.T) *Config {
+	cfg, err := DefaultConfig()
+	require.NoError(t, err)
+	cfg.MetadataStore = config.DBConfig{Type: "memory"}
+	cfg.Frontend.Mode = config.FrontendNone
+	cfg.Exports.Dir = t.TempDir()
+	return cfg
+}
+
+func serve(s http.Handler, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequestWithContext(context.Background(), method, path, nil))
+	return w
+}
+
+func TestNewServer(t *testing.T) {
+	cfg := testConfig(t)
+	var seen []string
+	s, err := NewServer(cfg,
+		WithPlugins(testPlugin{}),
+		WithMiddleware(func(c *gin.Context) { seen = append(seen, c.Request.URL.Path); c.Next() }),
+		WithRoutes(func(api *gin.RouterGroup) {
+			api.GET("/custom", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"data": "hi"}) })
+		}),
+	)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, s.Close()) }()
+
+	get := func(path string) *httptest.ResponseRecorder { return serve(s, http.MethodGet, path) }
+
+	w := get("/api/v1/custom")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":"hi"}`, w.Body.String())
+
+	w = get("/api/v1/datasource-types")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var body struct {
+		Data []string `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Contains(t, body.Data, "embedded")
+
+	assert.Equal(t, http.StatusOK, get("/health").Code)
+	assert.Equal(t, []string{"/api/v1/custom", "/api/v1/datasource-types", "/health"}, seen)
+}
+
+func TestNewServer_Policies(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.Security.Policies = []config.PolicyConfig{
+		{Name: "read-only", Effect: "deny", Actions: []string{"datasources:write"}},
+	}
+	s, err := NewServer(cfg)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, s.Close()) }()
+
+	assert.Equal(t, http.StatusOK, serve(s, http.MethodGet, "/api/v1/datasources").Code)
+	w := serve(s, http.MethodDelete, "/api/v1/datasources/abc")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "read-only")
+}
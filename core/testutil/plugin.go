@@ -0,0 +1,93 @@
+package testutil
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"data-voyager/sdk"
+)
+
+// StubPlugin is a datasource plugin that answers every query from memory.
+// Respond, when set, answers each query; otherwise every query gets Result
+// and Err. The zero value is a plugin of type "stub" returning no rows.
+type StubPlugin struct {
+	Type    sdk.DataSourceType
+	Result  *sdk.QueryResult
+	Err     error
+	Respond func(query string) (*sdk.QueryResult, error)
+	// Schema is what connections report for GetSchema.
+	Schema *sdk.SchemaInfo
+
+	mu      sync.Mutex
+	queries []string
+}
+
+// Queries returns the queries run so far, oldest first.
+func (p *StubPlugin) Queries() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.queries...)
+}
+
+func (p *StubPlugin) GetType() sdk.DataSourceType {
+	if p.Type == "" {
+		return "stub"
+	}
+	return p.Type
+}
+
+func (p *StubPlugin) GetName() string { return "Stub " + string(p.GetType()) }
+
+func (p *StubPlugin) ParseConfig(json.RawMessage) (sdk.ConnectionConfig, error) {
+	return stubConfig{}, nil
+}
+
+func (p *StubPlugin) ValidateConfig(any) error { return nil }
+
+func (p *StubPlugin) Connect(context.Context, sdk.ConnectionConfig) (sdk.Connection, error) {
+	return &stubConn{plugin: p}, nil
+}
+
+func (p *StubPlugin) TestConnection(context.Context, sdk.ConnectionConfig) (*sdk.ConnectionTestResult, error) {
+	return &sdk.ConnectionTestResult{IsConnected: true}, nil
+}
+
+func (p *StubPlugin) query(q string) (*sdk.QueryResult, error) {
+	p.mu.Lock()
+	p.queries = append(p.queries, q)
+	p.mu.Unlock()
+	if p.Respond != nil {
+		return p.Respond(q)
+	}
+	if p.Err != nil {
+		return nil, p.Err
+	}
+	if p.Result == nil {
+		return &sdk.QueryResult{}, nil
+	}
+	return p.Result, nil
+}
+
+type stubConfig struct{}
+
+func (stubConfig) Validate() error             { return nil }
+func (stubConfig) GetConnectionString() string { return "stub://" }
+
+type stubConn struct{ plugin *StubPlugin }
+
+func (c *stubConn) Query(_ context.Context, query string, _ ...any) (*sdk.QueryResult, error) {
+	return c.plugin.query(query)
+}
+
+func (c *stubConn) GetSchema(context.Context) (*sdk.SchemaInfo, error) {
+	if c.plugin.Schema == nil {
+		return &sdk.SchemaInfo{}, nil
+	}
+	return c.plugin.Schema, nil
+}
+
+func (c *stubConn) GetTables(context.Context, string) ([]sdk.TableInfo, error) { return nil, nil }
+func (c *stubConn) Close() error                                               { return nil }
+func (c *stubConn) Ping(context.Context) error                                 { return nil }
+func (c *stubConn) GetMetrics() sdk.ConnectionMetrics                          { return sdk.ConnectionMetrics{} }
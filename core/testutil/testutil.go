@@ -0,0 +1,43 @@
+// Package testutil helps test code built on Data Voyager without the
+// databases it normally runs against: an in-memory metadata store, stub
+// datasource plugins and builders for requests to its HTTP handlers.
+package testutil
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"data-voyager/core/internal/config"
+	"data-voyager/core/internal/datasource"
+	"data-voyager/core/internal/store"
+	"data-voyager/sdk"
+)
+
+// MetadataStore returns an empty, migrated metadata store that lives in
+// memory and is closed when t ends. Stores are independent of each other.
+func MetadataStore(t testing.TB) store.MetadataStore {
+	t.Helper()
+	ms, err := store.OpenMetadataStore(config.DBConfig{Type: "memory"})
+	if err != nil {
+		t.Fatalf("testutil: open metadata store: %v", err)
+	}
+	t.Cleanup(func() { _ = ms.Close() })
+	return ms
+}
+
+// Registry returns a datasource registry holding plugins.
+func Registry(plugins ...sdk.DatasourcePlugin) *datasource.Registry {
+	r := datasource.NewRegistry()
+	for _, p := range plugins {
+		r.Register(p)
+	}
+	return r
+}
+
+// Engine returns a gin engine in test mode, without the default logger
+// and recovery middleware.
+func Engine() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
@@ -0,0 +1,59 @@
+package testutil_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/connection"
+	"data-voyager/core/internal/dashboard"
+	"data-voyager/core/testutil"
+	"data-voyager/sdk"
+)
+
+func TestHarness_Dashboard(t *testing.T) {
+	ms := testutil.MetadataStore(t)
+	repos := ms.Repos()
+	plugin := &testutil.StubPlugin{Result: &sdk.QueryResult{Frames: []*sdk.DataFrame{{
+		Fields: []sdk.Field{{Name: "n", Values: []any{int64(42)}}},
+	}}}}
+	ds := &connection.Connection{Name: "stub", Type: plugin.GetType(), Config: json.RawMessage(`{}`), IsActive: true}
+	require.NoError(t, repos.Connection.Create(context.Background(), ds))
+
+	r := testutil.Engine()
+	svc := dashboard.NewService(repos.Dashboards, repos.Connection, testutil.Registry(plugin))
+	dashboard.RegisterRoutes(r.Group("/api/v1"), dashboard.NewHandler(svc))
+
+	var d dashboard.Dashboard
+	testutil.NewRequest(t, http.MethodPost, "/api/v1/dashboards").
+		JSON(map[string]any{
+			"name":       "Ops",
+			"panels":     []map[string]any{{"id": "p1", "datasource_id": ds.ID, "query": "SELECT 42"}},
+			"time_range": map[string]string{"from": "1 hour ago", "to": "now"},
+		}).
+		As("ann", "editor").Do(r).Status(http.StatusCreated).Data(&d)
+	require.NotEmpty(t, d.ID)
+
+	var res dashboard.DataResult
+	testutil.NewRequest(t, http.MethodPost, "/api/v1/dashboards/"+d.ID+"/data").
+		As("ann", "viewer").Do(r).Status(http.StatusOK).Data(&res)
+	require.Len(t, res.Panels, 1)
+	assert.Empty(t, res.Panels[0].Error)
+	assert.Equal(t, []string{"SELECT 42"}, plugin.Queries())
+
+	resp := testutil.NewRequest(t, http.MethodGet, "/api/v1/dashboards/missing").Do(r)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.NotEmpty(t, resp.Error())
+}
+
+func TestMetadataStore_Independent(t *testing.T) {
+	a, b := testutil.MetadataStore(t), testutil.MetadataStore(t)
+	ctx := context.Background()
+	require.NoError(t, a.Repos().Settings.Set(ctx, "k", "v", false))
+	_, err := b.Repos().Settings.Get(ctx, "k")
+	assert.Error(t, err)
+}
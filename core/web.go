@@ -1,7 +1,8 @@
 package core
 
 import (
-	"embed"
+	"errors"
+	"fmt"
 	"io"
 	"io/fs"
 	"log/slog"
@@ -12,13 +13,14 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-)
 
-//go:embed frontend/out/*
-var frontendFS embed.FS
+	"data-voyager/core/internal/config"
+)
 
 // StaticFileSystemConfig holds configuration for serving static files
 type StaticFileSystemConfig struct {
@@ -26,18 +28,13 @@ type StaticFileSystemConfig struct {
 	BasePath               string   // e.g., "/ui"
 	IndexFallback          bool     // Enable SPA index.html fallback
 	SkipPaths              []string // Paths to skip (e.g., ["/api", "/health"])
-	CacheControl           string   // Cache-Control header value
+	CacheControl           string   // Cache-Control for fingerprinted assets
 	EnableDirectoryListing bool
 }
 
-// DefaultStaticConfig returns default configuration for serving frontend
-func DefaultStaticConfig() *StaticFileSystemConfig {
-	frontendFiles, err := fs.Sub(frontendFS, "frontend/out")
-	if err != nil {
-		slog.Warn("failed to create sub filesystem, falling back to root", "err", err)
-		frontendFiles = frontendFS
-	}
-
+// DefaultStaticConfig returns default configuration for serving frontendFiles,
+// the root of a built SPA.
+func DefaultStaticConfig(frontendFiles fs.FS) *StaticFileSystemConfig {
 	return &StaticFileSystemConfig{
 		FS:                     frontendFiles,
 		BasePath:               "/ui",
@@ -48,6 +45,66 @@ func DefaultStaticConfig() *StaticFileSystemConfig {
 	}
 }
 
+// frontendFiles returns the SPA to serve for cfg, or nil when the UI is
+// disabled.
+func frontendFiles(cfg config.FrontendConfig) (fs.FS, error) {
+	switch cfg.Mode {
+	case config.FrontendNone:
+		return nil, nil
+	case config.FrontendDir:
+		files := os.DirFS(cfg.Dir)
+		if _, err := fs.Stat(files, "index.html"); err != nil {
+			return nil, fmt.Errorf("frontend.dir %q has no index.html: %w", cfg.Dir, err)
+		}
+		return files, nil
+	default:
+		if frontendFS == nil {
+			return nil, errors.New("this binary was built headless without the embedded frontend; set frontend.mode to \"dir\" or \"none\"")
+		}
+		files, err := fs.Sub(frontendFS, "frontend/out")
+		if err != nil {
+			slog.Warn("failed to create sub filesystem, falling back to root", "err", err)
+			files = frontendFS
+		}
+		return files, nil
+	}
+}
+
+// CheckFrontend reports why the UI cannot be served with cfg, or nil when
+// it can or is disabled.
+func CheckFrontend(cfg config.FrontendConfig) error {
+	files, err := frontendFiles(cfg)
+	if err != nil || files == nil {
+		return err
+	}
+	if _, err := fs.Stat(files, "index.html"); err != nil {
+		return errors.New("the embedded frontend has no index.html; build it with `make build-frontend` before the backend")
+	}
+	return nil
+}
+
+// Cache-Control values. Fingerprinted assets never change under their name
+// and are cached for good; other files are revalidated; HTML is never stored
+// so a new deploy is picked up on the next navigation.
+const (
+	cacheHTML       = "no-store"
+	cacheRevalidate = "no-cache"
+)
+
+// hashedAssetName matches the content hash Vite appends to build output,
+// e.g. index-BxY7aZ3k.js.
+var hashedAssetName = regexp.MustCompile(`-[A-Za-z0-9_-]{8,}\.[a-z0-9]+$`)
+
+// isFingerprinted reports whether name is a build asset with its content
+// hash in the path: anything Next.js emits under _next/static/, and Vite's
+// assets/<name>-<hash>.<ext>.
+func isFingerprinted(name string) bool {
+	if strings.HasPrefix(name, "_next/static/") {
+		return true
+	}
+	return strings.HasPrefix(name, "assets/") && hashedAssetName.MatchString(path.Base(name))
+}
+
 // StaticFileServer returns a middleware that serves static files with SPA support
 func StaticFileServer(config *StaticFileSystemConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -64,6 +121,10 @@ func StaticFileServer(config *StaticFileSystemConfig) gin.HandlerFunc {
 			c.Next()
 			return
 		}
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
 
 		// Remove BasePath prefix
 		urlPath := strings.TrimPrefix(c.Request.URL.Path, config.BasePath)
@@ -76,8 +137,10 @@ func StaticFileServer(config *StaticFileSystemConfig) gin.HandlerFunc {
 			return
 		}
 
-		// SPA fallback: serve index.html
-		if config.IndexFallback {
+		// SPA fallback: serve index.html for client-side routes. A missing
+		// file with an extension is a broken asset reference, and answering
+		// it with HTML would only hide that.
+		if config.IndexFallback && path.Ext(urlPath) == "" {
 			serveIndexHTML(c, config)
 			return
 		}
@@ -86,89 +149,64 @@ func StaticFileServer(config *StaticFileSystemConfig) gin.HandlerFunc {
 	}
 }
 
-// serveFile attempts to serve a file from the filesystem
+// serveFile serves urlPath from the filesystem, trying in turn the exact
+// file, a pre-rendered route page (<path>.html) and a directory index
+// (<path>/index.html).
 func serveFile(c *gin.Context, config *StaticFileSystemConfig, urlPath string) bool {
-	// Clean path
-	cleanPath := path.Clean(strings.TrimPrefix(urlPath, "/"))
-	if cleanPath == "." {
-		cleanPath = ""
-	}
+	cleanPath := strings.TrimPrefix(path.Clean("/"+urlPath), "/")
 
-	// Try exact file match
-	file, err := config.FS.Open(cleanPath)
-	if err == nil {
-		defer func() { _ = file.Close() }()
-		stat, err := file.Stat()
-		if err == nil && !stat.IsDir() {
-			// File exists and is not a directory
-			serveContent(c, config, file, cleanPath, stat)
+	candidates := []string{"index.html"}
+	if cleanPath != "" {
+		candidates = []string{cleanPath, cleanPath + ".html", path.Join(cleanPath, "index.html")}
+	}
+	for _, name := range candidates {
+		if serveNamed(c, config, name) {
 			return true
 		}
 	}
+	return false
+}
 
-	// Try directory index
-	if cleanPath == "" || strings.HasSuffix(cleanPath, "/") {
-		indexPath := filepath.Join(cleanPath, "index.html")
-		file, err := config.FS.Open(indexPath)
-		if err == nil {
-			defer func() { _ = file.Close() }()
-			stat, err := file.Stat()
-			if err == nil {
-				serveContent(c, config, file, indexPath, stat)
-				return true
-			}
-		}
-	} else {
-		// Try adding index.html
-		indexPath := filepath.Join(cleanPath, "index.html")
-		file, err := config.FS.Open(indexPath)
-		if err == nil {
-			defer func() { _ = file.Close() }()
-			stat, err := file.Stat()
-			if err == nil {
-				serveContent(c, config, file, indexPath, stat)
-				return true
-			}
-		}
+// serveNamed serves name if it is a regular file.
+func serveNamed(c *gin.Context, config *StaticFileSystemConfig, name string) bool {
+	file, err := config.FS.Open(name)
+	if err != nil {
+		return false
 	}
-
-	return false
+	defer func() { _ = file.Close() }()
+	stat, err := file.Stat()
+	if err != nil || stat.IsDir() {
+		return false
+	}
+	serveContent(c, config, file, name, stat)
+	return true
 }
 
 // serveIndexHTML serves the index.html file for SPA fallback
 func serveIndexHTML(c *gin.Context, config *StaticFileSystemConfig) {
-	file, err := config.FS.Open("index.html")
-	if err != nil {
-		slog.Error("failed to open index.html", "err", err)
+	if !serveNamed(c, config, "index.html") {
+		slog.Error("failed to open index.html")
 		c.Status(http.StatusNotFound)
-		return
 	}
-	defer func() { _ = file.Close() }()
-
-	// No cache for index.html
-	c.Header("Content-Type", "text/html; charset=utf-8")
-	c.Header("Cache-Control", "no-cache, no-store, must-revalidate")
-	c.Status(http.StatusOK)
-	_, _ = io.Copy(c.Writer, file)
 }
 
 // serveContent serves file content with appropriate headers
 func serveContent(c *gin.Context, config *StaticFileSystemConfig, file fs.File, filePath string, stat fs.FileInfo) {
-	// Determine content type
-	contentType := getContentType(filePath)
-	c.Header("Content-Type", contentType)
-
-	// Set cache headers (skip for HTML files)
-	if !strings.HasSuffix(filePath, ".html") && config.CacheControl != "" {
+	c.Header("Content-Type", getContentType(filePath))
+	switch {
+	case strings.HasSuffix(filePath, ".html"):
+		c.Header("Cache-Control", cacheHTML)
+	case isFingerprinted(filePath) && config.CacheControl != "":
 		c.Header("Cache-Control", config.CacheControl)
-	} else {
-		c.Header("Cache-Control", "no-cache")
+	default:
+		c.Header("Cache-Control", cacheRevalidate)
 	}
-
-	// Set content length
-	c.Header("Content-Length", string(rune(stat.Size())))
+	c.Header("Content-Length", strconv.FormatInt(stat.Size(), 10))
 
 	c.Status(http.StatusOK)
+	if c.Request.Method == http.MethodHead {
+		return
+	}
 	_, _ = io.Copy(c.Writer, file)
 }
 
@@ -238,23 +276,34 @@ func getContentType(filePath string) string {
 	}
 }
 
-// ServeFrontend sets up frontend serving with default configuration
-func ServeFrontend(r *gin.Engine) {
+// ServeFrontend sets up frontend serving as cfg selects: the embedded SPA, one
+// from a directory, or none for an API-only server.
+func ServeFrontend(r *gin.Engine, cfg config.FrontendConfig) error {
 	// Check if we're in development mode (GO_ENV=development)
 	if os.Getenv("GO_ENV") == "development" {
 		slog.Info("development mode: proxying /ui to frontend dev server", "target", "http://localhost:3000")
 		setupDevProxy(r)
-		return
+		return nil
 	}
 
-	// Production mode: serve embedded static files
-	config := DefaultStaticConfig()
-	r.Use(StaticFileServer(config))
+	files, err := frontendFiles(cfg)
+	if err != nil {
+		return err
+	}
+	if files == nil {
+		slog.Info("frontend disabled, serving the API only")
+		return nil
+	}
+	if cfg.Mode == config.FrontendDir {
+		slog.Info("serving frontend from directory", "dir", cfg.Dir)
+	}
+	r.Use(StaticFileServer(DefaultStaticConfig(files)))
 
 	// Redirect root to /ui
 	r.GET("/", func(c *gin.Context) {
 		c.Redirect(http.StatusMovedPermanently, "/ui")
 	})
+	return nil
 }
 
 // setupDevProxy sets up reverse proxy to Next.js dev server for development
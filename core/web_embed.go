@@ -0,0 +1,15 @@
+//go:build !headless
+
+package core
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed frontend/out/*
+var embeddedFS embed.FS
+
+// frontendFS is the built SPA compiled into the binary. Build with
+// -tags headless to leave it out.
+var frontendFS fs.FS = embeddedFS
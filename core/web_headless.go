@@ -0,0 +1,9 @@
+//go:build headless
+
+package core
+
+import "io/fs"
+
+// frontendFS is nil in headless builds: the UI is served from a directory
+// or not at all.
+var frontendFS fs.FS
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/core/internal/config"
+)
+
+func TestServeFrontend_Dir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("<html>external</html>"), 0o644))
+	r := gin.New()
+	require.NoError(t, ServeFrontend(r, config.FrontendConfig{Mode: config.FrontendDir, Dir: dir}))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ui/datasources", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "external")
+}
+
+func TestServeFrontend_DirWithoutIndex(t *testing.T) {
+	err := ServeFrontend(gin.New(), config.FrontendConfig{Mode: config.FrontendDir, Dir: t.TempDir()})
+	assert.ErrorContains(t, err, "no index.html")
+}
+
+func TestServeFrontend_None(t *testing.T) {
+	r := gin.New()
+	require.NoError(t, ServeFrontend(r, config.FrontendConfig{Mode: config.FrontendNone}))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ui", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestStaticFileServer_Caching(t *testing.T) {
+	files := fstest.MapFS{
+		"index.html":                  {Data: []byte("<html>app</html>")},
+		"dashboards.html":             {Data: []byte("<html>dashboards</html>")},
+		"settings/index.html":         {Data: []byte("<html>settings</html>")},
+		"assets/index-BxY7aZ3k.js":    {Data: []byte("console.log(1)")},
+		"_next/static/chunks/main.js": {Data: []byte("next")},
+		"favicon.ico":                 {Data: []byte("ico")},
+	}
+	r := gin.New()
+	r.Use(StaticFileServer(DefaultStaticConfig(files)))
+
+	tests := []struct {
+		path, body, cache string
+		code              int
+	}{
+		{"/ui", "app", cacheHTML, http.StatusOK},
+		{"/ui/assets/index-BxY7aZ3k.js", "console.log(1)", "public, max-age=31536000, immutable", http.StatusOK},
+		{"/ui/_next/static/chunks/main.js", "next", "public, max-age=31536000, immutable", http.StatusOK},
+		{"/ui/favicon.ico", "ico", cacheRevalidate, http.StatusOK},
+		// Pre-rendered routes are found with or without a trailing slash.
+		{"/ui/dashboards", "dashboards", cacheHTML, http.StatusOK},
+		{"/ui/settings/", "settings", cacheHTML, http.StatusOK},
+		// Unknown routes fall back to the SPA; missing assets do not.
+		{"/ui/datasources/42", "app", cacheHTML, http.StatusOK},
+		{"/ui/assets/gone-12345678.js", "", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		assert.Equal(t, tt.code, w.Code, tt.path)
+		if tt.code == http.StatusOK {
+			assert.Contains(t, w.Body.String(), tt.body, tt.path)
+			assert.Equal(t, tt.cache, w.Header().Get("Cache-Control"), tt.path)
+			assert.Equal(t, strconv.Itoa(w.Body.Len()), w.Header().Get("Content-Length"), tt.path)
+		}
+	}
+}
+
+func TestCheckFrontend(t *testing.T) {
+	assert.NoError(t, CheckFrontend(config.FrontendConfig{Mode: config.FrontendNone}))
+	assert.ErrorContains(t, CheckFrontend(config.FrontendConfig{Mode: config.FrontendDir, Dir: t.TempDir()}), "no index.html")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("<html></html>"), 0o644))
+	assert.NoError(t, CheckFrontend(config.FrontendConfig{Mode: config.FrontendDir, Dir: dir}))
+}
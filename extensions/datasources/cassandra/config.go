@@ -0,0 +1,97 @@
+package cassandra
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/gocql/gocql"
+
+	"data-voyager/sdk/pluginsdk"
+)
+
+// defaultConsistency is used when a config leaves Consistency unset. It
+// keeps reads and writes within the local datacenter while still surviving
+// the loss of a replica.
+const defaultConsistency = "LOCAL_QUORUM"
+
+// Config holds Cassandra connection parameters.
+type Config struct {
+	// ContactPoints are the nodes the driver first connects to, as host or
+	// host:port. The rest of the cluster is discovered from them.
+	ContactPoints []string `json:"contact_points" toml:"contact_points"`
+	Port          int      `json:"port" toml:"port"`
+	// Keyspace is used for unqualified table names and by GetTables when
+	// no keyspace is given. Optional.
+	Keyspace string `json:"keyspace,omitempty" toml:"keyspace"`
+	Username string `json:"username,omitempty" toml:"username"`
+	Password string `json:"password,omitempty" toml:"password"`
+	// Datacenter is the local datacenter. When set, queries are routed to
+	// its nodes only; otherwise to any node.
+	Datacenter string `json:"datacenter,omitempty" toml:"datacenter"`
+	// Consistency is the consistency level queries run at, e.g. ONE,
+	// LOCAL_ONE, QUORUM or LOCAL_QUORUM (the default).
+	Consistency string `json:"consistency,omitempty" toml:"consistency"`
+	Secure      bool   `json:"secure" toml:"secure"`
+
+	pluginsdk.DialOptions
+	// FetchSize is the CQL page size: rows are read this many at a time.
+	pluginsdk.FetchOptions
+}
+
+func (c *Config) Validate() error {
+	if len(c.ContactPoints) == 0 {
+		return fmt.Errorf("at least one contact point is required")
+	}
+	for _, cp := range c.ContactPoints {
+		if strings.TrimSpace(cp) == "" {
+			return fmt.Errorf("contact points must not be empty")
+		}
+	}
+	if c.Port <= 0 {
+		c.Port = 9042
+	}
+	if c.Consistency == "" {
+		c.Consistency = defaultConsistency
+	}
+	if _, err := c.consistency(); err != nil {
+		return err
+	}
+	if err := c.DialOptions.Validate(); err != nil {
+		return err
+	}
+	return c.FetchOptions.Validate()
+}
+
+func (c *Config) GetConnectionString() string {
+	hosts := make([]string, len(c.ContactPoints))
+	for i, cp := range c.ContactPoints {
+		hosts[i] = c.address(cp)
+	}
+	return fmt.Sprintf("cassandra://%s/%s?username=%s&password=%s",
+		strings.Join(hosts, ","), c.Keyspace, c.Username, c.Password)
+}
+
+// address returns a contact point as host:port, adding Port when it has
+// none.
+func (c *Config) address(cp string) string {
+	cp = strings.TrimSpace(cp)
+	if _, _, err := net.SplitHostPort(cp); err == nil {
+		return cp
+	}
+	return net.JoinHostPort(strings.Trim(cp, "[]"), strconv.Itoa(c.Port))
+}
+
+// consistency parses Consistency, case-insensitively.
+func (c *Config) consistency() (gocql.Consistency, error) {
+	name := c.Consistency
+	if name == "" {
+		name = defaultConsistency
+	}
+	level, err := gocql.ParseConsistencyWrapper(name)
+	if err != nil {
+		return 0, fmt.Errorf("consistency must be one of ANY, ONE, TWO, THREE, QUORUM, ALL, LOCAL_QUORUM, EACH_QUORUM or LOCAL_ONE, got %q", c.Consistency)
+	}
+	return level, nil
+}
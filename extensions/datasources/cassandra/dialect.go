@@ -0,0 +1,46 @@
+package cassandra
+
+import "data-voyager/sdk"
+
+// Dialect implements sdk.DialectProvider.
+func (p *Plugin) Dialect() sdk.Dialect {
+	return sdk.Dialect{
+		IdentifierQuote: `"`,
+		StringQuote:     "'",
+		ParamStyle:      sdk.ParamStyleQuestion,
+		ReservedWords:   reservedWords,
+		Functions:       functions,
+	}
+}
+
+// reservedWords are the CQL keywords that cannot be used as unquoted
+// identifiers.
+var reservedWords = []string{
+	"ADD", "ALLOW", "ALTER", "AND", "APPLY", "ASC", "AUTHORIZE", "BATCH",
+	"BEGIN", "BY", "COLUMNFAMILY", "CREATE", "DELETE", "DESC", "DESCRIBE",
+	"DROP", "ENTRIES", "EXECUTE", "FROM", "FULL", "GRANT", "IF", "IN",
+	"INDEX", "INFINITY", "INSERT", "INTO", "KEYSPACE", "LIMIT", "MODIFY",
+	"NAN", "NORECURSIVE", "NOT", "NULL", "OF", "ON", "OR", "ORDER",
+	"PRIMARY", "RENAME", "REPLACE", "REVOKE", "SCHEMA", "SELECT", "SET",
+	"TABLE", "TO", "TOKEN", "TRUNCATE", "UNLOGGED", "UPDATE", "USE",
+	"USING", "VIEW", "WHERE", "WITH",
+}
+
+var functions = []sdk.FunctionDoc{
+	{Name: "count", Signature: "count(*)", ReturnType: "bigint", Description: "Counts rows, or non-NULL values of a column."},
+	{Name: "sum", Signature: "sum(x)", Description: "Sums a numeric column over the selected rows."},
+	{Name: "avg", Signature: "avg(x)", Description: "Averages a numeric column over the selected rows."},
+	{Name: "min", Signature: "min(x)", Description: "Smallest value of a column over the selected rows."},
+	{Name: "max", Signature: "max(x)", Description: "Largest value of a column over the selected rows."},
+	{Name: "token", Signature: "token(partition_key[, ...])", ReturnType: "bigint", Description: "Token of a partition key; restrict on it to page through a table by token range."},
+	{Name: "writetime", Signature: "writetime(column)", ReturnType: "bigint", Description: "Microsecond timestamp of when a regular column was last written."},
+	{Name: "ttl", Signature: "ttl(column)", ReturnType: "int", Description: "Seconds until a regular column expires, or NULL when it does not."},
+	{Name: "now", Signature: "now()", ReturnType: "timeuuid", Description: "A new, unique timeuuid for the current time."},
+	{Name: "uuid", Signature: "uuid()", ReturnType: "uuid", Description: "A new random version 4 uuid."},
+	{Name: "toTimestamp", Signature: "toTimestamp(x)", ReturnType: "timestamp", Description: "Converts a timeuuid or date to a timestamp."},
+	{Name: "toDate", Signature: "toDate(x)", ReturnType: "date", Description: "Converts a timeuuid or timestamp to a date."},
+	{Name: "minTimeuuid", Signature: "minTimeuuid(timestamp)", ReturnType: "timeuuid", Description: "Smallest timeuuid for a timestamp, for range queries on timeuuid columns."},
+	{Name: "maxTimeuuid", Signature: "maxTimeuuid(timestamp)", ReturnType: "timeuuid", Description: "Largest timeuuid for a timestamp, for range queries on timeuuid columns."},
+	{Name: "toJson", Signature: "toJson(x)", ReturnType: "text", Description: "Encodes a value as JSON."},
+	{Name: "fromJson", Signature: "fromJson(json)", Description: "Decodes a JSON string into the type of the column it is assigned to."},
+}
@@ -0,0 +1,79 @@
+package cassandra
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gocql/gocql"
+
+	"data-voyager/sdk"
+)
+
+// errorCodes maps native protocol error codes to sdk error codes. Invalid
+// requests (0x2200) cover several sdk codes and are told apart by message
+// in invalidCode.
+var errorCodes = map[int]string{
+	gocql.ErrCodeCredentials:  sdk.ErrCodeAuthFailed,
+	gocql.ErrCodeUnauthorized: sdk.ErrCodePermissionDenied,
+	gocql.ErrCodeSyntax:       sdk.ErrCodeSyntaxError,
+}
+
+// positionRe finds "line 1:7" in syntax errors; the column is 0-based.
+var positionRe = regexp.MustCompile(`line (\d+):(\d+)`)
+
+// classify wraps a server error in an sdk.QueryError when its code is one
+// clients care about. stmt is the statement of query that failed.
+func classify(query, stmt string, err error) error {
+	var re gocql.RequestError
+	if !errors.As(err, &re) {
+		return err
+	}
+	code, ok := errorCodes[re.Code()]
+	if re.Code() == gocql.ErrCodeInvalid {
+		code, ok = invalidCode(re.Message())
+	}
+	if !ok {
+		return err
+	}
+	qe := &sdk.QueryError{Code: code, Err: err}
+	if m := positionRe.FindStringSubmatch(re.Message()); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		col, _ := strconv.Atoi(m[2])
+		qe.Locate(query, stmt, offset(stmt, line, col+1))
+	}
+	return qe
+}
+
+// invalidCode classifies an invalid request error by its message.
+func invalidCode(msg string) (string, bool) {
+	switch {
+	case strings.HasPrefix(msg, "Keyspace ") && strings.HasSuffix(msg, " does not exist"):
+		return sdk.ErrCodeUnknownDatabase, true
+	case strings.HasPrefix(msg, "unconfigured table"),
+		strings.HasPrefix(msg, "Undefined column name"),
+		strings.HasPrefix(msg, "Unknown function"):
+		return sdk.ErrCodeUndefinedObject, true
+	}
+	return "", false
+}
+
+// offset converts a 1-based line and column in s to a 1-based character
+// offset, or 0 when s has no such position.
+func offset(s string, line, column int) int {
+	if line < 1 || column < 1 {
+		return 0
+	}
+	n := 0
+	for i, l := range strings.SplitAfter(s, "\n") {
+		if i+1 == line {
+			if column > len([]rune(l))+1 {
+				return 0
+			}
+			return n + column
+		}
+		n += len([]rune(l))
+	}
+	return 0
+}
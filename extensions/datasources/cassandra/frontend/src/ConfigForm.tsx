@@ -0,0 +1,115 @@
+import { Input } from '@data-voyager/shared-ui/components/ui/input'
+import { Label } from '@data-voyager/shared-ui/components/ui/label'
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from '@data-voyager/shared-ui/components/ui/select'
+import { Switch } from '@data-voyager/shared-ui/components/ui/switch'
+import type { DatasourceConfigProps } from '@data-voyager/sdk'
+
+interface CassandraConfig {
+  contact_points: string[]
+  port: number
+  keyspace: string
+  username: string
+  password: string
+  datacenter: string
+  consistency: string
+  secure: boolean
+}
+
+const CONSISTENCY_LEVELS = ['LOCAL_QUORUM', 'LOCAL_ONE', 'ONE', 'TWO', 'THREE', 'QUORUM', 'EACH_QUORUM', 'ALL', 'ANY']
+
+export function CassandraConfigForm({ config, onChange }: DatasourceConfigProps) {
+  const cfg = config as Partial<CassandraConfig>
+
+  const set = (key: keyof CassandraConfig, value: string | string[] | number | boolean) =>
+    onChange({ ...cfg, [key]: value })
+
+  return (
+    <div className="flex flex-col gap-4 max-w-lg">
+      <div className="grid grid-cols-[1fr_120px] gap-3">
+        <div className="space-y-2">
+          <Label>Contact points</Label>
+          <Input
+            placeholder="10.0.0.1, 10.0.0.2"
+            value={(cfg.contact_points ?? []).join(', ')}
+            onChange={(e) =>
+              set('contact_points', e.target.value.split(',').map((s) => s.trim()).filter(Boolean))
+            }
+          />
+        </div>
+        <div className="space-y-2">
+          <Label>Port</Label>
+          <Input
+            placeholder="9042"
+            value={cfg.port || ''}
+            onChange={(e) => set('port', Number(e.target.value) || 9042)}
+          />
+        </div>
+      </div>
+
+      <div className="space-y-2">
+        <Label>Keyspace</Label>
+        <Input
+          placeholder="optional"
+          value={cfg.keyspace ?? ''}
+          onChange={(e) => set('keyspace', e.target.value)}
+        />
+      </div>
+
+      <div className="space-y-2">
+        <Label>Local datacenter</Label>
+        <Input
+          placeholder="datacenter1"
+          value={cfg.datacenter ?? ''}
+          onChange={(e) => set('datacenter', e.target.value)}
+        />
+      </div>
+
+      <div className="space-y-2">
+        <Label>Consistency level</Label>
+        <Select
+          value={cfg.consistency || 'LOCAL_QUORUM'}
+          onValueChange={(v) => v !== null && set('consistency', v)}
+        >
+          <SelectTrigger>
+            <SelectValue />
+          </SelectTrigger>
+          <SelectContent>
+            {CONSISTENCY_LEVELS.map((c) => (
+              <SelectItem key={c} value={c}>{c}</SelectItem>
+            ))}
+          </SelectContent>
+        </Select>
+      </div>
+
+      <div className="space-y-2">
+        <Label>Username</Label>
+        <Input
+          placeholder="cassandra"
+          value={cfg.username ?? ''}
+          onChange={(e) => set('username', e.target.value)}
+        />
+      </div>
+
+      <div className="space-y-2">
+        <Label>Password</Label>
+        <Input
+          type="password"
+          placeholder="••••••••"
+          value={cfg.password ?? ''}
+          onChange={(e) => set('password', e.target.value)}
+        />
+      </div>
+
+      <div className="flex items-center gap-3">
+        <Switch
+          checked={cfg.secure ?? false}
+          onCheckedChange={(v) => set('secure', v)}
+          id="secure"
+        />
+        <Label htmlFor="secure" className="cursor-pointer">
+          Secure (TLS)
+        </Label>
+      </div>
+    </div>
+  )
+}
@@ -0,0 +1 @@
+export { GenericSQLQueryEditor as CassandraQueryEditor } from '@data-voyager/shared-ui';
@@ -0,0 +1,18 @@
+import type { DatasourcePlugin } from '@data-voyager/sdk';
+import { datasourceRegistry } from '@data-voyager/sdk';
+import { CassandraConfigForm } from './ConfigForm';
+import { CassandraQueryEditor } from './QueryEditorWidget';
+import { cassandraSchemaProvider } from './schemaProvider';
+
+const plugin: DatasourcePlugin = {
+  id: 'cassandra',
+  name: 'Cassandra',
+  description: 'Connect to Apache Cassandra clusters with CQL',
+  configComponent: CassandraConfigForm,
+  queryEditorComponent: CassandraQueryEditor,
+  schemaProvider: cassandraSchemaProvider,
+};
+
+datasourceRegistry.register(plugin);
+
+export { plugin };
@@ -0,0 +1,70 @@
+import type { SchemaProvider, SchemaNode, PluginContext } from '@data-voyager/sdk';
+import type { SchemaInfo, DatabaseInfo, TableInfo } from './types';
+
+export const cassandraSchemaProvider: SchemaProvider = {
+  async getRootNodes(_ctx: PluginContext, connectionId: string): Promise<SchemaNode[]> {
+    const schema = await fetchSchema(connectionId);
+    // Cassandra: databases 배열이 keyspace 목록
+    return schema.databases.map((db: DatabaseInfo) => ({
+      id: `keyspace/${db.name}`,
+      label: db.name,
+      type: 'database' as const,
+      hasChildren: db.tables.length > 0,
+    }));
+  },
+
+  async getChildNodes(_ctx: PluginContext, connectionId: string, node: SchemaNode): Promise<SchemaNode[]> {
+    if (node.type === 'database') {
+      const schema = await fetchSchema(connectionId);
+      const db = schema.databases.find((d: DatabaseInfo) => d.name === node.label);
+      if (!db) return [];
+      return db.tables.map((t: TableInfo) => ({
+        id: `${node.id}/table/${t.name}`,
+        label: t.name,
+        // type: table 또는 view (materialized view)
+        type: (t.type === 'view' ? 'view' : 'table') as 'view' | 'table',
+        hasChildren: (t.columns?.length ?? 0) > 0,
+        meta: { comment: t.description || undefined },
+      }));
+    }
+
+    if (node.type === 'table' || node.type === 'view') {
+      // id: keyspace/<keyspace>/table/<table>
+      const [, keyspace, , tableName] = node.id.split('/');
+      const schema = await fetchSchema(connectionId);
+      const db = schema.databases.find((d: DatabaseInfo) => d.name === keyspace);
+      const table = db?.tables.find((t: TableInfo) => t.name === tableName);
+      return (table?.columns ?? []).map((col) => ({
+        id: `${node.id}/col/${col.name}`,
+        label: col.name,
+        type: 'column' as const,
+        hasChildren: false,
+        meta: { dataType: col.type, nullable: col.nullable },
+      }));
+    }
+
+    return [];
+  },
+
+  getInsertText(node: SchemaNode): string {
+    // id: keyspace/shop/table/orders/col/total → "shop"."orders"."total"
+    const parts = node.id.split('/');
+    const names = parts.filter((_, i) => i % 2 === 1);
+    return names.map((n) => `"${n.replace(/"/g, '""')}"`).join('.');
+  },
+};
+
+// 스키마 캐시
+const schemaCache = new Map<string, SchemaInfo>();
+
+async function fetchSchema(connectionId: string): Promise<SchemaInfo> {
+  if (schemaCache.has(connectionId)) {
+    return schemaCache.get(connectionId)!;
+  }
+  const res = await fetch(`/api/v1/connections/${connectionId}/schema`);
+  if (!res.ok) throw new Error('Failed to fetch schema');
+  const json = await res.json();
+  const schema: SchemaInfo = json.data;
+  schemaCache.set(connectionId, schema);
+  return schema;
+}
@@ -0,0 +1,10 @@
+module data-voyager/extensions/datasources/cassandra
+
+go 1.26.1
+
+require (
+	github.com/gocql/gocql v1.7.0
+	github.com/stretchr/testify v1.11.1
+	github.com/testcontainers/testcontainers-go v0.39.0
+	gopkg.in/inf.v0 v0.9.1
+)
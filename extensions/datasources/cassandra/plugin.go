@@ -0,0 +1,421 @@
+package cassandra
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"time"
+
+	"data-voyager/sdk"
+	"data-voyager/sdk/pluginsdk"
+
+	"github.com/gocql/gocql"
+	"gopkg.in/inf.v0"
+)
+
+func init() {
+	sdk.RegisterDatasource(&Plugin{})
+}
+
+// Type is the DataSourceType identifier for this extension.
+const Type sdk.DataSourceType = "cassandra"
+
+// Defaults for the options a config leaves unset. The driver's own query
+// timeout of 11s is too short for the scans people run from an explorer.
+const (
+	defaultConnectTimeout = 10 * time.Second
+	defaultReadTimeout    = time.Minute
+	defaultPageSize       = 5000
+)
+
+// Plugin implements sdk.DatasourcePlugin for Apache Cassandra and other
+// servers that speak CQL.
+type Plugin struct{}
+
+func (p *Plugin) GetType() sdk.DataSourceType { return Type }
+func (p *Plugin) GetName() string             { return "Cassandra" }
+
+// Capabilities implements sdk.CapabilityProvider.
+func (p *Plugin) Capabilities() sdk.Capabilities {
+	return sdk.Capabilities{
+		Exec:    true,
+		Schemas: true,
+		Writes:  true,
+	}
+}
+
+// Version implements sdk.VersionProvider.
+func (p *Plugin) Version() sdk.VersionInfo {
+	return sdk.VersionInfo{
+		Version:          "0.1.0",
+		MinServerVersion: "0.1.0",
+		MaxServerVersion: "1.0.0",
+		DriverVersion:    pluginsdk.ModuleVersion("github.com/gocql/gocql"),
+	}
+}
+
+func (p *Plugin) ParseConfig(data json.RawMessage) (sdk.ConnectionConfig, error) {
+	cfg, err := pluginsdk.DecodeConfig[Config](data, "cassandra")
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (p *Plugin) ValidateConfig(config any) error {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return fmt.Errorf("config must be *cassandra.Config")
+	}
+	return cfg.Validate()
+}
+
+func (p *Plugin) Connect(ctx context.Context, config sdk.ConnectionConfig) (sdk.Connection, error) {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("invalid config type for Cassandra")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid cassandra config: %w", err)
+	}
+	consistency, err := cfg.consistency()
+	if err != nil {
+		return nil, fmt.Errorf("invalid cassandra config: %w", err)
+	}
+
+	hosts := make([]string, len(cfg.ContactPoints))
+	for i, cp := range cfg.ContactPoints {
+		hosts[i] = cfg.address(cp)
+	}
+	cluster := gocql.NewCluster(hosts...)
+	cluster.Keyspace = cfg.Keyspace
+	cluster.Consistency = consistency
+	cluster.ConnectTimeout = cfg.Connect(defaultConnectTimeout)
+	cluster.Timeout = cfg.Read(defaultReadTimeout)
+	cluster.WriteTimeout = cfg.Write(cluster.Timeout)
+	cluster.PageSize = pageSize(cfg)
+	switch {
+	case cfg.KeepAlive < 0:
+		cluster.SocketKeepalive = 0
+	case cfg.KeepAlive > 0:
+		cluster.SocketKeepalive = time.Duration(cfg.KeepAlive) * time.Second
+	}
+	if cfg.Username != "" {
+		cluster.Authenticator = gocql.PasswordAuthenticator{Username: cfg.Username, Password: cfg.Password}
+	}
+	if cfg.Datacenter != "" {
+		cluster.PoolConfig.HostSelectionPolicy = gocql.TokenAwareHostPolicy(gocql.DCAwareRoundRobinPolicy(cfg.Datacenter))
+	} else {
+		cluster.PoolConfig.HostSelectionPolicy = gocql.TokenAwareHostPolicy(gocql.RoundRobinHostPolicy())
+	}
+	if cfg.Secure {
+		cluster.SslOpts = &gocql.SslOptions{
+			Config:                 &tls.Config{MinVersion: tls.VersionTLS12},
+			EnableHostVerification: true,
+		}
+	}
+
+	session, err := cluster.CreateSession()
+	if err != nil {
+		return nil, classify("", "", fmt.Errorf("failed to connect to Cassandra: %w", err))
+	}
+	conn := &Connection{session: session, config: cfg}
+	if err := conn.Ping(ctx); err != nil {
+		session.Close()
+		return nil, classify("", "", fmt.Errorf("failed to ping Cassandra: %w", err))
+	}
+	return conn, nil
+}
+
+func (p *Plugin) TestConnection(ctx context.Context, config sdk.ConnectionConfig) (*sdk.ConnectionTestResult, error) {
+	return pluginsdk.TestConnection(ctx, p, config)
+}
+
+// pageSize is the number of rows requested per page.
+func pageSize(cfg *Config) int {
+	if cfg.FetchSize > 0 {
+		return cfg.FetchSize
+	}
+	return defaultPageSize
+}
+
+// Connection is an active Cassandra session.
+type Connection struct {
+	session *gocql.Session
+	config  *Config
+
+	metrics pluginsdk.QueryMetrics
+}
+
+// Query runs each statement of query in turn and returns the result of the
+// last one that produced rows. Bind parameters (?) are only accepted with
+// a single statement, since they cannot be attributed otherwise.
+func (c *Connection) Query(ctx context.Context, query string, args ...any) (*sdk.QueryResult, error) {
+	defer c.metrics.Track()()
+	start := time.Now()
+	stmts := splitStatements(query)
+	if len(stmts) == 0 {
+		return pluginsdk.EmptyResult(), nil
+	}
+	if len(args) > 0 && len(stmts) > 1 {
+		return nil, fmt.Errorf("query parameters are only supported for a single statement")
+	}
+
+	last := pluginsdk.EmptyResult()
+	for _, stmt := range stmts {
+		result, err := c.execOne(ctx, stmt, args)
+		if err != nil {
+			return nil, classify(query, stmt, err)
+		}
+		if len(result.Frames) > 0 && len(result.Frames[0].Fields) > 0 {
+			last = result
+		}
+	}
+	last.Stats.ExecutionTime = time.Since(start)
+	return last, nil
+}
+
+// execOne runs one statement, following its pages until the rows run out
+// or the query's memory budget does.
+func (c *Connection) execOne(ctx context.Context, stmt string, args []any) (*sdk.QueryResult, error) {
+	start := time.Now()
+	iter := c.session.Query(stmt, args...).WithContext(ctx).PageSize(pageSize(c.config)).Iter()
+	columns, rows, pages, err := scanAll(ctx, iter)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return pluginsdk.EmptyResult(), nil
+	}
+	result := pluginsdk.TableResult(columns, rows, time.Since(start))
+	result.Stats.FetchSize = c.config.FetchSize
+	result.Stats.Batches = pages
+	return result, nil
+}
+
+// scanAll reads every row of iter and closes it. Tuple columns are split
+// into one column per element, as gocql scans them.
+func scanAll(ctx context.Context, iter *gocql.Iter) ([]sdk.ColumnInfo, [][]any, int64, error) {
+	// RowData allocates one value per (expanded) column; its types tell us
+	// what to scan into.
+	rd, err := iter.RowData()
+	if err != nil {
+		_ = iter.Close()
+		return nil, nil, 0, fmt.Errorf("query execution failed: %w", err)
+	}
+	columns := columnInfo(iter.Columns())
+	if len(columns) == 0 {
+		return nil, nil, 0, iter.Close()
+	}
+
+	// Scanning into pointers to the driver's types lets NULL come back as
+	// nil rather than a zero value.
+	types := make([]reflect.Type, len(rd.Values))
+	for i, v := range rd.Values {
+		types[i] = reflect.PointerTo(reflect.TypeOf(v).Elem())
+	}
+	var rows [][]any
+	pages := int64(1)
+	budget := pluginsdk.NewRowBudget(ctx)
+	for {
+		if iter.WillSwitchPage() && len(rows) > 0 {
+			pages++
+		}
+		dest := make([]any, len(types))
+		for i, t := range types {
+			dest[i] = reflect.New(t).Interface()
+		}
+		if !iter.Scan(dest...) {
+			break
+		}
+		values := make([]any, len(dest))
+		for i, d := range dest {
+			values[i] = normalize(d)
+		}
+		if err := budget.Add(values); err != nil {
+			_ = iter.Close()
+			return nil, nil, 0, err
+		}
+		rows = append(rows, values)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, nil, 0, fmt.Errorf("query execution failed: %w", err)
+	}
+	return columns, rows, pages, nil
+}
+
+// columnInfo describes result columns, expanding tuples the way
+// gocql.Iter.RowData does.
+func columnInfo(cols []gocql.ColumnInfo) []sdk.ColumnInfo {
+	out := make([]sdk.ColumnInfo, 0, len(cols))
+	for _, col := range cols {
+		if tuple, ok := col.TypeInfo.(gocql.TupleTypeInfo); ok {
+			for i, elem := range tuple.Elems {
+				out = append(out, sdk.ColumnInfo{Name: gocql.TupleColumnName(col.Name, i), Type: fmt.Sprint(elem), Nullable: true})
+			}
+			continue
+		}
+		out = append(out, sdk.ColumnInfo{Name: col.Name, Type: fmt.Sprint(col.TypeInfo), Nullable: true})
+	}
+	return out
+}
+
+// normalize turns a scanned value into one that encodes cleanly as JSON.
+// Arbitrary-precision numbers become strings so they survive JavaScript.
+func normalize(v any) any {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		switch x := rv.Interface().(type) {
+		case *inf.Dec:
+			return x.String()
+		case *big.Int:
+			return x.String()
+		}
+		rv = rv.Elem()
+	}
+	if u, ok := rv.Interface().(gocql.UUID); ok {
+		return u.String()
+	}
+	return pluginsdk.NormalizeValue(rv.Interface())
+}
+
+// GetSchema lists every keyspace with its tables and materialized views.
+func (c *Connection) GetSchema(ctx context.Context) (*sdk.SchemaInfo, error) {
+	var names []string
+	scanner := c.session.Query("SELECT keyspace_name FROM system_schema.keyspaces").WithContext(ctx).Iter().Scanner()
+	for scanner.Next() {
+		var name string
+		if err := scanner.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to get keyspaces: %w", err)
+		}
+		names = append(names, name)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get keyspaces: %w", err)
+	}
+	sort.Strings(names)
+
+	databases := make([]sdk.DatabaseInfo, 0, len(names))
+	for _, name := range names {
+		tables, _ := c.GetTables(ctx, name)
+		databases = append(databases, sdk.DatabaseInfo{Name: name, Tables: tables})
+	}
+	return &sdk.SchemaInfo{Databases: databases}, nil
+}
+
+// GetTables lists the tables and materialized views of keyspace, or of the
+// configured keyspace when keyspace is empty, with their columns in
+// primary key order.
+func (c *Connection) GetTables(ctx context.Context, keyspace string) ([]sdk.TableInfo, error) {
+	if keyspace == "" {
+		keyspace = c.config.Keyspace
+	}
+	if keyspace == "" {
+		return nil, fmt.Errorf("keyspace is required")
+	}
+
+	columns, err := c.columns(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	var tables []sdk.TableInfo
+	for _, src := range []struct{ query, kind string }{
+		{"SELECT table_name, comment FROM system_schema.tables WHERE keyspace_name = ?", "table"},
+		{"SELECT view_name, comment FROM system_schema.views WHERE keyspace_name = ?", "view"},
+	} {
+		scanner := c.session.Query(src.query, keyspace).WithContext(ctx).Iter().Scanner()
+		for scanner.Next() {
+			var name, comment string
+			if err := scanner.Scan(&name, &comment); err != nil {
+				return nil, fmt.Errorf("failed to get tables: %w", err)
+			}
+			tables = append(tables, sdk.TableInfo{Name: name, Type: src.kind, Columns: columns[name], Description: comment})
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to get tables: %w", err)
+		}
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Name < tables[j].Name })
+	return tables, nil
+}
+
+// columnKinds orders columns the way DESCRIBE does: partition key, then
+// clustering columns, then static and regular columns.
+var columnKinds = map[string]int{"partition_key": 0, "clustering": 1, "static": 2, "regular": 3}
+
+// columns returns the columns of every table and view in keyspace, keyed
+// by table name. Primary key columns are the only ones that cannot be
+// NULL.
+func (c *Connection) columns(ctx context.Context, keyspace string) (map[string][]sdk.ColumnInfo, error) {
+	type column struct {
+		sdk.ColumnInfo
+		kind, position int
+	}
+	byTable := map[string][]column{}
+	scanner := c.session.Query(
+		"SELECT table_name, column_name, kind, position, type FROM system_schema.columns WHERE keyspace_name = ?", keyspace,
+	).WithContext(ctx).Iter().Scanner()
+	for scanner.Next() {
+		var table, name, kind, typ string
+		var position int
+		if err := scanner.Scan(&table, &name, &kind, &position, &typ); err != nil {
+			return nil, fmt.Errorf("failed to get columns: %w", err)
+		}
+		k := columnKinds[kind]
+		byTable[table] = append(byTable[table], column{
+			ColumnInfo: sdk.ColumnInfo{Name: name, Type: typ, Nullable: k > columnKinds["clustering"]},
+			kind:       k,
+			position:   position,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get columns: %w", err)
+	}
+
+	out := make(map[string][]sdk.ColumnInfo, len(byTable))
+	for table, cols := range byTable {
+		sort.Slice(cols, func(i, j int) bool {
+			a, b := cols[i], cols[j]
+			if a.kind != b.kind {
+				return a.kind < b.kind
+			}
+			if a.position != b.position {
+				return a.position < b.position
+			}
+			return a.Name < b.Name
+		})
+		infos := make([]sdk.ColumnInfo, len(cols))
+		for i, col := range cols {
+			infos[i] = col.ColumnInfo
+		}
+		out[table] = infos
+	}
+	return out, nil
+}
+
+func (c *Connection) Close() error {
+	if c.session != nil {
+		c.session.Close()
+	}
+	return nil
+}
+
+func (c *Connection) Ping(ctx context.Context) error {
+	return c.session.Query("SELECT release_version FROM system.local").WithContext(ctx).Exec()
+}
+
+func (c *Connection) GetMetrics() sdk.ConnectionMetrics {
+	metrics := sdk.ConnectionMetrics{
+		OpenConnections: 1,
+		LastActivity:    time.Now(),
+	}
+	c.metrics.Fill(&metrics)
+	return metrics
+}
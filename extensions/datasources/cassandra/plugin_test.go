@@ -0,0 +1,231 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"data-voyager/sdk"
+	"data-voyager/sdk/pluginsdk"
+	"data-voyager/sdk/plugintest"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"gopkg.in/inf.v0"
+)
+
+func TestCassandraPlugin(t *testing.T) {
+	ctx := context.Background()
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "cassandra:4.1",
+			ExposedPorts: []string{"9042/tcp"},
+			Env:          map[string]string{"MAX_HEAP_SIZE": "512M", "HEAP_NEWSIZE": "128M"},
+			WaitingFor:   wait.ForLog("Starting listening for CQL clients").WithStartupTimeout(3 * time.Minute),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	defer func() {
+		if err := testcontainers.TerminateContainer(container); err != nil {
+			t.Logf("failed to terminate container: %s", err)
+		}
+	}()
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+	port, err := container.MappedPort(ctx, "9042")
+	require.NoError(t, err)
+
+	plugin := &Plugin{}
+	assert.Equal(t, Type, plugin.GetType())
+	assert.Equal(t, "Cassandra", plugin.GetName())
+
+	config := &Config{
+		ContactPoints: []string{host},
+		Port:          port.Int(),
+		Datacenter:    "datacenter1",
+	}
+
+	conn, err := plugin.Connect(ctx, config)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	_, err = conn.Query(ctx, `
+		CREATE KEYSPACE IF NOT EXISTS shop
+			WITH replication = {'class': 'SimpleStrategy', 'replication_factor': 1};
+		CREATE TABLE IF NOT EXISTS shop.orders (
+			customer text, placed timeuuid, total decimal, note text, tags set<text>,
+			PRIMARY KEY (customer, placed)
+		) WITH comment = 'orders; by customer';
+	`)
+	require.NoError(t, err)
+	for i := range 25 {
+		_, err = conn.Query(ctx, "INSERT INTO shop.orders (customer, placed, total, tags) VALUES (?, now(), ?, {'a'})",
+			"ann", inf.NewDec(int64(i)*125, 2))
+		require.NoError(t, err)
+	}
+
+	t.Run("Contract", func(t *testing.T) {
+		bad := *config
+		bad.ContactPoints, bad.Port = []string{"127.0.0.1"}, 1
+		plugintest.Run(t, plugintest.Suite{
+			Plugin:    plugin,
+			Config:    config,
+			BadConfig: &bad,
+			Database:  "shop",
+			SelectOne: "SELECT COUNT(*) FROM system.local",
+		})
+	})
+
+	t.Run("Paging", func(t *testing.T) {
+		paged := *config
+		paged.FetchSize = 10
+		conn, err := plugin.Connect(ctx, &paged)
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+
+		res, err := conn.Query(ctx, "SELECT customer, placed, total, note, tags FROM shop.orders WHERE customer = ?", "ann")
+		require.NoError(t, err)
+		assert.EqualValues(t, 25, res.Stats.RowsReturned)
+		assert.EqualValues(t, 3, res.Stats.Batches)
+		assert.Equal(t, 10, res.Stats.FetchSize)
+
+		fields := res.Frames[0].Fields
+		assert.Equal(t, "placed", fields[1].Name)
+		assert.IsType(t, "", fields[1].Values[0], "uuids are strings")
+		assert.Equal(t, "0.00", fields[2].Values[0])
+		assert.Nil(t, fields[3].Values[0], "NULL is nil, not an empty string")
+		assert.Equal(t, []string{"a"}, fields[4].Values[0])
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		tables, err := conn.GetTables(ctx, "shop")
+		require.NoError(t, err)
+		require.Len(t, tables, 1)
+		orders := tables[0]
+		assert.Equal(t, "orders", orders.Name)
+		assert.Equal(t, "orders; by customer", orders.Description)
+		var names []string
+		for _, c := range orders.Columns {
+			names = append(names, c.Name)
+		}
+		assert.Equal(t, []string{"customer", "placed", "note", "tags", "total"}, names)
+		assert.False(t, orders.Columns[0].Nullable)
+		assert.True(t, orders.Columns[2].Nullable)
+
+		schema, err := conn.GetSchema(ctx)
+		require.NoError(t, err)
+		var keyspaces []string
+		for _, db := range schema.Databases {
+			keyspaces = append(keyspaces, db.Name)
+		}
+		assert.Contains(t, keyspaces, "shop")
+		assert.Contains(t, keyspaces, "system_schema")
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		for query, code := range map[string]string{
+			"SELECT * FROM shop.missing":          sdk.ErrCodeUndefinedObject,
+			"SELECT * FROM nowhere.orders":        sdk.ErrCodeUnknownDatabase,
+			"SELECT nope FROM shop.orders":        sdk.ErrCodeUndefinedObject,
+			"SELECT * FROM shop.orders;\nSELEC 1": sdk.ErrCodeSyntaxError,
+		} {
+			_, err := conn.Query(ctx, query)
+			var qe *sdk.QueryError
+			require.True(t, errors.As(err, &qe), "%s: %v", query, err)
+			assert.Equal(t, code, qe.Code, query)
+		}
+	})
+}
+
+func TestSplitStatements(t *testing.T) {
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		{"SELECT 1", []string{"SELECT 1"}},
+		{"SELECT 1; SELECT 2;", []string{"SELECT 1", "SELECT 2"}},
+		{"INSERT INTO t (a) VALUES ('x;y'); SELECT 1", []string{"INSERT INTO t (a) VALUES ('x;y')", "SELECT 1"}},
+		{"INSERT INTO t (a) VALUES ('it''s; ok')", []string{"INSERT INTO t (a) VALUES ('it''s; ok')"}},
+		{`SELECT "a;b" FROM t`, []string{`SELECT "a;b" FROM t`}},
+		{"SELECT 1 -- one; two\n; SELECT 2 // three; four", []string{"SELECT 1", "SELECT 2"}},
+		{"SELECT /* a; b */ 1", []string{"SELECT   1"}},
+		{"CREATE FUNCTION f() RETURNS int LANGUAGE java AS $$ return 1; $$; SELECT 1",
+			[]string{"CREATE FUNCTION f() RETURNS int LANGUAGE java AS $$ return 1; $$", "SELECT 1"}},
+		{" ;; ", nil},
+	} {
+		assert.Equal(t, tc.want, splitStatements(tc.query), tc.query)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := &Config{ContactPoints: []string{"10.0.0.1", "10.0.0.2:9142", "::1"}}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, 9042, cfg.Port)
+	assert.Equal(t, defaultConsistency, cfg.Consistency)
+	assert.Equal(t, "cassandra://10.0.0.1:9042,10.0.0.2:9142,[::1]:9042/?username=&password=", cfg.GetConnectionString())
+
+	cfg.Consistency = "local_one"
+	level, err := cfg.consistency()
+	require.NoError(t, err)
+	assert.Equal(t, gocql.LocalOne, level)
+
+	for _, bad := range []*Config{
+		{},
+		{ContactPoints: []string{" "}},
+		{ContactPoints: []string{"h"}, Consistency: "most"},
+		{ContactPoints: []string{"h"}, FetchOptions: pluginsdk.FetchOptions{FetchSize: -1}},
+	} {
+		assert.Error(t, bad.Validate(), "%+v", bad)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	query := "SELECT 1 FROM system.local;\nSELECT *\nFRM t"
+	stmt := "SELECT *\nFRM t"
+	err := classify(query, stmt, fmt.Errorf("query execution failed: %w", requestError{
+		code: gocql.ErrCodeSyntax,
+		msg:  "line 2:0 mismatched input 'FRM' expecting K_FROM (SELECT *[FRM]...)",
+	}))
+	var qe *sdk.QueryError
+	require.True(t, errors.As(err, &qe))
+	assert.Equal(t, sdk.ErrCodeSyntaxError, qe.Code)
+	assert.Equal(t, 3, qe.Line)
+	assert.Equal(t, 1, qe.Column)
+
+	other := requestError{code: gocql.ErrCodeInvalid, msg: "Cannot execute this query as it might involve data filtering"}
+	assert.Equal(t, error(other), classify("", "", other), "unclassified errors pass through")
+}
+
+func TestNormalize(t *testing.T) {
+	s := "x"
+	ps := &s
+	var nilString *string
+	assert.Equal(t, "x", normalize(&ps))
+	assert.Nil(t, normalize(&nilString))
+
+	dec := inf.NewDec(1234, 2)
+	assert.Equal(t, "12.34", normalize(&dec))
+	n, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	assert.Equal(t, "123456789012345678901234567890", normalize(&n))
+
+	u := gocql.TimeUUID()
+	pu := &u
+	assert.Equal(t, u.String(), normalize(&pu))
+}
+
+type requestError struct {
+	code int
+	msg  string
+}
+
+func (e requestError) Code() int       { return e.code }
+func (e requestError) Message() string { return e.msg }
+func (e requestError) Error() string   { return e.msg }
@@ -0,0 +1,64 @@
+package cassandra
+
+import "strings"
+
+// splitStatements splits a CQL script on `;` delimiters. Semicolons inside
+// 'strings', "quoted identifiers", $$function bodies$$ and comments (--,
+// // and /* */) do not split; comments are dropped.
+func splitStatements(query string) []string {
+	var parts []string
+	var cur strings.Builder
+	flush := func() {
+		if part := strings.TrimSpace(cur.String()); part != "" {
+			parts = append(parts, part)
+		}
+		cur.Reset()
+	}
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		rest := query[i:]
+		switch {
+		case strings.HasPrefix(rest, "--") || strings.HasPrefix(rest, "//"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				i = len(query)
+				continue
+			}
+			i += end - 1
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				i = len(query)
+				continue
+			}
+			i += end + 3
+			cur.WriteByte(' ')
+		case strings.HasPrefix(rest, "$$"):
+			end := strings.Index(rest[2:], "$$")
+			if end < 0 {
+				cur.WriteString(rest)
+				i = len(query)
+				continue
+			}
+			cur.WriteString(rest[:end+4])
+			i += end + 3
+		case ch == '\'' || ch == '"':
+			// A doubled quote escapes itself, which scanning the doubled
+			// quote as the end and start of two literals handles.
+			end := strings.IndexByte(rest[1:], ch)
+			if end < 0 {
+				cur.WriteString(rest)
+				i = len(query)
+				continue
+			}
+			cur.WriteString(rest[:end+2])
+			i += end + 1
+		case ch == ';':
+			flush()
+		default:
+			cur.WriteByte(ch)
+		}
+	}
+	flush()
+	return parts
+}
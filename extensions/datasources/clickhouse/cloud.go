@@ -0,0 +1,137 @@
+package clickhouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"data-voyager/sdk"
+)
+
+// DefaultCloudAPIURL is the ClickHouse Cloud API.
+const DefaultCloudAPIURL = "https://api.clickhouse.cloud"
+
+// CloudService identifies the ClickHouse Cloud service behind Host and the
+// API key that may check and start it. Services with idle scaling suspend
+// after a quiet period; with this set, queries against a suspended service
+// wake it instead of waiting out the resume.
+type CloudService struct {
+	OrganizationID string `json:"organization_id" toml:"organization_id"`
+	ServiceID      string `json:"service_id" toml:"service_id"`
+	KeyID          string `json:"key_id" toml:"key_id"`
+	KeySecret      string `json:"key_secret" toml:"key_secret"`
+	// APIURL defaults to DefaultCloudAPIURL.
+	APIURL string `json:"api_url,omitempty" toml:"api_url"`
+}
+
+func (s *CloudService) Validate() error {
+	if s.OrganizationID == "" || s.ServiceID == "" {
+		return fmt.Errorf("cloud.organization_id and cloud.service_id are required")
+	}
+	if s.KeyID == "" || s.KeySecret == "" {
+		return fmt.Errorf("cloud.key_id and cloud.key_secret are required")
+	}
+	if s.APIURL != "" {
+		u, err := url.Parse(s.APIURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("cloud.api_url must be an http or https URL")
+		}
+	}
+	return nil
+}
+
+// cloudStates maps Cloud API service states onto warehouse states. Those
+// not listed, such as "running" and "degraded", are reported as running so
+// queries go ahead and fail on their own terms.
+var cloudStates = map[string]sdk.WarehouseState{
+	"idle":     sdk.WarehouseSuspended,
+	"stopped":  sdk.WarehouseSuspended,
+	"stopping": sdk.WarehouseSuspended,
+	"awaking":  sdk.WarehouseResuming,
+	"starting": sdk.WarehouseResuming,
+}
+
+var cloudClient = &http.Client{Timeout: 30 * time.Second}
+
+// WarehouseStatus reports the state of the configured Cloud service.
+func (p *Plugin) WarehouseStatus(ctx context.Context, config sdk.ConnectionConfig) (*sdk.WarehouseStatus, error) {
+	svc := cloudService(config)
+	if svc == nil {
+		return nil, nil
+	}
+	var body struct {
+		Result struct {
+			State string `json:"state"`
+		} `json:"result"`
+	}
+	if err := svc.call(ctx, http.MethodGet, "", nil, &body); err != nil {
+		return nil, err
+	}
+	state, ok := cloudStates[body.Result.State]
+	if !ok {
+		state = sdk.WarehouseRunning
+	}
+	return &sdk.WarehouseStatus{State: state, Detail: body.Result.State}, nil
+}
+
+// ResumeWarehouse starts the configured Cloud service.
+func (p *Plugin) ResumeWarehouse(ctx context.Context, config sdk.ConnectionConfig) error {
+	svc := cloudService(config)
+	if svc == nil {
+		return fmt.Errorf("no ClickHouse Cloud service configured")
+	}
+	return svc.call(ctx, http.MethodPatch, "/state", map[string]string{"command": "start"}, nil)
+}
+
+func cloudService(config sdk.ConnectionConfig) *CloudService {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return nil
+	}
+	return cfg.Cloud
+}
+
+// call sends a request about the service to the Cloud API and decodes the
+// response into out, when given.
+func (s *CloudService) call(ctx context.Context, method, path string, in, out any) error {
+	base := s.APIURL
+	if base == "" {
+		base = DefaultCloudAPIURL
+	}
+	u := fmt.Sprintf("%s/v1/organizations/%s/services/%s%s",
+		base, url.PathEscape(s.OrganizationID), url.PathEscape(s.ServiceID), path)
+	var body io.Reader
+	if in != nil {
+		raw, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.KeyID, s.KeySecret)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := cloudClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("clickhouse cloud API: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("clickhouse cloud API: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -1,6 +1,13 @@
 package clickhouse
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	goch "github.com/ClickHouse/clickhouse-go/v2"
+
+	"data-voyager/sdk/pluginsdk"
+)
 
 // Config holds ClickHouse connection parameters.
 type Config struct {
@@ -10,6 +17,19 @@ type Config struct {
 	Username string `json:"username" toml:"username"`
 	Password string `json:"password" toml:"password"`
 	Secure   bool   `json:"secure" toml:"secure"`
+	// Compression is the block compression negotiated for results: lz4
+	// (the default), lz4hc, zstd or none. CompressionLevel applies to lz4hc
+	// and is left to the driver when zero.
+	Compression      string `json:"compression,omitempty" toml:"compression"`
+	CompressionLevel int    `json:"compression_level,omitempty" toml:"compression_level"`
+	// Cloud, when set, lets queries wake the ClickHouse Cloud service
+	// behind Host when idle scaling has suspended it.
+	Cloud *CloudService `json:"cloud,omitempty" toml:"cloud"`
+
+	pluginsdk.DialOptions
+	// FetchSize, when set, is sent as max_block_size so the server streams
+	// results in blocks of about this many rows.
+	pluginsdk.FetchOptions
 }
 
 func (c *Config) Validate() error {
@@ -19,7 +39,18 @@ func (c *Config) Validate() error {
 	if c.Port <= 0 {
 		c.Port = 9000
 	}
-	return nil
+	if _, err := c.compression(); err != nil {
+		return err
+	}
+	if c.Cloud != nil {
+		if err := c.Cloud.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := c.DialOptions.Validate(); err != nil {
+		return err
+	}
+	return c.FetchOptions.Validate()
 }
 
 func (c *Config) GetConnectionString() string {
@@ -30,3 +61,29 @@ func (c *Config) GetConnectionString() string {
 	return fmt.Sprintf("%s://%s:%d/%s?username=%s&password=%s",
 		protocol, c.Host, c.Port, c.Database, c.Username, c.Password)
 }
+
+// compressionMethods are the methods the native protocol supports.
+var compressionMethods = map[string]goch.CompressionMethod{
+	"none":  goch.CompressionNone,
+	"lz4":   goch.CompressionLZ4,
+	"lz4hc": goch.CompressionLZ4HC,
+	"zstd":  goch.CompressionZSTD,
+}
+
+// compression returns the driver setting for Compression. Results are
+// compressed with lz4 unless configured otherwise: it is cheap enough that
+// it pays for itself on almost any network.
+func (c *Config) compression() (*goch.Compression, error) {
+	name := strings.ToLower(c.Compression)
+	if name == "" {
+		name = "lz4"
+	}
+	method, ok := compressionMethods[name]
+	if !ok {
+		return nil, fmt.Errorf("compression must be one of lz4, lz4hc, zstd or none, got %q", c.Compression)
+	}
+	if c.CompressionLevel < 0 {
+		return nil, fmt.Errorf("compression_level must not be negative")
+	}
+	return &goch.Compression{Method: method, Level: c.CompressionLevel}, nil
+}
@@ -0,0 +1,47 @@
+package clickhouse
+
+import "data-voyager/sdk"
+
+// Dialect implements sdk.DialectProvider.
+func (p *Plugin) Dialect() sdk.Dialect {
+	return sdk.Dialect{
+		IdentifierQuote: "`",
+		StringQuote:     "'",
+		ParamStyle:      sdk.ParamStyleNone,
+		ReservedWords:   reservedWords,
+		Functions:       functions,
+	}
+}
+
+var reservedWords = append(append([]string{}, sdk.ANSIReservedWords...),
+	"ARRAY", "ASOF", "ATTACH", "DATABASE", "DESCRIBE", "DETACH", "ENGINE",
+	"EXPLAIN", "FINAL", "FORMAT", "GLOBAL", "ILIKE", "INTERVAL", "OPTIMIZE",
+	"PARTITION", "PREWHERE", "SAMPLE", "SETTINGS", "SHOW", "SYSTEM", "TOTALS",
+	"TRUNCATE", "TTL",
+)
+
+var functions = []sdk.FunctionDoc{
+	{Name: "count", Signature: "count([expr])", ReturnType: "UInt64", Description: "Counts rows, or non-NULL values of expr."},
+	{Name: "sum", Signature: "sum(x)", Description: "Calculates the sum of numeric values."},
+	{Name: "avg", Signature: "avg(x)", ReturnType: "Float64", Description: "Calculates the arithmetic mean."},
+	{Name: "min", Signature: "min(x)", Description: "Minimum value across a group."},
+	{Name: "max", Signature: "max(x)", Description: "Maximum value across a group."},
+	{Name: "uniq", Signature: "uniq(x[, ...])", ReturnType: "UInt64", Description: "Approximate number of distinct values using an adaptive sampling algorithm."},
+	{Name: "uniqExact", Signature: "uniqExact(x[, ...])", ReturnType: "UInt64", Description: "Exact number of distinct values; uses more memory than uniq."},
+	{Name: "quantile", Signature: "quantile(level)(expr)", Description: "Approximate quantile of a numeric sequence, e.g. quantile(0.95)(latency)."},
+	{Name: "groupArray", Signature: "groupArray([max_size])(x)", ReturnType: "Array", Description: "Creates an array of argument values."},
+	{Name: "argMax", Signature: "argMax(arg, val)", Description: "Value of arg for the row with the maximum val."},
+	{Name: "countIf", Signature: "countIf(cond)", ReturnType: "UInt64", Description: "Counts rows where cond is true."},
+	{Name: "sumIf", Signature: "sumIf(x, cond)", Description: "Sums x over rows where cond is true."},
+	{Name: "now", Signature: "now([timezone])", ReturnType: "DateTime", Description: "Current date and time at the moment of query analysis."},
+	{Name: "toStartOfInterval", Signature: "toStartOfInterval(t, INTERVAL x unit[, timezone])", ReturnType: "DateTime", Description: "Rounds a time down to the start of the given interval."},
+	{Name: "toStartOfHour", Signature: "toStartOfHour(t)", ReturnType: "DateTime", Description: "Rounds a time down to the start of the hour."},
+	{Name: "toDate", Signature: "toDate(expr)", ReturnType: "Date", Description: "Converts the argument to Date."},
+	{Name: "formatDateTime", Signature: "formatDateTime(t, format[, timezone])", ReturnType: "String", Description: "Formats a time according to a MySQL-style format string."},
+	{Name: "lower", Signature: "lower(s)", ReturnType: "String", Description: "Converts ASCII letters to lower case."},
+	{Name: "upper", Signature: "upper(s)", ReturnType: "String", Description: "Converts ASCII letters to upper case."},
+	{Name: "length", Signature: "length(x)", ReturnType: "UInt64", Description: "Length of a string in bytes, or of an array in elements."},
+	{Name: "JSONExtractString", Signature: "JSONExtractString(json[, indices_or_keys...])", ReturnType: "String", Description: "Parses JSON and extracts a string value."},
+	{Name: "if", Signature: "if(cond, then, else)", Description: "Returns then when cond is non-zero, otherwise else."},
+	{Name: "arrayJoin", Signature: "arrayJoin(arr)", Description: "Unfolds an array into one row per element."},
+}
@@ -0,0 +1,48 @@
+package clickhouse
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+
+	goch "github.com/ClickHouse/clickhouse-go/v2"
+
+	"data-voyager/sdk"
+)
+
+// exceptionCodes maps server error codes to sdk error codes; see
+// src/Common/ErrorCodes.cpp in the ClickHouse source.
+var exceptionCodes = map[int32]string{
+	516: sdk.ErrCodeAuthFailed, // AUTHENTICATION_FAILED
+	192: sdk.ErrCodeAuthFailed, // UNKNOWN_USER
+	194: sdk.ErrCodeAuthFailed, // REQUIRED_PASSWORD
+	81:  sdk.ErrCodeUnknownDatabase,
+	497: sdk.ErrCodePermissionDenied, // ACCESS_DENIED
+	60:  sdk.ErrCodeUndefinedObject,  // UNKNOWN_TABLE
+	47:  sdk.ErrCodeUndefinedObject,  // UNKNOWN_IDENTIFIER
+	46:  sdk.ErrCodeUndefinedObject,  // UNKNOWN_FUNCTION
+	16:  sdk.ErrCodeUndefinedObject,  // NO_SUCH_COLUMN_IN_TABLE
+	62:  sdk.ErrCodeSyntaxError,      // SYNTAX_ERROR
+}
+
+// positionRe finds the offset in "Syntax error: failed at position 8".
+var positionRe = regexp.MustCompile(`failed at position (\d+)`)
+
+// classify wraps a server exception in an sdk.QueryError when its code is
+// one clients care about. stmt is the statement of query that failed.
+func classify(query, stmt string, err error) error {
+	var ex *goch.Exception
+	if !errors.As(err, &ex) {
+		return err
+	}
+	code, ok := exceptionCodes[ex.Code]
+	if !ok {
+		return err
+	}
+	qe := &sdk.QueryError{Code: code, Err: err}
+	if m := positionRe.FindStringSubmatch(ex.Message); m != nil {
+		pos, _ := strconv.Atoi(m[1])
+		qe.Locate(query, stmt, pos)
+	}
+	return qe
+}
@@ -0,0 +1,134 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"data-voyager/sdk"
+
+	goch "github.com/ClickHouse/clickhouse-go/v2"
+)
+
+var (
+	_ sdk.CardinalityEstimator = (*Connection)(nil)
+	_ sdk.ScanEstimator        = (*Connection)(nil)
+)
+
+// quoteIdent wraps a ClickHouse identifier in backticks. Backslash is an
+// escape character inside quoted identifiers, so it is doubled along with
+// the backtick.
+func quoteIdent(name string) string {
+	return "`" + identEscaper.Replace(name) + "`"
+}
+
+var identEscaper = strings.NewReplacer(`\`, `\\`, "`", "``")
+
+// CountRows sums active part row counts from system.parts. ReplacingMergeTree
+// and CollapsingMergeTree counts include rows not yet merged away, so the
+// result is flagged approximate; exact=true runs count() instead.
+func (c *Connection) CountRows(ctx context.Context, database, table string, exact bool) (*sdk.RowCount, error) {
+	if database == "" {
+		database = c.config.Database
+	}
+	if !exact {
+		query := `
+			SELECT toInt64(sum(rows)), count()
+			FROM system.parts
+			WHERE active AND database = {database:String} AND table = {table:String}
+		`
+		_, rows, err := c.queryRaw(ctx, query, goch.Parameters{"database": database, "table": table})
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate row count: %w", err)
+		}
+		// Tables without parts (non-MergeTree engines) fall through to count().
+		if len(rows) > 0 {
+			if parts, _ := rows[0][1].(uint64); parts > 0 {
+				n, _ := rows[0][0].(int64)
+				return &sdk.RowCount{Count: n, Approximate: true, Method: "system.parts"}, nil
+			}
+		}
+	}
+
+	query := fmt.Sprintf("SELECT toInt64(count()) FROM %s.%s", quoteIdent(database), quoteIdent(table))
+	_, rows, err := c.queryRaw(ctx, query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count rows: %w", err)
+	}
+	n, _ := rows[0][0].(int64)
+	return &sdk.RowCount{Count: n, Method: "count"}, nil
+}
+
+// CountDistinct uses uniq(), ClickHouse's adaptive-sampling cardinality sketch.
+func (c *Connection) CountDistinct(ctx context.Context, database, table, column string) (*sdk.RowCount, error) {
+	if database == "" {
+		database = c.config.Database
+	}
+	query := fmt.Sprintf("SELECT toInt64(uniq(%s)) FROM %s.%s",
+		quoteIdent(column), quoteIdent(database), quoteIdent(table))
+	_, rows, err := c.queryRaw(ctx, query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate distinct count: %w", err)
+	}
+	n, _ := rows[0][0].(int64)
+	return &sdk.RowCount{Count: n, Approximate: true, Method: "uniq"}, nil
+}
+
+// EstimateScan asks EXPLAIN ESTIMATE how many rows each statement reads
+// from each MergeTree table and prices those rows at the table's average
+// compressed row size from system.tables. Tables of other engines are not
+// counted.
+func (c *Connection) EstimateScan(ctx context.Context, query string, args ...any) (*sdk.ScanEstimate, error) {
+	params, err := bindParams(args)
+	if err != nil {
+		return nil, err
+	}
+	type tableRows struct{ database, table string }
+	read := map[tableRows]int64{}
+	for _, stmt := range splitStatements(query) {
+		_, rows, err := c.queryRaw(ctx, "EXPLAIN ESTIMATE "+stmt, params)
+		if err != nil {
+			return nil, classify(query, stmt, err)
+		}
+		for _, row := range rows {
+			database, _ := row[0].(string)
+			table, _ := row[1].(string)
+			n, _ := row[3].(uint64)
+			read[tableRows{database, table}] += int64(n)
+		}
+	}
+
+	est := &sdk.ScanEstimate{Method: "explain estimate"}
+	for t, n := range read {
+		_, rows, err := c.queryRaw(ctx, `
+			SELECT total_rows, total_bytes
+			FROM system.tables
+			WHERE database = {database:String} AND name = {table:String}
+		`, goch.Parameters{"database": t.database, "table": t.table})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read table size: %w", err)
+		}
+		est.Rows += n
+		if len(rows) == 0 {
+			continue
+		}
+		total, size := nullableUint(rows[0][0]), nullableUint(rows[0][1])
+		if total > 0 {
+			est.Bytes += int64(float64(n) / float64(total) * float64(size))
+		}
+	}
+	return est, nil
+}
+
+// nullableUint reads a Nullable(UInt64) value, as 0 when NULL.
+func nullableUint(v any) uint64 {
+	switch x := v.(type) {
+	case uint64:
+		return x
+	case *uint64:
+		if x != nil {
+			return *x
+		}
+	}
+	return 0
+}
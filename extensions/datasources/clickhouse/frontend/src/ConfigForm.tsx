@@ -1,5 +1,6 @@
 import { Input } from '@data-voyager/shared-ui/components/ui/input'
 import { Label } from '@data-voyager/shared-ui/components/ui/label'
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from '@data-voyager/shared-ui/components/ui/select'
 import { Switch } from '@data-voyager/shared-ui/components/ui/switch'
 import type { DatasourceConfigProps } from '@data-voyager/sdk'
 
@@ -10,14 +11,41 @@ interface ClickHouseConfig {
   username: string
   password: string
   secure: boolean
+  compression: string
+  cloud?: CloudService
 }
 
+interface CloudService {
+  organization_id: string
+  service_id: string
+  key_id: string
+  key_secret: string
+}
+
+const CLOUD_FIELDS: { key: keyof CloudService; label: string; secret?: boolean }[] = [
+  { key: 'organization_id', label: 'Organization ID' },
+  { key: 'service_id', label: 'Service ID' },
+  { key: 'key_id', label: 'API key ID' },
+  { key: 'key_secret', label: 'API key secret', secret: true },
+]
+
+const COMPRESSION_METHODS = ['lz4', 'lz4hc', 'zstd', 'none']
+
 export function ClickHouseConfigForm({ config, onChange }: DatasourceConfigProps) {
   const cfg = config as Partial<ClickHouseConfig>
 
   const set = (key: keyof ClickHouseConfig, value: string | number | boolean) =>
     onChange({ ...cfg, [key]: value })
 
+  // Without a Cloud API key the service is never checked or woken.
+  const toggleCloud = (on: boolean) =>
+    onChange({
+      ...cfg,
+      cloud: on ? { organization_id: '', service_id: '', key_id: '', key_secret: '' } : undefined,
+    })
+  const setCloud = (key: keyof CloudService, value: string) =>
+    onChange({ ...cfg, cloud: { ...(cfg.cloud as CloudService), [key]: value } })
+
   return (
     <div className="flex flex-col gap-4 max-w-lg">
       <div className="grid grid-cols-[1fr_120px] gap-3">
@@ -77,6 +105,49 @@ export function ClickHouseConfigForm({ config, onChange }: DatasourceConfigProps
           Secure (TLS)
         </Label>
       </div>
+
+      <div className="space-y-2">
+        <Label>Compression</Label>
+        <Select
+          value={cfg.compression || 'lz4'}
+          onValueChange={(v) => v !== null && set('compression', v)}
+        >
+          <SelectTrigger>
+            <SelectValue />
+          </SelectTrigger>
+          <SelectContent>
+            {COMPRESSION_METHODS.map((m) => (
+              <SelectItem key={m} value={m}>{m}</SelectItem>
+            ))}
+          </SelectContent>
+        </Select>
+      </div>
+
+      <div className="flex items-center gap-3">
+        <Switch
+          checked={cfg.cloud != null}
+          onCheckedChange={toggleCloud}
+          id="cloud"
+        />
+        <Label htmlFor="cloud" className="cursor-pointer">
+          Wake idle ClickHouse Cloud service
+        </Label>
+      </div>
+
+      {cfg.cloud && (
+        <div className="grid grid-cols-2 gap-3">
+          {CLOUD_FIELDS.map((f) => (
+            <div key={f.key} className="space-y-2">
+              <Label>{f.label}</Label>
+              <Input
+                type={f.secret ? 'password' : 'text'}
+                value={cfg.cloud?.[f.key] ?? ''}
+                onChange={(e) => setCloud(f.key, e.target.value)}
+              />
+            </div>
+          ))}
+        </div>
+      )}
     </div>
   )
 }
@@ -0,0 +1,100 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"data-voyager/sdk"
+
+	goch "github.com/ClickHouse/clickhouse-go/v2"
+)
+
+var _ sdk.SystemHealthReporter = (*Connection)(nil)
+
+// maxHealthSessions caps how many system.processes rows a health snapshot
+// returns.
+const maxHealthSessions = 500
+
+// SystemHealth reads running queries from system.processes and gauges from
+// system.metrics. ClickHouse has no idle sessions in system.processes, so
+// every reported session is "active". The snapshot's own query is excluded.
+func (c *Connection) SystemHealth(ctx context.Context) (*sdk.SystemHealth, error) {
+	query := fmt.Sprintf(`
+		SELECT query_id, user, current_database, query, toInt64(elapsed * 1000)
+		FROM system.processes
+		WHERE query_id != queryID()
+		ORDER BY elapsed DESC
+		LIMIT %d
+	`, maxHealthSessions)
+	_, rows, err := c.queryRaw(ctx, query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read system.processes: %w", err)
+	}
+	health := &sdk.SystemHealth{Sessions: make([]sdk.SystemSession, 0, len(rows))}
+	for _, r := range rows {
+		ms, _ := r[4].(int64)
+		health.Sessions = append(health.Sessions, sdk.SystemSession{
+			ID:       str(r[0]),
+			User:     str(r[1]),
+			Database: str(r[2]),
+			State:    "active",
+			Query:    str(r[3]),
+			Elapsed:  time.Duration(ms) * time.Millisecond,
+		})
+	}
+
+	query = `
+		SELECT
+			toFloat64(sumIf(value, metric IN ('TCPConnection', 'HTTPConnection', 'MySQLConnection', 'PostgreSQLConnection'))),
+			toFloat64(sumIf(value, metric = 'Query')),
+			toFloat64(uptime()),
+			toFloat64(sumIf(value, metric = 'MemoryTracking')),
+			toFloat64(sumIf(value, metric = 'Merge')),
+			toFloat64(sumIf(value, metric = 'BackgroundMergesAndMutationsPoolTask'))
+		FROM system.metrics
+	`
+	_, rows, err = c.queryRaw(ctx, query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read system.metrics: %w", err)
+	}
+	if len(rows) == 0 {
+		return health, nil
+	}
+	names := []string{
+		sdk.MetricConnections,
+		sdk.MetricActiveQueries,
+		sdk.MetricUptimeSeconds,
+		sdk.MetricMemoryBytes,
+		"ch.merges",
+		"ch.background_pool_tasks",
+	}
+	for i, name := range names {
+		v, _ := rows[0][i].(float64)
+		health.Metrics = append(health.Metrics, sdk.SystemMetric{Name: name, Value: v})
+	}
+	return health, nil
+}
+
+// str returns v as a string, or "" for NULL and non-string values.
+func str(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+var _ sdk.SessionTerminator = (*Connection)(nil)
+
+// TerminateSession runs KILL QUERY for the query_id id. ClickHouse sessions
+// outlive their queries only over HTTP and cannot be closed server-side, so
+// queryOnly makes no difference.
+func (c *Connection) TerminateSession(ctx context.Context, id string, _ bool) error {
+	query := "KILL QUERY WHERE query_id = {id:String}"
+	_, rows, err := c.queryRaw(ctx, query, goch.Parameters{"id": id})
+	if err != nil {
+		return classify(query, query, err)
+	}
+	if len(rows) == 0 {
+		return sdk.ErrSessionNotFound
+	}
+	return nil
+}
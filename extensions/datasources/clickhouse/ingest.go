@@ -0,0 +1,90 @@
+package clickhouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	goch "github.com/ClickHouse/clickhouse-go/v2"
+
+	"data-voyager/sdk"
+)
+
+var _ sdk.Ingester = (*Connection)(nil)
+
+// batchExceptions are server errors that no record of a batch can avoid,
+// so ingestion gives up instead of looking for the records at fault.
+var batchExceptions = map[int32]bool{
+	60:  true, // UNKNOWN_TABLE
+	81:  true, // UNKNOWN_DATABASE
+	164: true, // READONLY
+	497: true, // ACCESS_DENIED
+	516: true, // AUTHENTICATION_FAILED
+}
+
+// Ingest inserts records with INSERT ... FORMAT JSONEachRow, so the server
+// converts JSON values to the column types. An insert into a MergeTree
+// table is all or nothing; when a batch fails it is split in halves and
+// each retried, narrowing down to the records at fault while the rest are
+// inserted. target is a table, optionally qualified as database.table.
+func (c *Connection) Ingest(ctx context.Context, target string, records []json.RawMessage) (*sdk.IngestResult, error) {
+	database, table, ok := strings.Cut(target, ".")
+	if !ok {
+		database, table = c.config.Database, target
+	}
+	if table == "" {
+		return nil, fmt.Errorf("table is required")
+	}
+	insert := "INSERT INTO " + quoteIdent(table) + " FORMAT JSONEachRow\n"
+	if database != "" {
+		insert = "INSERT INTO " + quoteIdent(database) + "." + quoteIdent(table) + " FORMAT JSONEachRow\n"
+	}
+	return ingestBisect(records, func(batch []json.RawMessage) error {
+		var body bytes.Buffer
+		body.WriteString(insert)
+		for _, r := range batch {
+			body.Write(r)
+			body.WriteByte('\n')
+		}
+		return c.conn.Exec(ctx, body.String())
+	}, func(err error) bool {
+		var ex *goch.Exception
+		return ctx.Err() != nil || !errors.As(err, &ex) || batchExceptions[ex.Code]
+	})
+}
+
+// ingestBisect inserts records with insert, splitting failed batches until
+// each failure is pinned on a single record. An error fatal reports as
+// affecting the whole batch is returned as is.
+func ingestBisect(records []json.RawMessage, insert func([]json.RawMessage) error, fatal func(error) bool) (*sdk.IngestResult, error) {
+	res := &sdk.IngestResult{Errors: []sdk.IngestError{}}
+	var run func(offset int, batch []json.RawMessage) error
+	run = func(offset int, batch []json.RawMessage) error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := insert(batch)
+		switch {
+		case err == nil:
+			res.Accepted += len(batch)
+			return nil
+		case fatal(err):
+			return err
+		case len(batch) == 1:
+			res.Errors = append(res.Errors, sdk.IngestError{Index: offset, Error: err.Error()})
+			return nil
+		}
+		half := len(batch) / 2
+		if err := run(offset, batch[:half]); err != nil {
+			return err
+		}
+		return run(offset+half, batch[half:])
+	}
+	if err := run(0, records); err != nil {
+		return res, err
+	}
+	return res, nil
+}
@@ -0,0 +1,99 @@
+package clickhouse
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	goch "github.com/ClickHouse/clickhouse-go/v2"
+)
+
+var paramNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// bindParams turns the arguments core passes to Query into server-side
+// query parameters, referenced in SQL as {name:Type}. ClickHouse parses each
+// value as the type the query declares for it, so nothing the caller sends
+// is spliced into the query text. Only named arguments are accepted: the
+// driver's positional binding formats values into the SQL on the client.
+func bindParams(args []any) (goch.Parameters, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	params := make(goch.Parameters, len(args))
+	for _, a := range args {
+		named, ok := a.(sql.NamedArg)
+		if !ok {
+			return nil, fmt.Errorf("query parameters must be named and referenced as {name:Type}, got %T", a)
+		}
+		if !paramNameRe.MatchString(named.Name) {
+			return nil, fmt.Errorf("invalid query parameter name %q", named.Name)
+		}
+		v, err := formatParam(named.Value, false)
+		if err != nil {
+			return nil, fmt.Errorf("query parameter %s: %w", named.Name, err)
+		}
+		params[named.Name] = v
+	}
+	return params, nil
+}
+
+// formatParam renders v in the text format ClickHouse reads parameter
+// values in: escaped at the top level, quoted inside arrays.
+func formatParam(v any, quoted bool) (string, error) {
+	switch x := v.(type) {
+	case nil:
+		if quoted {
+			return "NULL", nil
+		}
+		return `\N`, nil
+	case string:
+		if quoted {
+			return "'" + quotedEscaper.Replace(x) + "'", nil
+		}
+		return escapedEscaper.Replace(x), nil
+	case bool:
+		return strconv.FormatBool(x), nil
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(x), 'f', -1, 32), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(x), nil
+	case time.Time:
+		layout := "2006-01-02 15:04:05"
+		if x.Nanosecond() != 0 {
+			layout += ".999999999"
+		}
+		s := x.UTC().Format(layout)
+		if quoted {
+			return "'" + s + "'", nil
+		}
+		return s, nil
+	case []any:
+		elems := make([]string, len(x))
+		for i, e := range x {
+			s, err := formatParam(e, true)
+			if err != nil {
+				return "", err
+			}
+			elems[i] = s
+		}
+		return "[" + strings.Join(elems, ",") + "]", nil
+	case []string:
+		elems := make([]any, len(x))
+		for i, e := range x {
+			elems[i] = e
+		}
+		return formatParam(elems, quoted)
+	default:
+		return "", fmt.Errorf("unsupported value of type %T", v)
+	}
+}
+
+var (
+	escapedEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`)
+	quotedEscaper  = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+)
@@ -4,11 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"data-voyager/sdk"
+	"data-voyager/sdk/pluginsdk"
 
 	goch "github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
@@ -21,16 +24,45 @@ func init() {
 // Type is the DataSourceType identifier for this extension.
 const Type sdk.DataSourceType = "clickhouse"
 
+// Defaults for the dial options a config leaves unset. The read timeout
+// matches the driver's own default.
+const (
+	defaultDialTimeout = 30 * time.Second
+	defaultReadTimeout = 5 * time.Minute
+)
+
 // Plugin implements sdk.DatasourcePlugin for ClickHouse.
 type Plugin struct{}
 
 func (p *Plugin) GetType() sdk.DataSourceType { return Type }
 func (p *Plugin) GetName() string             { return "ClickHouse" }
 
+// Capabilities implements sdk.CapabilityProvider.
+func (p *Plugin) Capabilities() sdk.Capabilities {
+	return sdk.Capabilities{
+		Exec:        true,
+		Explain:     true,
+		Schemas:     true,
+		Writes:      true,
+		NamedParams: true,
+		Ingest:      true,
+	}
+}
+
+// Version implements sdk.VersionProvider.
+func (p *Plugin) Version() sdk.VersionInfo {
+	return sdk.VersionInfo{
+		Version:          "0.1.0",
+		MinServerVersion: "0.1.0",
+		MaxServerVersion: "1.0.0",
+		DriverVersion:    pluginsdk.ModuleVersion("github.com/ClickHouse/clickhouse-go/v2"),
+	}
+}
+
 func (p *Plugin) ParseConfig(data json.RawMessage) (sdk.ConnectionConfig, error) {
-	cfg := &Config{}
-	if err := json.Unmarshal(data, cfg); err != nil {
-		return nil, fmt.Errorf("invalid clickhouse config: %w", err)
+	cfg, err := pluginsdk.DecodeConfig[Config](data, "clickhouse")
+	if err != nil {
+		return nil, err
 	}
 	return cfg, nil
 }
@@ -52,6 +84,10 @@ func (p *Plugin) Connect(ctx context.Context, config sdk.ConnectionConfig) (sdk.
 		return nil, fmt.Errorf("invalid clickhouse config: %w", err)
 	}
 
+	compression, err := cfg.compression()
+	if err != nil {
+		return nil, fmt.Errorf("invalid clickhouse config: %w", err)
+	}
 	options := &goch.Options{
 		Addr: []string{fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)},
 		Auth: goch.Auth{
@@ -59,16 +95,27 @@ func (p *Plugin) Connect(ctx context.Context, config sdk.ConnectionConfig) (sdk.
 			Username: cfg.Username,
 			Password: cfg.Password,
 		},
-		DialTimeout:      30 * time.Second,
+		DialTimeout:      cfg.Connect(defaultDialTimeout),
+		ReadTimeout:      cfg.Read(defaultReadTimeout),
 		MaxOpenConns:     10,
 		MaxIdleConns:     5,
 		ConnMaxLifetime:  time.Hour,
 		ConnOpenStrategy: goch.ConnOpenInOrder,
+		Compression:      compression,
 	}
 
 	if cfg.Secure {
 		options.Protocol = goch.Native
 	}
+	if cfg.WriteTimeout > 0 || cfg.KeepAlive != 0 {
+		// The driver applies the read timeout itself; only the write
+		// timeout needs wrapping.
+		dialer := cfg.Dialer(options.DialTimeout)
+		dialer.ReadTimeout = 0
+		options.DialContext = func(ctx context.Context, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "tcp", addr)
+		}
+	}
 
 	conn, err := goch.Open(options)
 	if err != nil {
@@ -77,45 +124,22 @@ func (p *Plugin) Connect(ctx context.Context, config sdk.ConnectionConfig) (sdk.
 
 	if err := conn.Ping(ctx); err != nil {
 		_ = conn.Close()
-		return nil, fmt.Errorf("failed to ping ClickHouse: %w", err)
+		return nil, classify("", "", fmt.Errorf("failed to ping ClickHouse: %w", err))
 	}
 
 	return &Connection{conn: conn, config: cfg}, nil
 }
 
 func (p *Plugin) TestConnection(ctx context.Context, config sdk.ConnectionConfig) (*sdk.ConnectionTestResult, error) {
-	start := time.Now()
-
-	conn, err := p.Connect(ctx, config)
-	if err != nil {
-		return &sdk.ConnectionTestResult{
-			IsConnected: false,
-			Message:     err.Error(),
-			TestedAt:    time.Now(),
-		}, nil
-	}
-	defer func() { _ = conn.Close() }()
-
-	if err := conn.Ping(ctx); err != nil {
-		return &sdk.ConnectionTestResult{
-			IsConnected: false,
-			Message:     fmt.Sprintf("ping failed: %v", err),
-			TestedAt:    time.Now(),
-		}, nil
-	}
-
-	return &sdk.ConnectionTestResult{
-		IsConnected: true,
-		Message:     "Connection successful",
-		Latency:     time.Since(start).Milliseconds(),
-		TestedAt:    time.Now(),
-	}, nil
+	return pluginsdk.TestConnection(ctx, p, config)
 }
 
 // Connection is an active ClickHouse connection.
 type Connection struct {
 	conn   driver.Conn
 	config *Config
+
+	metrics pluginsdk.QueryMetrics
 }
 
 // splitStatements splits a SQL string on `;` delimiters, respecting
@@ -168,35 +192,47 @@ func splitStatements(query string) []string {
 	return parts
 }
 
-func (c *Connection) Query(ctx context.Context, query string, _ ...any) (*sdk.QueryResult, error) {
+func (c *Connection) Query(ctx context.Context, query string, args ...any) (*sdk.QueryResult, error) {
+	defer c.metrics.Track()()
 	start := time.Now()
+	params, err := bindParams(args)
+	if err != nil {
+		return nil, err
+	}
 	stmts := splitStatements(query)
 	if len(stmts) == 0 {
-		return &sdk.QueryResult{Frames: []*sdk.DataFrame{}}, nil
+		return pluginsdk.EmptyResult(), nil
 	}
 
 	// Execute all statements; keep the last result that has fields (SELECT-like).
 	// DDL/DML statements (CREATE, INSERT, …) return no fields and are skipped.
-	last := &sdk.QueryResult{Frames: []*sdk.DataFrame{}}
+	last := pluginsdk.EmptyResult()
+	var bytesRead int64
 	for _, stmt := range stmts {
-		result, err := c.execOne(ctx, stmt)
+		result, err := c.execOne(ctx, stmt, params)
 		if err != nil {
-			return nil, err
+			return nil, classify(query, stmt, err)
 		}
+		bytesRead += result.Stats.BytesRead
 		if len(result.Frames) > 0 && len(result.Frames[0].Fields) > 0 {
 			last = result
 		}
 	}
 
-	// Accumulate total execution time in Stats.
+	// Accumulate total execution time and bytes read in Stats.
 	last.Stats.ExecutionTime = time.Since(start)
+	last.Stats.BytesRead = bytesRead
 	return last, nil
 }
 
 // queryRaw executes a single statement and returns column metadata + raw rows.
 // Used internally by execOne (for building DataFrames) and schema helpers.
-func (c *Connection) queryRaw(ctx context.Context, query string, params ...any) ([]sdk.ColumnInfo, [][]any, error) {
-	rows, err := c.conn.Query(ctx, query, params...)
+// params are bound server-side to the query's {name:Type} placeholders.
+func (c *Connection) queryRaw(ctx context.Context, query string, params goch.Parameters) ([]sdk.ColumnInfo, [][]any, error) {
+	if len(params) > 0 {
+		ctx = goch.Context(ctx, goch.WithParameters(params))
+	}
+	rows, err := c.conn.Query(ctx, query)
 	if err != nil {
 		return nil, nil, fmt.Errorf("query execution failed: %w", err)
 	}
@@ -209,6 +245,7 @@ func (c *Connection) queryRaw(ctx context.Context, query string, params ...any)
 	}
 
 	var resultRows [][]any
+	budget := pluginsdk.NewRowBudget(ctx)
 	for rows.Next() {
 		valuePtrs := make([]any, len(columnTypes))
 		for i, ct := range columnTypes {
@@ -221,6 +258,9 @@ func (c *Connection) queryRaw(ctx context.Context, query string, params ...any)
 		for i, ptr := range valuePtrs {
 			values[i] = reflect.ValueOf(ptr).Elem().Interface()
 		}
+		if err := budget.Add(values); err != nil {
+			return nil, nil, err
+		}
 		resultRows = append(resultRows, values)
 	}
 	if err := rows.Err(); err != nil {
@@ -229,38 +269,29 @@ func (c *Connection) queryRaw(ctx context.Context, query string, params ...any)
 	return columns, resultRows, nil
 }
 
-func (c *Connection) execOne(ctx context.Context, query string) (*sdk.QueryResult, error) {
+func (c *Connection) execOne(ctx context.Context, query string, params goch.Parameters) (*sdk.QueryResult, error) {
 	start := time.Now()
-	columns, resultRows, err := c.queryRaw(ctx, query)
+	// The server reports how many blocks it sent once the result is done,
+	// and what it read as it goes; the driver may deliver either from its
+	// reader goroutine.
+	var blocks, bytesRead atomic.Int64
+	opts := []goch.QueryOption{goch.WithProfileInfo(func(p *goch.ProfileInfo) {
+		blocks.Add(int64(p.Blocks))
+	}), goch.WithProgress(func(p *goch.Progress) {
+		bytesRead.Add(int64(p.Bytes))
+	})}
+	if c.config.FetchSize > 0 {
+		opts = append(opts, goch.WithSettings(goch.Settings{"max_block_size": c.config.FetchSize}))
+	}
+	columns, resultRows, err := c.queryRaw(goch.Context(ctx, opts...), query, params)
 	if err != nil {
 		return nil, err
 	}
-
-	// Transpose row-oriented data into column-oriented Fields.
-	fields := make([]sdk.Field, len(columns))
-	for i, col := range columns {
-		values := make([]any, len(resultRows))
-		for j, row := range resultRows {
-			values[j] = row[i]
-		}
-		fields[i] = sdk.Field{
-			Name:   col.Name,
-			Kind:   sdk.InferFieldKind(col.Type),
-			Type:   col.Type,
-			Values: values,
-		}
-	}
-
-	return &sdk.QueryResult{
-		Frames: []*sdk.DataFrame{{
-			FrameType: sdk.FrameTypeTable,
-			Fields:    fields,
-		}},
-		Stats: sdk.QueryStats{
-			ExecutionTime: time.Since(start),
-			RowsReturned:  int64(len(resultRows)),
-		},
-	}, nil
+	result := pluginsdk.TableResult(columns, resultRows, time.Since(start))
+	result.Stats.FetchSize = c.config.FetchSize
+	result.Stats.Batches = blocks.Load()
+	result.Stats.BytesRead = bytesRead.Load()
+	return result, nil
 }
 
 func (c *Connection) GetSchema(ctx context.Context) (*sdk.SchemaInfo, error) {
@@ -275,10 +306,10 @@ func (c *Connection) GetTables(ctx context.Context, database string) ([]sdk.Tabl
 	query := `
 		SELECT name, engine as type, total_rows, total_bytes
 		FROM system.tables
-		WHERE database = ?
+		WHERE database = {database:String}
 		ORDER BY name
 	`
-	_, rows, err := c.queryRaw(ctx, query, database)
+	_, rows, err := c.queryRaw(ctx, query, goch.Parameters{"database": database})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tables: %w", err)
 	}
@@ -304,7 +335,7 @@ func (c *Connection) GetTables(ctx context.Context, database string) ([]sdk.Tabl
 }
 
 func (c *Connection) getDatabases(ctx context.Context) ([]sdk.DatabaseInfo, error) {
-	_, rows, err := c.queryRaw(ctx, "SELECT name FROM system.databases ORDER BY name")
+	_, rows, err := c.queryRaw(ctx, "SELECT name FROM system.databases ORDER BY name", nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get databases: %w", err)
 	}
@@ -329,8 +360,10 @@ func (c *Connection) Close() error {
 func (c *Connection) Ping(ctx context.Context) error { return c.conn.Ping(ctx) }
 
 func (c *Connection) GetMetrics() sdk.ConnectionMetrics {
-	return sdk.ConnectionMetrics{
+	metrics := sdk.ConnectionMetrics{
 		OpenConnections: 1,
 		LastActivity:    time.Now(),
 	}
+	c.metrics.Fill(&metrics)
+	return metrics
 }
@@ -2,12 +2,20 @@ package clickhouse
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"data-voyager/sdk"
+	"data-voyager/sdk/plugintest"
 
+	goch "github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
@@ -48,6 +56,18 @@ func TestClickHousePlugin(t *testing.T) {
 		Password: "password",
 	}
 
+	t.Run("Contract", func(t *testing.T) {
+		bad := *config
+		bad.Host, bad.Port = "127.0.0.1", 1
+		plugintest.Run(t, plugintest.Suite{
+			Plugin:    plugin,
+			Config:    config,
+			BadConfig: &bad,
+			Database:  "testdb",
+			SlowQuery: "SELECT sleep(3)",
+		})
+	})
+
 	t.Run("TestConnection", func(t *testing.T) {
 		result, err := plugin.TestConnection(ctx, config)
 		require.NoError(t, err)
@@ -56,6 +76,58 @@ func TestClickHousePlugin(t *testing.T) {
 		assert.Greater(t, result.Latency, int64(0))
 	})
 
+	t.Run("FetchSize", func(t *testing.T) {
+		batched := *config
+		batched.FetchSize = 10
+		conn, err := plugin.Connect(ctx, &batched)
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+
+		result, err := conn.Query(ctx, "SELECT number FROM numbers(100)")
+		require.NoError(t, err)
+		assert.Equal(t, int64(100), result.Stats.RowsReturned)
+		assert.Equal(t, 10, result.Stats.FetchSize)
+		assert.GreaterOrEqual(t, result.Stats.Batches, int64(10))
+	})
+
+	t.Run("ScanEstimate", func(t *testing.T) {
+		conn, err := plugin.Connect(ctx, config)
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+
+		_, err = conn.Query(ctx, `CREATE TABLE scanned (id UInt64, s String) ENGINE = MergeTree ORDER BY id`)
+		require.NoError(t, err)
+		defer func() { _, _ = conn.Query(ctx, "DROP TABLE scanned") }()
+		_, err = conn.Query(ctx, "INSERT INTO scanned SELECT number, toString(number) FROM numbers(10000)")
+		require.NoError(t, err)
+
+		est, err := conn.(sdk.ScanEstimator).EstimateScan(ctx, "SELECT count() FROM scanned WHERE s != ''")
+		require.NoError(t, err)
+		assert.Equal(t, int64(10000), est.Rows)
+		assert.Greater(t, est.Bytes, int64(0))
+
+		result, err := conn.Query(ctx, "SELECT count() FROM scanned WHERE s != ''")
+		require.NoError(t, err)
+		assert.Greater(t, result.Stats.BytesRead, int64(0))
+	})
+
+	t.Run("NamedParams", func(t *testing.T) {
+		conn, err := plugin.Connect(ctx, config)
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+
+		result, err := conn.Query(ctx, "SELECT {s:String} AS s, {n:UInt8} + 1 AS n, length({xs:Array(String)}) AS l",
+			sql.Named("s", "it's\\ok"), sql.Named("n", float64(41)), sql.Named("xs", []any{"a", "b'c"}))
+		require.NoError(t, err)
+		fields := result.Frames[0].Fields
+		assert.Equal(t, "it's\\ok", fields[0].Values[0])
+		assert.EqualValues(t, 42, fields[1].Values[0])
+		assert.EqualValues(t, 2, fields[2].Values[0])
+
+		_, err = conn.Query(ctx, "SELECT {n:UInt8}", sql.Named("n", "not a number"))
+		assert.Error(t, err)
+	})
+
 	t.Run("Query", func(t *testing.T) {
 		conn, err := plugin.Connect(ctx, config)
 		require.NoError(t, err)
@@ -89,6 +161,29 @@ func TestClickHousePlugin(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("Ingest", func(t *testing.T) {
+		conn, err := plugin.Connect(ctx, config)
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+		_, err = conn.Query(ctx, `CREATE TABLE IF NOT EXISTS ingest_events (id UInt32, at DateTime, name String) ENGINE = MergeTree ORDER BY id`)
+		require.NoError(t, err)
+
+		ing, ok := conn.(sdk.Ingester)
+		require.True(t, ok)
+		res, err := ing.Ingest(ctx, "ingest_events", []json.RawMessage{
+			json.RawMessage(`{"id": 1, "at": "2024-01-01 00:00:00", "name": "a"}`),
+			json.RawMessage(`{"id": "not a number", "at": "2024-01-01 00:00:00", "name": "b"}`),
+			json.RawMessage(`{"id": 3, "at": "2024-01-01 00:00:00", "name": "c"}`),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, res.Accepted)
+		require.Len(t, res.Errors, 1)
+		assert.Equal(t, 1, res.Errors[0].Index)
+
+		_, err = ing.Ingest(ctx, "testdb.missing", []json.RawMessage{json.RawMessage(`{"id": 1}`)})
+		assert.Error(t, err)
+	})
+
 	t.Run("GetSchema", func(t *testing.T) {
 		conn, err := plugin.Connect(ctx, config)
 		require.NoError(t, err)
@@ -133,6 +228,25 @@ func TestClickHousePlugin(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("SystemHealth", func(t *testing.T) {
+		conn, err := plugin.Connect(ctx, config)
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+
+		health, err := conn.(sdk.SystemHealthReporter).SystemHealth(ctx)
+		require.NoError(t, err)
+
+		metrics := map[string]float64{}
+		for _, m := range health.Metrics {
+			metrics[m.Name] = m.Value
+		}
+		assert.Greater(t, metrics[sdk.MetricUptimeSeconds], 0.0)
+		assert.Contains(t, metrics, sdk.MetricActiveQueries)
+		for _, s := range health.Sessions {
+			assert.NotContains(t, s.Query, "system.processes", "snapshot query should be excluded")
+		}
+	})
+
 	t.Run("InvalidConnection", func(t *testing.T) {
 		invalidConfig := &Config{Host: "invalid-host", Port: 9999, Database: "testdb"}
 		result, err := plugin.TestConnection(ctx, invalidConfig)
@@ -167,6 +281,114 @@ func TestClickHouseConfig(t *testing.T) {
 		_ = config.Validate()
 		assert.Contains(t, config.GetConnectionString(), "tls://localhost:9440")
 	})
+
+	t.Run("InvalidDialOptions", func(t *testing.T) {
+		config := &Config{Host: "localhost"}
+		config.KeepAlive = -2
+		assert.ErrorContains(t, config.Validate(), "keepalive")
+	})
+
+	t.Run("Compression", func(t *testing.T) {
+		c, err := (&Config{Host: "localhost"}).compression()
+		require.NoError(t, err)
+		assert.Equal(t, goch.CompressionLZ4, c.Method, "lz4 by default")
+
+		c, err = (&Config{Host: "localhost", Compression: "ZSTD"}).compression()
+		require.NoError(t, err)
+		assert.Equal(t, goch.CompressionZSTD, c.Method)
+
+		assert.ErrorContains(t, (&Config{Host: "localhost", Compression: "gzip"}).Validate(), "compression must be one of")
+		assert.ErrorContains(t, (&Config{Host: "localhost", CompressionLevel: -1}).Validate(), "compression_level")
+	})
+
+	t.Run("Cloud", func(t *testing.T) {
+		cloud := &CloudService{OrganizationID: "org", ServiceID: "svc", KeyID: "id", KeySecret: "secret"}
+		assert.NoError(t, (&Config{Host: "localhost", Cloud: cloud}).Validate())
+		assert.ErrorContains(t, (&Config{Host: "localhost", Cloud: &CloudService{OrganizationID: "org"}}).Validate(), "service_id")
+		cloud.APIURL = "ftp://example.com"
+		assert.ErrorContains(t, (&Config{Host: "localhost", Cloud: cloud}).Validate(), "api_url")
+	})
+}
+
+func TestCloudWarehouse(t *testing.T) {
+	state := "idle"
+	var started []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "id" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/organizations/org/services/svc":
+			_, _ = fmt.Fprintf(w, `{"status":200,"result":{"id":"svc","state":%q}}`, state)
+		case r.Method == http.MethodPatch && r.URL.Path == "/v1/organizations/org/services/svc/state":
+			raw, _ := io.ReadAll(r.Body)
+			started = append(started, string(raw))
+			state = "awaking"
+			_, _ = fmt.Fprint(w, `{"status":200,"result":{}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer api.Close()
+
+	p := &Plugin{}
+	ctx := context.Background()
+	cfg := &Config{Host: "localhost", Cloud: &CloudService{
+		OrganizationID: "org", ServiceID: "svc", KeyID: "id", KeySecret: "secret", APIURL: api.URL,
+	}}
+
+	st, err := p.WarehouseStatus(ctx, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, &sdk.WarehouseStatus{State: sdk.WarehouseSuspended, Detail: "idle"}, st)
+
+	require.NoError(t, p.ResumeWarehouse(ctx, cfg))
+	assert.Equal(t, []string{`{"command":"start"}`}, started)
+	st, err = p.WarehouseStatus(ctx, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.WarehouseResuming, st.State)
+
+	state = "running"
+	st, err = p.WarehouseStatus(ctx, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.WarehouseRunning, st.State)
+
+	cfg.Cloud.KeySecret = "wrong"
+	_, err = p.WarehouseStatus(ctx, cfg)
+	assert.ErrorContains(t, err, "401")
+
+	st, err = p.WarehouseStatus(ctx, &Config{Host: "localhost"})
+	assert.NoError(t, err)
+	assert.Nil(t, st, "self-managed servers do not suspend")
+}
+
+func TestBindParams(t *testing.T) {
+	params, err := bindParams([]any{
+		sql.Named("s", "a\tb\n'c'"),
+		sql.Named("n", float64(42)),
+		sql.Named("f", 1.5),
+		sql.Named("b", true),
+		sql.Named("null", nil),
+		sql.Named("at", time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)),
+		sql.Named("xs", []any{"x", "it's", float64(1), nil}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, goch.Parameters{
+		"s":    `a\tb\n'c'`,
+		"n":    "42",
+		"f":    "1.5",
+		"b":    "true",
+		"null": `\N`,
+		"at":   "2024-05-01 10:00:00",
+		"xs":   `['x','it\'s',1,NULL]`,
+	}, params)
+
+	_, err = bindParams([]any{"positional"})
+	assert.ErrorContains(t, err, "must be named")
+	_, err = bindParams([]any{sql.Named("a-b", 1)})
+	assert.ErrorContains(t, err, "invalid query parameter name")
+	_, err = bindParams([]any{sql.Named("m", map[string]any{})})
+	assert.ErrorContains(t, err, "unsupported value")
 }
 
 func BenchmarkClickHouseQuery(b *testing.B) {
@@ -211,3 +433,60 @@ func BenchmarkClickHouseQuery(b *testing.B) {
 		require.NoError(b, err)
 	}
 }
+
+func TestQuoteIdent(t *testing.T) {
+	assert.Equal(t, "`events`", quoteIdent("events"))
+	assert.Equal(t, "`a``b`", quoteIdent("a`b"))
+	assert.Equal(t, "`a\\\\`` FROM x`", quoteIdent("a\\` FROM x"))
+}
+
+func TestIngestBisect(t *testing.T) {
+	records := make([]json.RawMessage, 7)
+	for i := range records {
+		records[i] = json.RawMessage(fmt.Sprintf(`{"id": %d}`, i))
+	}
+	bad := map[string]bool{`{"id": 2}`: true, `{"id": 5}`: true}
+	inserted := 0
+	insert := func(batch []json.RawMessage) error {
+		for _, r := range batch {
+			if bad[string(r)] {
+				return errors.New("cannot parse " + string(r))
+			}
+		}
+		inserted += len(batch)
+		return nil
+	}
+	res, err := ingestBisect(records, insert, func(error) bool { return false })
+	require.NoError(t, err)
+	assert.Equal(t, 5, res.Accepted)
+	assert.Equal(t, 5, inserted)
+	assert.Equal(t, []sdk.IngestError{
+		{Index: 2, Error: `cannot parse {"id": 2}`},
+		{Index: 5, Error: `cannot parse {"id": 5}`},
+	}, res.Errors)
+
+	calls := 0
+	_, err = ingestBisect(records, func([]json.RawMessage) error { calls++; return errors.New("no table") },
+		func(error) bool { return true })
+	assert.EqualError(t, err, "no table")
+	assert.Equal(t, 1, calls, "a batch-wide failure is not bisected")
+}
+
+func TestClassify(t *testing.T) {
+	query := "SELECT 1;\nSELECT * FORM t"
+	err := classify(query, "SELECT * FORM t", &goch.Exception{
+		Code: 62, Message: "Syntax error: failed at position 10 ('FORM'): FORM t. Expected one of: ...",
+	})
+	var qe *sdk.QueryError
+	require.ErrorAs(t, err, &qe)
+	assert.Equal(t, sdk.ErrCodeSyntaxError, qe.Code)
+	assert.Equal(t, 2, qe.Line)
+	assert.Equal(t, 10, qe.Column)
+
+	err = classify("", "", &goch.Exception{Code: 81, Message: "Database nope does not exist"})
+	require.ErrorAs(t, err, &qe)
+	assert.Equal(t, sdk.ErrCodeUnknownDatabase, qe.Code)
+
+	plain := &goch.Exception{Code: 241, Message: "memory limit exceeded"}
+	assert.Same(t, error(plain), classify("", "", plain))
+}
@@ -0,0 +1,82 @@
+package cockroachdb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"data-voyager/sdk/pluginsdk"
+)
+
+// Config holds CockroachDB connection parameters.
+type Config struct {
+	Host     string `json:"host" toml:"host"`
+	Port     int    `json:"port" toml:"port"`
+	Database string `json:"database" toml:"database"`
+	Username string `json:"username" toml:"username"`
+	Password string `json:"password" toml:"password"`
+	SSLMode  string `json:"ssl_mode" toml:"ssl_mode"`
+	// Cluster is the routing ID of a multi-tenant cluster such as
+	// CockroachDB Cloud Serverless; empty for dedicated clusters.
+	Cluster string `json:"cluster,omitempty" toml:"cluster"`
+
+	// AsOfSystemTime, when set, runs plain reads as of a past timestamp so
+	// they are served without contending with writes: "follower" reads at
+	// follower_read_timestamp(), a duration such as "10s" reads that far
+	// back.
+	AsOfSystemTime string `json:"as_of_system_time,omitempty" toml:"as_of_system_time"`
+
+	pluginsdk.DialOptions
+}
+
+func (c *Config) Validate() error {
+	if c.Host == "" {
+		return fmt.Errorf("host is required")
+	}
+	if c.Port <= 0 {
+		c.Port = 26257
+	}
+	if c.Database == "" {
+		c.Database = "defaultdb"
+	}
+	if c.SSLMode == "" {
+		c.SSLMode = "require"
+	}
+	if strings.ContainsAny(c.Cluster, " '\\") {
+		return fmt.Errorf("cluster must be a routing ID")
+	}
+	if _, err := c.asOfClause(); err != nil {
+		return err
+	}
+	return c.DialOptions.Validate()
+}
+
+func (c *Config) GetConnectionString() string {
+	s := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		quoteValue(c.Host), c.Port, quoteValue(c.Username), quoteValue(c.Password), quoteValue(c.Database), quoteValue(c.SSLMode))
+	if c.Cluster != "" {
+		s += " options=" + quoteValue("--cluster="+c.Cluster)
+	}
+	return s
+}
+
+// quoteValue quotes a keyword/value connection string value for lib/pq.
+func quoteValue(v string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+}
+
+// asOfClause renders AsOfSystemTime as the expression that follows AS OF
+// SYSTEM TIME, or "" when reads are not historical.
+func (c *Config) asOfClause() (string, error) {
+	switch c.AsOfSystemTime {
+	case "":
+		return "", nil
+	case "follower":
+		return "follower_read_timestamp()", nil
+	}
+	d, err := time.ParseDuration(c.AsOfSystemTime)
+	if err != nil || d.Milliseconds() <= 0 {
+		return "", fmt.Errorf(`as_of_system_time must be "follower" or a positive duration such as "10s"`)
+	}
+	return fmt.Sprintf("'-%dms'", d.Milliseconds()), nil
+}
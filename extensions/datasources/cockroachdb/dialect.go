@@ -0,0 +1,48 @@
+package cockroachdb
+
+import "data-voyager/sdk"
+
+// Dialect implements sdk.DialectProvider.
+func (p *Plugin) Dialect() sdk.Dialect {
+	return sdk.Dialect{
+		IdentifierQuote: `"`,
+		StringQuote:     "'",
+		ParamStyle:      sdk.ParamStyleDollar,
+		ReservedWords:   reservedWords,
+		Functions:       functions,
+	}
+}
+
+var reservedWords = append(append([]string{}, sdk.ANSIReservedWords...),
+	"ANALYSE", "ANALYZE", "ARRAY", "ASYMMETRIC", "BOTH", "COLLATE",
+	"CONCURRENTLY", "CURRENT_ROLE", "CURRENT_USER", "DEFERRABLE", "DO",
+	"EXPLAIN", "FAMILY", "ILIKE", "INDEX", "INITIALLY", "LATERAL", "LEADING",
+	"LOCALTIME", "LOCALTIMESTAMP", "NOTHING", "ONLY", "OVERLAPS", "PLACING",
+	"RETURNING", "SESSION_USER", "SOME", "SYMMETRIC", "TRAILING", "USER",
+	"VARIADIC", "WINDOW",
+)
+
+var functions = []sdk.FunctionDoc{
+	{Name: "count", Signature: "count(expression)", ReturnType: "INT8", Description: "Number of input rows for which expression is not null; count(*) counts all rows."},
+	{Name: "sum", Signature: "sum(expression)", ReturnType: "DECIMAL", Description: "Sum of expression across all non-null input values."},
+	{Name: "avg", Signature: "avg(expression)", ReturnType: "DECIMAL", Description: "Average of all non-null input values."},
+	{Name: "min", Signature: "min(expression)", Description: "Minimum of all non-null input values."},
+	{Name: "max", Signature: "max(expression)", Description: "Maximum of all non-null input values."},
+	{Name: "string_agg", Signature: "string_agg(value STRING, delimiter STRING)", ReturnType: "STRING", Description: "Concatenates non-null input values into a string, separated by delimiter."},
+	{Name: "array_agg", Signature: "array_agg(expression)", ReturnType: "array", Description: "Collects all input values, including nulls, into an array."},
+	{Name: "coalesce", Signature: "coalesce(value [, ...])", Description: "Returns the first of its arguments that is not null."},
+	{Name: "now", Signature: "now()", ReturnType: "TIMESTAMPTZ", Description: "Current date and time at the start of the current transaction."},
+	{Name: "date_trunc", Signature: "date_trunc(field STRING, source TIMESTAMP)", ReturnType: "TIMESTAMP", Description: "Truncates a timestamp to the given precision, e.g. 'hour' or 'day'."},
+	{Name: "extract", Signature: "extract(field FROM source)", ReturnType: "FLOAT8", Description: "Retrieves a subfield such as year or epoch from a date/time value."},
+	{Name: "lower", Signature: "lower(STRING)", ReturnType: "STRING", Description: "Converts the string to lower case."},
+	{Name: "upper", Signature: "upper(STRING)", ReturnType: "STRING", Description: "Converts the string to upper case."},
+	{Name: "length", Signature: "length(STRING)", ReturnType: "INT8", Description: "Number of characters in the string."},
+	{Name: "gen_random_uuid", Signature: "gen_random_uuid()", ReturnType: "UUID", Description: "Generates a random version 4 UUID, the usual primary key default."},
+	{Name: "unique_rowid", Signature: "unique_rowid()", ReturnType: "INT8", Description: "Unique, roughly ordered ID from the timestamp and node ID; the default for implicit rowid columns."},
+	{Name: "follower_read_timestamp", Signature: "follower_read_timestamp()", ReturnType: "TIMESTAMPTZ", Description: "A timestamp old enough to be served by the nearest replica, for AS OF SYSTEM TIME."},
+	{Name: "cluster_logical_timestamp", Signature: "cluster_logical_timestamp()", ReturnType: "DECIMAL", Description: "Logical timestamp of the current transaction in the cluster's HLC."},
+	{Name: "crdb_internal.node_id", Signature: "crdb_internal.node_id()", ReturnType: "INT8", Description: "ID of the gateway node serving the session."},
+	{Name: "jsonb_extract_path_text", Signature: "jsonb_extract_path_text(from_json JSONB, VARIADIC path_elems STRING[])", ReturnType: "STRING", Description: "Extracts the JSON sub-object at the given path as text."},
+	{Name: "row_number", Signature: "row_number() OVER (...)", ReturnType: "INT8", Description: "Number of the current row within its partition, counting from 1."},
+	{Name: "generate_series", Signature: "generate_series(start, stop [, step])", ReturnType: "setof", Description: "Generates a series of values from start to stop with the given step."},
+}
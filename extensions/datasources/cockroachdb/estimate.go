@@ -0,0 +1,81 @@
+package cockroachdb
+
+import (
+	"context"
+	"fmt"
+
+	"data-voyager/sdk"
+
+	"github.com/lib/pq"
+)
+
+var _ sdk.CardinalityEstimator = (*Connection)(nil)
+
+// CountRows answers from crdb_internal.table_row_statistics unless exact is
+// requested or the table has no statistics yet, in which case it scans.
+func (c *Connection) CountRows(ctx context.Context, schemaName, table string, exact bool) (*sdk.RowCount, error) {
+	if schemaName == "" {
+		schemaName = "public"
+	}
+	if !exact {
+		query := `
+			SELECT s.estimated_row_count
+			FROM crdb_internal.tables t
+			LEFT JOIN crdb_internal.table_row_statistics s ON s.table_id = t.table_id
+			WHERE t.database_name = current_database() AND t.schema_name = $1 AND t.name = $2
+			  AND t.state = 'PUBLIC'
+		`
+		_, rows, err := c.queryRaw(ctx, query, schemaName, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate row count: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil, fmt.Errorf("table %s.%s not found", schemaName, table)
+		}
+		if n, ok := rows[0][0].(int64); ok && n >= 0 {
+			return &sdk.RowCount{Count: n, Approximate: true, Method: "crdb_internal.table_row_statistics"}, nil
+		}
+	}
+
+	query := fmt.Sprintf("SELECT count(*) FROM %s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(table))
+	_, rows, err := c.queryRaw(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count rows: %w", err)
+	}
+	n, _ := rows[0][0].(int64)
+	return &sdk.RowCount{Count: n, Method: "count"}, nil
+}
+
+// CountDistinct reads the distinct count of the newest single-column
+// statistic on column, scanning when there is none.
+func (c *Connection) CountDistinct(ctx context.Context, schemaName, table, column string) (*sdk.RowCount, error) {
+	if schemaName == "" {
+		schemaName = "public"
+	}
+	qualified := pq.QuoteIdentifier(schemaName) + "." + pq.QuoteIdentifier(table)
+	query := fmt.Sprintf(`
+		SELECT distinct_count
+		FROM [SHOW STATISTICS FOR TABLE %s]
+		WHERE column_names = ARRAY[$1::STRING]
+		ORDER BY created DESC
+		LIMIT 1
+	`, qualified)
+	_, rows, err := c.queryRaw(ctx, query, column)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate distinct count: %w", err)
+	}
+	if len(rows) > 0 {
+		if n, ok := rows[0][0].(int64); ok {
+			return &sdk.RowCount{Count: n, Approximate: true, Method: "table_statistics.distinct_count"}, nil
+		}
+	}
+
+	// No statistics for the column yet: fall back to an exact scan.
+	query = fmt.Sprintf("SELECT count(DISTINCT %s) FROM %s", pq.QuoteIdentifier(column), qualified)
+	_, rows, err = c.queryRaw(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count distinct values: %w", err)
+	}
+	n, _ := rows[0][0].(int64)
+	return &sdk.RowCount{Count: n, Method: "count_distinct"}, nil
+}
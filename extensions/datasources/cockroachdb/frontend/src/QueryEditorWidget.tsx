@@ -0,0 +1 @@
+export { PostgreSQLQueryEditor as CockroachDBQueryEditor } from '@data-voyager/shared-ui';
@@ -0,0 +1,18 @@
+import type { DatasourcePlugin } from '@data-voyager/sdk';
+import { datasourceRegistry } from '@data-voyager/sdk';
+import { CockroachDBConfigForm } from './ConfigForm';
+import { CockroachDBQueryEditor } from './QueryEditorWidget';
+import { cockroachSchemaProvider } from './schemaProvider';
+
+const plugin: DatasourcePlugin = {
+  id: 'cockroachdb',
+  name: 'CockroachDB',
+  description: 'Connect to CockroachDB clusters',
+  configComponent: CockroachDBConfigForm,
+  queryEditorComponent: CockroachDBQueryEditor,
+  schemaProvider: cockroachSchemaProvider,
+};
+
+datasourceRegistry.register(plugin);
+
+export { plugin };
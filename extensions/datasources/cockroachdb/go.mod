@@ -0,0 +1,9 @@
+module data-voyager/extensions/datasources/cockroachdb
+
+go 1.26.1
+
+require (
+	github.com/lib/pq v1.10.9
+	github.com/stretchr/testify v1.11.1
+	github.com/testcontainers/testcontainers-go v0.39.0
+)
@@ -0,0 +1,131 @@
+package cockroachdb
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"data-voyager/sdk"
+)
+
+var _ sdk.SystemHealthReporter = (*Connection)(nil)
+
+// maxHealthSessions caps how many sessions a health snapshot returns.
+const maxHealthSessions = 500
+
+// SystemHealth reads sessions across the cluster from
+// crdb_internal.cluster_sessions and node membership from
+// crdb_internal.gossip_nodes. The session running the snapshot itself is
+// excluded.
+func (c *Connection) SystemHealth(ctx context.Context) (*sdk.SystemHealth, error) {
+	query := fmt.Sprintf(`
+		SELECT session_id,
+		       user_name,
+		       coalesce(status, ''),
+		       active_queries,
+		       (extract(epoch FROM now() - coalesce(active_query_start, session_start)) * 1000)::INT8
+		FROM crdb_internal.cluster_sessions
+		WHERE session_id <> current_setting('session_id') AND coalesce(status, '') <> 'CLOSED'
+		ORDER BY active_queries <> '' DESC, active_query_start NULLS LAST
+		LIMIT %d
+	`, maxHealthSessions)
+	_, rows, err := c.queryRaw(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read crdb_internal.cluster_sessions: %w", err)
+	}
+	health := &sdk.SystemHealth{Sessions: make([]sdk.SystemSession, 0, len(rows))}
+	for _, r := range rows {
+		ms, _ := r[4].(int64)
+		health.Sessions = append(health.Sessions, sdk.SystemSession{
+			ID:       str(r[0]),
+			User:     str(r[1]),
+			Database: c.config.Database,
+			State:    str(r[2]),
+			Query:    str(r[3]),
+			Elapsed:  time.Duration(ms) * time.Millisecond,
+		})
+	}
+
+	query = `
+		SELECT node_id::STRING, address, server_version, locality, is_live, started_at, ranges
+		FROM crdb_internal.gossip_nodes
+		ORDER BY node_id
+	`
+	_, rows, err = c.queryRaw(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read crdb_internal.gossip_nodes: %w", err)
+	}
+	var live, ranges float64
+	for _, r := range rows {
+		node := sdk.SystemNode{ID: str(r[0]), Address: str(r[1]), Version: str(r[2]), Locality: str(r[3])}
+		node.Live, _ = r[4].(bool)
+		node.StartedAt, _ = r[5].(time.Time)
+		if node.Live {
+			live++
+		}
+		n, _ := r[6].(int64)
+		ranges += float64(n)
+		health.Nodes = append(health.Nodes, node)
+	}
+
+	query = `
+		SELECT
+			(SELECT count(*) FROM crdb_internal.cluster_sessions WHERE coalesce(status, '') <> 'CLOSED')::FLOAT8,
+			(SELECT count(*) FROM crdb_internal.cluster_queries)::FLOAT8,
+			(SELECT extract(epoch FROM now() - started_at) FROM crdb_internal.gossip_nodes
+			 WHERE node_id = crdb_internal.node_id())::FLOAT8
+	`
+	_, rows, err = c.queryRaw(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cluster counters: %w", err)
+	}
+	values := []float64{0, 0, 0}
+	if len(rows) > 0 {
+		for i := range values {
+			values[i], _ = rows[0][i].(float64)
+		}
+	}
+	health.Metrics = []sdk.SystemMetric{
+		{Name: sdk.MetricConnections, Value: values[0]},
+		{Name: sdk.MetricActiveQueries, Value: values[1]},
+		{Name: sdk.MetricUptimeSeconds, Value: values[2]},
+		{Name: "crdb.nodes", Value: float64(len(health.Nodes))},
+		{Name: "crdb.live_nodes", Value: live},
+		{Name: "crdb.ranges", Value: ranges},
+	}
+	return health, nil
+}
+
+// str returns v as a string, or "" for NULL and non-string values.
+func str(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+var _ sdk.SessionTerminator = (*Connection)(nil)
+
+// TerminateSession cancels the session id, the 128-bit hex ID reported by
+// SystemHealth, with CANCEL SESSION, or only its running queries with CANCEL
+// QUERIES. Both work from any node of the cluster.
+func (c *Connection) TerminateSession(ctx context.Context, id string, queryOnly bool) error {
+	if b, err := hex.DecodeString(id); err != nil || len(b) != 16 {
+		return fmt.Errorf("%w: invalid session id %q", sdk.ErrSessionNotFound, id)
+	}
+	_, rows, err := c.queryRaw(ctx, `SELECT count(*) FROM crdb_internal.cluster_sessions
+		WHERE session_id = $1 AND coalesce(status, '') <> 'CLOSED'`, id)
+	if err != nil {
+		return classify("", err)
+	}
+	if n, _ := rows[0][0].(int64); n == 0 {
+		return sdk.ErrSessionNotFound
+	}
+	stmt := "CANCEL SESSION IF EXISTS $1"
+	if queryOnly {
+		stmt = "CANCEL QUERIES IF EXISTS (SELECT query_id FROM crdb_internal.cluster_queries WHERE session_id = $1)"
+	}
+	if _, err := c.db.ExecContext(ctx, stmt, id); err != nil {
+		return classify("", err)
+	}
+	return nil
+}
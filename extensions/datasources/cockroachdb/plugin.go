@@ -0,0 +1,376 @@
+package cockroachdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"data-voyager/sdk"
+	"data-voyager/sdk/pluginsdk"
+
+	"github.com/lib/pq"
+)
+
+func init() {
+	sdk.RegisterDatasource(&Plugin{})
+}
+
+// Type is the DataSourceType identifier for this extension.
+const Type sdk.DataSourceType = "cockroachdb"
+
+// defaultDialTimeout applies when the config sets no connect_timeout.
+const defaultDialTimeout = 30 * time.Second
+
+// Plugin implements sdk.DatasourcePlugin for CockroachDB. It speaks the
+// PostgreSQL wire protocol through lib/pq but reads metadata from
+// crdb_internal, which knows about hidden columns, row statistics and the
+// nodes of the cluster.
+type Plugin struct{}
+
+func (p *Plugin) GetType() sdk.DataSourceType { return Type }
+func (p *Plugin) GetName() string             { return "CockroachDB" }
+
+// Capabilities implements sdk.CapabilityProvider.
+func (p *Plugin) Capabilities() sdk.Capabilities {
+	return sdk.Capabilities{
+		Exec:         true,
+		Explain:      true,
+		Schemas:      true,
+		Writes:       true,
+		Transactions: true,
+	}
+}
+
+// Version implements sdk.VersionProvider.
+func (p *Plugin) Version() sdk.VersionInfo {
+	return sdk.VersionInfo{
+		Version:          "0.1.0",
+		MinServerVersion: "0.1.0",
+		MaxServerVersion: "1.0.0",
+		DriverVersion:    pluginsdk.ModuleVersion("github.com/lib/pq"),
+	}
+}
+
+func (p *Plugin) ParseConfig(data json.RawMessage) (sdk.ConnectionConfig, error) {
+	cfg, err := pluginsdk.DecodeConfig[Config](data, "cockroachdb")
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (p *Plugin) ValidateConfig(config any) error {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return fmt.Errorf("config must be *cockroachdb.Config")
+	}
+	return cfg.Validate()
+}
+
+func (p *Plugin) Connect(ctx context.Context, config sdk.ConnectionConfig) (sdk.Connection, error) {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("invalid config type for CockroachDB")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid cockroachdb config: %w", err)
+	}
+	asOf, _ := cfg.asOfClause()
+
+	connector, err := pq.NewConnector(cfg.GetConnectionString())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CockroachDB connection: %w", err)
+	}
+	connector.Dialer(cfg.Dialer(defaultDialTimeout))
+	db := sql.OpenDB(connector)
+
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(5)
+	db.SetConnMaxLifetime(time.Hour)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, classify("", fmt.Errorf("failed to ping CockroachDB: %w", err))
+	}
+	return &Connection{db: db, config: cfg, asOf: asOf}, nil
+}
+
+func (p *Plugin) TestConnection(ctx context.Context, config sdk.ConnectionConfig) (*sdk.ConnectionTestResult, error) {
+	return pluginsdk.TestConnection(ctx, p, config)
+}
+
+// Connection is an active CockroachDB connection.
+type Connection struct {
+	db     *sql.DB
+	config *Config
+	// asOf is the AS OF SYSTEM TIME expression plain reads run at; empty
+	// reads current data.
+	asOf string
+
+	metrics pluginsdk.QueryMetrics
+}
+
+func (c *Connection) Query(ctx context.Context, query string, params ...any) (*sdk.QueryResult, error) {
+	defer c.metrics.Track()()
+	start := time.Now()
+	var columns []sdk.ColumnInfo
+	var resultRows [][]any
+	var err error
+	switch {
+	case sdk.ReadOnly(ctx):
+		columns, resultRows, err = c.queryReadOnly(ctx, query, params...)
+	case c.asOf != "" && historical(query):
+		columns, resultRows, err = c.queryAsOf(ctx, query, params...)
+	default:
+		columns, resultRows, err = c.queryRaw(ctx, query, params...)
+	}
+	if err != nil {
+		return nil, classify(query, err)
+	}
+	return pluginsdk.TableResult(columns, resultRows, time.Since(start)), nil
+}
+
+// queryRaw executes a query and returns column metadata + raw rows.
+func (c *Connection) queryRaw(ctx context.Context, query string, params ...any) ([]sdk.ColumnInfo, [][]any, error) {
+	rows, err := c.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query execution failed: %w", err)
+	}
+	return scanRows(rows, pluginsdk.NewRowBudget(ctx))
+}
+
+// queryAsOf runs query in a transaction fixed at the configured system
+// time. Such a transaction is read-only, so only reads are routed here.
+func (c *Connection) queryAsOf(ctx context.Context, query string, params ...any) ([]sdk.ColumnInfo, [][]any, error) {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query execution failed: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.ExecContext(ctx, "SET TRANSACTION AS OF SYSTEM TIME "+c.asOf); err != nil {
+		return nil, nil, fmt.Errorf("query execution failed: %w", err)
+	}
+	rows, err := tx.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query execution failed: %w", err)
+	}
+	columns, resultRows, err := scanRows(rows, pluginsdk.NewRowBudget(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("query execution failed: %w", err)
+	}
+	return columns, resultRows, nil
+}
+
+// queryReadOnly runs query in a read-only transaction, at the configured
+// system time when it may, and rolls it back so nothing it did outlives it.
+func (c *Connection) queryReadOnly(ctx context.Context, query string, params ...any) ([]sdk.ColumnInfo, [][]any, error) {
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("query execution failed: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if c.asOf != "" && historical(query) {
+		if _, err := tx.ExecContext(ctx, "SET TRANSACTION AS OF SYSTEM TIME "+c.asOf); err != nil {
+			return nil, nil, fmt.Errorf("query execution failed: %w", err)
+		}
+	}
+	rows, err := tx.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query execution failed: %w", err)
+	}
+	return scanRows(rows, pluginsdk.NewRowBudget(ctx))
+}
+
+// historical reports whether query is a single plain read that may run as
+// of a past system time. Statements naming their own system time, writes
+// and anything in doubt read current data.
+func historical(query string) bool {
+	q := strings.TrimRight(strings.TrimSpace(query), "; \t\r\n")
+	if strings.Contains(q, ";") {
+		return false
+	}
+	words := strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	if len(words) == 0 {
+		return false
+	}
+	switch words[0] {
+	case "select", "with", "values", "table":
+	default:
+		return false
+	}
+	for i, w := range words {
+		switch w {
+		case "insert", "update", "delete", "upsert", "for":
+			// FOR covers SELECT ... FOR UPDATE, which needs a read-write
+			// transaction.
+			return false
+		case "system":
+			if i > 1 && words[i-2] == "as" && words[i-1] == "of" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// scanRows reads and closes rows, charging each row to budget.
+func scanRows(rows *sql.Rows, budget *pluginsdk.RowBudget) ([]sdk.ColumnInfo, [][]any, error) {
+	defer func() { _ = rows.Close() }()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get column types: %w", err)
+	}
+
+	columns := make([]sdk.ColumnInfo, len(columnTypes))
+	for i, ct := range columnTypes {
+		nullable, _ := ct.Nullable()
+		columns[i] = sdk.ColumnInfo{Name: ct.Name(), Type: ct.DatabaseTypeName(), Nullable: nullable}
+	}
+
+	var resultRows [][]any
+	for rows.Next() {
+		values := make([]any, len(columnTypes))
+		valuePtrs := make([]any, len(columnTypes))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		for i, v := range values {
+			values[i] = pluginsdk.NormalizeValue(v)
+		}
+		if err := budget.Add(values); err != nil {
+			return nil, nil, err
+		}
+		resultRows = append(resultRows, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return columns, resultRows, nil
+}
+
+func (c *Connection) GetSchema(ctx context.Context) (*sdk.SchemaInfo, error) {
+	query := `
+		SELECT schema_name
+		FROM information_schema.schemata
+		WHERE catalog_name = current_database()
+		  AND schema_name NOT IN ('crdb_internal', 'information_schema', 'pg_catalog', 'pg_extension')
+		ORDER BY schema_name
+	`
+	_, rows, err := c.queryRaw(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schemas: %w", err)
+	}
+
+	databases := make([]sdk.DatabaseInfo, 0, len(rows))
+	for _, row := range rows {
+		if schemaName, ok := row[0].(string); ok {
+			tables, _ := c.GetTables(ctx, schemaName)
+			databases = append(databases, sdk.DatabaseInfo{Name: schemaName, Tables: tables})
+		}
+	}
+	return &sdk.SchemaInfo{Databases: databases}, nil
+}
+
+// GetTables lists the tables of a schema in the current database with the
+// row estimates of their latest statistics.
+func (c *Connection) GetTables(ctx context.Context, schemaName string) ([]sdk.TableInfo, error) {
+	if schemaName == "" {
+		schemaName = "public"
+	}
+
+	query := `
+		SELECT t.table_name, t.table_type, s.estimated_row_count
+		FROM information_schema.tables t
+		LEFT JOIN crdb_internal.tables c
+			ON c.database_name = t.table_catalog AND c.schema_name = t.table_schema
+			AND c.name = t.table_name AND c.state = 'PUBLIC'
+		LEFT JOIN crdb_internal.table_row_statistics s ON s.table_id = c.table_id
+		WHERE t.table_catalog = current_database() AND t.table_schema = $1
+		ORDER BY t.table_name
+	`
+	_, rows, err := c.queryRaw(ctx, query, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tables: %w", err)
+	}
+
+	tables := make([]sdk.TableInfo, 0, len(rows))
+	for _, row := range rows {
+		name, _ := row[0].(string)
+		tableType, _ := row[1].(string)
+
+		var rowCount *int64
+		if rc, ok := row[2].(int64); ok && rc > 0 {
+			rowCount = &rc
+		}
+
+		columns, _ := c.getTableColumns(ctx, schemaName, name)
+		tables = append(tables, sdk.TableInfo{
+			Name:     name,
+			Type:     tableType,
+			Columns:  columns,
+			RowCount: rowCount,
+		})
+	}
+	return tables, nil
+}
+
+// getTableColumns lists the visible columns of a table with their
+// CockroachDB type names. Hidden columns such as the implicit rowid are
+// left out.
+func (c *Connection) getTableColumns(ctx context.Context, schemaName, tableName string) ([]sdk.ColumnInfo, error) {
+	query := `
+		SELECT column_name, crdb_sql_type, is_nullable
+		FROM information_schema.columns
+		WHERE table_catalog = current_database() AND table_schema = $1 AND table_name = $2
+		  AND is_hidden = 'NO'
+		ORDER BY ordinal_position
+	`
+	_, rows, err := c.queryRaw(ctx, query, schemaName, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns: %w", err)
+	}
+
+	columns := make([]sdk.ColumnInfo, 0, len(rows))
+	for _, row := range rows {
+		name, _ := row[0].(string)
+		dataType, _ := row[1].(string)
+		isNullable, _ := row[2].(string)
+		columns = append(columns, sdk.ColumnInfo{Name: name, Type: dataType, Nullable: isNullable == "YES"})
+	}
+	return columns, nil
+}
+
+func (c *Connection) Close() error {
+	if c.db != nil {
+		return c.db.Close()
+	}
+	return nil
+}
+
+func (c *Connection) Ping(ctx context.Context) error {
+	return c.db.PingContext(ctx)
+}
+
+func (c *Connection) GetMetrics() sdk.ConnectionMetrics {
+	stats := c.db.Stats()
+	metrics := sdk.ConnectionMetrics{
+		OpenConnections: stats.OpenConnections,
+		IdleConnections: stats.Idle,
+		LastActivity:    time.Now(),
+	}
+	c.metrics.Fill(&metrics)
+	return metrics
+}
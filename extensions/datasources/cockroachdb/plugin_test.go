@@ -0,0 +1,166 @@
+package cockroachdb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"data-voyager/sdk"
+	"data-voyager/sdk/plugintest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestCockroachDBPlugin(t *testing.T) {
+	ctx := context.Background()
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "cockroachdb/cockroach:v24.1.0",
+			Cmd:          []string{"start-single-node", "--insecure"},
+			ExposedPorts: []string{"26257/tcp"},
+			WaitingFor:   wait.ForLog("CockroachDB node starting").WithStartupTimeout(2 * time.Minute),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	defer func() {
+		if err := testcontainers.TerminateContainer(container); err != nil {
+			t.Logf("failed to terminate container: %s", err)
+		}
+	}()
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+	port, err := container.MappedPort(ctx, "26257")
+	require.NoError(t, err)
+
+	plugin := &Plugin{}
+	assert.Equal(t, Type, plugin.GetType())
+	assert.Equal(t, "CockroachDB", plugin.GetName())
+
+	config := &Config{Host: host, Port: port.Int(), Username: "root", SSLMode: "disable"}
+
+	t.Run("Contract", func(t *testing.T) {
+		bad := *config
+		bad.Host, bad.Port = "127.0.0.1", 1
+		plugintest.Run(t, plugintest.Suite{
+			Plugin:     plugin,
+			Config:     config,
+			BadConfig:  &bad,
+			Database:   "public",
+			ParamQuery: "SELECT $1::INT8",
+			SlowQuery:  "SELECT pg_sleep(10)",
+		})
+	})
+
+	conn, err := plugin.Connect(ctx, config)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	_, err = conn.Query(ctx, `CREATE TABLE IF NOT EXISTS orders (customer STRING NOT NULL, total DECIMAL(10,2))`)
+	require.NoError(t, err)
+	_, err = conn.Query(ctx, `INSERT INTO orders VALUES ('ann', 12.50), ('bob', 3), ('ann', NULL)`)
+	require.NoError(t, err)
+
+	t.Run("GetTables", func(t *testing.T) {
+		tables, err := conn.GetTables(ctx, "public")
+		require.NoError(t, err)
+		require.Len(t, tables, 1)
+		assert.Equal(t, "orders", tables[0].Name)
+		var names []string
+		for _, c := range tables[0].Columns {
+			names = append(names, c.Name)
+		}
+		assert.Equal(t, []string{"customer", "total"}, names, "the hidden rowid is left out")
+		assert.Equal(t, "DECIMAL(10,2)", tables[0].Columns[1].Type)
+
+		schema, err := conn.GetSchema(ctx)
+		require.NoError(t, err)
+		for _, db := range schema.Databases {
+			assert.NotEqual(t, "crdb_internal", db.Name)
+		}
+	})
+
+	t.Run("CountRows", func(t *testing.T) {
+		est := conn.(sdk.CardinalityEstimator)
+		n, err := est.CountRows(ctx, "public", "orders", true)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), n.Count)
+		d, err := est.CountDistinct(ctx, "public", "orders", "customer")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), d.Count)
+	})
+
+	t.Run("AsOfSystemTime", func(t *testing.T) {
+		stale := *config
+		stale.AsOfSystemTime = "1h"
+		sc, err := plugin.Connect(ctx, &stale)
+		require.NoError(t, err)
+		defer func() { _ = sc.Close() }()
+		// An hour ago the table did not exist yet.
+		_, err = sc.Query(ctx, "SELECT count(*) FROM orders")
+		assert.Error(t, err)
+		_, err = sc.Query(ctx, "INSERT INTO orders VALUES ('cy', 1)")
+		assert.NoError(t, err, "writes are not historical")
+	})
+
+	t.Run("SystemHealth", func(t *testing.T) {
+		health, err := conn.(sdk.SystemHealthReporter).SystemHealth(ctx)
+		require.NoError(t, err)
+		require.Len(t, health.Nodes, 1)
+		assert.True(t, health.Nodes[0].Live)
+		assert.NotEmpty(t, health.Nodes[0].Version)
+		metrics := map[string]float64{}
+		for _, m := range health.Metrics {
+			metrics[m.Name] = m.Value
+		}
+		assert.Equal(t, float64(1), metrics["crdb.live_nodes"])
+		assert.Greater(t, metrics[sdk.MetricUptimeSeconds], float64(0))
+
+		err = conn.(sdk.SessionTerminator).TerminateSession(ctx, "00000000000000000000000000000001", true)
+		assert.ErrorIs(t, err, sdk.ErrSessionNotFound)
+	})
+}
+
+func TestConfig(t *testing.T) {
+	cfg := &Config{Host: "db.example.com", Username: "o'neil", Password: `p\w d`, Cluster: "blue-cat-123"}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, 26257, cfg.Port)
+	assert.Equal(t, "defaultdb", cfg.Database)
+	assert.Equal(t, `host='db.example.com' port=26257 user='o\'neil' password='p\\w d' dbname='defaultdb' sslmode='require' options='--cluster=blue-cat-123'`,
+		cfg.GetConnectionString())
+
+	for value, clause := range map[string]string{"": "", "follower": "follower_read_timestamp()", "1m30s": "'-90000ms'"} {
+		cfg.AsOfSystemTime = value
+		got, err := cfg.asOfClause()
+		require.NoError(t, err)
+		assert.Equal(t, clause, got)
+	}
+	for _, bad := range []*Config{
+		{},
+		{Host: "h", AsOfSystemTime: "-10s"},
+		{Host: "h", AsOfSystemTime: "yesterday"},
+		{Host: "h", Cluster: "x' options='-c"},
+	} {
+		assert.Error(t, bad.Validate())
+	}
+}
+
+func TestHistorical(t *testing.T) {
+	for query, want := range map[string]bool{
+		"SELECT * FROM t":                               true,
+		"  with x AS (SELECT 1) SELECT * FROM x;":       true,
+		"SELECT * FROM t AS OF SYSTEM TIME '-5s'":       false,
+		"SELECT * FROM t FOR UPDATE":                    false,
+		"WITH x AS (DELETE FROM t RETURNING *) TABLE x": false,
+		"INSERT INTO t VALUES (1)":                      false,
+		"SELECT 1; SELECT 2":                            false,
+		"SHOW TABLES":                                   false,
+		"":                                              false,
+	} {
+		assert.Equal(t, want, historical(query), query)
+	}
+}
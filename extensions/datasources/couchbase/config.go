@@ -0,0 +1,98 @@
+package couchbase
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strings"
+
+	"data-voyager/sdk/pluginsdk"
+)
+
+const (
+	defaultQueryPort = 8093
+	defaultTLSPort   = 18093
+)
+
+// Config holds Couchbase connection parameters.
+type Config struct {
+	// Host is a node running the query service.
+	Host     string `json:"host" toml:"host"`
+	Port     int    `json:"port" toml:"port"`
+	Username string `json:"username" toml:"username"`
+	Password string `json:"password" toml:"password"`
+
+	// TLS connects to the query service's TLS port, 18093 unless Port says
+	// otherwise, as Capella and clusters with enforced encryption require.
+	TLS bool `json:"tls" toml:"tls"`
+	// CACert is the PEM certificate of the CA that signed the cluster's
+	// certificates when that is not a public CA, e.g. the cluster's own
+	// root certificate. Empty trusts the system roots.
+	CACert string `json:"ca_cert,omitempty" toml:"ca_cert"`
+	// TLSSkipVerify accepts any certificate the cluster presents. It is
+	// meant for test clusters only.
+	TLSSkipVerify bool `json:"tls_skip_verify,omitempty" toml:"tls_skip_verify"`
+
+	// ScanConsistency is not_bounded (the server's default) or
+	// request_plus, which waits for indexes to catch up with writes made
+	// before the query.
+	ScanConsistency string `json:"scan_consistency,omitempty" toml:"scan_consistency"`
+
+	pluginsdk.DialOptions
+}
+
+func (c *Config) Validate() error {
+	if c.Host == "" {
+		return fmt.Errorf("host is required")
+	}
+	if strings.Contains(c.Host, "://") {
+		return fmt.Errorf("host must be a host name, not a URL; set tls for https")
+	}
+	if c.Port <= 0 {
+		c.Port = defaultQueryPort
+		if c.TLS {
+			c.Port = defaultTLSPort
+		}
+	}
+	if !c.TLS && (c.CACert != "" || c.TLSSkipVerify) {
+		return fmt.Errorf("ca_cert and tls_skip_verify need tls")
+	}
+	if _, err := c.tlsConfig(); err != nil {
+		return err
+	}
+	switch c.ScanConsistency {
+	case "", "not_bounded", "request_plus":
+	default:
+		return fmt.Errorf("scan_consistency must be not_bounded or request_plus, got %q", c.ScanConsistency)
+	}
+	return c.DialOptions.Validate()
+}
+
+func (c *Config) GetConnectionString() string {
+	return c.baseURL()
+}
+
+// baseURL is the query service's address.
+func (c *Config) baseURL() string {
+	scheme := "http"
+	if c.TLS {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s:%d", scheme, c.Host, c.Port)
+}
+
+// tlsConfig returns the client TLS settings, or nil without TLS.
+func (c *Config) tlsConfig() (*tls.Config, error) {
+	if !c.TLS {
+		return nil, nil
+	}
+	tc := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: c.TLSSkipVerify}
+	if c.CACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(c.CACert)) {
+			return nil, fmt.Errorf("ca_cert must be a PEM certificate")
+		}
+		tc.RootCAs = pool
+	}
+	return tc, nil
+}
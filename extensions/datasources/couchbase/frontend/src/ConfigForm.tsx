@@ -0,0 +1,131 @@
+import { Input } from '@data-voyager/shared-ui/components/ui/input'
+import { Label } from '@data-voyager/shared-ui/components/ui/label'
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from '@data-voyager/shared-ui/components/ui/select'
+import { Switch } from '@data-voyager/shared-ui/components/ui/switch'
+import { Textarea } from '@data-voyager/shared-ui/components/ui/textarea'
+import type { DatasourceConfigProps } from '@data-voyager/sdk'
+
+interface CouchbaseConfig {
+  host: string
+  port: number
+  username: string
+  password: string
+  tls: boolean
+  ca_cert: string
+  tls_skip_verify: boolean
+  scan_consistency: string
+}
+
+const SCAN_CONSISTENCIES = ['not_bounded', 'request_plus']
+
+export function CouchbaseConfigForm({ config, onChange }: DatasourceConfigProps) {
+  const cfg = config as Partial<CouchbaseConfig>
+
+  const set = (key: keyof CouchbaseConfig, value: string | number | boolean | undefined) =>
+    onChange({ ...cfg, [key]: value })
+
+  // CA 인증서와 검증 생략은 TLS에서만 허용되므로 TLS를 끄면 함께 지운다
+  const toggleTLS = (on: boolean) =>
+    onChange({
+      ...cfg,
+      tls: on,
+      ca_cert: on ? cfg.ca_cert : undefined,
+      tls_skip_verify: on ? cfg.tls_skip_verify : undefined,
+    })
+
+  return (
+    <div className="flex flex-col gap-4 max-w-lg">
+      <div className="grid grid-cols-[1fr_120px] gap-3">
+        <div className="space-y-2">
+          <Label>Query service host</Label>
+          <Input
+            placeholder="cb.example.com"
+            value={cfg.host ?? ''}
+            onChange={(e) => set('host', e.target.value)}
+          />
+        </div>
+        <div className="space-y-2">
+          <Label>Port</Label>
+          <Input
+            placeholder={cfg.tls ? '18093' : '8093'}
+            value={cfg.port || ''}
+            onChange={(e) => set('port', Number(e.target.value) || undefined)}
+          />
+        </div>
+      </div>
+
+      <div className="grid grid-cols-2 gap-3">
+        <div className="space-y-2">
+          <Label>Username</Label>
+          <Input
+            value={cfg.username ?? ''}
+            onChange={(e) => set('username', e.target.value)}
+          />
+        </div>
+        <div className="space-y-2">
+          <Label>Password</Label>
+          <Input
+            type="password"
+            placeholder="••••••••"
+            value={cfg.password ?? ''}
+            onChange={(e) => set('password', e.target.value)}
+          />
+        </div>
+      </div>
+
+      <div className="flex items-center gap-3">
+        <Switch
+          checked={cfg.tls ?? false}
+          onCheckedChange={toggleTLS}
+          id="tls"
+        />
+        <Label htmlFor="tls" className="cursor-pointer">
+          TLS
+        </Label>
+      </div>
+
+      {cfg.tls && (
+        <>
+          <div className="space-y-2">
+            <Label>Cluster CA certificate</Label>
+            <Textarea
+              className="font-mono text-xs"
+              rows={4}
+              placeholder={'-----BEGIN CERTIFICATE-----\n(optional, PEM)'}
+              value={cfg.ca_cert ?? ''}
+              onChange={(e) => set('ca_cert', e.target.value || undefined)}
+            />
+          </div>
+
+          <div className="flex items-center gap-3">
+            <Switch
+              checked={cfg.tls_skip_verify ?? false}
+              onCheckedChange={(v) => set('tls_skip_verify', v)}
+              id="tls_skip_verify"
+            />
+            <Label htmlFor="tls_skip_verify" className="cursor-pointer">
+              Skip certificate verification (test clusters only)
+            </Label>
+          </div>
+        </>
+      )}
+
+      <div className="space-y-2">
+        <Label>Scan consistency</Label>
+        <Select
+          value={cfg.scan_consistency || 'not_bounded'}
+          onValueChange={(v) => v !== null && set('scan_consistency', v)}
+        >
+          <SelectTrigger>
+            <SelectValue />
+          </SelectTrigger>
+          <SelectContent>
+            {SCAN_CONSISTENCIES.map((m) => (
+              <SelectItem key={m} value={m}>{m}</SelectItem>
+            ))}
+          </SelectContent>
+        </Select>
+      </div>
+    </div>
+  )
+}
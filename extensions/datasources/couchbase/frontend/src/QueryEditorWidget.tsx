@@ -0,0 +1 @@
+export { GenericSQLQueryEditor as CouchbaseQueryEditor } from '@data-voyager/shared-ui';
@@ -0,0 +1,18 @@
+import type { DatasourcePlugin } from '@data-voyager/sdk';
+import { datasourceRegistry } from '@data-voyager/sdk';
+import { CouchbaseConfigForm } from './ConfigForm';
+import { CouchbaseQueryEditor } from './QueryEditorWidget';
+import { couchbaseSchemaProvider } from './schemaProvider';
+
+const plugin: DatasourcePlugin = {
+  id: 'couchbase',
+  name: 'Couchbase',
+  description: 'Query Couchbase buckets, scopes and collections with SQL++ (N1QL)',
+  configComponent: CouchbaseConfigForm,
+  queryEditorComponent: CouchbaseQueryEditor,
+  schemaProvider: couchbaseSchemaProvider,
+};
+
+datasourceRegistry.register(plugin);
+
+export { plugin };
@@ -0,0 +1,90 @@
+import type { SchemaProvider, SchemaNode, PluginContext } from '@data-voyager/sdk';
+import type { DatabaseInfo, SchemaInfo, TableInfo } from './types';
+
+export const couchbaseSchemaProvider: SchemaProvider = {
+  async getRootNodes(_ctx: PluginContext, connectionId: string): Promise<SchemaNode[]> {
+    const schema = await fetchSchema(connectionId);
+    // Couchbase: database 이름은 "bucket.scope" — bucket 아래에 scope를 묶어 보여준다
+    const buckets = [...new Set(schema.databases.map((db: DatabaseInfo) => splitDatabase(db.name)[0]))];
+    return buckets.map((bucket) => ({
+      id: `bucket/${bucket}`,
+      label: bucket,
+      type: 'database' as const,
+      hasChildren: true,
+    }));
+  },
+
+  async getChildNodes(_ctx: PluginContext, connectionId: string, node: SchemaNode): Promise<SchemaNode[]> {
+    const schema = await fetchSchema(connectionId);
+    const parts = node.id.split('/');
+
+    if (parts.length === 2) {
+      // id: bucket/<bucket>
+      const bucket = parts[1];
+      return schema.databases
+        .filter((db: DatabaseInfo) => splitDatabase(db.name)[0] === bucket)
+        .map((db: DatabaseInfo) => ({
+          id: `${node.id}/scope/${splitDatabase(db.name)[1]}`,
+          label: splitDatabase(db.name)[1],
+          type: 'schema' as const,
+          hasChildren: db.tables.length > 0,
+        }));
+    }
+
+    if (parts.length === 4) {
+      // id: bucket/<bucket>/scope/<scope>
+      const db = schema.databases.find((d: DatabaseInfo) => d.name === `${parts[1]}.${parts[3]}`);
+      return (db?.tables ?? []).map((t: TableInfo) => ({
+        id: `${node.id}/collection/${t.name}`,
+        label: t.name,
+        type: 'table' as const,
+        hasChildren: (t.columns?.length ?? 0) > 0,
+        meta: { comment: t.type },
+      }));
+    }
+
+    if (parts.length === 6) {
+      // id: bucket/<bucket>/scope/<scope>/collection/<collection>
+      const db = schema.databases.find((d: DatabaseInfo) => d.name === `${parts[1]}.${parts[3]}`);
+      const table = db?.tables.find((t: TableInfo) => t.name === parts[5]);
+      return (table?.columns ?? []).map((col) => ({
+        id: `${node.id}/field/${col.name}`,
+        label: col.name,
+        type: 'column' as const,
+        hasChildren: false,
+        meta: { dataType: col.type, nullable: col.nullable },
+      }));
+    }
+
+    return [];
+  },
+
+  getInsertText(node: SchemaNode): string {
+    // 필드는 이름만, 나머지는 keyspace 경로: `travel-sample`.`inventory`.`airline`
+    if (node.type === 'column') return `\`${node.label}\``;
+    const parts = node.id.split('/');
+    const names = parts.filter((_, i) => i % 2 === 1);
+    return names.map((n) => `\`${n}\``).join('.');
+  },
+};
+
+// bucket 이름에는 '.'이 들어갈 수 있지만 scope 이름에는 없으므로 마지막 '.'에서 나눈다
+function splitDatabase(name: string): [string, string] {
+  const i = name.lastIndexOf('.');
+  return [name.slice(0, i), name.slice(i + 1)];
+}
+
+// 스키마 캐시
+const schemaCache = new Map<string, SchemaInfo>();
+
+async function fetchSchema(connectionId: string): Promise<SchemaInfo> {
+  if (schemaCache.has(connectionId)) {
+    return schemaCache.get(connectionId)!;
+  }
+  const res = await fetch(`/api/v1/connections/${connectionId}/schema`);
+  if (!res.ok) throw new Error('Failed to fetch schema');
+  const json = await res.json();
+  const schema: SchemaInfo = json.data;
+  schemaCache.set(connectionId, schema);
+  return schema;
+}
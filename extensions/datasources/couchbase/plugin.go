@@ -0,0 +1,135 @@
+package couchbase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"data-voyager/sdk"
+	"data-voyager/sdk/pluginsdk"
+)
+
+func init() {
+	sdk.RegisterDatasource(&Plugin{})
+}
+
+// Type is the DataSourceType identifier for this extension.
+const Type sdk.DataSourceType = "couchbase"
+
+const defaultConnectTimeout = 10 * time.Second
+
+// Plugin implements sdk.DatasourcePlugin for Couchbase through the query
+// service's REST API. Queries are SQL++ (N1QL). Each bucket's scopes are
+// browsed as databases named "bucket.scope", with their collections as
+// tables.
+type Plugin struct{}
+
+func (p *Plugin) GetType() sdk.DataSourceType { return Type }
+func (p *Plugin) GetName() string             { return "Couchbase" }
+
+// Capabilities implements sdk.CapabilityProvider.
+func (p *Plugin) Capabilities() sdk.Capabilities {
+	return sdk.Capabilities{Schemas: true, NamedParams: true}
+}
+
+// Version implements sdk.VersionProvider.
+func (p *Plugin) Version() sdk.VersionInfo {
+	return sdk.VersionInfo{
+		Version:          "0.1.0",
+		MinServerVersion: "0.1.0",
+		MaxServerVersion: "1.0.0",
+	}
+}
+
+func (p *Plugin) ParseConfig(data json.RawMessage) (sdk.ConnectionConfig, error) {
+	cfg, err := pluginsdk.DecodeConfig[Config](data, "couchbase")
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (p *Plugin) ValidateConfig(config any) error {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return fmt.Errorf("config must be *couchbase.Config")
+	}
+	return cfg.Validate()
+}
+
+func (p *Plugin) Connect(ctx context.Context, config sdk.ConnectionConfig) (sdk.Connection, error) {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("invalid config type for Couchbase")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid couchbase config: %w", err)
+	}
+	tlsConfig, err := cfg.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = cfg.Dialer(defaultConnectTimeout).DialContext
+	transport.TLSClientConfig = tlsConfig
+	conn := &Connection{config: cfg, client: &http.Client{Transport: transport}}
+	if err := conn.Ping(ctx); err != nil {
+		conn.client.CloseIdleConnections()
+		return nil, fmt.Errorf("failed to ping Couchbase: %w", err)
+	}
+	return conn, nil
+}
+
+func (p *Plugin) TestConnection(ctx context.Context, config sdk.ConnectionConfig) (*sdk.ConnectionTestResult, error) {
+	return pluginsdk.TestConnection(ctx, p, config)
+}
+
+// Connection talks to one query service node over HTTP. It keeps no state
+// between queries beyond the client's idle connections.
+type Connection struct {
+	config *Config
+	client *http.Client
+
+	metrics pluginsdk.QueryMetrics
+}
+
+// GetSchema lists every scope the credentials can see with its
+// collections and their fields. Fields are sampled from each collection's
+// documents, one INFER statement per collection.
+func (c *Connection) GetSchema(ctx context.Context) (*sdk.SchemaInfo, error) {
+	scopes, err := c.scopes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	collections, err := c.collections(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	schema := &sdk.SchemaInfo{Databases: make([]sdk.DatabaseInfo, 0, len(scopes))}
+	for _, scope := range scopes {
+		tables := collections[scope]
+		c.inferAll(ctx, scope, tables)
+		schema.Databases = append(schema.Databases, sdk.DatabaseInfo{Name: scope, Tables: tables})
+	}
+	return schema, nil
+}
+
+func (c *Connection) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+// Ping runs a trivial query, which checks the credentials as well as that
+// the query service is up; /admin/ping answers without either.
+func (c *Connection) Ping(ctx context.Context) error {
+	_, _, err := c.statement(ctx, "SELECT 1", nil)
+	return err
+}
+
+func (c *Connection) GetMetrics() sdk.ConnectionMetrics {
+	metrics := sdk.ConnectionMetrics{LastActivity: time.Now()}
+	c.metrics.Fill(&metrics)
+	return metrics
+}
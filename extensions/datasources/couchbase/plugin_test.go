@@ -0,0 +1,281 @@
+package couchbase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/sdk"
+	"data-voyager/sdk/pluginsdk"
+)
+
+// queryService is a fake query service answering the statements it knows
+// with canned responses.
+type queryService struct {
+	mu        sync.Mutex
+	requests  []map[string]any
+	cancelled []string
+	results   map[string]string
+	// block holds back the answer to statements it names until the
+	// request is abandoned.
+	block string
+}
+
+func (q *queryService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if user, pass, _ := r.BasicAuth(); user != "admin" || pass != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"requestID":"1","errors":[{"code":10000,"msg":"Authentication Failure"}],"status":"fatal"}`))
+		return
+	}
+	var req map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || r.URL.Path != "/query/service" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	statement, _ := req["statement"].(string)
+	q.mu.Lock()
+	if statement == cancelStatement {
+		q.cancelled = append(q.cancelled, req["$id"].(string))
+		q.mu.Unlock()
+		_, _ = w.Write([]byte(`{"results":[],"status":"success"}`))
+		return
+	}
+	q.requests = append(q.requests, req)
+	q.mu.Unlock()
+	if q.block != "" && strings.Contains(statement, q.block) {
+		<-r.Context().Done()
+		return
+	}
+	for prefix, result := range q.results {
+		if strings.HasPrefix(statement, prefix) {
+			_, _ = w.Write([]byte(`{"requestID":"1","signature":{"*":"*"},"results":` + result +
+				`,"status":"success","metrics":{"resultCount":1}}`))
+			return
+		}
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	_, _ = w.Write([]byte(`{"requestID":"1","errors":[{"code":12003,"msg":"Keyspace not found in CB datastore: default:missing"}],"status":"fatal"}`))
+}
+
+func (q *queryService) last() map[string]any {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.requests[len(q.requests)-1]
+}
+
+func TestCouchbasePlugin(t *testing.T) {
+	q := &queryService{results: map[string]string{
+		"SELECT 1":       `[{"$1":1}]`,
+		"SELECT RAW [s.": `[["travel-sample","_default"],["travel-sample","inventory"],["beer.sample","brewing"]]`,
+		"SELECT RAW [k.": `[["travel-sample","_default","_default"],["travel-sample","inventory","airline"],
+			["travel-sample","inventory","route"],["beer.sample","brewing","beers"]]`,
+		"INFER `travel-sample`.`inventory`.`airline`": `[[
+			{"#docs":90,"Flavor":"type = \"airline\"","properties":{
+				"name":{"#docs":90,"%docs":100,"type":"string"},
+				"id":{"#docs":90,"%docs":100,"type":"number"},
+				"iata":{"#docs":90,"%docs":100,"type":["null","string"]}},"type":"object"},
+			{"#docs":10,"Flavor":"","properties":{
+				"name":{"#docs":10,"%docs":100,"type":"string"},
+				"id":{"#docs":10,"%docs":100,"type":["number","string"]},
+				"callsign":{"#docs":5,"%docs":50,"type":"string"}},"type":"object"}]]`,
+		"SELECT a.name": `[{"name":"40-Mile Air","id":10,"ratio":0.5,"tags":["a"],"geo":{"lat":64.8}},
+			{"name":"Texas Wings","id":10123,"callsign":"TXW"},
+			{"id":-1,"name":null}]`,
+		"SELECT RAW a.id": `[10,10123]`,
+	}}
+	srv := httptest.NewUnstartedServer(q)
+	// The untrusted connect below fails its handshake on purpose.
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	defer srv.Close()
+	host, portText, err := net.SplitHostPort(srv.Listener.Addr().String())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portText)
+	require.NoError(t, err)
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	plugin := &Plugin{}
+	raw, err := json.Marshal(map[string]any{
+		"host": host, "port": port, "username": "admin", "password": "secret",
+		"tls": true, "ca_cert": string(caCert), "scan_consistency": "request_plus",
+	})
+	require.NoError(t, err)
+	cfg, err := plugin.ParseConfig(raw)
+	require.NoError(t, err)
+	require.NoError(t, plugin.ValidateConfig(cfg))
+	assert.Equal(t, "https://"+srv.Listener.Addr().String(), cfg.GetConnectionString())
+
+	ctx := context.Background()
+	result, err := plugin.TestConnection(ctx, cfg)
+	require.NoError(t, err)
+	assert.True(t, result.IsConnected, result.Message)
+
+	conn, err := plugin.Connect(ctx, cfg)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	t.Run("schema", func(t *testing.T) {
+		schema, err := conn.GetSchema(ctx)
+		require.NoError(t, err)
+		require.Len(t, schema.Databases, 3)
+		assert.Equal(t, "travel-sample.inventory", schema.Databases[1].Name)
+		collections := schema.Databases[1].Tables
+		require.Len(t, collections, 2)
+		assert.Equal(t, "airline", collections[0].Name)
+		assert.Equal(t, "collection", collections[0].Type)
+		assert.Len(t, collections[0].Columns, 4)
+		assert.Equal(t, "beer.sample.brewing", schema.Databases[2].Name)
+
+		tables, err := conn.GetTables(ctx, "travel-sample.inventory")
+		require.NoError(t, err)
+		q.mu.Lock()
+		listed := q.requests[len(q.requests)-3]
+		q.mu.Unlock()
+		assert.Equal(t, "travel-sample", listed["$bucket"], "collections are listed for the scope only")
+		assert.Equal(t, "inventory", listed["$scope"])
+		require.Len(t, tables, 2)
+		assert.Equal(t, []sdk.ColumnInfo{
+			{Name: "callsign", Type: "string", Nullable: true},
+			{Name: "iata", Type: "string", Nullable: true},
+			{Name: "id", Type: "number|string"},
+			{Name: "name", Type: "string"},
+		}, tables[0].Columns)
+		// INFER failing on a collection leaves it without fields.
+		assert.Equal(t, "route", tables[1].Name)
+		assert.Empty(t, tables[1].Columns)
+
+		for _, db := range []string{"travel-sample", "travel-sample.nope", "travel-sample."} {
+			_, err = conn.GetTables(ctx, db)
+			var qe *sdk.QueryError
+			require.ErrorAs(t, err, &qe, db)
+			assert.Equal(t, sdk.ErrCodeUnknownDatabase, qe.Code)
+		}
+	})
+
+	t.Run("query", func(t *testing.T) {
+		res, err := conn.Query(ctx, "SELECT a.name, a.id FROM `travel-sample`.inventory.airline AS a WHERE a.id > $1 AND a.iata = $iata",
+			10, sql.Named("iata", "Q5"))
+		require.NoError(t, err)
+		req := q.last()
+		assert.Equal(t, []any{float64(10)}, req["args"])
+		assert.Equal(t, "Q5", req["$iata"])
+		assert.Equal(t, "request_plus", req["scan_consistency"])
+		assert.Contains(t, req["client_context_id"], "data-voyager-")
+
+		require.Len(t, res.Frames, 1)
+		fields := res.Frames[0].Fields
+		require.Len(t, fields, 6)
+		assert.EqualValues(t, 3, res.Stats.RowsReturned)
+		names := make([]string, len(fields))
+		for i, f := range fields {
+			names[i] = f.Name
+		}
+		assert.Equal(t, []string{"name", "id", "ratio", "tags", "geo", "callsign"}, names)
+		assert.Equal(t, []any{"40-Mile Air", "Texas Wings", nil}, fields[0].Values)
+		assert.Equal(t, "number", fields[1].Type)
+		assert.Equal(t, sdk.FieldKindNumber, fields[1].Kind)
+		assert.Equal(t, []any{int64(10), int64(10123), int64(-1)}, fields[1].Values)
+		assert.Equal(t, []any{0.5, nil, nil}, fields[2].Values)
+		assert.Equal(t, []any{"a"}, fields[3].Values[0])
+		assert.Equal(t, map[string]any{"lat": 64.8}, fields[4].Values[0])
+		assert.Equal(t, []any{nil, "TXW", nil}, fields[5].Values)
+
+		res, err = conn.Query(ctx, "SELECT RAW a.id FROM `travel-sample`.inventory.airline AS a")
+		require.NoError(t, err)
+		fields = res.Frames[0].Fields
+		require.Len(t, fields, 1)
+		assert.Equal(t, "value", fields[0].Name)
+		assert.Equal(t, []any{int64(10), int64(10123)}, fields[0].Values)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := conn.Query(ctx, "SELECT * FROM missing")
+		var qe *sdk.QueryError
+		require.ErrorAs(t, err, &qe)
+		assert.Equal(t, sdk.ErrCodeUndefinedObject, qe.Code)
+		assert.ErrorContains(t, err, "Keyspace not found in CB datastore")
+
+		bad := *cfg.(*Config)
+		bad.Password = "wrong"
+		_, err = plugin.Connect(ctx, &bad)
+		require.ErrorAs(t, err, &qe)
+		assert.Equal(t, sdk.ErrCodeAuthFailed, qe.Code)
+
+		// Without the cluster's CA the certificate is not trusted.
+		untrusted := *cfg.(*Config)
+		untrusted.CACert = ""
+		_, err = plugin.Connect(ctx, &untrusted)
+		assert.ErrorContains(t, err, "certificate")
+		untrusted.TLSSkipVerify = true
+		conn, err := plugin.Connect(ctx, &untrusted)
+		require.NoError(t, err)
+		_ = conn.Close()
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		q.block = "SLEEP"
+		defer func() { q.block = "" }()
+		ctx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+		defer cancel()
+		_, err := conn.Query(ctx, "SELECT RAW 1 FROM array_range(0, 1) AS r WHERE SLEEP(60)")
+		require.Error(t, err)
+
+		req := q.last()
+		timeout, err := time.ParseDuration(req["timeout"].(string))
+		require.NoError(t, err)
+		assert.InDelta(t, 200, timeout.Milliseconds(), 50)
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		assert.Equal(t, []string{req["client_context_id"].(string)}, q.cancelled)
+	})
+}
+
+func TestStatusErrors(t *testing.T) {
+	for code, want := range map[int]string{
+		3000:  sdk.ErrCodeSyntaxError,
+		12021: sdk.ErrCodeUndefinedObject,
+		13014: sdk.ErrCodePermissionDenied,
+	} {
+		err := statusError(http.StatusBadRequest, []apiError{{Code: code, Msg: "m"}}, "400 Bad Request")
+		var qe *sdk.QueryError
+		require.ErrorAs(t, err, &qe, code)
+		assert.Equal(t, want, qe.Code, code)
+	}
+	err := statusError(http.StatusInternalServerError, []apiError{{Code: 5000, Msg: "internal"}}, "500")
+	assert.EqualError(t, err, "couchbase: internal (5000)")
+	assert.EqualError(t, statusError(http.StatusBadGateway, nil, "502 Bad Gateway"), "couchbase: 502 Bad Gateway")
+}
+
+func TestConfigValidate(t *testing.T) {
+	for _, cfg := range []Config{
+		{},
+		{Host: "https://cb.example.com"},
+		{Host: "cb", CACert: "-----BEGIN CERTIFICATE-----"},
+		{Host: "cb", TLSSkipVerify: true},
+		{Host: "cb", TLS: true, CACert: "not a certificate"},
+		{Host: "cb", ScanConsistency: "at_plus"},
+		{Host: "cb", DialOptions: pluginsdk.DialOptions{ConnectTimeout: -1}},
+	} {
+		assert.Error(t, cfg.Validate(), cfg.Host)
+	}
+	cfg := Config{Host: "cb"}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, "http://cb:8093", cfg.GetConnectionString())
+	cfg = Config{Host: "cb", TLS: true}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, "https://cb:"+strconv.Itoa(defaultTLSPort), cfg.GetConnectionString())
+}
@@ -0,0 +1,371 @@
+package couchbase
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"data-voyager/sdk"
+	"data-voyager/sdk/pluginsdk"
+)
+
+const (
+	// cancelTimeout bounds the request that cancels an abandoned query.
+	cancelTimeout = 5 * time.Second
+	// valueColumn holds results that are not objects, as SELECT RAW
+	// returns.
+	valueColumn = "value"
+	// cancelStatement ends a running request by the client context ID it
+	// was sent with.
+	cancelStatement = "DELETE FROM system:active_requests WHERE clientContextID = $id"
+)
+
+// Error codes of the query service that map to sdk error codes.
+const (
+	codeAuthFailed       = 10000
+	codeSyntaxFirst      = 3000
+	codeSyntaxLast       = 3999
+	codeKeyspaceNotFound = 12003
+	codeScopeNotFound    = 12021
+	codeNoPermission     = 13014
+)
+
+// apiError is one entry of a response's errors.
+type apiError struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+// Query runs a SQL++ statement. Plain params bind to its positional $1, $2
+// or ? placeholders and sql.NamedArg ones to the $name placeholders of the
+// same name.
+func (c *Connection) Query(ctx context.Context, query string, params ...any) (*sdk.QueryResult, error) {
+	defer c.metrics.Track()()
+	start := time.Now()
+	columns, rows, err := c.statement(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+	return pluginsdk.TableResult(columns, rows, time.Since(start)), nil
+}
+
+// statement runs one statement and reads its results. Each request gets
+// its own client context ID, so a statement given up on when ctx ends is
+// cancelled on the query service rather than left running.
+func (c *Connection) statement(ctx context.Context, statement string, params []any) ([]sdk.ColumnInfo, [][]any, error) {
+	id := "data-voyager-" + rand.Text()
+	req, err := c.request(ctx, id, statement, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		c.cancelAbandoned(ctx, id)
+		return nil, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	columns, rows, err := readResponse(resp, pluginsdk.NewRowBudget(ctx))
+	if err != nil {
+		c.cancelAbandoned(ctx, id)
+		return nil, nil, err
+	}
+	return columns, rows, nil
+}
+
+// request builds the body of a query service request. When ctx has a
+// deadline, the request times out on the server when the caller stops
+// waiting.
+func (c *Connection) request(ctx context.Context, id, statement string, params []any) (map[string]any, error) {
+	req := map[string]any{"statement": statement, "client_context_id": id}
+	if c.config.ScanConsistency != "" {
+		req["scan_consistency"] = c.config.ScanConsistency
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		req["timeout"] = fmt.Sprintf("%dms", max(time.Until(deadline).Milliseconds(), 1))
+	}
+	var args []any
+	for i, p := range params {
+		if named, ok := p.(sql.NamedArg); ok {
+			name := strings.TrimPrefix(named.Name, "$")
+			if name == "" {
+				return nil, fmt.Errorf("parameter %d has no name", i+1)
+			}
+			req["$"+name] = argument(named.Value)
+			continue
+		}
+		args = append(args, argument(p))
+	}
+	if len(args) > 0 {
+		req["args"] = args
+	}
+	return req, nil
+}
+
+// argument is v as it is sent to the query service. Bytes are sent as a
+// string rather than base64; times encode as RFC 3339, which the date
+// functions of SQL++ read.
+func argument(v any) any {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return v
+}
+
+// cancelAbandoned cancels request id once ctx is done. A request that
+// already finished matches nothing, which is not an error.
+func (c *Connection) cancelAbandoned(ctx context.Context, id string) {
+	if ctx.Err() == nil {
+		return
+	}
+	cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cancelTimeout)
+	defer cancel()
+	req := map[string]any{"statement": cancelStatement, "$id": id}
+	if resp, err := c.do(cancelCtx, req); err == nil {
+		_ = resp.Body.Close()
+	}
+}
+
+// do posts a request to the query service. Failed statements are
+// answered with an error status and a body listing the errors, so the
+// response is returned whatever its status for readResponse to report.
+func (c *Connection) do(ctx context.Context, body map[string]any) (*http.Response, error) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.baseURL()+"/query/service", bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.config.Username != "" {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+	return c.client.Do(req)
+}
+
+// readResponse decodes a response as it streams in: results come first,
+// then the errors of a statement that failed part way through and the
+// final status.
+func readResponse(resp *http.Response, budget *pluginsdk.RowBudget) ([]sdk.ColumnInfo, [][]any, error) {
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		// Not a query service response, e.g. a proxy's error page.
+		return nil, nil, statusError(resp.StatusCode, nil, resp.Status)
+	}
+	var (
+		res    results
+		errs   []apiError
+		status string
+	)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, fmt.Errorf("couchbase: response ended early: %w", err)
+		}
+		switch tok {
+		case "results":
+			err = res.read(dec, budget)
+		case "errors":
+			err = dec.Decode(&errs)
+		case "status":
+			err = dec.Decode(&status)
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(errs) > 0 || resp.StatusCode/100 != 2 {
+		return nil, nil, statusError(resp.StatusCode, errs, resp.Status)
+	}
+	if status != "" && status != "success" {
+		return nil, nil, fmt.Errorf("couchbase: statement ended with status %q", status)
+	}
+	return res.columns(), res.rows, nil
+}
+
+// statusError classifies a failed statement by its first error's code,
+// or by the HTTP status when the response lists none.
+func statusError(httpStatus int, errs []apiError, status string) error {
+	if len(errs) == 0 {
+		err := fmt.Errorf("couchbase: %s", status)
+		if httpStatus == http.StatusUnauthorized {
+			return &sdk.QueryError{Code: sdk.ErrCodeAuthFailed, Err: err}
+		}
+		return err
+	}
+	first := errs[0]
+	err := fmt.Errorf("couchbase: %s (%d)", first.Msg, first.Code)
+	switch {
+	case first.Code == codeAuthFailed || httpStatus == http.StatusUnauthorized:
+		return &sdk.QueryError{Code: sdk.ErrCodeAuthFailed, Err: err}
+	case first.Code == codeNoPermission:
+		return &sdk.QueryError{Code: sdk.ErrCodePermissionDenied, Err: err}
+	case first.Code == codeKeyspaceNotFound || first.Code == codeScopeNotFound:
+		return &sdk.QueryError{Code: sdk.ErrCodeUndefinedObject, Err: err}
+	case first.Code >= codeSyntaxFirst && first.Code <= codeSyntaxLast:
+		return &sdk.QueryError{Code: sdk.ErrCodeSyntaxError, Err: err}
+	}
+	return err
+}
+
+// results gathers documents into rows. Documents need not share fields,
+// so the columns are every field seen, in the order first seen, and a row
+// holds nil for the fields its document lacks. Results that are not
+// objects go in a single "value" column.
+type results struct {
+	names []string
+	types []string
+	index map[string]int
+	rows  [][]any
+}
+
+// read decodes the results array.
+func (r *results) read(dec *json.Decoder, budget *pluginsdk.RowBudget) error {
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return fmt.Errorf("couchbase: unexpected results: %v", tok)
+	}
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			// Results stream as the statement produces them, so one
+			// failing part way through leaves the response cut short.
+			return fmt.Errorf("couchbase: results ended early: %w", err)
+		}
+		row, err := r.row(raw)
+		if err != nil {
+			return err
+		}
+		if err := budget.Add(row); err != nil {
+			return err
+		}
+		r.rows = append(r.rows, row)
+	}
+	_, err := dec.Token()
+	return err
+}
+
+// row turns one result into a row, adding columns for fields not seen
+// before. The row is as long as the columns known so far; columns() pads
+// the earlier, shorter ones.
+func (r *results) row(raw json.RawMessage) ([]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if !bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		return r.set(nil, valueColumn, v), nil
+	}
+	// Walk the object's tokens rather than decoding a map, which would
+	// lose the order of the fields.
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	var row []any
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		name, _ := tok.(string)
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		row = r.set(row, name, v)
+	}
+	return row, nil
+}
+
+func (r *results) set(row []any, name string, v any) []any {
+	i, ok := r.index[name]
+	if !ok {
+		if r.index == nil {
+			r.index = map[string]int{}
+		}
+		i = len(r.names)
+		r.index[name] = i
+		r.names = append(r.names, name)
+		r.types = append(r.types, "")
+	}
+	v = convert(v)
+	if r.types[i] == "" {
+		r.types[i] = valueType(v)
+	}
+	for len(row) <= i {
+		row = append(row, nil)
+	}
+	row[i] = v
+	return row
+}
+
+// columns pads every row to the full set of columns and describes them.
+func (r *results) columns() []sdk.ColumnInfo {
+	columns := make([]sdk.ColumnInfo, len(r.names))
+	for i, name := range r.names {
+		columns[i] = sdk.ColumnInfo{Name: name, Type: r.types[i], Nullable: true}
+	}
+	for i, row := range r.rows {
+		for len(row) < len(columns) {
+			row = append(row, nil)
+		}
+		r.rows[i] = row
+	}
+	return columns
+}
+
+// valueType names the JSON type of a decoded value as INFER does, or ""
+// for null, which says nothing of the column's type.
+func valueType(v any) string {
+	switch v.(type) {
+	case int64, float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	}
+	return ""
+}
+
+// convert turns the numbers in a decoded value into int64, or float64
+// when they are not integers.
+func convert(v any) any {
+	switch x := v.(type) {
+	case json.Number:
+		if n, err := x.Int64(); err == nil {
+			return n
+		}
+		if f, err := x.Float64(); err == nil {
+			return f
+		}
+		return x.String()
+	case []any:
+		for i, e := range x {
+			x[i] = convert(e)
+		}
+		return x
+	case map[string]any:
+		for k, e := range x {
+			x[k] = convert(e)
+		}
+		return x
+	}
+	return v
+}
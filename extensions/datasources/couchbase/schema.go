@@ -0,0 +1,198 @@
+package couchbase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"data-voyager/sdk"
+)
+
+const (
+	scopesQuery = "SELECT RAW [s.`bucket`, s.name] FROM system:scopes AS s ORDER BY s.`bucket`, s.name"
+	// collectionsQuery leaves out the rows system:keyspaces has for the
+	// buckets themselves, which have no bucket field.
+	collectionsQuery = "SELECT RAW [k.`bucket`, k.`scope`, k.name] FROM system:keyspaces AS k WHERE k.`bucket` IS NOT MISSING"
+	// inferSampleSize is how many documents INFER reads to describe a
+	// collection's fields.
+	inferSampleSize = 100
+)
+
+// splitDatabase splits a "bucket.scope" database name. Scope names cannot
+// contain dots but bucket names can, so the scope follows the last one.
+func splitDatabase(database string) (bucket, scope string, ok bool) {
+	i := strings.LastIndexByte(database, '.')
+	if i <= 0 || i == len(database)-1 {
+		return "", "", false
+	}
+	return database[:i], database[i+1:], true
+}
+
+// scopes lists the "bucket.scope" names the credentials can see.
+func (c *Connection) scopes(ctx context.Context) ([]string, error) {
+	_, rows, err := c.statement(ctx, scopesQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scopes: %w", err)
+	}
+	scopes := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if pair, _ := row[0].([]any); len(pair) == 2 {
+			bucket, _ := pair[0].(string)
+			scope, _ := pair[1].(string)
+			scopes = append(scopes, bucket+"."+scope)
+		}
+	}
+	return scopes, nil
+}
+
+// collections lists the collections of database, or of every scope when
+// database is empty, keyed by their "bucket.scope" name.
+func (c *Connection) collections(ctx context.Context, database string) (map[string][]sdk.TableInfo, error) {
+	query, params := collectionsQuery, []any(nil)
+	if database != "" {
+		bucket, scope, _ := splitDatabase(database)
+		query += " AND k.`bucket` = $bucket AND k.`scope` = $scope"
+		params = []any{sql.Named("bucket", bucket), sql.Named("scope", scope)}
+	}
+	_, rows, err := c.statement(ctx, query+" ORDER BY k.`bucket`, k.`scope`, k.name", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	collections := map[string][]sdk.TableInfo{}
+	for _, row := range rows {
+		if triple, _ := row[0].([]any); len(triple) == 3 {
+			bucket, _ := triple[0].(string)
+			scope, _ := triple[1].(string)
+			name, _ := triple[2].(string)
+			key := bucket + "." + scope
+			collections[key] = append(collections[key], sdk.TableInfo{Name: name, Type: "collection"})
+		}
+	}
+	return collections, nil
+}
+
+// GetTables lists the collections of a "bucket.scope" database with the
+// fields INFER finds in a sample of their documents.
+func (c *Connection) GetTables(ctx context.Context, database string) ([]sdk.TableInfo, error) {
+	bucket, scope, ok := splitDatabase(database)
+	if !ok {
+		return nil, &sdk.QueryError{Code: sdk.ErrCodeUnknownDatabase,
+			Err: fmt.Errorf("database %q is not a bucket.scope name", database)}
+	}
+	scopes, err := c.scopes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(scopes, database) {
+		return nil, &sdk.QueryError{Code: sdk.ErrCodeUnknownDatabase,
+			Err: fmt.Errorf("scope %q of bucket %q does not exist", scope, bucket)}
+	}
+	collections, err := c.collections(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	tables := collections[database]
+	c.inferAll(ctx, database, tables)
+	if tables == nil {
+		tables = []sdk.TableInfo{}
+	}
+	return tables, nil
+}
+
+// inferAll fills in the fields of the collections of database. INFER
+// needs the query_select privilege and reads documents; a collection it
+// fails on is listed without fields.
+func (c *Connection) inferAll(ctx context.Context, database string, tables []sdk.TableInfo) {
+	bucket, scope, _ := splitDatabase(database)
+	for i := range tables {
+		tables[i].Columns, _ = c.infer(ctx, bucket, scope, tables[i].Name)
+	}
+}
+
+// infer describes a collection's fields from a sample of its documents.
+// INFER answers with one flavor per shape of document it found; the
+// fields are those of every flavor, sorted by name. A field is nullable
+// when it is null or missing in any of the sampled documents.
+func (c *Connection) infer(ctx context.Context, bucket, scope, collection string) ([]sdk.ColumnInfo, error) {
+	// Bucket, scope and collection names cannot contain backticks.
+	statement := fmt.Sprintf("INFER `%s`.`%s`.`%s` WITH {\"sample_size\": %d}", bucket, scope, collection, inferSampleSize)
+	_, rows, err := c.statement(ctx, statement, nil)
+	if err != nil {
+		return nil, err
+	}
+	type field struct {
+		types    []string
+		flavors  int
+		nullable bool
+	}
+	fields := map[string]*field{}
+	var flavors int
+	for _, row := range rows {
+		list, _ := row[0].([]any)
+		for _, f := range list {
+			flavor, _ := f.(map[string]any)
+			properties, _ := flavor["properties"].(map[string]any)
+			flavors++
+			for name, p := range properties {
+				prop, _ := p.(map[string]any)
+				fd := fields[name]
+				if fd == nil {
+					fd = &field{}
+					fields[name] = fd
+				}
+				fd.flavors++
+				for _, t := range propertyTypes(prop["type"]) {
+					if t == "null" {
+						fd.nullable = true
+					} else if !slices.Contains(fd.types, t) {
+						fd.types = append(fd.types, t)
+					}
+				}
+				if pct, ok := prop["%docs"]; ok && !isHundred(pct) {
+					fd.nullable = true
+				}
+			}
+		}
+	}
+	columns := make([]sdk.ColumnInfo, 0, len(fields))
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		fd := fields[name]
+		columns = append(columns, sdk.ColumnInfo{
+			Name:     name,
+			Type:     strings.Join(fd.types, "|"),
+			Nullable: fd.nullable || fd.flavors < flavors,
+		})
+	}
+	return columns, nil
+}
+
+// propertyTypes reads an INFER property's type, a name or a list of them.
+func propertyTypes(v any) []string {
+	switch x := v.(type) {
+	case string:
+		return []string{x}
+	case []any:
+		types := make([]string, 0, len(x))
+		for _, t := range x {
+			if s, ok := t.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// isHundred reports whether a %docs figure covers every sampled document.
+func isHundred(v any) bool {
+	switch x := v.(type) {
+	case int64:
+		return x >= 100
+	case float64:
+		return x >= 100
+	}
+	return false
+}
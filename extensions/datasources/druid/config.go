@@ -0,0 +1,48 @@
+package druid
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"data-voyager/sdk/pluginsdk"
+)
+
+// Config holds Druid connection parameters.
+type Config struct {
+	// URL is the router or broker the SQL API is served from, e.g.
+	// http://druid-router:8888.
+	URL      string `json:"url" toml:"url"`
+	Username string `json:"username,omitempty" toml:"username"`
+	Password string `json:"password,omitempty" toml:"password"`
+
+	// Context is the query context sent with every query, e.g.
+	// {"sqlTimeZone": "Asia/Seoul", "priority": 10, "timeout": 60000}.
+	Context map[string]any `json:"context,omitempty" toml:"context"`
+
+	pluginsdk.DialOptions
+}
+
+// reservedContext are context parameters the plugin sets per query.
+var reservedContext = []string{"sqlQueryId"}
+
+func (c *Config) Validate() error {
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an http or https URL, e.g. http://druid-router:8888")
+	}
+	if u.User != nil {
+		return fmt.Errorf("url must not contain credentials; set username and password instead")
+	}
+	c.URL = strings.TrimRight(c.URL, "/")
+	for _, k := range reservedContext {
+		if _, ok := c.Context[k]; ok {
+			return fmt.Errorf("context parameter %s is set per query and cannot be configured", k)
+		}
+	}
+	return c.DialOptions.Validate()
+}
+
+func (c *Config) GetConnectionString() string {
+	return strings.TrimRight(c.URL, "/")
+}
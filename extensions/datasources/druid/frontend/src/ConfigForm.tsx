@@ -0,0 +1,88 @@
+import { useState } from 'react'
+import { Input } from '@data-voyager/shared-ui/components/ui/input'
+import { Label } from '@data-voyager/shared-ui/components/ui/label'
+import { Textarea } from '@data-voyager/shared-ui/components/ui/textarea'
+import type { DatasourceConfigProps } from '@data-voyager/sdk'
+
+interface DruidConfig {
+  url: string
+  username: string
+  password: string
+  context: Record<string, unknown>
+}
+
+export function DruidConfigForm({ config, onChange }: DatasourceConfigProps) {
+  const cfg = config as Partial<DruidConfig>
+  // query context는 JSON으로 편집하고, 올바른 JSON일 때만 config에 반영한다
+  const [contextText, setContextText] = useState(
+    cfg.context ? JSON.stringify(cfg.context, null, 2) : '',
+  )
+  const [contextError, setContextError] = useState<string | null>(null)
+
+  const set = (key: keyof DruidConfig, value: string | Record<string, unknown> | undefined) =>
+    onChange({ ...cfg, [key]: value })
+
+  const setContext = (text: string) => {
+    setContextText(text)
+    if (text.trim() === '') {
+      setContextError(null)
+      set('context', undefined)
+      return
+    }
+    try {
+      const parsed = JSON.parse(text)
+      if (typeof parsed !== 'object' || parsed === null || Array.isArray(parsed)) {
+        setContextError('Context must be a JSON object')
+        return
+      }
+      setContextError(null)
+      set('context', parsed)
+    } catch {
+      setContextError('Invalid JSON')
+    }
+  }
+
+  return (
+    <div className="flex flex-col gap-4 max-w-lg">
+      <div className="space-y-2">
+        <Label>Router or broker URL</Label>
+        <Input
+          placeholder="http://druid-router:8888"
+          value={cfg.url ?? ''}
+          onChange={(e) => set('url', e.target.value)}
+        />
+      </div>
+
+      <div className="grid grid-cols-2 gap-3">
+        <div className="space-y-2">
+          <Label>Username</Label>
+          <Input
+            placeholder="(optional)"
+            value={cfg.username ?? ''}
+            onChange={(e) => set('username', e.target.value)}
+          />
+        </div>
+        <div className="space-y-2">
+          <Label>Password</Label>
+          <Input
+            type="password"
+            value={cfg.password ?? ''}
+            onChange={(e) => set('password', e.target.value)}
+          />
+        </div>
+      </div>
+
+      <div className="space-y-2">
+        <Label>Query context</Label>
+        <Textarea
+          className="font-mono text-xs"
+          rows={4}
+          placeholder={'{\n  "sqlTimeZone": "Etc/UTC",\n  "priority": 10\n}'}
+          value={contextText}
+          onChange={(e) => setContext(e.target.value)}
+        />
+        {contextError && <p className="text-xs text-destructive">{contextError}</p>}
+      </div>
+    </div>
+  )
+}
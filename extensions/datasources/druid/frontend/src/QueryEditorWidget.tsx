@@ -0,0 +1 @@
+export { GenericSQLQueryEditor as DruidQueryEditor } from '@data-voyager/shared-ui';
@@ -0,0 +1,18 @@
+import type { DatasourcePlugin } from '@data-voyager/sdk';
+import { datasourceRegistry } from '@data-voyager/sdk';
+import { DruidConfigForm } from './ConfigForm';
+import { DruidQueryEditor } from './QueryEditorWidget';
+import { druidSchemaProvider } from './schemaProvider';
+
+const plugin: DatasourcePlugin = {
+  id: 'druid',
+  name: 'Apache Druid',
+  description: 'Query Apache Druid datasources with Druid SQL',
+  configComponent: DruidConfigForm,
+  queryEditorComponent: DruidQueryEditor,
+  schemaProvider: druidSchemaProvider,
+};
+
+datasourceRegistry.register(plugin);
+
+export { plugin };
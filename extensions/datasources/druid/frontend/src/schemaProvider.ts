@@ -0,0 +1,56 @@
+import type { SchemaProvider, SchemaNode, PluginContext } from '@data-voyager/sdk';
+import type { SchemaInfo, TableInfo } from './types';
+
+export const druidSchemaProvider: SchemaProvider = {
+  async getRootNodes(_ctx: PluginContext, connectionId: string): Promise<SchemaNode[]> {
+    const schema = await fetchSchema(connectionId);
+    // Druid: 'druid' database 아래 datasource를 바로 루트에 표시
+    const tables = schema.databases[0]?.tables ?? [];
+    return tables.map((t: TableInfo) => ({
+      id: `table/${t.name}`,
+      label: t.name,
+      type: 'table' as const,
+      hasChildren: (t.columns?.length ?? 0) > 0,
+      meta: {
+        comment: t.description || t.type,
+        rowCount: t.row_count != null ? t.row_count.toLocaleString() : undefined,
+      },
+    }));
+  },
+
+  async getChildNodes(_ctx: PluginContext, connectionId: string, node: SchemaNode): Promise<SchemaNode[]> {
+    if (node.type !== 'table') return [];
+    // id: table/<datasource>
+    const tableName = node.id.slice('table/'.length);
+    const schema = await fetchSchema(connectionId);
+    const table = schema.databases[0]?.tables.find((t: TableInfo) => t.name === tableName);
+    return (table?.columns ?? []).map((col) => ({
+      id: `${node.id}/col/${col.name}`,
+      label: col.name,
+      type: 'column' as const,
+      hasChildren: false,
+      meta: { dataType: col.type, nullable: col.nullable },
+    }));
+  },
+
+  getInsertText(node: SchemaNode): string {
+    // datasource 이름에는 '-' 등이 흔해 항상 따옴표로 감싼다
+    const name = node.type === 'column' ? node.label : node.id.slice('table/'.length);
+    return `"${name.replace(/"/g, '""')}"`;
+  },
+};
+
+// 스키마 캐시
+const schemaCache = new Map<string, SchemaInfo>();
+
+async function fetchSchema(connectionId: string): Promise<SchemaInfo> {
+  if (schemaCache.has(connectionId)) {
+    return schemaCache.get(connectionId)!;
+  }
+  const res = await fetch(`/api/v1/connections/${connectionId}/schema`);
+  if (!res.ok) throw new Error('Failed to fetch schema');
+  const json = await res.json();
+  const schema: SchemaInfo = json.data;
+  schemaCache.set(connectionId, schema);
+  return schema;
+}
@@ -0,0 +1,121 @@
+package druid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"data-voyager/sdk"
+	"data-voyager/sdk/pluginsdk"
+)
+
+func init() {
+	sdk.RegisterDatasource(&Plugin{})
+}
+
+// Type is the DataSourceType identifier for this extension.
+const Type sdk.DataSourceType = "druid"
+
+const (
+	defaultConnectTimeout = 10 * time.Second
+	// datasourcesDatabase is the SQL schema Druid lists datasources under.
+	datasourcesDatabase = "druid"
+)
+
+// Plugin implements sdk.DatasourcePlugin for Apache Druid through its SQL
+// HTTP API. Datasources are browsed as the tables of a single "druid"
+// database, with their segment count and size from sys.segments.
+type Plugin struct{}
+
+func (p *Plugin) GetType() sdk.DataSourceType { return Type }
+func (p *Plugin) GetName() string             { return "Apache Druid" }
+
+// Capabilities implements sdk.CapabilityProvider.
+func (p *Plugin) Capabilities() sdk.Capabilities {
+	return sdk.Capabilities{Schemas: true}
+}
+
+// Version implements sdk.VersionProvider.
+func (p *Plugin) Version() sdk.VersionInfo {
+	return sdk.VersionInfo{
+		Version:          "0.1.0",
+		MinServerVersion: "0.1.0",
+		MaxServerVersion: "1.0.0",
+	}
+}
+
+func (p *Plugin) ParseConfig(data json.RawMessage) (sdk.ConnectionConfig, error) {
+	cfg, err := pluginsdk.DecodeConfig[Config](data, "druid")
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (p *Plugin) ValidateConfig(config any) error {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return fmt.Errorf("config must be *druid.Config")
+	}
+	return cfg.Validate()
+}
+
+func (p *Plugin) Connect(ctx context.Context, config sdk.ConnectionConfig) (sdk.Connection, error) {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("invalid config type for Druid")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid druid config: %w", err)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = cfg.Dialer(defaultConnectTimeout).DialContext
+	conn := &Connection{config: cfg, client: &http.Client{Transport: transport}}
+	if err := conn.Ping(ctx); err != nil {
+		conn.client.CloseIdleConnections()
+		return nil, fmt.Errorf("failed to ping Druid: %w", err)
+	}
+	return conn, nil
+}
+
+func (p *Plugin) TestConnection(ctx context.Context, config sdk.ConnectionConfig) (*sdk.ConnectionTestResult, error) {
+	return pluginsdk.TestConnection(ctx, p, config)
+}
+
+// Connection talks to one Druid cluster over HTTP. It keeps no state
+// between queries beyond the client's idle connections.
+type Connection struct {
+	config *Config
+	client *http.Client
+
+	metrics pluginsdk.QueryMetrics
+}
+
+// GetSchema lists the datasources under a single "druid" database.
+func (c *Connection) GetSchema(ctx context.Context) (*sdk.SchemaInfo, error) {
+	tables, err := c.GetTables(ctx, datasourcesDatabase)
+	if err != nil {
+		return nil, err
+	}
+	return &sdk.SchemaInfo{Databases: []sdk.DatabaseInfo{{Name: datasourcesDatabase, Tables: tables}}}, nil
+}
+
+func (c *Connection) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+// Ping runs a trivial query, which checks the credentials as well as that
+// a broker is up; /status/health answers without either.
+func (c *Connection) Ping(ctx context.Context) error {
+	_, _, err := c.sql(ctx, "SELECT 1", nil)
+	return err
+}
+
+func (c *Connection) GetMetrics() sdk.ConnectionMetrics {
+	metrics := sdk.ConnectionMetrics{LastActivity: time.Now()}
+	c.metrics.Fill(&metrics)
+	return metrics
+}
@@ -0,0 +1,300 @@
+package druid
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"data-voyager/sdk"
+	"data-voyager/sdk/pluginsdk"
+)
+
+// cancelTimeout bounds the request that cancels an abandoned query.
+const cancelTimeout = 5 * time.Second
+
+// sqlRequest is the body of POST /druid/v2/sql. With an array result and
+// both headers, the first two rows of the response are the column names
+// and their SQL types.
+type sqlRequest struct {
+	Query          string         `json:"query"`
+	ResultFormat   string         `json:"resultFormat"`
+	Header         bool           `json:"header"`
+	SQLTypesHeader bool           `json:"sqlTypesHeader"`
+	Context        map[string]any `json:"context,omitempty"`
+	Parameters     []sqlParameter `json:"parameters,omitempty"`
+}
+
+// sqlParameter binds one ? placeholder.
+type sqlParameter struct {
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+// apiError is an error response, in both the DruidException format of
+// Druid 28 and later and the older error/errorMessage/errorClass one.
+type apiError struct {
+	Error        string `json:"error"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+// Query runs query, in Druid SQL, with params bound to its ? placeholders
+// and the configured query context.
+func (c *Connection) Query(ctx context.Context, query string, params ...any) (*sdk.QueryResult, error) {
+	defer c.metrics.Track()()
+	start := time.Now()
+	columns, rows, err := c.sql(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+	return pluginsdk.TableResult(columns, rows, time.Since(start)), nil
+}
+
+// sql runs one query and reads its rows. Each query gets its own
+// sqlQueryId, so a query given up on when ctx ends is cancelled on the
+// broker rather than left running.
+func (c *Connection) sql(ctx context.Context, query string, params []any) ([]sdk.ColumnInfo, [][]any, error) {
+	req := sqlRequest{Query: query, ResultFormat: "array", Header: true, SQLTypesHeader: true, Context: c.queryContext(ctx)}
+	for i, p := range params {
+		param, err := parameter(p)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parameter %d: %w", i+1, err)
+		}
+		req.Parameters = append(req.Parameters, param)
+	}
+	id := req.Context["sqlQueryId"].(string)
+
+	resp, err := c.do(ctx, http.MethodPost, "/druid/v2/sql", req)
+	if err != nil {
+		c.cancelAbandoned(ctx, id)
+		return nil, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	columns, rows, err := readRows(resp.Body, pluginsdk.NewRowBudget(ctx))
+	if err != nil {
+		c.cancelAbandoned(ctx, id)
+		return nil, nil, err
+	}
+	return columns, rows, nil
+}
+
+// queryContext is the configured context plus the query's ID and, when ctx
+// has a deadline and no timeout is configured, a timeout that ends the
+// query on the broker when the caller stops waiting.
+func (c *Connection) queryContext(ctx context.Context) map[string]any {
+	qc := maps.Clone(c.config.Context)
+	if qc == nil {
+		qc = map[string]any{}
+	}
+	qc["sqlQueryId"] = "data-voyager-" + rand.Text()
+	if deadline, ok := ctx.Deadline(); ok {
+		if _, set := qc["timeout"]; !set {
+			qc["timeout"] = max(time.Until(deadline).Milliseconds(), 1)
+		}
+	}
+	return qc
+}
+
+// cancelAbandoned cancels query id once ctx is done. Druid answers 404
+// for a query that already finished, which is ignored like any failure.
+func (c *Connection) cancelAbandoned(ctx context.Context, id string) {
+	if ctx.Err() == nil {
+		return
+	}
+	cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cancelTimeout)
+	defer cancel()
+	if resp, err := c.do(cancelCtx, http.MethodDelete, "/druid/v2/sql/"+url.PathEscape(id), nil); err == nil {
+		_ = resp.Body.Close()
+	}
+}
+
+// do sends a request and returns the response of a successful one; an
+// error status is turned into an error carrying Druid's message.
+func (c *Connection) do(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.config.URL+path, r)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.config.Username != "" {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer func() { _ = resp.Body.Close() }()
+		return nil, responseError(resp)
+	}
+	return resp, nil
+}
+
+// responseError classifies an error response by its status and message.
+func responseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	msg := strings.TrimSpace(string(body))
+	var e apiError
+	if json.Unmarshal(body, &e) == nil {
+		switch {
+		case e.ErrorMessage != "":
+			msg = e.ErrorMessage
+		case e.Error != "":
+			msg = e.Error
+		}
+	}
+	if msg == "" {
+		msg = resp.Status
+	}
+	err := fmt.Errorf("druid: %s", msg)
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return &sdk.QueryError{Code: sdk.ErrCodeAuthFailed, Err: err}
+	case resp.StatusCode == http.StatusForbidden:
+		return &sdk.QueryError{Code: sdk.ErrCodePermissionDenied, Err: err}
+	case resp.StatusCode == http.StatusBadRequest && strings.Contains(msg, "not found"):
+		// "Object 'x' not found within 'druid'", or a column likewise.
+		return &sdk.QueryError{Code: sdk.ErrCodeUndefinedObject, Err: err}
+	case resp.StatusCode == http.StatusBadRequest:
+		return &sdk.QueryError{Code: sdk.ErrCodeSyntaxError, Err: err}
+	}
+	return err
+}
+
+// parameter types a Go value as a Druid SQL parameter.
+func parameter(v any) (sqlParameter, error) {
+	switch x := v.(type) {
+	case nil:
+		return sqlParameter{Type: "VARCHAR"}, nil
+	case string:
+		return sqlParameter{Type: "VARCHAR", Value: x}, nil
+	case []byte:
+		return sqlParameter{Type: "VARCHAR", Value: string(x)}, nil
+	case bool:
+		return sqlParameter{Type: "BOOLEAN", Value: x}, nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return sqlParameter{Type: "BIGINT", Value: x}, nil
+	case float32, float64:
+		return sqlParameter{Type: "DOUBLE", Value: x}, nil
+	case time.Time:
+		return sqlParameter{Type: "TIMESTAMP", Value: x.UTC().Format("2006-01-02 15:04:05.000")}, nil
+	case sql.NamedArg:
+		return sqlParameter{}, errors.New("druid does not support named parameters; use ? placeholders")
+	}
+	return sqlParameter{}, fmt.Errorf("unsupported type %T", v)
+}
+
+// readRows decodes an array result: the column names, their SQL types and
+// then one array per row.
+func readRows(body io.Reader, budget *pluginsdk.RowBudget) ([]sdk.ColumnInfo, [][]any, error) {
+	dec := json.NewDecoder(body)
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, nil, fmt.Errorf("druid: failed to read result: %w", err)
+	}
+	if tok != json.Delim('[') {
+		return nil, nil, fmt.Errorf("druid: unexpected result: %v", tok)
+	}
+	var names, types []string
+	if dec.More() {
+		if err := dec.Decode(&names); err != nil {
+			return nil, nil, fmt.Errorf("druid: failed to read column names: %w", err)
+		}
+	}
+	if dec.More() {
+		if err := dec.Decode(&types); err != nil {
+			return nil, nil, fmt.Errorf("druid: failed to read column types: %w", err)
+		}
+	}
+	columns := make([]sdk.ColumnInfo, len(names))
+	for i, name := range names {
+		columns[i] = sdk.ColumnInfo{Name: name, Nullable: true}
+		if i < len(types) {
+			columns[i].Type = types[i]
+		}
+	}
+
+	var rows [][]any
+	for dec.More() {
+		var row []any
+		if err := dec.Decode(&row); err != nil {
+			// Druid streams rows as it reads them, so a query failing part
+			// way through leaves the response cut short.
+			return nil, nil, fmt.Errorf("druid: result ended early: %w", err)
+		}
+		if len(row) != len(columns) {
+			return nil, nil, fmt.Errorf("druid: row has %d values for %d columns", len(row), len(columns))
+		}
+		for i, v := range row {
+			row[i] = convert(v, columns[i].Type)
+		}
+		if err := budget.Add(row); err != nil {
+			return nil, nil, err
+		}
+		rows = append(rows, row)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, fmt.Errorf("druid: result ended early: %w", err)
+	}
+	return columns, rows, nil
+}
+
+// convert turns a decoded value into the Go value its SQL type suggests:
+// integers as int64, other numbers as float64 and timestamps as time.Time.
+// Arrays and COMPLEX<json> values keep their shape with numbers converted.
+func convert(v any, sqlType string) any {
+	switch x := v.(type) {
+	case json.Number:
+		switch sqlType {
+		case "BOOLEAN":
+			return x.String() != "0"
+		case "DOUBLE", "FLOAT", "REAL", "DECIMAL":
+		default:
+			if n, err := x.Int64(); err == nil {
+				return n
+			}
+		}
+		if f, err := x.Float64(); err == nil {
+			return f
+		}
+		return x.String()
+	case string:
+		if sqlType == "TIMESTAMP" || sqlType == "DATE" {
+			if t, err := time.Parse(time.RFC3339Nano, x); err == nil {
+				return t
+			}
+		}
+		return x
+	case []any:
+		for i, e := range x {
+			x[i] = convert(e, "")
+		}
+		return x
+	case map[string]any:
+		for k, e := range x {
+			x[k] = convert(e, "")
+		}
+		return x
+	}
+	return v
+}
@@ -0,0 +1,94 @@
+package druid
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"data-voyager/sdk"
+)
+
+const (
+	tablesQuery = `SELECT TABLE_NAME, TABLE_TYPE FROM INFORMATION_SCHEMA.TABLES
+WHERE TABLE_SCHEMA = 'druid' ORDER BY TABLE_NAME`
+	columnsQuery = `SELECT TABLE_NAME, COLUMN_NAME, DATA_TYPE, IS_NULLABLE FROM INFORMATION_SCHEMA.COLUMNS
+WHERE TABLE_SCHEMA = 'druid' ORDER BY TABLE_NAME, ORDINAL_POSITION`
+	// segmentsQuery sums the segments queries are served from: published
+	// ones no newer segment replaces.
+	segmentsQuery = `SELECT "datasource", COUNT(*), SUM("size"), SUM("num_rows"), MIN("start"), MAX("end")
+FROM sys.segments WHERE is_published = 1 AND is_overshadowed = 0 GROUP BY "datasource"`
+)
+
+// segments summarizes the segments of one datasource.
+type segments struct {
+	count, size, rows int64
+	start, end        string
+}
+
+// GetTables lists the datasources with their columns and, when the caller
+// may read sys.segments, their row count, size and segments.
+func (c *Connection) GetTables(ctx context.Context, database string) ([]sdk.TableInfo, error) {
+	if database != "" && database != datasourcesDatabase {
+		return nil, &sdk.QueryError{Code: sdk.ErrCodeUnknownDatabase,
+			Err: fmt.Errorf("database %q does not exist; datasources are listed under %q", database, datasourcesDatabase)}
+	}
+	_, tableRows, err := c.sql(ctx, tablesQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list datasources: %w", err)
+	}
+	_, columnRows, err := c.sql(ctx, columnsQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list columns: %w", err)
+	}
+	columns := map[string][]sdk.ColumnInfo{}
+	for _, row := range columnRows {
+		table, _ := row[0].(string)
+		name, _ := row[1].(string)
+		typ, _ := row[2].(string)
+		nullable, _ := row[3].(string)
+		columns[table] = append(columns[table], sdk.ColumnInfo{Name: name, Type: typ, Nullable: nullable == "YES"})
+	}
+	// sys.segments needs the STATE read permission, which readers of
+	// datasources often lack; the tables are listed without it.
+	stats := map[string]segments{}
+	if _, segmentRows, err := c.sql(ctx, segmentsQuery, nil); err == nil {
+		for _, row := range segmentRows {
+			name, _ := row[0].(string)
+			var s segments
+			s.count, _ = row[1].(int64)
+			s.size, _ = row[2].(int64)
+			s.rows, _ = row[3].(int64)
+			s.start, _ = row[4].(string)
+			s.end, _ = row[5].(string)
+			stats[name] = s
+		}
+	}
+
+	tables := make([]sdk.TableInfo, 0, len(tableRows))
+	for _, row := range tableRows {
+		name, _ := row[0].(string)
+		typ, _ := row[1].(string)
+		info := sdk.TableInfo{Name: name, Type: typ, Columns: columns[name]}
+		if s, ok := stats[name]; ok {
+			info.RowCount, info.Size = &s.rows, &s.size
+			info.Description = s.describe()
+		}
+		tables = append(tables, info)
+	}
+	return tables, nil
+}
+
+// describe is e.g. "12 segments, 2024-01-01 to 2024-06-30".
+func (s segments) describe() string {
+	desc := fmt.Sprintf("%d segments", s.count)
+	if s.count == 1 {
+		desc = "1 segment"
+	}
+	start, err1 := time.Parse(time.RFC3339Nano, s.start)
+	end, err2 := time.Parse(time.RFC3339Nano, s.end)
+	if err1 == nil && err2 == nil {
+		// Segment intervals end exclusively.
+		desc += fmt.Sprintf(", %s to %s", start.UTC().Format(time.DateOnly), end.UTC().Add(-time.Millisecond).Format(time.DateOnly))
+	}
+	return desc
+}
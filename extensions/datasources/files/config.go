@@ -0,0 +1,95 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Listing and query limits.
+const (
+	DefaultMaxFiles = 1000
+	// DefaultMaxQueryMB bounds how much file data one query loads.
+	DefaultMaxQueryMB = 1024
+)
+
+// RootsEnv names the environment variable listing, separated like PATH, the
+// directories datasources may read below. The files are on the server, so
+// with none set no directory may be read.
+const RootsEnv = "VOYAGER_FILES_ROOTS"
+
+// Config holds the directory a datasource reads.
+type Config struct {
+	// Path is the directory, on the server, whose files are the tables.
+	Path string `json:"path" toml:"path"`
+	// Recursive also lists the files in subdirectories, named by their
+	// path below Path.
+	Recursive bool `json:"recursive" toml:"recursive"`
+
+	// MaxFiles caps how many files are listed as tables.
+	MaxFiles int `json:"max_files,omitempty" toml:"max_files"`
+	// MaxQueryMB caps the size of the files one query may read.
+	MaxQueryMB int `json:"max_query_mb,omitempty" toml:"max_query_mb"`
+}
+
+func (c *Config) Validate() error {
+	if c.Path == "" {
+		return fmt.Errorf("path is required")
+	}
+	if !filepath.IsAbs(c.Path) {
+		return fmt.Errorf("path %q must be absolute", c.Path)
+	}
+	if c.MaxFiles < 0 || c.MaxQueryMB < 0 {
+		return fmt.Errorf("max_files and max_query_mb must not be negative")
+	}
+	if c.MaxFiles == 0 {
+		c.MaxFiles = DefaultMaxFiles
+	}
+	if c.MaxQueryMB == 0 {
+		c.MaxQueryMB = DefaultMaxQueryMB
+	}
+	c.Path = filepath.Clean(c.Path)
+	return checkRoot(c.Path)
+}
+
+// checkRoot requires dir, once symbolic links are resolved, to be one of the
+// roots in RootsEnv or below one. A directory that does not exist yet is
+// checked as written; it is checked again on every connect.
+func checkRoot(dir string) error {
+	var roots []string
+	for _, r := range filepath.SplitList(os.Getenv(RootsEnv)) {
+		if filepath.IsAbs(r) {
+			roots = append(roots, resolve(filepath.Clean(r)))
+		}
+	}
+	if len(roots) == 0 {
+		return fmt.Errorf("no directories may be read; list them in %s", RootsEnv)
+	}
+	resolved, err := filepath.EvalSymlinks(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		resolved = dir
+	} else if err != nil {
+		return err
+	}
+	for _, root := range roots {
+		if rel, err := filepath.Rel(root, resolved); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("path %q is outside the directories listed in %s", dir, RootsEnv)
+}
+
+// resolve returns dir with symbolic links resolved, or as is if it cannot.
+func resolve(dir string) string {
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		return resolved
+	}
+	return dir
+}
+
+func (c *Config) GetConnectionString() string {
+	return "file://" + filepath.ToSlash(c.Path)
+}
@@ -0,0 +1,63 @@
+import { Input } from '@data-voyager/shared-ui/components/ui/input'
+import { Label } from '@data-voyager/shared-ui/components/ui/label'
+import { Switch } from '@data-voyager/shared-ui/components/ui/switch'
+import type { DatasourceConfigProps } from '@data-voyager/sdk'
+
+interface FilesConfig {
+  path: string
+  recursive: boolean
+  max_files: number
+  max_query_mb: number
+}
+
+export function FilesConfigForm({ config, onChange }: DatasourceConfigProps) {
+  const cfg = config as Partial<FilesConfig>
+
+  const set = (key: keyof FilesConfig, value: string | number | boolean) =>
+    onChange({ ...cfg, [key]: value })
+
+  return (
+    <div className="flex flex-col gap-4 max-w-lg">
+      <div className="space-y-2">
+        <Label>Directory</Label>
+        <Input
+          placeholder="/data/exports (absolute path on the server)"
+          value={cfg.path ?? ''}
+          onChange={(e) => set('path', e.target.value)}
+        />
+      </div>
+
+      <div className="flex items-center gap-3">
+        <Switch
+          checked={cfg.recursive ?? false}
+          onCheckedChange={(v) => set('recursive', v)}
+          id="recursive"
+        />
+        <Label htmlFor="recursive" className="cursor-pointer">
+          Include subdirectories
+        </Label>
+      </div>
+
+      <div className="grid grid-cols-2 gap-3">
+        <div className="space-y-2">
+          <Label>Max files</Label>
+          <Input
+            type="number"
+            placeholder="1000"
+            value={cfg.max_files ?? ''}
+            onChange={(e) => set('max_files', Number(e.target.value))}
+          />
+        </div>
+        <div className="space-y-2">
+          <Label>Max data per query (MB)</Label>
+          <Input
+            type="number"
+            placeholder="1024"
+            value={cfg.max_query_mb ?? ''}
+            onChange={(e) => set('max_query_mb', Number(e.target.value))}
+          />
+        </div>
+      </div>
+    </div>
+  )
+}
@@ -0,0 +1 @@
+export { GenericSQLQueryEditor as FilesQueryEditor } from '@data-voyager/shared-ui';
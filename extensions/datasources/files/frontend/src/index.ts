@@ -0,0 +1,18 @@
+import type { DatasourcePlugin } from '@data-voyager/sdk';
+import { datasourceRegistry } from '@data-voyager/sdk';
+import { FilesConfigForm } from './ConfigForm';
+import { FilesQueryEditor } from './QueryEditorWidget';
+import { filesSchemaProvider } from './schemaProvider';
+
+const plugin: DatasourcePlugin = {
+  id: 'files',
+  name: 'Local files',
+  description: 'Query CSV, Parquet and JSON Lines files in a server directory with SQL',
+  configComponent: FilesConfigForm,
+  queryEditorComponent: FilesQueryEditor,
+  schemaProvider: filesSchemaProvider,
+};
+
+datasourceRegistry.register(plugin);
+
+export { plugin };
@@ -0,0 +1,147 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"data-voyager/sdk"
+	"data-voyager/sdk/pluginsdk"
+)
+
+func init() {
+	sdk.RegisterDatasource(&Plugin{})
+}
+
+// Type is the DataSourceType identifier for this extension.
+const Type sdk.DataSourceType = "files"
+
+// Plugin implements sdk.DatasourcePlugin for CSV, Parquet and JSON Lines
+// files in a directory on the server. Each file is listed as a table of a
+// single database named after the directory; queries are SQL run by an
+// embedded DuckDB over the files they name.
+type Plugin struct{}
+
+func (p *Plugin) GetType() sdk.DataSourceType { return Type }
+func (p *Plugin) GetName() string             { return "Local files" }
+
+// Capabilities implements sdk.CapabilityProvider.
+func (p *Plugin) Capabilities() sdk.Capabilities {
+	return sdk.Capabilities{Schemas: true}
+}
+
+// Version implements sdk.VersionProvider.
+func (p *Plugin) Version() sdk.VersionInfo {
+	return sdk.VersionInfo{
+		Version:          "0.1.0",
+		MinServerVersion: "0.1.0",
+		MaxServerVersion: "1.0.0",
+		DriverVersion:    pluginsdk.ModuleVersion("github.com/marcboeker/go-duckdb"),
+	}
+}
+
+func (p *Plugin) ParseConfig(data json.RawMessage) (sdk.ConnectionConfig, error) {
+	cfg, err := pluginsdk.DecodeConfig[Config](data, "files")
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (p *Plugin) ValidateConfig(config any) error {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return fmt.Errorf("config must be *files.Config")
+	}
+	return cfg.Validate()
+}
+
+func (p *Plugin) Connect(ctx context.Context, config sdk.ConnectionConfig) (sdk.Connection, error) {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("invalid config type for local files")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid files config: %w", err)
+	}
+	conn := &Connection{config: cfg}
+	if err := conn.Ping(ctx); err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (p *Plugin) TestConnection(ctx context.Context, config sdk.ConnectionConfig) (*sdk.ConnectionTestResult, error) {
+	return pluginsdk.TestConnection(ctx, p, config)
+}
+
+// Connection reads one directory. It holds no open resources between
+// calls: every query gets a database of its own.
+type Connection struct {
+	config *Config
+
+	metrics pluginsdk.QueryMetrics
+}
+
+// database is the name of the single database the tables are listed under.
+func (c *Connection) database() string {
+	return filepath.Base(c.config.Path)
+}
+
+// GetSchema lists the tables under a single database named after the
+// directory.
+func (c *Connection) GetSchema(ctx context.Context) (*sdk.SchemaInfo, error) {
+	tables, err := c.GetTables(ctx, c.database())
+	if err != nil {
+		return nil, err
+	}
+	return &sdk.SchemaInfo{Databases: []sdk.DatabaseInfo{{Name: c.database(), Tables: tables, Description: c.config.GetConnectionString()}}}, nil
+}
+
+// GetTables lists the data files in the directory, with the columns DuckDB
+// reads from each.
+func (c *Connection) GetTables(ctx context.Context, database string) ([]sdk.TableInfo, error) {
+	if database != "" && database != c.database() {
+		return nil, &sdk.QueryError{Code: sdk.ErrCodeUnknownDatabase,
+			Err: fmt.Errorf("database %q does not exist; tables are listed under %q", database, c.database())}
+	}
+	tables, err := c.tables()
+	if err != nil {
+		return nil, err
+	}
+	return c.describe(ctx, tables)
+}
+
+func (c *Connection) Close() error { return nil }
+
+// Ping checks that the directory exists and can be read.
+func (c *Connection) Ping(context.Context) error {
+	info, err := os.Stat(c.config.Path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return &sdk.QueryError{Code: sdk.ErrCodeUnknownDatabase, Err: fmt.Errorf("directory %s does not exist", c.config.Path)}
+	case errors.Is(err, fs.ErrPermission):
+		return &sdk.QueryError{Code: sdk.ErrCodePermissionDenied, Err: err}
+	case err != nil:
+		return err
+	case !info.IsDir():
+		return &sdk.QueryError{Code: sdk.ErrCodeUnknownDatabase, Err: fmt.Errorf("%s is not a directory", c.config.Path)}
+	}
+	if _, err := os.ReadDir(c.config.Path); errors.Is(err, fs.ErrPermission) {
+		return &sdk.QueryError{Code: sdk.ErrCodePermissionDenied, Err: err}
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Connection) GetMetrics() sdk.ConnectionMetrics {
+	metrics := sdk.ConnectionMetrics{LastActivity: time.Now()}
+	c.metrics.Fill(&metrics)
+	return metrics
+}
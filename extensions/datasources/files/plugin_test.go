@@ -0,0 +1,220 @@
+package files
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/sdk"
+)
+
+// newDir writes a directory of exports: Parquet, CSV and gzipped JSON Lines
+// files, a subdirectory, and files that are not tables.
+func newDir(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	t.Setenv(RootsEnv, root)
+	dir := filepath.Join(root, "exports")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "2026"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".cache"), 0o755))
+
+	db, err := sql.Open("duckdb", "")
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+	_, err = db.Exec(`COPY (SELECT * FROM (VALUES (1::BIGINT, 'ann', 12.5), (2, NULL, 3.0), (3, 'ann', 7.25)) t(id, customer, total))
+		TO '` + filepath.Join(dir, "orders.parquet") + `' (FORMAT PARQUET)`)
+	require.NoError(t, err)
+
+	var events bytes.Buffer
+	zw := gzip.NewWriter(&events)
+	_, _ = zw.Write([]byte("{\"kind\":\"click\",\"n\":1}\n{\"kind\":\"view\",\"n\":2}\n"))
+	require.NoError(t, zw.Close())
+
+	for name, body := range map[string][]byte{
+		"customers.csv":        []byte("name,vip,score\nann,true,1.5\nbob,false,2\n"),
+		"2026/events.jsonl.gz": events.Bytes(),
+		"README.txt":           []byte("not a table"),
+		".cache/hidden.csv":    []byte("a\n1\n"),
+		"broken.jsonl":         []byte("{not json\n"),
+		"2026/customers.tsv":   []byte("name\tcity\nann\tOslo\n"),
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), body, 0o644))
+	}
+	require.NoError(t, os.Symlink("/etc/hostname", filepath.Join(dir, "linked.csv")))
+	return dir
+}
+
+func connect(t *testing.T, cfg Config) *Connection {
+	t.Helper()
+	raw, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	p := &Plugin{}
+	parsed, err := p.ParseConfig(raw)
+	require.NoError(t, err)
+	conn, err := p.Connect(context.Background(), parsed)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn.(*Connection)
+}
+
+func TestFilesPlugin(t *testing.T) {
+	ctx := context.Background()
+	dir := newDir(t)
+	conn := connect(t, Config{Path: dir, Recursive: true})
+
+	t.Run("schema", func(t *testing.T) {
+		schema, err := conn.GetSchema(ctx)
+		require.NoError(t, err)
+		require.Len(t, schema.Databases, 1)
+		db := schema.Databases[0]
+		assert.Equal(t, "exports", db.Name)
+		byName := map[string]sdk.TableInfo{}
+		for _, tbl := range db.Tables {
+			byName[tbl.Name] = tbl
+		}
+		assert.ElementsMatch(t, []string{"2026/customers", "2026/events", "broken", "customers", "orders"}, keys(byName))
+
+		assert.Equal(t, []sdk.ColumnInfo{
+			{Name: "kind", Type: "VARCHAR", Nullable: true},
+			{Name: "n", Type: "BIGINT", Nullable: true},
+		}, byName["2026/events"].Columns)
+		assert.Equal(t, "jsonl", byName["2026/events"].Type)
+		assert.Equal(t, []sdk.ColumnInfo{
+			{Name: "name", Type: "VARCHAR", Nullable: true},
+			{Name: "vip", Type: "BOOLEAN", Nullable: true},
+			{Name: "score", Type: "DOUBLE", Nullable: true},
+		}, byName["customers"].Columns)
+		orders := byName["orders"]
+		require.NotNil(t, orders.RowCount)
+		assert.EqualValues(t, 3, *orders.RowCount)
+		assert.Equal(t, "BIGINT", orders.Columns[0].Type)
+		assert.Contains(t, byName["broken"].Description, "columns unavailable")
+	})
+
+	t.Run("not recursive", func(t *testing.T) {
+		tables, err := connect(t, Config{Path: dir}).GetTables(ctx, "")
+		require.NoError(t, err)
+		var names []string
+		for _, tbl := range tables {
+			names = append(names, tbl.Name)
+		}
+		assert.ElementsMatch(t, []string{"broken", "customers", "orders"}, names)
+	})
+
+	t.Run("query", func(t *testing.T) {
+		res, err := conn.Query(ctx, `SELECT o.id, c.vip, o.total FROM orders o JOIN customers c ON c.name = o.customer
+			WHERE o.total > ? ORDER BY o.id`, 5)
+		require.NoError(t, err)
+		fields := res.Frames[0].Fields
+		require.Len(t, fields, 3)
+		assert.Equal(t, []any{int64(1), int64(3)}, fields[0].Values)
+		assert.Equal(t, []any{true, true}, fields[1].Values)
+		assert.Equal(t, []any{"12.5", "7.25"}, fields[2].Values)
+
+		// A query reads all of every file it names, so the estimate is what
+		// it then reads.
+		est, err := conn.EstimateScan(ctx, "SELECT * FROM orders JOIN customers USING (name)")
+		require.NoError(t, err)
+		customers, err := os.Stat(filepath.Join(dir, "customers.csv"))
+		require.NoError(t, err)
+		assert.Greater(t, est.Bytes, customers.Size())
+		assert.Equal(t, est.Bytes, res.Stats.BytesRead)
+
+		// sum over BIGINT is a HUGEINT, returned as an exact string.
+		res, err = conn.Query(ctx, `SELECT sum(n) AS n FROM "2026/events"`)
+		require.NoError(t, err)
+		assert.Equal(t, []any{"3"}, res.Frames[0].Fields[0].Values)
+	})
+
+	t.Run("query cannot reach outside its tables", func(t *testing.T) {
+		for _, q := range []string{
+			`SELECT * FROM read_csv_auto('/etc/passwd')`,
+			`SELECT * FROM read_csv_auto('` + filepath.Join(dir, ".cache", "hidden.csv") + `')`,
+			`SET enable_external_access = true`,
+			`COPY orders TO '` + filepath.Join(dir, "copy.csv") + `'`,
+		} {
+			_, err := conn.Query(ctx, q)
+			var qe *sdk.QueryError
+			require.ErrorAs(t, err, &qe, q)
+		}
+		_, err := os.Stat(filepath.Join(dir, "copy.csv"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := conn.Query(ctx, "SELEC 1")
+		var qe *sdk.QueryError
+		require.ErrorAs(t, err, &qe)
+		assert.Equal(t, sdk.ErrCodeSyntaxError, qe.Code)
+
+		_, err = conn.Query(ctx, "SELECT * FROM refunds")
+		require.ErrorAs(t, err, &qe)
+		assert.Equal(t, sdk.ErrCodeUndefinedObject, qe.Code)
+
+		_, err = conn.GetTables(ctx, "elsewhere")
+		require.ErrorAs(t, err, &qe)
+		assert.Equal(t, sdk.ErrCodeUnknownDatabase, qe.Code)
+	})
+}
+
+func keys(m map[string]sdk.TableInfo) []string {
+	var out []string
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
+
+func TestFilesPlugin_Limits(t *testing.T) {
+	dir := newDir(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "big.csv"), bytes.Repeat([]byte("x\n"), 1<<20), 0o644))
+	conn := connect(t, Config{Path: dir, MaxQueryMB: 1})
+
+	_, err := conn.Query(context.Background(), "SELECT count(*) FROM big")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "over the limit of 1 MB")
+
+	tables, err := connect(t, Config{Path: dir, MaxFiles: 2}).GetTables(context.Background(), "")
+	require.NoError(t, err)
+	assert.Len(t, tables, 2)
+
+	_, err = (&Plugin{}).Connect(context.Background(), &Config{Path: filepath.Join(dir, "missing")})
+	var qe *sdk.QueryError
+	require.ErrorAs(t, err, &qe)
+	assert.Equal(t, sdk.ErrCodeUnknownDatabase, qe.Code)
+}
+
+func TestConfigValidate(t *testing.T) {
+	root, outside := t.TempDir(), t.TempDir()
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "escape")))
+	t.Setenv(RootsEnv, root)
+
+	cases := map[string]Config{
+		"no path":        {},
+		"relative path":  {Path: "exports"},
+		"negative":       {Path: root, MaxFiles: -1},
+		"outside roots":  {Path: outside},
+		"dot-dot":        {Path: root + "/exports/../.."},
+		"symlinked away": {Path: filepath.Join(root, "escape")},
+	}
+	for name, cfg := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, cfg.Validate())
+		})
+	}
+	cfg := Config{Path: root + "/exports/"}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, DefaultMaxFiles, cfg.MaxFiles)
+	assert.Equal(t, "file://"+filepath.ToSlash(root)+"/exports", cfg.GetConnectionString())
+
+	t.Setenv(RootsEnv, "")
+	assert.ErrorContains(t, (&Config{Path: root}).Validate(), RootsEnv)
+}
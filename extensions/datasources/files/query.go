@@ -0,0 +1,203 @@
+package files
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/marcboeker/go-duckdb"
+
+	"data-voyager/sdk"
+	"data-voyager/sdk/pluginsdk"
+)
+
+// Query runs query, in DuckDB's SQL dialect, over the tables it names.
+// Those files are loaded into an in-memory database of the query's own,
+// which is then locked down: the query itself can read no other files,
+// reach no network and change no settings.
+func (c *Connection) Query(ctx context.Context, query string, params ...any) (*sdk.QueryResult, error) {
+	defer c.metrics.Track()()
+	start := time.Now()
+
+	used, size, err := c.named(query)
+	if err != nil {
+		return nil, err
+	}
+	if limit := int64(c.config.MaxQueryMB) << 20; size > limit {
+		return nil, fmt.Errorf("the tables this query names hold %d MB, over the limit of %d MB", size>>20, c.config.MaxQueryMB)
+	}
+
+	db, err := sql.Open("duckdb", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DuckDB: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+	// An in-memory database lives as long as its connections; one
+	// connection keeps the loaded tables for the query.
+	db.SetMaxOpenConns(1)
+
+	for _, t := range used {
+		if _, err := db.ExecContext(ctx, "CREATE TABLE "+quoteIdent(t.name)+" AS SELECT * FROM "+reader(t)); err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", t.name, err)
+		}
+	}
+	for _, stmt := range []string{"SET enable_external_access = false", "SET lock_configuration = true"} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return nil, fmt.Errorf("failed to lock down DuckDB: %w", err)
+		}
+	}
+
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, &sdk.QueryError{Code: queryErrorCode(err), Err: err}
+	}
+	columns, resultRows, err := scanRows(rows, pluginsdk.NewRowBudget(ctx))
+	if err != nil {
+		return nil, err
+	}
+	result := pluginsdk.TableResult(columns, resultRows, time.Since(start))
+	result.Stats.BytesRead = size
+	return result, nil
+}
+
+// EstimateScan implements sdk.ScanEstimator: a query reads the whole of
+// every file it names.
+func (c *Connection) EstimateScan(_ context.Context, query string, _ ...any) (*sdk.ScanEstimate, error) {
+	_, size, err := c.named(query)
+	if err != nil {
+		return nil, err
+	}
+	return &sdk.ScanEstimate{Bytes: size, Method: "file size"}, nil
+}
+
+// named returns the tables query names and their total size.
+func (c *Connection) named(query string) ([]table, int64, error) {
+	tables, err := c.tables()
+	if err != nil {
+		return nil, 0, err
+	}
+	var used []table
+	var size int64
+	for _, t := range tables {
+		if mentions(query, t.name) {
+			used = append(used, t)
+			size += t.size
+		}
+	}
+	return used, size, nil
+}
+
+// mentions reports whether query names table: whether its name, compared
+// without regard to case as DuckDB compares identifiers, appears with no
+// identifier character on either side.
+func mentions(query, table string) bool {
+	q, name := strings.ToLower(query), strings.ToLower(table)
+	for i := 0; ; {
+		j := strings.Index(q[i:], name)
+		if j < 0 {
+			return false
+		}
+		j += i
+		end := j + len(name)
+		if (j == 0 || !identChar(q[j-1])) && (end == len(q) || !identChar(q[end])) {
+			return true
+		}
+		i = j + 1
+	}
+}
+
+func identChar(b byte) bool {
+	return b == '_' || b >= 'a' && b <= 'z' || b >= '0' && b <= '9' || b >= 0x80
+}
+
+func quoteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// queryErrorCode classifies a DuckDB query error by its message prefix.
+func queryErrorCode(err error) string {
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "Parser Error"):
+		return sdk.ErrCodeSyntaxError
+	case strings.HasPrefix(msg, "Catalog Error"):
+		return sdk.ErrCodeUndefinedObject
+	case strings.HasPrefix(msg, "Permission Error"):
+		return sdk.ErrCodePermissionDenied
+	}
+	return ""
+}
+
+func scanRows(rows *sql.Rows, budget *pluginsdk.RowBudget) ([]sdk.ColumnInfo, [][]any, error) {
+	defer func() { _ = rows.Close() }()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get column types: %w", err)
+	}
+
+	columns := make([]sdk.ColumnInfo, len(columnTypes))
+	for i, ct := range columnTypes {
+		columns[i] = sdk.ColumnInfo{Name: ct.Name(), Type: ct.DatabaseTypeName(), Nullable: true}
+	}
+
+	var resultRows [][]any
+	for rows.Next() {
+		values := make([]any, len(columnTypes))
+		valuePtrs := make([]any, len(columnTypes))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		for i, v := range values {
+			values[i] = normalize(v)
+		}
+		if err := budget.Add(values); err != nil {
+			return nil, nil, err
+		}
+		resultRows = append(resultRows, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return columns, resultRows, nil
+}
+
+// normalize turns the values DuckDB scans into ones that encode as JSON:
+// decimals and huge integers as exact strings, and maps keyed by strings.
+func normalize(v any) any {
+	switch x := v.(type) {
+	case duckdb.Decimal:
+		return x.String()
+	case *big.Int:
+		return x.String()
+	case duckdb.UUID:
+		return x.String()
+	case duckdb.Map:
+		out := make(map[string]any, len(x))
+		for k, e := range x {
+			out[fmt.Sprint(k)] = normalize(e)
+		}
+		return out
+	case map[string]any:
+		for k, e := range x {
+			x[k] = normalize(e)
+		}
+		return x
+	case []any:
+		for i, e := range x {
+			x[i] = normalize(e)
+		}
+		return x
+	}
+	return pluginsdk.NormalizeValue(v)
+}
@@ -0,0 +1,170 @@
+package files
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"data-voyager/sdk"
+)
+
+// File formats, as the table type of the files holding them.
+const (
+	formatParquet = "parquet"
+	formatCSV     = "csv"
+	formatJSONL   = "jsonl"
+)
+
+// extensions maps the file extensions read as tables to their format.
+// Longer extensions come first so .csv.gz is not taken for .gz.
+var extensions = []struct{ ext, format string }{
+	{".parquet", formatParquet},
+	{".csv.gz", formatCSV},
+	{".csv", formatCSV},
+	{".tsv", formatCSV},
+	{".jsonl.gz", formatJSONL},
+	{".jsonl", formatJSONL},
+	{".ndjson", formatJSONL},
+}
+
+// table is a file listed as a table.
+type table struct {
+	name   string
+	format string
+	path   string
+	size   int64
+}
+
+// errEnough stops the directory walk once MaxFiles tables are found.
+var errEnough = errors.New("enough files")
+
+// tables lists the data files in the directory, named by their path below
+// it, with forward slashes and without the extension. When two files would
+// share a name the later one keeps its extension. Hidden files and
+// directories are skipped and symbolic links are not followed, so tables
+// never come from outside the directory.
+func (c *Connection) tables() ([]table, error) {
+	root := c.config.Path
+	var tables []table
+	taken := map[string]bool{}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root {
+				return err
+			}
+			// An unreadable subdirectory should not hide the rest.
+			return nil
+		}
+		if p == root {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if !c.config.Recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		for _, e := range extensions {
+			if !strings.HasSuffix(strings.ToLower(rel), e.ext) || len(path.Base(rel)) == len(e.ext) {
+				continue
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			name := rel[:len(rel)-len(e.ext)]
+			if taken[name] {
+				name = rel
+			}
+			taken[name] = true
+			tables = append(tables, table{name: name, format: e.format, path: p, size: info.Size()})
+			if len(tables) == c.config.MaxFiles {
+				return errEnough
+			}
+			break
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errEnough) {
+		return nil, fmt.Errorf("list %s: %w", root, err)
+	}
+	return tables, nil
+}
+
+// describe reads the columns of tables the way DuckDB will read them in a
+// query, from the Parquet footer or a sample of the rows.
+func (c *Connection) describe(ctx context.Context, tables []table) ([]sdk.TableInfo, error) {
+	db, err := sql.Open("duckdb", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DuckDB: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	infos := make([]sdk.TableInfo, len(tables))
+	for i, t := range tables {
+		size := t.size
+		infos[i] = sdk.TableInfo{Name: t.name, Type: t.format, Size: &size}
+		infos[i].Columns, err = columns(ctx, db, t)
+		if err != nil {
+			// One unreadable file should not hide the rest of the
+			// directory.
+			infos[i].Description = "columns unavailable: " + err.Error()
+			continue
+		}
+		if t.format == formatParquet {
+			var rows int64
+			if err := db.QueryRowContext(ctx, "SELECT num_rows FROM parquet_file_metadata("+quoteLiteral(t.path)+")").Scan(&rows); err == nil {
+				infos[i].RowCount = &rows
+			}
+		}
+	}
+	return infos, nil
+}
+
+func columns(ctx context.Context, db *sql.DB, t table) ([]sdk.ColumnInfo, error) {
+	rows, err := db.QueryContext(ctx, "DESCRIBE SELECT * FROM "+reader(t))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	var columns []sdk.ColumnInfo
+	for rows.Next() {
+		var name, typ string
+		var null, key, def, extra sql.NullString
+		if err := rows.Scan(&name, &typ, &null, &key, &def, &extra); err != nil {
+			return nil, err
+		}
+		columns = append(columns, sdk.ColumnInfo{Name: name, Type: typ, Nullable: null.String != "NO"})
+	}
+	return columns, rows.Err()
+}
+
+// reader is the DuckDB table function call reading the file of t.
+func reader(t table) string {
+	switch t.format {
+	case formatParquet:
+		return "read_parquet(" + quoteLiteral(t.path) + ")"
+	case formatJSONL:
+		return "read_json_auto(" + quoteLiteral(t.path) + ", format = 'newline_delimited')"
+	}
+	return "read_csv_auto(" + quoteLiteral(t.path) + ")"
+}
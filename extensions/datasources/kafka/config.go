@@ -0,0 +1,90 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"data-voyager/sdk/pluginsdk"
+)
+
+// SASL mechanisms a config may name.
+const (
+	SASLPlain       = "PLAIN"
+	SASLScramSHA256 = "SCRAM-SHA-256"
+	SASLScramSHA512 = "SCRAM-SHA-512"
+)
+
+// Message limits. A query reads at most MaxMessages messages, however many
+// it asks for.
+const (
+	DefaultMessages = 100
+	MaxMessages     = 10000
+)
+
+// Config holds Kafka connection parameters.
+type Config struct {
+	// Brokers are the seed brokers, as host:port; the rest of the cluster
+	// is discovered from them.
+	Brokers  []string `json:"brokers" toml:"brokers"`
+	ClientID string   `json:"client_id,omitempty" toml:"client_id"`
+	// SASLMechanism is PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512; empty
+	// connects without authenticating.
+	SASLMechanism string `json:"sasl_mechanism,omitempty" toml:"sasl_mechanism"`
+	Username      string `json:"username,omitempty" toml:"username"`
+	Password      string `json:"password,omitempty" toml:"password"`
+	TLS           bool   `json:"tls" toml:"tls"`
+
+	// SchemaRegistryURL points at a Confluent-compatible schema registry.
+	// When set, topic columns come from the latest "<topic>-value" schema
+	// and registry-framed messages are decoded with the schema they name.
+	SchemaRegistryURL      string `json:"schema_registry_url,omitempty" toml:"schema_registry_url"`
+	SchemaRegistryUsername string `json:"schema_registry_username,omitempty" toml:"schema_registry_username"`
+	SchemaRegistryPassword string `json:"schema_registry_password,omitempty" toml:"schema_registry_password"`
+
+	// ShowInternal lists internal topics such as __consumer_offsets.
+	ShowInternal bool `json:"show_internal,omitempty" toml:"show_internal"`
+
+	pluginsdk.DialOptions
+}
+
+func (c *Config) Validate() error {
+	if len(c.Brokers) == 0 {
+		return fmt.Errorf("at least one broker is required")
+	}
+	for _, b := range c.Brokers {
+		if _, _, err := net.SplitHostPort(strings.TrimSpace(b)); err != nil {
+			return fmt.Errorf("broker %q must be host:port", b)
+		}
+	}
+	if c.ClientID == "" {
+		c.ClientID = "data-voyager"
+	}
+	c.SASLMechanism = strings.ToUpper(c.SASLMechanism)
+	switch c.SASLMechanism {
+	case "":
+	case SASLPlain, SASLScramSHA256, SASLScramSHA512:
+		if c.Username == "" {
+			return fmt.Errorf("username is required for SASL %s", c.SASLMechanism)
+		}
+	default:
+		return fmt.Errorf("sasl_mechanism must be one of %s, %s or %s, got %q",
+			SASLPlain, SASLScramSHA256, SASLScramSHA512, c.SASLMechanism)
+	}
+	if c.SchemaRegistryURL != "" {
+		u, err := url.Parse(c.SchemaRegistryURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("schema_registry_url must be an http or https URL")
+		}
+	}
+	return c.DialOptions.Validate()
+}
+
+func (c *Config) GetConnectionString() string {
+	brokers := make([]string, len(c.Brokers))
+	for i, b := range c.Brokers {
+		brokers[i] = strings.TrimSpace(b)
+	}
+	return "kafka://" + strings.Join(brokers, ",")
+}
@@ -0,0 +1,201 @@
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hamba/avro/v2"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// wireMagic starts a message framed by a schema registry serializer: the
+// magic byte, a 4-byte big-endian schema ID, then the payload.
+const wireMagic = 0
+
+// decode turns a message key or value into a JSON-friendly value: objects
+// become map[string]any. Registry-framed data is decoded with the schema it
+// names; other data is parsed as JSON, else kept as text, or base64 when it
+// is not UTF-8. A registry-framed message whose schema cannot be read or
+// applied is treated as other data.
+func decode(ctx context.Context, reg *registry, data []byte) any {
+	if data == nil {
+		return nil
+	}
+	if reg != nil && len(data) > 5 && data[0] == wireMagic {
+		if s, err := reg.schema(ctx, int(binary.BigEndian.Uint32(data[1:5]))); err == nil {
+			if v, err := s.decode(data[5:]); err == nil {
+				return v
+			}
+		}
+	}
+	return decodePlain(data)
+}
+
+func decodePlain(data []byte) any {
+	if json.Valid(data) {
+		if v, err := decodeJSON(data); err == nil {
+			return v
+		}
+	}
+	if utf8.Valid(data) {
+		return string(data)
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// decodeJSON decodes data keeping integers exact.
+func decodeJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return normalize(v), nil
+}
+
+// decode decodes a payload written with s.
+func (s *schema) decode(payload []byte) (any, error) {
+	switch {
+	case s.avro != nil:
+		// avro.Unmarshal takes running out of data for success, which
+		// would show a truncated payload as empty fields.
+		r := avro.NewReader(nil, 0)
+		r.Reset(payload)
+		var v any
+		if r.ReadVal(s.avro, &v); r.Error != nil {
+			return nil, r.Error
+		}
+		return normalize(v), nil
+	case s.json:
+		return decodeJSON(payload)
+	case s.proto != nil:
+		payload, err := skipMessageIndexes(payload)
+		if err != nil {
+			return nil, err
+		}
+		m := dynamicpb.NewMessage(s.proto)
+		if err := proto.Unmarshal(payload, m); err != nil {
+			return nil, err
+		}
+		return protoValue(m), nil
+	}
+	return nil, fmt.Errorf("unsupported schema")
+}
+
+// skipMessageIndexes strips the message indexes a Protobuf serializer
+// writes after the schema ID. Only the first message of a schema, written
+// as a single 0, is supported.
+func skipMessageIndexes(payload []byte) ([]byte, error) {
+	n, size := protowire.ConsumeVarint(payload)
+	if size < 0 {
+		return nil, fmt.Errorf("invalid message indexes")
+	}
+	if protowire.DecodeZigZag(n) != 0 {
+		return nil, fmt.Errorf("only the first message of a Protobuf schema is supported")
+	}
+	return payload[size:], nil
+}
+
+// protoValue converts a message to map[string]any keyed by field name.
+// Timestamps become time.Time, enums their value names and int64s stay
+// numbers; other nested messages are converted recursively.
+func protoValue(m protoreflect.Message) any {
+	if m.Descriptor().FullName() == "google.protobuf.Timestamp" {
+		fields := m.Descriptor().Fields()
+		return time.Unix(m.Get(fields.ByName("seconds")).Int(), m.Get(fields.ByName("nanos")).Int()).UTC()
+	}
+	out := map[string]any{}
+	m.Range(func(f protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case f.IsList():
+			list := v.List()
+			items := make([]any, list.Len())
+			for i := range items {
+				items[i] = protoScalarValue(f, list.Get(i))
+			}
+			out[string(f.Name())] = items
+		case f.IsMap():
+			entries := map[string]any{}
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				entries[k.String()] = protoScalarValue(f.MapValue(), mv)
+				return true
+			})
+			out[string(f.Name())] = entries
+		default:
+			out[string(f.Name())] = protoScalarValue(f, v)
+		}
+		return true
+	})
+	return out
+}
+
+func protoScalarValue(f protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	switch f.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return protoValue(v.Message())
+	case protoreflect.EnumKind:
+		if ev := f.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return int32(v.Enum())
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	}
+	return v.Interface()
+}
+
+// normalize makes decoded values JSON-friendly: json.Numbers become int64 or
+// float64, Avro decimals strings and bytes base64, recursively.
+func normalize(v any) any {
+	switch x := v.(type) {
+	case json.Number:
+		if n, err := x.Int64(); err == nil {
+			return n
+		}
+		f, _ := x.Float64()
+		return f
+	case map[string]any:
+		for k, e := range x {
+			x[k] = normalize(e)
+		}
+		return x
+	case []any:
+		for i, e := range x {
+			x[i] = normalize(e)
+		}
+		return x
+	case *big.Rat:
+		return x.FloatString(decimalDigits(x))
+	case []byte:
+		return base64.StdEncoding.EncodeToString(x)
+	}
+	return v
+}
+
+// decimalDigits is the number of decimal places needed to print r exactly,
+// capped for fractions that never terminate.
+func decimalDigits(r *big.Rat) int {
+	d := new(big.Int).Set(r.Denom())
+	n := 0
+	ten := big.NewInt(10)
+	for n < 38 && d.Cmp(big.NewInt(1)) != 0 {
+		g := new(big.Int).GCD(nil, nil, d, ten)
+		if g.Cmp(big.NewInt(1)) == 0 {
+			return 38
+		}
+		d.Quo(d, g)
+		n++
+	}
+	return n
+}
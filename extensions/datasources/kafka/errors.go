@@ -0,0 +1,30 @@
+package kafka
+
+import (
+	"errors"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+
+	"data-voyager/sdk"
+)
+
+// errorCodes maps broker error codes to sdk error codes.
+var errorCodes = map[*kerr.Error]string{
+	kerr.SaslAuthenticationFailed:   sdk.ErrCodeAuthFailed,
+	kerr.TopicAuthorizationFailed:   sdk.ErrCodePermissionDenied,
+	kerr.ClusterAuthorizationFailed: sdk.ErrCodePermissionDenied,
+	kerr.UnknownTopicOrPartition:    sdk.ErrCodeUndefinedObject,
+}
+
+// classify wraps a broker error in an sdk.QueryError when its code is one
+// clients care about.
+func classify(err error) error {
+	var ke *kerr.Error
+	if !errors.As(err, &ke) {
+		return err
+	}
+	if code, ok := errorCodes[ke]; ok {
+		return &sdk.QueryError{Code: code, Err: err}
+	}
+	return err
+}
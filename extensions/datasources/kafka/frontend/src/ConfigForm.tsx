@@ -0,0 +1,145 @@
+import { Input } from '@data-voyager/shared-ui/components/ui/input'
+import { Label } from '@data-voyager/shared-ui/components/ui/label'
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from '@data-voyager/shared-ui/components/ui/select'
+import { Switch } from '@data-voyager/shared-ui/components/ui/switch'
+import type { DatasourceConfigProps } from '@data-voyager/sdk'
+
+interface KafkaConfig {
+  brokers: string[]
+  client_id: string
+  sasl_mechanism: string
+  username: string
+  password: string
+  tls: boolean
+  schema_registry_url: string
+  schema_registry_username: string
+  schema_registry_password: string
+  show_internal: boolean
+}
+
+// Select는 빈 값을 쓸 수 없어 인증 없음을 'NONE'으로 표시
+const SASL_MECHANISMS = ['NONE', 'PLAIN', 'SCRAM-SHA-256', 'SCRAM-SHA-512']
+
+export function KafkaConfigForm({ config, onChange }: DatasourceConfigProps) {
+  const cfg = config as Partial<KafkaConfig>
+
+  const set = (key: keyof KafkaConfig, value: string | string[] | boolean) =>
+    onChange({ ...cfg, [key]: value })
+
+  const sasl = cfg.sasl_mechanism || 'NONE'
+
+  return (
+    <div className="flex flex-col gap-4 max-w-lg">
+      <div className="space-y-2">
+        <Label>Brokers</Label>
+        <Input
+          placeholder="broker-1:9092, broker-2:9092"
+          value={(cfg.brokers ?? []).join(', ')}
+          onChange={(e) =>
+            set('brokers', e.target.value.split(',').map((s) => s.trim()).filter(Boolean))
+          }
+        />
+      </div>
+
+      <div className="space-y-2">
+        <Label>Client ID</Label>
+        <Input
+          placeholder="data-voyager"
+          value={cfg.client_id ?? ''}
+          onChange={(e) => set('client_id', e.target.value)}
+        />
+      </div>
+
+      <div className="space-y-2">
+        <Label>SASL mechanism</Label>
+        <Select
+          value={sasl}
+          onValueChange={(v) => v !== null && set('sasl_mechanism', v === 'NONE' ? '' : v)}
+        >
+          <SelectTrigger>
+            <SelectValue />
+          </SelectTrigger>
+          <SelectContent>
+            {SASL_MECHANISMS.map((m) => (
+              <SelectItem key={m} value={m}>{m}</SelectItem>
+            ))}
+          </SelectContent>
+        </Select>
+      </div>
+
+      {sasl !== 'NONE' && (
+        <>
+          <div className="space-y-2">
+            <Label>Username</Label>
+            <Input
+              value={cfg.username ?? ''}
+              onChange={(e) => set('username', e.target.value)}
+            />
+          </div>
+
+          <div className="space-y-2">
+            <Label>Password</Label>
+            <Input
+              type="password"
+              placeholder="••••••••"
+              value={cfg.password ?? ''}
+              onChange={(e) => set('password', e.target.value)}
+            />
+          </div>
+        </>
+      )}
+
+      <div className="flex items-center gap-3">
+        <Switch
+          checked={cfg.tls ?? false}
+          onCheckedChange={(v) => set('tls', v)}
+          id="tls"
+        />
+        <Label htmlFor="tls" className="cursor-pointer">
+          TLS
+        </Label>
+      </div>
+
+      <div className="space-y-2">
+        <Label>Schema registry URL</Label>
+        <Input
+          placeholder="http://schema-registry:8081 (optional)"
+          value={cfg.schema_registry_url ?? ''}
+          onChange={(e) => set('schema_registry_url', e.target.value)}
+        />
+      </div>
+
+      {cfg.schema_registry_url && (
+        <div className="grid grid-cols-2 gap-3">
+          <div className="space-y-2">
+            <Label>Registry username</Label>
+            <Input
+              value={cfg.schema_registry_username ?? ''}
+              onChange={(e) => set('schema_registry_username', e.target.value)}
+            />
+          </div>
+          <div className="space-y-2">
+            <Label>Registry password</Label>
+            <Input
+              type="password"
+              placeholder="••••••••"
+              value={cfg.schema_registry_password ?? ''}
+              onChange={(e) => set('schema_registry_password', e.target.value)}
+            />
+          </div>
+        </div>
+      )}
+
+      <div className="flex items-center gap-3">
+        <Switch
+          checked={cfg.show_internal ?? false}
+          onCheckedChange={(v) => set('show_internal', v)}
+          id="show_internal"
+        />
+        <Label htmlFor="show_internal" className="cursor-pointer">
+          Show internal topics
+        </Label>
+      </div>
+    </div>
+  )
+}
@@ -0,0 +1 @@
+export { GenericSQLQueryEditor as KafkaQueryEditor } from '@data-voyager/shared-ui';
@@ -0,0 +1,18 @@
+import type { DatasourcePlugin } from '@data-voyager/sdk';
+import { datasourceRegistry } from '@data-voyager/sdk';
+import { KafkaConfigForm } from './ConfigForm';
+import { KafkaQueryEditor } from './QueryEditorWidget';
+import { kafkaSchemaProvider } from './schemaProvider';
+
+const plugin: DatasourcePlugin = {
+  id: 'kafka',
+  name: 'Kafka',
+  description: 'Browse Apache Kafka topics and read messages as rows',
+  configComponent: KafkaConfigForm,
+  queryEditorComponent: KafkaQueryEditor,
+  schemaProvider: kafkaSchemaProvider,
+};
+
+datasourceRegistry.register(plugin);
+
+export { plugin };
@@ -0,0 +1,55 @@
+import type { SchemaProvider, SchemaNode, PluginContext } from '@data-voyager/sdk';
+import type { SchemaInfo, TableInfo } from './types';
+
+export const kafkaSchemaProvider: SchemaProvider = {
+  async getRootNodes(_ctx: PluginContext, connectionId: string): Promise<SchemaNode[]> {
+    const schema = await fetchSchema(connectionId);
+    // Kafka: 단일 "topics" database 아래의 토픽을 바로 루트에 표시
+    const topics = schema.databases[0]?.tables ?? [];
+    return topics.map((t: TableInfo) => ({
+      id: `topic/${t.name}`,
+      label: t.name,
+      type: 'table' as const,
+      hasChildren: (t.columns?.length ?? 0) > 0,
+      meta: {
+        comment: t.description || undefined,
+        rowCount: t.row_count != null ? t.row_count.toLocaleString() : undefined,
+      },
+    }));
+  },
+
+  async getChildNodes(_ctx: PluginContext, connectionId: string, node: SchemaNode): Promise<SchemaNode[]> {
+    if (node.type !== 'table') return [];
+    // id: topic/<topic>
+    const topicName = node.id.slice('topic/'.length);
+    const schema = await fetchSchema(connectionId);
+    const topic = schema.databases[0]?.tables.find((t: TableInfo) => t.name === topicName);
+    return (topic?.columns ?? []).map((col) => ({
+      id: `${node.id}/col/${col.name}`,
+      label: col.name,
+      type: 'column' as const,
+      hasChildren: false,
+      meta: { dataType: col.type, nullable: col.nullable },
+    }));
+  },
+
+  getInsertText(node: SchemaNode): string {
+    // 토픽 이름만으로 최신 메시지를 읽는 쿼리가 된다; 컬럼은 이름 그대로
+    return node.type === 'column' ? node.label : node.id.slice('topic/'.length);
+  },
+};
+
+// 스키마 캐시
+const schemaCache = new Map<string, SchemaInfo>();
+
+async function fetchSchema(connectionId: string): Promise<SchemaInfo> {
+  if (schemaCache.has(connectionId)) {
+    return schemaCache.get(connectionId)!;
+  }
+  const res = await fetch(`/api/v1/connections/${connectionId}/schema`);
+  if (!res.ok) throw new Error('Failed to fetch schema');
+  const json = await res.json();
+  const schema: SchemaInfo = json.data;
+  schemaCache.set(connectionId, schema);
+  return schema;
+}
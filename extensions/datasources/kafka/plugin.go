@@ -0,0 +1,537 @@
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+
+	"data-voyager/sdk"
+	"data-voyager/sdk/pluginsdk"
+)
+
+func init() {
+	sdk.RegisterDatasource(&Plugin{})
+}
+
+// Type is the DataSourceType identifier for this extension.
+const Type sdk.DataSourceType = "kafka"
+
+const (
+	defaultConnectTimeout = 10 * time.Second
+	// idleTimeout ends a read once no records arrive for this long, which
+	// happens when the last offsets of a partition hold transaction markers
+	// or were compacted away.
+	idleTimeout = 2 * time.Second
+	// topicsDatabase is the single database topics are listed under.
+	topicsDatabase = "topics"
+)
+
+// Plugin implements sdk.DatasourcePlugin for Apache Kafka. Topics are
+// browsed as tables whose columns come from the schema registry, and a
+// query reads a bounded range of a topic's messages as rows.
+type Plugin struct{}
+
+func (p *Plugin) GetType() sdk.DataSourceType { return Type }
+func (p *Plugin) GetName() string             { return "Kafka" }
+
+// Capabilities implements sdk.CapabilityProvider.
+func (p *Plugin) Capabilities() sdk.Capabilities {
+	return sdk.Capabilities{Schemas: true}
+}
+
+// Version implements sdk.VersionProvider.
+func (p *Plugin) Version() sdk.VersionInfo {
+	return sdk.VersionInfo{
+		Version:          "0.1.0",
+		MinServerVersion: "0.1.0",
+		MaxServerVersion: "1.0.0",
+		DriverVersion:    pluginsdk.ModuleVersion("github.com/twmb/franz-go"),
+	}
+}
+
+func (p *Plugin) ParseConfig(data json.RawMessage) (sdk.ConnectionConfig, error) {
+	cfg, err := pluginsdk.DecodeConfig[Config](data, "kafka")
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (p *Plugin) ValidateConfig(config any) error {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return fmt.Errorf("config must be *kafka.Config")
+	}
+	return cfg.Validate()
+}
+
+func (p *Plugin) Connect(ctx context.Context, config sdk.ConnectionConfig) (sdk.Connection, error) {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("invalid config type for Kafka")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid kafka config: %w", err)
+	}
+	opts := clientOptions(cfg)
+	cl, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka client: %w", err)
+	}
+	conn := &Connection{cl: cl, adm: kadm.NewClient(cl), opts: opts, config: cfg}
+	if cfg.SchemaRegistryURL != "" {
+		if conn.reg, err = newRegistry(cfg); err != nil {
+			cl.Close()
+			return nil, err
+		}
+	}
+	if err := conn.Ping(ctx); err != nil {
+		cl.Close()
+		return nil, classify(fmt.Errorf("failed to ping Kafka: %w", err))
+	}
+	return conn, nil
+}
+
+func (p *Plugin) TestConnection(ctx context.Context, config sdk.ConnectionConfig) (*sdk.ConnectionTestResult, error) {
+	return pluginsdk.TestConnection(ctx, p, config)
+}
+
+// clientOptions are the options every client of cfg's cluster is built
+// with. TLS is layered on the configured dialer, since kgo takes either a
+// dialer or a TLS config but not both.
+func clientOptions(cfg *Config) []kgo.Opt {
+	brokers := make([]string, len(cfg.Brokers))
+	for i, b := range cfg.Brokers {
+		brokers[i] = strings.TrimSpace(b)
+	}
+	dialer := cfg.Dialer(defaultConnectTimeout)
+	dial := dialer.DialContext
+	if cfg.TLS {
+		dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, network, address)
+			if err != nil {
+				return nil, err
+			}
+			host, _, _ := net.SplitHostPort(address)
+			tc := tls.Client(conn, &tls.Config{MinVersion: tls.VersionTLS12, ServerName: host})
+			if err := tc.HandshakeContext(ctx); err != nil {
+				_ = conn.Close()
+				return nil, err
+			}
+			return tc, nil
+		}
+	}
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(brokers...),
+		kgo.ClientID(cfg.ClientID),
+		kgo.Dialer(dial),
+	}
+	switch cfg.SASLMechanism {
+	case SASLPlain:
+		opts = append(opts, kgo.SASL(plain.Auth{User: cfg.Username, Pass: cfg.Password}.AsMechanism()))
+	case SASLScramSHA256:
+		opts = append(opts, kgo.SASL(scram.Auth{User: cfg.Username, Pass: cfg.Password}.AsSha256Mechanism()))
+	case SASLScramSHA512:
+		opts = append(opts, kgo.SASL(scram.Auth{User: cfg.Username, Pass: cfg.Password}.AsSha512Mechanism()))
+	}
+	return opts
+}
+
+// Connection is an active Kafka client. Queries consume through clients
+// of their own, so concurrent reads never share partition assignments.
+type Connection struct {
+	cl     *kgo.Client
+	adm    *kadm.Client
+	opts   []kgo.Opt
+	config *Config
+	// reg is nil without a schema registry.
+	reg *registry
+
+	metrics pluginsdk.QueryMetrics
+}
+
+// Query reads the messages query selects; see request for its syntax.
+func (c *Connection) Query(ctx context.Context, query string, params ...any) (*sdk.QueryResult, error) {
+	defer c.metrics.Track()()
+	start := time.Now()
+	if len(params) > 0 {
+		return nil, fmt.Errorf("query parameters are not supported")
+	}
+	req, err := parseRequest(query, start)
+	if err != nil {
+		return nil, &sdk.QueryError{Code: sdk.ErrCodeSyntaxError, Err: err}
+	}
+
+	low, high, err := c.offsets(ctx, req.Topic)
+	if err != nil {
+		return nil, classify(err)
+	}
+	var fromTime, toTime map[int32]int64
+	if req.From.kind == posTime {
+		if fromTime, err = c.offsetsAfter(ctx, req.Topic, req.From.time); err != nil {
+			return nil, classify(err)
+		}
+	}
+	if req.To.kind == posTime {
+		if toTime, err = c.offsetsAfter(ctx, req.Topic, req.To.time); err != nil {
+			return nil, classify(err)
+		}
+	}
+	records, err := c.consume(ctx, req.Topic, req.spans(low, high, fromTime, toTime))
+	if err != nil {
+		return nil, classify(err)
+	}
+
+	// Each partition contributed up to MaxMessages; keep the oldest, or
+	// the newest when reading the latest messages, across partitions.
+	slices.SortFunc(records, compareRecords)
+	if n := len(records) - req.MaxMessages; n > 0 {
+		if req.From.kind == posLatest {
+			records = records[n:]
+		} else {
+			records = records[:req.MaxMessages]
+		}
+	}
+	columns, rows := c.rows(ctx, req.Topic, records)
+	return pluginsdk.TableResult(columns, rows, time.Since(start)), nil
+}
+
+func compareRecords(a, b *kgo.Record) int {
+	if c := a.Timestamp.Compare(b.Timestamp); c != 0 {
+		return c
+	}
+	if a.Partition != b.Partition {
+		return int(a.Partition - b.Partition)
+	}
+	return int(a.Offset - b.Offset)
+}
+
+// offsets returns the log start and end offset of every partition of
+// topic.
+func (c *Connection) offsets(ctx context.Context, topic string) (low, high map[int32]int64, err error) {
+	starts, err := c.adm.ListStartOffsets(ctx, topic)
+	if err != nil {
+		return nil, nil, err
+	}
+	ends, err := c.adm.ListEndOffsets(ctx, topic)
+	if err != nil {
+		return nil, nil, err
+	}
+	if low, err = partitionOffsets(starts, topic); err != nil {
+		return nil, nil, err
+	}
+	if high, err = partitionOffsets(ends, topic); err != nil {
+		return nil, nil, err
+	}
+	return low, high, nil
+}
+
+// offsetsAfter returns the first offset at or after t of every partition
+// of topic, or its end offset when there is none.
+func (c *Connection) offsetsAfter(ctx context.Context, topic string, t time.Time) (map[int32]int64, error) {
+	listed, err := c.adm.ListOffsetsAfterMilli(ctx, t.UnixMilli(), topic)
+	if err != nil {
+		return nil, err
+	}
+	return partitionOffsets(listed, topic)
+}
+
+func partitionOffsets(listed kadm.ListedOffsets, topic string) (map[int32]int64, error) {
+	if err := listed.Error(); err != nil {
+		return nil, fmt.Errorf("topic %s: %w", topic, err)
+	}
+	out := map[int32]int64{}
+	for p, o := range listed[topic] {
+		out[p] = o.Offset
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("topic %s has no partitions", topic)
+	}
+	return out, nil
+}
+
+// consume reads spans of topic with a client of its own, stopping at each
+// span's end, when the messages stop arriving or when the query's memory
+// budget runs out.
+func (c *Connection) consume(ctx context.Context, topic string, spans []span) ([]*kgo.Record, error) {
+	if len(spans) == 0 {
+		return nil, nil
+	}
+	starts := map[int32]kgo.Offset{}
+	ends := map[int32]int64{}
+	for _, s := range spans {
+		starts[s.partition] = kgo.NewOffset().At(s.start)
+		ends[s.partition] = s.end
+	}
+	cl, err := kgo.NewClient(append(slices.Clone(c.opts),
+		kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{topic: starts}),
+		kgo.FetchMaxWait(500*time.Millisecond),
+	)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka consumer: %w", err)
+	}
+	defer cl.Close()
+
+	var records []*kgo.Record
+	budget := pluginsdk.NewRowBudget(ctx)
+	for len(ends) > 0 {
+		pollCtx, cancel := context.WithTimeout(ctx, idleTimeout)
+		fetches := cl.PollFetches(pollCtx)
+		idle := pollCtx.Err() != nil
+		cancel()
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for _, fe := range fetches.Errors() {
+			if !errors.Is(fe.Err, context.DeadlineExceeded) && !errors.Is(fe.Err, context.Canceled) {
+				return nil, fe.Err
+			}
+		}
+		received := 0
+		var err error
+		fetches.EachRecord(func(r *kgo.Record) {
+			end, ok := ends[r.Partition]
+			if !ok || err != nil {
+				return
+			}
+			if r.Offset >= end-1 {
+				delete(ends, r.Partition)
+			}
+			if r.Offset >= end {
+				return
+			}
+			if err = budget.Add([]any{r.Key, r.Value}); err == nil {
+				records = append(records, r)
+				received++
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		if received == 0 && idle {
+			break
+		}
+	}
+	return records, nil
+}
+
+// baseColumns are the columns every message has, ahead of its value.
+var baseColumns = []sdk.ColumnInfo{
+	{Name: "partition", Type: "INT32"},
+	{Name: "offset", Type: "INT64"},
+	{Name: "timestamp", Type: "TIMESTAMP"},
+}
+
+// rows renders records as rows: the base columns, the key, then the value
+// spread over one column per field when it is an object, and the headers
+// when any record has some. Fields come from the topic's latest value
+// schema when the registry has one, else from the values themselves in
+// name order.
+func (c *Connection) rows(ctx context.Context, topic string, records []*kgo.Record) ([]sdk.ColumnInfo, [][]any) {
+	keyCol, valueSchema := c.topicSchemas(ctx, topic)
+	keys := make([]any, len(records))
+	values := make([]any, len(records))
+	hasHeaders := false
+	for i, r := range records {
+		keys[i] = decode(ctx, c.reg, r.Key)
+		values[i] = decode(ctx, c.reg, r.Value)
+		hasHeaders = hasHeaders || len(r.Headers) > 0
+	}
+
+	var fields []sdk.ColumnInfo
+	plainValue := false
+	if valueSchema != nil && valueSchema.record {
+		fields = valueSchema.columns
+	} else {
+		seen := map[string]bool{}
+		for _, v := range values {
+			obj, ok := v.(map[string]any)
+			if !ok {
+				plainValue = plainValue || v != nil
+				continue
+			}
+			var added []sdk.ColumnInfo
+			for name, fv := range obj {
+				if !seen[name] {
+					seen[name] = true
+					added = append(added, sdk.ColumnInfo{Name: name, Type: valueType(fv), Nullable: true})
+				}
+			}
+			slices.SortFunc(added, func(a, b sdk.ColumnInfo) int { return strings.Compare(a.Name, b.Name) })
+			fields = append(fields, added...)
+		}
+		if len(fields) == 0 {
+			plainValue = true
+		}
+	}
+
+	columns := append(slices.Clone(baseColumns), keyCol)
+	for _, f := range fields {
+		if slices.ContainsFunc(columns, func(c sdk.ColumnInfo) bool { return c.Name == f.Name }) {
+			f.Name = "value." + f.Name
+		}
+		columns = append(columns, f)
+	}
+	if plainValue {
+		typ := "STRING"
+		if valueSchema != nil {
+			typ = valueSchema.columns[0].Type
+		}
+		columns = append(columns, sdk.ColumnInfo{Name: "value", Type: typ, Nullable: true})
+	}
+	if hasHeaders {
+		columns = append(columns, sdk.ColumnInfo{Name: "headers", Type: "JSON", Nullable: true})
+	}
+
+	rows := make([][]any, len(records))
+	for i, r := range records {
+		row := []any{r.Partition, r.Offset, r.Timestamp.UTC(), keys[i]}
+		obj, isObj := values[i].(map[string]any)
+		for _, f := range fields {
+			row = append(row, obj[f.Name])
+		}
+		if plainValue {
+			if isObj && len(fields) > 0 {
+				row = append(row, nil)
+			} else {
+				row = append(row, values[i])
+			}
+		}
+		if hasHeaders {
+			headers := map[string]string{}
+			for _, h := range r.Headers {
+				headers[h.Key] = string(h.Value)
+			}
+			row = append(row, headers)
+		}
+		rows[i] = row
+	}
+	return columns, rows
+}
+
+// topicSchemas returns the key column of topic and its latest value
+// schema, if the registry has one. Registry errors are ignored: messages
+// are still shown, decoded without it.
+func (c *Connection) topicSchemas(ctx context.Context, topic string) (sdk.ColumnInfo, *schema) {
+	key := sdk.ColumnInfo{Name: "key", Type: "STRING", Nullable: true}
+	if c.reg == nil {
+		return key, nil
+	}
+	if s, err := c.reg.latest(ctx, topic+"-key"); err == nil && s != nil && !s.record {
+		key.Type = s.columns[0].Type
+	}
+	value, err := c.reg.latest(ctx, topic+"-value")
+	if err != nil {
+		return key, nil
+	}
+	return key, value
+}
+
+// valueType names the type of a decoded JSON value.
+func valueType(v any) string {
+	switch v.(type) {
+	case int64:
+		return "INT64"
+	case float64:
+		return "DOUBLE"
+	case bool:
+		return "BOOLEAN"
+	case string:
+		return "STRING"
+	}
+	return "JSON"
+}
+
+// GetSchema lists every topic under a single "topics" database.
+func (c *Connection) GetSchema(ctx context.Context) (*sdk.SchemaInfo, error) {
+	tables, err := c.GetTables(ctx, topicsDatabase)
+	if err != nil {
+		return nil, err
+	}
+	return &sdk.SchemaInfo{Databases: []sdk.DatabaseInfo{{Name: topicsDatabase, Tables: tables}}}, nil
+}
+
+// GetTables lists the topics, internal ones only when the config asks,
+// with the number of messages they retain as the row count.
+func (c *Connection) GetTables(ctx context.Context, database string) ([]sdk.TableInfo, error) {
+	if database != "" && database != topicsDatabase {
+		return nil, &sdk.QueryError{Code: sdk.ErrCodeUnknownDatabase,
+			Err: fmt.Errorf("database %q does not exist; topics are listed under %q", database, topicsDatabase)}
+	}
+	list := c.adm.ListTopics
+	if c.config.ShowInternal {
+		list = c.adm.ListTopicsWithInternal
+	}
+	topics, err := list(ctx)
+	if err != nil {
+		return nil, classify(fmt.Errorf("failed to list topics: %w", err))
+	}
+	names := topics.Names()
+	starts, err := c.adm.ListStartOffsets(ctx, names...)
+	if err != nil {
+		return nil, classify(fmt.Errorf("failed to list offsets: %w", err))
+	}
+	ends, err := c.adm.ListEndOffsets(ctx, names...)
+	if err != nil {
+		return nil, classify(fmt.Errorf("failed to list offsets: %w", err))
+	}
+
+	tables := make([]sdk.TableInfo, 0, len(names))
+	for _, name := range names {
+		var messages int64
+		for p, end := range ends[name] {
+			if start, ok := starts.Lookup(name, p); ok && end.Err == nil && start.Err == nil {
+				messages += end.Offset - start.Offset
+			}
+		}
+		key, value := c.topicSchemas(ctx, name)
+		columns := append(slices.Clone(baseColumns), key)
+		if value != nil {
+			for _, f := range value.columns {
+				if slices.ContainsFunc(columns, func(c sdk.ColumnInfo) bool { return c.Name == f.Name }) {
+					f.Name = "value." + f.Name
+				}
+				columns = append(columns, f)
+			}
+		} else {
+			columns = append(columns, sdk.ColumnInfo{Name: "value", Type: "STRING", Nullable: true})
+		}
+		tables = append(tables, sdk.TableInfo{
+			Name:        name,
+			Type:        "topic",
+			Columns:     columns,
+			RowCount:    &messages,
+			Description: fmt.Sprintf("%d partitions", len(topics[name].Partitions)),
+		})
+	}
+	return tables, nil
+}
+
+func (c *Connection) Close() error {
+	c.cl.Close()
+	return nil
+}
+
+func (c *Connection) Ping(ctx context.Context) error {
+	return c.cl.Ping(ctx)
+}
+
+func (c *Connection) GetMetrics() sdk.ConnectionMetrics {
+	metrics := sdk.ConnectionMetrics{
+		OpenConnections: 1,
+		LastActivity:    time.Now(),
+	}
+	c.metrics.Fill(&metrics)
+	return metrics
+}
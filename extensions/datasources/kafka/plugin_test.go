@@ -0,0 +1,344 @@
+package kafka
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hamba/avro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/redpanda"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sr"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"data-voyager/sdk"
+)
+
+const orderSchema = `{"type": "record", "name": "Order", "fields": [
+	{"name": "id", "type": "long"},
+	{"name": "customer", "type": ["null", "string"]},
+	{"name": "total", "type": {"type": "bytes", "logicalType": "decimal", "precision": 10, "scale": 2}},
+	{"name": "placed_at", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+	{"name": "offset", "type": "int"}
+]}`
+
+// framed prefixes payload with the registry wire header naming id.
+func framed(id int, payload []byte) []byte {
+	out := []byte{wireMagic, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(out[1:], uint32(id))
+	return append(out, payload...)
+}
+
+func TestKafkaPlugin(t *testing.T) {
+	ctx := context.Background()
+
+	container, err := redpanda.Run(ctx, "docker.redpanda.com/redpandadata/redpanda:v24.1.7", redpanda.WithAutoCreateTopics())
+	require.NoError(t, err)
+	defer func() {
+		if err := testcontainers.TerminateContainer(container); err != nil {
+			t.Logf("failed to terminate container: %s", err)
+		}
+	}()
+	broker, err := container.KafkaSeedBroker(ctx)
+	require.NoError(t, err)
+	registryURL, err := container.SchemaRegistryAddress(ctx)
+	require.NoError(t, err)
+
+	src, err := sr.NewClient(sr.URLs(registryURL))
+	require.NoError(t, err)
+	registered, err := src.CreateSchema(ctx, "orders-value", sr.Schema{Schema: orderSchema, Type: sr.TypeAvro})
+	require.NoError(t, err)
+
+	producer, err := kgo.NewClient(kgo.SeedBrokers(broker), kgo.DefaultProduceTopic("orders"))
+	require.NoError(t, err)
+	defer producer.Close()
+	avroSchema := avro.MustParse(orderSchema)
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := range 5 {
+		payload, err := avro.Marshal(avroSchema, map[string]any{
+			"id": int64(i), "customer": fmt.Sprintf("c%d", i), "total": big.NewRat(int64(1250+i), 100),
+			"placed_at": base.Add(time.Duration(i) * time.Minute), "offset": int32(i * 10),
+		})
+		require.NoError(t, err)
+		require.NoError(t, producer.ProduceSync(ctx, &kgo.Record{
+			Key: fmt.Appendf(nil, "order-%d", i), Value: framed(registered.ID, payload), Timestamp: base.Add(time.Duration(i) * time.Second),
+		}).FirstErr())
+	}
+	require.NoError(t, producer.ProduceSync(ctx,
+		&kgo.Record{Topic: "events", Value: []byte(`{"kind": "click", "n": 1}`)},
+		&kgo.Record{Topic: "events", Value: []byte(`{"kind": "view", "page": "/"}`), Headers: []kgo.RecordHeader{{Key: "source", Value: []byte("web")}}},
+		&kgo.Record{Topic: "events", Value: []byte("plain text")},
+	).FirstErr())
+
+	plugin := &Plugin{}
+	assert.Equal(t, Type, plugin.GetType())
+	assert.Equal(t, "Kafka", plugin.GetName())
+
+	config := &Config{Brokers: []string{broker}, SchemaRegistryURL: registryURL}
+	res, err := plugin.TestConnection(ctx, config)
+	require.NoError(t, err)
+	assert.True(t, res.IsConnected, res.Message)
+
+	conn, err := plugin.Connect(ctx, config)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	t.Run("GetTables", func(t *testing.T) {
+		tables, err := conn.GetTables(ctx, topicsDatabase)
+		require.NoError(t, err)
+		byName := map[string]sdk.TableInfo{}
+		for _, tbl := range tables {
+			byName[tbl.Name] = tbl
+		}
+		orders, ok := byName["orders"]
+		require.True(t, ok)
+		assert.Equal(t, "topic", orders.Type)
+		require.NotNil(t, orders.RowCount)
+		assert.EqualValues(t, 5, *orders.RowCount)
+		var names []string
+		for _, c := range orders.Columns {
+			names = append(names, c.Name+" "+c.Type)
+		}
+		assert.Equal(t, []string{"partition INT32", "offset INT64", "timestamp TIMESTAMP", "key STRING",
+			"id INT64", "customer STRING", "total DECIMAL(10,2)", "placed_at TIMESTAMP", "value.offset INT32"}, names)
+		assert.Contains(t, byName, "events")
+		assert.NotContains(t, byName, "_schemas", "internal topics are hidden")
+
+		_, err = conn.GetTables(ctx, "other")
+		var qe *sdk.QueryError
+		require.ErrorAs(t, err, &qe)
+		assert.Equal(t, sdk.ErrCodeUnknownDatabase, qe.Code)
+	})
+
+	t.Run("QueryAvro", func(t *testing.T) {
+		res, err := conn.Query(ctx, `{"topic": "orders", "from": "earliest", "max_messages": 3}`)
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, res.Stats.RowsReturned)
+		fields := res.Frames[0].Fields
+		assert.Equal(t, "value.offset", fields[8].Name)
+		assert.Equal(t, "order-0", fields[3].Values[0])
+		assert.EqualValues(t, 0, fields[4].Values[0])
+		assert.Equal(t, "c0", fields[5].Values[0])
+		assert.Equal(t, "12.50", fields[6].Values[0])
+		assert.Equal(t, base, fields[7].Values[0])
+		assert.EqualValues(t, 20, fields[8].Values[2])
+
+		res, err = conn.Query(ctx, "orders")
+		require.NoError(t, err)
+		assert.EqualValues(t, 5, res.Stats.RowsReturned)
+
+		res, err = conn.Query(ctx, `{"topic": "orders", "from": 1, "to": 3}`)
+		require.NoError(t, err)
+		assert.Equal(t, []any{"order-1", "order-2"}, res.Frames[0].Fields[3].Values)
+	})
+
+	t.Run("QueryInferred", func(t *testing.T) {
+		res, err := conn.Query(ctx, `{"topic": "events", "from": "earliest"}`)
+		require.NoError(t, err)
+		fields := res.Frames[0].Fields
+		var names []string
+		for _, f := range fields {
+			names = append(names, f.Name)
+		}
+		assert.Equal(t, []string{"partition", "offset", "timestamp", "key", "kind", "n", "page", "value", "headers"}, names)
+		assert.Equal(t, []any{"click", "view", nil}, fields[4].Values)
+		assert.EqualValues(t, 1, fields[5].Values[0])
+		assert.Equal(t, map[string]string{"source": "web"}, fields[8].Values[1])
+		assert.Equal(t, "plain text", fields[7].Values[2])
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := conn.Query(ctx, `{"topic": "orders", "limit": 3}`)
+		var qe *sdk.QueryError
+		require.ErrorAs(t, err, &qe)
+		assert.Equal(t, sdk.ErrCodeSyntaxError, qe.Code)
+
+		_, err = conn.Query(ctx, "missing")
+		require.ErrorAs(t, err, &qe)
+		assert.Equal(t, sdk.ErrCodeUndefinedObject, qe.Code)
+	})
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := &Config{Brokers: []string{"localhost:9092", " b2:9093"}, SASLMechanism: "scram-sha-512", Username: "u"}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, SASLScramSHA512, cfg.SASLMechanism)
+	assert.Equal(t, "data-voyager", cfg.ClientID)
+	assert.Equal(t, "kafka://localhost:9092,b2:9093", cfg.GetConnectionString())
+
+	for name, cfg := range map[string]*Config{
+		"no brokers":   {},
+		"no port":      {Brokers: []string{"localhost"}},
+		"no username":  {Brokers: []string{"k:9092"}, SASLMechanism: "PLAIN"},
+		"bad sasl":     {Brokers: []string{"k:9092"}, SASLMechanism: "GSSAPI", Username: "u"},
+		"bad registry": {Brokers: []string{"k:9092"}, SchemaRegistryURL: "registry:8081"},
+	} {
+		assert.Error(t, cfg.Validate(), name)
+	}
+}
+
+func TestParseRequest(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	req, err := parseRequest(" orders ", now)
+	require.NoError(t, err)
+	assert.Equal(t, &request{Topic: "orders", From: position{kind: posLatest}, To: position{kind: posLatest}, MaxMessages: DefaultMessages}, req)
+
+	req, err = parseRequest(`{"topic": "orders", "partitions": [2, 0, 2], "from": "-15m", "to": "2026-05-01T11:50:00Z", "max_messages": 10}`, now)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{0, 2}, req.Partitions)
+	assert.Equal(t, position{kind: posTime, time: now.Add(-15 * time.Minute)}, req.From)
+	assert.Equal(t, position{kind: posTime, time: now.Add(-10 * time.Minute)}, req.To)
+	assert.Equal(t, 10, req.MaxMessages)
+
+	req, err = parseRequest(`{"topic": "orders", "from": 42, "to": "now"}`, now)
+	require.NoError(t, err)
+	assert.Equal(t, position{kind: posOffset, offset: 42}, req.From)
+	assert.Equal(t, position{kind: posLatest}, req.To)
+
+	for _, q := range []string{
+		"",
+		"SELECT * FROM orders",
+		`{"from": "earliest"}`,
+		`{"topic": "orders", "limit": 5}`,
+		`{"topic": "orders", "max_messages": 10001}`,
+		`{"topic": "orders", "partitions": [-1]}`,
+		`{"topic": "orders", "from": -3}`,
+		`{"topic": "orders", "from": "yesterday"}`,
+		`{"topic": "orders", "to": "earliest"}`,
+	} {
+		_, err := parseRequest(q, now)
+		assert.Error(t, err, q)
+	}
+}
+
+func TestSpans(t *testing.T) {
+	low := map[int32]int64{0: 0, 1: 50, 2: 0}
+	high := map[int32]int64{0: 100, 1: 60, 2: 0}
+
+	latest := &request{From: position{kind: posLatest}, To: position{kind: posLatest}, MaxMessages: 20}
+	assert.Equal(t, []span{{0, 80, 100}, {1, 50, 60}}, latest.spans(low, high, nil, nil))
+
+	earliest := &request{Partitions: []int32{0}, From: position{kind: posEarliest}, To: position{kind: posOffset, offset: 5}, MaxMessages: 20}
+	assert.Equal(t, []span{{0, 0, 5}}, earliest.spans(low, high, nil, nil))
+
+	byTime := &request{From: position{kind: posTime}, To: position{kind: posTime}, MaxMessages: 20}
+	from := map[int32]int64{0: 10, 1: 60, 2: 0}
+	to := map[int32]int64{0: 90, 1: 60, 2: 0}
+	assert.Equal(t, []span{{0, 10, 30}}, byTime.spans(low, high, from, to))
+
+	offset := &request{From: position{kind: posOffset, offset: 40}, To: position{kind: posLatest}, MaxMessages: 20}
+	assert.Equal(t, []span{{0, 40, 60}, {1, 50, 60}}, offset.spans(low, high, nil, nil))
+}
+
+func TestParseSchema(t *testing.T) {
+	s, err := parseSchema(sr.Schema{Type: sr.TypeAvro, Schema: orderSchema})
+	require.NoError(t, err)
+	assert.True(t, s.record)
+	assert.Equal(t, []sdk.ColumnInfo{
+		{Name: "id", Type: "INT64"},
+		{Name: "customer", Type: "STRING", Nullable: true},
+		{Name: "total", Type: "DECIMAL(10,2)"},
+		{Name: "placed_at", Type: "TIMESTAMP"},
+		{Name: "offset", Type: "INT32"},
+	}, s.columns)
+
+	s, err = parseSchema(sr.Schema{Type: sr.TypeAvro, Schema: `{"type": "array", "items": "double"}`})
+	require.NoError(t, err)
+	assert.False(t, s.record)
+	assert.Equal(t, []sdk.ColumnInfo{{Name: "value", Type: "ARRAY(DOUBLE)"}}, s.columns)
+
+	s, err = parseSchema(sr.Schema{Type: sr.TypeJSON, Schema: `{"type": "object", "required": ["z"], "properties": {
+		"z": {"type": "integer"},
+		"a": {"type": ["string", "null"], "format": "date-time"},
+		"m": {"type": "array", "items": {"type": "number"}}
+	}}`})
+	require.NoError(t, err)
+	assert.Equal(t, []sdk.ColumnInfo{
+		{Name: "z", Type: "INT64"},
+		{Name: "a", Type: "TIMESTAMP", Nullable: true},
+		{Name: "m", Type: "ARRAY(DOUBLE)", Nullable: true},
+	}, s.columns, "properties keep their declared order")
+
+	s, err = parseSchema(sr.Schema{Type: sr.TypeProtobuf, Schema: `
+		syntax = "proto3";
+		import "google/protobuf/timestamp.proto";
+		message Order {
+			int64 id = 1;
+			optional string customer = 2;
+			google.protobuf.Timestamp placed_at = 3;
+			repeated Status history = 4;
+			map<string, int32> counts = 5;
+		}
+		enum Status { NEW = 0; SHIPPED = 1; }
+	`})
+	require.NoError(t, err)
+	assert.Equal(t, []sdk.ColumnInfo{
+		{Name: "id", Type: "INT64"},
+		{Name: "customer", Type: "STRING", Nullable: true},
+		{Name: "placed_at", Type: "TIMESTAMP", Nullable: true},
+		{Name: "history", Type: "ARRAY(ENUM(Status))", Nullable: true},
+		{Name: "counts", Type: "MAP(INT32)", Nullable: true},
+	}, s.columns)
+
+	_, err = parseSchema(sr.Schema{Type: sr.TypeAvro, Schema: orderSchema, References: []sr.SchemaReference{{Name: "x"}}})
+	assert.Error(t, err)
+}
+
+func TestDecode(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, decode(ctx, nil, nil))
+	assert.Equal(t, map[string]any{"n": int64(9007199254740993), "f": 1.5, "s": "x"},
+		decode(ctx, nil, []byte(`{"n": 9007199254740993, "f": 1.5, "s": "x"}`)))
+	assert.Equal(t, "hello", decode(ctx, nil, []byte("hello")))
+	assert.Equal(t, "/w==", decode(ctx, nil, []byte{0xff}))
+
+	avroOrders, err := parseSchema(sr.Schema{Type: sr.TypeAvro, Schema: orderSchema})
+	require.NoError(t, err)
+	protoOrders, err := parseSchema(sr.Schema{Type: sr.TypeProtobuf, Schema: `
+		syntax = "proto3";
+		message Order { int64 id = 1; Status status = 2; bytes raw = 3; }
+		enum Status { NEW = 0; SHIPPED = 1; }
+	`})
+	require.NoError(t, err)
+	reg := &registry{byID: map[int]*schema{1: avroOrders, 2: protoOrders}}
+
+	placed := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	payload, err := avro.Marshal(avroOrders.avro, map[string]any{
+		"id": int64(7), "customer": nil, "total": big.NewRat(1005, 100), "placed_at": placed, "offset": int32(3),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"id": int64(7), "customer": nil, "total": "10.05", "placed_at": placed, "offset": 3},
+		decode(ctx, reg, framed(1, payload)))
+
+	m := dynamicpb.NewMessage(protoOrders.proto)
+	fields := protoOrders.proto.Fields()
+	m.Set(fields.ByName("id"), protoreflect.ValueOfInt64(7))
+	m.Set(fields.ByName("status"), protoreflect.ValueOfEnum(1))
+	m.Set(fields.ByName("raw"), protoreflect.ValueOfBytes([]byte{1, 2}))
+	payload, err = proto.Marshal(m)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"id": int64(7), "status": "SHIPPED", "raw": "AQI="},
+		decode(ctx, reg, framed(2, append([]byte{0}, payload...))))
+
+	// Framed data whose schema does not apply is shown as it is.
+	assert.Equal(t, "AAAAAAH/", decode(ctx, reg, framed(1, []byte{0xff})))
+}
+
+func TestClassify(t *testing.T) {
+	var qe *sdk.QueryError
+	require.ErrorAs(t, classify(fmt.Errorf("listing: %w", kerr.TopicAuthorizationFailed)), &qe)
+	assert.Equal(t, sdk.ErrCodePermissionDenied, qe.Code)
+	require.ErrorAs(t, classify(kerr.UnknownTopicOrPartition), &qe)
+	assert.Equal(t, sdk.ErrCodeUndefinedObject, qe.Code)
+	assert.NotErrorAs(t, classify(kerr.NotLeaderForPartition), &qe)
+}
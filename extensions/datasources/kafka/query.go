@@ -0,0 +1,178 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Position kinds. Earliest and latest follow the partition's log start and
+// end; offset and time name a point in it.
+const (
+	posLatest = iota
+	posEarliest
+	posOffset
+	posTime
+)
+
+// position is where a read starts or stops in every partition it covers.
+type position struct {
+	kind   int
+	offset int64
+	time   time.Time
+}
+
+// request is a parsed query: a bounded read of one topic.
+//
+// A query is either a bare topic name, reading its latest messages, or a
+// JSON object:
+//
+//	{"topic": "orders", "partitions": [0, 1], "from": "-1h", "to": "now", "max_messages": 500}
+//
+// from and to are an offset, "earliest", "latest", an RFC 3339 time or a
+// duration back from now such as "15m". from defaults to "latest", which
+// reads the last max_messages messages; to defaults to the end of each
+// partition when the query starts, so a read never waits for new messages.
+// max_messages defaults to 100 and is capped at 10000.
+type request struct {
+	Topic       string
+	Partitions  []int32
+	From, To    position
+	MaxMessages int
+}
+
+// parseRequest parses query, resolving relative times against now.
+func parseRequest(query string, now time.Time) (*request, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("a topic is required")
+	}
+	req := &request{From: position{kind: posLatest}, To: position{kind: posLatest}, MaxMessages: DefaultMessages}
+	if !strings.HasPrefix(query, "{") {
+		if strings.ContainsAny(query, " \t\r\n;") {
+			return nil, fmt.Errorf("a query is a topic name or a JSON object")
+		}
+		req.Topic = query
+		return req, nil
+	}
+
+	var raw struct {
+		Topic       string          `json:"topic"`
+		Partitions  []int32         `json:"partitions"`
+		From        json.RawMessage `json:"from"`
+		To          json.RawMessage `json:"to"`
+		MaxMessages int             `json:"max_messages"`
+	}
+	dec := json.NewDecoder(strings.NewReader(query))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+	if raw.Topic == "" {
+		return nil, fmt.Errorf("topic is required")
+	}
+	req.Topic = raw.Topic
+	for _, p := range raw.Partitions {
+		if p < 0 {
+			return nil, fmt.Errorf("partitions must not be negative")
+		}
+	}
+	slices.Sort(raw.Partitions)
+	req.Partitions = slices.Compact(raw.Partitions)
+	switch {
+	case raw.MaxMessages < 0 || raw.MaxMessages > MaxMessages:
+		return nil, fmt.Errorf("max_messages must be between 1 and %d", MaxMessages)
+	case raw.MaxMessages > 0:
+		req.MaxMessages = raw.MaxMessages
+	}
+	var err error
+	if req.From, err = parsePosition(raw.From, now); err != nil {
+		return nil, fmt.Errorf("from: %w", err)
+	}
+	if req.To, err = parsePosition(raw.To, now); err != nil {
+		return nil, fmt.Errorf("to: %w", err)
+	}
+	if req.To.kind == posEarliest {
+		return nil, fmt.Errorf(`to must not be "earliest"`)
+	}
+	return req, nil
+}
+
+func parsePosition(raw json.RawMessage, now time.Time) (position, error) {
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return position{kind: posLatest}, nil
+	}
+	var offset int64
+	if err := json.Unmarshal(raw, &offset); err == nil {
+		if offset < 0 {
+			return position{}, fmt.Errorf("an offset must not be negative")
+		}
+		return position{kind: posOffset, offset: offset}, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return position{}, fmt.Errorf("must be an offset or a string")
+	}
+	switch s {
+	case "earliest":
+		return position{kind: posEarliest}, nil
+	case "latest", "now":
+		return position{kind: posLatest}, nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return position{kind: posTime, time: t}, nil
+	}
+	if d, err := time.ParseDuration(strings.TrimPrefix(s, "-")); err == nil && d > 0 {
+		return position{kind: posTime, time: now.Add(-d)}, nil
+	}
+	return position{}, fmt.Errorf(`%q is not an offset, "earliest", "latest", an RFC 3339 time or a duration`, s)
+}
+
+// span is the offsets [start, end) of one partition a read covers.
+type span struct {
+	partition  int32
+	start, end int64
+}
+
+// spans bounds the read of each partition between its log start (low) and
+// end (high) offsets; fromTime and toTime hold the first offset at or
+// after From and To when those are times. No more than MaxMessages are
+// read per partition: from the end when reading the latest messages, else
+// from the start.
+func (r *request) spans(low, high, fromTime, toTime map[int32]int64) []span {
+	var out []span
+	for p, hi := range high {
+		if len(r.Partitions) > 0 && !slices.Contains(r.Partitions, p) {
+			continue
+		}
+		lo := low[p]
+		end := hi
+		switch r.To.kind {
+		case posOffset:
+			end = min(end, r.To.offset)
+		case posTime:
+			end = min(end, toTime[p])
+		}
+		var start int64
+		switch r.From.kind {
+		case posEarliest:
+			start = lo
+		case posLatest:
+			start = end - int64(r.MaxMessages)
+		case posOffset:
+			start = r.From.offset
+		case posTime:
+			start = fromTime[p]
+		}
+		start = max(start, lo)
+		end = min(end, start+int64(r.MaxMessages))
+		if start < end {
+			out = append(out, span{partition: p, start: start, end: end})
+		}
+	}
+	slices.SortFunc(out, func(a, b span) int { return int(a.partition - b.partition) })
+	return out
+}
@@ -0,0 +1,392 @@
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/hamba/avro/v2"
+	"github.com/twmb/franz-go/pkg/sr"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"data-voyager/sdk"
+)
+
+// schema is a registry schema parsed once for both column listing and
+// message decoding. Exactly one of avro, proto and json is set.
+type schema struct {
+	typ     sr.SchemaType
+	avro    avro.Schema
+	proto   protoreflect.MessageDescriptor
+	json    bool
+	columns []sdk.ColumnInfo
+	// record is true when the schema describes an object whose fields
+	// become columns of their own, rather than a single value.
+	record bool
+}
+
+// registry reads schemas from a schema registry, caching them by ID since
+// an ID always names the same schema.
+type registry struct {
+	cl *sr.Client
+
+	mu   sync.Mutex
+	byID map[int]*schema
+}
+
+func newRegistry(cfg *Config) (*registry, error) {
+	opts := []sr.ClientOpt{sr.URLs(cfg.SchemaRegistryURL)}
+	if cfg.SchemaRegistryUsername != "" {
+		opts = append(opts, sr.BasicAuth(cfg.SchemaRegistryUsername, cfg.SchemaRegistryPassword))
+	}
+	cl, err := sr.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema registry: %w", err)
+	}
+	return &registry{cl: cl, byID: map[int]*schema{}}, nil
+}
+
+// latest returns the latest schema of subject, or nil when the subject
+// does not exist.
+func (r *registry) latest(ctx context.Context, subject string) (*schema, error) {
+	ss, err := r.cl.SchemaByVersion(ctx, subject, -1)
+	if err != nil {
+		var re *sr.ResponseError
+		if errors.As(err, &re) && sr.IsNotFoundError(re.ErrorCode) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read schema %s: %w", subject, err)
+	}
+	return r.parse(ss.ID, ss.Schema)
+}
+
+// schema returns the schema with id.
+func (r *registry) schema(ctx context.Context, id int) (*schema, error) {
+	r.mu.Lock()
+	s, ok := r.byID[id]
+	r.mu.Unlock()
+	if ok {
+		return s, nil
+	}
+	raw, err := r.cl.SchemaByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema %d: %w", id, err)
+	}
+	return r.parse(id, raw)
+}
+
+func (r *registry) parse(id int, raw sr.Schema) (*schema, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.byID[id]; ok {
+		return s, nil
+	}
+	s, err := parseSchema(raw)
+	if err != nil {
+		return nil, fmt.Errorf("schema %d: %w", id, err)
+	}
+	r.byID[id] = s
+	return s, nil
+}
+
+// parseSchema parses a registry schema. Schemas referencing other subjects
+// are not supported.
+func parseSchema(raw sr.Schema) (*schema, error) {
+	if len(raw.References) > 0 {
+		return nil, fmt.Errorf("schema references are not supported")
+	}
+	s := &schema{typ: raw.Type}
+	switch raw.Type {
+	case sr.TypeAvro:
+		parsed, err := avro.Parse(raw.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Avro schema: %w", err)
+		}
+		s.avro = parsed
+		if rec, ok := parsed.(*avro.RecordSchema); ok {
+			s.record = true
+			for _, f := range rec.Fields() {
+				typ, nullable := avroType(f.Type())
+				s.columns = append(s.columns, sdk.ColumnInfo{Name: f.Name(), Type: typ, Nullable: nullable})
+			}
+		} else {
+			typ, nullable := avroType(parsed)
+			s.columns = []sdk.ColumnInfo{{Name: "value", Type: typ, Nullable: nullable}}
+		}
+	case sr.TypeJSON:
+		s.json = true
+		cols, err := jsonSchemaColumns([]byte(raw.Schema))
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON schema: %w", err)
+		}
+		s.record = cols != nil
+		s.columns = cols
+		if cols == nil {
+			s.columns = []sdk.ColumnInfo{{Name: "value", Type: "JSON", Nullable: true}}
+		}
+	case sr.TypeProtobuf:
+		md, err := protoMessage(raw.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Protobuf schema: %w", err)
+		}
+		s.proto = md
+		s.record = true
+		fields := md.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			f := fields.Get(i)
+			s.columns = append(s.columns, sdk.ColumnInfo{Name: string(f.Name()), Type: protoType(f),
+				Nullable: f.HasPresence() || f.IsList() || f.IsMap()})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported schema type %s", raw.Type)
+	}
+	return s, nil
+}
+
+// avroType names an Avro type the way sdk.InferFieldKind understands. A
+// union with null is its other branch, nullable.
+func avroType(s avro.Schema) (string, bool) {
+	switch t := s.(type) {
+	case *avro.UnionSchema:
+		var branches []avro.Schema
+		nullable := false
+		for _, b := range t.Types() {
+			if b.Type() == avro.Null {
+				nullable = true
+				continue
+			}
+			branches = append(branches, b)
+		}
+		if len(branches) == 1 {
+			typ, _ := avroType(branches[0])
+			return typ, nullable
+		}
+		return "UNION", nullable
+	case *avro.PrimitiveSchema:
+		if l := t.Logical(); l != nil {
+			switch l.Type() {
+			case avro.TimestampMillis, avro.TimestampMicros, avro.LocalTimestampMillis, avro.LocalTimestampMicros:
+				return "TIMESTAMP", false
+			case avro.Date:
+				return "DATE", false
+			case avro.TimeMillis, avro.TimeMicros:
+				return "TIME", false
+			case avro.Decimal:
+				if d, ok := l.(*avro.DecimalLogicalSchema); ok {
+					return fmt.Sprintf("DECIMAL(%d,%d)", d.Precision(), d.Scale()), false
+				}
+				return "DECIMAL", false
+			case avro.UUID:
+				return "UUID", false
+			}
+		}
+		switch t.Type() {
+		case avro.Null:
+			return "NULL", true
+		case avro.Boolean:
+			return "BOOLEAN", false
+		case avro.Int:
+			return "INT32", false
+		case avro.Long:
+			return "INT64", false
+		case avro.Float:
+			return "FLOAT32", false
+		case avro.Double:
+			return "DOUBLE", false
+		case avro.Bytes:
+			return "BYTES", false
+		}
+		return "STRING", false
+	case *avro.ArraySchema:
+		items, _ := avroType(t.Items())
+		return "ARRAY(" + items + ")", false
+	case *avro.MapSchema:
+		values, _ := avroType(t.Values())
+		return "MAP(" + values + ")", false
+	case *avro.RecordSchema:
+		return "RECORD(" + t.Name() + ")", false
+	case *avro.EnumSchema:
+		return "ENUM(" + t.Name() + ")", false
+	case *avro.FixedSchema:
+		return "FIXED(" + t.Name() + ")", false
+	case *avro.RefSchema:
+		return avroType(t.Schema())
+	}
+	return "STRING", false
+}
+
+// jsonSchema is the part of a JSON Schema that columns come from.
+type jsonSchema struct {
+	Type       json.RawMessage `json:"type"`
+	Format     string          `json:"format"`
+	Properties json.RawMessage `json:"properties"`
+	Required   []string        `json:"required"`
+	Items      *jsonSchema     `json:"items"`
+}
+
+// jsonSchemaColumns returns a column per property of an object schema, in
+// the order the schema declares them, or nil for any other schema.
+func jsonSchemaColumns(data []byte) ([]sdk.ColumnInfo, error) {
+	var root jsonSchema
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	if len(root.Properties) == 0 {
+		return nil, nil
+	}
+	names, props, err := orderedObject(root.Properties)
+	if err != nil {
+		return nil, err
+	}
+	cols := make([]sdk.ColumnInfo, len(names))
+	for i, name := range names {
+		var prop jsonSchema
+		if err := json.Unmarshal(props[i], &prop); err != nil {
+			return nil, fmt.Errorf("property %s: %w", name, err)
+		}
+		typ, nullable := jsonType(&prop)
+		required := false
+		for _, r := range root.Required {
+			required = required || r == name
+		}
+		cols[i] = sdk.ColumnInfo{Name: name, Type: typ, Nullable: nullable || !required}
+	}
+	return cols, nil
+}
+
+// jsonType names a JSON Schema type; a list of types including "null" is
+// its other type, nullable.
+func jsonType(s *jsonSchema) (string, bool) {
+	var types []string
+	var one string
+	if json.Unmarshal(s.Type, &one) == nil {
+		types = []string{one}
+	} else {
+		_ = json.Unmarshal(s.Type, &types)
+	}
+	nullable := false
+	var typ string
+	for _, t := range types {
+		if t == "null" {
+			nullable = true
+		} else if typ == "" {
+			typ = t
+		} else {
+			typ = "json"
+		}
+	}
+	switch typ {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			return "TIMESTAMP", nullable
+		case "date":
+			return "DATE", nullable
+		}
+		return "STRING", nullable
+	case "integer":
+		return "INT64", nullable
+	case "number":
+		return "DOUBLE", nullable
+	case "boolean":
+		return "BOOLEAN", nullable
+	case "array":
+		items := "JSON"
+		if s.Items != nil {
+			items, _ = jsonType(s.Items)
+		}
+		return "ARRAY(" + items + ")", nullable
+	case "object":
+		return "OBJECT", nullable
+	}
+	return "JSON", nullable
+}
+
+// orderedObject returns the keys and values of a JSON object in document
+// order, which a map would lose.
+func orderedObject(data []byte) ([]string, []json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, nil, fmt.Errorf("expected an object")
+	}
+	var keys []string
+	var values []json.RawMessage
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, tok.(string))
+		values = append(values, v)
+	}
+	return keys, values, nil
+}
+
+// protoMessage compiles a .proto schema and returns its first message,
+// the one a registry message index of [0] names. Well-known types such as
+// google/protobuf/timestamp.proto may be imported.
+func protoMessage(src string) (protoreflect.MessageDescriptor, error) {
+	const name = "schema.proto"
+	c := protocompile.Compiler{Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+		Accessor: protocompile.SourceAccessorFromMap(map[string]string{name: src}),
+	})}
+	files, err := c.Compile(context.Background(), name)
+	if err != nil {
+		return nil, err
+	}
+	msgs := files[0].Messages()
+	if msgs.Len() == 0 {
+		return nil, fmt.Errorf("no message is declared")
+	}
+	return msgs.Get(0), nil
+}
+
+// protoType names a Protobuf field type the way sdk.InferFieldKind
+// understands.
+func protoType(f protoreflect.FieldDescriptor) string {
+	if f.IsMap() {
+		return "MAP(" + protoScalar(f.MapValue()) + ")"
+	}
+	if f.IsList() {
+		return "ARRAY(" + protoScalar(f) + ")"
+	}
+	return protoScalar(f)
+}
+
+func protoScalar(f protoreflect.FieldDescriptor) string {
+	switch f.Kind() {
+	case protoreflect.BoolKind:
+		return "BOOLEAN"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return "INT32"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return "INT64"
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return "UINT32"
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "UINT64"
+	case protoreflect.FloatKind:
+		return "FLOAT32"
+	case protoreflect.DoubleKind:
+		return "DOUBLE"
+	case protoreflect.StringKind:
+		return "STRING"
+	case protoreflect.BytesKind:
+		return "BYTES"
+	case protoreflect.EnumKind:
+		return "ENUM(" + string(f.Enum().Name()) + ")"
+	}
+	if full := f.Message().FullName(); full == "google.protobuf.Timestamp" {
+		return "TIMESTAMP"
+	}
+	return "MESSAGE(" + strings.TrimPrefix(string(f.Message().FullName()), ".") + ")"
+}
@@ -0,0 +1,279 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"data-voyager/sdk"
+)
+
+var _ sdk.ChangeStreamer = (*Connection)(nil)
+
+// ReadChanges peeks at the slot with the pgoutput plugin through
+// pg_logical_slot_peek_binary_changes, so it works over an ordinary
+// connection; the user needs the REPLICATION attribute. Changes stay in the
+// slot until ConfirmChanges advances it. A transaction cut short by max is
+// left for the next read, so every batch ends on a commit.
+func (c *Connection) ReadChanges(ctx context.Context, slot, publication string, max int) (*sdk.ChangeBatch, error) {
+	if err := c.ensureSlot(ctx, slot); err != nil {
+		return nil, err
+	}
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT lsn::text, data FROM pg_logical_slot_peek_binary_changes($1, NULL, $2,
+			'proto_version', '1', 'publication_names', $3)`, slot, max, publication)
+	if err != nil {
+		return nil, fmt.Errorf("read changes: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	d := newPgoutputDecoder()
+	batch := &sdk.ChangeBatch{Events: []sdk.ChangeEvent{}}
+	var pending []sdk.ChangeEvent
+	for rows.Next() {
+		var (
+			lsn  string
+			data []byte
+		)
+		if err := rows.Scan(&lsn, &data); err != nil {
+			return nil, err
+		}
+		events, commit, err := d.decode(lsn, data)
+		if err != nil {
+			return nil, fmt.Errorf("decode change at %s: %w", lsn, err)
+		}
+		pending = append(pending, events...)
+		if commit {
+			batch.Events = append(batch.Events, pending...)
+			batch.Position, pending = lsn, nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read changes: %w", err)
+	}
+	return batch, nil
+}
+
+// ConfirmChanges advances the slot past position, letting the server
+// discard the WAL it kept for it.
+func (c *Connection) ConfirmChanges(ctx context.Context, slot, position string) error {
+	if _, err := c.db.ExecContext(ctx, `SELECT pg_replication_slot_advance($1, $2::pg_lsn)`, slot, position); err != nil {
+		return fmt.Errorf("confirm changes: %w", err)
+	}
+	return nil
+}
+
+func (c *Connection) ensureSlot(ctx context.Context, slot string) error {
+	var exists bool
+	if err := c.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM pg_replication_slots WHERE slot_name = $1)`, slot).Scan(&exists); err != nil {
+		return fmt.Errorf("look up replication slot: %w", err)
+	}
+	if exists {
+		return nil
+	}
+	if _, err := c.db.ExecContext(ctx, `SELECT pg_create_logical_replication_slot($1, 'pgoutput')`, slot); err != nil {
+		return fmt.Errorf("create replication slot %s: %w", slot, err)
+	}
+	return nil
+}
+
+// pgEpoch is where pgoutput timestamps count microseconds from.
+var pgEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// relation is a table described by a pgoutput Relation message.
+type relation struct {
+	schema, name string
+	columns      []string
+	types        []uint32
+}
+
+// pgoutputDecoder turns pgoutput protocol version 1 messages into change
+// events. It remembers relations across messages; the server describes each
+// one before its first change in a decoding session.
+type pgoutputDecoder struct {
+	relations map[uint32]*relation
+	commitAt  time.Time
+}
+
+func newPgoutputDecoder() *pgoutputDecoder {
+	return &pgoutputDecoder{relations: map[uint32]*relation{}}
+}
+
+// decode decodes one message. commit is true for the message ending a
+// transaction.
+func (d *pgoutputDecoder) decode(lsn string, data []byte) (events []sdk.ChangeEvent, commit bool, err error) {
+	if len(data) == 0 {
+		return nil, false, errors.New("empty message")
+	}
+	r := &msgReader{b: data[1:]}
+	switch data[0] {
+	case 'B': // Begin: final LSN, commit time, xid
+		r.uint64()
+		d.commitAt = pgTime(int64(r.uint64()))
+	case 'C': // Commit
+		return nil, true, r.err
+	case 'R': // Relation
+		id := r.uint32()
+		rel := &relation{schema: r.string(), name: r.string()}
+		r.byte() // replica identity
+		n := int(r.uint16())
+		for range n {
+			r.byte() // flags
+			rel.columns = append(rel.columns, r.string())
+			rel.types = append(rel.types, r.uint32())
+			r.uint32() // type modifier
+		}
+		d.relations[id] = rel
+	case 'I':
+		rel, err := d.relation(r.uint32())
+		if err != nil {
+			return nil, false, err
+		}
+		r.byte() // 'N'
+		ev := d.event(lsn, sdk.ChangeInsert, rel)
+		ev.Row = r.tuple(rel)
+		events = append(events, ev)
+	case 'U':
+		rel, err := d.relation(r.uint32())
+		if err != nil {
+			return nil, false, err
+		}
+		ev := d.event(lsn, sdk.ChangeUpdate, rel)
+		kind := r.byte()
+		if kind == 'K' || kind == 'O' {
+			ev.Old = r.tuple(rel)
+			kind = r.byte()
+		}
+		if kind != 'N' {
+			return nil, false, fmt.Errorf("unexpected update tuple %q", kind)
+		}
+		ev.Row = r.tuple(rel)
+		events = append(events, ev)
+	case 'D':
+		rel, err := d.relation(r.uint32())
+		if err != nil {
+			return nil, false, err
+		}
+		r.byte() // 'K' or 'O'
+		ev := d.event(lsn, sdk.ChangeDelete, rel)
+		ev.Old = r.tuple(rel)
+		events = append(events, ev)
+	case 'T':
+		n := int(r.uint32())
+		r.byte() // options
+		for range n {
+			rel, err := d.relation(r.uint32())
+			if err != nil {
+				return nil, false, err
+			}
+			events = append(events, d.event(lsn, sdk.ChangeTruncate, rel))
+		}
+	default:
+		// Origin, Type and logical messages carry nothing to report.
+	}
+	return events, false, r.err
+}
+
+func (d *pgoutputDecoder) relation(id uint32) (*relation, error) {
+	rel, ok := d.relations[id]
+	if !ok {
+		return nil, fmt.Errorf("change to undescribed relation %d", id)
+	}
+	return rel, nil
+}
+
+func (d *pgoutputDecoder) event(lsn, op string, rel *relation) sdk.ChangeEvent {
+	return sdk.ChangeEvent{Position: lsn, Op: op, Schema: rel.schema, Table: rel.name, CommitTime: d.commitAt}
+}
+
+func pgTime(micros int64) time.Time {
+	return pgEpoch.Add(time.Duration(micros) * time.Microsecond)
+}
+
+// msgReader reads big-endian protocol fields, remembering the first
+// short read.
+type msgReader struct {
+	b   []byte
+	err error
+}
+
+func (r *msgReader) take(n int) []byte {
+	if r.err != nil {
+		return make([]byte, n)
+	}
+	if len(r.b) < n {
+		r.err = errors.New("message is truncated")
+		return make([]byte, n)
+	}
+	out := r.b[:n]
+	r.b = r.b[n:]
+	return out
+}
+
+func (r *msgReader) byte() byte     { return r.take(1)[0] }
+func (r *msgReader) uint16() uint16 { return binary.BigEndian.Uint16(r.take(2)) }
+func (r *msgReader) uint32() uint32 { return binary.BigEndian.Uint32(r.take(4)) }
+func (r *msgReader) uint64() uint64 { return binary.BigEndian.Uint64(r.take(8)) }
+
+func (r *msgReader) string() string {
+	for i, c := range r.b {
+		if c == 0 {
+			s := string(r.b[:i])
+			r.b = r.b[i+1:]
+			return s
+		}
+	}
+	if r.err == nil {
+		r.err = errors.New("unterminated string")
+	}
+	return ""
+}
+
+// tuple reads TupleData into a row keyed by column name. Unchanged TOAST
+// values, which the server does not resend, are left out.
+func (r *msgReader) tuple(rel *relation) map[string]any {
+	n := int(r.uint16())
+	row := make(map[string]any, n)
+	for i := range n {
+		kind := r.byte()
+		var name string
+		var typ uint32
+		if i < len(rel.columns) {
+			name, typ = rel.columns[i], rel.types[i]
+		}
+		switch kind {
+		case 'n':
+			row[name] = nil
+		case 't':
+			row[name] = textValue(typ, string(r.take(int(r.uint32()))))
+		}
+	}
+	return row
+}
+
+// textValue converts a value in text format to the Go type Query returns
+// for its column type.
+func textValue(typ uint32, s string) any {
+	switch typ {
+	case 16: // bool
+		return s == "t"
+	case 20, 21, 23: // int8, int2, int4
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+	case 700, 701: // float4, float8
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case 114, 3802: // json, jsonb
+		if json.Valid([]byte(s)) {
+			return json.RawMessage(s)
+		}
+	}
+	return s
+}
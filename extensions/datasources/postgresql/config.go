@@ -1,6 +1,11 @@
 package postgresql
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+
+	"data-voyager/sdk/pluginsdk"
+)
 
 // Config holds PostgreSQL connection parameters.
 type Config struct {
@@ -10,6 +15,35 @@ type Config struct {
 	Username string `json:"username" toml:"username"`
 	Password string `json:"password" toml:"password"`
 	SSLMode  string `json:"ssl_mode" toml:"ssl_mode"`
+
+	// StatementCacheSize caps the per-connection prepared statement cache.
+	// Zero uses the default; a negative value disables caching.
+	StatementCacheSize int `json:"statement_cache_size,omitempty" toml:"statement_cache_size"`
+
+	pluginsdk.DialOptions
+	// FetchSize, when set, reads plain SELECTs through a cursor: the first
+	// round trip fetches this many rows and each later one twice as many,
+	// up to a cap, so small results return quickly and large ones take few
+	// round trips.
+	pluginsdk.FetchOptions
+}
+
+// configSteps upgrades stored configs; see sdk.ConfigUpgrader.
+var configSteps = pluginsdk.ConfigSteps{
+	// v1 records ssl_mode explicitly so a future change to the default does
+	// not silently change how existing connections negotiate TLS.
+	func(cfg map[string]any) error {
+		pluginsdk.SetDefault(cfg, "ssl_mode", "prefer")
+		return nil
+	},
+}
+
+// ConfigVersion implements sdk.ConfigUpgrader.
+func (p *Plugin) ConfigVersion() int { return configSteps.ConfigVersion() }
+
+// UpgradeConfig implements sdk.ConfigUpgrader.
+func (p *Plugin) UpgradeConfig(from int, data json.RawMessage) (json.RawMessage, error) {
+	return configSteps.UpgradeConfig(from, data)
 }
 
 func (c *Config) Validate() error {
@@ -22,7 +56,13 @@ func (c *Config) Validate() error {
 	if c.SSLMode == "" {
 		c.SSLMode = "prefer"
 	}
-	return nil
+	if c.StatementCacheSize == 0 {
+		c.StatementCacheSize = defaultStmtCacheSize
+	}
+	if err := c.DialOptions.Validate(); err != nil {
+		return err
+	}
+	return c.FetchOptions.Validate()
 }
 
 func (c *Config) GetConnectionString() string {
@@ -0,0 +1,47 @@
+package postgresql
+
+import "data-voyager/sdk"
+
+// Dialect implements sdk.DialectProvider.
+func (p *Plugin) Dialect() sdk.Dialect {
+	return sdk.Dialect{
+		IdentifierQuote: `"`,
+		StringQuote:     "'",
+		ParamStyle:      sdk.ParamStyleDollar,
+		ReservedWords:   reservedWords,
+		Functions:       functions,
+	}
+}
+
+var reservedWords = append(append([]string{}, sdk.ANSIReservedWords...),
+	"ANALYSE", "ANALYZE", "ARRAY", "ASYMMETRIC", "BOTH", "COLLATE",
+	"CONCURRENTLY", "CURRENT_ROLE", "CURRENT_USER", "DEFERRABLE", "DO",
+	"EXPLAIN", "FREEZE", "ILIKE", "INITIALLY", "LATERAL", "LEADING",
+	"LOCALTIME", "LOCALTIMESTAMP", "NOTNULL", "ONLY", "OVERLAPS", "PLACING",
+	"RETURNING", "SESSION_USER", "SIMILAR", "SOME", "SYMMETRIC", "TABLESAMPLE",
+	"TRAILING", "USER", "VARIADIC", "VERBOSE", "WINDOW",
+)
+
+var functions = []sdk.FunctionDoc{
+	{Name: "count", Signature: "count(expression)", ReturnType: "bigint", Description: "Number of input rows for which expression is not null; count(*) counts all rows."},
+	{Name: "sum", Signature: "sum(expression)", ReturnType: "numeric", Description: "Sum of expression across all non-null input values."},
+	{Name: "avg", Signature: "avg(expression)", ReturnType: "numeric", Description: "Average of all non-null input values."},
+	{Name: "min", Signature: "min(expression)", Description: "Minimum of all non-null input values."},
+	{Name: "max", Signature: "max(expression)", Description: "Maximum of all non-null input values."},
+	{Name: "string_agg", Signature: "string_agg(value text, delimiter text)", ReturnType: "text", Description: "Concatenates non-null input values into a string, separated by delimiter."},
+	{Name: "array_agg", Signature: "array_agg(expression)", ReturnType: "anyarray", Description: "Collects all input values, including nulls, into an array."},
+	{Name: "coalesce", Signature: "coalesce(value [, ...])", Description: "Returns the first of its arguments that is not null."},
+	{Name: "nullif", Signature: "nullif(value1, value2)", Description: "Returns null if value1 equals value2, otherwise value1."},
+	{Name: "now", Signature: "now()", ReturnType: "timestamptz", Description: "Current date and time at the start of the current transaction."},
+	{Name: "date_trunc", Signature: "date_trunc(field text, source timestamp)", ReturnType: "timestamp", Description: "Truncates a timestamp to the given precision, e.g. 'hour' or 'day'."},
+	{Name: "extract", Signature: "extract(field FROM source)", ReturnType: "numeric", Description: "Retrieves a subfield such as year or epoch from a date/time value."},
+	{Name: "to_char", Signature: "to_char(value, format text)", ReturnType: "text", Description: "Formats a timestamp or number as a string using a template."},
+	{Name: "lower", Signature: "lower(text)", ReturnType: "text", Description: "Converts the string to lower case."},
+	{Name: "upper", Signature: "upper(text)", ReturnType: "text", Description: "Converts the string to upper case."},
+	{Name: "length", Signature: "length(text)", ReturnType: "integer", Description: "Number of characters in the string."},
+	{Name: "substring", Signature: "substring(string text [FROM start int] [FOR count int])", ReturnType: "text", Description: "Extracts the substring starting at start, of at most count characters."},
+	{Name: "jsonb_extract_path_text", Signature: "jsonb_extract_path_text(from_json jsonb, VARIADIC path_elems text[])", ReturnType: "text", Description: "Extracts the JSON sub-object at the given path as text."},
+	{Name: "percentile_cont", Signature: "percentile_cont(fraction) WITHIN GROUP (ORDER BY expression)", ReturnType: "double precision", Description: "Continuous percentile, interpolating between adjacent values if needed."},
+	{Name: "row_number", Signature: "row_number() OVER (...)", ReturnType: "bigint", Description: "Number of the current row within its partition, counting from 1."},
+	{Name: "generate_series", Signature: "generate_series(start, stop [, step])", ReturnType: "setof", Description: "Generates a series of values from start to stop with the given step."},
+}
@@ -0,0 +1,43 @@
+package postgresql
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/lib/pq"
+
+	"data-voyager/sdk"
+)
+
+// sqlStateCodes maps SQLSTATE codes to sdk error codes; see
+// https://www.postgresql.org/docs/current/errcodes-appendix.html.
+var sqlStateCodes = map[pq.ErrorCode]string{
+	"28000": sdk.ErrCodeAuthFailed, // invalid_authorization_specification
+	"28P01": sdk.ErrCodeAuthFailed, // invalid_password
+	"3D000": sdk.ErrCodeUnknownDatabase,
+	"42501": sdk.ErrCodePermissionDenied, // insufficient_privilege
+	"42P01": sdk.ErrCodeUndefinedObject,  // undefined_table
+	"42703": sdk.ErrCodeUndefinedObject,  // undefined_column
+	"42883": sdk.ErrCodeUndefinedObject,  // undefined_function
+	"3F000": sdk.ErrCodeUndefinedObject,  // invalid_schema_name
+	"42704": sdk.ErrCodeUndefinedObject,  // undefined_object
+	"42601": sdk.ErrCodeSyntaxError,      // syntax_error
+}
+
+// classify wraps a server error in an sdk.QueryError when its SQLSTATE is
+// one clients care about, locating it in query from the reported position.
+func classify(query string, err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+	code, ok := sqlStateCodes[pqErr.Code]
+	if !ok {
+		return err
+	}
+	qe := &sdk.QueryError{Code: code, Err: err}
+	if pos, _ := strconv.Atoi(pqErr.Position); pos > 0 {
+		qe.Line, qe.Column = sdk.LineColumn(query, pos)
+	}
+	return qe
+}
@@ -0,0 +1,85 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"data-voyager/sdk"
+
+	"github.com/lib/pq"
+)
+
+var _ sdk.CardinalityEstimator = (*Connection)(nil)
+
+// CountRows answers from pg_class.reltuples unless exact is requested or the
+// table has never been analyzed (reltuples < 0), in which case it scans.
+func (c *Connection) CountRows(ctx context.Context, schemaName, table string, exact bool) (*sdk.RowCount, error) {
+	if schemaName == "" {
+		schemaName = "public"
+	}
+	if !exact {
+		query := `
+			SELECT c.reltuples::bigint
+			FROM pg_class c
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE n.nspname = $1 AND c.relname = $2
+		`
+		_, rows, err := c.queryRaw(ctx, query, schemaName, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate row count: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil, fmt.Errorf("table %s.%s not found", schemaName, table)
+		}
+		if n, ok := rows[0][0].(int64); ok && n >= 0 {
+			return &sdk.RowCount{Count: n, Approximate: true, Method: "pg_class.reltuples"}, nil
+		}
+	}
+
+	query := fmt.Sprintf("SELECT count(*) FROM %s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(table))
+	_, rows, err := c.queryRaw(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count rows: %w", err)
+	}
+	n, _ := rows[0][0].(int64)
+	return &sdk.RowCount{Count: n, Method: "count"}, nil
+}
+
+// CountDistinct reads pg_stats.n_distinct, which is either an absolute count
+// or, when negative, a fraction of the table's row estimate.
+func (c *Connection) CountDistinct(ctx context.Context, schemaName, table, column string) (*sdk.RowCount, error) {
+	if schemaName == "" {
+		schemaName = "public"
+	}
+	query := `
+		SELECT s.n_distinct::float8, c.reltuples::float8
+		FROM pg_stats s
+		JOIN pg_namespace n ON n.nspname = s.schemaname
+		JOIN pg_class c ON c.relnamespace = n.oid AND c.relname = s.tablename
+		WHERE s.schemaname = $1 AND s.tablename = $2 AND s.attname = $3
+	`
+	_, rows, err := c.queryRaw(ctx, query, schemaName, table, column)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate distinct count: %w", err)
+	}
+	if len(rows) > 0 {
+		nd, _ := rows[0][0].(float64)
+		tuples, _ := rows[0][1].(float64)
+		if nd < 0 && tuples >= 0 {
+			nd = -nd * tuples
+		}
+		if nd >= 0 {
+			return &sdk.RowCount{Count: int64(nd), Approximate: true, Method: "pg_stats.n_distinct"}, nil
+		}
+	}
+
+	// No statistics for the column yet: fall back to an exact scan.
+	query = fmt.Sprintf("SELECT count(DISTINCT %s) FROM %s.%s",
+		pq.QuoteIdentifier(column), pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(table))
+	_, rows, err = c.queryRaw(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count distinct values: %w", err)
+	}
+	n, _ := rows[0][0].(int64)
+	return &sdk.RowCount{Count: n, Method: "count_distinct"}, nil
+}
@@ -0,0 +1,117 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"data-voyager/sdk"
+)
+
+var _ sdk.SystemHealthReporter = (*Connection)(nil)
+
+// maxHealthSessions caps how many pg_stat_activity rows a health snapshot
+// returns; busy servers can have thousands of idle sessions.
+const maxHealthSessions = 500
+
+// SystemHealth reads client sessions from pg_stat_activity and server-wide
+// counters from pg_stat_database. The session running the snapshot itself is
+// excluded.
+func (c *Connection) SystemHealth(ctx context.Context) (*sdk.SystemHealth, error) {
+	query := fmt.Sprintf(`
+		SELECT pid::text,
+		       coalesce(usename, ''),
+		       coalesce(datname, ''),
+		       coalesce(state, ''),
+		       coalesce(query, ''),
+		       coalesce(extract(epoch FROM now() - coalesce(query_start, backend_start)) * 1000, 0)::bigint,
+		       coalesce(wait_event_type || ':' || wait_event, '')
+		FROM pg_stat_activity
+		WHERE backend_type = 'client backend' AND pid <> pg_backend_pid()
+		ORDER BY state = 'active' DESC, query_start NULLS LAST
+		LIMIT %d
+	`, maxHealthSessions)
+	_, rows, err := c.queryRaw(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pg_stat_activity: %w", err)
+	}
+	health := &sdk.SystemHealth{Sessions: make([]sdk.SystemSession, 0, len(rows))}
+	for _, r := range rows {
+		ms, _ := r[5].(int64)
+		health.Sessions = append(health.Sessions, sdk.SystemSession{
+			ID:        str(r[0]),
+			User:      str(r[1]),
+			Database:  str(r[2]),
+			State:     str(r[3]),
+			Query:     str(r[4]),
+			Elapsed:   time.Duration(ms) * time.Millisecond,
+			WaitEvent: str(r[6]),
+		})
+	}
+
+	query = `
+		SELECT
+			(SELECT count(*) FROM pg_stat_activity WHERE backend_type = 'client backend')::float8,
+			current_setting('max_connections')::float8,
+			(SELECT count(*) FROM pg_stat_activity WHERE backend_type = 'client backend' AND state = 'active')::float8,
+			extract(epoch FROM now() - pg_postmaster_start_time())::float8,
+			coalesce(sum(blks_hit)::float8 / nullif(sum(blks_hit) + sum(blks_read), 0), 0)::float8,
+			coalesce(sum(xact_commit), 0)::float8,
+			coalesce(sum(xact_rollback), 0)::float8,
+			coalesce(sum(deadlocks), 0)::float8
+		FROM pg_stat_database
+	`
+	_, rows, err = c.queryRaw(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pg_stat_database: %w", err)
+	}
+	if len(rows) == 0 {
+		return health, nil
+	}
+	names := []string{
+		sdk.MetricConnections,
+		sdk.MetricMaxConnections,
+		sdk.MetricActiveQueries,
+		sdk.MetricUptimeSeconds,
+		sdk.MetricCacheHitRatio,
+		"pg.xact_commit",
+		"pg.xact_rollback",
+		"pg.deadlocks",
+	}
+	for i, name := range names {
+		v, _ := rows[0][i].(float64)
+		health.Metrics = append(health.Metrics, sdk.SystemMetric{Name: name, Value: v})
+	}
+	return health, nil
+}
+
+// str returns v as a string, or "" for NULL and non-string values.
+func str(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+var _ sdk.SessionTerminator = (*Connection)(nil)
+
+// TerminateSession calls pg_cancel_backend or pg_terminate_backend for the
+// backend pid id. Both return false, rather than failing, when the pid is
+// not a live backend.
+func (c *Connection) TerminateSession(ctx context.Context, id string, queryOnly bool) error {
+	pid, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("%w: invalid pid %q", sdk.ErrSessionNotFound, id)
+	}
+	fn := "pg_terminate_backend"
+	if queryOnly {
+		fn = "pg_cancel_backend"
+	}
+	_, rows, err := c.queryRaw(ctx, "SELECT "+fn+"($1)", pid)
+	if err != nil {
+		return classify("", err)
+	}
+	if ok, _ := rows[0][0].(bool); !ok {
+		return sdk.ErrSessionNotFound
+	}
+	return nil
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"data-voyager/sdk"
+	"data-voyager/sdk/pluginsdk"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 func init() {
@@ -19,16 +22,41 @@ func init() {
 // Type is the DataSourceType identifier for this extension.
 const Type sdk.DataSourceType = "postgresql"
 
+// defaultDialTimeout applies when the config sets no connect_timeout.
+const defaultDialTimeout = 30 * time.Second
+
 // Plugin implements sdk.DatasourcePlugin for PostgreSQL.
 type Plugin struct{}
 
 func (p *Plugin) GetType() sdk.DataSourceType { return Type }
 func (p *Plugin) GetName() string             { return "PostgreSQL" }
 
+// Capabilities implements sdk.CapabilityProvider.
+func (p *Plugin) Capabilities() sdk.Capabilities {
+	return sdk.Capabilities{
+		Exec:         true,
+		Explain:      true,
+		Schemas:      true,
+		Writes:       true,
+		Transactions: true,
+		ChangeStream: true,
+	}
+}
+
+// Version implements sdk.VersionProvider.
+func (p *Plugin) Version() sdk.VersionInfo {
+	return sdk.VersionInfo{
+		Version:          "0.1.0",
+		MinServerVersion: "0.1.0",
+		MaxServerVersion: "1.0.0",
+		DriverVersion:    pluginsdk.ModuleVersion("github.com/lib/pq"),
+	}
+}
+
 func (p *Plugin) ParseConfig(data json.RawMessage) (sdk.ConnectionConfig, error) {
-	cfg := &Config{}
-	if err := json.Unmarshal(data, cfg); err != nil {
-		return nil, fmt.Errorf("invalid postgresql config: %w", err)
+	cfg, err := pluginsdk.DecodeConfig[Config](data, "postgresql")
+	if err != nil {
+		return nil, err
 	}
 	return cfg, nil
 }
@@ -50,10 +78,12 @@ func (p *Plugin) Connect(ctx context.Context, config sdk.ConnectionConfig) (sdk.
 		return nil, fmt.Errorf("invalid postgresql config: %w", err)
 	}
 
-	db, err := sql.Open("postgres", cfg.GetConnectionString())
+	connector, err := pq.NewConnector(cfg.GetConnectionString())
 	if err != nil {
 		return nil, fmt.Errorf("failed to open PostgreSQL connection: %w", err)
 	}
+	connector.Dialer(cfg.Dialer(defaultDialTimeout))
+	db := sql.OpenDB(connector)
 
 	db.SetMaxOpenConns(25)
 	db.SetMaxIdleConns(5)
@@ -61,88 +91,158 @@ func (p *Plugin) Connect(ctx context.Context, config sdk.ConnectionConfig) (sdk.
 
 	if err := db.PingContext(ctx); err != nil {
 		_ = db.Close()
-		return nil, fmt.Errorf("failed to ping PostgreSQL: %w", err)
+		return nil, classify("", fmt.Errorf("failed to ping PostgreSQL: %w", err))
 	}
 
-	return &Connection{db: db, config: cfg}, nil
+	conn := &Connection{db: db, config: cfg}
+	if cfg.StatementCacheSize > 0 {
+		conn.stmts = newStmtCache(cfg.StatementCacheSize)
+	}
+	return conn, nil
 }
 
 func (p *Plugin) TestConnection(ctx context.Context, config sdk.ConnectionConfig) (*sdk.ConnectionTestResult, error) {
-	start := time.Now()
-
-	conn, err := p.Connect(ctx, config)
-	if err != nil {
-		return &sdk.ConnectionTestResult{
-			IsConnected: false,
-			Message:     err.Error(),
-			TestedAt:    time.Now(),
-		}, nil
-	}
-	defer func() { _ = conn.Close() }()
-
-	if err := conn.Ping(ctx); err != nil {
-		return &sdk.ConnectionTestResult{
-			IsConnected: false,
-			Message:     fmt.Sprintf("ping failed: %v", err),
-			TestedAt:    time.Now(),
-		}, nil
-	}
-
-	return &sdk.ConnectionTestResult{
-		IsConnected: true,
-		Message:     "Connection successful",
-		Latency:     time.Since(start).Milliseconds(),
-		TestedAt:    time.Now(),
-	}, nil
+	return pluginsdk.TestConnection(ctx, p, config)
 }
 
 // Connection is an active PostgreSQL connection.
 type Connection struct {
 	db     *sql.DB
 	config *Config
+	stmts  *stmtCache // nil when statement caching is disabled
+
+	metrics pluginsdk.QueryMetrics
 }
 
 func (c *Connection) Query(ctx context.Context, query string, params ...any) (*sdk.QueryResult, error) {
+	defer c.metrics.Track()()
 	start := time.Now()
-	columns, resultRows, err := c.queryRaw(ctx, query, params...)
-	if err != nil {
-		return nil, err
+	if sdk.ReadOnly(ctx) {
+		columns, resultRows, err := c.queryReadOnly(ctx, query, params...)
+		if err != nil {
+			return nil, classify(query, err)
+		}
+		return pluginsdk.TableResult(columns, resultRows, time.Since(start)), nil
 	}
-
-	// Transpose row-oriented data into column-oriented Fields.
-	fields := make([]sdk.Field, len(columns))
-	for i, col := range columns {
-		values := make([]any, len(resultRows))
-		for j, row := range resultRows {
-			values[j] = row[i]
+	if c.config.FetchSize > 0 && len(params) == 0 && cursorable(query) {
+		columns, resultRows, batches, err := c.queryBatched(ctx, query)
+		if err == nil {
+			result := pluginsdk.TableResult(columns, resultRows, time.Since(start))
+			result.Stats.FetchSize = c.config.FetchSize
+			result.Stats.Batches = batches
+			return result, nil
 		}
-		fields[i] = sdk.Field{
-			Name:   col.Name,
-			Kind:   sdk.InferFieldKind(col.Type),
-			Type:   col.Type,
-			Values: values,
+		// Not every SELECT can back a cursor (data-modifying CTEs, for
+		// one); those run as usual and report their own errors.
+		if !errors.Is(err, errNotCursorable) {
+			return nil, classify(query, err)
 		}
 	}
-
-	return &sdk.QueryResult{
-		Frames: []*sdk.DataFrame{{
-			FrameType: sdk.FrameTypeTable,
-			Fields:    fields,
-		}},
-		Stats: sdk.QueryStats{
-			ExecutionTime: time.Since(start),
-			RowsReturned:  int64(len(resultRows)),
-		},
-	}, nil
+	columns, resultRows, err := c.queryRaw(ctx, query, params...)
+	if err != nil {
+		return nil, classify(query, err)
+	}
+	return pluginsdk.TableResult(columns, resultRows, time.Since(start)), nil
 }
 
 // queryRaw executes a query and returns column metadata + raw rows.
 // Used internally by Query (for building DataFrames) and schema helpers.
 func (c *Connection) queryRaw(ctx context.Context, query string, params ...any) ([]sdk.ColumnInfo, [][]any, error) {
-	rows, err := c.db.QueryContext(ctx, query, params...)
+	rows, err := c.query(ctx, query, params...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query execution failed: %w", err)
+	}
+	return scanRows(rows, nil, pluginsdk.NewRowBudget(ctx))
+}
+
+// queryReadOnly runs query in a read-only transaction and rolls it back,
+// so neither writes hidden in functions such as nextval nor settings
+// changed with set_config outlive it.
+func (c *Connection) queryReadOnly(ctx context.Context, query string, params ...any) ([]sdk.ColumnInfo, [][]any, error) {
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
 		return nil, nil, fmt.Errorf("query execution failed: %w", err)
 	}
+	defer func() { _ = tx.Rollback() }()
+	rows, err := tx.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query execution failed: %w", err)
+	}
+	return scanRows(rows, nil, pluginsdk.NewRowBudget(ctx))
+}
+
+// fetchCursor names the cursor batched reads go through. It only lives for
+// the transaction that declares it, so the name never clashes.
+const fetchCursor = "voyager_fetch"
+
+var errNotCursorable = errors.New("query cannot be read through a cursor")
+
+// cursorable reports whether query is a single statement that may be
+// declared as a cursor. Anything in doubt takes the ordinary path.
+func cursorable(query string) bool {
+	q := strings.TrimRight(strings.TrimSpace(query), "; \t\r\n")
+	if strings.Contains(q, ";") {
+		return false
+	}
+	words := strings.Fields(strings.ToLower(q))
+	if len(words) == 0 {
+		return false
+	}
+	switch words[0] {
+	case "select", "with", "values", "table":
+		return true
+	}
+	return false
+}
+
+// queryBatched reads query through a cursor in batches sized by the
+// config's FetchOptions and reports how many batches it took.
+func (c *Connection) queryBatched(ctx context.Context, query string) ([]sdk.ColumnInfo, [][]any, int64, error) {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("query execution failed: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	declare := "DECLARE " + fetchCursor + " NO SCROLL CURSOR FOR " + strings.TrimRight(strings.TrimSpace(query), "; \t\r\n")
+	if _, err := tx.ExecContext(ctx, declare); err != nil {
+		return nil, nil, 0, fmt.Errorf("%w: %w", errNotCursorable, err)
+	}
+
+	var columns []sdk.ColumnInfo
+	var resultRows [][]any
+	var batches int64
+	budget := pluginsdk.NewRowBudget(ctx)
+	for n := c.config.NextFetch(0); ; n = c.config.NextFetch(n) {
+		rows, err := tx.QueryContext(ctx, fmt.Sprintf("FETCH FORWARD %d FROM %s", n, fetchCursor))
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("query execution failed: %w", err)
+		}
+		before := len(resultRows)
+		cols, all, err := scanRows(rows, resultRows, budget)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		batches++
+		if columns == nil {
+			columns = cols
+		}
+		resultRows = all
+		if len(resultRows)-before < n {
+			break
+		}
+	}
+	// Commit rather than roll back so the read behaves as it would outside
+	// a cursor, e.g. for functions with side effects.
+	if err := tx.Commit(); err != nil {
+		return nil, nil, 0, fmt.Errorf("query execution failed: %w", err)
+	}
+	return columns, resultRows, batches, nil
+}
+
+// scanRows reads and closes rows, appending the values to dst and charging
+// each row to budget.
+func scanRows(rows *sql.Rows, dst [][]any, budget *pluginsdk.RowBudget) ([]sdk.ColumnInfo, [][]any, error) {
 	defer func() { _ = rows.Close() }()
 
 	columnTypes, err := rows.ColumnTypes()
@@ -156,7 +256,6 @@ func (c *Connection) queryRaw(ctx context.Context, query string, params ...any)
 		columns[i] = sdk.ColumnInfo{Name: ct.Name(), Type: ct.DatabaseTypeName(), Nullable: nullable}
 	}
 
-	var resultRows [][]any
 	for rows.Next() {
 		values := make([]any, len(columnTypes))
 		valuePtrs := make([]any, len(columnTypes))
@@ -167,16 +266,33 @@ func (c *Connection) queryRaw(ctx context.Context, query string, params ...any)
 			return nil, nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 		for i, v := range values {
-			if b, ok := v.([]byte); ok {
-				values[i] = string(b)
-			}
+			values[i] = pluginsdk.NormalizeValue(v)
+		}
+		if err := budget.Add(values); err != nil {
+			return nil, nil, err
 		}
-		resultRows = append(resultRows, values)
+		dst = append(dst, values)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, nil, fmt.Errorf("rows iteration error: %w", err)
 	}
-	return columns, resultRows, nil
+	return columns, dst, nil
+}
+
+// query runs the statement through the prepared statement cache when enabled.
+// Statements that cannot be prepared (e.g. multi-statement scripts) fall back
+// to a plain query so behaviour matches the uncached path.
+func (c *Connection) query(ctx context.Context, query string, params ...any) (*sql.Rows, error) {
+	if c.stmts == nil {
+		return c.db.QueryContext(ctx, query, params...)
+	}
+	stmt, release, err := c.stmts.get(ctx, c.db, query)
+	if err != nil {
+		return c.db.QueryContext(ctx, query, params...)
+	}
+	// Rows outlive the statement's close, so the hold ends with the call.
+	defer release()
+	return stmt.QueryContext(ctx, params...)
 }
 
 func (c *Connection) GetSchema(ctx context.Context) (*sdk.SchemaInfo, error) {
@@ -238,6 +354,9 @@ func (c *Connection) GetTables(ctx context.Context, schemaName string) ([]sdk.Ta
 }
 
 func (c *Connection) Close() error {
+	if c.stmts != nil {
+		c.stmts.close()
+	}
 	if c.db != nil {
 		return c.db.Close()
 	}
@@ -250,11 +369,21 @@ func (c *Connection) Ping(ctx context.Context) error {
 
 func (c *Connection) GetMetrics() sdk.ConnectionMetrics {
 	stats := c.db.Stats()
-	return sdk.ConnectionMetrics{
+	metrics := sdk.ConnectionMetrics{
 		OpenConnections: stats.OpenConnections,
 		IdleConnections: stats.Idle,
 		LastActivity:    time.Now(),
 	}
+	c.metrics.Fill(&metrics)
+	if c.stmts != nil {
+		hits, misses := c.stmts.stats()
+		metrics.PreparedStmtHits = hits
+		metrics.PreparedStmtMisses = misses
+		if total := hits + misses; total > 0 {
+			metrics.PreparedStmtHitRate = float64(hits) / float64(total)
+		}
+	}
+	return metrics
 }
 
 func (c *Connection) getDatabases(ctx context.Context) ([]sdk.DatabaseInfo, error) {
@@ -2,12 +2,15 @@ package postgresql
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
 	"testing"
 	"time"
 
 	"data-voyager/sdk"
+	"data-voyager/sdk/plugintest"
 
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
@@ -23,6 +26,7 @@ func TestPostgreSQLPlugin(t *testing.T) {
 		postgres.WithDatabase("testdb"),
 		postgres.WithUsername("testuser"),
 		postgres.WithPassword("testpass"),
+		testcontainers.WithCmd("postgres", "-c", "fsync=off", "-c", "wal_level=logical"),
 		testcontainers.WithWaitStrategy(
 			wait.ForLog("database system is ready to accept connections").
 				WithOccurrence(2).
@@ -54,6 +58,19 @@ func TestPostgreSQLPlugin(t *testing.T) {
 		SSLMode:  "disable",
 	}
 
+	t.Run("Contract", func(t *testing.T) {
+		bad := *config
+		bad.Host, bad.Port = "127.0.0.1", 1
+		plugintest.Run(t, plugintest.Suite{
+			Plugin:     plugin,
+			Config:     config,
+			BadConfig:  &bad,
+			Database:   "public",
+			ParamQuery: "SELECT $1::int",
+			SlowQuery:  "SELECT pg_sleep(10)",
+		})
+	})
+
 	t.Run("TestConnection", func(t *testing.T) {
 		result, err := plugin.TestConnection(ctx, config)
 		require.NoError(t, err)
@@ -141,6 +158,113 @@ func TestPostgreSQLPlugin(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("StatementCache", func(t *testing.T) {
+		conn, err := plugin.Connect(ctx, config)
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+
+		for i := 0; i < 3; i++ {
+			_, err = conn.Query(ctx, "SELECT $1::int AS n", i)
+			require.NoError(t, err)
+		}
+
+		metrics := conn.GetMetrics()
+		assert.Equal(t, int64(1), metrics.PreparedStmtMisses)
+		assert.Equal(t, int64(2), metrics.PreparedStmtHits)
+		assert.InDelta(t, 2.0/3.0, metrics.PreparedStmtHitRate, 0.001)
+	})
+
+	t.Run("FetchSize", func(t *testing.T) {
+		batched := *config
+		batched.FetchSize = 10
+		conn, err := plugin.Connect(ctx, &batched)
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+
+		// 10 + 20 + 40 + 80 rows; the short fourth batch ends the read.
+		result, err := conn.Query(ctx, "SELECT g AS n FROM generate_series(1, 100) g;")
+		require.NoError(t, err)
+		assert.Equal(t, int64(100), result.Stats.RowsReturned)
+		assert.Equal(t, 10, result.Stats.FetchSize)
+		assert.Equal(t, int64(4), result.Stats.Batches)
+		assert.Equal(t, "n", result.Frames[0].Fields[0].Name)
+
+		// Data-modifying CTEs cannot back a cursor and run as usual.
+		_, err = conn.Query(ctx, "CREATE TABLE fetch_cte (n int)")
+		require.NoError(t, err)
+		result, err = conn.Query(ctx, "WITH ins AS (INSERT INTO fetch_cte VALUES (1) RETURNING n) SELECT * FROM ins")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), result.Stats.RowsReturned)
+		assert.Zero(t, result.Stats.Batches)
+		_, err = conn.Query(ctx, "DROP TABLE fetch_cte")
+		require.NoError(t, err)
+	})
+
+	t.Run("ChangeStream", func(t *testing.T) {
+		conn, err := plugin.Connect(ctx, config)
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+		cs := conn.(sdk.ChangeStreamer)
+
+		for _, q := range []string{
+			"CREATE TABLE cdc_items (id int PRIMARY KEY, name text)",
+			"CREATE PUBLICATION cdc_pub FOR TABLE cdc_items",
+		} {
+			_, err = conn.Query(ctx, q)
+			require.NoError(t, err)
+		}
+		// The first read creates the slot; changes before it are not seen.
+		batch, err := cs.ReadChanges(ctx, "cdc_slot", "cdc_pub", 100)
+		require.NoError(t, err)
+		assert.Empty(t, batch.Events)
+		defer func() { _, _ = conn.Query(ctx, "SELECT pg_drop_replication_slot('cdc_slot')") }()
+
+		for _, q := range []string{
+			"INSERT INTO cdc_items VALUES (1, 'a'), (2, 'b')",
+			"UPDATE cdc_items SET name = 'c' WHERE id = 2",
+			"DELETE FROM cdc_items WHERE id = 1",
+		} {
+			_, err = conn.Query(ctx, q)
+			require.NoError(t, err)
+		}
+		batch, err = cs.ReadChanges(ctx, "cdc_slot", "cdc_pub", 100)
+		require.NoError(t, err)
+		require.Len(t, batch.Events, 4)
+		assert.Equal(t, sdk.ChangeInsert, batch.Events[0].Op)
+		assert.Equal(t, "cdc_items", batch.Events[0].Table)
+		assert.Equal(t, map[string]any{"id": int64(1), "name": "a"}, batch.Events[0].Row)
+		assert.Equal(t, sdk.ChangeUpdate, batch.Events[2].Op)
+		assert.Equal(t, "c", batch.Events[2].Row["name"])
+		assert.Equal(t, sdk.ChangeDelete, batch.Events[3].Op)
+		assert.Equal(t, int64(1), batch.Events[3].Old["id"])
+
+		// Unconfirmed changes are read again; confirmed ones are not.
+		again, err := cs.ReadChanges(ctx, "cdc_slot", "cdc_pub", 100)
+		require.NoError(t, err)
+		assert.Len(t, again.Events, 4)
+		require.NoError(t, cs.ConfirmChanges(ctx, "cdc_slot", batch.Position))
+		again, err = cs.ReadChanges(ctx, "cdc_slot", "cdc_pub", 100)
+		require.NoError(t, err)
+		assert.Empty(t, again.Events)
+	})
+
+	t.Run("SystemHealth", func(t *testing.T) {
+		conn, err := plugin.Connect(ctx, config)
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+
+		health, err := conn.(sdk.SystemHealthReporter).SystemHealth(ctx)
+		require.NoError(t, err)
+
+		metrics := map[string]float64{}
+		for _, m := range health.Metrics {
+			metrics[m.Name] = m.Value
+		}
+		assert.Greater(t, metrics[sdk.MetricMaxConnections], 0.0)
+		assert.Greater(t, metrics[sdk.MetricUptimeSeconds], 0.0)
+		assert.Contains(t, metrics, sdk.MetricCacheHitRatio)
+	})
+
 	t.Run("InvalidConnection", func(t *testing.T) {
 		invalidConfig := &Config{Host: "invalid-host", Port: 5432, Database: "testdb"}
 		result, err := plugin.TestConnection(ctx, invalidConfig)
@@ -177,6 +301,25 @@ func TestPostgreSQLConfig(t *testing.T) {
 		require.NoError(t, config.Validate())
 		assert.Equal(t, "prefer", config.SSLMode)
 	})
+
+	t.Run("DefaultStatementCacheSize", func(t *testing.T) {
+		config := &Config{Host: "localhost"}
+		require.NoError(t, config.Validate())
+		assert.Equal(t, defaultStmtCacheSize, config.StatementCacheSize)
+	})
+
+	t.Run("DialOptions", func(t *testing.T) {
+		p := &Plugin{}
+		cfg, err := p.ParseConfig([]byte(`{"host":"localhost","connect_timeout":5,"read_timeout":60,"keepalive":-1}`))
+		require.NoError(t, err)
+		config := cfg.(*Config)
+		require.NoError(t, config.Validate())
+		assert.Equal(t, 5, config.ConnectTimeout)
+		assert.Equal(t, 60, config.ReadTimeout)
+
+		config.ConnectTimeout = -1
+		assert.ErrorContains(t, config.Validate(), "connect_timeout")
+	})
 }
 
 func BenchmarkPostgreSQLQuery(b *testing.B) {
@@ -225,3 +368,123 @@ func BenchmarkPostgreSQLQuery(b *testing.B) {
 		require.NoError(b, err)
 	}
 }
+
+func TestClassify(t *testing.T) {
+	query := "SELECT *\n  FORM users"
+	err := classify(query, fmt.Errorf("query execution failed: %w",
+		&pq.Error{Code: "42601", Message: `syntax error at or near "FORM"`, Position: "12"}))
+	var qe *sdk.QueryError
+	require.ErrorAs(t, err, &qe)
+	assert.Equal(t, sdk.ErrCodeSyntaxError, qe.Code)
+	assert.Equal(t, 2, qe.Line)
+	assert.Equal(t, 3, qe.Column)
+
+	err = classify("", &pq.Error{Code: "28P01", Message: "password authentication failed"})
+	require.ErrorAs(t, err, &qe)
+	assert.Equal(t, sdk.ErrCodeAuthFailed, qe.Code)
+	assert.Zero(t, qe.Line)
+
+	plain := &pq.Error{Code: "23505", Message: "duplicate key"}
+	assert.Same(t, error(plain), classify("", plain))
+}
+
+func TestQuery_ReadOnlyContext(t *testing.T) {
+	db, d := openFake(t)
+	conn := &Connection{db: db, config: &Config{}}
+
+	result, err := conn.Query(sdk.WithReadOnly(context.Background()), "SELECT nextval('s')")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, result.Stats.RowsReturned)
+	assert.Equal(t, []string{"begin read only", "rollback"}, d.txs,
+		"read-only queries run in a read-only transaction that is rolled back")
+
+	d.txs = nil
+	_, err = conn.Query(context.Background(), "SELECT 1")
+	require.NoError(t, err)
+	assert.Empty(t, d.txs)
+}
+
+func TestCursorable(t *testing.T) {
+	assert.True(t, cursorable("SELECT 1;"))
+	assert.True(t, cursorable("  with t as (select 1) select * from t"))
+	assert.True(t, cursorable("VALUES (1), (2)"))
+	assert.False(t, cursorable("SELECT 1; SELECT 2"))
+	assert.False(t, cursorable("INSERT INTO t VALUES (1)"))
+	assert.False(t, cursorable(""))
+}
+
+// pgMsg builds a pgoutput message from a type byte and its fields.
+func pgMsg(kind byte, fields ...any) []byte {
+	b := []byte{kind}
+	for _, f := range fields {
+		switch v := f.(type) {
+		case byte:
+			b = append(b, v)
+		case uint16:
+			b = binary.BigEndian.AppendUint16(b, v)
+		case uint32:
+			b = binary.BigEndian.AppendUint32(b, v)
+		case uint64:
+			b = binary.BigEndian.AppendUint64(b, v)
+		case string:
+			b = append(append(b, v...), 0)
+		case []byte: // a text tuple value
+			b = append(binary.BigEndian.AppendUint32(append(b, 't'), uint32(len(v))), v...)
+		}
+	}
+	return b
+}
+
+func TestPgoutputDecoder(t *testing.T) {
+	d := newPgoutputDecoder()
+	commitAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	steps := []struct {
+		data   []byte
+		events int
+		commit bool
+	}{
+		{pgMsg('B', uint64(0x16B3748), uint64(commitAt.Sub(pgEpoch).Microseconds()), uint32(731)), 0, false},
+		{pgMsg('R', uint32(16385), "public", "orders", byte('d'), uint16(3),
+			byte(1), "id", uint32(23), uint32(0xFFFFFFFF),
+			byte(0), "total", uint32(701), uint32(0xFFFFFFFF),
+			byte(0), "note", uint32(25), uint32(0xFFFFFFFF)), 0, false},
+		{pgMsg('I', uint32(16385), byte('N'), uint16(3), []byte("7"), []byte("12.5"), byte('n')), 1, false},
+		{pgMsg('U', uint32(16385), byte('K'), uint16(3), []byte("7"), byte('n'), byte('n'),
+			byte('N'), uint16(3), []byte("7"), []byte("13"), byte('u')), 1, false},
+		{pgMsg('D', uint32(16385), byte('K'), uint16(3), []byte("7"), byte('n'), byte('n')), 1, false},
+		{pgMsg('T', uint32(1), byte(0), uint32(16385)), 1, false},
+		{pgMsg('C', byte(0), uint64(0x16B3748), uint64(0x16B3778), uint64(0)), 0, true},
+	}
+	var events []sdk.ChangeEvent
+	for i, s := range steps {
+		got, commit, err := d.decode("0/16B3778", s.data)
+		require.NoError(t, err, "step %d", i)
+		assert.Len(t, got, s.events, "step %d", i)
+		assert.Equal(t, s.commit, commit, "step %d", i)
+		events = append(events, got...)
+	}
+
+	require.Len(t, events, 4)
+	ins := events[0]
+	assert.Equal(t, sdk.ChangeInsert, ins.Op)
+	assert.Equal(t, "public", ins.Schema)
+	assert.Equal(t, "orders", ins.Table)
+	assert.Equal(t, commitAt, ins.CommitTime)
+	assert.Equal(t, map[string]any{"id": int64(7), "total": 12.5, "note": nil}, ins.Row)
+
+	upd := events[1]
+	assert.Equal(t, sdk.ChangeUpdate, upd.Op)
+	assert.Equal(t, map[string]any{"id": int64(7), "total": float64(13)}, upd.Row, "unchanged TOAST values are left out")
+	assert.Equal(t, int64(7), upd.Old["id"])
+
+	assert.Equal(t, sdk.ChangeDelete, events[2].Op)
+	assert.Nil(t, events[2].Row)
+	assert.Equal(t, int64(7), events[2].Old["id"])
+	assert.Equal(t, sdk.ChangeTruncate, events[3].Op)
+
+	_, _, err := d.decode("0/1", pgMsg('I', uint32(99), byte('N'), uint16(0)))
+	assert.Error(t, err, "a change to an undescribed relation")
+	_, _, err = d.decode("0/1", []byte{'I', 0, 0})
+	assert.Error(t, err, "a truncated message")
+}
@@ -0,0 +1,140 @@
+package postgresql
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// defaultStmtCacheSize is used when Config.StatementCacheSize is zero.
+const defaultStmtCacheSize = 64
+
+// stmtCache is a per-connection LRU cache of prepared statements keyed by
+// statement text. It is safe for concurrent use.
+//
+// Entries are reference counted: a statement evicted while a caller still
+// holds it is closed when the last holder releases it, not under them.
+type stmtCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front = most recently used
+	entries  map[string]*list.Element
+	hits     int64
+	misses   int64
+}
+
+type stmtEntry struct {
+	query string
+	stmt  *sql.Stmt
+	// refs counts the callers holding stmt; evicted is set once the entry
+	// has left the cache, after which the last release closes stmt.
+	refs    int
+	evicted bool
+}
+
+func newStmtCache(capacity int) *stmtCache {
+	return &stmtCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// get returns a cached statement for query, preparing and caching it on
+// miss, and a release func the caller must call once done with it. When the
+// cache is full the least recently used statement is evicted and closed as
+// soon as nobody holds it.
+func (sc *stmtCache) get(ctx context.Context, db *sql.DB, query string) (*sql.Stmt, func(), error) {
+	sc.mu.Lock()
+	if el, ok := sc.entries[query]; ok {
+		sc.order.MoveToFront(el)
+		sc.hits++
+		entry := el.Value.(*stmtEntry)
+		entry.refs++
+		sc.mu.Unlock()
+		return entry.stmt, func() { sc.release(entry) }, nil
+	}
+	sc.misses++
+	sc.mu.Unlock()
+
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sc.mu.Lock()
+	// Another goroutine may have prepared the same text concurrently.
+	if el, ok := sc.entries[query]; ok {
+		sc.order.MoveToFront(el)
+		entry := el.Value.(*stmtEntry)
+		entry.refs++
+		sc.mu.Unlock()
+		_ = stmt.Close()
+		return entry.stmt, func() { sc.release(entry) }, nil
+	}
+	entry := &stmtEntry{query: query, stmt: stmt, refs: 1}
+	sc.entries[query] = sc.order.PushFront(entry)
+	var idle []*sql.Stmt
+	for sc.order.Len() > sc.capacity {
+		oldest := sc.order.Back()
+		if s := sc.evict(oldest); s != nil {
+			idle = append(idle, s)
+		}
+	}
+	sc.mu.Unlock()
+	// sql.Stmt.Close waits for in-flight executions, so not under sc.mu.
+	for _, s := range idle {
+		_ = s.Close()
+	}
+	return stmt, func() { sc.release(entry) }, nil
+}
+
+// evict removes el from the cache and returns its statement when nobody
+// holds it, for the caller to close once sc.mu is released. sc.mu must be
+// held.
+func (sc *stmtCache) evict(el *list.Element) *sql.Stmt {
+	entry := el.Value.(*stmtEntry)
+	sc.order.Remove(el)
+	delete(sc.entries, entry.query)
+	entry.evicted = true
+	if entry.refs > 0 {
+		return nil
+	}
+	return entry.stmt
+}
+
+// release drops one hold on entry, closing its statement when it was the
+// last hold on an evicted entry.
+func (sc *stmtCache) release(entry *stmtEntry) {
+	sc.mu.Lock()
+	entry.refs--
+	done := entry.evicted && entry.refs == 0
+	sc.mu.Unlock()
+	if done {
+		_ = entry.stmt.Close()
+	}
+}
+
+// stats returns cumulative hit and miss counts.
+func (sc *stmtCache) stats() (hits, misses int64) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.hits, sc.misses
+}
+
+// close evicts every cached statement, closing those nobody holds now and
+// the rest as their holders release them.
+func (sc *stmtCache) close() {
+	sc.mu.Lock()
+	var idle []*sql.Stmt
+	for sc.order.Len() > 0 {
+		if s := sc.evict(sc.order.Front()); s != nil {
+			idle = append(idle, s)
+		}
+	}
+	sc.mu.Unlock()
+	for _, s := range idle {
+		_ = s.Close()
+	}
+}
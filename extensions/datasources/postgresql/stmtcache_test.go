@@ -0,0 +1,149 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriver prepares any text and answers every query with one row after
+// a short pause, counting the statements it closes and noting how the
+// transactions it begins are opened and end.
+type fakeDriver struct {
+	closed atomic.Int64
+
+	mu  sync.Mutex
+	txs []string
+}
+
+func (d *fakeDriver) note(event string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.txs = append(d.txs, event)
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+
+// fakeDriver is its own connector, so tests need not register it by name.
+func (d *fakeDriver) Connect(context.Context) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+func (d *fakeDriver) Driver() driver.Driver                        { return d }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return &fakeStmt{d: c.d}, nil }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return nil, fmt.Errorf("not supported") }
+
+func (c *fakeConn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if opts.ReadOnly {
+		c.d.note("begin read only")
+	} else {
+		c.d.note("begin")
+	}
+	return fakeTx{d: c.d}, nil
+}
+
+type fakeTx struct{ d *fakeDriver }
+
+func (t fakeTx) Commit() error   { t.d.note("commit"); return nil }
+func (t fakeTx) Rollback() error { t.d.note("rollback"); return nil }
+
+type fakeStmt struct{ d *fakeDriver }
+
+func (s *fakeStmt) Close() error  { s.d.closed.Add(1); return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, fmt.Errorf("not supported")
+}
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	time.Sleep(time.Millisecond)
+	return &fakeRows{}, nil
+}
+
+type fakeRows struct{ done bool }
+
+func (r *fakeRows) Columns() []string { return []string{"n"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(1)
+	return nil
+}
+
+func openFake(t *testing.T) (*sql.DB, *fakeDriver) {
+	t.Helper()
+	d := &fakeDriver{}
+	db := sql.OpenDB(d)
+	t.Cleanup(func() { _ = db.Close() })
+	return db, d
+}
+
+func TestStmtCache_EvictionWaitsForHolders(t *testing.T) {
+	db, d := openFake(t)
+	sc := newStmtCache(1)
+	ctx := context.Background()
+
+	a, releaseA, err := sc.get(ctx, db, "SELECT 'a'")
+	require.NoError(t, err)
+	// Caching another statement evicts a while it is still held.
+	_, releaseB, err := sc.get(ctx, db, "SELECT 'b'")
+	require.NoError(t, err)
+	releaseB()
+
+	rows, err := a.QueryContext(ctx)
+	require.NoError(t, err, "an evicted statement stays open for its holder")
+	_ = rows.Close()
+	assert.Zero(t, d.closed.Load())
+
+	releaseA()
+	assert.EqualValues(t, 1, d.closed.Load(), "the last release closes the evicted statement")
+
+	sc.close()
+	assert.EqualValues(t, 2, d.closed.Load())
+}
+
+func TestStmtCache_ConcurrentEviction(t *testing.T) {
+	db, _ := openFake(t)
+	sc := newStmtCache(2)
+	conn := &Connection{db: db, stmts: sc}
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 400)
+	for g := range 8 {
+		wg.Go(func() {
+			for i := range 50 {
+				rows, err := conn.query(ctx, fmt.Sprintf("SELECT %d", (g+i)%5))
+				if err != nil {
+					errs <- err
+					continue
+				}
+				for rows.Next() {
+				}
+				if err := rows.Err(); err != nil {
+					errs <- err
+				}
+				_ = rows.Close()
+			}
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	hits, misses := sc.stats()
+	assert.EqualValues(t, 400, hits+misses)
+}
@@ -0,0 +1,253 @@
+package s3
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// emptyHash is the SHA-256 of an empty payload; every request is a GET.
+const emptyHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// object is one listed object.
+type object struct {
+	Key  string `xml:"Key"`
+	Size int64  `xml:"Size"`
+}
+
+// client speaks the few S3 calls the plugin needs: listing a prefix and
+// reading objects, whole or by range. Requests are signed with Signature
+// Version 4, or sent unsigned when no credentials are configured.
+type client struct {
+	cfg  *Config
+	http *http.Client
+	now  func() time.Time
+}
+
+func newClient(cfg *Config) *client {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+	}
+	c := *cfg
+	c.Endpoint = strings.TrimRight(endpoint, "/")
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = cfg.Dialer(defaultConnectTimeout).DialContext
+	return &client{cfg: &c, http: &http.Client{Transport: transport}, now: time.Now}
+}
+
+// list returns the objects below the configured prefix in key order, up to
+// limit of them.
+func (c *client) list(ctx context.Context, limit int) ([]object, error) {
+	prefix := c.cfg.Prefix
+	if prefix != "" {
+		prefix += "/"
+	}
+	var objects []object
+	var token string
+	for {
+		q := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		var page struct {
+			Contents              []object `xml:"Contents"`
+			IsTruncated           bool     `xml:"IsTruncated"`
+			NextContinuationToken string   `xml:"NextContinuationToken"`
+		}
+		if err := c.getXML(ctx, q, &page); err != nil {
+			return nil, fmt.Errorf("list s3://%s/%s: %w", c.cfg.Bucket, prefix, err)
+		}
+		for _, o := range page.Contents {
+			if len(objects) == limit {
+				return objects, nil
+			}
+			objects = append(objects, o)
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+func (c *client) getXML(ctx context.Context, q url.Values, v any) error {
+	resp, err := c.do(ctx, "", q, "")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		return responseError(resp)
+	}
+	return xml.NewDecoder(resp.Body).Decode(v)
+}
+
+// open reads key, or the byte range rng ("bytes=0-99") of it.
+func (c *client) open(ctx context.Context, key, rng string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, key, nil, rng)
+	if err != nil {
+		return nil, fmt.Errorf("get s3://%s/%s: %w", c.cfg.Bucket, key, err)
+	}
+	if resp.StatusCode/100 != 2 {
+		defer func() { _ = resp.Body.Close() }()
+		return nil, fmt.Errorf("get s3://%s/%s: %w", c.cfg.Bucket, key, responseError(resp))
+	}
+	return resp.Body, nil
+}
+
+// rangeReader reads an object through ranged GETs, one per ReadAt, so
+// file footers can be read without downloading the file.
+type rangeReader struct {
+	ctx context.Context
+	c   *client
+	key string
+}
+
+func (r *rangeReader) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	body, err := r.c.open(r.ctx, r.key, fmt.Sprintf("bytes=%d-%d", off, off+int64(len(p))-1))
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = body.Close() }()
+	n, err := io.ReadFull(body, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// do sends one GET, for key or, when key is empty, for the bucket.
+func (c *client) do(ctx context.Context, key string, q url.Values, rng string) (*http.Response, error) {
+	u, err := url.Parse(c.cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint: %w", err)
+	}
+	path := "/" + key
+	if c.cfg.PathStyle {
+		path = "/" + c.cfg.Bucket + path
+	} else {
+		u.Host = c.cfg.Bucket + "." + u.Host
+	}
+	u.Path = path
+	u.RawPath = uriEncode(path, false)
+	u.RawQuery = canonicalQuery(q)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if rng != "" {
+		req.Header.Set("Range", rng)
+	}
+	if c.cfg.AccessKeyID != "" {
+		c.sign(req)
+	}
+	return c.http.Do(req)
+}
+
+// sign adds AWS Signature Version 4 headers to req.
+func (c *client) sign(req *http.Request) {
+	now := c.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", emptyHash)
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": emptyHash,
+		"x-amz-date":           amzDate,
+	}
+	if c.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", c.cfg.SessionToken)
+		headers["x-amz-security-token"] = c.cfg.SessionToken
+	}
+	names := make([]string, 0, len(headers))
+	for n := range headers {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+	var canonHeaders strings.Builder
+	for _, n := range names {
+		canonHeaders.WriteString(n + ":" + strings.TrimSpace(headers[n]) + "\n")
+	}
+	signed := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonHeaders.String(),
+		signed,
+		emptyHash,
+	}, "\n")
+
+	scope := day + "/" + c.cfg.Region + "/s3/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonical))
+
+	key := hmacSHA256([]byte("AWS4"+c.cfg.SecretAccessKey), day)
+	key = hmacSHA256(key, c.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	sig := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.cfg.AccessKeyID, scope, signed, sig))
+}
+
+// canonicalQuery encodes q sorted by name with SigV4's escaping, which
+// differs from url.Values.Encode in escaping spaces as %20.
+func canonicalQuery(q url.Values) string {
+	names := make([]string, 0, len(q))
+	for n := range q {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+	var parts []string
+	for _, n := range names {
+		for _, v := range q[n] {
+			parts = append(parts, uriEncode(n, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode escapes s the way SigV4 expects: everything but unreserved
+// characters, and "/" too when encoding a query value.
+func uriEncode(s string, slash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' && !slash {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,76 @@
+package s3
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"data-voyager/sdk/pluginsdk"
+)
+
+// Listing and download limits.
+const (
+	DefaultMaxObjects = 1000
+	// DefaultMaxQueryMB bounds how much object data one query downloads.
+	DefaultMaxQueryMB = 1024
+)
+
+// Config holds the bucket and prefix a datasource reads.
+type Config struct {
+	// Endpoint is the S3 API URL; empty means AWS in Region. Set it, with
+	// PathStyle, for MinIO and other S3-compatible stores.
+	Endpoint        string `json:"endpoint,omitempty" toml:"endpoint"`
+	Region          string `json:"region" toml:"region"`
+	Bucket          string `json:"bucket" toml:"bucket"`
+	Prefix          string `json:"prefix,omitempty" toml:"prefix"`
+	AccessKeyID     string `json:"access_key_id,omitempty" toml:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key,omitempty" toml:"secret_access_key"`
+	SessionToken    string `json:"session_token,omitempty" toml:"session_token"`
+	PathStyle       bool   `json:"path_style" toml:"path_style"`
+
+	// MaxObjects caps how many objects are listed as tables.
+	MaxObjects int `json:"max_objects,omitempty" toml:"max_objects"`
+	// MaxQueryMB caps the size of the objects one query may read.
+	MaxQueryMB int `json:"max_query_mb,omitempty" toml:"max_query_mb"`
+
+	pluginsdk.DialOptions
+}
+
+func (c *Config) Validate() error {
+	if c.Bucket == "" {
+		return fmt.Errorf("bucket is required")
+	}
+	if strings.ContainsAny(c.Bucket, "/ ") {
+		return fmt.Errorf("bucket %q is not a bucket name", c.Bucket)
+	}
+	if c.Region == "" {
+		c.Region = "us-east-1"
+	}
+	if c.Endpoint != "" {
+		u, err := url.Parse(c.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("endpoint must be an http or https URL")
+		}
+	}
+	if (c.AccessKeyID == "") != (c.SecretAccessKey == "") {
+		return fmt.Errorf("access_key_id and secret_access_key must be set together")
+	}
+	if c.MaxObjects < 0 || c.MaxQueryMB < 0 {
+		return fmt.Errorf("max_objects and max_query_mb must not be negative")
+	}
+	if c.MaxObjects == 0 {
+		c.MaxObjects = DefaultMaxObjects
+	}
+	if c.MaxQueryMB == 0 {
+		c.MaxQueryMB = DefaultMaxQueryMB
+	}
+	c.Prefix = strings.Trim(c.Prefix, "/")
+	return c.DialOptions.Validate()
+}
+
+func (c *Config) GetConnectionString() string {
+	if c.Prefix == "" {
+		return "s3://" + c.Bucket
+	}
+	return "s3://" + c.Bucket + "/" + c.Prefix
+}
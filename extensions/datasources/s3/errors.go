@@ -0,0 +1,59 @@
+package s3
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"data-voyager/sdk"
+)
+
+// apiError is an error response from the S3 API.
+type apiError struct {
+	Status  int    `xml:"-"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func (e *apiError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status))
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func responseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	e := &apiError{Status: resp.StatusCode}
+	_ = xml.Unmarshal(bytes.TrimSpace(body), e)
+	return e
+}
+
+// errorCodes maps S3 error codes to sdk error codes.
+var errorCodes = map[string]string{
+	"InvalidAccessKeyId":    sdk.ErrCodeAuthFailed,
+	"SignatureDoesNotMatch": sdk.ErrCodeAuthFailed,
+	"ExpiredToken":          sdk.ErrCodeAuthFailed,
+	"AccessDenied":          sdk.ErrCodePermissionDenied,
+	"NoSuchBucket":          sdk.ErrCodeUnknownDatabase,
+	"NoSuchKey":             sdk.ErrCodeUndefinedObject,
+}
+
+// classify wraps an S3 error in an sdk.QueryError when its code is one
+// clients care about.
+func classify(err error) error {
+	var ae *apiError
+	if !errors.As(err, &ae) {
+		return err
+	}
+	if code, ok := errorCodes[ae.Code]; ok {
+		return &sdk.QueryError{Code: code, Err: err}
+	}
+	if ae.Status == http.StatusForbidden {
+		return &sdk.QueryError{Code: sdk.ErrCodePermissionDenied, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,127 @@
+import { Input } from '@data-voyager/shared-ui/components/ui/input'
+import { Label } from '@data-voyager/shared-ui/components/ui/label'
+import { Switch } from '@data-voyager/shared-ui/components/ui/switch'
+import type { DatasourceConfigProps } from '@data-voyager/sdk'
+
+interface S3Config {
+  endpoint: string
+  region: string
+  bucket: string
+  prefix: string
+  access_key_id: string
+  secret_access_key: string
+  session_token: string
+  path_style: boolean
+  max_objects: number
+  max_query_mb: number
+}
+
+export function S3ConfigForm({ config, onChange }: DatasourceConfigProps) {
+  const cfg = config as Partial<S3Config>
+
+  const set = (key: keyof S3Config, value: string | number | boolean) =>
+    onChange({ ...cfg, [key]: value })
+
+  return (
+    <div className="flex flex-col gap-4 max-w-lg">
+      <div className="grid grid-cols-2 gap-3">
+        <div className="space-y-2">
+          <Label>Bucket</Label>
+          <Input
+            placeholder="analytics"
+            value={cfg.bucket ?? ''}
+            onChange={(e) => set('bucket', e.target.value)}
+          />
+        </div>
+        <div className="space-y-2">
+          <Label>Prefix</Label>
+          <Input
+            placeholder="warehouse/ (optional)"
+            value={cfg.prefix ?? ''}
+            onChange={(e) => set('prefix', e.target.value)}
+          />
+        </div>
+      </div>
+
+      <div className="grid grid-cols-2 gap-3">
+        <div className="space-y-2">
+          <Label>Region</Label>
+          <Input
+            placeholder="us-east-1"
+            value={cfg.region ?? ''}
+            onChange={(e) => set('region', e.target.value)}
+          />
+        </div>
+        <div className="space-y-2">
+          <Label>Endpoint</Label>
+          <Input
+            placeholder="http://minio:9000 (optional)"
+            value={cfg.endpoint ?? ''}
+            onChange={(e) => set('endpoint', e.target.value)}
+          />
+        </div>
+      </div>
+
+      <div className="flex items-center gap-3">
+        <Switch
+          checked={cfg.path_style ?? false}
+          onCheckedChange={(v) => set('path_style', v)}
+          id="path_style"
+        />
+        <Label htmlFor="path_style" className="cursor-pointer">
+          Path-style addressing (MinIO and most S3-compatible stores)
+        </Label>
+      </div>
+
+      <div className="space-y-2">
+        <Label>Access key ID</Label>
+        <Input
+          placeholder="empty for a public bucket"
+          value={cfg.access_key_id ?? ''}
+          onChange={(e) => set('access_key_id', e.target.value)}
+        />
+      </div>
+
+      <div className="space-y-2">
+        <Label>Secret access key</Label>
+        <Input
+          type="password"
+          placeholder="••••••••"
+          value={cfg.secret_access_key ?? ''}
+          onChange={(e) => set('secret_access_key', e.target.value)}
+        />
+      </div>
+
+      <div className="space-y-2">
+        <Label>Session token</Label>
+        <Input
+          type="password"
+          placeholder="for temporary credentials (optional)"
+          value={cfg.session_token ?? ''}
+          onChange={(e) => set('session_token', e.target.value)}
+        />
+      </div>
+
+      <div className="grid grid-cols-2 gap-3">
+        <div className="space-y-2">
+          <Label>Max objects</Label>
+          <Input
+            type="number"
+            placeholder="1000"
+            value={cfg.max_objects ?? ''}
+            onChange={(e) => set('max_objects', Number(e.target.value))}
+          />
+        </div>
+        <div className="space-y-2">
+          <Label>Max data per query (MB)</Label>
+          <Input
+            type="number"
+            placeholder="1024"
+            value={cfg.max_query_mb ?? ''}
+            onChange={(e) => set('max_query_mb', Number(e.target.value))}
+          />
+        </div>
+      </div>
+    </div>
+  )
+}
@@ -0,0 +1 @@
+export { GenericSQLQueryEditor as S3QueryEditor } from '@data-voyager/shared-ui';
@@ -0,0 +1,18 @@
+import type { DatasourcePlugin } from '@data-voyager/sdk';
+import { datasourceRegistry } from '@data-voyager/sdk';
+import { S3ConfigForm } from './ConfigForm';
+import { S3QueryEditor } from './QueryEditorWidget';
+import { s3SchemaProvider } from './schemaProvider';
+
+const plugin: DatasourcePlugin = {
+  id: 's3',
+  name: 'S3 (Parquet/CSV)',
+  description: 'Query Parquet and CSV objects in an S3-compatible bucket with SQL',
+  configComponent: S3ConfigForm,
+  queryEditorComponent: S3QueryEditor,
+  schemaProvider: s3SchemaProvider,
+};
+
+datasourceRegistry.register(plugin);
+
+export { plugin };
@@ -0,0 +1,132 @@
+package s3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"data-voyager/sdk"
+	"data-voyager/sdk/pluginsdk"
+)
+
+func init() {
+	sdk.RegisterDatasource(&Plugin{})
+}
+
+// Type is the DataSourceType identifier for this extension.
+const Type sdk.DataSourceType = "s3"
+
+const defaultConnectTimeout = 10 * time.Second
+
+// Plugin implements sdk.DatasourcePlugin for Parquet and CSV objects in an
+// S3-compatible bucket. Objects below the configured prefix are listed as
+// tables of a single database named after the bucket; queries are SQL run
+// by an embedded DuckDB over the objects they name.
+type Plugin struct{}
+
+func (p *Plugin) GetType() sdk.DataSourceType { return Type }
+func (p *Plugin) GetName() string             { return "S3 (Parquet/CSV)" }
+
+// Capabilities implements sdk.CapabilityProvider.
+func (p *Plugin) Capabilities() sdk.Capabilities {
+	return sdk.Capabilities{Schemas: true}
+}
+
+// Version implements sdk.VersionProvider.
+func (p *Plugin) Version() sdk.VersionInfo {
+	return sdk.VersionInfo{
+		Version:          "0.1.0",
+		MinServerVersion: "0.1.0",
+		MaxServerVersion: "1.0.0",
+		DriverVersion:    pluginsdk.ModuleVersion("github.com/marcboeker/go-duckdb"),
+	}
+}
+
+func (p *Plugin) ParseConfig(data json.RawMessage) (sdk.ConnectionConfig, error) {
+	cfg, err := pluginsdk.DecodeConfig[Config](data, "s3")
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (p *Plugin) ValidateConfig(config any) error {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return fmt.Errorf("config must be *s3.Config")
+	}
+	return cfg.Validate()
+}
+
+func (p *Plugin) Connect(ctx context.Context, config sdk.ConnectionConfig) (sdk.Connection, error) {
+	cfg, ok := config.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("invalid config type for S3")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid s3 config: %w", err)
+	}
+	conn := &Connection{client: newClient(cfg), config: cfg}
+	if err := conn.Ping(ctx); err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (p *Plugin) TestConnection(ctx context.Context, config sdk.ConnectionConfig) (*sdk.ConnectionTestResult, error) {
+	return pluginsdk.TestConnection(ctx, p, config)
+}
+
+// Connection reads one bucket prefix. It holds no open resources between
+// calls: every query gets a database of its own.
+type Connection struct {
+	client *client
+	config *Config
+
+	metrics pluginsdk.QueryMetrics
+}
+
+// GetSchema lists the tables under a single database named after the
+// bucket.
+func (c *Connection) GetSchema(ctx context.Context) (*sdk.SchemaInfo, error) {
+	tables, err := c.GetTables(ctx, c.config.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	return &sdk.SchemaInfo{Databases: []sdk.DatabaseInfo{{Name: c.config.Bucket, Tables: tables, Description: c.config.GetConnectionString()}}}, nil
+}
+
+// GetTables lists the Parquet and CSV objects below the prefix, with the
+// columns read from each.
+func (c *Connection) GetTables(ctx context.Context, database string) ([]sdk.TableInfo, error) {
+	if database != "" && database != c.config.Bucket {
+		return nil, &sdk.QueryError{Code: sdk.ErrCodeUnknownDatabase,
+			Err: fmt.Errorf("database %q does not exist; tables are listed under %q", database, c.config.Bucket)}
+	}
+	tables, err := c.tables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]sdk.TableInfo, len(tables))
+	for i, t := range tables {
+		infos[i] = c.describe(ctx, t)
+	}
+	return infos, nil
+}
+
+func (c *Connection) Close() error { return nil }
+
+// Ping lists the prefix, which checks the credentials and the bucket.
+func (c *Connection) Ping(ctx context.Context) error {
+	if _, err := c.client.list(ctx, 1); err != nil {
+		return classify(err)
+	}
+	return nil
+}
+
+func (c *Connection) GetMetrics() sdk.ConnectionMetrics {
+	metrics := sdk.ConnectionMetrics{LastActivity: time.Now()}
+	c.metrics.Fill(&metrics)
+	return metrics
+}
@@ -0,0 +1,259 @@
+package s3
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"data-voyager/sdk"
+)
+
+type order struct {
+	ID       int64     `parquet:"id"`
+	Customer *string   `parquet:"customer,optional"`
+	Total    float64   `parquet:"total"`
+	PlacedAt time.Time `parquet:"placed_at,timestamp"`
+}
+
+// fakeS3 serves a path-style bucket from memory: ListObjectsV2 for the
+// bucket and ranged GETs for its objects.
+type fakeS3 struct {
+	bucket  string
+	objects map[string][]byte
+
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests = append(f.requests, r)
+	f.mu.Unlock()
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+f.bucket+"/")
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("<Error><Code>NoSuchBucket</Code><Message>no such bucket</Message></Error>"))
+		return
+	}
+	if key == "" {
+		type entry struct {
+			Key  string
+			Size int
+		}
+		var list struct {
+			XMLName  xml.Name `xml:"ListBucketResult"`
+			Contents []entry
+		}
+		for k, body := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+				list.Contents = append(list.Contents, entry{Key: k, Size: len(body)})
+			}
+		}
+		slices.SortFunc(list.Contents, func(a, b entry) int { return strings.Compare(a.Key, b.Key) })
+		_ = xml.NewEncoder(w).Encode(list)
+		return
+	}
+	body, ok := f.objects[key]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("<Error><Code>NoSuchKey</Code><Message>no such key</Message></Error>"))
+		return
+	}
+	http.ServeContent(w, r, key, time.Time{}, bytes.NewReader(body))
+}
+
+// downloads lists the keys read in full.
+func (f *fakeS3) downloads() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var keys []string
+	for _, r := range f.requests {
+		if r.Header.Get("Range") == "" && r.URL.Query().Get("list-type") == "" {
+			keys = append(keys, strings.TrimPrefix(r.URL.Path, "/"+f.bucket+"/"))
+		}
+	}
+	return keys
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	t.Helper()
+	var orders bytes.Buffer
+	ann := "ann"
+	placed := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, parquet.Write(&orders, []order{
+		{ID: 1, Customer: &ann, Total: 12.5, PlacedAt: placed},
+		{ID: 2, Total: 3, PlacedAt: placed.Add(time.Hour)},
+		{ID: 3, Customer: &ann, Total: 7.25, PlacedAt: placed.Add(2 * time.Hour)},
+	}))
+	var events bytes.Buffer
+	zw := gzip.NewWriter(&events)
+	_, _ = zw.Write([]byte("kind;n\nclick;1\nview;2\n"))
+	require.NoError(t, zw.Close())
+
+	f := &fakeS3{bucket: "lake", objects: map[string][]byte{
+		"shop/orders.parquet":     orders.Bytes(),
+		"shop/customers.csv":      []byte("name,vip,score\nann,true,1.5\nbob,false,2\n"),
+		"shop/2026/events.csv.gz": events.Bytes(),
+		"shop/README.txt":         []byte("not a table"),
+		"other/ignored.csv":       []byte("a\n1\n"),
+	}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func connect(t *testing.T, cfg Config) *Connection {
+	t.Helper()
+	raw, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	p := &Plugin{}
+	parsed, err := p.ParseConfig(raw)
+	require.NoError(t, err)
+	conn, err := p.Connect(context.Background(), parsed)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn.(*Connection)
+}
+
+func TestS3Plugin(t *testing.T) {
+	ctx := context.Background()
+	f, srv := newFakeS3(t)
+	conn := connect(t, Config{Endpoint: srv.URL, Bucket: "lake", Prefix: "/shop/", PathStyle: true,
+		AccessKeyID: "AKID", SecretAccessKey: "secret"})
+
+	t.Run("requests are signed", func(t *testing.T) {
+		auth := f.requests[0].Header.Get("Authorization")
+		assert.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/"), auth)
+		assert.Contains(t, auth, "/us-east-1/s3/aws4_request")
+	})
+
+	t.Run("schema", func(t *testing.T) {
+		schema, err := conn.GetSchema(ctx)
+		require.NoError(t, err)
+		require.Len(t, schema.Databases, 1)
+		db := schema.Databases[0]
+		assert.Equal(t, "lake", db.Name)
+		names := make([]string, len(db.Tables))
+		for i, tbl := range db.Tables {
+			names[i] = tbl.Name
+		}
+		assert.Equal(t, []string{"2026/events", "customers", "orders"}, names)
+
+		events, customers, orders := db.Tables[0], db.Tables[1], db.Tables[2]
+		assert.Equal(t, []sdk.ColumnInfo{
+			{Name: "kind", Type: "VARCHAR", Nullable: true},
+			{Name: "n", Type: "BIGINT", Nullable: true},
+		}, events.Columns)
+		assert.Equal(t, []sdk.ColumnInfo{
+			{Name: "name", Type: "VARCHAR", Nullable: true},
+			{Name: "vip", Type: "BOOLEAN", Nullable: true},
+			{Name: "score", Type: "DOUBLE", Nullable: true},
+		}, customers.Columns)
+		assert.Equal(t, "parquet", orders.Type)
+		require.NotNil(t, orders.RowCount)
+		assert.EqualValues(t, 3, *orders.RowCount)
+		assert.Equal(t, []sdk.ColumnInfo{
+			{Name: "id", Type: "BIGINT"},
+			{Name: "customer", Type: "VARCHAR", Nullable: true},
+			{Name: "total", Type: "DOUBLE"},
+			{Name: "placed_at", Type: "TIMESTAMP WITH TIME ZONE"},
+		}, orders.Columns)
+		assert.Empty(t, f.downloads(), "columns come from ranged reads")
+	})
+
+	t.Run("query", func(t *testing.T) {
+		res, err := conn.Query(ctx, `SELECT o.id, c.vip, o.total FROM orders o JOIN customers c ON c.name = o.customer
+			WHERE o.total > ? ORDER BY o.id`, 5)
+		require.NoError(t, err)
+		fields := res.Frames[0].Fields
+		require.Len(t, fields, 3)
+		assert.Equal(t, []any{int64(1), int64(3)}, fields[0].Values)
+		assert.Equal(t, []any{true, true}, fields[1].Values)
+		assert.Equal(t, []any{12.5, 7.25}, fields[2].Values)
+		assert.ElementsMatch(t, []string{"shop/orders.parquet", "shop/customers.csv"}, f.downloads())
+
+		res, err = conn.Query(ctx, `SELECT (sum(n) / 2)::DECIMAL(10,2) AS total FROM "2026/events"`)
+		require.NoError(t, err)
+		assert.Equal(t, []any{"1.5"}, res.Frames[0].Fields[0].Values)
+	})
+
+	t.Run("query cannot reach outside its tables", func(t *testing.T) {
+		for _, q := range []string{
+			`SELECT * FROM read_csv_auto('/etc/passwd')`,
+			`SET enable_external_access = true`,
+			`COPY orders TO '/tmp/orders.csv'`,
+		} {
+			_, err := conn.Query(ctx, q)
+			var qe *sdk.QueryError
+			require.ErrorAs(t, err, &qe, q)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := conn.Query(ctx, "SELEC 1")
+		var qe *sdk.QueryError
+		require.ErrorAs(t, err, &qe)
+		assert.Equal(t, sdk.ErrCodeSyntaxError, qe.Code)
+
+		_, err = conn.Query(ctx, "SELECT * FROM refunds")
+		require.ErrorAs(t, err, &qe)
+		assert.Equal(t, sdk.ErrCodeUndefinedObject, qe.Code)
+
+		_, err = conn.GetTables(ctx, "elsewhere")
+		require.ErrorAs(t, err, &qe)
+		assert.Equal(t, sdk.ErrCodeUnknownDatabase, qe.Code)
+	})
+}
+
+func TestS3Plugin_Limits(t *testing.T) {
+	f, srv := newFakeS3(t)
+	f.objects["shop/big.csv"] = bytes.Repeat([]byte("x\n"), 1<<20)
+	conn := connect(t, Config{Endpoint: srv.URL, Bucket: "lake", Prefix: "shop", PathStyle: true, MaxQueryMB: 1})
+
+	_, err := conn.Query(context.Background(), "SELECT count(*) FROM big")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "over the limit of 1 MB")
+	assert.Empty(t, f.downloads())
+
+	_, err = (&Plugin{}).Connect(context.Background(), &Config{Endpoint: srv.URL, Bucket: "missing", PathStyle: true})
+	var qe *sdk.QueryError
+	require.ErrorAs(t, err, &qe)
+	assert.Equal(t, sdk.ErrCodeUnknownDatabase, qe.Code)
+}
+
+func TestConfigValidate(t *testing.T) {
+	cases := map[string]Config{
+		"no bucket":        {},
+		"bad endpoint":     {Bucket: "b", Endpoint: "minio:9000"},
+		"half credentials": {Bucket: "b", AccessKeyID: "AKID"},
+	}
+	for name, cfg := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, cfg.Validate())
+		})
+	}
+	cfg := Config{Bucket: "b", Prefix: "/data/"}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, "us-east-1", cfg.Region)
+	assert.Equal(t, "s3://b/data", cfg.GetConnectionString())
+}
+
+func TestMentions(t *testing.T) {
+	assert.True(t, mentions(`select * from "2026/events"`, "2026/events"))
+	assert.True(t, mentions("SELECT * FROM Orders", "orders"))
+	assert.False(t, mentions("SELECT * FROM orders_archive", "orders"))
+	assert.False(t, mentions("SELECT preorders FROM t", "orders"))
+}
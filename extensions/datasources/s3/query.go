@@ -0,0 +1,233 @@
+package s3
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/marcboeker/go-duckdb"
+
+	"data-voyager/sdk"
+	"data-voyager/sdk/pluginsdk"
+)
+
+// Query runs query, in DuckDB's SQL dialect, over the tables it names.
+// Those objects are downloaded and loaded into an in-memory database of the
+// query's own, which is then locked down: the query itself can read no
+// files, reach no network and change no settings.
+func (c *Connection) Query(ctx context.Context, query string, params ...any) (*sdk.QueryResult, error) {
+	defer c.metrics.Track()()
+	start := time.Now()
+
+	tables, err := c.tables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var used []table
+	var size int64
+	for _, t := range tables {
+		if mentions(query, t.name) {
+			used = append(used, t)
+			size += t.object.Size
+		}
+	}
+	if limit := int64(c.config.MaxQueryMB) << 20; size > limit {
+		return nil, fmt.Errorf("the tables this query names hold %d MB, over the limit of %d MB", size>>20, c.config.MaxQueryMB)
+	}
+
+	dir, err := os.MkdirTemp("", "voyager-s3-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	db, err := sql.Open("duckdb", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DuckDB: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+	// An in-memory database lives as long as its connections; one
+	// connection keeps the loaded tables for the query.
+	db.SetMaxOpenConns(1)
+
+	for i, t := range used {
+		path, err := c.download(ctx, t, filepath.Join(dir, fmt.Sprint(i)))
+		if err != nil {
+			return nil, classify(err)
+		}
+		if _, err := db.ExecContext(ctx, loadStatement(t, path)); err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", t.name, err)
+		}
+	}
+	for _, stmt := range []string{"SET enable_external_access = false", "SET lock_configuration = true"} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return nil, fmt.Errorf("failed to lock down DuckDB: %w", err)
+		}
+	}
+
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, &sdk.QueryError{Code: queryErrorCode(err), Err: err}
+	}
+	columns, resultRows, err := scanRows(rows, pluginsdk.NewRowBudget(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return pluginsdk.TableResult(columns, resultRows, time.Since(start)), nil
+}
+
+// mentions reports whether query names table: whether its name, compared
+// without regard to case as DuckDB compares identifiers, appears with no
+// identifier character on either side.
+func mentions(query, table string) bool {
+	q, name := strings.ToLower(query), strings.ToLower(table)
+	for i := 0; ; {
+		j := strings.Index(q[i:], name)
+		if j < 0 {
+			return false
+		}
+		j += i
+		end := j + len(name)
+		if (j == 0 || !identChar(q[j-1])) && (end == len(q) || !identChar(q[end])) {
+			return true
+		}
+		i = j + 1
+	}
+}
+
+func identChar(b byte) bool {
+	return b == '_' || b >= 'a' && b <= 'z' || b >= '0' && b <= '9' || b >= 0x80
+}
+
+// download copies the object of t to base, keeping its extension so
+// DuckDB recognizes compressed CSV.
+func (c *Connection) download(ctx context.Context, t table, base string) (string, error) {
+	ext := ".parquet"
+	if t.format == formatCSV {
+		ext = ".csv"
+		if strings.HasSuffix(strings.ToLower(t.object.Key), ".gz") {
+			ext += ".gz"
+		}
+	}
+	path := base + ext
+	body, err := c.client.open(ctx, t.object.Key, "")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = body.Close() }()
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	// The listed size is what the query was allowed; an object that has
+	// grown since is read no further.
+	if _, err := io.Copy(f, io.LimitReader(body, t.object.Size)); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("download %s: %w", t.object.Key, err)
+	}
+	return path, f.Close()
+}
+
+// loadStatement copies the file at path into a table named after t.
+func loadStatement(t table, path string) string {
+	read := "read_parquet"
+	if t.format == formatCSV {
+		read = "read_csv_auto"
+	}
+	return fmt.Sprintf("CREATE TABLE %s AS SELECT * FROM %s(%s)", quoteIdent(t.name), read, quoteLiteral(path))
+}
+
+func quoteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// queryErrorCode classifies a DuckDB query error by its message prefix.
+func queryErrorCode(err error) string {
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "Parser Error"):
+		return sdk.ErrCodeSyntaxError
+	case strings.HasPrefix(msg, "Catalog Error"):
+		return sdk.ErrCodeUndefinedObject
+	case strings.HasPrefix(msg, "Permission Error"):
+		return sdk.ErrCodePermissionDenied
+	}
+	return ""
+}
+
+func scanRows(rows *sql.Rows, budget *pluginsdk.RowBudget) ([]sdk.ColumnInfo, [][]any, error) {
+	defer func() { _ = rows.Close() }()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get column types: %w", err)
+	}
+
+	columns := make([]sdk.ColumnInfo, len(columnTypes))
+	for i, ct := range columnTypes {
+		columns[i] = sdk.ColumnInfo{Name: ct.Name(), Type: ct.DatabaseTypeName(), Nullable: true}
+	}
+
+	var resultRows [][]any
+	for rows.Next() {
+		values := make([]any, len(columnTypes))
+		valuePtrs := make([]any, len(columnTypes))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		for i, v := range values {
+			values[i] = normalize(v)
+		}
+		if err := budget.Add(values); err != nil {
+			return nil, nil, err
+		}
+		resultRows = append(resultRows, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return columns, resultRows, nil
+}
+
+// normalize turns the values DuckDB scans into ones that encode as JSON:
+// decimals and huge integers as exact strings, and maps keyed by strings.
+func normalize(v any) any {
+	switch x := v.(type) {
+	case duckdb.Decimal:
+		return x.String()
+	case *big.Int:
+		return x.String()
+	case duckdb.UUID:
+		return x.String()
+	case duckdb.Map:
+		out := make(map[string]any, len(x))
+		for k, e := range x {
+			out[fmt.Sprint(k)] = normalize(e)
+		}
+		return out
+	case map[string]any:
+		for k, e := range x {
+			x[k] = normalize(e)
+		}
+		return x
+	case []any:
+		for i, e := range x {
+			x[i] = normalize(e)
+		}
+		return x
+	}
+	return pluginsdk.NormalizeValue(v)
+}
@@ -0,0 +1,269 @@
+package s3
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+
+	"data-voyager/sdk"
+)
+
+// Object formats, as the table type of the objects holding them.
+const (
+	formatParquet = "parquet"
+	formatCSV     = "csv"
+)
+
+// extensions maps the file extensions read as tables to their format.
+// Longer extensions come first so .csv.gz is not taken for .gz.
+var extensions = []struct{ ext, format string }{
+	{".parquet", formatParquet},
+	{".csv.gz", formatCSV},
+	{".csv", formatCSV},
+}
+
+const (
+	// csvSampleBytes is how much of a CSV object is read to infer its
+	// columns.
+	csvSampleBytes = 64 << 10
+	// csvSampleRows is how many rows past the header types are inferred
+	// from.
+	csvSampleRows = 100
+)
+
+// table is an object listed as a table.
+type table struct {
+	name   string
+	format string
+	object object
+}
+
+// tables lists the objects below the prefix that hold Parquet or CSV data,
+// named by their key below the prefix without the extension. When two
+// objects would share a name the later one keeps its extension.
+func (c *Connection) tables(ctx context.Context) ([]table, error) {
+	objects, err := c.client.list(ctx, c.config.MaxObjects)
+	if err != nil {
+		return nil, classify(err)
+	}
+	prefix := c.config.Prefix
+	if prefix != "" {
+		prefix += "/"
+	}
+	var tables []table
+	taken := map[string]bool{}
+	for _, o := range objects {
+		rel := strings.TrimPrefix(o.Key, prefix)
+		for _, e := range extensions {
+			if !strings.HasSuffix(strings.ToLower(rel), e.ext) || len(rel) == len(e.ext) {
+				continue
+			}
+			name := rel[:len(rel)-len(e.ext)]
+			if taken[name] {
+				name = rel
+			}
+			taken[name] = true
+			tables = append(tables, table{name: name, format: e.format, object: o})
+			break
+		}
+	}
+	return tables, nil
+}
+
+// describe reads the columns of t: from the file footer for Parquet, from
+// the header and first rows for CSV. Only the parts of the object it needs
+// are read.
+func (c *Connection) describe(ctx context.Context, t table) sdk.TableInfo {
+	size := t.object.Size
+	info := sdk.TableInfo{Name: t.name, Type: t.format, Size: &size}
+	var err error
+	if t.format == formatParquet {
+		info.Columns, info.RowCount, err = c.parquetColumns(ctx, t.object)
+	} else {
+		info.Columns, err = c.csvColumns(ctx, t.object)
+	}
+	if err != nil {
+		// One unreadable object should not hide the rest of the bucket.
+		info.Description = "columns unavailable: " + classify(err).Error()
+	}
+	return info
+}
+
+func (c *Connection) parquetColumns(ctx context.Context, o object) ([]sdk.ColumnInfo, *int64, error) {
+	f, err := parquet.OpenFile(&rangeReader{ctx: ctx, c: c.client, key: o.Key}, o.Size,
+		parquet.SkipPageIndex(true), parquet.SkipBloomFilters(true))
+	if err != nil {
+		return nil, nil, fmt.Errorf("read parquet footer of %s: %w", o.Key, err)
+	}
+	fields := f.Schema().Fields()
+	columns := make([]sdk.ColumnInfo, len(fields))
+	for i, field := range fields {
+		columns[i] = sdk.ColumnInfo{Name: field.Name(), Type: parquetType(field), Nullable: field.Optional()}
+	}
+	rows := f.NumRows()
+	return columns, &rows, nil
+}
+
+// parquetType names the type DuckDB reads a Parquet column as.
+func parquetType(n parquet.Node) string {
+	lt := n.Type().LogicalType()
+	if !n.Leaf() {
+		switch {
+		case lt != nil && isLogical[*format.ListType](lt):
+			return "LIST"
+		case lt != nil && isLogical[*format.MapType](lt):
+			return "MAP"
+		}
+		return "STRUCT"
+	}
+	if n.Repeated() {
+		return "LIST"
+	}
+	if lt != nil && lt.Value != nil {
+		switch t := lt.Value.(type) {
+		case *format.StringType, *format.EnumType:
+			return "VARCHAR"
+		case *format.JsonType:
+			return "JSON"
+		case *format.UUIDType:
+			return "UUID"
+		case *format.DateType:
+			return "DATE"
+		case *format.TimeType:
+			return "TIME"
+		case *format.TimestampType:
+			if t.IsAdjustedToUTC {
+				return "TIMESTAMP WITH TIME ZONE"
+			}
+			return "TIMESTAMP"
+		case *format.DecimalType:
+			return fmt.Sprintf("DECIMAL(%d,%d)", t.Precision, t.Scale)
+		case *format.IntType:
+			name := map[int8]string{8: "TINYINT", 16: "SMALLINT", 32: "INTEGER", 64: "BIGINT"}[t.BitWidth]
+			if !t.IsSigned {
+				name = "U" + name
+			}
+			return name
+		}
+	}
+	switch n.Type().Kind() {
+	case parquet.Boolean:
+		return "BOOLEAN"
+	case parquet.Int32:
+		return "INTEGER"
+	case parquet.Int64:
+		return "BIGINT"
+	case parquet.Int96:
+		return "TIMESTAMP"
+	case parquet.Float:
+		return "FLOAT"
+	case parquet.Double:
+		return "DOUBLE"
+	}
+	return "BLOB"
+}
+
+func isLogical[T format.LogicalTypeValue](lt *format.LogicalType) bool {
+	_, ok := lt.Value.(T)
+	return ok
+}
+
+// csvColumns names the columns of a CSV object after its header and infers
+// their types from the rows that follow it.
+func (c *Connection) csvColumns(ctx context.Context, o object) ([]sdk.ColumnInfo, error) {
+	body, err := c.client.open(ctx, o.Key, fmt.Sprintf("bytes=0-%d", csvSampleBytes-1))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+	var r io.Reader = body
+	if strings.HasSuffix(strings.ToLower(o.Key), ".gz") {
+		// A gzip stream decompresses from its start, so the first bytes
+		// of the object are enough for the first rows.
+		zr, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", o.Key, err)
+		}
+		r = io.LimitReader(zr, csvSampleBytes)
+	}
+	sample, err := io.ReadAll(r)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("read %s: %w", o.Key, err)
+	}
+	text := string(sample)
+	// The sample may end mid-row; only whole lines are read.
+	if len(sample) == csvSampleBytes || o.Size > csvSampleBytes {
+		if i := strings.LastIndexByte(text, '\n'); i >= 0 {
+			text = text[:i+1]
+		}
+	}
+	header, _, _ := strings.Cut(text, "\n")
+	cr := csv.NewReader(strings.NewReader(text))
+	cr.Comma = delimiter(header)
+	cr.FieldsPerRecord = -1
+	names, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header of %s: %w", o.Key, err)
+	}
+	types := make([]string, len(names))
+	for range csvSampleRows {
+		row, err := cr.Read()
+		if err != nil {
+			break
+		}
+		for i := range min(len(row), len(names)) {
+			types[i] = widen(types[i], row[i])
+		}
+	}
+	columns := make([]sdk.ColumnInfo, len(names))
+	for i, name := range names {
+		columns[i] = sdk.ColumnInfo{Name: strings.TrimSpace(name), Type: types[i], Nullable: true}
+		if columns[i].Type == "" {
+			columns[i].Type = "VARCHAR"
+		}
+	}
+	return columns, nil
+}
+
+// delimiter picks the separator the header line uses most.
+func delimiter(header string) rune {
+	best, count := ',', 0
+	for _, d := range []rune{',', '\t', ';', '|'} {
+		if n := strings.Count(header, string(d)); n > count {
+			best, count = d, n
+		}
+	}
+	return best
+}
+
+// widen returns the narrowest type holding both the values typ was inferred
+// from and v. Empty cells are nulls and leave typ as it is.
+func widen(typ, v string) string {
+	v = strings.TrimSpace(v)
+	if v == "" || typ == "VARCHAR" {
+		return typ
+	}
+	own := "VARCHAR"
+	if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+		own = "BIGINT"
+	} else if _, err := strconv.ParseFloat(v, 64); err == nil {
+		own = "DOUBLE"
+	} else if _, err := strconv.ParseBool(v); err == nil && len(v) > 1 {
+		own = "BOOLEAN"
+	}
+	switch {
+	case typ == "" || typ == own:
+		return own
+	case typ == "BIGINT" && own == "DOUBLE", typ == "DOUBLE" && own == "BIGINT":
+		return "DOUBLE"
+	}
+	return "VARCHAR"
+}
@@ -1,8 +1,10 @@
 package sdk
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -56,6 +58,416 @@ type Connection interface {
 	GetMetrics() ConnectionMetrics
 }
 
+// RowCount is the result of a row or distinct-value count.
+type RowCount struct {
+	Count int64 `json:"count"`
+	// Approximate is true when Count comes from statistics or a sketch rather
+	// than a full scan.
+	Approximate bool `json:"approximate"`
+	// Method names the strategy used, e.g. "pg_class.reltuples" or "count".
+	Method string `json:"method"`
+}
+
+// CardinalityEstimator is optionally implemented by a Connection that can
+// count rows cheaply from backend statistics. Callers should type-assert
+// for it and fall back to COUNT(*) when it is absent.
+type CardinalityEstimator interface {
+	// CountRows returns the row count of a table. When exact is false the
+	// driver may answer from statistics and flag the result approximate.
+	CountRows(ctx context.Context, database, table string, exact bool) (*RowCount, error)
+
+	// CountDistinct estimates the number of distinct values in a column,
+	// using a sketch (e.g. HyperLogLog) or column statistics where available.
+	CountDistinct(ctx context.Context, database, table, column string) (*RowCount, error)
+}
+
+// ScanEstimate is how much data a query would read, found without running
+// it.
+type ScanEstimate struct {
+	Bytes int64 `json:"bytes"`
+	// Rows is the number of rows read, 0 when the driver cannot tell.
+	Rows int64 `json:"rows,omitempty"`
+	// Method names the strategy used, e.g. "explain estimate" or "dry run".
+	Method string `json:"method"`
+}
+
+// ScanEstimator is optionally implemented by a Connection that can estimate
+// what a query will read before it runs, from a dry run or planner
+// statistics. It backs cost estimates; drivers that implement it should
+// also report QueryStats.BytesRead so estimates can be checked against
+// what was read.
+type ScanEstimator interface {
+	EstimateScan(ctx context.Context, query string, params ...any) (*ScanEstimate, error)
+}
+
+// Canonical SystemHealth metric names. Plugins report whichever of these
+// their backend exposes and may add backend-specific metrics under their own
+// prefix (e.g. "pg.deadlocks").
+const (
+	MetricConnections    = "connections"
+	MetricMaxConnections = "max_connections"
+	MetricActiveQueries  = "active_queries"
+	MetricUptimeSeconds  = "uptime_seconds"
+	MetricMemoryBytes    = "memory_bytes"
+	MetricCacheHitRatio  = "cache_hit_ratio"
+)
+
+// SystemSession is one client session or running query as reported by the
+// backend's activity table (pg_stat_activity, system.processes).
+type SystemSession struct {
+	ID       string `json:"id"`
+	User     string `json:"user"`
+	Database string `json:"database"`
+	// State is the backend's own state label, e.g. "active" or "idle".
+	State string `json:"state"`
+	Query string `json:"query"`
+	// Elapsed is how long the current query (or state) has been running.
+	Elapsed time.Duration `json:"elapsed"`
+	// WaitEvent names what the session is blocked on, if anything.
+	WaitEvent string `json:"waitEvent,omitempty"`
+}
+
+// SystemMetric is a single named gauge or counter.
+type SystemMetric struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// SystemNode is one member of a clustered backend.
+type SystemNode struct {
+	ID      string `json:"id"`
+	Address string `json:"address"`
+	// Version is the server build the node runs.
+	Version string `json:"version,omitempty"`
+	// Locality is the node's placement, e.g. "region=us-east1,zone=b".
+	Locality  string    `json:"locality,omitempty"`
+	Live      bool      `json:"live"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+// SystemHealth is a normalized snapshot of backend activity read from system
+// tables, so clients can show live health without backend-specific queries.
+type SystemHealth struct {
+	Sessions []SystemSession `json:"sessions"`
+	Metrics  []SystemMetric  `json:"metrics"`
+	// Nodes lists the cluster members of backends that report them.
+	Nodes []SystemNode `json:"nodes,omitempty"`
+}
+
+// SystemHealthReporter is optionally implemented by a Connection that can
+// read its backend's system tables.
+type SystemHealthReporter interface {
+	SystemHealth(ctx context.Context) (*SystemHealth, error)
+}
+
+// SessionTerminator is optionally implemented by a Connection that can stop
+// sessions reported by SystemHealth.
+type SessionTerminator interface {
+	// TerminateSession stops the session whose SystemSession.ID is id. When
+	// queryOnly is true only its running query is cancelled and the session
+	// stays connected; backends without that distinction cancel the query
+	// either way. It returns ErrSessionNotFound when no such session exists.
+	TerminateSession(ctx context.Context, id string, queryOnly bool) error
+}
+
+// IngestError is a record of an ingestion batch the backend rejected.
+type IngestError struct {
+	// Index is the record's position in the batch, from 0.
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// IngestResult reports what became of an ingestion batch.
+type IngestResult struct {
+	Accepted int           `json:"accepted"`
+	Errors   []IngestError `json:"errors"`
+}
+
+// Ingester is optionally implemented by a Connection that can load records
+// through the backend's bulk API (a batch INSERT, an _bulk request). Ingest
+// writes records, JSON objects keyed by column or field name, to target: a
+// table, or an index for search backends. Records the backend rejects are
+// reported in IngestResult.Errors without failing the rest; the error return
+// is for failures of the whole batch, such as an unknown target.
+type Ingester interface {
+	Ingest(ctx context.Context, target string, records []json.RawMessage) (*IngestResult, error)
+}
+
+// Change operations reported in ChangeEvent.Op.
+const (
+	ChangeInsert   = "insert"
+	ChangeUpdate   = "update"
+	ChangeDelete   = "delete"
+	ChangeTruncate = "truncate"
+)
+
+// ChangeEvent is one row change read from a backend's change stream.
+type ChangeEvent struct {
+	// Position is where the change sits in the stream, e.g. a PostgreSQL LSN.
+	Position string `json:"position"`
+	Op       string `json:"op"`
+	Schema   string `json:"schema"`
+	Table    string `json:"table"`
+	// Row holds the new values; nil for deletes and truncates.
+	Row map[string]any `json:"row,omitempty"`
+	// Old holds the key, or the whole old row when the backend sends it,
+	// for updates and deletes.
+	Old        map[string]any `json:"old,omitempty"`
+	CommitTime time.Time      `json:"commit_time"`
+}
+
+// ChangeBatch is a run of whole transactions read from a change stream.
+type ChangeBatch struct {
+	Events []ChangeEvent
+	// Position is what to confirm once Events are handled; empty when the
+	// batch is empty.
+	Position string
+}
+
+// ChangeStreamer is optionally implemented by a Connection that can tail a
+// backend's change stream, such as PostgreSQL logical replication.
+type ChangeStreamer interface {
+	// ReadChanges returns up to about max changes published by publication
+	// that slot has not confirmed, oldest first, without confirming them,
+	// so reading again returns the same changes. The slot is created when
+	// missing.
+	ReadChanges(ctx context.Context, slot, publication string, max int) (*ChangeBatch, error)
+	// ConfirmChanges marks the changes up to position as handled.
+	ConfirmChanges(ctx context.Context, slot, position string) error
+}
+
+// Capabilities advertises optional features of a datasource type so clients
+// can enable them per source.
+type Capabilities struct {
+	// Exec is true when statements that return no rows (DDL, DML) can be run.
+	Exec bool `json:"exec"`
+	// Explain is true when the backend supports EXPLAIN for query plans.
+	Explain bool `json:"explain"`
+	// Streaming is true when results can be consumed incrementally.
+	Streaming bool `json:"streaming"`
+	// Schemas is true when GetSchema/GetTables return a browsable tree.
+	Schemas bool `json:"schemas"`
+	// Writes is true when the backend accepts INSERT/UPDATE/DELETE.
+	Writes bool `json:"writes"`
+	// Transactions is true when multi-statement transactions are supported.
+	Transactions bool `json:"transactions"`
+	// NamedParams is true when Query accepts sql.NamedArg parameters and
+	// binds them server-side rather than splicing them into the SQL text.
+	NamedParams bool `json:"named_params"`
+	// Ingest is true when connections implement Ingester.
+	Ingest bool `json:"ingest"`
+	// ChangeStream is true when connections implement ChangeStreamer.
+	ChangeStream bool `json:"change_stream"`
+}
+
+// CapabilityProvider is optionally implemented by a DatasourcePlugin to
+// declare its Capabilities. Use PluginCapabilities rather than asserting
+// for it directly.
+type CapabilityProvider interface {
+	Capabilities() Capabilities
+}
+
+// PluginCapabilities returns the capabilities declared by p. Plugins that do
+// not implement CapabilityProvider are assumed to support only read queries
+// and schema browsing, which every Connection provides.
+func PluginCapabilities(p DatasourcePlugin) Capabilities {
+	if cp, ok := p.(CapabilityProvider); ok {
+		return cp.Capabilities()
+	}
+	return Capabilities{Schemas: true}
+}
+
+// ConfigUpgrader is optionally implemented by a DatasourcePlugin whose config
+// shape changes between releases. Stored configs record the version they were
+// written at; on startup the server upgrades older ones one step at a time
+// until they reach ConfigVersion. Use PluginConfigVersion rather than
+// asserting for it directly.
+type ConfigUpgrader interface {
+	// ConfigVersion is the version new configs are written at.
+	ConfigVersion() int
+	// UpgradeConfig rewrites a config from version from to from+1.
+	UpgradeConfig(from int, data json.RawMessage) (json.RawMessage, error)
+}
+
+// PluginConfigVersion returns the current config version of p, or 0 for
+// plugins that do not implement ConfigUpgrader.
+func PluginConfigVersion(p DatasourcePlugin) int {
+	if u, ok := p.(ConfigUpgrader); ok {
+		return u.ConfigVersion()
+	}
+	return 0
+}
+
+// WarehouseState is whether a backend's compute can run queries right now.
+type WarehouseState string
+
+const (
+	WarehouseRunning WarehouseState = "running"
+	// WarehouseSuspended compute was stopped while idle and must resume,
+	// on demand or through ResumeWarehouse, before it answers queries.
+	WarehouseSuspended WarehouseState = "suspended"
+	WarehouseResuming  WarehouseState = "resuming"
+)
+
+// WarehouseStatus is what a WarehouseController reports about the compute a
+// config points at.
+type WarehouseStatus struct {
+	State WarehouseState `json:"state"`
+	// Detail is the backend's own name for the state, e.g. "idle".
+	Detail string `json:"detail,omitempty"`
+}
+
+// WarehouseController is optionally implemented by a DatasourcePlugin whose
+// backend suspends idle compute, as Snowflake warehouses and ClickHouse
+// Cloud services do. It works from the config rather than a Connection,
+// since connecting to suspended compute can itself block until it resumes.
+type WarehouseController interface {
+	// WarehouseStatus reports the compute's state. It returns nil, nil
+	// when the config names no compute the plugin can inspect.
+	WarehouseStatus(ctx context.Context, config ConnectionConfig) (*WarehouseStatus, error)
+	// ResumeWarehouse asks suspended compute to resume and returns without
+	// waiting for it to.
+	ResumeWarehouse(ctx context.Context, config ConnectionConfig) error
+}
+
+// VersionInfo identifies a plugin build so operators can audit what is
+// running.
+type VersionInfo struct {
+	// Version is the plugin's own release, e.g. "1.4.0".
+	Version string `json:"version"`
+	// MinServerVersion and MaxServerVersion bound the server releases the
+	// plugin supports; Max is exclusive. Either may be empty for no bound.
+	MinServerVersion string `json:"min_server_version,omitempty"`
+	MaxServerVersion string `json:"max_server_version,omitempty"`
+	// DriverVersion names the client library the plugin talks to its backend
+	// with, e.g. "github.com/lib/pq v1.10.9".
+	DriverVersion string `json:"driver_version,omitempty"`
+}
+
+// SupportsServer reports whether server falls within the supported range.
+func (v VersionInfo) SupportsServer(server string) bool {
+	if v.MinServerVersion != "" && CompareVersions(server, v.MinServerVersion) < 0 {
+		return false
+	}
+	return v.MaxServerVersion == "" || CompareVersions(server, v.MaxServerVersion) < 0
+}
+
+// VersionProvider is optionally implemented by a DatasourcePlugin to report
+// its VersionInfo. Use PluginVersion rather than asserting for it directly.
+type VersionProvider interface {
+	Version() VersionInfo
+}
+
+// PluginVersion returns the version declared by p. Plugins that do not
+// implement VersionProvider report an empty Version and support any server.
+func PluginVersion(p DatasourcePlugin) VersionInfo {
+	if vp, ok := p.(VersionProvider); ok {
+		return vp.Version()
+	}
+	return VersionInfo{}
+}
+
+// CompareVersions compares dotted numeric versions such as "1.2.10" and
+// returns -1, 0 or 1. A leading "v" and any pre-release or build suffix are
+// ignored; missing components count as zero.
+func CompareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if c := cmp.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func versionParts(v string) []int {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	var parts []int
+	for _, s := range strings.Split(v, ".") {
+		n, _ := strconv.Atoi(s)
+		parts = append(parts, n)
+	}
+	return parts
+}
+
+// ParamStyle is how bind parameters are written in a query.
+type ParamStyle string
+
+const (
+	// ParamStyleNone means the driver does not accept bind parameters.
+	ParamStyleNone ParamStyle = "none"
+	// ParamStyleDollar is PostgreSQL-style positional $1, $2, ...
+	ParamStyleDollar ParamStyle = "dollar"
+	// ParamStyleQuestion is ODBC-style positional ?.
+	ParamStyleQuestion ParamStyle = "question"
+)
+
+// FunctionDoc documents one SQL function for editor completion and hover.
+type FunctionDoc struct {
+	Name        string `json:"name"`
+	Signature   string `json:"signature"`
+	ReturnType  string `json:"return_type,omitempty"`
+	Description string `json:"description"`
+}
+
+// Dialect describes the query language of a datasource type for the query
+// editor: quoting, parameter style, reserved words and function docs.
+type Dialect struct {
+	// IdentifierQuote opens and closes a quoted identifier, e.g. " or `.
+	IdentifierQuote string        `json:"identifier_quote"`
+	StringQuote     string        `json:"string_quote"`
+	ParamStyle      ParamStyle    `json:"param_style"`
+	ReservedWords   []string      `json:"reserved_words"`
+	Functions       []FunctionDoc `json:"functions"`
+}
+
+// DialectProvider is optionally implemented by a DatasourcePlugin to
+// describe its query dialect. Use PluginDialect rather than asserting for it
+// directly.
+type DialectProvider interface {
+	Dialect() Dialect
+}
+
+// PluginDialect returns the dialect declared by p, or ANSI SQL defaults for
+// plugins that do not implement DialectProvider.
+func PluginDialect(p DatasourcePlugin) Dialect {
+	if dp, ok := p.(DialectProvider); ok {
+		return dp.Dialect()
+	}
+	return Dialect{
+		IdentifierQuote: `"`,
+		StringQuote:     "'",
+		ParamStyle:      ParamStyleNone,
+		ReservedWords:   ANSIReservedWords,
+		Functions:       []FunctionDoc{},
+	}
+}
+
+// ANSIReservedWords is the common subset of SQL reserved words shared by the
+// supported dialects. Plugins usually extend it with their own keywords.
+var ANSIReservedWords = []string{
+	"ALL", "ALTER", "AND", "ANY", "AS", "ASC", "BETWEEN", "BY", "CASE", "CAST",
+	"CHECK", "COLUMN", "CONSTRAINT", "CREATE", "CROSS", "CURRENT_DATE",
+	"CURRENT_TIME", "CURRENT_TIMESTAMP", "DEFAULT", "DELETE", "DESC",
+	"DISTINCT", "DROP", "ELSE", "END", "EXCEPT", "EXISTS", "FALSE", "FETCH",
+	"FOR", "FOREIGN", "FROM", "FULL", "GRANT", "GROUP", "HAVING", "IN",
+	"INNER", "INSERT", "INTERSECT", "INTO", "IS", "JOIN", "LEFT", "LIKE",
+	"LIMIT", "NATURAL", "NOT", "NULL", "OFFSET", "ON", "OR", "ORDER", "OUTER",
+	"PRIMARY", "REFERENCES", "RIGHT", "SELECT", "SET", "TABLE", "THEN", "TO",
+	"TRUE", "UNION", "UNIQUE", "UPDATE", "USING", "VALUES", "WHEN", "WHERE",
+	"WITH",
+}
+
 // FieldKind is the semantic type of a Field, used for rendering decisions.
 type FieldKind string
 
@@ -133,12 +545,59 @@ func InferFieldKind(dbType string) FieldKind {
 	return FieldKindString
 }
 
+// Float64Value converts the numeric representations drivers put in Field
+// values to float64, including decimals delivered as strings. ok is false for
+// nil and non-numeric values.
+func Float64Value(v any) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case float32:
+		return float64(x), true
+	case int:
+		return float64(x), true
+	case int8:
+		return float64(x), true
+	case int16:
+		return float64(x), true
+	case int32:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case uint:
+		return float64(x), true
+	case uint8:
+		return float64(x), true
+	case uint16:
+		return float64(x), true
+	case uint32:
+		return float64(x), true
+	case uint64:
+		return float64(x), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		return f, err == nil
+	case []byte:
+		f, err := strconv.ParseFloat(strings.TrimSpace(string(x)), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
 // QueryStats holds execution metrics.
 type QueryStats struct {
 	ExecutionTime time.Duration `json:"execution_time"`
 	RowsReturned  int64         `json:"rows_returned"`
 	RowsAffected  int64         `json:"rows_affected"`
-	BytesRead     int64         `json:"bytes_read"`
+	// BytesRead is how much data the backend read to answer the query,
+	// 0 when the driver does not report it.
+	BytesRead int64 `json:"bytes_read"`
+	// FetchSize is the rows-per-batch setting the query ran with, 0 when it
+	// used the driver default. Batches is how many batches the rows arrived
+	// in (ClickHouse blocks, PostgreSQL cursor fetches), 0 when the driver
+	// does not report it. Together they show the effect of tuning.
+	FetchSize int   `json:"fetch_size,omitempty"`
+	Batches   int64 `json:"batches,omitempty"`
 }
 
 // SchemaInfo describes a datasource's full schema tree.
@@ -171,6 +630,11 @@ type ConnectionMetrics struct {
 	TotalQueries    int64         `json:"total_queries"`
 	AverageLatency  time.Duration `json:"average_latency"`
 	LastActivity    time.Time     `json:"last_activity"`
+
+	// Prepared statement cache counters; zero for drivers without a cache.
+	PreparedStmtHits    int64   `json:"prepared_stmt_hits"`
+	PreparedStmtMisses  int64   `json:"prepared_stmt_misses"`
+	PreparedStmtHitRate float64 `json:"prepared_stmt_hit_rate"`
 }
 
 // datasourcePlugins holds all drivers registered via RegisterDatasource.
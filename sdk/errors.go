@@ -0,0 +1,76 @@
+package sdk
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrSessionNotFound is returned by SessionTerminator when the session has
+// already ended or never existed.
+var ErrSessionNotFound = errors.New("session not found")
+
+// Codes a QueryError may carry. They are stable identifiers clients match
+// on; plugins leave errors they cannot classify unwrapped.
+const (
+	ErrCodeAuthFailed       = "auth_failed"
+	ErrCodeUnknownDatabase  = "unknown_database"
+	ErrCodePermissionDenied = "permission_denied"
+	ErrCodeUndefinedObject  = "undefined_object"
+	ErrCodeSyntaxError      = "syntax_error"
+)
+
+// QueryError is a driver error classified by the plugin, so clients can
+// tell a typo from an outage and point at the offending SQL.
+type QueryError struct {
+	Code string
+	// Line and Column locate the error in the submitted query, both 1-based;
+	// zero when the driver reports no position.
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *QueryError) Error() string { return e.Err.Error() }
+func (e *QueryError) Unwrap() error { return e.Err }
+
+// LineColumn converts a 1-based character offset into query to a 1-based
+// line and column. Offsets outside the query yield zeros.
+func LineColumn(query string, offset int) (line, column int) {
+	if offset < 1 {
+		return 0, 0
+	}
+	line, column = 1, 1
+	n := 1
+	for _, r := range query {
+		if n == offset {
+			return line, column
+		}
+		n++
+		if r == '\n' {
+			line, column = line+1, 1
+		} else {
+			column++
+		}
+	}
+	if n == offset {
+		// Just past the end, where drivers report an unexpected end of input.
+		return line, column
+	}
+	return 0, 0
+}
+
+// offsetOf is the 1-based character offset of byte index i in s.
+func offsetOf(s string, i int) int {
+	return len([]rune(s[:i])) + 1
+}
+
+// Locate fills in e's line and column from a 1-based character offset into
+// stmt, which is found within query, so statements split out of a script
+// report positions in the script as submitted.
+func (e *QueryError) Locate(query, stmt string, offset int) *QueryError {
+	if i := strings.Index(query, stmt); i >= 0 && offset > 0 {
+		offset += offsetOf(query, i) - 1
+	}
+	e.Line, e.Column = LineColumn(query, offset)
+	return e
+}
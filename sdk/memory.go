@@ -0,0 +1,32 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrMemoryLimit is wrapped by MemoryBudget errors so core can tell a result
+// that was too large to buffer from a failing datasource.
+var ErrMemoryLimit = errors.New("result memory limit exceeded")
+
+// MemoryBudget accounts for the result rows a plugin buffers while reading a
+// query. Core attaches one to the query context; plugins that buffer rows
+// reserve their estimated size as they scan and stop with the returned
+// error, which wraps ErrMemoryLimit, once the budget is spent.
+type MemoryBudget interface {
+	Reserve(bytes int64) error
+}
+
+type memoryBudgetKey struct{}
+
+// WithMemoryBudget returns a context carrying b.
+func WithMemoryBudget(ctx context.Context, b MemoryBudget) context.Context {
+	return context.WithValue(ctx, memoryBudgetKey{}, b)
+}
+
+// MemoryBudgetFrom returns the budget attached to ctx, or nil when reads
+// are not being accounted.
+func MemoryBudgetFrom(ctx context.Context) MemoryBudget {
+	b, _ := ctx.Value(memoryBudgetKey{}).(MemoryBudget)
+	return b
+}
@@ -0,0 +1,129 @@
+package pluginsdk
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// maxDialSeconds caps every DialOptions duration.
+const maxDialSeconds = 24 * 60 * 60
+
+// DialOptions are the network settings a datasource config embeds so users
+// can tune them per datasource. Durations are in seconds and zero keeps the
+// plugin's default.
+type DialOptions struct {
+	// ConnectTimeout bounds establishing a connection, including TLS.
+	ConnectTimeout int `json:"connect_timeout,omitempty" toml:"connect_timeout"`
+	// ReadTimeout and WriteTimeout bound a single network read or write.
+	// A read timeout also bounds how long a query may go without sending
+	// any data back.
+	ReadTimeout  int `json:"read_timeout,omitempty" toml:"read_timeout"`
+	WriteTimeout int `json:"write_timeout,omitempty" toml:"write_timeout"`
+	// KeepAlive is the TCP keepalive probe interval; -1 disables keepalives.
+	KeepAlive int `json:"keepalive,omitempty" toml:"keepalive"`
+}
+
+// Validate rejects negative or absurdly large durations.
+func (o DialOptions) Validate() error {
+	for _, f := range []struct {
+		name string
+		v    int
+		min  int
+	}{
+		{"connect_timeout", o.ConnectTimeout, 0},
+		{"read_timeout", o.ReadTimeout, 0},
+		{"write_timeout", o.WriteTimeout, 0},
+		{"keepalive", o.KeepAlive, -1},
+	} {
+		if f.v < f.min || f.v > maxDialSeconds {
+			return fmt.Errorf("%s must be between %d and %d seconds", f.name, f.min, maxDialSeconds)
+		}
+	}
+	return nil
+}
+
+// Connect returns the connect timeout, or def when unset.
+func (o DialOptions) Connect(def time.Duration) time.Duration { return seconds(o.ConnectTimeout, def) }
+
+// Read returns the read timeout, or def when unset.
+func (o DialOptions) Read(def time.Duration) time.Duration { return seconds(o.ReadTimeout, def) }
+
+// Write returns the write timeout, or def when unset.
+func (o DialOptions) Write(def time.Duration) time.Duration { return seconds(o.WriteTimeout, def) }
+
+func seconds(n int, def time.Duration) time.Duration {
+	if n == 0 {
+		return def
+	}
+	return time.Duration(n) * time.Second
+}
+
+// Dialer dials TCP connections with the options applied: the connect
+// timeout (defaulting to defConnect), the keepalive interval, and per-call
+// read and write deadlines on the returned connections.
+type Dialer struct {
+	net.Dialer
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+// Dialer builds a Dialer from the options.
+func (o DialOptions) Dialer(defConnect time.Duration) *Dialer {
+	d := &Dialer{ReadTimeout: o.Read(0), WriteTimeout: o.Write(0)}
+	d.Timeout = o.Connect(defConnect)
+	switch {
+	case o.KeepAlive < 0:
+		d.KeepAlive = -1
+	case o.KeepAlive > 0:
+		d.KeepAlive = time.Duration(o.KeepAlive) * time.Second
+	}
+	return d
+}
+
+// DialContext dials address and wraps the connection so every Read and
+// Write is bounded by the configured timeouts.
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := d.Dialer.DialContext(ctx, network, address)
+	if err != nil || (d.ReadTimeout == 0 && d.WriteTimeout == 0) {
+		return conn, err
+	}
+	return &deadlineConn{Conn: conn, read: d.ReadTimeout, write: d.WriteTimeout}, nil
+}
+
+// Dial is DialContext without a context.
+func (d *Dialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// DialTimeout is Dial with an explicit connect timeout.
+func (d *Dialer) DialTimeout(network, address string, timeout time.Duration) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return d.DialContext(ctx, network, address)
+}
+
+// deadlineConn pushes the read or write deadline forward before each call.
+type deadlineConn struct {
+	net.Conn
+	read, write time.Duration
+}
+
+func (c *deadlineConn) Read(b []byte) (int, error) {
+	if c.read > 0 {
+		if err := c.Conn.SetReadDeadline(time.Now().Add(c.read)); err != nil {
+			return 0, err
+		}
+	}
+	return c.Conn.Read(b)
+}
+
+func (c *deadlineConn) Write(b []byte) (int, error) {
+	if c.write > 0 {
+		if err := c.Conn.SetWriteDeadline(time.Now().Add(c.write)); err != nil {
+			return 0, err
+		}
+	}
+	return c.Conn.Write(b)
+}
@@ -0,0 +1,38 @@
+package pluginsdk
+
+import "fmt"
+
+// maxFetchSize caps FetchOptions.FetchSize.
+const maxFetchSize = 1_000_000
+
+// maxFetchGrowth bounds how far an adaptive batch grows past FetchSize.
+const maxFetchGrowth = 16
+
+// FetchOptions are the row batching settings a datasource config embeds so
+// users can tune large reads per datasource.
+type FetchOptions struct {
+	// FetchSize is the number of rows requested per round trip. Zero keeps
+	// the driver's default; plugins that fetch adaptively treat it as the
+	// first batch and grow from there.
+	FetchSize int `json:"fetch_size,omitempty" toml:"fetch_size"`
+}
+
+// Validate rejects negative or absurdly large fetch sizes.
+func (o FetchOptions) Validate() error {
+	if o.FetchSize < 0 || o.FetchSize > maxFetchSize {
+		return fmt.Errorf("fetch_size must be between 0 and %d", maxFetchSize)
+	}
+	return nil
+}
+
+// NextFetch returns the batch size to request after a full batch of cur
+// rows. Batches double so a small result costs one short round trip while a
+// large one needs only a few, and stop growing at maxFetchGrowth times
+// FetchSize to bound memory per batch.
+func (o FetchOptions) NextFetch(cur int) int {
+	limit := o.FetchSize * maxFetchGrowth
+	if cur <= 0 {
+		return o.FetchSize
+	}
+	return min(cur*2, limit)
+}
@@ -0,0 +1,62 @@
+package pluginsdk
+
+import (
+	"context"
+	"time"
+
+	"data-voyager/sdk"
+)
+
+// reserveChunk is how many estimated bytes a RowBudget collects before
+// reserving them, so scan loops do not touch the shared budget per row.
+const reserveChunk = 64 << 10
+
+// EstimateRowSize approximates the heap a scanned row occupies. It counts
+// the slice, one interface per value and the payload of strings and byte
+// slices; other values are charged a flat amount. It is meant for limits,
+// not exact accounting.
+func EstimateRowSize(values []any) int64 {
+	n := int64(24)
+	for _, v := range values {
+		n += 16
+		switch x := v.(type) {
+		case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		case string:
+			n += int64(len(x))
+		case []byte:
+			n += int64(len(x))
+		case time.Time:
+			n += 24
+		default:
+			n += 32
+		}
+	}
+	return n
+}
+
+// RowBudget charges scanned rows to the MemoryBudget on a query's context.
+// The zero value and a RowBudget for a context without a budget accept
+// every row.
+type RowBudget struct {
+	budget  sdk.MemoryBudget
+	pending int64
+}
+
+// NewRowBudget returns a RowBudget for the budget attached to ctx, if any.
+func NewRowBudget(ctx context.Context) *RowBudget {
+	return &RowBudget{budget: sdk.MemoryBudgetFrom(ctx)}
+}
+
+// Add charges one row and returns the budget's error once it is exceeded.
+func (r *RowBudget) Add(values []any) error {
+	if r == nil || r.budget == nil {
+		return nil
+	}
+	r.pending += EstimateRowSize(values)
+	if r.pending < reserveChunk {
+		return nil
+	}
+	n := r.pending
+	r.pending = 0
+	return r.budget.Reserve(n)
+}
@@ -0,0 +1,51 @@
+package pluginsdk
+
+import (
+	"sync"
+	"time"
+
+	"data-voyager/sdk"
+)
+
+// QueryMetrics tracks query counts and latency for a Connection. Embed it
+// (or hold one) and wrap each query with Track; Fill copies the totals into
+// the ConnectionMetrics returned by GetMetrics. It is safe for concurrent use.
+type QueryMetrics struct {
+	mu           sync.Mutex
+	active       int
+	total        int64
+	totalLatency time.Duration
+	last         time.Time
+}
+
+// Track records the start of a query and returns a func that records its end.
+//
+//	defer m.Track()()
+func (m *QueryMetrics) Track() func() {
+	start := time.Now()
+	m.mu.Lock()
+	m.active++
+	m.mu.Unlock()
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.active--
+		m.total++
+		m.totalLatency += time.Since(start)
+		m.last = time.Now()
+	}
+}
+
+// Fill sets the query fields of dst from the recorded totals.
+func (m *QueryMetrics) Fill(dst *sdk.ConnectionMetrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	dst.ActiveQueries = m.active
+	dst.TotalQueries = m.total
+	if m.total > 0 {
+		dst.AverageLatency = m.totalLatency / time.Duration(m.total)
+	}
+	if !m.last.IsZero() {
+		dst.LastActivity = m.last
+	}
+}
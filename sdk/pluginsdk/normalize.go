@@ -0,0 +1,50 @@
+package pluginsdk
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+)
+
+// NormalizeValue converts a scanned driver value into a plain Go value that
+// encodes cleanly as JSON: []byte becomes string, pointers are dereferenced
+// (nil pointers become nil) and driver.Valuer types such as sql.NullString
+// are unwrapped.
+func NormalizeValue(v any) any {
+	switch x := v.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return string(x)
+	case driver.Valuer:
+		val, err := x.Value()
+		if err != nil {
+			return v
+		}
+		return NormalizeValue(val)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		return NormalizeValue(rv.Elem().Interface())
+	}
+	return v
+}
+
+// NormalizeType upper-cases a native type name and strips wrappers that do
+// not change its semantic kind, e.g. Nullable(LowCardinality(String)) →
+// STRING and VARCHAR(255) → VARCHAR.
+func NormalizeType(dbType string) string {
+	t := strings.ToUpper(strings.TrimSpace(dbType))
+	for _, wrapper := range []string{"NULLABLE(", "LOWCARDINALITY("} {
+		for strings.HasPrefix(t, wrapper) && strings.HasSuffix(t, ")") {
+			t = t[len(wrapper) : len(t)-1]
+		}
+	}
+	if idx := strings.IndexByte(t, '('); idx != -1 {
+		t = t[:idx]
+	}
+	return t
+}
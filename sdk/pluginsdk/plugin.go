@@ -0,0 +1,52 @@
+package pluginsdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"data-voyager/sdk"
+)
+
+// DecodeConfig unmarshals raw JSON config into a new T. name is used in the
+// error message, e.g. "postgresql".
+func DecodeConfig[T any](data json.RawMessage, name string) (*T, error) {
+	cfg := new(T)
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid %s config: %w", name, err)
+	}
+	return cfg, nil
+}
+
+// TestConnection implements the usual DatasourcePlugin.TestConnection flow:
+// connect, ping, close, and report latency. Connection failures are reported
+// in the result rather than as an error.
+func TestConnection(ctx context.Context, p sdk.DatasourcePlugin, config sdk.ConnectionConfig) (*sdk.ConnectionTestResult, error) {
+	start := time.Now()
+
+	conn, err := p.Connect(ctx, config)
+	if err != nil {
+		return &sdk.ConnectionTestResult{
+			IsConnected: false,
+			Message:     err.Error(),
+			TestedAt:    time.Now(),
+		}, nil
+	}
+	defer func() { _ = conn.Close() }()
+
+	if err := conn.Ping(ctx); err != nil {
+		return &sdk.ConnectionTestResult{
+			IsConnected: false,
+			Message:     fmt.Sprintf("ping failed: %v", err),
+			TestedAt:    time.Now(),
+		}, nil
+	}
+
+	return &sdk.ConnectionTestResult{
+		IsConnected: true,
+		Message:     "Connection successful",
+		Latency:     time.Since(start).Milliseconds(),
+		TestedAt:    time.Now(),
+	}, nil
+}
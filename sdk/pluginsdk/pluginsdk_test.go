@@ -0,0 +1,256 @@
+package pluginsdk
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"data-voyager/sdk"
+)
+
+func TestTableResult(t *testing.T) {
+	cols := []sdk.ColumnInfo{{Name: "id", Type: "INT8"}, {Name: "name", Type: "TEXT"}}
+	res := TableResult(cols, [][]any{{int64(1), "a"}, {int64(2), "b"}}, time.Millisecond)
+
+	if len(res.Frames) != 1 {
+		t.Fatalf("frames = %d, want 1", len(res.Frames))
+	}
+	f := res.Frames[0]
+	if f.Fields[0].Kind != sdk.FieldKindNumber {
+		t.Errorf("id kind = %s, want number", f.Fields[0].Kind)
+	}
+	if want := []any{"a", "b"}; !reflect.DeepEqual(f.Fields[1].Values, want) {
+		t.Errorf("name values = %v, want %v", f.Fields[1].Values, want)
+	}
+	if res.Stats.RowsReturned != 2 {
+		t.Errorf("rows returned = %d, want 2", res.Stats.RowsReturned)
+	}
+}
+
+func TestNormalizeValue(t *testing.T) {
+	s := "x"
+	var nilPtr *int64
+	tests := []struct {
+		in   any
+		want any
+	}{
+		{[]byte("raw"), "raw"},
+		{&s, "x"},
+		{nilPtr, nil},
+		{sql.NullString{String: "v", Valid: true}, "v"},
+		{sql.NullString{}, nil},
+		{3, 3},
+	}
+	for _, tt := range tests {
+		if got := NormalizeValue(tt.in); got != tt.want {
+			t.Errorf("NormalizeValue(%#v) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeType(t *testing.T) {
+	tests := map[string]string{
+		"Nullable(LowCardinality(String))": "STRING",
+		"varchar(255)":                     "VARCHAR",
+		"Nullable(DateTime64(3))":          "DATETIME64",
+	}
+	for in, want := range tests {
+		if got := NormalizeType(in); got != want {
+			t.Errorf("NormalizeType(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), RetryPolicy{Attempts: 3}, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("err = %v, calls = %d; want nil, 3", err, calls)
+	}
+
+	calls = 0
+	permanent := errors.New("bad password")
+	err = Retry(context.Background(), RetryPolicy{
+		Attempts:  5,
+		Retryable: func(err error) bool { return !errors.Is(err, permanent) },
+	}, func(context.Context) error {
+		calls++
+		return permanent
+	})
+	if !errors.Is(err, permanent) || calls != 1 {
+		t.Fatalf("err = %v, calls = %d; want permanent error after 1 call", err, calls)
+	}
+}
+
+func TestQueryMetrics(t *testing.T) {
+	var m QueryMetrics
+	done := m.Track()
+
+	var mid sdk.ConnectionMetrics
+	m.Fill(&mid)
+	if mid.ActiveQueries != 1 {
+		t.Errorf("active during query = %d, want 1", mid.ActiveQueries)
+	}
+
+	done()
+	var after sdk.ConnectionMetrics
+	m.Fill(&after)
+	if after.ActiveQueries != 0 || after.TotalQueries != 1 || after.LastActivity.IsZero() {
+		t.Errorf("after query: %+v", after)
+	}
+}
+
+func TestConfigSteps(t *testing.T) {
+	steps := ConfigSteps{
+		func(cfg map[string]any) error { SetDefault(cfg, "ssl_mode", "prefer"); return nil },
+		func(cfg map[string]any) error { RenameKey(cfg, "user", "username"); return nil },
+	}
+	if steps.ConfigVersion() != 2 {
+		t.Fatalf("version = %d, want 2", steps.ConfigVersion())
+	}
+
+	data := json.RawMessage(`{"host":"db","user":"app"}`)
+	for v := 0; v < steps.ConfigVersion(); v++ {
+		var err error
+		if data, err = steps.UpgradeConfig(v, data); err != nil {
+			t.Fatalf("upgrade v%d: %v", v, err)
+		}
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"host": "db", "username": "app", "ssl_mode": "prefer"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("upgraded = %v, want %v", got, want)
+	}
+
+	if _, err := steps.UpgradeConfig(2, data); err == nil {
+		t.Error("expected an error upgrading past the current version")
+	}
+}
+
+func TestDialOptions(t *testing.T) {
+	if err := (DialOptions{ConnectTimeout: 5, KeepAlive: -1}).Validate(); err != nil {
+		t.Fatalf("valid options: %v", err)
+	}
+	if err := (DialOptions{ReadTimeout: -1}).Validate(); err == nil {
+		t.Error("negative read_timeout accepted")
+	}
+
+	o := DialOptions{ReadTimeout: 1}
+	if got := o.Connect(30 * time.Second); got != 30*time.Second {
+		t.Errorf("default connect = %s, want 30s", got)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+	go func() {
+		// Accept and stay silent so reads have to time out.
+		if c, err := ln.Accept(); err == nil {
+			defer func() { _ = c.Close() }()
+			time.Sleep(3 * time.Second)
+		}
+	}()
+
+	conn, err := o.Dialer(time.Second).DialContext(context.Background(), "tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	var ne net.Error
+	if _, err := conn.Read(make([]byte, 1)); !errors.As(err, &ne) || !ne.Timeout() {
+		t.Errorf("read err = %v, want a timeout", err)
+	}
+}
+
+func TestVersionInfo(t *testing.T) {
+	cmp := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.10", "1.2.9", 1},
+		{"v1.2", "1.2.0", 0},
+		{"0.9.0-rc1", "0.9.0", 0},
+		{"0.1.0", "1.0.0", -1},
+	}
+	for _, c := range cmp {
+		if got := sdk.CompareVersions(c.a, c.b); got != c.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+
+	v := sdk.VersionInfo{Version: "1.0.0", MinServerVersion: "0.2.0", MaxServerVersion: "1.0.0"}
+	for server, want := range map[string]bool{"0.1.9": false, "0.2.0": true, "0.9.5": true, "1.0.0": false} {
+		if got := v.SupportsServer(server); got != want {
+			t.Errorf("SupportsServer(%q) = %v, want %v", server, got, want)
+		}
+	}
+	if !(sdk.VersionInfo{}).SupportsServer("0.1.0") {
+		t.Error("an unbounded plugin should support any server")
+	}
+
+	if got := ModuleVersion("example.com/not-linked"); got != "example.com/not-linked" {
+		t.Errorf("ModuleVersion of an unlinked module = %q", got)
+	}
+}
+
+func TestFetchOptions(t *testing.T) {
+	if err := (FetchOptions{FetchSize: -1}).Validate(); err == nil {
+		t.Error("negative fetch_size accepted")
+	}
+	o := FetchOptions{FetchSize: 100}
+	var sizes []int
+	for n := o.NextFetch(0); len(sizes) < 7; n = o.NextFetch(n) {
+		sizes = append(sizes, n)
+	}
+	want := []int{100, 200, 400, 800, 1600, 1600, 1600}
+	for i := range want {
+		if sizes[i] != want[i] {
+			t.Fatalf("batch sizes = %v, want %v", sizes, want)
+		}
+	}
+}
+
+type capBudget struct{ left int64 }
+
+func (b *capBudget) Reserve(n int64) error {
+	if b.left -= n; b.left < 0 {
+		return sdk.ErrMemoryLimit
+	}
+	return nil
+}
+
+func TestRowBudget(t *testing.T) {
+	if err := NewRowBudget(context.Background()).Add([]any{"x"}); err != nil {
+		t.Fatalf("row without a budget rejected: %v", err)
+	}
+	ctx := sdk.WithMemoryBudget(context.Background(), &capBudget{left: 1 << 20})
+	rb := NewRowBudget(ctx)
+	row := []any{int64(1), string(make([]byte, 1000))}
+	var err error
+	var rows int
+	for ; err == nil && rows < 10_000; rows++ {
+		err = rb.Add(row)
+	}
+	if !errors.Is(err, sdk.ErrMemoryLimit) {
+		t.Fatalf("err = %v after %d rows, want ErrMemoryLimit", err, rows)
+	}
+	if rows < 900 || rows > 1100 {
+		t.Errorf("limit hit after %d rows, want about 1000", rows)
+	}
+}
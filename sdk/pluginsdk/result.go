@@ -0,0 +1,44 @@
+// Package pluginsdk holds helpers shared by datasource plugins: result
+// builders, value and type normalization, retries, query metrics and the
+// standard TestConnection flow. Third-party plugins should use these rather
+// than copy code from the bundled extensions.
+package pluginsdk
+
+import (
+	"time"
+
+	"data-voyager/sdk"
+)
+
+// TableResult transposes row-oriented values into a single table DataFrame.
+// Each row must have one value per column.
+func TableResult(columns []sdk.ColumnInfo, rows [][]any, elapsed time.Duration) *sdk.QueryResult {
+	fields := make([]sdk.Field, len(columns))
+	for i, col := range columns {
+		values := make([]any, len(rows))
+		for j, row := range rows {
+			values[j] = row[i]
+		}
+		fields[i] = sdk.Field{
+			Name:   col.Name,
+			Kind:   sdk.InferFieldKind(col.Type),
+			Type:   col.Type,
+			Values: values,
+		}
+	}
+	return &sdk.QueryResult{
+		Frames: []*sdk.DataFrame{{
+			FrameType: sdk.FrameTypeTable,
+			Fields:    fields,
+		}},
+		Stats: sdk.QueryStats{
+			ExecutionTime: elapsed,
+			RowsReturned:  int64(len(rows)),
+		},
+	}
+}
+
+// EmptyResult is returned for statements that produce no rows.
+func EmptyResult() *sdk.QueryResult {
+	return &sdk.QueryResult{Frames: []*sdk.DataFrame{}}
+}
@@ -0,0 +1,60 @@
+package pluginsdk
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RetryPolicy configures Retry. The zero value makes a single attempt.
+type RetryPolicy struct {
+	// Attempts is the total number of tries, including the first.
+	Attempts int
+	// Backoff is the delay before the second attempt; it doubles after each
+	// failure up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Retryable reports whether err is worth retrying. Nil retries every
+	// error except context cancellation.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy suits connection setup against a backend that may still
+// be starting: three attempts, 200ms then 400ms apart.
+var DefaultRetryPolicy = RetryPolicy{Attempts: 3, Backoff: 200 * time.Millisecond, MaxBackoff: 2 * time.Second}
+
+// Retry calls fn until it succeeds, returns a non-retryable error, the
+// attempts are exhausted or ctx is done. The last error is returned.
+func Retry(ctx context.Context, p RetryPolicy, fn func(ctx context.Context) error) error {
+	attempts := max(p.Attempts, 1)
+	backoff := p.Backoff
+	var err error
+	for i := range attempts {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		if !p.retryable(err) || i == attempts-1 {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+	}
+	return err
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if p.Retryable == nil {
+		return true
+	}
+	return p.Retryable(err)
+}
@@ -0,0 +1,57 @@
+package pluginsdk
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ConfigSteps implements sdk.ConfigUpgrader from a list of edits on the
+// decoded config object. steps[i] upgrades version i to i+1, so appending a
+// step is all a plugin needs to do when its config shape changes.
+//
+//	var configSteps = pluginsdk.ConfigSteps{
+//		func(cfg map[string]any) error { // v0 → v1
+//			pluginsdk.SetDefault(cfg, "ssl_mode", "prefer")
+//			return nil
+//		},
+//	}
+type ConfigSteps []func(cfg map[string]any) error
+
+// ConfigVersion implements sdk.ConfigUpgrader.
+func (s ConfigSteps) ConfigVersion() int { return len(s) }
+
+// UpgradeConfig implements sdk.ConfigUpgrader.
+func (s ConfigSteps) UpgradeConfig(from int, data json.RawMessage) (json.RawMessage, error) {
+	if from < 0 || from >= len(s) {
+		return nil, fmt.Errorf("no config upgrade from version %d", from)
+	}
+	cfg := map[string]any{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("decode config v%d: %w", from, err)
+		}
+	}
+	if err := s[from](cfg); err != nil {
+		return nil, fmt.Errorf("upgrade config v%d to v%d: %w", from, from+1, err)
+	}
+	return json.Marshal(cfg)
+}
+
+// SetDefault sets cfg[key] to value when the key is missing or empty.
+func SetDefault(cfg map[string]any, key string, value any) {
+	if v, ok := cfg[key]; !ok || v == nil || v == "" {
+		cfg[key] = value
+	}
+}
+
+// RenameKey moves cfg[from] to cfg[to] unless to is already set.
+func RenameKey(cfg map[string]any, from, to string) {
+	v, ok := cfg[from]
+	if !ok {
+		return
+	}
+	delete(cfg, from)
+	if _, exists := cfg[to]; !exists {
+		cfg[to] = v
+	}
+}
@@ -0,0 +1,27 @@
+package pluginsdk
+
+import "runtime/debug"
+
+// ModuleVersion returns "path version" for a module linked into the running
+// binary, e.g. "github.com/lib/pq v1.10.9", for use as
+// sdk.VersionInfo.DriverVersion. It returns path alone when the version is
+// unknown, as in tests or builds without module information.
+func ModuleVersion(path string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return path
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != path {
+			continue
+		}
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		if dep.Version == "" || dep.Version == "(devel)" {
+			return path
+		}
+		return path + " " + dep.Version
+	}
+	return path
+}
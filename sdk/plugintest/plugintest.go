@@ -0,0 +1,253 @@
+// Package plugintest is a conformance suite for datasource plugins. Plugin
+// authors and CI run it against a live backend to check that a plugin
+// connects, queries, binds parameters, browses schemas, honours
+// cancellation and reports errors the same way as the bundled plugins.
+//
+//	func TestContract(t *testing.T) {
+//		plugintest.Run(t, plugintest.Suite{
+//			Plugin:     &Plugin{},
+//			Config:     cfg,
+//			ParamQuery: "SELECT $1::int",
+//			SlowQuery:  "SELECT pg_sleep(10)",
+//		})
+//	}
+package plugintest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"data-voyager/sdk"
+)
+
+// Suite describes the plugin under test and the backend-specific queries the
+// checks need. Optional queries skip their check when empty.
+type Suite struct {
+	Plugin sdk.DatasourcePlugin
+	// Config must connect to a live backend.
+	Config sdk.ConnectionConfig
+	// BadConfig should point at an unreachable backend. Optional.
+	BadConfig sdk.ConnectionConfig
+	// Database is passed to GetTables; empty uses the plugin's default.
+	Database string
+
+	// SelectOne returns one row with one integer column equal to 1.
+	// Defaults to "SELECT 1".
+	SelectOne string
+	// InvalidQuery must be rejected by the backend. Defaults to "SELEC 1".
+	InvalidQuery string
+	// ParamQuery returns its single integer bind parameter, e.g.
+	// "SELECT $1::int". Skipped when the dialect has no parameter style.
+	ParamQuery string
+	// SlowQuery runs for at least a few seconds so cancellation can be
+	// observed, e.g. "SELECT pg_sleep(10)".
+	SlowQuery string
+
+	// CancelGrace bounds how long a cancelled query may take to return.
+	// Defaults to 5s.
+	CancelGrace time.Duration
+}
+
+func (s *Suite) defaults() {
+	if s.SelectOne == "" {
+		s.SelectOne = "SELECT 1"
+	}
+	if s.InvalidQuery == "" {
+		s.InvalidQuery = "SELEC 1"
+	}
+	if s.CancelGrace == 0 {
+		s.CancelGrace = 5 * time.Second
+	}
+}
+
+// Run executes the conformance checks as subtests of t.
+func Run(t *testing.T, s Suite) {
+	t.Helper()
+	if s.Plugin == nil || s.Config == nil {
+		t.Fatal("plugintest: Suite.Plugin and Suite.Config are required")
+	}
+	s.defaults()
+
+	t.Run("Metadata", s.testMetadata)
+	t.Run("TestConnection", s.testConnection)
+	t.Run("Query", s.testQuery)
+	t.Run("Params", s.testParams)
+	t.Run("Schema", s.testSchema)
+	t.Run("Cancellation", s.testCancellation)
+	t.Run("Errors", s.testErrors)
+}
+
+func (s *Suite) connect(t *testing.T) sdk.Connection {
+	t.Helper()
+	conn, err := s.Plugin.Connect(context.Background(), s.Config)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func (s *Suite) testMetadata(t *testing.T) {
+	if s.Plugin.GetType() == "" {
+		t.Error("GetType returned an empty type")
+	}
+	if s.Plugin.GetName() == "" {
+		t.Error("GetName returned an empty name")
+	}
+	switch d := sdk.PluginDialect(s.Plugin); d.ParamStyle {
+	case sdk.ParamStyleNone, sdk.ParamStyleDollar, sdk.ParamStyleQuestion:
+	default:
+		t.Errorf("Dialect: unknown param style %q", d.ParamStyle)
+	}
+	if _, ok := s.Plugin.(sdk.VersionProvider); ok {
+		v := sdk.PluginVersion(s.Plugin)
+		if v.Version == "" {
+			t.Error("Version: empty version")
+		}
+		if v.MinServerVersion != "" && v.MaxServerVersion != "" &&
+			sdk.CompareVersions(v.MinServerVersion, v.MaxServerVersion) >= 0 {
+			t.Errorf("Version: empty server range [%s, %s)", v.MinServerVersion, v.MaxServerVersion)
+		}
+	}
+	if err := s.Plugin.ValidateConfig(s.Config); err != nil {
+		t.Errorf("ValidateConfig rejected a working config: %v", err)
+	}
+}
+
+func (s *Suite) testConnection(t *testing.T) {
+	ctx := context.Background()
+	res, err := s.Plugin.TestConnection(ctx, s.Config)
+	if err != nil {
+		t.Fatalf("TestConnection: %v", err)
+	}
+	if !res.IsConnected {
+		t.Errorf("TestConnection: not connected: %s", res.Message)
+	}
+
+	if s.BadConfig == nil {
+		return
+	}
+	res, err = s.Plugin.TestConnection(ctx, s.BadConfig)
+	if err != nil {
+		t.Errorf("TestConnection must report failures in the result, got error: %v", err)
+	} else if res.IsConnected || res.Message == "" {
+		t.Errorf("TestConnection with bad config: got %+v, want IsConnected=false with a message", res)
+	}
+}
+
+func (s *Suite) testQuery(t *testing.T) {
+	conn := s.connect(t)
+	if err := conn.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+
+	res, err := conn.Query(context.Background(), s.SelectOne)
+	if err != nil {
+		t.Fatalf("Query(%q): %v", s.SelectOne, err)
+	}
+	got, ok := singleInt(t, res)
+	if ok && got != 1 {
+		t.Errorf("Query(%q) = %d, want 1", s.SelectOne, got)
+	}
+	if res.Stats.RowsReturned != 1 {
+		t.Errorf("Stats.RowsReturned = %d, want 1", res.Stats.RowsReturned)
+	}
+	_ = conn.GetMetrics()
+}
+
+func (s *Suite) testParams(t *testing.T) {
+	if s.ParamQuery == "" || sdk.PluginDialect(s.Plugin).ParamStyle == sdk.ParamStyleNone {
+		t.Skip("plugin does not bind parameters")
+	}
+	conn := s.connect(t)
+	res, err := conn.Query(context.Background(), s.ParamQuery, 42)
+	if err != nil {
+		t.Fatalf("Query(%q, 42): %v", s.ParamQuery, err)
+	}
+	if got, ok := singleInt(t, res); ok && got != 42 {
+		t.Errorf("Query(%q, 42) = %d, want 42", s.ParamQuery, got)
+	}
+}
+
+func (s *Suite) testSchema(t *testing.T) {
+	if !sdk.PluginCapabilities(s.Plugin).Schemas {
+		t.Skip("plugin does not support schema browsing")
+	}
+	conn := s.connect(t)
+	ctx := context.Background()
+	schema, err := conn.GetSchema(ctx)
+	if err != nil {
+		t.Fatalf("GetSchema: %v", err)
+	}
+	if schema == nil || len(schema.Databases) == 0 {
+		t.Error("GetSchema returned no databases")
+	}
+	if _, err := conn.GetTables(ctx, s.Database); err != nil {
+		t.Errorf("GetTables(%q): %v", s.Database, err)
+	}
+}
+
+func (s *Suite) testCancellation(t *testing.T) {
+	if s.SlowQuery == "" {
+		t.Skip("no SlowQuery configured")
+	}
+	conn := s.connect(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := conn.Query(ctx, s.SlowQuery)
+	elapsed := time.Since(start)
+	if err == nil {
+		t.Fatalf("Query(%q) succeeded after its context expired", s.SlowQuery)
+	}
+	if elapsed > s.CancelGrace {
+		t.Errorf("cancelled query returned after %s, want under %s", elapsed, s.CancelGrace)
+	}
+	// The connection must stay usable after a cancelled query.
+	if _, err := conn.Query(context.Background(), s.SelectOne); err != nil {
+		t.Errorf("Query after cancellation: %v", err)
+	}
+}
+
+func (s *Suite) testErrors(t *testing.T) {
+	conn := s.connect(t)
+	ctx := context.Background()
+	if _, err := conn.Query(ctx, s.InvalidQuery); err == nil {
+		t.Errorf("Query(%q) succeeded, want an error", s.InvalidQuery)
+	}
+	if _, err := conn.Query(ctx, s.SelectOne); err != nil {
+		t.Errorf("Query after a failed query: %v", err)
+	}
+	if _, err := s.Plugin.ParseConfig([]byte("not json")); err == nil {
+		t.Error("ParseConfig accepted invalid JSON")
+	}
+}
+
+// singleInt extracts the value of a one-row, one-column result as an int64.
+func singleInt(t *testing.T, res *sdk.QueryResult) (int64, bool) {
+	t.Helper()
+	if res == nil || len(res.Frames) == 0 || len(res.Frames[0].Fields) == 0 {
+		t.Error("result has no fields")
+		return 0, false
+	}
+	values := res.Frames[0].Fields[0].Values
+	if len(values) != 1 {
+		t.Errorf("result has %d rows, want 1", len(values))
+		return 0, false
+	}
+	v := reflect.ValueOf(values[0])
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch {
+	case v.CanInt():
+		return v.Int(), true
+	case v.CanUint():
+		return int64(v.Uint()), true
+	}
+	t.Errorf("result value %#v is not an integer", values[0])
+	return 0, false
+}
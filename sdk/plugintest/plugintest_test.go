@@ -0,0 +1,83 @@
+package plugintest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"data-voyager/sdk"
+	"data-voyager/sdk/pluginsdk"
+)
+
+// fakePlugin is a minimal in-memory backend used to check the suite itself.
+type fakePlugin struct{}
+
+type fakeConfig struct{ Reachable bool }
+
+func (fakeConfig) Validate() error             { return nil }
+func (fakeConfig) GetConnectionString() string { return "fake://" }
+
+func (fakePlugin) GetType() sdk.DataSourceType { return "fake" }
+func (fakePlugin) GetName() string             { return "Fake" }
+func (fakePlugin) ParseConfig(data json.RawMessage) (sdk.ConnectionConfig, error) {
+	cfg, err := pluginsdk.DecodeConfig[fakeConfig](data, "fake")
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+func (fakePlugin) ValidateConfig(any) error { return nil }
+func (fakePlugin) Connect(_ context.Context, cfg sdk.ConnectionConfig) (sdk.Connection, error) {
+	if !cfg.(fakeConfig).Reachable {
+		return nil, errors.New("connection refused")
+	}
+	return &fakeConn{}, nil
+}
+func (p fakePlugin) TestConnection(ctx context.Context, cfg sdk.ConnectionConfig) (*sdk.ConnectionTestResult, error) {
+	return pluginsdk.TestConnection(ctx, p, cfg)
+}
+func (fakePlugin) Dialect() sdk.Dialect {
+	return sdk.Dialect{ParamStyle: sdk.ParamStyleQuestion}
+}
+
+type fakeConn struct{}
+
+func (fakeConn) Query(ctx context.Context, query string, params ...any) (*sdk.QueryResult, error) {
+	cols := []sdk.ColumnInfo{{Name: "v", Type: "INT"}}
+	switch {
+	case query == "SELECT 1":
+		return pluginsdk.TableResult(cols, [][]any{{1}}, 0), nil
+	case query == "SELECT ?":
+		return pluginsdk.TableResult(cols, [][]any{{params[0]}}, 0), nil
+	case query == "SLEEP":
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Second):
+			return pluginsdk.EmptyResult(), nil
+		}
+	case strings.HasPrefix(query, "SELEC "):
+		return nil, errors.New("syntax error")
+	}
+	return pluginsdk.EmptyResult(), nil
+}
+func (fakeConn) GetSchema(context.Context) (*sdk.SchemaInfo, error) {
+	return &sdk.SchemaInfo{Databases: []sdk.DatabaseInfo{{Name: "main"}}}, nil
+}
+func (fakeConn) GetTables(context.Context, string) ([]sdk.TableInfo, error) { return nil, nil }
+func (fakeConn) Close() error                                               { return nil }
+func (fakeConn) Ping(context.Context) error                                 { return nil }
+func (fakeConn) GetMetrics() sdk.ConnectionMetrics                          { return sdk.ConnectionMetrics{} }
+
+func TestRun_FakePlugin(t *testing.T) {
+	Run(t, Suite{
+		Plugin:     fakePlugin{},
+		Config:     fakeConfig{Reachable: true},
+		BadConfig:  fakeConfig{},
+		ParamQuery: "SELECT ?",
+		SlowQuery:  "SLEEP",
+	})
+}
@@ -0,0 +1,20 @@
+package sdk
+
+import "context"
+
+type readOnlyKey struct{}
+
+// WithReadOnly returns a context whose queries must not change anything.
+// Core checks such queries are plain reads before running them; plugins
+// whose database can enforce it as well run them in a read-only
+// transaction that is rolled back afterwards, which also catches reads
+// that call functions with side effects.
+func WithReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyKey{}, true)
+}
+
+// ReadOnly reports whether queries run with ctx must not change anything.
+func ReadOnly(ctx context.Context) bool {
+	ro, _ := ctx.Value(readOnlyKey{}).(bool)
+	return ro
+}